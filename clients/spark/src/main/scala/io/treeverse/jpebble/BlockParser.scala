@@ -1,5 +1,6 @@
 package io.treeverse.jpebble
 
+import com.github.luben.zstd.Zstd
 import org.xerial.snappy.Snappy
 
 import java.io.IOException
@@ -111,11 +112,15 @@ object BlockParser {
   // Support (only) the non-legacy format.  It is compatible with reading
   // the legacy footer and identifying this different footer magic.
   val footerMagic = Seq(0xf7, 0xcf, 0xf4, 0x85, 0xb7, 0x41, 0xe2, 0x88).map(_.toByte)
+  // Pebble table formats (lakeFS sstable format version 2 writes Pebblev2)
+  // use the same footer layout with a different magic.
+  val pebbleFooterMagic = Seq(0xf0, 0x9f, 0xaa, 0xb3, 0xf0, 0x9f, 0xaa, 0xb3).map(_.toByte)
 
   val blockTrailerLen = 1 + 4
 
   val COMPRESSION_BLOCK_TYPE_NONE = 0
   val COMPRESSION_BLOCK_TYPE_SNAPPY = 1
+  val COMPRESSION_BLOCK_TYPE_ZSTD = 7
 
   val INDEX_TYPE_KEY = "rocksdb.block.based.table.index.type".getBytes
   val INDEX_TYPE_TWO_LEVEL = 2
@@ -134,10 +139,12 @@ object BlockParser {
         s"Bad magic ${magic.map("%02x".format(_)).mkString(" ")}: too short"
       )
     }
-    val isMatch = magic
-      .zip(BlockParser.footerMagic)
-      .filter({ case ((a, b)) => a != b })
-      .isEmpty
+    val isMatch = Seq(BlockParser.footerMagic, BlockParser.pebbleFooterMagic).exists((expected) =>
+      magic
+        .zip(expected)
+        .filter({ case ((a, b)) => a != b })
+        .isEmpty
+    )
     if (!isMatch) {
       throw new BadFileFormatException(
         s"Bad magic ${magic.map("%02x".format(_)).mkString(" ")}: wrong bytes"
@@ -244,6 +251,20 @@ object BlockParser {
             throw new BadFileFormatException(s"Bad Snappy-compressed data", e)
         }
       }
+      case COMPRESSION_BLOCK_TYPE_ZSTD => {
+        // Zstd-compressed data is prefixed by its varint uncompressed length.
+        val lengthIt = new CountedIterator(data.iterator)
+        val uncompressedLength = readUnsignedVarLong(lengthIt)
+        val compressed = data.slice(lengthIt.count, data.size)
+        try {
+          val compressedBytes = java.util.Arrays
+            .copyOfRange(compressed.bytes, compressed.from, compressed.from + compressed.size)
+          IndexedBytes.create(Zstd.decompress(compressedBytes, uncompressedLength.toInt))
+        } catch {
+          case e: RuntimeException =>
+            throw new BadFileFormatException(s"Bad Zstd-compressed data", e)
+        }
+      }
       case _ => throw new BadFileFormatException(s"Unknown compression type $compressionType")
     }
   }
@@ -49,6 +49,7 @@ func main() {
 	writeMultiSizedSstsWithContentsFuzzing()
 	writeSstsWithWriterOptionsFuzzing()
 	writeSstsWithUnsupportedWriterOptions()
+	writeSstsWithCompression()
 	writeLargeSsts()
 	writeZeroRecordSst()
 	writeEmptyFile()
@@ -131,15 +132,24 @@ func writeSstsWithUnsupportedWriterOptions() {
 		"checksum.type.xxHash64", writerOptions)
 
 	// TableFormat specifies the format version for writing sstables. The default
-	// is TableFormatRocksDBv2 which creates RocksDB compatible sstables. this is the only format supported by lakeFS.
+	// is TableFormatRocksDBv2 which creates RocksDB compatible sstables. lakeFS supports it, and TableFormatPebblev2
+	// written by sstable format version 2, which uses the same footer with a Pebble magic.
 	writerOptions = newDefaultWriterOptions()
 	writerOptions.TableFormat = sstable.TableFormatLevelDB
 	createTestInputFiles(keys, generateNanoidValue, sizeBytes, "table.format.leveldb",
 		writerOptions)
+}
 
-	// Compression defines the per-block compression to use. lakeFS support Snappy or no compression.
-	writerOptions = newDefaultWriterOptions()
+func writeSstsWithCompression() {
+	sizeBytes := DefaultSstSizeBytes
+	generateNanoidKey := newGenerateNanoid(DefaultKeySizeBytes)
+	keys := prepareSortedSlice(sizeBytes, generateNanoidKey)
+
+	// Compression defines the per-block compression to use. lakeFS supports Snappy, Zstd (sstable format version 2)
+	// or no compression.
+	writerOptions := newDefaultWriterOptions()
 	writerOptions.Compression = sstable.ZstdCompression
+	generateNanoidValue := newGenerateNanoid(DefaultValueSizeBytes)
 	createTestInputFiles(keys, generateNanoidValue, sizeBytes, "compression.type.zstd",
 		writerOptions)
 }
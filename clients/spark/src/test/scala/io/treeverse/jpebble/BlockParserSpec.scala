@@ -44,6 +44,12 @@ class BlockParserSpec extends AnyFunSpec with Matchers {
       BlockParser.readEnd(bytes)
     }
 
+    it("can read Pebble magic") {
+      val bytes = BlockParser.pebbleFooterMagic.iterator
+      BlockParser.readMagic(bytes)
+      BlockParser.readEnd(bytes)
+    }
+
     it("can read magic with a suffix") {
       val bytes = magicBytes :+ 123.toByte
       BlockParser.readMagic(magicBytes.iterator)
@@ -210,7 +216,8 @@ class BlockParserSpec extends AnyFunSpec with Matchers {
       "h.no-compression.two_level_index.sst",
       "h.table-bloom.no-compression.prefix_extractor.no_whole_key_filter.sst",
       "h.table-bloom.no-compression.sst",
-      "h.table-bloom.sst"
+      "h.table-bloom.sst",
+      "h.zstd-compression.sst"
     )
 
     /** Lightweight fixture for running particular tests with an SSTable and
@@ -372,10 +379,8 @@ class GolangContainerSpec extends AnyFunSpec with ForAllTestContainer {
     }
 
     describe("with sstable with Zstd compression") {
-      it("should fail parsing") {
-        assertThrows[BadFileFormatException] {
-          withGeneratedSstTestFiles("compression.type.zstd", verifyBlockParserOutput)
-        }
+      it("should parse successfully") {
+        withGeneratedSstTestFiles("compression.type.zstd", verifyBlockParserOutput)
       }
     }
 
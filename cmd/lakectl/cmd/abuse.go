@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/testutil/stress"
 )
 
 const (
 	abuseDefaultAmount      = 1000000
 	abuseDefaultParallelism = 100
+
+	abuseResultsFilePerm = 0o644
 )
 
 var abuseCmd = &cobra.Command{
@@ -15,7 +21,29 @@ var abuseCmd = &cobra.Command{
 	Hidden: true,
 }
 
+// writeAbuseReport writes the report of a run as JSON to the file set by --results-file, if any
+func writeAbuseReport(cmd *cobra.Command, report *stress.Report) {
+	resultsFile := Must(cmd.Flags().GetString("results-file"))
+	if resultsFile == "" || report == nil {
+		return
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		DieErr(err)
+	}
+	data = append(data, '\n')
+	if resultsFile == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(resultsFile, data, abuseResultsFilePerm)
+	}
+	if err != nil {
+		DieFmt("write results: %s", err)
+	}
+}
+
 //nolint:gochecknoinits
 func init() {
+	abuseCmd.PersistentFlags().String("results-file", "", "write the results of the run as JSON to this file (\"-\" for stdout), including throughput and latency percentiles of each operation")
 	rootCmd.AddCommand(abuseCmd)
 }
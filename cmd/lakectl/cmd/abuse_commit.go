@@ -45,7 +45,7 @@ var abuseCommitCmd = &cobra.Command{
 		}
 
 		// execute the things!
-		generator.Run(func(input chan string, output chan stress.Result) {
+		report := generator.Run(func(input chan string, output chan stress.Result) {
 			ctx := cmd.Context()
 			client := getClient()
 			allowEmpty := true
@@ -70,6 +70,7 @@ var abuseCommitCmd = &cobra.Command{
 				}
 			}
 		})
+		writeAbuseReport(cmd, report)
 	},
 }
 
@@ -86,7 +86,7 @@ var abuseCreateBranchesCmd = &cobra.Command{
 			}
 		})
 
-		generator.Run(func(input chan string, output chan stress.Result) {
+		report := generator.Run(func(input chan string, output chan stress.Result) {
 			ctx := cmd.Context()
 			for branch := range input {
 				start := time.Now()
@@ -104,6 +104,7 @@ var abuseCreateBranchesCmd = &cobra.Command{
 				}
 			}
 		})
+		writeAbuseReport(cmd, report)
 	},
 }
 
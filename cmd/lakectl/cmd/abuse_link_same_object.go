@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"syscall"
@@ -43,48 +44,50 @@ var abuseLinkSameObjectCmd = &cobra.Command{
 }
 
 func runLinkObject(cmd *cobra.Command, u *uri.URI, generator *stress.Generator) {
-	generator.Run(func(input chan string, output chan stress.Result) {
+	report := generator.Run(func(input chan string, output chan stress.Result) {
 		ctx := cmd.Context()
 		client := getClient()
 		for work := range input {
 			start := time.Now()
-
-			getResponse, err := client.GetPhysicalAddressWithResponse(ctx, u.Repository, u.Ref, &apigen.GetPhysicalAddressParams{Path: work})
-			if err == nil && getResponse.JSON200 == nil {
-				err = helpers.ResponseAsError(getResponse)
-			}
-			if err != nil {
-				output <- stress.Result{
-					Error: err,
-					Took:  time.Since(start),
-				}
-				continue
-			}
-
-			// The code links an "existing object" without actually uploading the object.
-			// This tests the operations done on the lakeFS server side without the overhead of uploading the
-			// object to the object store which should optimally be performed with lakeFS not in the data path (upload using pre-signed urls / set/link).
-			stagingLocation := getResponse.JSON200
-			linkResponse, err := client.LinkPhysicalAddressWithResponse(ctx, u.Repository, u.Ref,
-				&apigen.LinkPhysicalAddressParams{
-					Path: work,
-				},
-				apigen.LinkPhysicalAddressJSONRequestBody{
-					Checksum: "00695c7307b0480c7b6bdc873cf05c15",
-					Staging: apigen.StagingLocation{
-						PhysicalAddress: stagingLocation.PhysicalAddress,
-					},
-					UserMetadata: nil,
-				})
-			if err == nil && linkResponse.JSON200 == nil {
-				err = helpers.ResponseAsError(linkResponse)
-			}
+			err := linkObject(ctx, client, u, work)
 			output <- stress.Result{
 				Error: err,
 				Took:  time.Since(start),
 			}
 		}
 	})
+	writeAbuseReport(cmd, report)
+}
+
+// linkObject stages an object at objPath on branch u
+func linkObject(ctx context.Context, client *apigen.ClientWithResponses, u *uri.URI, objPath string) error {
+	getResponse, err := client.GetPhysicalAddressWithResponse(ctx, u.Repository, u.Ref, &apigen.GetPhysicalAddressParams{Path: objPath})
+	if err == nil && getResponse.JSON200 == nil {
+		err = helpers.ResponseAsError(getResponse)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The code links an "existing object" without actually uploading the object.
+	// This tests the operations done on the lakeFS server side without the overhead of uploading the
+	// object to the object store which should optimally be performed with lakeFS not in the data path (upload using pre-signed urls / set/link).
+	stagingLocation := getResponse.JSON200
+	linkResponse, err := client.LinkPhysicalAddressWithResponse(ctx, u.Repository, u.Ref,
+		&apigen.LinkPhysicalAddressParams{
+			Path: objPath,
+		},
+		apigen.LinkPhysicalAddressJSONRequestBody{
+			Checksum: "00695c7307b0480c7b6bdc873cf05c15",
+			Staging: apigen.StagingLocation{
+				PhysicalAddress: stagingLocation.PhysicalAddress,
+			},
+			UserMetadata: nil,
+		})
+	if err == nil && linkResponse.JSON200 == nil {
+		err = helpers.ResponseAsError(linkResponse)
+	}
+	return err
 }
 
 //nolint:gochecknoinits
@@ -36,7 +36,7 @@ var abuseListCmd = &cobra.Command{
 
 		listPrefix := apigen.PaginationPrefix(prefix)
 		// execute the things!
-		generator.Run(func(input chan string, output chan stress.Result) {
+		report := generator.Run(func(input chan string, output chan stress.Result) {
 			ctx := cmd.Context()
 			client := getClient()
 			for range input {
@@ -53,6 +53,7 @@ var abuseListCmd = &cobra.Command{
 				}
 			}
 		})
+		writeAbuseReport(cmd, report)
 	},
 }
 
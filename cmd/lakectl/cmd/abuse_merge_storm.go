@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/testutil/stress"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	abuseOperationCreateBranch = "create branch"
+	abuseOperationCommit       = "commit"
+	abuseOperationMerge        = "merge"
+	abuseOperationDeleteBranch = "delete branch"
+)
+
+var abuseMergeStormCmd = &cobra.Command{
+	Use:   "merge-storm <branch URI>",
+	Short: "Merge many short-lived branches into the branch concurrently",
+	Long: `Merge many short-lived branches into the branch concurrently: each branch is created from the branch,
+written to, committed and merged back, and then deleted. Every step is reported as a separate operation.`,
+	Hidden:            false,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRef,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		amount := Must(cmd.Flags().GetInt("amount"))
+		parallelism := Must(cmd.Flags().GetInt("parallelism"))
+		branchPrefix := Must(cmd.Flags().GetString("branch-prefix"))
+		objectsPerBranch := Must(cmd.Flags().GetInt("objects-per-branch"))
+		keepBranches := Must(cmd.Flags().GetBool("keep-branches"))
+
+		fmt.Println("Destination branch:", u)
+		generator := stress.NewGenerator("merge storm", parallelism, stress.WithSignalHandlersFor(os.Interrupt, syscall.SIGTERM))
+
+		generator.Setup(func(add stress.GeneratorAddFn) {
+			for i := 0; i < amount; i++ {
+				add(branchPrefix + strconv.Itoa(i))
+			}
+		})
+
+		report := generator.Run(func(input chan string, output chan stress.Result) {
+			ctx := cmd.Context()
+			client := getClient()
+			for branch := range input {
+				mergeStormBranch(ctx, client, u, branch, objectsPerBranch, keepBranches, output)
+			}
+		})
+		writeAbuseReport(cmd, report)
+	},
+}
+
+// mergeStormBranch runs and reports the steps of merging a single branch into u, until the first failing step
+func mergeStormBranch(ctx context.Context, client *apigen.ClientWithResponses, u *uri.URI, branch string, objects int, keepBranch bool, output chan stress.Result) {
+	step := func(op string, fn func() error) bool {
+		start := time.Now()
+		err := fn()
+		output <- stress.Result{
+			Operation: op,
+			Error:     err,
+			Took:      time.Since(start),
+		}
+		return err == nil
+	}
+	branchURI := &uri.URI{Repository: u.Repository, Ref: branch}
+
+	if !step(abuseOperationCreateBranch, func() error {
+		resp, err := client.CreateBranchWithResponse(ctx, u.Repository, apigen.CreateBranchJSONRequestBody{
+			Name:   branch,
+			Source: u.Ref,
+		})
+		if err == nil && resp.StatusCode() != http.StatusCreated {
+			err = helpers.ResponseAsError(resp)
+		}
+		return err
+	}) {
+		return
+	}
+	for i := 0; i < objects; i++ {
+		objPath := fmt.Sprintf("%s/file-%d", branch, i)
+		if !step(abuseOperationWrite, func() error {
+			return linkObject(ctx, client, branchURI, objPath)
+		}) {
+			return
+		}
+	}
+	if !step(abuseOperationCommit, func() error {
+		resp, err := client.CommitWithResponse(ctx, u.Repository, branch, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+			Message: "merge storm " + branch,
+		})
+		if err == nil && resp.StatusCode() != http.StatusCreated {
+			err = helpers.ResponseAsError(resp)
+		}
+		return err
+	}) {
+		return
+	}
+	if !step(abuseOperationMerge, func() error {
+		resp, err := client.MergeIntoBranchWithResponse(ctx, u.Repository, branch, u.Ref, apigen.MergeIntoBranchJSONRequestBody{})
+		if err == nil && resp.StatusCode() != http.StatusOK {
+			err = helpers.ResponseAsError(resp)
+		}
+		return err
+	}) {
+		return
+	}
+	if keepBranch {
+		return
+	}
+	step(abuseOperationDeleteBranch, func() error {
+		resp, err := client.DeleteBranchWithResponse(ctx, u.Repository, branch, &apigen.DeleteBranchParams{})
+		if err == nil && resp.StatusCode() != http.StatusNoContent {
+			err = helpers.ResponseAsError(resp)
+		}
+		return err
+	})
+}
+
+//nolint:gochecknoinits
+func init() {
+	const (
+		defaultAmount      = 100
+		defaultParallelism = 10
+	)
+
+	abuseCmd.AddCommand(abuseMergeStormCmd)
+	abuseMergeStormCmd.Flags().String("branch-prefix", "merge-storm-", "prefix of the merged branches")
+	abuseMergeStormCmd.Flags().Int("objects-per-branch", 1, "amount of objects written to each branch before it is merged")
+	abuseMergeStormCmd.Flags().Bool("keep-branches", false, "do not delete the branches after they are merged")
+	abuseMergeStormCmd.Flags().Int("amount", defaultAmount, "amount of branches to merge")
+	abuseMergeStormCmd.Flags().Int("parallelism", defaultParallelism, "amount of branches to merge in parallel")
+}
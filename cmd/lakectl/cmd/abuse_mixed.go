@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/testutil/stress"
+)
+
+const (
+	abuseOperationRead  = "read"
+	abuseOperationWrite = "write"
+	abuseOperationList  = "list"
+
+	abuseDefaultMix     = "read=70,write=20,list=10"
+	abuseDefaultObjects = 1000
+)
+
+var ErrInvalidOperationMix = errors.New("invalid operation mix")
+
+// abuseOperationMix picks operations at random by their relative weights
+type abuseOperationMix struct {
+	operations []string
+	weights    []int
+	total      int
+}
+
+// parseAbuseOperationMix parses a mix of the form "read=70,write=20,list=10". Weights are relative and need not sum
+// to 100.
+func parseAbuseOperationMix(s string) (*abuseOperationMix, error) {
+	mix := &abuseOperationMix{}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		op, weightStr, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not operation=weight", ErrInvalidOperationMix, part)
+		}
+		switch op {
+		case abuseOperationRead, abuseOperationWrite, abuseOperationList:
+		default:
+			return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidOperationMix, op)
+		}
+		if seen[op] {
+			return nil, fmt.Errorf("%w: operation %q set more than once", ErrInvalidOperationMix, op)
+		}
+		seen[op] = true
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("%w: weight of %q must be a non-negative integer", ErrInvalidOperationMix, op)
+		}
+		if weight == 0 {
+			continue
+		}
+		mix.operations = append(mix.operations, op)
+		mix.weights = append(mix.weights, weight)
+		mix.total += weight
+	}
+	if mix.total == 0 {
+		return nil, fmt.Errorf("%w: no operation has a positive weight", ErrInvalidOperationMix)
+	}
+	return mix, nil
+}
+
+// pick returns the operation of n, a number in [0, total)
+func (m *abuseOperationMix) pick(n int) string {
+	for i, weight := range m.weights {
+		if n < weight {
+			return m.operations[i]
+		}
+		n -= weight
+	}
+	return m.operations[len(m.operations)-1]
+}
+
+var abuseMixedCmd = &cobra.Command{
+	Use:   "mixed <branch URI>",
+	Short: "Generate a mixed workload of reads, writes and lists on the branch",
+	Long: `Generate a mixed workload on the branch: each operation is picked at random by the weights of the mix,
+reads stat and writes link a random object out of a fixed set of objects, and lists list the prefix of the objects.
+The objects are written before the run, so reads find them.`,
+	Example:           "lakectl abuse mixed --mix read=80,write=15,list=5 --results-file results.json lakefs://example-repo/main",
+	Hidden:            false,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRef,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		amount := Must(cmd.Flags().GetInt("amount"))
+		parallelism := Must(cmd.Flags().GetInt("parallelism"))
+		prefix := Must(cmd.Flags().GetString("prefix"))
+		objects := Must(cmd.Flags().GetInt("objects"))
+		populate := Must(cmd.Flags().GetBool("populate"))
+		mix, err := parseAbuseOperationMix(Must(cmd.Flags().GetString("mix")))
+		if err != nil {
+			DieErr(err)
+		}
+		if objects <= 0 {
+			Die("objects must be positive", 1)
+		}
+
+		fmt.Println("Source branch:", u)
+		objectPath := func(i int) string {
+			return fmt.Sprintf("%sfile-%d", prefix, i)
+		}
+
+		if populate {
+			populateGen := stress.NewGenerator("populate", parallelism, stress.WithSignalHandlersFor(os.Interrupt, syscall.SIGTERM))
+			populateGen.Setup(func(add stress.GeneratorAddFn) {
+				for i := 0; i < objects; i++ {
+					add(objectPath(i))
+				}
+			})
+			populateGen.Run(func(input chan string, output chan stress.Result) {
+				ctx := cmd.Context()
+				client := getClient()
+				for work := range input {
+					start := time.Now()
+					err := linkObject(ctx, client, u, work)
+					output <- stress.Result{
+						Error: err,
+						Took:  time.Since(start),
+					}
+				}
+			})
+		}
+
+		generator := stress.NewGenerator("mixed", parallelism, stress.WithSignalHandlersFor(os.Interrupt, syscall.SIGTERM))
+
+		// each input is an operation and the path it works on
+		generator.Setup(func(add stress.GeneratorAddFn) {
+			for i := 0; i < amount; i++ {
+				//nolint:gosec
+				op := mix.pick(rand.Intn(mix.total))
+				//nolint:gosec
+				add(op + " " + objectPath(rand.Intn(objects)))
+			}
+		})
+
+		listPrefix := apigen.PaginationPrefix(prefix)
+		report := generator.Run(func(input chan string, output chan stress.Result) {
+			ctx := cmd.Context()
+			client := getClient()
+			for work := range input {
+				op, objPath, _ := strings.Cut(work, " ")
+				start := time.Now()
+				var err error
+				switch op {
+				case abuseOperationRead:
+					var resp *apigen.StatObjectResponse
+					resp, err = client.StatObjectWithResponse(ctx, u.Repository, u.Ref, &apigen.StatObjectParams{Path: objPath})
+					if err == nil && resp.StatusCode() != http.StatusOK {
+						err = helpers.ResponseAsError(resp)
+					}
+				case abuseOperationWrite:
+					err = linkObject(ctx, client, u, objPath)
+				case abuseOperationList:
+					var resp *apigen.ListObjectsResponse
+					resp, err = client.ListObjectsWithResponse(ctx, u.Repository, u.Ref, &apigen.ListObjectsParams{Prefix: &listPrefix})
+					if err == nil && resp.StatusCode() != http.StatusOK {
+						err = helpers.ResponseAsError(resp)
+					}
+				}
+				output <- stress.Result{
+					Operation: op,
+					Error:     err,
+					Took:      time.Since(start),
+				}
+			}
+		})
+		writeAbuseReport(cmd, report)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	abuseCmd.AddCommand(abuseMixedCmd)
+	abuseMixedCmd.Flags().String("mix", abuseDefaultMix, "relative weights of the operations, of read, write and list")
+	abuseMixedCmd.Flags().String("prefix", "abuse/", "prefix of the objects")
+	abuseMixedCmd.Flags().Int("objects", abuseDefaultObjects, "amount of distinct objects read and written")
+	abuseMixedCmd.Flags().Bool("populate", true, "write the objects before the run")
+	abuseMixedCmd.Flags().Int("amount", abuseDefaultAmount, "amount of operations to do")
+	abuseMixedCmd.Flags().Int("parallelism", abuseDefaultParallelism, "amount of operations to do in parallel")
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAbuseOperationMix(t *testing.T) {
+	mix, err := parseAbuseOperationMix("read=70, write=0,list=30")
+	if err != nil {
+		t.Fatalf("parse mix: %s", err)
+	}
+	if mix.total != 100 {
+		t.Fatalf("expected total weight 100, got %d", mix.total)
+	}
+	// operations with no weight are never picked
+	for n, expected := range map[int]string{0: "read", 69: "read", 70: "list", 99: "list"} {
+		if op := mix.pick(n); op != expected {
+			t.Errorf("pick(%d) = %s, expected %s", n, op, expected)
+		}
+	}
+
+	for _, s := range []string{"", "read", "read=-1", "read=x", "delete=10", "read=10,read=20", "read=0,list=0"} {
+		if _, err := parseAbuseOperationMix(s); !errors.Is(err, ErrInvalidOperationMix) {
+			t.Errorf("parse %q: expected ErrInvalidOperationMix, got %v", s, err)
+		}
+	}
+}
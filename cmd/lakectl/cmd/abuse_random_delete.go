@@ -45,7 +45,7 @@ var abuseRandomDeletesCmd = &cobra.Command{
 		})
 
 		// execute the things!
-		generator.Run(func(input chan string, output chan stress.Result) {
+		report := generator.Run(func(input chan string, output chan stress.Result) {
 			ctx := cmd.Context()
 			client := getClient()
 			for work := range input {
@@ -62,6 +62,7 @@ var abuseRandomDeletesCmd = &cobra.Command{
 				}
 			}
 		})
+		writeAbuseReport(cmd, report)
 	},
 }
 
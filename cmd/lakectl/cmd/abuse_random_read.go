@@ -46,7 +46,7 @@ var abuseRandomReadsCmd = &cobra.Command{
 		})
 
 		// execute the things!
-		generator.Run(func(input chan string, output chan stress.Result) {
+		report := generator.Run(func(input chan string, output chan stress.Result) {
 			ctx := cmd.Context()
 			client := getClient()
 			for work := range input {
@@ -63,6 +63,7 @@ var abuseRandomReadsCmd = &cobra.Command{
 				}
 			}
 		})
+		writeAbuseReport(cmd, report)
 	},
 }
 
@@ -27,7 +27,7 @@ var annotateCmd = &cobra.Command{
 	Short:             "List entries under a given path, annotating each with the latest modifying commit",
 	Aliases:           []string{"blame"},
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := MustParsePathURI("path URI", args[0])
 		recursive := Must(cmd.Flags().GetBool(recursiveFlagName))
@@ -13,7 +13,7 @@ var branchDeleteCmd = &cobra.Command{
 	Short:             "Delete a branch in a repository, along with its uncommitted changes (CAREFUL)",
 	Example:           "lakectl branch delete " + myRepoExample + "/" + myBranchExample,
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		confirmation, err := Confirm(cmd.Flags(), "Are you sure you want to delete branch")
 		if err != nil || !confirmation {
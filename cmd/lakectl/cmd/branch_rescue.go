@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var branchRescueCmd = &cobra.Command{
+	Use:   "rescue <branch URI> [--commit <commit ID>]",
+	Short: "Create a branch at a dangling commit, a commit that cannot be reached from any branch or tag",
+	Long: `Create a branch at a dangling commit, a commit that cannot be reached from any branch or tag.
+Without --commit, the branch is created at the only dangling head of the repository.`,
+	Example:           "lakectl branch rescue " + myRepoExample + "/rescued --commit 2397cc9a9d04c20a4e5739b42c1dd3d8ba655c0b3a3b974850895a13d8bf9917",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		commitID := Must(cmd.Flags().GetString("commit"))
+		ctx := cmd.Context()
+		client := getClient()
+
+		// find the commit among the dangling commits, or the only dangling head
+		var (
+			heads []string
+			found bool
+			after string
+		)
+		for !found {
+			resp, err := client.ListDanglingCommitsWithResponse(ctx, u.Repository, &apigen.ListDanglingCommitsParams{
+				After: apiutil.Ptr(apigen.PaginationAfter(after)),
+			})
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			for _, c := range resp.JSON200.Results {
+				if commitID != "" && c.Commit.Id == commitID {
+					found = true
+					break
+				}
+				if c.Head {
+					heads = append(heads, c.Commit.Id)
+				}
+			}
+			if !resp.JSON200.Pagination.HasMore {
+				break
+			}
+			after = resp.JSON200.Pagination.NextOffset
+		}
+		switch {
+		case commitID != "" && !found:
+			DieFmt("commit %s is not a dangling commit", commitID)
+		case commitID == "" && len(heads) == 0:
+			Die("No dangling commits", 1)
+		case commitID == "" && len(heads) > 1:
+			DieFmt("found %d dangling heads, use --commit to select one (see 'lakectl repo dangling-commits')", len(heads))
+		case commitID == "":
+			commitID = heads[0]
+		}
+
+		resp, err := client.CreateBranchWithResponse(ctx, u.Repository, apigen.CreateBranchJSONRequestBody{
+			Name:   u.Ref,
+			Source: commitID,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		fmt.Printf("created branch '%s' at dangling commit %s\n", u.Ref, commitID)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchRescueCmd.Flags().String("commit", "", "dangling commit ID to create the branch at")
+
+	branchCmd.AddCommand(branchRescueCmd)
+}
@@ -18,7 +18,7 @@ var branchResetCmd = &cobra.Command{
   2. reset uncommitted changes under specific path - reset lakefs://myrepo/main --prefix path
   3. reset uncommitted changes for specific object - reset lakefs://myrepo/main --object path`,
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		clt := getClient()
 		u := MustParseBranchURI("branch URI", args[0])
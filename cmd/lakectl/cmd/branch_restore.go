@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var branchRestoreCmd = &cobra.Command{
+	Use:               "restore <branch URI>",
+	Short:             "Restore a recently deleted branch, without its uncommitted changes",
+	Example:           "lakectl branch restore " + myRepoExample + "/" + myBranchExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseBranchURI("branch URI", args[0])
+		fmt.Println("Branch:", u)
+		resp, err := client.RestoreBranchWithResponse(cmd.Context(), u.Repository, u.Ref, &apigen.RestoreBranchParams{})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Restored branch '%s' at commit %s\n", resp.JSON201.Id, resp.JSON201.CommitId)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	branchCmd.AddCommand(branchRestoreCmd)
+}
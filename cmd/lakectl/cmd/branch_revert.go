@@ -25,7 +25,7 @@ var branchRevertCmd = &cobra.Command{
 		      Revert the changes done by the second last commit to the fourth last commit in example-branch`,
 	Args: cobra.MinimumNArgs(branchRevertCmdArgs),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return validRepositoryToComplete(cmd.Context(), toComplete)
+		return validRefToComplete(cmd.Context(), toComplete)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		u := MustParseBranchURI("branch URI", args[0])
@@ -12,7 +12,7 @@ var branchShowCmd = &cobra.Command{
 	Example:           "lakectl branch show " + myRepoExample + "/" + myBranchExample,
 	Short:             "Show branch latest commit reference",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		u := MustParseBranchURI("branch URI", args[0])
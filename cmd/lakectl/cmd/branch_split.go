@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const (
+	branchSplitCmdArgs = 2
+	splitPrefixFlag    = "prefix"
+)
+
+// lakectl branch split lakefs://myrepo/main commitId --prefix a/ --prefix b/
+var branchSplitCmd = &cobra.Command{
+	Use:   "split <branch URI> <commit ref>",
+	Short: "Split a commit of the branch into commits partitioned by prefixes",
+	Long: `Replace a commit on the branch with a commit of its changes under each prefix, in the given order, followed by a
+commit of its remaining changes. The commits after it on the branch are recreated on top of the new commits with
+the same contents, rewriting the history of the branch.`,
+	Example: `lakectl branch split lakefs://example-repo/example-branch commitA --prefix tables/events/ --prefix tables/users/
+	Split the changes of commitA into commits of the changes under each table, and a commit of the rest`,
+	Args: cobra.ExactArgs(branchSplitCmdArgs),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validRefToComplete(cmd.Context(), toComplete)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		commitRef := args[1]
+		prefixes := Must(cmd.Flags().GetStringArray(splitPrefixFlag))
+		force := Must(cmd.Flags().GetBool("force"))
+		if len(prefixes) == 0 {
+			Die("at least one prefix is required", 1)
+		}
+		fmt.Println("Branch:", u)
+		confirmation, err := Confirm(cmd.Flags(), fmt.Sprintf("Are you sure you want to split commit %s and rewrite the history of the branch after it", commitRef))
+		if err != nil || !confirmation {
+			Die("Split aborted", 1)
+		}
+		clt := getClient()
+		resp, err := clt.SplitCommitWithResponse(cmd.Context(), u.Repository, u.Ref, apigen.SplitCommitJSONRequestBody{
+			Ref:      commitRef,
+			Prefixes: prefixes,
+			Force:    &force,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(commitsTemplate, struct {
+			Commits         []apigen.Commit
+			Pagination      *Pagination
+			ShowMetaRangeID bool
+		}{
+			Commits: resp.JSON201.Results,
+		})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	AssignAutoConfirmFlag(branchSplitCmd.Flags())
+
+	branchSplitCmd.Flags().StringArray(splitPrefixFlag, nil, "path prefix of the changes of a commit, may be repeated (in order)")
+	branchSplitCmd.Flags().Bool("force", false, "split a commit in a read-only repository")
+
+	branchCmd.AddCommand(branchSplitCmd)
+}
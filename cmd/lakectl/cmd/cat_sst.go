@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -17,10 +18,13 @@ import (
 
 const sstCatTemplate = `{{ .Table | table }}`
 
+var ErrUnknownSSTableType = errors.New("could not determine sstable file type")
+
 var catSstCmd = &cobra.Command{
-	Use:    "cat-sst <sst-file>",
-	Short:  "Explore lakeFS .sst files",
-	Hidden: true,
+	Use:     "cat-sst <sst-file>",
+	Aliases: []string{"cat-sstable"},
+	Short:   "Explore lakeFS .sst files",
+	Hidden:  true,
 	Run: func(cmd *cobra.Command, args []string) {
 		amount := Must(cmd.Flags().GetInt("amount"))
 		filePath := Must(cmd.Flags().GetString("file"))
@@ -30,21 +34,7 @@ var catSstCmd = &cobra.Command{
 		}
 		defer iter.Close()
 
-		// get props
-		typ, ok := props[committed.MetadataTypeKey]
-		if !ok {
-			DieFmt("could not determine sstable file type")
-		}
-
-		var table *Table
-		switch typ {
-		case committed.MetadataMetarangesType:
-			table, err = formatMetaRangeSSTable(iter, amount)
-		case committed.MetadataRangesType:
-			table, err = formatRangeSSTable(iter, amount, props[graveler.EntityTypeKey])
-		default:
-			DieFmt("unknown sstable file type: %s", typ)
-		}
+		table, err := formatSSTable(iter, props, amount)
 		if err != nil {
 			DieErr(err)
 		}
@@ -63,9 +53,12 @@ func getIterFromFile(filePath string) (committed.ValueIterator, map[string]strin
 		return nil, nil, err
 	}
 	defer func() { _ = file.Close() }()
+	return getIterFromReader(file)
+}
 
+func getIterFromReader(r io.Reader) (committed.ValueIterator, map[string]string, error) {
 	// read all content
-	content, err := io.ReadAll(file)
+	content, err := io.ReadAll(r)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -87,6 +80,22 @@ func getIterFromFile(filePath string) (committed.ValueIterator, map[string]strin
 	return sstable.NewIterator(iter, dummyDeref), reader.Properties.UserProperties, nil
 }
 
+// formatSSTable formats the entries of an sstable by its type, read from its properties
+func formatSSTable(iter committed.ValueIterator, props map[string]string, amount int) (*Table, error) {
+	typ, ok := props[committed.MetadataTypeKey]
+	if !ok {
+		return nil, ErrUnknownSSTableType
+	}
+	switch typ {
+	case committed.MetadataMetarangesType:
+		return formatMetaRangeSSTable(iter, amount)
+	case committed.MetadataRangesType:
+		return formatRangeSSTable(iter, amount, props[graveler.EntityTypeKey])
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSSTableType, typ)
+	}
+}
+
 func formatEntryRangeSSTable(iter committed.ValueIterator, amount int) (*Table, error) {
 	rows := make([][]interface{}, 0)
 	for iter.Next() {
@@ -21,7 +21,7 @@ var cherryPick = &cobra.Command{
 
 	Args: cobra.ExactArgs(cherryPickCmdArgs),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return validRepositoryToComplete(cmd.Context(), toComplete)
+		return validRefToComplete(cmd.Context(), toComplete)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		ref := MustParseRefURI("commit URI", args[0])
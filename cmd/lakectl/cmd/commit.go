@@ -29,7 +29,7 @@ var commitCmd = &cobra.Command{
 	Use:               "commit <branch URI>",
 	Short:             "Commit changes on a given branch",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		message, kvPairs := getCommitFlags(cmd)
 		date := Must(cmd.Flags().GetInt64(dateFlagName))
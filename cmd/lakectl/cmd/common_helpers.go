@@ -134,6 +134,9 @@ func WriteTo(tpl string, data interface{}, w io.Writer) {
 			}
 			return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "kMGTPE"[exp])
 		},
+		"exact_bytes": func(b int64) string {
+			return strconv.FormatInt(b, 10)
+		},
 		"join": func(sep string, args []string) string {
 			return strings.Join(args, sep)
 		},
@@ -10,7 +10,11 @@ import (
 var completionCmd = &cobra.Command{
 	Use:   "completion <bash|zsh|fish>",
 	Short: "Generate completion script",
-	Long: `To load completions:
+	Long: `Completions of lakeFS URIs suggest repositories, branches, tags and object paths
+read from the configured lakeFS server. Suggestions are cached for a few seconds
+in the user cache directory.
+
+To load completions:
 
 Bash:
 
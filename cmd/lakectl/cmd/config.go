@@ -57,13 +57,20 @@ var configCmd = &cobra.Command{
 			},
 		}
 
+		// with a profile, configure the profile instead of the default settings
+		keyPrefix := ""
+		if profile != "" {
+			fmt.Printf("Profile %s will be configured\n", profile)
+			keyPrefix = "profiles." + profile + "."
+		}
 		for _, question := range questions {
-			question.Prompt.Default = viper.GetString(question.Key)
+			key := keyPrefix + question.Key
+			question.Prompt.Default = viper.GetString(key)
 			val, err := question.Prompt.Run()
 			if err != nil {
 				DieErr(err)
 			}
-			viper.Set(question.Key, val)
+			viper.Set(key, val)
 		}
 
 		err := viper.SafeWriteConfig()
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	lakefsconfig "github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/uri"
+	"gopkg.in/yaml.v3"
+)
+
+// DirectoryContextFileName is the name of the file pinning the lakeFS repository and branch used by lakectl in a
+// directory and its subdirectories
+const DirectoryContextFileName = ".lakefs"
+
+var (
+	ErrInvalidDirectoryContext = errors.New("invalid directory context")
+	ErrProfileNotFound         = errors.New("profile not found")
+)
+
+// ProfileCfg holds the server endpoint and credentials of a named profile in the lakectl configuration
+type ProfileCfg struct {
+	Credentials struct {
+		AccessKeyID     lakefsconfig.OnlyString `mapstructure:"access_key_id"`
+		SecretAccessKey lakefsconfig.OnlyString `mapstructure:"secret_access_key"`
+	} `mapstructure:"credentials"`
+	Server struct {
+		EndpointURL  lakefsconfig.OnlyString `mapstructure:"endpoint_url"`
+		EndpointURLs []string                `mapstructure:"endpoint_urls"`
+	} `mapstructure:"server"`
+}
+
+// DirectoryContext is the content of a DirectoryContextFileName file
+type DirectoryContext struct {
+	Repository string `yaml:"repository"`
+	Branch     string `yaml:"branch"`
+}
+
+// BaseURI returns the base URI relative lakeFS addresses are parsed with in the context
+func (d *DirectoryContext) BaseURI() string {
+	u := uri.URI{Repository: d.Repository, Ref: d.Branch}
+	return u.String()
+}
+
+// FindDirectoryContext reads the closest DirectoryContextFileName file in dir or its parents, and returns it with its
+// path. Returns a nil context if there is none.
+func FindDirectoryContext(dir string) (*DirectoryContext, string, error) {
+	for {
+		p := filepath.Join(dir, DirectoryContextFileName)
+		info, err := os.Stat(p)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, p, err
+		case !info.IsDir():
+			dc, err := readDirectoryContext(p)
+			return dc, p, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, "", nil
+		}
+		dir = parent
+	}
+}
+
+func readDirectoryContext(p string) (*DirectoryContext, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var dc DirectoryContext
+	if err := yaml.Unmarshal(data, &dc); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", p, ErrInvalidDirectoryContext, err)
+	}
+	if dc.Repository == "" {
+		return nil, fmt.Errorf("%s: %w: missing repository", p, ErrInvalidDirectoryContext)
+	}
+	return &dc, nil
+}
+
+// applyProfile replaces the server endpoint and credentials of the configuration with those of the named profile
+func applyProfile(c *Configuration, name string) error {
+	// configuration keys, and so profile names, are case-insensitive
+	profile, ok := c.Profiles[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if profile.Server.EndpointURL != "" || len(profile.Server.EndpointURLs) > 0 {
+		c.Server.EndpointURL = profile.Server.EndpointURL
+		c.Server.EndpointURLs = profile.Server.EndpointURLs
+	}
+	if profile.Credentials.AccessKeyID != "" {
+		c.Credentials.AccessKeyID = profile.Credentials.AccessKeyID
+		c.Credentials.SecretAccessKey = profile.Credentials.SecretAccessKey
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindDirectoryContext(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	dc, _, err := FindDirectoryContext(nested)
+	if err != nil {
+		t.Fatalf("find without context: %s", err)
+	}
+	if dc != nil {
+		t.Fatalf("found context %+v, expected none", dc)
+	}
+
+	contextPath := filepath.Join(root, "a", DirectoryContextFileName)
+	if err := os.WriteFile(contextPath, []byte("repository: repo1\nbranch: main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dc, p, err := FindDirectoryContext(nested)
+	if err != nil {
+		t.Fatalf("find context: %s", err)
+	}
+	if p != contextPath {
+		t.Errorf("context path %s, expected %s", p, contextPath)
+	}
+	if got := dc.BaseURI(); got != "lakefs://repo1/main" {
+		t.Errorf("base URI %s, expected lakefs://repo1/main", got)
+	}
+
+	// a directory with the context file name is not a context
+	if err := os.Mkdir(filepath.Join(nested, DirectoryContextFileName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, p, err = FindDirectoryContext(nested); err != nil || p != contextPath {
+		t.Errorf("find context under directory: %s, %s", p, err)
+	}
+
+	if err := os.WriteFile(contextPath, []byte("branch: main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = FindDirectoryContext(nested); !errors.Is(err, ErrInvalidDirectoryContext) {
+		t.Errorf("find context without repository: %v, expected %s", err, ErrInvalidDirectoryContext)
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	c := &Configuration{}
+	c.Server.EndpointURL = "http://localhost:8000"
+	c.Credentials.AccessKeyID = "default-key"
+	c.Credentials.SecretAccessKey = "default-secret"
+	var staging ProfileCfg
+	staging.Server.EndpointURL = "https://staging.example.com"
+	staging.Credentials.AccessKeyID = "staging-key"
+	staging.Credentials.SecretAccessKey = "staging-secret"
+	c.Profiles = map[string]ProfileCfg{"staging": staging}
+
+	if err := applyProfile(c, "missing"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("apply missing profile: %v, expected %s", err, ErrProfileNotFound)
+	}
+	if err := applyProfile(c, "Staging"); err != nil {
+		t.Fatalf("apply profile: %s", err)
+	}
+	if c.Server.EndpointURL != "https://staging.example.com" || c.Credentials.AccessKeyID != "staging-key" || c.Credentials.SecretAccessKey != "staging-secret" {
+		t.Errorf("configuration after applying profile: %+v", c)
+	}
+}
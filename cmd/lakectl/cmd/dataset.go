@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const datasetReleaseTemplate = `Dataset: {{ .Dataset | yellow }}
+Version: {{ .Version | yellow }}
+{{ if .Description }}Description: {{ .Description }}
+{{ end }}{{ if .CreatedBy }}Created By: {{ .CreatedBy }}
+{{ end }}Creation Date: {{ .CreationDate | date }}
+Sources:
+{{ range $source := .Sources }}  lakefs://{{ $source.Repository }}/{{ $source.CommitId }}/{{ $source.Prefix }} (ref {{ $source.Ref }})
+{{ end }}`
+
+// datasetCmd represents the dataset command
+var datasetCmd = &cobra.Command{
+	Use:   "dataset",
+	Short: "Release and resolve versions of datasets",
+	Long:  `Release data of one or more repositories under a semantic version of a dataset, and resolve dataset references such as dataset:customers@^1.4 to the commits of a release. Released versions never change.`,
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(datasetCmd)
+}
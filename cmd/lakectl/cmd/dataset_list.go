@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var datasetListCmd = &cobra.Command{
+	Use:     "list <dataset>",
+	Short:   "List the releases of a dataset, latest version first",
+	Example: "lakectl dataset list customers",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+
+		client := getClient()
+		resp, err := client.ListDatasetReleasesWithResponse(cmd.Context(), args[0], &apigen.ListDatasetReleasesParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, row := range results {
+			rows[i] = []interface{}{row.Version, len(row.Sources), time.Unix(row.CreationDate, 0).String(), apiutil.Value(row.CreatedBy), apiutil.Value(row.Description)}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Version", "Sources", "Creation Date", "Created By", "Description"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := datasetListCmd.Flags()
+	flags.Int("amount", defaultAmountArgumentValue, "number of results to return")
+	flags.String("after", "", "show results after this version (used for pagination)")
+
+	datasetCmd.AddCommand(datasetListCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var datasetReleaseCmd = &cobra.Command{
+	Use:     "release <dataset> <version>",
+	Short:   "Release a version of a dataset",
+	Long:    `Release a version of a dataset from one or more sources. The ref of each source is resolved to its commit, the objects of the release are the ones under the source path at that commit.`,
+	Example: "lakectl dataset release customers 1.4.2 --source " + myRepoExample + "/" + myBranchExample + "/customers/",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		sources := Must(cmd.Flags().GetStringSlice("source"))
+		description := Must(cmd.Flags().GetString("description"))
+		if len(sources) == 0 {
+			Die("at least one --source is required", 1)
+		}
+		body := apigen.CreateDatasetReleaseJSONRequestBody{
+			Version: args[1],
+		}
+		if description != "" {
+			body.Description = apiutil.Ptr(description)
+		}
+		for _, source := range sources {
+			u := MustParsePathURI("source URI", source)
+			body.Sources = append(body.Sources, apigen.DatasetReleaseSourceCreation{
+				Repository: u.Repository,
+				Ref:        u.Ref,
+				Prefix:     apiutil.Ptr(u.GetPath()),
+			})
+		}
+
+		client := getClient()
+		resp, err := client.CreateDatasetReleaseWithResponse(cmd.Context(), args[0], body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(datasetReleaseTemplate, resp.JSON201)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := datasetReleaseCmd.Flags()
+	flags.StringSlice("source", nil, "path URI of data of the release, may be repeated")
+	flags.String("description", "", "description of the release")
+
+	datasetCmd.AddCommand(datasetReleaseCmd)
+}
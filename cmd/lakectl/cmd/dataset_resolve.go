@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var datasetResolveCmd = &cobra.Command{
+	Use:   "resolve <dataset reference>",
+	Short: "Resolve a dataset reference to the commits of a release",
+	Long:  `Resolve a dataset reference, dataset:<name>@<version>, to a release. The version may be an exact version, a constraint such as ^1.4 or ~1.4.0 resolved to the latest matching release, or latest, the default.`,
+	Example: `lakectl dataset resolve dataset:customers@1.4.2
+lakectl dataset resolve 'dataset:customers@^1.4'`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		resp, err := client.ResolveDatasetReleaseWithResponse(cmd.Context(), &apigen.ResolveDatasetReleaseParams{
+			Reference: args[0],
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(datasetReleaseTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	datasetCmd.AddCommand(datasetResolveCmd)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const (
+	dbtMacrosFilename      = "lakefs.sql"
+	dbtRunIDTimeFormat     = "20060102150405"
+	dbtDefaultBranchPrefix = "dbt-"
+
+	dbtMacrosTemplate = `{# Generated by "lakectl dbt" - do not edit, the file is overwritten on every run #}
+
+{% macro lakefs_repository() %}{{ return('__REPOSITORY__') }}{% endmacro %}
+
+{% macro lakefs_branch() %}{{ return('__BRANCH__') }}{% endmacro %}
+
+{% macro lakefs_schema() %}{{ return('__SCHEMA__') }}{% endmacro %}
+
+{# lakefs_location returns the S3 gateway location of path on the run branch #}
+{% macro lakefs_location(path) %}{{ return('s3://__REPOSITORY__/__BRANCH__/' ~ path) }}{% endmacro %}
+`
+	dbtGenerateSchemaNameTemplate = `
+{# Place every model of the run in the schema of the run branch #}
+{% macro generate_schema_name(custom_schema_name, node) -%}
+    {%- if custom_schema_name is none -%}
+        {{ lakefs_schema() }}
+    {%- else -%}
+        {{ lakefs_schema() }}_{{ custom_schema_name | trim }}
+    {%- endif -%}
+{%- endmacro %}
+`
+)
+
+var dbtSchemaInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+var dbtCmd = &cobra.Command{
+	Use:   "dbt",
+	Short: "Run dbt models in isolation on a lakeFS branch",
+	Long: `Run dbt models in isolation on a lakeFS branch.
+Each dbt run works on its own branch, created from the source branch. Macros pointing at the run branch are generated
+into the dbt project, and the run branch is merged back once the run succeeds.`,
+}
+
+// dbtBranchSchema returns the database schema name used for models built on branch
+func dbtBranchSchema(branch string) string {
+	return strings.ToLower(dbtSchemaInvalidChars.ReplaceAllString(branch, "_"))
+}
+
+// dbtWriteMacros generates the lakeFS macros for the run branch into the project macros directory
+func dbtWriteMacros(projectRoot string, branchURI *uri.URI, generateSchemaName bool) (string, error) {
+	replacer := strings.NewReplacer(
+		"__REPOSITORY__", branchURI.Repository,
+		"__BRANCH__", branchURI.Ref,
+		"__SCHEMA__", dbtBranchSchema(branchURI.Ref),
+	)
+	content := replacer.Replace(dbtMacrosTemplate)
+	if generateSchemaName {
+		content += dbtGenerateSchemaNameTemplate
+	}
+	macrosDir := filepath.Join(projectRoot, "macros")
+	const dirPerm = 0o755
+	if err := os.MkdirAll(macrosDir, dirPerm); err != nil {
+		return "", err
+	}
+	macrosPath := filepath.Join(macrosDir, dbtMacrosFilename)
+	const filePerm = 0o644
+	if err := os.WriteFile(macrosPath, []byte(content), filePerm); err != nil {
+		return "", err
+	}
+	return macrosPath, nil
+}
+
+// dbtStart creates the run branch from sourceURI and generates the macros pointing at it
+func dbtStart(cmd *cobra.Command, client *apigen.ClientWithResponses, sourceURI *uri.URI) *uri.URI {
+	runID := Must(cmd.Flags().GetString("run-id"))
+	prefix := Must(cmd.Flags().GetString("branch-prefix"))
+	projectRoot := Must(cmd.Flags().GetString("project-root"))
+	generateSchemaName := Must(cmd.Flags().GetBool("generate-schema-name"))
+	if runID == "" {
+		runID = time.Now().UTC().Format(dbtRunIDTimeFormat)
+	}
+	branchURI := &uri.URI{Repository: sourceURI.Repository, Ref: prefix + runID}
+
+	resp, err := client.CreateBranchWithResponse(cmd.Context(), branchURI.Repository, apigen.CreateBranchJSONRequestBody{
+		Name:   branchURI.Ref,
+		Source: sourceURI.Ref,
+	})
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+	fmt.Printf("Created run branch %s from %s\n", branchURI, sourceURI)
+
+	macrosPath, err := dbtWriteMacros(projectRoot, branchURI, generateSchemaName)
+	if err != nil {
+		DieFmt("Failed to generate macros: %s", err)
+	}
+	fmt.Printf("Generated macros %s (schema %s)\n", macrosPath, dbtBranchSchema(branchURI.Ref))
+	return branchURI
+}
+
+// dbtFinish commits the run branch, merges it into destinationURI and optionally deletes it
+func dbtFinish(ctx context.Context, client *apigen.ClientWithResponses, branchURI, destinationURI *uri.URI, message string, deleteBranch bool) {
+	if branchURI.Repository != destinationURI.Repository {
+		Die("run branch and destination branch must belong to the same repository", 1)
+	}
+	allowEmpty := true
+	commitResp, err := client.CommitWithResponse(ctx, branchURI.Repository, branchURI.Ref, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+		Message:    message,
+		AllowEmpty: &allowEmpty,
+	})
+	DieOnErrorOrUnexpectedStatusCode(commitResp, err, http.StatusCreated)
+	fmt.Printf("Committed run branch %s\n", branchURI)
+
+	mergeResp, err := client.MergeIntoBranchWithResponse(ctx, branchURI.Repository, branchURI.Ref, destinationURI.Ref, apigen.MergeIntoBranchJSONRequestBody{
+		Message:    &message,
+		AllowEmpty: &allowEmpty,
+	})
+	if mergeResp != nil && mergeResp.JSON409 != nil {
+		DieFmt("Conflict found merging %s into %s, run branch was kept", branchURI, destinationURI)
+	}
+	DieOnErrorOrUnexpectedStatusCode(mergeResp, err, http.StatusOK)
+	fmt.Printf("Merged %s into %s\n", branchURI, destinationURI)
+
+	if deleteBranch {
+		deleteResp, err := client.DeleteBranchWithResponse(ctx, branchURI.Repository, branchURI.Ref, &apigen.DeleteBranchParams{})
+		DieOnErrorOrUnexpectedStatusCode(deleteResp, err, http.StatusNoContent)
+		fmt.Printf("Deleted run branch %s\n", branchURI)
+	}
+}
+
+func withDBTStartFlags(cmd *cobra.Command) {
+	cmd.Flags().String("run-id", "", "identifier of the run, used to name the run branch (default current UTC time)")
+	cmd.Flags().String("branch-prefix", dbtDefaultBranchPrefix, "prefix of the run branch name")
+	cmd.Flags().String("project-root", ".", "location of the dbt project")
+	cmd.Flags().Bool("generate-schema-name", true, "generate a generate_schema_name macro placing models in the run branch schema")
+}
+
+func withDBTFinishFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("message", "m", "dbt run", "commit and merge message")
+	cmd.Flags().Bool("delete-branch", true, "delete the run branch after a successful merge")
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(dbtCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const dbtFinishCmdArgs = 2
+
+var dbtFinishCmd = &cobra.Command{
+	Use:               "finish <run branch URI> <destination branch URI>",
+	Short:             "Commit the run branch and merge it into the destination branch",
+	Example:           "lakectl dbt finish " + myRepoExample + "/dbt-20231016120000 " + myRepoExample + "/main",
+	Args:              cobra.ExactArgs(dbtFinishCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		branchURI := MustParseBranchURI("run branch URI", args[0])
+		destinationURI := MustParseBranchURI("destination branch URI", args[1])
+		message := Must(cmd.Flags().GetString("message"))
+		deleteBranch := Must(cmd.Flags().GetBool("delete-branch"))
+		client := getClient()
+		dbtFinish(cmd.Context(), client, branchURI, destinationURI, message, deleteBranch)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	withDBTFinishFlags(dbtFinishCmd)
+	dbtCmd.AddCommand(dbtFinishCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+const dbtRunCmdMinArgs = 1
+
+var dbtRunCmd = &cobra.Command{
+	Use:   "run <source branch URI> [-- dbt arguments]",
+	Short: "Run dbt on a new branch and merge it into the source branch on success",
+	Long: `Run dbt on a new branch and merge it into the source branch on success.
+A run branch is created from the source branch and dbt macros pointing at it are generated into the project.
+dbt is executed with the given arguments (default "run"), with LAKEFS_REPOSITORY, LAKEFS_BRANCH and LAKEFS_SCHEMA
+set in its environment. When dbt succeeds the run branch is committed and merged into the source branch,
+otherwise the run branch is kept for inspection.`,
+	Example:           "lakectl dbt run " + myRepoExample + "/main --project-root ./my-dbt-project -- build --select my_model",
+	Args:              cobra.MinimumNArgs(dbtRunCmdMinArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceURI := MustParseBranchURI("source branch URI", args[0])
+		dbtArgs := args[1:]
+		if len(dbtArgs) == 0 {
+			dbtArgs = []string{"run"}
+		}
+		dbtExecutable := Must(cmd.Flags().GetString("dbt"))
+		projectRoot := Must(cmd.Flags().GetString("project-root"))
+		message := Must(cmd.Flags().GetString("message"))
+		deleteBranch := Must(cmd.Flags().GetBool("delete-branch"))
+		ctx := cmd.Context()
+		client := getClient()
+
+		branchURI := dbtStart(cmd, client, sourceURI)
+
+		runCommand := exec.CommandContext(ctx, dbtExecutable, dbtArgs...)
+		runCommand.Dir = projectRoot
+		runCommand.Env = append(runCommand.Environ(),
+			"LAKEFS_REPOSITORY="+branchURI.Repository,
+			"LAKEFS_BRANCH="+branchURI.Ref,
+			"LAKEFS_SCHEMA="+dbtBranchSchema(branchURI.Ref),
+		)
+		runCommand.Stdin = os.Stdin
+		runCommand.Stdout = os.Stdout
+		runCommand.Stderr = os.Stderr
+		if err := runCommand.Run(); err != nil {
+			DieFmt("dbt failed: %s\nRun branch %s was kept for inspection", err, branchURI)
+		}
+		fmt.Println("dbt completed successfully")
+
+		dbtFinish(ctx, client, branchURI, sourceURI, message, deleteBranch)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	withDBTStartFlags(dbtRunCmd)
+	withDBTFinishFlags(dbtRunCmd)
+	dbtRunCmd.Flags().String("dbt", "dbt", "dbt executable")
+	dbtCmd.AddCommand(dbtRunCmd)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var dbtStartCmd = &cobra.Command{
+	Use:               "start <source branch URI>",
+	Short:             "Create a run branch from the source branch and generate dbt macros pointing at it",
+	Example:           "lakectl dbt start " + myRepoExample + "/main --project-root ./my-dbt-project",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceURI := MustParseBranchURI("source branch URI", args[0])
+		client := getClient()
+		dbtStart(cmd, client, sourceURI)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	withDBTStartFlags(dbtStartCmd)
+	dbtCmd.AddCommand(dbtStartCmd)
+}
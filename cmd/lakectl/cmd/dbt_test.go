@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+func TestDBTWriteMacros(t *testing.T) {
+	projectRoot := t.TempDir()
+	branchURI := &uri.URI{Repository: "repo", Ref: "dbt-Run.1"}
+
+	macrosPath, err := dbtWriteMacros(projectRoot, branchURI, false)
+	require.NoError(t, err)
+	data, err := os.ReadFile(macrosPath)
+	require.NoError(t, err)
+	macros := string(data)
+	require.Contains(t, macros, "{{ return('dbt-Run.1') }}")
+	require.Contains(t, macros, "{{ return('dbt_run_1') }}")
+	require.Contains(t, macros, "{{ return('s3://repo/dbt-Run.1/' ~ path) }}")
+	require.NotContains(t, macros, "generate_schema_name")
+
+	// regenerate with the schema name override - file is replaced
+	_, err = dbtWriteMacros(projectRoot, branchURI, true)
+	require.NoError(t, err)
+	data, err = os.ReadFile(macrosPath)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(data), "macro generate_schema_name"))
+}
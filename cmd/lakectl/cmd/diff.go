@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
@@ -21,10 +22,23 @@ const (
 	minDiffPageSize = 50
 	maxDiffPageSize = 1000
 
-	twoWayFlagName = "two-way"
-	prefixFlagName = "prefix"
+	twoWayFlagName  = "two-way"
+	prefixFlagName  = "prefix"
+	summaryFlagName = "summary"
+	schemaFlagName  = "schema"
 )
 
+const diffSummaryTemplate = `{{ "Total" | bold }}
+{{ .Total | table -}}
+
+{{ "By prefix" | bold }}
+{{ .ByPrefix | table -}}
+
+{{ "By extension" | bold }}
+{{ .ByExtension | table -}}
+{{ if .Truncated }}{{ "Too many prefixes and extensions to list, the remaining changes are counted only in the total" | yellow }}
+{{ end }}`
+
 var diffCmd = &cobra.Command{
 	Use:   `diff <ref URI> [ref URI]`,
 	Short: "Show changes between two commits, or the currently uncommitted changes",
@@ -46,33 +60,70 @@ var diffCmd = &cobra.Command{
 	Show changes between the tip of the main and the dev branch, including uncommitted changes on dev.
 	
 	lakectl diff --%s some/path lakefs://example-repo/main lakefs://example-repo/dev
-	Show changes of objects prefixed with 'some/path' between the tips of the main and dev branches.`, twoWayFlagName, twoWayFlagName, prefixFlagName),
+	Show changes of objects prefixed with 'some/path' between the tips of the main and dev branches.
+
+	lakectl diff lakefs://example-repo/main/curated/v1/ lakefs://example-repo/main/curated/v2/
+	Show changes from the objects under curated/v1/ to the objects under curated/v2/ on main, comparing objects
+	with the same path relative to each prefix.
+
+	lakectl diff --%s lakefs://example-repo/main lakefs://example-repo/dev
+	Summarize the changes between main and dev, counting objects and bytes by top-level prefix and file extension.
+
+	lakectl diff --%s lakefs://example-repo/main lakefs://example-repo/dev
+	Show the columns added, removed and retyped in CSV, TSV, JSON lines and Parquet objects between the tips of
+	the main and dev branches.`, twoWayFlagName, twoWayFlagName, prefixFlagName, summaryFlagName, schemaFlagName),
 
 	Args: cobra.RangeArgs(diffCmdMinArgs, diffCmdMaxArgs),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) >= diffCmdMaxArgs {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
-		return validRepositoryToComplete(cmd.Context(), toComplete)
+		return validRefToComplete(cmd.Context(), toComplete)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
+		summary := Must(cmd.Flags().GetBool(summaryFlagName))
+		schema := Must(cmd.Flags().GetBool(schemaFlagName))
+		prefix := Must(cmd.Flags().GetString(prefixFlagName))
 		if len(args) == diffCmdMinArgs {
 			// got one arg ref: uncommitted changes diff
 			branchURI := MustParseBranchURI("branch URI", args[0])
 			fmt.Println("Ref:", branchURI)
+			if summary {
+				// uncommitted changes are the changes of the staging area over the branch head
+				printDiffSummary(cmd.Context(), client, branchURI.Repository, branchURI.Ref, branchURI.Ref+"$", true, prefix)
+				return
+			}
+			if schema {
+				printDiffSchema(cmd.Context(), client, branchURI.Repository, branchURI.Ref, branchURI.Ref+"$", prefix)
+				return
+			}
 			printDiffBranch(cmd.Context(), client, branchURI.Repository, branchURI.Ref)
 			return
 		}
 
 		twoWay := Must(cmd.Flags().GetBool(twoWayFlagName))
-		prefix := Must(cmd.Flags().GetString(prefixFlagName))
-		leftRefURI := MustParseRefURI("left ref", args[0])
-		rightRefURI := MustParseRefURI("right ref", args[1])
+		leftRefURI := mustParseDiffURI("left ref", args[0])
+		rightRefURI := mustParseDiffURI("right ref", args[1])
 		fmt.Printf("Left ref: %s\nRight ref: %s\n", leftRefURI, rightRefURI)
 		if leftRefURI.Repository != rightRefURI.Repository {
 			Die("both references must belong to the same repository", 1)
 		}
+		if leftRefURI.Path != nil || rightRefURI.Path != nil {
+			if summary || schema {
+				Die("paths can be compared only when listing changes", 1)
+			}
+			printDiffPrefixes(cmd.Context(), client, leftRefURI, rightRefURI, prefix)
+			return
+		}
+		if summary {
+			printDiffSummary(cmd.Context(), client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref, twoWay, prefix)
+			return
+		}
+		if schema {
+			printDiffSchema(cmd.Context(), client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref, prefix)
+			return
+		}
 		printDiffRefs(cmd.Context(), client, leftRefURI, rightRefURI, twoWay, prefix)
 	},
 }
@@ -128,6 +179,165 @@ func printDiffRefs(ctx context.Context, client apigen.ClientWithResponsesInterfa
 	}
 }
 
+// mustParseDiffURI parses a ref URI, which may include a path to compare the objects under it
+func mustParseDiffURI(name, s string) *uri.URI {
+	if u, err := uri.ParseWithBaseURI(s, baseURI); err == nil && u.GetPath() != "" {
+		return MustParsePathURI(name, s)
+	}
+	return MustParseRefURI(name, s)
+}
+
+// printDiffPrefixes prints the changes from the objects under the path of left to the objects under the path of
+// right, by their paths relative to these paths. Always compares the tips of the refs.
+func printDiffPrefixes(ctx context.Context, client apigen.ClientWithResponsesInterface, left, right *uri.URI, prefix string) {
+	var after string
+	pageSize := pageSize(minDiffPageSize)
+	for {
+		resp, err := client.DiffRefsWithResponse(ctx, left.Repository, left.Ref, right.Ref, &apigen.DiffRefsParams{
+			After:       apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount:      apiutil.Ptr(apigen.PaginationAmount(pageSize)),
+			Prefix:      apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+			Type:        apiutil.Ptr("two_dot"),
+			LeftPrefix:  apiutil.Ptr(left.GetPath()),
+			RightPrefix: apiutil.Ptr(right.GetPath()),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		for _, line := range resp.JSON200.Results {
+			FmtDiff(line, true)
+		}
+		pagination := resp.JSON200.Pagination
+		if !pagination.HasMore {
+			break
+		}
+		after = pagination.NextOffset
+		pageSize.Next()
+	}
+}
+
+func printDiffSummary(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, left, right string, twoDot bool, prefix string) {
+	diffType := apigen.DiffRefsSummaryParamsTypeThreeDot
+	if twoDot {
+		diffType = apigen.DiffRefsSummaryParamsTypeTwoDot
+	}
+	resp, err := client.DiffRefsSummaryWithResponse(ctx, repository, left, right, &apigen.DiffRefsSummaryParams{
+		Prefix: apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		Type:   &diffType,
+	})
+	DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+	if resp.JSON200 == nil {
+		Die("Bad response from server", 1)
+	}
+	summary := resp.JSON200
+
+	byPrefix := make(map[string]*apigen.DiffSummaryStats)
+	byExtension := make(map[string]*apigen.DiffSummaryStats)
+	for _, g := range summary.Groups {
+		addDiffSummaryStats(byPrefix, g.Prefix, g.Stats)
+		addDiffSummaryStats(byExtension, g.Extension, g.Stats)
+	}
+	Write(diffSummaryTemplate, struct {
+		Total       *Table
+		ByPrefix    *Table
+		ByExtension *Table
+		Truncated   bool
+	}{
+		Total:       diffSummaryTable("", map[string]*apigen.DiffSummaryStats{"": &summary.Total}),
+		ByPrefix:    diffSummaryTable("Prefix", byPrefix),
+		ByExtension: diffSummaryTable("Extension", byExtension),
+		Truncated:   summary.Truncated,
+	})
+}
+
+// printDiffSchema prints the column changes of each tabular object that differs between the tips of the refs
+func printDiffSchema(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, left, right, prefix string) {
+	var after string
+	for {
+		resp, err := client.DiffRefsSchemaWithResponse(ctx, repository, left, right, &apigen.DiffRefsSchemaParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(maxDiffPageSize)),
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		for _, d := range resp.JSON200.Results {
+			action, color := diff.Fmt(string(d.Type))
+			_, _ = os.Stdout.WriteString(color.Sprintf("%s %s (%s)\n", action, d.Path, d.Format))
+			if d.Error != nil {
+				_, _ = os.Stdout.WriteString(fmt.Sprintf("    schema not read: %s\n", *d.Error))
+			}
+			for _, change := range d.Changes {
+				_, _ = os.Stdout.WriteString(fmtColumnChange(change))
+			}
+		}
+		pagination := resp.JSON200.Pagination
+		if !pagination.HasMore {
+			break
+		}
+		after = pagination.NextOffset
+	}
+}
+
+func fmtColumnChange(change apigen.ColumnChange) string {
+	leftType := apiutil.Value(change.LeftType)
+	rightType := apiutil.Value(change.RightType)
+	switch change.Type {
+	case apigen.ColumnChangeTypeAdded:
+		return fmt.Sprintf("    + %s %s\n", change.Name, rightType)
+	case apigen.ColumnChangeTypeRemoved:
+		return fmt.Sprintf("    - %s %s\n", change.Name, leftType)
+	default:
+		return fmt.Sprintf("    ~ %s %s -> %s\n", change.Name, leftType, rightType)
+	}
+}
+
+func addDiffSummaryStats(m map[string]*apigen.DiffSummaryStats, key string, stats apigen.DiffSummaryStats) {
+	s, ok := m[key]
+	if !ok {
+		s = &apigen.DiffSummaryStats{}
+		m[key] = s
+	}
+	s.AddedCount += stats.AddedCount
+	s.AddedBytes += stats.AddedBytes
+	s.RemovedCount += stats.RemovedCount
+	s.RemovedBytes += stats.RemovedBytes
+	s.ChangedCount += stats.ChangedCount
+	s.ChangedBytes += stats.ChangedBytes
+}
+
+// diffSummaryTable renders stats as a table, keyed by the keyHeader column unless it is empty
+func diffSummaryTable(keyHeader string, m map[string]*apigen.DiffSummaryStats) *Table {
+	headers := []interface{}{"Added", "Added Bytes", "Removed", "Removed Bytes", "Changed", "Changed Bytes"}
+	if keyHeader != "" {
+		headers = append([]interface{}{keyHeader}, headers...)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rows := make([][]interface{}, 0, len(keys))
+	for _, k := range keys {
+		s := m[k]
+		row := []interface{}{s.AddedCount, s.AddedBytes, s.RemovedCount, s.RemovedBytes, s.ChangedCount, s.ChangedBytes}
+		if keyHeader != "" {
+			name := k
+			if name == "" {
+				name = "(none)"
+			}
+			row = append([]interface{}{name}, row...)
+		}
+		rows = append(rows, row)
+	}
+	return &Table{Headers: headers, Rows: rows}
+}
+
 func FmtDiff(d apigen.Diff, withDirection bool) {
 	action, color := diff.Fmt(d.Type)
 
@@ -146,5 +356,7 @@ func FmtDiff(d apigen.Diff, withDirection bool) {
 func init() {
 	diffCmd.Flags().Bool(twoWayFlagName, false, "Use two-way diff: show difference between the given refs, regardless of a common ancestor.")
 	diffCmd.Flags().String(prefixFlagName, "", "Show only changes in the given prefix.")
+	diffCmd.Flags().Bool(summaryFlagName, false, "Summarize the changes by top-level prefix and file extension instead of listing them.")
+	diffCmd.Flags().Bool(schemaFlagName, false, "Show the column changes of CSV, TSV, JSON lines and Parquet objects. Always compares the tips of the refs.")
 	rootCmd.AddCommand(diffCmd)
 }
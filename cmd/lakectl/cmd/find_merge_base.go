@@ -30,7 +30,7 @@ var findMergeBaseCmd = &cobra.Command{
 		if len(args) >= mergeCmdMaxArgs {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
-		return validRepositoryToComplete(cmd.Context(), toComplete)
+		return validRefToComplete(cmd.Context(), toComplete)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var fsAliasCmd = &cobra.Command{
+	Use:   "alias <target path URI> <alias path URI>",
+	Short: "Create an alias pointing to another object on the same branch",
+	Long: `Create an alias pointing to another object on the same branch.
+Reading the alias at any ref returns the object found at its target on that ref, without copying it.`,
+	Example:           "lakectl fs alias lakefs://example-repo/main/releases/2023-06-01/model.bin lakefs://example-repo/main/latest/model.bin",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsPath,
+	Run: func(cmd *cobra.Command, args []string) {
+		targetURI := MustParsePathURI("target path URI", args[0])
+		aliasURI := MustParsePathURI("alias path URI", args[1])
+		if targetURI.Repository != aliasURI.Repository || targetURI.Ref != aliasURI.Ref {
+			Die("target and alias must be on the same repository and branch", 1)
+		}
+		client := getClient()
+		resp, err := client.CreateObjectAliasWithResponse(cmd.Context(), aliasURI.Repository, aliasURI.Ref, &apigen.CreateObjectAliasParams{
+			Path: *aliasURI.Path,
+		}, apigen.CreateObjectAliasJSONRequestBody{
+			Target: *targetURI.Path,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write("Alias {{ .Path | yellow }} -> {{ .AliasTarget }}\n", resp.JSON201)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsCmd.AddCommand(fsAliasCmd)
+}
@@ -14,7 +14,7 @@ var fsCatCmd = &cobra.Command{
 	Use:               "cat <path URI>",
 	Short:             "Dump content of object to stdout",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := MustParsePathURI("path URI", args[0])
 		client := getClient()
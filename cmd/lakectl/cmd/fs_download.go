@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
 
 	"github.com/go-openapi/swag"
@@ -45,10 +45,11 @@ var fsDownloadCmd = &cobra.Command{
 			}
 			// if dest is a directory, add the file name
 			if s, _ := os.Stat(dest); s != nil && s.IsDir() {
-				dest += uri.PathSeparator
-			}
-			if strings.HasSuffix(dest, uri.PathSeparator) {
-				dest += filepath.Base(remotePath)
+				var err error
+				dest, err = local.LocalPath(dest, path.Base(remotePath))
+				if err != nil {
+					DieErr(err)
+				}
 			}
 
 			d := helpers.NewDownloader(client, syncFlags.Presign)
@@ -13,11 +13,12 @@ var fsLsCmd = &cobra.Command{
 	Use:               "ls <path URI>",
 	Short:             "List entries under a given tree",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		pathURI := MustParsePathURI("path URI", args[0])
 		recursive := Must(cmd.Flags().GetBool(recursiveFlagName))
+		bytesSizes := Must(cmd.Flags().GetBool(fsLsBytesFlagName))
 		prefix := *pathURI.Path
 
 		// prefix we need to trim in ls output (non-recursive)
@@ -53,7 +54,11 @@ var fsLsCmd = &cobra.Command{
 				}
 			}
 
-			Write(fsLsTemplate, results)
+			if bytesSizes {
+				Write(fsLsBytesTemplate, results)
+			} else {
+				Write(fsLsTemplate, results)
+			}
 			pagination := resp.JSON200.Pagination
 			if !pagination.HasMore {
 				break
@@ -66,9 +71,17 @@ var fsLsCmd = &cobra.Command{
 //nolint:gochecknoinits
 func init() {
 	withRecursiveFlag(fsLsCmd, "list all objects under the specified path")
+	fsLsCmd.Flags().Bool(fsLsBytesFlagName, false, "print exact object sizes in bytes")
 	fsCmd.AddCommand(fsLsCmd)
 }
 
+const fsLsBytesFlagName = "bytes"
+
+const fsLsBytesTemplate = `{{ range $val := . -}}
+{{ $val.PathType|ljust 12 }}    {{ if eq $val.PathType "object" }}{{ $val.Mtime|date|ljust 29 }}    {{ $val.SizeBytes|exact_bytes|ljust 12 }}{{ else }}                                            {{ end }}    {{ $val.Path|yellow }}
+{{ end -}}
+`
+
 const fsLsTemplate = `{{ range $val := . -}}
 {{ $val.PathType|ljust 12 }}    {{ if eq $val.PathType "object" }}{{ $val.Mtime|date|ljust 29 }}    {{ $val.SizeBytes|human_bytes|ljust 12 }}{{ else }}                                            {{ end }}    {{ $val.Path|yellow }}
 {{ end -}}
@@ -13,7 +13,7 @@ var fsPresignCmd = &cobra.Command{
 	Use:               "presign <path URI>",
 	Short:             "return a pre-signed URL for reading the specified object",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := MustParsePathURI("path URI", args[0])
 		client := getClient()
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const fsPreviewTemplate = `{{.Table | table -}}
+{{ if .Truncated }}Showing the first {{ len .Table.Rows }} rows{{ end }}
+`
+
+var fsPreviewCmd = &cobra.Command{
+	Use:               "preview <path URI>",
+	Short:             "Show the first rows of a CSV, TSV, JSON lines or Parquet object",
+	Example:           "lakectl fs preview " + myRepoExample + "/" + myBranchExample + "/data/table.csv --rows 20",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsPath,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		rows := Must(cmd.Flags().GetInt("rows"))
+		format := Must(cmd.Flags().GetString("format"))
+		params := &apigen.PreviewObjectParams{
+			Path: *pathURI.Path,
+			Rows: apiutil.Ptr(rows),
+		}
+		if format != "" {
+			params.Format = apiutil.Ptr(apigen.PreviewObjectParamsFormat(format))
+		}
+
+		client := getClient()
+		resp, err := client.PreviewObjectWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, params)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		result := resp.JSON200
+		headers := make([]interface{}, len(result.Columns))
+		for i, column := range result.Columns {
+			headers[i] = fmt.Sprintf("%s (%s)", column.Name, column.Type)
+		}
+		tableRows := make([][]interface{}, len(result.Rows))
+		for i, row := range result.Rows {
+			tableRows[i] = make([]interface{}, len(row))
+			for j, value := range row {
+				tableRows[i][j] = previewValue(value)
+			}
+		}
+		Write(fsPreviewTemplate, struct {
+			Table     *Table
+			Truncated bool
+		}{
+			Table:     &Table{Headers: headers, Rows: tableRows},
+			Truncated: result.Truncated,
+		})
+	},
+}
+
+// previewValue formats nested values as JSON
+func previewValue(value interface{}) interface{} {
+	switch value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(b)
+	default:
+		return value
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsPreviewCmd.Flags().Int("rows", 20, "number of rows to show")
+	fsPreviewCmd.Flags().String("format", "", "object format: csv, tsv, jsonl or parquet (detected by default)")
+
+	fsCmd.AddCommand(fsPreviewCmd)
+}
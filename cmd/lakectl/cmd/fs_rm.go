@@ -19,7 +19,7 @@ var fsRmCmd = &cobra.Command{
 	Use:               "rm <path URI>",
 	Short:             "Delete object",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		recursive := Must(cmd.Flags().GetBool(recursiveFlagName))
 		concurrency := Must(cmd.Flags().GetInt("concurrency"))
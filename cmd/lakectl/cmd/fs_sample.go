@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const fsSampleTemplate = `{{.Table | table -}}
+Sampled {{ len .Table.Rows }} of {{ .RowsRead }} rows in {{ .Objects }} objects at commit {{ .CommitID }}
+`
+
+var fsSampleCmd = &cobra.Command{
+	Use:               "sample <path URI>",
+	Short:             "Show a random sample of the rows of the CSV, TSV, JSON lines or Parquet objects under a prefix",
+	Long:              "Show a random sample of the rows of the CSV, TSV, JSON lines or Parquet objects under a prefix. Sampling the same commit with the same seed shows the same rows.",
+	Example:           "lakectl fs sample " + myRepoExample + "/" + myBranchExample + "/data/table/ --rows 1000 --seed 42",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsPath,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		rows := Must(cmd.Flags().GetInt("rows"))
+		seed := Must(cmd.Flags().GetInt64("seed"))
+		format := Must(cmd.Flags().GetString("format"))
+		params := &apigen.SampleObjectsParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix(*pathURI.Path)),
+			Rows:   apiutil.Ptr(rows),
+			Seed:   apiutil.Ptr(seed),
+		}
+		if format != "" {
+			params.Format = apiutil.Ptr(apigen.SampleObjectsParamsFormat(format))
+		}
+
+		client := getClient()
+		resp, err := client.SampleObjectsWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, params)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		result := resp.JSON200
+		headers := make([]interface{}, len(result.Columns))
+		for i, column := range result.Columns {
+			headers[i] = fmt.Sprintf("%s (%s)", column.Name, column.Type)
+		}
+		tableRows := make([][]interface{}, len(result.Rows))
+		for i, row := range result.Rows {
+			tableRows[i] = make([]interface{}, len(row))
+			for j, value := range row {
+				tableRows[i][j] = previewValue(value)
+			}
+		}
+		Write(fsSampleTemplate, struct {
+			Table    *Table
+			RowsRead int64
+			Objects  int
+			CommitID string
+		}{
+			Table:    &Table{Headers: headers, Rows: tableRows},
+			RowsRead: result.RowsRead,
+			Objects:  result.Objects,
+			CommitID: result.CommitId,
+		})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsSampleCmd.Flags().Int("rows", 20, "number of rows to sample")
+	fsSampleCmd.Flags().Int64("seed", 0, "seed of the random sample")
+	fsSampleCmd.Flags().String("format", "", "format of the objects to sample: csv, tsv, jsonl or parquet (detected by default)")
+
+	fsCmd.AddCommand(fsSampleCmd)
+}
@@ -15,7 +15,7 @@ var fsStageCmd = &cobra.Command{
 The object location must be outside the repository's storage namespace`,
 	Hidden:            true,
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		pathURI := MustParsePathURI("path URI", args[0])
@@ -12,7 +12,7 @@ var fsStatCmd = &cobra.Command{
 	Use:               "stat <path URI>",
 	Short:             "View object metadata",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := MustParsePathURI("path URI", args[0])
 		client := getClient()
@@ -40,7 +40,8 @@ Physical Address: {{ .PhysicalAddress }}
 {{- if .PhysicalAddressExpiry }}
 Physical Address Expires: {{ .PhysicalAddressExpiry|date }}{{end}}
 Checksum: {{ .Checksum }}
-Content-Type: {{ .ContentType }}{{ if and $.Metadata $.Metadata.AdditionalProperties }}
+Content-Type: {{ .ContentType }}{{ if .AliasTarget }}
+Alias Target: {{ .AliasTarget }}{{ end }}{{ if and $.Metadata $.Metadata.AdditionalProperties }}
 Metadata:
 	{{ range $key, $value := .Metadata.AdditionalProperties }}
 	{{ $key | printf "%-18s" }} = {{ $value }}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var fsUpdateMetadataCmd = &cobra.Command{
+	Use:   "update-metadata <path URI>",
+	Short: "Update user metadata and content type of an object without re-uploading it",
+	Long: `Update user metadata and content type of an object on a branch, creating an uncommitted change.
+The object data is not copied. Metadata is merged into the current metadata, unless --replace is set.`,
+	Example:           "lakectl fs update-metadata lakefs://example-repo/main/data/file.csv --meta owner=data-team --content-type text/csv",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsPath,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		meta, err := getKV(cmd, metaFlagName)
+		if err != nil {
+			DieErr(err)
+		}
+		replace := Must(cmd.Flags().GetBool("replace"))
+		body := apigen.UpdateObjectMetadataJSONRequestBody{
+			Metadata: apigen.ObjectUserMetadata{AdditionalProperties: meta},
+			Replace:  &replace,
+		}
+		if cmd.Flags().Changed("content-type") {
+			contentType := Must(cmd.Flags().GetString("content-type"))
+			body.ContentType = &contentType
+		}
+		client := getClient()
+		resp, err := client.UpdateObjectMetadataWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.UpdateObjectMetadataParams{
+			Path: *pathURI.Path,
+		}, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(fsStatTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsUpdateMetadataCmd.Flags().StringSlice(metaFlagName, []string{}, "key value pairs in the form of key=value")
+	fsUpdateMetadataCmd.Flags().String("content-type", "", "MIME type of contents")
+	fsUpdateMetadataCmd.Flags().Bool("replace", false, "replace all user metadata instead of merging into it")
+
+	fsCmd.AddCommand(fsUpdateMetadataCmd)
+}
@@ -19,7 +19,7 @@ var fsUploadCmd = &cobra.Command{
 	Use:               "upload <path URI>",
 	Short:             "Upload a local file to the specified URI",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsPath,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		pathURI, _ := getSyncArgs(args, true, false)
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+const (
+	fsVerifyCmdArgs          = 2
+	fsVerifySizeOnlyFlagName = "size-only"
+
+	// fsVerifyUnknownChecksumExitCode is the exit code when the object checksum cannot be verified
+	fsVerifyUnknownChecksumExitCode = 2
+)
+
+const fsVerifyTemplate = `Path: {{ .Path | yellow }}
+Size: {{ .Size }} bytes ({{ .Size|human_bytes }})
+Checksum: {{ .Checksum }}{{ if .PartSize }} (part size {{ .PartSize }} bytes){{ end }}
+{{ "Verified" | green }}
+`
+
+var fsVerifyCmd = &cobra.Command{
+	Use:   "verify <path URI> <local file>",
+	Short: "Verify that a local file matches an object",
+	Long: `Compare the size and checksum of a local file with an object, without downloading the object.
+Checksums of objects uploaded in parts (multipart ETags) are verified using the part size given by --part-size,
+or else the common part sizes that could have produced the ETag.
+Exits with code 1 when the file does not match, or 2 when the object checksum cannot be verified.`,
+	Example:           "lakectl fs verify lakefs://example-repo/main/data/file.parquet ./file.parquet",
+	Args:              cobra.ExactArgs(fsVerifyCmdArgs),
+	ValidArgsFunction: ValidArgsPath,
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := MustParsePathURI("path URI", args[0])
+		localPath := args[1]
+		partSize := Must(cmd.Flags().GetInt64(partSizeFlagName))
+		sizeOnly := Must(cmd.Flags().GetBool(fsVerifySizeOnlyFlagName))
+		client := getClient()
+
+		resp, err := client.StatObjectWithResponse(cmd.Context(), pathURI.Repository, pathURI.Ref, &apigen.StatObjectParams{
+			Path: *pathURI.Path,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		stats := resp.JSON200
+
+		f, err := os.Open(localPath)
+		if err != nil {
+			DieErr(err)
+		}
+		defer func() { _ = f.Close() }()
+		info, err := f.Stat()
+		if err != nil {
+			DieErr(err)
+		}
+		remoteSize := swag.Int64Value(stats.SizeBytes)
+		if info.Size() != remoteSize {
+			DieFmt("Size mismatch: local file %s is %d bytes, object %s is %d bytes", localPath, info.Size(), pathURI, remoteSize)
+		}
+
+		result := struct {
+			Path     string
+			Size     int64
+			Checksum string
+			PartSize int64
+		}{
+			Path:     pathURI.String(),
+			Size:     remoteSize,
+			Checksum: stats.Checksum,
+		}
+		if sizeOnly {
+			result.Checksum = "not verified"
+			Write(fsVerifyTemplate, result)
+			return
+		}
+		match, matchedPartSize, err := helpers.VerifyChecksum(f, info.Size(), stats.Checksum, partSize)
+		if errors.Is(err, helpers.ErrUnknownChecksum) {
+			Die(fmt.Sprintf("Cannot verify checksum of %s: %s (use --%s to compare sizes only)", pathURI, err, fsVerifySizeOnlyFlagName), fsVerifyUnknownChecksumExitCode)
+		}
+		if err != nil {
+			DieErr(err)
+		}
+		if !match {
+			DieFmt("Checksum mismatch: local file %s does not match object %s checksum %s", localPath, pathURI, stats.Checksum)
+		}
+		result.PartSize = matchedPartSize
+		Write(fsVerifyTemplate, result)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fsVerifyCmd.Flags().Int64(partSizeFlagName, 0, "part size in bytes the object was uploaded with, to verify multipart checksums (default: try common part sizes)")
+	fsVerifyCmd.Flags().Bool(fsVerifySizeOnlyFlagName, false, "compare sizes only")
+	fsCmd.AddCommand(fsVerifyCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const gcCollectMetadataTemplate = `{{ if .DryRun }}Files to collect{{ else }}Collected files{{ end }}: {{ .CollectedFiles }} ({{ .CollectedBytes | human_bytes }})
+Reachable metaranges: {{ .ReachableMetaranges }}
+Reachable ranges: {{ .ReachableRanges }}
+Skipped recent files: {{ .SkippedFiles }}
+{{ if .ArchivePath }}Archived to: {{ .ArchivePath }}
+{{ end }}{{ range .Files }}  {{ . }}
+{{ end }}`
+
+var gcCollectMetadataCmd = &cobra.Command{
+	Use:   "collect-metadata <repository URI>",
+	Short: "Delete range and metarange files not used by any branch or tag",
+	Long: `Delete the range and metarange files of a repository that are not used by any commit reachable from a branch
+or a tag, such as files left by rewritten history or by failed commits. Only files older than --min-age are
+collected. Data objects are not collected by this command.`,
+	Example:           "lakectl gc collect-metadata " + myRepoExample + " --dry-run",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+		dryRun := Must(cmd.Flags().GetBool("dry-run"))
+		archive := Must(cmd.Flags().GetBool("archive"))
+		minAge := Must(cmd.Flags().GetDuration("min-age"))
+		keepDangling := Must(cmd.Flags().GetBool("keep-dangling-commits"))
+		body := apigen.CollectOrphanMetadataJSONRequestBody{
+			DryRun:              apiutil.Ptr(dryRun),
+			Archive:             apiutil.Ptr(archive),
+			KeepDanglingCommits: apiutil.Ptr(keepDangling),
+		}
+		if minAge > 0 {
+			body.MinAgeSeconds = apiutil.Ptr(int64(minAge.Seconds()))
+		}
+		client := getClient()
+		resp, err := client.CollectOrphanMetadataWithResponse(cmd.Context(), u.Repository, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(gcCollectMetadataTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	gcCollectMetadataCmd.Flags().Bool("dry-run", false, "only list the files that would be collected")
+	gcCollectMetadataCmd.Flags().Bool("archive", false, "copy collected files under the archive path before deleting them")
+	gcCollectMetadataCmd.Flags().Duration("min-age", 0, "age of the youngest file collected (default 6h)")
+	gcCollectMetadataCmd.Flags().Bool("keep-dangling-commits", false, "keep the metadata of commits that cannot be reached from any branch or tag")
+
+	gcCmd.AddCommand(gcCollectMetadataCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const gcRunLocalTemplate = `Garbage collection started: {{ .Id }}
+Track it with: lakectl repo operations show {{ .Repository }} {{ .Id }}
+`
+
+var gcRunLocalCmd = &cobra.Command{
+	Use:   "run-local <repository URI>",
+	Short: "Start garbage collection of a repository stored on the local blockstore",
+	Long: `Start garbage collection of the objects of a repository stored on the local blockstore, which the Spark garbage
+collection client cannot reach. The storage namespace is snapshotted with hard links, the objects of the commits
+retained by the repository garbage collection rules and of all branches are marked, and the other snapshot objects
+older than --min-age are removed in batches. The operation reports the space reclaimed.`,
+	Example:           "lakectl gc run-local " + myRepoExample + " --dry-run",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+		dryRun := Must(cmd.Flags().GetBool("dry-run"))
+		minAge := Must(cmd.Flags().GetDuration("min-age"))
+		body := apigen.RunLocalGarbageCollectionJSONRequestBody{
+			DryRun: apiutil.Ptr(dryRun),
+		}
+		if minAge > 0 {
+			body.MinAgeSeconds = apiutil.Ptr(int64(minAge.Seconds()))
+		}
+		client := getClient()
+		resp, err := client.RunLocalGarbageCollectionWithResponse(cmd.Context(), u.Repository, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusAccepted)
+		if resp.JSON202 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(gcRunLocalTemplate, struct {
+			Id         string
+			Repository string
+		}{Id: resp.JSON202.Id, Repository: u.String()})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	gcRunLocalCmd.Flags().Bool("dry-run", false, "only report the objects and space that would be reclaimed")
+	gcRunLocalCmd.Flags().Duration("min-age", 0, "age of the youngest object collected (default 6h)")
+
+	gcCmd.AddCommand(gcRunLocalCmd)
+}
@@ -68,7 +68,7 @@ func localDiff(ctx context.Context, client apigen.ClientWithResponsesInterface,
 		return local.ListRemote(ctx, client, remote, currentRemoteState, includePOSIXPermissions)
 	})
 
-	changes, err := local.DiffLocalWithHead(currentRemoteState, path, includePOSIXPermissions, includePOSIXPermissions)
+	changes, err := local.DiffLocalWithHead(currentRemoteState, path, includePOSIXPermissions, includePOSIXPermissions, cfg.Local.IgnoreLineEndings)
 	if err != nil {
 		DieErr(err)
 	}
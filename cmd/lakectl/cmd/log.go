@@ -68,23 +68,28 @@ func (d *dotWriter) Write(commits []apigen.Commit) {
 
 // logCmd represents the log command
 var logCmd = &cobra.Command{
-	Use:               "log <branch URI>",
-	Short:             "Show log of commits",
-	Long:              "Show log of commits for a given branch",
-	Example:           "lakectl log --dot lakefs://example-repository/main | dot -Tsvg > graph.svg",
+	Use:   "log <branch URI>",
+	Short: "Show log of commits",
+	Long:  "Show log of commits for a given branch",
+	Example: `lakectl log --dot lakefs://example-repository/main | dot -Tsvg > graph.svg
+lakectl log --graph lakefs://example-repository/main`,
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		amount := Must(cmd.Flags().GetInt("amount"))
 		after := Must(cmd.Flags().GetString("after"))
 		limit := Must(cmd.Flags().GetBool("limit"))
 		since := Must(cmd.Flags().GetString("since"))
 		dot := Must(cmd.Flags().GetBool("dot"))
+		asciiGraph := Must(cmd.Flags().GetBool("graph"))
 		firstParent := Must(cmd.Flags().GetBool("first-parent"))
 		objects := Must(cmd.Flags().GetStringSlice("objects"))
 		prefixes := Must(cmd.Flags().GetStringSlice("prefixes"))
 		stopAt := Must(cmd.Flags().GetString("stop-at"))
 
+		if dot && asciiGraph {
+			Die("Can't use both --dot and --graph", 1)
+		}
 		if slices.Contains(objects, "") {
 			Die("Objects list contains empty string!", 1)
 		}
@@ -128,6 +133,10 @@ var logCmd = &cobra.Command{
 		if dot {
 			graph.Start()
 		}
+		asciiGraphWriter := &graphWriter{
+			w:           os.Stdout,
+			firstParent: firstParent,
+		}
 
 		for pagination.HasMore {
 			resp, err := client.LogCommitsWithResponse(cmd.Context(), branchURI.Repository, branchURI.Ref, logCommitsParams)
@@ -151,9 +160,15 @@ var logCmd = &cobra.Command{
 				},
 			}
 
-			if dot {
+			switch {
+			case dot:
 				graph.Write(data.Commits)
-			} else {
+			case asciiGraph:
+				asciiGraphWriter.Write(data.Commits)
+				if amount != 0 && pagination.HasMore {
+					Write("{{.Pagination | paginate }}", data)
+				}
+			default:
 				Write(commitsTemplate, data)
 			}
 
@@ -176,6 +191,7 @@ func init() {
 	logCmd.Flags().Bool("limit", false, "limit result just to amount. By default, returns whether more items are available.")
 	logCmd.Flags().String("after", "", "show results after this value (used for pagination)")
 	logCmd.Flags().Bool("dot", false, "return results in a dotgraph format")
+	logCmd.Flags().Bool("graph", false, "draw a text-based graph of the commit history, one line per commit")
 	logCmd.Flags().Bool("first-parent", false, "follow only the first parent commit upon seeing a merge commit")
 	logCmd.Flags().Bool("show-meta-range-id", false, "also show meta range ID")
 	logCmd.Flags().StringSlice("objects", nil, "show results that contains changes to at least one path in that list of objects. Use comma separator to pass all objects together")
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const graphShortIDLength = 8
+
+// graphWriter renders commits as an ASCII commit graph, one line per commit. Each column of the graph tracks the
+// commit expected next on a line of history: a merge commit opens a column for each of its other parents, and
+// columns waiting for the same commit join when it is reached.
+type graphWriter struct {
+	w           io.Writer
+	firstParent bool
+	columns     []string
+}
+
+func shortCommitID(id string) string {
+	if len(id) > graphShortIDLength {
+		return id[:graphShortIDLength]
+	}
+	return id
+}
+
+func (g *graphWriter) writeLine(cells []string, suffix string) {
+	_, _ = fmt.Fprintln(g.w, strings.TrimRight(strings.Join(cells, " ")+" "+suffix, " "))
+}
+
+func (g *graphWriter) Write(commits []apigen.Commit) {
+	for _, commit := range commits {
+		g.writeCommit(commit)
+	}
+}
+
+func (g *graphWriter) writeCommit(commit apigen.Commit) {
+	idx := -1
+	for i, id := range g.columns {
+		if id == commit.Id {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		// a commit no column waits for starts a new line of history
+		g.columns = append(g.columns, commit.Id)
+		idx = len(g.columns) - 1
+	}
+
+	// join the other columns waiting for this commit: the branch point of their lines of history
+	joined := false
+	cells := make([]string, len(g.columns))
+	columns := g.columns[:0]
+	for i, id := range g.columns {
+		cells[i] = "|"
+		if i > idx && id == commit.Id {
+			cells[i] = "/"
+			joined = true
+			continue
+		}
+		columns = append(columns, id)
+	}
+	if joined {
+		g.writeLine(cells, "")
+	}
+	g.columns = columns
+
+	cells = make([]string, len(g.columns))
+	for i := range g.columns {
+		cells[i] = "|"
+	}
+	cells[idx] = "*"
+	message, _, _ := strings.Cut(commit.Message, "\n")
+	line := text.FgHiYellow.Sprint(shortCommitID(commit.Id)) + " " + message
+	if g.firstParent && len(commit.Parents) > 1 {
+		merged := make([]string, 0, len(commit.Parents)-1)
+		for _, parent := range commit.Parents[1:] {
+			merged = append(merged, shortCommitID(parent))
+		}
+		line += " " + text.Bold.Sprint("(merge of "+strings.Join(merged, ", ")+")")
+	}
+	g.writeLine(cells, line)
+
+	g.updateColumns(idx, commit)
+}
+
+// updateColumns replaces the column of commit with columns for its parents
+func (g *graphWriter) updateColumns(idx int, commit apigen.Commit) {
+	if len(commit.Parents) == 0 {
+		g.columns = append(g.columns[:idx], g.columns[idx+1:]...)
+		return
+	}
+	g.columns[idx] = commit.Parents[0]
+	if g.firstParent {
+		return
+	}
+	var opened []string
+	for _, parent := range commit.Parents[1:] {
+		found := false
+		for _, id := range g.columns {
+			if id == parent {
+				found = true
+				break
+			}
+		}
+		if !found {
+			opened = append(opened, parent)
+		}
+	}
+	if len(opened) == 0 {
+		return
+	}
+	// draw the merge: the new columns open to the right of the commit, shifting the columns after it
+	cells := make([]string, 0, len(g.columns)+len(opened))
+	for i := range g.columns {
+		switch {
+		case i < idx:
+			cells = append(cells, "|")
+		case i == idx:
+			cells = append(cells, "|"+strings.Repeat("\\", len(opened)))
+		default:
+			cells = append(cells, "\\")
+		}
+	}
+	g.writeLine(cells, "")
+	columns := make([]string, 0, len(g.columns)+len(opened))
+	columns = append(columns, g.columns[:idx+1]...)
+	columns = append(columns, opened...)
+	g.columns = append(columns, g.columns[idx+1:]...)
+}
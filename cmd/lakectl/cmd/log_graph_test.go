@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+func TestGraphWriter(t *testing.T) {
+	DisableColors()
+	commit := func(id, message string, parents ...string) apigen.Commit {
+		return apigen.Commit{Id: id, Message: message, Parents: parents}
+	}
+	tests := []struct {
+		name        string
+		firstParent bool
+		commits     []apigen.Commit
+		want        string
+	}{
+		{
+			name: "linear",
+			commits: []apigen.Commit{
+				commit("c3", "third", "c2"),
+				commit("c2", "second\nbody", "c1"),
+				commit("c1", "first"),
+			},
+			want: "* c3 third\n* c2 second\n* c1 first\n",
+		},
+		{
+			name: "merge",
+			commits: []apigen.Commit{
+				commit("m", "merge", "a", "b"),
+				commit("b", "on branch", "a"),
+				commit("a", "base"),
+			},
+			want: "* m merge\n|\\\n| * b on branch\n| /\n* a base\n",
+		},
+		{
+			name: "branches",
+			commits: []apigen.Commit{
+				commit("m2", "merge 2", "m1", "c"),
+				commit("m1", "merge 1", "a", "b"),
+				commit("c", "on c", "a"),
+				commit("b", "on b", "a"),
+				commit("a", "base"),
+			},
+			want: "* m2 merge 2\n|\\\n* | m1 merge 1\n|\\ \\\n| | * c on c\n| * | b on b\n| / /\n* a base\n",
+		},
+		{
+			name:        "first parent",
+			firstParent: true,
+			commits: []apigen.Commit{
+				commit("m", "merge", "a", "b"),
+				commit("a", "base"),
+			},
+			want: "* m merge (merge of b)\n* a base\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			g := &graphWriter{w: &buf, firstParent: tt.firstParent}
+			g.Write(tt.commits)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("graph got\n%s\nexpected\n%s", got, tt.want)
+			}
+		})
+	}
+}
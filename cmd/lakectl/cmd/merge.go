@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"time"
 
+	"github.com/go-openapi/swag"
+	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 )
@@ -12,6 +17,9 @@ const (
 	mergeCmdMinArgs = 2
 	mergeCmdMaxArgs = 6
 
+	// mergeStatusPollInterval is the time between status requests of a merge running in the background
+	mergeStatusPollInterval = 2 * time.Second
+
 	mergeCreateTemplate = `Merged "{{.Merge.FromRef|yellow}}" into "{{.Merge.ToRef|yellow}}" to get "{{.Result.Reference|green}}".
 `
 )
@@ -25,13 +33,16 @@ type FromTo struct {
 var mergeCmd = &cobra.Command{
 	Use:   "merge <source ref> <destination ref>",
 	Short: "Merge & commit changes from source branch into destination branch",
-	Long:  "Merge & commit changes from source branch into destination branch",
-	Args:  cobra.RangeArgs(mergeCmdMinArgs, mergeCmdMaxArgs),
+	Long: `Merge & commit changes from source branch into destination branch.
+Merges the server runs in the background show their progress, in source and destination ranges merged, until done.`,
+	Example: `lakectl merge lakefs://example-repo/ingest lakefs://example-repo/main --prefix datasets/team-a/
+	Merge only the changes of branch 'ingest' under 'datasets/team-a/' into branch 'main'`,
+	Args: cobra.RangeArgs(mergeCmdMinArgs, mergeCmdMaxArgs),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) >= mergeCmdMaxArgs {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
-		return validRepositoryToComplete(cmd.Context(), toComplete)
+		return validRefToComplete(cmd.Context(), toComplete)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		message, kvPairs := getCommitFlags(cmd)
@@ -42,6 +53,8 @@ var mergeCmd = &cobra.Command{
 		strategy := Must(cmd.Flags().GetString("strategy"))
 		force := Must(cmd.Flags().GetBool("force"))
 		allowEmpty := Must(cmd.Flags().GetBool("allow-empty"))
+		noProgress := Must(cmd.Flags().GetBool("no-progress"))
+		prefix := Must(cmd.Flags().GetString("prefix"))
 
 		fmt.Println("Source:", sourceRef)
 		fmt.Println("Destination:", destinationRef)
@@ -59,15 +72,26 @@ var mergeCmd = &cobra.Command{
 			Strategy:   &strategy,
 			Force:      &force,
 			AllowEmpty: &allowEmpty,
+			AllowAsync: swag.Bool(true),
+		}
+		if prefix != "" {
+			body.PathScope = &prefix
 		}
 
 		resp, err := client.MergeIntoBranchWithResponse(cmd.Context(), destinationRef.Repository, sourceRef.Ref, destinationRef.Ref, body)
 		if resp != nil && resp.JSON409 != nil {
 			Die("Conflict found.", 1)
 		}
-		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
-		if resp.JSON200 == nil {
-			Die("Bad response from server", 1)
+		var result *apigen.MergeResult
+		if resp != nil && resp.JSON202 != nil {
+			// merge runs in the background, wait for it while showing its progress
+			result = waitForMerge(cmd.Context(), client, destinationRef.Repository, destinationRef.Ref, resp.JSON202.Id, !noProgress)
+		} else {
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+			if resp.JSON200 == nil {
+				Die("Bad response from server", 1)
+			}
+			result = resp.JSON200
 		}
 
 		Write(mergeCreateTemplate, struct {
@@ -78,17 +102,78 @@ var mergeCmd = &cobra.Command{
 				FromRef: sourceRef.Ref,
 				ToRef:   destinationRef.Ref,
 			},
-			Result: resp.JSON200,
+			Result: result,
 		})
 	},
 }
 
+// waitForMerge polls the status of the merge task into branch until it is done, updating a progress bar of the
+// ranges merged
+func waitForMerge(ctx context.Context, client apigen.ClientWithResponsesInterface, repository, branch, taskID string, showProgress bool) *apigen.MergeResult {
+	bar := newMergeProgressBar(showProgress)
+	ticker := time.NewTicker(mergeStatusPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			DieErr(ctx.Err())
+		case <-ticker.C:
+		}
+		resp, err := client.MergeIntoBranchStatusWithResponse(ctx, repository, branch, &apigen.MergeIntoBranchStatusParams{TaskId: taskID})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		status := resp.JSON200
+		if status == nil {
+			Die("Bad response from server", 1)
+		}
+		if status.RangesTotal > 0 {
+			bar.ChangeMax64(status.RangesTotal)
+			_ = bar.Set64(status.RangesProcessed)
+		}
+		if !status.Done {
+			continue
+		}
+		_ = bar.Clear()
+		if swag.BoolValue(status.Conflict) {
+			Die("Conflict found.", 1)
+		}
+		if status.Error != nil {
+			DieFmt("Merge failed: %s", *status.Error)
+		}
+		return &apigen.MergeResult{Reference: swag.StringValue(status.Reference)}
+	}
+}
+
+func newMergeProgressBar(visible bool) *progressbar.ProgressBar {
+	const (
+		barWidth    = 10
+		barThrottle = 65 * time.Millisecond
+	)
+	bar := progressbar.NewOptions64(
+		-1,
+		progressbar.OptionSetDescription("Merging"),
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetWidth(barWidth),
+		progressbar.OptionThrottle(barThrottle),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetItsString("range"),
+		progressbar.OptionOnCompletion(func() {
+			_, _ = fmt.Fprint(os.Stderr, "\n")
+		}),
+		progressbar.OptionFullWidth(),
+		progressbar.OptionSetVisibility(visible),
+	)
+	_ = bar.RenderBlank()
+	return bar
+}
+
 //nolint:gochecknoinits
 func init() {
 	flags := mergeCmd.Flags()
 	flags.String("strategy", "", "In case of a merge conflict, this option will force the merge process to automatically favor changes from the dest branch (\"dest-wins\") or from the source branch(\"source-wins\"). In case no selection is made, the merge process will fail in case of a conflict")
 	flags.Bool("force", false, "Allow merge into a read-only branch or into a branch with the same content")
 	flags.Bool("allow-empty", false, "Allow merge when the branches have the same content")
+	flags.Bool("no-progress", false, "switch off the progress output of long merges")
+	flags.String("prefix", "", "merge only the changes under this path prefix, leaving the other changes of the source unmerged")
 	withCommitFlags(mergeCmd, true)
 	rootCmd.AddCommand(mergeCmd)
 }
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/factory"
+	"github.com/treeverse/lakefs/pkg/block/params"
+)
+
+const (
+	metadataInspectCmdArgs = 2
+
+	defaultBlockStoragePrefix = "_lakefs"
+)
+
+var ErrUnsupportedBlockstore = errors.New("unsupported blockstore")
+
+// inspectAdapterConfig configures the blockstore adapter reading metadata files from flags, credentials not passed as
+// flags are taken from the default credentials of each cloud SDK
+type inspectAdapterConfig struct {
+	blockstoreType string
+	local          params.Local
+	s3             params.S3
+	gs             params.GS
+	azure          params.Azure
+}
+
+func (c *inspectAdapterConfig) BlockstoreType() string { return c.blockstoreType }
+
+func (c *inspectAdapterConfig) BlockstoreLocalParams() (params.Local, error) { return c.local, nil }
+
+func (c *inspectAdapterConfig) BlockstoreS3Params() (params.S3, error) { return c.s3, nil }
+
+func (c *inspectAdapterConfig) BlockstoreGSParams() (params.GS, error) { return c.gs, nil }
+
+func (c *inspectAdapterConfig) BlockstoreAzureParams() (params.Azure, error) { return c.azure, nil }
+
+var metadataCmd = &cobra.Command{
+	Use:    "metadata",
+	Short:  "Read lakeFS metadata directly from the object store",
+	Hidden: true,
+}
+
+var metadataInspectCmd = &cobra.Command{
+	Use:   "inspect <storage namespace> <range, metarange or refs dump ID>",
+	Short: "Print the entries of a range, metarange or refs dump file in a storage namespace",
+	Long: `Print the entries of a range, metarange or refs dump file read directly from the storage namespace of a repository,
+without accessing the lakeFS server. Useful for debugging when the server is down or data seems inconsistent.
+Object store credentials are read from the flags, or from the default credentials of the object store SDK.`,
+	Example: `lakectl metadata inspect s3://example-bucket/example-repo 8fb6d9e3c5c6ad9d8c3d4e8d2f4ed5b0a6b3cbb4b0ef3b54bd5e4f2dc6d3b8ce
+lakectl metadata inspect --local-path ~/lakefs/data local://example-repo 8fb6d9e3c5c6ad9d8c3d4e8d2f4ed5b0a6b3cbb4b0ef3b54bd5e4f2dc6d3b8ce`,
+	Args: cobra.ExactArgs(metadataInspectCmdArgs),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		storageNamespace, id := args[0], args[1]
+		amount := Must(cmd.Flags().GetInt("amount"))
+		blockStoragePrefix := Must(cmd.Flags().GetString("block-storage-prefix"))
+
+		namespaceURL, err := url.Parse(storageNamespace)
+		if err != nil {
+			DieFmt("storage namespace: %s", err)
+		}
+		storageType, err := block.GetStorageType(namespaceURL)
+		if err != nil {
+			DieFmt("storage namespace: %s", err)
+		}
+		adapterConfig := &inspectAdapterConfig{blockstoreType: storageType.BlockstoreType()}
+		switch adapterConfig.blockstoreType {
+		case block.BlockstoreTypeLocal:
+			adapterConfig.local.Path = Must(cmd.Flags().GetString("local-path"))
+		case block.BlockstoreTypeS3:
+			adapterConfig.s3 = params.S3{
+				Region:               Must(cmd.Flags().GetString("s3-region")),
+				Endpoint:             Must(cmd.Flags().GetString("s3-endpoint-url")),
+				ForcePathStyle:       Must(cmd.Flags().GetBool("s3-force-path-style")),
+				DiscoverBucketRegion: true,
+			}
+		case block.BlockstoreTypeGS:
+			adapterConfig.gs.CredentialsFile = Must(cmd.Flags().GetString("gs-credentials-file"))
+		case block.BlockstoreTypeAzure:
+			adapterConfig.azure = params.Azure{
+				StorageAccount:   Must(cmd.Flags().GetString("azure-storage-account")),
+				StorageAccessKey: Must(cmd.Flags().GetString("azure-storage-access-key")),
+			}
+		default:
+			DieErr(fmt.Errorf("%w: %s", ErrUnsupportedBlockstore, adapterConfig.blockstoreType))
+		}
+		adapter, err := factory.BuildBlockAdapter(ctx, nil, adapterConfig)
+		if err != nil {
+			DieFmt("create blockstore adapter: %s", err)
+		}
+
+		// metadata files are stored as <storage namespace>/<block storage prefix>/<ID>
+		reader, err := adapter.Get(ctx, block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       path.Join(blockStoragePrefix, id),
+		})
+		if err != nil {
+			DieFmt("read %s: %s", id, err)
+		}
+		defer func() { _ = reader.Close() }()
+		iter, props, err := getIterFromReader(reader)
+		if err != nil {
+			DieErr(err)
+		}
+		defer iter.Close()
+
+		table, err := formatSSTable(iter, props, amount)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(sstCatTemplate, struct {
+			Table *Table
+		}{table})
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	metadataInspectCmd.Flags().Int("amount", -1, "how many records to return, or -1 for all records")
+	metadataInspectCmd.Flags().String("block-storage-prefix", defaultBlockStoragePrefix, "prefix of metadata files in the storage namespace, as set by the lakeFS committed.block_storage_prefix configuration")
+	metadataInspectCmd.Flags().String("local-path", "", "base path of the local blockstore")
+	metadataInspectCmd.Flags().String("s3-region", "us-east-1", "S3 region, used until the bucket region is discovered")
+	metadataInspectCmd.Flags().String("s3-endpoint-url", "", "S3 endpoint URL, for S3 compatible object stores")
+	metadataInspectCmd.Flags().Bool("s3-force-path-style", false, "use path-style S3 addressing")
+	metadataInspectCmd.Flags().String("gs-credentials-file", "", "Google Cloud Storage credentials file")
+	metadataInspectCmd.Flags().String("azure-storage-account", "", "Azure storage account")
+	metadataInspectCmd.Flags().String("azure-storage-access-key", "", "Azure storage account access key")
+
+	metadataCmd.AddCommand(metadataInspectCmd)
+	rootCmd.AddCommand(metadataCmd)
+}
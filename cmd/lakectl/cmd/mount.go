@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/mount"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+const mountCmdArgs = 2
+
+var mountCmd = &cobra.Command{
+	Use:   "mount <ref URI> <mount point>",
+	Short: "Mount a lakeFS ref as a local filesystem (Linux and macOS)",
+	Long: `Mount a lakeFS ref as a local filesystem, using FUSE (Linux and macOS).
+The ref, or a path under it, is exposed read-only at the mount point so tools that only work with local paths can
+read versioned data. With --write, the ref must be a branch: files created, written or removed through the mount
+are staged on the branch, and are committed separately (e.g. with "lakectl commit").
+The command runs until interrupted, or until the mount point is unmounted.`,
+	Example: `lakectl mount ` + myRepoExample + `/main/datasets/ /mnt/datasets
+lakectl mount ` + myRepoExample + `/my-branch/ /mnt/my-branch --write`,
+	Args:              cobra.ExactArgs(mountCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		mountURI, err := uri.ParseWithBaseURI(args[0], baseURI)
+		if err != nil {
+			DieFmt("ref URI %s", err)
+		}
+		refURI := &uri.URI{Repository: mountURI.Repository, Ref: mountURI.Ref}
+		if err := refURI.ValidateRef(); err != nil {
+			DieFmt("ref URI %s", err)
+		}
+		prefix := mountURI.GetPath()
+		if prefix != "" && !strings.HasSuffix(prefix, uri.PathSeparator) {
+			prefix += uri.PathSeparator
+		}
+		mountpoint := args[1]
+		writable := Must(cmd.Flags().GetBool("write"))
+		cacheTTL := Must(cmd.Flags().GetDuration("cache-ttl"))
+		readAhead := Must(cmd.Flags().GetInt64("read-ahead"))
+		allowOther := Must(cmd.Flags().GetBool("allow-other"))
+		debug := Must(cmd.Flags().GetBool("debug-fuse"))
+
+		client := getClient()
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if writable {
+			resp, err := client.GetBranchWithResponse(ctx, refURI.Repository, refURI.Ref)
+			if resp != nil && resp.StatusCode() == http.StatusNotFound {
+				DieFmt("--write requires a branch, '%s' is not a branch of '%s'", refURI.Ref, refURI.Repository)
+			}
+			DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		}
+
+		server, err := mount.Mount(mountpoint, client, mount.Options{
+			Repository: refURI.Repository,
+			Ref:        refURI.Ref,
+			Prefix:     prefix,
+			Writable:   writable,
+			CacheTTL:   cacheTTL,
+			ReadAhead:  readAhead,
+			AllowOther: allowOther,
+			Debug:      debug,
+		})
+		if errors.Is(err, mount.ErrNotSupported) {
+			Die("lakectl mount is supported on Linux and macOS only", 1)
+		}
+		if err != nil {
+			DieErr(err)
+		}
+		mode := "read-only"
+		if writable {
+			mode = "writable"
+		}
+		fmt.Printf("Mounted %s at %s (%s), press Ctrl+C to unmount\n", mountURI, mountpoint, mode)
+
+		go func() {
+			<-ctx.Done()
+			if err := server.Unmount(); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to unmount %s: %s\n", mountpoint, err)
+			}
+		}()
+		server.Wait()
+		fmt.Printf("Unmounted %s\n", mountpoint)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	mountCmd.Flags().Bool("write", false, "stage changes made through the mount on the branch")
+	mountCmd.Flags().Duration("cache-ttl", mount.DefaultCacheTTL, "duration object metadata and directory listings are cached")
+	mountCmd.Flags().Int64("read-ahead", mount.DefaultReadAhead, "size in bytes of the range fetched from lakeFS on each read")
+	mountCmd.Flags().Bool("allow-other", false, "allow other users to access the mount (requires user_allow_other in /etc/fuse.conf)")
+	mountCmd.Flags().Bool("debug-fuse", false, "log FUSE operations")
+	_ = mountCmd.Flags().MarkHidden("debug-fuse")
+	rootCmd.AddCommand(mountCmd)
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// orgCmd represents the org command
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Manage organizations",
+	Long:  `Manage organizations, which group the projects of a team or a tenant of the lakeFS installation.`,
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(orgCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var orgCreateCmd = &cobra.Command{
+	Use:     "create <organization>",
+	Short:   "Create an organization",
+	Long:    `Create an organization. Organization IDs are lowercase letters and digits, without dashes.`,
+	Example: "lakectl org create acme --description 'Acme Corp.'",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		description := Must(cmd.Flags().GetString("description"))
+		kmsKeyID := Must(cmd.Flags().GetString("kms-key-id"))
+		body := apigen.CreateOrganizationJSONRequestBody{
+			Id: args[0],
+		}
+		if description != "" {
+			body.Description = apiutil.Ptr(description)
+		}
+		if kmsKeyID != "" {
+			body.KmsKeyId = apiutil.Ptr(kmsKeyID)
+		}
+		client := getClient()
+		resp, err := client.CreateOrganizationWithResponse(cmd.Context(), body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Organization: %s\n", resp.JSON201.Id)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	orgCreateCmd.Flags().String("description", "", "description of the organization")
+	orgCreateCmd.Flags().String("kms-key-id", "", "KMS key encrypting the data of the organization projects that do not set their own key")
+
+	orgCmd.AddCommand(orgCreateCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var orgDeleteCmd = &cobra.Command{
+	Use:     "delete <organization>",
+	Short:   "Delete an organization without projects",
+	Example: "lakectl org delete acme",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		resp, err := client.DeleteOrganizationWithResponse(cmd.Context(), args[0])
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Organization %s deleted\n", args[0])
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	orgCmd.AddCommand(orgDeleteCmd)
+}
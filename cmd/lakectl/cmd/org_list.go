@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var orgListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List organizations",
+	Example: "lakectl org list",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+
+		client := getClient()
+		resp, err := client.ListOrganizationsWithResponse(cmd.Context(), &apigen.ListOrganizationsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, row := range results {
+			rows[i] = []interface{}{row.Id, time.Unix(row.CreationDate, 0).String(), apiutil.Value(row.CreatedBy), apiutil.Value(row.Description)}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Organization", "Creation Date", "Created By", "Description"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := orgListCmd.Flags()
+	flags.Int("amount", defaultAmountArgumentValue, "number of results to return")
+	flags.String("after", "", "show results after this organization (used for pagination)")
+
+	orgCmd.AddCommand(orgListCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var orgSetEncryptionKeyCmd = &cobra.Command{
+	Use:   "set-encryption-key <organization> [<kms key id>]",
+	Short: "Set the KMS key encrypting the data of an organization",
+	Long: `Set the KMS key encrypting the data written to the repositories of the organization projects that do not set
+their own key. Without a key, the data is encrypted as configured for the blockstore. Data written before the change
+keeps its key.`,
+	Example: "lakectl org set-encryption-key acme arn:aws:kms:us-east-1:123456789012:key/acme",
+	Args:    cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var kmsKeyID string
+		if len(args) > 1 {
+			kmsKeyID = args[1]
+		}
+		client := getClient()
+		resp, err := client.SetOrganizationEncryptionKeyWithResponse(cmd.Context(), args[0], apigen.SetOrganizationEncryptionKeyJSONRequestBody{
+			KmsKeyId: kmsKeyID,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Organization: %s\n", resp.JSON200.Id)
+		if kmsKeyID == "" {
+			fmt.Println("KMS key: blockstore default")
+		} else {
+			fmt.Printf("KMS key: %s\n", kmsKeyID)
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	orgCmd.AddCommand(orgSetEncryptionKeyCmd)
+}
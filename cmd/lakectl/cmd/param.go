@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// paramCmd represents the param command
+var paramCmd = &cobra.Command{
+	Use:   "param",
+	Short: "Manage branch parameters, committed with the data",
+	Long:  `Set, get, list and delete parameters of a branch. Parameters are stored under _lakefs_params/ and are versioned with the data: read them at a commit to get the configuration of that data version.`,
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(paramCmd)
+}
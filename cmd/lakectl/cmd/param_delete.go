@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var paramDeleteCmd = &cobra.Command{
+	Use:               "delete <branch URI> <key>",
+	Short:             "Stage the deletion of a parameter from a branch",
+	Example:           "lakectl param delete " + myRepoExample + "/" + myBranchExample + " learning_rate",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRef,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		client := getClient()
+		resp, err := client.DeleteParamWithResponse(cmd.Context(), u.Repository, u.Ref, args[1])
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	paramCmd.AddCommand(paramDeleteCmd)
+}
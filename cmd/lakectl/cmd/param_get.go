@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var paramGetCmd = &cobra.Command{
+	Use:               "get <ref URI> <key>",
+	Short:             "Print the value of a parameter at a ref",
+	Example:           "lakectl param get " + myRepoExample + "/" + myBranchExample + " learning_rate",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: ValidArgsRef,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRefURI("ref URI", args[0])
+		client := getClient()
+		resp, err := client.GetParamWithResponse(cmd.Context(), u.Repository, u.Ref, args[1])
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Println(resp.JSON200.Value)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	paramCmd.AddCommand(paramGetCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var paramListCmd = &cobra.Command{
+	Use:               "list <ref URI>",
+	Short:             "List the parameters at a ref",
+	Example:           "lakectl param list " + myRepoExample + "/" + myBranchExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRef,
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		u := MustParseRefURI("ref URI", args[0])
+
+		client := getClient()
+		resp, err := client.ListParamsWithResponse(cmd.Context(), u.Repository, u.Ref, &apigen.ListParamsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, row := range results {
+			rows[i] = []interface{}{row.Key, row.Value}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Key", "Value"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := paramListCmd.Flags()
+	flags.Int("amount", defaultAmountArgumentValue, "number of results to return")
+	flags.String("after", "", "show results after this value (used for pagination)")
+
+	paramCmd.AddCommand(paramListCmd)
+}
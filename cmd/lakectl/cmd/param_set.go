@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var paramSetCmd = &cobra.Command{
+	Use:               "set <branch URI> <key> <value>",
+	Short:             "Stage the value of a parameter on a branch",
+	Example:           "lakectl param set " + myRepoExample + "/" + myBranchExample + " learning_rate 0.01",
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: ValidArgsRef,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseBranchURI("branch URI", args[0])
+		client := getClient()
+		resp, err := client.SetParamWithResponse(cmd.Context(), u.Repository, u.Ref, args[1], apigen.SetParamJSONRequestBody{
+			Value: args[2],
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Parameter '%s' set on branch '%s', commit the branch to version it\n", args[1], u.Ref)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	paramCmd.AddCommand(paramSetCmd)
+}
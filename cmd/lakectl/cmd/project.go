@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const projectTemplate = `Project: {{ .Organization | yellow }}/{{ .Id | yellow }}
+{{ if .Description }}Description: {{ .Description }}
+{{ end }}Repository prefix: {{ .RepositoryPrefix }}
+{{ if .Repositories }}Repositories: {{ .Repositories }}
+{{ end }}Max repositories: {{ if .MaxRepositories }}{{ .MaxRepositories }}{{ else }}unlimited{{ end }}
+{{ if .KmsKeyId }}KMS key: {{ .KmsKeyId }}
+{{ end }}{{ if .CreatedBy }}Created By: {{ .CreatedBy }}
+{{ end }}Creation Date: {{ .CreationDate | date }}
+`
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage the projects of organizations",
+	Long: `Manage the projects of organizations. The repositories of a project are the repositories whose IDs start with
+<organization>-<project>-, created with "lakectl repo create" like any repository. Repository names of different
+projects never collide, policies on arn:lakefs:fs:::repository/<organization>-<project>-* apply to all of the
+project repositories, and the project quota limits the number of its repositories.`,
+}
+
+// mustParseProject returns the organization and project of a project given as <organization>/<project>
+func mustParseProject(s string) (string, string) {
+	organization, project, found := strings.Cut(s, "/")
+	if !found || organization == "" || project == "" {
+		DieFmt("Invalid project %q, expected <organization>/<project>", s)
+	}
+	return organization, project
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(projectCmd)
+}
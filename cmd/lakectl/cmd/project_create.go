@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var projectCreateCmd = &cobra.Command{
+	Use:     "create <organization>/<project>",
+	Short:   "Create a project in an organization",
+	Long:    `Create a project in an organization. Project IDs are lowercase letters and digits, without dashes.`,
+	Example: "lakectl project create acme/ml --max-repositories 20\nlakectl repo create lakefs://acme-ml-features s3://bucket/features",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		organization, project := mustParseProject(args[0])
+		description := Must(cmd.Flags().GetString("description"))
+		maxRepositories := Must(cmd.Flags().GetInt("max-repositories"))
+		kmsKeyID := Must(cmd.Flags().GetString("kms-key-id"))
+		body := apigen.CreateProjectJSONRequestBody{
+			Id: project,
+		}
+		if description != "" {
+			body.Description = apiutil.Ptr(description)
+		}
+		if maxRepositories > 0 {
+			body.MaxRepositories = apiutil.Ptr(maxRepositories)
+		}
+		if kmsKeyID != "" {
+			body.KmsKeyId = apiutil.Ptr(kmsKeyID)
+		}
+		client := getClient()
+		resp, err := client.CreateProjectWithResponse(cmd.Context(), organization, body)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(projectTemplate, resp.JSON201)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := projectCreateCmd.Flags()
+	flags.String("description", "", "description of the project")
+	flags.Int("max-repositories", 0, "largest number of repositories in the project (default unlimited)")
+	flags.String("kms-key-id", "", "KMS key encrypting the data of the project repositories (default the key of the organization)")
+
+	projectCmd.AddCommand(projectCreateCmd)
+}
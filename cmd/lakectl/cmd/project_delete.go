@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var projectDeleteCmd = &cobra.Command{
+	Use:     "delete <organization>/<project>",
+	Short:   "Delete a project without repositories",
+	Example: "lakectl project delete acme/ml",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		organization, project := mustParseProject(args[0])
+		client := getClient()
+		resp, err := client.DeleteProjectWithResponse(cmd.Context(), organization, project)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Project %s/%s deleted\n", organization, project)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	projectCmd.AddCommand(projectDeleteCmd)
+}
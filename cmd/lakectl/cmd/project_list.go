@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var projectListCmd = &cobra.Command{
+	Use:     "list <organization>",
+	Short:   "List the projects of an organization",
+	Example: "lakectl project list acme",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+
+		client := getClient()
+		resp, err := client.ListProjectsWithResponse(cmd.Context(), args[0], &apigen.ListProjectsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, row := range results {
+			maxRepositories := "unlimited"
+			if row.MaxRepositories > 0 {
+				maxRepositories = strconv.Itoa(row.MaxRepositories)
+			}
+			rows[i] = []interface{}{row.Id, row.RepositoryPrefix, maxRepositories, apiutil.Value(row.Description)}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Project", "Repository Prefix", "Max Repositories", "Description"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := projectListCmd.Flags()
+	flags.Int("amount", defaultAmountArgumentValue, "number of results to return")
+	flags.String("after", "", "show results after this project (used for pagination)")
+
+	projectCmd.AddCommand(projectListCmd)
+}
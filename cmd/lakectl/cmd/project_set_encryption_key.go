@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var projectSetEncryptionKeyCmd = &cobra.Command{
+	Use:   "set-encryption-key <organization>/<project> [<kms key id>]",
+	Short: "Set the KMS key encrypting the data of a project",
+	Long: `Set the KMS key encrypting the data written to the project repositories, overriding the key of the
+organization. Without a key, the key of the organization is used. Data written before the change keeps its key.`,
+	Example: "lakectl project set-encryption-key acme/ml arn:aws:kms:us-east-1:123456789012:key/acme-ml",
+	Args:    cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		organization, project := mustParseProject(args[0])
+		var kmsKeyID string
+		if len(args) > 1 {
+			kmsKeyID = args[1]
+		}
+		client := getClient()
+		resp, err := client.SetProjectEncryptionKeyWithResponse(cmd.Context(), organization, project, apigen.SetProjectEncryptionKeyJSONRequestBody{
+			KmsKeyId: kmsKeyID,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(projectTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	projectCmd.AddCommand(projectSetEncryptionKeyCmd)
+}
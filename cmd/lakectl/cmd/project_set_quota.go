@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var projectSetQuotaCmd = &cobra.Command{
+	Use:   "set-quota <organization>/<project> <max repositories>",
+	Short: "Set the largest number of repositories in a project",
+	Long: `Set the largest number of repositories in a project, 0 for unlimited. Lowering it below the number of
+project repositories only prevents creating more of them.`,
+	Example: "lakectl project set-quota acme/ml 50",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		organization, project := mustParseProject(args[0])
+		maxRepositories, err := strconv.Atoi(args[1])
+		if err != nil || maxRepositories < 0 {
+			DieFmt("Invalid max repositories %q", args[1])
+		}
+		client := getClient()
+		resp, err := client.SetProjectQuotaWithResponse(cmd.Context(), organization, project, apigen.SetProjectQuotaJSONRequestBody{
+			MaxRepositories: maxRepositories,
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(projectTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	projectCmd.AddCommand(projectSetQuotaCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var projectShowCmd = &cobra.Command{
+	Use:     "show <organization>/<project>",
+	Short:   "Show a project and the number of its repositories",
+	Example: "lakectl project show acme/ml",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		organization, project := mustParseProject(args[0])
+		client := getClient()
+		resp, err := client.GetProjectWithResponse(cmd.Context(), organization, project)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(projectTemplate, resp.JSON200)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	projectCmd.AddCommand(projectShowCmd)
+}
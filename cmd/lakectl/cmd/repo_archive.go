@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+// repoArchiveCmd represents the archive repo command
+var repoArchiveCmd = &cobra.Command{
+	Use:               "archive <repository URI>",
+	Short:             "Archive existing repository, making it read-only and hidden from listings",
+	Example:           "lakectl repo archive " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		clt := getClient()
+		u := MustParseRepoURI("repository URI", args[0])
+		fmt.Println("Repository:", u)
+		resp, err := clt.ArchiveRepositoryWithResponse(cmd.Context(), u.Repository)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Repository '%s' archived\n", u.Repository)
+	},
+}
+
+// repoRestoreCmd represents the restore repo command
+var repoRestoreCmd = &cobra.Command{
+	Use:               "restore <repository URI>",
+	Short:             "Restore an archived repository",
+	Example:           "lakectl repo restore " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		clt := getClient()
+		u := MustParseRepoURI("repository URI", args[0])
+		fmt.Println("Repository:", u)
+		resp, err := clt.RestoreRepositoryWithResponse(cmd.Context(), u.Repository)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusNoContent)
+		fmt.Printf("Repository '%s' restored\n", u.Repository)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	repoCmd.AddCommand(repoArchiveCmd)
+	repoCmd.AddCommand(repoRestoreCmd)
+}
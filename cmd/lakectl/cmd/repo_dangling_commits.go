@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+var repoDanglingCommitsCmd = &cobra.Command{
+	Use:               "dangling-commits <repository URI>",
+	Short:             "List commits that cannot be reached from any branch or tag",
+	Long:              "List commits that cannot be reached from any branch or tag. A head is a dangling commit that no other commit has as its parent: use 'lakectl branch rescue' to create a branch at it.",
+	Example:           "lakectl repo dangling-commits " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		u := MustParseRepoURI("repository URI", args[0])
+
+		client := getClient()
+		resp, err := client.ListDanglingCommitsWithResponse(cmd.Context(), u.Repository, &apigen.ListDanglingCommitsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, row := range results {
+			rows[i] = []interface{}{row.Commit.Id, row.Head, time.Unix(row.Commit.CreationDate, 0).String(), row.Commit.Message}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Commit ID", "Head", "Creation Date", "Message"}, &pagination, amount)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	flags := repoDanglingCommitsCmd.Flags()
+	flags.Int("amount", defaultAmountArgumentValue, "number of results to return")
+	flags.String("after", "", "show results after this value (used for pagination)")
+
+	repoCmd.AddCommand(repoDanglingCommitsCmd)
+}
@@ -19,11 +19,13 @@ var repoListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		amount := Must(cmd.Flags().GetInt("amount"))
 		after := Must(cmd.Flags().GetString("after"))
+		archived := Must(cmd.Flags().GetBool("archived"))
 		clt := getClient()
 
 		resp, err := clt.ListRepositoriesWithResponse(cmd.Context(), &apigen.ListRepositoriesParams{
-			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
-			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+			After:    apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount:   apiutil.Ptr(apigen.PaginationAmount(amount)),
+			Archived: apiutil.Ptr(archived),
 		})
 		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
 		if resp.JSON200 == nil {
@@ -44,6 +46,7 @@ var repoListCmd = &cobra.Command{
 func init() {
 	repoListCmd.Flags().Int("amount", defaultAmountArgumentValue, "number of results to return")
 	repoListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	repoListCmd.Flags().Bool("archived", false, "list archived repositories")
 
 	repoCmd.AddCommand(repoListCmd)
 }
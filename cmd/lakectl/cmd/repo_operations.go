@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const repoOperationCmdArgs = 2
+
+const repoOperationTemplate = `ID: {{ .Id | yellow }}
+Kind: {{ .Kind }}
+{{ if .Description }}Description: {{ .Description }}
+{{ end }}Status: {{ .Status }}
+Progress: {{ .Progress }}{{ if .ProgressTotal }} / {{ .ProgressTotal }}{{ end }}
+{{ if .CreatedBy }}Created By: {{ .CreatedBy }}
+{{ end }}Created: {{ .CreationDate }}
+Updated: {{ .UpdateTime }}
+{{ if .Error }}Error: {{ .Error }}
+{{ end }}{{ if .Logs }}
+Logs:
+{{ range .Logs }}  {{ .Time }} {{ .Message }}
+{{ end }}{{ end }}`
+
+var repoOperationsCmd = &cobra.Command{
+	Use:   "operations",
+	Short: "Show and cancel long-running repository operations",
+	Long:  "Show and cancel long-running repository operations, such as background merges, imports, object tagging and refs dumps",
+}
+
+var repoOperationsListCmd = &cobra.Command{
+	Use:               "list <repository URI>",
+	Short:             "List repository operations",
+	Example:           "lakectl repo operations list " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := Must(cmd.Flags().GetInt("amount"))
+		after := Must(cmd.Flags().GetString("after"))
+		u := MustParseRepoURI("repository URI", args[0])
+
+		client := getClient()
+		resp, err := client.ListOperationsWithResponse(cmd.Context(), u.Repository, &apigen.ListOperationsParams{
+			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+			Amount: apiutil.Ptr(apigen.PaginationAmount(amount)),
+		})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		results := resp.JSON200.Results
+		rows := make([][]interface{}, len(results))
+		for i, op := range results {
+			progress := fmt.Sprint(op.Progress)
+			if op.ProgressTotal != nil {
+				progress += fmt.Sprintf(" / %d", *op.ProgressTotal)
+			}
+			rows[i] = []interface{}{op.Id, op.Kind, op.Status, progress, op.UpdateTime.String(), swag.StringValue(op.Description)}
+		}
+		pagination := resp.JSON200.Pagination
+		PrintTable(rows, []interface{}{"Operation ID", "Kind", "Status", "Progress", "Update Time", "Description"}, &pagination, amount)
+	},
+}
+
+var repoOperationsShowCmd = &cobra.Command{
+	Use:               "show <repository URI> <operation ID>",
+	Short:             "Show a repository operation with its logs",
+	Example:           "lakectl repo operations show " + myRepoExample + " <operation ID>",
+	Args:              cobra.ExactArgs(repoOperationCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+
+		client := getClient()
+		resp, err := client.GetOperationWithResponse(cmd.Context(), u.Repository, args[1])
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+		Write(repoOperationTemplate, resp.JSON200)
+	},
+}
+
+var repoOperationsCancelCmd = &cobra.Command{
+	Use:               "cancel <repository URI> <operation ID>",
+	Short:             "Cancel a running repository operation",
+	Example:           "lakectl repo operations cancel " + myRepoExample + " <operation ID>",
+	Args:              cobra.ExactArgs(repoOperationCmdArgs),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+
+		client := getClient()
+		resp, err := client.CancelOperationWithResponse(cmd.Context(), u.Repository, args[1])
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusAccepted)
+		fmt.Printf("Cancel requested for operation %s\n", args[1])
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	repoOperationsListCmd.Flags().Int("amount", defaultAmountArgumentValue, "number of results to return")
+	repoOperationsListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+
+	repoOperationsCmd.AddCommand(repoOperationsListCmd)
+	repoOperationsCmd.AddCommand(repoOperationsShowCmd)
+	repoOperationsCmd.AddCommand(repoOperationsCancelCmd)
+	repoCmd.AddCommand(repoOperationsCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var repoVerifyIntegrityCmd = &cobra.Command{
+	Use:               "verify-integrity <repository URI>",
+	Short:             "Verify that the branch histories of a repository were not rewritten",
+	Long:              "Verify that each change of the head of each branch chains the integrity hash of the change before it, and that the branch histories include the changes recorded by the latest notarization in the storage namespace. Exits with a non-zero status if any branch fails verification.",
+	Example:           "lakectl repo verify-integrity " + myRepoExample,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		u := MustParseRepoURI("repository URI", args[0])
+
+		client := getClient()
+		resp, err := client.VerifyRepositoryIntegrityWithResponse(cmd.Context(), u.Repository)
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusOK)
+		if resp.JSON200 == nil {
+			Die("Bad response from server", 1)
+		}
+
+		integrity := resp.JSON200
+		if integrity.NotarizedAt != nil {
+			fmt.Println("Latest notarization:", integrity.NotarizedAt.Format(time.RFC3339))
+		} else {
+			fmt.Println("Latest notarization: none")
+		}
+		rows := make([][]interface{}, len(integrity.Branches))
+		for i, b := range integrity.Branches {
+			notarized := "no"
+			switch {
+			case b.Notarized:
+				notarized = "yes"
+			case b.NotarizationExpired:
+				notarized = "expired"
+			}
+			status := "ok"
+			if b.Error != nil {
+				status = *b.Error
+			}
+			rows[i] = []interface{}{b.Branch, b.Records, swag.StringValue(b.LatestHash), notarized, status}
+		}
+		PrintTable(rows, []interface{}{"Branch", "Records", "Latest Hash", "Notarized", "Status"}, &apigen.Pagination{
+			HasMore: false,
+			Results: len(rows),
+		}, len(rows))
+		if !integrity.Verified {
+			Die("Repository integrity verification failed", 1)
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	repoCmd.AddCommand(repoVerifyIntegrityCmd)
+}
@@ -23,7 +23,7 @@ var (
 	respReadLimit = int64(4096) //nolint:mnd
 )
 
-func NewRetryClient(retriesCfg RetriesCfg, transport *http.Transport) *http.Client {
+func NewRetryClient(retriesCfg RetriesCfg, transport http.RoundTripper) *http.Client {
 	retryClient := retryablehttp.NewClient()
 	if transport != nil {
 		retryClient.HTTPClient.Transport = transport
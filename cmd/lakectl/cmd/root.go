@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/loadbalance"
 	lakefsconfig "github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/git"
 	"github.com/treeverse/lakefs/pkg/local"
@@ -60,6 +62,12 @@ type RetriesCfg struct {
 	MaxWaitInterval time.Duration `mapstructure:"max_wait_interval"` // MaxWaitInterval is the maximum amount of time to wait between retries
 }
 
+type LoadBalancingCfg struct {
+	FailureThreshold    int           `mapstructure:"failure_threshold"`     // FailureThreshold is the number of consecutive failures taking an endpoint out of the rotation
+	OpenDuration        time.Duration `mapstructure:"open_duration"`         // OpenDuration is how long an endpoint is out of the rotation before it is tried again
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"` // HealthCheckInterval is the interval of health checks returning endpoints to the rotation
+}
+
 // Configuration is the user-visible configuration structure in Golang form.
 // When editing, make sure *all* fields have a `mapstructure:"..."` tag, to simplify future refactoring.
 type Configuration struct {
@@ -69,7 +77,11 @@ type Configuration struct {
 	} `mapstructure:"credentials"`
 	Server struct {
 		EndpointURL lakefsconfig.OnlyString `mapstructure:"endpoint_url"`
-		Retries     RetriesCfg              `mapstructure:"retries"`
+		// EndpointURLs are the endpoints of several lakeFS instances of the same installation, requests are
+		// balanced across. Overrides EndpointURL when set.
+		EndpointURLs  []string         `mapstructure:"endpoint_urls"`
+		LoadBalancing LoadBalancingCfg `mapstructure:"load_balancing"`
+		Retries       RetriesCfg       `mapstructure:"retries"`
 	} `mapstructure:"server"`
 	Metastore struct {
 		Type lakefsconfig.OnlyString `mapstructure:"type"`
@@ -94,6 +106,10 @@ type Configuration struct {
 		// setting FixSparkPlaceholder to true will change spark placeholder with the actual location. for more information see https://github.com/treeverse/lakeFS/issues/2213
 		FixSparkPlaceholder bool `mapstructure:"fix_spark_placeholder"`
 	}
+	Local struct {
+		// IgnoreLineEndings compares the content of files that differ from the remote ignoring CRLF line endings
+		IgnoreLineEndings bool `mapstructure:"ignore_line_endings"`
+	} `mapstructure:"local"`
 	// Experimental - Use caution when enabling experimental features. It should only be used after consulting with the lakeFS team!
 	Experimental struct {
 		Local struct {
@@ -102,6 +118,8 @@ type Configuration struct {
 			} `mapstructure:"posix_permissions"`
 		} `mapstructure:"local"`
 	} `mapstructure:"experimental"`
+	// Profiles are named server endpoints and credentials, selected with --profile or LAKECTL_PROFILE
+	Profiles map[string]ProfileCfg `mapstructure:"profiles"`
 }
 
 type versionInfo struct {
@@ -125,6 +143,10 @@ var (
 	//   `--base-uri lakefs://repo1 /main/file.md` will resolve to path `lakefs://repo1/main/file.md`
 	baseURI string
 
+	// profile is the name of the configuration profile used, set by the environment variable LAKECTL_PROFILE and
+	// overridden by flag 'profile'
+	profile string
+
 	// logLevel logging level (default is off)
 	logLevel string
 	// logFormat logging output format
@@ -162,6 +184,8 @@ const (
 	defaultMaxAttempts      = 4
 	defaultMaxRetryInterval = 30 * time.Second
 	defaultMinRetryInterval = 200 * time.Millisecond
+
+	defaultHealthCheckInterval = 10 * time.Second
 )
 
 func withRecursiveFlag(cmd *cobra.Command, usage string) {
@@ -228,6 +252,8 @@ func getSyncFlags(cmd *cobra.Command, client *apigen.ClientWithResponses) local.
 		Parallelism:      parallelism,
 		Presign:          presignMode.Enabled,
 		PresignMultipart: presignMode.Multipart,
+		// default filesystems of these systems are case-insensitive
+		CaseInsensitive: runtime.GOOS == "windows" || runtime.GOOS == "darwin",
 	}
 }
 
@@ -367,6 +393,7 @@ var rootCmd = &cobra.Command{
 var excludeStatsCmds = []string{
 	"doctor",
 	"config",
+	"metadata_inspect", // runs without a lakeFS server
 }
 
 func preRunCmd(cmd *cobra.Command) {
@@ -396,6 +423,27 @@ func preRunCmd(cmd *cobra.Command) {
 	if err != nil {
 		DieFmt("error unmarshal configuration: %v", err)
 	}
+	if profile != "" {
+		if err := applyProfile(cfg, profile); err != nil {
+			DieErr(err)
+		}
+	}
+	if baseURI == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			DieErr(err)
+		}
+		dirContext, contextPath, err := FindDirectoryContext(wd)
+		if err != nil {
+			DieErr(err)
+		}
+		if dirContext != nil {
+			logging.ContextUnavailable().
+				WithField("file", contextPath).
+				Debug("loaded directory context")
+			baseURI = dirContext.BaseURI()
+		}
+	}
 }
 
 func sendStats(cmd *cobra.Command, cmdSuffix string) {
@@ -443,10 +491,40 @@ func getHTTPClient() *http.Client {
 	// see: https://stackoverflow.com/a/39834253
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	var roundTripper http.RoundTripper = transport
+	if endpoints := serverEndpoints(); len(endpoints) > 1 {
+		balancer, err := loadbalance.NewTransport(endpoints, loadbalance.Config{
+			FailureThreshold:    cfg.Server.LoadBalancing.FailureThreshold,
+			OpenDuration:        cfg.Server.LoadBalancing.OpenDuration,
+			HealthCheckInterval: cfg.Server.LoadBalancing.HealthCheckInterval,
+		}, transport)
+		if err != nil {
+			DieErr(err)
+		}
+		roundTripper = balancer
+	}
 	if !cfg.Server.Retries.Enabled {
-		return &http.Client{Transport: transport}
+		return &http.Client{Transport: roundTripper}
+	}
+	return NewRetryClient(cfg.Server.Retries, roundTripper)
+}
+
+// serverEndpoints returns the normalized API endpoints of the configured lakeFS instances, the first of which is
+// the endpoint of the API client
+func serverEndpoints() []string {
+	endpointURLs := cfg.Server.EndpointURLs
+	if len(endpointURLs) == 0 {
+		endpointURLs = []string{cfg.Server.EndpointURL.String()}
 	}
-	return NewRetryClient(cfg.Server.Retries, transport)
+	endpoints := make([]string, 0, len(endpointURLs))
+	for _, endpointURL := range endpointURLs {
+		endpoint, err := apiutil.NormalizeLakeFSEndpoint(endpointURL)
+		if err != nil {
+			DieErr(err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
 }
 
 func getClient() *apigen.ClientWithResponses {
@@ -459,10 +537,7 @@ func getClient() *apigen.ClientWithResponses {
 		DieErr(err)
 	}
 
-	serverEndpoint, err := apiutil.NormalizeLakeFSEndpoint(cfg.Server.EndpointURL.String())
-	if err != nil {
-		DieErr(err)
-	}
+	serverEndpoint := serverEndpoints()[0]
 
 	oss := osinfo.GetOSInfo()
 	client, err := apigen.NewClientWithResponses(
@@ -500,6 +575,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $HOME/.lakectl.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&noColorRequested, "no-color", getEnvNoColor(), "don't use fancy output colors (default value can be set by NO_COLOR environment variable)")
 	rootCmd.PersistentFlags().StringVarP(&baseURI, "base-uri", "", os.Getenv("LAKECTL_BASE_URI"), "base URI used for lakeFS address parse")
+	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "", os.Getenv("LAKECTL_PROFILE"), "name of the configuration profile to use")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "", "none", "set logging level")
 	rootCmd.PersistentFlags().StringVarP(&logFormat, "log-format", "", "", "set logging output format")
 	rootCmd.PersistentFlags().StringSliceVarP(&logOutputs, "log-output", "", []string{}, "set logging output(s)")
@@ -544,11 +620,15 @@ func initConfig() {
 	// set defaults
 	viper.SetDefault("metastore.hive.db_location_uri", "file:/user/hive/warehouse/")
 	viper.SetDefault("server.endpoint_url", "http://127.0.0.1:8000")
+	viper.SetDefault("server.load_balancing.failure_threshold", loadbalance.DefaultFailureThreshold)
+	viper.SetDefault("server.load_balancing.open_duration", loadbalance.DefaultOpenDuration)
+	viper.SetDefault("server.load_balancing.health_check_interval", defaultHealthCheckInterval)
 	viper.SetDefault("server.retries.enabled", true)
 	viper.SetDefault("server.retries.max_attempts", defaultMaxAttempts)
 	viper.SetDefault("server.retries.max_wait_interval", defaultMaxRetryInterval)
 	viper.SetDefault("server.retries.min_wait_interval", defaultMinRetryInterval)
 	viper.SetDefault("experimental.local.posix_permissions.enabled", false)
+	viper.SetDefault("local.ignore_line_endings", false)
 
 	cfgErr = viper.ReadInConfig()
 }
@@ -12,7 +12,7 @@ var showCommitCmd = &cobra.Command{
 	Use:               "commit <commit URI>",
 	Short:             "See detailed information about a commit",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		commitURI := MustParseRefURI("commit URI", args[0])
 		showMetaRangeID := Must(cmd.Flags().GetBool("show-meta-range-id"))
@@ -11,7 +11,7 @@ var tagDeleteCmd = &cobra.Command{
 	Use:               "delete <tag URI>",
 	Short:             "Delete a tag from a repository",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		confirmation, err := Confirm(cmd.Flags(), "Are you sure you want to delete tag")
 		if err != nil || !confirmation {
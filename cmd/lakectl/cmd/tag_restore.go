@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var tagRestoreCmd = &cobra.Command{
+	Use:               "restore <tag URI>",
+	Short:             "Restore a recently deleted tag",
+	Example:           "lakectl tag restore lakefs://example-repo/example-tag",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: ValidArgsRepository,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := MustParseRefURI("tag URI", args[0])
+
+		ctx := cmd.Context()
+		resp, err := client.RestoreTagWithResponse(ctx, u.Repository, u.Ref, &apigen.RestoreTagParams{})
+		DieOnErrorOrUnexpectedStatusCode(resp, err, http.StatusCreated)
+		if resp.JSON201 == nil {
+			Die("Bad response from server", 1)
+		}
+		fmt.Printf("Restored tag '%s' (%s)\n", resp.JSON201.Id, resp.JSON201.CommitId)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	tagCmd.AddCommand(tagRestoreCmd)
+}
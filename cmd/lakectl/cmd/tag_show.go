@@ -11,7 +11,7 @@ var tagShowCmd = &cobra.Command{
 	Use:               "show <tag URI>",
 	Short:             "Show tag's commit reference",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: ValidArgsRepository,
+	ValidArgsFunction: ValidArgsRef,
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		u := MustParseRefURI("tag URI", args[0])
@@ -2,7 +2,14 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
@@ -10,6 +17,29 @@ import (
 	"github.com/treeverse/lakefs/pkg/uri"
 )
 
+const (
+	// completionTimeout bounds the API calls made to complete a single argument, so a slow server doesn't hang the shell
+	completionTimeout = 2 * time.Second
+	// completionCacheTTL is how long completions are reused between invocations, as the shell runs lakectl on each key press
+	completionCacheTTL = 30 * time.Second
+	// completionMaxResults is the number of completions suggested for refs and paths
+	completionMaxResults = 500
+
+	completionCacheDirPerm  = 0o700
+	completionCacheFilePerm = 0o600
+)
+
+var errCompletionFailed = errors.New("completion request failed")
+
+// completionLevel is the deepest part of a lakeFS URI completed for an argument
+type completionLevel int
+
+const (
+	completeRepository completionLevel = iota
+	completeRef
+	completePath
+)
+
 func ValidArgsRepository(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) != 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -17,44 +47,210 @@ func ValidArgsRepository(cmd *cobra.Command, args []string, toComplete string) (
 	return validRepositoryToComplete(cmd.Context(), toComplete)
 }
 
+// ValidArgsRef completes the repository and ref of a ref URI argument
+func ValidArgsRef(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return validRefToComplete(cmd.Context(), toComplete)
+}
+
+// ValidArgsPath completes the repository, ref and object path of a path URI argument
+func ValidArgsPath(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return validURIToComplete(cmd.Context(), toComplete, completePath)
+}
+
 func validRepositoryToComplete(ctx context.Context, toComplete string) ([]string, cobra.ShellCompDirective) {
-	// do not suggest in case we are passed the repository part
+	return validURIToComplete(ctx, toComplete, completeRepository)
+}
+
+func validRefToComplete(ctx context.Context, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validURIToComplete(ctx, toComplete, completeRef)
+}
+
+func validURIToComplete(ctx context.Context, toComplete string, level completionLevel) ([]string, cobra.ShellCompDirective) {
 	uriPrefix := uri.LakeFSSchema + uri.LakeFSSchemaSeparator
-	if strings.HasPrefix(toComplete, uriPrefix) && strings.Contains(toComplete[len(uriPrefix):], uri.PathSeparator) {
+	if !strings.HasPrefix(toComplete, uriPrefix) {
+		// suggest all repositories until the schema is written
+		toComplete = ""
+	}
+	parts := strings.SplitN(strings.TrimPrefix(toComplete, uriPrefix), uri.PathSeparator, 3) //nolint:mnd
+	// do not suggest in case we are passed the completed part
+	if len(parts) > int(level)+1 {
 		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
 	}
 
-	// extract repository name written so far
-	var prefix apigen.PaginationPrefix
-	if strings.HasPrefix(toComplete, uriPrefix) {
-		if !strings.Contains(toComplete[len(uriPrefix):], uri.PathSeparator) {
-			prefix = apigen.PaginationPrefix(toComplete[len(uriPrefix):])
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+	var (
+		completions []string
+		err         error
+	)
+	switch len(parts) {
+	case 1:
+		completions, err = cachedCompletions("repository", parts[0], func() ([]string, error) {
+			return listRepositoryCompletions(ctx, parts[0])
+		})
+	case 2: //nolint:mnd
+		completions, err = cachedCompletions("ref/"+parts[0], parts[1], func() ([]string, error) {
+			return listRefCompletions(ctx, parts[0], parts[1])
+		})
+	default:
+		completions, err = cachedCompletions("path/"+parts[0]+"/"+parts[1], parts[2], func() ([]string, error) {
+			return listPathCompletions(ctx, parts[0], parts[1], parts[2])
+		})
+	}
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	// build full URIs, and let the shell continue typing the next part unless the last part is complete
+	directive := cobra.ShellCompDirectiveNoFileComp
+	base := uriPrefix + strings.Join(parts[:len(parts)-1], uri.PathSeparator)
+	if len(parts) > 1 {
+		base += uri.PathSeparator
+	}
+	results := make([]string, 0, len(completions))
+	for _, c := range completions {
+		if len(parts) <= int(level) {
+			c += uri.PathSeparator
+			directive |= cobra.ShellCompDirectiveNoSpace
+		} else if strings.HasSuffix(c, uri.PathSeparator) {
+			directive |= cobra.ShellCompDirectiveNoSpace
 		}
+		results = append(results, base+c)
 	}
+	if level == completeRepository {
+		// repository URIs are completed without a separator, but the user may still type one
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return results, directive
+}
 
-	// suggest repositories
+func listRepositoryCompletions(ctx context.Context, prefix string) ([]string, error) {
 	clt := getClient()
 	var (
 		completions []string
 		after       string
 	)
 	for {
-		params := &apigen.ListRepositoriesParams{
-			Prefix: &prefix,
+		resp, err := clt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix(prefix)),
 			After:  apiutil.Ptr(apigen.PaginationAfter(after)),
+		})
+		if err != nil {
+			return nil, err
 		}
-		resp, err := clt.ListRepositoriesWithResponse(ctx, params)
-		result := resp.JSON200
-		if err != nil || result == nil {
-			return nil, cobra.ShellCompDirectiveError
+		if resp.JSON200 == nil {
+			return nil, errCompletionFailed
 		}
-		for _, repo := range result.Results {
-			completions = append(completions, uriPrefix+repo.Id)
+		for _, repo := range resp.JSON200.Results {
+			completions = append(completions, repo.Id)
 		}
-		if !result.Pagination.HasMore {
-			break
+		if !resp.JSON200.Pagination.HasMore {
+			return completions, nil
 		}
-		after = result.Pagination.NextOffset
+		after = resp.JSON200.Pagination.NextOffset
+	}
+}
+
+// listRefCompletions lists the branches and tags of repository starting with prefix
+func listRefCompletions(ctx context.Context, repository, prefix string) ([]string, error) {
+	clt := getClient()
+	branches, err := clt.ListBranchesWithResponse(ctx, repository, &apigen.ListBranchesParams{
+		Prefix: apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		Amount: apiutil.Ptr(apigen.PaginationAmount(completionMaxResults)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if branches.JSON200 == nil {
+		return nil, errCompletionFailed
+	}
+	tags, err := clt.ListTagsWithResponse(ctx, repository, &apigen.ListTagsParams{
+		Prefix: apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		Amount: apiutil.Ptr(apigen.PaginationAmount(completionMaxResults)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tags.JSON200 == nil {
+		return nil, errCompletionFailed
+	}
+	completions := make([]string, 0, len(branches.JSON200.Results)+len(tags.JSON200.Results))
+	for _, branch := range branches.JSON200.Results {
+		completions = append(completions, branch.Id)
+	}
+	for _, tag := range tags.JSON200.Results {
+		completions = append(completions, tag.Id)
+	}
+	return completions, nil
+}
+
+// listPathCompletions lists the objects and common prefixes at the level of prefix
+func listPathCompletions(ctx context.Context, repository, ref, prefix string) ([]string, error) {
+	resp, err := getClient().ListObjectsWithResponse(ctx, repository, ref, &apigen.ListObjectsParams{
+		Prefix:    apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		Delimiter: apiutil.Ptr(apigen.PaginationDelimiter(uri.PathSeparator)),
+		Amount:    apiutil.Ptr(apigen.PaginationAmount(completionMaxResults)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, errCompletionFailed
+	}
+	completions := make([]string, 0, len(resp.JSON200.Results))
+	for _, obj := range resp.JSON200.Results {
+		completions = append(completions, obj.Path)
+	}
+	return completions, nil
+}
+
+type completionCacheEntry struct {
+	Time        time.Time `json:"time"`
+	Completions []string  `json:"completions"`
+}
+
+// cachedCompletions returns the completions listed for kind and prefix by the current user in the last
+// completionCacheTTL, or lists and caches them
+func cachedCompletions(kind, prefix string, list func() ([]string, error)) ([]string, error) {
+	cachePath := completionCachePath(kind, prefix)
+	if cachePath != "" {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var entry completionCacheEntry
+			if json.Unmarshal(data, &entry) == nil && time.Since(entry.Time) < completionCacheTTL {
+				return entry.Completions, nil
+			}
+		}
+	}
+	completions, err := list()
+	if err != nil || cachePath == "" {
+		return completions, err
+	}
+	if data, err := json.Marshal(completionCacheEntry{Time: time.Now(), Completions: completions}); err == nil {
+		// caching is best effort
+		if err := os.MkdirAll(filepath.Dir(cachePath), completionCacheDirPerm); err == nil {
+			_ = os.WriteFile(cachePath, data, completionCacheFilePerm)
+		}
+	}
+	return completions, nil
+}
+
+// completionCachePath returns the cache file of a completion, unique to the server and credentials used, or empty if
+// there is no cache directory
+func completionCachePath(kind, prefix string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, s := range []string{cfg.Server.EndpointURL.String(), cfg.Credentials.AccessKeyID.String(), kind, prefix} {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
 	}
-	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	return filepath.Join(dir, "lakectl", "completion", hex.EncodeToString(h.Sum(nil)))
 }
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/xid"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/factory"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+const (
+	MetadataExportCmdNumArgs = 1
+	MetadataImportCmdNumArgs = 2
+
+	// metadataExportPrefix location of metadata exports, relative to the repository storage namespace
+	metadataExportPrefix = "_lakefs/metadata/export/"
+)
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Export and import repository metadata in a portable format",
+	Long: `Export and import repository metadata in a portable format.
+The repository record, commits, branches and tags are written as Parquet files into the repository storage namespace.
+Exports can be analyzed by any Parquet reader, or imported to rebuild the repository metadata on a new Key-Value Store.`,
+	Hidden: true,
+}
+
+var metadataExportCmd = &cobra.Command{
+	Use:   "export <repository>",
+	Short: "Export repository refs and commits as Parquet files into the storage namespace",
+	Args:  cobra.ExactArgs(MetadataExportCmdNumArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		refManager, kvStore, err := openRefManager(ctx)
+		if err != nil {
+			return err
+		}
+		defer kvStore.Close()
+		adapter, err := factory.BuildBlockAdapter(ctx, nil, loadConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create block adapter: %w", err)
+		}
+
+		repository, err := refManager.GetRepository(ctx, graveler.RepositoryID(args[0]))
+		if err != nil {
+			return fmt.Errorf("get repository: %w", err)
+		}
+		location := strings.TrimSuffix(repository.StorageNamespace.String(), "/") + "/" + metadataExportPrefix + xid.New().String()
+		err = ref.ExportMetadata(ctx, refManager, repository, func(name string) (io.WriteCloser, error) {
+			return newBlockstoreFileWriter(ctx, adapter, location+"/"+name)
+		})
+		if err != nil {
+			return fmt.Errorf("export metadata: %w", err)
+		}
+		fmt.Printf("Exported repository '%s' metadata to %s\n", repository.RepositoryID, location)
+		return nil
+	},
+}
+
+var metadataImportCmd = &cobra.Command{
+	Use:   "import <repository> <export location>",
+	Short: "Create a repository from a metadata export",
+	Long: `Create a repository from a metadata export.
+The repository must not exist. Its storage namespace and settings are taken from the export.`,
+	Example: "lakefs metadata import example-repo s3://bucket/example-repo/_lakefs/metadata/export/cn1k2ge4km9s73c0nk0g",
+	Args:    cobra.ExactArgs(MetadataImportCmdNumArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		refManager, kvStore, err := openRefManager(ctx)
+		if err != nil {
+			return err
+		}
+		defer kvStore.Close()
+		adapter, err := factory.BuildBlockAdapter(ctx, nil, loadConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create block adapter: %w", err)
+		}
+
+		location := strings.TrimSuffix(args[1], "/")
+		repository, err := ref.ImportMetadata(ctx, refManager, graveler.RepositoryID(args[0]), func(name string) (source.ParquetFile, error) {
+			return readBlockstoreFile(ctx, adapter, location+"/"+name)
+		})
+		if err != nil {
+			return fmt.Errorf("import metadata: %w", err)
+		}
+		fmt.Printf("Imported repository '%s' (storage namespace %s) from %s\n", repository.RepositoryID, repository.StorageNamespace, location)
+		return nil
+	},
+}
+
+// blockstoreFileWriter buffers data into a temporary file and uploads it to the blockstore on Close
+type blockstoreFileWriter struct {
+	*os.File
+	ctx     context.Context
+	adapter block.Adapter
+	address string
+}
+
+func newBlockstoreFileWriter(ctx context.Context, adapter block.Adapter, address string) (*blockstoreFileWriter, error) {
+	f, err := os.CreateTemp("", "lakefs-metadata-")
+	if err != nil {
+		return nil, err
+	}
+	return &blockstoreFileWriter{File: f, ctx: ctx, adapter: adapter, address: address}, nil
+}
+
+func (w *blockstoreFileWriter) Close() error {
+	defer func() {
+		_ = w.File.Close()
+		_ = os.Remove(w.File.Name())
+	}()
+	size, err := w.File.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := w.File.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	obj := block.ObjectPointer{
+		StorageNamespace: w.address,
+		Identifier:       w.address,
+		IdentifierType:   block.IdentifierTypeFull,
+	}
+	return w.adapter.Put(w.ctx, obj, size, w.File, block.PutOpts{})
+}
+
+func readBlockstoreFile(ctx context.Context, adapter block.Adapter, address string) (source.ParquetFile, error) {
+	reader, err := adapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: address,
+		Identifier:       address,
+		IdentifierType:   block.IdentifierTypeFull,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.NewBufferFileFromBytes(data), nil
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(metadataCmd)
+	metadataCmd.AddCommand(metadataExportCmd)
+	metadataCmd.AddCommand(metadataImportCmd)
+}
@@ -119,6 +119,8 @@ func DoMigration(ctx context.Context, kvStore kv.Store, cfg *config.Config, forc
 			err = migrations.MigrateToACL(ctx, kvStore, cfg, logging.ContextUnavailable(), version, force)
 		case version < kv.ACLImportMigrateVersion:
 			err = migrations.MigrateImportPermissions(ctx, kvStore, cfg)
+		case version < kv.ActionsConfigMigrateVersion:
+			err = migrations.MigrateActionsConfigPermissions(ctx, kvStore, cfg)
 		}
 		if err != nil {
 			return err
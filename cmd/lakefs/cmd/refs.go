@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/pkg/batch"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/ident"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	"go.uber.org/ratelimit"
+)
+
+const (
+	RefsRepoCmdNumArgs         = 1
+	RefsBranchCmdNumArgs       = 2
+	RefsBranchResetCmdNumArgs  = 3
+	refsRecoveredBranchIDChars = 16
+)
+
+var errNotConfirmed = errors.New("operation not confirmed, use --yes to apply it")
+
+var refsCmd = &cobra.Command{
+	Use:   "refs",
+	Short: "Inspect and repair lakeFS' ref store directly through the Key-Value Store",
+	Long: `Inspect and repair lakeFS' ref store directly through the Key-Value Store.
+These commands are intended for emergencies where the API cannot be used. Stop all lakeFS servers before using commands that modify the ref store.`,
+	Hidden: true,
+}
+
+var refsBranchShowCmd = &cobra.Command{
+	Use:   "show-branch <repository> <branch>",
+	Short: "Print the commit pointer and staging tokens of a branch",
+	Args:  cobra.ExactArgs(RefsBranchCmdNumArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		refManager, kvStore, err := openRefManager(ctx)
+		if err != nil {
+			return err
+		}
+		defer kvStore.Close()
+
+		repository, err := refManager.GetRepository(ctx, graveler.RepositoryID(args[0]))
+		if err != nil {
+			return fmt.Errorf("get repository: %w", err)
+		}
+		branch, err := refManager.GetBranch(ctx, repository, graveler.BranchID(args[1]))
+		if err != nil {
+			return fmt.Errorf("get branch: %w", err)
+		}
+		sealedTokens := make([]string, 0, len(branch.SealedTokens))
+		for _, token := range branch.SealedTokens {
+			sealedTokens = append(sealedTokens, token.String())
+		}
+		fmt.Printf("Commit ID:      %s\n", branch.CommitID)
+		fmt.Printf("Staging token:  %s\n", branch.StagingToken)
+		fmt.Printf("Sealed tokens:  %s\n", strings.Join(sealedTokens, ", "))
+		return nil
+	},
+}
+
+var refsBranchResetCmd = &cobra.Command{
+	Use:   "reset-branch <repository> <branch> <commit id>",
+	Short: "Point a branch at the given commit, dropping its staging area",
+	Long: `Point a branch at the given commit, dropping its staging area.
+The branch is created if it does not exist. Uncommitted changes of the branch are no longer accessible after the reset.`,
+	Args: cobra.ExactArgs(RefsBranchResetCmdNumArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		refManager, kvStore, err := openRefManager(ctx)
+		if err != nil {
+			return err
+		}
+		defer kvStore.Close()
+
+		repository, err := refManager.GetRepository(ctx, graveler.RepositoryID(args[0]))
+		if err != nil {
+			return fmt.Errorf("get repository: %w", err)
+		}
+		branchID := graveler.BranchID(args[1])
+		rawRef, err := refManager.ParseRef(graveler.Ref(args[2]))
+		if err != nil {
+			return fmt.Errorf("parse commit id: %w", err)
+		}
+		resolved, err := refManager.ResolveRawRef(ctx, repository, rawRef)
+		if err != nil {
+			return fmt.Errorf("get commit: %w", err)
+		}
+		if resolved.Type != graveler.ReferenceTypeCommit {
+			return fmt.Errorf("%s is not a commit id: %w", args[2], graveler.ErrCommitNotFound)
+		}
+		commitID := resolved.CommitID
+		current, err := refManager.GetBranch(ctx, repository, branchID)
+		switch {
+		case errors.Is(err, graveler.ErrBranchNotFound):
+			fmt.Printf("Branch '%s' not found, it will be created\n", branchID)
+		case err != nil:
+			return fmt.Errorf("get branch: %w", err)
+		default:
+			fmt.Printf("Branch '%s' currently points to commit %s (staging token %s)\n", branchID, current.CommitID, current.StagingToken)
+		}
+		fmt.Printf("Reset branch '%s' to commit %s\n", branchID, commitID)
+		if !yes {
+			return errNotConfirmed
+		}
+
+		err = refManager.SetBranch(ctx, repository, branchID, graveler.Branch{
+			CommitID:     commitID,
+			StagingToken: graveler.GenerateStagingToken(repository.RepositoryID, branchID),
+		})
+		if err != nil {
+			return fmt.Errorf("set branch: %w", err)
+		}
+		fmt.Println("Done")
+		return nil
+	},
+}
+
+var refsRebuildBranchesCmd = &cobra.Command{
+	Use:   "rebuild-branches <repository>",
+	Short: "Create branches for commits not reachable from any branch or tag",
+	Long: `Scan all the commits of the repository and find the commits that cannot be reached from any branch or tag.
+A branch is created for each unreachable commit that is not a parent of another commit, making all lost commits reachable again.`,
+	Args: cobra.ExactArgs(RefsRepoCmdNumArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			return err
+		}
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		refManager, kvStore, err := openRefManager(ctx)
+		if err != nil {
+			return err
+		}
+		defer kvStore.Close()
+
+		repository, err := refManager.GetRepository(ctx, graveler.RepositoryID(args[0]))
+		if err != nil {
+			return fmt.Errorf("get repository: %w", err)
+		}
+		// keep only the heads, the branches point at them
+		unreachable := 0
+		var heads []graveler.CommitID
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "BRANCH\tCOMMIT ID\tCREATION DATE\tMESSAGE")
+		err = ref.FindUnreachableCommits(ctx, refManager, repository, nil, func(commit *graveler.CommitRecord, head bool) error {
+			unreachable++
+			if !head {
+				return nil
+			}
+			heads = append(heads, commit.CommitID)
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", recoveredBranchID(prefix, commit.CommitID), commit.CommitID, commit.CreationDate, commit.Message)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(heads) > 0 {
+			_ = w.Flush()
+		}
+		fmt.Printf("Found %d unreachable commits, %d heads\n", unreachable, len(heads))
+		if len(heads) == 0 {
+			return nil
+		}
+		if !yes {
+			return errNotConfirmed
+		}
+
+		for _, commitID := range heads {
+			branchID := recoveredBranchID(prefix, commitID)
+			err := refManager.CreateBranch(ctx, repository, branchID, graveler.Branch{
+				CommitID:     commitID,
+				StagingToken: graveler.GenerateStagingToken(repository.RepositoryID, branchID),
+			})
+			if err != nil {
+				return fmt.Errorf("create branch %s: %w", branchID, err)
+			}
+		}
+		fmt.Printf("Created %d branches\n", len(heads))
+		return nil
+	},
+}
+
+// recoveredBranchID returns the name of the branch created for the unreachable head commitID
+func recoveredBranchID(prefix string, commitID graveler.CommitID) graveler.BranchID {
+	id := commitID.String()
+	if len(id) > refsRecoveredBranchIDChars {
+		id = id[:refsRecoveredBranchIDChars]
+	}
+	return graveler.BranchID(prefix + id)
+}
+
+// openRefManager opens the KV store configured for lakeFS and returns a ref manager which works directly on it
+func openRefManager(ctx context.Context) (graveler.RefManager, kv.Store, error) {
+	cfg := loadConfig()
+	kvParams, err := kvparams.NewConfig(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("KV params: %w", err)
+	}
+	kvStore, err := kv.Open(ctx, kvParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open KV store: %w", err)
+	}
+	refManager := ref.NewRefManager(ref.ManagerConfig{
+		Executor:        batch.NopExecutor(),
+		KVStore:         kvStore,
+		KVStoreLimited:  kv.NewStoreLimiter(kvStore, ratelimit.NewUnlimited()),
+		AddressProvider: ident.NewHexAddressProvider(),
+	})
+	return refManager, kvStore, nil
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(refsCmd)
+	refsCmd.AddCommand(refsBranchShowCmd)
+	refsCmd.AddCommand(refsBranchResetCmd)
+	refsBranchResetCmd.Flags().Bool("yes", false, "apply the reset without further confirmation")
+	refsCmd.AddCommand(refsRebuildBranchesCmd)
+	refsRebuildBranchesCmd.Flags().String("prefix", "recovered-", "prefix of the created branch names, followed by the head commit ID")
+	refsRebuildBranchesCmd.Flags().Bool("yes", false, "create the branches, otherwise only list them")
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"golang.org/x/exp/slices"
+)
+
+// s3GatewayRouter serves S3 gateway requests by the gateway domain names. The gateway handler is rebuilt when the
+// domain names change.
+type s3GatewayRouter struct {
+	newHandler func(domainNames []string) http.Handler
+
+	mu          sync.RWMutex
+	domainNames []string
+	handler     http.Handler
+}
+
+func newS3GatewayRouter(domainNames []string, newHandler func(domainNames []string) http.Handler) *s3GatewayRouter {
+	return &s3GatewayRouter{
+		newHandler:  newHandler,
+		domainNames: domainNames,
+		handler:     newHandler(domainNames),
+	}
+}
+
+func (g *s3GatewayRouter) DomainNames() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.domainNames
+}
+
+// SetDomainNames rebuilds the gateway handler for domainNames. Requests in flight complete on the previous handler.
+func (g *s3GatewayRouter) SetDomainNames(domainNames []string) {
+	handler := g.newHandler(domainNames)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.domainNames = domainNames
+	g.handler = handler
+}
+
+// Matches reports whether the request host is a gateway domain name or a subdomain of one
+func (g *s3GatewayRouter) Matches(r *http.Request) bool {
+	domainNames := g.DomainNames()
+	return httputil.HostMatches(r, domainNames) || httputil.HostSubdomainOf(r, domainNames)
+}
+
+func (g *s3GatewayRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.RLock()
+	handler := g.handler
+	g.mu.RUnlock()
+	handler.ServeHTTP(w, r)
+}
+
+// configReloader re-reads the configuration and applies the settings that can change without restarting the server:
+// log level, background operations rate limit, hook endpoints allowlist and S3 gateway domain names. Other settings
+// keep their values until the next restart.
+type configReloader struct {
+	logger   logging.Logger
+	catalog  *catalog.Catalog
+	actions  *actions.StoreService
+	s3Router *s3GatewayRouter
+
+	mu  sync.Mutex
+	cfg *config.Config
+}
+
+func newConfigReloader(cfg *config.Config, c *catalog.Catalog, actionsService *actions.StoreService, s3Router *s3GatewayRouter, logger logging.Logger) *configReloader {
+	return &configReloader{
+		logger:   logger,
+		catalog:  c,
+		actions:  actionsService,
+		s3Router: s3Router,
+		cfg:      cfg,
+	}
+}
+
+// Reload reads the configuration file and applies it
+func (r *configReloader) Reload(_ context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := viper.ReadInConfig()
+	var errFileNotFound viper.ConfigFileNotFoundError
+	if err != nil && !errors.As(err, &errFileNotFound) {
+		return fmt.Errorf("read config: %w", err)
+	}
+	return r.apply()
+}
+
+// OnConfigChange applies the configuration after viper reads a changed configuration file
+func (r *configReloader) OnConfigChange() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.apply(); err != nil {
+		r.logger.WithError(err).Error("Failed to reload config")
+	}
+}
+
+// HandleSignals reloads the configuration on SIGHUP, until ctx is done
+func (r *configReloader) HandleSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				r.logger.Info("Received SIGHUP, reloading config")
+				if err := r.Reload(ctx); err != nil {
+					r.logger.WithError(err).Error("Failed to reload config")
+				}
+			}
+		}
+	}()
+}
+
+func (r *configReloader) apply() error {
+	cfg, err := newConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Logging.Level != logging.Level() {
+		r.logger.WithField("level", cfg.Logging.Level).Info("Update log level")
+		logging.SetLevel(cfg.Logging.Level)
+	}
+	if cfg.Graveler.Background.RateLimit != r.cfg.Graveler.Background.RateLimit {
+		r.logger.WithField("rate_limit", cfg.Graveler.Background.RateLimit).Info("Update background rate limit")
+		r.catalog.SetBackgroundRateLimit(cfg.Graveler.Background.RateLimit)
+	}
+	if !slices.Equal(cfg.Actions.Webhook.AllowedEndpoints, r.cfg.Actions.Webhook.AllowedEndpoints) {
+		r.logger.WithField("allowed_endpoints", cfg.Actions.Webhook.AllowedEndpoints).Info("Update hook endpoints allowlist")
+		r.actions.SetWebhookAllowedEndpoints(cfg.Actions.Webhook.AllowedEndpoints)
+	}
+	if !slices.Equal(cfg.Gateways.S3.DomainNames, r.cfg.Gateways.S3.DomainNames) {
+		r.logger.WithField("domain_names", cfg.Gateways.S3.DomainNames).Info("Update S3 gateway domain names")
+		r.s3Router.SetDomainNames(cfg.Gateways.S3.DomainNames)
+	}
+	r.cfg = cfg
+	return nil
+}
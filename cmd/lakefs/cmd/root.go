@@ -46,10 +46,16 @@ func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	rootCmd.PersistentFlags().Bool(config.UseLocalConfiguration, false, "Use lakeFS local default configuration")
 	rootCmd.PersistentFlags().Bool(config.QuickstartConfiguration, false, "Use lakeFS quickstart configuration")
+	rootCmd.PersistentFlags().Bool(config.DevConfiguration, false, "Use lakeFS embedded development configuration, with a seeded admin user and a sample repository")
+}
+
+// isLocalEnv reports whether lakeFS runs with an embedded KV store and a local blockstore
+func isLocalEnv(cfg *config.Config) bool {
+	return (cfg.Database.Type == local.DriverName || cfg.Database.Type == mem.DriverName) && cfg.Blockstore.Type == block.BlockstoreTypeLocal
 }
 
 func validateQuickstartEnv(cfg *config.Config) {
-	if (cfg.Database.Type != local.DriverName && cfg.Database.Type != mem.DriverName) || cfg.Blockstore.Type != block.BlockstoreTypeLocal {
+	if !isLocalEnv(cfg) {
 		_, _ = fmt.Fprint(os.Stderr, "\nFATAL: quickstart mode can only run with local settings\n")
 		os.Exit(1)
 	}
@@ -76,7 +82,7 @@ func useConfig(flagName string) bool {
 
 func newConfig() (*config.Config, error) {
 	name := ""
-	configurations := []string{config.QuickstartConfiguration, config.UseLocalConfiguration}
+	configurations := []string{config.DevConfiguration, config.QuickstartConfiguration, config.UseLocalConfiguration}
 	if idx := slices.IndexFunc(configurations, useConfig); idx != -1 {
 		name = configurations[idx]
 	}
@@ -86,8 +92,14 @@ func newConfig() (*config.Config, error) {
 		return nil, err
 	}
 
-	if name == config.QuickstartConfiguration {
+	switch name {
+	case config.QuickstartConfiguration:
 		validateQuickstartEnv(cfg)
+	case config.DevConfiguration:
+		if !isLocalEnv(cfg) {
+			_, _ = fmt.Fprint(os.Stderr, "\nFATAL: dev mode can only run with local settings\n")
+			os.Exit(1)
+		}
 	}
 	return cfg, nil
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -10,18 +12,24 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"path"
+	"strings"
 	"syscall"
 	"text/template"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-co-op/gocron"
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/accesslog"
 	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/actions/lua/lakefs"
 	"github.com/treeverse/lakefs/pkg/api"
 	"github.com/treeverse/lakefs/pkg/auth"
 	"github.com/treeverse/lakefs/pkg/auth/crypt"
+	"github.com/treeverse/lakefs/pkg/auth/model"
 	authparams "github.com/treeverse/lakefs/pkg/auth/params"
 	authremote "github.com/treeverse/lakefs/pkg/auth/remoteauthenticator"
 	"github.com/treeverse/lakefs/pkg/authentication"
@@ -31,9 +39,17 @@ import (
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/gateway"
 	"github.com/treeverse/lakefs/pkg/gateway/multipart"
+	"github.com/treeverse/lakefs/pkg/gateway/nfs"
 	"github.com/treeverse/lakefs/pkg/gateway/sig"
+	"github.com/treeverse/lakefs/pkg/gateway/webdav"
+	"github.com/treeverse/lakefs/pkg/gateway/website"
+	"github.com/treeverse/lakefs/pkg/graphqlapi"
+	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/grpcapi"
+	"github.com/treeverse/lakefs/pkg/health"
 	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/iceberg"
 	"github.com/treeverse/lakefs/pkg/kv"
 	_ "github.com/treeverse/lakefs/pkg/kv/cosmosdb"
 	_ "github.com/treeverse/lakefs/pkg/kv/dynamodb"
@@ -42,22 +58,35 @@ import (
 	"github.com/treeverse/lakefs/pkg/kv/mem"
 	_ "github.com/treeverse/lakefs/pkg/kv/postgres"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/notifications"
+	"github.com/treeverse/lakefs/pkg/openlineage"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/upload"
 	"github.com/treeverse/lakefs/pkg/version"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	gracefulShutdownTimeout = 30 * time.Second
 
 	mismatchedReposFlagName = "allow-mismatched-repos"
+
+	healthProbePartition = "health"
+	healthProbeKey       = "dummy"
 )
 
 type Shutter interface {
 	Shutdown(context.Context) error
 }
 
-var errSimplifiedOrExternalAuth = errors.New(`cannot set auth.ui_config.rbac to non-simplified without setting an external auth service`)
+var (
+	errSimplifiedOrExternalAuth = errors.New(`cannot set auth.ui_config.rbac to non-simplified without setting an external auth service`)
+	errBadClientCA              = errors.New("bad client CA")
+	errACMEHostNotAllowed       = errors.New("acme: host not allowed")
+)
 
 func checkAuthModeSupport(cfg *config.Config) error {
 	if !cfg.IsAuthUISimplified() && !cfg.IsAuthTypeAPI() {
@@ -104,24 +133,29 @@ var runCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := logging.ContextUnavailable()
 		cfg := loadConfig()
-		viper.WatchConfig()
-		viper.OnConfigChange(func(in fsnotify.Event) {
-			var c config.Config
-			if err := config.Unmarshal(&c); err != nil {
-				logger.WithError(err).Error("Failed to unmarshal config while reload")
-				return
-			}
-			if c.Logging.Level != logging.Level() {
-				logger.WithField("level", c.Logging.Level).Info("Update log level")
-				logging.SetLevel(c.Logging.Level)
-			}
-		})
-
 		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
 		defer stop()
 
 		logger.WithField("version", version.Version).Info("lakeFS run")
 
+		if cfg.AccessLog.Enabled {
+			accessLog, err := accesslog.New(ctx, accesslog.Config{
+				Format:        cfg.AccessLog.Format,
+				Output:        cfg.AccessLog.Output,
+				FileMaxSizeMB: cfg.AccessLog.FileMaxSizeMB,
+				FilesKeep:     cfg.AccessLog.FilesKeep,
+				S3: accesslog.S3Config{
+					Region:        cfg.AccessLog.S3.Region,
+					FlushInterval: cfg.AccessLog.S3.FlushInterval,
+				},
+			})
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create access log")
+			}
+			accesslog.SetDefault(accessLog)
+			defer func() { _ = accessLog.Close() }()
+		}
+
 		kvParams, err := kvparams.NewConfig(cfg)
 		if err != nil {
 			logger.WithError(err).Fatal("Get KV params")
@@ -185,6 +219,8 @@ var runCmd = &cobra.Command{
 			logger.WithError(err).Fatal("failed to create catalog")
 		}
 		defer func() { _ = c.Close() }()
+		// encrypt data written to repositories of projects using the KMS keys of their encryption domains
+		blockStore = c.EncryptionDomainAdapter(blockStore)
 
 		// usage report setup - default usage reporter is a no-op
 		usageReporter := stats.DefaultUsageReporter
@@ -193,6 +229,26 @@ var runCmd = &cobra.Command{
 			ur.Start(ctx, cfg.UsageReport.FlushInterval, logger.WithField("service", "usage_report"))
 			usageReporter = ur
 		}
+		if cfg.UsageReport.Attribution.Enabled {
+			recorder := stats.NewAttributionRecorder(kvStore)
+			recorder.Start(ctx, cfg.UsageReport.FlushInterval, logger.WithField("service", "usage_attribution"))
+			c.StartAttributionReports(ctx, recorder, catalog.AttributionReportsConfig{
+				Interval:              cfg.UsageReport.Attribution.Interval,
+				Location:              cfg.UsageReport.Attribution.Location,
+				BranchPrefixDelimiter: cfg.UsageReport.Attribution.BranchPrefixDelimiter,
+			}, logger.WithField("service", "usage_attribution"))
+		}
+		if cfg.ObjectAccess.ColdData.Enabled {
+			c.StartColdDataReports(ctx, catalog.ColdDataReportsConfig{
+				Interval:    cfg.ObjectAccess.ColdData.Interval,
+				InactiveFor: cfg.ObjectAccess.ColdData.InactiveFor,
+				PrefixDepth: cfg.ObjectAccess.ColdData.PrefixDepth,
+				Location:    path.Join(cfg.Committed.BlockStoragePrefix, "cold_data"),
+			}, logger.WithField("service", "cold_data"))
+		}
+		if integrity := cfg.Graveler.BranchHistory.Integrity; integrity.Enabled && integrity.NotarizationInterval > 0 {
+			c.StartIntegrityNotarization(ctx, integrity.NotarizationInterval, logger.WithField("service", "integrity_notarization"))
+		}
 
 		deleteScheduler := gocron.NewScheduler(time.UTC)
 		err = scheduleCleanupJobs(ctx, deleteScheduler, c)
@@ -203,6 +259,7 @@ var runCmd = &cobra.Command{
 
 		// initial setup - support only when a local database is configured.
 		// local database lock will make sure that only one instance will run the setup.
+		initialSetup := false
 		if (kvParams.Type == local.DriverName || kvParams.Type == mem.DriverName) &&
 			cfg.Installation.UserName != "" && cfg.Installation.AccessKeyID.SecureValue() != "" && cfg.Installation.SecretAccessKey.SecureValue() != "" {
 			setupCreds, err := setupLakeFS(ctx, cfg, authMetadataManager, authService, cfg.Installation.UserName,
@@ -211,6 +268,7 @@ var runCmd = &cobra.Command{
 				logger.WithError(err).WithField("admin", cfg.Installation.UserName).Fatal("Failed to initial setup environment")
 			}
 			if setupCreds != nil {
+				initialSetup = true
 				logger.WithField("admin", cfg.Installation.UserName).Info("Initial setup completed successfully")
 			}
 		}
@@ -225,10 +283,47 @@ var runCmd = &cobra.Command{
 			actions.Config(cfg.Actions),
 			cfg.ListenAddress,
 		)
+		// Lua hooks read the catalog in-process, authorized as the user running the hook
+		actionsService.SetCatalogAccess(&lakefs.CatalogAccess{
+			Catalog:      c,
+			Authorizer:   authService,
+			BlockAdapter: blockStore,
+		})
 
 		// wire actions into entry catalog
 		defer actionsService.Stop()
-		c.SetHooksHandler(actionsService)
+		var hooksHandler graveler.HooksHandler = actionsService
+		var notificationsService *notifications.Service
+		if cfg.Notifications.Enabled {
+			notificationsService = newNotificationsService(ctx, cfg, kvStore)
+			defer notificationsService.Stop()
+			notifyingHooks := notifications.NewHooksHandler(actionsService, notificationsService, func(ctx context.Context, repositoryID string) (string, error) {
+				repo, err := c.GetRepository(ctx, repositoryID)
+				if err != nil {
+					return "", err
+				}
+				return repo.DefaultBranch, nil
+			})
+			actionsService.SetRunFailureFunc(notifyingHooks.NotifyHookFailed)
+			hooksHandler = notifyingHooks
+		}
+		if cfg.OpenLineage.Enabled {
+			lineageHooks := openlineage.NewHooksHandler(ctx, hooksHandler,
+				openlineage.NewClient(cfg.OpenLineage.URL, cfg.OpenLineage.APIKey.SecureValue(), cfg.OpenLineage.Timeout),
+				c,
+				openlineage.Config{
+					Namespace:          cfg.OpenLineage.Namespace,
+					DatasetPrefixDepth: cfg.OpenLineage.DatasetPrefixDepth,
+				})
+			defer lineageHooks.Stop()
+			hooksHandler = lineageHooks
+		}
+		c.SetHooksHandler(hooksHandler)
+		if initialSetup && cfg.Installation.SampleRepository != "" {
+			if err := createSampleRepository(ctx, cfg, c, authService, blockStore); err != nil {
+				logger.WithError(err).WithField("repository", cfg.Installation.SampleRepository).Error("Failed to create sample repository")
+			}
+		}
 
 		middlewareAuthenticator := auth.ChainAuthenticator{
 			auth.NewBuiltinAuthenticator(authService),
@@ -246,7 +341,7 @@ var runCmd = &cobra.Command{
 
 		auditChecker := version.NewDefaultAuditChecker(cfg.Security.AuditCheckURL, metadata.InstallationID, version.NewDefaultVersionSource(cfg.Security.CheckLatestVersionCache))
 		defer auditChecker.Close()
-		if !version.IsVersionUnreleased() {
+		if !version.IsVersionUnreleased() && cfg.Security.AuditCheckInterval > 0 {
 			auditChecker.StartPeriodicCheck(ctx, cfg.Security.AuditCheckInterval, logger)
 		}
 
@@ -260,27 +355,9 @@ var runCmd = &cobra.Command{
 
 		// update health info with installation ID
 		httputil.SetHealthHandlerInfo(metadata.InstallationID)
-
-		// start API server
-		apiHandler := api.Serve(
-			cfg,
-			c,
-			middlewareAuthenticator,
-			authService,
-			authenticationService,
-			blockStore,
-			authMetadataManager,
-			migrator,
-			bufferedCollector,
-			cloudMetadataProvider,
-			actionsService,
-			auditChecker,
-			logger.WithField("service", "api_gateway"),
-			cfg.Gateways.S3.DomainNames,
-			cfg.UISnippets(),
-			upload.DefaultPathProvider,
-			usageReporter,
-		)
+		healthChecker := newHealthChecker(cfg, kvStore, blockStore, c, authService, logger.WithField("service", "health"))
+		healthChecker.Start(ctx)
+		healthHandler := healthChecker.Handler()
 
 		// init gateway server
 		var s3FallbackURL *url.URL
@@ -305,71 +382,239 @@ var runCmd = &cobra.Command{
 			logger.WithError(err).Fatal("could not initialize authenticator for S3 gateway")
 		}
 
-		s3gatewayHandler := gateway.NewHandler(
-			cfg.Gateways.S3.Region,
+		anonymousRead := newAnonymousReadPolicy(cfg)
+		gatewayDenials := gateway.NewDenialLog(cfg.Gateways.S3.DenialsLogSize)
+		c.SetEncryptionKeyAccess(auth.NewEncryptionKeyAccess(authService, anonymousRead))
+		s3Router := newS3GatewayRouter(cfg.Gateways.S3.DomainNames, func(domainNames []string) http.Handler {
+			return apiAuthenticator(gateway.NewHandler(
+				cfg.Gateways.S3.Region,
+				c,
+				multipartTracker,
+				blockStore,
+				authService,
+				anonymousRead,
+				domainNames,
+				bufferedCollector,
+				upload.DefaultPathProvider,
+				s3FallbackURL,
+				cfg.Logging.AuditLogLevel,
+				cfg.Logging.TraceRequestHeaders,
+				cfg.Gateways.S3.VerifyUnsupported,
+				gatewayDenials,
+			))
+		})
+
+		// reload selected settings on config file change, SIGHUP or API request
+		reloader := newConfigReloader(cfg, c, actionsService, s3Router, logger.WithField("service", "config"))
+		viper.WatchConfig()
+		viper.OnConfigChange(func(in fsnotify.Event) {
+			reloader.OnConfigChange()
+		})
+		reloader.HandleSignals(ctx)
+
+		// start API server
+		apiHandler := api.Serve(
+			cfg,
 			c,
-			multipartTracker,
-			blockStore,
+			middlewareAuthenticator,
 			authService,
-			cfg.Gateways.S3.DomainNames,
+			authenticationService,
+			blockStore,
+			authMetadataManager,
+			migrator,
 			bufferedCollector,
+			cloudMetadataProvider,
+			actionsService,
+			auditChecker,
+			logger.WithField("service", "api_gateway"),
+			cfg.Gateways.S3.DomainNames,
+			cfg.UISnippets(),
 			upload.DefaultPathProvider,
-			s3FallbackURL,
-			cfg.Logging.AuditLogLevel,
-			cfg.Logging.TraceRequestHeaders,
-			cfg.Gateways.S3.VerifyUnsupported,
+			usageReporter,
+			reloader,
+			anonymousRead,
+			notificationsService,
+			gatewayDenials,
 		)
-		s3gatewayHandler = apiAuthenticator(s3gatewayHandler)
+
+		var icebergHandler http.Handler
+		if cfg.Iceberg.Enabled {
+			icebergHandler = apiAuthenticator(iceberg.NewHandler(
+				c,
+				middlewareAuthenticator,
+				authService,
+				upload.DefaultPathProvider,
+				iceberg.Config{
+					S3Endpoint:    cfg.Iceberg.S3Endpoint,
+					LoginDuration: cfg.Auth.LoginDuration,
+				},
+				cfg.Logging.AuditLogLevel,
+				cfg.Logging.TraceRequestHeaders,
+			))
+		}
+
+		var graphqlHandler http.Handler
+		if cfg.GraphQL.Enabled {
+			handler, err := graphqlapi.NewHandler(c, authService, actionsService, cfg.Logging.AuditLogLevel, cfg.Logging.TraceRequestHeaders)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create GraphQL handler")
+			}
+			graphqlHandler = apiAuthenticator(handler)
+		}
+
+		var webdavHandler http.Handler
+		if cfg.Gateways.WebDAV.Enabled {
+			webdavHandler = apiAuthenticator(webdav.NewHandler(
+				c,
+				authService,
+				upload.DefaultPathProvider,
+				cfg.Gateways.WebDAV.Writable,
+				cfg.Logging.AuditLogLevel,
+				cfg.Logging.TraceRequestHeaders,
+			))
+		}
+
+		var websiteHandler http.Handler
+		if cfg.Gateways.Website.Enabled {
+			websiteHandler = apiAuthenticator(website.NewHandler(
+				c,
+				authService,
+				anonymousRead,
+				website.Config{
+					IndexDocument: cfg.Gateways.Website.IndexDocument,
+					ErrorDocument: cfg.Gateways.Website.ErrorDocument,
+				},
+				cfg.Logging.AuditLogLevel,
+				cfg.Logging.TraceRequestHeaders,
+			))
+		}
+
+		if cfg.Gateways.NFS.Enabled {
+			nfsServer, err := nfs.NewServer(ctx, c, cfg.Gateways.NFS.ListenAddress, cfg.Gateways.NFS.Repositories)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to start NFS gateway")
+			}
+			nfsServer.ListenAndServe(ctx)
+			defer func() { _ = nfsServer.Close() }()
+		}
+
+		// each listener acquires its ACME certificates once, the gRPC server shares those of the main listener
+		listeners := cfg.HTTPListeners()
+		acmeManagers := make([]*autocert.Manager, len(listeners))
+		for i, listener := range listeners {
+			if !listener.TLS.ACME.Enabled {
+				continue
+			}
+			acmeManagers[i], err = newACMEManager(listener.TLS.ACME, s3Router.DomainNames)
+			if err != nil {
+				logger.WithError(err).WithField("listen_address", listener.ListenAddress).Fatal("Failed to setup ACME certificate manager")
+			}
+		}
+
+		var grpcServer *grpcapi.Server
+		if cfg.GRPC.Enabled {
+			var grpcOpts []grpc.ServerOption
+			if cfg.TLS.Enabled {
+				tlsConfig, err := newGRPCTLSConfig(listeners[0], acmeManagers[0])
+				if err != nil {
+					logger.WithError(err).Fatal("Failed to load gRPC server TLS credentials")
+				}
+				grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+			}
+			grpcServer, err = grpcapi.NewServer(c, middlewareAuthenticator, authService, upload.DefaultPathProvider, cfg.GRPC.ListenAddress, logger, grpcOpts...)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to start gRPC server")
+			}
+			grpcServer.ListenAndServe(ctx)
+		}
 
 		bufferedCollector.Start(ctx)
 		defer bufferedCollector.Close()
 
 		bufferedCollector.CollectEvent(stats.Event{Class: "global", Name: "run"})
 
-		logger.WithField("listen_address", cfg.ListenAddress).Info("starting HTTP server")
-		server := &http.Server{
-			Addr:              cfg.ListenAddress,
-			ReadHeaderTimeout: time.Minute,
-			Handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-				// If the request has the S3 GW domain (exact or subdomain) - or carries an AWS sig, serve S3GW
-				if httputil.HostMatches(request, cfg.Gateways.S3.DomainNames) ||
-					httputil.HostSubdomainOf(request, cfg.Gateways.S3.DomainNames) ||
-					sig.IsAWSSignedRequest(request) {
-					s3gatewayHandler.ServeHTTP(writer, request)
-					return
-				}
+		handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			// Liveness and readiness probes are served by the health checker
+			if strings.HasPrefix(request.URL.Path, health.BasePath+"/") {
+				healthHandler.ServeHTTP(writer, request)
+				return
+			}
 
-				// Otherwise, serve the API handler
-				apiHandler.ServeHTTP(writer, request)
-			}),
-		}
+			// Iceberg REST catalog requests are served by the catalog handler, when enabled
+			if icebergHandler != nil && strings.HasPrefix(request.URL.Path, iceberg.BasePath+"/") {
+				icebergHandler.ServeHTTP(writer, request)
+				return
+			}
+
+			// GraphQL queries are served by the GraphQL handler, when enabled
+			if graphqlHandler != nil && request.URL.Path == graphqlapi.BasePath {
+				graphqlHandler.ServeHTTP(writer, request)
+				return
+			}
 
-		actionsService.SetEndpoint(server)
+			// WebDAV requests are served by the WebDAV gateway, when enabled
+			if webdavHandler != nil && (request.URL.Path == webdav.BasePath || strings.HasPrefix(request.URL.Path, webdav.BasePath+"/")) {
+				webdavHandler.ServeHTTP(writer, request)
+				return
+			}
 
-		go func() {
-			var err error
-			if cfg.TLS.Enabled {
-				err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
-			} else {
-				err = server.ListenAndServe()
+			// Refs are served as static websites by the website gateway, when enabled
+			if websiteHandler != nil && strings.HasPrefix(request.URL.Path, website.BasePath+"/") {
+				websiteHandler.ServeHTTP(writer, request)
+				return
+			}
+
+			// If the request has the S3 GW domain (exact or subdomain) - or carries an AWS sig, serve S3GW
+			if s3Router.Matches(request) || sig.IsAWSSignedRequest(request) {
+				s3Router.ServeHTTP(writer, request)
+				return
+			}
+
+			// Otherwise, serve the API handler
+			apiHandler.ServeHTTP(writer, request)
+		})
+
+		servers := make([]Shutter, 0, len(listeners)+1)
+		for i, listener := range listeners {
+			server, err := newHTTPServer(listener, acmeManagers[i], handler)
+			if err != nil {
+				logger.WithError(err).WithField("listen_address", listener.ListenAddress).Fatal("Failed to setup HTTP server")
 			}
-			if err != nil && !errors.Is(err, http.ErrServerClosed) {
-				_, _ = fmt.Fprintf(os.Stderr, "Failed to listen on %s: %v\n", cfg.ListenAddress, err)
-				os.Exit(1)
+			if i == 0 {
+				// actions call lakeFS through the main listener handler
+				actionsService.SetEndpoint(server)
 			}
-		}()
+			logger.WithFields(logging.Fields{
+				"listen_address": listener.ListenAddress,
+				"tls":            listener.TLS.Enabled,
+				"mtls":           listener.TLS.ClientCAFile != "",
+			}).Info("starting HTTP server")
+			go serveHTTP(server, listener)
+			servers = append(servers, server)
+		}
+		if grpcServer != nil {
+			servers = append(servers, grpcServer)
+		}
 
 		isQuickstart, err := cmd.Flags().GetBool(config.QuickstartConfiguration)
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to get command flag %s: %v\n", config.QuickstartConfiguration, err)
 			os.Exit(1)
 		}
+		isDev, err := cmd.Flags().GetBool(config.DevConfiguration)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to get command flag %s: %v\n", config.DevConfiguration, err)
+			os.Exit(1)
+		}
 
 		data := bannerData{
 			SetupMessage: localBanner,
 			Version:      version.Version,
 		}
-		if isQuickstart {
+		switch {
+		case isDev:
+			data.SetupMessage = devBanner(cfg)
+		case isQuickstart:
 			data.SetupMessage = quickStartBanner
 		}
 
@@ -380,10 +625,207 @@ var runCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		printWelcome(os.Stderr, buf.String())
-		gracefulShutdown(ctx, server)
+		gracefulShutdown(ctx, servers...)
 	},
 }
 
+// newHTTPServer returns a server for the listener address and TLS configuration. When the listener sets a client CA
+// file, clients must present a certificate signed by one of its CAs. When the listener enables ACME, certificates are
+// served by acmeManager.
+func newHTTPServer(listener config.Listener, acmeManager *autocert.Manager, handler http.Handler) (*http.Server, error) {
+	tlsConfig, err := listenerTLSConfig(listener, acmeManager)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Server{
+		Addr:              listener.ListenAddress,
+		ReadHeaderTimeout: time.Minute,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+	}, nil
+}
+
+// listenerTLSConfig returns the TLS configuration for the ACME certificates, the SNI certificates and the client CA
+// file of the listener, nil when it uses none of them.
+func listenerTLSConfig(listener config.Listener, acmeManager *autocert.Manager) (*tls.Config, error) {
+	var tlsConfig *tls.Config
+	if acmeManager != nil {
+		tlsConfig = acmeManager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+	if len(listener.TLS.Certificates) > 0 {
+		selector, err := newCertificateSelector(listener.TLS)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: selector.GetCertificate,
+		}
+	}
+	if listener.TLS.ClientCAFile != "" {
+		pem, err := os.ReadFile(listener.TLS.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates in %s", errBadClientCA, listener.TLS.ClientCAFile)
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// newCertificateSelector returns a selector serving the certificates of the TLS configuration by the server name
+// clients request, and its certificate file to clients requesting other names
+func newCertificateSelector(cfg config.TLS) (*httputil.CertificateSelector, error) {
+	var fallback *tls.Certificate
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate: %w", err)
+		}
+		fallback = &cert
+	}
+	certificates := make([]httputil.SNICertificate, 0, len(cfg.Certificates))
+	for _, c := range cfg.Certificates {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate %s: %w", c.CertFile, err)
+		}
+		certificates = append(certificates, httputil.SNICertificate{Domains: c.Domains, Certificate: cert})
+	}
+	return httputil.NewCertificateSelector(certificates, fallback)
+}
+
+// newGRPCTLSConfig returns the TLS configuration of the gRPC server: the certificates, ACME certificates and client CA
+// file of listener.
+func newGRPCTLSConfig(listener config.Listener, acmeManager *autocert.Manager) (*tls.Config, error) {
+	tlsConfig, err := listenerTLSConfig(listener, acmeManager)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if tlsConfig.GetCertificate == nil {
+		cert, err := tls.LoadX509KeyPair(listener.TLS.CertFile, listener.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// newACMEManager returns a certificate manager answering TLS-ALPN-01 challenges on the listener itself
+func newACMEManager(cfg config.ACME, gatewayDomainNames func() []string) (*autocert.Manager, error) {
+	cacheDir := cfg.CacheDir
+	if cacheDir == "" {
+		cacheDir = config.DefaultACMECacheDir
+	}
+	cacheDir, err := homedir.Expand(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("acme cache dir: %w", err)
+	}
+	manager := &autocert.Manager{
+		Prompt: autocert.AcceptTOS,
+		Cache:  autocert.DirCache(cacheDir),
+		Email:  cfg.Email,
+		HostPolicy: func(_ context.Context, host string) error {
+			if !cfg.AllowsHost(host, gatewayDomainNames()) {
+				return fmt.Errorf("%w: %s", errACMEHostNotAllowed, host)
+			}
+			return nil
+		},
+	}
+	if cfg.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+	return manager, nil
+}
+
+func serveHTTP(server *http.Server, listener config.Listener) {
+	var err error
+	switch {
+	case listener.TLS.ACME.Enabled, len(listener.TLS.Certificates) > 0:
+		// certificates are served by the ACME manager or the certificate selector through the server TLS configuration
+		err = server.ListenAndServeTLS("", "")
+	case listener.TLS.Enabled:
+		err = server.ListenAndServeTLS(listener.TLS.CertFile, listener.TLS.KeyFile)
+	default:
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to listen on %s: %v\n", listener.ListenAddress, err)
+		os.Exit(1)
+	}
+}
+
+// newNotificationsService returns the service delivering repository event notifications to user subscriptions
+func newNotificationsService(ctx context.Context, cfg *config.Config, kvStore kv.Store) *notifications.Service {
+	smtpCfg := cfg.Notifications.SMTP
+	return notifications.NewService(ctx, kvStore, notifications.Config{
+		DeliveryTimeout: cfg.Notifications.DeliveryTimeout,
+		SMTP: notifications.SMTPConfig{
+			Host:     smtpCfg.Host,
+			Port:     smtpCfg.Port,
+			Username: smtpCfg.Username,
+			Password: smtpCfg.Password.SecureValue(),
+			From:     smtpCfg.From,
+		},
+		SlackAllowedEndpoints: cfg.Notifications.Slack.AllowedEndpoints,
+	})
+}
+
+// newAnonymousReadPolicy returns the policy authorizing requests without credentials, nil if none are allowed
+func newAnonymousReadPolicy(cfg *config.Config) *auth.AnonymousReadPolicy {
+	rules := make([]auth.AnonymousReadRule, 0, len(cfg.Auth.AnonymousRead))
+	for _, rule := range cfg.Auth.AnonymousRead {
+		rules = append(rules, auth.AnonymousReadRule(rule))
+	}
+	return auth.NewAnonymousReadPolicy(rules)
+}
+
+// newHealthChecker returns a checker probing the dependencies lakeFS requires to serve requests: the KV store, the
+// blockstore (through the storage namespace of a repository) and the auth service.
+func newHealthChecker(cfg *config.Config, kvStore kv.Store, blockStore block.Adapter, c *catalog.Catalog, authService auth.Service, logger logging.Logger) *health.Checker {
+	checks := map[string]health.CheckFunc{
+		"kv": func(ctx context.Context) error {
+			_, err := kvStore.Get(ctx, []byte(healthProbePartition), []byte(healthProbeKey))
+			if errors.Is(err, kv.ErrNotFound) {
+				return nil
+			}
+			return err
+		},
+		"blockstore": func(ctx context.Context) error {
+			repos, _, err := c.ListRepositories(ctx, 1, "", "")
+			if err != nil {
+				return fmt.Errorf("list repositories: %w", err)
+			}
+			if len(repos) == 0 {
+				return nil
+			}
+			_, err = blockStore.Exists(ctx, block.ObjectPointer{
+				StorageNamespace: repos[0].StorageNamespace,
+				IdentifierType:   block.IdentifierTypeRelative,
+				Identifier:       cfg.Committed.BlockStoragePrefix + "/" + healthProbeKey,
+			})
+			return err
+		},
+		"auth": func(ctx context.Context) error {
+			_, _, err := authService.ListUsers(ctx, &model.PaginationParams{Amount: 1})
+			return err
+		},
+	}
+	return health.NewChecker(checks, cfg.Health.ProbeInterval, cfg.Health.ProbeTimeout, logger)
+}
+
 // checkRepos iterating on all repos and validates that their settings are correct.
 func checkRepos(ctx context.Context, logger logging.Logger, authMetadataManager auth.MetadataManager, blockStore block.Adapter, c *catalog.Catalog) {
 	initialized, err := authMetadataManager.IsInitialized(ctx)
@@ -508,6 +950,24 @@ var quickStartBanner = fmt.Sprintf(`
 │
 `, config.DefaultQuickstartKeyID, config.DefaultQuickstartSecretKey)
 
+// devBanner is the setup message of lakeFS running in dev mode, with the credentials of the seeded admin user
+func devBanner(cfg *config.Config) string {
+	msg := fmt.Sprintf(`
+│
+│ lakeFS running in dev mode.
+│     Login at http://127.0.0.1:8000/
+│
+│     Access Key ID    : %s
+│     Secret Access Key: %s
+│`, cfg.Installation.AccessKeyID.SecureValue(), cfg.Installation.SecretAccessKey.SecureValue())
+	if cfg.Installation.SampleRepository != "" {
+		msg += fmt.Sprintf(`
+│     Sample repository: lakefs://%s
+│`, cfg.Installation.SampleRepository)
+	}
+	return msg + "\n"
+}
+
 type bannerData struct {
 	SetupMessage string
 	Version      string
@@ -11,11 +11,15 @@ import (
 	"github.com/treeverse/lakefs/pkg/auth/model"
 	authparams "github.com/treeverse/lakefs/pkg/auth/params"
 	"github.com/treeverse/lakefs/pkg/auth/setup"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/samplerepo"
 	"github.com/treeverse/lakefs/pkg/stats"
+	"github.com/treeverse/lakefs/pkg/upload"
 	"github.com/treeverse/lakefs/pkg/version"
 )
 
@@ -127,6 +131,31 @@ func setupLakeFS(ctx context.Context, cfg *config.Config, metadataManager auth.M
 	return credentials, nil
 }
 
+// sampleRepositoryDefaultBranch is the default branch of the sample repository created by the initial setup
+const sampleRepositoryDefaultBranch = "main"
+
+// createSampleRepository creates the sample repository of the installation on the local blockstore, with sample
+// data committed by the admin user and its default branch protected
+func createSampleRepository(ctx context.Context, cfg *config.Config, c *catalog.Catalog, authService auth.Service, blockAdapter block.Adapter) error {
+	user, err := authService.GetUser(ctx, cfg.Installation.UserName)
+	if err != nil {
+		return fmt.Errorf("get admin user: %w", err)
+	}
+	name := cfg.Installation.SampleRepository
+	storageNamespace := block.BlockstoreTypeLocal + "://" + name
+	repo, err := c.CreateRepository(ctx, name, storageNamespace, sampleRepositoryDefaultBranch, false)
+	if err != nil {
+		return fmt.Errorf("create repository: %w", err)
+	}
+	if err := samplerepo.PopulateSampleRepo(ctx, repo, c, upload.DefaultPathProvider, blockAdapter, user); err != nil {
+		return fmt.Errorf("populate sample data: %w", err)
+	}
+	if err := samplerepo.AddBranchProtection(ctx, repo, c); err != nil {
+		return fmt.Errorf("add branch protection: %w", err)
+	}
+	return nil
+}
+
 const internalErrorCode = 2
 
 //nolint:gochecknoinits
@@ -0,0 +1,234 @@
+package esti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/config"
+)
+
+// GC engines, selected by the ESTI_GC_ENGINE environment variable
+const (
+	// gcEngineDockerSpark runs the Spark GC job in a local Bitnami Spark container, submitting to a local Spark master
+	gcEngineDockerSpark = "docker-spark"
+	// gcEngineSparkCluster runs the Spark GC job by submitting it to an external cluster, such as EMR, using a local
+	// spark-submit
+	gcEngineSparkCluster = "spark-cluster"
+	// gcEngineNative runs a native GC command
+	gcEngineNative = "native"
+
+	gcSparkEntryPoint = "io.treeverse.gc.GarbageCollection"
+
+	gcHadoopAzurePackage = "org.apache.hadoop:hadoop-azure:3.2.1"
+	gcGCSConnectorJar    = "https://storage.googleapis.com/hadoop-lib/gcs/gcs-connector-hadoop3-latest.jar"
+	// gcDockerGCSCredentialsPath is where the GCS credentials file is mounted in the Spark container
+	gcDockerGCSCredentialsPath = "/opt/gcs/credentials.json"
+)
+
+var (
+	errUnknownGCEngine     = errors.New("unknown GC engine")
+	errGCEngineUnavailable = errors.New("GC engine unavailable")
+)
+
+// gcRunParams are the parameters of a single GC run
+type gcRunParams struct {
+	repository string
+	region     string
+	// uncommittedMinAge is the minimal age of uncommitted objects collected by the run
+	uncommittedMinAge time.Duration
+	logSource         string
+}
+
+// gcEngine runs garbage collection on a repository
+type gcEngine interface {
+	RunGC(ctx context.Context, params *gcRunParams) error
+}
+
+// newGCEngine returns the GC engine configured by ESTI_GC_ENGINE, docker-spark by default. Returns
+// errGCEngineUnavailable if the engine is not configured well enough to run.
+func newGCEngine() (gcEngine, error) {
+	engine := viper.GetString("gc_engine")
+	switch engine {
+	case "", gcEngineDockerSpark:
+		return &dockerSparkGCEngine{
+			sparkVersion: sparkImageTag,
+			localJar:     metaClientJarPath,
+		}, nil
+	case gcEngineSparkCluster:
+		master := viper.GetString("gc_spark_master")
+		if master == "" {
+			return nil, fmt.Errorf("%w: %s requires ESTI_GC_SPARK_MASTER", errGCEngineUnavailable, engine)
+		}
+		jar := viper.GetString("gc_spark_jar")
+		if jar == "" {
+			jar = metaClientJarPath
+		}
+		apiURL := viper.GetString("gc_lakefs_api_url")
+		if apiURL == "" {
+			apiURL = strings.TrimSuffix(viper.GetString("endpoint_url"), "/") + apiutil.BaseURL
+		}
+		sparkSubmit := viper.GetString("gc_spark_submit")
+		if sparkSubmit == "" {
+			sparkSubmit = "spark-submit"
+		}
+		return &sparkClusterGCEngine{
+			sparkSubmit: sparkSubmit,
+			master:      master,
+			deployMode:  viper.GetString("gc_spark_deploy_mode"),
+			jar:         jar,
+			apiURL:      apiURL,
+			extraConfs:  viper.GetStringSlice("gc_spark_conf"),
+		}, nil
+	case gcEngineNative:
+		command := strings.Fields(viper.GetString("gc_native_command"))
+		if len(command) == 0 {
+			return nil, fmt.Errorf("%w: %s requires ESTI_GC_NATIVE_COMMAND", errGCEngineUnavailable, engine)
+		}
+		return &nativeGCEngine{command: command}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownGCEngine, engine)
+	}
+}
+
+func gcSparkConfs(params *gcRunParams) []string {
+	return []string{"--conf", "spark.hadoop.lakefs.debug.gc.uncommitted_min_age_seconds=" + strconv.Itoa(int(params.uncommittedMinAge.Seconds()))}
+}
+
+// gcBlockstoreSubmitArgs returns the spark-submit arguments the GC job needs to access the blockstore of the tests.
+// gcsCredentialsFile is the GCS credentials file as seen by spark-submit. S3 credentials are passed in the
+// environment.
+func gcBlockstoreSubmitArgs(gcsCredentialsFile string) []string {
+	switch viper.GetString(config.BlockstoreTypeKey) {
+	case block.BlockstoreTypeAzure:
+		account := viper.GetString("azure_storage_account")
+		key := viper.GetString("azure_storage_access_key")
+		return []string{
+			"--packages", gcHadoopAzurePackage,
+			"--conf", fmt.Sprintf("spark.hadoop.fs.azure.account.key.%s.dfs.core.windows.net=%s", account, key),
+			"--conf", fmt.Sprintf("spark.hadoop.fs.azure.account.key.%s.blob.core.windows.net=%s", account, key),
+		}
+	case block.BlockstoreTypeGS:
+		// GC on GCS supports only the mark phase
+		return []string{
+			"--jars", gcGCSConnectorJar,
+			"--conf", "spark.hadoop.google.cloud.auth.service.account.enable=true",
+			"--conf", "spark.hadoop.google.cloud.auth.service.account.json.keyfile=" + gcsCredentialsFile,
+			"--conf", "spark.hadoop.fs.gs.project.id=" + viper.GetString("gcs_project_id"),
+			"--conf", "spark.hadoop.fs.gs.impl=com.google.cloud.hadoop.fs.gcs.GoogleHadoopFileSystem",
+			"--conf", "spark.hadoop.fs.AbstractFileSystem.gs.impl=com.google.cloud.hadoop.fs.gcs.GoogleHadoopFS",
+			"--conf", "spark.hadoop.lakefs.gc.do_sweep=false",
+		}
+	default:
+		return nil
+	}
+}
+
+// gcsCredentialsFile returns a file holding the GCS credentials of the tests, writing ESTI_GCS_CREDENTIALS_JSON to
+// a temporary file if ESTI_GCS_CREDENTIALS_FILE is not set
+func gcsCredentialsFile() (string, error) {
+	if f := viper.GetString("gcs_credentials_file"); f != "" {
+		return f, nil
+	}
+	credentials := viper.GetString("gcs_credentials_json")
+	if credentials == "" {
+		return "", fmt.Errorf("%w: GCS requires ESTI_GCS_CREDENTIALS_FILE or ESTI_GCS_CREDENTIALS_JSON", errGCEngineUnavailable)
+	}
+	f, err := os.CreateTemp("", "esti-gcs-credentials-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(credentials); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+type dockerSparkGCEngine struct {
+	sparkVersion string
+	localJar     string
+}
+
+func (e *dockerSparkGCEngine) RunGC(_ context.Context, params *gcRunParams) error {
+	var volumes []string
+	if viper.GetString(config.BlockstoreTypeKey) == block.BlockstoreTypeGS {
+		credentialsFile, err := gcsCredentialsFile()
+		if err != nil {
+			return err
+		}
+		volumes = append(volumes, credentialsFile+":"+gcDockerGCSCredentialsPath)
+	}
+	return runSparkSubmit(&sparkSubmitConfig{
+		sparkVersion:    e.sparkVersion,
+		localJar:        e.localJar,
+		entryPoint:      gcSparkEntryPoint,
+		programArgs:     []string{params.repository, params.region},
+		extraSubmitArgs: append(gcBlockstoreSubmitArgs(gcDockerGCSCredentialsPath), gcSparkConfs(params)...),
+		volumes:         volumes,
+		logSource:       params.logSource,
+	})
+}
+
+// sparkClusterGCEngine submits the GC job to an external Spark cluster. The cluster must be able to reach lakeFS
+// at apiURL, and to read jar.
+type sparkClusterGCEngine struct {
+	sparkSubmit string
+	master      string
+	deployMode  string
+	jar         string
+	apiURL      string
+	// extraConfs are passed as --conf to spark-submit, for cluster specific configuration
+	extraConfs []string
+}
+
+func (e *sparkClusterGCEngine) RunGC(ctx context.Context, params *gcRunParams) error {
+	args := []string{
+		"--master", e.master,
+		"--conf", "spark.hadoop.lakefs.api.url=" + e.apiURL,
+		"--conf", "spark.hadoop.lakefs.api.access_key=" + viper.GetString("access_key_id"),
+		"--conf", "spark.hadoop.lakefs.api.secret_key=" + viper.GetString("secret_access_key"),
+		"--class", gcSparkEntryPoint,
+	}
+	if e.deployMode != "" {
+		args = append(args, "--deploy-mode", e.deployMode)
+	}
+	for _, conf := range e.extraConfs {
+		args = append(args, "--conf", conf)
+	}
+	// the GCS credentials file must exist at the same path on the cluster
+	args = append(args, gcBlockstoreSubmitArgs(viper.GetString("gcs_credentials_file"))...)
+	args = append(args, gcSparkConfs(params)...)
+	args = append(args, e.jar, params.repository, params.region)
+	cmd := exec.CommandContext(ctx, e.sparkSubmit, args...)
+	// do not log the command, it holds the lakeFS credentials
+	logger.WithField("master", e.master).Info("Submitting GC job to Spark cluster")
+	return runCommand(params.logSource, cmd)
+}
+
+// nativeGCEngine runs a GC command on the repository, passing it the repository and region as arguments and the
+// uncommitted min age in seconds as LAKEFS_GC_UNCOMMITTED_MIN_AGE_SECONDS
+type nativeGCEngine struct {
+	command []string
+}
+
+func (e *nativeGCEngine) RunGC(ctx context.Context, params *gcRunParams) error {
+	args := append(append([]string{}, e.command[1:]...), params.repository, params.region)
+	cmd := exec.CommandContext(ctx, e.command[0], args...)
+	cmd.Env = append(cmd.Environ(),
+		"LAKEFS_GC_UNCOMMITTED_MIN_AGE_SECONDS="+strconv.Itoa(int(params.uncommittedMinAge.Seconds())),
+		"LAKECTL_SERVER_ENDPOINT_URL="+viper.GetString("endpoint_url"),
+		"LAKECTL_CREDENTIALS_ACCESS_KEY_ID="+viper.GetString("access_key_id"),
+		"LAKECTL_CREDENTIALS_SECRET_ACCESS_KEY="+viper.GetString("secret_access_key"),
+	)
+	logger.Infof("Running native GC command: %s", strings.Join(e.command, " "))
+	return runCommand(params.logSource, cmd)
+}
@@ -23,15 +23,20 @@ func getSparkSubmitArgs(entryPoint string) []string {
 	}
 }
 
-func getDockerArgs(workingDirectory string, localJar string) []string {
-	return []string{
+func getDockerArgs(workingDirectory string, localJar string, volumes []string) []string {
+	args := []string{
 		"run", "--network", "host", "--add-host", "lakefs:127.0.0.1",
 		"-v", fmt.Sprintf("%s/ivy:/opt/bitnami/spark/.ivy2", workingDirectory),
 		"-v", fmt.Sprintf("%s:/opt/metaclient/client.jar", localJar),
+	}
+	for _, volume := range volumes {
+		args = append(args, "-v", volume)
+	}
+	return append(args,
 		"--rm",
 		"-e", "AWS_ACCESS_KEY_ID",
 		"-e", "AWS_SECRET_ACCESS_KEY",
-	}
+	)
 }
 
 // handlePipe calls log on each line of pipe, and writes nil or an error to
@@ -100,7 +105,9 @@ type sparkSubmitConfig struct {
 	entryPoint      string
 	extraSubmitArgs []string
 	programArgs     []string
-	logSource       string
+	// volumes are additional volumes mounted in the container, as docker -v arguments
+	volumes   []string
+	logSource string
 }
 
 func runSparkSubmit(config *sparkSubmitConfig) error {
@@ -109,7 +116,7 @@ func runSparkSubmit(config *sparkSubmitConfig) error {
 		return fmt.Errorf("getting working directory: %w", err)
 	}
 	workingDirectory = strings.TrimSuffix(workingDirectory, "/")
-	dockerArgs := getDockerArgs(workingDirectory, config.localJar)
+	dockerArgs := getDockerArgs(workingDirectory, config.localJar, config.volumes)
 	dockerArgs = append(dockerArgs, fmt.Sprintf("docker.io/bitnami/spark:%s", config.sparkVersion), "spark-submit")
 	sparkSubmitArgs := getSparkSubmitArgs(config.entryPoint)
 	sparkSubmitArgs = append(sparkSubmitArgs, config.extraSubmitArgs...)
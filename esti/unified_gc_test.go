@@ -2,15 +2,25 @@ package esti
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"cloud.google.com/go/storage"
 	"github.com/go-openapi/swag"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/testutil"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
 )
 
 const RepoName = "repo1"
@@ -21,16 +31,30 @@ type objectEvent struct {
 	commitDaysAgo int // if set to -1, do not commit
 }
 
-func gcTestCreateObject(t *testing.T, ctx context.Context, branch string, key string) string {
+// gcTestObject locates an object created by a GC test, to check whether GC collected it
+type gcTestObject struct {
+	presignedURL    string
+	physicalAddress string
+}
+
+func gcTestCreateObject(t *testing.T, ctx context.Context, branch string, key string) gcTestObject {
 	t.Helper()
 	_, _ = uploadFileRandomData(ctx, t, RepoName, branch, key)
-	res, err := client.StatObjectWithResponse(ctx, RepoName, branch, &apigen.StatObjectParams{
-		Path:    key,
-		Presign: swag.Bool(true),
-	})
-	testutil.MustDo(t, fmt.Sprintf("Stats object %s after upload", key), err)
-	require.Falsef(t, res.StatusCode() != 200, "Unexpected status code %d in stats object after upload", res.StatusCode())
-	return res.JSON200.PhysicalAddress
+	var obj gcTestObject
+	for _, presign := range []bool{true, false} {
+		res, err := client.StatObjectWithResponse(ctx, RepoName, branch, &apigen.StatObjectParams{
+			Path:    key,
+			Presign: swag.Bool(presign),
+		})
+		testutil.MustDo(t, fmt.Sprintf("Stats object %s after upload", key), err)
+		require.Falsef(t, res.StatusCode() != 200, "Unexpected status code %d in stats object after upload", res.StatusCode())
+		if presign {
+			obj.presignedURL = res.JSON200.PhysicalAddress
+		} else {
+			obj.physicalAddress = res.JSON200.PhysicalAddress
+		}
+	}
+	return obj
 }
 
 func gcTestDeleteObject(t *testing.T, ctx context.Context, branch string, key string) {
@@ -50,6 +74,12 @@ func gcTestCommit(t *testing.T, ctx context.Context, branch string, daysAgo int)
 
 func TestUnifiedGC(t *testing.T) {
 	ctx := context.Background()
+	blockstoreType := viper.GetString(config.BlockstoreTypeKey)
+	switch blockstoreType {
+	case block.BlockstoreTypeS3, block.BlockstoreTypeAzure, block.BlockstoreTypeGS:
+	default:
+		t.Skipf("GC isn't supported on blockstore type %s", blockstoreType)
+	}
 	prepareForUnifiedGC(t, ctx)
 	committedCreateEvents := []objectEvent{
 		{
@@ -134,9 +164,9 @@ func TestUnifiedGC(t *testing.T) {
 			commitDaysAgo: -1,
 		},
 	}
-	presignedURLs := map[string]string{}
+	objects := map[string]gcTestObject{}
 	for _, e := range committedCreateEvents {
-		presignedURLs[e.key] = gcTestCreateObject(t, ctx, e.branch, e.key)
+		objects[e.key] = gcTestCreateObject(t, ctx, e.branch, e.key)
 		gcTestCommit(t, ctx, e.branch, 14) // creations are always committed 14 days ago for this test
 	}
 	for _, e := range committedDeleteEvents {
@@ -144,7 +174,7 @@ func TestUnifiedGC(t *testing.T) {
 		gcTestCommit(t, ctx, e.branch, e.commitDaysAgo)
 	}
 	for _, e := range uncommittedCreateEvents {
-		presignedURLs[e.key] = gcTestCreateObject(t, ctx, e.branch, e.key)
+		objects[e.key] = gcTestCreateObject(t, ctx, e.branch, e.key)
 	}
 	for _, e := range uncommittedDeleteEvents {
 		gcTestDeleteObject(t, ctx, e.branch, e.key)
@@ -155,13 +185,16 @@ func TestUnifiedGC(t *testing.T) {
 	revertRes, err := client.ResetBranchWithResponse(ctx, RepoName, "dev", apigen.ResetBranchJSONRequestBody{Type: "reset"})
 	require.Falsef(t, revertRes.StatusCode() > 299, "Unexpected status code %d in revert branch dev", revertRes.StatusCode())
 	testutil.MustDo(t, "Revert changes in dev branch", err)
-	err = runSparkSubmit(&sparkSubmitConfig{
-		sparkVersion:    sparkImageTag,
-		localJar:        metaClientJarPath,
-		entryPoint:      "io.treeverse.gc.GarbageCollection",
-		programArgs:     []string{RepoName, "us-east-1"},
-		extraSubmitArgs: []string{"--conf", "spark.hadoop.lakefs.debug.gc.uncommitted_min_age_seconds=1"},
-		logSource:       fmt.Sprintf("gc-%s", RepoName),
+	engine, err := newGCEngine()
+	if errors.Is(err, errGCEngineUnavailable) {
+		t.Skip(err)
+	}
+	testutil.MustDo(t, "GC engine", err)
+	err = engine.RunGC(ctx, &gcRunParams{
+		repository:        RepoName,
+		region:            "us-east-1",
+		uncommittedMinAge: time.Second,
+		logSource:         fmt.Sprintf("gc-%s", RepoName),
 	})
 	testutil.MustDo(t, "Run GC job", err)
 	expectedExisting := map[string]bool{
@@ -177,9 +210,15 @@ func TestUnifiedGC(t *testing.T) {
 		"file_10": false,
 	}
 
+	if blockstoreType == block.BlockstoreTypeGS {
+		// GC on GCS only marks objects, verify the marked objects instead of the deleted ones
+		gcVerifyMarked(t, ctx, objects, expectedExisting)
+		return
+	}
 	for file, expected := range expectedExisting {
-		r, err := http.Get(presignedURLs[file])
+		r, err := http.Get(objects[file].presignedURL)
 		testutil.MustDo(t, "Http request to presigned url", err)
+		_ = r.Body.Close()
 		if r.StatusCode > 299 && r.StatusCode != 404 {
 			t.Fatalf("Unexpected status code in http request: %d", r.StatusCode)
 		}
@@ -192,6 +231,59 @@ func TestUnifiedGC(t *testing.T) {
 	}
 }
 
+// gcVerifyMarked verifies that the GC run on a GCS repository marked exactly the objects not expected to exist, by
+// reading the addresses it reported under the storage namespace
+func gcVerifyMarked(t *testing.T, ctx context.Context, objects map[string]gcTestObject, expectedExisting map[string]bool) {
+	t.Helper()
+	repoRes, err := client.GetRepositoryWithResponse(ctx, RepoName)
+	testutil.MustDo(t, "Get repository", err)
+	require.NotNil(t, repoRes.JSON200, "Get repository")
+	namespace, err := url.Parse(repoRes.JSON200.StorageNamespace)
+	testutil.MustDo(t, "Parse storage namespace", err)
+	namespacePrefix := strings.Trim(namespace.Path, "/")
+	if namespacePrefix != "" {
+		namespacePrefix += "/"
+	}
+
+	credentialsFile, err := gcsCredentialsFile()
+	testutil.MustDo(t, "GCS credentials", err)
+	gcsClient, err := storage.NewClient(ctx, option.WithCredentialsFile(credentialsFile))
+	testutil.MustDo(t, "GCS client", err)
+	defer func() { _ = gcsClient.Close() }()
+
+	// reports are written as text files of addresses relative to the storage namespace
+	marked := make(map[string]bool)
+	bucket := gcsClient.Bucket(namespace.Host)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: namespacePrefix + "_lakefs/retention/gc/unified/"})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		testutil.MustDo(t, "List GC reports", err)
+		if !strings.Contains(attrs.Name, "/deleted.text/part-") {
+			continue
+		}
+		r, err := bucket.Object(attrs.Name).NewReader(ctx)
+		testutil.MustDo(t, "Read GC report "+attrs.Name, err)
+		data, err := io.ReadAll(r)
+		_ = r.Close()
+		testutil.MustDo(t, "Read GC report "+attrs.Name, err)
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" {
+				marked[line] = true
+			}
+		}
+	}
+
+	for file, expected := range expectedExisting {
+		address := strings.TrimPrefix(objects[file].physicalAddress, strings.TrimSuffix(repoRes.JSON200.StorageNamespace, "/")+"/")
+		if marked[address] == expected {
+			t.Errorf("Object %s at %s: expected to exist %t, but marked for deletion %t", file, address, expected, marked[address])
+		}
+	}
+}
+
 func prepareForUnifiedGC(t *testing.T, ctx context.Context) {
 	repo := createRepositoryByName(ctx, t, RepoName)
 	createBranchRes, err := client.CreateBranchWithResponse(ctx, repo, apigen.CreateBranchJSONRequestBody{Name: "dev", Source: mainBranch})
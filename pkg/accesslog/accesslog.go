@@ -0,0 +1,275 @@
+// Package accesslog writes a structured log of the requests served by the lakeFS API and gateways, one entry per
+// request, configured separately from the application logs.
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	FormatJSON = "json"
+	// FormatCLF is the Common Log Format, followed by the request ID, service, repository, ref and latency
+	FormatCLF = "clf"
+
+	OutcomeSuccess     = "success"
+	OutcomeDenied      = "denied"
+	OutcomeClientError = "client_error"
+	OutcomeServerError = "server_error"
+
+	clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+)
+
+var ErrInvalidFormat = errors.New("invalid access log format")
+
+// Config configures the access log
+type Config struct {
+	// Format is FormatJSON or FormatCLF
+	Format string
+	// Output are the destinations of the log: "-" for the standard output, "=" for the standard error,
+	// s3://bucket/prefix for objects uploaded to S3, or a path of a file
+	Output []string
+	// FileMaxSizeMB is the size of log files, and of log objects on S3, at which they are rotated
+	FileMaxSizeMB int
+	// FilesKeep is the number of rotated log files to keep, all if 0
+	FilesKeep int
+	S3        S3Config
+}
+
+// Entry is the access log entry of a request
+type Entry struct {
+	Time          time.Time `json:"time"`
+	Service       string    `json:"service"`
+	RequestID     string    `json:"request_id"`
+	SourceIP      string    `json:"source_ip"`
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	Protocol      string    `json:"protocol"`
+	Status        int       `json:"status"`
+	Outcome       string    `json:"outcome"`
+	LatencyMS     float64   `json:"latency_ms"`
+	BytesReceived int64     `json:"bytes_received"`
+	BytesSent     int64     `json:"bytes_sent"`
+	Principal     string    `json:"principal,omitempty"`
+	Repository    string    `json:"repository,omitempty"`
+	Ref           string    `json:"ref,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+}
+
+type contextKey struct{}
+
+func entryFromContext(ctx context.Context) *Entry {
+	entry, _ := ctx.Value(contextKey{}).(*Entry)
+	return entry
+}
+
+// SetPrincipal records the user authenticated for the request handled with ctx
+func SetPrincipal(ctx context.Context, principal string) {
+	if entry := entryFromContext(ctx); entry != nil {
+		entry.Principal = principal
+	}
+}
+
+// SetResource records the repository and ref addressed by the request handled with ctx
+func SetResource(ctx context.Context, repository, ref string) {
+	if entry := entryFromContext(ctx); entry != nil {
+		entry.Repository = repository
+		entry.Ref = ref
+	}
+}
+
+// Outcome classifies the outcome of a request by its status code
+func Outcome(statusCode int) string {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return OutcomeDenied
+	case statusCode >= http.StatusInternalServerError:
+		return OutcomeServerError
+	case statusCode >= http.StatusBadRequest:
+		return OutcomeClientError
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// Logger writes access log entries
+type Logger struct {
+	format  string
+	mu      sync.Mutex
+	out     io.Writer
+	closers []io.Closer
+}
+
+// New returns a logger writing entries to the outputs of cfg. Close it to flush entries not yet uploaded to S3.
+func New(ctx context.Context, cfg Config) (*Logger, error) {
+	switch cfg.Format {
+	case FormatJSON, FormatCLF:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, cfg.Format)
+	}
+	l := &Logger{format: cfg.Format}
+	var writers []io.Writer
+	for _, output := range cfg.Output {
+		var w io.Writer
+		switch {
+		case output == "":
+			continue
+		case output == "-":
+			w = os.Stdout
+		case output == "=":
+			w = os.Stderr
+		case strings.HasPrefix(output, s3Scheme):
+			s3w, err := newS3Writer(ctx, output, cfg.S3, cfg.FileMaxSizeMB)
+			if err != nil {
+				_ = l.Close()
+				return nil, err
+			}
+			w = s3w
+			l.closers = append(l.closers, s3w)
+		default:
+			lj := &lumberjack.Logger{
+				Filename:   output,
+				MaxSize:    cfg.FileMaxSizeMB,
+				MaxBackups: cfg.FilesKeep,
+			}
+			w = lj
+			l.closers = append(l.closers, lj)
+		}
+		writers = append(writers, w)
+	}
+	l.out = io.MultiWriter(writers...)
+	return l, nil
+}
+
+// Close closes the outputs of the logger
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var errs []error
+	for _, c := range l.closers {
+		errs = append(errs, c.Close())
+	}
+	l.closers = nil
+	return errors.Join(errs...)
+}
+
+// Log writes entry to the outputs of the logger
+func (l *Logger) Log(entry *Entry) {
+	line := l.formatEntry(entry)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(line)
+}
+
+func clfField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (l *Logger) formatEntry(entry *Entry) []byte {
+	if l.format == FormatJSON {
+		line, _ := json.Marshal(entry)
+		return append(line, '\n')
+	}
+	bytesSent := "-"
+	if entry.BytesSent > 0 {
+		bytesSent = strconv.FormatInt(entry.BytesSent, 10)
+	}
+	return []byte(fmt.Sprintf("%s - %s [%s] %q %d %s %q %q %q %q %.3f\n",
+		clfField(entry.SourceIP),
+		clfField(entry.Principal),
+		entry.Time.Format(clfTimeFormat),
+		entry.Method+" "+entry.Path+" "+entry.Protocol,
+		entry.Status,
+		bytesSent,
+		clfField(entry.RequestID),
+		clfField(entry.Service),
+		clfField(entry.Repository),
+		clfField(entry.Ref),
+		entry.LatencyMS,
+	))
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+// SetDefault sets the logger of the access log middleware, nil to stop logging
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+// countingReader counts the bytes read from a request body
+type countingReader struct {
+	io.ReadCloser
+	count int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// Middleware logs the requests of service to the default logger, once it is set
+func Middleware(service string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := defaultLogger.Load()
+			if l == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			startTime := time.Now()
+			r, reqID := httputil.RequestID(r)
+			sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				sourceIP = r.RemoteAddr
+			}
+			entry := &Entry{
+				Time:      startTime,
+				Service:   service,
+				RequestID: reqID,
+				SourceIP:  sourceIP,
+				Method:    r.Method,
+				Path:      r.URL.RequestURI(),
+				Protocol:  r.Proto,
+				UserAgent: r.UserAgent(),
+			}
+			if user, err := auth.GetUser(r.Context()); err == nil {
+				entry.Principal = user.Username
+			}
+			var body *countingReader
+			if r.Body != nil && r.Body != http.NoBody {
+				body = &countingReader{ReadCloser: r.Body}
+				r.Body = body
+			}
+			writer := &httputil.ResponseRecordingWriter{Writer: w, StatusCode: http.StatusOK}
+			next.ServeHTTP(writer, r.WithContext(context.WithValue(r.Context(), contextKey{}, entry)))
+
+			entry.Status = writer.StatusCode
+			entry.Outcome = Outcome(writer.StatusCode)
+			entry.LatencyMS = float64(time.Since(startTime).Microseconds()) / float64(time.Millisecond/time.Microsecond)
+			entry.BytesSent = writer.ResponseSize
+			if body != nil {
+				entry.BytesReceived = body.count
+			}
+			l.Log(entry)
+		})
+	}
+}
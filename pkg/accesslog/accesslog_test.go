@@ -0,0 +1,83 @@
+package accesslog_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/accesslog"
+	"github.com/treeverse/lakefs/pkg/httputil"
+)
+
+// serve logs a request to handler in format, returning the log
+func serve(t *testing.T, format string, handler http.HandlerFunc, req *http.Request) string {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "access.log")
+	l, err := accesslog.New(context.Background(), accesslog.Config{Format: format, Output: []string{output}})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	accesslog.SetDefault(l)
+	defer accesslog.SetDefault(nil)
+
+	accesslog.Middleware("test_service")(handler).ServeHTTP(httptest.NewRecorder(), req)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("read log: %s", err)
+	}
+	return string(data)
+}
+
+func newRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/repositories/repo1/branches/main/objects?path=a", strings.NewReader("12345"))
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set(httputil.RequestIDHeaderName, "req-1")
+	return req
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.Copy(io.Discard, r.Body)
+	accesslog.SetPrincipal(r.Context(), "user1")
+	accesslog.SetResource(r.Context(), "repo1", "main")
+	w.WriteHeader(http.StatusForbidden)
+	_, _ = w.Write([]byte("denied"))
+}
+
+func TestMiddleware_JSON(t *testing.T) {
+	var entry accesslog.Entry
+	if err := json.Unmarshal([]byte(serve(t, accesslog.FormatJSON, handler, newRequest())), &entry); err != nil {
+		t.Fatalf("parse log entry: %s", err)
+	}
+	entry.Time = entry.Time.UTC()
+	if entry.Service != "test_service" || entry.RequestID != "req-1" || entry.SourceIP != "10.0.0.1" ||
+		entry.Method != http.MethodPut || entry.Path != "/api/v1/repositories/repo1/branches/main/objects?path=a" ||
+		entry.Status != http.StatusForbidden || entry.Outcome != accesslog.OutcomeDenied ||
+		entry.BytesReceived != 5 || entry.BytesSent != 6 ||
+		entry.Principal != "user1" || entry.Repository != "repo1" || entry.Ref != "main" || entry.LatencyMS < 0 {
+		t.Errorf("log entry %+v", entry)
+	}
+}
+
+func TestMiddleware_CLF(t *testing.T) {
+	line := serve(t, accesslog.FormatCLF, handler, newRequest())
+	clf := regexp.MustCompile(`^10\.0\.0\.1 - user1 \[[^]]+\] "PUT /api/v1/repositories/repo1/branches/main/objects\?path=a HTTP/1\.1" 403 6 "req-1" "test_service" "repo1" "main" [0-9.]+\n$`)
+	if !clf.MatchString(line) {
+		t.Errorf("log line %q", line)
+	}
+}
+
+func TestNew_InvalidFormat(t *testing.T) {
+	if _, err := accesslog.New(context.Background(), accesslog.Config{Format: "xml"}); err == nil {
+		t.Error("New with invalid format succeeded")
+	}
+}
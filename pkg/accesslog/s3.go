@@ -0,0 +1,162 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	s3Scheme = "s3://"
+
+	DefaultS3FlushInterval = time.Minute
+
+	s3UploadTimeout = time.Minute
+	bytesPerMB      = 1024 * 1024
+)
+
+var ErrInvalidS3Output = errors.New("invalid S3 access log output")
+
+// S3Config configures uploading the access log to S3
+type S3Config struct {
+	// Region of the bucket, resolved from the environment if empty
+	Region string
+	// FlushInterval is the interval of uploading the entries logged since the previous upload as an object
+	FlushInterval time.Duration
+}
+
+// s3Writer buffers log lines and uploads them as objects named by the time of their upload, every flush interval or
+// once they reach maxSize
+type s3Writer struct {
+	client  *s3.Client
+	bucket  string
+	prefix  string
+	host    string
+	maxSize int
+
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newS3Writer(ctx context.Context, output string, cfg S3Config, maxSizeMB int) (*s3Writer, error) {
+	u, err := url.Parse(output)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidS3Output, output)
+	}
+	var opts []func(*config.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Region))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("access log S3 configuration: %w", err)
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "lakefs"
+	}
+	flushInterval := cfg.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = DefaultS3FlushInterval
+	}
+	w := &s3Writer{
+		client:  s3.NewFromConfig(awsCfg),
+		bucket:  u.Host,
+		prefix:  strings.TrimPrefix(u.Path, "/"),
+		host:    host,
+		maxSize: maxSizeMB * bytesPerMB,
+		done:    make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.flushLoop(flushInterval)
+	return w, nil
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	n, _ := w.buf.Write(p)
+	var data []byte
+	if w.maxSize > 0 && w.buf.Len() >= w.maxSize {
+		data = w.take()
+	}
+	w.mu.Unlock()
+	if data != nil {
+		w.upload(data)
+	}
+	return n, nil
+}
+
+// take returns the buffered lines and empties the buffer, must be called with the lock held
+func (w *s3Writer) take() []byte {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	data := bytes.Clone(w.buf.Bytes())
+	w.buf.Reset()
+	return data
+}
+
+func (w *s3Writer) flush() {
+	w.mu.Lock()
+	data := w.take()
+	w.mu.Unlock()
+	if data != nil {
+		w.upload(data)
+	}
+}
+
+func (w *s3Writer) flushLoop(interval time.Duration) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.flush()
+		}
+	}
+}
+
+// upload writes data as a new object. Entries that fail to upload are dropped, to keep the memory of the server
+// bounded.
+func (w *s3Writer) upload(data []byte) {
+	now := time.Now().UTC()
+	key := path.Join(w.prefix, now.Format("2006/01/02"), fmt.Sprintf("%s-%s.log", now.Format("150405.000000000"), w.host))
+	ctx, cancel := context.WithTimeout(context.Background(), s3UploadTimeout)
+	defer cancel()
+	_, err := w.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		logging.ContextUnavailable().
+			WithError(err).
+			WithFields(logging.Fields{"bucket": w.bucket, "key": key, "size": len(data)}).
+			Error("Failed to upload access log")
+	}
+}
+
+// Close uploads the entries not yet uploaded
+func (w *s3Writer) Close() error {
+	close(w.done)
+	w.wg.Wait()
+	w.flush()
+	return nil
+}
@@ -73,6 +73,14 @@ func NewAirflowHook(h ActionHook, action *Action, cfg Config, endpoint *http.Ser
 	if err != nil {
 		return nil, fmt.Errorf("airflow hook url property: %w", err)
 	}
+	if err := checkEndpointAllowed(cfg, airflowHook.URL); err != nil {
+		return nil, err
+	}
+
+	airflowHook.PayloadVersion, err = negotiatePayloadVersion(h.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("airflow hook payload version: %w", err)
+	}
 
 	airflowHook.DagID, err = h.Properties.getRequiredProperty(airflowDagIDPropertyKey)
 	if err != nil {
@@ -128,7 +136,7 @@ func (a *Airflow) Run(ctx context.Context, record graveler.HookRecord, buf *byte
 		WithField("event_type", record.EventType).
 		Debug("hook action executing")
 
-	eventData, err := marshalEventInformation(a.ActionName, a.ID, record)
+	eventData, err := marshalEventInformation(ctx, a.PayloadVersion, a.ActionName, a.ID, record)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// CentralSource adds the actions of a branch of a central repository to the actions of every other repository, so
+// that repositories cannot remove them
+type CentralSource struct {
+	Source     Source
+	Repository graveler.RepositoryID
+	Branch     graveler.BranchID
+}
+
+func NewCentralSource(source Source, repository, branch string) *CentralSource {
+	return &CentralSource{
+		Source:     source,
+		Repository: graveler.RepositoryID(repository),
+		Branch:     graveler.BranchID(branch),
+	}
+}
+
+// namePrefix marks the names of the central actions, so that they load from the central repository
+func (s *CentralSource) namePrefix() string {
+	return fmt.Sprintf("lakefs://%s/%s/", s.Repository, s.Branch)
+}
+
+// centralRecord returns record reading the actions of the central branch
+func (s *CentralSource) centralRecord(record graveler.HookRecord) graveler.HookRecord {
+	record.RepositoryID = s.Repository
+	record.SourceRef = graveler.Ref(s.Branch)
+	return record
+}
+
+func (s *CentralSource) List(ctx context.Context, record graveler.HookRecord) ([]string, error) {
+	names, err := s.Source.List(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	// the central repository runs its own actions
+	if record.RepositoryID == s.Repository {
+		return names, nil
+	}
+	centralNames, err := s.Source.List(ctx, s.centralRecord(record))
+	if err != nil {
+		return nil, fmt.Errorf("central actions %s: %w", s.namePrefix(), err)
+	}
+	prefix := s.namePrefix()
+	for _, name := range centralNames {
+		names = append(names, prefix+name)
+	}
+	return names, nil
+}
+
+func (s *CentralSource) Load(ctx context.Context, record graveler.HookRecord, name string) ([]byte, error) {
+	if centralName, ok := strings.CutPrefix(name, s.namePrefix()); ok && record.RepositoryID != s.Repository {
+		return s.Source.Load(ctx, s.centralRecord(record), centralName)
+	}
+	return s.Source.Load(ctx, record, name)
+}
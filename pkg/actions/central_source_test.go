@@ -0,0 +1,50 @@
+package actions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/actions/mock"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestCentralSource(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	source := mock.NewMockSource(ctrl)
+	centralSource := actions.NewCentralSource(source, "platform", "main")
+
+	record := graveler.HookRecord{
+		RepositoryID: "repo1",
+		SourceRef:    "feature",
+		BranchID:     "main",
+	}
+	centralRecord := record
+	centralRecord.RepositoryID = "platform"
+	centralRecord.SourceRef = "main"
+
+	source.EXPECT().List(ctx, record).Return([]string{"_lakefs_actions/local.yaml"}, nil)
+	source.EXPECT().List(ctx, centralRecord).Return([]string{"_lakefs_actions/org.yaml"}, nil)
+	names, err := centralSource.List(ctx, record)
+	require.NoError(t, err)
+	require.Equal(t, []string{"_lakefs_actions/local.yaml", "lakefs://platform/main/_lakefs_actions/org.yaml"}, names)
+
+	source.EXPECT().Load(ctx, record, "_lakefs_actions/local.yaml").Return([]byte("local"), nil)
+	data, err := centralSource.Load(ctx, record, names[0])
+	require.NoError(t, err)
+	require.Equal(t, "local", string(data))
+
+	source.EXPECT().Load(ctx, centralRecord, "_lakefs_actions/org.yaml").Return([]byte("org"), nil)
+	data, err = centralSource.Load(ctx, record, names[1])
+	require.NoError(t, err)
+	require.Equal(t, "org", string(data))
+
+	// the central repository runs only its own actions
+	source.EXPECT().List(ctx, centralRecord).Return([]string{"_lakefs_actions/org.yaml"}, nil)
+	names, err = centralSource.List(ctx, centralRecord)
+	require.NoError(t, err)
+	require.Equal(t, []string{"_lakefs_actions/org.yaml"}, names)
+}
@@ -0,0 +1,337 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// ChatHook posts a message describing the event, with the commit summary and diff stats, to a Slack or Microsoft
+// Teams incoming webhook
+type ChatHook struct {
+	HookBase
+	Provider HookType
+	URL      SecureString
+	// UIURL is the base URL of the lakeFS UI, used to link the message to the commit
+	UIURL   string
+	Timeout time.Duration
+}
+
+// DiffStats counts the changes introduced by the event. Truncated is set when counting stopped after
+// chatDiffStatsLimit changes.
+type DiffStats struct {
+	Added     int
+	Removed   int
+	Changed   int
+	Conflicts int
+	Truncated bool
+}
+
+const (
+	chatDefaultTimeout = 1 * time.Minute
+	chatDiffStatsLimit = 10000
+	chatDiffPageAmount = 1000
+
+	chatURLPropertyKey     = "url"
+	chatUIURLPropertyKey   = "ui_url"
+	chatTimeoutPropertyKey = "timeout"
+)
+
+var errChatRequestFailed = errors.New("chat webhook request failed")
+
+func NewChatHook(h ActionHook, action *Action, cfg Config, endpoint *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	hook := ChatHook{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   endpoint,
+		},
+		Provider: h.Type,
+		Timeout:  chatDefaultTimeout,
+	}
+
+	rawURL, err := h.Properties.getRequiredProperty(chatURLPropertyKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s hook url property: %w", h.Type, err)
+	}
+	envGetter := NewEnvironmentVariableGetter(cfg.Env.Enabled, cfg.Env.Prefix)
+	hook.URL, err = NewSecureString(rawURL, envGetter)
+	if err != nil {
+		return nil, fmt.Errorf("%s hook url property: %w", h.Type, err)
+	}
+	if err := checkEndpointAllowed(cfg, hook.URL.val); err != nil {
+		return nil, err
+	}
+
+	if v, ok := h.Properties[chatUIURLPropertyKey].(string); ok {
+		hook.UIURL = strings.TrimSuffix(v, "/")
+	}
+
+	if v, ok := h.Properties[chatTimeoutPropertyKey].(string); ok {
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s hook timeout property: %w", h.Type, err)
+		}
+		hook.Timeout = duration
+	}
+	return &hook, nil
+}
+
+func (c *ChatHook) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	logging.FromContext(ctx).
+		WithField("hook_type", c.Provider).
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	msg := newChatMessage(record, c.UIURL)
+	diffStats, err := c.diffStats(ctx, record)
+	if err != nil {
+		// the message is still useful without the stats
+		_, _ = fmt.Fprintf(buf, "Failed to compute diff stats: %s\n", err)
+	}
+	msg.DiffStats = diffStats
+
+	var body interface{}
+	if c.Provider == HookTypeTeams {
+		body = msg.teamsPayload()
+	} else {
+		body = msg.slackPayload()
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("request serialization error: %w", err)
+	}
+	_, _ = fmt.Fprintf(buf, "Request:\n%s %s\n", http.MethodPost, c.URL.String())
+	_, _ = fmt.Fprintf(buf, "Body: %s\n\n", data)
+	req, err := http.NewRequest(http.MethodPost, c.URL.val, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	statusCode, err := doHTTPRequestWithLog(ctx, req, buf, c.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed executing %s request: %w", c.Provider, err)
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return fmt.Errorf("%s webhook status code (%d): %w", c.Provider, statusCode, errChatRequestFailed)
+	}
+	return nil
+}
+
+// diffRefs returns the refs to diff in order to describe the changes of the event, or "" if the event has no changes
+func diffRefs(record graveler.HookRecord) (string, string) {
+	switch record.EventType {
+	case graveler.EventTypePreMerge:
+		return record.BranchID.String(), record.SourceRef.String()
+	case graveler.EventTypePostCommit, graveler.EventTypePostMerge:
+		if len(record.Commit.Parents) == 0 {
+			return "", ""
+		}
+		return record.Commit.Parents[0].String(), record.CommitID.String()
+	default:
+		return "", ""
+	}
+}
+
+type chatDiffList struct {
+	Pagination struct {
+		HasMore    bool   `json:"has_more"`
+		NextOffset string `json:"next_offset"`
+	} `json:"pagination"`
+	Results []struct {
+		Type string `json:"type"`
+	} `json:"results"`
+}
+
+// diffStats counts the changes of the event using the lakeFS API on behalf of the user that triggered it.
+// Returns nil if the event has no changes or the API is not available to the hook.
+func (c *ChatHook) diffStats(ctx context.Context, record graveler.HookRecord) (*DiffStats, error) {
+	left, right := diffRefs(record)
+	if left == "" || c.Endpoint == nil {
+		return nil, nil
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reqURL, err := url.JoinPath(apiutil.BaseURL, "repositories", record.RepositoryID.String(), "refs", left, "diff", right)
+	if err != nil {
+		return nil, err
+	}
+	// Chi stores its routing information on the request context which breaks this sub-request's routing
+	reqCtx := context.WithValue(ctx, chi.RouteCtxKey, nil)
+	reqCtx = auth.WithUser(reqCtx, user)
+
+	diffStats := &DiffStats{}
+	after := ""
+	for {
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		q := req.URL.Query()
+		q.Set("amount", strconv.Itoa(chatDiffPageAmount))
+		q.Set("after", after)
+		req.URL.RawQuery = q.Encode()
+		rr := httptest.NewRecorder()
+		c.Endpoint.Handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			return nil, fmt.Errorf("diff %s...%s: HTTP %d: %w", left, right, rr.Code, errChatRequestFailed)
+		}
+		var page chatDiffList
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			return nil, err
+		}
+		for _, result := range page.Results {
+			switch result.Type {
+			case "added":
+				diffStats.Added++
+			case "removed":
+				diffStats.Removed++
+			case "changed":
+				diffStats.Changed++
+			case "conflict":
+				diffStats.Conflicts++
+			}
+		}
+		if !page.Pagination.HasMore {
+			return diffStats, nil
+		}
+		if diffStats.Added+diffStats.Removed+diffStats.Changed+diffStats.Conflicts >= chatDiffStatsLimit {
+			diffStats.Truncated = true
+			return diffStats, nil
+		}
+		after = page.Pagination.NextOffset
+	}
+}
+
+func (d *DiffStats) String() string {
+	plus := ""
+	if d.Truncated {
+		plus = "+"
+	}
+	s := fmt.Sprintf("%d%s added, %d%s changed, %d%s removed", d.Added, plus, d.Changed, plus, d.Removed, plus)
+	if d.Conflicts > 0 {
+		s += fmt.Sprintf(", %d%s conflicts", d.Conflicts, plus)
+	}
+	return s
+}
+
+type chatFact struct {
+	Name  string
+	Value string
+}
+
+// chatMessage is the provider independent content of the message posted for an event
+type chatMessage struct {
+	Title     string
+	Summary   string
+	Link      string
+	Facts     []chatFact
+	DiffStats *DiffStats
+}
+
+func newChatMessage(record graveler.HookRecord, uiURL string) *chatMessage {
+	msg := &chatMessage{
+		Title: fmt.Sprintf("lakeFS %s on %s", record.EventType, record.RepositoryID),
+	}
+	msg.Facts = append(msg.Facts, chatFact{Name: "Repository", Value: record.RepositoryID.String()})
+	if record.BranchID != "" {
+		msg.Facts = append(msg.Facts, chatFact{Name: "Branch", Value: record.BranchID.String()})
+	}
+	if record.TagID != "" {
+		msg.Facts = append(msg.Facts, chatFact{Name: "Tag", Value: record.TagID.String()})
+	}
+	if source := mergeSource(record); source != "" {
+		msg.Facts = append(msg.Facts, chatFact{Name: "Merged", Value: source.String()})
+	} else if record.EventType == graveler.EventTypePreMerge {
+		msg.Facts = append(msg.Facts, chatFact{Name: "Merging", Value: record.SourceRef.String()})
+	}
+	if record.CommitID != "" {
+		msg.Facts = append(msg.Facts, chatFact{Name: "Commit", Value: record.CommitID.String()})
+		if uiURL != "" {
+			msg.Link = fmt.Sprintf("%s/repositories/%s/commits/%s", uiURL, url.PathEscape(record.RepositoryID.String()), url.PathEscape(record.CommitID.String()))
+		}
+	}
+	if record.Commit.Committer != "" {
+		msg.Facts = append(msg.Facts, chatFact{Name: "Committer", Value: record.Commit.Committer})
+	}
+	msg.Summary = record.Commit.Message
+	return msg
+}
+
+func (m *chatMessage) facts() []chatFact {
+	if m.DiffStats == nil {
+		return m.Facts
+	}
+	return append(m.Facts, chatFact{Name: "Changes", Value: m.DiffStats.String()})
+}
+
+func (m *chatMessage) slackPayload() map[string]interface{} {
+	fields := make([]map[string]interface{}, 0, len(m.Facts)+1)
+	for _, f := range m.facts() {
+		fields = append(fields, map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", f.Name, f.Value)})
+	}
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": m.Title}},
+	}
+	if m.Summary != "" {
+		blocks = append(blocks, map[string]interface{}{"type": "section", "text": map[string]interface{}{"type": "plain_text", "text": m.Summary}})
+	}
+	blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	if m.Link != "" {
+		blocks = append(blocks, map[string]interface{}{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("<%s|View commit>", m.Link)}})
+	}
+	return map[string]interface{}{
+		// text is the fallback shown in notifications
+		"text":   m.Title,
+		"blocks": blocks,
+	}
+}
+
+func (m *chatMessage) teamsPayload() map[string]interface{} {
+	facts := make([]map[string]interface{}, 0, len(m.Facts)+1)
+	for _, f := range m.facts() {
+		facts = append(facts, map[string]interface{}{"title": f.Name, "value": f.Value})
+	}
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": m.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+	if m.Summary != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": m.Summary, "wrap": true})
+	}
+	body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if m.Link != "" {
+		card["actions"] = []map[string]interface{}{{"type": "Action.OpenUrl", "title": "View commit", "url": m.Link}}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
@@ -0,0 +1,165 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func newChatServer(t *testing.T) (*httptest.Server, func() []map[string]interface{}) {
+	t.Helper()
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []map[string]interface{} { return bodies }
+}
+
+// newDiffEndpoint serves the lakeFS diff API with two pages of changes
+func newDiffEndpoint(t *testing.T) *http.Server {
+	t.Helper()
+	return &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/repositories/repo/refs/c1/diff/c2", r.URL.Path)
+		_, err := auth.GetUser(r.Context())
+		require.NoError(t, err)
+		var page interface{}
+		if r.URL.Query().Get("after") == "" {
+			page = map[string]interface{}{
+				"pagination": map[string]interface{}{"has_more": true, "next_offset": "b"},
+				"results":    []map[string]string{{"type": "added", "path": "a"}, {"type": "added", "path": "b"}},
+			}
+		} else {
+			page = map[string]interface{}{
+				"pagination": map[string]interface{}{"has_more": false},
+				"results":    []map[string]string{{"type": "changed", "path": "c"}, {"type": "removed", "path": "d"}},
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	})}
+}
+
+func TestChatHook(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), &model.User{Username: "alice"})
+	record := graveler.HookRecord{
+		RunID:        "run",
+		EventType:    graveler.EventTypePostMerge,
+		RepositoryID: "repo",
+		BranchID:     "main",
+		CommitID:     "c2",
+		Commit: graveler.Commit{
+			Committer: "alice",
+			Message:   "merge feature",
+			Parents:   graveler.CommitParents{"c1", "s1"},
+		},
+	}
+
+	t.Run("slack", func(t *testing.T) {
+		server, bodies := newChatServer(t)
+		action, err := actions.ParseAction([]byte(`name: notify
+on:
+  post-merge: {}
+hooks:
+  - id: slack
+    type: slack
+    properties:
+      url: "` + server.URL + `/services/T/B/X"
+      ui_url: "https://lakefs.example.com/"
+`))
+		require.NoError(t, err)
+		h, err := actions.NewHook(action.Hooks[0], action, actions.Config{Enabled: true}, newDiffEndpoint(t), "", nil)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, h.Run(ctx, record, &buf))
+		got := bodies()
+		require.Len(t, got, 1)
+		require.Equal(t, "lakeFS post-merge on repo", got[0]["text"])
+		blocks, err := json.Marshal(got[0]["blocks"])
+		require.NoError(t, err)
+		require.Contains(t, string(blocks), "merge feature")
+		require.Contains(t, string(blocks), "2 added, 1 changed, 1 removed")
+		require.Contains(t, string(blocks), "https://lakefs.example.com/repositories/repo/commits/c2")
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		server, bodies := newChatServer(t)
+		action, err := actions.ParseAction([]byte(`name: notify
+on:
+  post-merge: {}
+hooks:
+  - id: teams
+    type: teams
+    properties:
+      url: "` + server.URL + `/webhook"
+`))
+		require.NoError(t, err)
+		// without an endpoint the message is posted without diff stats
+		h, err := actions.NewHook(action.Hooks[0], action, actions.Config{Enabled: true}, nil, "", nil)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, h.Run(ctx, record, &buf))
+		got := bodies()
+		require.Len(t, got, 1)
+		require.Equal(t, "message", got[0]["type"])
+		attachments := got[0]["attachments"].([]interface{})
+		require.Len(t, attachments, 1)
+		card, err := json.Marshal(attachments[0])
+		require.NoError(t, err)
+		require.Contains(t, string(card), "AdaptiveCard")
+		require.Contains(t, string(card), "s1")
+		require.NotContains(t, string(card), "Changes")
+	})
+}
+
+func TestChatHook_InvalidProperties(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Properties string
+		Allowed    []string
+	}{
+		{Name: "missing url", Properties: "timeout: 1s"},
+		{Name: "bad timeout", Properties: "url: https://hooks.slack.com/x\n      timeout: soon"},
+		{Name: "endpoint not allowed", Properties: "url: https://hooks.slack.com/x", Allowed: []string{"https://example.com/"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			action, err := actions.ParseAction([]byte(`name: notify
+on:
+  post-commit: {}
+hooks:
+  - id: slack
+    type: slack
+    properties:
+      ` + tc.Properties + `
+`))
+			require.NoError(t, err)
+			cfg := actions.Config{Enabled: true}
+			cfg.Webhook.AllowedEndpoints = tc.Allowed
+			_, err = actions.NewHook(action.Hooks[0], action, cfg, nil, "", nil)
+			require.Error(t, err)
+		})
+	}
+}
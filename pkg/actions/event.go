@@ -1,12 +1,88 @@
 package actions
 
 import (
+	"context"
+	"embed"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/httputil"
 )
 
+const (
+	// PayloadVersionHeader is the header of hook requests holding the version of the event payload they post
+	PayloadVersionHeader = "X-Lakefs-Payload-Version"
+
+	PayloadVersion1 = "1"
+	PayloadVersion2 = "2"
+	// DefaultPayloadVersion is the payload version of hooks which do not choose one
+	DefaultPayloadVersion = PayloadVersion1
+	LatestPayloadVersion  = PayloadVersion2
+
+	payloadVersionPropertyKey = "payload_version"
+)
+
+var ErrUnsupportedPayloadVersion = errors.New("unsupported payload version")
+
+// payloadVersions are the supported event payload versions, oldest first
+var payloadVersions = []string{PayloadVersion1, PayloadVersion2}
+
+//go:embed schemas/*.json
+var payloadSchemas embed.FS
+
+type PayloadSchema struct {
+	Version string
+	// Schema is the JSON Schema of the payload
+	Schema []byte
+}
+
+// PayloadSchemas returns the JSON Schemas of the supported event payload versions, oldest first
+func PayloadSchemas() ([]PayloadSchema, error) {
+	schemas := make([]PayloadSchema, 0, len(payloadVersions))
+	for _, version := range payloadVersions {
+		schema, err := payloadSchemas.ReadFile(fmt.Sprintf("schemas/event_v%s.json", version))
+		if err != nil {
+			return nil, fmt.Errorf("payload version %s schema: %w", version, err)
+		}
+		schemas = append(schemas, PayloadSchema{Version: version, Schema: schema})
+	}
+	return schemas, nil
+}
+
+// negotiatePayloadVersion returns the latest payload version supported out of the versions a hook accepts, set in
+// its payload_version property as a version or a list of versions. Hooks which do not set it get
+// DefaultPayloadVersion, so upgrading lakeFS does not change their payload.
+func negotiatePayloadVersion(props Properties) (string, error) {
+	raw, ok := props[payloadVersionPropertyKey]
+	if !ok {
+		return DefaultPayloadVersion, nil
+	}
+	var accepted []string
+	switch v := raw.(type) {
+	case string, int:
+		accepted = []string{fmt.Sprint(v)}
+	case []interface{}:
+		for _, version := range v {
+			accepted = append(accepted, fmt.Sprint(version))
+		}
+	default:
+		return "", fmt.Errorf("%s: %w", payloadVersionPropertyKey, errWrongValueType)
+	}
+	for i := len(payloadVersions) - 1; i >= 0; i-- {
+		for _, version := range accepted {
+			if version == payloadVersions[i] {
+				return version, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%v: %w", accepted, ErrUnsupportedPayloadVersion)
+}
+
+// EventInfo is the payload of version 1
 type EventInfo struct {
 	EventType      string            `json:"event_type"`
 	EventTime      string            `json:"event_time"`
@@ -20,23 +96,97 @@ type EventInfo struct {
 	CommitMessage  string            `json:"commit_message,omitempty"`
 	Committer      string            `json:"committer,omitempty"`
 	CommitMetadata map[string]string `json:"commit_metadata,omitempty"`
+	RequestID      string            `json:"request_id,omitempty"`
+	TraceID        string            `json:"trace_id,omitempty"`
+}
+
+// requestTrace returns the ID and the W3C trace ID of the request running hooks with ctx, "" if unknown
+func requestTrace(ctx context.Context) (string, string) {
+	traceContext, _ := httputil.TraceContextFromContext(ctx)
+	return httputil.RequestIDFromContext(ctx), traceContext.TraceID
+}
+
+// setTraceHeaders propagates the ID and trace context of the request running hooks with ctx to a request sent by a
+// hook
+func setTraceHeaders(ctx context.Context, header http.Header) {
+	httputil.SetTraceHeaders(ctx, header)
+}
+
+// EventInfoV2 is the payload of version 2. It groups the details of the commit, and adds the payload version, the run
+// ID and the merged reference.
+type EventInfoV2 struct {
+	PayloadVersion string       `json:"payload_version"`
+	EventType      string       `json:"event_type"`
+	EventTime      string       `json:"event_time"`
+	ActionName     string       `json:"action_name"`
+	HookID         string       `json:"hook_id"`
+	RunID          string       `json:"run_id"`
+	RepositoryID   string       `json:"repository_id"`
+	BranchID       string       `json:"branch_id,omitempty"`
+	SourceRef      string       `json:"source_ref,omitempty"`
+	MergeSource    string       `json:"merge_source,omitempty"`
+	TagID          string       `json:"tag_id,omitempty"`
+	Commit         *EventCommit `json:"commit,omitempty"`
+	RequestID      string       `json:"request_id,omitempty"`
+	TraceID        string       `json:"trace_id,omitempty"`
+}
+
+type EventCommit struct {
+	// ID is empty for the commit of pre-commit and pre-merge events, which is not created yet
+	ID        string            `json:"id,omitempty"`
+	Message   string            `json:"message"`
+	Committer string            `json:"committer"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
 }
 
-func marshalEventInformation(actionName, hookID string, record graveler.HookRecord) ([]byte, error) {
+func marshalEventInformation(ctx context.Context, payloadVersion, actionName, hookID string, record graveler.HookRecord) ([]byte, error) {
 	now := time.Now()
-	info := EventInfo{
-		EventType:      string(record.EventType),
-		EventTime:      now.UTC().Format(time.RFC3339),
-		ActionName:     actionName,
-		HookID:         hookID,
-		RepositoryID:   record.RepositoryID.String(),
-		BranchID:       record.BranchID.String(),
-		SourceRef:      record.SourceRef.String(),
-		TagID:          record.TagID.String(),
-		CommitID:       record.CommitID.String(),
-		CommitMessage:  record.Commit.Message,
-		Committer:      record.Commit.Committer,
-		CommitMetadata: record.Commit.Metadata,
+	requestID, traceID := requestTrace(ctx)
+	switch payloadVersion {
+	case PayloadVersion1:
+		info := EventInfo{
+			EventType:      string(record.EventType),
+			EventTime:      now.UTC().Format(time.RFC3339),
+			ActionName:     actionName,
+			HookID:         hookID,
+			RepositoryID:   record.RepositoryID.String(),
+			BranchID:       record.BranchID.String(),
+			SourceRef:      record.SourceRef.String(),
+			TagID:          record.TagID.String(),
+			CommitID:       record.CommitID.String(),
+			CommitMessage:  record.Commit.Message,
+			Committer:      record.Commit.Committer,
+			CommitMetadata: record.Commit.Metadata,
+			RequestID:      requestID,
+			TraceID:        traceID,
+		}
+		return json.Marshal(info)
+	case PayloadVersion2:
+		info := EventInfoV2{
+			PayloadVersion: PayloadVersion2,
+			EventType:      string(record.EventType),
+			EventTime:      now.UTC().Format(time.RFC3339),
+			ActionName:     actionName,
+			HookID:         hookID,
+			RunID:          record.RunID,
+			RepositoryID:   record.RepositoryID.String(),
+			BranchID:       record.BranchID.String(),
+			SourceRef:      record.SourceRef.String(),
+			MergeSource:    record.MergeSource.String(),
+			TagID:          record.TagID.String(),
+			RequestID:      requestID,
+			TraceID:        traceID,
+		}
+		if record.CommitID != "" || record.Commit.Committer != "" {
+			info.Commit = &EventCommit{
+				ID:        record.CommitID.String(),
+				Message:   record.Commit.Message,
+				Committer: record.Commit.Committer,
+				Metadata:  record.Commit.Metadata,
+			}
+		}
+		return json.Marshal(info)
+	default:
+		return nil, fmt.Errorf("%s: %w", payloadVersion, ErrUnsupportedPayloadVersion)
 	}
-	return json.Marshal(info)
 }
@@ -0,0 +1,82 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestNegotiatePayloadVersion(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Accepted interface{}
+		Expected string
+		Err      error
+	}{
+		{Name: "default", Expected: DefaultPayloadVersion},
+		{Name: "version", Accepted: "2", Expected: PayloadVersion2},
+		{Name: "yaml_number", Accepted: 1, Expected: PayloadVersion1},
+		{Name: "latest_of_list", Accepted: []interface{}{"1", 2, "3"}, Expected: PayloadVersion2},
+		{Name: "unsupported", Accepted: []interface{}{"3"}, Err: ErrUnsupportedPayloadVersion},
+		{Name: "wrong_type", Accepted: map[string]interface{}{}, Err: errWrongValueType},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			props := Properties{}
+			if tt.Accepted != nil {
+				props[payloadVersionPropertyKey] = tt.Accepted
+			}
+			version, err := negotiatePayloadVersion(props)
+			if !errors.Is(err, tt.Err) {
+				t.Fatalf("negotiatePayloadVersion() err=%v, expected %v", err, tt.Err)
+			}
+			require.Equal(t, tt.Expected, version)
+		})
+	}
+}
+
+func TestMarshalEventInformationV2(t *testing.T) {
+	record := graveler.HookRecord{
+		RunID:        "run1",
+		EventType:    graveler.EventTypePreMerge,
+		RepositoryID: "repo1",
+		BranchID:     "main",
+		SourceRef:    "feature",
+		MergeSource:  "feature",
+		Commit: graveler.Commit{
+			Message:   "merge feature",
+			Committer: "user1",
+			Metadata:  graveler.Metadata{"key": "value"},
+		},
+	}
+	data, err := marshalEventInformation(context.Background(), PayloadVersion2, "action1", "hook1", record)
+	require.NoError(t, err)
+
+	var info EventInfoV2
+	require.NoError(t, json.Unmarshal(data, &info))
+	require.Equal(t, PayloadVersion2, info.PayloadVersion)
+	require.Equal(t, "run1", info.RunID)
+	require.Equal(t, "feature", info.MergeSource)
+	require.Equal(t, &EventCommit{
+		Message:   "merge feature",
+		Committer: "user1",
+		Metadata:  map[string]string{"key": "value"},
+	}, info.Commit)
+
+	_, err = marshalEventInformation(context.Background(), "3", "action1", "hook1", record)
+	require.ErrorIs(t, err, ErrUnsupportedPayloadVersion)
+}
+
+func TestPayloadSchemas(t *testing.T) {
+	schemas, err := PayloadSchemas()
+	require.NoError(t, err)
+	require.Len(t, schemas, len(payloadVersions))
+	for _, schema := range schemas {
+		var parsed map[string]interface{}
+		require.NoError(t, json.Unmarshal(schema.Schema, &parsed), "schema of payload version %s", schema.Version)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/stats"
@@ -14,9 +15,14 @@ import (
 type HookType string
 
 const (
-	HookTypeWebhook HookType = "webhook"
-	HookTypeAirflow HookType = "airflow"
-	HookTypeLua     HookType = "lua"
+	HookTypeWebhook      HookType = "webhook"
+	HookTypeAirflow      HookType = "airflow"
+	HookTypeLua          HookType = "lua"
+	HookTypeDataHub      HookType = "datahub"
+	HookTypeOpenMetadata HookType = "openmetadata"
+	HookTypeSlack        HookType = "slack"
+	HookTypeTeams        HookType = "teams"
+	HookTypeMaterialize  HookType = "materialize"
 )
 
 // Hook is the abstraction of the basic user-configured runnable building-stone
@@ -31,15 +37,25 @@ type HookBase struct {
 	ActionName string
 	Config     Config
 	Endpoint   *http.Server
+	// PayloadVersion is the version of the event payload posted by the hook
+	PayloadVersion string
 }
 
 var hooks = map[HookType]NewHookFunc{
-	HookTypeWebhook: NewWebhook,
-	HookTypeAirflow: NewAirflowHook,
-	HookTypeLua:     NewLuaHook,
+	HookTypeWebhook:      NewWebhook,
+	HookTypeAirflow:      NewAirflowHook,
+	HookTypeLua:          NewLuaHook,
+	HookTypeDataHub:      NewMetadataCatalogHook,
+	HookTypeOpenMetadata: NewMetadataCatalogHook,
+	HookTypeSlack:        NewChatHook,
+	HookTypeTeams:        NewChatHook,
+	HookTypeMaterialize:  NewMaterializeHook,
 }
 
-var ErrUnknownHookType = errors.New("unknown hook type")
+var (
+	ErrUnknownHookType        = errors.New("unknown hook type")
+	ErrHookEndpointNotAllowed = errors.New("hook endpoint not allowed")
+)
 
 func NewHook(hook ActionHook, action *Action, cfg Config, server *http.Server, serverAddress string, collector stats.Collector) (Hook, error) {
 	f := hooks[hook.Type]
@@ -48,3 +64,16 @@ func NewHook(hook ActionHook, action *Action, cfg Config, server *http.Server, s
 	}
 	return f(hook, action, cfg, server, serverAddress, collector)
 }
+
+// checkEndpointAllowed verifies the hook may call endpoint, when the configuration restricts hook endpoints
+func checkEndpointAllowed(cfg Config, endpoint string) error {
+	if len(cfg.Webhook.AllowedEndpoints) == 0 {
+		return nil
+	}
+	for _, prefix := range cfg.Webhook.AllowedEndpoints {
+		if strings.HasPrefix(endpoint, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w", endpoint, ErrHookEndpointNotAllowed)
+}
@@ -33,9 +33,11 @@ type LuaHook struct {
 	Args          map[string]interface{}
 	collector     stats.Collector
 	serverAddress string
+	// CatalogAccess serves the lakefs/catalog library, which is not available when it is not set
+	CatalogAccess *lakefs.CatalogAccess
 }
 
-func applyRecord(l *lua.State, actionName, hookID string, record graveler.HookRecord) {
+func applyRecord(ctx context.Context, l *lua.State, actionName, hookID string, record graveler.HookRecord) {
 	parents := make([]string, len(record.Commit.Parents))
 	for i := 0; i < len(record.Commit.Parents); i++ {
 		parents[i] = string(record.Commit.Parents[i])
@@ -44,6 +46,7 @@ func applyRecord(l *lua.State, actionName, hookID string, record graveler.HookRe
 	for k, v := range record.Commit.Metadata {
 		metadata[k] = v
 	}
+	requestID, traceID := requestTrace(ctx)
 	luautil.DeepPush(l, map[string]interface{}{
 		"action_name":       actionName,
 		"hook_id":           hookID,
@@ -56,6 +59,8 @@ func applyRecord(l *lua.State, actionName, hookID string, record graveler.HookRe
 		"tag_id":            record.TagID.String(),
 		"repository_id":     record.RepositoryID.String(),
 		"storage_namespace": record.StorageNamespace.String(),
+		"request_id":        requestID,
+		"trace_id":          traceID,
 		"commit": map[string]interface{}{
 			"message":       record.Commit.Message,
 			"meta_range_id": record.Commit.MetaRangeID.String(),
@@ -68,12 +73,15 @@ func applyRecord(l *lua.State, actionName, hookID string, record graveler.HookRe
 	l.SetGlobal("action")
 }
 
-func injectHookContext(l *lua.State, ctx context.Context, user *model.User, endpoint *http.Server, args map[string]interface{}) {
+func injectHookContext(l *lua.State, ctx context.Context, user *model.User, endpoint *http.Server, catalogAccess *lakefs.CatalogAccess, args map[string]interface{}) {
 	l.PushString(user.Username)
 	l.SetGlobal("username")
 	luautil.DeepPush(l, args)
 	l.SetGlobal("args")
 	lakefs.OpenClient(l, ctx, user, endpoint)
+	if catalogAccess != nil {
+		lakefs.OpenCatalog(l, ctx, user, catalogAccess)
+	}
 }
 
 type loggingBuffer struct {
@@ -114,8 +122,8 @@ func (h *LuaHook) Run(ctx context.Context, record graveler.HookRecord, buf *byte
 		LakeFSAddr:     h.serverAddress,
 	}
 	lualibs.OpenSafe(l, ctx, osc, &loggingBuffer{buf: buf, ctx: ctx})
-	injectHookContext(l, ctx, user, h.Endpoint, h.Args)
-	applyRecord(l, h.ActionName, h.ID, record)
+	injectHookContext(l, ctx, user, h.Endpoint, h.CatalogAccess, h.Args)
+	applyRecord(ctx, l, h.ActionName, h.ID, record)
 
 	// determine if this is an object to load
 	code := h.Script
@@ -0,0 +1,320 @@
+package lakefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Shopify/go-lua"
+	"github.com/treeverse/lakefs/pkg/actions/lua/util"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	// DefaultMaxObjectSize is the size of the largest object get_object reads when CatalogAccess sets no limit
+	DefaultMaxObjectSize = 5 * 1024 * 1024
+
+	catalogDefaultAmount = 100
+	catalogMaxAmount     = 1000
+)
+
+var ErrObjectTooLarge = errors.New("object too large")
+
+// Catalog is the part of the lakeFS catalog read by the lakefs/catalog library
+type Catalog interface {
+	GetRepository(ctx context.Context, repository string) (*catalog.Repository, error)
+	GetEntry(ctx context.Context, repositoryID string, reference string, path string, params catalog.GetEntryParams) (*catalog.DBEntry, error)
+	ListEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int) ([]*catalog.DBEntry, bool, error)
+	Compare(ctx context.Context, repositoryID, leftReference string, rightReference string, params catalog.DiffParams) (catalog.Differences, bool, error)
+}
+
+// CatalogAccess serves the lakefs/catalog library directly from the catalog, without passing each call through the
+// lakeFS API. Every call is authorized as the user running the hook, the same way the API would.
+type CatalogAccess struct {
+	Catalog      Catalog
+	Authorizer   auth.Authorizer
+	BlockAdapter block.Adapter
+	// MaxObjectSize is the size of the largest object get_object reads, DefaultMaxObjectSize when not set
+	MaxObjectSize int64
+}
+
+func (a *CatalogAccess) authorize(ctx context.Context, user *model.User, action, resource string) error {
+	resp, err := a.Authorizer.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: user.Username,
+		RequiredPermissions: permissions.WithActionsConfig(permissions.Node{
+			Permission: permissions.Permission{
+				Action:   action,
+				Resource: resource,
+			},
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if !resp.Allowed {
+		return fmt.Errorf("%s on %s: %w", action, resource, auth.ErrInsufficientPermissions)
+	}
+	return nil
+}
+
+// getEntry returns the entry of path after checking the user may read it, nil when it does not exist
+func (a *CatalogAccess) getEntry(ctx context.Context, user *model.User, repository, ref, path string) (*catalog.DBEntry, error) {
+	if err := a.authorize(ctx, user, permissions.ReadObjectAction, permissions.ObjectArn(repository, path)); err != nil {
+		return nil, err
+	}
+	entry, err := a.Catalog.GetEntry(ctx, repository, ref, path, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// reading through an alias requires permission to read its target
+	if entry.AliasTarget != "" {
+		if err := a.authorize(ctx, user, permissions.ReadObjectAction, permissions.ObjectArn(repository, entry.AliasTarget)); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+func (a *CatalogAccess) objectStats(repo *catalog.Repository, entry *catalog.DBEntry, withUserMetadata bool) (map[string]interface{}, error) {
+	if entry.CommonLevel {
+		return map[string]interface{}{
+			"path":      entry.Path,
+			"path_type": "common_prefix",
+		}, nil
+	}
+	qk, err := a.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		return nil, err
+	}
+	var mtime int64
+	if !entry.CreationDate.IsZero() {
+		mtime = entry.CreationDate.Unix()
+	}
+	stats := map[string]interface{}{
+		"path":             entry.Path,
+		"path_type":        "object",
+		"physical_address": qk.Format(),
+		"checksum":         entry.Checksum,
+		"size_bytes":       entry.Size,
+		"mtime":            mtime,
+		"content_type":     entry.ContentType,
+	}
+	if withUserMetadata && entry.Metadata != nil {
+		stats["metadata"] = map[string]string(entry.Metadata)
+	}
+	return stats, nil
+}
+
+func (a *CatalogAccess) listObjects(ctx context.Context, user *model.User, repository, ref, after, prefix, delimiter string, amount int, withUserMetadata bool) (map[string]interface{}, error) {
+	if err := a.authorize(ctx, user, permissions.ListObjectsAction, permissions.RepoArn(repository)); err != nil {
+		return nil, err
+	}
+	repo, err := a.Catalog.GetRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	entries, hasMore, err := a.Catalog.ListEntries(ctx, repository, ref, prefix, after, delimiter, amount)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		stats, err := a.objectStats(repo, entry, withUserMetadata)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, stats)
+	}
+	var nextOffset string
+	if hasMore && len(entries) > 0 {
+		nextOffset = entries[len(entries)-1].Path
+	}
+	return map[string]interface{}{
+		"pagination": catalogPagination(hasMore, nextOffset, len(results)),
+		"results":    results,
+	}, nil
+}
+
+func (a *CatalogAccess) statObject(ctx context.Context, user *model.User, repository, ref, path string) (map[string]interface{}, error) {
+	entry, err := a.getEntry(ctx, user, repository, ref, path)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	repo, err := a.Catalog.GetRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	return a.objectStats(repo, entry, true)
+}
+
+// getObject returns the content of the object at path, nil when it does not exist
+func (a *CatalogAccess) getObject(ctx context.Context, user *model.User, repository, ref, path string) ([]byte, error) {
+	entry, err := a.getEntry(ctx, user, repository, ref, path)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if entry.Expired {
+		return nil, fmt.Errorf("%s: %w", path, catalog.ErrExpired)
+	}
+	maxSize := a.MaxObjectSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxObjectSize
+	}
+	if entry.Size > maxSize {
+		return nil, fmt.Errorf("%s is %d bytes, get_object reads up to %d bytes: %w", path, entry.Size, maxSize, ErrObjectTooLarge)
+	}
+	repo, err := a.Catalog.GetRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := a.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(io.LimitReader(reader, maxSize))
+}
+
+func (a *CatalogAccess) diffRefs(ctx context.Context, user *model.User, repository, leftRef, rightRef, after, prefix, delimiter string, amount int) (map[string]interface{}, error) {
+	if err := a.authorize(ctx, user, permissions.ListObjectsAction, permissions.RepoArn(repository)); err != nil {
+		return nil, err
+	}
+	diff, hasMore, err := a.Catalog.Compare(ctx, repository, leftRef, rightRef, catalog.DiffParams{
+		Limit:     amount,
+		After:     after,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0, len(diff))
+	for _, d := range diff {
+		result := map[string]interface{}{
+			"path":      d.Path,
+			"path_type": "object",
+			"type":      differenceTypeString(d.Type),
+		}
+		if d.CommonLevel {
+			result["path_type"] = "common_prefix"
+		} else {
+			result["size_bytes"] = d.Size
+		}
+		results = append(results, result)
+	}
+	var nextOffset string
+	if hasMore && len(diff) > 0 {
+		nextOffset = diff[len(diff)-1].Path
+	}
+	return map[string]interface{}{
+		"pagination": catalogPagination(hasMore, nextOffset, len(results)),
+		"results":    results,
+	}, nil
+}
+
+func catalogPagination(hasMore bool, nextOffset string, results int) map[string]interface{} {
+	return map[string]interface{}{
+		"has_more":     hasMore,
+		"next_offset":  nextOffset,
+		"results":      results,
+		"max_per_page": catalogMaxAmount,
+	}
+}
+
+func differenceTypeString(d catalog.DifferenceType) string {
+	switch d {
+	case catalog.DifferenceTypeAdded:
+		return "added"
+	case catalog.DifferenceTypeRemoved:
+		return "removed"
+	case catalog.DifferenceTypeChanged:
+		return "changed"
+	case catalog.DifferenceTypeConflict:
+		return "conflict"
+	case catalog.DifferenceTypePrefixChanged:
+		return "prefix_changed"
+	default:
+		return ""
+	}
+}
+
+// optAmount returns the amount argument at index, bounded to the amounts the lakeFS API accepts
+func optAmount(l *lua.State, index int) int {
+	amount := lua.OptInteger(l, index, catalogDefaultAmount)
+	if amount > catalogMaxAmount {
+		return catalogMaxAmount
+	}
+	if amount <= 0 {
+		return catalogDefaultAmount
+	}
+	return amount
+}
+
+// OpenCatalog opens the lakefs/catalog library, reading the catalog in-process as user. It offers the read calls of
+// the lakefs library without their HTTP round trip: results are returned directly and failures raise errors.
+func OpenCatalog(l *lua.State, ctx context.Context, user *model.User, access *CatalogAccess) {
+	catalogOpen := func(l *lua.State) int {
+		lua.NewLibrary(l, []lua.RegistryFunction{
+			{Name: "list_objects", Function: func(l *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				ref := lua.CheckString(l, 2)
+				withUserMetadata := l.IsNoneOrNil(7) || l.ToBoolean(7)
+				result, err := access.listObjects(ctx, user, repo, ref, lua.OptString(l, 3, ""), lua.OptString(l, 4, ""), lua.OptString(l, 5, ""), optAmount(l, 6), withUserMetadata)
+				check(l, err)
+				return util.DeepPush(l, result)
+			}},
+			{Name: "stat_object", Function: func(l *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				ref := lua.CheckString(l, 2)
+				path := lua.CheckString(l, 3)
+				result, err := access.statObject(ctx, user, repo, ref, path)
+				check(l, err)
+				if result == nil {
+					l.PushNil()
+					return 1
+				}
+				return util.DeepPush(l, result)
+			}},
+			{Name: "get_object", Function: func(l *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				ref := lua.CheckString(l, 2)
+				path := lua.CheckString(l, 3)
+				data, err := access.getObject(ctx, user, repo, ref, path)
+				check(l, err)
+				if data == nil {
+					l.PushNil()
+					return 1
+				}
+				l.PushString(string(data))
+				return 1
+			}},
+			{Name: "diff_refs", Function: func(l *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				leftRef := lua.CheckString(l, 2)
+				rightRef := lua.CheckString(l, 3)
+				result, err := access.diffRefs(ctx, user, repo, leftRef, rightRef, lua.OptString(l, 4, ""), lua.OptString(l, 5, ""), lua.OptString(l, 6, ""), optAmount(l, 7))
+				check(l, err)
+				return util.DeepPush(l, result)
+			}},
+		})
+		return 1
+	}
+	lua.Require(l, "lakefs/catalog", catalogOpen, false)
+	l.Pop(1)
+}
@@ -76,6 +76,18 @@ func getLakeFSJSONResponse(l *lua.State, server *http.Server, request *http.Requ
 	return 1 + util.DeepPush(l, output)
 }
 
+// getLakeFSStatusResponse pushes the status code of a request with no content on success, and the error body otherwise
+func getLakeFSStatusResponse(l *lua.State, server *http.Server, request *http.Request) int {
+	rr := httptest.NewRecorder()
+	server.Handler.ServeHTTP(rr, request)
+	l.PushInteger(rr.Code)
+	if rr.Body.Len() == 0 {
+		return 1
+	}
+	l.PushString(rr.Body.String())
+	return 2
+}
+
 func OpenClient(l *lua.State, ctx context.Context, user *model.User, server *http.Server) {
 	clientOpen := func(l *lua.State) int {
 		lua.NewLibrary(l, []lua.RegistryFunction{
@@ -232,6 +244,76 @@ func OpenClient(l *lua.State, ctx context.Context, user *model.User, server *htt
 				req.URL.RawQuery = q.Encode()
 				return getLakeFSJSONResponse(l, server, req)
 			}},
+			{Name: "list_params", Function: func(state *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				ref := lua.CheckString(l, 2)
+				reqURL, err := url.JoinPath("/repositories", repo, "refs", ref, "params")
+				if err != nil {
+					check(l, err)
+				}
+				req, err := newLakeFSJSONRequest(ctx, user, http.MethodGet, reqURL, nil)
+				if err != nil {
+					check(l, err)
+				}
+				// query params
+				q := req.URL.Query()
+				if !l.IsNone(3) {
+					q.Add("after", lua.CheckString(l, 3))
+				}
+				if !l.IsNone(4) {
+					q.Add("amount", fmt.Sprintf("%d", lua.CheckInteger(l, 4)))
+				}
+				req.URL.RawQuery = q.Encode()
+				return getLakeFSJSONResponse(l, server, req)
+			}},
+			{Name: "get_param", Function: func(state *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				ref := lua.CheckString(l, 2)
+				key := lua.CheckString(l, 3)
+				reqURL, err := url.JoinPath("/repositories", repo, "refs", ref, "params", key)
+				if err != nil {
+					check(l, err)
+				}
+				req, err := newLakeFSJSONRequest(ctx, user, http.MethodGet, reqURL, nil)
+				if err != nil {
+					check(l, err)
+				}
+				return getLakeFSJSONResponse(l, server, req)
+			}},
+			{Name: "set_param", Function: func(state *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				branch := lua.CheckString(l, 2)
+				key := lua.CheckString(l, 3)
+				data, err := json.Marshal(map[string]string{
+					"value": lua.CheckString(l, 4),
+				})
+				if err != nil {
+					check(l, err)
+				}
+				reqURL, err := url.JoinPath("/repositories", repo, "branches", branch, "params", key)
+				if err != nil {
+					check(l, err)
+				}
+				req, err := newLakeFSJSONRequest(ctx, user, http.MethodPut, reqURL, data)
+				if err != nil {
+					check(l, err)
+				}
+				return getLakeFSStatusResponse(l, server, req)
+			}},
+			{Name: "delete_param", Function: func(state *lua.State) int {
+				repo := lua.CheckString(l, 1)
+				branch := lua.CheckString(l, 2)
+				key := lua.CheckString(l, 3)
+				reqURL, err := url.JoinPath("/repositories", repo, "branches", branch, "params", key)
+				if err != nil {
+					check(l, err)
+				}
+				req, err := newLakeFSJSONRequest(ctx, user, http.MethodDelete, reqURL, nil)
+				if err != nil {
+					check(l, err)
+				}
+				return getLakeFSStatusResponse(l, server, req)
+			}},
 		})
 		return 1
 	}
@@ -0,0 +1,154 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/actions/lua/lakefs"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const luaCatalogNamespace = "mem://repo"
+
+// luaCatalog is a catalog holding the objects of a single repository and ref
+type luaCatalog struct {
+	entries []*catalog.DBEntry
+}
+
+func (c *luaCatalog) GetRepository(_ context.Context, repository string) (*catalog.Repository, error) {
+	return &catalog.Repository{Name: repository, StorageNamespace: luaCatalogNamespace}, nil
+}
+
+func (c *luaCatalog) GetEntry(_ context.Context, _, _, path string, _ catalog.GetEntryParams) (*catalog.DBEntry, error) {
+	for _, entry := range c.entries {
+		if entry.Path == path {
+			return entry, nil
+		}
+	}
+	return nil, graveler.ErrNotFound
+}
+
+func (c *luaCatalog) ListEntries(_ context.Context, _, _, prefix, after, _ string, limit int) ([]*catalog.DBEntry, bool, error) {
+	var entries []*catalog.DBEntry
+	for _, entry := range c.entries {
+		if strings.HasPrefix(entry.Path, prefix) && entry.Path > after {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > limit {
+		return entries[:limit], true, nil
+	}
+	return entries, false, nil
+}
+
+func (c *luaCatalog) Compare(_ context.Context, _, _, _ string, _ catalog.DiffParams) (catalog.Differences, bool, error) {
+	return catalog.Differences{
+		{DBEntry: catalog.DBEntry{Path: "data/a.txt", Size: 5}, Type: catalog.DifferenceTypeChanged},
+		{DBEntry: catalog.DBEntry{Path: "data/b.txt"}, Type: catalog.DifferenceTypeRemoved},
+	}, false, nil
+}
+
+// luaCatalogAuthorizer allows everything except reading objects under secret/
+type luaCatalogAuthorizer struct{}
+
+func (luaCatalogAuthorizer) Authorize(_ context.Context, req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+	denied := req.RequiredPermissions.Permission.Resource == permissions.ObjectArn("repo", "secret/key.txt")
+	return &auth.AuthorizationResponse{Allowed: !denied}, nil
+}
+
+func TestLuaRun_Catalog(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), &model.User{Username: "user1"})
+	adapter := mem.New(ctx)
+	objects := map[string]string{
+		"data/a.txt":     "hello",
+		"data/b.txt":     "large object",
+		"secret/key.txt": "secret",
+	}
+	lc := &luaCatalog{}
+	for _, path := range []string{"data/a.txt", "data/b.txt", "secret/key.txt"} {
+		content := objects[path]
+		address := "data/" + strings.ReplaceAll(path, "/", "_")
+		require.NoError(t, adapter.Put(ctx, block.ObjectPointer{
+			StorageNamespace: luaCatalogNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       address,
+		}, int64(len(content)), strings.NewReader(content), block.PutOpts{}))
+		lc.entries = append(lc.entries, &catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: address,
+			AddressType:     catalog.AddressTypeRelative,
+			Size:            int64(len(content)),
+			Checksum:        "etag-" + path,
+		})
+	}
+
+	mockStatsCollector := NewActionStatsMockCollector()
+	h, err := actions.NewLuaHook(
+		actions.ActionHook{
+			ID:   "myHook",
+			Type: actions.HookTypeLua,
+			Properties: map[string]interface{}{
+				"script": `
+local catalog = require("lakefs/catalog")
+local objects = catalog.list_objects("repo", "main", "", "data/")
+for _, o in ipairs(objects.results) do
+  print("object " .. o.path .. " " .. o.checksum)
+end
+print("content " .. catalog.get_object("repo", "main", "data/a.txt"))
+print("stat " .. catalog.stat_object("repo", "main", "data/a.txt").path)
+print("missing " .. tostring(catalog.stat_object("repo", "main", "data/missing.txt")))
+local diff = catalog.diff_refs("repo", "main~1", "main")
+for _, d in ipairs(diff.results) do
+  print("diff " .. d.type .. " " .. d.path)
+end
+local ok, err = pcall(catalog.get_object, "repo", "main", "secret/key.txt")
+print("secret " .. tostring(ok) .. " " .. err)
+ok, err = pcall(catalog.get_object, "repo", "main", "data/b.txt")
+print("large " .. tostring(ok) .. " " .. err)
+`,
+			},
+		},
+		&actions.Action{Name: "catalog"},
+		actions.Config{Enabled: true},
+		nil, "", &mockStatsCollector)
+	require.NoError(t, err)
+	h.(*actions.LuaHook).CatalogAccess = &lakefs.CatalogAccess{
+		Catalog:       lc,
+		Authorizer:    luaCatalogAuthorizer{},
+		BlockAdapter:  adapter,
+		MaxObjectSize: 10,
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, h.Run(ctx, graveler.HookRecord{
+		RunID:        "abc123",
+		EventType:    graveler.EventTypePreCommit,
+		RepositoryID: "repo",
+		SourceRef:    "main",
+		BranchID:     "main",
+	}, out))
+	output := out.String()
+	for _, expected := range []string{
+		"object data/a.txt etag-data/a.txt\nobject data/b.txt etag-data/b.txt\n",
+		"content hello\n",
+		"stat data/a.txt\n",
+		"missing nil\n",
+		"diff changed data/a.txt\ndiff removed data/b.txt\n",
+		"secret false",
+		"insufficient permissions",
+		"large false",
+		"object too large",
+	} {
+		require.Contains(t, output, expected)
+	}
+}
@@ -0,0 +1,363 @@
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// Materialize regenerates objects derived from the objects under prefixes of a branch after commits and merges to
+// it, and commits them in a follow-up commit
+type Materialize struct {
+	HookBase
+	Views []MaterializedView
+}
+
+// MaterializedView is an object at Path derived from the objects under Prefix
+type MaterializedView struct {
+	Path   string
+	Prefix string
+	Type   string
+	// Header is set if CSV and TSV objects start with a header line, which is not counted as a row
+	Header bool
+}
+
+const (
+	MaterializedViewManifest = "manifest"
+	MaterializedViewSummary  = "summary"
+
+	// MaterializedFromMetadataKey records on the follow-up commit the commit its views were generated from
+	MaterializedFromMetadataKey = ".lakefs.materialized_from"
+
+	materializeViewsPropertyKey = "views"
+	materializeListAmount       = 1000
+)
+
+var (
+	errMaterializeWrongFormat   = errors.New("materialize wrong format")
+	errMaterializeRequestFailed = errors.New("materialize request failed")
+)
+
+// materializeRowFormats are the extensions of objects holding a row per line
+var materializeRowFormats = map[string]bool{
+	".csv":    true,
+	".tsv":    true,
+	".jsonl":  true,
+	".ndjson": true,
+}
+
+func NewMaterializeHook(h ActionHook, action *Action, cfg Config, e *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	rawViews, ok := h.Properties[materializeViewsPropertyKey].([]interface{})
+	if !ok || len(rawViews) == 0 {
+		return nil, fmt.Errorf("views must be a non-empty list: %w", errMaterializeWrongFormat)
+	}
+	views := make([]MaterializedView, 0, len(rawViews))
+	for i, rawView := range rawViews {
+		props, ok := rawView.(Properties)
+		if !ok {
+			return nil, fmt.Errorf("view %d is not a map: %w", i, errMaterializeWrongFormat)
+		}
+		var view MaterializedView
+		var err error
+		if view.Path, err = props.getRequiredProperty("path"); err != nil {
+			return nil, fmt.Errorf("view %d: %w", i, err)
+		}
+		view.Prefix, _ = props["prefix"].(string)
+		view.Type, _ = props["type"].(string)
+		switch view.Type {
+		case "":
+			view.Type = MaterializedViewManifest
+		case MaterializedViewManifest, MaterializedViewSummary:
+		default:
+			return nil, fmt.Errorf("view %d: unknown type %s: %w", i, view.Type, errMaterializeWrongFormat)
+		}
+		view.Header, _ = props["header"].(bool)
+		views = append(views, view)
+	}
+	return &Materialize{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   e,
+		},
+		Views: views,
+	}, nil
+}
+
+type materializeObject struct {
+	Path      string `json:"path"`
+	PathType  string `json:"path_type,omitempty"`
+	Checksum  string `json:"checksum"`
+	SizeBytes int64  `json:"size_bytes"`
+	Mtime     int64  `json:"mtime"`
+}
+
+type materializeManifest struct {
+	Prefix   string              `json:"prefix"`
+	CommitID string              `json:"commit_id"`
+	Files    []materializeObject `json:"files"`
+}
+
+type materializeSummary struct {
+	Prefix    string `json:"prefix"`
+	CommitID  string `json:"commit_id"`
+	Objects   int    `json:"objects"`
+	SizeBytes int64  `json:"size_bytes"`
+	Rows      int64  `json:"rows"`
+}
+
+func (m *Materialize) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	if record.EventType != graveler.EventTypePostCommit && record.EventType != graveler.EventTypePostMerge {
+		return fmt.Errorf("materialize hook runs on post-commit and post-merge events, not %s: %w", record.EventType, ErrInvalidAction)
+	}
+	if _, ok := record.Commit.Metadata[MaterializedFromMetadataKey]; ok {
+		// the follow-up commit of the views
+		_, _ = fmt.Fprintf(buf, "Commit %s holds materialized views, skipping\n", record.CommitID)
+		return nil
+	}
+	if m.Endpoint == nil {
+		return fmt.Errorf("no endpoint configured: %w", ErrInvalidAction)
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).
+		WithField("hook_type", "materialize").
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	viewPaths := make(map[string]struct{}, len(m.Views))
+	for _, view := range m.Views {
+		viewPaths[view.Path] = struct{}{}
+	}
+	var changed []string
+	for _, view := range m.Views {
+		data, err := m.generate(ctx, user, record, view, viewPaths)
+		if err != nil {
+			return fmt.Errorf("view %s: %w", view.Path, err)
+		}
+		current, found, err := m.getObject(ctx, user, record, view.Path)
+		if err != nil {
+			return fmt.Errorf("view %s: %w", view.Path, err)
+		}
+		if found && bytes.Equal(current, data) {
+			_, _ = fmt.Fprintf(buf, "View %s is up to date\n", view.Path)
+			continue
+		}
+		if err := m.uploadObject(ctx, user, record, view.Path, data); err != nil {
+			return fmt.Errorf("view %s: %w", view.Path, err)
+		}
+		_, _ = fmt.Fprintf(buf, "View %s regenerated (%d bytes)\n", view.Path, len(data))
+		changed = append(changed, view.Path)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	commitID, err := m.commit(ctx, user, record, changed)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(buf, "Committed views in %s\n", commitID)
+	return nil
+}
+
+// generate returns the content of view from the objects of the commit of record
+func (m *Materialize) generate(ctx context.Context, user *model.User, record graveler.HookRecord, view MaterializedView, viewPaths map[string]struct{}) ([]byte, error) {
+	objects, err := m.listObjects(ctx, user, record, view.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	// views are not part of the data they describe
+	sourceObjects := objects[:0]
+	for _, obj := range objects {
+		if _, ok := viewPaths[obj.Path]; !ok {
+			sourceObjects = append(sourceObjects, obj)
+		}
+	}
+
+	switch view.Type {
+	case MaterializedViewManifest:
+		manifest := materializeManifest{
+			Prefix:   view.Prefix,
+			CommitID: record.CommitID.String(),
+			Files:    make([]materializeObject, 0, len(sourceObjects)),
+		}
+		for _, obj := range sourceObjects {
+			obj.PathType = ""
+			manifest.Files = append(manifest.Files, obj)
+		}
+		return json.MarshalIndent(manifest, "", "  ")
+	case MaterializedViewSummary:
+		summary := materializeSummary{
+			Prefix:   view.Prefix,
+			CommitID: record.CommitID.String(),
+		}
+		for _, obj := range sourceObjects {
+			summary.Objects++
+			summary.SizeBytes += obj.SizeBytes
+			ext := strings.ToLower(path.Ext(obj.Path))
+			if !materializeRowFormats[ext] {
+				continue
+			}
+			data, _, err := m.getObject(ctx, user, record, obj.Path)
+			if err != nil {
+				return nil, err
+			}
+			rows := countLines(data)
+			if view.Header && rows > 0 && (ext == ".csv" || ext == ".tsv") {
+				rows--
+			}
+			summary.Rows += rows
+		}
+		return json.MarshalIndent(summary, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown type %s: %w", view.Type, errMaterializeWrongFormat)
+	}
+}
+
+func countLines(data []byte) int64 {
+	var lines int64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 0 {
+			lines++
+		}
+	}
+	return lines
+}
+
+// serve performs a lakeFS API request as user
+func (m *Materialize) serve(ctx context.Context, user *model.User, method string, pathElements []string, query url.Values, contentType string, body io.Reader) (*httptest.ResponseRecorder, error) {
+	reqURL, err := url.JoinPath(apiutil.BaseURL, pathElements...)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	// the routing information of the request running the hook breaks the routing of this request
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, nil)
+	req, err := http.NewRequestWithContext(auth.WithUser(ctx, user), method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rr := httptest.NewRecorder()
+	m.Endpoint.Handler.ServeHTTP(rr, req)
+	return rr, nil
+}
+
+func (m *Materialize) listObjects(ctx context.Context, user *model.User, record graveler.HookRecord, prefix string) ([]materializeObject, error) {
+	var objects []materializeObject
+	after := ""
+	for {
+		query := url.Values{
+			"prefix": {prefix},
+			"after":  {after},
+			"amount": {strconv.Itoa(materializeListAmount)},
+		}
+		rr, err := m.serve(ctx, user, http.MethodGet, []string{"repositories", record.RepositoryID.String(), "refs", record.CommitID.String(), "objects", "ls"}, query, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		if rr.Code != http.StatusOK {
+			return nil, fmt.Errorf("list objects %s: HTTP %d: %w", prefix, rr.Code, errMaterializeRequestFailed)
+		}
+		var page struct {
+			Pagination struct {
+				HasMore    bool   `json:"has_more"`
+				NextOffset string `json:"next_offset"`
+			} `json:"pagination"`
+			Results []materializeObject `json:"results"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			return nil, fmt.Errorf("list objects %s: %w", prefix, err)
+		}
+		for _, obj := range page.Results {
+			if obj.PathType == "object" {
+				objects = append(objects, obj)
+			}
+		}
+		if !page.Pagination.HasMore {
+			return objects, nil
+		}
+		after = page.Pagination.NextOffset
+	}
+}
+
+// getObject returns the content of the object at p of the commit of record, and false if it does not exist
+func (m *Materialize) getObject(ctx context.Context, user *model.User, record graveler.HookRecord, p string) ([]byte, bool, error) {
+	rr, err := m.serve(ctx, user, http.MethodGet, []string{"repositories", record.RepositoryID.String(), "refs", record.CommitID.String(), "objects"}, url.Values{"path": {p}}, "", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	switch rr.Code {
+	case http.StatusOK:
+		return rr.Body.Bytes(), true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("get object %s: HTTP %d: %w", p, rr.Code, errMaterializeRequestFailed)
+	}
+}
+
+func (m *Materialize) uploadObject(ctx context.Context, user *model.User, record graveler.HookRecord, p string, data []byte) error {
+	rr, err := m.serve(ctx, user, http.MethodPost, []string{"repositories", record.RepositoryID.String(), "branches", record.BranchID.String(), "objects"}, url.Values{"path": {p}}, "application/octet-stream", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if rr.Code != http.StatusCreated {
+		return fmt.Errorf("upload object %s: HTTP %d: %w", p, rr.Code, errMaterializeRequestFailed)
+	}
+	return nil
+}
+
+// commit commits the views to the branch of record and returns the ID of the commit
+func (m *Materialize) commit(ctx context.Context, user *model.User, record graveler.HookRecord, views []string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"message": fmt.Sprintf("Materialize %s of %s", strings.Join(views, ", "), record.CommitID),
+		"metadata": map[string]string{
+			MaterializedFromMetadataKey: record.CommitID.String(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	rr, err := m.serve(ctx, user, http.MethodPost, []string{"repositories", record.RepositoryID.String(), "branches", record.BranchID.String(), "commits"}, nil, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	if rr.Code != http.StatusCreated {
+		return "", fmt.Errorf("commit: HTTP %d: %s: %w", rr.Code, strings.TrimSpace(rr.Body.String()), errMaterializeRequestFailed)
+	}
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &commit); err != nil {
+		return "", fmt.Errorf("commit: %w", err)
+	}
+	return commit.ID, nil
+}
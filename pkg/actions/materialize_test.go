@@ -0,0 +1,144 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// materializeEndpoint serves the lakeFS objects and commits APIs of a repository holding objects
+type materializeEndpoint struct {
+	objects  map[string]string
+	uploaded map[string]string
+	commits  []map[string]interface{}
+}
+
+func (e *materializeEndpoint) server(t *testing.T) *http.Server {
+	t.Helper()
+	return &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := auth.GetUser(r.Context())
+		require.NoError(t, err)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/repositories/repo/refs/c1/objects/ls":
+			var results []map[string]interface{}
+			for _, p := range []string{"events/a.csv", "events/b.jsonl", "events/manifest.json", "events/readme.md"} {
+				if content, ok := e.objects[p]; ok {
+					results = append(results, map[string]interface{}{
+						"path": p, "path_type": "object", "checksum": "etag-" + p, "size_bytes": len(content),
+					})
+				}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+				"pagination": map[string]interface{}{"has_more": false},
+				"results":    results,
+			}))
+		case "GET /api/v1/repositories/repo/refs/c1/objects":
+			content, ok := e.objects[r.URL.Query().Get("path")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, content)
+		case "POST /api/v1/repositories/repo/branches/main/objects":
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			e.uploaded[r.URL.Query().Get("path")] = string(data)
+			w.WriteHeader(http.StatusCreated)
+		case "POST /api/v1/repositories/repo/branches/main/commits":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			e.commits = append(e.commits, body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"id": "c2"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})}
+}
+
+func TestMaterializeHook(t *testing.T) {
+	ctx := auth.WithUser(context.Background(), &model.User{Username: "alice"})
+	record := graveler.HookRecord{
+		RunID:        "run",
+		EventType:    graveler.EventTypePostCommit,
+		RepositoryID: "repo",
+		BranchID:     "main",
+		CommitID:     "c1",
+		Commit:       graveler.Commit{Committer: "alice", Message: "add events"},
+	}
+	action, err := actions.ParseAction([]byte(`name: views
+on:
+  post-commit: {}
+hooks:
+  - id: materialize
+    type: materialize
+    properties:
+      views:
+        - path: events/manifest.json
+          prefix: events/
+        - path: events/summary.json
+          prefix: events/
+          type: summary
+          header: true
+`))
+	require.NoError(t, err)
+
+	endpoint := &materializeEndpoint{
+		objects: map[string]string{
+			"events/a.csv":         "id,name\n1,a\n2,b\n",
+			"events/b.jsonl":       "{\"id\": 3}\n",
+			"events/manifest.json": "{}",
+			"events/readme.md":     "events",
+		},
+		uploaded: map[string]string{},
+	}
+	h, err := actions.NewHook(action.Hooks[0], action, actions.Config{Enabled: true}, endpoint.server(t), "", nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, h.Run(ctx, record, &buf))
+	require.Len(t, endpoint.uploaded, 2)
+
+	var manifest struct {
+		CommitID string `json:"commit_id"`
+		Files    []struct {
+			Path string `json:"path"`
+		} `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(endpoint.uploaded["events/manifest.json"]), &manifest))
+	require.Equal(t, "c1", manifest.CommitID)
+	paths := make([]string, 0, len(manifest.Files))
+	for _, f := range manifest.Files {
+		paths = append(paths, f.Path)
+	}
+	require.Equal(t, []string{"events/a.csv", "events/b.jsonl", "events/readme.md"}, paths)
+
+	var summary struct {
+		Objects int   `json:"objects"`
+		Rows    int64 `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(endpoint.uploaded["events/summary.json"]), &summary))
+	require.Equal(t, 3, summary.Objects)
+	require.EqualValues(t, 3, summary.Rows)
+
+	require.Len(t, endpoint.commits, 1)
+	require.Equal(t, map[string]interface{}{actions.MaterializedFromMetadataKey: "c1"}, endpoint.commits[0]["metadata"])
+
+	t.Run("follow_up_commit", func(t *testing.T) {
+		followUp := record
+		followUp.CommitID = "c2"
+		followUp.Commit.Metadata = graveler.Metadata{actions.MaterializedFromMetadataKey: "c1"}
+		require.NoError(t, h.Run(ctx, followUp, &buf))
+		require.Len(t, endpoint.commits, 1)
+	})
+}
@@ -0,0 +1,416 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+// MetadataCatalog pushes the datasets of a repository, with their schema and lineage, to a metadata catalog
+type MetadataCatalog struct {
+	HookBase
+	Provider HookType
+	URL      string
+	Token    SecureString
+	// Platform is the DataHub data platform of the datasets
+	Platform string
+	// Database is the fully qualified name of the OpenMetadata database holding the datasets
+	Database string
+	Datasets []MetadataCatalogDataset
+	Timeout  time.Duration
+}
+
+// MetadataCatalogDataset is a dataset stored under Path of each branch
+type MetadataCatalogDataset struct {
+	Path   string
+	Schema []MetadataCatalogField
+}
+
+type MetadataCatalogField struct {
+	Name string
+	Type string
+}
+
+const (
+	metadataCatalogDefaultTimeout  = 1 * time.Minute
+	metadataCatalogDefaultPlatform = "lakefs"
+
+	metadataCatalogURLPropertyKey      = "url"
+	metadataCatalogTokenPropertyKey    = "token"
+	metadataCatalogPlatformPropertyKey = "platform"
+	metadataCatalogDatabasePropertyKey = "database"
+	metadataCatalogDatasetsPropertyKey = "datasets"
+	metadataCatalogTimeoutPropertyKey  = "timeout"
+)
+
+var (
+	errMetadataCatalogRequestFailed = errors.New("metadata catalog request failed")
+	errMetadataCatalogWrongFormat   = errors.New("metadata catalog wrong format")
+)
+
+// metadataCatalogTypes maps the supported field types to their DataHub and OpenMetadata types
+var metadataCatalogTypes = map[string]struct {
+	DataHub      string
+	OpenMetadata string
+}{
+	"string":    {DataHub: "StringType", OpenMetadata: "STRING"},
+	"int":       {DataHub: "NumberType", OpenMetadata: "INT"},
+	"long":      {DataHub: "NumberType", OpenMetadata: "BIGINT"},
+	"float":     {DataHub: "NumberType", OpenMetadata: "FLOAT"},
+	"double":    {DataHub: "NumberType", OpenMetadata: "DOUBLE"},
+	"decimal":   {DataHub: "NumberType", OpenMetadata: "DECIMAL"},
+	"boolean":   {DataHub: "BooleanType", OpenMetadata: "BOOLEAN"},
+	"date":      {DataHub: "DateType", OpenMetadata: "DATE"},
+	"timestamp": {DataHub: "TimeType", OpenMetadata: "TIMESTAMP"},
+	"bytes":     {DataHub: "BytesType", OpenMetadata: "BINARY"},
+}
+
+func NewMetadataCatalogHook(h ActionHook, action *Action, cfg Config, endpoint *http.Server, _ string, _ stats.Collector) (Hook, error) {
+	hook := MetadataCatalog{
+		HookBase: HookBase{
+			ID:         h.ID,
+			ActionName: action.Name,
+			Config:     cfg,
+			Endpoint:   endpoint,
+		},
+		Provider: h.Type,
+		Platform: metadataCatalogDefaultPlatform,
+		Timeout:  metadataCatalogDefaultTimeout,
+	}
+
+	var err error
+	hook.URL, err = h.Properties.getRequiredProperty(metadataCatalogURLPropertyKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s hook url property: %w", h.Type, err)
+	}
+	if err := checkEndpointAllowed(cfg, hook.URL); err != nil {
+		return nil, err
+	}
+
+	if rawToken, ok := h.Properties[metadataCatalogTokenPropertyKey].(string); ok {
+		envGetter := NewEnvironmentVariableGetter(cfg.Env.Enabled, cfg.Env.Prefix)
+		hook.Token, err = NewSecureString(rawToken, envGetter)
+		if err != nil {
+			return nil, fmt.Errorf("%s hook token property: %w", h.Type, err)
+		}
+	}
+
+	switch h.Type {
+	case HookTypeDataHub:
+		if v, ok := h.Properties[metadataCatalogPlatformPropertyKey].(string); ok && v != "" {
+			hook.Platform = v
+		}
+	case HookTypeOpenMetadata:
+		hook.Database, err = h.Properties.getRequiredProperty(metadataCatalogDatabasePropertyKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s hook database property: %w", h.Type, err)
+		}
+	}
+
+	if v, ok := h.Properties[metadataCatalogTimeoutPropertyKey].(string); ok {
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s hook timeout property: %w", h.Type, err)
+		}
+		hook.Timeout = duration
+	}
+
+	hook.Datasets, err = extractMetadataCatalogDatasets(h.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("%s hook datasets property: %w", h.Type, err)
+	}
+	return &hook, nil
+}
+
+func extractMetadataCatalogDatasets(props Properties) ([]MetadataCatalogDataset, error) {
+	rawDatasets, ok := props[metadataCatalogDatasetsPropertyKey].([]interface{})
+	if !ok || len(rawDatasets) == 0 {
+		return nil, fmt.Errorf("datasets must be a non-empty list: %w", errMetadataCatalogWrongFormat)
+	}
+	datasets := make([]MetadataCatalogDataset, 0, len(rawDatasets))
+	for _, rawDataset := range rawDatasets {
+		datasetProps, ok := rawDataset.(Properties)
+		if !ok {
+			return nil, fmt.Errorf("dataset is not of type Properties: %w", errWrongValueType)
+		}
+		datasetPath, err := datasetProps.getRequiredProperty("path")
+		if err != nil {
+			return nil, err
+		}
+		dataset := MetadataCatalogDataset{Path: strings.Trim(datasetPath, "/")}
+		rawSchema, _ := datasetProps["schema"].([]interface{})
+		for _, rawField := range rawSchema {
+			fieldProps, ok := rawField.(Properties)
+			if !ok {
+				return nil, fmt.Errorf("dataset %s schema field is not of type Properties: %w", datasetPath, errWrongValueType)
+			}
+			name, err := fieldProps.getRequiredProperty("name")
+			if err != nil {
+				return nil, err
+			}
+			fieldType, err := fieldProps.getRequiredProperty("type")
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := metadataCatalogTypes[fieldType]; !ok {
+				return nil, fmt.Errorf("dataset %s field %s type %s: %w", datasetPath, name, fieldType, errMetadataCatalogWrongFormat)
+			}
+			dataset.Schema = append(dataset.Schema, MetadataCatalogField{Name: name, Type: fieldType})
+		}
+		datasets = append(datasets, dataset)
+	}
+	return datasets, nil
+}
+
+func (m *MetadataCatalog) Run(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	logging.FromContext(ctx).
+		WithField("hook_type", m.Provider).
+		WithField("event_type", record.EventType).
+		Debug("hook action executing")
+
+	if record.EventType != graveler.EventTypePostCommit && record.EventType != graveler.EventTypePostMerge {
+		_, _ = fmt.Fprintf(buf, "Skipping event %s: metadata is pushed only after commit and merge\n", record.EventType)
+		return nil
+	}
+	if m.Provider == HookTypeOpenMetadata {
+		return m.runOpenMetadata(ctx, record, buf)
+	}
+	return m.runDataHub(ctx, record, buf)
+}
+
+// mergeSource returns the commit merged into the branch, or "" if the record is not of a merge
+func mergeSource(record graveler.HookRecord) graveler.CommitID {
+	if record.EventType != graveler.EventTypePostMerge || len(record.Commit.Parents) < 2 { //nolint:mnd
+		return ""
+	}
+	return record.Commit.Parents[1]
+}
+
+// commitProperties describes the commit that created the current version of the datasets, and its lineage
+func commitProperties(record graveler.HookRecord) map[string]string {
+	parents := make([]string, 0, len(record.Commit.Parents))
+	for _, parent := range record.Commit.Parents {
+		parents = append(parents, parent.String())
+	}
+	props := map[string]string{
+		"lakefs.repository":  record.RepositoryID.String(),
+		"lakefs.branch":      record.BranchID.String(),
+		"lakefs.commit_id":   record.CommitID.String(),
+		"lakefs.committer":   record.Commit.Committer,
+		"lakefs.message":     record.Commit.Message,
+		"lakefs.parents":     strings.Join(parents, ","),
+		"lakefs.metarange":   record.Commit.MetaRangeID.String(),
+		"lakefs.commit_time": record.Commit.CreationDate.UTC().Format(time.RFC3339),
+	}
+	if source := mergeSource(record); source != "" {
+		props["lakefs.merge_source"] = source.String()
+	}
+	for k, v := range record.Commit.Metadata {
+		props["lakefs.metadata."+k] = v
+	}
+	return props
+}
+
+func (m *MetadataCatalog) datasetURN(repository, ref, datasetPath string) string {
+	return fmt.Sprintf("urn:li:dataset:(urn:li:dataPlatform:%s,%s/%s/%s,PROD)", m.Platform, repository, ref, datasetPath)
+}
+
+type dataHubProposal struct {
+	Proposal dataHubMetadataChange `json:"proposal"`
+}
+
+type dataHubMetadataChange struct {
+	EntityType string        `json:"entityType"`
+	EntityURN  string        `json:"entityUrn"`
+	ChangeType string        `json:"changeType"`
+	AspectName string        `json:"aspectName"`
+	Aspect     dataHubAspect `json:"aspect"`
+}
+
+type dataHubAspect struct {
+	Value       string `json:"value"`
+	ContentType string `json:"contentType"`
+}
+
+func (m *MetadataCatalog) runDataHub(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	properties := commitProperties(record)
+	auditStamp := map[string]interface{}{
+		"time":  record.Commit.CreationDate.UnixMilli(),
+		"actor": "urn:li:corpuser:" + record.Commit.Committer,
+	}
+	source := mergeSource(record)
+	for _, dataset := range m.Datasets {
+		urn := m.datasetURN(record.RepositoryID.String(), record.BranchID.String(), dataset.Path)
+		aspects := map[string]interface{}{
+			"datasetProperties": map[string]interface{}{
+				"name":             dataset.Path,
+				"qualifiedName":    fmt.Sprintf("lakefs://%s/%s/%s", record.RepositoryID, record.BranchID, dataset.Path),
+				"customProperties": properties,
+			},
+		}
+		if len(dataset.Schema) > 0 {
+			fields := make([]map[string]interface{}, 0, len(dataset.Schema))
+			for _, field := range dataset.Schema {
+				fields = append(fields, map[string]interface{}{
+					"fieldPath":      field.Name,
+					"nativeDataType": field.Type,
+					"type": map[string]interface{}{
+						"type": map[string]interface{}{
+							"com.linkedin.schema." + metadataCatalogTypes[field.Type].DataHub: map[string]interface{}{},
+						},
+					},
+				})
+			}
+			aspects["schemaMetadata"] = map[string]interface{}{
+				"schemaName": dataset.Path,
+				"platform":   "urn:li:dataPlatform:" + m.Platform,
+				"version":    0,
+				"hash":       record.CommitID.String(),
+				"platformSchema": map[string]interface{}{
+					"com.linkedin.schema.OtherSchema": map[string]interface{}{"rawSchema": ""},
+				},
+				"fields": fields,
+			}
+		}
+		if source != "" {
+			// the merged commit is the upstream of the branch dataset
+			aspects["upstreamLineage"] = map[string]interface{}{
+				"upstreams": []map[string]interface{}{{
+					"dataset":    m.datasetURN(record.RepositoryID.String(), source.String(), dataset.Path),
+					"type":       "COPY",
+					"auditStamp": auditStamp,
+				}},
+			}
+		}
+		for _, aspectName := range []string{"datasetProperties", "schemaMetadata", "upstreamLineage"} {
+			aspect, ok := aspects[aspectName]
+			if !ok {
+				continue
+			}
+			value, err := json.Marshal(aspect)
+			if err != nil {
+				return fmt.Errorf("aspect %s serialization error: %w", aspectName, err)
+			}
+			err = m.doRequest(ctx, http.MethodPost, "/aspects", url.Values{"action": []string{"ingestProposal"}}, dataHubProposal{
+				Proposal: dataHubMetadataChange{
+					EntityType: "dataset",
+					EntityURN:  urn,
+					ChangeType: "UPSERT",
+					AspectName: aspectName,
+					Aspect:     dataHubAspect{Value: string(value), ContentType: "application/json"},
+				},
+			}, buf)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type openMetadataColumn struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+}
+
+type openMetadataTable struct {
+	Name           string               `json:"name"`
+	DatabaseSchema string               `json:"databaseSchema"`
+	Description    string               `json:"description,omitempty"`
+	Columns        []openMetadataColumn `json:"columns"`
+}
+
+// openMetadataFQNPart quotes a part of a fully qualified name that contains the separator
+func openMetadataFQNPart(s string) string {
+	if strings.Contains(s, ".") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+func (m *MetadataCatalog) runOpenMetadata(ctx context.Context, record graveler.HookRecord, buf *bytes.Buffer) error {
+	// each branch is a schema of the database, holding a table for each dataset
+	schemaName := record.BranchID.String()
+	err := m.doRequest(ctx, http.MethodPut, "/api/v1/databaseSchemas", nil, map[string]string{
+		"name":     schemaName,
+		"database": m.Database,
+	}, buf)
+	if err != nil {
+		return err
+	}
+
+	var description strings.Builder
+	description.WriteString("Created by lakeFS commit `" + record.CommitID.String() + "`.\n\n")
+	properties := commitProperties(record)
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(&description, "- **%s**: %s\n", k, properties[k])
+	}
+
+	for _, dataset := range m.Datasets {
+		columns := make([]openMetadataColumn, 0, len(dataset.Schema))
+		for _, field := range dataset.Schema {
+			columns = append(columns, openMetadataColumn{Name: field.Name, DataType: metadataCatalogTypes[field.Type].OpenMetadata})
+		}
+		err := m.doRequest(ctx, http.MethodPut, "/api/v1/tables", nil, openMetadataTable{
+			Name:           dataset.Path,
+			DatabaseSchema: m.Database + "." + openMetadataFQNPart(schemaName),
+			Description:    description.String(),
+			Columns:        columns,
+		}, buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MetadataCatalog) doRequest(ctx context.Context, method, requestPath string, query url.Values, body interface{}, buf *bytes.Buffer) error {
+	u, err := url.Parse(m.URL)
+	if err != nil {
+		return fmt.Errorf("parse %s url: %w", m.Provider, err)
+	}
+	u.Path = path.Join(u.Path, requestPath)
+	u.RawQuery = query.Encode()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("request serialization error: %w", err)
+	}
+	_, _ = fmt.Fprintf(buf, "Request:\n%s %s\n", method, u.String())
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.Token.val != "" {
+		req.Header.Set("Authorization", "Bearer "+m.Token.val)
+		_, _ = fmt.Fprintf(buf, "Authorization: Bearer %s\n", m.Token.String())
+	}
+	_, _ = fmt.Fprintf(buf, "Body: %s\n\n", data)
+
+	statusCode, err := doHTTPRequestWithLog(ctx, req, buf, m.Timeout)
+	if err != nil {
+		return fmt.Errorf("failed executing %s request: %w", m.Provider, err)
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return fmt.Errorf("%s %s status code (%d): %w", method, requestPath, statusCode, errMetadataCatalogRequestFailed)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+type metadataCatalogRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Auth   string
+	Body   map[string]interface{}
+}
+
+func newMetadataCatalogServer(t *testing.T) (*httptest.Server, func() []metadataCatalogRequest) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		requests []metadataCatalogRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		req := metadataCatalogRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Auth: r.Header.Get("Authorization")}
+		if err := json.Unmarshal(data, &req.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []metadataCatalogRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func newMetadataCatalogHook(t *testing.T, actionContent string) actions.Hook {
+	t.Helper()
+	action, err := actions.ParseAction([]byte(actionContent))
+	require.NoError(t, err)
+	require.Len(t, action.Hooks, 1)
+	h, err := actions.NewHook(action.Hooks[0], action, actions.Config{Enabled: true}, nil, "", nil)
+	require.NoError(t, err)
+	return h
+}
+
+func TestMetadataCatalog_DataHub(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newMetadataCatalogServer(t)
+	h := newMetadataCatalogHook(t, `name: catalog
+on:
+  post-merge: {}
+hooks:
+  - id: datahub
+    type: datahub
+    properties:
+      url: "`+server.URL+`/gms"
+      token: "secret"
+      datasets:
+        - path: tables/users/
+          schema:
+            - name: id
+              type: long
+            - name: name
+              type: string
+`)
+	record := graveler.HookRecord{
+		RunID:        "run",
+		EventType:    graveler.EventTypePostMerge,
+		RepositoryID: "repo",
+		BranchID:     "main",
+		CommitID:     "c2",
+		Commit: graveler.Commit{
+			Committer:    "alice",
+			Message:      "merge",
+			CreationDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Parents:      graveler.CommitParents{"c1", "s1"},
+			Metadata:     graveler.Metadata{"team": "data"},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, h.Run(ctx, record, &buf))
+	require.NotContains(t, buf.String(), "secret")
+
+	got := requests()
+	require.Len(t, got, 3)
+	const urn = "urn:li:dataset:(urn:li:dataPlatform:lakefs,repo/main/tables/users,PROD)"
+	aspects := map[string]map[string]interface{}{}
+	for _, req := range got {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/gms/aspects", req.Path)
+		require.Equal(t, "action=ingestProposal", req.Query)
+		require.Equal(t, "Bearer secret", req.Auth)
+		proposal := req.Body["proposal"].(map[string]interface{})
+		require.Equal(t, urn, proposal["entityUrn"])
+		aspect := proposal["aspect"].(map[string]interface{})
+		var value map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(aspect["value"].(string)), &value))
+		aspects[proposal["aspectName"].(string)] = value
+	}
+
+	properties := aspects["datasetProperties"]["customProperties"].(map[string]interface{})
+	require.Equal(t, "c2", properties["lakefs.commit_id"])
+	require.Equal(t, "c1,s1", properties["lakefs.parents"])
+	require.Equal(t, "s1", properties["lakefs.merge_source"])
+	require.Equal(t, "data", properties["lakefs.metadata.team"])
+	require.Len(t, aspects["schemaMetadata"]["fields"], 2)
+	upstreams := aspects["upstreamLineage"]["upstreams"].([]interface{})
+	require.Len(t, upstreams, 1)
+	require.Equal(t, "urn:li:dataset:(urn:li:dataPlatform:lakefs,repo/s1/tables/users,PROD)", upstreams[0].(map[string]interface{})["dataset"])
+
+	// other events are not pushed
+	record.EventType = graveler.EventTypePreMerge
+	require.NoError(t, h.Run(ctx, record, &buf))
+	require.Len(t, requests(), 3)
+}
+
+func TestMetadataCatalog_OpenMetadata(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newMetadataCatalogServer(t)
+	h := newMetadataCatalogHook(t, `name: catalog
+on:
+  post-commit: {}
+hooks:
+  - id: openmetadata
+    type: openmetadata
+    properties:
+      url: "`+server.URL+`"
+      database: lakefs.repo
+      datasets:
+        - path: users
+          schema:
+            - name: id
+              type: long
+`)
+	record := graveler.HookRecord{
+		RunID:        "run",
+		EventType:    graveler.EventTypePostCommit,
+		RepositoryID: "repo",
+		BranchID:     "release.1",
+		CommitID:     "c1",
+		Commit:       graveler.Commit{Committer: "alice", Message: "commit"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, h.Run(ctx, record, &buf))
+
+	got := requests()
+	require.Len(t, got, 2)
+	require.Equal(t, http.MethodPut, got[0].Method)
+	require.Equal(t, "/api/v1/databaseSchemas", got[0].Path)
+	require.Equal(t, "release.1", got[0].Body["name"])
+	require.Equal(t, "lakefs.repo", got[0].Body["database"])
+	require.Equal(t, "/api/v1/tables", got[1].Path)
+	require.Equal(t, "users", got[1].Body["name"])
+	require.Equal(t, `lakefs.repo."release.1"`, got[1].Body["databaseSchema"])
+	require.Equal(t, []interface{}{map[string]interface{}{"name": "id", "dataType": "BIGINT"}}, got[1].Body["columns"])
+	require.Contains(t, got[1].Body["description"], "c1")
+}
+
+func TestMetadataCatalog_InvalidProperties(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Type       string
+		Properties string
+	}{
+		{Name: "missing url", Properties: "datasets: [{path: a}]"},
+		{Name: "missing datasets", Properties: "url: http://catalog"},
+		{Name: "unknown type", Properties: "url: http://catalog\n      datasets: [{path: a, schema: [{name: x, type: struct}]}]"},
+		{Name: "openmetadata missing database", Properties: "url: http://catalog\n      datasets: [{path: a}]", Type: "openmetadata"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hookType := tc.Type
+			if hookType == "" {
+				hookType = "datahub"
+			}
+			action, err := actions.ParseAction([]byte(`name: catalog
+on:
+  post-commit: {}
+hooks:
+  - id: catalog
+    type: ` + hookType + `
+    properties:
+      ` + tc.Properties + "\n"))
+			require.NoError(t, err)
+			_, err = actions.NewHook(action.Hooks[0], action, actions.Config{Enabled: true}, nil, "", nil)
+			require.Error(t, err)
+		})
+	}
+}
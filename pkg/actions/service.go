@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/treeverse/lakefs/pkg/actions/lua/hook"
+	"github.com/treeverse/lakefs/pkg/actions/lua/lakefs"
 
 	"github.com/antonmedv/expr"
 	"github.com/hashicorp/go-multierror"
@@ -48,6 +49,13 @@ type Config struct {
 		Enabled bool
 		Prefix  string
 	}
+	Webhook struct {
+		AllowedEndpoints []string
+	}
+	Central struct {
+		Repository string
+		Branch     string
+	}
 }
 
 // StoreService is an implementation of actions.Service that saves
@@ -62,11 +70,17 @@ type StoreService struct {
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
 	stats         stats.Collector
+	cfgMu         sync.RWMutex
 	cfg           Config
 	endpoint      *http.Server
+	catalogAccess *lakefs.CatalogAccess
 	serverAddress string
+	onRunFailure  RunFailureFunc
 }
 
+// RunFailureFunc is called with the error of each run whose hooks failed
+type RunFailureFunc func(ctx context.Context, record graveler.HookRecord, err error)
+
 type Task struct {
 	RunID     string
 	HookRunID string
@@ -222,6 +236,9 @@ type Service interface {
 
 func NewService(ctx context.Context, store Store, source Source, writer OutputWriter, idGen IDGenerator, stats stats.Collector, cfg Config, serverAddress string) *StoreService {
 	ctx, cancel := context.WithCancel(ctx)
+	if cfg.Central.Repository != "" {
+		source = NewCentralSource(source, cfg.Central.Repository, cfg.Central.Branch)
+	}
 	return &StoreService{
 		Store:         store,
 		Source:        source,
@@ -236,6 +253,19 @@ func NewService(ctx context.Context, store Store, source Source, writer OutputWr
 	}
 }
 
+// SetWebhookAllowedEndpoints replaces the URL prefixes hooks may call. Applies to hooks of runs started after the call.
+func (s *StoreService) SetWebhookAllowedEndpoints(endpoints []string) {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+	s.cfg.Webhook.AllowedEndpoints = endpoints
+}
+
+func (s *StoreService) config() Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
 func (s *StoreService) Stop() {
 	s.cancel()
 	s.wg.Wait()
@@ -245,6 +275,16 @@ func (s *StoreService) SetEndpoint(h *http.Server) {
 	s.endpoint = h
 }
 
+// SetCatalogAccess lets Lua hooks read the catalog in-process through the lakefs/catalog library
+func (s *StoreService) SetCatalogAccess(access *lakefs.CatalogAccess) {
+	s.catalogAccess = access
+}
+
+// SetRunFailureFunc sets fn to be called when the hooks of a run fail. Should be set before runs start.
+func (s *StoreService) SetRunFailureFunc(fn RunFailureFunc) {
+	s.onRunFailure = fn
+}
+
 func (s *StoreService) asyncRun(ctx context.Context, record graveler.HookRecord) {
 	s.wg.Add(1)
 	go func() {
@@ -272,7 +312,8 @@ func (s *StoreService) asyncRun(ctx context.Context, record graveler.HookRecord)
 
 // Run load and run actions based on the event information
 func (s *StoreService) Run(ctx context.Context, record graveler.HookRecord) error {
-	if !s.cfg.Enabled {
+	cfg := s.config()
+	if !cfg.Enabled {
 		logging.FromContext(ctx).WithField("record", record).Debug("Hooks are disabled, skipping hooks execution")
 		return nil
 	}
@@ -289,12 +330,15 @@ func (s *StoreService) Run(ctx context.Context, record graveler.HookRecord) erro
 	}
 
 	// allocate and run hooks
-	tasks, err := s.allocateTasks(record.RunID, actions)
+	tasks, err := s.allocateTasks(cfg, record.RunID, actions)
 	if err != nil {
 		return err
 	}
 
 	runErr := s.runTasks(ctx, record, tasks)
+	if runErr != nil && s.onRunFailure != nil {
+		s.onRunFailure(ctx, record, runErr)
+	}
 
 	// keep results before returning an error (if any)
 	err = s.saveRunInformation(ctx, record, tasks)
@@ -313,15 +357,18 @@ func (s *StoreService) loadMatchedActions(ctx context.Context, record graveler.H
 	return MatchedActions(actions, spec)
 }
 
-func (s *StoreService) allocateTasks(runID string, actions []*Action) ([][]*Task, error) {
+func (s *StoreService) allocateTasks(cfg Config, runID string, actions []*Action) ([][]*Task, error) {
 	var tasks [][]*Task
 	for actionIdx, action := range actions {
 		var actionTasks []*Task
 		for hookIdx, hook := range action.Hooks {
-			h, err := NewHook(hook, action, s.cfg, s.endpoint, s.serverAddress, s.stats)
+			h, err := NewHook(hook, action, cfg, s.endpoint, s.serverAddress, s.stats)
 			if err != nil {
 				return nil, err
 			}
+			if luaHook, ok := h.(*LuaHook); ok {
+				luaHook.CatalogAccess = s.catalogAccess
+			}
 			task := &Task{
 				RunID:     runID,
 				HookRunID: NewHookRunID(actionIdx, hookIdx),
@@ -343,6 +343,36 @@ hooks:
 	}
 }
 
+func TestHookEndpointNotAllowed(t *testing.T) {
+	ctx := context.Background()
+	testOutputWriter, ctrl, _, record := setupTest(t)
+
+	actionContent := `name: test action
+on:
+  pre-commit: {}
+hooks:
+  - id: webhook_id
+    type: webhook
+    properties:
+      url: "http://wontsendrequesthere/webhook"
+`
+	testSource := mock.NewMockSource(ctrl)
+	testSource.EXPECT().
+		List(ctx, record).
+		Return([]string{"act.yaml"}, nil)
+	testSource.EXPECT().
+		Load(ctx, record, "act.yaml").
+		Return([]byte(actionContent), nil)
+
+	mockStatsCollector := NewActionStatsMockCollector()
+	actionsService := GetKVService(t, ctx, testSource, testOutputWriter, &mockStatsCollector, true).(*actions.StoreService)
+	defer actionsService.Stop()
+
+	actionsService.SetWebhookAllowedEndpoints([]string{"https://hooks.example.com/"})
+	require.ErrorIs(t, actionsService.Run(ctx, record), actions.ErrHookEndpointNotAllowed)
+	require.Equal(t, 0, mockStatsCollector.Hits["pre-commit"])
+}
+
 func TestHookIf(t *testing.T) {
 	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
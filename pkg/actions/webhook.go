@@ -45,6 +45,9 @@ func NewWebhook(h ActionHook, action *Action, cfg Config, e *http.Server, _ stri
 	if !ok {
 		return nil, fmt.Errorf("webhook url must be string: %w", errWebhookWrongFormat)
 	}
+	if err := checkEndpointAllowed(cfg, webhookURL); err != nil {
+		return nil, err
+	}
 
 	envGetter := NewEnvironmentVariableGetter(cfg.Env.Enabled, cfg.Env.Prefix)
 	queryParams, err := extractQueryParams(h.Properties, envGetter)
@@ -57,6 +60,11 @@ func NewWebhook(h ActionHook, action *Action, cfg Config, e *http.Server, _ stri
 		return nil, fmt.Errorf("extracting headers: %w", err)
 	}
 
+	payloadVersion, err := negotiatePayloadVersion(h.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("webhook payload version: %w", err)
+	}
+
 	requestTimeout := webhookClientDefaultTimeout
 	if timeoutDuration, ok := h.Properties[webhookTimeoutPropertyKey]; ok {
 		if timeout, ok := timeoutDuration.(string); ok && len(timeout) > 0 {
@@ -70,10 +78,11 @@ func NewWebhook(h ActionHook, action *Action, cfg Config, e *http.Server, _ stri
 
 	return &Webhook{
 		HookBase: HookBase{
-			ID:         h.ID,
-			ActionName: action.Name,
-			Config:     cfg,
-			Endpoint:   e,
+			ID:             h.ID,
+			ActionName:     action.Name,
+			Config:         cfg,
+			Endpoint:       e,
+			PayloadVersion: payloadVersion,
 		},
 		Timeout:     requestTimeout,
 		URL:         webhookURL,
@@ -89,7 +98,7 @@ func (w *Webhook) Run(ctx context.Context, record graveler.HookRecord, buf *byte
 		WithField("event_type", record.EventType).
 		Debug("hook action executing")
 
-	eventData, err := marshalEventInformation(w.ActionName, w.ID, record)
+	eventData, err := marshalEventInformation(ctx, w.PayloadVersion, w.ActionName, w.ID, record)
 	if err != nil {
 		return err
 	}
@@ -101,6 +110,7 @@ func (w *Webhook) Run(ctx context.Context, record graveler.HookRecord, buf *byte
 		return err
 	}
 	w.Headers["Content-Type"] = SecureString{val: "application/json"}
+	w.Headers[PayloadVersionHeader] = SecureString{val: w.PayloadVersion}
 
 	buf.WriteString("Query Params:\n")
 	q := req.URL.Query()
@@ -141,6 +151,7 @@ func doHTTPRequestWithLog(ctx context.Context, req *http.Request, buf *bytes.Buf
 // returns the response status code or -1 on error
 func doHTTPRequestResponseWithLog(ctx context.Context, req *http.Request, respJSON interface{}, buf *bytes.Buffer, timeout time.Duration) (int, error) {
 	req = req.WithContext(ctx)
+	setTraceHeaders(ctx, req.Header)
 
 	client := &http.Client{
 		Timeout: timeout,
@@ -13,6 +13,7 @@ import (
 	"github.com/getkin/kin-openapi/routers/legacy"
 	"github.com/go-openapi/swag"
 	"github.com/gorilla/sessions"
+	"github.com/treeverse/lakefs/pkg/accesslog"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
 	"github.com/treeverse/lakefs/pkg/auth"
 	"github.com/treeverse/lakefs/pkg/auth/model"
@@ -76,6 +77,7 @@ func GenericAuthMiddleware(logger logging.Logger, authenticator auth.Authenticat
 			if user != nil {
 				ctx := logging.AddFields(r.Context(), logging.Fields{logging.UserFieldKey: user.Username})
 				r = r.WithContext(auth.WithUser(ctx, user))
+				accesslog.SetPrincipal(ctx, user.Username)
 			}
 			next.ServeHTTP(w, r)
 		})
@@ -107,6 +109,7 @@ func AuthMiddleware(logger logging.Logger, swagger *openapi3.Swagger, authentica
 			if user != nil {
 				ctx := logging.AddFields(r.Context(), logging.Fields{logging.UserFieldKey: user.Username})
 				r = r.WithContext(auth.WithUser(ctx, user))
+				accesslog.SetPrincipal(ctx, user.Username)
 			}
 			next.ServeHTTP(w, r)
 		})
@@ -377,6 +380,16 @@ func userFromOIDC(ctx context.Context, logger logging.Logger, authService auth.S
 	return enhanceWithFriendlyName(ctx, user, friendlyName, false, authService, logger), nil
 }
 
+// UserByToken returns the user of a lakeFS login token, for services authenticating requests outside the API
+func UserByToken(ctx context.Context, logger logging.Logger, authService auth.Service, tokenString string) (*model.User, error) {
+	return userByToken(ctx, logger, authService, tokenString)
+}
+
+// UserByAuth returns the user of access key credentials, for services authenticating requests outside the API
+func UserByAuth(ctx context.Context, logger logging.Logger, authenticator auth.Authenticator, authService auth.Service, accessKey string, secretKey string) (*model.User, error) {
+	return userByAuth(ctx, logger, authenticator, authService, accessKey, secretKey)
+}
+
 func userByToken(ctx context.Context, logger logging.Logger, authService auth.Service, tokenString string) (*model.User, error) {
 	claims, err := auth.VerifyToken(authService.SecretStore().SharedSecret(), tokenString)
 	// make sure no audience is set for login token
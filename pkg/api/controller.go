@@ -35,11 +35,15 @@ import (
 	"github.com/treeverse/lakefs/pkg/catalog"
 	"github.com/treeverse/lakefs/pkg/cloud"
 	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/gateway"
 	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/retention"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/kv"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/notifications"
 	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/preview"
 	"github.com/treeverse/lakefs/pkg/samplerepo"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/upload"
@@ -84,6 +88,11 @@ type Migrator interface {
 	Migrate(ctx context.Context) error
 }
 
+// ConfigReloader re-reads the configuration and applies the settings that can change without a restart
+type ConfigReloader interface {
+	Reload(ctx context.Context) error
+}
+
 type Controller struct {
 	Config                *config.Config
 	Catalog               *catalog.Catalog
@@ -101,11 +110,15 @@ type Controller struct {
 	sessionStore          sessions.Store
 	PathProvider          upload.PathProvider
 	usageReporter         stats.UsageReporterOperations
+	ConfigReloader        ConfigReloader
+	AnonymousRead         *auth.AnonymousReadPolicy
+	Notifications         *notifications.Service
+	GatewayDenials        *gateway.DenialLog
 }
 
 var usageCounter = stats.NewUsageCounter()
 
-func NewController(cfg *config.Config, catalog *catalog.Catalog, authenticator auth.Authenticator, authService auth.Service, authenticationService authentication.Service, blockAdapter block.Adapter, metadataManager auth.MetadataManager, migrator Migrator, collector stats.Collector, cloudMetadataProvider cloud.MetadataProvider, actions actionsHandler, auditChecker AuditChecker, logger logging.Logger, sessionStore sessions.Store, pathProvider upload.PathProvider, usageReporter stats.UsageReporterOperations) *Controller {
+func NewController(cfg *config.Config, catalog *catalog.Catalog, authenticator auth.Authenticator, authService auth.Service, authenticationService authentication.Service, blockAdapter block.Adapter, metadataManager auth.MetadataManager, migrator Migrator, collector stats.Collector, cloudMetadataProvider cloud.MetadataProvider, actions actionsHandler, auditChecker AuditChecker, logger logging.Logger, sessionStore sessions.Store, pathProvider upload.PathProvider, usageReporter stats.UsageReporterOperations, configReloader ConfigReloader, anonymousRead *auth.AnonymousReadPolicy, notificationsService *notifications.Service, gatewayDenials *gateway.DenialLog) *Controller {
 	return &Controller{
 		Config:                cfg,
 		Catalog:               catalog,
@@ -123,6 +136,10 @@ func NewController(cfg *config.Config, catalog *catalog.Catalog, authenticator a
 		sessionStore:          sessionStore,
 		PathProvider:          pathProvider,
 		usageReporter:         usageReporter,
+		ConfigReloader:        configReloader,
+		AnonymousRead:         anonymousRead,
+		Notifications:         notificationsService,
+		GatewayDenials:        gatewayDenials,
 	}
 }
 
@@ -342,6 +359,12 @@ func (c *Controller) CompletePresignMultipartUpload(w http.ResponseWriter, r *ht
 		writeError(w, r, http.StatusBadRequest, "parts are required")
 		return
 	}
+	if body.UserMetadata != nil {
+		if err := catalog.ValidateUserMetadata(body.UserMetadata.AdditionalProperties); err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
 
 	// verify physical address
 	repo, err := c.Catalog.GetRepository(ctx, repository)
@@ -697,8 +720,8 @@ func (c *Controller) GetPhysicalAddress(w http.ResponseWriter, r *http.Request,
 			Identifier:       address,
 			IdentifierType:   block.IdentifierTypeRelative,
 		}, block.PreSignModeWrite)
-		if err != nil {
-			writeError(w, r, http.StatusInternalServerError, err)
+		// repositories with an encryption domain key do not support pre-signed writes
+		if c.handleAPIError(ctx, w, r, err) {
 			return
 		}
 		response.PresignedUrl = &preSignedURL
@@ -791,6 +814,10 @@ func (c *Controller) LinkPhysicalAddress(w http.ResponseWriter, r *http.Request,
 		Checksum(checksum).
 		ContentType(swag.StringValue(body.ContentType))
 	if body.UserMetadata != nil {
+		if err := catalog.ValidateUserMetadata(body.UserMetadata.AdditionalProperties); err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
 		entryBuilder.Metadata(body.UserMetadata.AdditionalProperties)
 	}
 	entry := entryBuilder.Build()
@@ -1755,6 +1782,73 @@ func (c *Controller) ListUserPolicies(w http.ResponseWriter, r *http.Request, us
 	writeResponse(w, r, http.StatusOK, response)
 }
 
+var simulationResults = map[auth.CheckResult]string{
+	auth.CheckAllow:   "allow",
+	auth.CheckDeny:    "deny",
+	auth.CheckNeutral: "neutral",
+}
+
+func serializeSimulationStatement(s auth.SimulationStatement) apigen.AuthorizationStatement {
+	return apigen.AuthorizationStatement{
+		Policy: s.Policy,
+		Statement: apigen.Statement{
+			Action:   s.Statement.Action,
+			Effect:   s.Statement.Effect,
+			Resource: s.Statement.Resource,
+		},
+	}
+}
+
+func (c *Controller) SimulateAuthorization(w http.ResponseWriter, r *http.Request, body apigen.SimulateAuthorizationJSONRequestBody) {
+	if c.Config.IsAuthUISimplified() {
+		writeError(w, r, http.StatusNotImplemented, "Not implemented")
+		return
+	}
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadUserAction,
+			Resource: permissions.UserArn(body.UserId),
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "simulate_authorization", r, "", "", "")
+	if body.Action == "" || body.Resource == "" {
+		writeError(w, r, http.StatusBadRequest, "action and resource are required")
+		return
+	}
+	if _, err := c.Auth.GetUser(ctx, body.UserId); c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	policies, _, err := c.Auth.ListEffectivePolicies(ctx, body.UserId, &model.PaginationParams{
+		After:  "", // all
+		Amount: -1, // all
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	result := auth.SimulatePermission(permissions.Permission{
+		Action:   body.Action,
+		Resource: body.Resource,
+	}, body.UserId, policies)
+	response := apigen.AuthorizationSimulationResult{
+		Allowed:  result.Result == auth.CheckAllow,
+		Result:   simulationResults[result.Result],
+		Matching: make([]apigen.AuthorizationStatement, 0, len(result.Matching)),
+	}
+	if result.Decisive != nil {
+		decisive := serializeSimulationStatement(*result.Decisive)
+		response.Decisive = &decisive
+	}
+	for _, s := range result.Matching {
+		response.Matching = append(response.Matching, serializeSimulationStatement(s))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
 func (c *Controller) DetachPolicyFromUser(w http.ResponseWriter, r *http.Request, userID, policyID string) {
 	if c.Config.IsAuthUISimplified() {
 		writeError(w, r, http.StatusNotImplemented, "Not implemented")
@@ -1833,1591 +1927,1844 @@ func (c *Controller) GetConfig(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, r, http.StatusOK, apigen.Config{StorageConfig: &storageCfg, VersionConfig: &versionConfig})
 }
 
-func (c *Controller) GetStorageConfig(w http.ResponseWriter, r *http.Request) {
+func (c *Controller) ReloadConfig(w http.ResponseWriter, r *http.Request) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadConfigAction,
+			Action:   permissions.ReloadConfigAction,
 			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
+	if c.ConfigReloader == nil {
+		writeError(w, r, http.StatusNotImplemented, "config reload is not supported")
+		return
+	}
 
-	writeResponse(w, r, http.StatusOK, c.getStorageConfig())
+	ctx := r.Context()
+	c.LogAction(ctx, "reload_config", r, "", "", "")
+	err := c.ConfigReloader.Reload(ctx)
+	if errors.Is(err, config.ErrBadConfiguration) {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) getStorageConfig() apigen.StorageConfig {
-	info := c.BlockAdapter.GetStorageNamespaceInfo()
-	defaultNamespacePrefix := swag.String(info.DefaultNamespacePrefix)
-	if c.Config.Blockstore.DefaultNamespacePrefix != nil {
-		defaultNamespacePrefix = c.Config.Blockstore.DefaultNamespacePrefix
+func loggingConfig() apigen.LoggingConfig {
+	sampling := logging.Sampling()
+	return apigen.LoggingConfig{
+		Level:        logging.Level(),
+		ModuleLevels: logging.ModuleLevels(),
+		Sampling: apigen.LogSampling{
+			Enabled:    sampling.Enabled,
+			Initial:    apiutil.Ptr(sampling.Initial),
+			Thereafter: apiutil.Ptr(sampling.Thereafter),
+			IntervalMs: apiutil.Ptr(sampling.Interval.Milliseconds()),
+		},
 	}
-	return apigen.StorageConfig{
-		BlockstoreType:                   c.Config.Blockstore.Type,
-		BlockstoreNamespaceValidityRegex: info.ValidityRegex,
-		BlockstoreNamespaceExample:       info.Example,
-		DefaultNamespacePrefix:           defaultNamespacePrefix,
-		PreSignSupport:                   info.PreSignSupport,
-		PreSignSupportUi:                 info.PreSignSupportUI,
-		ImportSupport:                    info.ImportSupport,
-		ImportValidityRegex:              info.ImportValidityRegex,
-		PreSignMultipartUpload:           swag.Bool(info.PreSignSupportMultipart),
+}
+
+func (c *Controller) GetLoggingConfig(w http.ResponseWriter, r *http.Request) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadConfigAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
 	}
+	writeResponse(w, r, http.StatusOK, loggingConfig())
 }
 
-func (c *Controller) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	writeResponse(w, r, http.StatusNoContent, nil)
+func (c *Controller) SetLoggingConfig(w http.ResponseWriter, r *http.Request, body apigen.SetLoggingConfigJSONRequestBody) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UpdateLoggingAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "set_logging_config", r, "", "", "")
+	if err := logging.SetLevels(body.Level, body.ModuleLevels); err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	logging.SetSampling(logging.SamplingConfig{
+		Enabled:    body.Sampling.Enabled,
+		Initial:    swag.IntValue(body.Sampling.Initial),
+		Thereafter: swag.IntValue(body.Sampling.Thereafter),
+		Interval:   time.Duration(swag.Int64Value(body.Sampling.IntervalMs)) * time.Millisecond,
+	})
+	c.Logger.WithFields(logging.Fields{"level": body.Level, "module_levels": body.ModuleLevels}).Info("Update log levels")
+	writeResponse(w, r, http.StatusOK, loggingConfig())
 }
 
-func (c *Controller) ListRepositories(w http.ResponseWriter, r *http.Request, params apigen.ListRepositoriesParams) {
+func (c *Controller) ListGatewayDenials(w http.ResponseWriter, r *http.Request, params apigen.ListGatewayDenialsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListRepositoriesAction,
+			Action:   permissions.ReadGatewayDenialsAction,
 			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
+	if c.GatewayDenials == nil {
+		writeError(w, r, http.StatusNotImplemented, "gateway denials log is disabled")
+		return
+	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_repos", r, "", "", "")
+	c.LogAction(ctx, "list_gateway_denials", r, "", "", "")
+
+	denials := c.GatewayDenials.Recent(paginationAmount(params.Amount))
+	results := make([]apigen.GatewayDenial, 0, len(denials))
+	for _, d := range denials {
+		missing := make([]apigen.GatewayDeniedPermission, 0, len(d.MissingPermissions))
+		for _, p := range d.MissingPermissions {
+			missing = append(missing, apigen.GatewayDeniedPermission{Action: p.Action, Resource: p.Resource})
+		}
+		results = append(results, apigen.GatewayDenial{
+			Time:               d.Time.Unix(),
+			RequestId:          d.RequestID,
+			Reason:             apigen.GatewayDenialReason(d.Reason),
+			User:               swag.String(d.User),
+			AccessKeyId:        swag.String(d.AccessKeyID),
+			Operation:          swag.String(d.Operation),
+			Method:             d.Method,
+			Host:               d.Host,
+			Path:               d.Path,
+			Repository:         swag.String(d.Repository),
+			MissingPermissions: &missing,
+			Error:              swag.String(d.Error),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, apigen.GatewayDenialList{Results: results})
+}
+
+func attributionReportResponse(report *catalog.AttributionReport) apigen.AttributionReport {
+	response := apigen.AttributionReport{
+		Id:              report.ID,
+		WindowStart:     report.WindowStart.Unix(),
+		WindowEnd:       report.WindowEnd.Unix(),
+		CreatedAt:       report.CreatedAt.Unix(),
+		PhysicalAddress: report.PhysicalAddress,
+	}
+	if report.Rows != nil {
+		rows := make([]apigen.AttributionRow, 0, len(report.Rows))
+		for _, row := range report.Rows {
+			rows = append(rows, apigen.AttributionRow{
+				Repository:   row.Repository,
+				BranchPrefix: row.BranchPrefix,
+				User:         row.User,
+				Requests:     row.Requests,
+				StorageBytes: row.StorageBytes,
+			})
+		}
+		response.Rows = &rows
+	}
+	return response
+}
+
+func (c *Controller) ListAttributionReports(w http.ResponseWriter, r *http.Request, params apigen.ListAttributionReportsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadUsageReportAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_attribution_reports", r, "", "", "")
 
-	repos, hasMore, err := c.Catalog.ListRepositories(ctx, paginationAmount(params.Amount), paginationPrefix(params.Prefix), paginationAfter(params.After))
+	reports, hasMore, err := c.Catalog.ListAttributionReports(ctx, paginationAmount(params.Amount), paginationAfter(params.After))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	results := make([]apigen.Repository, 0, len(repos))
-	for _, repo := range repos {
-		creationDate := repo.CreationDate.Unix()
-		r := apigen.Repository{
-			Id:               repo.Name,
-			StorageNamespace: repo.StorageNamespace,
-			CreationDate:     creationDate,
-			DefaultBranch:    repo.DefaultBranch,
-			ReadOnly:         swag.Bool(repo.ReadOnly),
-		}
-		results = append(results, r)
+	results := make([]apigen.AttributionReport, 0, len(reports))
+	for _, report := range reports {
+		results = append(results, attributionReportResponse(report))
 	}
-	repositoryList := apigen.RepositoryList{
-		Pagination: paginationFor(hasMore, results, "Id"),
+	response := apigen.AttributionReportList{
 		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Id"),
 	}
-	writeResponse(w, r, http.StatusOK, repositoryList)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, body apigen.CreateRepositoryJSONRequestBody, params apigen.CreateRepositoryParams) {
+func (c *Controller) GetAttributionReport(w http.ResponseWriter, r *http.Request, reportID string) {
 	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadUsageReportAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_attribution_report", r, "", "", "")
+
+	report, err := c.Catalog.GetAttributionReport(ctx, reportID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, attributionReportResponse(report))
+}
+
+func datasetReleaseResponse(release *catalog.DatasetRelease) apigen.DatasetRelease {
+	sources := make([]apigen.DatasetReleaseSource, 0, len(release.Sources))
+	for _, source := range release.Sources {
+		sources = append(sources, apigen.DatasetReleaseSource{
+			Repository: source.Repository,
+			Ref:        source.Ref,
+			CommitId:   source.CommitID,
+			Prefix:     source.Prefix,
+		})
+	}
+	return apigen.DatasetRelease{
+		Dataset:      release.Dataset,
+		Version:      release.Version,
+		Description:  optionalString(release.Description),
+		Sources:      sources,
+		CreatedBy:    optionalString(release.CreatedBy),
+		CreationDate: release.CreatedAt.Unix(),
+	}
+}
+
+func (c *Controller) CreateDatasetRelease(w http.ResponseWriter, r *http.Request, body apigen.CreateDatasetReleaseJSONRequestBody, dataset string) {
+	// releasing data requires reading it
+	perms := permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateRepositoryAction,
-					Resource: permissions.RepoArn(body.Name),
+					Action:   permissions.CreateDatasetReleaseAction,
+					Resource: permissions.DatasetArn(dataset),
 				},
 			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.AttachStorageNamespaceAction,
-					Resource: permissions.StorageNamespace(body.StorageNamespace),
-				},
+		},
+	}
+	sources := make([]catalog.DatasetReleaseSource, 0, len(body.Sources))
+	for _, source := range body.Sources {
+		perms.Nodes = append(perms.Nodes, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(source.Repository),
 			},
+		})
+		sources = append(sources, catalog.DatasetReleaseSource{
+			Repository: source.Repository,
+			Ref:        source.Ref,
+			Prefix:     swag.StringValue(source.Prefix),
+		})
+	}
+	if !c.authorize(w, r, perms) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_dataset_release", r, "", "", "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	release, err := c.Catalog.CreateDatasetRelease(ctx, catalog.DatasetRelease{
+		Dataset:     dataset,
+		Version:     body.Version,
+		Sources:     sources,
+		Description: swag.StringValue(body.Description),
+		CreatedBy:   user.Username,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, datasetReleaseResponse(release))
+}
+
+func (c *Controller) ListDatasetReleases(w http.ResponseWriter, r *http.Request, dataset string, params apigen.ListDatasetReleasesParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadDatasetReleaseAction,
+			Resource: permissions.DatasetArn(dataset),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
+	c.LogAction(ctx, "list_dataset_releases", r, "", "", "")
 
-	// Verify first if there is a repository definition.
-	// Return conflict if definition already exists, before
-	// creating the repository itself and ensuring (optional) storage namespace holds an object.
-	// Example will be by restoring a repository from a backup or previous bare repository.
-	_, err := c.Catalog.GetRepository(ctx, body.Name)
-	if err == nil {
-		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", graveler.ErrNotUnique))
+	releases, hasMore, err := c.Catalog.ListDatasetReleases(ctx, dataset, paginationAfter(params.After), paginationAmount(params.Amount))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.DatasetRelease, 0, len(releases))
+	for _, release := range releases {
+		results = append(results, datasetReleaseResponse(release))
+	}
+	writeResponse(w, r, http.StatusOK, apigen.DatasetReleaseList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Version"),
+	})
+}
+
+func (c *Controller) GetDatasetRelease(w http.ResponseWriter, r *http.Request, dataset, version string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadDatasetReleaseAction,
+			Resource: permissions.DatasetArn(dataset),
+		},
+	}) {
 		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_dataset_release", r, "", "", "")
 
-	sampleData := swag.BoolValue(body.SampleData)
-	c.LogAction(ctx, "create_repo", r, body.Name, "", "")
-	if sampleData {
-		c.LogAction(ctx, "repo_sample_data", r, body.Name, "", "")
+	release, err := c.Catalog.GetDatasetRelease(ctx, dataset, version)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
+	writeResponse(w, r, http.StatusOK, datasetReleaseResponse(release))
+}
 
-	if err := c.validateStorageNamespace(body.StorageNamespace); err != nil {
+func (c *Controller) ResolveDatasetRelease(w http.ResponseWriter, r *http.Request, params apigen.ResolveDatasetReleaseParams) {
+	dataset, _, err := catalog.ParseDatasetReference(params.Reference)
+	if err != nil {
 		writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadDatasetReleaseAction,
+			Resource: permissions.DatasetArn(dataset),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "resolve_dataset_release", r, "", "", "")
 
-	if !c.Config.Installation.AllowInterRegionStorage {
-		if err := block.ValidateInterRegionStorage(r.Context(), c.BlockAdapter, body.StorageNamespace); err != nil {
-			writeError(w, r, http.StatusBadRequest, err)
-			return
-		}
+	release, err := c.Catalog.ResolveDatasetRelease(ctx, params.Reference)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
+	writeResponse(w, r, http.StatusOK, datasetReleaseResponse(release))
+}
 
-	defaultBranch := swag.StringValue(body.DefaultBranch)
-	if defaultBranch == "" {
-		defaultBranch = "main"
+func organizationResponse(organization *catalog.Organization) apigen.Organization {
+	return apigen.Organization{
+		Id:           organization.ID,
+		Description:  optionalString(organization.Description),
+		KmsKeyId:     optionalString(organization.KMSKeyID),
+		CreatedBy:    optionalString(organization.CreatedBy),
+		CreationDate: organization.CreatedAt.Unix(),
 	}
+}
 
-	if swag.BoolValue(params.Bare) {
-		// create a bare repository. This is useful in conjunction with refs-restore to create a copy
-		// of another repository by e.g. copying the _lakefs/ directory and restoring its refs
-		repo, err := c.Catalog.CreateBareRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		response := apigen.Repository{
-			CreationDate:     repo.CreationDate.Unix(),
-			DefaultBranch:    repo.DefaultBranch,
-			Id:               repo.Name,
-			StorageNamespace: repo.StorageNamespace,
-		}
-		writeResponse(w, r, http.StatusCreated, response)
-		return
+// withEncryptionKeyPermission requires permission to use kmsKeyID in addition to node, for setting it as the key of
+// an encryption domain
+func withEncryptionKeyPermission(node permissions.Node, kmsKeyID string) permissions.Node {
+	if kmsKeyID == "" {
+		return node
+	}
+	return permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			node,
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.UseEncryptionKeyAction,
+					Resource: permissions.EncryptionKeyArn(kmsKeyID),
+				},
+			},
+		},
 	}
+}
 
-	if !swag.BoolValue(body.ReadOnly) {
-		if err := c.ensureStorageNamespace(ctx, body.StorageNamespace); err != nil {
-			var (
-				reason string
-				retErr error
-				urlErr *url.Error
-			)
-			switch {
-			case errors.As(err, &urlErr) && urlErr.Op == "parse":
-				retErr = err
-				reason = "bad_url"
-			case errors.Is(err, block.ErrInvalidAddress):
-				retErr = fmt.Errorf("%w, must match: %s", err, c.BlockAdapter.BlockstoreType())
-				reason = "invalid_namespace"
-			case errors.Is(err, ErrStorageNamespaceInUse):
-				retErr = err
-				reason = "already_in_use"
-			default:
-				retErr = ErrFailedToAccessStorage
-				reason = "unknown"
-			}
-			c.Logger.
-				WithError(err).
-				WithField("storage_namespace", body.StorageNamespace).
-				WithField("reason", reason).
-				Warn("Could not access storage namespace")
-			writeError(w, r, http.StatusBadRequest, fmt.Errorf("failed to create repository: %w", retErr))
-			return
-		}
-	}
-
-	newRepo, err := c.Catalog.CreateRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
-	if err != nil {
-		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", err))
-		return
+// projectResponse returns project, with the number of its repositories unless withRepositories is false
+func projectResponse(project *catalog.Project, withRepositories bool) apigen.Project {
+	response := apigen.Project{
+		Organization:     project.Organization,
+		Id:               project.ID,
+		Description:      optionalString(project.Description),
+		RepositoryPrefix: catalog.ProjectRepositoryID(project.Organization, project.ID, ""),
+		MaxRepositories:  project.MaxRepositories,
+		KmsKeyId:         optionalString(project.KMSKeyID),
+		CreatedBy:        optionalString(project.CreatedBy),
+		CreationDate:     project.CreatedAt.Unix(),
 	}
-
-	if sampleData {
-		// add sample data, hooks, etc.
-		user, err := auth.GetUser(ctx)
-		if err != nil {
-			writeError(w, r, http.StatusUnauthorized, "missing user")
-			return
-		}
-
-		err = samplerepo.PopulateSampleRepo(ctx, newRepo, c.Catalog, c.PathProvider, c.BlockAdapter, user)
-		if err != nil {
-			c.handleAPIError(ctx, w, r, fmt.Errorf("error populating sample repository: %w", err))
-			return
-		}
-
-		err = samplerepo.AddBranchProtection(ctx, newRepo, c.Catalog)
-		if err != nil {
-			c.handleAPIError(ctx, w, r, fmt.Errorf("error adding branch protection to sample repository: %w", err))
-			return
-		}
+	if withRepositories {
+		response.Repositories = swag.Int(project.Repositories)
 	}
+	return response
+}
 
-	response := apigen.Repository{
-		CreationDate:     newRepo.CreationDate.Unix(),
-		DefaultBranch:    newRepo.DefaultBranch,
-		Id:               newRepo.Name,
-		StorageNamespace: newRepo.StorageNamespace,
-		ReadOnly:         swag.Bool(newRepo.ReadOnly),
+func (c *Controller) ListOrganizations(w http.ResponseWriter, r *http.Request, params apigen.ListOrganizationsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListOrganizationsAction,
+			Resource: permissions.All,
+		},
+	}) {
+		return
 	}
-	writeResponse(w, r, http.StatusCreated, response)
-}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_organizations", r, "", "", "")
 
-func (c *Controller) validateStorageNamespace(storageNamespace string) error {
-	validRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
-	storagePrefixRegex, err := regexp.Compile(validRegex)
-	if err != nil {
-		return fmt.Errorf("failed to compile validity regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	organizations, hasMore, err := c.Catalog.ListOrganizations(ctx, paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	if !storagePrefixRegex.MatchString(storageNamespace) {
-		return fmt.Errorf("failed to match required regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	results := make([]apigen.Organization, 0, len(organizations))
+	for _, organization := range organizations {
+		results = append(results, organizationResponse(organization))
 	}
-	return nil
+	writeResponse(w, r, http.StatusOK, apigen.OrganizationList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Id"),
+	})
 }
 
-func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespace string) error {
-	const (
-		dummyData    = "this is dummy data - created by lakeFS to check accessibility"
-		dummyObjName = "dummy"
-	)
-	dummyKey := fmt.Sprintf("%s/%s", c.Config.Committed.BlockStoragePrefix, dummyObjName)
-
-	objLen := int64(len(dummyData))
-
-	// check if the dummy file exist in the root of the storage namespace
-	// this serves two purposes, first, we maintain safety check for older lakeFS version.
-	// second, in scenarios where lakeFS shouldn't have access to the root namespace (i.e pre-sign URL only).
-	if c.Config.Graveler.EnsureReadableRootNamespace {
-		rootObj := block.ObjectPointer{
-			StorageNamespace: storageNamespace,
-			IdentifierType:   block.IdentifierTypeRelative,
-			Identifier:       dummyObjName,
-		}
-
-		if s, err := c.BlockAdapter.Get(ctx, rootObj); err == nil {
-			_ = s.Close()
-			return fmt.Errorf("found lakeFS objects in the storage namespace root(%s): %w",
-				storageNamespace, ErrStorageNamespaceInUse)
-		} else if !errors.Is(err, block.ErrDataNotFound) {
-			return err
-		}
-	}
-
-	// check if the dummy file exists
-	obj := block.ObjectPointer{
-		StorageNamespace: storageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       dummyKey,
+func (c *Controller) CreateOrganization(w http.ResponseWriter, r *http.Request, body apigen.CreateOrganizationJSONRequestBody) {
+	if !c.authorize(w, r, withEncryptionKeyPermission(permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateOrganizationAction,
+			Resource: permissions.OrganizationArn(body.Id),
+		},
+	}, swag.StringValue(body.KmsKeyId))) {
+		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_organization", r, "", "", "")
 
-	if s, err := c.BlockAdapter.Get(ctx, obj); err == nil {
-		_ = s.Close()
-		return fmt.Errorf("found lakeFS objects in the storage namespace(%s) key(%s): %w",
-			storageNamespace, obj.Identifier, ErrStorageNamespaceInUse)
-	} else if !errors.Is(err, block.ErrDataNotFound) {
-		return err
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
 	}
-
-	if err := c.BlockAdapter.Put(ctx, obj, objLen, strings.NewReader(dummyData), block.PutOpts{}); err != nil {
-		return err
+	organization, err := c.Catalog.CreateOrganization(ctx, catalog.Organization{
+		ID:          body.Id,
+		Description: swag.StringValue(body.Description),
+		KMSKeyID:    swag.StringValue(body.KmsKeyId),
+		CreatedBy:   user.Username,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-
-	_, err := c.BlockAdapter.Get(ctx, obj)
-	return err
+	writeResponse(w, r, http.StatusCreated, organizationResponse(organization))
 }
 
-func (c *Controller) DeleteRepository(w http.ResponseWriter, r *http.Request, repository string, params apigen.DeleteRepositoryParams) {
+func (c *Controller) GetOrganization(w http.ResponseWriter, r *http.Request, organization string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadOrganizationAction,
+			Resource: permissions.OrganizationArn(organization),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_repo", r, repository, "", "")
-	err := c.Catalog.DeleteRepository(ctx, repository, graveler.WithForce(swag.BoolValue(params.Force)))
+	c.LogAction(ctx, "get_organization", r, "", "", "")
+
+	org, err := c.Catalog.GetOrganization(ctx, organization)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusOK, organizationResponse(org))
 }
 
-func (c *Controller) GetRepository(w http.ResponseWriter, r *http.Request, repository string) {
-	if !c.authorize(w, r, permissions.Node{
+func (c *Controller) SetOrganizationEncryptionKey(w http.ResponseWriter, r *http.Request, body apigen.SetOrganizationEncryptionKeyJSONRequestBody, organization string) {
+	if !c.authorize(w, r, withEncryptionKeyPermission(permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.UpdateOrganizationAction,
+			Resource: permissions.OrganizationArn(organization),
 		},
-	}) {
+	}, body.KmsKeyId)) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_repo", r, repository, "", "")
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	switch {
-	case err == nil:
-		response := apigen.Repository{
-			CreationDate:     repo.CreationDate.Unix(),
-			DefaultBranch:    repo.DefaultBranch,
-			Id:               repo.Name,
-			StorageNamespace: repo.StorageNamespace,
-			ReadOnly:         swag.Bool(repo.ReadOnly),
-		}
-		writeResponse(w, r, http.StatusOK, response)
-
-	case errors.Is(err, graveler.ErrNotFound):
-		writeError(w, r, http.StatusNotFound, "repository not found")
-
-	case errors.Is(err, graveler.ErrRepositoryInDeletion):
-		writeError(w, r, http.StatusGone, err)
+	c.LogAction(ctx, "set_organization_encryption_key", r, "", "", "")
 
-	default:
-		writeError(w, r, http.StatusInternalServerError, err)
+	o, err := c.Catalog.SetOrganizationEncryptionKey(ctx, organization, body.KmsKeyId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
+	writeResponse(w, r, http.StatusOK, organizationResponse(o))
 }
 
-func (c *Controller) GetRepositoryMetadata(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) DeleteOrganization(w http.ResponseWriter, r *http.Request, organization string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DeleteOrganizationAction,
+			Resource: permissions.OrganizationArn(organization),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_repo_metadata", r, repository, "", "")
-	metadata, err := c.Catalog.GetRepositoryMetadata(ctx, repository)
+	c.LogAction(ctx, "delete_organization", r, "", "", "")
+
+	err := c.Catalog.DeleteOrganization(ctx, organization)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusOK, apigen.RepositoryMetadata{AdditionalProperties: metadata})
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) SetRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.SetRepositoryMetadataJSONRequestBody, repository string) {
+func (c *Controller) ListProjects(w http.ResponseWriter, r *http.Request, organization string, params apigen.ListProjectsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.UpdateRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ListProjectsAction,
+			Resource: permissions.OrganizationArn(organization),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "update_repo_metadata", r, repository, "", "")
-	err := c.Catalog.UpdateRepositoryMetadata(ctx, repository, body.Metadata.AdditionalProperties)
+	c.LogAction(ctx, "list_projects", r, "", "", "")
+
+	projects, hasMore, err := c.Catalog.ListProjects(ctx, organization, paginationAmount(params.Amount), paginationAfter(params.After))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	results := make([]apigen.Project, 0, len(projects))
+	for _, project := range projects {
+		results = append(results, projectResponse(project, false))
+	}
+	writeResponse(w, r, http.StatusOK, apigen.ProjectList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Id"),
+	})
 }
 
-func (c *Controller) DeleteRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.DeleteRepositoryMetadataJSONRequestBody, repository string) {
-	if !c.authorize(w, r, permissions.Node{
+func (c *Controller) CreateProject(w http.ResponseWriter, r *http.Request, body apigen.CreateProjectJSONRequestBody, organization string) {
+	if !c.authorize(w, r, withEncryptionKeyPermission(permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.UpdateRepositoryAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.CreateProjectAction,
+			Resource: permissions.ProjectArn(organization, body.Id),
 		},
-	}) {
+	}, swag.StringValue(body.KmsKeyId))) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_repo_metadata", r, repository, "", "")
-	err := c.Catalog.DeleteRepositoryMetadata(ctx, repository, body.Keys)
+	c.LogAction(ctx, "create_project", r, "", "", "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	project, err := c.Catalog.CreateProject(ctx, catalog.Project{
+		Organization:    organization,
+		ID:              body.Id,
+		Description:     swag.StringValue(body.Description),
+		MaxRepositories: swag.IntValue(body.MaxRepositories),
+		KMSKeyID:        swag.StringValue(body.KmsKeyId),
+		CreatedBy:       user.Username,
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusCreated, projectResponse(project, true))
 }
 
-func (c *Controller) GetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) GetProject(w http.ResponseWriter, r *http.Request, organization, project string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.GetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadProjectAction,
+			Resource: permissions.ProjectArn(organization, project),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	rules, eTag, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	c.LogAction(ctx, "get_project", r, "", "", "")
+
+	p, err := c.Catalog.GetProject(ctx, organization, project)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	resp := make([]*apigen.BranchProtectionRule, 0, len(rules.BranchPatternToBlockedActions))
-	for pattern := range rules.BranchPatternToBlockedActions {
-		resp = append(resp, &apigen.BranchProtectionRule{
-			Pattern: pattern,
-		})
-	}
-	w.Header().Set("ETag", swag.StringValue(eTag))
-	writeResponse(w, r, http.StatusOK, resp)
+	writeResponse(w, r, http.StatusOK, projectResponse(p, true))
 }
 
-func (c *Controller) SetBranchProtectionRules(w http.ResponseWriter, r *http.Request, body apigen.SetBranchProtectionRulesJSONRequestBody, repository string, params apigen.SetBranchProtectionRulesParams) {
+func (c *Controller) SetProjectQuota(w http.ResponseWriter, r *http.Request, body apigen.SetProjectQuotaJSONRequestBody, organization, project string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.UpdateProjectAction,
+			Resource: permissions.ProjectArn(organization, project),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
+	c.LogAction(ctx, "set_project_quota", r, "", "", "")
 
-	// For now, all protected branches use the same default set of blocked actions. In the future this set will be user configurable.
-	blockedActions := []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT}
-
-	rules := &graveler.BranchProtectionRules{
-		BranchPatternToBlockedActions: make(map[string]*graveler.BranchProtectionBlockedActions),
-	}
-	for _, r := range body {
-		rules.BranchPatternToBlockedActions[r.Pattern] = &graveler.BranchProtectionBlockedActions{
-			Value: blockedActions,
-		}
-	}
-	err := c.Catalog.SetBranchProtectionRules(ctx, repository, rules, params.IfMatch)
+	p, err := c.Catalog.SetProjectQuota(ctx, organization, project, body.MaxRepositories)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusOK, projectResponse(p, true))
 }
 
-func (c *Controller) DeleteGCRules(w http.ResponseWriter, r *http.Request, repository string) {
-	if !c.authorize(w, r, permissions.Node{
+func (c *Controller) SetProjectEncryptionKey(w http.ResponseWriter, r *http.Request, body apigen.SetProjectEncryptionKeyJSONRequestBody, organization, project string) {
+	if !c.authorize(w, r, withEncryptionKeyPermission(permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.UpdateProjectAction,
+			Resource: permissions.ProjectArn(organization, project),
 		},
-	}) {
+	}, body.KmsKeyId)) {
 		return
 	}
 	ctx := r.Context()
-	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, nil)
+	c.LogAction(ctx, "set_project_encryption_key", r, "", "", "")
+
+	p, err := c.Catalog.SetProjectEncryptionKey(ctx, organization, project, body.KmsKeyId)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	writeResponse(w, r, http.StatusOK, projectResponse(p, true))
 }
 
-func (c *Controller) GetGCRules(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) DeleteProject(w http.ResponseWriter, r *http.Request, organization, project string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.GetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DeleteProjectAction,
+			Resource: permissions.ProjectArn(organization, project),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	rules, err := c.Catalog.GetGarbageCollectionRules(ctx, repository)
+	c.LogAction(ctx, "delete_project", r, "", "", "")
+
+	err := c.Catalog.DeleteProject(ctx, organization, project)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	resp := apigen.GarbageCollectionRules{}
-	resp.DefaultRetentionDays = int(rules.DefaultRetentionDays)
-	for branchID, retentionDays := range rules.BranchRetentionDays {
-		resp.Branches = append(resp.Branches, apigen.GarbageCollectionRule{BranchId: branchID, RetentionDays: int(retentionDays)})
-	}
-	writeResponse(w, r, http.StatusOK, resp)
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) SetGCRules(w http.ResponseWriter, r *http.Request, body apigen.SetGCRulesJSONRequestBody, repository string) {
+func (c *Controller) GetStorageConfig(w http.ResponseWriter, r *http.Request) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadConfigAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
-	ctx := r.Context()
-	rules := &graveler.GarbageCollectionRules{
-		DefaultRetentionDays: int32(body.DefaultRetentionDays),
-		BranchRetentionDays:  make(map[string]int32),
-	}
-	for _, rule := range body.Branches {
-		rules.BranchRetentionDays[rule.BranchId] = int32(rule.RetentionDays)
+
+	writeResponse(w, r, http.StatusOK, c.getStorageConfig())
+}
+
+func (c *Controller) getStorageConfig() apigen.StorageConfig {
+	info := c.BlockAdapter.GetStorageNamespaceInfo()
+	defaultNamespacePrefix := swag.String(info.DefaultNamespacePrefix)
+	if c.Config.Blockstore.DefaultNamespacePrefix != nil {
+		defaultNamespacePrefix = c.Config.Blockstore.DefaultNamespacePrefix
 	}
-	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, rules)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	return apigen.StorageConfig{
+		BlockstoreType:                   c.Config.Blockstore.Type,
+		BlockstoreNamespaceValidityRegex: info.ValidityRegex,
+		BlockstoreNamespaceExample:       info.Example,
+		DefaultNamespacePrefix:           defaultNamespacePrefix,
+		PreSignSupport:                   info.PreSignSupport,
+		PreSignSupportUi:                 info.PreSignSupportUI,
+		ImportSupport:                    info.ImportSupport,
+		ImportValidityRegex:              info.ImportValidityRegex,
+		PreSignMultipartUpload:           swag.Bool(info.PreSignSupportMultipart),
 	}
+}
+
+func (c *Controller) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) ListRepositoryRuns(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListRepositoryRunsParams) {
+func (c *Controller) ListRepositories(w http.ResponseWriter, r *http.Request, params apigen.ListRepositoriesParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ListRepositoriesAction,
+			Resource: permissions.All,
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_repository_runs", r, repository, "", "")
+	c.LogAction(ctx, "list_repos", r, "", "", "")
 
-	_, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	listRepositories := c.Catalog.ListRepositories
+	if swag.BoolValue(params.Archived) {
+		listRepositories = c.Catalog.ListArchivedRepositories
 	}
-
-	branchName := swag.StringValue(params.Branch)
-	commitID := swag.StringValue(params.Commit)
-	runsIter, err := c.Actions.ListRunResults(ctx, repository, branchName, commitID, paginationAfter(params.After))
+	repos, hasMore, err := listRepositories(ctx, paginationAmount(params.Amount), paginationPrefix(params.Prefix), paginationAfter(params.After))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer runsIter.Close()
-
-	response := apigen.ActionRunList{
-		Pagination: apigen.Pagination{
-			MaxPerPage: DefaultMaxPerPage,
-		},
-		Results: make([]apigen.ActionRun, 0),
-	}
-	amount := paginationAmount(params.Amount)
-	for len(response.Results) < amount && runsIter.Next() {
-		val := runsIter.Value()
-		response.Results = append(response.Results, runResultToActionRun(val))
-	}
-	response.Pagination.Results = len(response.Results)
-	if runsIter.Next() {
-		response.Pagination.HasMore = true
-		if len(response.Results) > 0 {
-			lastRun := response.Results[len(response.Results)-1]
-			response.Pagination.NextOffset = lastRun.RunId
-		}
-	}
-	if err := runsIter.Err(); err != nil {
-		writeResponse(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	writeResponse(w, r, http.StatusOK, response)
-}
-
-func runResultToActionRun(val *actions.RunResult) apigen.ActionRun {
-	runResult := apigen.ActionRun{
-		Branch:    val.BranchID,
-		CommitId:  val.CommitID,
-		RunId:     val.RunID,
-		StartTime: val.StartTime,
-		EndTime:   &val.EndTime,
-		EventType: val.EventType,
+	results := make([]apigen.Repository, 0, len(repos))
+	for _, repo := range repos {
+		results = append(results, repositoryResponse(repo))
 	}
-	if val.Passed {
-		runResult.Status = actionStatusCompleted
-	} else {
-		runResult.Status = actionStatusFailed
+	repositoryList := apigen.RepositoryList{
+		Pagination: paginationFor(hasMore, results, "Id"),
+		Results:    results,
 	}
-	return runResult
+	writeResponse(w, r, http.StatusOK, repositoryList)
 }
 
-func (c *Controller) GetRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
+func (c *Controller) CreateRepository(w http.ResponseWriter, r *http.Request, body apigen.CreateRepositoryJSONRequestBody, params apigen.CreateRepositoryParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateRepositoryAction,
+					Resource: permissions.RepoArn(body.Name),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.AttachStorageNamespaceAction,
+					Resource: permissions.StorageNamespace(body.StorageNamespace),
+				},
+			},
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_get_run", r, repository, "", "")
-	_, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
 
-	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
-	if c.handleAPIError(ctx, w, r, err) {
+	// Verify first if there is a repository definition.
+	// Return conflict if definition already exists, before
+	// creating the repository itself and ensuring (optional) storage namespace holds an object.
+	// Example will be by restoring a repository from a backup or previous bare repository.
+	_, err := c.Catalog.GetRepository(ctx, body.Name)
+	if err == nil {
+		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", graveler.ErrNotUnique))
 		return
 	}
 
-	var status string
-	if runResult.Passed {
-		status = actionStatusCompleted
-	} else {
-		status = actionStatusFailed
-	}
-	response := apigen.ActionRun{
-		RunId:     runResult.RunID,
-		EventType: runResult.EventType,
-		StartTime: runResult.StartTime,
-		EndTime:   &runResult.EndTime,
-		Status:    status,
-		Branch:    runResult.BranchID,
-		CommitId:  runResult.CommitID,
+	sampleData := swag.BoolValue(body.SampleData)
+	c.LogAction(ctx, "create_repo", r, body.Name, "", "")
+	if sampleData {
+		c.LogAction(ctx, "repo_sample_data", r, body.Name, "", "")
 	}
-	writeResponse(w, r, http.StatusOK, response)
-}
 
-func (c *Controller) ListRunHooks(w http.ResponseWriter, r *http.Request, repository, runID string, params apigen.ListRunHooksParams) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
-			Resource: permissions.RepoArn(repository),
-		},
-	}) {
+	if err := c.validateStorageNamespace(body.StorageNamespace); err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "actions_list_run_hooks", r, repository, "", "")
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	if !c.Config.Installation.AllowInterRegionStorage {
+		if err := block.ValidateInterRegionStorage(r.Context(), c.BlockAdapter, body.StorageNamespace); err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
 	}
 
-	tasksIter, err := c.Actions.ListRunTaskResults(ctx, repo.Name, runID, paginationAfter(params.After))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	defaultBranch := swag.StringValue(body.DefaultBranch)
+	if defaultBranch == "" {
+		defaultBranch = "main"
 	}
-	defer tasksIter.Close()
 
-	response := apigen.HookRunList{
-		Results: make([]apigen.HookRun, 0),
-		Pagination: apigen.Pagination{
-			MaxPerPage: DefaultMaxPerPage,
-		},
-	}
-	amount := paginationAmount(params.Amount)
-	for len(response.Results) < amount && tasksIter.Next() {
-		val := tasksIter.Value()
-		hookRun := apigen.HookRun{
-			HookRunId: val.HookRunID,
-			Action:    val.ActionName,
-			HookId:    val.HookID,
-			StartTime: val.StartTime,
-			EndTime:   &val.EndTime,
+	if swag.BoolValue(params.Bare) {
+		// create a bare repository. This is useful in conjunction with refs-restore to create a copy
+		// of another repository by e.g. copying the _lakefs/ directory and restoring its refs
+		repo, err := c.Catalog.CreateBareRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
+		if c.handleAPIError(ctx, w, r, err) {
+			return
 		}
-		switch {
-		case val.Passed:
-			hookRun.Status = actionStatusCompleted
-		case val.StartTime.IsZero(): // assumes that database values are only stored after run is finished
-			hookRun.Status = actionStatusSkipped
-			hookRun.EndTime = nil
-		default:
-			hookRun.Status = actionStatusFailed
+		response := apigen.Repository{
+			CreationDate:     repo.CreationDate.Unix(),
+			DefaultBranch:    repo.DefaultBranch,
+			Id:               repo.Name,
+			StorageNamespace: repo.StorageNamespace,
 		}
-		response.Results = append(response.Results, hookRun)
+		writeResponse(w, r, http.StatusCreated, response)
+		return
 	}
-	if tasksIter.Next() {
-		response.Pagination.HasMore = true
-		if len(response.Results) > 0 {
-			lastHookRun := response.Results[len(response.Results)-1]
-			response.Pagination.NextOffset = lastHookRun.HookRunId
+
+	if !swag.BoolValue(body.ReadOnly) {
+		if err := c.ensureStorageNamespace(ctx, body.StorageNamespace); err != nil {
+			var (
+				reason string
+				retErr error
+				urlErr *url.Error
+			)
+			switch {
+			case errors.As(err, &urlErr) && urlErr.Op == "parse":
+				retErr = err
+				reason = "bad_url"
+			case errors.Is(err, block.ErrInvalidAddress):
+				retErr = fmt.Errorf("%w, must match: %s", err, c.BlockAdapter.BlockstoreType())
+				reason = "invalid_namespace"
+			case errors.Is(err, ErrStorageNamespaceInUse):
+				retErr = err
+				reason = "already_in_use"
+			default:
+				retErr = ErrFailedToAccessStorage
+				reason = "unknown"
+			}
+			c.Logger.
+				WithError(err).
+				WithField("storage_namespace", body.StorageNamespace).
+				WithField("reason", reason).
+				Warn("Could not access storage namespace")
+			writeError(w, r, http.StatusBadRequest, fmt.Errorf("failed to create repository: %w", retErr))
+			return
 		}
 	}
-	if err := tasksIter.Err(); err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+
+	newRepo, err := c.Catalog.CreateRepository(ctx, body.Name, body.StorageNamespace, defaultBranch, swag.BoolValue(body.ReadOnly))
+	if err != nil {
+		c.handleAPIError(ctx, w, r, fmt.Errorf("error creating repository: %w", err))
 		return
 	}
-	response.Pagination.Results = len(response.Results)
-	writeResponse(w, r, http.StatusOK, response)
-}
 
-func (c *Controller) GetRunHookOutput(w http.ResponseWriter, r *http.Request, repository, runID, hookRunID string) {
+	if sampleData {
+		// add sample data, hooks, etc.
+		user, err := auth.GetUser(ctx)
+		if err != nil {
+			writeError(w, r, http.StatusUnauthorized, "missing user")
+			return
+		}
+
+		err = samplerepo.PopulateSampleRepo(ctx, newRepo, c.Catalog, c.PathProvider, c.BlockAdapter, user)
+		if err != nil {
+			c.handleAPIError(ctx, w, r, fmt.Errorf("error populating sample repository: %w", err))
+			return
+		}
+
+		err = samplerepo.AddBranchProtection(ctx, newRepo, c.Catalog)
+		if err != nil {
+			c.handleAPIError(ctx, w, r, fmt.Errorf("error adding branch protection to sample repository: %w", err))
+			return
+		}
+	}
+
+	response := apigen.Repository{
+		CreationDate:     newRepo.CreationDate.Unix(),
+		DefaultBranch:    newRepo.DefaultBranch,
+		Id:               newRepo.Name,
+		StorageNamespace: newRepo.StorageNamespace,
+		ReadOnly:         swag.Bool(newRepo.ReadOnly),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) validateStorageNamespace(storageNamespace string) error {
+	validRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
+	storagePrefixRegex, err := regexp.Compile(validRegex)
+	if err != nil {
+		return fmt.Errorf("failed to compile validity regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	}
+	if !storagePrefixRegex.MatchString(storageNamespace) {
+		return fmt.Errorf("failed to match required regex %s: %w", validRegex, block.ErrInvalidNamespace)
+	}
+	return nil
+}
+
+func (c *Controller) ensureStorageNamespace(ctx context.Context, storageNamespace string) error {
+	const (
+		dummyData    = "this is dummy data - created by lakeFS to check accessibility"
+		dummyObjName = "dummy"
+	)
+	dummyKey := fmt.Sprintf("%s/%s", c.Config.Committed.BlockStoragePrefix, dummyObjName)
+
+	objLen := int64(len(dummyData))
+
+	// check if the dummy file exist in the root of the storage namespace
+	// this serves two purposes, first, we maintain safety check for older lakeFS version.
+	// second, in scenarios where lakeFS shouldn't have access to the root namespace (i.e pre-sign URL only).
+	if c.Config.Graveler.EnsureReadableRootNamespace {
+		rootObj := block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       dummyObjName,
+		}
+
+		if s, err := c.BlockAdapter.Get(ctx, rootObj); err == nil {
+			_ = s.Close()
+			return fmt.Errorf("found lakeFS objects in the storage namespace root(%s): %w",
+				storageNamespace, ErrStorageNamespaceInUse)
+		} else if !errors.Is(err, block.ErrDataNotFound) {
+			return err
+		}
+	}
+
+	// check if the dummy file exists
+	obj := block.ObjectPointer{
+		StorageNamespace: storageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       dummyKey,
+	}
+
+	if s, err := c.BlockAdapter.Get(ctx, obj); err == nil {
+		_ = s.Close()
+		return fmt.Errorf("found lakeFS objects in the storage namespace(%s) key(%s): %w",
+			storageNamespace, obj.Identifier, ErrStorageNamespaceInUse)
+	} else if !errors.Is(err, block.ErrDataNotFound) {
+		return err
+	}
+
+	if err := c.BlockAdapter.Put(ctx, obj, objLen, strings.NewReader(dummyData), block.PutOpts{}); err != nil {
+		return err
+	}
+
+	_, err := c.BlockAdapter.Get(ctx, obj)
+	return err
+}
+
+func (c *Controller) DeleteRepository(w http.ResponseWriter, r *http.Request, repository string, params apigen.DeleteRepositoryParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadActionsAction,
+			Action:   permissions.DeleteRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "actions_run_hook_output", r, repository, "", "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	c.LogAction(ctx, "delete_repo", r, repository, "", "")
+	err := c.Catalog.DeleteRepository(ctx, repository, graveler.WithForce(swag.BoolValue(params.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	taskResult, err := c.Actions.GetTaskResult(ctx, repo.Name, runID, hookRunID)
+func (c *Controller) ArchiveRepository(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "archive_repo", r, repository, "", "")
+	err := c.Catalog.ArchiveRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	if taskResult.StartTime.IsZero() { // skipped task
-		writeResponse(w, r, http.StatusOK, nil)
+func (c *Controller) RestoreRepository(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
 		return
 	}
-
-	logPath := taskResult.LogPath()
-	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       logPath,
-	})
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_repo", r, repository, "", "")
+	err := c.Catalog.RestoreRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer func() {
-		_ = reader.Close()
-	}()
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	cd := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(logPath)})
-	w.Header().Set("Content-Disposition", cd)
-	w.Header().Set("Content-Type", "application/octet-stream")
-	_, err = io.Copy(w, reader)
-	if err != nil {
-		c.Logger.WithError(err).WithField("log_path", logPath).Warn("Write run hook output")
+func repositoryResponse(repo *catalog.Repository) apigen.Repository {
+	response := apigen.Repository{
+		Id:               repo.Name,
+		StorageNamespace: repo.StorageNamespace,
+		CreationDate:     repo.CreationDate.Unix(),
+		DefaultBranch:    repo.DefaultBranch,
+		ReadOnly:         swag.Bool(repo.ReadOnly),
 	}
+	if !repo.ArchivedAt.IsZero() {
+		response.ArchivedAt = swag.Int64(repo.ArchivedAt.Unix())
+	}
+	return response
 }
 
-func (c *Controller) ListBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListBranchesParams) {
+func (c *Controller) GetRepository(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListBranchesAction,
+			Action:   permissions.ReadRepositoryAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "list_branches", r, repository, "", "")
+	c.LogAction(ctx, "get_repo", r, repository, "", "")
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	switch {
+	case err == nil:
+		writeResponse(w, r, http.StatusOK, repositoryResponse(repo))
 
-	res, hasMore, err := c.Catalog.ListBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
+	case errors.Is(err, graveler.ErrNotFound):
+		writeError(w, r, http.StatusNotFound, "repository not found")
+
+	case errors.Is(err, graveler.ErrRepositoryInDeletion):
+		writeError(w, r, http.StatusGone, err)
+
+	default:
+		writeError(w, r, http.StatusInternalServerError, err)
+	}
+}
+
+func (c *Controller) VerifyRepositoryIntegrity(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "verify_repo_integrity", r, repository, "", "")
+	integrity, err := c.Catalog.VerifyRepositoryIntegrity(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	refs := make([]apigen.Ref, 0, len(res))
-	for _, branch := range res {
-		refs = append(refs, apigen.Ref{
-			CommitId: branch.Reference,
-			Id:       branch.Name,
-		})
+	response := apigen.RepositoryIntegrity{
+		Verified: integrity.Verified,
+		Branches: make([]apigen.BranchIntegrity, 0, len(integrity.Branches)),
 	}
-	response := apigen.RefList{
-		Results:    refs,
-		Pagination: paginationFor(hasMore, refs, "Id"),
+	if !integrity.NotarizedAt.IsZero() {
+		response.NotarizedAt = apiutil.Ptr(integrity.NotarizedAt)
+	}
+	for _, b := range integrity.Branches {
+		branch := apigen.BranchIntegrity{
+			Branch:              b.Branch,
+			Records:             b.Records,
+			Notarized:           b.Notarized,
+			NotarizationExpired: b.NotarizationExpired,
+		}
+		if b.LatestCommitID != "" {
+			branch.LatestCommitId = apiutil.Ptr(b.LatestCommitID)
+			branch.LatestTime = apiutil.Ptr(b.LatestTime)
+			branch.LatestHash = swag.String(b.LatestHash)
+		}
+		if b.Error != "" {
+			branch.Error = swag.String(b.Error)
+		}
+		response.Branches = append(response.Branches, branch)
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateBranch(w http.ResponseWriter, r *http.Request, body apigen.CreateBranchJSONRequestBody, repository string) {
+func (c *Controller) GetRepositoryMetadata(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.CreateBranchAction,
-			Resource: permissions.BranchArn(repository, body.Name),
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch", r, repository, body.Name, "")
-
-	commitLog, err := c.Catalog.CreateBranch(ctx, repository, body.Name, body.Source, graveler.WithForce(swag.BoolValue(body.Force)))
+	c.LogAction(ctx, "get_repo_metadata", r, repository, "", "")
+	metadata, err := c.Catalog.GetRepositoryMetadata(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
-	_, _ = io.WriteString(w, commitLog.Reference)
+	writeResponse(w, r, http.StatusOK, apigen.RepositoryMetadata{AdditionalProperties: metadata})
 }
 
-func (c *Controller) DeleteBranch(w http.ResponseWriter, r *http.Request, repository, branch string, body apigen.DeleteBranchParams) {
+func (c *Controller) SetRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.SetRepositoryMetadataJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_branch", r, repository, branch, "")
-
-	err := c.Catalog.DeleteBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+	c.LogAction(ctx, "update_repo_metadata", r, repository, "", "")
+	err := c.Catalog.UpdateRepositoryMetadata(ctx, repository, body.Metadata.AdditionalProperties)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
+func (c *Controller) DeleteRepositoryMetadata(w http.ResponseWriter, r *http.Request, body apigen.DeleteRepositoryMetadataJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.UpdateRepositoryAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_branch", r, repository, branch, "")
-	reference, err := c.Catalog.GetBranchReference(ctx, repository, branch)
+	c.LogAction(ctx, "delete_repo_metadata", r, repository, "", "")
+	err := c.Catalog.DeleteRepositoryMetadata(ctx, repository, body.Keys)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	response := apigen.Ref{
-		CommitId: reference,
-		Id:       branch,
-	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.ResponseWriter, r *http.Request, err error, cb func(w http.ResponseWriter, r *http.Request, code int, v interface{})) bool {
-	// verify if request canceled even if there is no error, early exit point
-	if httputil.IsRequestCanceled(r) {
-		cb(w, r, httpStatusClientClosedRequest, httpStatusClientClosedRequestText)
-		return true
+func (c *Controller) GetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.GetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
 	}
-	if err == nil {
-		return false
-	}
-
-	log := c.Logger.WithContext(ctx).WithError(err)
-
-	// Handle Hook Errors
-	var hookAbortErr *graveler.HookAbortError
-	if errors.As(err, &hookAbortErr) {
-		log.WithField("run_id", hookAbortErr.RunID).Warn("aborted by hooks")
-		cb(w, r, http.StatusPreconditionFailed, hookAbortErr.Unwrap())
-		return true
+	ctx := r.Context()
+	rules, eTag, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-
-	// order of case is important, more specific errors should be first
-	switch {
-	case errors.Is(err, graveler.ErrLinkAddressInvalid),
-		errors.Is(err, graveler.ErrLinkAddressExpired):
-		log.Debug("Expired or invalid address token")
-		cb(w, r, http.StatusBadRequest, err)
-
-	case errors.Is(err, graveler.ErrNotFound),
-		errors.Is(err, actions.ErrNotFound),
-		errors.Is(err, auth.ErrNotFound),
-		errors.Is(err, kv.ErrNotFound):
-		log.Debug("Not found")
-		cb(w, r, http.StatusNotFound, err)
-
-	case errors.Is(err, block.ErrForbidden),
-		errors.Is(err, graveler.ErrProtectedBranch),
-		errors.Is(err, graveler.ErrReadOnlyRepository):
-		cb(w, r, http.StatusForbidden, err)
-
-	case errors.Is(err, authentication.ErrSessionExpired):
-		cb(w, r, http.StatusForbidden, "session expired")
-
-	case errors.Is(err, authentication.ErrInvalidTokenFormat):
-		cb(w, r, http.StatusUnauthorized, "invalid token format")
-
-	case errors.Is(err, graveler.ErrDirtyBranch),
-		errors.Is(err, graveler.ErrCommitMetaRangeDirtyBranch),
-		errors.Is(err, graveler.ErrInvalidValue),
-		errors.Is(err, validator.ErrInvalidValue),
-		errors.Is(err, catalog.ErrPathRequiredValue),
-		errors.Is(err, graveler.ErrNoChanges),
-		errors.Is(err, permissions.ErrInvalidServiceName),
-		errors.Is(err, permissions.ErrInvalidAction),
-		errors.Is(err, model.ErrValidationError),
-		errors.Is(err, graveler.ErrInvalidRef),
-		errors.Is(err, actions.ErrParamConflict),
-		errors.Is(err, graveler.ErrDereferenceCommitWithStaging),
-		errors.Is(err, graveler.ErrParentOutOfRange),
-		errors.Is(err, graveler.ErrCherryPickMergeNoParent),
-		errors.Is(err, graveler.ErrInvalidMergeStrategy),
-		errors.Is(err, block.ErrInvalidAddress),
-		errors.Is(err, block.ErrOperationNotSupported),
-		errors.Is(err, authentication.ErrInvalidRequest):
-		log.Debug("Bad request")
-		cb(w, r, http.StatusBadRequest, err)
-
-	case errors.Is(err, graveler.ErrNotUnique),
-		errors.Is(err, graveler.ErrConflictFound),
-		errors.Is(err, graveler.ErrRevertMergeNoParent):
-		log.Debug("Conflict")
-		cb(w, r, http.StatusConflict, err)
-
-	case errors.Is(err, graveler.ErrLockNotAcquired):
-		log.Debug("Lock not acquired")
-		cb(w, r, http.StatusInternalServerError, "branch is currently locked, try again later")
-
-	case errors.Is(err, block.ErrDataNotFound):
-		log.Debug("No data")
-		cb(w, r, http.StatusGone, "No data")
-
-	case errors.Is(err, auth.ErrAlreadyExists):
-		log.Debug("Already exists")
-		cb(w, r, http.StatusConflict, "Already exists")
-
-	case errors.Is(err, graveler.ErrTooManyTries):
-		log.Debug("Retried too many times")
-		cb(w, r, http.StatusLocked, "Too many attempts, try again later")
-	case errors.Is(err, kv.ErrSlowDown):
-		log.Debug("KV Throttling")
-		cb(w, r, http.StatusServiceUnavailable, "Throughput exceeded. Slow down and retry")
-	case errors.Is(err, graveler.ErrPreconditionFailed):
-		log.Debug("Precondition failed")
-		cb(w, r, http.StatusPreconditionFailed, "Precondition failed")
-	case errors.Is(err, authentication.ErrNotImplemented):
-		cb(w, r, http.StatusNotImplemented, "Not implemented")
-	case errors.Is(err, authentication.ErrInsufficientPermissions):
-		c.Logger.WithContext(ctx).WithError(err).Info("User verification failed - insufficient permissions")
-		cb(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
-	case err != nil:
-		c.Logger.WithContext(ctx).WithError(err).Error("API call returned status internal server error")
-		cb(w, r, http.StatusInternalServerError, err)
-
-	default:
-		return false
+	resp := make([]*apigen.BranchProtectionRule, 0, len(rules.BranchPatternToBlockedActions))
+	for pattern := range rules.BranchPatternToBlockedActions {
+		resp = append(resp, &apigen.BranchProtectionRule{
+			Pattern: pattern,
+		})
 	}
-
-	return true
-}
-
-func (c *Controller) handleAPIError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) bool {
-	return c.handleAPIErrorCallback(ctx, w, r, err, writeError)
+	w.Header().Set("ETag", swag.StringValue(eTag))
+	writeResponse(w, r, http.StatusOK, resp)
 }
 
-func (c *Controller) ResetBranch(w http.ResponseWriter, r *http.Request, body apigen.ResetBranchJSONRequestBody, repository, branch string) {
+func (c *Controller) SetBranchProtectionRules(w http.ResponseWriter, r *http.Request, body apigen.SetBranchProtectionRulesJSONRequestBody, repository string, params apigen.SetBranchProtectionRulesParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.RevertBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
+	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
 
-	c.LogAction(ctx, "reset_branch", r, repository, branch, "")
-
-	var err error
+	// For now, all protected branches use the same default set of blocked actions. In the future this set will be user configurable.
+	blockedActions := []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT}
 
-	switch body.Type {
-	case entryTypeCommonPrefix:
-		err = c.Catalog.ResetEntries(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
-	case "reset":
-		err = c.Catalog.ResetBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
-	case entryTypeObject:
-		err = c.Catalog.ResetEntry(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
-	default:
-		writeError(w, r, http.StatusBadRequest, "unknown reset type")
+	rules := &graveler.BranchProtectionRules{
+		BranchPatternToBlockedActions: make(map[string]*graveler.BranchProtectionBlockedActions),
+	}
+	for _, r := range body {
+		rules.BranchPatternToBlockedActions[r.Pattern] = &graveler.BranchProtectionBlockedActions{
+			Value: blockedActions,
+		}
+	}
+	err := c.Catalog.SetBranchProtectionRules(ctx, repository, rules, params.IfMatch)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
+func (c *Controller) DeleteGCRules(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, nil)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) HardResetBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.HardResetBranchParams) {
+func (c *Controller) GetGCRules(w http.ResponseWriter, r *http.Request, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			// TODO(ozkatz): Can we have another action here?
-			Action:   permissions.RevertBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.GetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-
-	c.LogAction(ctx, "hard_reset_branch", r, repository, branch, "")
-
-	err := c.Catalog.HardResetBranch(ctx, repository, branch, params.Ref, graveler.WithForce(swag.BoolValue(params.Force)))
+	rules, err := c.Catalog.GetGarbageCollectionRules(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	resp := apigen.GarbageCollectionRules{}
+	resp.DefaultRetentionDays = int(rules.DefaultRetentionDays)
+	for branchID, retentionDays := range rules.BranchRetentionDays {
+		resp.Branches = append(resp.Branches, apigen.GarbageCollectionRule{BranchId: branchID, RetentionDays: int(retentionDays)})
+	}
+	writeResponse(w, r, http.StatusOK, resp)
 }
 
-func (c *Controller) ImportStart(w http.ResponseWriter, r *http.Request, body apigen.ImportStartJSONRequestBody, repository, branch string) {
-	perm := permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.WriteObjectAction,
-					Resource: permissions.BranchArn(repository, branch),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.BranchArn(repository, branch),
-				},
-			},
+func (c *Controller) SetGCRules(w http.ResponseWriter, r *http.Request, body apigen.SetGCRulesJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
 		},
+	}) {
+		return
 	}
-	// Add import permissions per source
-	// Add object permissions per destination
-	for _, source := range body.Paths {
-		perm.Nodes = append(perm.Nodes,
-			permissions.Node{Permission: permissions.Permission{
-				Action:   permissions.ImportFromStorageAction,
-				Resource: permissions.StorageNamespace(source.Path),
-			}},
-			permissions.Node{Permission: permissions.Permission{
-				Action:   permissions.WriteObjectAction,
-				Resource: permissions.ObjectArn(repository, source.Destination),
-			}})
+	ctx := r.Context()
+	rules := &graveler.GarbageCollectionRules{
+		DefaultRetentionDays: int32(body.DefaultRetentionDays),
+		BranchRetentionDays:  make(map[string]int32),
 	}
-	if !c.authorize(w, r, perm) {
+	for _, rule := range body.Branches {
+		rules.BranchRetentionDays[rule.BranchId] = int32(rule.RetentionDays)
+	}
+	err := c.Catalog.SetGarbageCollectionRules(ctx, repository, rules)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
+func (c *Controller) ListActionPayloadSchemas(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	c.LogAction(ctx, "import", r, repository, branch, "")
-
-	user, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "missing user")
+	if _, err := auth.GetUser(ctx); err != nil {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
 		return
 	}
-	metadata := map[string]string{}
-	if body.Commit.Metadata != nil {
-		metadata = body.Commit.Metadata.AdditionalProperties
+	schemas, err := actions.PayloadSchemas()
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	paths := make([]catalog.ImportPath, 0, len(body.Paths))
-	for _, p := range body.Paths {
-		pathType, err := catalog.GetImportPathType(p.Type)
-		if c.handleAPIError(ctx, w, r, err) {
+	response := apigen.ActionPayloadSchemaList{
+		DefaultVersion: actions.DefaultPayloadVersion,
+		LatestVersion:  actions.LatestPayloadVersion,
+		Results:        make([]apigen.ActionPayloadSchema, 0, len(schemas)),
+	}
+	for _, schema := range schemas {
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(schema.Schema, &parsed); c.handleAPIError(ctx, w, r, err) {
 			return
 		}
-		paths = append(paths, catalog.ImportPath{
-			Destination: p.Destination,
-			Path:        p.Path,
-			Type:        pathType,
+		response.Results = append(response.Results, apigen.ActionPayloadSchema{
+			Version: schema.Version,
+			Schema:  parsed,
 		})
 	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	importID, err := c.Catalog.Import(r.Context(), repository, branch, catalog.ImportRequest{
-		Paths: paths,
-		Commit: catalog.ImportCommit{
-			CommitMessage: body.Commit.Message,
-			Committer:     user.Committer(),
-			Metadata:      metadata,
+func (c *Controller) ListRepositoryRuns(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListRepositoryRunsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
 		},
-		Force: swag.BoolValue(body.Force),
-	})
-	if c.handleAPIError(ctx, w, r, err) {
+	}) {
 		return
 	}
-	writeResponse(w, r, http.StatusAccepted, apigen.ImportCreationResponse{
-		Id: importID,
-	})
-}
+	ctx := r.Context()
+	c.LogAction(ctx, "actions_repository_runs", r, repository, "", "")
 
-func importStatusToResponse(status *graveler.ImportStatus) apigen.ImportStatus {
-	resp := apigen.ImportStatus{
-		Completed:       status.Completed,
-		IngestedObjects: &status.Progress,
-		UpdateTime:      status.UpdatedAt,
+	selection, err := newFieldSelection(reflect.TypeOf(apigen.ActionRun{}), "run_id", params.Fields, nil)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
 	}
 
-	if status.Error != nil {
-		resp.Error = &apigen.Error{Message: status.Error.Error()}
-	}
-	if status.MetaRangeID != "" {
-		metarange := status.MetaRangeID.String()
-		resp.MetarangeId = &metarange
+	_, err = c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
 
-	commitLog := catalog.CommitRecordToLog(status.Commit)
-	if commitLog != nil {
-		resp.Commit = &apigen.Commit{
-			Committer:    commitLog.Committer,
-			CreationDate: commitLog.CreationDate.Unix(),
-			Id:           commitLog.Reference,
-			Message:      commitLog.Message,
-			MetaRangeId:  commitLog.MetaRangeID,
-			Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commitLog.Metadata},
-			Parents:      commitLog.Parents,
-			Version:      apiutil.Ptr(int(commitLog.Version)),
-			Generation:   apiutil.Ptr(int64(commitLog.Generation)),
-		}
+	branchName := swag.StringValue(params.Branch)
+	commitID := swag.StringValue(params.Commit)
+	runsIter, err := c.Actions.ListRunResults(ctx, repository, branchName, commitID, paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
+	defer runsIter.Close()
 
-	return resp
-}
-
-func (c *Controller) ImportStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportStatusParams) {
-	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadBranchAction,
-			Resource: permissions.BranchArn(repository, branch),
+	response := apigen.ActionRunList{
+		Pagination: apigen.Pagination{
+			MaxPerPage: DefaultMaxPerPage,
 		},
-	}) {
+		Results: make([]apigen.ActionRun, 0),
+	}
+	amount := paginationAmount(params.Amount)
+	for len(response.Results) < amount && runsIter.Next() {
+		val := runsIter.Value()
+		response.Results = append(response.Results, runResultToActionRun(val))
+	}
+	response.Pagination.Results = len(response.Results)
+	if runsIter.Next() {
+		response.Pagination.HasMore = true
+		if len(response.Results) > 0 {
+			lastRun := response.Results[len(response.Results)-1]
+			response.Pagination.NextOffset = lastRun.RunId
+		}
+	}
+	if err := runsIter.Err(); err != nil {
+		writeResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	ctx := r.Context()
-	status, err := c.Catalog.GetImportStatus(ctx, repository, params.Id)
+	selected, err := selection.apply(response)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, selected)
+}
 
-	resp := importStatusToResponse(status)
-	writeResponse(w, r, http.StatusOK, resp)
+func runResultToActionRun(val *actions.RunResult) apigen.ActionRun {
+	runResult := apigen.ActionRun{
+		Branch:    val.BranchID,
+		CommitId:  val.CommitID,
+		RunId:     val.RunID,
+		StartTime: val.StartTime,
+		EndTime:   &val.EndTime,
+		EventType: val.EventType,
+	}
+	if val.Passed {
+		runResult.Status = actionStatusCompleted
+	} else {
+		runResult.Status = actionStatusFailed
+	}
+	return runResult
 }
 
-func (c *Controller) ImportCancel(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportCancelParams) {
+func (c *Controller) GetRun(w http.ResponseWriter, r *http.Request, repository, runID string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ImportCancelAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "cancel_import", r, repository, branch, "")
-	err := c.Catalog.CancelImport(ctx, repository, params.Id)
+	c.LogAction(ctx, "actions_get_run", r, repository, "", "")
+	_, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	writeResponse(w, r, http.StatusNoContent, nil)
+	runResult, err := c.Actions.GetRunResult(ctx, repository, runID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	var status string
+	if runResult.Passed {
+		status = actionStatusCompleted
+	} else {
+		status = actionStatusFailed
+	}
+	response := apigen.ActionRun{
+		RunId:     runResult.RunID,
+		EventType: runResult.EventType,
+		StartTime: runResult.StartTime,
+		EndTime:   &runResult.EndTime,
+		Status:    status,
+		Branch:    runResult.BranchID,
+		CommitId:  runResult.CommitID,
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) Commit(w http.ResponseWriter, r *http.Request, body apigen.CommitJSONRequestBody, repository, branch string, params apigen.CommitParams) {
+func (c *Controller) ListRunHooks(w http.ResponseWriter, r *http.Request, repository, runID string, params apigen.ListRunHooksParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.CreateCommitAction,
-			Resource: permissions.BranchArn(repository, branch),
+			Action:   permissions.ReadActionsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_commit", r, repository, branch, "")
-	user, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "missing user")
+	c.LogAction(ctx, "actions_list_run_hooks", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	var metadata map[string]string
-	if body.Metadata != nil {
-		metadata = body.Metadata.AdditionalProperties
-	}
 
-	newCommit, err := c.Catalog.Commit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, params.SourceMetarange, swag.BoolValue(body.AllowEmpty), graveler.WithForce(swag.BoolValue(body.Force)))
+	tasksIter, err := c.Actions.ListRunTaskResults(ctx, repo.Name, runID, paginationAfter(params.After))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	commitResponse(w, r, newCommit)
+	defer tasksIter.Close()
+
+	response := apigen.HookRunList{
+		Results: make([]apigen.HookRun, 0),
+		Pagination: apigen.Pagination{
+			MaxPerPage: DefaultMaxPerPage,
+		},
+	}
+	amount := paginationAmount(params.Amount)
+	for len(response.Results) < amount && tasksIter.Next() {
+		val := tasksIter.Value()
+		hookRun := apigen.HookRun{
+			HookRunId: val.HookRunID,
+			Action:    val.ActionName,
+			HookId:    val.HookID,
+			StartTime: val.StartTime,
+			EndTime:   &val.EndTime,
+		}
+		switch {
+		case val.Passed:
+			hookRun.Status = actionStatusCompleted
+		case val.StartTime.IsZero(): // assumes that database values are only stored after run is finished
+			hookRun.Status = actionStatusSkipped
+			hookRun.EndTime = nil
+		default:
+			hookRun.Status = actionStatusFailed
+		}
+		response.Results = append(response.Results, hookRun)
+	}
+	if tasksIter.Next() {
+		response.Pagination.HasMore = true
+		if len(response.Results) > 0 {
+			lastHookRun := response.Results[len(response.Results)-1]
+			response.Pagination.NextOffset = lastHookRun.HookRunId
+		}
+	}
+	if err := tasksIter.Err(); err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	response.Pagination.Results = len(response.Results)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateCommitRecord(w http.ResponseWriter, r *http.Request, body apigen.CreateCommitRecordJSONRequestBody, repository string) {
+func (c *Controller) GetRunHookOutput(w http.ResponseWriter, r *http.Request, repository, runID, hookRunID string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.CreateCommitAction,
+			Action:   permissions.ReadActionsAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_commit_record", r, repository, body.CommitId, "")
-	_, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "missing user")
+	c.LogAction(ctx, "actions_run_hook_output", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	err = c.Catalog.CreateCommitRecord(ctx, repository, body.CommitId, body.Version, body.Committer, body.Message, body.MetarangeId, body.CreationDate, body.Parents, body.Metadata.AdditionalProperties, int(body.Generation), graveler.WithForce(swag.BoolValue(body.Force)))
+
+	taskResult, err := c.Actions.GetTaskResult(ctx, repo.Name, runID, hookRunID)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	writeResponse(w, r, http.StatusNoContent, nil)
-}
+	if taskResult.StartTime.IsZero() { // skipped task
+		writeResponse(w, r, http.StatusOK, nil)
+		return
+	}
 
-func commitResponse(w http.ResponseWriter, r *http.Request, newCommit *catalog.CommitLog) {
-	response := apigen.Commit{
-		Committer:    newCommit.Committer,
-		CreationDate: newCommit.CreationDate.Unix(),
-		Id:           newCommit.Reference,
-		Message:      newCommit.Message,
-		MetaRangeId:  newCommit.MetaRangeID,
-		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: newCommit.Metadata},
-		Parents:      newCommit.Parents,
-		Version:      apiutil.Ptr(int(newCommit.Version)),
-		Generation:   apiutil.Ptr(int64(newCommit.Generation)),
+	logPath := taskResult.LogPath()
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       logPath,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	cd := mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(logPath)})
+	w.Header().Set("Content-Disposition", cd)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		c.Logger.WithError(err).WithField("log_path", logPath).Warn("Write run hook output")
 	}
-	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) DiffBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DiffBranchParams) {
+func (c *Controller) ListBranches(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListBranchesParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ListObjectsAction,
+			Action:   permissions.ListBranchesAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "diff_workspace", r, repository, branch, "")
+	c.LogAction(ctx, "list_branches", r, repository, "", "")
 
-	diff, hasMore, err := c.Catalog.DiffUncommitted(
-		ctx,
-		repository,
-		branch,
-		paginationPrefix(params.Prefix),
-		paginationDelimiter(params.Delimiter),
-		paginationAmount(params.Amount),
-		paginationAfter(params.After),
-	)
+	res, hasMore, err := c.Catalog.ListBranches(ctx, repository, paginationPrefix(params.Prefix), paginationAmount(params.Amount), paginationAfter(params.After))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	results := make([]apigen.Diff, 0, len(diff))
-	for _, d := range diff {
-		pathType := entryTypeObject
-		if d.CommonLevel {
-			pathType = entryTypeCommonPrefix
-		}
-		diff := apigen.Diff{
-			Path:     d.Path,
-			Type:     transformDifferenceTypeToString(d.Type),
-			PathType: pathType,
-		}
-		if !d.CommonLevel {
-			diff.SizeBytes = swag.Int64(d.Size)
-		}
-		results = append(results, diff)
+	refs := make([]apigen.Ref, 0, len(res))
+	for _, branch := range res {
+		refs = append(refs, apigen.Ref{
+			CommitId: branch.Reference,
+			Id:       branch.Name,
+		})
 	}
-	response := apigen.DiffList{
-		Pagination: paginationFor(hasMore, results, "Path"),
-		Results:    results,
+	response := apigen.RefList{
+		Results:    refs,
+		Pagination: paginationFor(hasMore, refs, "Id"),
 	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) DeleteObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DeleteObjectParams) {
+func (c *Controller) CreateBranch(w http.ResponseWriter, r *http.Request, body apigen.CreateBranchJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.DeleteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.CreateBranchAction,
+			Resource: permissions.BranchArn(repository, body.Name),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_object", r, repository, branch, "")
-	err := c.Catalog.DeleteEntry(ctx, repository, branch, params.Path, graveler.WithForce(swag.BoolValue(params.Force)))
+	c.LogAction(ctx, "create_branch", r, repository, body.Name, "")
+
+	commitLog, err := c.Catalog.CreateBranch(ctx, repository, body.Name, body.Source, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	writeResponse(w, r, http.StatusNoContent, nil)
+	w.WriteHeader(http.StatusCreated)
+	_, _ = io.WriteString(w, commitLog.Reference)
 }
 
-func (c *Controller) UploadObjectPreflight(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectPreflightParams) {
+func (c *Controller) DeleteBranch(w http.ResponseWriter, r *http.Request, repository, branch string, body apigen.DeleteBranchParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.DeleteBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "put_object_preflight", r, repository, branch, "")
+	c.LogAction(ctx, "delete_branch", r, repository, branch, "")
 
+	err := c.Catalog.DeleteBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectParams) {
+func (c *Controller) ListParams(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListParamsParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, catalog.ParamsPrefix+"*"),
+				},
+			},
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "put_object", r, repository, branch, "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	c.LogAction(ctx, "list_params", r, repository, ref, "")
+	res, hasMore, err := c.Catalog.ListParams(ctx, repository, ref, paginationAfter(params.After), paginationAmount(params.Amount))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	results := make([]apigen.Param, 0, len(res))
+	for _, p := range res {
+		results = append(results, apigen.Param{Key: p.Key, Value: p.Value})
+	}
+	writeResponse(w, r, http.StatusOK, apigen.ParamList{
+		Pagination: paginationFor(hasMore, results, "Key"),
+		Results:    results,
+	})
+}
 
-	// check if branch exists - it is still a possibility, but we don't want to upload large object when the branch was not there in the first place
-	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
-	if c.handleAPIError(ctx, w, r, err) {
+func (c *Controller) GetParam(w http.ResponseWriter, r *http.Request, repository, ref, key string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, catalog.ParamsPrefix+key),
+		},
+	}) {
 		return
 	}
-	if !branchExists {
-		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+	ctx := r.Context()
+	c.LogAction(ctx, "get_param", r, repository, ref, "")
+	value, err := c.Catalog.GetParam(ctx, repository, ref, key)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, apigen.Param{Key: key, Value: value})
+}
 
-	// before writing body, ensure preconditions - this means we essentially check for object existence twice:
-	// once before uploading the body to save resources and time,
-	//	and then graveler will check again when passed a SetOptions.
-	allowOverwrite := true
-	if params.IfNoneMatch != nil {
-		if swag.StringValue((*string)(params.IfNoneMatch)) != "*" {
-			writeError(w, r, http.StatusBadRequest, "Unsupported value for If-None-Match - Only \"*\" is supported")
-			return
-		}
-		// check if exists
-		_, err := c.Catalog.GetEntry(ctx, repo.Name, branch, params.Path, catalog.GetEntryParams{})
-		if err == nil {
-			writeError(w, r, http.StatusPreconditionFailed, "path already exists")
-			return
-		}
-		if !errors.Is(err, graveler.ErrNotFound) {
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
-		}
-		allowOverwrite = false
-	}
-
-	// read request body parse multipart for "content" and upload the data
-	contentType := catalog.ContentTypeOrDefault(r.Header.Get("Content-Type"))
-	mediaType, p, err := mime.ParseMediaType(contentType)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+func (c *Controller) SetParam(w http.ResponseWriter, r *http.Request, body apigen.SetParamJSONRequestBody, repository, branch, key string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, catalog.ParamsPrefix+key),
+		},
+	}) {
 		return
 	}
-
-	var blob *upload.Blob
-	if mediaType != "multipart/form-data" {
-		// handle non-multipart, direct content upload
-		address := c.PathProvider.NewPath()
-		blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, r.Body, r.ContentLength,
-			block.PutOpts{StorageClass: params.StorageClass})
-		if err != nil {
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
-		}
-	} else {
-		// handle multipart upload
-		boundary, ok := p["boundary"]
-		if !ok {
-			writeError(w, r, http.StatusInternalServerError, http.ErrMissingBoundary)
-			return
-		}
-
-		contentUploaded := false
-		reader := multipart.NewReader(r.Body, boundary)
-		for !contentUploaded {
-			part, err := reader.NextPart()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				writeError(w, r, http.StatusInternalServerError, err)
-				return
-			}
-			contentType = part.Header.Get("Content-Type")
-			partName := part.FormName()
-			if partName == "content" {
-				// upload the first "content" and exit the loop
-				address := c.PathProvider.NewPath()
-				blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, part, -1, block.PutOpts{StorageClass: params.StorageClass})
-				if err != nil {
-					_ = part.Close()
-					writeError(w, r, http.StatusInternalServerError, err)
-					return
-				}
-				contentUploaded = true
-			}
-			_ = part.Close()
-		}
-		if !contentUploaded {
-			err := fmt.Errorf("multipart upload missing key 'content': %w", http.ErrMissingFile)
-			writeError(w, r, http.StatusInternalServerError, err)
-			return
-		}
-	}
-	// write metadata
-	writeTime := time.Now()
-	entryBuilder := catalog.NewDBEntryBuilder().
-		Path(params.Path).
-		PhysicalAddress(blob.PhysicalAddress).
-		CreationDate(writeTime).
-		Size(blob.Size).
-		Checksum(blob.Checksum).
-		ContentType(contentType)
-	if blob.RelativePath {
-		entryBuilder.AddressType(catalog.AddressTypeRelative)
-	} else {
-		entryBuilder.AddressType(catalog.AddressTypeFull)
-	}
-	meta := extractLakeFSMetadata(r.Header)
-	if len(meta) > 0 {
-		entryBuilder.Metadata(meta)
+	ctx := r.Context()
+	c.LogAction(ctx, "set_param", r, repository, branch, "")
+	err := c.Catalog.SetParam(ctx, repository, branch, key, body.Value)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	entry := entryBuilder.Build()
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithIfAbsent(!allowOverwrite), graveler.WithForce(swag.BoolValue(params.Force)))
-	if errors.Is(err, graveler.ErrPreconditionFailed) {
-		writeError(w, r, http.StatusPreconditionFailed, "path already exists")
+func (c *Controller) DeleteParam(w http.ResponseWriter, r *http.Request, repository, branch, key string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.DeleteObjectAction,
+			Resource: permissions.ObjectArn(repository, catalog.ParamsPrefix+key),
+		},
+	}) {
 		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_param", r, repository, branch, "")
+	err := c.Catalog.DeleteParam(ctx, repository, branch, key)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
 
-	identifierType := block.IdentifierTypeFull
-	if blob.RelativePath {
-		identifierType = block.IdentifierTypeRelative
+func (c *Controller) RestoreBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.RestoreBranchParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
 	}
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_branch", r, repository, branch, "")
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, blob.PhysicalAddress, identifierType)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+	restored, err := c.Catalog.RestoreBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(params.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	response := apigen.ObjectStats{
-		Checksum:        blob.Checksum,
-		Mtime:           writeTime.Unix(),
-		Path:            params.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(blob.Size),
-		ContentType:     &contentType,
-		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta},
+	response := apigen.Ref{
+		CommitId: restored.Reference,
+		Id:       restored.Name,
 	}
 	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) StageObject(w http.ResponseWriter, r *http.Request, body apigen.StageObjectJSONRequestBody, repository, branch string, params apigen.StageObjectParams) {
+func (c *Controller) GetBranch(w http.ResponseWriter, r *http.Request, repository, branch string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "stage_object", r, repository, branch, "")
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	c.LogAction(ctx, "get_branch", r, repository, branch, "")
+	reference, err := c.Catalog.GetBranchReference(ctx, repository, branch)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	// write metadata
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, body.PhysicalAddress, block.IdentifierTypeFull)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	response := apigen.Ref{
+		CommitId: reference,
+		Id:       branch,
 	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	// see what storage type this is and whether it fits our configuration
-	uriRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
-	if match, err := regexp.MatchString(uriRegex, body.PhysicalAddress); err != nil || !match {
-		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("physical address is not valid for block adapter: %s",
-			c.BlockAdapter.BlockstoreType(),
-		))
-		return
+func (c *Controller) handleAPIErrorCallback(ctx context.Context, w http.ResponseWriter, r *http.Request, err error, cb func(w http.ResponseWriter, r *http.Request, code int, v interface{})) bool {
+	// verify if request canceled even if there is no error, early exit point
+	if httputil.IsRequestCanceled(r) {
+		cb(w, r, httpStatusClientClosedRequest, httpStatusClientClosedRequestText)
+		return true
+	}
+	if err == nil {
+		return false
 	}
 
-	// take mtime from request, if any
-	writeTime := time.Now()
-	if body.Mtime != nil {
-		writeTime = time.Unix(*body.Mtime, 0)
+	log := c.Logger.WithContext(ctx).WithError(err)
+
+	// Handle Hook Errors
+	var hookAbortErr *graveler.HookAbortError
+	if errors.As(err, &hookAbortErr) {
+		log.WithField("run_id", hookAbortErr.RunID).Warn("aborted by hooks")
+		cb(w, r, http.StatusPreconditionFailed, hookAbortErr.Unwrap())
+		return true
 	}
 
-	physicalAddress, addressType := normalizePhysicalAddress(repo.StorageNamespace, body.PhysicalAddress)
+	// order of case is important, more specific errors should be first
+	switch {
+	case errors.Is(err, graveler.ErrLinkAddressInvalid),
+		errors.Is(err, graveler.ErrLinkAddressExpired):
+		log.Debug("Expired or invalid address token")
+		cb(w, r, http.StatusBadRequest, err)
 
-	entryBuilder := catalog.NewDBEntryBuilder().
-		CommonLevel(false).
-		Path(params.Path).
-		PhysicalAddress(physicalAddress).
-		AddressType(addressType).
-		CreationDate(writeTime).
-		Size(body.SizeBytes).
-		Checksum(body.Checksum).
-		ContentType(swag.StringValue(body.ContentType))
-	if body.Metadata != nil {
-		entryBuilder.Metadata(body.Metadata.AdditionalProperties)
-	}
-	entry := entryBuilder.Build()
+	case errors.Is(err, graveler.ErrNotFound),
+		errors.Is(err, actions.ErrNotFound),
+		errors.Is(err, auth.ErrNotFound),
+		errors.Is(err, kv.ErrNotFound),
+		errors.Is(err, notifications.ErrNotFound):
+		log.Debug("Not found")
+		cb(w, r, http.StatusNotFound, err)
 
-	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-	response := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
+	case errors.Is(err, block.ErrForbidden),
+		errors.Is(err, catalog.ErrEncryptionKeyNotAllowed),
+		errors.Is(err, graveler.ErrProtectedBranch),
+		errors.Is(err, graveler.ErrReadOnlyRepository):
+		cb(w, r, http.StatusForbidden, err)
+
+	case errors.Is(err, authentication.ErrSessionExpired):
+		cb(w, r, http.StatusForbidden, "session expired")
+
+	case errors.Is(err, authentication.ErrInvalidTokenFormat):
+		cb(w, r, http.StatusUnauthorized, "invalid token format")
+
+	case errors.Is(err, graveler.ErrDirtyBranch),
+		errors.Is(err, graveler.ErrCommitMetaRangeDirtyBranch),
+		errors.Is(err, graveler.ErrInvalidValue),
+		errors.Is(err, validator.ErrInvalidValue),
+		errors.Is(err, catalog.ErrPathRequiredValue),
+		errors.Is(err, graveler.ErrNoChanges),
+		errors.Is(err, permissions.ErrInvalidServiceName),
+		errors.Is(err, permissions.ErrInvalidAction),
+		errors.Is(err, model.ErrValidationError),
+		errors.Is(err, graveler.ErrInvalidRef),
+		errors.Is(err, actions.ErrParamConflict),
+		errors.Is(err, graveler.ErrDereferenceCommitWithStaging),
+		errors.Is(err, graveler.ErrParentOutOfRange),
+		errors.Is(err, graveler.ErrCherryPickMergeNoParent),
+		errors.Is(err, graveler.ErrSplitMergeCommit),
+		errors.Is(err, graveler.ErrInvalidMergeStrategy),
+		errors.Is(err, block.ErrInvalidAddress),
+		errors.Is(err, block.ErrOperationNotSupported),
+		errors.Is(err, authentication.ErrInvalidRequest),
+		errors.Is(err, notifications.ErrInvalidEvent),
+		errors.Is(err, notifications.ErrInvalidChannel),
+		errors.Is(err, notifications.ErrInvalidTarget),
+		errors.Is(err, notifications.ErrChannelNotConfigured):
+		log.Debug("Bad request")
+		cb(w, r, http.StatusBadRequest, err)
+
+	case errors.Is(err, graveler.ErrNotUnique),
+		errors.Is(err, graveler.ErrConflictFound),
+		errors.Is(err, graveler.ErrRevertMergeNoParent):
+		log.Debug("Conflict")
+		cb(w, r, http.StatusConflict, err)
+
+	case errors.Is(err, graveler.ErrLockNotAcquired):
+		log.Debug("Lock not acquired")
+		cb(w, r, http.StatusInternalServerError, "branch is currently locked, try again later")
+
+	case errors.Is(err, block.ErrDataNotFound):
+		log.Debug("No data")
+		cb(w, r, http.StatusGone, "No data")
+
+	case errors.Is(err, auth.ErrAlreadyExists):
+		log.Debug("Already exists")
+		cb(w, r, http.StatusConflict, "Already exists")
+
+	case errors.Is(err, graveler.ErrTooManyTries):
+		log.Debug("Retried too many times")
+		cb(w, r, http.StatusLocked, "Too many attempts, try again later")
+	case errors.Is(err, kv.ErrSlowDown):
+		log.Debug("KV Throttling")
+		cb(w, r, http.StatusServiceUnavailable, "Throughput exceeded. Slow down and retry")
+	case errors.Is(err, block.ErrCircuitOpen):
+		log.WithError(err).Warn("Blockstore circuit open")
+		cb(w, r, http.StatusServiceUnavailable, "Object store is failing, try again later")
+	case errors.Is(err, block.ErrOperationTimeout):
+		log.WithError(err).Warn("Blockstore operation timeout")
+		cb(w, r, http.StatusServiceUnavailable, "Object store did not respond in time, try again later")
+	case errors.Is(err, graveler.ErrPreconditionFailed):
+		log.Debug("Precondition failed")
+		cb(w, r, http.StatusPreconditionFailed, "Precondition failed")
+	case errors.Is(err, authentication.ErrNotImplemented):
+		cb(w, r, http.StatusNotImplemented, "Not implemented")
+	case errors.Is(err, authentication.ErrInsufficientPermissions):
+		c.Logger.WithContext(ctx).WithError(err).Info("User verification failed - insufficient permissions")
+		cb(w, r, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+	case err != nil:
+		c.Logger.WithContext(ctx).WithError(err).Error("API call returned status internal server error")
+		cb(w, r, http.StatusInternalServerError, err)
+
+	default:
+		return false
 	}
-	writeResponse(w, r, http.StatusCreated, response)
+
+	return true
 }
 
-func (c *Controller) CopyObject(w http.ResponseWriter, r *http.Request, body apigen.CopyObjectJSONRequestBody, repository, branch string, params apigen.CopyObjectParams) {
-	srcPath := body.SrcPath
-	destPath := params.DestPath
+func (c *Controller) handleAPIError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) bool {
+	return c.handleAPIErrorCallback(ctx, w, r, err, writeError)
+}
+
+func (c *Controller) ResetBranch(w http.ResponseWriter, r *http.Request, body apigen.ResetBranchJSONRequestBody, repository, branch string) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ReadObjectAction,
-					Resource: permissions.ObjectArn(repository, srcPath),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.WriteObjectAction,
-					Resource: permissions.ObjectArn(repository, destPath),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.RevertBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "copy_object", r, repository, branch, destPath)
-
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
 
-	// verify destination is a branch (and exists)
-	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
-	if !branchExists {
-		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
-		return
-	}
+	c.LogAction(ctx, "reset_branch", r, repository, branch, "")
 
-	// use destination branch as source if not specified
-	srcRef := swag.StringValue(body.SrcRef)
-	if srcRef == "" {
-		srcRef = branch
-	}
+	var err error
 
-	// copy entry
-	entry, err := c.Catalog.CopyEntry(ctx, repository, srcRef, srcPath, repository, branch, destPath, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
+	switch body.Type {
+	case entryTypeCommonPrefix:
+		err = c.Catalog.ResetEntries(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
+	case "reset":
+		err = c.Catalog.ResetBranch(ctx, repository, branch, graveler.WithForce(swag.BoolValue(body.Force)))
+	case entryTypeObject:
+		err = c.Catalog.ResetEntry(ctx, repository, branch, swag.StringValue(body.Path), graveler.WithForce(swag.BoolValue(body.Force)))
+	default:
+		writeError(w, r, http.StatusBadRequest, "unknown reset type")
 		return
 	}
 
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, block.IdentifierTypeRelative)
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	var metadata map[string]string
-	if entry.Metadata != nil {
-		metadata = entry.Metadata
-	} else {
-		metadata = map[string]string{}
-	}
-
-	response := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
-		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
-	}
-	writeResponse(w, r, http.StatusCreated, response)
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body apigen.RevertBranchJSONRequestBody, repository, branch string) {
+func (c *Controller) HardResetBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.HardResetBranchParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
+			// TODO(ozkatz): Can we have another action here?
 			Action:   permissions.RevertBranchAction,
 			Resource: permissions.BranchArn(repository, branch),
 		},
@@ -3425,426 +3772,541 @@ func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body a
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "revert_branch", r, repository, branch, "")
 
-	user, err := auth.GetUser(ctx)
-	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "user not found")
-		return
-	}
-	revertParams := catalog.RevertParams{
-		Reference:       body.Ref,
-		Committer:       user.Committer(),
-		ParentNumber:    body.ParentNumber,
-		AllowEmpty:      swag.BoolValue(body.AllowEmpty),
-		CommitOverrides: getCommitOverrides(body.CommitOverrides),
-	}
-	err = c.Catalog.Revert(ctx, repository, branch, revertParams, graveler.WithForce(swag.BoolValue(body.Force)))
+	c.LogAction(ctx, "hard_reset_branch", r, repository, branch, "")
+
+	err := c.Catalog.HardResetBranch(ctx, repository, branch, params.Ref, graveler.WithForce(swag.BoolValue(params.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body apigen.CherryPickJSONRequestBody, repository string, branch string) {
-	if !c.authorize(w, r, permissions.Node{
+func (c *Controller) ImportStart(w http.ResponseWriter, r *http.Request, body apigen.ImportStartJSONRequestBody, repository, branch string) {
+	perm := permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
+					Action:   permissions.WriteObjectAction,
 					Resource: permissions.BranchArn(repository, branch),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.ReadCommitAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.BranchArn(repository, branch),
 				},
 			},
 		},
-	}) {
+	}
+	// Add import permissions per source
+	// Add object permissions per destination
+	for _, source := range body.Paths {
+		perm.Nodes = append(perm.Nodes,
+			permissions.Node{Permission: permissions.Permission{
+				Action:   permissions.ImportFromStorageAction,
+				Resource: permissions.StorageNamespace(source.Path),
+			}},
+			permissions.Node{Permission: permissions.Permission{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(repository, source.Destination),
+			}})
+		if source.Type == entryTypeCommonPrefix && strings.HasPrefix(permissions.ActionsConfigPrefix, source.Destination) {
+			// importing a prefix to its parent may write the actions configuration
+			perm.Nodes = append(perm.Nodes, permissions.Node{Permission: permissions.Permission{
+				Action:   permissions.WriteActionsConfigAction,
+				Resource: permissions.ObjectArn(repository, permissions.ActionsConfigPrefix),
+			}})
+		}
+	}
+	if !c.authorize(w, r, perm) {
 		return
 	}
+
 	ctx := r.Context()
-	c.LogAction(ctx, "cherry_pick", r, repository, branch, body.Ref)
+	c.LogAction(ctx, "import", r, repository, branch, "")
 
 	user, err := auth.GetUser(ctx)
 	if err != nil {
-		writeError(w, r, http.StatusUnauthorized, "user not found")
+		writeError(w, r, http.StatusUnauthorized, "missing user")
 		return
 	}
-	cherryPickParams := catalog.CherryPickParams{
-		Reference:       body.Ref,
-		Committer:       user.Committer(),
-		ParentNumber:    body.ParentNumber,
-		CommitOverrides: getCommitOverrides(body.CommitOverrides),
+	metadata := map[string]string{}
+	if body.Commit.Metadata != nil {
+		metadata = body.Commit.Metadata.AdditionalProperties
+	}
+	paths := make([]catalog.ImportPath, 0, len(body.Paths))
+	for _, p := range body.Paths {
+		pathType, err := catalog.GetImportPathType(p.Type)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		paths = append(paths, catalog.ImportPath{
+			Destination: p.Destination,
+			Path:        p.Path,
+			Type:        pathType,
+		})
 	}
 
-	newCommit, err := c.Catalog.CherryPick(ctx, repository, branch, cherryPickParams, graveler.WithForce(swag.BoolValue(body.Force)))
+	importID, err := c.Catalog.Import(r.Context(), repository, branch, catalog.ImportRequest{
+		Paths: paths,
+		Commit: catalog.ImportCommit{
+			CommitMessage: body.Commit.Message,
+			Committer:     user.Committer(),
+			Metadata:      metadata,
+		},
+		Force: swag.BoolValue(body.Force),
+	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	commitResponse(w, r, newCommit)
+	writeResponse(w, r, http.StatusAccepted, apigen.ImportCreationResponse{
+		Id: importID,
+	})
 }
 
-func getCommitOverrides(commitOverrides *apigen.CommitOverrides) *graveler.CommitOverrides {
-	if commitOverrides == nil {
-		return nil
+func importStatusToResponse(status *graveler.ImportStatus) apigen.ImportStatus {
+	resp := apigen.ImportStatus{
+		Completed:       status.Completed,
+		IngestedObjects: &status.Progress,
+		UpdateTime:      status.UpdatedAt,
 	}
-	var metadata graveler.Metadata
-	if commitOverrides.Metadata != nil {
-		metadata = commitOverrides.Metadata.AdditionalProperties
+
+	if status.Error != nil {
+		resp.Error = &apigen.Error{Message: status.Error.Error()}
 	}
-	return &graveler.CommitOverrides{
-		Message:  swag.StringValue(commitOverrides.Message),
-		Metadata: metadata,
+	if status.MetaRangeID != "" {
+		metarange := status.MetaRangeID.String()
+		resp.MetarangeId = &metarange
+	}
+
+	commitLog := catalog.CommitRecordToLog(status.Commit)
+	if commitLog != nil {
+		resp.Commit = &apigen.Commit{
+			Committer:    commitLog.Committer,
+			CreationDate: commitLog.CreationDate.Unix(),
+			Id:           commitLog.Reference,
+			Message:      commitLog.Message,
+			MetaRangeId:  commitLog.MetaRangeID,
+			Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commitLog.Metadata},
+			Parents:      commitLog.Parents,
+			Version:      apiutil.Ptr(int(commitLog.Version)),
+			Generation:   apiutil.Ptr(int64(commitLog.Generation)),
+		}
 	}
+
+	return resp
 }
 
-func (c *Controller) GetCommit(w http.ResponseWriter, r *http.Request, repository, commitID string) {
+func (c *Controller) ImportStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportStatusParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.ReadCommitAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_commit", r, repository, commitID, "")
-	commit, err := c.Catalog.GetCommit(ctx, repository, commitID)
-	if errors.Is(err, graveler.ErrRepositoryNotFound) {
-		writeError(w, r, http.StatusNotFound, "repository not found")
-		return
-	}
-	if errors.Is(err, graveler.ErrNotFound) {
-		writeError(w, r, http.StatusNotFound, "commit not found")
-		return
-	}
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
+	status, err := c.Catalog.GetImportStatus(ctx, repository, params.Id)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	response := apigen.Commit{
-		Id:           commit.Reference,
-		Committer:    commit.Committer,
-		CreationDate: commit.CreationDate.Unix(),
-		Message:      commit.Message,
-		MetaRangeId:  commit.MetaRangeID,
-		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commit.Metadata},
-		Parents:      commit.Parents,
-		Generation:   apiutil.Ptr(int64(commit.Generation)),
-		Version:      apiutil.Ptr(int(commit.Version)),
-	}
-	writeResponse(w, r, http.StatusOK, response)
-}
 
-func (c *Controller) InternalGetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
-	c.GetGCRules(w, r, repository)
+	resp := importStatusToResponse(status)
+	writeResponse(w, r, http.StatusOK, resp)
 }
 
-func (c *Controller) SetGarbageCollectionRulesPreflight(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) ImportCancel(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ImportCancelParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetGarbageCollectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.ImportCancelAction,
+			Resource: permissions.BranchArn(repository, branch),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "set_gc_collection_rules_preflight", r, repository, "", "")
+	c.LogAction(ctx, "cancel_import", r, repository, branch, "")
+	err := c.Catalog.CancelImport(ctx, repository, params.Id)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) InternalSetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, body apigen.InternalSetGarbageCollectionRulesJSONRequestBody, repository string) {
-	c.SetGCRules(w, r, apigen.SetGCRulesJSONRequestBody(body), repository)
-}
+func (c *Controller) Commit(w http.ResponseWriter, r *http.Request, body apigen.CommitJSONRequestBody, repository, branch string, params apigen.CommitParams) {
+	perm := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}
+	if params.SourceMetarange != nil {
+		// committing a metarange replaces the content of the branch, including its actions configuration
+		perm = permissions.Node{
+			Type: permissions.NodeTypeAnd,
+			Nodes: []permissions.Node{
+				perm,
+				{
+					Permission: permissions.Permission{
+						Action:   permissions.WriteActionsConfigAction,
+						Resource: permissions.ObjectArn(repository, permissions.ActionsConfigPrefix),
+					},
+				},
+			},
+		}
+	}
+	if !c.authorize(w, r, perm) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_commit", r, repository, branch, "")
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	var metadata map[string]string
+	if body.Metadata != nil {
+		metadata = body.Metadata.AdditionalProperties
+	}
 
-func (c *Controller) InternalDeleteGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
-	c.DeleteGCRules(w, r, repository)
+	newCommit, err := c.Catalog.Commit(ctx, repository, branch, body.Message, user.Committer(), metadata, body.Date, params.SourceMetarange, swag.BoolValue(body.AllowEmpty), graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	commitResponse(w, r, newCommit)
 }
 
-func (c *Controller) PrepareGarbageCollectionCommits(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) CreateCommitRecord(w http.ResponseWriter, r *http.Request, body apigen.CreateCommitRecordJSONRequestBody, repository string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.PrepareGarbageCollectionCommitsAction,
+			Action:   permissions.CreateCommitAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "prepare_garbage_collection_commits", r, repository, "", "")
-	gcRunMetadata, err := c.Catalog.PrepareExpiredCommits(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	c.LogAction(ctx, "create_commit_record", r, repository, body.CommitId, "")
+	_, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
 		return
 	}
-	presignedURL, _, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
-		Identifier:     gcRunMetadata.CommitsCSVLocation,
-		IdentifierType: block.IdentifierTypeFull,
-	}, block.PreSignModeRead)
-	if err != nil {
-		c.Logger.WithError(err).Warn("Failed to presign url for GC commits")
+	err = c.Catalog.CreateCommitRecord(ctx, repository, body.CommitId, body.Version, body.Committer, body.Message, body.MetarangeId, body.CreationDate, body.Parents, body.Metadata.AdditionalProperties, int(body.Generation), graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	writeResponse(w, r, http.StatusCreated, apigen.GarbageCollectionPrepareResponse{
-		GcCommitsLocation:     gcRunMetadata.CommitsCSVLocation,
-		GcAddressesLocation:   gcRunMetadata.AddressLocation,
-		RunId:                 gcRunMetadata.RunID,
-		GcCommitsPresignedUrl: &presignedURL,
-	})
+
+	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) InternalGetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
-	c.GetBranchProtectionRules(w, r, repository)
+func commitResponse(w http.ResponseWriter, r *http.Request, newCommit *catalog.CommitLog) {
+	writeResponse(w, r, http.StatusCreated, newCommitFromLog(newCommit))
 }
 
-func (c *Controller) InternalDeleteBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalDeleteBranchProtectionRuleJSONRequestBody, repository string) {
+func newCommitFromLog(commitLog *catalog.CommitLog) apigen.Commit {
+	return apigen.Commit{
+		Committer:    commitLog.Committer,
+		CreationDate: commitLog.CreationDate.Unix(),
+		Id:           commitLog.Reference,
+		Message:      commitLog.Message,
+		MetaRangeId:  commitLog.MetaRangeID,
+		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commitLog.Metadata},
+		Parents:      commitLog.Parents,
+		Version:      apiutil.Ptr(int(commitLog.Version)),
+		Generation:   apiutil.Ptr(int64(commitLog.Generation)),
+	}
+}
+
+func (c *Controller) DiffBranch(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DiffBranchParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
+			Action:   permissions.ListObjectsAction,
 			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "delete_branch_protection_rule", r, repository, "", "")
+	c.LogAction(ctx, "diff_workspace", r, repository, branch, "")
 
-	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	diff, hasMore, err := c.Catalog.DiffUncommitted(
+		ctx,
+		repository,
+		branch,
+		paginationPrefix(params.Prefix),
+		paginationDelimiter(params.Delimiter),
+		paginationAmount(params.Amount),
+		paginationAfter(params.After),
+	)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	for p := range rules.BranchPatternToBlockedActions {
-		if p == body.Pattern {
-			delete(rules.BranchPatternToBlockedActions, p)
-			err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
-			if c.handleAPIError(ctx, w, r, err) {
-				return
-			}
-			writeResponse(w, r, http.StatusNoContent, nil)
-			return
+
+	results := make([]apigen.Diff, 0, len(diff))
+	for _, d := range diff {
+		pathType := entryTypeObject
+		if d.CommonLevel {
+			pathType = entryTypeCommonPrefix
+		}
+		diff := apigen.Diff{
+			Path:     d.Path,
+			Type:     transformDifferenceTypeToString(d.Type),
+			PathType: pathType,
 		}
+		if !d.CommonLevel {
+			diff.SizeBytes = swag.Int64(d.Size)
+		}
+		results = append(results, diff)
 	}
-	writeResponse(w, r, http.StatusNotFound, nil)
+	response := apigen.DiffList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
+	}
+	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateBranchProtectionRulePreflight(w http.ResponseWriter, r *http.Request, repository string) {
+func (c *Controller) DeleteObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.DeleteObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.DeleteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch_protection_rule_preflight", r, repository, "", "")
-
+	c.LogAction(ctx, "delete_object", r, repository, branch, "")
+	err := c.Catalog.DeleteEntry(ctx, repository, branch, params.Path, graveler.WithForce(swag.BoolValue(params.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) InternalCreateBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalCreateBranchProtectionRuleJSONRequestBody, repository string) {
+func (c *Controller) UploadObjectPreflight(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectPreflightParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
-			Action:   permissions.SetBranchProtectionRulesAction,
-			Resource: permissions.RepoArn(repository),
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
+
 	ctx := r.Context()
-	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
-	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
-	if !errors.Is(err, graveler.ErrNotFound) {
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-	}
-	if rules == nil {
-		rules = &graveler.BranchProtectionRules{}
-	}
-	if rules.BranchPatternToBlockedActions == nil {
-		rules.BranchPatternToBlockedActions = make(map[string]*graveler.BranchProtectionBlockedActions)
-	}
-	blockedActions := &graveler.BranchProtectionBlockedActions{
-		Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT},
-	}
-	rules.BranchPatternToBlockedActions[body.Pattern] = blockedActions
-	err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
-	}
+	c.LogAction(ctx, "put_object_preflight", r, repository, branch, "")
+
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
-func (c *Controller) GetMetaRange(w http.ResponseWriter, r *http.Request, repository, metaRange string) {
+func (c *Controller) UploadObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.UploadObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListObjectsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ReadRepositoryAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "metadata_get_metarange", r, repository, "", "")
+	c.LogAction(ctx, "put_object", r, repository, branch, "")
 
-	metarange, err := c.Catalog.GetMetaRange(ctx, repository, metaRange)
+	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	response := apigen.StorageURI{
-		Location: string(metarange),
+	// check if branch exists - it is still a possibility, but we don't want to upload large object when the branch was not there in the first place
+	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	w.Header().Set("Location", string(metarange))
-	writeResponse(w, r, http.StatusOK, response)
-}
-
-func (c *Controller) GetRange(w http.ResponseWriter, r *http.Request, repository, pRange string) {
-	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListObjectsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ReadRepositoryAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-		},
-	}) {
+	if !branchExists {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "metadata_get_range", r, repository, "", "")
 
-	rng, err := c.Catalog.GetRange(ctx, repository, pRange)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	// before writing body, ensure preconditions - this means we essentially check for object existence twice:
+	// once before uploading the body to save resources and time,
+	//	and then graveler will check again when passed a SetOptions.
+	allowOverwrite := true
+	if params.IfNoneMatch != nil {
+		if swag.StringValue((*string)(params.IfNoneMatch)) != "*" {
+			writeError(w, r, http.StatusBadRequest, "Unsupported value for If-None-Match - Only \"*\" is supported")
+			return
+		}
+		// check if exists
+		_, err := c.Catalog.GetEntry(ctx, repo.Name, branch, params.Path, catalog.GetEntryParams{})
+		if err == nil {
+			writeError(w, r, http.StatusPreconditionFailed, "path already exists")
+			return
+		}
+		if !errors.Is(err, graveler.ErrNotFound) {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		allowOverwrite = false
 	}
-	response := apigen.StorageURI{
-		Location: string(rng),
+
+	// read request body parse multipart for "content" and upload the data
+	contentType := catalog.ContentTypeOrDefault(r.Header.Get("Content-Type"))
+	mediaType, p, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
 	}
-	w.Header().Set("Location", string(rng))
-	writeResponse(w, r, http.StatusOK, response)
-}
 
-func (c *Controller) DumpRefs(w http.ResponseWriter, r *http.Request, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListTagsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListBranchesAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListCommitsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-		},
-	}) {
+	meta := extractLakeFSMetadata(r.Header)
+	if err := catalog.ValidateUserMetadata(meta); err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "dump_repository_refs", r, repository, "", "")
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	checksums := upload.Checksums{
+		SHA256: swag.StringValue(params.XLakefsChecksumSha256),
+		CRC32C: swag.StringValue(params.XLakefsChecksumCrc32c),
+	}
+	if err := checksums.Validate(); err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	// dump all types:
-	tagsID, err := c.Catalog.DumpTags(ctx, repository)
+	var blob *upload.Blob
+	if mediaType != "multipart/form-data" {
+		// handle non-multipart, direct content upload
+		address := c.PathProvider.NewPath()
+		blob, err = upload.WriteBlobWithChecksums(ctx, c.BlockAdapter, repo.StorageNamespace, address, r.Body, r.ContentLength,
+			block.PutOpts{StorageClass: params.StorageClass}, checksums)
+		if errors.Is(err, upload.ErrChecksumMismatch) {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		// handle multipart upload
+		boundary, ok := p["boundary"]
+		if !ok {
+			writeError(w, r, http.StatusInternalServerError, http.ErrMissingBoundary)
+			return
+		}
+
+		contentUploaded := false
+		reader := multipart.NewReader(r.Body, boundary)
+		for !contentUploaded {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				writeError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			contentType = part.Header.Get("Content-Type")
+			partName := part.FormName()
+			if partName == "content" {
+				// upload the first "content" and exit the loop
+				address := c.PathProvider.NewPath()
+				blob, err = upload.WriteBlobWithChecksums(ctx, c.BlockAdapter, repo.StorageNamespace, address, part, -1, block.PutOpts{StorageClass: params.StorageClass}, checksums)
+				if errors.Is(err, upload.ErrChecksumMismatch) {
+					_ = part.Close()
+					writeError(w, r, http.StatusBadRequest, err)
+					return
+				}
+				if err != nil {
+					_ = part.Close()
+					writeError(w, r, http.StatusInternalServerError, err)
+					return
+				}
+				contentUploaded = true
+			}
+			_ = part.Close()
+		}
+		if !contentUploaded {
+			err := fmt.Errorf("multipart upload missing key 'content': %w", http.ErrMissingFile)
+			writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	// write metadata
+	writeTime := time.Now()
+	entryBuilder := catalog.NewDBEntryBuilder().
+		Path(params.Path).
+		PhysicalAddress(blob.PhysicalAddress).
+		CreationDate(writeTime).
+		Size(blob.Size).
+		Checksum(blob.Checksum).
+		ContentType(contentType)
+	if blob.RelativePath {
+		entryBuilder.AddressType(catalog.AddressTypeRelative)
+	} else {
+		entryBuilder.AddressType(catalog.AddressTypeFull)
+	}
+	// store the client checksums verified against the stored data
+	blob.Checksums.SetMetadata(meta)
+	encryption, err := c.Catalog.RepositoryEncryption(ctx, repo.Name)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	encryption.SetMetadata(meta)
+	entryBuilder.Metadata(meta)
+	entry := entryBuilder.Build()
 
-	branchesID, err := c.Catalog.DumpBranches(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
+	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithIfAbsent(!allowOverwrite), graveler.WithForce(swag.BoolValue(params.Force)))
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		writeError(w, r, http.StatusPreconditionFailed, "path already exists")
 		return
 	}
-	commitsID, err := c.Catalog.DumpCommits(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	response := apigen.RefsDump{
-		BranchesMetaRangeId: branchesID,
-		CommitsMetaRangeId:  commitsID,
-		TagsMetaRangeId:     tagsID,
+	identifierType := block.IdentifierTypeFull
+	if blob.RelativePath {
+		identifierType = block.IdentifierTypeRelative
 	}
 
-	// write this to the block store
-	manifestBytes, err := json.MarshalIndent(response, "", "  ")
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, blob.PhysicalAddress, identifierType)
 	if err != nil {
 		writeError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       fmt.Sprintf("%s/refs_manifest.json", c.Config.Committed.BlockStoragePrefix),
-	}, int64(len(manifestBytes)), bytes.NewReader(manifestBytes), block.PutOpts{})
-	if err != nil {
-		writeError(w, r, http.StatusInternalServerError, err)
-		return
+
+	response := apigen.ObjectStats{
+		Checksum:        blob.Checksum,
+		Mtime:           writeTime.Unix(),
+		Path:            params.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(blob.Size),
+		ContentType:     &contentType,
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta},
 	}
 	writeResponse(w, r, http.StatusCreated, response)
 }
 
-func (c *Controller) RestoreRefs(w http.ResponseWriter, r *http.Request, body apigen.RestoreRefsJSONRequestBody, repository string) {
+func (c *Controller) AppendObject(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.AppendObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateTagAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, params.Path),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, params.Path),
 				},
 			},
 		},
@@ -3852,173 +4314,180 @@ func (c *Controller) RestoreRefs(w http.ResponseWriter, r *http.Request, body ap
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "restore_repository_refs", r, repository, "", "")
+	c.LogAction(ctx, "append_object", r, repository, branch, params.Path)
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	// ensure no refs currently found
-	_, _, err = c.Catalog.ListCommits(ctx, repo.Name, repo.DefaultBranch, catalog.LogParams{
-		PathList:      make([]catalog.PathRecord, 0),
-		FromReference: "",
-		Amount:        1,
-	})
-	if !errors.Is(err, graveler.ErrNotFound) {
-		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
+	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	// load commits
-	err = c.Catalog.LoadCommits(ctx, repo.Name, body.CommitsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
+	if !branchExists {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
 		return
 	}
 
-	err = c.Catalog.LoadBranches(ctx, repo.Name, body.BranchesMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
+	entry, err := c.Catalog.GetEntry(ctx, repository, branch, params.Path, catalog.GetEntryParams{})
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		c.handleAPIError(ctx, w, r, err)
 		return
 	}
-
-	err = c.Catalog.LoadTags(ctx, repo.Name, body.TagsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
-	if c.handleAPIError(ctx, w, r, err) {
+	if entry != nil && entry.AliasTarget != "" {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("cannot append to alias of '%s'", entry.AliasTarget))
 		return
 	}
-}
 
-func (c *Controller) DumpSubmit(w http.ResponseWriter, r *http.Request, repository string) {
-	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListTagsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListBranchesAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListCommitsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-		},
-	}) {
+	address := c.PathProvider.NewPath()
+	var (
+		blob        *upload.Blob
+		contentType string
+		meta        = map[string]string{}
+	)
+	if entry == nil {
+		contentType = catalog.ContentTypeOrDefault(r.Header.Get("Content-Type"))
+		blob, err = upload.WriteBlob(ctx, c.BlockAdapter, repo.StorageNamespace, address, r.Body, r.ContentLength, block.PutOpts{})
+	} else {
+		contentType = entry.ContentType
+		for k, v := range entry.Metadata {
+			meta[k] = v
+		}
+		// checksums of the previous data no longer apply
+		delete(meta, upload.ChecksumSHA256MetadataKey)
+		delete(meta, upload.ChecksumCRC32CMetadataKey)
+		source := block.ObjectPointer{
+			StorageNamespace: repo.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}
+		blob, err = upload.AppendBlob(ctx, c.BlockAdapter, repo.StorageNamespace, source, entry.Size, address, r.Body, r.ContentLength, block.PutOpts{})
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	ctx := r.Context()
-	c.LogAction(ctx, "dump_repository", r, repository, "", "")
 
-	taskID, err := c.Catalog.DumpRepositorySubmit(ctx, repository)
+	encryption, err := c.Catalog.RepositoryEncryption(ctx, repo.Name)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeTime := time.Now()
+	encryption.SetMetadata(meta)
+	newEntry := catalog.NewDBEntryBuilder().
+		Path(params.Path).
+		PhysicalAddress(blob.PhysicalAddress).
+		AddressType(catalog.AddressTypeRelative).
+		CreationDate(writeTime).
+		Size(blob.Size).
+		Checksum(blob.Checksum).
+		ContentType(contentType).
+		Metadata(meta).
+		Build()
+	err = c.Catalog.CreateEntry(ctx, repository, branch, newEntry, graveler.WithForce(swag.BoolValue(params.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
-		Id: taskID,
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, blob.PhysicalAddress, block.IdentifierTypeRelative)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, apigen.ObjectStats{
+		Checksum:        blob.Checksum,
+		Mtime:           writeTime.Unix(),
+		Path:            params.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(blob.Size),
+		ContentType:     &contentType,
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: meta},
 	})
 }
 
-func (c *Controller) DumpStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.DumpStatusParams) {
+func (c *Controller) UpdateObjectMetadata(w http.ResponseWriter, r *http.Request, body apigen.UpdateObjectMetadataJSONRequestBody, repository, branch string, params apigen.UpdateObjectMetadataParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListTagsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListBranchesAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListCommitsAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
-
-	// get the current status
 	ctx := r.Context()
-	status, err := c.Catalog.DumpRepositoryStatus(ctx, repository, params.TaskId)
+	c.LogAction(ctx, "update_object_metadata", r, repository, branch, params.Path)
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-
-	// build response based on status
-	response := &apigen.RepositoryDumpStatus{
-		Id:         params.TaskId,
-		Done:       status.Task.Done,
-		UpdateTime: status.Task.UpdatedAt.AsTime(),
-	}
-	if status.Task.Error != "" {
-		response.Error = apiutil.Ptr(status.Task.Error)
+	entry, err := c.Catalog.UpdateEntryMetadata(ctx, repository, branch, params.Path, catalog.UpdateEntryMetadataParams{
+		Metadata:    body.Metadata.AdditionalProperties,
+		Replace:     swag.BoolValue(body.Replace),
+		ContentType: body.ContentType,
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	if status.Task.Done && status.Info != nil {
-		response.Refs = &apigen.RefsDump{
-			CommitsMetaRangeId:  status.Info.CommitsMetarangeId,
-			TagsMetaRangeId:     status.Info.TagsMetarangeId,
-			BranchesMetaRangeId: status.Info.BranchesMetarangeId,
-		}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
 	}
-	writeResponse(w, r, http.StatusOK, response)
+	writeResponse(w, r, http.StatusOK, apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata},
+	})
 }
 
-func (c *Controller) RestoreSubmit(w http.ResponseWriter, r *http.Request, body apigen.RestoreSubmitJSONRequestBody, repository string) {
+func (c *Controller) TagObjectsSubmit(w http.ResponseWriter, r *http.Request, body apigen.TagObjectsSubmitJSONRequestBody, repository, branch string) {
 	if !c.authorize(w, r, permissions.Node{
 		Type: permissions.NodeTypeAnd,
 		Nodes: []permissions.Node{
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateTagAction,
+					Action:   permissions.ListObjectsAction,
 					Resource: permissions.RepoArn(repository),
 				},
 			},
 			{
 				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, "*"),
 				},
 			},
 			{
 				Permission: permissions.Permission{
 					Action:   permissions.CreateCommitAction,
-					Resource: permissions.RepoArn(repository),
+					Resource: permissions.BranchArn(repository, branch),
 				},
 			},
 		},
 	}) {
 		return
 	}
-
 	ctx := r.Context()
-	c.LogAction(ctx, "restore_repository", r, repository, "", "")
+	c.LogAction(ctx, "tag_objects", r, repository, branch, "")
 
-	info := &catalog.RepositoryDumpInfo{
-		CommitsMetarangeId:  body.CommitsMetaRangeId,
-		TagsMetarangeId:     body.TagsMetaRangeId,
-		BranchesMetarangeId: body.BranchesMetaRangeId,
-	}
-	taskID, err := c.Catalog.RestoreRepositorySubmit(ctx, repository, info, graveler.WithForce(swag.BoolValue(body.Force)))
-	if errors.Is(err, catalog.ErrNonEmptyRepository) {
-		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
 		return
 	}
+	taskID, err := c.Catalog.TagObjectsSubmit(ctx, repository, branch, catalog.TagObjectsParams{
+		Pattern:       body.Pattern,
+		Metadata:      body.Metadata.AdditionalProperties,
+		CommitMessage: swag.StringValue(body.CommitMessage),
+		Committer:     user.Committer(),
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
@@ -4027,105 +4496,1697 @@ func (c *Controller) RestoreSubmit(w http.ResponseWriter, r *http.Request, body
 	})
 }
 
-func (c *Controller) RestoreStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.RestoreStatusParams) {
+func (c *Controller) TagObjectsStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.TagObjectsStatusParams) {
 	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateTagAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateBranchAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.CreateCommitAction,
-					Resource: permissions.RepoArn(repository),
-				},
-			},
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
-
-	// get the current status
 	ctx := r.Context()
-	status, err := c.Catalog.RestoreRepositoryStatus(ctx, repository, params.TaskId)
+	status, err := c.Catalog.TagObjectsStatus(ctx, repository, params.TaskId)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// build response based on status
-	response := &apigen.RepositoryRestoreStatus{
-		Id:         params.TaskId,
-		Done:       status.Task.Done,
-		UpdateTime: status.Task.UpdatedAt.AsTime(),
+	response := &apigen.ObjectsTaggingStatus{
+		Id:          params.TaskId,
+		Done:        status.Task.Done,
+		UpdateTime:  status.Task.UpdatedAt.AsTime(),
+		TaggedCount: status.Task.Progress,
 	}
 	if status.Task.Error != "" {
 		response.Error = apiutil.Ptr(status.Task.Error)
 	}
+	if status.CommitId != "" {
+		response.CommitId = apiutil.Ptr(status.CommitId)
+	}
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) CreateSymlinkFile(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.CreateSymlinkFileParams) {
+func (c *Controller) StageObject(w http.ResponseWriter, r *http.Request, body apigen.StageObjectJSONRequestBody, repository, branch string, params apigen.StageObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repository, branch),
+			Resource: permissions.ObjectArn(repository, params.Path),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "create_symlink", r, repository, branch, "")
+	c.LogAction(ctx, "stage_object", r, repository, branch, "")
 
-	// read repo
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	// write metadata
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, body.PhysicalAddress, block.IdentifierTypeFull)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 
-	// list entries
-	var currentPath string
-	var currentAddresses []string
-	var after string
-	var entries []*catalog.DBEntry
-	hasMore := true
-	for hasMore {
-		entries, hasMore, err = c.Catalog.ListEntries(
-			ctx,
-			repository,
-			branch,
-			swag.StringValue(params.Location),
-			after,
-			"",
-			-1)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		// loop all entries enter to map[path] physicalAddress
-		for _, entry := range entries {
-			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
-			if err != nil {
-				writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while resolving address: %s", err))
-				return
-			}
-			idx := strings.LastIndex(entry.Path, "/")
-			var path string
-			if idx != -1 {
-				path = entry.Path[0:idx]
-			}
-			if path != currentPath {
-				// push current
-				err := writeSymlink(ctx, repo, branch, path, currentAddresses, c.BlockAdapter)
-				if err != nil {
-					writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while writing symlinks: %s", err))
+	// see what storage type this is and whether it fits our configuration
+	uriRegex := c.BlockAdapter.GetStorageNamespaceInfo().ValidityRegex
+	if match, err := regexp.MatchString(uriRegex, body.PhysicalAddress); err != nil || !match {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("physical address is not valid for block adapter: %s",
+			c.BlockAdapter.BlockstoreType(),
+		))
+		return
+	}
+
+	// take mtime from request, if any
+	writeTime := time.Now()
+	if body.Mtime != nil {
+		writeTime = time.Unix(*body.Mtime, 0)
+	}
+
+	physicalAddress, addressType := normalizePhysicalAddress(repo.StorageNamespace, body.PhysicalAddress)
+
+	entryBuilder := catalog.NewDBEntryBuilder().
+		CommonLevel(false).
+		Path(params.Path).
+		PhysicalAddress(physicalAddress).
+		AddressType(addressType).
+		CreationDate(writeTime).
+		Size(body.SizeBytes).
+		Checksum(body.Checksum).
+		ContentType(swag.StringValue(body.ContentType))
+	if body.Metadata != nil {
+		if err := catalog.ValidateUserMetadata(body.Metadata.AdditionalProperties); err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		entryBuilder.Metadata(body.Metadata.AdditionalProperties)
+	}
+	entry := entryBuilder.Build()
+
+	err = c.Catalog.CreateEntry(ctx, repo.Name, branch, entry, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) CopyObject(w http.ResponseWriter, r *http.Request, body apigen.CopyObjectJSONRequestBody, repository, branch string, params apigen.CopyObjectParams) {
+	srcPath := body.SrcPath
+	destPath := params.DestPath
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, srcPath),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, destPath),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "copy_object", r, repository, branch, destPath)
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// verify destination is a branch (and exists)
+	branchExists, err := c.Catalog.BranchExists(ctx, repository, branch)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !branchExists {
+		writeError(w, r, http.StatusNotFound, fmt.Sprintf("branch '%s' not found", branch))
+		return
+	}
+
+	// use destination branch as source if not specified
+	srcRef := swag.StringValue(body.SrcRef)
+	if srcRef == "" {
+		srcRef = branch
+	}
+
+	// copying an alias copies the object it points to, which the user must be allowed to read
+	srcEntry, err := c.Catalog.GetEntry(ctx, repository, srcRef, srcPath, catalog.GetEntryParams{})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !c.authorizeAliasTarget(w, r, repository, srcEntry, writeError) {
+		return
+	}
+	if srcEntry.AliasTarget != "" {
+		srcPath = srcEntry.AliasTarget
+	}
+
+	// copy entry
+	entry, err := c.Catalog.CopyEntry(ctx, repository, srcRef, srcPath, repository, branch, destPath, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, block.IdentifierTypeRelative)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	var metadata map[string]string
+	if entry.Metadata != nil {
+		metadata = entry.Metadata
+	} else {
+		metadata = map[string]string{}
+	}
+
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: metadata},
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) MoveObject(w http.ResponseWriter, r *http.Request, body apigen.MoveObjectJSONRequestBody, repository, branch string, params apigen.MoveObjectParams) {
+	srcPath := body.SrcPath
+	destPath := params.DestPath
+	commitMessage := swag.StringValue(body.CommitMessage)
+	nodes := []permissions.Node{
+		{
+			Permission: permissions.Permission{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(repository, srcPath),
+			},
+		},
+		{
+			Permission: permissions.Permission{
+				Action:   permissions.DeleteObjectAction,
+				Resource: permissions.ObjectArn(repository, srcPath),
+			},
+		},
+		{
+			Permission: permissions.Permission{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(repository, destPath),
+			},
+		},
+	}
+	if commitMessage != "" {
+		nodes = append(nodes, permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(repository, branch),
+			},
+		})
+	}
+	if !c.authorize(w, r, permissions.Node{
+		Type:  permissions.NodeTypeAnd,
+		Nodes: nodes,
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "move_object", r, repository, branch, destPath)
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	entry, commitLog, err := c.Catalog.MoveEntry(ctx, repository, branch, srcPath, destPath, catalog.MoveEntryParams{
+		CommitMessage: commitMessage,
+		Committer:     user.Committer(),
+	}, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	response := apigen.ObjectMoveResult{
+		Object: apigen.ObjectStats{
+			Checksum:        entry.Checksum,
+			Mtime:           entry.CreationDate.Unix(),
+			Path:            entry.Path,
+			PathType:        entryTypeObject,
+			PhysicalAddress: qk.Format(),
+			SizeBytes:       swag.Int64(entry.Size),
+			ContentType:     swag.String(entry.ContentType),
+			Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata},
+		},
+	}
+	if commitLog != nil {
+		commit := newCommitFromLog(commitLog)
+		response.Commit = &commit
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) CreateObjectAlias(w http.ResponseWriter, r *http.Request, body apigen.CreateObjectAliasJSONRequestBody, repository, branch string, params apigen.CreateObjectAliasParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, body.Target),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.WriteObjectAction,
+					Resource: permissions.ObjectArn(repository, params.Path),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "create_object_alias", r, repository, branch, params.Path)
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	entry, err := c.Catalog.CreateAlias(ctx, repository, branch, params.Path, body.Target)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	response := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+		Metadata:        &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata},
+		AliasTarget:     swag.String(body.Target),
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func lockResponse(lock *catalog.Lock) apigen.Lock {
+	response := apigen.Lock{
+		Path:       lock.Path,
+		Owner:      lock.Owner,
+		AcquiredAt: lock.AcquiredAt.Unix(),
+		ExpiresAt:  lock.ExpiresAt.Unix(),
+	}
+	if lock.Token != "" {
+		response.Token = swag.String(lock.Token)
+	}
+	return response
+}
+
+func (c *Controller) GetLock(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.GetLockParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_lock", r, repository, branch, params.Path)
+
+	lock, err := c.Catalog.GetLock(ctx, repository, branch, params.Path)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, lockResponse(lock))
+}
+
+func (c *Controller) AcquireLock(w http.ResponseWriter, r *http.Request, body apigen.AcquireLockJSONRequestBody, repository, branch string, params apigen.AcquireLockParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "acquire_lock", r, repository, branch, params.Path)
+
+	// verify the branch exists, locks are scoped to existing branches
+	if _, err := c.Catalog.GetBranchReference(ctx, repository, branch); c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	ttl := time.Duration(swag.Int64Value(body.TtlSeconds)) * time.Second
+	lock, err := c.Catalog.AcquireLock(ctx, repository, branch, params.Path, body.Owner, ttl)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, lockResponse(lock))
+}
+
+func (c *Controller) RenewLock(w http.ResponseWriter, r *http.Request, body apigen.RenewLockJSONRequestBody, repository, branch string, params apigen.RenewLockParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "renew_lock", r, repository, branch, params.Path)
+
+	ttl := time.Duration(swag.Int64Value(body.TtlSeconds)) * time.Second
+	lock, err := c.Catalog.RenewLock(ctx, repository, branch, params.Path, body.Token, ttl)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, lockResponse(lock))
+}
+
+func (c *Controller) ReleaseLock(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.ReleaseLockParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "release_lock", r, repository, branch, params.Path)
+
+	err := c.Catalog.ReleaseLock(ctx, repository, branch, params.Path, params.Token)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) RevertBranch(w http.ResponseWriter, r *http.Request, body apigen.RevertBranchJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.RevertBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "revert_branch", r, repository, branch, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "user not found")
+		return
+	}
+	revertParams := catalog.RevertParams{
+		Reference:       body.Ref,
+		Committer:       user.Committer(),
+		ParentNumber:    body.ParentNumber,
+		AllowEmpty:      swag.BoolValue(body.AllowEmpty),
+		CommitOverrides: getCommitOverrides(body.CommitOverrides),
+	}
+	err = c.Catalog.Revert(ctx, repository, branch, revertParams, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) CherryPick(w http.ResponseWriter, r *http.Request, body apigen.CherryPickJSONRequestBody, repository string, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "cherry_pick", r, repository, branch, body.Ref)
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "user not found")
+		return
+	}
+	cherryPickParams := catalog.CherryPickParams{
+		Reference:       body.Ref,
+		Committer:       user.Committer(),
+		ParentNumber:    body.ParentNumber,
+		CommitOverrides: getCommitOverrides(body.CommitOverrides),
+	}
+
+	newCommit, err := c.Catalog.CherryPick(ctx, repository, branch, cherryPickParams, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	commitResponse(w, r, newCommit)
+}
+
+func (c *Controller) SplitCommit(w http.ResponseWriter, r *http.Request, body apigen.SplitCommitJSONRequestBody, repository, branch string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+			{
+				// splitting rewrites the history of the branch
+				Permission: permissions.Permission{
+					Action:   permissions.RevertBranchAction,
+					Resource: permissions.BranchArn(repository, branch),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "split_commit", r, repository, branch, body.Ref)
+
+	commits, err := c.Catalog.SplitCommit(ctx, repository, branch, body.Ref, body.Prefixes, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.CommitSplitResult{
+		Results: make([]apigen.Commit, 0, len(commits)),
+	}
+	for _, commit := range commits {
+		response.Results = append(response.Results, newCommitFromLog(commit))
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func getCommitOverrides(commitOverrides *apigen.CommitOverrides) *graveler.CommitOverrides {
+	if commitOverrides == nil {
+		return nil
+	}
+	var metadata graveler.Metadata
+	if commitOverrides.Metadata != nil {
+		metadata = commitOverrides.Metadata.AdditionalProperties
+	}
+	return &graveler.CommitOverrides{
+		Message:  swag.StringValue(commitOverrides.Message),
+		Metadata: metadata,
+	}
+}
+
+func (c *Controller) GetCommit(w http.ResponseWriter, r *http.Request, repository, commitID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadCommitAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_commit", r, repository, commitID, "")
+	commit, err := c.Catalog.GetCommit(ctx, repository, commitID)
+	if errors.Is(err, graveler.ErrRepositoryNotFound) {
+		writeError(w, r, http.StatusNotFound, "repository not found")
+		return
+	}
+	if errors.Is(err, graveler.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "commit not found")
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	response := apigen.Commit{
+		Id:           commit.Reference,
+		Committer:    commit.Committer,
+		CreationDate: commit.CreationDate.Unix(),
+		Message:      commit.Message,
+		MetaRangeId:  commit.MetaRangeID,
+		Metadata:     &apigen.Commit_Metadata{AdditionalProperties: commit.Metadata},
+		Parents:      commit.Parents,
+		Generation:   apiutil.Ptr(int64(commit.Generation)),
+		Version:      apiutil.Ptr(int(commit.Version)),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListDanglingCommits(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListDanglingCommitsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadCommitAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_dangling_commits", r, repository, "", "")
+	commits, hasMore, err := c.Catalog.ListDanglingCommits(ctx, repository, paginationAfter(params.After), paginationAmount(params.Amount))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.DanglingCommit, 0, len(commits))
+	for _, commit := range commits {
+		results = append(results, apigen.DanglingCommit{
+			Commit: newCommitFromLog(commit.Commit),
+			Head:   commit.Head,
+		})
+	}
+	pagination := paginationFor(hasMore, nil, "")
+	pagination.Results = len(results)
+	if hasMore && len(results) > 0 {
+		pagination.NextOffset = results[len(results)-1].Commit.Id
+	}
+	writeResponse(w, r, http.StatusOK, apigen.DanglingCommitList{
+		Results:    results,
+		Pagination: pagination,
+	})
+}
+
+// optionalString returns nil for an empty string, to omit it from responses
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return swag.String(s)
+}
+
+func lineageEdgeResponse(edge *catalog.LineageEdge) apigen.LineageEdge {
+	return apigen.LineageEdge{
+		Id:               edge.ID,
+		Type:             apigen.LineageEdgeType(edge.Type),
+		Repository:       edge.Repository,
+		CommitId:         edge.CommitID,
+		From:             edge.From(),
+		To:               edge.To(),
+		Path:             optionalString(edge.Path),
+		JobSystem:        optionalString(edge.JobSystem),
+		RunId:            optionalString(edge.RunID),
+		CodeVersion:      optionalString(edge.CodeVersion),
+		SourceRepository: optionalString(edge.SourceRepository),
+		SourceRef:        optionalString(edge.SourceRef),
+		SourceCommitId:   optionalString(edge.SourceCommitID),
+		SourcePath:       optionalString(edge.SourcePath),
+		CreatedBy:        optionalString(edge.CreatedBy),
+		CreationDate:     edge.CreatedAt.Unix(),
+	}
+}
+
+func (c *Controller) AddCommitLineage(w http.ResponseWriter, r *http.Request, body apigen.AddCommitLineageJSONRequestBody, repository, commitID string) {
+	perms := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateLineageAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}
+	sourceRepository := swag.StringValue(body.SourceRepository)
+	if sourceRepository != "" {
+		// deriving from data requires reading it
+		perms = permissions.Node{
+			Type: permissions.NodeTypeAnd,
+			Nodes: []permissions.Node{
+				perms,
+				{
+					Permission: permissions.Permission{
+						Action:   permissions.ReadCommitAction,
+						Resource: permissions.RepoArn(sourceRepository),
+					},
+				},
+			},
+		}
+	}
+	if !c.authorize(w, r, perms) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "add_commit_lineage", r, repository, commitID, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	edge, err := c.Catalog.AddLineageEdge(ctx, repository, commitID, catalog.LineageEdge{
+		Type:             string(body.Type),
+		Path:             swag.StringValue(body.Path),
+		JobSystem:        swag.StringValue(body.JobSystem),
+		RunID:            swag.StringValue(body.RunId),
+		CodeVersion:      swag.StringValue(body.CodeVersion),
+		SourceRepository: sourceRepository,
+		SourceRef:        swag.StringValue(body.SourceRef),
+		SourcePath:       swag.StringValue(body.SourcePath),
+		CreatedBy:        user.Username,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, lineageEdgeResponse(edge))
+}
+
+func (c *Controller) ListCommitLineage(w http.ResponseWriter, r *http.Request, repository, commitID string, params apigen.ListCommitLineageParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadCommitAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_commit_lineage", r, repository, commitID, "")
+
+	edges, hasMore, err := c.Catalog.ListLineageEdges(ctx, repository, commitID, paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.LineageEdge, 0, len(edges))
+	for _, edge := range edges {
+		results = append(results, lineageEdgeResponse(edge))
+	}
+	writeResponse(w, r, http.StatusOK, apigen.LineageEdgeList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Id"),
+	})
+}
+
+func (c *Controller) LinkMlflowRun(w http.ResponseWriter, r *http.Request, body apigen.LinkMlflowRunJSONRequestBody, repository, commitID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateLineageAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "link_mlflow_run", r, repository, commitID, "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	var edgeType string
+	switch body.Role {
+	case apigen.MlflowRunLinkRoleInput:
+		edgeType = catalog.LineageEdgeTypeUsedBy
+	case apigen.MlflowRunLinkRoleOutput:
+		edgeType = catalog.LineageEdgeTypeProducedBy
+	default:
+		writeError(w, r, http.StatusBadRequest, "invalid role")
+		return
+	}
+	edge, err := c.Catalog.AddLineageEdge(ctx, repository, commitID, catalog.LineageEdge{
+		Type:        edgeType,
+		Path:        swag.StringValue(body.Path),
+		JobSystem:   catalog.MLflowJobSystem,
+		RunID:       body.RunId,
+		CodeVersion: swag.StringValue(body.CodeVersion),
+		CreatedBy:   user.Username,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, lineageEdgeResponse(edge))
+}
+
+func (c *Controller) ListJobRunLineage(w http.ResponseWriter, r *http.Request, repository, jobSystem, runID string, params apigen.ListJobRunLineageParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadCommitAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_job_run_lineage", r, repository, "", "")
+
+	edges, hasMore, err := c.Catalog.ListJobRunLineage(ctx, repository, jobSystem, runID, paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.LineageEdge, 0, len(edges))
+	for _, edge := range edges {
+		results = append(results, lineageEdgeResponse(edge))
+	}
+	writeResponse(w, r, http.StatusOK, apigen.LineageEdgeList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Id"),
+	})
+}
+
+func (c *Controller) GetLineageGraph(w http.ResponseWriter, r *http.Request, repository string, params apigen.GetLineageGraphParams) {
+	readCommitPerms := func(repository string) permissions.Node {
+		return permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(repository),
+			},
+		}
+	}
+	if !c.authorize(w, r, readCommitPerms(repository)) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_lineage_graph", r, repository, swag.StringValue(params.Ref), swag.StringValue(params.Path))
+
+	direction := catalog.LineageDirectionUpstream
+	if params.Direction != nil {
+		direction = string(*params.Direction)
+	}
+	graph, err := c.Catalog.GetLineageGraph(ctx, catalog.LineageGraphParams{
+		Repository: repository,
+		Ref:        swag.StringValue(params.Ref),
+		Path:       swag.StringValue(params.Path),
+		Direction:  direction,
+		Depth:      swag.IntValue(params.Depth),
+		// lineage is followed only into repositories the user may read
+		CanRead: func(repository string) bool {
+			return c.authorizeCallback(w, r, readCommitPerms(repository), func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {})
+		},
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.LineageGraph{
+		Nodes:     make([]apigen.LineageNode, 0, len(graph.Nodes)),
+		Edges:     make([]apigen.LineageEdge, 0, len(graph.Edges)),
+		Truncated: graph.Truncated,
+	}
+	for _, node := range graph.Nodes {
+		response.Nodes = append(response.Nodes, apigen.LineageNode{
+			Id:          node.ID,
+			Type:        apigen.LineageNodeType(node.Type),
+			Repository:  optionalString(node.Repository),
+			CommitId:    optionalString(node.CommitID),
+			JobSystem:   optionalString(node.JobSystem),
+			RunId:       optionalString(node.RunID),
+			CodeVersion: optionalString(node.CodeVersion),
+		})
+	}
+	for _, edge := range graph.Edges {
+		response.Edges = append(response.Edges, lineageEdgeResponse(edge))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) ListCommitQuality(w http.ResponseWriter, r *http.Request, repository, commitID string, params apigen.ListCommitQualityParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, catalog.QualityPrefix),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_commit_quality", r, repository, commitID, "")
+
+	commit, err := c.Catalog.GetCommit(ctx, repository, commitID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results, hasMore, err := c.Catalog.ListQualityResults(ctx, repository, commit.Reference, paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.QualityResultList{
+		CommitId: commit.Reference,
+		Results:  make([]apigen.QualityResult, 0, len(results)),
+	}
+	for _, result := range results {
+		response.Results = append(response.Results, apigen.QualityResult{
+			Path:                     result.Path,
+			Suite:                    optionalString(result.Suite),
+			RunName:                  optionalString(result.RunName),
+			RunTime:                  optionalString(result.RunTime),
+			Success:                  result.Success,
+			EvaluatedExpectations:    result.EvaluatedExpectations,
+			SuccessfulExpectations:   result.SuccessfulExpectations,
+			UnsuccessfulExpectations: result.UnsuccessfulExpectations,
+			SuccessPercent:           result.SuccessPercent,
+		})
+	}
+	if len(results) > 0 {
+		success := true
+		for _, result := range results {
+			success = success && result.Success
+		}
+		response.Success = &success
+	}
+	response.Pagination = paginationFor(hasMore, response.Results, "Path")
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) InternalGetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	c.GetGCRules(w, r, repository)
+}
+
+func (c *Controller) SetGarbageCollectionRulesPreflight(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetGarbageCollectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "set_gc_collection_rules_preflight", r, repository, "", "")
+
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) InternalSetGarbageCollectionRules(w http.ResponseWriter, r *http.Request, body apigen.InternalSetGarbageCollectionRulesJSONRequestBody, repository string) {
+	c.SetGCRules(w, r, apigen.SetGCRulesJSONRequestBody(body), repository)
+}
+
+func (c *Controller) InternalDeleteGarbageCollectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	c.DeleteGCRules(w, r, repository)
+}
+
+func (c *Controller) PrepareGarbageCollectionCommits(w http.ResponseWriter, r *http.Request, repository string, params apigen.PrepareGarbageCollectionCommitsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.PrepareGarbageCollectionCommitsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "prepare_garbage_collection_commits", r, repository, "", "")
+	if params.RetentionTime != nil {
+		if !c.Config.Graveler.Retention.AllowTimeOverrideTestOnly {
+			writeError(w, r, http.StatusBadRequest, "retention time override is not allowed")
+			return
+		}
+		ctx = retention.ContextWithTime(ctx, *params.RetentionTime)
+	}
+	gcRunMetadata, err := c.Catalog.PrepareExpiredCommits(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	presignedURL, _, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+		Identifier:     gcRunMetadata.CommitsCSVLocation,
+		IdentifierType: block.IdentifierTypeFull,
+	}, block.PreSignModeRead)
+	if err != nil {
+		c.Logger.WithError(err).Warn("Failed to presign url for GC commits")
+	}
+	writeResponse(w, r, http.StatusCreated, apigen.GarbageCollectionPrepareResponse{
+		GcCommitsLocation:     gcRunMetadata.CommitsCSVLocation,
+		GcAddressesLocation:   gcRunMetadata.AddressLocation,
+		RunId:                 gcRunMetadata.RunID,
+		GcCommitsPresignedUrl: &presignedURL,
+	})
+}
+
+func (c *Controller) ReportGarbageCollectionCompletion(w http.ResponseWriter, r *http.Request, body apigen.ReportGarbageCollectionCompletionJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.PrepareGarbageCollectionCommitsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "report_garbage_collection_completion", r, repository, "", "")
+	if _, err := c.Catalog.GetRepository(ctx, repository); c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if c.Notifications != nil {
+		message := fmt.Sprintf("Garbage collection run %s completed", body.RunId)
+		if body.DeletedObjects != nil {
+			message += fmt.Sprintf(", %d objects deleted", *body.DeletedObjects)
+		}
+		c.Notifications.Notify(notifications.Notification{
+			Event:      notifications.EventGCCompleted,
+			Repository: repository,
+			Message:    message,
+		})
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) CollectOrphanMetadata(w http.ResponseWriter, r *http.Request, body apigen.CollectOrphanMetadataJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CollectOrphanMetadataAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "collect_orphan_metadata", r, repository, "", "")
+	params := catalog.MetadataGCParams{
+		DryRun:       swag.BoolValue(body.DryRun),
+		Archive:      swag.BoolValue(body.Archive),
+		MinAge:       time.Duration(swag.Int64Value(body.MinAgeSeconds)) * time.Second,
+		KeepDangling: swag.BoolValue(body.KeepDanglingCommits),
+	}
+	result, err := c.Catalog.CollectOrphanMetadata(ctx, repository, params)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.MetadataGCResult{
+		DryRun:              params.DryRun,
+		ReachableMetaranges: result.ReachableMetaRanges,
+		ReachableRanges:     result.ReachableRanges,
+		SkippedFiles:        result.Skipped,
+		CollectedFiles:      result.CollectedFiles,
+		CollectedBytes:      result.CollectedBytes,
+		Files:               result.Files,
+		ArchivePath:         optionalString(result.ArchivePath),
+	}
+	if response.Files == nil {
+		response.Files = []string{}
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) RunLocalGarbageCollection(w http.ResponseWriter, r *http.Request, body apigen.RunLocalGarbageCollectionJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.RunLocalGarbageCollectionAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "run_local_gc", r, repository, "", "")
+
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	taskID, err := c.Catalog.RunLocalGC(ctx, repository, catalog.LocalGCParams{
+		DryRun:    swag.BoolValue(body.DryRun),
+		MinAge:    time.Duration(swag.Int64Value(body.MinAgeSeconds)) * time.Second,
+		CreatedBy: user.Username,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
+
+func (c *Controller) InternalGetBranchProtectionRules(w http.ResponseWriter, r *http.Request, repository string) {
+	c.GetBranchProtectionRules(w, r, repository)
+}
+
+func (c *Controller) InternalDeleteBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalDeleteBranchProtectionRuleJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "delete_branch_protection_rule", r, repository, "", "")
+
+	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	for p := range rules.BranchPatternToBlockedActions {
+		if p == body.Pattern {
+			delete(rules.BranchPatternToBlockedActions, p)
+			err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+			writeResponse(w, r, http.StatusNoContent, nil)
+			return
+		}
+	}
+	writeResponse(w, r, http.StatusNotFound, nil)
+}
+
+func (c *Controller) CreateBranchProtectionRulePreflight(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "create_branch_protection_rule_preflight", r, repository, "", "")
+
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) InternalCreateBranchProtectionRule(w http.ResponseWriter, r *http.Request, body apigen.InternalCreateBranchProtectionRuleJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.SetBranchProtectionRulesAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_branch_protection_rule", r, repository, "", "")
+	rules, _, err := c.Catalog.GetBranchProtectionRules(ctx, repository)
+	if !errors.Is(err, graveler.ErrNotFound) {
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+	}
+	if rules == nil {
+		rules = &graveler.BranchProtectionRules{}
+	}
+	if rules.BranchPatternToBlockedActions == nil {
+		rules.BranchPatternToBlockedActions = make(map[string]*graveler.BranchProtectionBlockedActions)
+	}
+	blockedActions := &graveler.BranchProtectionBlockedActions{
+		Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_STAGING_WRITE, graveler.BranchProtectionBlockedAction_COMMIT},
+	}
+	rules.BranchPatternToBlockedActions[body.Pattern] = blockedActions
+	err = c.Catalog.SetBranchProtectionRules(ctx, repository, rules, nil)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func (c *Controller) GetMetaRange(w http.ResponseWriter, r *http.Request, repository, metaRange string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "metadata_get_metarange", r, repository, "", "")
+
+	metarange, err := c.Catalog.GetMetaRange(ctx, repository, metaRange)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := apigen.StorageURI{
+		Location: string(metarange),
+	}
+	w.Header().Set("Location", string(metarange))
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) GetRange(w http.ResponseWriter, r *http.Request, repository, pRange string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "metadata_get_range", r, repository, "", "")
+
+	rng, err := c.Catalog.GetRange(ctx, repository, pRange)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.StorageURI{
+		Location: string(rng),
+	}
+	w.Header().Set("Location", string(rng))
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) DumpRefs(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListCommitsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "dump_repository_refs", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// dump all types:
+	tagsID, err := c.Catalog.DumpTags(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	branchesID, err := c.Catalog.DumpBranches(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	commitsID, err := c.Catalog.DumpCommits(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := apigen.RefsDump{
+		BranchesMetaRangeId: branchesID,
+		CommitsMetaRangeId:  commitsID,
+		TagsMetaRangeId:     tagsID,
+	}
+
+	// write this to the block store
+	manifestBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       fmt.Sprintf("%s/refs_manifest.json", c.Config.Committed.BlockStoragePrefix),
+	}, int64(len(manifestBytes)), bytes.NewReader(manifestBytes), block.PutOpts{})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) RestoreRefs(w http.ResponseWriter, r *http.Request, body apigen.RestoreRefsJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateTagAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_repository_refs", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// ensure no refs currently found
+	_, _, err = c.Catalog.ListCommits(ctx, repo.Name, repo.DefaultBranch, catalog.LogParams{
+		PathList:      make([]catalog.PathRecord, 0),
+		FromReference: "",
+		Amount:        1,
+	})
+	if !errors.Is(err, graveler.ErrNotFound) {
+		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
+		return
+	}
+
+	// load commits
+	err = c.Catalog.LoadCommits(ctx, repo.Name, body.CommitsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	err = c.Catalog.LoadBranches(ctx, repo.Name, body.BranchesMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	err = c.Catalog.LoadTags(ctx, repo.Name, body.TagsMetaRangeId, graveler.WithForce(swag.BoolValue(body.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+}
+
+func (c *Controller) DumpSubmit(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListCommitsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "dump_repository", r, repository, "", "")
+
+	taskID, err := c.Catalog.DumpRepositorySubmit(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
+
+func (c *Controller) DumpStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.DumpStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListCommitsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	// get the current status
+	ctx := r.Context()
+	status, err := c.Catalog.DumpRepositoryStatus(ctx, repository, params.TaskId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// build response based on status
+	response := &apigen.RepositoryDumpStatus{
+		Id:         params.TaskId,
+		Done:       status.Task.Done,
+		UpdateTime: status.Task.UpdatedAt.AsTime(),
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	if status.Task.Done && status.Info != nil {
+		response.Refs = &apigen.RefsDump{
+			CommitsMetaRangeId:  status.Info.CommitsMetarangeId,
+			TagsMetaRangeId:     status.Info.TagsMetarangeId,
+			BranchesMetaRangeId: status.Info.BranchesMetarangeId,
+		}
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) RestoreSubmit(w http.ResponseWriter, r *http.Request, body apigen.RestoreSubmitJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateTagAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_repository", r, repository, "", "")
+
+	info := &catalog.RepositoryDumpInfo{
+		CommitsMetarangeId:  body.CommitsMetaRangeId,
+		TagsMetarangeId:     body.TagsMetaRangeId,
+		BranchesMetarangeId: body.BranchesMetaRangeId,
+	}
+	taskID, err := c.Catalog.RestoreRepositorySubmit(ctx, repository, info, graveler.WithForce(swag.BoolValue(body.Force)))
+	if errors.Is(err, catalog.ErrNonEmptyRepository) {
+		writeError(w, r, http.StatusBadRequest, "can only restore into a bare repository")
+		return
+	}
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+		Id: taskID,
+	})
+}
+
+func (c *Controller) RestoreStatus(w http.ResponseWriter, r *http.Request, repository string, params apigen.RestoreStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateTagAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateBranchAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.CreateCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+
+	// get the current status
+	ctx := r.Context()
+	status, err := c.Catalog.RestoreRepositoryStatus(ctx, repository, params.TaskId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// build response based on status
+	response := &apigen.RepositoryRestoreStatus{
+		Id:         params.TaskId,
+		Done:       status.Task.Done,
+		UpdateTime: status.Task.UpdatedAt.AsTime(),
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func operationResponse(op *catalog.Operation) apigen.Operation {
+	resp := apigen.Operation{
+		Id:           op.ID,
+		Kind:         op.Kind,
+		Status:       apigen.OperationStatus(op.Status),
+		Progress:     op.Progress,
+		CreationDate: op.CreatedAt,
+		UpdateTime:   op.UpdatedAt,
+	}
+	if op.Description != "" {
+		resp.Description = swag.String(op.Description)
+	}
+	if op.ProgressTotal > 0 {
+		resp.ProgressTotal = swag.Int64(op.ProgressTotal)
+	}
+	if op.Error != "" {
+		resp.Error = swag.String(op.Error)
+	}
+	if op.CreatedBy != "" {
+		resp.CreatedBy = swag.String(op.CreatedBy)
+	}
+	if len(op.Logs) > 0 {
+		logs := make([]apigen.OperationLogEntry, 0, len(op.Logs))
+		for _, entry := range op.Logs {
+			logs = append(logs, apigen.OperationLogEntry{
+				Time:    entry.Time,
+				Message: entry.Message,
+			})
+		}
+		resp.Logs = &logs
+	}
+	return resp
+}
+
+func (c *Controller) ListOperations(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListOperationsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_operations", r, repository, "", "")
+
+	ops, hasMore, err := c.Catalog.ListOperations(ctx, repository, paginationAmount(params.Amount), paginationAfter(params.After))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.Operation, 0, len(ops))
+	for _, op := range ops {
+		results = append(results, operationResponse(op))
+	}
+	writeResponse(w, r, http.StatusOK, apigen.OperationList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Id"),
+	})
+}
+
+func (c *Controller) GetOperation(w http.ResponseWriter, r *http.Request, repository, operationID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_operation", r, repository, "", "")
+
+	op, err := c.Catalog.GetOperation(ctx, repository, operationID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, operationResponse(op))
+}
+
+func (c *Controller) CancelOperation(w http.ResponseWriter, r *http.Request, repository, operationID string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CancelOperationAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "cancel_operation", r, repository, "", "")
+
+	err := c.Catalog.CancelOperation(ctx, repository, operationID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusAccepted, nil)
+}
+
+func (c *Controller) CreateSymlinkFile(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.CreateSymlinkFileParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_symlink", r, repository, branch, "")
+
+	// read repo
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// list entries
+	var currentPath string
+	var currentAddresses []string
+	var after string
+	var entries []*catalog.DBEntry
+	hasMore := true
+	for hasMore {
+		entries, hasMore, err = c.Catalog.ListEntries(
+			ctx,
+			repository,
+			branch,
+			swag.StringValue(params.Location),
+			after,
+			"",
+			-1)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		// loop all entries enter to map[path] physicalAddress
+		for _, entry := range entries {
+			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+			if err != nil {
+				writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while resolving address: %s", err))
+				return
+			}
+			idx := strings.LastIndex(entry.Path, "/")
+			var path string
+			if idx != -1 {
+				path = entry.Path[0:idx]
+			}
+			if path != currentPath {
+				// push current
+				err := writeSymlink(ctx, repo, branch, path, currentAddresses, c.BlockAdapter)
+				if err != nil {
+					writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("error while writing symlinks: %s", err))
 					return
 				}
 				currentPath = path
@@ -4161,6 +6222,51 @@ func writeSymlink(ctx context.Context, repo *catalog.Repository, branch, path st
 	return err
 }
 
+func (c *Controller) CreateManifest(w http.ResponseWriter, r *http.Request, body apigen.CreateManifestJSONRequestBody, repository, ref string) {
+	prefix := swag.StringValue(body.Prefix)
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				// the manifest exposes the physical addresses of the objects
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, prefix+"*"),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "create_manifest", r, repository, ref, "")
+
+	format := catalog.ManifestFormatSymlink
+	if body.Format != nil {
+		format = catalog.ManifestFormat(*body.Format)
+	}
+	manifest, err := c.Catalog.WriteManifest(ctx, repository, ref, catalog.ManifestParams{
+		Prefix:   prefix,
+		Format:   format,
+		Location: fmt.Sprintf("%s/manifests", c.Config.Committed.BlockStoragePrefix),
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, apigen.Manifest{
+		Location: manifest.Location,
+		CommitId: manifest.CommitID,
+		Format:   string(manifest.Format),
+		Objects:  manifest.Objects,
+	})
+}
+
 func (c *Controller) DiffRefs(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffRefsParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -4176,38 +6282,166 @@ func (c *Controller) DiffRefs(w http.ResponseWriter, r *http.Request, repository
 	if params.Type != nil && *params.Type == "two_dot" {
 		diffFunc = c.Catalog.Diff
 	}
+	if params.LeftPrefix != nil || params.RightPrefix != nil {
+		if params.Type != nil && *params.Type != "two_dot" {
+			writeError(w, r, http.StatusBadRequest, "prefixes are compared only by a two-dot diff")
+			return
+		}
+		leftPrefix := swag.StringValue(params.LeftPrefix)
+		rightPrefix := swag.StringValue(params.RightPrefix)
+		diffFunc = func(ctx context.Context, repositoryID, leftReference, rightReference string, params catalog.DiffParams) (catalog.Differences, bool, error) {
+			return c.Catalog.DiffPrefixes(ctx, repositoryID, leftReference, leftPrefix, rightReference, rightPrefix, params)
+		}
+	}
+
+	listDiff := func(after string, amount int) ([]apigen.Diff, bool, error) {
+		diff, hasMore, err := diffFunc(ctx, repository, leftRef, rightRef, catalog.DiffParams{
+			Limit:            amount,
+			After:            after,
+			Prefix:           paginationPrefix(params.Prefix),
+			Delimiter:        paginationDelimiter(params.Delimiter),
+			AdditionalFields: nil,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		results := make([]apigen.Diff, 0, len(diff))
+		for _, d := range diff {
+			pathType := entryTypeObject
+			if d.CommonLevel {
+				pathType = entryTypeCommonPrefix
+			}
+			diff := apigen.Diff{
+				Path:     d.Path,
+				Type:     transformDifferenceTypeToString(d.Type),
+				PathType: pathType,
+			}
+			if !d.CommonLevel {
+				diff.SizeBytes = swag.Int64(d.Size)
+			}
+			results = append(results, diff)
+		}
+		return results, hasMore, nil
+	}
 
-	diff, hasMore, err := diffFunc(ctx, repository, leftRef, rightRef, catalog.DiffParams{
-		Limit:            paginationAmount(params.Amount),
-		After:            paginationAfter(params.After),
-		Prefix:           paginationPrefix(params.Prefix),
-		Delimiter:        paginationDelimiter(params.Delimiter),
-		AdditionalFields: nil,
+	if contentType := streamingContentType(r); contentType != "" {
+		after := paginationAfter(params.After)
+		streamListing(c, w, r, contentType, diffArrowColumns, streamLimit(params.StreamLimit), func(amount int) ([]apigen.Diff, bool, error) {
+			results, hasMore, err := listDiff(after, amount)
+			if len(results) > 0 {
+				after = results[len(results)-1].Path
+			}
+			return results, hasMore, err
+		})
+		return
+	}
+
+	results, hasMore, err := listDiff(paginationAfter(params.After), paginationAmount(params.Amount))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.DiffList{
+		Pagination: paginationFor(hasMore, results, "Path"),
+		Results:    results,
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func diffSummaryStatsResponse(s catalog.DiffSummaryStats) apigen.DiffSummaryStats {
+	return apigen.DiffSummaryStats{
+		AddedCount:   s.AddedCount,
+		AddedBytes:   s.AddedBytes,
+		RemovedCount: s.RemovedCount,
+		RemovedBytes: s.RemovedBytes,
+		ChangedCount: s.ChangedCount,
+		ChangedBytes: s.ChangedBytes,
+	}
+}
+
+func (c *Controller) DiffRefsSummary(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffRefsSummaryParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "diff_refs_summary", r, repository, rightRef, leftRef)
+	summary, err := c.Catalog.DiffSummary(ctx, repository, leftRef, rightRef, catalog.DiffSummaryParams{
+		Prefix: paginationPrefix(params.Prefix),
+		TwoDot: params.Type != nil && *params.Type == "two_dot",
 	})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	results := make([]apigen.Diff, 0, len(diff))
-	for _, d := range diff {
-		pathType := entryTypeObject
-		if d.CommonLevel {
-			pathType = entryTypeCommonPrefix
-		}
-		diff := apigen.Diff{
-			Path:     d.Path,
-			Type:     transformDifferenceTypeToString(d.Type),
-			PathType: pathType,
-		}
-		if !d.CommonLevel {
-			diff.SizeBytes = swag.Int64(d.Size)
+	groups := make([]apigen.DiffSummaryGroup, 0, len(summary.Groups))
+	for _, g := range summary.Groups {
+		groups = append(groups, apigen.DiffSummaryGroup{
+			Prefix:    g.Prefix,
+			Extension: g.Extension,
+			Stats:     diffSummaryStatsResponse(g.Stats),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, apigen.DiffSummary{
+		Groups:    groups,
+		Total:     diffSummaryStatsResponse(summary.Total),
+		Truncated: summary.Truncated,
+	})
+}
+
+func (c *Controller) DiffRefsSchema(w http.ResponseWriter, r *http.Request, repository, leftRef, rightRef string, params apigen.DiffRefsSchemaParams) {
+	prefix := paginationPrefix(params.Prefix)
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, prefix+"*"),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "diff_refs_schema", r, repository, rightRef, leftRef)
+	drifts, hasMore, err := c.Catalog.ListSchemaDrift(ctx, repository, leftRef, rightRef, prefix,
+		paginationAfter(params.After), paginationAmount(params.Amount))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.SchemaDrift, 0, len(drifts))
+	for _, d := range drifts {
+		changes := make([]apigen.ColumnChange, 0, len(d.Changes))
+		for _, change := range d.Changes {
+			changes = append(changes, apigen.ColumnChange{
+				Name:      change.Name,
+				Type:      apigen.ColumnChangeType(change.Type),
+				LeftType:  optionalString(change.LeftType),
+				RightType: optionalString(change.RightType),
+			})
 		}
-		results = append(results, diff)
+		results = append(results, apigen.SchemaDrift{
+			Path:    d.Path,
+			Type:    apigen.SchemaDriftType(transformDifferenceTypeToString(d.Type)),
+			Format:  apigen.SchemaDriftFormat(d.Format),
+			Changes: changes,
+			Error:   optionalString(d.Error),
+		})
 	}
-	response := apigen.DiffList{
+	writeResponse(w, r, http.StatusOK, apigen.SchemaDriftList{
 		Pagination: paginationFor(hasMore, results, "Path"),
 		Results:    results,
-	}
-	writeResponse(w, r, http.StatusOK, response)
+	})
 }
 
 func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.LogCommitsParams) {
@@ -4222,6 +6456,12 @@ func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, reposito
 	ctx := r.Context()
 	c.LogAction(ctx, "get_branch_commit_log", r, repository, ref, "")
 
+	selection, err := newFieldSelection(reflect.TypeOf(apigen.Commit{}), "id", params.Fields, params.Compact)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
 	// get commit log
 	commitLog, hasMore, err := c.Catalog.ListCommits(ctx, repository, ref, catalog.LogParams{
 		PathList:      resolvePathList(params.Objects, params.Prefixes),
@@ -4254,154 +6494,626 @@ func (c *Controller) LogCommits(w http.ResponseWriter, r *http.Request, reposito
 		})
 	}
 
-	response := apigen.CommitList{
-		Pagination: paginationFor(hasMore, serializedCommits, "Id"),
-		Results:    serializedCommits,
+	response := apigen.CommitList{
+		Pagination: paginationFor(hasMore, serializedCommits, "Id"),
+		Results:    serializedCommits,
+	}
+	selected, err := selection.apply(response)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, selected)
+}
+
+func (c *Controller) HeadObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.HeadObjectParams) {
+	if !c.authorizeCallback(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
+		writeResponse(w, r, code, nil)
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "head_object", r, repository, ref, "")
+
+	// read the FS entry
+	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
+	if err != nil {
+		c.handleAPIErrorCallback(ctx, w, r, err, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
+			writeResponse(w, r, code, nil)
+		})
+		return
+	}
+	if !c.authorizeAliasTarget(w, r, repository, entry, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
+		writeResponse(w, r, code, nil)
+	}) {
+		return
+	}
+	if entry.Expired {
+		writeResponse(w, r, http.StatusGone, nil)
+		return
+	}
+
+	etag := httputil.ETag(entry.Checksum)
+	w.Header().Set("ETag", etag)
+	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
+	w.Header().Set("Last-Modified", lastModified)
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Content-Type", entry.ContentType)
+	// for security, make sure the browser and any proxies en route don't cache the response
+	w.Header().Set("Cache-Control", "no-store, must-revalidate")
+	w.Header().Set("Expires", "0")
+
+	// calculate possible byte range, if any.
+	if params.Range != nil {
+		rng, err := httputil.ParseRange(*params.Range, entry.Size)
+		if err != nil {
+			writeResponse(w, r, http.StatusRequestedRangeNotSatisfiable, nil)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
+		writeResponse(w, r, http.StatusPartialContent, nil)
+	} else {
+		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+	}
+}
+
+func (c *Controller) GetMetadataObject(w http.ResponseWriter, r *http.Request, repository string, objectType string, objectID string, params apigen.GetMetadataObjectParams) {
+	const getTypeMetaRange = "meta_range"
+	const getTypeRange = "range"
+
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadRepositoryAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_metadata_object", r, repository, "", "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	var objPath string
+	if objectType == getTypeMetaRange {
+		addr, err := c.Catalog.GetMetaRange(ctx, repository, objectID)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		objPath = string(addr)
+	} else if objectType == getTypeRange {
+		addr, err := c.Catalog.GetRange(ctx, repository, objectID)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		objPath = string(addr)
+	}
+
+	// if pre-sign, return a redirect
+	pointer := block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       objPath,
+	}
+	if swag.BoolValue(params.Presign) {
+		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+
+	// set response headers
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+
+	reader, err := c.BlockAdapter.Get(ctx, pointer)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	// copy the content
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		c.Logger.
+			WithError(err).
+			WithFields(logging.Fields{
+				"storage_namespace": repo.StorageNamespace,
+			}).
+			Debug("GetObjectMetadata copy content")
+	}
+}
+
+func (c *Controller) GetObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetObjectParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_object", r, repository, ref, "")
+
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	// read the FS entry
+	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !c.authorizeAliasTarget(w, r, repository, entry, writeError) {
+		return
+	}
+	c.Logger.Tracef("get repo %s ref %s path %s: %+v", repository, ref, params.Path, entry)
+	if entry.Expired {
+		writeError(w, r, http.StatusGone, "resource expired")
+		return
+	}
+	c.recordObjectAccess(ctx, repository, params.Path)
+
+	eTag := httputil.ETag(entry.Checksum)
+
+	// check ETag if not modified in request
+	// Not using swag.StringValue to catch edge case where eTag == ""
+	if params.IfNoneMatch != nil && *params.IfNoneMatch == eTag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// if pre-sign, return a redirect
+	pointer := block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}
+	if swag.BoolValue(params.Presign) {
+		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+	c.writeObjectContent(w, r, repo, entry, pointer, params.Range)
+}
+
+// writeObjectContent writes the content of entry, or the byte range of it when rangeHeader is set
+func (c *Controller) writeObjectContent(w http.ResponseWriter, r *http.Request, repo *catalog.Repository, entry *catalog.DBEntry, pointer block.ObjectPointer, rangeHeader *string) {
+	ctx := r.Context()
+
+	// set response headers
+	w.Header().Set("ETag", httputil.ETag(entry.Checksum))
+	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
+	w.Header().Set("Last-Modified", lastModified)
+	w.Header().Set("Content-Type", entry.ContentType)
+	// for security, make sure the browser and any proxies en route don't cache the response
+	w.Header().Set("Cache-Control", "no-store, must-revalidate")
+	w.Header().Set("Expires", "0")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+
+	// handle partial response if byte range supplied
+	var reader io.ReadCloser
+	var err error
+	if rangeHeader != nil {
+		rng, err := httputil.ParseRange(*rangeHeader, entry.Size)
+		if err != nil {
+			writeError(w, r, http.StatusRequestedRangeNotSatisfiable, "Requested Range Not Satisfiable")
+			return
+		}
+		reader, err = c.BlockAdapter.GetRange(ctx, pointer, rng.StartOffset, rng.EndOffset)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		reader, err = c.BlockAdapter.Get(ctx, pointer)
+		if c.handleAPIError(ctx, w, r, err) {
+			return
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+	}
+
+	// copy the content
+	_, err = io.Copy(w, reader)
+	if err != nil {
+		c.Logger.
+			WithError(err).
+			WithFields(logging.Fields{
+				"storage_namespace": repo.StorageNamespace,
+				"physical_address":  entry.PhysicalAddress,
+			}).
+			Debug("GetObject copy content")
 	}
-	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) HeadObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.HeadObjectParams) {
-	if !c.authorizeCallback(w, r, permissions.Node{
+func (c *Controller) CreateObjectLink(w http.ResponseWriter, r *http.Request, body apigen.CreateObjectLinkJSONRequestBody, repository, ref string) {
+	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.ReadObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+			Resource: permissions.ObjectArn(repository, body.Path),
 		},
-	}, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
-		writeResponse(w, r, code, nil)
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "head_object", r, repository, ref, "")
+	c.LogAction(ctx, "create_object_link", r, repository, ref, "")
 
-	// read the FS entry
-	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
+	user, err := auth.GetUser(ctx)
 	if err != nil {
-		c.handleAPIErrorCallback(ctx, w, r, err, func(w http.ResponseWriter, r *http.Request, code int, v interface{}) {
-			writeResponse(w, r, code, nil)
-		})
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
 		return
 	}
-	if entry.Expired {
-		writeResponse(w, r, http.StatusGone, nil)
+	expiry := DefaultObjectLinkExpiry
+	if body.ExpiresIn != nil {
+		expiry = time.Duration(*body.ExpiresIn) * time.Second
+	}
+	if expiry <= 0 || expiry > MaxObjectLinkExpiry {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("expires_in must be between 1 and %d seconds", int64(MaxObjectLinkExpiry.Seconds())))
 		return
 	}
 
-	etag := httputil.ETag(entry.Checksum)
-	w.Header().Set("ETag", etag)
-	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
-	w.Header().Set("Last-Modified", lastModified)
-	w.Header().Set("Accept-Ranges", "bytes")
-	w.Header().Set("Content-Type", entry.ContentType)
-	// for security, make sure the browser and any proxies en route don't cache the response
-	w.Header().Set("Cache-Control", "no-store, must-revalidate")
-	w.Header().Set("Expires", "0")
+	// pin the link to the commit of ref, reading the object there
+	commit, err := c.Catalog.GetCommit(ctx, repository, ref)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	entry, err := c.Catalog.GetEntry(ctx, repository, commit.Reference, body.Path, catalog.GetEntryParams{})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !c.authorizeAliasTarget(w, r, repository, entry, writeError) {
+		return
+	}
 
-	// calculate possible byte range, if any.
-	if params.Range != nil {
-		rng, err := httputil.ParseRange(*params.Range, entry.Size)
-		if err != nil {
-			writeResponse(w, r, http.StatusRequestedRangeNotSatisfiable, nil)
-			return
-		}
-		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
-		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
-		writeResponse(w, r, http.StatusPartialContent, nil)
-	} else {
-		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+	now := time.Now()
+	expiresAt := now.Add(expiry)
+	token, err := GenerateObjectLinkToken(c.Auth.SecretStore().SharedSecret(), user.Username, repository, commit.Reference, body.Path, now, expiresAt)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	linkURL := url.URL{
+		Scheme: httputil.RequestScheme(r),
+		Host:   r.Host,
+		Path:   apiutil.BaseURL + "/links/" + token,
 	}
+	writeResponse(w, r, http.StatusCreated, apigen.ObjectLink{
+		Url:       linkURL.String(),
+		CommitId:  commit.Reference,
+		Path:      body.Path,
+		ExpiresAt: expiresAt.Unix(),
+	})
 }
 
-func (c *Controller) GetMetadataObject(w http.ResponseWriter, r *http.Request, repository string, objectType string, objectID string, params apigen.GetMetadataObjectParams) {
-	const getTypeMetaRange = "meta_range"
-	const getTypeRange = "range"
+func (c *Controller) GetObjectByLink(w http.ResponseWriter, r *http.Request, token string, params apigen.GetObjectByLinkParams) {
+	ctx := r.Context()
+	claims, err := VerifyObjectLinkToken(c.Auth.SecretStore().SharedSecret(), token)
+	if errors.Is(err, ErrObjectLinkExpired) {
+		writeError(w, r, http.StatusGone, err)
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
+		return
+	}
 
-	if !c.authorize(w, r, permissions.Node{
-		Type: permissions.NodeTypeAnd,
-		Nodes: []permissions.Node{
-			{
-				Permission: permissions.Permission{
-					Action:   permissions.ListObjectsAction,
-					Resource: permissions.RepoArn(repository),
-				},
+	// the link reads on behalf of its creator, for as long as they may read the object
+	resp, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: claims.Subject,
+		RequiredPermissions: permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(claims.Repository, claims.Path),
 			},
-			{
+		},
+	})
+	if err != nil || resp.Error != nil || !resp.Allowed {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
+		return
+	}
+	ctx = logging.AddFields(ctx, logging.Fields{logging.UserFieldKey: claims.Subject, "link_id": claims.Id})
+	r = r.WithContext(ctx)
+	c.LogAction(ctx, "get_object_by_link", r, claims.Repository, claims.CommitID, "")
+
+	repo, err := c.Catalog.GetRepository(ctx, claims.Repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	entry, err := c.Catalog.GetEntry(ctx, claims.Repository, claims.CommitID, claims.Path, catalog.GetEntryParams{})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if entry.AliasTarget != "" {
+		// the creator must also be allowed to read the object the alias points to
+		resp, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: claims.Subject,
+			RequiredPermissions: permissions.Node{
 				Permission: permissions.Permission{
-					Action:   permissions.ReadRepositoryAction,
-					Resource: permissions.RepoArn(repository),
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(claims.Repository, entry.AliasTarget),
 				},
 			},
+		})
+		if err != nil || resp.Error != nil || !resp.Allowed {
+			writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
+			return
+		}
+	}
+	if entry.Expired {
+		writeError(w, r, http.StatusGone, "resource expired")
+		return
+	}
+	pointer := block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}
+	c.writeObjectContent(w, r, repo, entry, pointer, params.Range)
+}
+
+func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListObjectsParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_metadata_object", r, repository, "", "")
+	user, _ := auth.GetUser(ctx)
+	c.LogAction(ctx, "list_objects", r, repository, ref, "")
+
+	selection, err := newFieldSelection(reflect.TypeOf(apigen.ObjectStats{}), "path", params.Fields, params.Compact)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	var objPath string
-	if objectType == getTypeMetaRange {
-		addr, err := c.Catalog.GetMetaRange(ctx, repository, objectID)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
+	listObjects := func(after string, amount int) ([]apigen.ObjectStats, bool, error) {
+		res, hasMore, err := c.Catalog.ListEntries(
+			ctx,
+			repository,
+			ref,
+			paginationPrefix(params.Prefix),
+			after,
+			paginationDelimiter(params.Delimiter),
+			amount,
+		)
+		if err != nil {
+			return nil, false, err
 		}
-		objPath = string(addr)
-	} else if objectType == getTypeRange {
-		addr, err := c.Catalog.GetRange(ctx, repository, objectID)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
+		objList := make([]apigen.ObjectStats, 0, len(res))
+		for _, entry := range res {
+			objStat, err := c.entryObjectStats(ctx, repo, user, entry, params, selection)
+			if err != nil {
+				return nil, false, err
+			}
+			objList = append(objList, objStat)
 		}
-		objPath = string(addr)
+		return objList, hasMore, nil
 	}
 
-	// if pre-sign, return a redirect
-	pointer := block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   block.IdentifierTypeRelative,
-		Identifier:       objPath,
+	if contentType := streamingContentType(r); contentType != "" {
+		after := paginationAfter(params.After)
+		streamListing(c, w, r, contentType, objectStatsArrowColumns, streamLimit(params.StreamLimit), func(amount int) ([]apigen.ObjectStats, bool, error) {
+			objList, hasMore, err := listObjects(after, amount)
+			if len(objList) > 0 {
+				after = objList[len(objList)-1].Path
+			}
+			return objList, hasMore, err
+		})
+		return
 	}
-	if swag.BoolValue(params.Presign) {
-		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		w.Header().Set("Location", location)
-		w.WriteHeader(http.StatusFound)
+
+	objList, hasMore, err := listObjects(paginationAfter(params.After), paginationAmount(params.Amount))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.ObjectStatsList{
+		Pagination: apigen.Pagination{
+			HasMore:    hasMore,
+			MaxPerPage: DefaultMaxPerPage,
+			Results:    len(objList),
+		},
+		Results: objList,
+	}
+	if len(objList) > 0 && hasMore {
+		lastObj := objList[len(objList)-1]
+		response.Pagination.NextOffset = lastObj.Path
+	}
+	selected, err := selection.apply(response)
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	writeResponse(w, r, http.StatusOK, selected)
+}
 
-	// set response headers
-	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
-	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+func (c *Controller) ListObjectTree(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListObjectTreeParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_object_tree", r, repository, ref, "")
 
-	reader, err := c.BlockAdapter.Get(ctx, pointer)
+	delimiter := catalog.DefaultPathDelimiter
+	if params.Delimiter != nil {
+		delimiter = paginationDelimiter(params.Delimiter)
+	}
+	tree, err := c.Catalog.ListPrefixTree(ctx, repository, ref, paginationPrefix(params.Prefix), paginationAfter(params.After), delimiter, paginationAmount(params.Amount))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	defer func() {
-		_ = reader.Close()
-	}()
+	results := make([]apigen.ObjectTreeEntry, 0, len(tree.Children))
+	for _, child := range tree.Children {
+		entry := apigen.ObjectTreeEntry{
+			Path:      child.Path,
+			PathType:  entryTypeCommonPrefix,
+			Count:     child.Stats.Count,
+			SizeBytes: child.Stats.Size,
+		}
+		if !child.Stats.LastModified.IsZero() {
+			entry.Mtime = child.Stats.LastModified.Unix()
+		}
+		if child.Entry != nil {
+			entry.PathType = entryTypeObject
+			entry.Checksum = swag.String(child.Entry.Checksum)
+			entry.ContentType = swag.String(child.Entry.ContentType)
+		}
+		results = append(results, entry)
+	}
+	response := apigen.ObjectTreeList{
+		Pagination: apigen.Pagination{
+			HasMore:    tree.HasMore,
+			MaxPerPage: DefaultMaxPerPage,
+			Results:    len(results),
+		},
+		Results: results,
+	}
+	if len(results) > 0 && tree.HasMore {
+		response.Pagination.NextOffset = results[len(results)-1].Path
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	// copy the content
-	_, err = io.Copy(w, reader)
+// objectEncryption returns the encryption of the object data recorded on its metadata, nil if none was recorded
+func objectEncryption(metadata catalog.Metadata) *apigen.ObjectEncryption {
+	enc := catalog.EncryptionContextFromMetadata(metadata)
+	if enc.Algorithm == "" && enc.KMSKeyID == "" {
+		return nil
+	}
+	res := &apigen.ObjectEncryption{}
+	if enc.Algorithm != "" {
+		res.Algorithm = swag.String(enc.Algorithm)
+	}
+	if enc.KMSKeyID != "" {
+		res.KmsKeyId = swag.String(enc.KMSKeyID)
+	}
+	return res
+}
+
+// entryObjectStats returns the listed object stats of entry
+func (c *Controller) entryObjectStats(ctx context.Context, repo *catalog.Repository, user *model.User, entry *catalog.DBEntry, params apigen.ListObjectsParams, selection *fieldSelection) (apigen.ObjectStats, error) {
+	if entry.CommonLevel {
+		return apigen.ObjectStats{
+			Path:     entry.Path,
+			PathType: entryTypeCommonPrefix,
+		}, nil
+	}
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
 	if err != nil {
-		c.Logger.
-			WithError(err).
-			WithFields(logging.Fields{
-				"storage_namespace": repo.StorageNamespace,
-			}).
-			Debug("GetObjectMetadata copy content")
+		return apigen.ObjectStats{}, err
+	}
+	var mtime int64
+	if !entry.CreationDate.IsZero() {
+		mtime = entry.CreationDate.Unix()
+	}
+	objStat := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           mtime,
+		Path:            entry.Path,
+		PhysicalAddress: qk.Format(),
+		PathType:        entryTypeObject,
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
+	}
+	if selection.compact {
+		// streaming responses are not filtered by the field selection
+		objStat.Checksum = ""
+	} else if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
+		objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
+	}
+	objStat.Encryption = objectEncryption(entry.Metadata)
+	if entry.IsAlias() {
+		objStat.AliasTarget = swag.String(entry.Metadata[catalog.AliasTargetMetadataKey])
+	}
+	// the physical address of an alias is the one of its target when the alias was created, reading it
+	// through a pre-signed URL would not follow the alias
+	if !swag.BoolValue(params.Presign) || entry.IsAlias() {
+		return objStat, nil
+	}
+	// check if the user has read permissions for this object
+	authResponse, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: user.Username,
+		RequiredPermissions: permissions.Node{
+			Permission: permissions.Permission{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(repo.Name, entry.Path),
+			},
+		},
+	})
+	if err != nil {
+		return apigen.ObjectStats{}, err
+	}
+	if authResponse.Allowed {
+		var expiry time.Time
+		objStat.PhysicalAddress, expiry, err = c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+			StorageNamespace: repo.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}, block.PreSignModeRead)
+		if err != nil {
+			return apigen.ObjectStats{}, err
+		}
+		if !expiry.IsZero() {
+			objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
+		}
 	}
+	return objStat, nil
 }
 
-func (c *Controller) GetObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetObjectParams) {
+func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.StatObjectParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.ReadObjectAction,
@@ -4411,104 +7123,198 @@ func (c *Controller) GetObject(w http.ResponseWriter, r *http.Request, repositor
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "get_object", r, repository, ref, "")
+	c.LogAction(ctx, "stat_object", r, repository, ref, "")
 
 	repo, err := c.Catalog.GetRepository(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// read the FS entry
 	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
-	c.Logger.Tracef("get repo %s ref %s path %s: %+v", repository, ref, params.Path, entry)
-	if entry.Expired {
-		writeError(w, r, http.StatusGone, "resource expired")
+	if !c.authorizeAliasTarget(w, r, repository, entry, writeError) {
 		return
 	}
 
-	eTag := httputil.ETag(entry.Checksum)
-
-	// check ETag if not modified in request
-	// Not using swag.StringValue to catch edge case where eTag == ""
-	if params.IfNoneMatch != nil && *params.IfNoneMatch == eTag {
-		w.WriteHeader(http.StatusNotModified)
+	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	// if pre-sign, return a redirect
-	pointer := block.ObjectPointer{
-		StorageNamespace: repo.StorageNamespace,
-		IdentifierType:   entry.AddressType.ToIdentifierType(),
-		Identifier:       entry.PhysicalAddress,
+	objStat := apigen.ObjectStats{
+		Checksum:        entry.Checksum,
+		Mtime:           entry.CreationDate.Unix(),
+		Path:            entry.Path,
+		PathType:        entryTypeObject,
+		PhysicalAddress: qk.Format(),
+		SizeBytes:       swag.Int64(entry.Size),
+		ContentType:     swag.String(entry.ContentType),
 	}
-	if swag.BoolValue(params.Presign) {
-		location, _, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
+
+	// add metadata if requested
+	var metadata map[string]string
+	if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
+		metadata = entry.Metadata
+	} else {
+		metadata = map[string]string{}
+	}
+	objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: metadata}
+	objStat.Encryption = objectEncryption(entry.Metadata)
+	if entry.AliasTarget != "" {
+		objStat.AliasTarget = swag.String(entry.AliasTarget)
+	}
+
+	code := http.StatusOK
+	if entry.Expired {
+		code = http.StatusGone
+	} else if swag.BoolValue(params.Presign) {
+		// need to pre-sign the physical address
+		preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
+			StorageNamespace: repo.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}, block.PreSignModeRead)
 		if c.handleAPIError(ctx, w, r, err) {
 			return
 		}
-		w.Header().Set("Location", location)
-		w.WriteHeader(http.StatusFound)
+		objStat.PhysicalAddress = preSignedURL
+		if !expiry.IsZero() {
+			objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
+		}
+		// a pre-signed address is handed out for reading the object
+		c.recordObjectAccess(ctx, repository, params.Path)
+	}
+	writeResponse(w, r, code, objStat)
+}
+
+func (c *Controller) ResolveAddresses(w http.ResponseWriter, r *http.Request, body apigen.ResolveAddressesJSONRequestBody, repository, ref string) {
+	ctx := r.Context()
+	// each path is authorized separately below
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
 		return
 	}
+	c.LogAction(ctx, "resolve_addresses", r, repository, ref, "")
 
-	// set response headers
-	w.Header().Set("ETag", eTag)
-	lastModified := httputil.HeaderTimestamp(entry.CreationDate)
-	w.Header().Set("Last-Modified", lastModified)
-	w.Header().Set("Content-Type", entry.ContentType)
-	// for security, make sure the browser and any proxies en route don't cache the response
-	w.Header().Set("Cache-Control", "no-store, must-revalidate")
-	w.Header().Set("Expires", "0")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.Header().Set("X-Frame-Options", "SAMEORIGIN")
-	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	// fail on a missing ref, rather than reporting each path as not found
+	if _, err := c.Catalog.GetCommit(ctx, repository, ref); c.handleAPIError(ctx, w, r, err) {
+		return
+	}
 
-	// handle partial response if byte range supplied
-	var reader io.ReadCloser
-	if params.Range != nil {
-		rng, err := httputil.ParseRange(*params.Range, entry.Size)
+	mayRead := func(p string) (bool, error) {
+		authResponse, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+			Username: user.Username,
+			RequiredPermissions: permissions.Node{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, p),
+				},
+			},
+		})
 		if err != nil {
-			writeError(w, r, http.StatusRequestedRangeNotSatisfiable, "Requested Range Not Satisfiable")
-			return
+			return false, err
 		}
-		reader, err = c.BlockAdapter.GetRange(ctx, pointer, rng.StartOffset, rng.EndOffset)
+		return authResponse.Allowed, nil
+	}
+
+	presign := swag.BoolValue(body.Presign)
+	results := make([]apigen.ResolvedAddress, 0, len(body.Paths))
+	for _, p := range body.Paths {
+		result := apigen.ResolvedAddress{Path: p}
+		allowed, err := mayRead(p)
 		if c.handleAPIError(ctx, w, r, err) {
 			return
 		}
-		defer func() {
-			_ = reader.Close()
-		}()
-		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
-		w.Header().Set("Content-Length", fmt.Sprintf("%d", rng.EndOffset-rng.StartOffset+1))
-		w.WriteHeader(http.StatusPartialContent)
-	} else {
-		reader, err = c.BlockAdapter.Get(ctx, pointer)
+		if !allowed {
+			result.StatusCode = http.StatusForbidden
+			results = append(results, result)
+			continue
+		}
+
+		entry, err := c.Catalog.GetEntry(ctx, repository, ref, p, catalog.GetEntryParams{})
+		if errors.Is(err, graveler.ErrNotFound) {
+			result.StatusCode = http.StatusNotFound
+			results = append(results, result)
+			continue
+		}
 		if c.handleAPIError(ctx, w, r, err) {
 			return
 		}
-		defer func() {
-			_ = reader.Close()
-		}()
-		w.Header().Set("Content-Length", fmt.Sprint(entry.Size))
+		if entry.AliasTarget != "" {
+			// an alias resolves to the object it points to, which must be readable as well
+			allowed, err = mayRead(entry.AliasTarget)
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+			if !allowed {
+				result.StatusCode = http.StatusForbidden
+				results = append(results, result)
+				continue
+			}
+		}
+		if entry.Expired {
+			result.StatusCode = http.StatusGone
+			results = append(results, result)
+			continue
+		}
+
+		pointer := block.ObjectPointer{
+			StorageNamespace: repo.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		}
+		if presign {
+			preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, pointer, block.PreSignModeRead)
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+			result.PhysicalAddress = swag.String(preSignedURL)
+			if !expiry.IsZero() {
+				result.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
+			}
+		} else {
+			qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+			result.PhysicalAddress = swag.String(qk.Format())
+		}
+		result.StatusCode = http.StatusOK
+		result.SizeBytes = swag.Int64(entry.Size)
+		result.Checksum = swag.String(entry.Checksum)
+		results = append(results, result)
+		c.Catalog.RecordObjectAccess(repository, p, user.Username)
 	}
+	writeResponse(w, r, http.StatusOK, apigen.ResolvedAddressList{Results: results})
+}
 
-	// copy the content
-	_, err = io.Copy(w, reader)
-	if err != nil {
-		c.Logger.
-			WithError(err).
-			WithFields(logging.Fields{
-				"storage_namespace": repo.StorageNamespace,
-				"physical_address":  entry.PhysicalAddress,
-			}).
-			Debug("GetObject copy content")
+func (c *Controller) FindObjectRefs(w http.ResponseWriter, r *http.Request, body apigen.FindObjectRefsJSONRequestBody, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, body.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "find_object_refs", r, repository, "", "")
+
+	refs, err := c.Catalog.FindObjectInRefs(ctx, repository, body.Path, body.Refs)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
+	writeResponse(w, r, http.StatusOK, apigen.ObjectRefs{Refs: refs})
 }
 
-func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.ListObjectsParams) {
+func (c *Controller) ListObjectAccess(w http.ResponseWriter, r *http.Request, repository string, params apigen.ListObjectAccessParams) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
 			Action:   permissions.ListObjectsAction,
@@ -4518,169 +7324,204 @@ func (c *Controller) ListObjects(w http.ResponseWriter, r *http.Request, reposit
 		return
 	}
 	ctx := r.Context()
-	user, _ := auth.GetUser(ctx)
-	c.LogAction(ctx, "list_objects", r, repository, ref, "")
+	c.LogAction(ctx, "list_object_access", r, repository, "", "")
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
+	accesses, hasMore, err := c.Catalog.ListObjectAccess(ctx, repository, paginationPrefix(params.Prefix), paginationAfter(params.After), paginationAmount(params.Amount))
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	results := make([]apigen.ObjectAccess, 0, len(accesses))
+	for _, access := range accesses {
+		principals := make([]apigen.PrincipalAccess, 0, len(access.Principals))
+		for _, p := range access.Principals {
+			principals = append(principals, apigen.PrincipalAccess{
+				User:         p.User,
+				Count:        p.Count,
+				LastAccessed: p.LastAccessed.Unix(),
+			})
+		}
+		results = append(results, apigen.ObjectAccess{
+			Path:         access.Path,
+			Count:        access.Count,
+			LastAccessed: access.LastAccessed.Unix(),
+			Principals:   principals,
+		})
+	}
+	response := apigen.ObjectAccessList{
+		Results:    results,
+		Pagination: paginationFor(hasMore, results, "Path"),
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
 
-	res, hasMore, err := c.Catalog.ListEntries(
-		ctx,
-		repository,
-		ref,
-		paginationPrefix(params.Prefix),
-		paginationAfter(params.After),
-		paginationDelimiter(params.Delimiter),
-		paginationAmount(params.Amount),
-	)
+func (c *Controller) GetColdDataReport(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "get_cold_data_report", r, repository, "", "")
+
+	report, err := c.Catalog.GetColdDataReport(ctx, repository)
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	prefixes := make([]apigen.ColdPrefix, 0, len(report.Prefixes))
+	for _, p := range report.Prefixes {
+		prefixes = append(prefixes, apigen.ColdPrefix{
+			Prefix:       p.Prefix,
+			Objects:      p.Objects,
+			SizeBytes:    p.SizeBytes,
+			LastActivity: p.LastActivity.Unix(),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, apigen.ColdDataReport{
+		Repository:         report.Repository,
+		CommitId:           report.CommitID,
+		CreatedAt:          report.CreatedAt.Unix(),
+		InactiveForSeconds: int64(report.InactiveFor.Seconds()),
+		PhysicalAddress:    report.PhysicalAddress,
+		TotalObjects:       report.TotalObjects,
+		TotalBytes:         report.TotalBytes,
+		ColdObjects:        report.ColdObjects,
+		ColdBytes:          report.ColdBytes,
+		Prefixes:           prefixes,
+	})
+}
+
+func (c *Controller) PreviewObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.PreviewObjectParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, params.Path),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "preview_object", r, repository, ref, "")
 
-	objList := make([]apigen.ObjectStats, 0, len(res))
-	for _, entry := range res {
-		qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
+	repo, err := c.Catalog.GetRepository(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	if !c.authorizeAliasTarget(w, r, repository, entry, writeError) {
+		return
+	}
+	if entry.Expired {
+		writeError(w, r, http.StatusGone, "resource expired")
+		return
+	}
+	var format preview.Format
+	if params.Format != nil {
+		format = preview.Format(*params.Format)
+	} else {
+		format, err = preview.DetectFormat(params.Path, entry.ContentType)
 		if err != nil {
-			writeError(w, r, http.StatusInternalServerError, err)
+			writeError(w, r, http.StatusBadRequest, err)
 			return
 		}
+	}
+	c.recordObjectAccess(ctx, repository, params.Path)
 
-		if entry.CommonLevel {
-			objList = append(objList, apigen.ObjectStats{
-				Path:     entry.Path,
-				PathType: entryTypeCommonPrefix,
-			})
-		} else {
-			var mtime int64
-			if !entry.CreationDate.IsZero() {
-				mtime = entry.CreationDate.Unix()
-			}
-			objStat := apigen.ObjectStats{
-				Checksum:        entry.Checksum,
-				Mtime:           mtime,
-				Path:            entry.Path,
-				PhysicalAddress: qk.Format(),
-				PathType:        entryTypeObject,
-				SizeBytes:       swag.Int64(entry.Size),
-				ContentType:     swag.String(entry.ContentType),
-			}
-			if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
-				objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: entry.Metadata}
-			}
-			if swag.BoolValue(params.Presign) {
-				// check if the user has read permissions for this object
-				authResponse, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
-					Username: user.Username,
-					RequiredPermissions: permissions.Node{
-						Permission: permissions.Permission{
-							Action:   permissions.ReadObjectAction,
-							Resource: permissions.ObjectArn(repository, entry.Path),
-						},
-					},
-				})
-				if c.handleAPIError(ctx, w, r, err) {
-					return
-				}
-				if authResponse.Allowed {
-					var expiry time.Time
-					objStat.PhysicalAddress, expiry, err = c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
-						StorageNamespace: repo.StorageNamespace,
-						IdentifierType:   entry.AddressType.ToIdentifierType(),
-						Identifier:       entry.PhysicalAddress,
-					}, block.PreSignModeRead)
-					if c.handleAPIError(ctx, w, r, err) {
-						return
-					}
-					if !expiry.IsZero() {
-						objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
-					}
-				}
-			}
-			objList = append(objList, objStat)
-		}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if c.handleAPIError(ctx, w, r, err) {
+		return
 	}
-	response := apigen.ObjectStatsList{
-		Pagination: apigen.Pagination{
-			HasMore:    hasMore,
-			MaxPerPage: DefaultMaxPerPage,
-			Results:    len(objList),
-		},
-		Results: objList,
+	defer func() {
+		_ = reader.Close()
+	}()
+	table, err := preview.Preview(reader, entry.Size, format, swag.IntValue(params.Rows))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("preview %s: %s", params.Path, err))
+		return
 	}
-	if len(objList) > 0 && hasMore {
-		lastObj := objList[len(objList)-1]
-		response.Pagination.NextOffset = lastObj.Path
+
+	response := apigen.ObjectPreview{
+		Format:    apigen.ObjectPreviewFormat(table.Format),
+		Columns:   make([]apigen.ObjectPreviewColumn, 0, len(table.Columns)),
+		Rows:      make([][]interface{}, 0, len(table.Rows)),
+		Truncated: table.Truncated,
+	}
+	for _, column := range table.Columns {
+		response.Columns = append(response.Columns, apigen.ObjectPreviewColumn{
+			Name: column.Name,
+			Type: apigen.ObjectPreviewColumnType(column.Type),
+		})
 	}
+	response.Rows = append(response.Rows, table.Rows...)
 	writeResponse(w, r, http.StatusOK, response)
 }
 
-func (c *Controller) StatObject(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.StatObjectParams) {
+func (c *Controller) SampleObjects(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.SampleObjectsParams) {
+	prefix := paginationPrefix(params.Prefix)
 	if !c.authorize(w, r, permissions.Node{
-		Permission: permissions.Permission{
-			Action:   permissions.ReadObjectAction,
-			Resource: permissions.ObjectArn(repository, params.Path),
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListObjectsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repository, prefix+"*"),
+				},
+			},
 		},
 	}) {
 		return
 	}
 	ctx := r.Context()
-	c.LogAction(ctx, "stat_object", r, repository, ref, "")
+	c.LogAction(ctx, "sample_objects", r, repository, ref, "")
 
-	repo, err := c.Catalog.GetRepository(ctx, repository)
-	if c.handleAPIError(ctx, w, r, err) {
-		return
+	sampleParams := catalog.SampleParams{
+		Prefix: prefix,
+		Rows:   swag.IntValue(params.Rows),
+		Seed:   swag.Int64Value(params.Seed),
 	}
-
-	entry, err := c.Catalog.GetEntry(ctx, repository, ref, params.Path, catalog.GetEntryParams{})
-	if c.handleAPIError(ctx, w, r, err) {
+	if params.Format != nil {
+		sampleParams.Format = preview.Format(*params.Format)
+	}
+	sample, err := c.Catalog.SampleObjects(ctx, repository, ref, sampleParams)
+	if errors.Is(err, catalog.ErrExpired) {
+		writeError(w, r, http.StatusGone, err)
 		return
 	}
-
-	qk, err := c.BlockAdapter.ResolveNamespace(repo.StorageNamespace, entry.PhysicalAddress, entry.AddressType.ToIdentifierType())
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
 
-	objStat := apigen.ObjectStats{
-		Checksum:        entry.Checksum,
-		Mtime:           entry.CreationDate.Unix(),
-		Path:            entry.Path,
-		PathType:        entryTypeObject,
-		PhysicalAddress: qk.Format(),
-		SizeBytes:       swag.Int64(entry.Size),
-		ContentType:     swag.String(entry.ContentType),
-	}
-
-	// add metadata if requested
-	var metadata map[string]string
-	if (params.UserMetadata == nil || *params.UserMetadata) && entry.Metadata != nil {
-		metadata = entry.Metadata
-	} else {
-		metadata = map[string]string{}
+	response := apigen.ObjectSample{
+		Format:   apigen.ObjectSampleFormat(sample.Format),
+		CommitId: sample.CommitID,
+		Columns:  make([]apigen.ObjectPreviewColumn, 0, len(sample.Columns)),
+		Rows:     make([][]interface{}, 0, len(sample.Rows)),
+		Objects:  sample.Objects,
+		RowsRead: sample.RowsRead,
 	}
-	objStat.Metadata = &apigen.ObjectUserMetadata{AdditionalProperties: metadata}
-
-	code := http.StatusOK
-	if entry.Expired {
-		code = http.StatusGone
-	} else if swag.BoolValue(params.Presign) {
-		// need to pre-sign the physical address
-		preSignedURL, expiry, err := c.BlockAdapter.GetPreSignedURL(ctx, block.ObjectPointer{
-			StorageNamespace: repo.StorageNamespace,
-			IdentifierType:   entry.AddressType.ToIdentifierType(),
-			Identifier:       entry.PhysicalAddress,
-		}, block.PreSignModeRead)
-		if c.handleAPIError(ctx, w, r, err) {
-			return
-		}
-		objStat.PhysicalAddress = preSignedURL
-		if !expiry.IsZero() {
-			objStat.PhysicalAddressExpiry = swag.Int64(expiry.Unix())
-		}
+	for _, column := range sample.Columns {
+		response.Columns = append(response.Columns, apigen.ObjectPreviewColumn{
+			Name: column.Name,
+			Type: apigen.ObjectPreviewColumnType(column.Type),
+		})
 	}
-	writeResponse(w, r, code, objStat)
+	response.Rows = append(response.Rows, sample.Rows...)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
 func (c *Controller) GetUnderlyingProperties(w http.ResponseWriter, r *http.Request, repository, ref string, params apigen.GetUnderlyingPropertiesParams) {
@@ -4705,6 +7546,9 @@ func (c *Controller) GetUnderlyingProperties(w http.ResponseWriter, r *http.Requ
 	if c.handleAPIError(ctx, w, r, err) {
 		return
 	}
+	if !c.authorizeAliasTarget(w, r, repository, entry, writeError) {
+		return
+	}
 
 	// read object properties from underlying storage
 	properties, err := c.BlockAdapter.GetProperties(ctx, block.ObjectPointer{
@@ -4739,28 +7583,128 @@ func (c *Controller) MergeIntoBranch(w http.ResponseWriter, r *http.Request, bod
 		writeError(w, r, http.StatusUnauthorized, "user not found")
 		return
 	}
+	pathScope := swag.StringValue(body.PathScope)
+	approvedCommitID, ok := c.authorizeMergeCodeOwners(w, r, user, repository, destinationBranch, sourceRef, pathScope)
+	if !ok {
+		return
+	}
+	message := swag.StringValue(body.Message)
+	if approvedCommitID != "" {
+		// merge the commit approved by the code owners, the source may have moved since
+		if message == "" {
+			message = fmt.Sprintf("Merge '%s' into '%s'", sourceRef, destinationBranch)
+		}
+		sourceRef = approvedCommitID
+	}
 	metadata := map[string]string{}
 	if body.Metadata != nil {
 		metadata = body.Metadata.AdditionalProperties
 	}
 
-	reference, err := c.Catalog.Merge(ctx,
+	opts := []graveler.SetOptionsFunc{
+		graveler.WithForce(swag.BoolValue(body.Force)),
+		graveler.WithAllowEmpty(swag.BoolValue(body.AllowEmpty)),
+	}
+	if pathScope != "" {
+		opts = append(opts, graveler.WithPathScope(graveler.Prefix(pathScope)))
+	}
+	asyncThreshold := c.Config.Graveler.Merge.AsyncThreshold
+	if !swag.BoolValue(body.AllowAsync) || asyncThreshold <= 0 {
+		reference, err := c.Catalog.Merge(ctx,
+			repository, destinationBranch, sourceRef,
+			user.Committer(),
+			message,
+			metadata,
+			swag.StringValue(body.Strategy),
+			opts...,
+		)
+		c.writeMergeResult(w, r, reference, err)
+		return
+	}
+
+	// run the merge in the background, and return its task ID if it does not complete within the threshold
+	taskID, done, err := c.Catalog.MergeSubmit(ctx,
 		repository, destinationBranch, sourceRef,
 		user.Committer(),
-		swag.StringValue(body.Message),
+		message,
 		metadata,
 		swag.StringValue(body.Strategy),
-		graveler.WithForce(swag.BoolValue(body.Force)),
-		graveler.WithAllowEmpty(swag.BoolValue(body.AllowEmpty)),
+		opts...,
 	)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	timer := time.NewTimer(asyncThreshold)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		c.writeMergeResult(w, r, result.Reference, result.Err)
+	case <-timer.C:
+		writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+			Id: taskID,
+		})
+	case <-ctx.Done():
+		writeResponse(w, r, http.StatusAccepted, apigen.TaskInfo{
+			Id: taskID,
+		})
+	}
+}
 
+// authorizeMergeCodeOwners verifies that user is a member of one of the groups owning each of the changes merged into
+// a branch protected by its CODEOWNERS file: merging is the approval of the owners. Returns the commit sourceRef was
+// resolved to when the check applies, which is the commit to merge.
+func (c *Controller) authorizeMergeCodeOwners(w http.ResponseWriter, r *http.Request, user *model.User, repository, destinationBranch, sourceRef, pathScope string) (string, bool) {
+	ctx := r.Context()
+	approvals, sourceCommitID, err := c.Catalog.MergeCodeOwners(ctx, repository, destinationBranch, sourceRef, pathScope)
+	if c.handleAPIError(ctx, w, r, err) {
+		return "", false
+	}
+	if len(approvals) == 0 {
+		return sourceCommitID, true
+	}
+	userGroups := make(map[string]struct{})
+	after := ""
+	for {
+		groups, paginator, err := c.Auth.ListUserGroups(ctx, user.Username, &model.PaginationParams{
+			After:  after,
+			Amount: -1,
+		})
+		if c.handleAPIError(ctx, w, r, err) {
+			return "", false
+		}
+		for _, group := range groups {
+			userGroups[group.DisplayName] = struct{}{}
+		}
+		if paginator.NextPageToken == "" {
+			break
+		}
+		after = paginator.NextPageToken
+	}
+	for _, approval := range approvals {
+		approved := false
+		for _, owner := range approval.Owners {
+			if _, ok := userGroups[owner]; ok {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			writeError(w, r, http.StatusForbidden, fmt.Sprintf("merging changes to %s requires approval of a member of %s",
+				approval.Path, strings.Join(approval.Owners, ", ")))
+			return "", false
+		}
+	}
+	return sourceCommitID, true
+}
+
+func (c *Controller) writeMergeResult(w http.ResponseWriter, r *http.Request, reference string, err error) {
 	if errors.Is(err, graveler.ErrConflictFound) {
 		writeResponse(w, r, http.StatusConflict, apigen.MergeResult{
 			Reference: reference,
 		})
 		return
 	}
-	if c.handleAPIError(ctx, w, r, err) {
+	if c.handleAPIError(r.Context(), w, r, err) {
 		return
 	}
 	writeResponse(w, r, http.StatusOK, apigen.MergeResult{
@@ -4768,6 +7712,40 @@ func (c *Controller) MergeIntoBranch(w http.ResponseWriter, r *http.Request, bod
 	})
 }
 
+func (c *Controller) MergeIntoBranchStatus(w http.ResponseWriter, r *http.Request, repository, branch string, params apigen.MergeIntoBranchStatusParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadBranchAction,
+			Resource: permissions.BranchArn(repository, branch),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	status, err := c.Catalog.MergeStatus(ctx, repository, branch, params.TaskId)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+
+	response := &apigen.MergeStatus{
+		Id:              params.TaskId,
+		Done:            status.Task.Done,
+		UpdateTime:      status.Task.UpdatedAt.AsTime(),
+		RangesProcessed: status.Task.Progress,
+		RangesTotal:     status.RangesTotal,
+	}
+	if status.Task.Error != "" {
+		response.Error = apiutil.Ptr(status.Task.Error)
+	}
+	if status.Reference != "" {
+		response.Reference = apiutil.Ptr(status.Reference)
+	}
+	if status.Conflict {
+		response.Conflict = apiutil.Ptr(true)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
 func (c *Controller) FindMergeBase(w http.ResponseWriter, r *http.Request, repository string, sourceRef string, destinationRef string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -4863,6 +7841,66 @@ func (c *Controller) DeleteTag(w http.ResponseWriter, r *http.Request, repositor
 	writeResponse(w, r, http.StatusNoContent, nil)
 }
 
+func (c *Controller) RestoreTag(w http.ResponseWriter, r *http.Request, repository, tag string, params apigen.RestoreTagParams) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.CreateTagAction,
+			Resource: permissions.TagArn(repository, tag),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "restore_tag", r, repository, tag, "")
+	commitID, err := c.Catalog.RestoreTag(ctx, repository, tag, graveler.WithForce(swag.BoolValue(params.Force)))
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.Ref{
+		CommitId: commitID,
+		Id:       tag,
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+func (c *Controller) ListDeletedRefs(w http.ResponseWriter, r *http.Request, repository string) {
+	if !c.authorize(w, r, permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListBranchesAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ListTagsAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			},
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	c.LogAction(ctx, "list_deleted_refs", r, repository, "", "")
+	refs, err := c.Catalog.ListDeletedRefs(ctx, repository)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	results := make([]apigen.DeletedRef, 0, len(refs))
+	for _, ref := range refs {
+		results = append(results, apigen.DeletedRef{
+			Type:      apigen.DeletedRefType(ref.Type),
+			Id:        ref.ID,
+			CommitId:  ref.CommitID,
+			DeletedAt: ref.DeletedAt.Unix(),
+		})
+	}
+	writeResponse(w, r, http.StatusOK, apigen.DeletedRefList{Results: results})
+}
+
 func (c *Controller) GetTag(w http.ResponseWriter, r *http.Request, repository, tag string) {
 	if !c.authorize(w, r, permissions.Node{
 		Permission: permissions.Permission{
@@ -5067,6 +8105,99 @@ func (c *Controller) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, r, http.StatusOK, response)
 }
 
+func (c *Controller) ListNotificationSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	if c.Notifications == nil {
+		writeError(w, r, http.StatusNotImplemented, "notifications are not enabled")
+		return
+	}
+	subs, err := c.Notifications.ListSubscriptions(ctx, user.Username)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	response := apigen.NotificationSubscriptionList{
+		Results: make([]apigen.NotificationSubscription, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		response.Results = append(response.Results, newNotificationSubscription(sub))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+func (c *Controller) CreateNotificationSubscription(w http.ResponseWriter, r *http.Request, body apigen.CreateNotificationSubscriptionJSONRequestBody) {
+	if !c.authorize(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadRepositoryAction,
+			Resource: permissions.RepoArn(body.Repository),
+		},
+	}) {
+		return
+	}
+	ctx := r.Context()
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	if c.Notifications == nil {
+		writeError(w, r, http.StatusNotImplemented, "notifications are not enabled")
+		return
+	}
+	c.LogAction(ctx, "create_notification_subscription", r, body.Repository, "", "")
+
+	if _, err := c.Catalog.GetRepository(ctx, body.Repository); c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	events := make([]notifications.EventType, 0, len(body.Events))
+	for _, e := range body.Events {
+		events = append(events, notifications.EventType(e))
+	}
+	sub, err := c.Notifications.Subscribe(ctx, user.Username, body.Repository, events, notifications.Channel(body.Channel), body.Target)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, newNotificationSubscription(sub))
+}
+
+func (c *Controller) DeleteNotificationSubscription(w http.ResponseWriter, r *http.Request, subscriptionID string) {
+	ctx := r.Context()
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, "missing user")
+		return
+	}
+	if c.Notifications == nil {
+		writeError(w, r, http.StatusNotImplemented, "notifications are not enabled")
+		return
+	}
+	c.LogAction(ctx, "delete_notification_subscription", r, "", "", "")
+	err = c.Notifications.Unsubscribe(ctx, user.Username, subscriptionID)
+	if c.handleAPIError(ctx, w, r, err) {
+		return
+	}
+	writeResponse(w, r, http.StatusNoContent, nil)
+}
+
+func newNotificationSubscription(sub *notifications.Subscription) apigen.NotificationSubscription {
+	events := make(apigen.NotificationEvents, 0, len(sub.Events))
+	for _, e := range sub.Events {
+		events = append(events, string(e))
+	}
+	return apigen.NotificationSubscription{
+		Id:           sub.ID,
+		Repository:   sub.Repository,
+		Events:       events,
+		Channel:      string(sub.Channel),
+		Target:       sub.Target,
+		CreationDate: sub.CreatedAt.Unix(),
+	}
+}
+
 func (c *Controller) GetLakeFSVersion(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	_, err := auth.GetUser(ctx)
@@ -5224,6 +8355,14 @@ func paginationDelimiter(v *apigen.PaginationDelimiter) string {
 	return string(*v)
 }
 
+// streamLimit returns the maximal number of results of a streaming response, 0 for all results
+func streamLimit(v *apigen.StreamLimit) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
 func paginationAmount(v *apigen.PaginationAmount) int {
 	if v == nil {
 		return DefaultPerPage
@@ -5295,6 +8434,18 @@ func (c *Controller) LogAction(ctx context.Context, action string, r *http.Reque
 	}).Debug("performing API action")
 	c.Collector.CollectEvent(ev)
 	usageCounter.Add(1)
+	if ev.Repository != "" {
+		stats.RecordAttribution(ev.Repository, ev.Ref, ev.UserID)
+	}
+}
+
+// recordObjectAccess counts a read of path by the user of the request, for object access tracking
+func (c *Controller) recordObjectAccess(ctx context.Context, repository, path string) {
+	var username string
+	if user, _ := auth.GetUser(ctx); user != nil {
+		username = user.Username
+	}
+	c.Catalog.RecordObjectAccess(repository, path, username)
 }
 
 func paginationFor(hasMore bool, results interface{}, fieldName string) apigen.Pagination {
@@ -5323,12 +8474,16 @@ func (c *Controller) authorizeCallback(w http.ResponseWriter, r *http.Request, p
 	ctx := r.Context()
 	user, err := auth.GetUser(ctx)
 	if err != nil {
+		// requests without credentials are allowed by the anonymous read policy only
+		if c.AnonymousRead.Authorize(ctx, perms) {
+			return true
+		}
 		cb(w, r, http.StatusUnauthorized, ErrAuthenticatingRequest)
 		return false
 	}
 	resp, err := c.Auth.Authorize(ctx, &auth.AuthorizationRequest{
 		Username:            user.Username,
-		RequiredPermissions: perms,
+		RequiredPermissions: permissions.WithActionsConfig(perms),
 	})
 	if err != nil {
 		cb(w, r, http.StatusInternalServerError, err)
@@ -5349,6 +8504,20 @@ func (c *Controller) authorize(w http.ResponseWriter, r *http.Request, perms per
 	return c.authorizeCallback(w, r, perms, writeError)
 }
 
+// authorizeAliasTarget checks that the user may read the object an alias entry was resolved to. Reading an alias
+// serves the object found at its target, so permission to read the alias path alone is not enough.
+func (c *Controller) authorizeAliasTarget(w http.ResponseWriter, r *http.Request, repository string, entry *catalog.DBEntry, cb func(w http.ResponseWriter, r *http.Request, code int, v interface{})) bool {
+	if entry.AliasTarget == "" {
+		return true
+	}
+	return c.authorizeCallback(w, r, permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, entry.AliasTarget),
+		},
+	}, cb)
+}
+
 func (c *Controller) isNameValid(name, nameType string) (bool, string) {
 	// URLs are % encoded. Allowing % signs in entity names would
 	// limit the ability to use these entity names in the URL for both
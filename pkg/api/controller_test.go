@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -22,6 +23,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/apache/arrow/go/arrow/ipc"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-openapi/swag"
 	"github.com/go-test/deep"
@@ -39,6 +41,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/notifications"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
@@ -1358,6 +1361,49 @@ func TestController_SetRepositoryMetadataHandler(t *testing.T) {
 	})
 }
 
+func TestController_ArchiveRepositoryHandler(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	listed := func(t *testing.T, archived bool) bool {
+		t.Helper()
+		resp, err := clt.ListRepositoriesWithResponse(ctx, &apigen.ListRepositoriesParams{Archived: swag.Bool(archived)})
+		verifyResponseOK(t, resp, err)
+		for _, r := range resp.JSON200.Results {
+			if r.Id == repo {
+				return true
+			}
+		}
+		return false
+	}
+
+	resp, err := clt.ArchiveRepositoryWithResponse(ctx, repo)
+	verifyResponseOK(t, resp, err)
+	require.False(t, listed(t, false), "archived repository in default listing")
+	require.True(t, listed(t, true), "archived repository missing from archived listing")
+
+	archiveResp, err := clt.ArchiveRepositoryWithResponse(ctx, repo)
+	testutil.Must(t, err)
+	require.NotNil(t, archiveResp.JSON409, "expected conflict archiving twice, got %s", archiveResp.Status())
+
+	deleteResp, err := clt.DeleteRepositoryWithResponse(ctx, repo, &apigen.DeleteRepositoryParams{})
+	testutil.Must(t, err)
+	require.NotNil(t, deleteResp.JSON409, "expected conflict deleting within grace period, got %s", deleteResp.Status())
+
+	restoreResp, err := clt.RestoreRepositoryWithResponse(ctx, repo)
+	verifyResponseOK(t, restoreResp, err)
+	require.True(t, listed(t, false), "restored repository missing from default listing")
+	require.False(t, listed(t, true), "restored repository in archived listing")
+
+	restoreResp, err = clt.RestoreRepositoryWithResponse(ctx, repo)
+	testutil.Must(t, err)
+	require.NotNil(t, restoreResp.JSON409, "expected conflict restoring an active repository, got %s", restoreResp.Status())
+}
+
 func TestController_DeleteRepositoryMetadataHandler(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -2462,6 +2508,93 @@ func TestController_ObjectsListObjectsHandler(t *testing.T) {
 			t.Fatalf("expected next offset to be foo/bar, got %s", resp.JSON200.Pagination.NextOffset)
 		}
 	})
+
+	t.Run("get object list selected fields", func(t *testing.T) {
+		prefix := apigen.PaginationPrefix("foo/")
+		resp, err := clt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{
+			Prefix: &prefix,
+			Fields: &apigen.FieldSelection{"size_bytes"},
+		})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 4)
+		for _, obj := range resp.JSON200.Results {
+			require.NotEmpty(t, obj.Path)
+			require.NotNil(t, obj.SizeBytes)
+			require.Empty(t, obj.Checksum)
+			require.Empty(t, obj.PhysicalAddress)
+			require.Nil(t, obj.Metadata)
+		}
+	})
+
+	t.Run("get object list compact", func(t *testing.T) {
+		prefix := apigen.PaginationPrefix("foo/")
+		resp, err := clt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{
+			Prefix:  &prefix,
+			Compact: apiutil.Ptr(apigen.CompactListing(true)),
+		})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 4)
+		for _, obj := range resp.JSON200.Results {
+			require.NotEmpty(t, obj.PhysicalAddress)
+			require.Empty(t, obj.Checksum)
+			require.Nil(t, obj.Metadata)
+		}
+	})
+
+	t.Run("stream object list ndjson", func(t *testing.T) {
+		prefix := apigen.PaginationPrefix("foo/")
+		resp, err := clt.ListObjects(ctx, repo, "main", &apigen.ListObjectsParams{
+			Prefix:      &prefix,
+			StreamLimit: apiutil.Ptr(apigen.StreamLimit(3)),
+		}, func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Accept", "application/x-ndjson")
+			return nil
+		})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+		dec := json.NewDecoder(resp.Body)
+		var paths []string
+		for dec.More() {
+			var obj apigen.ObjectStats
+			require.NoError(t, dec.Decode(&obj))
+			paths = append(paths, obj.Path)
+		}
+		require.Equal(t, []string{"foo/a_dir/baz", "foo/bar", "foo/baz"}, paths)
+	})
+
+	t.Run("stream object list arrow", func(t *testing.T) {
+		prefix := apigen.PaginationPrefix("foo/")
+		resp, err := clt.ListObjects(ctx, repo, "main", &apigen.ListObjectsParams{
+			Prefix: &prefix,
+		}, func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Accept", "application/vnd.apache.arrow.stream")
+			return nil
+		})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		reader, err := ipc.NewReader(resp.Body)
+		require.NoError(t, err)
+		defer reader.Release()
+		rows := 0
+		for reader.Next() {
+			rec := reader.Record()
+			require.Equal(t, "path", rec.ColumnName(0))
+			rows += int(rec.NumRows())
+		}
+		require.NoError(t, reader.Err())
+		require.Equal(t, 4, rows)
+	})
+
+	t.Run("get object list unknown field", func(t *testing.T) {
+		resp, err := clt.ListObjectsWithResponse(ctx, repo, "main", &apigen.ListObjectsParams{
+			Fields: &apigen.FieldSelection{"no_such_field"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.JSON400, "expected bad request, got %s", resp.Status())
+	})
 }
 
 func TestController_ObjectsHeadObjectHandler(t *testing.T) {
@@ -2687,16 +2820,596 @@ func TestController_ObjectsGetObjectHandler(t *testing.T) {
 		require.Equal(t, int64(37), resp.HTTPResponse.ContentLength)
 		require.Equal(t, "\"\"", resp.HTTPResponse.Header.Get("ETag"))
 	})
-	t.Run("get object with if-none-match returns expected response for empty etag", func(t *testing.T) {
-		eTagInput := "\"\""
-		resp, err := clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{
-			Path:        "foo/empty-etag",
-			IfNoneMatch: &eTagInput,
-		})
-		require.Nil(t, err)
-		require.Equal(t, http.StatusNotModified, resp.HTTPResponse.StatusCode)
-		require.Equal(t, int64(0), resp.HTTPResponse.ContentLength)
-		require.Empty(t, resp.HTTPResponse.Header.Get("ETag"))
+	t.Run("get object with if-none-match returns expected response for empty etag", func(t *testing.T) {
+		eTagInput := "\"\""
+		resp, err := clt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{
+			Path:        "foo/empty-etag",
+			IfNoneMatch: &eTagInput,
+		})
+		require.Nil(t, err)
+		require.Equal(t, http.StatusNotModified, resp.HTTPResponse.StatusCode)
+		require.Equal(t, int64(0), resp.HTTPResponse.ContentLength)
+		require.Empty(t, resp.HTTPResponse.Header.Get("ETag"))
+	})
+}
+
+func TestController_ObjectAliasTargetAuthorization(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	testutil.Must(t, err)
+	uploadResp, err := uploadObjectHelper(t, ctx, clt, "secret/data", strings.NewReader("secret"), repo, "main")
+	verifyResponseOK(t, uploadResp, err)
+	aliasResp, err := clt.CreateObjectAliasWithResponse(ctx, repo, "main", &apigen.CreateObjectAliasParams{Path: "public/link"}, apigen.CreateObjectAliasJSONRequestBody{Target: "secret/data"})
+	verifyResponseOK(t, aliasResp, err)
+
+	// a user allowed to read only under public/
+	creds := createUserWithDefaultGroup(t, clt)
+	const policyID = "ReadPublicObjects"
+	policyResp, err := clt.CreatePolicyWithResponse(ctx, apigen.CreatePolicyJSONRequestBody{
+		Id: policyID,
+		Statement: []apigen.Statement{
+			{
+				Action:   []string{"fs:ReadObject"},
+				Effect:   "allow",
+				Resource: "arn:lakefs:fs:::repository/" + repo + "/object/public/*",
+			},
+		},
+	})
+	verifyResponseOK(t, policyResp, err)
+	attachResp, err := clt.AttachPolicyToUserWithResponse(ctx, "test@example.com", policyID)
+	verifyResponseOK(t, attachResp, err)
+	userClt := setupClientByEndpoint(t, deps.server.URL, creds.AccessKeyID, creds.SecretAccessKey)
+
+	t.Run("get object", func(t *testing.T) {
+		resp, err := userClt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: "public/link"})
+		testutil.Must(t, err)
+		if resp.StatusCode() != http.StatusUnauthorized {
+			t.Fatalf("GetObject of alias to unreadable target status code %d, expected %d", resp.StatusCode(), http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("stat object", func(t *testing.T) {
+		resp, err := userClt.StatObjectWithResponse(ctx, repo, "main", &apigen.StatObjectParams{Path: "public/link"})
+		testutil.Must(t, err)
+		if resp.JSON401 == nil {
+			t.Fatalf("StatObject of alias to unreadable target status code %d, expected %d", resp.StatusCode(), http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("forged alias", func(t *testing.T) {
+		contentType, body := writeMultipart("content", "forged", "forged")
+		resp, err := clt.UploadObjectWithBodyWithResponse(ctx, repo, "main", &apigen.UploadObjectParams{Path: "public/forged"}, contentType, body,
+			func(_ context.Context, req *http.Request) error {
+				req.Header.Set("X-Lakefs-Internal-Alias-Target", "secret/data")
+				return nil
+			})
+		testutil.Must(t, err)
+		if resp.JSON400 == nil {
+			t.Fatalf("UploadObject with alias target metadata status code %d, expected %d", resp.StatusCode(), http.StatusBadRequest)
+		}
+	})
+
+	t.Run("readable target", func(t *testing.T) {
+		uploadResp, err := uploadObjectHelper(t, ctx, clt, "public/data", strings.NewReader("public"), repo, "main")
+		verifyResponseOK(t, uploadResp, err)
+		aliasResp, err := clt.CreateObjectAliasWithResponse(ctx, repo, "main", &apigen.CreateObjectAliasParams{Path: "public/other"}, apigen.CreateObjectAliasJSONRequestBody{Target: "public/data"})
+		verifyResponseOK(t, aliasResp, err)
+
+		resp, err := userClt.GetObjectWithResponse(ctx, repo, "main", &apigen.GetObjectParams{Path: "public/other"})
+		verifyResponseOK(t, resp, err)
+		if string(resp.Body) != "public" {
+			t.Fatalf("GetObject of alias body = '%s', expected 'public'", string(resp.Body))
+		}
+	})
+}
+
+func TestController_ObjectLink(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	testutil.Must(t, err)
+
+	writeEntry := func(content string) {
+		t.Helper()
+		address := upload.DefaultPathProvider.NewPath()
+		blob, err := upload.WriteBlob(ctx, deps.blocks, onBlock(deps, "ns1"), address, strings.NewReader(content), int64(len(content)), block.PutOpts{})
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            "data/file",
+			PhysicalAddress: blob.PhysicalAddress,
+			CreationDate:    time.Now(),
+			Size:            blob.Size,
+			Checksum:        blob.Checksum,
+		}))
+	}
+	writeEntry("committed content")
+	commit, err := deps.catalog.Commit(ctx, repo, "main", "add file", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	// uncommitted changes are not read by the link
+	writeEntry("uncommitted content")
+
+	resp, err := clt.CreateObjectLinkWithResponse(ctx, repo, "main", apigen.CreateObjectLinkJSONRequestBody{Path: "data/file"})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+	link := resp.JSON201
+	require.Equal(t, commit.Reference, link.CommitId)
+
+	t.Run("get", func(t *testing.T) {
+		getResp, err := http.Get(link.Url)
+		testutil.Must(t, err)
+		defer func() { _ = getResp.Body.Close() }()
+		require.Equal(t, http.StatusOK, getResp.StatusCode)
+		body, err := io.ReadAll(getResp.Body)
+		testutil.Must(t, err)
+		require.Equal(t, "committed content", string(body))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		getResp, err := http.Get(link.Url + "x")
+		testutil.Must(t, err)
+		defer func() { _ = getResp.Body.Close() }()
+		require.Equal(t, http.StatusUnauthorized, getResp.StatusCode)
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		resp, err := clt.CreateObjectLinkWithResponse(ctx, repo, "main", apigen.CreateObjectLinkJSONRequestBody{Path: "data/missing"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+
+	t.Run("expiry too long", func(t *testing.T) {
+		expiresIn := int(api.MaxObjectLinkExpiry.Seconds()) + 1
+		resp, err := clt.CreateObjectLinkWithResponse(ctx, repo, "main", apigen.CreateObjectLinkJSONRequestBody{Path: "data/file", ExpiresIn: &expiresIn})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
+
+func TestController_Lineage(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	commitFile := func(repo, path string) string {
+		t.Helper()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: "address",
+			CreationDate:    time.Now(),
+			Size:            1,
+			Checksum:        "checksum",
+		}))
+		commit, err := deps.catalog.Commit(ctx, repo, "main", "add "+path, "some_user", nil, nil, nil, false)
+		testutil.Must(t, err)
+		return commit.Reference
+	}
+	rawRepo := testUniqueRepoName()
+	rawCommit := commitFile(rawRepo, "data/raw.csv")
+	modelRepo := testUniqueRepoName()
+	modelCommit := commitFile(modelRepo, "models/m1/model.bin")
+
+	resp, err := clt.AddCommitLineageWithResponse(ctx, modelRepo, "main", apigen.AddCommitLineageJSONRequestBody{
+		Type:        apigen.LineageEdgeCreationTypeProducedBy,
+		Path:        swag.String("models/m1/"),
+		JobSystem:   swag.String("airflow"),
+		RunId:       swag.String("train-42"),
+		CodeVersion: swag.String("3f2a1c"),
+	})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+	require.Equal(t, modelCommit, resp.JSON201.CommitId)
+	require.Equal(t, "admin", swag.StringValue(resp.JSON201.CreatedBy))
+
+	resp, err = clt.AddCommitLineageWithResponse(ctx, modelRepo, modelCommit, apigen.AddCommitLineageJSONRequestBody{
+		Type:             apigen.LineageEdgeCreationTypeDerivedFrom,
+		SourceRepository: swag.String(rawRepo),
+		SourceRef:        swag.String("main"),
+		SourcePath:       swag.String("data/"),
+	})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+	require.Equal(t, rawCommit, swag.StringValue(resp.JSON201.SourceCommitId))
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListCommitLineageWithResponse(ctx, modelRepo, modelCommit, &apigen.ListCommitLineageParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Len(t, resp.JSON200.Results, 2)
+		require.Equal(t, apigen.LineageEdgeTypeProducedBy, resp.JSON200.Results[0].Type)
+		require.Equal(t, apigen.LineageEdgeTypeDerivedFrom, resp.JSON200.Results[1].Type)
+	})
+
+	t.Run("upstream", func(t *testing.T) {
+		resp, err := clt.GetLineageGraphWithResponse(ctx, modelRepo, &apigen.GetLineageGraphParams{
+			Ref:  swag.String("main"),
+			Path: swag.String("models/m1/model.bin"),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Len(t, resp.JSON200.Edges, 2)
+		nodes := make(map[string]apigen.LineageNode)
+		for _, node := range resp.JSON200.Nodes {
+			nodes[node.Id] = node
+		}
+		require.Len(t, nodes, 3)
+		require.Contains(t, nodes, catalog.LineageCommitNodeID(rawRepo, rawCommit))
+		require.Contains(t, nodes, catalog.LineageJobRunNodeID("airflow", "train-42"))
+		for _, edge := range resp.JSON200.Edges {
+			require.Equal(t, catalog.LineageCommitNodeID(modelRepo, modelCommit), edge.To)
+			require.Contains(t, nodes, edge.From)
+		}
+	})
+
+	t.Run("upstream other path", func(t *testing.T) {
+		resp, err := clt.GetLineageGraphWithResponse(ctx, modelRepo, &apigen.GetLineageGraphParams{
+			Ref:  swag.String("main"),
+			Path: swag.String("models/m2/"),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		// only the edge attached to the whole commit applies
+		require.Len(t, resp.JSON200.Edges, 1)
+		require.Equal(t, apigen.LineageEdgeTypeDerivedFrom, resp.JSON200.Edges[0].Type)
+	})
+
+	t.Run("downstream", func(t *testing.T) {
+		direction := apigen.GetLineageGraphParamsDirectionDownstream
+		resp, err := clt.GetLineageGraphWithResponse(ctx, rawRepo, &apigen.GetLineageGraphParams{
+			Ref:       swag.String("main"),
+			Path:      swag.String("data/raw.csv"),
+			Direction: &direction,
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Len(t, resp.JSON200.Edges, 1)
+		require.Equal(t, catalog.LineageCommitNodeID(modelRepo, modelCommit), resp.JSON200.Edges[0].To)
+	})
+
+	t.Run("invalid edge", func(t *testing.T) {
+		resp, err := clt.AddCommitLineageWithResponse(ctx, modelRepo, "main", apigen.AddCommitLineageJSONRequestBody{
+			Type: apigen.LineageEdgeCreationTypeProducedBy,
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		resp, err := clt.AddCommitLineageWithResponse(ctx, modelRepo, "main", apigen.AddCommitLineageJSONRequestBody{
+			Type:             apigen.LineageEdgeCreationTypeDerivedFrom,
+			SourceRepository: swag.String(rawRepo),
+			SourceRef:        swag.String("no-such-branch"),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_MlflowRuns(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	commitFile := func(path string) string {
+		t.Helper()
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: "address-" + path,
+			CreationDate:    time.Now(),
+			Size:            1,
+			Checksum:        "checksum",
+		}))
+		commit, err := deps.catalog.Commit(ctx, repo, "main", "add "+path, "some_user", nil, nil, nil, false)
+		testutil.Must(t, err)
+		return commit.Reference
+	}
+	dataCommit := commitFile("data/train.csv")
+	resp, err := clt.LinkMlflowRunWithResponse(ctx, repo, dataCommit, apigen.LinkMlflowRunJSONRequestBody{
+		RunId: "a1b2c3",
+		Role:  apigen.MlflowRunLinkRoleInput,
+		Path:  swag.String("data/"),
+	})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+	require.Equal(t, apigen.LineageEdgeTypeUsedBy, resp.JSON201.Type)
+	require.Equal(t, catalog.LineageJobRunNodeID(catalog.MLflowJobSystem, "a1b2c3"), resp.JSON201.To)
+
+	modelCommit := commitFile("models/m1/model.pkl")
+	resp, err = clt.LinkMlflowRunWithResponse(ctx, repo, "main", apigen.LinkMlflowRunJSONRequestBody{
+		RunId: "a1b2c3",
+		Role:  apigen.MlflowRunLinkRoleOutput,
+		Path:  swag.String("models/m1/"),
+	})
+	testutil.Must(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode(), string(resp.Body))
+	require.Equal(t, apigen.LineageEdgeTypeProducedBy, resp.JSON201.Type)
+
+	t.Run("run commits", func(t *testing.T) {
+		resp, err := clt.ListJobRunLineageWithResponse(ctx, repo, catalog.MLflowJobSystem, "a1b2c3", &apigen.ListJobRunLineageParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Len(t, resp.JSON200.Results, 2)
+		require.Equal(t, dataCommit, resp.JSON200.Results[0].CommitId)
+		require.Equal(t, modelCommit, resp.JSON200.Results[1].CommitId)
+	})
+
+	t.Run("other run", func(t *testing.T) {
+		resp, err := clt.ListJobRunLineageWithResponse(ctx, repo, catalog.MLflowJobSystem, "d4e5f6", &apigen.ListJobRunLineageParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Empty(t, resp.JSON200.Results)
+	})
+
+	t.Run("downstream", func(t *testing.T) {
+		direction := apigen.GetLineageGraphParamsDirectionDownstream
+		resp, err := clt.GetLineageGraphWithResponse(ctx, repo, &apigen.GetLineageGraphParams{
+			Ref:       swag.String(dataCommit),
+			Path:      swag.String("data/train.csv"),
+			Direction: &direction,
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Len(t, resp.JSON200.Edges, 1)
+		require.Equal(t, apigen.LineageEdgeTypeUsedBy, resp.JSON200.Edges[0].Type)
+	})
+
+	t.Run("upstream", func(t *testing.T) {
+		resp, err := clt.GetLineageGraphWithResponse(ctx, repo, &apigen.GetLineageGraphParams{
+			Ref:  swag.String(modelCommit),
+			Path: swag.String("models/m1/"),
+		})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Len(t, resp.JSON200.Edges, 1)
+		require.Equal(t, apigen.LineageEdgeTypeProducedBy, resp.JSON200.Edges[0].Type)
+	})
+}
+
+func TestController_DatasetReleases(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	commitFile := func(path string) string {
+		t.Helper()
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: "address-" + path,
+			CreationDate:    time.Now(),
+			Size:            1,
+			Checksum:        "checksum",
+		}))
+		commit, err := deps.catalog.Commit(ctx, repo, "main", "add "+path, "some_user", nil, nil, nil, false)
+		testutil.Must(t, err)
+		return commit.Reference
+	}
+	release := func(version string) int {
+		t.Helper()
+		resp, err := clt.CreateDatasetReleaseWithResponse(ctx, "customers", apigen.CreateDatasetReleaseJSONRequestBody{
+			Version: version,
+			Sources: []apigen.DatasetReleaseSourceCreation{{Repository: repo, Ref: "main", Prefix: swag.String("customers/")}},
+		})
+		testutil.Must(t, err)
+		return resp.StatusCode()
+	}
+
+	firstCommit := commitFile("customers/part-0.parquet")
+	require.Equal(t, http.StatusCreated, release("1.4.2"))
+	secondCommit := commitFile("customers/part-1.parquet")
+	require.Equal(t, http.StatusCreated, release("1.10.0"))
+	require.Equal(t, http.StatusCreated, release("2.0.0-rc.1"))
+
+	t.Run("immutable", func(t *testing.T) {
+		require.Equal(t, http.StatusConflict, release("1.4.2"))
+		resp, err := clt.GetDatasetReleaseWithResponse(ctx, "customers", "1.4.2")
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Equal(t, firstCommit, resp.JSON200.Sources[0].CommitId)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, release("v1.5"))
+		require.Equal(t, http.StatusBadRequest, release("1.5.0+build.1"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListDatasetReleasesWithResponse(ctx, "customers", &apigen.ListDatasetReleasesParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		versions := make([]string, 0, len(resp.JSON200.Results))
+		for _, result := range resp.JSON200.Results {
+			versions = append(versions, result.Version)
+		}
+		require.Equal(t, []string{"2.0.0-rc.1", "1.10.0", "1.4.2"}, versions)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		for _, tt := range []struct {
+			reference string
+			version   string
+			commitID  string
+		}{
+			{reference: "dataset:customers@1.4.2", version: "1.4.2", commitID: firstCommit},
+			{reference: "dataset:customers@~1.4", version: "1.4.2", commitID: firstCommit},
+			{reference: "dataset:customers@^1", version: "1.10.0", commitID: secondCommit},
+			{reference: "customers", version: "1.10.0", commitID: secondCommit},
+			{reference: "dataset:customers@latest", version: "1.10.0", commitID: secondCommit},
+			{reference: "dataset:customers@>=2.0.0-rc.0", version: "2.0.0-rc.1", commitID: secondCommit},
+		} {
+			resp, err := clt.ResolveDatasetReleaseWithResponse(ctx, &apigen.ResolveDatasetReleaseParams{Reference: tt.reference})
+			testutil.Must(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode(), tt.reference)
+			require.Equal(t, tt.version, resp.JSON200.Version, tt.reference)
+			require.Equal(t, tt.commitID, resp.JSON200.Sources[0].CommitId, tt.reference)
+		}
+
+		resp, err := clt.ResolveDatasetReleaseWithResponse(ctx, &apigen.ResolveDatasetReleaseParams{Reference: "dataset:customers@^3"})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+}
+
+func TestController_ListCommitQuality(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	testutil.Must(t, err)
+	writeEntry := func(path, content string) {
+		t.Helper()
+		address := upload.DefaultPathProvider.NewPath()
+		blob, err := upload.WriteBlob(ctx, deps.blocks, onBlock(deps, "ns1"), address, strings.NewReader(content), int64(len(content)), block.PutOpts{})
+		testutil.Must(t, err)
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: blob.PhysicalAddress,
+			CreationDate:    time.Now(),
+			Size:            blob.Size,
+			Checksum:        blob.Checksum,
+		}))
+	}
+	validation := func(suite string, success bool) string {
+		unsuccessful := 0
+		if !success {
+			unsuccessful = 1
+		}
+		return fmt.Sprintf(`{"success": %t, "statistics": {"evaluated_expectations": 2, "successful_expectations": %d, "unsuccessful_expectations": %d, "success_percent": 50},
+			"meta": {"expectation_suite_name": %q, "run_id": {"run_name": "run", "run_time": "2024-03-01T00:00:00"}}}`, success, 2-unsuccessful, unsuccessful, suite)
+	}
+
+	writeEntry("data/users.parquet", "data")
+	writeEntry("_lakefs_quality/expectations/users.json", `{"expectation_suite_name": "users", "expectations": []}`)
+	writeEntry("_lakefs_quality/validations/users/run/1.json", validation("users", true))
+	first, err := deps.catalog.Commit(ctx, repo, "main", "passing", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	writeEntry("_lakefs_quality/validations/users/run/2.json", validation("users", false))
+	second, err := deps.catalog.Commit(ctx, repo, "main", "failing", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	t.Run("passing", func(t *testing.T) {
+		resp, err := clt.ListCommitQualityWithResponse(ctx, repo, first.Reference, &apigen.ListCommitQualityParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Equal(t, first.Reference, resp.JSON200.CommitId)
+		require.True(t, swag.BoolValue(resp.JSON200.Success))
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, "users", swag.StringValue(resp.JSON200.Results[0].Suite))
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		resp, err := clt.ListCommitQualityWithResponse(ctx, repo, second.Reference, &apigen.ListCommitQualityParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.False(t, swag.BoolValue(resp.JSON200.Success))
+		require.Len(t, resp.JSON200.Results, 2)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		amount := apigen.PaginationAmount(1)
+		resp, err := clt.ListCommitQualityWithResponse(ctx, repo, "main", &apigen.ListCommitQualityParams{Amount: &amount})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		require.Len(t, resp.JSON200.Results, 1)
+		require.True(t, resp.JSON200.Pagination.HasMore)
+		after := apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset)
+		resp, err = clt.ListCommitQualityWithResponse(ctx, repo, "main", &apigen.ListCommitQualityParams{Amount: &amount, After: &after})
+		testutil.Must(t, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, "_lakefs_quality/validations/users/run/2.json", resp.JSON200.Results[0].Path)
+		require.False(t, resp.JSON200.Pagination.HasMore)
+	})
+
+	t.Run("uncommitted branch", func(t *testing.T) {
+		resp, err := clt.ListCommitQualityWithResponse(ctx, repo, "main$", &apigen.ListCommitQualityParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
+
+func TestController_DiffRefsSummary(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, "ns1"), "main", false)
+	testutil.Must(t, err)
+	createEntry := func(branch, path string, size int64) {
+		t.Helper()
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, branch, catalog.DBEntry{
+			Path:            path,
+			PhysicalAddress: "address_" + path,
+			CreationDate:    time.Now(),
+			Size:            size,
+			Checksum:        fmt.Sprintf("checksum_%s_%d", path, size),
+		}))
+	}
+	createEntry("main", "tables/users/1.parquet", 10)
+	createEntry("main", "tables/orders/1.parquet", 20)
+	createEntry("main", "README.md", 5)
+	_, err = deps.catalog.Commit(ctx, repo, "main", "base", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "dev", "main")
+	testutil.Must(t, err)
+	createEntry("dev", "tables/users/2.parquet", 100)
+	createEntry("dev", "tables/users/1.parquet", 30)
+	createEntry("dev", "logs/run.LOG", 7)
+	testutil.Must(t, deps.catalog.DeleteEntry(ctx, repo, "dev", "README.md"))
+	_, err = deps.catalog.Commit(ctx, repo, "dev", "changes", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	t.Run("summary", func(t *testing.T) {
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "main", "dev", &apigen.DiffRefsSummaryParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Equal(t, apigen.DiffSummaryStats{
+			AddedCount:   2,
+			AddedBytes:   107,
+			RemovedCount: 1,
+			RemovedBytes: 5,
+			ChangedCount: 1,
+			ChangedBytes: 30,
+		}, resp.JSON200.Total)
+		require.False(t, resp.JSON200.Truncated)
+		require.Equal(t, []apigen.DiffSummaryGroup{
+			{Prefix: "", Extension: ".md", Stats: apigen.DiffSummaryStats{RemovedCount: 1, RemovedBytes: 5}},
+			{Prefix: "logs/", Extension: ".log", Stats: apigen.DiffSummaryStats{AddedCount: 1, AddedBytes: 7}},
+			{Prefix: "tables/", Extension: ".parquet", Stats: apigen.DiffSummaryStats{AddedCount: 1, AddedBytes: 100, ChangedCount: 1, ChangedBytes: 30}},
+		}, resp.JSON200.Groups)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		prefix := apigen.PaginationPrefix("tables/")
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "main", "dev", &apigen.DiffRefsSummaryParams{Prefix: &prefix})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Equal(t, []apigen.DiffSummaryGroup{
+			{Prefix: "users/", Extension: ".parquet", Stats: apigen.DiffSummaryStats{AddedCount: 1, AddedBytes: 100, ChangedCount: 1, ChangedBytes: 30}},
+		}, resp.JSON200.Groups)
+	})
+
+	t.Run("uncommitted", func(t *testing.T) {
+		createEntry("dev", "tables/orders/2.parquet", 1)
+		twoDot := apigen.DiffRefsSummaryParamsTypeTwoDot
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "dev", "dev$", &apigen.DiffRefsSummaryParams{Type: &twoDot})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), string(resp.Body))
+		require.Equal(t, apigen.DiffSummaryStats{AddedCount: 1, AddedBytes: 1}, resp.JSON200.Total)
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		resp, err := clt.DiffRefsSummaryWithResponse(ctx, repo, "main", "no-such-branch", &apigen.DiffRefsSummaryParams{})
+		testutil.Must(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode())
 	})
 }
 
@@ -3843,6 +4556,130 @@ func TestController_MergeBranchWithNoChanges(t *testing.T) {
 	verifyResponseOK(t, mergeWithForceFlagResp, err)
 }
 
+func TestController_MergeAsync(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
+	testutil.Must(t, err)
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+	err = deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar2", PhysicalAddress: "bar2addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum2"})
+	testutil.Must(t, err)
+	_, err = deps.catalog.Commit(ctx, repo, "main", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	t.Run("fast_merge", func(t *testing.T) {
+		// a merge completing within the async threshold returns its result
+		_, err := deps.catalog.CreateBranch(ctx, repo, "fast", "main")
+		testutil.Must(t, err)
+		resp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "fast", apigen.MergeIntoBranchJSONRequestBody{
+			AllowAsync: swag.Bool(true),
+		})
+		verifyResponseOK(t, resp, err)
+		require.NotNil(t, resp.JSON200)
+		require.NotEmpty(t, resp.JSON200.Reference)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		taskID, done, err := deps.catalog.MergeSubmit(ctx, repo, "main", "branch1", DefaultUserID, "", nil, "")
+		testutil.Must(t, err)
+		result := <-done
+		testutil.Must(t, result.Err)
+
+		var status *apigen.MergeStatus
+		require.Eventually(t, func() bool {
+			resp, err := clt.MergeIntoBranchStatusWithResponse(ctx, repo, "main", &apigen.MergeIntoBranchStatusParams{TaskId: taskID})
+			verifyResponseOK(t, resp, err)
+			status = resp.JSON200
+			return status.Done
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Nil(t, status.Error)
+		require.Equal(t, result.Reference, swag.StringValue(status.Reference))
+		require.Positive(t, status.RangesTotal)
+		require.Equal(t, status.RangesTotal, status.RangesProcessed)
+
+		// the task is not found through another destination branch
+		resp, err := clt.MergeIntoBranchStatusWithResponse(ctx, repo, "branch1", &apigen.MergeIntoBranchStatusParams{TaskId: taskID})
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON404)
+	})
+
+	t.Run("dirty_branch", func(t *testing.T) {
+		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "foo/bar3", PhysicalAddress: "bar3addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum3"})
+		testutil.Must(t, err)
+		resp, err := clt.MergeIntoBranchWithResponse(ctx, repo, "branch1", "main", apigen.MergeIntoBranchJSONRequestBody{
+			AllowAsync: swag.Bool(true),
+		})
+		testutil.MustDo(t, "perform merge into dirty branch", err)
+		if resp.JSON400 == nil || resp.JSON400.Message != graveler.ErrDirtyBranch.Error() {
+			t.Errorf("Merge dirty branch should fail with ErrDirtyBranch, got %+v", resp)
+		}
+	})
+}
+
+func TestController_Operations(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	_, err = deps.catalog.CreateBranch(ctx, repo, "branch1", "main")
+	testutil.Must(t, err)
+	err = deps.catalog.CreateEntry(ctx, repo, "branch1", catalog.DBEntry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"})
+	testutil.Must(t, err)
+	_, err = deps.catalog.Commit(ctx, repo, "branch1", "some message", DefaultUserID, nil, nil, nil, false)
+	testutil.Must(t, err)
+
+	taskID, done, err := deps.catalog.MergeSubmit(ctx, repo, "main", "branch1", DefaultUserID, "", nil, "")
+	testutil.Must(t, err)
+	result := <-done
+	testutil.Must(t, result.Err)
+
+	t.Run("get", func(t *testing.T) {
+		var op *apigen.Operation
+		require.Eventually(t, func() bool {
+			resp, err := clt.GetOperationWithResponse(ctx, repo, taskID)
+			verifyResponseOK(t, resp, err)
+			op = resp.JSON200
+			return op.Status != apigen.OperationStatusRunning
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Equal(t, apigen.OperationStatusCompleted, op.Status)
+		require.Equal(t, catalog.OperationKindMerge, op.Kind)
+		require.Equal(t, DefaultUserID, swag.StringValue(op.CreatedBy))
+		require.Positive(t, op.Progress)
+		require.Equal(t, op.Progress, swag.Int64Value(op.ProgressTotal))
+		require.NotNil(t, op.Logs)
+		require.NotEmpty(t, *op.Logs)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListOperationsWithResponse(ctx, repo, &apigen.ListOperationsParams{})
+		verifyResponseOK(t, resp, err)
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, taskID, resp.JSON200.Results[0].Id)
+		require.Nil(t, resp.JSON200.Results[0].Logs)
+	})
+
+	t.Run("cancel_completed", func(t *testing.T) {
+		resp, err := clt.CancelOperationWithResponse(ctx, repo, taskID)
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON409)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		resp, err := clt.GetOperationWithResponse(ctx, repo, "no-such-operation")
+		testutil.Must(t, err)
+		require.NotNil(t, resp.JSON404)
+	})
+}
+
 func TestController_CreateTag(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -4564,7 +5401,7 @@ func TestController_PrepareGarbageCollectionCommitted(t *testing.T) {
 		repo := testUniqueRepoName()
 		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", true)
 		testutil.Must(t, err)
-		resp, err := clt.PrepareGarbageCollectionCommitsWithResponse(ctx, repo)
+		resp, err := clt.PrepareGarbageCollectionCommitsWithResponse(ctx, repo, &apigen.PrepareGarbageCollectionCommitsParams{})
 		if err != nil {
 			t.Fatalf("PrepareGarbageCollectionCommits failed: %s", err)
 		}
@@ -4572,6 +5409,21 @@ func TestController_PrepareGarbageCollectionCommitted(t *testing.T) {
 			t.Fatalf("PrepareGarbageCollectionCommits expected 403 code, got %d instead", resp.StatusCode())
 		}
 	})
+
+	t.Run("retention_time_not_allowed", func(t *testing.T) {
+		repo := testUniqueRepoName()
+		_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+		testutil.Must(t, err)
+		resp, err := clt.PrepareGarbageCollectionCommitsWithResponse(ctx, repo, &apigen.PrepareGarbageCollectionCommitsParams{
+			RetentionTime: apiutil.Ptr(time.Now().AddDate(0, 0, 30)),
+		})
+		if err != nil {
+			t.Fatalf("PrepareGarbageCollectionCommits failed: %s", err)
+		}
+		if resp.StatusCode() != http.StatusBadRequest {
+			t.Fatalf("PrepareGarbageCollectionCommits expected 400 code, got %d instead", resp.StatusCode())
+		}
+	})
 }
 
 func TestController_ClientDisconnect(t *testing.T) {
@@ -5258,6 +6110,95 @@ func TestController_GarbageCollectionRules(t *testing.T) {
 	})
 }
 
+func TestController_NotificationSubscriptions(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	createResp, err := clt.CreateNotificationSubscriptionWithResponse(ctx, apigen.CreateNotificationSubscriptionJSONRequestBody{
+		Repository: repo,
+		Events:     apigen.NotificationEvents{"branch_created"},
+		Channel:    "slack",
+		Target:     "https://hooks.slack.com/services/T000/B000/XXXX",
+	})
+	testutil.MustDo(t, "create subscription", err)
+	if createResp.JSON201 == nil {
+		t.Fatalf("Expected 201 response, got %s", createResp.Status())
+	}
+	subscriptionID := createResp.JSON201.Id
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.ListNotificationSubscriptionsWithResponse(ctx)
+		testutil.MustDo(t, "list subscriptions", err)
+		if resp.JSON200 == nil {
+			t.Fatalf("Expected 200 response, got %s", resp.Status())
+		}
+		if len(resp.JSON200.Results) != 1 || resp.JSON200.Results[0].Id != subscriptionID {
+			t.Fatalf("Subscriptions %+v, expected only %s", resp.JSON200.Results, subscriptionID)
+		}
+	})
+
+	t.Run("branch_created", func(t *testing.T) {
+		resp, err := clt.CreateBranchWithResponse(ctx, repo, apigen.CreateBranchJSONRequestBody{Name: "feature", Source: "main"})
+		testutil.MustDo(t, "create branch", err)
+		if resp.StatusCode() != http.StatusCreated {
+			t.Fatalf("Expected 201 response, got %s", resp.Status())
+		}
+		started := time.Now()
+		for time.Since(started) < 5*time.Second {
+			for _, n := range deps.notified.Notifications() {
+				if n.Event == notifications.EventBranchCreated && n.Repository == repo && n.Branch == "feature" {
+					return
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatal("Expected branch created notification")
+	})
+
+	t.Run("target_not_allowed", func(t *testing.T) {
+		resp, err := clt.CreateNotificationSubscriptionWithResponse(ctx, apigen.CreateNotificationSubscriptionJSONRequestBody{
+			Repository: repo,
+			Events:     apigen.NotificationEvents{"branch_created"},
+			Channel:    "slack",
+			Target:     "https://example.com/webhook",
+		})
+		testutil.MustDo(t, "create subscription", err)
+		if resp.JSON400 == nil {
+			t.Fatalf("Expected 400 (bad request) response, got %s", resp.Status())
+		}
+	})
+
+	t.Run("email_not_configured", func(t *testing.T) {
+		resp, err := clt.CreateNotificationSubscriptionWithResponse(ctx, apigen.CreateNotificationSubscriptionJSONRequestBody{
+			Repository: repo,
+			Events:     apigen.NotificationEvents{"tag_created"},
+			Channel:    "email",
+			Target:     "user@example.com",
+		})
+		testutil.MustDo(t, "create subscription", err)
+		if resp.JSON400 == nil {
+			t.Fatalf("Expected 400 (bad request) response, got %s", resp.Status())
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp, err := clt.DeleteNotificationSubscriptionWithResponse(ctx, subscriptionID)
+		testutil.MustDo(t, "delete subscription", err)
+		if resp.StatusCode() != http.StatusNoContent {
+			t.Fatalf("Expected 204 response, got %s", resp.Status())
+		}
+		resp, err = clt.DeleteNotificationSubscriptionWithResponse(ctx, subscriptionID)
+		testutil.MustDo(t, "delete subscription", err)
+		if resp.JSON404 == nil {
+			t.Fatalf("Expected 404 (not found) response, got %s", resp.Status())
+		}
+	})
+}
+
 func TestController_DumpRestoreRepository(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -5386,6 +6327,89 @@ func TestController_DumpRestoreRepository(t *testing.T) {
 	})
 }
 
+func TestController_TagObjects(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	paths := []string{"data/a.parquet", "data/nested/b.parquet", "data/c.csv", "other/d.parquet"}
+	for _, p := range paths {
+		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, p), CreationDate: time.Now(), Size: 1, Checksum: "cksum"})
+		testutil.MustDo(t, "create entry "+p, err)
+	}
+	_, err = deps.catalog.Commit(ctx, repo, "main", "add objects", "tester", nil, nil, nil, false)
+	testutil.MustDo(t, "commit", err)
+
+	t.Run("tag", func(t *testing.T) {
+		resp, err := clt.TagObjectsSubmitWithResponse(ctx, repo, "main", apigen.TagObjectsSubmitJSONRequestBody{
+			Pattern:  "data/**.parquet",
+			Metadata: apigen.ObjectUserMetadata{AdditionalProperties: map[string]string{"classification": "pii"}},
+		})
+		testutil.MustDo(t, "tag objects submit", err)
+		if resp.JSON202 == nil {
+			t.Fatalf("Expected 202 response, got %s", resp.Status())
+		}
+
+		var status *apigen.ObjectsTaggingStatus
+		started := time.Now()
+		for status == nil && time.Since(started) < 30*time.Second {
+			statusResp, err := clt.TagObjectsStatusWithResponse(ctx, repo, "main", &apigen.TagObjectsStatusParams{TaskId: resp.JSON202.Id})
+			testutil.MustDo(t, "tag objects status", err)
+			if statusResp.JSON200 == nil {
+				t.Fatalf("Expected 200 response, got %s", statusResp.Status())
+			}
+			if statusResp.JSON200.Done {
+				status = statusResp.JSON200
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if status == nil {
+			t.Fatal("Expected tagging to complete (timed-out)")
+		}
+		if status.Error != nil {
+			t.Fatalf("Failed to tag objects: %s", *status.Error)
+		}
+		if status.TaggedCount != 2 {
+			t.Errorf("Tagged count %d, expected 2", status.TaggedCount)
+		}
+		if status.CommitId == nil {
+			t.Fatal("Expected tagging commit")
+		}
+
+		for _, p := range paths {
+			entry, err := deps.catalog.GetEntry(ctx, repo, *status.CommitId, p, catalog.GetEntryParams{})
+			testutil.MustDo(t, "get entry "+p, err)
+			tagged := entry.Metadata["classification"] == "pii"
+			expected := p == "data/a.parquet" || p == "data/nested/b.parquet"
+			if tagged != expected {
+				t.Errorf("Entry %s tagged=%t, expected %t", p, tagged, expected)
+			}
+		}
+	})
+
+	t.Run("lakefs_metadata_key", func(t *testing.T) {
+		resp, err := clt.TagObjectsSubmitWithResponse(ctx, repo, "main", apigen.TagObjectsSubmitJSONRequestBody{
+			Pattern:  "**",
+			Metadata: apigen.ObjectUserMetadata{AdditionalProperties: map[string]string{apiutil.LakeFSMetadataPrefix + "key": "value"}},
+		})
+		testutil.MustDo(t, "tag objects submit", err)
+		if resp.JSON400 == nil {
+			t.Fatalf("Expected 400 (bad request) response, got %s", resp.Status())
+		}
+	})
+
+	t.Run("status_invalid_id", func(t *testing.T) {
+		resp, err := clt.TagObjectsStatusWithResponse(ctx, repo, "main", &apigen.TagObjectsStatusParams{TaskId: "invalid"})
+		testutil.MustDo(t, "tag objects status", err)
+		if resp.JSON404 == nil {
+			t.Fatalf("Expected 404 (not found) response, got %s", resp.Status())
+		}
+	})
+}
+
 func TestController_CreateCommitRecord(t *testing.T) {
 	clt, deps := setupClientWithAdmin(t)
 	ctx := context.Background()
@@ -5499,3 +6523,245 @@ func pollRestoreStatus(t *testing.T, clt apigen.ClientWithResponsesInterface, re
 	}
 	return nil
 }
+
+func TestController_CreateManifest(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	storageNamespace := onBlock(deps, repo)
+	_, err := deps.catalog.CreateRepository(ctx, repo, storageNamespace, "main", false)
+	testutil.Must(t, err)
+	for _, p := range []string{"tables/t/part=1/a", "tables/t/part=1/b", "tables/t/part=2/c", "other/d"} {
+		err := deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, p), CreationDate: time.Now(), Size: 1, Checksum: "cksum"})
+		testutil.MustDo(t, "create entry "+p, err)
+	}
+	commit, err := deps.catalog.Commit(ctx, repo, "main", "add objects", "tester", nil, nil, nil, false)
+	testutil.MustDo(t, "commit", err)
+	// uncommitted objects are not part of the pinned snapshot
+	err = deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "tables/t/part=2/uncommitted", PhysicalAddress: onBlock(deps, "uncommitted"), CreationDate: time.Now(), Size: 1, Checksum: "cksum"})
+	testutil.MustDo(t, "create uncommitted entry", err)
+
+	t.Run("symlink", func(t *testing.T) {
+		resp, err := clt.CreateManifestWithResponse(ctx, repo, "main", apigen.CreateManifestJSONRequestBody{
+			Prefix: swag.String("tables/t/"),
+		})
+		testutil.MustDo(t, "create manifest", err)
+		require.NotNil(t, resp.JSON201, "expected created, got %s", resp.Status())
+		require.Equal(t, commit.Reference, resp.JSON201.CommitId)
+		require.Equal(t, "symlink", resp.JSON201.Format)
+		require.Equal(t, 3, resp.JSON201.Objects)
+
+		reader, err := deps.blocks.Get(ctx, block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeFull,
+			Identifier:       resp.JSON201.Location + "/part=1/symlink.txt",
+		})
+		testutil.MustDo(t, "get symlink file", err)
+		defer func() { _ = reader.Close() }()
+		data, err := io.ReadAll(reader)
+		testutil.MustDo(t, "read symlink file", err)
+		require.Equal(t, []string{onBlock(deps, "tables/t/part=1/a"), onBlock(deps, "tables/t/part=1/b")}, strings.Fields(string(data)))
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		resp, err := clt.CreateManifestWithResponse(ctx, repo, commit.Reference, apigen.CreateManifestJSONRequestBody{
+			Format: swag.String("parquet"),
+		})
+		testutil.MustDo(t, "create manifest", err)
+		require.NotNil(t, resp.JSON201, "expected created, got %s", resp.Status())
+		require.Equal(t, 4, resp.JSON201.Objects)
+		_, err = deps.blocks.GetProperties(ctx, block.ObjectPointer{
+			StorageNamespace: storageNamespace,
+			IdentifierType:   block.IdentifierTypeFull,
+			Identifier:       resp.JSON201.Location + "/manifest.parquet",
+		})
+		testutil.MustDo(t, "manifest file properties", err)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		resp, err := clt.CreateManifestWithResponse(ctx, repo, "main", apigen.CreateManifestJSONRequestBody{
+			Format: swag.String("csv"),
+		})
+		testutil.MustDo(t, "create manifest", err)
+		require.NotNil(t, resp.JSON400, "expected bad request, got %s", resp.Status())
+	})
+}
+
+func TestController_ResolveAddresses(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "data/a", PhysicalAddress: onBlock(deps, "a"), CreationDate: time.Now(), Size: 10, Checksum: "cksum-a"}))
+	testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: "data/b", PhysicalAddress: onBlock(deps, "b"), CreationDate: time.Now(), Size: 20, Checksum: "cksum-b"}))
+
+	t.Run("resolve", func(t *testing.T) {
+		resp, err := clt.ResolveAddressesWithResponse(ctx, repo, "main", apigen.ResolveAddressesJSONRequestBody{
+			Paths: []string{"data/b", "data/missing", "data/a"},
+		})
+		testutil.MustDo(t, "resolve addresses", err)
+		require.NotNil(t, resp.JSON200, "expected OK, got %s", resp.Status())
+		results := resp.JSON200.Results
+		require.Len(t, results, 3)
+		require.Equal(t, "data/b", results[0].Path)
+		require.Equal(t, http.StatusOK, results[0].StatusCode)
+		require.Equal(t, onBlock(deps, "b"), swag.StringValue(results[0].PhysicalAddress))
+		require.Equal(t, int64(20), swag.Int64Value(results[0].SizeBytes))
+		require.Equal(t, "cksum-b", swag.StringValue(results[0].Checksum))
+		require.Equal(t, http.StatusNotFound, results[1].StatusCode)
+		require.Nil(t, results[1].PhysicalAddress)
+		require.Equal(t, http.StatusOK, results[2].StatusCode)
+		require.Equal(t, onBlock(deps, "a"), swag.StringValue(results[2].PhysicalAddress))
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		resp, err := clt.ResolveAddressesWithResponse(ctx, repo, "no-such-branch", apigen.ResolveAddressesJSONRequestBody{
+			Paths: []string{"data/a"},
+		})
+		testutil.MustDo(t, "resolve addresses", err)
+		require.NotNil(t, resp.JSON404, "expected not found, got %s", resp.Status())
+	})
+}
+
+func TestController_ListObjectAccess(t *testing.T) {
+	viper.Set("object_access.enabled", true)
+	t.Cleanup(func() { viper.Set("object_access.enabled", false) })
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+	for _, p := range []string{"data/a", "data/b", "other/c"} {
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", catalog.DBEntry{Path: p, PhysicalAddress: onBlock(deps, p), CreationDate: time.Now(), Size: 1, Checksum: "cksum"}))
+	}
+	// resolving addresses reads the objects
+	for _, paths := range [][]string{{"data/a", "data/b", "other/c"}, {"data/a"}} {
+		resp, err := clt.ResolveAddressesWithResponse(ctx, repo, "main", apigen.ResolveAddressesJSONRequestBody{Paths: paths})
+		testutil.MustDo(t, "resolve addresses", err)
+		require.NotNil(t, resp.JSON200, "expected OK, got %s", resp.Status())
+	}
+	testutil.MustDo(t, "flush object access", deps.catalog.FlushObjectAccess(ctx))
+
+	t.Run("prefix", func(t *testing.T) {
+		resp, err := clt.ListObjectAccessWithResponse(ctx, repo, &apigen.ListObjectAccessParams{
+			Prefix: apiutil.Ptr(apigen.PaginationPrefix("data/")),
+		})
+		testutil.MustDo(t, "list object access", err)
+		require.NotNil(t, resp.JSON200, "expected OK, got %s", resp.Status())
+		results := resp.JSON200.Results
+		require.Len(t, results, 2)
+		require.Equal(t, "data/a", results[0].Path)
+		require.Equal(t, int64(2), results[0].Count)
+		require.Len(t, results[0].Principals, 1)
+		require.Equal(t, int64(2), results[0].Principals[0].Count)
+		require.NotZero(t, results[0].LastAccessed)
+		require.Equal(t, "data/b", results[1].Path)
+		require.Equal(t, int64(1), results[1].Count)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		resp, err := clt.ListObjectAccessWithResponse(ctx, repo, &apigen.ListObjectAccessParams{
+			Amount: apiutil.Ptr(apigen.PaginationAmount(2)),
+		})
+		testutil.MustDo(t, "list object access", err)
+		require.NotNil(t, resp.JSON200, "expected OK, got %s", resp.Status())
+		require.Len(t, resp.JSON200.Results, 2)
+		require.True(t, resp.JSON200.Pagination.HasMore)
+
+		resp, err = clt.ListObjectAccessWithResponse(ctx, repo, &apigen.ListObjectAccessParams{
+			After: apiutil.Ptr(apigen.PaginationAfter(resp.JSON200.Pagination.NextOffset)),
+		})
+		testutil.MustDo(t, "list object access", err)
+		require.NotNil(t, resp.JSON200, "expected OK, got %s", resp.Status())
+		require.Len(t, resp.JSON200.Results, 1)
+		require.Equal(t, "other/c", resp.JSON200.Results[0].Path)
+		require.False(t, resp.JSON200.Pagination.HasMore)
+	})
+}
+
+func TestController_GetColdDataReport(t *testing.T) {
+	viper.Set("object_access.enabled", true)
+	t.Cleanup(func() { viper.Set("object_access.enabled", false) })
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	t.Run("not reported", func(t *testing.T) {
+		resp, err := clt.GetColdDataReportWithResponse(ctx, repo)
+		testutil.MustDo(t, "get cold data report", err)
+		require.NotNil(t, resp.JSON404, "expected not found, got %s", resp.Status())
+	})
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	entries := []catalog.DBEntry{
+		{Path: "archive/2020/a", CreationDate: old, Size: 10},
+		{Path: "archive/2021/b", CreationDate: old, Size: 20},
+		{Path: "read/c", CreationDate: old, Size: 30},
+		{Path: "fresh/d", CreationDate: time.Now(), Size: 40},
+	}
+	for _, e := range entries {
+		e.PhysicalAddress = onBlock(deps, e.Path)
+		e.Checksum = "cksum"
+		testutil.Must(t, deps.catalog.CreateEntry(ctx, repo, "main", e))
+	}
+	_, err = deps.catalog.Commit(ctx, repo, "main", "data", "some_user", nil, nil, nil, false)
+	testutil.Must(t, err)
+	// an old object that is still read is not cold
+	resp, err := clt.ResolveAddressesWithResponse(ctx, repo, "main", apigen.ResolveAddressesJSONRequestBody{Paths: []string{"read/c"}})
+	testutil.MustDo(t, "resolve addresses", err)
+	require.NotNil(t, resp.JSON200, "expected OK, got %s", resp.Status())
+	testutil.MustDo(t, "flush object access", deps.catalog.FlushObjectAccess(ctx))
+
+	_, err = deps.catalog.CreateColdDataReport(ctx, repo, catalog.ColdDataParams{
+		InactiveFor: 30 * 24 * time.Hour,
+		PrefixDepth: 1,
+		Location:    "_lakefs/cold_data",
+	})
+	testutil.MustDo(t, "create cold data report", err)
+
+	reportResp, err := clt.GetColdDataReportWithResponse(ctx, repo)
+	testutil.MustDo(t, "get cold data report", err)
+	require.NotNil(t, reportResp.JSON200, "expected OK, got %s", reportResp.Status())
+	report := reportResp.JSON200
+	require.Equal(t, int64(4), report.TotalObjects)
+	require.Equal(t, int64(100), report.TotalBytes)
+	require.Equal(t, int64(2), report.ColdObjects)
+	require.Equal(t, int64(30), report.ColdBytes)
+	require.Len(t, report.Prefixes, 1)
+	require.Equal(t, "archive/", report.Prefixes[0].Prefix)
+	require.Equal(t, int64(30), report.Prefixes[0].SizeBytes)
+	require.NotEmpty(t, report.PhysicalAddress)
+}
+
+func TestController_CollectOrphanMetadata(t *testing.T) {
+	clt, deps := setupClientWithAdmin(t)
+	ctx := context.Background()
+
+	repo := testUniqueRepoName()
+	_, err := deps.catalog.CreateRepository(ctx, repo, onBlock(deps, repo), "main", false)
+	testutil.Must(t, err)
+
+	t.Run("invalid min age", func(t *testing.T) {
+		resp, err := clt.CollectOrphanMetadataWithResponse(ctx, repo, apigen.CollectOrphanMetadataJSONRequestBody{
+			DryRun:        swag.Bool(true),
+			MinAgeSeconds: swag.Int64(-1),
+		})
+		testutil.MustDo(t, "collect orphan metadata", err)
+		require.NotNil(t, resp.JSON400, "expected bad request, got %s", resp.Status())
+	})
+
+	t.Run("repository not found", func(t *testing.T) {
+		resp, err := clt.CollectOrphanMetadataWithResponse(ctx, "no-such-repo", apigen.CollectOrphanMetadataJSONRequestBody{
+			DryRun: swag.Bool(true),
+		})
+		testutil.MustDo(t, "collect orphan metadata", err)
+		require.NotNil(t, resp.JSON404, "expected not found, got %s", resp.Status())
+	})
+}
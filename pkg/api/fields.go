@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+// compactOmittedFields are the result fields omitted from listings in compact mode
+var compactOmittedFields = []string{"checksum", "metadata"}
+
+// fieldSelection describes the fields of each listing result returned to the client
+type fieldSelection struct {
+	// fields to return, all fields if empty
+	fields  []string
+	compact bool
+	// key is the field identifying each result, it is always returned
+	key string
+}
+
+// newFieldSelection validates the fields requested from a listing of results of type resultType
+func newFieldSelection(resultType reflect.Type, key string, fields *apigen.FieldSelection, compact *apigen.CompactListing) (*fieldSelection, error) {
+	s := &fieldSelection{key: key}
+	if compact != nil {
+		s.compact = bool(*compact)
+	}
+	if fields == nil {
+		return s, nil
+	}
+	known := jsonFieldNames(resultType)
+	for _, f := range *fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if _, ok := known[f]; !ok {
+			return nil, fmt.Errorf("unknown field %s: %w", f, ErrInvalidFieldSelection)
+		}
+		s.fields = append(s.fields, f)
+	}
+	return s, nil
+}
+
+func jsonFieldNames(t reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}
+
+func (s *fieldSelection) selectsAll() bool {
+	return len(s.fields) == 0 && !s.compact
+}
+
+// keep reports whether field of each result is returned
+func (s *fieldSelection) keep(field string) bool {
+	if field == s.key {
+		return true
+	}
+	if s.compact {
+		for _, f := range compactOmittedFields {
+			if f == field {
+				return false
+			}
+		}
+	}
+	if len(s.fields) == 0 {
+		return true
+	}
+	for _, f := range s.fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns the listing response with only the selected fields of its results
+func (s *fieldSelection) apply(response interface{}) (interface{}, error) {
+	if s.selectsAll() {
+		return response, nil
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var listing map[string]json.RawMessage
+	if err := json.Unmarshal(data, &listing); err != nil {
+		return nil, err
+	}
+	var results []map[string]json.RawMessage
+	if err := json.Unmarshal(listing["results"], &results); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		for field := range result {
+			if !s.keep(field) {
+				delete(result, field)
+			}
+		}
+	}
+	listing["results"], err = json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/httputil"
 )
 
 var (
@@ -36,6 +37,8 @@ type APIFields struct {
 	StatusCode int
 	Status     string
 	Message    string
+	// RequestID identifies the failed request in the logs of the server
+	RequestID string
 }
 
 // CallFailedError is an error performing the HTTP request itself formatted
@@ -86,6 +89,9 @@ func (e UserVisibleAPIError) Error() string {
 	if message != "" {
 		message = ": " + message
 	}
+	if e.RequestID != "" {
+		message += " (request ID: " + e.RequestID + ")"
+	}
 	return fmt.Sprintf("[%s]%s", e.Status, message)
 }
 
@@ -146,6 +152,7 @@ func ResponseAsError(response interface{}) error {
 			StatusCode: statusCode,
 			Status:     statusText,
 			Message:    message,
+			RequestID:  httpResponse.Header.Get(httputil.RequestIDHeaderName),
 		},
 	}
 }
@@ -173,6 +180,7 @@ func HTTPResponseAsError(httpResponse *http.Response) error {
 			StatusCode: statusCode,
 			Status:     statusText,
 			Message:    message,
+			RequestID:  httpResponse.Header.Get(httputil.RequestIDHeaderName),
 		},
 	}
 }
@@ -48,6 +48,11 @@ func TestResponseAsError(t *testing.T) {
 			&Body{Response{&http.Response{StatusCode: http.StatusTeapot}}, []byte("{\"message\": \"lemonade\"}")},
 			"[I'm a teapot]: lemonade request failed",
 		},
+		{
+			"request ID",
+			&Response{&http.Response{StatusCode: http.StatusTeapot, Header: http.Header{"X-Request-Id": []string{"f00d"}}}},
+			expectedClean418 + " (request ID: f00d)",
+		},
 	}
 
 	for _, tt := range cases {
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const mib = 1024 * 1024
+
+// ErrUnknownChecksum is returned when a checksum is neither an MD5 nor a multipart ETag, and content cannot be
+// verified against it
+var ErrUnknownChecksum = errors.New("unknown checksum format")
+
+// CommonPartSizes are part sizes used by popular clients (lakectl, AWS CLI and SDKs, Hadoop S3A, rclone), tried when
+// verifying a multipart ETag without a known part size
+var CommonPartSizes = []int64{5 * mib, 8 * mib, 10 * mib, 15 * mib, 16 * mib, 32 * mib, 50 * mib, 64 * mib, 100 * mib, 128 * mib, 256 * mib, 512 * mib, 1024 * mib}
+
+var (
+	md5Regexp       = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	multipartRegexp = regexp.MustCompile(`^([0-9a-f]{32})-([0-9]+)$`)
+)
+
+// ContentETag returns the ETag of size bytes of r uploaded in parts of partSize: the hex MD5 of the content of a single
+// part upload, or the MD5 of the MD5s of the parts followed by the number of parts for a multipart upload.
+func ContentETag(r io.ReaderAt, size, partSize int64, multipart bool) (string, error) {
+	if !multipart {
+		h := md5.New() //nolint:gosec
+		if _, err := io.Copy(h, io.NewSectionReader(r, 0, size)); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	if partSize <= 0 {
+		return "", fmt.Errorf("%w: part size %d", ErrUnknownChecksum, partSize)
+	}
+	partsHash := md5.New() //nolint:gosec
+	parts := 0
+	for offset := int64(0); offset < size || parts == 0; offset += partSize {
+		h := md5.New() //nolint:gosec
+		if _, err := io.Copy(h, io.NewSectionReader(r, offset, min(partSize, size-offset))); err != nil {
+			return "", err
+		}
+		partsHash.Write(h.Sum(nil))
+		parts++
+	}
+	return hex.EncodeToString(partsHash.Sum(nil)) + "-" + strconv.Itoa(parts), nil
+}
+
+// MultipartPartSizes returns the part sizes that split size bytes into parts parts, from the common part sizes and
+// the size of parts rounded up to a MiB
+func MultipartPartSizes(size int64, parts int) []int64 {
+	if parts <= 0 {
+		return nil
+	}
+	fits := func(partSize int64) bool {
+		return partSize > 0 && (size+partSize-1)/partSize == int64(parts)
+	}
+	var sizes []int64
+	for _, partSize := range CommonPartSizes {
+		if fits(partSize) {
+			sizes = append(sizes, partSize)
+		}
+	}
+	perPart := (size + int64(parts) - 1) / int64(parts)
+	rounded := (perPart + mib - 1) / mib * mib
+	for _, partSize := range []int64{perPart, rounded} {
+		if fits(partSize) && !slices.Contains(sizes, partSize) {
+			sizes = append(sizes, partSize)
+		}
+	}
+	return sizes
+}
+
+// VerifyChecksum checks that size bytes of r match checksum, the ETag of an object. A multipart ETag is verified with
+// partSize when set, or else with the part sizes that could have produced it. It returns the part size that matched
+// a multipart ETag, and ErrUnknownChecksum for checksums that are not ETags.
+func VerifyChecksum(r io.ReaderAt, size int64, checksum string, partSize int64) (bool, int64, error) {
+	checksum = strings.ToLower(strings.Trim(checksum, `"`))
+	if md5Regexp.MatchString(checksum) {
+		etag, err := ContentETag(r, size, 0, false)
+		return etag == checksum, 0, err
+	}
+	m := multipartRegexp.FindStringSubmatch(checksum)
+	if m == nil {
+		return false, 0, fmt.Errorf("%w: %s", ErrUnknownChecksum, checksum)
+	}
+	parts, err := strconv.Atoi(m[2])
+	if err != nil {
+		return false, 0, fmt.Errorf("%w: %s", ErrUnknownChecksum, checksum)
+	}
+	partSizes := []int64{partSize}
+	if partSize <= 0 {
+		partSizes = MultipartPartSizes(size, parts)
+	}
+	for _, ps := range partSizes {
+		etag, err := ContentETag(r, size, ps, true)
+		if err != nil {
+			return false, 0, err
+		}
+		if etag == checksum {
+			return true, ps, nil
+		}
+	}
+	return false, 0, nil
+}
@@ -0,0 +1,66 @@
+package helpers_test
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+// multipartETag returns the ETag of data uploaded in parts of partSize, computed the way S3 does
+func multipartETag(data []byte, partSize int) string {
+	var sums []byte
+	parts := 0
+	for offset := 0; offset < len(data); offset += partSize {
+		end := min(offset+partSize, len(data))
+		sum := md5.Sum(data[offset:end]) //nolint:gosec
+		sums = append(sums, sum[:]...)
+		parts++
+	}
+	sum := md5.Sum(sums) //nolint:gosec
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), parts)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	const mib = 1024 * 1024
+	data := bytes.Repeat([]byte("0123456789abcdef"), 13*mib/16+1234)
+	sum := md5.Sum(data) //nolint:gosec
+	plainETag := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name         string
+		checksum     string
+		partSize     int64
+		wantMatch    bool
+		wantPartSize int64
+		wantErr      error
+	}{
+		{name: "md5", checksum: plainETag, wantMatch: true},
+		{name: "quoted_md5", checksum: `"` + plainETag + `"`, wantMatch: true},
+		{name: "md5_mismatch", checksum: "0123456789abcdef0123456789abcdef"},
+		{name: "multipart_5mib", checksum: multipartETag(data, 5*mib), wantMatch: true, wantPartSize: 5 * mib},
+		{name: "multipart_8mib", checksum: multipartETag(data, 8*mib), wantMatch: true, wantPartSize: 8 * mib},
+		{name: "multipart_odd_size", checksum: multipartETag(data, 7*mib), wantMatch: true, wantPartSize: 7 * mib},
+		{name: "multipart_given_size", checksum: multipartETag(data, 6*mib), partSize: 6 * mib, wantMatch: true, wantPartSize: 6 * mib},
+		{name: "multipart_wrong_size", checksum: multipartETag(data, 5*mib), partSize: 6 * mib},
+		{name: "unknown", checksum: "AAAA/w==", wantErr: helpers.ErrUnknownChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, partSize, err := helpers.VerifyChecksum(bytes.NewReader(data), int64(len(data)), tt.checksum, tt.partSize)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyChecksum() error = %v, expected %v", err, tt.wantErr)
+			}
+			if match != tt.wantMatch {
+				t.Errorf("VerifyChecksum() match = %t, expected %t", match, tt.wantMatch)
+			}
+			if partSize != tt.wantPartSize {
+				t.Errorf("VerifyChecksum() part size = %d, expected %d", partSize, tt.wantPartSize)
+			}
+		})
+	}
+}
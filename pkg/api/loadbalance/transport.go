@@ -0,0 +1,279 @@
+// Package loadbalance balances the requests of lakeFS API clients across several lakeFS instances, failing over from
+// instances that do not respond.
+package loadbalance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultFailureThreshold = 3
+	DefaultOpenDuration     = 30 * time.Second
+
+	// healthCheckPath is the path of the lakeFS health check relative to the API endpoint
+	healthCheckPath = "/healthcheck"
+
+	// drainLimit bounds the size of response bodies read to reuse their connections
+	drainLimit = 4096
+)
+
+var (
+	ErrNoEndpoints     = errors.New("no endpoints")
+	ErrInvalidEndpoint = errors.New("invalid endpoint")
+)
+
+// Config configures balancing requests across endpoints
+type Config struct {
+	// FailureThreshold is the number of consecutive failures of an endpoint that open its circuit, taking it out of
+	// the rotation. DefaultFailureThreshold if not positive.
+	FailureThreshold int
+	// OpenDuration is how long an open circuit stays open before requests try the endpoint again.
+	// DefaultOpenDuration if not positive.
+	OpenDuration time.Duration
+	// HealthCheckInterval is the interval of health checks of endpoints with open circuits, which close their
+	// circuits once they pass. No health checks if not positive.
+	HealthCheckInterval time.Duration
+}
+
+type endpoint struct {
+	url *url.URL
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// available reports whether requests may be sent to the endpoint at now
+func (e *endpoint) available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !now.Before(e.openUntil)
+}
+
+func (e *endpoint) succeeded() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = 0
+	e.openUntil = time.Time{}
+}
+
+// failed records a failure at now, opening the circuit once the failures reach threshold
+func (e *endpoint) failed(now time.Time, threshold int, openDuration time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures++
+	if e.failures >= threshold {
+		e.openUntil = now.Add(openDuration)
+	}
+}
+
+// Transport sends requests to the API endpoint of the first of several lakeFS instances to one of the instances,
+// round-robin. Failing requests fail over to the next instance: requests of any method when the instance cannot be
+// reached or answers 503, and idempotent requests on any transport error or 502, 503 or 504. Instances failing
+// consecutive requests are taken out of the rotation for a while (circuit breaking). Requests to other URLs, such
+// as pre-signed URLs, are sent as they are.
+type Transport struct {
+	base      http.RoundTripper
+	endpoints []*endpoint
+	cfg       Config
+	next      atomic.Uint64
+	now       func() time.Time
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTransport returns a transport balancing requests to the first of endpoints across all of them, sending them
+// with base (http.DefaultTransport if nil). Close it to stop its health checks.
+func NewTransport(endpoints []string, cfg Config, base http.RoundTripper) (*Transport, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = DefaultOpenDuration
+	}
+	t := &Transport{
+		base: base,
+		cfg:  cfg,
+		now:  time.Now,
+		done: make(chan struct{}),
+	}
+	for _, e := range endpoints {
+		u, err := url.Parse(strings.TrimRight(e, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", e, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("endpoint %s: %w", e, ErrInvalidEndpoint)
+		}
+		t.endpoints = append(t.endpoints, &endpoint{url: u})
+	}
+	if cfg.HealthCheckInterval > 0 && len(t.endpoints) > 1 {
+		go t.healthChecks()
+	}
+	return t, nil
+}
+
+// Close stops the health checks of the transport
+func (t *Transport) Close() error {
+	t.closeOnce.Do(func() { close(t.done) })
+	return nil
+}
+
+// relativePath returns the path of u relative to the first endpoint and its escaped form, and false if u is not
+// under it
+func (t *Transport) relativePath(u *url.URL) (string, string, bool) {
+	primary := t.endpoints[0].url
+	if u.Scheme != primary.Scheme || u.Host != primary.Host || !strings.HasPrefix(u.Path, primary.Path) {
+		return "", "", false
+	}
+	return strings.TrimPrefix(u.Path, primary.Path), strings.TrimPrefix(u.EscapedPath(), primary.EscapedPath()), true
+}
+
+// order returns the endpoints to try a request on: the available endpoints starting at the next one in the
+// rotation, then the unavailable ones, so that requests are attempted even when all circuits are open
+func (t *Transport) order() []*endpoint {
+	start := int(t.next.Add(1)-1) % len(t.endpoints)
+	now := t.now()
+	available := make([]*endpoint, 0, len(t.endpoints))
+	var unavailable []*endpoint
+	for i := range t.endpoints {
+		e := t.endpoints[(start+i)%len(t.endpoints)]
+		if e.available(now) {
+			available = append(available, e)
+		} else {
+			unavailable = append(unavailable, e)
+		}
+	}
+	return append(available, unavailable...)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDialError reports whether err failed connecting to the endpoint, before the request was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldFailOver reports whether a request with method that got resp or err may be sent to another endpoint
+func shouldFailOver(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return isDialError(err) || isIdempotent(method)
+	}
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	relativePath, rawRelativePath, ok := t.relativePath(req.URL)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	endpoints := t.order()
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body cannot be sent again to another endpoint
+		endpoints = endpoints[:1]
+	}
+	var (
+		resp *http.Response
+		err  error
+	)
+	for i, e := range endpoints {
+		attempt := req.Clone(req.Context())
+		attempt.URL.Scheme = e.url.Scheme
+		attempt.URL.Host = e.url.Host
+		attempt.URL.Path = e.url.Path + relativePath
+		attempt.URL.RawPath = e.url.EscapedPath() + rawRelativePath
+		attempt.Host = ""
+		if i > 0 && req.GetBody != nil {
+			if attempt.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		resp, err = t.base.RoundTrip(attempt)
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+		if err != nil || shouldFailOver(req.Method, resp, err) {
+			e.failed(t.now(), t.cfg.FailureThreshold, t.cfg.OpenDuration)
+		} else {
+			e.succeeded()
+		}
+		if !shouldFailOver(req.Method, resp, err) {
+			return resp, err
+		}
+		if i == len(endpoints)-1 {
+			break
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, drainLimit))
+			_ = resp.Body.Close()
+			resp = nil
+		}
+	}
+	return resp, err
+}
+
+func (t *Transport) healthChecks() {
+	ticker := time.NewTicker(t.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			now := t.now()
+			for _, e := range t.endpoints {
+				if !e.available(now) && t.healthy(e) {
+					e.succeeded()
+				}
+			}
+		}
+	}
+}
+
+// healthy reports whether the endpoint passes the lakeFS health check
+func (t *Transport) healthy(e *endpoint) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.HealthCheckInterval)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url.String()+healthCheckPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, drainLimit))
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusOK
+}
@@ -0,0 +1,108 @@
+package loadbalance_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/loadbalance"
+)
+
+// countingServer answers with status, counting the requests it receives
+func countingServer(t *testing.T, status *atomic.Int32, count *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTransport(t *testing.T) {
+	var status1, status2, count1, count2 atomic.Int32
+	status1.Store(http.StatusOK)
+	status2.Store(http.StatusOK)
+	server1 := countingServer(t, &status1, &count1)
+	server2 := countingServer(t, &status2, &count2)
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+
+	transport, err := loadbalance.NewTransport([]string{
+		server1.URL + "/api/v1",
+		server2.URL + "/api/v1",
+		stopped.URL + "/api/v1",
+	}, loadbalance.Config{FailureThreshold: 1, OpenDuration: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("NewTransport: %s", err)
+	}
+	defer func() { _ = transport.Close() }()
+	client := &http.Client{Transport: transport}
+	do := func(method string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, server1.URL+"/api/v1/repositories", strings.NewReader("body"))
+		if err != nil {
+			t.Fatalf("NewRequest: %s", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %s", method, err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// requests are balanced across reachable endpoints, and the unreachable one leaves the rotation
+	const requests = 6
+	for i := 0; i < requests; i++ {
+		if code := do(http.MethodGet); code != http.StatusOK {
+			t.Fatalf("GET status %d, expected %d", code, http.StatusOK)
+		}
+	}
+	if count1.Load()+count2.Load() != requests || count1.Load() < 2 || count2.Load() < 2 {
+		t.Errorf("endpoints got %d and %d requests, expected %d balanced", count1.Load(), count2.Load(), requests)
+	}
+
+	// idempotent requests fail over from endpoints answering 502, which leave the rotation
+	status1.Store(http.StatusBadGateway)
+	count1.Store(0)
+	count2.Store(0)
+	if code := do(http.MethodPut); code != http.StatusOK {
+		t.Errorf("PUT status %d, expected %d", code, http.StatusOK)
+	}
+	if code := do(http.MethodPost); code != http.StatusOK {
+		t.Errorf("POST status %d, expected %d", code, http.StatusOK)
+	}
+	if count2.Load() != 2 {
+		t.Errorf("available endpoint got %d requests, expected 2", count2.Load())
+	}
+
+	// requests to other URLs are not balanced
+	req, err := http.NewRequest(http.MethodGet, server1.URL+"/other", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %s", err)
+	}
+	count1.Store(0)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET other: %s", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || count1.Load() != 1 {
+		t.Errorf("GET other status %d with %d requests, expected %d from its server", resp.StatusCode, count1.Load(), http.StatusBadGateway)
+	}
+}
+
+func TestTransport_NoEndpoints(t *testing.T) {
+	if _, err := loadbalance.NewTransport(nil, loadbalance.Config{}, nil); err == nil {
+		t.Error("NewTransport without endpoints succeeded")
+	}
+	if _, err := loadbalance.NewTransport([]string{"localhost:8000"}, loadbalance.Config{}, nil); err == nil {
+		t.Error("NewTransport with endpoint without scheme succeeded")
+	}
+}
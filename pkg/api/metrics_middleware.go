@@ -8,6 +8,7 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/routers/legacy"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/httputil"
 )
 
@@ -20,7 +21,11 @@ func MetricsMiddleware(swagger *openapi3.Swagger) func(http.Handler) http.Handle
 	return func(next http.Handler) http.Handler {
 		// request histogram by operation ID
 		requestHistogramHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			route, _, err := router.FindRoute(r)
+			route, pathParams, err := router.FindRoute(r)
+			if repository := pathParams["repository"]; err == nil && repository != "" {
+				// attribute blockstore requests serving this request to its repository
+				r = r.WithContext(block.WithRepository(r.Context(), repository))
+			}
 			start := time.Now()
 			mrw := httputil.NewMetricResponseWriter(w)
 			next.ServeHTTP(mrw, r)
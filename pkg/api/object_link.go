@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/treeverse/lakefs/pkg/auth"
+)
+
+const (
+	ObjectLinkAudience = "object_link"
+
+	DefaultObjectLinkExpiry = 24 * time.Hour
+	MaxObjectLinkExpiry     = 7 * 24 * time.Hour
+)
+
+var ErrObjectLinkExpired = errors.New("object link expired")
+
+// ObjectLinkClaims identify the object an object link reads. The subject is the user that created the link.
+type ObjectLinkClaims struct {
+	jwt.StandardClaims
+	Repository string `json:"repository"`
+	CommitID   string `json:"commit_id"`
+	Path       string `json:"path"`
+}
+
+// GenerateObjectLinkToken creates a jwt token reading path at commitID of repository, on behalf of userID, until
+// expiresAt. The audience makes the token invalid for login.
+func GenerateObjectLinkToken(secret []byte, userID, repository, commitID, path string, issuedAt, expiresAt time.Time) (string, error) {
+	claims := &ObjectLinkClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Audience:  ObjectLinkAudience,
+			Subject:   userID,
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+		Repository: repository,
+		CommitID:   commitID,
+		Path:       path,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secret)
+}
+
+// VerifyObjectLinkToken returns the claims of an object link token. Fails with ErrObjectLinkExpired if the token is
+// valid but expired.
+func VerifyObjectLinkToken(secret []byte, tokenString string) (*ObjectLinkClaims, error) {
+	claims := &ObjectLinkClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, auth.ErrUnexpectedSigningMethod
+		}
+		return secret, nil
+	})
+	var validationErr *jwt.ValidationError
+	if errors.As(err, &validationErr) && validationErr.Errors == jwt.ValidationErrorExpired {
+		return nil, ErrObjectLinkExpired
+	}
+	if err != nil || !token.Valid || !claims.VerifyAudience(ObjectLinkAudience, true) {
+		return nil, auth.ErrInvalidToken
+	}
+	return claims, nil
+}
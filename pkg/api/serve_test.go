@@ -34,6 +34,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/kv/kvtest"
 	"github.com/treeverse/lakefs/pkg/kv/mem"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/notifications"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
@@ -50,6 +51,26 @@ type dependencies struct {
 	authService auth.Service
 	collector   *memCollector
 	server      *httptest.Server
+	notified    *memSender
+}
+
+// memSender in-memory notifications sender stores the notifications sent
+type memSender struct {
+	Sent []notifications.Notification
+	mu   sync.Mutex
+}
+
+func (m *memSender) Send(_ context.Context, _ string, n notifications.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Sent = append(m.Sent, n)
+	return nil
+}
+
+func (m *memSender) Notifications() []notifications.Notification {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]notifications.Notification(nil), m.Sent...)
 }
 
 // memCollector in-memory collector stores events and metadata sent
@@ -175,7 +196,20 @@ func setupHandler(t testing.TB) (http.Handler, *dependencies) {
 		"",
 	)
 
-	c.SetHooksHandler(actionsService)
+	// wire notifications, delivering through an in-memory sender
+	notified := &memSender{}
+	notificationsService := notifications.NewService(ctx, kvStore, notifications.Config{
+		DeliveryTimeout:       time.Second,
+		SlackAllowedEndpoints: []string{"https://hooks.slack.com/"},
+	})
+	notificationsService.SetSender(notifications.ChannelSlack, notified)
+	c.SetHooksHandler(notifications.NewHooksHandler(actionsService, notificationsService, func(ctx context.Context, repositoryID string) (string, error) {
+		repo, err := c.GetRepository(ctx, repositoryID)
+		if err != nil {
+			return "", err
+		}
+		return repo.DefaultBranch, nil
+	}))
 
 	authenticator := auth.NewBuiltinAuthenticator(authService)
 	kvParams, err := kvparams.NewConfig(cfg)
@@ -184,19 +218,21 @@ func setupHandler(t testing.TB) (http.Handler, *dependencies) {
 
 	t.Cleanup(func() {
 		actionsService.Stop()
+		notificationsService.Stop()
 		_ = c.Close()
 	})
 
 	auditChecker := version.NewDefaultAuditChecker(cfg.Security.AuditCheckURL, "", nil)
 
 	authenticationService := authentication.NewDummyService()
-	handler := api.Serve(cfg, c, authenticator, authService, authenticationService, c.BlockAdapter, meta, migrator, collector, nil, actionsService, auditChecker, logging.ContextUnavailable(), nil, nil, upload.DefaultPathProvider, stats.DefaultUsageReporter)
+	handler := api.Serve(cfg, c, authenticator, authService, authenticationService, c.BlockAdapter, meta, migrator, collector, nil, actionsService, auditChecker, logging.ContextUnavailable(), nil, nil, upload.DefaultPathProvider, stats.DefaultUsageReporter, nil, nil, notificationsService, nil)
 
 	return handler, &dependencies{
 		blocks:      c.BlockAdapter,
 		authService: authService,
 		catalog:     c,
 		collector:   collector,
+		notified:    notified,
 	}
 }
 
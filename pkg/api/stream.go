@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	ndjsonContentType      = "application/x-ndjson"
+	arrowStreamContentType = "application/vnd.apache.arrow.stream"
+)
+
+// streamingContentType returns the streaming content type accepted by the request, or "" if the request
+// accepts JSON responses only
+func streamingContentType(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case ndjsonContentType, arrowStreamContentType:
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// arrowColumn is a column of the Arrow record batches streamed for results of type T
+type arrowColumn[T any] struct {
+	Field  arrow.Field
+	Append func(b array.Builder, v T)
+}
+
+func appendArrowString(b array.Builder, s string) {
+	b.(*array.StringBuilder).Append(s)
+}
+
+func appendArrowOptionalString(b array.Builder, s *string) {
+	if s == nil {
+		b.AppendNull()
+		return
+	}
+	b.(*array.StringBuilder).Append(*s)
+}
+
+func appendArrowOptionalInt64(b array.Builder, i *int64) {
+	if i == nil {
+		b.AppendNull()
+		return
+	}
+	b.(*array.Int64Builder).Append(*i)
+}
+
+var objectStatsArrowColumns = []arrowColumn[apigen.ObjectStats]{
+	{Field: arrow.Field{Name: "path", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		appendArrowString(b, v.Path)
+	}},
+	{Field: arrow.Field{Name: "path_type", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		appendArrowString(b, v.PathType)
+	}},
+	{Field: arrow.Field{Name: "physical_address", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		appendArrowString(b, v.PhysicalAddress)
+	}},
+	{Field: arrow.Field{Name: "checksum", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		appendArrowString(b, v.Checksum)
+	}},
+	{Field: arrow.Field{Name: "size_bytes", Type: arrow.PrimitiveTypes.Int64, Nullable: true}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		appendArrowOptionalInt64(b, v.SizeBytes)
+	}},
+	{Field: arrow.Field{Name: "mtime", Type: arrow.PrimitiveTypes.Int64}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		b.(*array.Int64Builder).Append(v.Mtime)
+	}},
+	{Field: arrow.Field{Name: "content_type", Type: arrow.BinaryTypes.String, Nullable: true}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		appendArrowOptionalString(b, v.ContentType)
+	}},
+	// user metadata is a JSON encoded object
+	{Field: arrow.Field{Name: "metadata", Type: arrow.BinaryTypes.String, Nullable: true}, Append: func(b array.Builder, v apigen.ObjectStats) {
+		if v.Metadata == nil {
+			b.AppendNull()
+			return
+		}
+		data, err := json.Marshal(v.Metadata.AdditionalProperties)
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		appendArrowString(b, string(data))
+	}},
+}
+
+var diffArrowColumns = []arrowColumn[apigen.Diff]{
+	{Field: arrow.Field{Name: "path", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.Diff) {
+		appendArrowString(b, v.Path)
+	}},
+	{Field: arrow.Field{Name: "type", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.Diff) {
+		appendArrowString(b, v.Type)
+	}},
+	{Field: arrow.Field{Name: "path_type", Type: arrow.BinaryTypes.String}, Append: func(b array.Builder, v apigen.Diff) {
+		appendArrowString(b, v.PathType)
+	}},
+	{Field: arrow.Field{Name: "size_bytes", Type: arrow.PrimitiveTypes.Int64, Nullable: true}, Append: func(b array.Builder, v apigen.Diff) {
+		appendArrowOptionalInt64(b, v.SizeBytes)
+	}},
+}
+
+// listingStreamWriter writes pages of listing results to a streaming response
+type listingStreamWriter[T any] interface {
+	WritePage(results []T) error
+	Close() error
+}
+
+type ndjsonStreamWriter[T any] struct {
+	w   http.ResponseWriter
+	enc *json.Encoder
+}
+
+func (s *ndjsonStreamWriter[T]) WritePage(results []T) error {
+	for _, result := range results {
+		if err := s.enc.Encode(result); err != nil {
+			return err
+		}
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (s *ndjsonStreamWriter[T]) Close() error {
+	return nil
+}
+
+// arrowStreamWriter writes each page as a record batch of an Arrow IPC stream
+type arrowStreamWriter[T any] struct {
+	w       http.ResponseWriter
+	columns []arrowColumn[T]
+	builder *array.RecordBuilder
+	writer  *ipc.Writer
+}
+
+func newArrowStreamWriter[T any](w http.ResponseWriter, columns []arrowColumn[T]) *arrowStreamWriter[T] {
+	fields := make([]arrow.Field, 0, len(columns))
+	for _, c := range columns {
+		fields = append(fields, c.Field)
+	}
+	schema := arrow.NewSchema(fields, nil)
+	mem := memory.NewGoAllocator()
+	return &arrowStreamWriter[T]{
+		w:       w,
+		columns: columns,
+		builder: array.NewRecordBuilder(mem, schema),
+		writer:  ipc.NewWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem)),
+	}
+}
+
+func (s *arrowStreamWriter[T]) WritePage(results []T) error {
+	for _, result := range results {
+		for i, c := range s.columns {
+			c.Append(s.builder.Field(i), result)
+		}
+	}
+	rec := s.builder.NewRecord()
+	defer rec.Release()
+	if err := s.writer.Write(rec); err != nil {
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (s *arrowStreamWriter[T]) Close() error {
+	defer s.builder.Release()
+	return s.writer.Close()
+}
+
+// streamListing writes the results returned by next to a streaming response of contentType, page by page, until
+// the listing is exhausted or limit results were written (limit <= 0 for no limit). next lists up to amount
+// results after the last result it returned.
+func streamListing[T any](c *Controller, w http.ResponseWriter, r *http.Request, contentType string, columns []arrowColumn[T], limit int, next func(amount int) ([]T, bool, error)) {
+	ctx := r.Context()
+	var stream listingStreamWriter[T]
+	remaining := limit
+	for {
+		amount := DefaultMaxPerPage
+		if limit > 0 {
+			amount = min(amount, remaining)
+		}
+		results, hasMore, err := next(amount)
+		if stream == nil {
+			// errors are reported as usual as long as nothing was written
+			if c.handleAPIError(ctx, w, r, err) {
+				return
+			}
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(http.StatusOK)
+			if contentType == arrowStreamContentType {
+				stream = newArrowStreamWriter(w, columns)
+			} else {
+				stream = &ndjsonStreamWriter[T]{w: w, enc: json.NewEncoder(w)}
+			}
+		} else if err != nil {
+			// the response is already committed, the stream is left truncated
+			logging.FromContext(ctx).WithError(err).Error("Failed to list streamed results")
+			return
+		}
+		if err := stream.WritePage(results); err != nil {
+			logging.FromContext(ctx).WithError(err).Info("Failed to write streamed results")
+			return
+		}
+		remaining -= len(results)
+		if !hasMore || len(results) == 0 || (limit > 0 && remaining <= 0) {
+			break
+		}
+	}
+	if err := stream.Close(); err != nil {
+		logging.FromContext(ctx).WithError(err).Info("Failed to close results stream")
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+// AnonymousUsername is the principal of requests made without credentials
+const AnonymousUsername = "anonymous"
+
+// anonymousRepositoryActions are allowed on repositories readable without credentials
+var anonymousRepositoryActions = []string{
+	permissions.ReadRepositoryAction,
+	permissions.ReadBranchAction,
+	permissions.ListBranchesAction,
+	permissions.ReadTagAction,
+	permissions.ListTagsAction,
+	permissions.ReadCommitAction,
+	permissions.ListCommitsAction,
+	permissions.ListObjectsAction,
+	permissions.ReadObjectAction,
+}
+
+// AnonymousReadRule makes a repository, or the objects under a prefix of it, readable without credentials
+type AnonymousReadRule struct {
+	Repository string
+	// Prefix limits access to reading objects under it. Listing requires the whole repository to be readable, as
+	// list permissions are checked on the repository.
+	Prefix string
+}
+
+// AnonymousReadPolicy authorizes requests made without credentials, by a read-only policy built from its rules.
+// A nil policy allows nothing.
+type AnonymousReadPolicy struct {
+	policies []*model.Policy
+}
+
+// NewAnonymousReadPolicy returns the policy allowing reads by rules, or nil if there are no rules
+func NewAnonymousReadPolicy(rules []AnonymousReadRule) *AnonymousReadPolicy {
+	if len(rules) == 0 {
+		return nil
+	}
+	policy := &model.Policy{DisplayName: "AnonymousRead"}
+	for _, rule := range rules {
+		if rule.Prefix == "" {
+			policy.Statement = append(policy.Statement,
+				model.Statement{
+					Effect:   model.StatementEffectAllow,
+					Action:   anonymousRepositoryActions,
+					Resource: permissions.RepoArn(rule.Repository),
+				},
+				model.Statement{
+					Effect:   model.StatementEffectAllow,
+					Action:   anonymousRepositoryActions,
+					Resource: permissions.RepoArn(rule.Repository) + "/*",
+				})
+			continue
+		}
+		policy.Statement = append(policy.Statement, model.Statement{
+			Effect:   model.StatementEffectAllow,
+			Action:   []string{permissions.ReadObjectAction},
+			Resource: permissions.ObjectArn(rule.Repository, rule.Prefix+"*"),
+		})
+	}
+	return &AnonymousReadPolicy{policies: []*model.Policy{policy}}
+}
+
+// Enabled reports whether any resource is readable without credentials
+func (p *AnonymousReadPolicy) Enabled() bool {
+	return p != nil
+}
+
+// Authorize reports whether perms are allowed without credentials
+func (p *AnonymousReadPolicy) Authorize(ctx context.Context, perms permissions.Node) bool {
+	if p == nil {
+		return false
+	}
+	return checkPermissions(ctx, perms, AnonymousUsername, p.policies) == CheckAllow
+}
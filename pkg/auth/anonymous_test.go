@@ -0,0 +1,68 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+func TestAnonymousReadPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := auth.NewAnonymousReadPolicy([]auth.AnonymousReadRule{
+		{Repository: "public"},
+		{Repository: "datasets", Prefix: "open/"},
+	})
+	node := func(action, resource string) permissions.Node {
+		return permissions.Node{Permission: permissions.Permission{Action: action, Resource: resource}}
+	}
+	cases := []struct {
+		Name    string
+		Perms   permissions.Node
+		Allowed bool
+	}{
+		{Name: "read object", Perms: node(permissions.ReadObjectAction, permissions.ObjectArn("public", "a/b")), Allowed: true},
+		{Name: "list objects", Perms: node(permissions.ListObjectsAction, permissions.RepoArn("public")), Allowed: true},
+		{Name: "read branch", Perms: node(permissions.ReadBranchAction, permissions.BranchArn("public", "main")), Allowed: true},
+		{Name: "write object", Perms: node(permissions.WriteObjectAction, permissions.ObjectArn("public", "a/b")), Allowed: false},
+		{Name: "delete repository", Perms: node(permissions.DeleteRepositoryAction, permissions.RepoArn("public")), Allowed: false},
+		{Name: "other repository", Perms: node(permissions.ReadObjectAction, permissions.ObjectArn("private", "a/b")), Allowed: false},
+		{Name: "repository name prefix", Perms: node(permissions.ReadObjectAction, permissions.ObjectArn("public-2", "a/b")), Allowed: false},
+		{Name: "read object under prefix", Perms: node(permissions.ReadObjectAction, permissions.ObjectArn("datasets", "open/x")), Allowed: true},
+		{Name: "read object outside prefix", Perms: node(permissions.ReadObjectAction, permissions.ObjectArn("datasets", "closed/x")), Allowed: false},
+		{Name: "list objects of prefix repository", Perms: node(permissions.ListObjectsAction, permissions.RepoArn("datasets")), Allowed: false},
+		{Name: "list repositories", Perms: node(permissions.ListRepositoriesAction, permissions.All), Allowed: false},
+		{
+			Name: "all allowed",
+			Perms: permissions.Node{Type: permissions.NodeTypeAnd, Nodes: []permissions.Node{
+				node(permissions.ReadObjectAction, permissions.ObjectArn("public", "a")),
+				node(permissions.ReadObjectAction, permissions.ObjectArn("datasets", "open/a")),
+			}},
+			Allowed: true,
+		},
+		{
+			Name: "one not allowed",
+			Perms: permissions.Node{Type: permissions.NodeTypeAnd, Nodes: []permissions.Node{
+				node(permissions.ReadObjectAction, permissions.ObjectArn("public", "a")),
+				node(permissions.WriteObjectAction, permissions.ObjectArn("public", "a")),
+			}},
+			Allowed: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if allowed := policy.Authorize(ctx, tc.Perms); allowed != tc.Allowed {
+				t.Errorf("Authorize() = %t, expected %t", allowed, tc.Allowed)
+			}
+		})
+	}
+
+	disabled := auth.NewAnonymousReadPolicy(nil)
+	if disabled.Enabled() {
+		t.Error("Enabled() without rules expected false")
+	}
+	if disabled.Authorize(ctx, node(permissions.ReadObjectAction, permissions.ObjectArn("public", "a"))) {
+		t.Error("Authorize() without rules expected not allowed")
+	}
+}
@@ -22,6 +22,8 @@ var statementByName = map[string]model.Statement{
 	"FSFullAccess": {
 		Action: []string{
 			"fs:*",
+			permissions.ReadActionsConfigAction,
+			permissions.WriteActionsConfigAction,
 		},
 		Effect: model.StatementEffectAllow,
 	},
@@ -38,6 +40,7 @@ var statementByName = map[string]model.Statement{
 			permissions.DeleteTagAction,
 			permissions.CreateCommitAction,
 			permissions.CreateMetaRangeAction,
+			permissions.ReadActionsConfigAction,
 		},
 		Effect: model.StatementEffectAllow,
 	},
@@ -51,6 +54,7 @@ var statementByName = map[string]model.Statement{
 		Action: []string{
 			"fs:List*",
 			"fs:Read*",
+			permissions.ReadActionsConfigAction,
 		},
 
 		Effect: model.StatementEffectAllow,
@@ -9,7 +9,8 @@ import (
 type contextKey string
 
 const (
-	userContextKey contextKey = "user"
+	userContextKey      contextKey = "user"
+	anonymousContextKey contextKey = "anonymous"
 )
 
 func GetUser(ctx context.Context) (*model.User, error) {
@@ -23,3 +24,15 @@ func GetUser(ctx context.Context) (*model.User, error) {
 func WithUser(ctx context.Context, user *model.User) context.Context {
 	return context.WithValue(ctx, userContextKey, user)
 }
+
+// WithAnonymousUser marks the request as made without credentials, by the anonymous user
+func WithAnonymousUser(ctx context.Context) context.Context {
+	ctx = WithUser(ctx, &model.User{Username: AnonymousUsername})
+	return context.WithValue(ctx, anonymousContextKey, true)
+}
+
+// IsAnonymous reports whether the request was made without credentials
+func IsAnonymous(ctx context.Context) bool {
+	anonymous, _ := ctx.Value(anonymousContextKey).(bool)
+	return anonymous
+}
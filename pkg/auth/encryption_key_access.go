@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const encryptionKeyAccessUsersPageSize = 1000
+
+// EncryptionKeyAccessService lists the principals that may read data and authorizes them
+type EncryptionKeyAccessService interface {
+	Authorizer
+	ListUsers(ctx context.Context, params *model.PaginationParams) ([]*model.User, *model.Paginator, error)
+}
+
+// EncryptionKeyAccess checks that the users able to read objects on a branch may use the KMS key the object data
+// is encrypted with.
+type EncryptionKeyAccess struct {
+	authService   EncryptionKeyAccessService
+	anonymousRead *AnonymousReadPolicy
+}
+
+func NewEncryptionKeyAccess(authService EncryptionKeyAccessService, anonymousRead *AnonymousReadPolicy) *EncryptionKeyAccess {
+	return &EncryptionKeyAccess{
+		authService:   authService,
+		anonymousRead: anonymousRead,
+	}
+}
+
+// ReadersMayUseKey reports whether every principal allowed to read path on the branch is allowed to use the KMS
+// key. Requests made without credentials are never allowed to use a key.
+func (a *EncryptionKeyAccess) ReadersMayUseKey(ctx context.Context, repositoryID, branchID, path, kmsKeyID string) (bool, error) {
+	readPerms := permissions.Node{
+		Type: permissions.NodeTypeAnd,
+		Nodes: []permissions.Node{
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadBranchAction,
+					Resource: permissions.BranchArn(repositoryID, branchID),
+				},
+			},
+			{
+				Permission: permissions.Permission{
+					Action:   permissions.ReadObjectAction,
+					Resource: permissions.ObjectArn(repositoryID, path),
+				},
+			},
+		},
+	}
+	if a.anonymousRead.Authorize(ctx, readPerms) {
+		return false, nil
+	}
+	keyPerms := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.UseEncryptionKeyAction,
+			Resource: permissions.EncryptionKeyArn(kmsKeyID),
+		},
+	}
+	after := ""
+	for {
+		users, paginator, err := a.authService.ListUsers(ctx, &model.PaginationParams{
+			After:  after,
+			Amount: encryptionKeyAccessUsersPageSize,
+		})
+		if err != nil {
+			return false, fmt.Errorf("list users: %w", err)
+		}
+		for _, user := range users {
+			canRead, err := a.allowed(ctx, user.Username, readPerms)
+			if err != nil {
+				return false, err
+			}
+			if !canRead {
+				continue
+			}
+			canUseKey, err := a.allowed(ctx, user.Username, keyPerms)
+			if err != nil {
+				return false, err
+			}
+			if !canUseKey {
+				return false, nil
+			}
+		}
+		if paginator.NextPageToken == "" {
+			return true, nil
+		}
+		after = paginator.NextPageToken
+	}
+}
+
+func (a *EncryptionKeyAccess) allowed(ctx context.Context, username string, perms permissions.Node) (bool, error) {
+	resp, err := a.authService.Authorize(ctx, &AuthorizationRequest{
+		Username:            username,
+		RequiredPermissions: perms,
+	})
+	if err != nil {
+		return false, fmt.Errorf("authorize %s: %w", username, err)
+	}
+	return resp.Error == nil && resp.Allowed, nil
+}
@@ -0,0 +1,89 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	authtestutil "github.com/treeverse/lakefs/pkg/auth/testutil"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+func TestEncryptionKeyAccess_ReadersMayUseKey(t *testing.T) {
+	ctx := context.Background()
+	authService, _ := authtestutil.SetupService(t, ctx, someSecret)
+
+	writePolicy := func(name string, statements ...model.Statement) {
+		t.Helper()
+		if err := authService.WritePolicy(ctx, &model.Policy{DisplayName: name, Statement: statements}, false); err != nil {
+			t.Fatalf("WritePolicy(%s): %s", name, err)
+		}
+	}
+	createUser := func(username string, policies ...string) {
+		t.Helper()
+		if _, err := authService.CreateUser(ctx, &model.User{Username: username}); err != nil {
+			t.Fatalf("CreateUser(%s): %s", username, err)
+		}
+		for _, p := range policies {
+			if err := authService.AttachPolicyToUser(ctx, p, username); err != nil {
+				t.Fatalf("AttachPolicyToUser(%s, %s): %s", p, username, err)
+			}
+		}
+	}
+	writePolicy("ReadRestricted", model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{permissions.ReadBranchAction, permissions.ReadObjectAction},
+		Resource: permissions.RepoArn("repo1") + "/*",
+	})
+	writePolicy("ReadOpen", model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{permissions.ReadBranchAction},
+		Resource: permissions.BranchArn("repo1", "open"),
+	}, model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{permissions.ReadObjectAction},
+		Resource: permissions.ObjectArn("repo1", "*"),
+	})
+	writePolicy("UseKey", model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{permissions.UseEncryptionKeyAction},
+		Resource: permissions.EncryptionKeyArn("finance-key"),
+	})
+	createUser("analyst", "ReadRestricted", "UseKey")
+	createUser("viewer", "ReadOpen")
+
+	access := auth.NewEncryptionKeyAccess(authService, nil)
+	cases := []struct {
+		Name     string
+		Branch   string
+		KMSKeyID string
+		Allowed  bool
+	}{
+		{Name: "readers use key", Branch: "restricted", KMSKeyID: "finance-key", Allowed: true},
+		{Name: "reader without key", Branch: "open", KMSKeyID: "finance-key", Allowed: false},
+		{Name: "other key", Branch: "restricted", KMSKeyID: "other-key", Allowed: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			allowed, err := access.ReadersMayUseKey(ctx, "repo1", tc.Branch, "data/file", tc.KMSKeyID)
+			if err != nil {
+				t.Fatalf("ReadersMayUseKey: %s", err)
+			}
+			if allowed != tc.Allowed {
+				t.Errorf("ReadersMayUseKey(%s, %s) = %t, expected %t", tc.Branch, tc.KMSKeyID, allowed, tc.Allowed)
+			}
+		})
+	}
+
+	t.Run("anonymous reader", func(t *testing.T) {
+		anonymousAccess := auth.NewEncryptionKeyAccess(authService, auth.NewAnonymousReadPolicy([]auth.AnonymousReadRule{{Repository: "repo1"}}))
+		allowed, err := anonymousAccess.ReadersMayUseKey(ctx, "repo1", "restricted", "data/file", "finance-key")
+		if err != nil {
+			t.Fatalf("ReadersMayUseKey: %s", err)
+		}
+		if allowed {
+			t.Error("ReadersMayUseKey with anonymous readers = true, expected false")
+		}
+	})
+}
@@ -1037,26 +1037,10 @@ func checkPermissions(ctx context.Context, node permissions.Node, username strin
 	allowed := CheckNeutral
 	switch node.Type {
 	case permissions.NodeTypeNode:
-		// check whether the permission is allowed, denied or natural (not allowed and not denied)
-		for _, policy := range policies {
-			for _, stmt := range policy.Statement {
-				resource := interpolateUser(stmt.Resource, username)
-				if !ArnMatch(resource, node.Permission.Resource) {
-					continue
-				}
-				for _, action := range stmt.Action {
-					if !wildcard.Match(action, node.Permission.Action) {
-						continue // not a matching action
-					}
-
-					if stmt.Effect == model.StatementEffectDeny {
-						// this is a "Deny" and it takes precedence
-						return CheckDeny
-					}
-
-					allowed = CheckAllow
-				}
-			}
+		allowed = checkPermission(node.Permission, username, policies)
+		if configPermission, ok := permissions.ActionsConfigPermission(node.Permission); ok && allowed == CheckAllow {
+			// the actions configuration also requires its own permission
+			allowed = checkPermission(configPermission, username, policies)
 		}
 
 	case permissions.NodeTypeOr:
@@ -1094,6 +1078,32 @@ func checkPermissions(ctx context.Context, node permissions.Node, username strin
 	return allowed
 }
 
+// checkPermission returns whether the permission is allowed, denied or natural (not allowed and not denied)
+func checkPermission(permission permissions.Permission, username string, policies []*model.Policy) CheckResult {
+	allowed := CheckNeutral
+	for _, policy := range policies {
+		for _, stmt := range policy.Statement {
+			resource := interpolateUser(stmt.Resource, username)
+			if !ArnMatch(resource, permission.Resource) {
+				continue
+			}
+			for _, action := range stmt.Action {
+				if !wildcard.Match(action, permission.Action) {
+					continue // not a matching action
+				}
+
+				if stmt.Effect == model.StatementEffectDeny {
+					// this is a "Deny" and it takes precedence
+					return CheckDeny
+				}
+
+				allowed = CheckAllow
+			}
+		}
+	}
+	return allowed
+}
+
 func (s *AuthService) Authorize(ctx context.Context, req *AuthorizationRequest) (*AuthorizationResponse, error) {
 	policies, _, err := s.ListEffectivePolicies(ctx, req.Username, &model.PaginationParams{
 		After:  "", // all
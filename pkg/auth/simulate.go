@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/wildcard"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+// SimulationStatement is a statement of a policy matching a simulated permission
+type SimulationStatement struct {
+	Policy    string
+	Statement model.Statement
+}
+
+// SimulationResult explains the authorization of a permission
+type SimulationResult struct {
+	Result CheckResult
+	// Decisive is the statement deciding Result: the first statement denying the permission, otherwise the first
+	// statement allowing it. Nil if no statement matches the permission, which is not allowed.
+	Decisive *SimulationStatement
+	// Matching are all the statements matching the permission, in the order of the policies
+	Matching []SimulationStatement
+}
+
+// SimulatePermission evaluates permission against the policies of username the same way authorization does, and
+// reports the statements matching it
+func SimulatePermission(permission permissions.Permission, username string, policies []*model.Policy) *SimulationResult {
+	result := simulatePermission(permission, username, policies)
+	configPermission, ok := permissions.ActionsConfigPermission(permission)
+	if !ok || result.Result != CheckAllow {
+		return result
+	}
+	// the actions configuration also requires its own permission, which decides the result
+	configResult := simulatePermission(configPermission, username, policies)
+	configResult.Matching = append(result.Matching, configResult.Matching...)
+	return configResult
+}
+
+func simulatePermission(permission permissions.Permission, username string, policies []*model.Policy) *SimulationResult {
+	result := &SimulationResult{Result: CheckNeutral}
+	for _, policy := range policies {
+		for _, stmt := range policy.Statement {
+			resource := interpolateUser(stmt.Resource, username)
+			if !ArnMatch(resource, permission.Resource) || !matchAnyAction(stmt.Action, permission.Action) {
+				continue
+			}
+			match := SimulationStatement{Policy: policy.DisplayName, Statement: stmt}
+			result.Matching = append(result.Matching, match)
+			switch {
+			case stmt.Effect == model.StatementEffectDeny && result.Result != CheckDeny:
+				// a "Deny" takes precedence
+				result.Result = CheckDeny
+				result.Decisive = &match
+			case stmt.Effect != model.StatementEffectDeny && result.Result == CheckNeutral:
+				result.Result = CheckAllow
+				result.Decisive = &match
+			}
+		}
+	}
+	return result
+}
+
+func matchAnyAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if wildcard.Match(a, action) {
+			return true
+		}
+	}
+	return false
+}
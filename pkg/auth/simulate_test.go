@@ -0,0 +1,97 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+func TestSimulatePermission(t *testing.T) {
+	policies := []*model.Policy{
+		{
+			DisplayName: "ReadAll",
+			Statement: model.Statements{
+				{Effect: model.StatementEffectAllow, Action: []string{"fs:Read*"}, Resource: "*"},
+			},
+		},
+		{
+			DisplayName: "OwnBranch",
+			Statement: model.Statements{
+				{Effect: model.StatementEffectAllow, Action: []string{"fs:WriteObject"}, Resource: "arn:lakefs:fs:::repository/repo1/object/${user}/*"},
+			},
+		},
+		{
+			DisplayName: "NoSecrets",
+			Statement: model.Statements{
+				{Effect: model.StatementEffectDeny, Action: []string{"fs:*"}, Resource: "arn:lakefs:fs:::repository/secrets/*"},
+			},
+		},
+	}
+
+	cases := []struct {
+		Name       string
+		Action     string
+		Resource   string
+		Expected   auth.CheckResult
+		Decisive   string
+		NumMatches int
+	}{
+		{
+			Name:       "allowed",
+			Action:     permissions.ReadObjectAction,
+			Resource:   permissions.ObjectArn("repo1", "data/file"),
+			Expected:   auth.CheckAllow,
+			Decisive:   "ReadAll",
+			NumMatches: 1,
+		},
+		{
+			Name:       "allowed_for_user",
+			Action:     permissions.WriteObjectAction,
+			Resource:   permissions.ObjectArn("repo1", "user1/file"),
+			Expected:   auth.CheckAllow,
+			Decisive:   "OwnBranch",
+			NumMatches: 1,
+		},
+		{
+			Name:     "not_allowed",
+			Action:   permissions.WriteObjectAction,
+			Resource: permissions.ObjectArn("repo1", "user2/file"),
+			Expected: auth.CheckNeutral,
+		},
+		{
+			Name:       "actions_config",
+			Action:     permissions.ReadObjectAction,
+			Resource:   permissions.ObjectArn("repo1", "_lakefs_actions/pre-merge.yaml"),
+			Expected:   auth.CheckNeutral,
+			NumMatches: 1,
+		},
+		{
+			Name:       "denied",
+			Action:     permissions.ReadObjectAction,
+			Resource:   permissions.ObjectArn("secrets", "file"),
+			Expected:   auth.CheckDeny,
+			Decisive:   "NoSecrets",
+			NumMatches: 2,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := auth.SimulatePermission(permissions.Permission{Action: tt.Action, Resource: tt.Resource}, "user1", policies)
+			if result.Result != tt.Expected {
+				t.Errorf("SimulatePermission() result=%d, expected %d", result.Result, tt.Expected)
+			}
+			decisive := ""
+			if result.Decisive != nil {
+				decisive = result.Decisive.Policy
+			}
+			if decisive != tt.Decisive {
+				t.Errorf("SimulatePermission() decisive policy=%q, expected %q", decisive, tt.Decisive)
+			}
+			if len(result.Matching) != tt.NumMatches {
+				t.Errorf("SimulatePermission() matching statements=%d, expected %d", len(result.Matching), tt.NumMatches)
+			}
+		})
+	}
+}
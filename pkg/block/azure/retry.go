@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/params"
+)
+
+// tries counts the attempts of a single call, shared by all its retries
+type tries struct {
+	n int
+}
+
+// startTriesPolicy runs once per call and starts counting its attempts
+type startTriesPolicy struct{}
+
+func (startTriesPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.SetOperationValue(&tries{})
+	return req.Next()
+}
+
+// countRetriesPolicy runs on each attempt of a call and reports all attempts after the first as retries
+type countRetriesPolicy struct{}
+
+func (countRetriesPolicy) Do(req *policy.Request) (*http.Response, error) {
+	var t *tries
+	if req.OperationValue(&t) && t != nil {
+		t.n++
+		if t.n > 1 {
+			block.ReportRetry(block.BlockstoreTypeAzure)
+		}
+	}
+	return req.Next()
+}
+
+// clientOptions returns client options following the retry policy of params
+func clientOptions(params params.Azure) azcore.ClientOptions {
+	retry := policy.RetryOptions{
+		TryTimeout:    params.TryTimeout,
+		RetryDelay:    params.Retry.InitialBackoff,
+		MaxRetryDelay: params.Retry.MaxBackoff,
+	}
+	switch {
+	case params.Retry.MaxAttempts == 1:
+		// zero retries selects the SDK default, negative disables retries
+		retry.MaxRetries = -1
+	case params.Retry.MaxAttempts > 1:
+		retry.MaxRetries = int32(params.Retry.MaxAttempts - 1)
+	}
+	return azcore.ClientOptions{
+		Retry:            retry,
+		PerCallPolicies:  []policy.Policy{startTriesPolicy{}},
+		PerRetryPolicies: []policy.Policy{countRetriesPolicy{}},
+	}
+}
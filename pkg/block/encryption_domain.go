@@ -0,0 +1,193 @@
+package block
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type kmsKeyIDContextKey struct{}
+
+// WithKMSKeyID returns a context whose blockstore writes are encrypted using kmsKeyID instead of the KMS key
+// configured for the blockstore. Adapters that do not encrypt using KMS keys ignore it.
+func WithKMSKeyID(ctx context.Context, kmsKeyID string) context.Context {
+	return context.WithValue(ctx, kmsKeyIDContextKey{}, kmsKeyID)
+}
+
+// KMSKeyIDFromContext returns the KMS key blockstore writes with ctx are encrypted using, or "" to use the
+// configured key
+func KMSKeyIDFromContext(ctx context.Context) string {
+	kmsKeyID, _ := ctx.Value(kmsKeyIDContextKey{}).(string)
+	return kmsKeyID
+}
+
+// KMSKeyResolver returns the KMS key of the encryption domain of a repository, or "" if it has none
+type KMSKeyResolver func(ctx context.Context, repository string) (string, error)
+
+// EncryptionDomainAdapter encrypts the data written to each repository using the KMS key of its encryption
+// domain, resolved for the repository of the request context.  Data of repositories without a key, and requests
+// without a repository, use the configured key.  Pre-signed URLs for writing data of a repository with a key are
+// not supported, as the blockstore cannot apply the key to data it does not write.
+type EncryptionDomainAdapter struct {
+	adapter Adapter
+	resolve KMSKeyResolver
+}
+
+func NewEncryptionDomainAdapter(adapter Adapter, resolve KMSKeyResolver) Adapter {
+	return &EncryptionDomainAdapter{adapter: adapter, resolve: resolve}
+}
+
+func (a *EncryptionDomainAdapter) InnerAdapter() Adapter {
+	return a.adapter
+}
+
+// writeContext returns ctx with the KMS key of the encryption domain of its repository, and whether it has one.
+// Fails rather than write data of a repository with an unknown key.
+func (a *EncryptionDomainAdapter) writeContext(ctx context.Context) (context.Context, bool, error) {
+	repository := RepositoryFromContext(ctx)
+	if repository == "" {
+		return ctx, false, nil
+	}
+	kmsKeyID, err := a.resolve(ctx, repository)
+	if err != nil {
+		return nil, false, fmt.Errorf("resolve encryption key of %s: %w", repository, err)
+	}
+	if kmsKeyID == "" {
+		return ctx, false, nil
+	}
+	return WithKMSKeyID(ctx, kmsKeyID), true, nil
+}
+
+func (a *EncryptionDomainAdapter) Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) error {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return err
+	}
+	return a.adapter.Put(ctx, obj, sizeBytes, reader, opts)
+}
+
+func (a *EncryptionDomainAdapter) Get(ctx context.Context, obj ObjectPointer) (io.ReadCloser, error) {
+	return a.adapter.Get(ctx, obj)
+}
+
+func (a *EncryptionDomainAdapter) GetWalker(uri *url.URL) (Walker, error) {
+	return a.adapter.GetWalker(uri)
+}
+
+func (a *EncryptionDomainAdapter) GetPreSignedURL(ctx context.Context, obj ObjectPointer, mode PreSignMode) (string, time.Time, error) {
+	if mode == PreSignModeWrite {
+		_, hasKey, err := a.writeContext(ctx)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if hasKey {
+			return "", time.Time{}, fmt.Errorf("pre-signed write in encryption domain: %w", ErrOperationNotSupported)
+		}
+	}
+	return a.adapter.GetPreSignedURL(ctx, obj, mode)
+}
+
+// GetPresignUploadPartURL signs uploads of parts of a multipart upload, whose encryption is set when the upload is
+// created
+func (a *EncryptionDomainAdapter) GetPresignUploadPartURL(ctx context.Context, obj ObjectPointer, uploadID string, partNumber int) (string, error) {
+	return a.adapter.GetPresignUploadPartURL(ctx, obj, uploadID, partNumber)
+}
+
+func (a *EncryptionDomainAdapter) Exists(ctx context.Context, obj ObjectPointer) (bool, error) {
+	return a.adapter.Exists(ctx, obj)
+}
+
+func (a *EncryptionDomainAdapter) GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	return a.adapter.GetRange(ctx, obj, startPosition, endPosition)
+}
+
+func (a *EncryptionDomainAdapter) GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error) {
+	return a.adapter.GetProperties(ctx, obj)
+}
+
+func (a *EncryptionDomainAdapter) Remove(ctx context.Context, obj ObjectPointer) error {
+	return a.adapter.Remove(ctx, obj)
+}
+
+func (a *EncryptionDomainAdapter) Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return err
+	}
+	return a.adapter.Copy(ctx, sourceObj, destinationObj)
+}
+
+func (a *EncryptionDomainAdapter) CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error) {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+}
+
+func (a *EncryptionDomainAdapter) UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
+}
+
+func (a *EncryptionDomainAdapter) ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (*ListPartsResponse, error) {
+	return a.adapter.ListParts(ctx, obj, uploadID, opts)
+}
+
+func (a *EncryptionDomainAdapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+}
+
+func (a *EncryptionDomainAdapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*UploadPartResponse, error) {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
+}
+
+func (a *EncryptionDomainAdapter) AbortMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string) error {
+	return a.adapter.AbortMultiPartUpload(ctx, obj, uploadID)
+}
+
+func (a *EncryptionDomainAdapter) CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (*CompleteMultiPartUploadResponse, error) {
+	ctx, _, err := a.writeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+}
+
+func (a *EncryptionDomainAdapter) BlockstoreType() string {
+	return a.adapter.BlockstoreType()
+}
+
+func (a *EncryptionDomainAdapter) BlockstoreMetadata(ctx context.Context) (*BlockstoreMetadata, error) {
+	return a.adapter.BlockstoreMetadata(ctx)
+}
+
+func (a *EncryptionDomainAdapter) GetStorageNamespaceInfo() StorageNamespaceInfo {
+	return a.adapter.GetStorageNamespaceInfo()
+}
+
+func (a *EncryptionDomainAdapter) ResolveNamespace(storageNamespace, key string, identifierType IdentifierType) (QualifiedKey, error) {
+	return a.adapter.ResolveNamespace(storageNamespace, key, identifierType)
+}
+
+func (a *EncryptionDomainAdapter) GetRegion(ctx context.Context, storageNamespace string) (string, error) {
+	return a.adapter.GetRegion(ctx, storageNamespace)
+}
+
+func (a *EncryptionDomainAdapter) RuntimeStats() map[string]string {
+	return a.adapter.RuntimeStats()
+}
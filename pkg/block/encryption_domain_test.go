@@ -0,0 +1,82 @@
+package block_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+)
+
+// kmsKeyRecordingAdapter records the KMS key of the context of the last Put, and pre-signs any URL
+type kmsKeyRecordingAdapter struct {
+	block.Adapter
+	kmsKeyID string
+}
+
+func (a *kmsKeyRecordingAdapter) GetPreSignedURL(_ context.Context, obj block.ObjectPointer, _ block.PreSignMode) (string, time.Time, error) {
+	return "https://example.com/" + obj.Identifier, time.Time{}, nil
+}
+
+func (a *kmsKeyRecordingAdapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	a.kmsKeyID = block.KMSKeyIDFromContext(ctx)
+	return a.Adapter.Put(ctx, obj, sizeBytes, reader, opts)
+}
+
+func TestEncryptionDomainAdapter(t *testing.T) {
+	ctx := context.Background()
+	errResolve := errors.New("resolve failed")
+	keys := map[string]string{"acme-ml-features": "acme-ml-key"}
+	inner := &kmsKeyRecordingAdapter{Adapter: mem.New(ctx)}
+	adapter := block.NewEncryptionDomainAdapter(inner, func(_ context.Context, repository string) (string, error) {
+		if repository == "broken" {
+			return "", errResolve
+		}
+		return keys[repository], nil
+	})
+	obj := block.ObjectPointer{StorageNamespace: "mem://bucket", Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+
+	tests := []struct {
+		name       string
+		repository string
+		kmsKeyID   string
+		err        error
+	}{
+		{name: "no_repository"},
+		{name: "without_key", repository: "other"},
+		{name: "with_key", repository: "acme-ml-features", kmsKeyID: "acme-ml-key"},
+		{name: "resolve_error", repository: "broken", err: errResolve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner.kmsKeyID = ""
+			ctx := ctx
+			if tt.repository != "" {
+				ctx = block.WithRepository(ctx, tt.repository)
+			}
+			err := adapter.Put(ctx, obj, 4, strings.NewReader("data"), block.PutOpts{})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Put = %v, expected %v", err, tt.err)
+			}
+			if inner.kmsKeyID != tt.kmsKeyID {
+				t.Errorf("Put with KMS key %q, expected %q", inner.kmsKeyID, tt.kmsKeyID)
+			}
+		})
+	}
+
+	// the key cannot be applied to data written using pre-signed URLs
+	keyCtx := block.WithRepository(ctx, "acme-ml-features")
+	if _, _, err := adapter.GetPreSignedURL(keyCtx, obj, block.PreSignModeWrite); !errors.Is(err, block.ErrOperationNotSupported) {
+		t.Errorf("GetPreSignedURL write with key = %v, expected %s", err, block.ErrOperationNotSupported)
+	}
+	if _, _, err := adapter.GetPreSignedURL(keyCtx, obj, block.PreSignModeRead); err != nil {
+		t.Errorf("GetPreSignedURL read with key: %s", err)
+	}
+	if _, _, err := adapter.GetPreSignedURL(block.WithRepository(ctx, "other"), obj, block.PreSignModeWrite); err != nil {
+		t.Errorf("GetPreSignedURL write without key: %s", err)
+	}
+}
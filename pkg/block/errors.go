@@ -10,4 +10,8 @@ var (
 	ErrForbidden             = errors.New("forbidden")
 	ErrInvalidAddress        = errors.New("invalid address")
 	ErrInvalidNamespace      = errors.New("invalid namespace")
+	// ErrCircuitOpen is returned by calls to a bucket failing fast after consecutive calls to it failed
+	ErrCircuitOpen = errors.New("blockstore circuit open")
+	// ErrOperationTimeout is returned by calls the blockstore did not respond to within the operation timeout
+	ErrOperationTimeout = errors.New("blockstore operation timeout")
 )
@@ -6,6 +6,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/googleapis/gax-go/v2"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/azure"
 	"github.com/treeverse/lakefs/pkg/block/gs"
@@ -14,6 +15,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/block/params"
 	s3a "github.com/treeverse/lakefs/pkg/block/s3"
 	"github.com/treeverse/lakefs/pkg/block/transient"
+	"github.com/treeverse/lakefs/pkg/faultinject"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/stats"
 	"golang.org/x/oauth2/google"
@@ -23,6 +25,8 @@ import (
 const (
 	// googleAuthCloudPlatform - Cloud Storage authentication https://cloud.google.com/storage/docs/authentication
 	googleAuthCloudPlatform = "https://www.googleapis.com/auth/cloud-platform"
+	// gsBackoffMultiplier - growth of the delay between retries of Google Cloud Storage calls
+	gsBackoffMultiplier = 2
 )
 
 func BuildBlockAdapter(ctx context.Context, statsCollector stats.Collector, c params.AdapterConfig) (block.Adapter, error) {
@@ -31,7 +35,28 @@ func BuildBlockAdapter(ctx context.Context, statsCollector stats.Collector, c pa
 		return nil, err
 	}
 
-	return block.NewMetricsAdapter(adapter), nil
+	if fc, ok := c.(params.FaultInjectionConfig); ok {
+		if p := fc.BlockstoreFaultInjectionParams(); p.Enabled() {
+			injector, err := faultinject.NewInjector(p)
+			if err != nil {
+				return nil, err
+			}
+			logging.FromContext(ctx).
+				WithField("type", c.BlockstoreType()).
+				Warn("Blockstore fault injection enabled, not for production use")
+			adapter = block.NewFaultInjectionAdapter(adapter, injector)
+		}
+	}
+	if rc, ok := c.(params.ResilienceConfig); ok {
+		if p := rc.BlockstoreResilienceParams(); p.Enabled() {
+			adapter = block.NewResilientAdapter(adapter, p)
+		}
+	}
+	var metricsParams params.Metrics
+	if mc, ok := c.(params.MetricsConfig); ok {
+		metricsParams = mc.BlockstoreMetricsParams()
+	}
+	return block.NewMetricsAdapter(adapter, metricsParams), nil
 }
 
 func buildBlockAdapter(ctx context.Context, statsCollector stats.Collector, c params.AdapterConfig) (block.Adapter, error) {
@@ -51,7 +76,11 @@ func buildBlockAdapter(ctx context.Context, statsCollector stats.Collector, c pa
 		if err != nil {
 			return nil, err
 		}
-		return buildS3Adapter(ctx, statsCollector, p)
+		adapter, err := buildS3Adapter(ctx, statsCollector, p)
+		if err != nil {
+			return nil, err
+		}
+		return block.NewTimeoutAdapter(adapter, p.OperationTimeout), nil
 	case block.BlockstoreTypeMem, "memory":
 		return mem.New(ctx), nil
 	case block.BlockstoreTypeTransient:
@@ -61,13 +90,21 @@ func buildBlockAdapter(ctx context.Context, statsCollector stats.Collector, c pa
 		if err != nil {
 			return nil, err
 		}
-		return buildGSAdapter(ctx, p)
+		adapter, err := buildGSAdapter(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		return block.NewTimeoutAdapter(adapter, p.OperationTimeout), nil
 	case block.BlockstoreTypeAzure:
 		p, err := c.BlockstoreAzureParams()
 		if err != nil {
 			return nil, err
 		}
-		return azure.NewAdapter(ctx, p)
+		adapter, err := azure.NewAdapter(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		return block.NewTimeoutAdapter(adapter, p.OperationTimeout), nil
 	default:
 		return nil, fmt.Errorf("%w '%s' please choose one of %s",
 			block.ErrInvalidAddress, blockstore, []string{block.BlockstoreTypeLocal, block.BlockstoreTypeS3, block.BlockstoreTypeAzure, block.BlockstoreTypeMem, block.BlockstoreTypeTransient, block.BlockstoreTypeGS})
@@ -78,6 +115,10 @@ func buildLocalAdapter(ctx context.Context, params params.Local) (*local.Adapter
 	adapter, err := local.NewAdapter(params.Path,
 		local.WithAllowedExternalPrefixes(params.AllowedExternalPrefixes),
 		local.WithImportEnabled(params.ImportEnabled),
+		local.WithFsync(params.Fsync),
+		local.WithShardDepth(params.ShardDepth),
+		local.WithMinFreeSpace(params.MinFreeSpace),
+		local.WithReflinkCopy(params.ReflinkCopy),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("got error opening a local block adapter with path %s: %w", params.Path, err)
@@ -133,7 +174,28 @@ func BuildGSClient(ctx context.Context, params params.GS) (*storage.Client, erro
 		}
 		opts = append(opts, option.WithCredentials(cred))
 	}
-	return storage.NewClient(ctx, opts...)
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	retryOpts := []storage.RetryOption{
+		storage.WithErrorFunc(func(err error) bool {
+			retry := storage.ShouldRetry(err)
+			if retry {
+				block.ReportRetry(block.BlockstoreTypeGS)
+			}
+			return retry
+		}),
+	}
+	if params.Retry.InitialBackoff > 0 || params.Retry.MaxBackoff > 0 {
+		retryOpts = append(retryOpts, storage.WithBackoff(gax.Backoff{
+			Initial:    params.Retry.InitialBackoff,
+			Max:        params.Retry.MaxBackoff,
+			Multiplier: gsBackoffMultiplier,
+		}))
+	}
+	client.SetRetry(retryOpts...)
+	return client, nil
 }
 
 func buildGSAdapter(ctx context.Context, params params.GS) (*gs.Adapter, error) {
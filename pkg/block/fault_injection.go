@@ -0,0 +1,229 @@
+package block
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/faultinject"
+)
+
+// FaultInjectionAdapter injects faults into the calls of an adapter, for testing. Partial failures of writes
+// perform the write and then fail it, partial failures of reads fail reading the object midway.
+type FaultInjectionAdapter struct {
+	adapter  Adapter
+	injector *faultinject.Injector
+}
+
+func NewFaultInjectionAdapter(adapter Adapter, injector *faultinject.Injector) Adapter {
+	return &FaultInjectionAdapter{adapter: adapter, injector: injector}
+}
+
+func (a *FaultInjectionAdapter) InnerAdapter() Adapter {
+	return a.adapter
+}
+
+// faultWriteErr returns the error of a write call of op performed with fault
+func faultWriteErr(err error, fault faultinject.Fault, op string) error {
+	if err == nil && fault == faultinject.FaultPartial {
+		return faultinject.Error(op)
+	}
+	return err
+}
+
+func (a *FaultInjectionAdapter) Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) error {
+	const op = "Put"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return faultinject.Error(op)
+	}
+	return faultWriteErr(a.adapter.Put(ctx, obj, sizeBytes, reader, opts), fault, op)
+}
+
+func (a *FaultInjectionAdapter) Get(ctx context.Context, obj ObjectPointer) (io.ReadCloser, error) {
+	const op = "Get"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	rc, err := a.adapter.Get(ctx, obj)
+	if err != nil || fault != faultinject.FaultPartial {
+		return rc, err
+	}
+	return faultinject.NewPartialReadCloser(rc, op), nil
+}
+
+func (a *FaultInjectionAdapter) GetWalker(uri *url.URL) (Walker, error) {
+	return a.adapter.GetWalker(uri)
+}
+
+func (a *FaultInjectionAdapter) GetPreSignedURL(ctx context.Context, obj ObjectPointer, mode PreSignMode) (string, time.Time, error) {
+	const op = "GetPreSignedURL"
+	if a.injector.Inject(ctx, op) != faultinject.FaultNone {
+		return "", time.Time{}, faultinject.Error(op)
+	}
+	return a.adapter.GetPreSignedURL(ctx, obj, mode)
+}
+
+func (a *FaultInjectionAdapter) GetPresignUploadPartURL(ctx context.Context, obj ObjectPointer, uploadID string, partNumber int) (string, error) {
+	const op = "GetPresignUploadPartURL"
+	if a.injector.Inject(ctx, op) != faultinject.FaultNone {
+		return "", faultinject.Error(op)
+	}
+	return a.adapter.GetPresignUploadPartURL(ctx, obj, uploadID, partNumber)
+}
+
+func (a *FaultInjectionAdapter) Exists(ctx context.Context, obj ObjectPointer) (bool, error) {
+	const op = "Exists"
+	if a.injector.Inject(ctx, op) != faultinject.FaultNone {
+		return false, faultinject.Error(op)
+	}
+	return a.adapter.Exists(ctx, obj)
+}
+
+func (a *FaultInjectionAdapter) GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	const op = "GetRange"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	rc, err := a.adapter.GetRange(ctx, obj, startPosition, endPosition)
+	if err != nil || fault != faultinject.FaultPartial {
+		return rc, err
+	}
+	return faultinject.NewPartialReadCloser(rc, op), nil
+}
+
+func (a *FaultInjectionAdapter) GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error) {
+	const op = "GetProperties"
+	if a.injector.Inject(ctx, op) != faultinject.FaultNone {
+		return Properties{}, faultinject.Error(op)
+	}
+	return a.adapter.GetProperties(ctx, obj)
+}
+
+func (a *FaultInjectionAdapter) Remove(ctx context.Context, obj ObjectPointer) error {
+	const op = "Remove"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return faultinject.Error(op)
+	}
+	return faultWriteErr(a.adapter.Remove(ctx, obj), fault, op)
+}
+
+func (a *FaultInjectionAdapter) Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error {
+	const op = "Copy"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return faultinject.Error(op)
+	}
+	return faultWriteErr(a.adapter.Copy(ctx, sourceObj, destinationObj), fault, op)
+}
+
+func (a *FaultInjectionAdapter) CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error) {
+	const op = "CreateMultiPartUpload"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	resp, err := a.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+	if err == nil && fault == faultinject.FaultPartial {
+		return nil, faultinject.Error(op)
+	}
+	return resp, err
+}
+
+func (a *FaultInjectionAdapter) UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	const op = "UploadPart"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	resp, err := a.adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
+	if err == nil && fault == faultinject.FaultPartial {
+		return nil, faultinject.Error(op)
+	}
+	return resp, err
+}
+
+func (a *FaultInjectionAdapter) ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (*ListPartsResponse, error) {
+	const op = "ListParts"
+	if a.injector.Inject(ctx, op) != faultinject.FaultNone {
+		return nil, faultinject.Error(op)
+	}
+	return a.adapter.ListParts(ctx, obj, uploadID, opts)
+}
+
+func (a *FaultInjectionAdapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	const op = "UploadCopyPart"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	resp, err := a.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+	if err == nil && fault == faultinject.FaultPartial {
+		return nil, faultinject.Error(op)
+	}
+	return resp, err
+}
+
+func (a *FaultInjectionAdapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*UploadPartResponse, error) {
+	const op = "UploadCopyPartRange"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	resp, err := a.adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
+	if err == nil && fault == faultinject.FaultPartial {
+		return nil, faultinject.Error(op)
+	}
+	return resp, err
+}
+
+func (a *FaultInjectionAdapter) AbortMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string) error {
+	const op = "AbortMultiPartUpload"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return faultinject.Error(op)
+	}
+	return faultWriteErr(a.adapter.AbortMultiPartUpload(ctx, obj, uploadID), fault, op)
+}
+
+func (a *FaultInjectionAdapter) CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (*CompleteMultiPartUploadResponse, error) {
+	const op = "CompleteMultiPartUpload"
+	fault := a.injector.Inject(ctx, op)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(op)
+	}
+	resp, err := a.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+	if err == nil && fault == faultinject.FaultPartial {
+		return nil, faultinject.Error(op)
+	}
+	return resp, err
+}
+
+func (a *FaultInjectionAdapter) BlockstoreType() string {
+	return a.adapter.BlockstoreType()
+}
+
+func (a *FaultInjectionAdapter) BlockstoreMetadata(ctx context.Context) (*BlockstoreMetadata, error) {
+	return a.adapter.BlockstoreMetadata(ctx)
+}
+
+func (a *FaultInjectionAdapter) GetStorageNamespaceInfo() StorageNamespaceInfo {
+	return a.adapter.GetStorageNamespaceInfo()
+}
+
+func (a *FaultInjectionAdapter) ResolveNamespace(storageNamespace, key string, identifierType IdentifierType) (QualifiedKey, error) {
+	return a.adapter.ResolveNamespace(storageNamespace, key, identifierType)
+}
+
+func (a *FaultInjectionAdapter) GetRegion(ctx context.Context, storageNamespace string) (string, error) {
+	return a.adapter.GetRegion(ctx, storageNamespace)
+}
+
+func (a *FaultInjectionAdapter) RuntimeStats() map[string]string {
+	return a.adapter.RuntimeStats()
+}
@@ -130,26 +130,36 @@ func (o *storageObjectHandle) withWriteHandle(a *Adapter) *storageObjectHandle {
 	}
 	return o
 }
+
+// kmsKeyName returns the KMS key of data written with ctx: the KMS key of ctx if it has one, otherwise the
+// configured key
+func (a *Adapter) kmsKeyName(ctx context.Context) string {
+	if kmsKeyID := block.KMSKeyIDFromContext(ctx); kmsKeyID != "" {
+		return kmsKeyID
+	}
+	return a.ServerSideEncryptionKmsKeyID
+}
+
 func (o *storageObjectHandle) newWriter(ctx context.Context, a *Adapter) *storage.Writer {
 	w := o.NewWriter(ctx)
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		w.KMSKeyName = a.ServerSideEncryptionKmsKeyID
+	if kmsKeyName := a.kmsKeyName(ctx); kmsKeyName != "" {
+		w.KMSKeyName = kmsKeyName
 	}
 	return w
 }
 
-func (o *storageObjectHandle) newCopier(a *Adapter, src *storage.ObjectHandle) *storage.Copier {
+func (o *storageObjectHandle) newCopier(ctx context.Context, a *Adapter, src *storage.ObjectHandle) *storage.Copier {
 	c := o.CopierFrom(src)
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		c.DestinationKMSKeyName = a.ServerSideEncryptionKmsKeyID
+	if kmsKeyName := a.kmsKeyName(ctx); kmsKeyName != "" {
+		c.DestinationKMSKeyName = kmsKeyName
 	}
 	return c
 }
 
-func (o *storageObjectHandle) newComposer(a *Adapter, srcs ...*storage.ObjectHandle) *storage.Composer {
+func (o *storageObjectHandle) newComposer(ctx context.Context, a *Adapter, srcs ...*storage.ObjectHandle) *storage.Composer {
 	c := o.ComposerFrom(srcs...)
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		c.KMSKeyName = a.ServerSideEncryptionKmsKeyID
+	if kmsKeyName := a.kmsKeyName(ctx); kmsKeyName != "" {
+		c.KMSKeyName = kmsKeyName
 	}
 	return c
 }
@@ -328,7 +338,7 @@ func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.Obje
 	dstHandle = dstHandle.withWriteHandle(a)
 	srcHandle := &storageObjectHandle{a.client.Bucket(srcBucket).Object(srcKey)}
 	srcHandle = srcHandle.withReadHandle(ctx, a)
-	copier := dstHandle.newCopier(a, srcHandle.ObjectHandle)
+	copier := dstHandle.newCopier(ctx, a, srcHandle.ObjectHandle)
 	_, err = copier.Run(ctx)
 	if err != nil {
 		return fmt.Errorf("copy: %w", err)
@@ -411,7 +421,7 @@ func (a *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj
 	srcHandle := &storageObjectHandle{a.client.Bucket(srcBucket).Object(srcKey)}
 	srcHandle = srcHandle.withReadHandle(ctx, a)
 	h := storageObjectHandle{a.client.Bucket(bucket).Object(objName)}
-	copier := h.withWriteHandle(a).newCopier(a, srcHandle.ObjectHandle)
+	copier := h.withWriteHandle(a).newCopier(ctx, a, srcHandle.ObjectHandle)
 	attrs, err := copier.Run(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("CopierFrom: %w", err)
@@ -622,7 +632,7 @@ func (a *Adapter) composeMultipartUploadParts(ctx context.Context, bucketName st
 		}
 		// compose target from parts
 		targetHandle := storageObjectHandle{bucket.Object(target)}
-		composer := targetHandle.withWriteHandle(a).newComposer(a, objs...)
+		composer := targetHandle.withWriteHandle(a).newComposer(ctx, a, objs...)
 		attrs, err := composer.Run(ctx)
 		if err != nil {
 			return nil, err
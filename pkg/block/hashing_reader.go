@@ -4,6 +4,7 @@ import (
 	"crypto/md5" //nolint:gosec
 	"crypto/sha256"
 	"hash"
+	"hash/crc32"
 	"io"
 	"strconv"
 )
@@ -13,11 +14,13 @@ type HashFunction int
 const (
 	HashFunctionMD5 HashFunction = iota
 	HashFunctionSHA256
+	HashFunctionCRC32C
 )
 
 type HashingReader struct {
 	Md5            hash.Hash
 	Sha256         hash.Hash
+	Crc32c         hash.Hash
 	originalReader io.Reader
 	CopiedSize     int64
 }
@@ -35,6 +38,11 @@ func (s *HashingReader) Read(p []byte) (int, error) {
 			return nb, err2
 		}
 	}
+	if s.Crc32c != nil {
+		if _, err2 := s.Crc32c.Write(p[0:nb]); err2 != nil {
+			return nb, err2
+		}
+	}
 	return nb, err
 }
 
@@ -51,6 +59,10 @@ func NewHashingReader(body io.Reader, hashTypes ...HashFunction) *HashingReader
 			if s.Sha256 == nil {
 				s.Sha256 = sha256.New()
 			}
+		case HashFunctionCRC32C:
+			if s.Crc32c == nil {
+				s.Crc32c = crc32.New(crc32.MakeTable(crc32.Castagnoli))
+			}
 		default:
 			panic("wrong hash type number " + strconv.Itoa(int(hashType)))
 		}
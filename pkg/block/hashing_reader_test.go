@@ -11,7 +11,7 @@ import (
 
 func TestHashingReaderRead(t *testing.T) {
 	origData := []byte{1, 2, 3, 4, 5, 6, 7}
-	hashReader := block.NewHashingReader(bytes.NewReader(origData), block.HashFunctionMD5, block.HashFunctionSHA256)
+	hashReader := block.NewHashingReader(bytes.NewReader(origData), block.HashFunctionMD5, block.HashFunctionSHA256, block.HashFunctionCRC32C)
 
 	buf1 := make([]byte, 5)
 	wLen, err := hashReader.Read(buf1)
@@ -23,6 +23,8 @@ func TestHashingReaderRead(t *testing.T) {
 	require.Equal(t, "7cfdd07889b3295d6a550914ab35e068", hex.EncodeToString(md5Hash))
 	sha256Hash := hashReader.Sha256.Sum(nil)
 	require.Equal(t, "74f81fe167d99b4cb41d6d0ccda82278caee9f3e2f25d5e5a3936ff3dcec60d0", hex.EncodeToString(sha256Hash))
+	crc32cHash := hashReader.Crc32c.Sum(nil)
+	require.Equal(t, "53518fab", hex.EncodeToString(crc32cHash))
 
 	buf2 := make([]byte, 5)
 	wLen, err = hashReader.Read(buf2)
@@ -34,4 +36,6 @@ func TestHashingReaderRead(t *testing.T) {
 	require.Equal(t, "498001217bc632cb158588224d7d23c4", hex.EncodeToString(md5Hash))
 	sha256Hash = hashReader.Sha256.Sum(nil)
 	require.Equal(t, "32bbe378a25091502b2baf9f7258c19444e7a43ee4593b08030acd790bd66e6a", hex.EncodeToString(sha256Hash))
+	crc32cHash = hashReader.Crc32c.Sum(nil)
+	require.Equal(t, "bd3a64dc", hex.EncodeToString(crc32cHash))
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"net/url"
@@ -25,26 +26,38 @@ import (
 
 const DefaultNamespacePrefix = block.BlockstoreTypeLocal + "://"
 
+// MaxShardDepth is the maximal number of directory levels objects are sharded into
+const MaxShardDepth = 8
+
 type Adapter struct {
 	path                    string
 	removeEmptyDir          bool
 	allowedExternalPrefixes []string
 	importEnabled           bool
+	fsync                   bool
+	shardDepth              int
+	minFreeSpace            int64
+	reflinkCopy             bool
 }
 
 var (
 	ErrPathNotWritable       = errors.New("path provided is not writable")
 	ErrInvalidUploadIDFormat = errors.New("invalid upload id format")
 	ErrBadPath               = errors.New("bad path traversal blocked")
+	ErrBadShardDepth         = fmt.Errorf("shard depth must be between 0 and %d", MaxShardDepth)
+	ErrInsufficientSpace     = errors.New("insufficient free space")
+	ErrFreeSpaceNotSupported = errors.New("free space check not supported")
+	ErrReflinkNotSupported   = errors.New("reflink not supported")
 )
 
 type QualifiedKey struct {
 	block.CommonQualifiedKey
-	path string
+	path       string
+	shardDepth int
 }
 
 func (qk QualifiedKey) Format() string {
-	p := path.Join(qk.path, qk.GetStorageNamespace(), qk.GetKey())
+	p := path.Join(qk.path, qk.GetStorageNamespace(), shardDir(qk.GetKey(), qk.shardDepth), qk.GetKey())
 	return qk.GetStorageType().Scheme() + "://" + p
 }
 
@@ -78,6 +91,37 @@ func WithRemoveEmptyDir(b bool) func(a *Adapter) {
 	}
 }
 
+// WithFsync makes writes durable before they return: objects are written to a temporary file which is synced and
+// then renamed into place, so a crash never leaves a partially written object
+func WithFsync(b bool) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.fsync = b
+	}
+}
+
+// WithShardDepth spreads the objects of each storage namespace over depth levels of directories by the hash of
+// their key, avoiding millions of files in a single directory.  Changing it hides objects already written.
+func WithShardDepth(depth int) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.shardDepth = depth
+	}
+}
+
+// WithMinFreeSpace fails writes that would leave less than bytes free on the filesystem
+func WithMinFreeSpace(bytes int64) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.minFreeSpace = bytes
+	}
+}
+
+// WithReflinkCopy copies objects by sharing their data on filesystems supporting reflinks, such as XFS, falling
+// back to copying the data
+func WithReflinkCopy(b bool) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.reflinkCopy = b
+	}
+}
+
 func NewAdapter(path string, opts ...func(a *Adapter)) (*Adapter, error) {
 	// Clean() the path so that misconfiguration does not allow path traversal.
 	path = filepath.Clean(path)
@@ -95,6 +139,9 @@ func NewAdapter(path string, opts ...func(a *Adapter)) (*Adapter, error) {
 	for _, opt := range opts {
 		opt(localAdapter)
 	}
+	if localAdapter.shardDepth < 0 || localAdapter.shardDepth > MaxShardDepth {
+		return nil, fmt.Errorf("%w: %d", ErrBadShardDepth, localAdapter.shardDepth)
+	}
 	return localAdapter, nil
 }
 
@@ -121,16 +168,112 @@ func (l *Adapter) extractParamsFromObj(ptr block.ObjectPointer) (string, error)
 		return p, nil
 	}
 	// relative path
-	if !strings.HasPrefix(ptr.StorageNamespace, DefaultNamespacePrefix) {
+	return l.relativePath(ptr.StorageNamespace, ptr.Identifier, ptr.Identifier)
+}
+
+// relativePath returns the path of identifier under storageNamespace, in the shard directory of shardKey
+func (l *Adapter) relativePath(storageNamespace, identifier, shardKey string) (string, error) {
+	if !strings.HasPrefix(storageNamespace, DefaultNamespacePrefix) {
 		return "", fmt.Errorf("%w: storage namespace", ErrBadPath)
 	}
-	p := path.Join(l.path, ptr.StorageNamespace[len(DefaultNamespacePrefix):], ptr.Identifier)
+	p := path.Join(l.path, storageNamespace[len(DefaultNamespacePrefix):], shardDir(shardKey, l.shardDepth), identifier)
 	if err := l.verifyRelPath(p); err != nil {
 		return "", err
 	}
 	return p, nil
 }
 
+// shardDir returns the shard directory of key: depth levels named by pairs of hex digits of its hash
+func shardDir(key string, depth int) string {
+	if depth <= 0 {
+		return ""
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	digits := fmt.Sprintf("%016x", h.Sum64())
+	levels := make([]string, depth)
+	for i := range levels {
+		levels[i] = digits[2*i : 2*i+2]
+	}
+	return path.Join(levels...)
+}
+
+// partPath returns the path of a part of a multipart upload, sharded by the upload so that its parts are found
+// together
+func (l *Adapter) partPath(obj block.ObjectPointer, uploadID string, partNumber int) (string, error) {
+	return l.relativePath(obj.StorageNamespace, uploadID+fmt.Sprintf("-%05d", partNumber), uploadID)
+}
+
+// checkFreeSpace fails with ErrInsufficientSpace if writing sizeBytes would leave less than the minimal free
+// space.  A negative sizeBytes checks only the minimal free space.
+func (l *Adapter) checkFreeSpace(sizeBytes int64) error {
+	if l.minFreeSpace <= 0 {
+		return nil
+	}
+	free, err := freeSpace(l.path)
+	if errors.Is(err, ErrFreeSpaceNotSupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("free space of %s: %w", l.path, err)
+	}
+	required := uint64(l.minFreeSpace + max(sizeBytes, 0))
+	if free < required {
+		return fmt.Errorf("%w: %d bytes free, %d required", ErrInsufficientSpace, free, required)
+	}
+	return nil
+}
+
+// writeFile writes the file at p with write.  With fsync it writes a temporary file, syncs it and renames it into
+// place, so p is either missing or complete even after a crash.
+func (l *Adapter) writeFile(p string, write func(f *os.File) (int64, error)) (int64, error) {
+	p = filepath.Clean(p)
+	if !l.fsync {
+		f, err := l.maybeMkdir(p, os.Create)
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		return write(f)
+	}
+	f, err := l.maybeMkdir(p, func(p string) (*os.File, error) {
+		return os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".tmp-*")
+	})
+	if err != nil {
+		return 0, err
+	}
+	tmp := f.Name()
+	n, err := write(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, p)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	return n, syncDir(filepath.Dir(p))
+}
+
+// syncDir syncs the directory dir, persisting files created or renamed into it
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = d.Close()
+	}()
+	return d.Sync()
+}
+
 // maybeMkdir verifies path is allowed and runs f(path), but if f fails due to file-not-found
 // MkdirAll's its dir and then runs it again.
 func (l *Adapter) maybeMkdir(path string, f func(p string) (*os.File, error)) (*os.File, error) {
@@ -152,20 +295,21 @@ func (l *Adapter) Path() string {
 	return l.path
 }
 
-func (l *Adapter) Put(_ context.Context, obj block.ObjectPointer, _ int64, reader io.Reader, _ block.PutOpts) error {
+func (l *Adapter) Put(_ context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, _ block.PutOpts) error {
 	p, err := l.extractParamsFromObj(obj)
 	if err != nil {
 		return err
 	}
-	p = filepath.Clean(p)
-	f, err := l.maybeMkdir(p, os.Create)
-	if err != nil {
+	return l.put(p, sizeBytes, reader)
+}
+
+func (l *Adapter) put(p string, sizeBytes int64, reader io.Reader) error {
+	if err := l.checkFreeSpace(sizeBytes); err != nil {
 		return err
 	}
-	defer func() {
-		_ = f.Close()
-	}()
-	_, err = io.Copy(f, reader)
+	_, err := l.writeFile(p, func(f *os.File) (int64, error) {
+		return io.Copy(f, reader)
+	})
 	return err
 }
 
@@ -212,24 +356,35 @@ func (l *Adapter) Copy(_ context.Context, sourceObj, destinationObj block.Object
 		return err
 	}
 	sourceFile, err := os.Open(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
 	defer func() {
 		_ = sourceFile.Close()
 	}()
+	dest, err := l.extractParamsFromObj(destinationObj)
 	if err != nil {
 		return err
 	}
-	dest, err := l.extractParamsFromObj(destinationObj)
+	sourceInfo, err := sourceFile.Stat()
 	if err != nil {
 		return err
 	}
-	destinationFile, err := l.maybeMkdir(dest, os.Create)
-	if err != nil {
+	if err := l.checkFreeSpace(sourceInfo.Size()); err != nil {
 		return err
 	}
-	defer func() {
-		_ = destinationFile.Close()
-	}()
-	_, err = io.Copy(destinationFile, sourceFile)
+	_, err = l.writeFile(dest, func(f *os.File) (int64, error) {
+		if l.reflinkCopy {
+			err := reflink(f, sourceFile)
+			if err == nil {
+				return sourceInfo.Size(), nil
+			}
+			if !errors.Is(err, ErrReflinkNotSupported) {
+				return 0, err
+			}
+		}
+		return io.Copy(f, sourceFile)
+	})
 	return err
 }
 
@@ -242,8 +397,11 @@ func (l *Adapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj
 		return nil, fmt.Errorf("copy get: %w", err)
 	}
 	md5Read := block.NewHashingReader(r, block.HashFunctionMD5)
-	fName := uploadID + fmt.Sprintf("-%05d", partNumber)
-	err = l.Put(ctx, block.ObjectPointer{StorageNamespace: destinationObj.StorageNamespace, Identifier: fName}, -1, md5Read, block.PutOpts{})
+	p, err := l.partPath(destinationObj, uploadID, partNumber)
+	if err != nil {
+		return nil, err
+	}
+	err = l.put(p, -1, md5Read)
 	if err != nil {
 		return nil, fmt.Errorf("copy put: %w", err)
 	}
@@ -262,8 +420,11 @@ func (l *Adapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinatio
 		return nil, fmt.Errorf("copy range get: %w", err)
 	}
 	md5Read := block.NewHashingReader(r, block.HashFunctionMD5)
-	fName := uploadID + fmt.Sprintf("-%05d", partNumber)
-	err = l.Put(ctx, block.ObjectPointer{StorageNamespace: destinationObj.StorageNamespace, Identifier: fName}, -1, md5Read, block.PutOpts{})
+	p, err := l.partPath(destinationObj, uploadID, partNumber)
+	if err != nil {
+		return nil, err
+	}
+	err = l.put(p, endPosition-startPosition+1, md5Read)
 	if err != nil {
 		return nil, fmt.Errorf("copy range put: %w", err)
 	}
@@ -389,13 +550,16 @@ func (l *Adapter) CreateMultiPartUpload(_ context.Context, obj block.ObjectPoint
 	}, nil
 }
 
-func (l *Adapter) UploadPart(ctx context.Context, obj block.ObjectPointer, _ int64, reader io.Reader, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
+func (l *Adapter) UploadPart(_ context.Context, obj block.ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*block.UploadPartResponse, error) {
 	if err := isValidUploadID(uploadID); err != nil {
 		return nil, err
 	}
 	md5Read := block.NewHashingReader(reader, block.HashFunctionMD5)
-	fName := uploadID + fmt.Sprintf("-%05d", partNumber)
-	err := l.Put(ctx, block.ObjectPointer{StorageNamespace: obj.StorageNamespace, Identifier: fName}, -1, md5Read, block.PutOpts{})
+	p, err := l.partPath(obj, uploadID, partNumber)
+	if err != nil {
+		return nil, err
+	}
+	err = l.put(p, sizeBytes, md5Read)
 	etag := hex.EncodeToString(md5Read.Md5.Sum(nil))
 	return &block.UploadPartResponse{
 		ETag: etag,
@@ -456,17 +620,13 @@ func (l *Adapter) unitePartFiles(identifier block.ObjectPointer, filenames []str
 	if err != nil {
 		return 0, err
 	}
-	unitedFile, err := os.Create(p)
-	if err != nil {
-		return 0, fmt.Errorf("create path %s: %w", p, err)
-	}
 	files := make([]*os.File, 0, len(filenames))
 	defer func() {
-		_ = unitedFile.Close()
 		for _, f := range files {
 			_ = f.Close()
 		}
 	}()
+	var size int64
 	for _, name := range filenames {
 		if err := l.verifyRelPath(name); err != nil {
 			return 0, err
@@ -476,6 +636,14 @@ func (l *Adapter) unitePartFiles(identifier block.ObjectPointer, filenames []str
 			return 0, fmt.Errorf("open file %s: %w", name, err)
 		}
 		files = append(files, f)
+		info, err := f.Stat()
+		if err != nil {
+			return 0, fmt.Errorf("stat file %s: %w", name, err)
+		}
+		size += info.Size()
+	}
+	if err := l.checkFreeSpace(size); err != nil {
+		return 0, err
 	}
 	// convert slice file files to readers
 	readers := make([]io.Reader, len(files))
@@ -483,7 +651,13 @@ func (l *Adapter) unitePartFiles(identifier block.ObjectPointer, filenames []str
 		readers[i] = files[i]
 	}
 	unitedReader := io.MultiReader(readers...)
-	return io.Copy(unitedFile, unitedReader)
+	n, err := l.writeFile(p, func(f *os.File) (int64, error) {
+		return io.Copy(f, unitedReader)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("write path %s: %w", p, err)
+	}
+	return n, nil
 }
 
 func (l *Adapter) removePartFiles(files []string) error {
@@ -501,11 +675,7 @@ func (l *Adapter) removePartFiles(files []string) error {
 }
 
 func (l *Adapter) getPartFiles(uploadID string, obj block.ObjectPointer) ([]string, error) {
-	newObj := block.ObjectPointer{
-		StorageNamespace: obj.StorageNamespace,
-		Identifier:       uploadID,
-	}
-	globPathPattern, err := l.extractParamsFromObj(newObj)
+	globPathPattern, err := l.relativePath(obj.StorageNamespace, uploadID, uploadID)
 	if err != nil {
 		return nil, err
 	}
@@ -553,6 +723,7 @@ func (l *Adapter) ResolveNamespace(storageNamespace, key string, identifierType
 	return QualifiedKey{
 		CommonQualifiedKey: qk,
 		path:               l.path,
+		shardDepth:         l.shardDepth,
 	}, nil
 }
 
@@ -1,8 +1,13 @@
 package local_test
 
 import (
+	"context"
+	"errors"
+	"math"
 	"path"
 	"regexp"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -25,6 +30,36 @@ func TestLocalAdapter(t *testing.T) {
 	blocktest.AdapterTest(t, adapter, testStorageNamespace, externalPath)
 }
 
+// TestLocalAdapterHardened tests the Local Storage Adapter with durable writes, sharding and reflink copies
+func TestLocalAdapterHardened(t *testing.T) {
+	tmpDir := t.TempDir()
+	localPath := path.Join(tmpDir, "lakefs")
+	externalPath := block.BlockstoreTypeLocal + "://" + path.Join(tmpDir, "lakefs", "external")
+	adapter, err := local.NewAdapter(localPath,
+		local.WithRemoveEmptyDir(false),
+		local.WithFsync(true),
+		local.WithShardDepth(2),
+		local.WithReflinkCopy(true),
+	)
+	if err != nil {
+		t.Fatal("Failed to create new adapter", err)
+	}
+	blocktest.AdapterTest(t, adapter, testStorageNamespace, externalPath)
+}
+
+func TestLocalAdapterMinFreeSpace(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("free space check supported on Linux only")
+	}
+	adapter, err := local.NewAdapter(path.Join(t.TempDir(), "lakefs"), local.WithMinFreeSpace(math.MaxInt64/2))
+	require.NoError(t, err, "create new adapter")
+	obj := block.ObjectPointer{StorageNamespace: testStorageNamespace, Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	err = adapter.Put(context.Background(), obj, 4, strings.NewReader("data"), block.PutOpts{})
+	if !errors.Is(err, local.ErrInsufficientSpace) {
+		t.Fatalf("Put = %v, expected %s", err, local.ErrInsufficientSpace)
+	}
+}
+
 // TestAdapterNamespace tests the namespace validity regex with various paths
 func TestAdapterNamespace(t *testing.T) {
 	tmpDir := t.TempDir()
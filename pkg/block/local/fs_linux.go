@@ -0,0 +1,41 @@
+package local
+
+import (
+	"os"
+	"syscall"
+)
+
+// ioctlFICLONE is the FICLONE ioctl request, sharing the extents of a file with another on reflink filesystems
+const ioctlFICLONE = 0x40049409
+
+// freeSpace returns the bytes available to unprivileged users on the filesystem of path
+func freeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil //nolint:gosec
+}
+
+// reflink makes dst share the data of src without copying it.  Fails with ErrReflinkNotSupported on filesystems
+// without reflinks (XFS and Btrfs support them).
+func reflink(dst, src *os.File) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dst.Fd(), ioctlFICLONE, src.Fd())
+	switch errno {
+	case 0:
+		return nil
+	case syscall.EOPNOTSUPP, syscall.EXDEV, syscall.EINVAL, syscall.ENOTTY:
+		return ErrReflinkNotSupported
+	default:
+		return errno
+	}
+}
+
+// linkCount returns the number of hard links to the file of info
+func linkCount(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Nlink), true //nolint:unconvert
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package local
+
+import "os"
+
+// freeSpace is supported on Linux only
+func freeSpace(_ string) (uint64, error) {
+	return 0, ErrFreeSpaceNotSupported
+}
+
+// reflink is supported on Linux only
+func reflink(_, _ *os.File) error {
+	return ErrReflinkNotSupported
+}
+
+// linkCount is supported on Linux only
+func linkCount(_ os.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,207 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+)
+
+const (
+	// gcSnapshotsDir holds the snapshots of running garbage collections under the adapter path
+	gcSnapshotsDir = ".gc-snapshots"
+	// lakeFSMetadataDir holds lakeFS metadata under each storage namespace, never collected
+	lakeFSMetadataDir = "_lakefs"
+)
+
+// GCSnapshot is a point-in-time view of the objects of a storage namespace, made of hard links to them.  Objects
+// written after the snapshot are not in it, so garbage collection never removes them, and the links keep the data
+// of swept objects around until the snapshot is removed, so the space a sweep reclaims can be measured exactly.
+type GCSnapshot struct {
+	adapter      *Adapter
+	dir          string
+	namespaceDir string
+	// Objects is the number of objects in the snapshot
+	Objects int64
+}
+
+// GCSweepResult reports the objects removed by a garbage collection sweep
+type GCSweepResult struct {
+	// Objects is the number of objects removed
+	Objects int64
+	// Bytes is the size of the objects removed
+	Bytes int64
+	// ReclaimedBytes is the size of the removed objects that were not hard linked elsewhere, so their space was
+	// freed.  Data shared by reflinks is counted as reclaimed.
+	ReclaimedBytes int64
+}
+
+// ObjectPath returns the path of the file holding obj
+func (l *Adapter) ObjectPath(obj block.ObjectPointer) (string, error) {
+	return l.extractParamsFromObj(obj)
+}
+
+// SnapshotNamespace hard links every object of storageNamespace into a new snapshot named id.  lakeFS metadata
+// under _lakefs and temporary files are skipped.
+func (l *Adapter) SnapshotNamespace(ctx context.Context, storageNamespace, id string) (*GCSnapshot, error) {
+	if !strings.HasPrefix(storageNamespace, DefaultNamespacePrefix) {
+		return nil, fmt.Errorf("%w: storage namespace", ErrBadPath)
+	}
+	namespaceDir := filepath.Join(l.path, storageNamespace[len(DefaultNamespacePrefix):])
+	if err := l.verifyRelPath(namespaceDir); err != nil {
+		return nil, err
+	}
+	snapshot := &GCSnapshot{
+		adapter:      l,
+		dir:          filepath.Join(l.path, gcSnapshotsDir, id),
+		namespaceDir: namespaceDir,
+	}
+	if err := l.verifyRelPath(snapshot.dir); err != nil {
+		return nil, err
+	}
+	snapshotsRoot := filepath.Join(l.path, gcSnapshotsDir)
+	err := filepath.WalkDir(namespaceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(namespaceDir, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel == lakeFSMetadataDir || p == snapshotsRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		target := filepath.Join(snapshot.dir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil { //nolint: mnd
+			return err
+		}
+		if err := os.Link(p, target); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// removed while walking
+				return nil
+			}
+			return fmt.Errorf("link %s: %w", p, err)
+		}
+		snapshot.Objects++
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) && snapshot.Objects == 0 {
+		// nothing was ever written to the storage namespace
+		err = nil
+	}
+	if err != nil {
+		_ = snapshot.Close()
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Sweep removes the objects of the snapshot that are not live and were last modified before olderThan, batchSize
+// objects at a time, calling progress with the number of objects examined after each batch.  Objects replaced
+// since the snapshot are kept.  With dryRun, only reports what it would remove.
+func (s *GCSnapshot) Sweep(ctx context.Context, live func(path string) bool, olderThan time.Time, batchSize int, dryRun bool, progress func(examined int64) error) (GCSweepResult, error) {
+	var (
+		result   GCSweepResult
+		examined int64
+		batch    []string
+	)
+	flush := func() error {
+		for _, p := range batch {
+			if err := s.remove(p, dryRun, &result); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if progress != nil {
+			return progress(examined)
+		}
+		return nil
+	}
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		examined++
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		objectPath := filepath.Join(s.namespaceDir, rel)
+		if !live(objectPath) {
+			snapshotInfo, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if snapshotInfo.ModTime().Before(olderThan) {
+				batch = append(batch, rel)
+			}
+		}
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	return result, err
+}
+
+// remove removes the object at rel unless it was replaced since the snapshot, adding it to result
+func (s *GCSnapshot) remove(rel string, dryRun bool, result *GCSweepResult) error {
+	objectPath := filepath.Join(s.namespaceDir, rel)
+	objectInfo, err := os.Lstat(objectPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	snapshotInfo, err := os.Lstat(filepath.Join(s.dir, rel))
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(objectInfo, snapshotInfo) {
+		return nil
+	}
+	// the data is freed when the object and its snapshot link are its only links
+	links, ok := linkCount(snapshotInfo)
+	reclaimed := !ok || links <= 2 //nolint: mnd
+	if !dryRun {
+		if err := os.Remove(objectPath); err != nil {
+			return fmt.Errorf("remove %s: %w", objectPath, err)
+		}
+		if s.adapter.removeEmptyDir {
+			removeEmptyDirUntil(filepath.Dir(objectPath), s.namespaceDir)
+		}
+	}
+	result.Objects++
+	result.Bytes += snapshotInfo.Size()
+	if reclaimed {
+		result.ReclaimedBytes += snapshotInfo.Size()
+	}
+	return nil
+}
+
+// Close removes the snapshot, freeing the space of swept objects
+func (s *GCSnapshot) Close() error {
+	return os.RemoveAll(s.dir)
+}
@@ -0,0 +1,78 @@
+package local_test
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/local"
+)
+
+func TestGCSnapshotSweep(t *testing.T) {
+	ctx := context.Background()
+	adapter, err := local.NewAdapter(path.Join(t.TempDir(), "lakefs"), local.WithRemoveEmptyDir(true))
+	require.NoError(t, err)
+	pointer := func(identifier string) block.ObjectPointer {
+		return block.ObjectPointer{
+			StorageNamespace: testStorageNamespace,
+			Identifier:       identifier,
+			IdentifierType:   block.IdentifierTypeRelative,
+		}
+	}
+	put := func(identifier, data string) {
+		t.Helper()
+		require.NoError(t, adapter.Put(ctx, pointer(identifier), int64(len(data)), strings.NewReader(data), block.PutOpts{}))
+	}
+	exists := func(identifier string) bool {
+		t.Helper()
+		ok, err := adapter.Exists(ctx, pointer(identifier))
+		require.NoError(t, err)
+		return ok
+	}
+	put("data/live", "live")
+	put("data/dead", "dead object")
+	put("_lakefs/metarange", "metadata")
+
+	snapshot, err := adapter.SnapshotNamespace(ctx, testStorageNamespace, "test")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, snapshot.Close()) }()
+	require.Equal(t, int64(2), snapshot.Objects, "snapshot objects, lakeFS metadata excluded")
+
+	// written after the snapshot, so never collected
+	put("data/new", "new")
+	livePath, err := adapter.ObjectPath(pointer("data/live"))
+	require.NoError(t, err)
+	live := func(p string) bool { return p == livePath }
+
+	t.Run("min_age", func(t *testing.T) {
+		result, err := snapshot.Sweep(ctx, live, time.Now().Add(-time.Hour), 1, false, nil)
+		require.NoError(t, err)
+		require.Equal(t, local.GCSweepResult{}, result)
+	})
+
+	t.Run("dry_run", func(t *testing.T) {
+		result, err := snapshot.Sweep(ctx, live, time.Now().Add(time.Hour), 1, true, nil)
+		require.NoError(t, err)
+		require.Equal(t, local.GCSweepResult{Objects: 1, Bytes: 11, ReclaimedBytes: 11}, result)
+		require.True(t, exists("data/dead"))
+	})
+
+	t.Run("sweep", func(t *testing.T) {
+		var examined int64
+		result, err := snapshot.Sweep(ctx, live, time.Now().Add(time.Hour), 1, false, func(n int64) error {
+			examined = n
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, local.GCSweepResult{Objects: 1, Bytes: 11, ReclaimedBytes: 11}, result)
+		require.Equal(t, int64(2), examined)
+		require.False(t, exists("data/dead"))
+		require.True(t, exists("data/live"))
+		require.True(t, exists("data/new"))
+		require.True(t, exists("_lakefs/metarange"))
+	})
+}
@@ -5,30 +5,115 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/treeverse/lakefs/pkg/block/params"
 	"github.com/treeverse/lakefs/pkg/httputil"
 )
 
+// OtherRepositoriesLabel labels the metrics of repositories beyond the repository labels limit
+const OtherRepositoriesLabel = "_other"
+
+var requestDurationHistograms = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: "blockstore_request_duration_seconds",
+		Help: "durations of blockstore requests by repository",
+	},
+	[]string{"type", "operation", "repository", "error"})
+
+var requestBytesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "blockstore_request_bytes_total",
+		Help: "bytes uploaded to the blockstore by repository",
+	},
+	[]string{"type", "operation", "repository"})
+
+type repositoryContextKey struct{}
+
+// WithRepository returns a context whose blockstore requests are attributed to repository in metrics
+func WithRepository(ctx context.Context, repository string) context.Context {
+	return context.WithValue(ctx, repositoryContextKey{}, repository)
+}
+
+// RepositoryFromContext returns the repository blockstore requests with ctx are attributed to, or "" if none
+func RepositoryFromContext(ctx context.Context) string {
+	repository, _ := ctx.Value(repositoryContextKey{}).(string)
+	return repository
+}
+
+// repositoryLabels bounds the number of repository label values, labelling repositories beyond the limit as other
+type repositoryLabels struct {
+	limit int
+	mu    sync.RWMutex
+	seen  map[string]struct{}
+}
+
+func (l *repositoryLabels) label(repository string) string {
+	if repository == "" || l.limit <= 0 {
+		return repository
+	}
+	l.mu.RLock()
+	_, ok := l.seen[repository]
+	l.mu.RUnlock()
+	if ok {
+		return repository
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[repository]; ok {
+		return repository
+	}
+	if len(l.seen) >= l.limit {
+		return OtherRepositoriesLabel
+	}
+	l.seen[repository] = struct{}{}
+	return repository
+}
+
 type MetricsAdapter struct {
-	adapter Adapter
+	adapter      Adapter
+	repositories *repositoryLabels
 }
 
-func NewMetricsAdapter(adapter Adapter) Adapter {
-	return &MetricsAdapter{adapter: adapter}
+func NewMetricsAdapter(adapter Adapter, p params.Metrics) Adapter {
+	return &MetricsAdapter{
+		adapter: adapter,
+		repositories: &repositoryLabels{
+			limit: p.RepositoryLabelsLimit,
+			seen:  make(map[string]struct{}),
+		},
+	}
 }
 
 func (m *MetricsAdapter) InnerAdapter() Adapter {
 	return m.adapter
 }
 
-func (m *MetricsAdapter) Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) error {
+// reportRequest observes a request of operation attributed to the repository of ctx, and sizeBytes uploaded by it
+// unless nil
+func (m *MetricsAdapter) reportRequest(ctx context.Context, operation string, start time.Time, sizeBytes *int64, err *error) {
+	blockstoreType := m.adapter.BlockstoreType()
+	repository := m.repositories.label(RepositoryFromContext(ctx))
+	isErrStr := strconv.FormatBool(*err != nil)
+	requestDurationHistograms.WithLabelValues(blockstoreType, operation, repository, isErrStr).Observe(time.Since(start).Seconds())
+	if sizeBytes != nil && *err == nil {
+		requestBytesCounter.WithLabelValues(blockstoreType, operation, repository).Add(float64(*sizeBytes))
+	}
+}
+
+func (m *MetricsAdapter) Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) (err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "Put", time.Now(), &sizeBytes, &err)
 	return m.adapter.Put(ctx, obj, sizeBytes, reader, opts)
 }
 
-func (m *MetricsAdapter) Get(ctx context.Context, obj ObjectPointer) (io.ReadCloser, error) {
+func (m *MetricsAdapter) Get(ctx context.Context, obj ObjectPointer) (_ io.ReadCloser, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "Get", time.Now(), nil, &err)
 	return m.adapter.Get(ctx, obj)
 }
 
@@ -46,63 +131,75 @@ func (m *MetricsAdapter) GetPresignUploadPartURL(ctx context.Context, obj Object
 	return m.adapter.GetPresignUploadPartURL(ctx, obj, uploadID, partNumber)
 }
 
-func (m *MetricsAdapter) Exists(ctx context.Context, obj ObjectPointer) (bool, error) {
+func (m *MetricsAdapter) Exists(ctx context.Context, obj ObjectPointer) (_ bool, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "Exists", time.Now(), nil, &err)
 	return m.adapter.Exists(ctx, obj)
 }
 
-func (m *MetricsAdapter) GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+func (m *MetricsAdapter) GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (_ io.ReadCloser, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "GetRange", time.Now(), nil, &err)
 	return m.adapter.GetRange(ctx, obj, startPosition, endPosition)
 }
 
-func (m *MetricsAdapter) GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error) {
+func (m *MetricsAdapter) GetProperties(ctx context.Context, obj ObjectPointer) (_ Properties, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "GetProperties", time.Now(), nil, &err)
 	return m.adapter.GetProperties(ctx, obj)
 }
 
-func (m *MetricsAdapter) Remove(ctx context.Context, obj ObjectPointer) error {
+func (m *MetricsAdapter) Remove(ctx context.Context, obj ObjectPointer) (err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "Remove", time.Now(), nil, &err)
 	return m.adapter.Remove(ctx, obj)
 }
 
-func (m *MetricsAdapter) Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error {
+func (m *MetricsAdapter) Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) (err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "Copy", time.Now(), nil, &err)
 	return m.adapter.Copy(ctx, sourceObj, destinationObj)
 }
 
-func (m *MetricsAdapter) CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error) {
+func (m *MetricsAdapter) CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (_ *CreateMultiPartUploadResponse, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "CreateMultiPartUpload", time.Now(), nil, &err)
 	return m.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
 }
 
-func (m *MetricsAdapter) UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*UploadPartResponse, error) {
+func (m *MetricsAdapter) UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (_ *UploadPartResponse, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "UploadPart", time.Now(), &sizeBytes, &err)
 	return m.adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
 }
 
-func (m *MetricsAdapter) ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (*ListPartsResponse, error) {
+func (m *MetricsAdapter) ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (_ *ListPartsResponse, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "ListParts", time.Now(), nil, &err)
 	return m.adapter.ListParts(ctx, obj, uploadID, opts)
 }
 
-func (m *MetricsAdapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int) (*UploadPartResponse, error) {
+func (m *MetricsAdapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int) (_ *UploadPartResponse, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "UploadCopyPart", time.Now(), nil, &err)
 	return m.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
 }
 
-func (m *MetricsAdapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*UploadPartResponse, error) {
+func (m *MetricsAdapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (_ *UploadPartResponse, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "UploadCopyPartRange", time.Now(), nil, &err)
 	return m.adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
 }
 
-func (m *MetricsAdapter) AbortMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string) error {
+func (m *MetricsAdapter) AbortMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string) (err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "AbortMultiPartUpload", time.Now(), nil, &err)
 	return m.adapter.AbortMultiPartUpload(ctx, obj, uploadID)
 }
 
-func (m *MetricsAdapter) CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (*CompleteMultiPartUploadResponse, error) {
+func (m *MetricsAdapter) CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (_ *CompleteMultiPartUploadResponse, err error) {
 	ctx = httputil.SetClientTrace(ctx, m.adapter.BlockstoreType())
+	defer m.reportRequest(ctx, "CompleteMultiPartUpload", time.Now(), nil, &err)
 	return m.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
 }
 
@@ -2,6 +2,8 @@ package params
 
 import (
 	"time"
+
+	"github.com/treeverse/lakefs/pkg/faultinject"
 )
 
 // AdapterConfig configures a block adapter.
@@ -13,6 +15,46 @@ type AdapterConfig interface {
 	BlockstoreAzureParams() (Azure, error)
 }
 
+// FaultInjectionConfig is implemented by adapter configurations that may inject faults into the adapter, for testing
+type FaultInjectionConfig interface {
+	BlockstoreFaultInjectionParams() faultinject.Params
+}
+
+// MetricsConfig is implemented by adapter configurations that bound the labels of blockstore request metrics
+type MetricsConfig interface {
+	BlockstoreMetricsParams() Metrics
+}
+
+// Metrics configures the metrics of blockstore requests
+type Metrics struct {
+	// RepositoryLabelsLimit is the number of repositories labelled in metrics, later repositories share a single
+	// label.  0 for no limit.
+	RepositoryLabelsLimit int
+}
+
+// ResilienceConfig is implemented by adapter configurations that protect requests from a slow or failing bucket
+type ResilienceConfig interface {
+	BlockstoreResilienceParams() Resilience
+}
+
+// Resilience configures the circuit breakers and hedged reads of calls to the buckets of a blockstore
+type Resilience struct {
+	// FailureThreshold is the number of consecutive failed or slow calls to a bucket that open its circuit, 0
+	// disables circuit breaking
+	FailureThreshold int
+	// SlowCallDuration is the duration from which calls count as failures, 0 counts only errors
+	SlowCallDuration time.Duration
+	// OpenDuration is the time calls to a bucket with an open circuit fail fast
+	OpenDuration time.Duration
+	// HedgedReads duplicates reads running longer than the P99 read latency, and at least HedgedReadsMinDelay
+	HedgedReads         bool
+	HedgedReadsMinDelay time.Duration
+}
+
+func (r Resilience) Enabled() bool {
+	return r.FailureThreshold > 0 || r.HedgedReads
+}
+
 type Mem struct{}
 
 type Local struct {
@@ -20,6 +62,14 @@ type Local struct {
 	ImportEnabled           bool
 	ImportHidden            bool
 	AllowedExternalPrefixes []string
+	// Fsync makes writes durable before they return
+	Fsync bool
+	// ShardDepth is the number of directory levels objects are sharded into, 0 for no sharding
+	ShardDepth int
+	// MinFreeSpace is the number of bytes writes must leave free, 0 for no check
+	MinFreeSpace int64
+	// ReflinkCopy copies objects by sharing their data on filesystems supporting reflinks
+	ReflinkCopy bool
 }
 
 // S3WebIdentity contains parameters for customizing S3 web identity.  This
@@ -35,6 +85,16 @@ type S3WebIdentity struct {
 	SessionExpiryWindow time.Duration
 }
 
+// Retry configures retrying failed calls to a blockstore
+type Retry struct {
+	// MaxAttempts is the number of attempts of a call including the first, 0 for the SDK default
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, 0 for the SDK default
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximal delay between retries, 0 for the SDK default
+	MaxBackoff time.Duration
+}
+
 type S3Credentials struct {
 	AccessKeyID     string
 	SecretAccessKey string
@@ -60,6 +120,9 @@ type S3 struct {
 	ClientLogRetries              bool
 	ClientLogRequest              bool
 	WebIdentity                   *S3WebIdentity
+	Retry                         Retry
+	// OperationTimeout bounds the time the blockstore takes to respond to a call, 0 for no timeout
+	OperationTimeout time.Duration
 }
 
 type GS struct {
@@ -70,6 +133,9 @@ type GS struct {
 	DisablePreSignedUI                   bool
 	ServerSideEncryptionCustomerSupplied []byte
 	ServerSideEncryptionKmsKeyID         string
+	Retry                                Retry
+	// OperationTimeout bounds the time the blockstore takes to respond to a call, 0 for no timeout
+	OperationTimeout time.Duration
 }
 
 type Azure struct {
@@ -83,4 +149,7 @@ type Azure struct {
 	TestEndpointURL string
 	// Domain - Azure cloud domain
 	Domain string
+	Retry  Retry
+	// OperationTimeout bounds the time the blockstore takes to respond to a call, 0 for no timeout
+	OperationTimeout time.Duration
 }
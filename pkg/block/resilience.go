@@ -0,0 +1,487 @@
+package block
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/treeverse/lakefs/pkg/block/params"
+)
+
+const (
+	// latencySamples is the number of latest read latencies the P99 latency is computed from
+	latencySamples = 1000
+	// latencyMinSamples is the number of read latencies measured before hedging after the P99 latency
+	latencyMinSamples = 100
+	// latencyRecomputeEvery is the number of read latencies measured between computations of the P99 latency
+	latencyRecomputeEvery = 100
+	latencyPercentile     = 0.99
+)
+
+var circuitRejectedCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "blockstore_circuit_rejected_total",
+		Help: "blockstore calls failed fast because the circuit of their bucket is open",
+	},
+	[]string{"operation"})
+
+var hedgedReadsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "blockstore_hedged_reads_total",
+		Help: "blockstore reads duplicated after running longer than the P99 read latency, by the attempt that won",
+	},
+	[]string{"operation", "winner"})
+
+// ResilientAdapter protects calls to an adapter from a slow or failing bucket.  Each bucket has a circuit breaker:
+// once enough consecutive calls to it fail or are slow, calls to it fail fast with ErrCircuitOpen for a while,
+// instead of piling up and timing out requests.  Reads that run longer than the P99 read latency are optionally
+// duplicated, and the first result wins.
+type ResilientAdapter struct {
+	adapter Adapter
+	params  params.Resilience
+
+	mu        sync.Mutex
+	breakers  map[string]*circuitBreaker
+	latencies map[string]*latencyTracker
+}
+
+func NewResilientAdapter(adapter Adapter, p params.Resilience) Adapter {
+	return &ResilientAdapter{
+		adapter:   adapter,
+		params:    p,
+		breakers:  make(map[string]*circuitBreaker),
+		latencies: make(map[string]*latencyTracker),
+	}
+}
+
+func (a *ResilientAdapter) InnerAdapter() Adapter {
+	return a.adapter
+}
+
+// circuitBreaker tracks the consecutive failures of calls to a bucket
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	// probing is set while a single call checks whether a bucket with an open circuit recovered
+	probing bool
+}
+
+// allow returns whether a call may be made, once the circuit is open it allows a single probing call after it was
+// open for openDuration
+func (b *circuitBreaker) allow(threshold int, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) record(failed bool, threshold int, openDuration time.Duration, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= threshold {
+		b.openUntil = now.Add(openDuration)
+	}
+}
+
+// latencyTracker keeps the latest latencies of a read operation
+type latencyTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	count   int
+	p99     time.Duration
+}
+
+func (t *latencyTracker) add(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.samples) < latencySamples {
+		t.samples = append(t.samples, d)
+	} else {
+		t.samples[t.next] = d
+		t.next = (t.next + 1) % latencySamples
+	}
+	t.count++
+	if t.count >= latencyMinSamples && t.count%latencyRecomputeEvery == 0 {
+		sorted := slices.Clone(t.samples)
+		slices.Sort(sorted)
+		t.p99 = sorted[int(float64(len(sorted)-1)*latencyPercentile)]
+	}
+}
+
+// percentile returns the P99 latency, or zero until enough latencies were measured
+func (t *latencyTracker) percentile() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.p99
+}
+
+// bucketOf returns the bucket (or container) holding obj
+func bucketOf(obj ObjectPointer) string {
+	address := obj.StorageNamespace
+	if obj.IdentifierType == IdentifierTypeFull {
+		address = obj.Identifier
+	}
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return address
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// isBucketFailure returns true if err shows the bucket is failing, rather than the call being wrong
+func isBucketFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, ErrDataNotFound) &&
+		!errors.Is(err, ErrForbidden) &&
+		!errors.Is(err, ErrOperationNotSupported) &&
+		!errors.Is(err, ErrInvalidAddress) &&
+		!errors.Is(err, context.Canceled)
+}
+
+func (a *ResilientAdapter) breaker(bucket string) *circuitBreaker {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b, ok := a.breakers[bucket]
+	if !ok {
+		b = &circuitBreaker{}
+		a.breakers[bucket] = b
+	}
+	return b
+}
+
+func (a *ResilientAdapter) latency(op string) *latencyTracker {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t, ok := a.latencies[op]
+	if !ok {
+		t = &latencyTracker{}
+		a.latencies[op] = t
+	}
+	return t
+}
+
+// call runs fn through the circuit breaker of the bucket of obj
+func (a *ResilientAdapter) call(op string, obj ObjectPointer, fn func() error) error {
+	if a.params.FailureThreshold <= 0 {
+		return fn()
+	}
+	bucket := bucketOf(obj)
+	b := a.breaker(bucket)
+	if !b.allow(a.params.FailureThreshold, time.Now()) {
+		circuitRejectedCounter.WithLabelValues(op).Inc()
+		return fmt.Errorf("%s %s: %w", op, bucket, ErrCircuitOpen)
+	}
+	start := time.Now()
+	err := fn()
+	slow := a.params.SlowCallDuration > 0 && time.Since(start) >= a.params.SlowCallDuration
+	b.record(isBucketFailure(err) || slow, a.params.FailureThreshold, a.params.OpenDuration, time.Now())
+	return err
+}
+
+type hedgeResult[T any] struct {
+	value   T
+	err     error
+	cancel  context.CancelFunc
+	attempt int
+}
+
+// hedge calls read and, when hedged reads are enabled and it runs longer than the P99 latency of op, calls it again
+// and returns the first successful result.  The context of the returned value is canceled by the returned cancel
+// function, values read by other attempts are passed to discard.
+func hedge[T any](ctx context.Context, a *ResilientAdapter, op string, read func(ctx context.Context) (T, error), discard func(T)) (T, context.CancelFunc, error) {
+	tracker := a.latency(op)
+	results := make(chan hedgeResult[T], 2)
+	var cancels []context.CancelFunc
+	start := func(attempt int) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func() {
+			started := time.Now()
+			value, err := read(attemptCtx)
+			if err == nil {
+				tracker.add(time.Since(started))
+			}
+			results <- hedgeResult[T]{value: value, err: err, cancel: cancel, attempt: attempt}
+		}()
+	}
+
+	start(0)
+	if !a.params.HedgedReads {
+		r := <-results
+		return r.value, r.cancel, r.err
+	}
+	timer := time.NewTimer(max(a.params.HedgedReadsMinDelay, tracker.percentile()))
+	defer timer.Stop()
+	pending := 1
+	var (
+		winner  hedgeResult[T]
+		won     bool
+		lastErr error
+	)
+loop:
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			start(1)
+			pending++
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				r.cancel()
+				lastErr = r.err
+				continue
+			}
+			winner, won = r, true
+			break loop
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+			break loop
+		}
+	}
+
+	// stop the attempts still running, and discard what they read
+	for attempt, cancel := range cancels {
+		if !won || attempt != winner.attempt {
+			cancel()
+		}
+	}
+	for ; pending > 0; pending-- {
+		go func() {
+			r := <-results
+			if r.err == nil {
+				discard(r.value)
+			}
+		}()
+	}
+
+	if !won {
+		var zero T
+		return zero, func() {}, lastErr
+	}
+	if len(cancels) > 1 {
+		label := "original"
+		if winner.attempt > 0 {
+			label = "hedged"
+		}
+		hedgedReadsCounter.WithLabelValues(op, label).Inc()
+	}
+	return winner.value, winner.cancel, nil
+}
+
+// cancelReadCloser cancels the context of a read once it is closed
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelReadCloser) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
+
+func closeReader(rc io.ReadCloser) {
+	_ = rc.Close()
+}
+
+// hedgedReader reads an object through hedge, the reader returned keeps its context until closed
+func (a *ResilientAdapter) hedgedReader(ctx context.Context, op string, obj ObjectPointer, read func(ctx context.Context) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := a.call(op, obj, func() error {
+		r, cancel, err := hedge(ctx, a, op, read, closeReader)
+		if err != nil {
+			cancel()
+			return err
+		}
+		rc = &cancelReadCloser{ReadCloser: r, cancel: cancel}
+		return nil
+	})
+	return rc, err
+}
+
+func (a *ResilientAdapter) Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) error {
+	return a.call("Put", obj, func() error {
+		return a.adapter.Put(ctx, obj, sizeBytes, reader, opts)
+	})
+}
+
+func (a *ResilientAdapter) Get(ctx context.Context, obj ObjectPointer) (io.ReadCloser, error) {
+	return a.hedgedReader(ctx, "Get", obj, func(ctx context.Context) (io.ReadCloser, error) {
+		return a.adapter.Get(ctx, obj)
+	})
+}
+
+func (a *ResilientAdapter) GetWalker(uri *url.URL) (Walker, error) {
+	return a.adapter.GetWalker(uri)
+}
+
+func (a *ResilientAdapter) GetPreSignedURL(ctx context.Context, obj ObjectPointer, mode PreSignMode) (string, time.Time, error) {
+	return a.adapter.GetPreSignedURL(ctx, obj, mode)
+}
+
+func (a *ResilientAdapter) GetPresignUploadPartURL(ctx context.Context, obj ObjectPointer, uploadID string, partNumber int) (string, error) {
+	return a.adapter.GetPresignUploadPartURL(ctx, obj, uploadID, partNumber)
+}
+
+func (a *ResilientAdapter) Exists(ctx context.Context, obj ObjectPointer) (bool, error) {
+	const op = "Exists"
+	var exists bool
+	err := a.call(op, obj, func() error {
+		var (
+			cancel context.CancelFunc
+			err    error
+		)
+		exists, cancel, err = hedge(ctx, a, op, func(ctx context.Context) (bool, error) {
+			return a.adapter.Exists(ctx, obj)
+		}, func(bool) {})
+		cancel()
+		return err
+	})
+	return exists, err
+}
+
+func (a *ResilientAdapter) GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	return a.hedgedReader(ctx, "GetRange", obj, func(ctx context.Context) (io.ReadCloser, error) {
+		return a.adapter.GetRange(ctx, obj, startPosition, endPosition)
+	})
+}
+
+func (a *ResilientAdapter) GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error) {
+	const op = "GetProperties"
+	var props Properties
+	err := a.call(op, obj, func() error {
+		var (
+			cancel context.CancelFunc
+			err    error
+		)
+		props, cancel, err = hedge(ctx, a, op, func(ctx context.Context) (Properties, error) {
+			return a.adapter.GetProperties(ctx, obj)
+		}, func(Properties) {})
+		cancel()
+		return err
+	})
+	return props, err
+}
+
+func (a *ResilientAdapter) Remove(ctx context.Context, obj ObjectPointer) error {
+	return a.call("Remove", obj, func() error {
+		return a.adapter.Remove(ctx, obj)
+	})
+}
+
+func (a *ResilientAdapter) Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error {
+	return a.call("Copy", destinationObj, func() error {
+		return a.adapter.Copy(ctx, sourceObj, destinationObj)
+	})
+}
+
+func (a *ResilientAdapter) CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error) {
+	var resp *CreateMultiPartUploadResponse
+	err := a.call("CreateMultiPartUpload", obj, func() error {
+		var err error
+		resp, err = a.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+		return err
+	})
+	return resp, err
+}
+
+func (a *ResilientAdapter) UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	var resp *UploadPartResponse
+	err := a.call("UploadPart", obj, func() error {
+		var err error
+		resp, err = a.adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
+		return err
+	})
+	return resp, err
+}
+
+func (a *ResilientAdapter) ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (*ListPartsResponse, error) {
+	var resp *ListPartsResponse
+	err := a.call("ListParts", obj, func() error {
+		var err error
+		resp, err = a.adapter.ListParts(ctx, obj, uploadID, opts)
+		return err
+	})
+	return resp, err
+}
+
+func (a *ResilientAdapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	var resp *UploadPartResponse
+	err := a.call("UploadCopyPart", destinationObj, func() error {
+		var err error
+		resp, err = a.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+		return err
+	})
+	return resp, err
+}
+
+func (a *ResilientAdapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*UploadPartResponse, error) {
+	var resp *UploadPartResponse
+	err := a.call("UploadCopyPartRange", destinationObj, func() error {
+		var err error
+		resp, err = a.adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
+		return err
+	})
+	return resp, err
+}
+
+func (a *ResilientAdapter) AbortMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string) error {
+	return a.call("AbortMultiPartUpload", obj, func() error {
+		return a.adapter.AbortMultiPartUpload(ctx, obj, uploadID)
+	})
+}
+
+func (a *ResilientAdapter) CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (*CompleteMultiPartUploadResponse, error) {
+	var resp *CompleteMultiPartUploadResponse
+	err := a.call("CompleteMultiPartUpload", obj, func() error {
+		var err error
+		resp, err = a.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+		return err
+	})
+	return resp, err
+}
+
+func (a *ResilientAdapter) BlockstoreType() string {
+	return a.adapter.BlockstoreType()
+}
+
+func (a *ResilientAdapter) BlockstoreMetadata(ctx context.Context) (*BlockstoreMetadata, error) {
+	return a.adapter.BlockstoreMetadata(ctx)
+}
+
+func (a *ResilientAdapter) GetStorageNamespaceInfo() StorageNamespaceInfo {
+	return a.adapter.GetStorageNamespaceInfo()
+}
+
+func (a *ResilientAdapter) ResolveNamespace(storageNamespace, key string, identifierType IdentifierType) (QualifiedKey, error) {
+	return a.adapter.ResolveNamespace(storageNamespace, key, identifierType)
+}
+
+func (a *ResilientAdapter) GetRegion(ctx context.Context, storageNamespace string) (string, error) {
+	return a.adapter.GetRegion(ctx, storageNamespace)
+}
+
+func (a *ResilientAdapter) RuntimeStats() map[string]string {
+	return a.adapter.RuntimeStats()
+}
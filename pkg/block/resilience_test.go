@@ -0,0 +1,110 @@
+package block_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/block/params"
+)
+
+var errBucketDown = errors.New("bucket down")
+
+// flakyAdapter fails reads from failing buckets, and delays the first read by slowFirst
+type flakyAdapter struct {
+	block.Adapter
+	failing   map[string]bool
+	slowFirst time.Duration
+	reads     atomic.Int64
+}
+
+func (a *flakyAdapter) Get(ctx context.Context, obj block.ObjectPointer) (io.ReadCloser, error) {
+	if a.failing[obj.StorageNamespace] {
+		return nil, errBucketDown
+	}
+	if a.reads.Add(1) == 1 && a.slowFirst > 0 {
+		select {
+		case <-time.After(a.slowFirst):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return a.Adapter.Get(ctx, obj)
+}
+
+func TestResilientAdapter_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyAdapter{Adapter: mem.New(ctx), failing: map[string]bool{"mem://down": true}}
+	adapter := block.NewResilientAdapter(inner, params.Resilience{
+		FailureThreshold: 2,
+		OpenDuration:     time.Hour,
+	})
+	up := block.ObjectPointer{StorageNamespace: "mem://up", Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	down := block.ObjectPointer{StorageNamespace: "mem://down", Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	if err := adapter.Put(ctx, up, 4, strings.NewReader("data"), block.PutOpts{}); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := adapter.Get(ctx, down); !errors.Is(err, errBucketDown) {
+			t.Fatalf("Get %d from failing bucket = %v, expected %s", i, err, errBucketDown)
+		}
+	}
+	if _, err := adapter.Get(ctx, down); !errors.Is(err, block.ErrCircuitOpen) {
+		t.Errorf("Get once circuit opens = %v, expected %s", err, block.ErrCircuitOpen)
+	}
+
+	// other buckets are not affected
+	rc, err := adapter.Get(ctx, up)
+	if err != nil {
+		t.Fatalf("Get from another bucket: %s", err)
+	}
+	_ = rc.Close()
+
+	// missing objects do not open the circuit
+	missing := block.ObjectPointer{StorageNamespace: "mem://up", Identifier: "missing", IdentifierType: block.IdentifierTypeRelative}
+	for i := 0; i < 3; i++ {
+		if _, err := adapter.Get(ctx, missing); errors.Is(err, block.ErrCircuitOpen) {
+			t.Fatalf("Get missing object %d = %s", i, err)
+		}
+	}
+}
+
+func TestResilientAdapter_HedgedReads(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyAdapter{Adapter: mem.New(ctx), slowFirst: 10 * time.Second}
+	adapter := block.NewResilientAdapter(inner, params.Resilience{
+		HedgedReads:         true,
+		HedgedReadsMinDelay: 10 * time.Millisecond,
+	})
+	obj := block.ObjectPointer{StorageNamespace: "mem://bucket", Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	if err := adapter.Put(ctx, obj, 4, strings.NewReader("data"), block.PutOpts{}); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	start := time.Now()
+	rc, err := adapter.Get(ctx, obj)
+	if err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	defer func() { _ = rc.Close() }()
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Get took %s, expected the hedged read to answer", took)
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read: %s", err)
+	}
+	if string(data) != "data" {
+		t.Errorf("read %q, expected %q", data, "data")
+	}
+	if reads := inner.reads.Load(); reads != 2 {
+		t.Errorf("got %d reads, expected original and hedged reads", reads)
+	}
+}
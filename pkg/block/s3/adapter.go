@@ -166,9 +166,9 @@ func LoadConfig(ctx context.Context, params params.S3) (aws.Config, error) {
 			),
 		))
 	}
-	if params.MaxRetries > 0 {
-		opts = append(opts, config.WithRetryMaxAttempts(params.MaxRetries))
-	}
+	opts = append(opts, config.WithRetryer(func() aws.Retryer {
+		return newRetryer(params)
+	}))
 	if params.SkipVerifyCertificateTestOnly {
 		tr := &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
@@ -237,11 +237,11 @@ func (a *Adapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes in
 	if opts.StorageClass != nil {
 		putObject.StorageClass = types.StorageClass(*opts.StorageClass)
 	}
-	if a.ServerSideEncryption != "" {
-		putObject.ServerSideEncryption = types.ServerSideEncryption(a.ServerSideEncryption)
-	}
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		putObject.SSEKMSKeyId = aws.String(a.ServerSideEncryptionKmsKeyID)
+	if sse, kmsKeyID := a.serverSideEncryption(ctx); sse != "" {
+		putObject.ServerSideEncryption = types.ServerSideEncryption(sse)
+		if kmsKeyID != "" {
+			putObject.SSEKMSKeyId = aws.String(kmsKeyID)
+		}
 	}
 
 	client := a.clients.Get(ctx, bucket)
@@ -260,6 +260,15 @@ func (a *Adapter) Put(ctx context.Context, obj block.ObjectPointer, sizeBytes in
 	return nil
 }
 
+// serverSideEncryption returns the server side encryption and KMS key id of data written with ctx: SSE-KMS using
+// the KMS key of ctx if it has one, otherwise the configured encryption
+func (a *Adapter) serverSideEncryption(ctx context.Context) (string, string) {
+	if kmsKeyID := block.KMSKeyIDFromContext(ctx); kmsKeyID != "" {
+		return string(types.ServerSideEncryptionAwsKms), kmsKeyID
+	}
+	return a.ServerSideEncryption, a.ServerSideEncryptionKmsKeyID
+}
+
 // retryMaxAttemptsByReader return s3 options function
 // setup RetryMaxAttempts - if the reader is not seekable, we can't retry the request
 func retryMaxAttemptsByReader(reader io.Reader) func(*s3.Options) {
@@ -652,11 +661,11 @@ func (a *Adapter) Copy(ctx context.Context, sourceObj, destinationObj block.Obje
 		Key:        aws.String(destKey),
 		CopySource: aws.String(qualifiedSourceKey.GetStorageNamespace() + "/" + qualifiedSourceKey.GetKey()),
 	}
-	if a.ServerSideEncryption != "" {
-		copyObjectInput.ServerSideEncryption = types.ServerSideEncryption(a.ServerSideEncryption)
-	}
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		copyObjectInput.SSEKMSKeyId = aws.String(a.ServerSideEncryptionKmsKeyID)
+	if sse, kmsKeyID := a.serverSideEncryption(ctx); sse != "" {
+		copyObjectInput.ServerSideEncryption = types.ServerSideEncryption(sse)
+		if kmsKeyID != "" {
+			copyObjectInput.SSEKMSKeyId = aws.String(kmsKeyID)
+		}
 	}
 	_, err = a.clients.Get(ctx, destBucket).CopyObject(ctx, copyObjectInput)
 	if err != nil {
@@ -682,11 +691,11 @@ func (a *Adapter) CreateMultiPartUpload(ctx context.Context, obj block.ObjectPoi
 	if opts.StorageClass != nil {
 		input.StorageClass = types.StorageClass(*opts.StorageClass)
 	}
-	if a.ServerSideEncryption != "" {
-		input.ServerSideEncryption = types.ServerSideEncryption(a.ServerSideEncryption)
-	}
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		input.SSEKMSKeyId = &a.ServerSideEncryptionKmsKeyID
+	if sse, kmsKeyID := a.serverSideEncryption(ctx); sse != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(sse)
+		if kmsKeyID != "" {
+			input.SSEKMSKeyId = aws.String(kmsKeyID)
+		}
 	}
 	client := a.clients.Get(ctx, bucket)
 	resp, err := client.CreateMultipartUpload(ctx, input)
@@ -912,11 +921,11 @@ func (a *Adapter) managerUpload(ctx context.Context, obj block.ObjectPointer, re
 	if opts.StorageClass != nil {
 		input.StorageClass = types.StorageClass(*opts.StorageClass)
 	}
-	if a.ServerSideEncryption != "" {
-		input.ServerSideEncryption = types.ServerSideEncryption(a.ServerSideEncryption)
-	}
-	if a.ServerSideEncryptionKmsKeyID != "" {
-		input.SSEKMSKeyId = aws.String(a.ServerSideEncryptionKmsKeyID)
+	if sse, kmsKeyID := a.serverSideEncryption(ctx); sse != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(sse)
+		if kmsKeyID != "" {
+			input.SSEKMSKeyId = aws.String(kmsKeyID)
+		}
 	}
 
 	output, err := uploader.Upload(ctx, input)
@@ -0,0 +1,64 @@
+package s3
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/params"
+)
+
+// backoffJitterDivider bounds the random reduction of each retry delay to half the delay
+const backoffJitterDivider = 2
+
+// countingRetryer reports each retry it delays to the blockstore retries metric
+type countingRetryer struct {
+	aws.Retryer
+}
+
+func (r *countingRetryer) RetryDelay(attempt int, opErr error) (time.Duration, error) {
+	block.ReportRetry(block.BlockstoreTypeS3)
+	return r.Retryer.RetryDelay(attempt, opErr)
+}
+
+// exponentialBackoff doubles the delay of each attempt starting at initial, up to max, with jitter
+type exponentialBackoff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+func (b *exponentialBackoff) BackoffDelay(attempt int, _ error) (time.Duration, error) {
+	delay := b.initial
+	for i := 1; i < attempt && (b.max <= 0 || delay < b.max); i++ {
+		delay *= 2
+	}
+	if b.max > 0 && delay > b.max {
+		delay = b.max
+	}
+	// randomize between half and the full delay, so clients failing together do not retry together
+	jitter := time.Duration(rand.Int63n(int64(delay/backoffJitterDivider) + 1)) //nolint:gosec
+	return delay - jitter, nil
+}
+
+// newRetryer returns a retryer following the retry policy of params.  MaxAttempts falls back to MaxRetries.
+func newRetryer(params params.S3) aws.Retryer {
+	maxAttempts := params.Retry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = params.MaxRetries
+	}
+	return &countingRetryer{
+		Retryer: retry.NewStandard(func(so *retry.StandardOptions) {
+			if maxAttempts > 0 {
+				so.MaxAttempts = maxAttempts
+			}
+			if params.Retry.MaxBackoff > 0 {
+				so.MaxBackoff = params.Retry.MaxBackoff
+			}
+			if params.Retry.InitialBackoff > 0 {
+				so.Backoff = &exponentialBackoff{initial: params.Retry.InitialBackoff, max: so.MaxBackoff}
+			}
+		}),
+	}
+}
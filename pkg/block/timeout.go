@@ -0,0 +1,213 @@
+package block
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var retriesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "blockstore_retries_total",
+		Help: "retries of failed calls to the blockstore",
+	},
+	[]string{"type"})
+
+var timeoutsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "blockstore_operation_timeouts_total",
+		Help: "calls to the blockstore that did not respond within the operation timeout",
+	},
+	[]string{"type", "operation"})
+
+// ReportRetry counts a retry of a failed call to a blockstore of blockstoreType
+func ReportRetry(blockstoreType string) {
+	retriesCounter.WithLabelValues(blockstoreType).Inc()
+}
+
+// TimeoutAdapter fails calls the blockstore does not respond to within a timeout with ErrOperationTimeout.  Reads
+// are bounded until the blockstore starts returning their content.  Uploads and copies, whose duration depends on
+// the size of the data, are not bounded.
+type TimeoutAdapter struct {
+	adapter Adapter
+	timeout time.Duration
+}
+
+// NewTimeoutAdapter returns adapter with calls bounded by timeout, or adapter if timeout is not positive
+func NewTimeoutAdapter(adapter Adapter, timeout time.Duration) Adapter {
+	if timeout <= 0 {
+		return adapter
+	}
+	return &TimeoutAdapter{adapter: adapter, timeout: timeout}
+}
+
+func (a *TimeoutAdapter) InnerAdapter() Adapter {
+	return a.adapter
+}
+
+// withTimeout calls fn with a context canceled once the timeout passes or cancel is called.  Unless keep is true,
+// cancel is called when fn returns.
+func (a *TimeoutAdapter) withTimeout(ctx context.Context, op string, keep bool, fn func(ctx context.Context) error) (context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(a.timeout, func() {
+		timedOut.Store(true)
+		cancel()
+	})
+	err := fn(ctx)
+	timer.Stop()
+	if err != nil || !keep {
+		cancel()
+	}
+	if err != nil && timedOut.Load() {
+		timeoutsCounter.WithLabelValues(a.adapter.BlockstoreType(), op).Inc()
+		return cancel, fmt.Errorf("%s after %s: %w", op, a.timeout, ErrOperationTimeout)
+	}
+	return cancel, err
+}
+
+func (a *TimeoutAdapter) read(ctx context.Context, op string, fn func(ctx context.Context) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	cancel, err := a.withTimeout(ctx, op, true, func(ctx context.Context) error {
+		var err error
+		rc, err = fn(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cancelReadCloser{ReadCloser: rc, cancel: cancel}, nil
+}
+
+func (a *TimeoutAdapter) Put(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, opts PutOpts) error {
+	return a.adapter.Put(ctx, obj, sizeBytes, reader, opts)
+}
+
+func (a *TimeoutAdapter) Get(ctx context.Context, obj ObjectPointer) (io.ReadCloser, error) {
+	return a.read(ctx, "Get", func(ctx context.Context) (io.ReadCloser, error) {
+		return a.adapter.Get(ctx, obj)
+	})
+}
+
+func (a *TimeoutAdapter) GetWalker(uri *url.URL) (Walker, error) {
+	return a.adapter.GetWalker(uri)
+}
+
+func (a *TimeoutAdapter) GetPreSignedURL(ctx context.Context, obj ObjectPointer, mode PreSignMode) (string, time.Time, error) {
+	return a.adapter.GetPreSignedURL(ctx, obj, mode)
+}
+
+func (a *TimeoutAdapter) GetPresignUploadPartURL(ctx context.Context, obj ObjectPointer, uploadID string, partNumber int) (string, error) {
+	return a.adapter.GetPresignUploadPartURL(ctx, obj, uploadID, partNumber)
+}
+
+func (a *TimeoutAdapter) Exists(ctx context.Context, obj ObjectPointer) (bool, error) {
+	var exists bool
+	_, err := a.withTimeout(ctx, "Exists", false, func(ctx context.Context) error {
+		var err error
+		exists, err = a.adapter.Exists(ctx, obj)
+		return err
+	})
+	return exists, err
+}
+
+func (a *TimeoutAdapter) GetRange(ctx context.Context, obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	return a.read(ctx, "GetRange", func(ctx context.Context) (io.ReadCloser, error) {
+		return a.adapter.GetRange(ctx, obj, startPosition, endPosition)
+	})
+}
+
+func (a *TimeoutAdapter) GetProperties(ctx context.Context, obj ObjectPointer) (Properties, error) {
+	var props Properties
+	_, err := a.withTimeout(ctx, "GetProperties", false, func(ctx context.Context) error {
+		var err error
+		props, err = a.adapter.GetProperties(ctx, obj)
+		return err
+	})
+	return props, err
+}
+
+func (a *TimeoutAdapter) Remove(ctx context.Context, obj ObjectPointer) error {
+	_, err := a.withTimeout(ctx, "Remove", false, func(ctx context.Context) error {
+		return a.adapter.Remove(ctx, obj)
+	})
+	return err
+}
+
+func (a *TimeoutAdapter) Copy(ctx context.Context, sourceObj, destinationObj ObjectPointer) error {
+	return a.adapter.Copy(ctx, sourceObj, destinationObj)
+}
+
+func (a *TimeoutAdapter) CreateMultiPartUpload(ctx context.Context, obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (*CreateMultiPartUploadResponse, error) {
+	var resp *CreateMultiPartUploadResponse
+	_, err := a.withTimeout(ctx, "CreateMultiPartUpload", false, func(ctx context.Context) error {
+		var err error
+		resp, err = a.adapter.CreateMultiPartUpload(ctx, obj, r, opts)
+		return err
+	})
+	return resp, err
+}
+
+func (a *TimeoutAdapter) UploadPart(ctx context.Context, obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	return a.adapter.UploadPart(ctx, obj, sizeBytes, reader, uploadID, partNumber)
+}
+
+func (a *TimeoutAdapter) ListParts(ctx context.Context, obj ObjectPointer, uploadID string, opts ListPartsOpts) (*ListPartsResponse, error) {
+	var resp *ListPartsResponse
+	_, err := a.withTimeout(ctx, "ListParts", false, func(ctx context.Context) error {
+		var err error
+		resp, err = a.adapter.ListParts(ctx, obj, uploadID, opts)
+		return err
+	})
+	return resp, err
+}
+
+func (a *TimeoutAdapter) UploadCopyPart(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int) (*UploadPartResponse, error) {
+	return a.adapter.UploadCopyPart(ctx, sourceObj, destinationObj, uploadID, partNumber)
+}
+
+func (a *TimeoutAdapter) UploadCopyPartRange(ctx context.Context, sourceObj, destinationObj ObjectPointer, uploadID string, partNumber int, startPosition, endPosition int64) (*UploadPartResponse, error) {
+	return a.adapter.UploadCopyPartRange(ctx, sourceObj, destinationObj, uploadID, partNumber, startPosition, endPosition)
+}
+
+func (a *TimeoutAdapter) AbortMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string) error {
+	_, err := a.withTimeout(ctx, "AbortMultiPartUpload", false, func(ctx context.Context) error {
+		return a.adapter.AbortMultiPartUpload(ctx, obj, uploadID)
+	})
+	return err
+}
+
+func (a *TimeoutAdapter) CompleteMultiPartUpload(ctx context.Context, obj ObjectPointer, uploadID string, multipartList *MultipartUploadCompletion) (*CompleteMultiPartUploadResponse, error) {
+	return a.adapter.CompleteMultiPartUpload(ctx, obj, uploadID, multipartList)
+}
+
+func (a *TimeoutAdapter) BlockstoreType() string {
+	return a.adapter.BlockstoreType()
+}
+
+func (a *TimeoutAdapter) BlockstoreMetadata(ctx context.Context) (*BlockstoreMetadata, error) {
+	return a.adapter.BlockstoreMetadata(ctx)
+}
+
+func (a *TimeoutAdapter) GetStorageNamespaceInfo() StorageNamespaceInfo {
+	return a.adapter.GetStorageNamespaceInfo()
+}
+
+func (a *TimeoutAdapter) ResolveNamespace(storageNamespace, key string, identifierType IdentifierType) (QualifiedKey, error) {
+	return a.adapter.ResolveNamespace(storageNamespace, key, identifierType)
+}
+
+func (a *TimeoutAdapter) GetRegion(ctx context.Context, storageNamespace string) (string, error) {
+	return a.adapter.GetRegion(ctx, storageNamespace)
+}
+
+func (a *TimeoutAdapter) RuntimeStats() map[string]string {
+	return a.adapter.RuntimeStats()
+}
@@ -0,0 +1,50 @@
+package block_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+)
+
+func TestTimeoutAdapter(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyAdapter{Adapter: mem.New(ctx), slowFirst: 10 * time.Second}
+	adapter := block.NewTimeoutAdapter(inner, 50*time.Millisecond)
+	obj := block.ObjectPointer{StorageNamespace: "mem://bucket", Identifier: "obj", IdentifierType: block.IdentifierTypeRelative}
+	if err := adapter.Put(ctx, obj, 4, strings.NewReader("data"), block.PutOpts{}); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	start := time.Now()
+	if _, err := adapter.Get(ctx, obj); !errors.Is(err, block.ErrOperationTimeout) {
+		t.Fatalf("Get slow read = %v, expected %s", err, block.ErrOperationTimeout)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Get took %s, expected to time out", took)
+	}
+
+	// the reader stays usable after the timeout passes
+	rc, err := adapter.Get(ctx, obj)
+	if err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	defer func() { _ = rc.Close() }()
+	time.Sleep(100 * time.Millisecond)
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read after timeout passed: %s", err)
+	}
+	if string(data) != "data" {
+		t.Errorf("read %q, expected %q", data, "data")
+	}
+
+	if a := block.NewTimeoutAdapter(inner, 0); a != block.Adapter(inner) {
+		t.Errorf("NewTimeoutAdapter without timeout wrapped the adapter")
+	}
+}
@@ -0,0 +1,85 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// AliasTargetMetadataKey entry metadata key marking an alias entry, holding the path the alias points to
+	AliasTargetMetadataKey = apiutil.LakeFSMetadataPrefix + "alias-target"
+
+	// maxAliasDepth bounds the number of aliases followed when reading an entry
+	maxAliasDepth = 8
+)
+
+// IsAlias reports whether the entry is an alias to another path
+func (e *DBEntry) IsAlias() bool {
+	return e.Metadata[AliasTargetMetadataKey] != ""
+}
+
+// resolveAlias follows alias to the entry it points to, at the same reference. The returned entry keeps the
+// alias path and records the path of the entry it was resolved to, which readers must be allowed to read.
+func (c *Catalog) resolveAlias(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref, alias *DBEntry, stageOnly bool) (*DBEntry, error) {
+	entry := alias
+	for i := 0; i < maxAliasDepth; i++ {
+		target := entry.Metadata[AliasTargetMetadataKey]
+		var err error
+		entry, err = c.getEntry(ctx, repository, ref, target, stageOnly)
+		if errors.Is(err, graveler.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", target, ErrAliasNotFound)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !entry.IsAlias() {
+			entry.Path = alias.Path
+			entry.AliasTarget = target
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", alias.Path, ErrAliasLoop)
+}
+
+// CreateAlias stages an alias entry at path pointing to target on the branch. Reads of the alias at any reference
+// return the entry found at target on that reference, while listings return the alias itself.
+// The alias keeps the physical address of the target at the time it was created, so the data it was created
+// with remains referenced by the repository.
+func (c *Catalog) CreateAlias(ctx context.Context, repositoryID, branch, path, target string, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+		{Name: "target", Value: Path(target), Fn: ValidatePath},
+	}); err != nil {
+		return nil, err
+	}
+	if path == target {
+		return nil, fmt.Errorf("%w: alias points to itself", ErrInvalidAlias)
+	}
+	targetEntry, err := c.GetEntry(ctx, repositoryID, branch, target, GetEntryParams{})
+	if err != nil {
+		return nil, err
+	}
+	entry := NewDBEntryBuilder().
+		Path(path).
+		PhysicalAddress(targetEntry.PhysicalAddress).
+		AddressType(targetEntry.AddressType).
+		CreationDate(time.Now()).
+		Size(targetEntry.Size).
+		Checksum(targetEntry.Checksum).
+		ContentType(targetEntry.ContentType).
+		Metadata(Metadata{AliasTargetMetadataKey: target}).
+		Build()
+	if err := c.CreateEntry(ctx, repositoryID, branch, entry, opts...); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,420 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/stats"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	attributionPartition      = "attribution"
+	attributionReportsPrefix  = "reports"
+	attributionReportIDFormat = "20060102T150405Z"
+
+	// attributionCheckInterval is how often the reporter checks whether the last window was reported
+	attributionCheckInterval = 10 * time.Minute
+	attributionListBatchSize = 1000
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(attributionPartition, attributionReportsPrefix, (&AttributionReportData{}).ProtoReflect().Type())
+}
+
+// AttributionRow is the usage attributed to a user on the branches of a repository sharing a prefix. Storage is
+// attributed to branches only, it is reported on rows with an empty user. Requests on refs that are not branches are
+// reported with an empty branch prefix.
+type AttributionRow struct {
+	Repository   string
+	BranchPrefix string
+	User         string
+	Requests     int64
+	StorageBytes int64
+}
+
+// AttributionReport is the usage attributed over a time window
+type AttributionReport struct {
+	ID              string
+	WindowStart     time.Time
+	WindowEnd       time.Time
+	CreatedAt       time.Time
+	PhysicalAddress string
+	Rows            []AttributionRow
+}
+
+// AttributionReportParams parameters to create a usage attribution report
+type AttributionReportParams struct {
+	WindowStart time.Time
+	WindowEnd   time.Time
+	// Location is the storage location the report Parquet file is written to, e.g. s3://bucket/lakefs/usage/
+	Location string
+	// BranchPrefixDelimiter ends the prefix branches are grouped by, each branch is its own group when empty
+	BranchPrefixDelimiter string
+	// Requests are the request counts of the window
+	Requests []*stats.AttributionRecord
+}
+
+type attributionParquetRow struct {
+	WindowStart  int64  `parquet:"name=window_start, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	WindowEnd    int64  `parquet:"name=window_end, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Repository   string `parquet:"name=repository, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BranchPrefix string `parquet:"name=branch_prefix, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	User         string `parquet:"name=user, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Requests     int64  `parquet:"name=requests, type=INT64, convertedtype=INT_64"`
+	StorageBytes int64  `parquet:"name=storage_bytes, type=INT64, convertedtype=INT_64"`
+}
+
+type attributionRowKey struct {
+	repository   string
+	branchPrefix string
+	user         string
+}
+
+// AttributionReportID returns the ID of the report of the window starting at windowStart
+func AttributionReportID(windowStart time.Time) string {
+	return windowStart.UTC().Format(attributionReportIDFormat)
+}
+
+// branchPrefix returns the prefix of branch up to and including the first delimiter
+func branchPrefix(branch, delimiter string) string {
+	if delimiter == "" {
+		return branch
+	}
+	idx := strings.Index(branch, delimiter)
+	if idx <= 0 {
+		return branch
+	}
+	return branch[:idx+len(delimiter)]
+}
+
+func attributionReportPath(id string) []byte {
+	return []byte(kv.FormatPath(attributionReportsPrefix, id))
+}
+
+func attributionReportFromProto(pb *AttributionReportData) *AttributionReport {
+	report := &AttributionReport{
+		ID:              pb.Id,
+		WindowStart:     time.Unix(0, pb.WindowStart).UTC(),
+		WindowEnd:       time.Unix(0, pb.WindowEnd).UTC(),
+		CreatedAt:       time.Unix(0, pb.CreatedAt).UTC(),
+		PhysicalAddress: pb.PhysicalAddress,
+		Rows:            make([]AttributionRow, 0, len(pb.Rows)),
+	}
+	for _, row := range pb.Rows {
+		report.Rows = append(report.Rows, AttributionRow{
+			Repository:   row.Repository,
+			BranchPrefix: row.BranchPrefix,
+			User:         row.User,
+			Requests:     row.Requests,
+			StorageBytes: row.StorageBytes,
+		})
+	}
+	return report
+}
+
+func protoFromAttributionReport(report *AttributionReport) *AttributionReportData {
+	pb := &AttributionReportData{
+		Id:              report.ID,
+		WindowStart:     report.WindowStart.UnixNano(),
+		WindowEnd:       report.WindowEnd.UnixNano(),
+		CreatedAt:       report.CreatedAt.UnixNano(),
+		PhysicalAddress: report.PhysicalAddress,
+		Rows:            make([]*AttributionRowData, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		pb.Rows = append(pb.Rows, &AttributionRowData{
+			Repository:   row.Repository,
+			BranchPrefix: row.BranchPrefix,
+			User:         row.User,
+			Requests:     row.Requests,
+			StorageBytes: row.StorageBytes,
+		})
+	}
+	return pb
+}
+
+// CreateAttributionReport attributes the storage of all branches and the requests of the window by repository,
+// branch prefix and user. The report is written as Parquet to the location and kept for the summary API. Fails with
+// ErrAttributionReportExists if the window was already reported.
+func (c *Catalog) CreateAttributionReport(ctx context.Context, params AttributionReportParams) (*AttributionReport, error) {
+	if !params.WindowStart.Before(params.WindowEnd) {
+		return nil, fmt.Errorf("window %s-%s: %w", params.WindowStart, params.WindowEnd, ErrInvalidAttributionWindow)
+	}
+	id := AttributionReportID(params.WindowStart)
+	if _, err := c.GetAttributionReport(ctx, id); err == nil {
+		return nil, fmt.Errorf("%s: %w", id, ErrAttributionReportExists)
+	} else if !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+
+	rows := make(map[attributionRowKey]*AttributionRow)
+	row := func(key attributionRowKey) *AttributionRow {
+		r, ok := rows[key]
+		if !ok {
+			r = &AttributionRow{Repository: key.repository, BranchPrefix: key.branchPrefix, User: key.user}
+			rows[key] = r
+		}
+		return r
+	}
+
+	// storage of each branch
+	branches, err := c.attributeStorage(ctx, params.BranchPrefixDelimiter, func(repository, prefix string, size int64) {
+		row(attributionRowKey{repository: repository, branchPrefix: prefix}).StorageBytes += size
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// requests on branches are attributed to their prefix, requests on other refs to no branch
+	for _, rec := range params.Requests {
+		if rec.Hour.Before(params.WindowStart.Truncate(time.Hour)) || !rec.Hour.Before(params.WindowEnd) {
+			continue
+		}
+		prefix := ""
+		if _, ok := branches[rec.Repository][rec.Ref]; ok {
+			prefix = branchPrefix(rec.Ref, params.BranchPrefixDelimiter)
+		}
+		row(attributionRowKey{repository: rec.Repository, branchPrefix: prefix, user: rec.User}).Requests += rec.Count
+	}
+
+	report := &AttributionReport{
+		ID:          id,
+		WindowStart: params.WindowStart.UTC(),
+		WindowEnd:   params.WindowEnd.UTC(),
+		CreatedAt:   time.Now().UTC(),
+		Rows:        make([]AttributionRow, 0, len(rows)),
+	}
+	for _, r := range rows {
+		report.Rows = append(report.Rows, *r)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		if a.BranchPrefix != b.BranchPrefix {
+			return a.BranchPrefix < b.BranchPrefix
+		}
+		return a.User < b.User
+	})
+
+	report.PhysicalAddress, err = c.writeAttributionReport(ctx, params.Location, report)
+	if err != nil {
+		return nil, fmt.Errorf("write attribution report: %w", err)
+	}
+	err = kv.SetMsgIf(ctx, c.KVStore, attributionPartition, attributionReportPath(id), protoFromAttributionReport(report), nil)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s: %w", id, ErrAttributionReportExists)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// attributeStorage calls attribute with the size of every object on every branch, and returns the branches of each
+// repository
+func (c *Catalog) attributeStorage(ctx context.Context, delimiter string, attribute func(repository, prefix string, size int64)) (map[string]map[string]struct{}, error) {
+	repositories, err := c.Store.ListRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer repositories.Close()
+	branches := make(map[string]map[string]struct{})
+	for repositories.Next() {
+		repository := repositories.Value()
+		repositoryBranches, err := c.attributeRepositoryStorage(ctx, repository, delimiter, attribute)
+		if err != nil {
+			return nil, fmt.Errorf("repository %s: %w", repository.RepositoryID, err)
+		}
+		branches[repository.RepositoryID.String()] = repositoryBranches
+	}
+	if err := repositories.Err(); err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+func (c *Catalog) attributeRepositoryStorage(ctx context.Context, repository *graveler.RepositoryRecord, delimiter string, attribute func(repository, prefix string, size int64)) (map[string]struct{}, error) {
+	it, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	branches := make(map[string]struct{})
+	for it.Next() {
+		branchID := it.Value().BranchID
+		branches[branchID.String()] = struct{}{}
+		size, err := c.branchStorageBytes(ctx, repository, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("branch %s: %w", branchID, err)
+		}
+		attribute(repository.RepositoryID.String(), branchPrefix(branchID.String(), delimiter), size)
+	}
+	return branches, it.Err()
+}
+
+func (c *Catalog) branchStorageBytes(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (int64, error) {
+	it, err := c.Store.List(ctx, repository, graveler.Ref(branchID), attributionListBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	var size int64
+	for it.Next() {
+		c.BackgroundLimiter.Take()
+		entry, err := ValueToEntry(it.Value().Value)
+		if err != nil {
+			return 0, err
+		}
+		size += entry.Size
+	}
+	return size, it.Err()
+}
+
+func (c *Catalog) writeAttributionReport(ctx context.Context, location string, report *AttributionReport) (string, error) {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(attributionParquetRow), gcParquetParallelNum)
+	if err != nil {
+		return "", err
+	}
+	pw.CompressionType = parquet.CompressionCodec_GZIP
+	for _, row := range report.Rows {
+		err := pw.Write(attributionParquetRow{
+			WindowStart:  report.WindowStart.UnixMilli(),
+			WindowEnd:    report.WindowEnd.UnixMilli(),
+			Repository:   row.Repository,
+			BranchPrefix: row.BranchPrefix,
+			User:         row.User,
+			Requests:     row.Requests,
+			StorageBytes: row.StorageBytes,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return "", err
+	}
+
+	name := "attribution-" + report.ID + ".parquet"
+	obj := block.ObjectPointer{
+		StorageNamespace: location,
+		Identifier:       name,
+		IdentifierType:   block.IdentifierTypeRelative,
+	}
+	if err := c.BlockAdapter.Put(ctx, obj, int64(buf.Len()), &buf, block.PutOpts{}); err != nil {
+		return "", err
+	}
+	return url.JoinPath(location, name)
+}
+
+// GetAttributionReport returns the usage attribution report with its rows
+func (c *Catalog) GetAttributionReport(ctx context.Context, id string) (*AttributionReport, error) {
+	data := &AttributionReportData{}
+	_, err := kv.GetMsg(ctx, c.KVStore, attributionPartition, attributionReportPath(id), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", id, ErrAttributionReportNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attributionReportFromProto(data), nil
+}
+
+// ListAttributionReports lists usage attribution reports by window start, without their rows
+func (c *Catalog) ListAttributionReports(ctx context.Context, limit int, after string) ([]*AttributionReport, bool, error) {
+	if limit < 0 || limit > ListAttributionReportsLimitMax {
+		limit = ListAttributionReportsLimitMax
+	}
+	var afterKey []byte
+	if after != "" {
+		afterKey = attributionReportPath(after)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&AttributionReportData{}).ProtoReflect().Type(), attributionPartition,
+		[]byte(attributionReportsPrefix+kv.PathDelimiter), kv.IteratorOptionsAfter(afterKey))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var reports []*AttributionReport
+	for it.Next() {
+		if len(reports) == limit {
+			return reports, true, nil
+		}
+		report := attributionReportFromProto(it.Entry().Value.(*AttributionReportData))
+		report.Rows = nil
+		reports = append(reports, report)
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return reports, false, nil
+}
+
+// AttributionReportsConfig configures periodic usage attribution reports
+type AttributionReportsConfig struct {
+	Interval              time.Duration
+	Location              string
+	BranchPrefixDelimiter string
+}
+
+// StartAttributionReports creates a report for each window of the configured interval once it ends, until ctx is
+// done. Windows are aligned to the interval in UTC, a window is reported once across all lakeFS servers.
+func (c *Catalog) StartAttributionReports(ctx context.Context, recorder *stats.AttributionRecorder, cfg AttributionReportsConfig, logger logging.Logger) {
+	go func() {
+		ticker := time.NewTicker(attributionCheckInterval)
+		defer ticker.Stop()
+		for {
+			windowEnd := time.Now().UTC().Truncate(cfg.Interval)
+			windowStart := windowEnd.Add(-cfg.Interval)
+			if err := c.reportAttributionWindow(ctx, recorder, cfg, windowStart, windowEnd); err != nil {
+				logger.WithError(err).WithField("window_start", windowStart).Error("Failed to create usage attribution report")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *Catalog) reportAttributionWindow(ctx context.Context, recorder *stats.AttributionRecorder, cfg AttributionReportsConfig, windowStart, windowEnd time.Time) error {
+	_, err := c.GetAttributionReport(ctx, AttributionReportID(windowStart))
+	if err == nil {
+		// already reported
+		return nil
+	}
+	if !errors.Is(err, graveler.ErrNotFound) {
+		return err
+	}
+	requests, err := recorder.Records(ctx, windowStart, windowEnd)
+	if err != nil {
+		return err
+	}
+	_, err = c.CreateAttributionReport(ctx, AttributionReportParams{
+		WindowStart:           windowStart,
+		WindowEnd:             windowEnd,
+		Location:              cfg.Location,
+		BranchPrefixDelimiter: cfg.BranchPrefixDelimiter,
+		Requests:              requests,
+	})
+	if errors.Is(err, ErrAttributionReportExists) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/stats"
+	"go.uber.org/ratelimit"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCatalog_AttributionReports(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := &catalog.Catalog{
+		Store: &catalog.FakeGraveler{
+			RepositoryIteratorFactory: catalog.NewFakeRepositoryIteratorFactory([]*graveler.RepositoryRecord{
+				{RepositoryID: "repo1", Repository: &graveler.Repository{StorageNamespace: "mem://repo1"}},
+			}),
+			BranchIteratorFactory: gUtils.NewFakeBranchIteratorFactory([]*graveler.BranchRecord{
+				{BranchID: "feature-a", Branch: &graveler.Branch{}},
+				{BranchID: "feature-b", Branch: &graveler.Branch{}},
+				{BranchID: "main", Branch: &graveler.Branch{}},
+			}),
+			// every branch lists the same objects
+			ListIteratorFactory: catalog.NewFakeValueIteratorFactory([]*graveler.ValueRecord{
+				{Key: graveler.Key("file1"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "file1", LastModified: timestamppb.New(now), Size: 10, ETag: "01"})},
+				{Key: graveler.Key("file2"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "file2", LastModified: timestamppb.New(now), Size: 5, ETag: "02"})},
+			}),
+		},
+		BlockAdapter:      mem.New(ctx),
+		BackgroundLimiter: ratelimit.NewUnlimited(),
+		KVStore:           kvtest.GetStore(ctx, t),
+	}
+
+	windowStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	windowEnd := windowStart.Add(24 * time.Hour)
+	request := func(ref, user string, hour time.Time, count int64) *stats.AttributionRecord {
+		return &stats.AttributionRecord{
+			AttributionKey: stats.AttributionKey{Repository: "repo1", Ref: ref, User: user},
+			Hour:           hour,
+			Count:          count,
+		}
+	}
+	params := catalog.AttributionReportParams{
+		WindowStart:           windowStart,
+		WindowEnd:             windowEnd,
+		Location:              "mem://reports",
+		BranchPrefixDelimiter: "-",
+		Requests: []*stats.AttributionRecord{
+			request("feature-a", "alice", windowStart, 3),
+			request("feature-b", "alice", windowStart.Add(time.Hour), 2),
+			request("main", "bob", windowStart, 1),
+			request("v1.0", "bob", windowStart, 4),
+			// outside the window
+			request("main", "bob", windowEnd, 100),
+		},
+	}
+
+	report, err := c.CreateAttributionReport(ctx, params)
+	require.NoError(t, err)
+	require.Equal(t, catalog.AttributionReportID(windowStart), report.ID)
+	require.Equal(t, "mem://reports/attribution-"+report.ID+".parquet", report.PhysicalAddress)
+	require.Equal(t, []catalog.AttributionRow{
+		{Repository: "repo1", BranchPrefix: "", User: "bob", Requests: 4},
+		{Repository: "repo1", BranchPrefix: "feature-", User: "", StorageBytes: 30},
+		{Repository: "repo1", BranchPrefix: "feature-", User: "alice", Requests: 5},
+		{Repository: "repo1", BranchPrefix: "main", User: "", StorageBytes: 15},
+		{Repository: "repo1", BranchPrefix: "main", User: "bob", Requests: 1},
+	}, report.Rows)
+
+	// a window is reported once
+	_, err = c.CreateAttributionReport(ctx, params)
+	require.ErrorIs(t, err, catalog.ErrAttributionReportExists)
+
+	got, err := c.GetAttributionReport(ctx, report.ID)
+	require.NoError(t, err)
+	require.Equal(t, report.Rows, got.Rows)
+	require.True(t, report.WindowEnd.Equal(got.WindowEnd))
+
+	_, err = c.GetAttributionReport(ctx, catalog.AttributionReportID(windowEnd))
+	require.ErrorIs(t, err, graveler.ErrNotFound)
+
+	reports, hasMore, err := c.ListAttributionReports(ctx, -1, "")
+	require.NoError(t, err)
+	require.False(t, hasMore)
+	require.Len(t, reports, 1)
+	require.Equal(t, report.ID, reports[0].ID)
+	require.Nil(t, reports[0].Rows)
+}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
@@ -26,6 +27,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/batch"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/block/factory"
+	"github.com/treeverse/lakefs/pkg/cache"
 	"github.com/treeverse/lakefs/pkg/config"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/graveler/branch"
@@ -71,6 +73,9 @@ const (
 
 	DumpRefsTaskIDPrefix    = "DR"
 	RestoreRefsTaskIDPrefix = "RR"
+	TagObjectsTaskIDPrefix  = "TO"
+	MergeTaskIDPrefix       = "MG"
+	LocalGCTaskIDPrefix     = "GC"
 
 	TaskExpiryTime = 24 * time.Hour
 
@@ -202,6 +207,8 @@ type ExpiryRows interface {
 type GetEntryParams struct {
 	// StageOnly when true will return entry found on stage without checking committed data
 	StageOnly bool
+	// NoFollowAlias when true will return an alias entry itself, instead of the entry it points to
+	NoFollowAlias bool
 }
 
 type WriteRangeRequest struct {
@@ -228,6 +235,7 @@ type Catalog struct {
 	workPool              *pond.WorkerPool
 	PathProvider          *upload.PathPartitionProvider
 	BackgroundLimiter     ratelimit.Limiter
+	backgroundLimiter     *backgroundLimiter
 	KVStore               kv.Store
 	KVStoreLimited        kv.Store
 	addressProvider       *ident.HexAddressProvider
@@ -235,17 +243,39 @@ type Catalog struct {
 	UGCPrepareMaxFileSize int64
 	UGCPrepareInterval    time.Duration
 	signingKey            config.SecureString
+	// blockstoreEncryption is the encryption used by the block adapter when writing data
+	blockstoreEncryption EncryptionContext
+	// restrictedEncryptionKeys are KMS keys whose data may be placed only on branches all of whose readers may use them
+	restrictedEncryptionKeys map[string]struct{}
+	encryptionKeyAccess      EncryptionKeyAccess
+	// objectAccess counts reads of objects, nil when object access tracking is disabled
+	objectAccess *objectAccessTracker
+	// directoryMarkersCache caches whether each repository persists directory markers
+	directoryMarkersCache cache.Cache
+	// rangeStatsCache caches the PrefixTreeStats of ranges by their ID
+	rangeStatsCache cache.Cache
+	// blockStoragePrefix is the path of range and metarange files in storage namespaces
+	blockStoragePrefix string
+	// operations holds the *runningOperation of background tasks running on this instance by ID
+	operations sync.Map
+	// priorities limit background jobs by their priority class
+	priorities *backgroundPriorities
+	// kmsKeyResolver resolves the KMS keys of the encryption domains of repositories, cached
+	kmsKeyResolver block.KMSKeyResolver
 }
 
 const (
-	ListRepositoriesLimitMax = 1000
-	ListBranchesLimitMax     = 1000
-	ListTagsLimitMax         = 1000
-	DiffLimitMax             = 1000
-	ListEntriesLimitMax      = 10000
-	sharedWorkers            = 30
-	pendingTasksPerWorker    = 3
-	workersMaxDrainDuration  = 5 * time.Second
+	ListRepositoriesLimitMax       = 1000
+	ListBranchesLimitMax           = 1000
+	ListTagsLimitMax               = 1000
+	DiffLimitMax                   = 1000
+	ListEntriesLimitMax            = 10000
+	ListAttributionReportsLimitMax = 1000
+	ListObjectAccessLimitMax       = 1000
+	ListDanglingCommitsLimitMax    = 1000
+	sharedWorkers                  = 30
+	pendingTasksPerWorker          = 3
+	workersMaxDrainDuration        = 5 * time.Second
 )
 
 type ImportPathType string
@@ -299,6 +329,8 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		cancelFn()
 		return nil, fmt.Errorf("build block adapter: %w", err)
 	}
+	kmsKeyResolver := newKMSKeyResolver(cfg.KVStore)
+	adapter = block.NewEncryptionDomainAdapter(adapter, kmsKeyResolver)
 	if cfg.WalkerFactory == nil {
 		cfg.WalkerFactory = store.NewFactory(cfg.Config)
 	}
@@ -331,14 +363,24 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 	pebbleSSTableCache := pebble.NewCache(tierFSParams.PebbleSSTableCacheSizeBytes)
 	defer pebbleSSTableCache.Unref()
 
-	sstableManager := sstable.NewPebbleSSTableRangeManager(pebbleSSTableCache, rangeFS, hashAlg)
-	sstableMetaManager := sstable.NewPebbleSSTableRangeManager(pebbleSSTableCache, metaRangeFS, hashAlg)
+	// bloom filters only help point lookups in ranges, metaranges are only scanned
+	rangeWriterConfig := sstable.WriterConfig{
+		Format:                sstable.FormatVersion(cfg.Config.Committed.SSTable.FormatVersion),
+		BloomFilterBitsPerKey: cfg.Config.Committed.SSTable.BloomFilterBitsPerKey,
+	}
+	metaRangeWriterConfig := sstable.WriterConfig{
+		Format: rangeWriterConfig.Format,
+	}
+	sstableManager := sstable.NewPebbleSSTableRangeManager(pebbleSSTableCache, rangeFS, hashAlg, rangeWriterConfig, cfg.Config.Committed.RangePrefetch.Concurrency)
+	sstableMetaManager := sstable.NewPebbleSSTableRangeManager(pebbleSSTableCache, metaRangeFS, hashAlg, metaRangeWriterConfig, 0)
 
 	committedParams := committed.Params{
 		MinRangeSizeBytes:          cfg.Config.Committed.Permanent.MinRangeSizeBytes,
 		MaxRangeSizeBytes:          cfg.Config.Committed.Permanent.MaxRangeSizeBytes,
 		RangeSizeEntriesRaggedness: cfg.Config.Committed.Permanent.RangeRaggednessEntries,
 		MaxUploaders:               cfg.Config.Committed.LocalCache.MaxUploadersPerWriter,
+		KeyFilterCacheSize:         cfg.Config.Committed.KeyFilter.CacheSize,
+		ReadAheadRanges:            cfg.Config.Committed.RangePrefetch.Ranges,
 	}
 	sstableMetaRangeManager, err := committed.NewMetaRangeManager(
 		committedParams,
@@ -350,7 +392,10 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		cancelFn()
 		return nil, fmt.Errorf("create SSTable-based metarange manager: %w", err)
 	}
-	committedManager := committed.NewCommittedManager(sstableMetaRangeManager, sstableManager, committedParams)
+	// metarange key filters are stored alongside ranges
+	committedManager := committed.NewCommittedManager(sstableMetaRangeManager, sstableManager, sstableManager, committedParams)
+
+	encryptionAlgorithm, encryptionKMSKeyID := cfg.Config.BlockstoreEncryption()
 
 	executor := batch.NewConditionalExecutor(logging.ContextUnavailable())
 	go executor.Run(ctx)
@@ -362,13 +407,17 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 	addressProvider := ident.NewHexAddressProvider()
 	refManager := ref.NewRefManager(
 		ref.ManagerConfig{
-			Executor:              executor,
-			KVStore:               cfg.KVStore,
-			KVStoreLimited:        storeLimiter,
-			AddressProvider:       addressProvider,
-			RepositoryCacheConfig: ref.CacheConfig(cfg.Config.Graveler.RepositoryCache),
-			CommitCacheConfig:     ref.CacheConfig(cfg.Config.Graveler.CommitCache),
-			MaxBatchDelay:         cfg.Config.Graveler.MaxBatchDelay,
+			Executor:               executor,
+			KVStore:                cfg.KVStore,
+			KVStoreLimited:         storeLimiter,
+			AddressProvider:        addressProvider,
+			RepositoryCacheConfig:  ref.CacheConfig(cfg.Config.Graveler.RepositoryCache),
+			CommitCacheConfig:      ref.CacheConfig(cfg.Config.Graveler.CommitCache),
+			MaxBatchDelay:          cfg.Config.Graveler.MaxBatchDelay,
+			ArchiveGracePeriod:     cfg.Config.Graveler.RepositoryArchive.GracePeriod,
+			BranchHistoryRetention: cfg.Config.Graveler.BranchHistory.Retention,
+			BranchHistoryIntegrity: cfg.Config.Graveler.BranchHistory.Integrity.Enabled,
+			RefTrashRetention:      cfg.Config.Graveler.RefTrash.Retention,
 		})
 	gcManager := retention.NewGarbageCollectionManager(tierFSParams.Adapter, refManager, cfg.Config.Committed.BlockStoragePrefix)
 	settingManager := settings.NewManager(refManager, cfg.KVStore)
@@ -391,17 +440,29 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		deleteSensor = graveler.NewDeleteSensor(cfg.Config.Graveler.CompactionSensorThreshold, cb)
 	}
 	gStore := graveler.NewGraveler(committedManager, stagingManager, refManager, gcManager, protectedBranchesManager, deleteSensor)
+	priorities := newBackgroundPriorities(cfg.Config)
+	if cfg.Config.Graveler.StagingCompaction.Enabled {
+		compactor := graveler.NewStagingCompactor(gStore, graveler.StagingCompactorConfig{
+			Interval:         cfg.Config.Graveler.StagingCompaction.Interval,
+			MinStagedEntries: cfg.Config.Graveler.StagingCompaction.MinStagedEntries,
+			Acquire: func(ctx context.Context) (context.Context, func(), error) {
+				return priorities.acquire(ctx, OperationKindStagingCompaction)
+			},
+		})
+		go compactor.Run(ctx)
+	}
 
 	// The size of the workPool is determined by the number of workers and the number of desired pending tasks for each worker.
 	workPool := pond.New(sharedWorkers, sharedWorkers*pendingTasksPerWorker, pond.Context(ctx))
 
-	return &Catalog{
+	c := &Catalog{
 		BlockAdapter:          tierFSParams.Adapter,
 		Store:                 gStore,
 		UGCPrepareMaxFileSize: cfg.Config.UGC.PrepareMaxFileSize,
 		UGCPrepareInterval:    cfg.Config.UGC.PrepareInterval,
 		PathProvider:          cfg.PathProvider,
 		BackgroundLimiter:     limiter,
+		backgroundLimiter:     limiter,
 		walkerFactory:         cfg.WalkerFactory,
 		workPool:              workPool,
 		KVStore:               cfg.KVStore,
@@ -410,17 +471,28 @@ func New(ctx context.Context, cfg Config) (*Catalog, error) {
 		addressProvider:       addressProvider,
 		deleteSensor:          deleteSensor,
 		signingKey:            cfg.Config.Blockstore.Signing.SecretKey,
-	}, nil
+		blockstoreEncryption: EncryptionContext{
+			Algorithm: encryptionAlgorithm,
+			KMSKeyID:  encryptionKMSKeyID,
+		},
+		restrictedEncryptionKeys: newRestrictedEncryptionKeys(cfg.Config),
+		directoryMarkersCache:    newDirectoryMarkersCache(cfg.Config),
+		rangeStatsCache:          newRangeStatsCache(),
+		blockStoragePrefix:       cfg.Config.Committed.BlockStoragePrefix,
+		priorities:               priorities,
+		kmsKeyResolver:           kmsKeyResolver,
+	}
+	if cfg.Config.ObjectAccess.Enabled {
+		c.objectAccess = newObjectAccessTracker(cfg.Config.ObjectAccess.SampleRate, cfg.Config.ObjectAccess.MaxPendingRecords)
+		c.startObjectAccessFlush(ctx, cfg.Config.ObjectAccess.FlushInterval)
+	}
+	return c, nil
 }
 
-func newLimiter(rateLimit int) ratelimit.Limiter {
-	var limiter ratelimit.Limiter
-	if rateLimit == 0 {
-		limiter = ratelimit.NewUnlimited()
-	} else {
-		limiter = ratelimit.New(rateLimit)
-	}
-	return limiter
+// SetBackgroundRateLimit replaces the rate limit of background operations, in operations per second. Zero means
+// unlimited.
+func (c *Catalog) SetBackgroundRateLimit(rateLimit int) {
+	c.backgroundLimiter.SetRate(rateLimit)
 }
 
 func (c *Catalog) SetHooksHandler(hooks graveler.HooksHandler) {
@@ -442,6 +514,9 @@ func (c *Catalog) CreateRepository(ctx context.Context, repository string, stora
 	}); err != nil {
 		return nil, err
 	}
+	if err := c.checkProjectQuota(ctx, repositoryID); err != nil {
+		return nil, err
+	}
 	repo, err := c.Store.CreateRepository(ctx, repositoryID, storageNS, branchID, readOnly)
 	if err != nil {
 		return nil, err
@@ -468,6 +543,9 @@ func (c *Catalog) CreateBareRepository(ctx context.Context, repository string, s
 	}); err != nil {
 		return nil, err
 	}
+	if err := c.checkProjectQuota(ctx, repositoryID); err != nil {
+		return nil, err
+	}
 	repo, err := c.Store.CreateBareRepository(ctx, repositoryID, storageNS, branchID, readOnly)
 	if err != nil {
 		return nil, err
@@ -506,11 +584,12 @@ func (c *Catalog) GetRepository(ctx context.Context, repository string) (*Reposi
 		DefaultBranch:    repo.DefaultBranchID.String(),
 		CreationDate:     repo.CreationDate,
 		ReadOnly:         repo.ReadOnly,
+		ArchivedAt:       repo.ArchivedAt,
 	}
 	return catalogRepository, nil
 }
 
-// DeleteRepository delete a repository
+// DeleteRepository delete a repository. Archived repositories are deleted only after the archive grace period.
 func (c *Catalog) DeleteRepository(ctx context.Context, repository string, opts ...graveler.SetOptionsFunc) error {
 	repositoryID := graveler.RepositoryID(repository)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -518,7 +597,36 @@ func (c *Catalog) DeleteRepository(ctx context.Context, repository string, opts
 	}); err != nil {
 		return err
 	}
-	return c.Store.DeleteRepository(ctx, repositoryID, opts...)
+	if err := c.Store.DeleteRepository(ctx, repositoryID, opts...); err != nil {
+		return err
+	}
+	if err := c.deleteObjectAccess(ctx, repository); err != nil {
+		c.log(ctx).WithError(err).WithField("repository", repository).Warn("Failed to delete object access records")
+	}
+	return nil
+}
+
+// ArchiveRepository archives a repository: it is read-only and hidden from repository listings, keeping all of its
+// data until it is restored or deleted
+func (c *Catalog) ArchiveRepository(ctx context.Context, repository string) error {
+	repositoryID := graveler.RepositoryID(repository)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return err
+	}
+	return c.Store.ArchiveRepository(ctx, repositoryID)
+}
+
+// RestoreRepository restores an archived repository
+func (c *Catalog) RestoreRepository(ctx context.Context, repository string) error {
+	repositoryID := graveler.RepositoryID(repository)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return err
+	}
+	return c.Store.RestoreRepository(ctx, repositoryID)
 }
 
 // GetRepositoryMetadata get repository metadata
@@ -570,8 +678,18 @@ func (c *Catalog) DeleteRepositoryMetadata(ctx context.Context, repository strin
 }
 
 // ListRepositories list repository information, the bool returned is true when more repositories can be listed.
-// In this case, pass the last repository name as 'after' on the next call to ListRepositories
+// In this case, pass the last repository name as 'after' on the next call to ListRepositories. Archived repositories
+// are not listed.
 func (c *Catalog) ListRepositories(ctx context.Context, limit int, prefix, after string) ([]*Repository, bool, error) {
+	return c.listRepositories(ctx, limit, prefix, after, false)
+}
+
+// ListArchivedRepositories lists archived repositories, as ListRepositories lists the others
+func (c *Catalog) ListArchivedRepositories(ctx context.Context, limit int, prefix, after string) ([]*Repository, bool, error) {
+	return c.listRepositories(ctx, limit, prefix, after, true)
+}
+
+func (c *Catalog) listRepositories(ctx context.Context, limit int, prefix, after string, archived bool) ([]*Repository, bool, error) {
 	// normalize limit
 	if limit < 0 || limit > ListRepositoriesLimitMax {
 		limit = ListRepositoriesLimitMax
@@ -604,12 +722,17 @@ func (c *Catalog) ListRepositories(ctx context.Context, limit int, prefix, after
 		if record.RepositoryID == afterRepositoryID {
 			continue
 		}
+		if (record.State == graveler.RepositoryState_ARCHIVED) != archived {
+			continue
+		}
 		repos = append(repos, &Repository{
 			Name:             record.RepositoryID.String(),
 			StorageNamespace: record.StorageNamespace.String(),
 			DefaultBranch:    record.DefaultBranchID.String(),
 			CreationDate:     record.CreationDate,
-			ReadOnly:         record.ReadOnly,
+			// archived repositories are read-only
+			ReadOnly:   record.ReadOnly || archived,
+			ArchivedAt: record.ArchivedAt,
 		})
 		// collect limit +1 to return limit and has more
 		if len(repos) >= limit+1 {
@@ -714,6 +837,29 @@ func (c *Catalog) DeleteBranch(ctx context.Context, repositoryID string, branch
 	return c.Store.DeleteBranch(ctx, repository, branchID, opts...)
 }
 
+// RestoreBranch creates a recently deleted branch again, pointing to the commit it pointed to when it was deleted
+func (c *Catalog) RestoreBranch(ctx context.Context, repositoryID string, branch string, opts ...graveler.SetOptionsFunc) (*Branch, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "name", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := c.Store.RestoreBranch(ctx, repository, branchID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Branch{
+		Name:      branch,
+		Reference: restored.CommitID.String(),
+	}, nil
+}
+
 func (c *Catalog) ListBranches(ctx context.Context, repositoryID string, prefix string, limit int, after string) ([]*Branch, bool, error) {
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
@@ -894,6 +1040,57 @@ func (c *Catalog) DeleteTag(ctx context.Context, repositoryID string, tagID stri
 	return c.Store.DeleteTag(ctx, repository, tag, opts...)
 }
 
+// RestoreTag creates a recently deleted tag again and returns the commit ID it points to
+func (c *Catalog) RestoreTag(ctx context.Context, repositoryID string, tagID string, opts ...graveler.SetOptionsFunc) (string, error) {
+	tag := graveler.TagID(tagID)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "name", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "tagID", Value: tag, Fn: graveler.ValidateTagID},
+	}); err != nil {
+		return "", err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	commitID, err := c.Store.RestoreTag(ctx, repository, tag, opts...)
+	if err != nil {
+		return "", err
+	}
+	return commitID.String(), nil
+}
+
+// ListDeletedRefs lists the deleted branches and tags that can be restored, most recently deleted first
+func (c *Catalog) ListDeletedRefs(ctx context.Context, repositoryID string) ([]*DeletedRef, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "name", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	deletedRefs, err := c.Store.ListDeletedRefs(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]*DeletedRef, 0, len(deletedRefs))
+	for _, r := range deletedRefs {
+		refType := DeletedRefTypeBranch
+		if r.Type == graveler.ReferenceTypeTag {
+			refType = DeletedRefTypeTag
+		}
+		refs = append(refs, &DeletedRef{
+			Type:      refType,
+			ID:        r.ID,
+			CommitID:  r.CommitID.String(),
+			DeletedAt: r.DeletedAt,
+		})
+	}
+	return refs, nil
+}
+
 func (c *Catalog) ListTags(ctx context.Context, repositoryID string, prefix string, limit int, after string) ([]*Tag, bool, error) {
 	if limit < 0 || limit > ListTagsLimitMax {
 		limit = ListTagsLimitMax
@@ -983,7 +1180,47 @@ func (c *Catalog) GetEntry(ctx context.Context, repositoryID string, reference s
 	if err != nil {
 		return nil, err
 	}
-	val, err := c.Store.Get(ctx, repository, refToGet, graveler.Key(path), graveler.WithStageOnly(params.StageOnly))
+	catalogEntry, err := c.getEntry(ctx, repository, refToGet, path, params.StageOnly)
+	if err != nil {
+		return nil, err
+	}
+	if !params.NoFollowAlias && catalogEntry.IsAlias() {
+		return c.resolveAlias(ctx, repository, refToGet, catalogEntry, params.StageOnly)
+	}
+	return catalogEntry, nil
+}
+
+// FindObjectInRefs returns the refs out of refs in which path exists
+func (c *Catalog) FindObjectInRefs(ctx context.Context, repositoryID string, path string, refs []string) ([]string, error) {
+	args := []validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}
+	gravelerRefs := make([]graveler.Ref, len(refs))
+	for i, ref := range refs {
+		gravelerRefs[i] = graveler.Ref(ref)
+		args = append(args, validator.ValidateArg{Name: "ref", Value: gravelerRefs[i], Fn: graveler.ValidateRef})
+	}
+	if err := validator.Validate(args); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	found, err := c.Store.FindKeyInRefs(ctx, repository, graveler.Key(path), gravelerRefs)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, len(found))
+	for i, ref := range found {
+		res[i] = ref.String()
+	}
+	return res, nil
+}
+
+func (c *Catalog) getEntry(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref, path string, stageOnly bool) (*DBEntry, error) {
+	val, err := c.Store.Get(ctx, repository, ref, graveler.Key(path), graveler.WithStageOnly(stageOnly))
 	if err != nil {
 		return nil, err
 	}
@@ -1036,7 +1273,6 @@ func addressTypeToCatalog(t Entry_AddressType) AddressType {
 
 func (c *Catalog) CreateEntry(ctx context.Context, repositoryID string, branch string, entry DBEntry, opts ...graveler.SetOptionsFunc) error {
 	branchID := graveler.BranchID(branch)
-	ent := newEntryFromCatalogEntry(entry)
 	path := Path(entry.Path)
 	if err := validator.Validate([]validator.ValidateArg{
 		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
@@ -1045,12 +1281,16 @@ func (c *Catalog) CreateEntry(ctx context.Context, repositoryID string, branch s
 	}); err != nil {
 		return err
 	}
-	key := graveler.Key(path)
-	value, err := EntryToValue(ent)
+	repository, err := c.getRepository(ctx, repositoryID)
 	if err != nil {
 		return err
 	}
-	repository, err := c.getRepository(ctx, repositoryID)
+	if err := c.normalizeDirectoryMarker(ctx, repository, &entry); err != nil {
+		return err
+	}
+	ent := newEntryFromCatalogEntry(entry)
+	key := graveler.Key(path)
+	value, err := EntryToValue(ent)
 	if err != nil {
 		return err
 	}
@@ -1071,8 +1311,15 @@ func (c *Catalog) DeleteEntry(ctx context.Context, repositoryID string, branch s
 	if err != nil {
 		return err
 	}
+	removed, err := c.existingPaths(ctx, repository, branchID, []string{path})
+	if err != nil {
+		return err
+	}
 	key := graveler.Key(p)
-	return c.Store.Delete(ctx, repository, branchID, key, opts...)
+	if err := c.Store.Delete(ctx, repository, branchID, key, opts...); err != nil {
+		return err
+	}
+	return c.keepParentDirectories(ctx, repository, branchID, removed, opts...)
 }
 
 func (c *Catalog) DeleteEntries(ctx context.Context, repositoryID string, branch string, paths []string, opts ...graveler.SetOptionsFunc) error {
@@ -1096,11 +1343,18 @@ func (c *Catalog) DeleteEntries(ctx context.Context, repositoryID string, branch
 		return err
 	}
 
+	removed, err := c.existingPaths(ctx, repository, branchID, paths)
+	if err != nil {
+		return err
+	}
 	keys := make([]graveler.Key, len(paths))
 	for i := range paths {
 		keys[i] = graveler.Key(paths[i])
 	}
-	return c.Store.DeleteBatch(ctx, repository, branchID, keys, opts...)
+	if err := c.Store.DeleteBatch(ctx, repository, branchID, keys, opts...); err != nil {
+		return err
+	}
+	return c.keepParentDirectories(ctx, repository, branchID, removed, opts...)
 }
 
 func (c *Catalog) ListEntries(ctx context.Context, repositoryID string, reference string, prefix string, after string, delimiter string, limit int) ([]*DBEntry, bool, error) {
@@ -1132,7 +1386,8 @@ func (c *Catalog) ListEntries(ctx context.Context, repositoryID string, referenc
 	defer it.Close()
 
 	if afterPath != "" {
-		it.SeekGE(afterPath)
+		// seek past after itself: a common prefix containing after is listed only when it has keys after it
+		it.SeekGE(afterPath + "\x00")
 	}
 
 	var entries []*DBEntry
@@ -1301,6 +1556,49 @@ func (c *Catalog) GetCommit(ctx context.Context, repositoryID string, reference
 	return catalogCommitLog, nil
 }
 
+// errDanglingCommitsPageFull stops scanning for dangling commits once a page was read
+var errDanglingCommitsPageFull = errors.New("dangling commits page full")
+
+// ListDanglingCommits lists the commits that cannot be reached from any branch or tag, ordered by commit ID.
+// Every page reads all the commits of the repository.
+func (c *Catalog) ListDanglingCommits(ctx context.Context, repositoryID string, after string, limit int) ([]*DanglingCommit, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListDanglingCommitsLimitMax {
+		limit = ListDanglingCommitsLimitMax
+	}
+	var commits []*DanglingCommit
+	err = c.Store.FindUnreachableCommits(ctx, repository, func(commit *graveler.CommitRecord, head bool) error {
+		if commit.CommitID.String() <= after {
+			return nil
+		}
+		commits = append(commits, &DanglingCommit{
+			Commit: CommitRecordToLog(commit),
+			Head:   head,
+		})
+		if len(commits) > limit {
+			return errDanglingCommitsPageFull
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDanglingCommitsPageFull) {
+		return nil, false, err
+	}
+	hasMore := false
+	if len(commits) > limit {
+		hasMore = true
+		commits = commits[:limit]
+	}
+	return commits, hasMore, nil
+}
+
 func (c *Catalog) ListCommits(ctx context.Context, repositoryID string, branch string, params LogParams) ([]*CommitLog, bool, error) {
 	branchRef := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -1686,6 +1984,9 @@ func (c *Catalog) Revert(ctx context.Context, repositoryID string, branch string
 	if err != nil {
 		return err
 	}
+	if err := c.checkCommitChangesEncryption(ctx, repository, branchID, reference, parentNumber, true); err != nil {
+		return err
+	}
 	_, err = c.Store.Revert(ctx, repository, branchID, reference, parentNumber, commitParams, params.CommitOverrides, opts...)
 	return err
 }
@@ -1708,6 +2009,13 @@ func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch st
 		return nil, err
 	}
 
+	pickedParent := 0
+	if parentNumber != nil {
+		pickedParent = *parentNumber
+	}
+	if err := c.checkCommitChangesEncryption(ctx, repository, branchID, reference, pickedParent, false); err != nil {
+		return nil, err
+	}
 	commitID, err := c.Store.CherryPick(ctx, repository, branchID, reference, parentNumber, params.Committer, params.CommitOverrides, opts...)
 	if err != nil {
 		return nil, err
@@ -1735,6 +2043,47 @@ func (c *Catalog) CherryPick(ctx context.Context, repositoryID string, branch st
 	return catalogCommitLog, nil
 }
 
+// SplitCommit replaces the commit reference on branch with a commit of its changes under each of prefixes, followed by
+// a commit of its remaining changes, rewriting the commits after it on the branch. Returns the replacing commits.
+func (c *Catalog) SplitCommit(ctx context.Context, repositoryID, branch, reference string, prefixes []string, opts ...graveler.SetOptionsFunc) ([]*CommitLog, error) {
+	branchID := graveler.BranchID(branch)
+	ref := graveler.Ref(reference)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "ref", Value: ref, Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, err
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("prefixes: %w", graveler.ErrRequiredValue)
+	}
+	splitPrefixes := make([]graveler.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if err := ValidatePath(Path(prefix)); err != nil {
+			return nil, fmt.Errorf("prefix %s: %w", prefix, err)
+		}
+		splitPrefixes = append(splitPrefixes, graveler.Prefix(prefix))
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	commitIDs, err := c.Store.SplitCommit(ctx, repository, branchID, ref, splitPrefixes, opts...)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]*CommitLog, 0, len(commitIDs))
+	for _, commitID := range commitIDs {
+		commit, err := c.Store.GetCommit(ctx, repository, commitID)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, CommitRecordToLog(&graveler.CommitRecord{CommitID: commitID, Commit: commit}))
+	}
+	return commits, nil
+}
+
 func (c *Catalog) Diff(ctx context.Context, repositoryID string, leftReference string, rightReference string, params DiffParams) (Differences, bool, error) {
 	left := graveler.Ref(leftReference)
 	right := graveler.Ref(rightReference)
@@ -1783,6 +2132,34 @@ func (c *Catalog) Compare(ctx context.Context, repositoryID, leftReference strin
 	return listDiffHelper(it, params.Prefix, params.Delimiter, params.Limit, params.After)
 }
 
+// DiffPrefixes lists the changes from the objects under leftPrefix of leftReference to the objects under rightPrefix
+// of rightReference, with paths relative to the prefixes. params.Prefix further filters the relative paths.
+func (c *Catalog) DiffPrefixes(ctx context.Context, repositoryID, leftReference, leftPrefix, rightReference, rightPrefix string, params DiffParams) (Differences, bool, error) {
+	left := graveler.Ref(leftReference)
+	right := graveler.Ref(rightReference)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "left", Value: left, Fn: graveler.ValidateRef},
+		{Name: "right", Value: right, Fn: graveler.ValidateRef},
+		{Name: "left_prefix", Value: Path(leftPrefix), Fn: ValidatePathOptional},
+		{Name: "right_prefix", Value: Path(rightPrefix), Fn: ValidatePathOptional},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	iter, err := c.Store.DiffPrefixes(ctx, repository, left, graveler.Key(leftPrefix), right, graveler.Key(rightPrefix))
+	if err != nil {
+		return nil, false, err
+	}
+	it := NewEntryDiffIterator(iter)
+	defer it.Close()
+	return listDiffHelper(it, params.Prefix, params.Delimiter, params.Limit, params.After)
+}
+
 func (c *Catalog) DiffUncommitted(ctx context.Context, repositoryID, branch, prefix, delimiter string, limit int, after string) (Differences, bool, error) {
 	branchID := graveler.BranchID(branch)
 	if err := validator.Validate([]validator.ValidateArg{
@@ -1926,6 +2303,10 @@ func (c *Catalog) Merge(ctx context.Context, repositoryID string, destinationBra
 		return "", err
 	}
 
+	if err := c.checkMergeEncryption(ctx, repository, destination, source); err != nil {
+		return "", err
+	}
+
 	commitID, err := c.Store.Merge(ctx, repository, destination, source, commitParams, strategy, opts...)
 	if err != nil {
 		return "", err
@@ -2003,7 +2384,7 @@ func (c *Catalog) DumpRepositorySubmit(ctx context.Context, repositoryID string)
 
 	// create refs dump task and update initial status.
 	taskID := NewTaskID(DumpRefsTaskIDPrefix)
-	err = c.runBackgroundTaskSteps(repository, taskID, taskSteps, taskStatus)
+	err = c.runBackgroundTaskSteps(repository, taskID, operationInfo{Kind: OperationKindDumpRefs}, taskSteps, taskStatus)
 	if err != nil {
 		return "", err
 	}
@@ -2065,7 +2446,7 @@ func (c *Catalog) RestoreRepositorySubmit(ctx context.Context, repositoryID stri
 		},
 	}
 	taskID := NewTaskID(RestoreRefsTaskIDPrefix)
-	if err := c.runBackgroundTaskSteps(repository, taskID, taskSteps, taskStatus); err != nil {
+	if err := c.runBackgroundTaskSteps(repository, taskID, operationInfo{Kind: OperationKindRestoreRefs}, taskSteps, taskStatus); err != nil {
 		return "", err
 	}
 	return taskID, nil
@@ -2091,7 +2472,8 @@ func (c *Catalog) RestoreRepositoryStatus(ctx context.Context, repositoryID stri
 // runBackgroundTaskSteps update task status provided after filling the 'Task' field and update for each step provided.
 // the task status is updated after each step, and the task is marked as completed if the step is the last one.
 // initial update if the task is done before running the steps.
-func (c *Catalog) runBackgroundTaskSteps(repository *graveler.RepositoryRecord, taskID string, steps []taskStep, taskStatus protoreflect.ProtoMessage) error {
+// The task is recorded as an operation described by info, which stops running its steps once canceled.
+func (c *Catalog) runBackgroundTaskSteps(repository *graveler.RepositoryRecord, taskID string, info operationInfo, steps []taskStep, taskStatus protoreflect.ProtoMessage) error {
 	// Allocate Task and set if on the taskStatus's 'Task' field.
 	// We continue to update this field while running each step.
 	// If the task field in the common Protobuf message is changed, we need to update the field name here as well.
@@ -2102,40 +2484,86 @@ func (c *Catalog) runBackgroundTaskSteps(repository *graveler.RepositoryRecord,
 	reflect.ValueOf(taskStatus).Elem().FieldByName("Task").Set(reflect.ValueOf(task))
 
 	// make sure we use background context as soon as we submit the task the request is done
-	ctx := context.Background()
+	ctx := block.WithRepository(context.Background(), repository.RepositoryID.String())
 
 	// initial task update done before we run each step in the background task
 	if err := UpdateTaskStatus(ctx, c.KVStore, repository, taskID, taskStatus); err != nil {
 		return err
 	}
+	// steps run with a context canceled when the operation is canceled
+	taskCtx, cancel := context.WithCancel(ctx)
+	op, err := c.startOperation(ctx, repository, taskID, info, cancel)
+	if err != nil {
+		cancel()
+		return err
+	}
 
 	log := c.log(ctx).WithFields(logging.Fields{"task_id": taskID, "repository": repository.RepositoryID})
-	c.workPool.Submit(func() {
-		for stepIdx, step := range steps {
-			// call the step function
-			err := step.Func(ctx)
-			// update task part
+	go func() {
+		defer cancel()
+		go c.watchOperationCancel(taskCtx, op)
+		// wait for the priority class of the task outside the work pool, so waiting tasks don't hold its workers
+		op.logf("queued with %s priority", c.priorities.JobPriority(info.Kind))
+		stepsCtx, release, err := c.priorities.acquire(taskCtx, info.Kind)
+		if err != nil {
+			err = fmt.Errorf("waiting to run: %w", ErrOperationCanceled)
+			task.Done = true
+			task.Error = err.Error()
 			task.UpdatedAt = timestamppb.Now()
-			if err != nil {
-				log.WithError(err).WithField("step", step.Name).Errorf("Catalog background task step failed")
-				task.Done = true
-				task.Error = err.Error()
-			} else if stepIdx == len(steps)-1 {
-				task.Done = true
+			if err := c.updateTaskProgress(ctx, repository, task, taskStatus, 0); err != nil {
+				log.WithError(err).Error("Catalog failed to update task status")
 			}
+			c.finishOperation(ctx, op, err)
+			return
+		}
+		defer release()
+		c.workPool.SubmitAndWait(func() {
+			c.runTaskSteps(ctx, stepsCtx, repository, task, op, steps, taskStatus)
+		})
+	}()
+	return nil
+}
 
-			// update task status
-			if err := UpdateTaskStatus(ctx, c.KVStore, repository, taskID, taskStatus); err != nil {
-				log.WithError(err).WithField("step", step.Name).Error("Catalog failed to update task status")
+// runTaskSteps runs the steps of a background task with stepsCtx, updating its status and operation after each step
+func (c *Catalog) runTaskSteps(ctx, stepsCtx context.Context, repository *graveler.RepositoryRecord, task *Task, op *runningOperation, steps []taskStep, taskStatus protoreflect.ProtoMessage) {
+	log := c.log(ctx).WithFields(logging.Fields{"task_id": task.Id, "repository": repository.RepositoryID})
+	var taskErr error
+	for stepIdx, step := range steps {
+		op.logf("%s started", step.Name)
+		// call the step function, unless the operation was canceled
+		err := stepsCtx.Err()
+		if err == nil {
+			err = step.Func(stepsCtx)
+		}
+		if err != nil && stepsCtx.Err() != nil {
+			err = fmt.Errorf("%s: %w", step.Name, ErrOperationCanceled)
+		}
+		// update task part
+		task.UpdatedAt = timestamppb.Now()
+		if err != nil {
+			log.WithError(err).WithField("step", step.Name).Errorf("Catalog background task step failed")
+			task.Done = true
+			task.Error = err.Error()
+			taskErr = err
+			op.logf("%s failed: %s", step.Name, err)
+		} else {
+			op.logf("%s done", step.Name)
+			if stepIdx == len(steps)-1 {
+				task.Done = true
 			}
+		}
 
-			// make sure we stop based on task completed status, as we may fail
-			if task.Done {
-				break
-			}
+		// update task status
+		if err := c.updateTaskProgress(ctx, repository, task, taskStatus, 0); err != nil {
+			log.WithError(err).WithField("step", step.Name).Error("Catalog failed to update task status")
 		}
-	})
-	return nil
+
+		// make sure we stop based on task completed status, as we may fail
+		if task.Done {
+			break
+		}
+	}
+	c.finishOperation(ctx, op, taskErr)
 }
 
 // DeleteExpiredRepositoryTasks deletes all expired tasks for the given repository
@@ -2249,6 +2677,7 @@ func (c *Catalog) GetRange(ctx context.Context, repositoryID, rangeID string) (g
 func (c *Catalog) importAsync(repository *graveler.RepositoryRecord, branchID, importID string, params ImportRequest, logger logging.Logger) error {
 	ctx, cancel := context.WithCancel(context.Background()) // Need a new context for the async operations
 	defer cancel()
+	ctx = block.WithRepository(ctx, repository.RepositoryID.String())
 
 	importManager, err := NewImport(ctx, cancel, logger, c.KVStore, repository, importID)
 	if err != nil {
@@ -2256,6 +2685,13 @@ func (c *Catalog) importAsync(repository *graveler.RepositoryRecord, branchID, i
 	}
 	defer importManager.Close()
 
+	// wait for the import priority class, canceling the import while waiting stops it
+	ctx, release, err := c.priorities.acquire(ctx, OperationKindImport)
+	if err != nil {
+		return nil
+	}
+	defer release()
+
 	wg, wgCtx := c.workPool.GroupContext(ctx)
 	for _, source := range params.Paths {
 		src := source // Pinning
@@ -2365,7 +2801,31 @@ func (c *Catalog) Import(ctx context.Context, repositoryID, branchID string, par
 		return "", err
 	}
 
+	destinations := make([]string, 0, len(params.Paths))
+	sources := make([]string, 0, len(params.Paths))
+	for _, p := range params.Paths {
+		destinations = append(destinations, p.Destination)
+		sources = append(sources, p.Path)
+	}
+	if err := c.checkImportEncryption(ctx, repositoryID, graveler.BranchID(branchID), destinations); err != nil {
+		return "", err
+	}
+
 	id := xid.New().String()
+	// record the import as an operation, its status is kept by the import itself
+	now := timestamppb.Now()
+	err = kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(OperationPath(id)), &OperationData{
+		Id:          id,
+		Kind:        OperationKindImport,
+		Description: fmt.Sprintf("import %s into %s", strings.Join(sources, ", "), branchID),
+		Status:      OperationStatusRunning,
+		CreatedBy:   params.Commit.Committer,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return "", err
+	}
 	// Run import
 	go func() {
 		logger := c.log(ctx).WithField("import_id", id)
@@ -2673,8 +3133,23 @@ func (c *Catalog) CopyEntry(ctx context.Context, srcRepository, srcRef, srcPath,
 		}
 	}
 
-	// copy data to a new physical address
+	if err := c.CheckEntryEncryption(ctx, destRepository, destBranch, destPath, srcEntry); err != nil {
+		return nil, err
+	}
+
+	// copy data to a new physical address, encrypted using the key of the destination repository
+	ctx = block.WithRepository(ctx, destRepository)
+	encryption, err := c.RepositoryEncryption(ctx, destRepository)
+	if err != nil {
+		return nil, err
+	}
 	dstEntry := *srcEntry
+	// the copy is encrypted by the block adapter as any data it writes
+	dstEntry.Metadata = make(Metadata, len(srcEntry.Metadata))
+	for k, v := range srcEntry.Metadata {
+		dstEntry.Metadata[k] = v
+	}
+	encryption.SetMetadata(dstEntry.Metadata)
 	dstEntry.CreationDate = time.Now()
 	dstEntry.Path = destPath
 	dstEntry.AddressType = AddressTypeRelative
@@ -2729,6 +3204,10 @@ func (c *Catalog) DeleteExpiredTasks(ctx context.Context) {
 		if err != nil {
 			c.log(ctx).WithError(err).WithField("repository", repo.RepositoryID).Warn("Delete expired tasks failed")
 		}
+		err = c.deleteRepositoryExpiredOperations(ctx, repo)
+		if err != nil {
+			c.log(ctx).WithError(err).WithField("repository", repo.RepositoryID).Warn("Delete expired operations failed")
+		}
 	}
 }
 
@@ -2829,6 +3308,9 @@ func (c *Catalog) Close() error {
 	if c.deleteSensor != nil {
 		c.deleteSensor.Close()
 	}
+	if err := c.closeObjectAccess(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -465,6 +465,62 @@ func (x *TaskMsg) GetTask() *Task {
 	return nil
 }
 
+// TagObjectsStatus holds the status of a bulk metadata tagging job
+type TagObjectsStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Task     *Task  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	CommitId string `protobuf:"bytes,2,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+}
+
+func (x *TagObjectsStatus) Reset() {
+	*x = TagObjectsStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_catalog_catalog_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TagObjectsStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagObjectsStatus) ProtoMessage() {}
+
+func (x *TagObjectsStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_catalog_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagObjectsStatus.ProtoReflect.Descriptor instead.
+func (*TagObjectsStatus) Descriptor() ([]byte, []int) {
+	return file_catalog_catalog_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TagObjectsStatus) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TagObjectsStatus) GetCommitId() string {
+	if x != nil {
+		return x.CommitId
+	}
+	return ""
+}
+
 var File_catalog_catalog_proto protoreflect.FileDescriptor
 
 var file_catalog_catalog_proto_rawDesc = []byte{
@@ -532,10 +588,15 @@ var file_catalog_catalog_proto_rawDesc = []byte{
 	0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x22, 0x2c, 0x0a, 0x07, 0x54, 0x61, 0x73, 0x6b, 0x4d, 0x73,
 	0x67, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x0d, 0x2e, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x04,
-	0x74, 0x61, 0x73, 0x6b, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x74, 0x72, 0x65, 0x65, 0x76, 0x65, 0x73, 0x65, 0x2f, 0x6c, 0x61, 0x6b, 0x65,
-	0x66, 0x73, 0x2f, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x74, 0x61, 0x73, 0x6b, 0x22, 0x52, 0x0a, 0x10, 0x54, 0x61, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x61, 0x73, 0x6b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67,
+	0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x04, 0x74, 0x61, 0x73, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x72, 0x65, 0x65, 0x76, 0x65, 0x73, 0x65, 0x2f,
+	0x6c, 0x61, 0x6b, 0x65, 0x66, 0x73, 0x2f, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -551,7 +612,7 @@ func file_catalog_catalog_proto_rawDescGZIP() []byte {
 }
 
 var file_catalog_catalog_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_catalog_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_catalog_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_catalog_catalog_proto_goTypes = []interface{}{
 	(Entry_AddressType)(0),          // 0: catalog.Entry.AddressType
 	(*Entry)(nil),                   // 1: catalog.Entry
@@ -560,23 +621,25 @@ var file_catalog_catalog_proto_goTypes = []interface{}{
 	(*RepositoryDumpStatus)(nil),    // 4: catalog.RepositoryDumpStatus
 	(*RepositoryRestoreStatus)(nil), // 5: catalog.RepositoryRestoreStatus
 	(*TaskMsg)(nil),                 // 6: catalog.TaskMsg
-	nil,                             // 7: catalog.Entry.MetadataEntry
-	(*timestamppb.Timestamp)(nil),   // 8: google.protobuf.Timestamp
+	(*TagObjectsStatus)(nil),        // 7: catalog.TagObjectsStatus
+	nil,                             // 8: catalog.Entry.MetadataEntry
+	(*timestamppb.Timestamp)(nil),   // 9: google.protobuf.Timestamp
 }
 var file_catalog_catalog_proto_depIdxs = []int32{
-	8, // 0: catalog.Entry.last_modified:type_name -> google.protobuf.Timestamp
-	7, // 1: catalog.Entry.metadata:type_name -> catalog.Entry.MetadataEntry
+	9, // 0: catalog.Entry.last_modified:type_name -> google.protobuf.Timestamp
+	8, // 1: catalog.Entry.metadata:type_name -> catalog.Entry.MetadataEntry
 	0, // 2: catalog.Entry.address_type:type_name -> catalog.Entry.AddressType
-	8, // 3: catalog.Task.updated_at:type_name -> google.protobuf.Timestamp
+	9, // 3: catalog.Task.updated_at:type_name -> google.protobuf.Timestamp
 	2, // 4: catalog.RepositoryDumpStatus.task:type_name -> catalog.Task
 	3, // 5: catalog.RepositoryDumpStatus.info:type_name -> catalog.RepositoryDumpInfo
 	2, // 6: catalog.RepositoryRestoreStatus.task:type_name -> catalog.Task
 	2, // 7: catalog.TaskMsg.task:type_name -> catalog.Task
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	2, // 8: catalog.TagObjectsStatus.task:type_name -> catalog.Task
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_catalog_catalog_proto_init() }
@@ -657,6 +720,18 @@ func file_catalog_catalog_proto_init() {
 				return nil
 			}
 		}
+		file_catalog_catalog_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TagObjectsStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -664,7 +739,7 @@ func file_catalog_catalog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_catalog_catalog_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -3,10 +3,12 @@ package catalog_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,11 +16,13 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	cUtils "github.com/treeverse/lakefs/pkg/catalog/testutils"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
 	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/treeverse/lakefs/pkg/upload"
 	"github.com/xitongsys/parquet-go-source/buffer"
 	"github.com/xitongsys/parquet-go/reader"
 	"google.golang.org/protobuf/proto"
@@ -403,6 +407,52 @@ func TestCatalog_ListTags(t *testing.T) {
 	}
 }
 
+func TestCatalog_ListDanglingCommits(t *testing.T) {
+	gravelerMock := &catalog.FakeGraveler{
+		UnreachableCommits: []*graveler.CommitRecord{
+			{CommitID: "c1", Commit: &graveler.Commit{Message: "m1"}},
+			{CommitID: "c2", Commit: &graveler.Commit{Message: "m2"}},
+			{CommitID: "c3", Commit: &graveler.Commit{Message: "m3"}},
+		},
+	}
+	c := &catalog.Catalog{
+		Store: gravelerMock,
+	}
+	tests := []struct {
+		name        string
+		limit       int
+		after       string
+		want        []string
+		wantHasMore bool
+	}{
+		{name: "all", limit: -1, want: []string{"c1", "c2", "c3"}},
+		{name: "exact", limit: 3, want: []string{"c1", "c2", "c3"}},
+		{name: "first", limit: 1, want: []string{"c1"}, wantHasMore: true},
+		{name: "second", limit: 1, after: "c1", want: []string{"c2"}, wantHasMore: true},
+		{name: "last", limit: 10, after: "c2", want: []string{"c3"}},
+		{name: "none", limit: 10, after: "c3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hasMore, err := c.ListDanglingCommits(context.Background(), "repo", tt.after, tt.limit)
+			testutil.Must(t, err)
+			var ids []string
+			for _, commit := range got {
+				if !commit.Head {
+					t.Errorf("ListDanglingCommits() commit %s is not a head", commit.Commit.Reference)
+				}
+				ids = append(ids, commit.Commit.Reference)
+			}
+			if diff := deep.Equal(ids, tt.want); diff != nil {
+				t.Error("ListDanglingCommits() found diff", diff)
+			}
+			if hasMore != tt.wantHasMore {
+				t.Errorf("ListDanglingCommits() hasMore = %t, want %t", hasMore, tt.wantHasMore)
+			}
+		})
+	}
+}
+
 func TestCatalog_ListEntries(t *testing.T) {
 	// prepare branch data
 	now := time.Now()
@@ -498,6 +548,39 @@ func TestCatalog_ListEntries(t *testing.T) {
 			wantHasMore: false,
 			wantErr:     false,
 		},
+		{
+			name: "after inside common prefix",
+			args: args{limit: -1, after: "h/file1", delimiter: "/"},
+			want: []*catalog.DBEntry{
+				{Path: "h/", CommonLevel: true},
+			},
+			wantHasMore: false,
+			wantErr:     false,
+		},
+		{
+			name:        "after last key of common prefix",
+			args:        args{limit: -1, after: "h/file2", delimiter: "/"},
+			want:        nil,
+			wantHasMore: false,
+			wantErr:     false,
+		},
+		{
+			name:        "after common prefix",
+			args:        args{limit: -1, after: "h/", delimiter: "/"},
+			want:        nil,
+			wantHasMore: false,
+			wantErr:     false,
+		},
+		{
+			name: "non slash delimiter",
+			args: args{limit: -1, after: "file1", delimiter: "e"},
+			want: []*catalog.DBEntry{
+				{Path: "file", CommonLevel: true},
+				{Path: "h/file", CommonLevel: true},
+			},
+			wantHasMore: false,
+			wantErr:     false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -814,3 +897,282 @@ func readPhysicalAddressesFromParquetObject(t *testing.T, repositoryID string, c
 	}
 	return records
 }
+
+func TestCatalog_GetEntryAlias(t *testing.T) {
+	aliasValue := func(target string) *graveler.Value {
+		return catalog.MustEntryToValue(&catalog.Entry{
+			Address:  "data/v2",
+			Size:     2,
+			ETag:     "02",
+			Metadata: map[string]string{catalog.AliasTargetMetadataKey: target},
+		})
+	}
+	gravelerMock := &catalog.FakeGraveler{
+		KeyValue: map[string]*graveler.Value{
+			"repo/ref/data/v3":       catalog.MustEntryToValue(&catalog.Entry{Address: "data/v3", Size: 3, ETag: "03"}),
+			"repo/ref/latest":        aliasValue("data/v3"),
+			"repo/ref/latest-latest": aliasValue("latest"),
+			"repo/ref/dangling":      aliasValue("data/missing"),
+			"repo/ref/loop/first":    aliasValue("loop/second"),
+			"repo/ref/loop/second":   aliasValue("loop/first"),
+		},
+	}
+	c := &catalog.Catalog{
+		Store: gravelerMock,
+	}
+	ctx := context.Background()
+
+	t.Run("resolve", func(t *testing.T) {
+		for _, path := range []string{"latest", "latest-latest"} {
+			entry, err := c.GetEntry(ctx, "repo", "ref", path, catalog.GetEntryParams{})
+			if err != nil {
+				t.Fatalf("GetEntry(%s) failed: %s", path, err)
+			}
+			if entry.Path != path {
+				t.Errorf("GetEntry(%s) path = %s, expected alias path", path, entry.Path)
+			}
+			if entry.PhysicalAddress != "data/v3" || entry.Size != 3 {
+				t.Errorf("GetEntry(%s) = %+v, expected entry of data/v3", path, entry)
+			}
+			if entry.AliasTarget != "data/v3" {
+				t.Errorf("GetEntry(%s) alias target = '%s', expected the resolved path data/v3", path, entry.AliasTarget)
+			}
+		}
+	})
+
+	t.Run("no follow", func(t *testing.T) {
+		entry, err := c.GetEntry(ctx, "repo", "ref", "latest", catalog.GetEntryParams{NoFollowAlias: true})
+		if err != nil {
+			t.Fatalf("GetEntry failed: %s", err)
+		}
+		if !entry.IsAlias() || entry.PhysicalAddress != "data/v2" {
+			t.Errorf("GetEntry() = %+v, expected the alias entry", entry)
+		}
+	})
+
+	t.Run("dangling", func(t *testing.T) {
+		_, err := c.GetEntry(ctx, "repo", "ref", "dangling", catalog.GetEntryParams{})
+		if !errors.Is(err, graveler.ErrNotFound) {
+			t.Errorf("GetEntry() err = %v, expected %s", err, graveler.ErrNotFound)
+		}
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		_, err := c.GetEntry(ctx, "repo", "ref", "loop/first", catalog.GetEntryParams{})
+		if !errors.Is(err, catalog.ErrAliasLoop) {
+			t.Errorf("GetEntry() err = %v, expected %s", err, catalog.ErrAliasLoop)
+		}
+	})
+}
+
+func TestCatalog_MoveEntry(t *testing.T) {
+	gravelerMock := &catalog.FakeGraveler{
+		KeyValue: map[string]*graveler.Value{
+			"repo/main/data/old": catalog.MustEntryToValue(&catalog.Entry{
+				Address:  "data/v1",
+				Size:     1,
+				ETag:     "01",
+				Metadata: map[string]string{"owner": "me"},
+			}),
+		},
+	}
+	c := &catalog.Catalog{
+		Store: gravelerMock,
+	}
+	ctx := context.Background()
+
+	entry, commitLog, err := c.MoveEntry(ctx, "repo", "main", "data/old", "data/new", catalog.MoveEntryParams{})
+	if err != nil {
+		t.Fatalf("MoveEntry failed: %s", err)
+	}
+	if commitLog != nil {
+		t.Errorf("MoveEntry() commit = %+v, expected no commit", commitLog)
+	}
+	if entry.Path != "data/new" || entry.PhysicalAddress != "data/v1" {
+		t.Errorf("MoveEntry() = %+v, expected data/new with the address of data/old", entry)
+	}
+	if _, ok := gravelerMock.KeyValue["repo/main/data/old"]; ok {
+		t.Error("MoveEntry() expected data/old to be removed")
+	}
+	moved, err := c.GetEntry(ctx, "repo", "main", "data/new", catalog.GetEntryParams{})
+	if err != nil {
+		t.Fatalf("GetEntry failed: %s", err)
+	}
+	expectedMetadata := catalog.Metadata{"owner": "me", catalog.RenamedFromMetadataKey: "data/old"}
+	if diff := deep.Equal(moved.Metadata, expectedMetadata); diff != nil {
+		t.Errorf("moved entry metadata diff: %s", diff)
+	}
+
+	_, _, err = c.MoveEntry(ctx, "repo", "main", "data/new", "data/new", catalog.MoveEntryParams{})
+	if !errors.Is(err, graveler.ErrInvalidValue) {
+		t.Errorf("MoveEntry() onto itself err = %v, expected %s", err, graveler.ErrInvalidValue)
+	}
+}
+
+func TestCatalog_UpdateEntryMetadata(t *testing.T) {
+	const checksumKey = upload.ChecksumSHA256MetadataKey
+	csvType := "text/csv"
+	newCatalog := func() (*catalog.Catalog, *catalog.FakeGraveler) {
+		gravelerMock := &catalog.FakeGraveler{
+			KeyValue: map[string]*graveler.Value{
+				"repo/main/data/file": catalog.MustEntryToValue(&catalog.Entry{
+					Address:     "data/v1",
+					Size:        1,
+					ETag:        "01",
+					ContentType: "text/plain",
+					Metadata:    map[string]string{"owner": "me", "stage": "raw", checksumKey: "sum"},
+				}),
+			},
+		}
+		return &catalog.Catalog{Store: gravelerMock}, gravelerMock
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		params           catalog.UpdateEntryMetadataParams
+		expectedMetadata catalog.Metadata
+		expectedType     string
+		expectedErr      error
+	}{
+		{
+			name:             "merge",
+			params:           catalog.UpdateEntryMetadataParams{Metadata: catalog.Metadata{"stage": "clean", "team": "data"}},
+			expectedMetadata: catalog.Metadata{"owner": "me", "stage": "clean", "team": "data", checksumKey: "sum"},
+			expectedType:     "text/plain",
+		},
+		{
+			name:             "replace",
+			params:           catalog.UpdateEntryMetadataParams{Metadata: catalog.Metadata{"team": "data"}, Replace: true, ContentType: &csvType},
+			expectedMetadata: catalog.Metadata{"team": "data", checksumKey: "sum"},
+			expectedType:     "text/csv",
+		},
+		{
+			name:        "reserved key",
+			params:      catalog.UpdateEntryMetadataParams{Metadata: catalog.Metadata{checksumKey: "other"}},
+			expectedErr: graveler.ErrInvalidValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCatalog()
+			_, err := c.UpdateEntryMetadata(ctx, "repo", "main", "data/file", tt.params)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("UpdateEntryMetadata() err = %v, expected %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			entry, err := c.GetEntry(ctx, "repo", "main", "data/file", catalog.GetEntryParams{})
+			if err != nil {
+				t.Fatalf("GetEntry failed: %s", err)
+			}
+			if entry.PhysicalAddress != "data/v1" {
+				t.Errorf("entry address = %s, expected data/v1", entry.PhysicalAddress)
+			}
+			if entry.ContentType != tt.expectedType {
+				t.Errorf("entry content type = %s, expected %s", entry.ContentType, tt.expectedType)
+			}
+			if diff := deep.Equal(entry.Metadata, tt.expectedMetadata); diff != nil {
+				t.Errorf("entry metadata diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCatalog_DirectoryMarkers(t *testing.T) {
+	ctx := context.Background()
+	newCatalog := func(enabled string, data []*graveler.ValueRecord) (*catalog.Catalog, *catalog.FakeGraveler) {
+		gravelerMock := &catalog.FakeGraveler{
+			KeyValue:            map[string]*graveler.Value{},
+			RepositoryMetadata:  graveler.RepositoryMetadata{catalog.DirectoryMarkersRepositoryMetadataKey: enabled},
+			ListIteratorFactory: catalog.NewFakeValueIteratorFactory(data),
+		}
+		return &catalog.Catalog{
+			Store:        gravelerMock,
+			BlockAdapter: mem.New(ctx),
+			PathProvider: upload.DefaultPathProvider,
+		}, gravelerMock
+	}
+
+	t.Run("create marker", func(t *testing.T) {
+		c, gravelerMock := newCatalog("true", nil)
+		err := c.CreateEntry(ctx, "repo", "main", catalog.DBEntry{Path: "dir/", PhysicalAddress: "addr"})
+		require.NoError(t, err)
+		require.NotNil(t, gravelerMock.KeyValue["repo/main/dir/"])
+		ent, err := catalog.ValueToEntry(gravelerMock.KeyValue["repo/main/dir/"])
+		require.NoError(t, err)
+		require.Equal(t, catalog.DirectoryMarkerContentType, ent.ContentType)
+	})
+
+	t.Run("marker with data", func(t *testing.T) {
+		c, _ := newCatalog("true", nil)
+		err := c.CreateEntry(ctx, "repo", "main", catalog.DBEntry{Path: "dir/", PhysicalAddress: "addr", Size: 1})
+		require.ErrorIs(t, err, catalog.ErrInvalidDirectoryMarker)
+
+		c, _ = newCatalog("false", nil)
+		err = c.CreateEntry(ctx, "repo", "main", catalog.DBEntry{Path: "dir/", PhysicalAddress: "addr", Size: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("delete last in directory", func(t *testing.T) {
+		c, gravelerMock := newCatalog("true", []*graveler.ValueRecord{
+			{Key: graveler.Key("other/file"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "file"})},
+		})
+		gravelerMock.KeyValue["repo/main/dir/sub/file"] = catalog.MustEntryToValue(&catalog.Entry{Address: "file"})
+		require.NoError(t, c.DeleteEntry(ctx, "repo", "main", "dir/sub/file"))
+		require.NotNil(t, gravelerMock.KeyValue["repo/main/dir/sub/"])
+		ent, err := catalog.ValueToEntry(gravelerMock.KeyValue["repo/main/dir/sub/"])
+		require.NoError(t, err)
+		require.Equal(t, catalog.DirectoryMarkerContentType, ent.ContentType)
+		require.Zero(t, ent.Size)
+	})
+
+	t.Run("delete in non empty directory", func(t *testing.T) {
+		c, gravelerMock := newCatalog("true", []*graveler.ValueRecord{
+			{Key: graveler.Key("dir/sub/another"), Value: catalog.MustEntryToValue(&catalog.Entry{Address: "another"})},
+		})
+		gravelerMock.KeyValue["repo/main/dir/sub/file"] = catalog.MustEntryToValue(&catalog.Entry{Address: "file"})
+		require.NoError(t, c.DeleteEntries(ctx, "repo", "main", []string{"dir/sub/file", "top"}))
+		require.Empty(t, gravelerMock.KeyValue)
+	})
+
+	t.Run("delete missing path", func(t *testing.T) {
+		c, gravelerMock := newCatalog("true", nil)
+		require.NoError(t, c.DeleteEntries(ctx, "repo", "main", []string{"dir/sub/file"}))
+		require.Empty(t, gravelerMock.KeyValue)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c, gravelerMock := newCatalog("false", nil)
+		require.NoError(t, c.DeleteEntry(ctx, "repo", "main", "dir/sub/file"))
+		require.Empty(t, gravelerMock.KeyValue)
+	})
+}
+
+func TestCatalog_Params(t *testing.T) {
+	ctx := context.Background()
+	gravelerMock := &catalog.FakeGraveler{
+		KeyValue: map[string]*graveler.Value{},
+	}
+	c := &catalog.Catalog{
+		Store:        gravelerMock,
+		BlockAdapter: mem.New(ctx),
+		PathProvider: upload.DefaultPathProvider,
+	}
+
+	require.NoError(t, c.SetParam(ctx, "repo", "main", "learning_rate", "0.01"))
+	entry, err := catalog.ValueToEntry(gravelerMock.KeyValue["repo/main/"+catalog.ParamsPrefix+"learning_rate"])
+	require.NoError(t, err)
+	require.Equal(t, catalog.ParamContentType, entry.ContentType)
+	value, err := c.GetParam(ctx, "repo", "main", "learning_rate")
+	require.NoError(t, err)
+	require.Equal(t, "0.01", value)
+
+	require.ErrorIs(t, c.SetParam(ctx, "repo", "main", "nested/key", "v"), catalog.ErrInvalidParamKey)
+	require.ErrorIs(t, c.SetParam(ctx, "repo", "main", "large", strings.Repeat("v", catalog.MaxParamValueSize+1)), catalog.ErrParamValueTooLarge)
+
+	require.NoError(t, c.DeleteParam(ctx, "repo", "main", "learning_rate"))
+	_, err = c.GetParam(ctx, "repo", "main", "learning_rate")
+	require.ErrorIs(t, err, catalog.ErrParamNotFound)
+}
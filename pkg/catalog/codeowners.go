@@ -0,0 +1,233 @@
+package catalog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// CodeOwnersPath is the path of the file mapping path patterns to the groups owning them
+	CodeOwnersPath = "_lakefs/CODEOWNERS"
+
+	maxCodeOwnersSize = 1024 * 1024
+)
+
+var ErrInvalidCodeOwners = fmt.Errorf("code owners: %w", graveler.ErrInvalidValue)
+
+type CodeOwnersRule struct {
+	Pattern string
+	Owners  []string
+	// dirOnly is set for patterns matching only directories
+	dirOnly bool
+	matcher glob.Glob
+}
+
+// match returns true if the rule matches path or one of the directories holding it
+func (r *CodeOwnersRule) match(path string) bool {
+	if !r.dirOnly && r.matcher.Match(path) {
+		return true
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && r.matcher.Match(path[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// CodeOwners maps paths to the groups owning them
+type CodeOwners struct {
+	Rules []*CodeOwnersRule
+}
+
+// ParseCodeOwners reads a CODEOWNERS file. Each line holds a path pattern followed by the groups owning the paths it
+// matches, and '#' starts a comment. Patterns are globs on paths from the root of the repository, with '*' matching
+// within a path segment and '**' across segments. A pattern matching a directory matches all the paths under it, and a
+// pattern ending with '/' matches only directories.
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	owners := &CodeOwners{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		pattern := strings.TrimPrefix(fields[0], "/")
+		rule := &CodeOwnersRule{
+			Pattern: fields[0],
+			dirOnly: strings.HasSuffix(pattern, "/"),
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+		if pattern == "" {
+			return nil, fmt.Errorf("line %d: empty pattern: %w", lineNumber, ErrInvalidCodeOwners)
+		}
+		matcher, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, fmt.Errorf("line %d: pattern %s: %w", lineNumber, fields[0], ErrInvalidCodeOwners)
+		}
+		rule.matcher = matcher
+		for _, owner := range fields[1:] {
+			rule.Owners = append(rule.Owners, strings.TrimPrefix(owner, "@"))
+		}
+		owners.Rules = append(owners.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// Owners returns the groups owning path: the owners of the last rule matching it, nil if none of the rules does
+func (o *CodeOwners) Owners(path string) []string {
+	for i := len(o.Rules) - 1; i >= 0; i-- {
+		if o.Rules[i].match(path) {
+			return o.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// CodeOwnersApproval is an approval of one of Owners, required to merge changes to the paths they own such as Path
+type CodeOwnersApproval struct {
+	Path   string
+	Owners []string
+}
+
+// MergeCodeOwners returns the approvals required to merge sourceRef into destinationBranch, one for each distinct set
+// of owners of the merged changes. Approvals are required to merge into branches protected from commits which have a
+// CODEOWNERS file. The file committed on the destination branch is used, so that a merge cannot change its own
+// owners. When pathScope is set only the changes under it are merged, so only their owners approve.
+// sourceRef is resolved once and the resolved commit ID is returned when approvals apply: merging it, rather than
+// sourceRef, merges the changes that were approved even if sourceRef moves after the check.
+func (c *Catalog) MergeCodeOwners(ctx context.Context, repositoryID, destinationBranch, sourceRef, pathScope string) ([]CodeOwnersApproval, string, error) {
+	destination := graveler.BranchID(destinationBranch)
+	source := graveler.Ref(sourceRef)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "destination", Value: destination, Fn: graveler.ValidateBranchID},
+		{Name: "source", Value: source, Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, "", err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, "", err
+	}
+	protected, err := c.isCommitProtected(ctx, repository, destination)
+	if err != nil || !protected {
+		return nil, "", err
+	}
+	owners, err := c.getCodeOwners(ctx, repository, destination)
+	if err != nil || owners == nil {
+		return nil, "", err
+	}
+	resolved, err := c.Store.Dereference(ctx, repository, source)
+	if err != nil {
+		return nil, "", err
+	}
+	sourceCommitID := resolved.CommitID
+
+	iter, err := c.Store.Compare(ctx, repository, graveler.Ref(destination), graveler.Ref(sourceCommitID))
+	if err != nil {
+		return nil, "", err
+	}
+	defer iter.Close()
+	if pathScope != "" {
+		iter.SeekGE(graveler.Key(pathScope))
+	}
+	var approvals []CodeOwnersApproval
+	seen := make(map[string]struct{})
+	for iter.Next() {
+		path := iter.Value().Key.String()
+		if !strings.HasPrefix(path, pathScope) {
+			break
+		}
+		pathOwners := owners.Owners(path)
+		if len(pathOwners) == 0 {
+			continue
+		}
+		key := strings.Join(pathOwners, " ")
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		approvals = append(approvals, CodeOwnersApproval{Path: path, Owners: pathOwners})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, "", err
+	}
+	return approvals, sourceCommitID.String(), nil
+}
+
+// isCommitProtected returns true if a branch protection rule blocks commits to branchID
+func (c *Catalog) isCommitProtected(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (bool, error) {
+	rules, _, err := c.Store.GetBranchProtectionRules(ctx, repository)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for pattern, blockedActions := range rules.BranchPatternToBlockedActions {
+		matcher, err := glob.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		if !matcher.Match(string(branchID)) {
+			continue
+		}
+		for _, action := range blockedActions.GetValue() {
+			if action == graveler.BranchProtectionBlockedAction_COMMIT {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// getCodeOwners reads the committed CODEOWNERS file of branchID, nil if it has none
+func (c *Catalog) getCodeOwners(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*CodeOwners, error) {
+	value, err := c.Store.Get(ctx, repository, graveler.Ref(branchID.String()+"@"), graveler.Key(CodeOwnersPath))
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry, err := ValueToEntry(value)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Size > maxCodeOwnersSize {
+		return nil, fmt.Errorf("%s size %d above %d: %w", CodeOwnersPath, entry.Size, maxCodeOwnersSize, ErrInvalidCodeOwners)
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   addressTypeToCatalog(entry.AddressType).ToIdentifierType(),
+		Identifier:       entry.Address,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	owners, err := ParseCodeOwners(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", CodeOwnersPath, err)
+	}
+	return owners, nil
+}
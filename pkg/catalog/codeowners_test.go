@@ -0,0 +1,117 @@
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	gUtils "github.com/treeverse/lakefs/pkg/graveler/testutil"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	const content = `
+# default owners
+*                  @data-platform
+finance/*          @finance-team   # everything finance
+finance/public/    analysts finance-team
+**/*.schema.json   schema-reviewers
+reports/daily
+`
+	owners, err := catalog.ParseCodeOwners(strings.NewReader(content))
+	require.NoError(t, err)
+
+	cases := []struct {
+		Path     string
+		Expected []string
+	}{
+		{Path: "readme.md", Expected: []string{"data-platform"}},
+		{Path: "events/2024/01/part-0.parquet", Expected: []string{"data-platform"}},
+		{Path: "finance/ledger.csv", Expected: []string{"finance-team"}},
+		{Path: "finance/q1/ledger.csv", Expected: []string{"finance-team"}},
+		{Path: "finance/public/summary.csv", Expected: []string{"analysts", "finance-team"}},
+		// only directories match a pattern ending with '/'
+		{Path: "finance/public", Expected: []string{"finance-team"}},
+		{Path: "events/users.schema.json", Expected: []string{"schema-reviewers"}},
+		// a rule without owners leaves the paths it matches unowned
+		{Path: "reports/daily/2024-01-01.csv", Expected: nil},
+		{Path: "reports/weekly/2024-01.csv", Expected: []string{"data-platform"}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Path, func(t *testing.T) {
+			require.Equal(t, tt.Expected, owners.Owners(tt.Path))
+		})
+	}
+}
+
+func TestParseCodeOwners_Invalid(t *testing.T) {
+	for _, content := range []string{"/ admins", "finance/[ finance-team"} {
+		t.Run(content, func(t *testing.T) {
+			_, err := catalog.ParseCodeOwners(strings.NewReader(content))
+			if !errors.Is(err, catalog.ErrInvalidCodeOwners) {
+				t.Fatalf("ParseCodeOwners(%q) err=%v, expected %v", content, err, catalog.ErrInvalidCodeOwners)
+			}
+		})
+	}
+}
+
+func TestCatalog_MergeCodeOwners(t *testing.T) {
+	ctx := context.Background()
+	const codeOwners = "finance/ finance-team\n"
+	adapter := mem.New(ctx)
+	err := adapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: "mem://repo1",
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       "codeowners",
+	}, int64(len(codeOwners)), strings.NewReader(codeOwners), block.PutOpts{})
+	require.NoError(t, err)
+	c := &catalog.Catalog{
+		Store: &catalog.FakeGraveler{
+			KeyValue: map[string]*graveler.Value{
+				"repo1/main@/" + catalog.CodeOwnersPath: catalog.MustEntryToValue(&catalog.Entry{
+					Address:     "codeowners",
+					AddressType: catalog.Entry_RELATIVE,
+					Size:        int64(len(codeOwners)),
+				}),
+			},
+			BranchProtectionRules: &graveler.BranchProtectionRules{
+				BranchPatternToBlockedActions: map[string]*graveler.BranchProtectionBlockedActions{
+					"main": {Value: []graveler.BranchProtectionBlockedAction{graveler.BranchProtectionBlockedAction_COMMIT}},
+				},
+			},
+			DiffIteratorFactory: func() graveler.DiffIterator {
+				return gUtils.NewDiffIter([]graveler.Diff{
+					{Key: graveler.Key("data/a"), Type: graveler.DiffTypeAdded},
+					{Key: graveler.Key("finance/b"), Type: graveler.DiffTypeAdded},
+				})
+			},
+		},
+		BlockAdapter: adapter,
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		approvals, commitID, err := c.MergeCodeOwners(ctx, "repo1", "main", "feature", "")
+		require.NoError(t, err)
+		require.Equal(t, []catalog.CodeOwnersApproval{{Path: "finance/b", Owners: []string{"finance-team"}}}, approvals)
+		require.Equal(t, "feature", commitID)
+	})
+
+	t.Run("path_scope", func(t *testing.T) {
+		approvals, commitID, err := c.MergeCodeOwners(ctx, "repo1", "main", "feature", "data/")
+		require.NoError(t, err)
+		require.Empty(t, approvals)
+		require.Equal(t, "feature", commitID)
+	})
+
+	t.Run("unprotected", func(t *testing.T) {
+		approvals, commitID, err := c.MergeCodeOwners(ctx, "repo1", "dev", "feature", "")
+		require.NoError(t, err)
+		require.Empty(t, approvals)
+		require.Empty(t, commitID)
+	})
+}
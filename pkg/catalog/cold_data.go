@@ -0,0 +1,353 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	coldDataReportsPrefix  = "cold"
+	coldDataReportIDFormat = "20060102T150405Z"
+
+	// coldDataCheckInterval is how often the analyzer checks for repositories due for a report
+	coldDataCheckInterval = 10 * time.Minute
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(objectAccessPartition, coldDataReportsPrefix, (&ColdDataReportData{}).ProtoReflect().Type())
+}
+
+// ColdPrefix is a prefix of a repository whose objects were neither read nor written for the inactivity period
+type ColdPrefix struct {
+	Prefix  string
+	Objects int64
+	// SizeBytes is the storage reclaimed by archiving the objects of the prefix
+	SizeBytes int64
+	// LastActivity is the last read or write of an object of the prefix
+	LastActivity time.Time
+}
+
+// ColdDataReport lists the cold prefixes of the default branch of a repository
+type ColdDataReport struct {
+	Repository      string
+	CommitID        string
+	CreatedAt       time.Time
+	InactiveFor     time.Duration
+	PhysicalAddress string
+	TotalObjects    int64
+	TotalBytes      int64
+	ColdObjects     int64
+	ColdBytes       int64
+	// Prefixes are the cold prefixes, by prefix
+	Prefixes []ColdPrefix
+}
+
+// ColdDataParams parameters to analyze the cold data of a repository
+type ColdDataParams struct {
+	// InactiveFor is the period without reads or writes after which a prefix is cold
+	InactiveFor time.Duration
+	// PrefixDepth is the number of directories of the prefixes analyzed, 0 analyzes the repository as a whole
+	PrefixDepth int
+	// Location in the storage namespace under which reports are written
+	Location string
+}
+
+type coldDataParquetRow struct {
+	Prefix       string `parquet:"name=prefix, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Objects      int64  `parquet:"name=objects, type=INT64, convertedtype=INT_64"`
+	SizeBytes    int64  `parquet:"name=size_bytes, type=INT64, convertedtype=INT_64"`
+	LastActivity int64  `parquet:"name=last_activity, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+func coldDataReportPath(repositoryID string) []byte {
+	return []byte(kv.FormatPath(coldDataReportsPrefix, repositoryID))
+}
+
+// coldDataPrefix returns the prefix of the first depth directories of p
+func coldDataPrefix(p string, depth int) string {
+	parts := strings.Split(p, DefaultPathDelimiter)
+	// the last part is the object name
+	if len(parts)-1 < depth {
+		depth = len(parts) - 1
+	}
+	if depth == 0 {
+		return ""
+	}
+	return strings.Join(parts[:depth], DefaultPathDelimiter) + DefaultPathDelimiter
+}
+
+func coldDataReportFromProto(pb *ColdDataReportData) *ColdDataReport {
+	report := &ColdDataReport{
+		Repository:      pb.Repository,
+		CommitID:        pb.CommitId,
+		CreatedAt:       time.Unix(0, pb.CreatedAt).UTC(),
+		InactiveFor:     time.Duration(pb.InactiveFor),
+		PhysicalAddress: pb.PhysicalAddress,
+		TotalObjects:    pb.TotalObjects,
+		TotalBytes:      pb.TotalBytes,
+		ColdObjects:     pb.ColdObjects,
+		ColdBytes:       pb.ColdBytes,
+		Prefixes:        make([]ColdPrefix, 0, len(pb.Prefixes)),
+	}
+	for _, p := range pb.Prefixes {
+		report.Prefixes = append(report.Prefixes, ColdPrefix{
+			Prefix:       p.Prefix,
+			Objects:      p.Objects,
+			SizeBytes:    p.SizeBytes,
+			LastActivity: time.Unix(0, p.LastActivity).UTC(),
+		})
+	}
+	return report
+}
+
+func protoFromColdDataReport(report *ColdDataReport) *ColdDataReportData {
+	pb := &ColdDataReportData{
+		Repository:      report.Repository,
+		CommitId:        report.CommitID,
+		CreatedAt:       report.CreatedAt.UnixNano(),
+		InactiveFor:     int64(report.InactiveFor),
+		PhysicalAddress: report.PhysicalAddress,
+		TotalObjects:    report.TotalObjects,
+		TotalBytes:      report.TotalBytes,
+		ColdObjects:     report.ColdObjects,
+		ColdBytes:       report.ColdBytes,
+		Prefixes:        make([]*ColdPrefixData, 0, len(report.Prefixes)),
+	}
+	for _, p := range report.Prefixes {
+		pb.Prefixes = append(pb.Prefixes, &ColdPrefixData{
+			Prefix:       p.Prefix,
+			Objects:      p.Objects,
+			SizeBytes:    p.SizeBytes,
+			LastActivity: p.LastActivity.UnixNano(),
+		})
+	}
+	return pb
+}
+
+// lastObjectAccess returns the last read of each object of a repository read since access tracking was enabled
+func (c *Catalog) lastObjectAccess(ctx context.Context, repositoryID string) (map[string]time.Time, error) {
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&ObjectAccessData{}).ProtoReflect().Type(), objectAccessPartition,
+		[]byte(objectAccessPathKey(repositoryID, "")), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	accessed := make(map[string]time.Time)
+	for it.Next() {
+		data := it.Entry().Value.(*ObjectAccessData)
+		t := time.Unix(0, data.LastAccessed)
+		if t.After(accessed[data.Path]) {
+			accessed[data.Path] = t
+		}
+	}
+	return accessed, it.Err()
+}
+
+// CreateColdDataReport analyzes the objects of the default branch of a repository by prefix, and reports the
+// prefixes whose objects were neither read nor written for params.InactiveFor. Objects are considered read as
+// tracked by object access tracking. The report is written as Parquet to the storage namespace of the repository and
+// replaces the last report of the repository kept for the summary API.
+func (c *Catalog) CreateColdDataReport(ctx context.Context, repositoryID string, params ColdDataParams) (*ColdDataReport, error) {
+	if params.InactiveFor <= 0 || params.PrefixDepth < 0 {
+		return nil, fmt.Errorf("cold data params: %w", graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(repository.DefaultBranchID))
+	if err != nil {
+		return nil, err
+	}
+	accessed, err := c.lastObjectAccess(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	report := &ColdDataReport{
+		Repository:  repositoryID,
+		CommitID:    commitID.String(),
+		CreatedAt:   now,
+		InactiveFor: params.InactiveFor,
+	}
+	prefixes := make(map[string]*ColdPrefix)
+	it, err := c.Store.List(ctx, repository, graveler.Ref(commitID), ListEntriesLimitMax)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for it.Next() {
+		c.BackgroundLimiter.Take()
+		v := it.Value()
+		entry, err := ValueToEntry(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		key := string(v.Key)
+		p := coldDataPrefix(key, params.PrefixDepth)
+		prefix, ok := prefixes[p]
+		if !ok {
+			prefix = &ColdPrefix{Prefix: p}
+			prefixes[p] = prefix
+		}
+		lastActivity := entry.LastModified.AsTime()
+		if t, ok := accessed[key]; ok && t.After(lastActivity) {
+			lastActivity = t
+		}
+		prefix.Objects++
+		prefix.SizeBytes += entry.Size
+		if lastActivity.After(prefix.LastActivity) {
+			prefix.LastActivity = lastActivity.UTC()
+		}
+		report.TotalObjects++
+		report.TotalBytes += entry.Size
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	cutoff := now.Add(-params.InactiveFor)
+	for _, prefix := range prefixes {
+		if !prefix.LastActivity.Before(cutoff) {
+			continue
+		}
+		report.Prefixes = append(report.Prefixes, *prefix)
+		report.ColdObjects += prefix.Objects
+		report.ColdBytes += prefix.SizeBytes
+	}
+	sort.Slice(report.Prefixes, func(i, j int) bool {
+		return report.Prefixes[i].Prefix < report.Prefixes[j].Prefix
+	})
+
+	report.PhysicalAddress, err = c.writeColdDataReport(ctx, repository, params.Location, report)
+	if err != nil {
+		return nil, fmt.Errorf("write cold data report: %w", err)
+	}
+	if err := kv.SetMsg(ctx, c.KVStore, objectAccessPartition, coldDataReportPath(repositoryID), protoFromColdDataReport(report)); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c *Catalog) writeColdDataReport(ctx context.Context, repository *graveler.RepositoryRecord, location string, report *ColdDataReport) (string, error) {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(coldDataParquetRow), gcParquetParallelNum)
+	if err != nil {
+		return "", err
+	}
+	pw.CompressionType = parquet.CompressionCodec_GZIP
+	for _, p := range report.Prefixes {
+		err := pw.Write(coldDataParquetRow{
+			Prefix:       p.Prefix,
+			Objects:      p.Objects,
+			SizeBytes:    p.SizeBytes,
+			LastActivity: p.LastActivity.UnixMilli(),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return "", err
+	}
+
+	identifier := path.Join(location, "cold-data-"+report.CreatedAt.Format(coldDataReportIDFormat)+".parquet")
+	obj := block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       identifier,
+		IdentifierType:   block.IdentifierTypeRelative,
+	}
+	if err := c.BlockAdapter.Put(ctx, obj, int64(buf.Len()), &buf, block.PutOpts{}); err != nil {
+		return "", err
+	}
+	qk, err := c.BlockAdapter.ResolveNamespace(obj.StorageNamespace, obj.Identifier, obj.IdentifierType)
+	if err != nil {
+		return "", err
+	}
+	return qk.Format(), nil
+}
+
+// GetColdDataReport returns the last cold data report of a repository
+func (c *Catalog) GetColdDataReport(ctx context.Context, repositoryID string) (*ColdDataReport, error) {
+	if _, err := c.getRepository(ctx, repositoryID); err != nil {
+		return nil, err
+	}
+	data := &ColdDataReportData{}
+	_, err := kv.GetMsg(ctx, c.KVStore, objectAccessPartition, coldDataReportPath(repositoryID), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", repositoryID, ErrColdDataReportNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return coldDataReportFromProto(data), nil
+}
+
+// ColdDataReportsConfig configures periodic cold data reports
+type ColdDataReportsConfig struct {
+	Interval    time.Duration
+	InactiveFor time.Duration
+	PrefixDepth int
+	Location    string
+}
+
+// StartColdDataReports reports the cold data of each repository once every interval, until ctx is done
+func (c *Catalog) StartColdDataReports(ctx context.Context, cfg ColdDataReportsConfig, logger logging.Logger) {
+	go func() {
+		ticker := time.NewTicker(coldDataCheckInterval)
+		defer ticker.Stop()
+		for {
+			if err := c.reportColdData(ctx, cfg, logger); err != nil {
+				logger.WithError(err).Error("Failed to create cold data reports")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *Catalog) reportColdData(ctx context.Context, cfg ColdDataReportsConfig, logger logging.Logger) error {
+	repositories, err := c.Store.ListRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	defer repositories.Close()
+	for repositories.Next() {
+		repositoryID := repositories.Value().RepositoryID.String()
+		report, err := c.GetColdDataReport(ctx, repositoryID)
+		if err == nil && time.Since(report.CreatedAt) < cfg.Interval {
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrColdDataReportNotFound) {
+			return err
+		}
+		_, err = c.CreateColdDataReport(ctx, repositoryID, ColdDataParams{
+			InactiveFor: cfg.InactiveFor,
+			PrefixDepth: cfg.PrefixDepth,
+			Location:    cfg.Location,
+		})
+		if err != nil {
+			// a failing repository does not stop reports of the others
+			logger.WithError(err).WithField("repository", repositoryID).Error("Failed to create cold data report")
+		}
+	}
+	return repositories.Err()
+}
@@ -0,0 +1,150 @@
+package catalog
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// DiffSummaryGroupsMax is the number of groups a diff summary reports, entries of further groups are only counted
+// in the summary totals
+const DiffSummaryGroupsMax = 1000
+
+type DiffSummaryParams struct {
+	Prefix string
+	// TwoDot compares the refs directly, instead of comparing right with the merge base of both refs
+	TwoDot bool
+}
+
+// DiffSummaryStats counts the entries and bytes of a diff by diff type. Removed bytes are the sizes of the removed
+// objects, changed bytes are the sizes of the changed objects on the right ref.
+type DiffSummaryStats struct {
+	AddedCount   int64
+	AddedBytes   int64
+	RemovedCount int64
+	RemovedBytes int64
+	ChangedCount int64
+	ChangedBytes int64
+}
+
+// DiffSummaryGroup holds the stats of the diff entries under the same top-level prefix with the same extension
+type DiffSummaryGroup struct {
+	// Prefix is the first path level under the summarized prefix, including the trailing delimiter, or empty for
+	// objects directly under the summarized prefix
+	Prefix string
+	// Extension is the lower-cased extension of the object name, including the dot, or empty if it has none
+	Extension string
+	Stats     DiffSummaryStats
+}
+
+type DiffSummary struct {
+	Groups []DiffSummaryGroup
+	Total  DiffSummaryStats
+	// Truncated is set when there are more than DiffSummaryGroupsMax groups
+	Truncated bool
+}
+
+func (s *DiffSummaryStats) add(diffType graveler.DiffType, size int64) {
+	switch diffType {
+	case graveler.DiffTypeAdded:
+		s.AddedCount++
+		s.AddedBytes += size
+	case graveler.DiffTypeRemoved:
+		s.RemovedCount++
+		s.RemovedBytes += size
+	case graveler.DiffTypeChanged:
+		s.ChangedCount++
+		s.ChangedBytes += size
+	}
+}
+
+// DiffSummary aggregates the diff between two refs under a prefix, grouped by top-level prefix and extension
+func (c *Catalog) DiffSummary(ctx context.Context, repositoryID, leftReference, rightReference string, params DiffSummaryParams) (*DiffSummary, error) {
+	left := graveler.Ref(leftReference)
+	right := graveler.Ref(rightReference)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "left", Value: left, Fn: graveler.ValidateRef},
+		{Name: "right", Value: right, Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var iter graveler.DiffIterator
+	if params.TwoDot {
+		iter, err = c.Store.Diff(ctx, repository, left, right)
+	} else {
+		iter, err = c.Store.Compare(ctx, repository, left, right)
+	}
+	if err != nil {
+		return nil, err
+	}
+	it := NewEntryDiffIterator(iter)
+	defer it.Close()
+	return summarizeDiff(it, params.Prefix)
+}
+
+func summarizeDiff(it EntryDiffIterator, prefix string) (*DiffSummary, error) {
+	type groupKey struct {
+		prefix    string
+		extension string
+	}
+	summary := &DiffSummary{}
+	groups := make(map[groupKey]*DiffSummaryStats)
+	it.SeekGE(Path(prefix))
+	for it.Next() {
+		v := it.Value()
+		p := string(v.Path)
+		if !strings.HasPrefix(p, prefix) {
+			break
+		}
+		var size int64
+		if v.Entry != nil {
+			size = v.Entry.Size
+		}
+		summary.Total.add(v.Type, size)
+
+		relPath := strings.TrimPrefix(p, prefix)
+		key := groupKey{extension: strings.ToLower(path.Ext(path.Base(relPath)))}
+		if idx := strings.Index(relPath, DefaultPathDelimiter); idx >= 0 {
+			key.prefix = relPath[:idx+1]
+		}
+		stats, ok := groups[key]
+		if !ok {
+			if len(groups) == DiffSummaryGroupsMax {
+				summary.Truncated = true
+				continue
+			}
+			stats = &DiffSummaryStats{}
+			groups[key] = stats
+		}
+		stats.add(v.Type, size)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	summary.Groups = make([]DiffSummaryGroup, 0, len(groups))
+	for key, stats := range groups {
+		summary.Groups = append(summary.Groups, DiffSummaryGroup{
+			Prefix:    key.prefix,
+			Extension: key.extension,
+			Stats:     *stats,
+		})
+	}
+	sort.Slice(summary.Groups, func(i, j int) bool {
+		if summary.Groups[i].Prefix != summary.Groups[j].Prefix {
+			return summary.Groups[i].Prefix < summary.Groups[j].Prefix
+		}
+		return summary.Groups[i].Extension < summary.Groups[j].Extension
+	})
+	return summary, nil
+}
@@ -0,0 +1,192 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	// DirectoryMarkersRepositoryMetadataKey repository metadata key enabling persistent directory markers on the repository
+	DirectoryMarkersRepositoryMetadataKey = apiutil.LakeFSMetadataPrefix + "directory-markers"
+
+	// DirectoryMarkerContentType content type of directory marker entries
+	DirectoryMarkerContentType = "application/x-directory"
+
+	directoryMarkerSuffix = "/"
+
+	// emptyObjectChecksum MD5 of empty content, the checksum of directory markers written by lakeFS
+	emptyObjectChecksum = "d41d8cd98f00b204e9800998ecf8427e"
+)
+
+// IsDirectoryMarkerPath reports whether path addresses a directory marker
+func IsDirectoryMarkerPath(path string) bool {
+	return strings.HasSuffix(path, directoryMarkerSuffix)
+}
+
+// IsDirectoryMarker reports whether the entry is a zero-byte directory marker
+func (e *DBEntry) IsDirectoryMarker() bool {
+	return !e.CommonLevel && IsDirectoryMarkerPath(e.Path) && e.Size == 0
+}
+
+// parentDirectory returns the directory marker path of the directory holding path, or empty string for the root
+func parentDirectory(path string) string {
+	idx := strings.LastIndex(strings.TrimSuffix(path, directoryMarkerSuffix), directoryMarkerSuffix)
+	if idx == -1 {
+		return ""
+	}
+	return path[:idx+len(directoryMarkerSuffix)]
+}
+
+// newDirectoryMarkersCache caches the per-repository setting with the repository cache configuration
+func newDirectoryMarkersCache(cfg *config.Config) cache.Cache {
+	repositoryCache := cfg.Graveler.RepositoryCache
+	if repositoryCache.Size == 0 {
+		return cache.NoCache
+	}
+	return cache.NewCache(repositoryCache.Size, repositoryCache.Expiry, cache.NewJitterFn(repositoryCache.Jitter))
+}
+
+// directoryMarkersEnabled reports whether the repository persists directory markers. The setting is cached like
+// repository records, so changing it takes effect once the cached value expires.
+func (c *Catalog) directoryMarkersEnabled(ctx context.Context, repository *graveler.RepositoryRecord) (bool, error) {
+	if c.directoryMarkersCache == nil {
+		return c.readDirectoryMarkersEnabled(ctx, repository)
+	}
+	enabled, err := c.directoryMarkersCache.GetOrSet(repository.RepositoryID, func() (interface{}, error) {
+		return c.readDirectoryMarkersEnabled(ctx, repository)
+	})
+	if err != nil {
+		return false, err
+	}
+	return enabled.(bool), nil
+}
+
+func (c *Catalog) readDirectoryMarkersEnabled(ctx context.Context, repository *graveler.RepositoryRecord) (bool, error) {
+	metadata, err := c.Store.GetRepositoryMetadata(ctx, repository.RepositoryID)
+	if err != nil {
+		return false, err
+	}
+	value, ok := metadata[DirectoryMarkersRepositoryMetadataKey]
+	if !ok {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("repository metadata %s: %w", DirectoryMarkersRepositoryMetadataKey, err)
+	}
+	return enabled, nil
+}
+
+// normalizeDirectoryMarker verifies a directory marker entry holds no data and sets its content type, so markers
+// written through the API and the S3 gateway are stored the same way.
+func (c *Catalog) normalizeDirectoryMarker(ctx context.Context, repository *graveler.RepositoryRecord, entry *DBEntry) error {
+	if !IsDirectoryMarkerPath(entry.Path) {
+		return nil
+	}
+	enabled, err := c.directoryMarkersEnabled(ctx, repository)
+	if err != nil || !enabled {
+		return err
+	}
+	if entry.Size != 0 {
+		return fmt.Errorf("%s: %w", entry.Path, ErrInvalidDirectoryMarker)
+	}
+	entry.ContentType = DirectoryMarkerContentType
+	return nil
+}
+
+// existingPaths returns the paths found on the branch, used before deleting paths to learn which of them the delete
+// removes. It returns no paths when the repository does not persist directory markers.
+func (c *Catalog) existingPaths(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, paths []string) ([]string, error) {
+	enabled, err := c.directoryMarkersEnabled(ctx, repository)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	var existing []string
+	for _, path := range paths {
+		_, err := c.Store.Get(ctx, repository, graveler.Ref(branchID), graveler.Key(path))
+		if errors.Is(err, graveler.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, path)
+	}
+	return existing, nil
+}
+
+// keepParentDirectories writes a directory marker for each parent directory of the removed paths left with no
+// entries on the branch. removed holds only paths returned by existingPaths.
+func (c *Catalog) keepParentDirectories(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, removed []string, opts ...graveler.SetOptionsFunc) error {
+	checked := make(map[string]struct{})
+	for _, path := range removed {
+		dir := parentDirectory(path)
+		if dir == "" {
+			continue
+		}
+		if _, ok := checked[dir]; ok {
+			continue
+		}
+		checked[dir] = struct{}{}
+		empty, err := c.isEmptyDirectory(ctx, repository, branchID, dir)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			continue
+		}
+		if err := c.writeDirectoryMarker(ctx, repository, branchID, dir, opts...); err != nil {
+			return fmt.Errorf("directory marker %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) isEmptyDirectory(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, dir string) (bool, error) {
+	it, err := c.Store.List(ctx, repository, graveler.Ref(branchID), 1)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	it.SeekGE(graveler.Key(dir))
+	if !it.Next() {
+		return true, it.Err()
+	}
+	return !strings.HasPrefix(it.Value().Key.String(), dir), nil
+}
+
+func (c *Catalog) writeDirectoryMarker(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, dir string, opts ...graveler.SetOptionsFunc) error {
+	address := c.PathProvider.NewPath()
+	obj := block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	}
+	if err := c.BlockAdapter.Put(ctx, obj, 0, bytes.NewReader(nil), block.PutOpts{}); err != nil {
+		return err
+	}
+	entry := NewDBEntryBuilder().
+		Path(dir).
+		PhysicalAddress(address).
+		AddressType(AddressTypeRelative).
+		CreationDate(time.Now()).
+		Checksum(emptyObjectChecksum).
+		ContentType(DirectoryMarkerContentType).
+		Build()
+	value, err := EntryToValue(newEntryFromCatalogEntry(entry))
+	if err != nil {
+		return err
+	}
+	return c.Store.Set(ctx, repository, branchID, graveler.Key(dir), *value, opts...)
+}
@@ -0,0 +1,186 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	// EncryptionAlgorithmMetadataKey entry metadata key used to store the server side encryption algorithm of the object data
+	EncryptionAlgorithmMetadataKey = apiutil.LakeFSMetadataPrefix + "encryption-algorithm"
+	// EncryptionKMSKeyIDMetadataKey entry metadata key used to store the KMS key id used to encrypt the object data
+	EncryptionKMSKeyIDMetadataKey = apiutil.LakeFSMetadataPrefix + "encryption-kms-key-id"
+)
+
+// EncryptionContext describes how the object data is encrypted on the underlying storage
+type EncryptionContext struct {
+	Algorithm string
+	KMSKeyID  string
+}
+
+// EncryptionContextFromMetadata extracts the encryption context stored on entry metadata
+func EncryptionContextFromMetadata(metadata Metadata) EncryptionContext {
+	return EncryptionContext{
+		Algorithm: metadata[EncryptionAlgorithmMetadataKey],
+		KMSKeyID:  metadata[EncryptionKMSKeyIDMetadataKey],
+	}
+}
+
+// SetMetadata stores the encryption context into entry metadata, replacing any context stored on it
+func (e EncryptionContext) SetMetadata(metadata Metadata) {
+	delete(metadata, EncryptionAlgorithmMetadataKey)
+	delete(metadata, EncryptionKMSKeyIDMetadataKey)
+	if e.Algorithm != "" {
+		metadata[EncryptionAlgorithmMetadataKey] = e.Algorithm
+	}
+	if e.KMSKeyID != "" {
+		metadata[EncryptionKMSKeyIDMetadataKey] = e.KMSKeyID
+	}
+}
+
+// BlockstoreEncryption returns the encryption context applied by the block adapter to data written through lakeFS
+func (c *Catalog) BlockstoreEncryption() EncryptionContext {
+	return c.blockstoreEncryption
+}
+
+// EncryptionKeyAccess decides who may read data encrypted with a restricted KMS key
+type EncryptionKeyAccess interface {
+	// ReadersMayUseKey reports whether every principal allowed to read path on the branch may use the KMS key
+	ReadersMayUseKey(ctx context.Context, repositoryID, branchID, path, kmsKeyID string) (bool, error)
+}
+
+// SetEncryptionKeyAccess sets the access checked before data encrypted with a restricted KMS key is placed on a
+// branch. Without it, such data cannot be placed on any branch it is not already on.
+func (c *Catalog) SetEncryptionKeyAccess(access EncryptionKeyAccess) {
+	c.encryptionKeyAccess = access
+}
+
+func newRestrictedEncryptionKeys(cfg *config.Config) map[string]struct{} {
+	keys := make(map[string]struct{}, len(cfg.Security.EncryptionRestrictions))
+	for _, r := range cfg.Security.EncryptionRestrictions {
+		keys[r.KMSKeyID] = struct{}{}
+	}
+	return keys
+}
+
+// checkEncryptionKey verifies that data encrypted using kmsKeyID may be placed at path on branchID: if the key is
+// restricted, every principal able to read it there must be allowed to use the key.
+func (c *Catalog) checkEncryptionKey(ctx context.Context, repositoryID string, branchID graveler.BranchID, path, kmsKeyID string) error {
+	if kmsKeyID == "" {
+		return nil
+	}
+	if _, ok := c.restrictedEncryptionKeys[kmsKeyID]; !ok {
+		return nil
+	}
+	if c.encryptionKeyAccess == nil {
+		return fmt.Errorf("%w: '%s' encrypted with key %s", ErrEncryptionKeyNotAllowed, path, kmsKeyID)
+	}
+	allowed, err := c.encryptionKeyAccess.ReadersMayUseKey(ctx, repositoryID, branchID.String(), path, kmsKeyID)
+	if err != nil {
+		return fmt.Errorf("check encryption key %s: %w", kmsKeyID, err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: '%s' encrypted with key %s", ErrEncryptionKeyNotAllowed, path, kmsKeyID)
+	}
+	return nil
+}
+
+// CheckEntryEncryption verifies that the data of entry may be placed at path on branch, used when the data is
+// copied without going through the catalog.
+func (c *Catalog) CheckEntryEncryption(ctx context.Context, repositoryID, branch, path string, entry *DBEntry) error {
+	return c.checkEncryptionKey(ctx, repositoryID, graveler.BranchID(branch), path, EncryptionContextFromMetadata(entry.Metadata).KMSKeyID)
+}
+
+// checkDiffEncryption verifies that the entries added or changed by diff may be placed on destination
+func (c *Catalog) checkDiffEncryption(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, diff graveler.DiffIterator) error {
+	it := NewEntryDiffIterator(diff)
+	defer it.Close()
+	for it.Next() {
+		v := it.Value()
+		if v.Entry == nil || v.Type == graveler.DiffTypeRemoved {
+			continue
+		}
+		if err := c.checkEncryptionKey(ctx, repository.RepositoryID.String(), destination, v.Path, EncryptionContextFromMetadata(v.Entry.Metadata).KMSKeyID); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// checkMergeEncryption verifies that merging source into destination will not expose data encrypted using a
+// restricted KMS key to readers of destination that may not use the key.
+func (c *Catalog) checkMergeEncryption(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, source graveler.Ref) error {
+	if len(c.restrictedEncryptionKeys) == 0 {
+		return nil
+	}
+	diff, err := c.Store.Compare(ctx, repository, graveler.Ref(destination), source)
+	if err != nil {
+		return err
+	}
+	return c.checkDiffEncryption(ctx, repository, destination, diff)
+}
+
+// checkCommitChangesEncryption verifies the data that cherry-picking (or, with revert, reverting) the commit at ref
+// relative to its parent parentNumber (1-based) places on destination.
+func (c *Catalog) checkCommitChangesEncryption(ctx context.Context, repository *graveler.RepositoryRecord, destination graveler.BranchID, ref graveler.Ref, parentNumber int, revert bool) error {
+	if len(c.restrictedEncryptionKeys) == 0 {
+		return nil
+	}
+	resolved, err := c.Store.Dereference(ctx, repository, ref)
+	if err != nil {
+		return err
+	}
+	commit, err := c.Store.GetCommit(ctx, repository, resolved.CommitID)
+	if err != nil {
+		return err
+	}
+	if parentNumber < 1 {
+		parentNumber = 1
+	}
+	if parentNumber > len(commit.Parents) {
+		// an out of range parent is rejected by graveler, reverting a commit without parents only removes data
+		if len(commit.Parents) > 0 || revert {
+			return nil
+		}
+		// cherry-picking a commit without parents adds all of its data
+		values, err := c.Store.List(ctx, repository, resolved.CommitID.Ref(), ListEntriesLimitMax)
+		if err != nil {
+			return err
+		}
+		it := NewValueToEntryIterator(values)
+		defer it.Close()
+		for it.Next() {
+			v := it.Value()
+			if err := c.checkEncryptionKey(ctx, repository.RepositoryID.String(), destination, string(v.Path), EncryptionContextFromMetadata(v.Metadata).KMSKeyID); err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	}
+	left, right := commit.Parents[parentNumber-1].Ref(), resolved.CommitID.Ref()
+	if revert {
+		left, right = right, left
+	}
+	diff, err := c.Store.Diff(ctx, repository, left, right)
+	if err != nil {
+		return err
+	}
+	return c.checkDiffEncryption(ctx, repository, destination, diff)
+}
+
+// checkImportEncryption verifies that data imported under prefixes of branch may be encrypted with any restricted
+// key: the keys of imported objects are not known to lakeFS.
+func (c *Catalog) checkImportEncryption(ctx context.Context, repositoryID string, branch graveler.BranchID, prefixes []string) error {
+	for kmsKeyID := range c.restrictedEncryptionKeys {
+		for _, prefix := range prefixes {
+			if err := c.checkEncryptionKey(ctx, repositoryID, branch, prefix, kmsKeyID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
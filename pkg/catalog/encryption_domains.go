@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+const (
+	encryptionDomainsCacheSize   = 1000
+	encryptionDomainsCacheExpiry = 10 * time.Second
+	encryptionDomainsCacheJitter = encryptionDomainsCacheExpiry / 2
+
+	// EncryptionAlgorithmAWSKMS is the server side encryption of data written to S3 using the KMS key of an
+	// encryption domain
+	EncryptionAlgorithmAWSKMS = "aws:kms"
+)
+
+// encryptionDomainAlgorithm returns the server side encryption algorithm of data written using the KMS key of an
+// encryption domain to a blockstore of blockstoreType, or "" if the blockstore does not encrypt using KMS keys
+func encryptionDomainAlgorithm(blockstoreType string) string {
+	switch blockstoreType {
+	case block.BlockstoreTypeS3:
+		return EncryptionAlgorithmAWSKMS
+	case block.BlockstoreTypeGS:
+		return config.EncryptionAlgorithmGCPKMS
+	default:
+		return ""
+	}
+}
+
+// repositoryKMSKeyID returns the KMS key of the encryption domain of a repository: the key of its project, or of
+// the organization of its project if the project has none. Repositories outside of projects have no key.
+func repositoryKMSKeyID(ctx context.Context, store kv.Store, repositoryID string) (string, error) {
+	organizationID, projectID, _, ok := ParseProjectRepositoryID(repositoryID)
+	if !ok {
+		return "", nil
+	}
+	project := &ProjectData{}
+	_, err := kv.GetMsg(ctx, store, projectsPartition, projectPath(organizationID, projectID), project)
+	if errors.Is(err, kv.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if project.KmsKeyId != "" {
+		return project.KmsKeyId, nil
+	}
+	organization := &OrganizationData{}
+	_, err = kv.GetMsg(ctx, store, projectsPartition, organizationPath(organizationID), organization)
+	if errors.Is(err, kv.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return organization.KmsKeyId, nil
+}
+
+// newKMSKeyResolver returns a resolver of the KMS keys of the encryption domains of repositories. Keys are cached,
+// so changed keys apply to data written once the cache expires.
+func newKMSKeyResolver(store kv.Store) block.KMSKeyResolver {
+	keys := cache.NewCache(encryptionDomainsCacheSize, encryptionDomainsCacheExpiry, cache.NewJitterFn(encryptionDomainsCacheJitter))
+	return func(ctx context.Context, repository string) (string, error) {
+		kmsKeyID, err := keys.GetOrSet(repository, func() (interface{}, error) {
+			return repositoryKMSKeyID(ctx, store, repository)
+		})
+		if err != nil {
+			return "", err
+		}
+		return kmsKeyID.(string), nil
+	}
+}
+
+// EncryptionDomainAdapter returns adapter encrypting the data written to each repository using the KMS key of its
+// encryption domain
+func (c *Catalog) EncryptionDomainAdapter(adapter block.Adapter) block.Adapter {
+	return block.NewEncryptionDomainAdapter(adapter, c.resolveKMSKeyID)
+}
+
+func (c *Catalog) resolveKMSKeyID(ctx context.Context, repositoryID string) (string, error) {
+	if c.kmsKeyResolver != nil {
+		return c.kmsKeyResolver(ctx, repositoryID)
+	}
+	return repositoryKMSKeyID(ctx, c.KVStore, repositoryID)
+}
+
+// RepositoryKMSKeyID returns the KMS key of the encryption domain of a repository, the key of its project or of
+// the organization of its project, or "" if it has none
+func (c *Catalog) RepositoryKMSKeyID(ctx context.Context, repositoryID string) (string, error) {
+	return repositoryKMSKeyID(ctx, c.KVStore, repositoryID)
+}
+
+// RepositoryEncryption returns the encryption context applied by the block adapter to data written to a
+// repository through lakeFS: the KMS key of its encryption domain if it has one, otherwise the configured
+// encryption
+func (c *Catalog) RepositoryEncryption(ctx context.Context, repositoryID string) (EncryptionContext, error) {
+	kmsKeyID, err := c.resolveKMSKeyID(ctx, repositoryID)
+	if err != nil {
+		return EncryptionContext{}, err
+	}
+	if kmsKeyID == "" {
+		return c.blockstoreEncryption, nil
+	}
+	return EncryptionContext{
+		Algorithm: encryptionDomainAlgorithm(c.BlockAdapter.BlockstoreType()),
+		KMSKeyID:  kmsKeyID,
+	}, nil
+}
+
+// checkEncryptionDomainKey verifies that the blockstore can encrypt data using the KMS key of an encryption domain
+func (c *Catalog) checkEncryptionDomainKey(kmsKeyID string) error {
+	if kmsKeyID == "" {
+		return nil
+	}
+	if encryptionDomainAlgorithm(c.BlockAdapter.BlockstoreType()) == "" {
+		return ErrEncryptionDomainsNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// branchKeyAccess allows the keys listed for each branch
+type branchKeyAccess map[string][]string
+
+func (a branchKeyAccess) ReadersMayUseKey(_ context.Context, _, branchID, _, kmsKeyID string) (bool, error) {
+	for _, k := range a[branchID] {
+		if k == kmsKeyID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCheckEncryptionKey(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Security.EncryptionRestrictions = []struct {
+		KMSKeyID string `mapstructure:"kms_key_id"`
+	}{
+		{KMSKeyID: "finance-key"},
+		{KMSKeyID: "locked-key"},
+	}
+	c := &Catalog{restrictedEncryptionKeys: newRestrictedEncryptionKeys(cfg)}
+	ctx := context.Background()
+
+	t.Run("no access", func(t *testing.T) {
+		err := c.checkEncryptionKey(ctx, "repo1", "finance", "a", "finance-key")
+		if !errors.Is(err, ErrEncryptionKeyNotAllowed) {
+			t.Errorf("checkEncryptionKey without access err=%v, expected %s", err, ErrEncryptionKeyNotAllowed)
+		}
+	})
+
+	c.SetEncryptionKeyAccess(branchKeyAccess{"finance": {"finance-key"}})
+	tests := []struct {
+		kmsKeyID string
+		branch   graveler.BranchID
+		expected bool
+	}{
+		{kmsKeyID: "finance-key", branch: "finance", expected: true},
+		{kmsKeyID: "finance-key", branch: "main", expected: false},
+		{kmsKeyID: "locked-key", branch: "finance", expected: false},
+		{kmsKeyID: "other-key", branch: "main", expected: true},
+		{kmsKeyID: "", branch: "main", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kmsKeyID+"_"+tt.branch.String(), func(t *testing.T) {
+			err := c.checkEncryptionKey(ctx, "repo1", tt.branch, "a", tt.kmsKeyID)
+			if tt.expected && err != nil {
+				t.Errorf("checkEncryptionKey(%s, %s) err=%s, expected allowed", tt.kmsKeyID, tt.branch, err)
+			}
+			if !tt.expected && !errors.Is(err, ErrEncryptionKeyNotAllowed) {
+				t.Errorf("checkEncryptionKey(%s, %s) err=%v, expected %s", tt.kmsKeyID, tt.branch, err, ErrEncryptionKeyNotAllowed)
+			}
+		})
+	}
+}
+
+func TestEncryptionContextMetadata(t *testing.T) {
+	metadata := Metadata{"user": "value", EncryptionKMSKeyIDMetadataKey: "old-key"}
+	EncryptionContext{Algorithm: "aws:kms", KMSKeyID: "key-1"}.SetMetadata(metadata)
+	got := EncryptionContextFromMetadata(metadata)
+	if got.Algorithm != "aws:kms" || got.KMSKeyID != "key-1" {
+		t.Errorf("EncryptionContextFromMetadata()=%+v", got)
+	}
+	if metadata["user"] != "value" {
+		t.Errorf("user metadata was modified: %v", metadata)
+	}
+	EncryptionContext{}.SetMetadata(metadata)
+	if got := EncryptionContextFromMetadata(metadata); got != (EncryptionContext{}) {
+		t.Errorf("EncryptionContextFromMetadata() after clearing=%+v", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/graveler"
 )
 
@@ -19,4 +20,61 @@ var (
 
 	ErrFeatureNotSupported = errors.New("feature not supported")
 	ErrNonEmptyRepository  = errors.New("non empty repository")
+
+	ErrEncryptionKeyNotAllowed = errors.New("encryption key not allowed on destination branch")
+
+	ErrInvalidAlias  = fmt.Errorf("invalid alias: %w", graveler.ErrInvalidValue)
+	ErrAliasLoop     = fmt.Errorf("too many levels of aliases: %w", graveler.ErrInvalidValue)
+	ErrAliasNotFound = fmt.Errorf("alias target: %w", graveler.ErrNotFound)
+
+	ErrReservedMetadataKey = fmt.Errorf("reserved metadata key: %w", graveler.ErrInvalidValue)
+
+	ErrInvalidDirectoryMarker = fmt.Errorf("directory marker with data: %w", graveler.ErrInvalidValue)
+
+	ErrInvalidLockTTL = fmt.Errorf("invalid lock ttl: %w", graveler.ErrInvalidValue)
+	ErrLockHeld       = fmt.Errorf("lock is held: %w", graveler.ErrConflictFound)
+	ErrLockNotHeld    = fmt.Errorf("lock is not held with token: %w", graveler.ErrConflictFound)
+	ErrLockNotFound   = fmt.Errorf("lock: %w", graveler.ErrNotFound)
+
+	ErrInvalidAttributionWindow  = fmt.Errorf("invalid attribution window: %w", graveler.ErrInvalidValue)
+	ErrAttributionReportExists   = fmt.Errorf("attribution report exists: %w", graveler.ErrConflictFound)
+	ErrAttributionReportNotFound = fmt.Errorf("attribution report: %w", graveler.ErrNotFound)
+
+	ErrColdDataReportNotFound = fmt.Errorf("cold data report: %w", graveler.ErrNotFound)
+
+	ErrInvalidLineageEdge = fmt.Errorf("invalid lineage edge: %w", graveler.ErrInvalidValue)
+
+	ErrTooManySampleObjects = fmt.Errorf("too many objects to sample: %w", graveler.ErrInvalidValue)
+	ErrInvalidSampleObject  = fmt.Errorf("cannot sample object: %w", graveler.ErrInvalidValue)
+	ErrNoSampleObjects      = fmt.Errorf("objects to sample: %w", graveler.ErrNotFound)
+
+	ErrInvalidParamKey    = fmt.Errorf("invalid parameter key: %w", graveler.ErrInvalidValue)
+	ErrParamValueTooLarge = fmt.Errorf("parameter value too large: %w", graveler.ErrInvalidValue)
+	ErrParamNotFound      = fmt.Errorf("parameter: %w", graveler.ErrNotFound)
+
+	ErrInvalidDatasetName     = fmt.Errorf("invalid dataset name: %w", graveler.ErrInvalidValue)
+	ErrInvalidDatasetVersion  = fmt.Errorf("invalid dataset version: %w", graveler.ErrInvalidValue)
+	ErrInvalidDatasetRelease  = fmt.Errorf("invalid dataset release: %w", graveler.ErrInvalidValue)
+	ErrDatasetReleaseExists   = fmt.Errorf("dataset release exists: %w", graveler.ErrConflictFound)
+	ErrDatasetReleaseNotFound = fmt.Errorf("dataset release: %w", graveler.ErrNotFound)
+
+	ErrInvalidMetadataGCMinAge = fmt.Errorf("invalid metadata gc min age: %w", graveler.ErrInvalidValue)
+
+	ErrOperationNotRunning = fmt.Errorf("operation not running: %w", graveler.ErrConflictFound)
+	ErrOperationCanceled   = errors.New("operation canceled")
+
+	ErrLocalGCNotSupported  = fmt.Errorf("local garbage collection requires the local blockstore: %w", block.ErrOperationNotSupported)
+	ErrInvalidLocalGCMinAge = fmt.Errorf("invalid local gc min age: %w", graveler.ErrInvalidValue)
+
+	ErrInvalidProjectID     = fmt.Errorf("invalid organization or project id: %w", graveler.ErrInvalidValue)
+	ErrInvalidProjectQuota  = fmt.Errorf("invalid project quota: %w", graveler.ErrInvalidValue)
+	ErrOrganizationExists   = fmt.Errorf("organization exists: %w", graveler.ErrConflictFound)
+	ErrOrganizationNotFound = fmt.Errorf("organization: %w", graveler.ErrNotFound)
+	ErrOrganizationNotEmpty = fmt.Errorf("organization has projects: %w", graveler.ErrConflictFound)
+	ErrProjectExists        = fmt.Errorf("project exists: %w", graveler.ErrConflictFound)
+	ErrProjectNotFound      = fmt.Errorf("project: %w", graveler.ErrNotFound)
+	ErrProjectNotEmpty      = fmt.Errorf("project has repositories: %w", graveler.ErrConflictFound)
+	ErrProjectQuotaExceeded = fmt.Errorf("project repository quota exceeded: %w", graveler.ErrConflictFound)
+
+	ErrEncryptionDomainsNotSupported = fmt.Errorf("encryption domain keys require an S3 or GS blockstore: %w", block.ErrOperationNotSupported)
 )
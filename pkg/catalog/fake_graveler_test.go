@@ -12,6 +12,7 @@ import (
 type FakeGraveler struct {
 	graveler.VersionController
 	KeyValue                   map[string]*graveler.Value
+	RepositoryMetadata         graveler.RepositoryMetadata
 	Err                        error
 	ListIteratorFactory        func() graveler.ValueIterator
 	DiffIteratorFactory        func() graveler.DiffIterator
@@ -19,6 +20,12 @@ type FakeGraveler struct {
 	BranchIteratorFactory      func() graveler.BranchIterator
 	TagIteratorFactory         func() graveler.TagIterator
 	LinkAddressIteratorFactory func() graveler.LinkAddressIterator
+	UnreachableCommits         []*graveler.CommitRecord
+	ReachableMetadata          *graveler.ReachableMetadata
+	ReachableMetadataParams    graveler.FindReachableMetadataParams
+	BranchProtectionRules      *graveler.BranchProtectionRules
+	Ranges                     []*graveler.RangeInfo
+	RangeValues                map[graveler.RangeID][]*graveler.ValueRecord
 	hooks                      graveler.HooksHandler
 }
 
@@ -31,6 +38,18 @@ func (g *FakeGraveler) GetRangeIDByKey(_ context.Context, _ *graveler.Repository
 	panic("implement me")
 }
 
+func (g *FakeGraveler) FindReachableMetadata(_ context.Context, _ *graveler.RepositoryRecord, params graveler.FindReachableMetadataParams) (*graveler.ReachableMetadata, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	g.ReachableMetadataParams = params
+	return g.ReachableMetadata, nil
+}
+
+func (g *FakeGraveler) FindKeyInRefs(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.Key, _ []graveler.Ref) ([]graveler.Ref, error) {
+	panic("implement me")
+}
+
 func (g *FakeGraveler) ParseRef(_ graveler.Ref) (graveler.RawRef, error) {
 	panic("implement me")
 }
@@ -117,10 +136,23 @@ func (g *FakeGraveler) Set(_ context.Context, repository *graveler.RepositoryRec
 }
 
 func (g *FakeGraveler) Delete(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, key graveler.Key, _ ...graveler.SetOptionsFunc) error {
+	delete(g.KeyValue, fakeGravelerBuildKey(repository.RepositoryID, graveler.Ref(branchID.String()), key))
 	return nil
 }
 
 func (g *FakeGraveler) DeleteBatch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, keys []graveler.Key, _ ...graveler.SetOptionsFunc) error {
+	for _, key := range keys {
+		delete(g.KeyValue, fakeGravelerBuildKey(repository.RepositoryID, graveler.Ref(branchID.String()), key))
+	}
+	return nil
+}
+
+func (g *FakeGraveler) Move(_ context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, srcKey, destKey graveler.Key, value graveler.Value, _ ...graveler.SetOptionsFunc) error {
+	if g.Err != nil {
+		return g.Err
+	}
+	delete(g.KeyValue, fakeGravelerBuildKey(repository.RepositoryID, graveler.Ref(branchID.String()), srcKey))
+	g.KeyValue[fakeGravelerBuildKey(repository.RepositoryID, graveler.Ref(branchID.String()), destKey)] = &value
 	return nil
 }
 
@@ -132,7 +164,11 @@ func (g *FakeGraveler) List(_ context.Context, _ *graveler.RepositoryRecord, _ g
 }
 
 func (g *FakeGraveler) GetRepository(ctx context.Context, repositoryID graveler.RepositoryID) (*graveler.RepositoryRecord, error) {
-	return &graveler.RepositoryRecord{RepositoryID: repositoryID}, nil
+	return &graveler.RepositoryRecord{RepositoryID: repositoryID, Repository: &graveler.Repository{StorageNamespace: graveler.StorageNamespace("mem://" + repositoryID.String())}}, nil
+}
+
+func (g *FakeGraveler) GetRepositoryMetadata(_ context.Context, _ graveler.RepositoryID) (graveler.RepositoryMetadata, error) {
+	return g.RepositoryMetadata, nil
 }
 
 func (g *FakeGraveler) CreateRepository(ctx context.Context, repositoryID graveler.RepositoryID, storageNamespace graveler.StorageNamespace, branchID graveler.BranchID, readOnly bool) (*graveler.RepositoryRecord, error) {
@@ -150,6 +186,14 @@ func (g *FakeGraveler) DeleteRepository(ctx context.Context, repositoryID gravel
 	panic("implement me")
 }
 
+func (g *FakeGraveler) ArchiveRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) RestoreRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	panic("implement me")
+}
+
 func (g *FakeGraveler) CreateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, _ ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
 	panic("implement me")
 }
@@ -197,10 +241,60 @@ func (g *FakeGraveler) ListTags(ctx context.Context, repository *graveler.Reposi
 	return g.TagIteratorFactory(), nil
 }
 
+func (g *FakeGraveler) ListDeletedRefs(_ context.Context, _ *graveler.RepositoryRecord) ([]*graveler.DeletedRef, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) GetBranchHistoryHead(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID) (*graveler.BranchHistoryRecord, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) VerifyBranchHistory(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ *graveler.BranchHistoryRecord) (*graveler.BranchHistoryVerification, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) DiffPrefixes(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.Ref, _ graveler.Key, _ graveler.Ref, _ graveler.Key) (graveler.DiffIterator, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) ListRanges(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.Ref) ([]*graveler.RangeInfo, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	return g.Ranges, nil
+}
+
+func (g *FakeGraveler) ListRange(_ context.Context, _ *graveler.RepositoryRecord, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	return NewFakeValueIterator(g.RangeValues[rangeID]), nil
+}
+
+func (g *FakeGraveler) RestoreBranch(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, _ ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
+	panic("implement me")
+}
+
+func (g *FakeGraveler) RestoreTag(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.TagID, _ ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
+	panic("implement me")
+}
+
 func (g *FakeGraveler) Log(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, firstParent bool, since *time.Time) (graveler.CommitIterator, error) {
 	panic("implement me")
 }
 
+func (g *FakeGraveler) FindUnreachableCommits(_ context.Context, _ *graveler.RepositoryRecord, fn graveler.UnreachableCommitFunc) error {
+	if g.Err != nil {
+		return g.Err
+	}
+	for _, commit := range g.UnreachableCommits {
+		if err := fn(commit, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (g *FakeGraveler) ListBranches(_ context.Context, _ *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
 	if g.Err != nil {
 		return nil, g.Err
@@ -232,8 +326,21 @@ func (g *FakeGraveler) GetCommit(ctx context.Context, repository *graveler.Repos
 	panic("implement me")
 }
 
+func (g *FakeGraveler) GetBranchProtectionRules(_ context.Context, _ *graveler.RepositoryRecord) (*graveler.BranchProtectionRules, *string, error) {
+	if g.BranchProtectionRules == nil {
+		return nil, nil, graveler.ErrNotFound
+	}
+	return g.BranchProtectionRules, nil, nil
+}
+
 func (g *FakeGraveler) Dereference(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref) (*graveler.ResolvedRef, error) {
-	panic("implement me")
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	return &graveler.ResolvedRef{
+		Type:         graveler.ReferenceTypeCommit,
+		BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: graveler.CommitID(ref)}},
+	}, nil
 }
 
 func (g *FakeGraveler) Reset(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, _ ...graveler.SetOptionsFunc) error {
@@ -0,0 +1,262 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	integrityLocation           = "integrity"
+	integrityLatestNotarization = "latest.json"
+	integrityNotarizationFormat = "20060102T150405.000000000Z"
+)
+
+// NotarizedBranch is the latest branch history record of a branch when it was notarized
+type NotarizedBranch struct {
+	CommitID string    `json:"commit_id"`
+	Time     time.Time `json:"time"`
+	Hash     string    `json:"hash"`
+}
+
+// Notarization records the integrity hashes of the latest branch history records of a repository in its storage
+// namespace, outside of the ref-store, so that rewriting the branch histories in the ref-store can be detected
+type Notarization struct {
+	Repository string                     `json:"repository"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	Branches   map[string]NotarizedBranch `json:"branches"`
+}
+
+// BranchIntegrity is the result of verifying the branch history of a branch
+type BranchIntegrity struct {
+	Branch string
+	// Records is the number of records with integrity hashes verified
+	Records int
+	// LatestCommitID, LatestTime and LatestHash describe the latest record, empty if the branch has no history
+	LatestCommitID string
+	LatestTime     time.Time
+	LatestHash     string
+	// Notarized reports that the branch history includes the record of the latest notarization
+	Notarized bool
+	// NotarizationExpired reports that the notarized record is older than the branch history retention
+	NotarizationExpired bool
+	// Error describes how the branch history breaks the hash chain or the notarization, "" if it does not
+	Error string
+}
+
+// RepositoryIntegrity is the result of verifying the branch histories of a repository
+type RepositoryIntegrity struct {
+	Repository string
+	// Verified reports that none of the branches failed verification
+	Verified bool
+	// NotarizedAt is the time of the latest notarization verified against, zero if there is none
+	NotarizedAt time.Time
+	Branches    []BranchIntegrity
+}
+
+func (c *Catalog) integrityObject(repository *graveler.RepositoryRecord, name string) block.ObjectPointer {
+	return block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       path.Join(c.blockStoragePrefix, integrityLocation, name),
+		IdentifierType:   block.IdentifierTypeRelative,
+	}
+}
+
+// NotarizeRepository writes the integrity hashes of the latest branch history records of the branches of a
+// repository to its storage namespace: a notarization object per call, and the latest notarization. Branches
+// whose history has no integrity hashes are not notarized.
+func (c *Catalog) NotarizeRepository(ctx context.Context, repositoryID string) (*Notarization, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: graveler.RepositoryID(repositoryID), Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	notarization := &Notarization{
+		Repository: repositoryID,
+		CreatedAt:  time.Now().UTC(),
+		Branches:   make(map[string]NotarizedBranch),
+	}
+	it, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for it.Next() {
+		branchID := it.Value().BranchID
+		head, err := c.Store.GetBranchHistoryHead(ctx, repository, branchID)
+		if errors.Is(err, graveler.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if head.Hash == "" {
+			continue
+		}
+		notarization.Branches[branchID.String()] = NotarizedBranch{
+			CommitID: head.CommitID.String(),
+			Time:     head.Time.UTC(),
+			Hash:     head.Hash,
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(notarization)
+	if err != nil {
+		return nil, err
+	}
+	ctx = block.WithRepository(ctx, repositoryID)
+	name := "notarization-" + notarization.CreatedAt.Format(integrityNotarizationFormat) + ".json"
+	for _, obj := range []block.ObjectPointer{c.integrityObject(repository, name), c.integrityObject(repository, integrityLatestNotarization)} {
+		if err := c.BlockAdapter.Put(ctx, obj, int64(len(data)), bytes.NewReader(data), block.PutOpts{}); err != nil {
+			return nil, err
+		}
+	}
+	return notarization, nil
+}
+
+// getLatestNotarization returns the latest notarization of a repository, nil if it was never notarized
+func (c *Catalog) getLatestNotarization(ctx context.Context, repository *graveler.RepositoryRecord) (*Notarization, error) {
+	obj := c.integrityObject(repository, integrityLatestNotarization)
+	exists, err := c.BlockAdapter.Exists(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	reader, err := c.BlockAdapter.Get(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	notarization := &Notarization{}
+	if err := json.Unmarshal(data, notarization); err != nil {
+		return nil, fmt.Errorf("notarization of %s: %w", repository.RepositoryID, err)
+	}
+	return notarization, nil
+}
+
+// VerifyRepositoryIntegrity verifies the integrity hash chains of the branch histories of the branches of a
+// repository, and that they include the records of its latest notarization
+func (c *Catalog) VerifyRepositoryIntegrity(ctx context.Context, repositoryID string) (*RepositoryIntegrity, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: graveler.RepositoryID(repositoryID), Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	notarization, err := c.getLatestNotarization(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	result := &RepositoryIntegrity{
+		Repository: repositoryID,
+		Verified:   true,
+	}
+	if notarization != nil {
+		result.NotarizedAt = notarization.CreatedAt
+	}
+
+	it, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for it.Next() {
+		branchID := it.Value().BranchID
+		var notarized *graveler.BranchHistoryRecord
+		if notarization != nil {
+			if b, ok := notarization.Branches[branchID.String()]; ok {
+				notarized = &graveler.BranchHistoryRecord{
+					BranchID: branchID,
+					CommitID: graveler.CommitID(b.CommitID),
+					Time:     b.Time,
+					Hash:     b.Hash,
+				}
+			}
+		}
+		verification, err := c.Store.VerifyBranchHistory(ctx, repository, branchID, notarized)
+		if err != nil {
+			return nil, err
+		}
+		branch := BranchIntegrity{
+			Branch:              branchID.String(),
+			Records:             verification.Records,
+			Notarized:           verification.Notarized,
+			NotarizationExpired: verification.NotarizationExpired,
+			Error:               verification.Error,
+		}
+		if verification.Latest != nil {
+			branch.LatestCommitID = verification.Latest.CommitID.String()
+			branch.LatestTime = verification.Latest.Time.UTC()
+			branch.LatestHash = verification.Latest.Hash
+		}
+		if branch.Error != "" {
+			result.Verified = false
+		}
+		result.Branches = append(result.Branches, branch)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StartIntegrityNotarization notarizes the branch histories of each repository once every interval, until ctx is
+// done
+func (c *Catalog) StartIntegrityNotarization(ctx context.Context, interval time.Duration, logger logging.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.notarizeRepositories(ctx, logger); err != nil {
+					logger.WithError(err).Error("Failed to notarize branch histories")
+				}
+			}
+		}
+	}()
+}
+
+func (c *Catalog) notarizeRepositories(ctx context.Context, logger logging.Logger) error {
+	repositories, err := c.Store.ListRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	defer repositories.Close()
+	for repositories.Next() {
+		repositoryID := repositories.Value().RepositoryID.String()
+		if _, err := c.NotarizeRepository(ctx, repositoryID); err != nil {
+			// a failing repository does not stop notarizing the others
+			logger.WithError(err).WithField("repository", repositoryID).Error("Failed to notarize branch histories")
+		}
+	}
+	return repositories.Err()
+}
@@ -0,0 +1,40 @@
+package catalog
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/ratelimit"
+)
+
+// backgroundLimiter rate limits background operations. The rate can be changed while the limiter is in use.
+type backgroundLimiter struct {
+	mu      sync.RWMutex
+	limiter ratelimit.Limiter
+}
+
+func newLimiter(rateLimit int) *backgroundLimiter {
+	l := &backgroundLimiter{}
+	l.SetRate(rateLimit)
+	return l
+}
+
+func (l *backgroundLimiter) Take() time.Time {
+	l.mu.RLock()
+	limiter := l.limiter
+	l.mu.RUnlock()
+	return limiter.Take()
+}
+
+// SetRate replaces the limiter rate by operations per second, zero means unlimited
+func (l *backgroundLimiter) SetRate(rateLimit int) {
+	var limiter ratelimit.Limiter
+	if rateLimit == 0 {
+		limiter = ratelimit.NewUnlimited()
+	} else {
+		limiter = ratelimit.New(rateLimit)
+	}
+	l.mu.Lock()
+	l.limiter = limiter
+	l.mu.Unlock()
+}
@@ -0,0 +1,578 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	lineagePrefix        = "lineage"
+	lineageCommitsPrefix = "commits"
+	lineageSourcesPrefix = "sources"
+	lineageRunsPrefix    = "runs"
+
+	LineageEdgeTypeProducedBy  = "produced_by"
+	LineageEdgeTypeDerivedFrom = "derived_from"
+	LineageEdgeTypeUsedBy      = "used_by"
+
+	// MLflowJobSystem is the job system of lineage edges linking commits to MLflow runs
+	MLflowJobSystem = "mlflow"
+
+	LineageNodeTypeCommit = "commit"
+	LineageNodeTypeJobRun = "job_run"
+
+	LineageDirectionUpstream   = "upstream"
+	LineageDirectionDownstream = "downstream"
+
+	ListLineageEdgesLimitMax = 1000
+	// DefaultLineageDepth is the number of edges followed from the queried commit when no depth is requested
+	DefaultLineageDepth = 5
+	MaxLineageDepth     = 50
+	// MaxLineageGraphEdges limits the size of a lineage graph, larger graphs are truncated
+	MaxLineageGraphEdges = 1000
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType("*", lineagePrefix, (&LineageEdgeData{}).ProtoReflect().Type())
+}
+
+// LineageEdge records the provenance of a commit, or of a path in it: the job run that produced it, or the data it
+// was derived from. used_by edges record the other direction, a job run that read the commit, e.g. the training run
+// of a model.
+type LineageEdge struct {
+	ID         string
+	Type       string
+	Repository string
+	CommitID   string
+	// Path limits the edge to objects under it, empty for the whole commit
+	Path string
+	// JobSystem, RunID and CodeVersion identify the job run of produced_by and used_by edges
+	JobSystem   string
+	RunID       string
+	CodeVersion string
+	// SourceRepository, SourceRef and SourcePath identify the data of derived_from edges. SourceCommitID is the commit
+	// SourceRef pointed to when the edge was created.
+	SourceRepository string
+	SourceRef        string
+	SourceCommitID   string
+	SourcePath       string
+	CreatedBy        string
+	CreatedAt        time.Time
+}
+
+func lineageEdgeFromProto(pb *LineageEdgeData) *LineageEdge {
+	return &LineageEdge{
+		ID:               pb.Id,
+		Type:             pb.Type,
+		Repository:       pb.Repository,
+		CommitID:         pb.CommitId,
+		Path:             pb.Path,
+		JobSystem:        pb.JobSystem,
+		RunID:            pb.RunId,
+		CodeVersion:      pb.CodeVersion,
+		SourceRepository: pb.SourceRepository,
+		SourceRef:        pb.SourceRef,
+		SourceCommitID:   pb.SourceCommitId,
+		SourcePath:       pb.SourcePath,
+		CreatedBy:        pb.CreatedBy,
+		CreatedAt:        time.Unix(0, pb.CreatedAt).UTC(),
+	}
+}
+
+func protoFromLineageEdge(e *LineageEdge) *LineageEdgeData {
+	return &LineageEdgeData{
+		Id:               e.ID,
+		Type:             e.Type,
+		Repository:       e.Repository,
+		CommitId:         e.CommitID,
+		Path:             e.Path,
+		JobSystem:        e.JobSystem,
+		RunId:            e.RunID,
+		CodeVersion:      e.CodeVersion,
+		SourceRepository: e.SourceRepository,
+		SourceRef:        e.SourceRef,
+		SourceCommitId:   e.SourceCommitID,
+		SourcePath:       e.SourcePath,
+		CreatedBy:        e.CreatedBy,
+		CreatedAt:        e.CreatedAt.UnixNano(),
+	}
+}
+
+// LineageNode is a commit, or a job run that produced one
+type LineageNode struct {
+	ID          string
+	Type        string
+	Repository  string
+	CommitID    string
+	JobSystem   string
+	RunID       string
+	CodeVersion string
+}
+
+// LineageCommitNodeID returns the ID of the lineage graph node of a commit
+func LineageCommitNodeID(repository, commitID string) string {
+	return LineageNodeTypeCommit + ":" + repository + "@" + commitID
+}
+
+// LineageJobRunNodeID returns the ID of the lineage graph node of a job run
+func LineageJobRunNodeID(jobSystem, runID string) string {
+	return LineageNodeTypeJobRun + ":" + jobSystem + "/" + runID
+}
+
+// From returns the ID of the node the edge comes from: the job run or the source commit, or the commit the edge is
+// attached to for used_by edges
+func (e *LineageEdge) From() string {
+	switch e.Type {
+	case LineageEdgeTypeProducedBy:
+		return LineageJobRunNodeID(e.JobSystem, e.RunID)
+	case LineageEdgeTypeUsedBy:
+		return LineageCommitNodeID(e.Repository, e.CommitID)
+	default:
+		return LineageCommitNodeID(e.SourceRepository, e.SourceCommitID)
+	}
+}
+
+// To returns the ID of the node the edge goes to: the commit the edge is attached to, or the job run for used_by
+// edges
+func (e *LineageEdge) To() string {
+	if e.Type == LineageEdgeTypeUsedBy {
+		return LineageJobRunNodeID(e.JobSystem, e.RunID)
+	}
+	return LineageCommitNodeID(e.Repository, e.CommitID)
+}
+
+// hasJobRun reports whether the edge links its commit to a job run
+func (e *LineageEdge) hasJobRun() bool {
+	return e.Type == LineageEdgeTypeProducedBy || e.Type == LineageEdgeTypeUsedBy
+}
+
+// LineageGraph holds the nodes and edges reachable from a commit
+type LineageGraph struct {
+	Nodes []*LineageNode
+	Edges []*LineageEdge
+	// Truncated is set when the graph holds MaxLineageGraphEdges edges and more were reachable
+	Truncated bool
+}
+
+// LineageGraphParams select the lineage graph of objects under Path at Ref of Repository
+type LineageGraphParams struct {
+	Repository string
+	// Ref is required for upstream graphs. Downstream graphs without a ref include everything derived from Path at any
+	// commit.
+	Ref       string
+	Path      string
+	Direction string
+	Depth     int
+	// CanRead reports whether lineage of repository may be read. Lineage is not followed into other repositories when
+	// it returns false.
+	CanRead func(repository string) bool
+}
+
+func lineageCommitPath(commitID graveler.CommitID, id string) []byte {
+	return []byte(kv.FormatPath(lineagePrefix, lineageCommitsPrefix, commitID.String(), id))
+}
+
+func lineageSourcePath(id string) []byte {
+	return []byte(kv.FormatPath(lineagePrefix, lineageSourcesPrefix, id))
+}
+
+func lineageRunPath(jobSystem, runID, id string) []byte {
+	return []byte(kv.FormatPath(lineagePrefix, lineageRunsPrefix, jobSystem, runID, id))
+}
+
+// lineagePathsOverlap reports whether objects under one path may be under the other, an empty path holds everything
+func lineagePathsOverlap(a, b string) bool {
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+func validateLineageEdge(v interface{}) error {
+	edge, ok := v.(*LineageEdge)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	switch edge.Type {
+	case LineageEdgeTypeProducedBy, LineageEdgeTypeUsedBy:
+		if edge.JobSystem == "" || edge.RunID == "" {
+			return fmt.Errorf("%s requires job system and run id: %w", edge.Type, ErrInvalidLineageEdge)
+		}
+		if strings.Contains(edge.JobSystem, kv.PathDelimiter) || strings.Contains(edge.RunID, kv.PathDelimiter) {
+			return fmt.Errorf("job system and run id may not contain %s: %w", kv.PathDelimiter, ErrInvalidLineageEdge)
+		}
+		if edge.SourceRepository != "" || edge.SourceRef != "" || edge.SourcePath != "" {
+			return fmt.Errorf("%s does not take a source: %w", edge.Type, ErrInvalidLineageEdge)
+		}
+	case LineageEdgeTypeDerivedFrom:
+		if edge.SourceRepository == "" || edge.SourceRef == "" {
+			return fmt.Errorf("derived_from requires source repository and ref: %w", ErrInvalidLineageEdge)
+		}
+		if edge.JobSystem != "" || edge.RunID != "" || edge.CodeVersion != "" {
+			return fmt.Errorf("derived_from does not take a job run: %w", ErrInvalidLineageEdge)
+		}
+	default:
+		return fmt.Errorf("type %s: %w", edge.Type, ErrInvalidLineageEdge)
+	}
+	return nil
+}
+
+// AddLineageEdge attaches edge to the commit ref points to in repositoryID. The source ref of derived_from edges is
+// resolved to its commit, so the edge keeps pointing to the data it was derived from.
+func (c *Catalog) AddLineageEdge(ctx context.Context, repositoryID, ref string, edge LineageEdge) (*LineageEdge, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(ref), Fn: graveler.ValidateRef},
+		{Name: "edge", Value: &edge, Fn: validateLineageEdge},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceRepository *graveler.RepositoryRecord
+	if edge.Type == LineageEdgeTypeDerivedFrom {
+		if err := validator.Validate([]validator.ValidateArg{
+			{Name: "source_repository", Value: edge.SourceRepository, Fn: graveler.ValidateRepositoryID},
+			{Name: "source_ref", Value: graveler.Ref(edge.SourceRef), Fn: graveler.ValidateRef},
+		}); err != nil {
+			return nil, err
+		}
+		sourceRepository, err = c.getRepository(ctx, edge.SourceRepository)
+		if err != nil {
+			return nil, fmt.Errorf("source repository: %w", err)
+		}
+		sourceCommitID, err := c.dereferenceCommitID(ctx, sourceRepository, graveler.Ref(edge.SourceRef))
+		if err != nil {
+			return nil, fmt.Errorf("source ref: %w", err)
+		}
+		edge.SourceCommitID = sourceCommitID.String()
+	}
+
+	edge.ID = xid.New().String()
+	edge.Repository = repositoryID
+	edge.CommitID = commitID.String()
+	edge.CreatedAt = time.Now().UTC()
+	data := protoFromLineageEdge(&edge)
+	if err := kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), lineageCommitPath(commitID, edge.ID), data); err != nil {
+		return nil, err
+	}
+	if sourceRepository != nil {
+		// index the edge on its source, for downstream lineage
+		if err := kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(sourceRepository), lineageSourcePath(edge.ID), data); err != nil {
+			return nil, err
+		}
+	}
+	if edge.hasJobRun() {
+		// index the edge on its job run, to find the commits of a run
+		if err := kv.SetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), lineageRunPath(edge.JobSystem, edge.RunID, edge.ID), data); err != nil {
+			return nil, err
+		}
+	}
+	return &edge, nil
+}
+
+// ListLineageEdges lists the lineage edges attached to the commit ref points to, in the order they were added
+func (c *Catalog) ListLineageEdges(ctx context.Context, repositoryID, ref string, limit int, after string) ([]*LineageEdge, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(ref), Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListLineageEdgesLimitMax {
+		limit = ListLineageEdgesLimitMax
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(ref))
+	if err != nil {
+		return nil, false, err
+	}
+	var afterKey []byte
+	if after != "" {
+		afterKey = lineageCommitPath(commitID, after)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&LineageEdgeData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		lineageCommitPath(commitID, ""), kv.IteratorOptionsAfter(afterKey))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var edges []*LineageEdge
+	for it.Next() {
+		if len(edges) == limit {
+			return edges, true, nil
+		}
+		edges = append(edges, lineageEdgeFromProto(it.Entry().Value.(*LineageEdgeData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return edges, false, nil
+}
+
+// ListJobRunLineage lists the lineage edges linking commits of repositoryID to a job run: the commits the run produced
+// and the commits it used, in the order they were added
+func (c *Catalog) ListJobRunLineage(ctx context.Context, repositoryID, jobSystem, runID string, limit int, after string) ([]*LineageEdge, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "job_system", Value: jobSystem, Fn: validator.ValidateRequiredString},
+		{Name: "run_id", Value: runID, Fn: validator.ValidateRequiredString},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListLineageEdgesLimitMax {
+		limit = ListLineageEdgesLimitMax
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	var afterKey []byte
+	if after != "" {
+		afterKey = lineageRunPath(jobSystem, runID, after)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&LineageEdgeData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		lineageRunPath(jobSystem, runID, ""), kv.IteratorOptionsAfter(afterKey))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var edges []*LineageEdge
+	for it.Next() {
+		if len(edges) == limit {
+			return edges, true, nil
+		}
+		edges = append(edges, lineageEdgeFromProto(it.Entry().Value.(*LineageEdgeData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return edges, false, nil
+}
+
+// lineageEdges returns the edges stored under prefix of the repository partition
+func (c *Catalog) lineageEdges(ctx context.Context, repository *graveler.RepositoryRecord, prefix []byte) ([]*LineageEdge, error) {
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&LineageEdgeData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		prefix, kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var edges []*LineageEdge
+	for it.Next() {
+		edges = append(edges, lineageEdgeFromProto(it.Entry().Value.(*LineageEdgeData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// lineageVisit is a commit, or a path in it, reached while building a lineage graph
+type lineageVisit struct {
+	repository string
+	commitID   string
+	path       string
+	depth      int
+}
+
+// GetLineageGraph returns the lineage graph of objects under a path: upstream, the job runs and data they were
+// produced by; downstream, the commits derived from them and the job runs that used them. Edges are followed up to the
+// requested depth.
+func (c *Catalog) GetLineageGraph(ctx context.Context, params LineageGraphParams) (*LineageGraph, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: params.Repository, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
+	if params.Direction != LineageDirectionUpstream && params.Direction != LineageDirectionDownstream {
+		return nil, fmt.Errorf("direction %s: %w", params.Direction, graveler.ErrInvalidValue)
+	}
+	if params.Direction == LineageDirectionUpstream && params.Ref == "" {
+		return nil, fmt.Errorf("ref: %w", graveler.ErrRequiredValue)
+	}
+	if params.Depth <= 0 {
+		params.Depth = DefaultLineageDepth
+	}
+	if params.Depth > MaxLineageDepth {
+		return nil, fmt.Errorf("depth %d: %w", params.Depth, graveler.ErrInvalidValue)
+	}
+	canRead := params.CanRead
+	if canRead == nil {
+		canRead = func(string) bool { return true }
+	}
+
+	repository, err := c.getRepository(ctx, params.Repository)
+	if err != nil {
+		return nil, err
+	}
+	var commitID string
+	if params.Ref != "" {
+		id, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(params.Ref))
+		if err != nil {
+			return nil, err
+		}
+		commitID = id.String()
+	}
+
+	g := &lineageGraphBuilder{
+		graph: &LineageGraph{},
+		nodes: make(map[string]struct{}),
+		edges: make(map[string]struct{}),
+	}
+	if commitID != "" {
+		g.addCommit(params.Repository, commitID)
+	}
+	visited := make(map[lineageVisit]struct{})
+	queue := []lineageVisit{{repository: params.Repository, commitID: commitID, path: params.Path}}
+	for len(queue) > 0 && !g.graph.Truncated {
+		v := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[v]; ok {
+			continue
+		}
+		visited[v] = struct{}{}
+
+		repository, err := c.getRepository(ctx, v.repository)
+		if errors.Is(err, graveler.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var next []lineageVisit
+		if params.Direction == LineageDirectionUpstream {
+			next, err = c.upstreamLineage(ctx, g, repository, v, canRead)
+		} else {
+			next, err = c.downstreamLineage(ctx, g, repository, v, canRead)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if v.depth+1 < params.Depth {
+			queue = append(queue, next...)
+		}
+	}
+	return g.graph, nil
+}
+
+func (c *Catalog) upstreamLineage(ctx context.Context, g *lineageGraphBuilder, repository *graveler.RepositoryRecord, v lineageVisit, canRead func(string) bool) ([]lineageVisit, error) {
+	edges, err := c.lineageEdges(ctx, repository, lineageCommitPath(graveler.CommitID(v.commitID), ""))
+	if err != nil {
+		return nil, err
+	}
+	var next []lineageVisit
+	for _, edge := range edges {
+		if edge.Type == LineageEdgeTypeUsedBy || !lineagePathsOverlap(edge.Path, v.path) {
+			continue
+		}
+		if !g.addEdge(edge) {
+			break
+		}
+		if edge.Type == LineageEdgeTypeDerivedFrom && canRead(edge.SourceRepository) {
+			next = append(next, lineageVisit{repository: edge.SourceRepository, commitID: edge.SourceCommitID, path: edge.SourcePath, depth: v.depth + 1})
+		}
+	}
+	return next, nil
+}
+
+func (c *Catalog) downstreamLineage(ctx context.Context, g *lineageGraphBuilder, repository *graveler.RepositoryRecord, v lineageVisit, canRead func(string) bool) ([]lineageVisit, error) {
+	if v.commitID != "" {
+		// job runs that used the commit, they are leaves of the graph
+		edges, err := c.lineageEdges(ctx, repository, lineageCommitPath(graveler.CommitID(v.commitID), ""))
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range edges {
+			if edge.Type != LineageEdgeTypeUsedBy || !lineagePathsOverlap(edge.Path, v.path) {
+				continue
+			}
+			if !g.addEdge(edge) {
+				return nil, nil
+			}
+		}
+	}
+	edges, err := c.lineageEdges(ctx, repository, lineageSourcePath(""))
+	if err != nil {
+		return nil, err
+	}
+	var next []lineageVisit
+	for _, edge := range edges {
+		if v.commitID != "" && edge.SourceCommitID != v.commitID {
+			continue
+		}
+		if !lineagePathsOverlap(edge.SourcePath, v.path) || !canRead(edge.Repository) {
+			continue
+		}
+		if !g.addEdge(edge) {
+			break
+		}
+		next = append(next, lineageVisit{repository: edge.Repository, commitID: edge.CommitID, path: edge.Path, depth: v.depth + 1})
+	}
+	return next, nil
+}
+
+type lineageGraphBuilder struct {
+	graph *LineageGraph
+	nodes map[string]struct{}
+	edges map[string]struct{}
+}
+
+func (g *lineageGraphBuilder) addNode(node *LineageNode) {
+	if _, ok := g.nodes[node.ID]; ok {
+		return
+	}
+	g.nodes[node.ID] = struct{}{}
+	g.graph.Nodes = append(g.graph.Nodes, node)
+}
+
+func (g *lineageGraphBuilder) addCommit(repository, commitID string) {
+	g.addNode(&LineageNode{
+		ID:         LineageCommitNodeID(repository, commitID),
+		Type:       LineageNodeTypeCommit,
+		Repository: repository,
+		CommitID:   commitID,
+	})
+}
+
+// addEdge adds edge and its nodes to the graph. Returns false, truncating the graph, if it is full.
+func (g *lineageGraphBuilder) addEdge(edge *LineageEdge) bool {
+	if _, ok := g.edges[edge.ID]; ok {
+		return true
+	}
+	if len(g.graph.Edges) == MaxLineageGraphEdges {
+		g.graph.Truncated = true
+		return false
+	}
+	g.edges[edge.ID] = struct{}{}
+	g.graph.Edges = append(g.graph.Edges, edge)
+	g.addCommit(edge.Repository, edge.CommitID)
+	if edge.hasJobRun() {
+		g.addNode(&LineageNode{
+			ID:          LineageJobRunNodeID(edge.JobSystem, edge.RunID),
+			Type:        LineageNodeTypeJobRun,
+			JobSystem:   edge.JobSystem,
+			RunID:       edge.RunID,
+			CodeVersion: edge.CodeVersion,
+		})
+	} else {
+		g.addCommit(edge.SourceRepository, edge.SourceCommitID)
+	}
+	return true
+}
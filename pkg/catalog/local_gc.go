@@ -0,0 +1,243 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/local"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultLocalGCMinAge protects recently written objects, which may have been uploaded but not yet linked
+	DefaultLocalGCMinAge = 6 * time.Hour
+
+	localGCListBatchSize  = 1000
+	localGCSweepBatchSize = 1000
+)
+
+type LocalGCParams struct {
+	// DryRun reports the objects and space garbage collection would reclaim without removing them
+	DryRun bool
+	// MinAge keeps objects modified more recently, DefaultLocalGCMinAge when zero
+	MinAge    time.Duration
+	CreatedBy string
+}
+
+// localAdapter returns the local adapter underlying adapter, or nil if it is not a local adapter
+func localAdapter(adapter block.Adapter) *local.Adapter {
+	for {
+		switch a := adapter.(type) {
+		case *local.Adapter:
+			return a
+		case interface{ InnerAdapter() block.Adapter }:
+			adapter = a.InnerAdapter()
+		default:
+			return nil
+		}
+	}
+}
+
+// RunLocalGC starts a background garbage collection of the repository objects on the local blockstore, which
+// Spark-based garbage collection cannot reach.  It snapshots the storage namespace with hard links, marks the
+// objects of the commits retained by the repository garbage collection rules and of all branches as live, and
+// removes the other snapshot objects older than params.MinAge in batches.  Objects written after the snapshot are
+// never removed.  Returns the task ID, whose operation reports progress and the space reclaimed.
+func (c *Catalog) RunLocalGC(ctx context.Context, repositoryID string, params LocalGCParams) (string, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return "", err
+	}
+	if params.MinAge < 0 {
+		return "", ErrInvalidLocalGCMinAge
+	}
+	if params.MinAge == 0 {
+		params.MinAge = DefaultLocalGCMinAge
+	}
+	adapter := localAdapter(c.BlockAdapter)
+	if adapter == nil {
+		return "", ErrLocalGCNotSupported
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if repository.ReadOnly {
+		return "", graveler.ErrReadOnlyRepository
+	}
+	// fail early on missing rules rather than in the background
+	if _, err := c.Store.GetGarbageCollectionRules(ctx, repository); err != nil {
+		return "", fmt.Errorf("get gc rules: %w", err)
+	}
+
+	taskID := NewTaskID(LocalGCTaskIDPrefix)
+	taskStatus := &TaskMsg{}
+	taskSteps := []taskStep{
+		{
+			Name: "garbage collection",
+			Func: func(ctx context.Context) error {
+				return c.localGC(ctx, repository, adapter, taskID, taskStatus, params)
+			},
+		},
+	}
+	description := "local garbage collection"
+	if params.DryRun {
+		description += " (dry run)"
+	}
+	info := operationInfo{
+		Kind:        OperationKindLocalGC,
+		Description: description,
+		CreatedBy:   params.CreatedBy,
+	}
+	if err := c.runBackgroundTaskSteps(repository, taskID, info, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+// localGC snapshots the repository storage namespace, marks its live objects and sweeps the other snapshot objects
+func (c *Catalog) localGC(ctx context.Context, repository *graveler.RepositoryRecord, adapter *local.Adapter, taskID string, taskStatus *TaskMsg, params LocalGCParams) error {
+	olderThan := time.Now().Add(-params.MinAge)
+	// snapshot before marking: objects linked while marking are either in the snapshot and marked, or not in it
+	snapshot, err := adapter.SnapshotNamespace(ctx, repository.StorageNamespace.String(), taskID)
+	if err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	defer func() {
+		if err := snapshot.Close(); err != nil {
+			c.log(ctx).WithError(err).WithField("task_id", taskID).Warn("Failed to remove local GC snapshot")
+		}
+	}()
+	c.operationLogf(taskID, "snapshot of %d objects", snapshot.Objects)
+
+	live, err := c.localGCLiveObjects(ctx, repository, adapter)
+	if err != nil {
+		return fmt.Errorf("mark: %w", err)
+	}
+	c.operationLogf(taskID, "marked %d live objects", len(live))
+
+	isLive := func(p string) bool {
+		_, ok := live[p]
+		return ok
+	}
+	progress := func(examined int64) error {
+		taskStatus.Task.Progress = examined
+		taskStatus.Task.UpdatedAt = timestamppb.Now()
+		return c.updateTaskProgress(ctx, repository, taskStatus.Task, taskStatus, snapshot.Objects)
+	}
+	result, err := snapshot.Sweep(ctx, isLive, olderThan, localGCSweepBatchSize, params.DryRun, progress)
+	verb := "removed"
+	if params.DryRun {
+		verb = "would remove"
+	}
+	c.operationLogf(taskID, "%s %d objects of %d bytes, reclaiming %d bytes", verb, result.Objects, result.Bytes, result.ReclaimedBytes)
+	if err != nil {
+		return fmt.Errorf("sweep: %w", err)
+	}
+	return nil
+}
+
+// localGCLiveObjects returns the paths of the objects of the commits retained by the repository garbage collection
+// rules and of all branches, including their staged objects
+func (c *Catalog) localGCLiveObjects(ctx context.Context, repository *graveler.RepositoryRecord, adapter *local.Adapter) (map[string]struct{}, error) {
+	refs, err := c.localGCRetainedCommits(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := c.Store.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	for branches.Next() {
+		refs = append(refs, graveler.Ref(branches.Value().BranchID))
+	}
+	err = branches.Err()
+	branches.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]struct{})
+	for _, ref := range refs {
+		if err := c.markLocalGCRef(ctx, repository, adapter, ref, live); err != nil {
+			return nil, fmt.Errorf("mark %s: %w", ref, err)
+		}
+	}
+	return live, nil
+}
+
+// localGCRetainedCommits returns a commit of each metarange retained by the repository garbage collection rules
+func (c *Catalog) localGCRetainedCommits(ctx context.Context, repository *graveler.RepositoryRecord) ([]graveler.Ref, error) {
+	runMetadata, err := c.Store.SaveGarbageCollectionCommits(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		Identifier:       runMetadata.CommitsCSVLocation,
+		IdentifierType:   block.IdentifierTypeFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read gc commits: %w", err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	csvReader := csv.NewReader(reader)
+	// skip the header: commit_id, expired, metarange_id
+	if _, err := csvReader.Read(); err != nil {
+		return nil, fmt.Errorf("read gc commits: %w", err)
+	}
+	var refs []graveler.Ref
+	metaRanges := make(map[string]struct{})
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read gc commits: %w", err)
+		}
+		const commitIDColumn, metaRangeIDColumn = 0, 2
+		if _, ok := metaRanges[record[metaRangeIDColumn]]; ok {
+			continue
+		}
+		metaRanges[record[metaRangeIDColumn]] = struct{}{}
+		refs = append(refs, graveler.Ref(record[commitIDColumn]))
+	}
+	return refs, nil
+}
+
+// markLocalGCRef adds the paths of the objects listed on ref to live
+func (c *Catalog) markLocalGCRef(ctx context.Context, repository *graveler.RepositoryRecord, adapter *local.Adapter, ref graveler.Ref, live map[string]struct{}) error {
+	it, err := c.Store.List(ctx, repository, ref, localGCListBatchSize)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		entry, err := ValueToEntry(it.Value().Value)
+		if err != nil {
+			return err
+		}
+		p, err := adapter.ObjectPath(block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace.String(),
+			Identifier:       entry.Address,
+			IdentifierType:   addressTypeToCatalog(entry.AddressType).ToIdentifierType(),
+		})
+		if err != nil {
+			// objects outside the storage namespace, such as imported ones, are never collected
+			continue
+		}
+		live[p] = struct{}{}
+	}
+	return it.Err()
+}
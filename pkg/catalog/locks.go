@@ -0,0 +1,269 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	nanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	locksPrefix = "locks"
+
+	// DefaultLockTTL is the time a lock is held when acquired or renewed without a TTL
+	DefaultLockTTL = 5 * time.Minute
+	// MaxLockTTL is the longest time a lock can be held without renewal
+	MaxLockTTL = 24 * time.Hour
+
+	lockTokenLength = 32
+	// lockCleanupBatchSize is the most expired lock records removed while acquiring a lock
+	lockCleanupBatchSize = 100
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType("*", locksPrefix, (&LockData{}).ProtoReflect().Type())
+}
+
+// Lock is an advisory lock held on a path of a branch. lakeFS does not block writes to locked paths, locks only
+// coordinate between clients that acquire them.
+type Lock struct {
+	Path       string
+	Owner      string
+	Token      string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+func lockFromProto(pb *LockData) *Lock {
+	return &Lock{
+		Path:       pb.Path,
+		Owner:      pb.Owner,
+		Token:      pb.Token,
+		AcquiredAt: time.Unix(0, pb.AcquiredAt).UTC(),
+		ExpiresAt:  time.Unix(0, pb.ExpiresAt).UTC(),
+	}
+}
+
+func protoFromLock(l *Lock) *LockData {
+	return &LockData{
+		Path:       l.Path,
+		Owner:      l.Owner,
+		Token:      l.Token,
+		AcquiredAt: l.AcquiredAt.UnixNano(),
+		ExpiresAt:  l.ExpiresAt.UnixNano(),
+	}
+}
+
+// Expired reports whether the lock is no longer held at t
+func (l *Lock) Expired(t time.Time) bool {
+	return !t.Before(l.ExpiresAt)
+}
+
+func lockPath(branchID graveler.BranchID, path string) []byte {
+	return []byte(kv.FormatPath(locksPrefix, branchID.String(), path))
+}
+
+func branchLocksPrefix(branchID graveler.BranchID) []byte {
+	return lockPath(branchID, "")
+}
+
+func validateLockTTL(v interface{}) error {
+	ttl, ok := v.(time.Duration)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	if ttl < 0 || ttl > MaxLockTTL {
+		return ErrInvalidLockTTL
+	}
+	return nil
+}
+
+func (c *Catalog) lockRecord(ctx context.Context, repositoryID, branch, path string, ttl time.Duration) (*graveler.RepositoryRecord, []byte, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+		{Name: "ttl", Value: ttl, Fn: validateLockTTL},
+	}); err != nil {
+		return nil, nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repository, lockPath(branchID, path), nil
+}
+
+// getLock returns the lock stored under key with its predicate, or a nil lock if none was stored
+func (c *Catalog) getLock(ctx context.Context, repository *graveler.RepositoryRecord, key []byte) (*Lock, kv.Predicate, error) {
+	data := &LockData{}
+	pred, err := kv.GetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), key, data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return lockFromProto(data), pred, nil
+}
+
+// AcquireLock acquires the advisory lock on path of the branch for ttl (DefaultLockTTL when zero). The returned lock
+// token is required to renew or release the lock. Fails with ErrLockHeld while the lock is held by anyone.
+func (c *Catalog) AcquireLock(ctx context.Context, repositoryID, branch, path, owner string, ttl time.Duration) (*Lock, error) {
+	repository, key, err := c.lockRecord(ctx, repositoryID, branch, path, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+	current, pred, err := c.getLock(ctx, repository, key)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if current != nil && !current.Expired(now) {
+		return nil, fmt.Errorf("%s: %w", path, ErrLockHeld)
+	}
+	if err := c.deleteExpiredLocks(ctx, repository, graveler.BranchID(branch), now); err != nil {
+		c.log(ctx).WithError(err).WithField("repository", repositoryID).WithField("branch", branch).Warn("Failed to delete expired locks")
+	}
+	lock := &Lock{
+		Path:       path,
+		Owner:      owner,
+		Token:      nanoid.Must(lockTokenLength),
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	err = kv.SetMsgIf(ctx, c.KVStore, graveler.RepoPartition(repository), key, protoFromLock(lock), pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s: %w", path, ErrLockHeld)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// GetLock returns the advisory lock currently held on path of the branch. The lock token is not returned.
+func (c *Catalog) GetLock(ctx context.Context, repositoryID, branch, path string) (*Lock, error) {
+	repository, key, err := c.lockRecord(ctx, repositoryID, branch, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	lock, _, err := c.getLock(ctx, repository, key)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil || lock.Expired(time.Now()) {
+		return nil, fmt.Errorf("%s: %w", path, ErrLockNotFound)
+	}
+	lock.Token = ""
+	return lock, nil
+}
+
+// RenewLock extends the advisory lock held with token on path of the branch by ttl (DefaultLockTTL when zero) from now
+func (c *Catalog) RenewLock(ctx context.Context, repositoryID, branch, path, token string, ttl time.Duration) (*Lock, error) {
+	repository, key, err := c.lockRecord(ctx, repositoryID, branch, path, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+	lock, pred, err := c.heldLock(ctx, repository, key, path, token)
+	if err != nil {
+		return nil, err
+	}
+	lock.ExpiresAt = time.Now().UTC().Add(ttl)
+	err = kv.SetMsgIf(ctx, c.KVStore, graveler.RepoPartition(repository), key, protoFromLock(lock), pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s: %w", path, ErrLockNotHeld)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// ReleaseLock releases the advisory lock held with token on path of the branch
+func (c *Catalog) ReleaseLock(ctx context.Context, repositoryID, branch, path, token string) error {
+	repository, key, err := c.lockRecord(ctx, repositoryID, branch, path, 0)
+	if err != nil {
+		return err
+	}
+	lock, pred, err := c.heldLock(ctx, repository, key, path, token)
+	if err != nil {
+		return err
+	}
+	// delete only the record read, so a release can never remove a lock acquired concurrently by another owner
+	err = c.KVStore.DeleteIf(ctx, []byte(graveler.RepoPartition(repository)), key, pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return fmt.Errorf("%s: %w", path, ErrLockNotHeld)
+	}
+	return err
+}
+
+// heldLock returns the lock stored under key if it is currently held with token
+func (c *Catalog) heldLock(ctx context.Context, repository *graveler.RepositoryRecord, key []byte, path, token string) (*Lock, kv.Predicate, error) {
+	lock, pred, err := c.getLock(ctx, repository, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lock == nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, ErrLockNotFound)
+	}
+	if lock.Token != token || lock.Expired(time.Now()) {
+		return nil, nil, fmt.Errorf("%s: %w", path, ErrLockNotHeld)
+	}
+	return lock, pred, nil
+}
+
+// deleteExpiredLocks removes up to lockCleanupBatchSize lock records of the branch that expired before now. A record
+// is deleted only if it was not changed since it was read, so locks acquired or renewed concurrently are kept.
+func (c *Catalog) deleteExpiredLocks(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, now time.Time) error {
+	partition := graveler.RepoPartition(repository)
+	it, err := kv.ScanPrefix(ctx, c.KVStore, []byte(partition), branchLocksPrefix(branchID), nil)
+	if err != nil {
+		return err
+	}
+	var expired [][]byte
+	for len(expired) < lockCleanupBatchSize && it.Next() {
+		entry := it.Entry()
+		data := &LockData{}
+		if err := proto.Unmarshal(entry.Value, data); err != nil {
+			it.Close()
+			return fmt.Errorf("lock %s: %w", entry.Key, err)
+		}
+		if lockFromProto(data).Expired(now) {
+			expired = append(expired, entry.Key)
+		}
+	}
+	err = it.Err()
+	it.Close()
+	if err != nil {
+		return err
+	}
+	for _, key := range expired {
+		lock, pred, err := c.getLock(ctx, repository, key)
+		if err != nil {
+			return err
+		}
+		if lock == nil || !lock.Expired(now) {
+			continue
+		}
+		err = c.KVStore.DeleteIf(ctx, []byte(partition), key, pred)
+		if err != nil && !errors.Is(err, kv.ErrPredicateFailed) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func TestCatalog_Locks(t *testing.T) {
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	c := &catalog.Catalog{
+		Store:   &catalog.FakeGraveler{},
+		KVStore: kvStore,
+	}
+	const (
+		repo   = "repo"
+		branch = "main"
+		path   = "tables/events/"
+	)
+	repository, err := c.Store.GetRepository(ctx, repo)
+	require.NoError(t, err)
+	lockRecords := func(branch string) int {
+		t.Helper()
+		it, err := kv.ScanPrefix(ctx, kvStore, []byte(graveler.RepoPartition(repository)), []byte(kv.FormatPath("locks", branch, "")), nil)
+		require.NoError(t, err)
+		defer it.Close()
+		n := 0
+		for it.Next() {
+			n++
+		}
+		require.NoError(t, it.Err())
+		return n
+	}
+
+	lock, err := c.AcquireLock(ctx, repo, branch, path, "compaction-1", time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, lock.Token)
+	require.Equal(t, "compaction-1", lock.Owner)
+
+	// held lock can't be acquired again
+	_, err = c.AcquireLock(ctx, repo, branch, path, "compaction-2", time.Minute)
+	require.ErrorIs(t, err, catalog.ErrLockHeld)
+
+	// locks are scoped to branch and path
+	_, err = c.AcquireLock(ctx, repo, "dev", path, "compaction-2", time.Minute)
+	require.NoError(t, err)
+	_, err = c.AcquireLock(ctx, repo, branch, "tables/users/", "compaction-2", time.Minute)
+	require.NoError(t, err)
+
+	got, err := c.GetLock(ctx, repo, branch, path)
+	require.NoError(t, err)
+	require.Equal(t, "compaction-1", got.Owner)
+	require.Empty(t, got.Token)
+
+	// renew requires the lock token
+	_, err = c.RenewLock(ctx, repo, branch, path, "wrong-token", time.Hour)
+	require.ErrorIs(t, err, catalog.ErrLockNotHeld)
+	renewed, err := c.RenewLock(ctx, repo, branch, path, lock.Token, time.Hour)
+	require.NoError(t, err)
+	require.True(t, renewed.ExpiresAt.After(lock.ExpiresAt))
+
+	_, err = c.AcquireLock(ctx, repo, branch, path, "compaction-2", 2*catalog.MaxLockTTL)
+	require.ErrorIs(t, err, catalog.ErrInvalidLockTTL)
+
+	// release requires the lock token, after release the lock can be acquired again
+	require.ErrorIs(t, c.ReleaseLock(ctx, repo, branch, path, "wrong-token"), catalog.ErrLockNotHeld)
+	require.NoError(t, c.ReleaseLock(ctx, repo, branch, path, lock.Token))
+	_, err = c.GetLock(ctx, repo, branch, path)
+	require.ErrorIs(t, err, catalog.ErrLockNotFound)
+	require.ErrorIs(t, c.ReleaseLock(ctx, repo, branch, path, lock.Token), catalog.ErrLockNotFound)
+	// released lock records are deleted
+	require.Equal(t, 1, lockRecords(branch))
+
+	lock2, err := c.AcquireLock(ctx, repo, branch, path, "compaction-2", 0)
+	require.NoError(t, err)
+	require.NotEqual(t, lock.Token, lock2.Token)
+	require.WithinDuration(t, lock2.AcquiredAt.Add(catalog.DefaultLockTTL), lock2.ExpiresAt, time.Second)
+
+	_, err = c.RenewLock(ctx, repo, branch, "tables/unknown/", lock2.Token, 0)
+	require.ErrorIs(t, err, catalog.ErrLockNotFound)
+
+	// expired lock records of the branch are deleted when acquiring a lock
+	_, err = c.AcquireLock(ctx, repo, "dev", "tables/expired/", "compaction-3", time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, 2, lockRecords("dev"))
+	_, err = c.AcquireLock(ctx, repo, "dev", "tables/other/", "compaction-3", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 2, lockRecords("dev"))
+	_, err = c.GetLock(ctx, repo, "dev", path)
+	require.NoError(t, err)
+}
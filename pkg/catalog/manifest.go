@@ -0,0 +1,220 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rs/xid"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type ManifestFormat string
+
+const (
+	// ManifestFormatSymlink writes a Hive SymlinkTextInputFormat symlink.txt file in each directory, listing
+	// the physical addresses of its objects
+	ManifestFormatSymlink ManifestFormat = "symlink"
+	// ManifestFormatParquet writes a single manifest.parquet file listing the objects with their physical
+	// addresses and sizes
+	ManifestFormatParquet ManifestFormat = "parquet"
+
+	manifestSymlinkFilename = "symlink.txt"
+	manifestParquetFilename = "manifest.parquet"
+)
+
+type ManifestParams struct {
+	// Prefix of the objects listed by the manifest
+	Prefix string
+	Format ManifestFormat
+	// Location in the storage namespace under which manifests are written
+	Location string
+}
+
+// ManifestInfo describes a written manifest
+type ManifestInfo struct {
+	// Location is the URI of the manifest root, the table location of engines reading it
+	Location string
+	// CommitID is the commit whose objects the manifest lists
+	CommitID string
+	Format   ManifestFormat
+	Objects  int
+}
+
+type manifestParquetRow struct {
+	Path             string `parquet:"name=path, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PhysicalAddress  string `parquet:"name=physical_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Size             int64  `parquet:"name=size, type=INT64, convertedtype=INT_64"`
+	ModificationTime int64  `parquet:"name=modification_time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+// WriteManifest writes a manifest of the objects under params.Prefix at ref into the storage namespace of the
+// repository, for engines that read the objects directly from the underlying storage.  The ref is resolved to a
+// commit, so the manifest lists a pinned snapshot that does not change as the branch moves.
+func (c *Catalog) WriteManifest(ctx context.Context, repositoryID, ref string, params ManifestParams) (*ManifestInfo, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(ref), Fn: graveler.ValidateRef},
+		{Name: "prefix", Value: Path(params.Prefix), Fn: ValidatePathOptional},
+	}); err != nil {
+		return nil, err
+	}
+	if params.Format != ManifestFormatSymlink && params.Format != ManifestFormatParquet {
+		return nil, fmt.Errorf("manifest format %s: %w", params.Format, graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	root := path.Join(params.Location, commitID.String(), xid.New().String())
+	var objects int
+	if params.Format == ManifestFormatSymlink {
+		objects, err = c.writeSymlinkManifest(ctx, repository, commitID, params.Prefix, root)
+	} else {
+		objects, err = c.writeParquetManifest(ctx, repository, commitID, params.Prefix, root)
+	}
+	if err != nil {
+		return nil, err
+	}
+	qk, err := c.BlockAdapter.ResolveNamespace(repository.StorageNamespace.String(), root, block.IdentifierTypeRelative)
+	if err != nil {
+		return nil, err
+	}
+	return &ManifestInfo{
+		Location: qk.Format(),
+		CommitID: commitID.String(),
+		Format:   params.Format,
+		Objects:  objects,
+	}, nil
+}
+
+// walkManifestEntries calls fn with each object under prefix at commitID and its qualified physical address
+func (c *Catalog) walkManifestEntries(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix string, fn func(p Path, entry *Entry, physicalAddress string) error) error {
+	it, err := c.Store.List(ctx, repository, graveler.Ref(commitID), ListEntriesLimitMax)
+	if err != nil {
+		return err
+	}
+	entries := NewEntryListingIterator(NewValueToEntryIterator(it), Path(prefix), "")
+	defer entries.Close()
+	for entries.Next() {
+		v := entries.Value()
+		// directory markers are not data files
+		if strings.HasSuffix(v.Path.String(), DefaultPathDelimiter) {
+			continue
+		}
+		qk, err := c.BlockAdapter.ResolveNamespace(repository.StorageNamespace.String(), v.Address, addressTypeToCatalog(v.AddressType).ToIdentifierType())
+		if err != nil {
+			return err
+		}
+		if err := fn(v.Path, v.Entry, qk.Format()); err != nil {
+			return err
+		}
+	}
+	return entries.Err()
+}
+
+// manifestDirectory returns the directory of p relative to prefix, "" for objects directly under prefix
+func manifestDirectory(prefix, p string) string {
+	if i := strings.LastIndex(prefix, DefaultPathDelimiter); i != -1 {
+		// directories are relative to the directory holding the prefix
+		p = p[i+1:]
+	}
+	dir := path.Dir(p)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+func (c *Catalog) writeSymlinkManifest(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix, root string) (int, error) {
+	// objects of a directory are not necessarily listed together, e.g. "a/b/c" is listed between "a/b" and "a/d"
+	addresses := make(map[string][]string)
+	objects := 0
+	err := c.walkManifestEntries(ctx, repository, commitID, prefix, func(p Path, _ *Entry, physicalAddress string) error {
+		dir := manifestDirectory(prefix, p.String())
+		addresses[dir] = append(addresses[dir], physicalAddress)
+		objects++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	dirs := make([]string, 0, len(addresses))
+	for dir := range addresses {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		data := strings.Join(addresses[dir], "\n") + "\n"
+		err := c.BlockAdapter.Put(ctx, block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace.String(),
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       path.Join(root, dir, manifestSymlinkFilename),
+		}, int64(len(data)), strings.NewReader(data), block.PutOpts{})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return objects, nil
+}
+
+func (c *Catalog) writeParquetManifest(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID, prefix, root string) (int, error) {
+	// manifests of large prefixes do not fit in memory
+	fd, err := os.CreateTemp("", "")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = fd.Close()
+		if err := os.Remove(fd.Name()); err != nil {
+			c.log(ctx).WithField("filename", fd.Name()).Warn("Failed to delete temporary manifest file")
+		}
+	}()
+	uw := NewUncommittedWriter(fd)
+	pw, err := writer.NewParquetWriterFromWriter(uw, new(manifestParquetRow), gcParquetParallelNum)
+	if err != nil {
+		return 0, err
+	}
+	pw.CompressionType = parquet.CompressionCodec_GZIP
+	objects := 0
+	err = c.walkManifestEntries(ctx, repository, commitID, prefix, func(p Path, entry *Entry, physicalAddress string) error {
+		objects++
+		return pw.Write(manifestParquetRow{
+			Path:             p.String(),
+			PhysicalAddress:  physicalAddress,
+			Size:             entry.Size,
+			ModificationTime: entry.LastModified.AsTime().UnixMilli(),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := pw.WriteStop(); err != nil {
+		return 0, err
+	}
+	if _, err := fd.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	err = c.BlockAdapter.Put(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       path.Join(root, manifestParquetFilename),
+	}, uw.Size(), fd, block.PutOpts{})
+	if err != nil {
+		return 0, err
+	}
+	return objects, nil
+}
@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mergeProgressInterval is the minimal time between task progress updates of a background merge
+const mergeProgressInterval = time.Second
+
+// MergeResult is the outcome of a merge running in the background
+type MergeResult struct {
+	Reference string
+	Err       error
+}
+
+// MergeSubmit starts a background task merging sourceRef into destinationBranch, taking the same arguments as Merge.
+// Task progress counts the source and destination ranges merged so far, out of the status RangesTotal.
+// Returns the task ID to poll using MergeStatus, and a channel receiving the result once the merge is done so callers
+// can wait for merges that end quickly.
+func (c *Catalog) MergeSubmit(ctx context.Context, repositoryID string, destinationBranch string, sourceRef string, committer string, message string, metadata Metadata, strategy string, opts ...graveler.SetOptionsFunc) (string, <-chan MergeResult, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "destination", Value: graveler.BranchID(destinationBranch), Fn: graveler.ValidateBranchID},
+		{Name: "source", Value: graveler.Ref(sourceRef), Fn: graveler.ValidateRef},
+	}); err != nil {
+		return "", nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	taskID := NewTaskID(MergeTaskIDPrefix)
+	taskStatus := &MergeStatus{DestinationBranch: destinationBranch}
+	done := make(chan MergeResult, 1)
+	taskSteps := []taskStep{
+		{
+			Name: "merge",
+			Func: func(ctx context.Context) error {
+				var lastUpdate time.Time
+				progress := func(processed, total int64) {
+					taskStatus.Task.Progress = processed
+					taskStatus.RangesTotal = total
+					if time.Since(lastUpdate) < mergeProgressInterval {
+						return
+					}
+					lastUpdate = time.Now()
+					taskStatus.Task.UpdatedAt = timestamppb.Now()
+					if err := c.updateTaskProgress(ctx, repository, taskStatus.Task, taskStatus, total); err != nil {
+						c.log(ctx).WithError(err).WithField("task_id", taskID).Warn("Failed to update merge progress")
+					}
+				}
+				mergeOpts := append(slices.Clone(opts), graveler.WithProgress(progress))
+				reference, err := c.Merge(ctx, repositoryID, destinationBranch, sourceRef, committer, message, metadata, strategy, mergeOpts...)
+				taskStatus.Reference = reference
+				taskStatus.Conflict = errors.Is(err, graveler.ErrConflictFound)
+				done <- MergeResult{Reference: reference, Err: err}
+				return err
+			},
+		},
+	}
+	info := operationInfo{
+		Kind:        OperationKindMerge,
+		Description: fmt.Sprintf("merge %s into %s", sourceRef, destinationBranch),
+		CreatedBy:   committer,
+	}
+	if err := c.runBackgroundTaskSteps(repository, taskID, info, taskSteps, taskStatus); err != nil {
+		return "", nil, err
+	}
+	return taskID, done, nil
+}
+
+// MergeStatus returns the status of the background merge id into destinationBranch
+func (c *Catalog) MergeStatus(ctx context.Context, repositoryID string, destinationBranch string, id string) (*MergeStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(MergeTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+
+	var status MergeStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	if status.DestinationBranch != destinationBranch {
+		return nil, graveler.ErrNotFound
+	}
+	return &status, nil
+}
@@ -0,0 +1,85 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/upload"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// reservedMetadataKeys are entry metadata keys set only by lakeFS itself, their values are trusted when reading entries
+var reservedMetadataKeys = map[string]struct{}{
+	AliasTargetMetadataKey:           {},
+	RenamedFromMetadataKey:           {},
+	EncryptionAlgorithmMetadataKey:   {},
+	EncryptionKMSKeyIDMetadataKey:    {},
+	upload.ChecksumSHA256MetadataKey: {},
+	upload.ChecksumCRC32CMetadataKey: {},
+}
+
+// ValidateUserMetadata returns ErrReservedMetadataKey if metadata supplied by a client sets a key reserved to lakeFS
+func ValidateUserMetadata(metadata Metadata) error {
+	for k := range metadata {
+		if _, ok := reservedMetadataKeys[k]; ok {
+			return fmt.Errorf("%s: %w", k, ErrReservedMetadataKey)
+		}
+	}
+	return nil
+}
+
+type UpdateEntryMetadataParams struct {
+	// Metadata user metadata to set on the entry
+	Metadata Metadata
+	// Replace when true replaces all user metadata with Metadata, otherwise Metadata is merged into it
+	Replace bool
+	// ContentType when set replaces the entry content type
+	ContentType *string
+}
+
+// UpdateEntryMetadata stages a new version of the entry at path with updated user metadata and content type.
+// The new entry points to the same physical address, so no data is copied.  Metadata keys managed by lakeFS
+// cannot be set and are kept as they are.
+func (c *Catalog) UpdateEntryMetadata(ctx context.Context, repositoryID, branch, path string, params UpdateEntryMetadataParams, opts ...graveler.SetOptionsFunc) (*DBEntry, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "path", Value: Path(path), Fn: ValidatePath},
+	}); err != nil {
+		return nil, err
+	}
+	for k := range params.Metadata {
+		if strings.HasPrefix(k, apiutil.LakeFSMetadataPrefix) {
+			return nil, fmt.Errorf("metadata key %s: %w", k, graveler.ErrInvalidValue)
+		}
+	}
+	entry, err := c.GetEntry(ctx, repositoryID, branch, path, GetEntryParams{NoFollowAlias: true})
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsAlias() {
+		return nil, fmt.Errorf("%w: cannot update metadata of an alias", ErrInvalidAlias)
+	}
+
+	metadata := make(Metadata, len(entry.Metadata)+len(params.Metadata))
+	for k, v := range entry.Metadata {
+		if !params.Replace || strings.HasPrefix(k, apiutil.LakeFSMetadataPrefix) {
+			metadata[k] = v
+		}
+	}
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	entry.Metadata = metadata
+	if params.ContentType != nil {
+		entry.ContentType = ContentTypeOrDefault(*params.ContentType)
+	}
+	if err := c.CreateEntry(ctx, repositoryID, branch, *entry, opts...); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
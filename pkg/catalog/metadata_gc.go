@@ -0,0 +1,234 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/ingest/store"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// DefaultMetadataGCMinAge is the age of the youngest metadata file collected by default. Ranges and metaranges are
+	// written before the commit, merge or import that uses them is recorded, so younger files may be in use soon.
+	DefaultMetadataGCMinAge = 6 * time.Hour
+	// MaxMetadataGCListedFiles is the largest number of collected files listed in a MetadataGCResult
+	MaxMetadataGCListedFiles = 1000
+
+	metadataGCArchivePrefix = "archive/metadata"
+	refsManifestFilename    = "refs_manifest.json"
+	keyFilterSuffix         = ".keys.bloom"
+)
+
+// metadataFilenameRegexp matches range and metarange files, and key filters of metaranges
+var metadataFilenameRegexp = regexp.MustCompile(`^[0-9a-f]{64}(\.keys\.bloom)?$`)
+
+type MetadataGCParams struct {
+	// DryRun only reports the files that would be collected
+	DryRun bool
+	// Archive copies collected files under the archive prefix of the metadata before deleting them
+	Archive bool
+	// MinAge is the age of the youngest file collected, DefaultMetadataGCMinAge when zero
+	MinAge time.Duration
+	// KeepDangling keeps the metadata of commits that cannot be reached from any branch or tag, so they can still
+	// be recovered
+	KeepDangling bool
+}
+
+// MetadataGCResult reports the metadata files collected from the storage namespace of a repository
+type MetadataGCResult struct {
+	ReachableMetaRanges int
+	ReachableRanges     int
+	// Skipped is the number of metadata files younger than the min age, which are not collected
+	Skipped        int
+	CollectedFiles int
+	CollectedBytes int64
+	// Files are the names of the first MaxMetadataGCListedFiles collected files
+	Files       []string
+	ArchivePath string
+}
+
+type metadataFile struct {
+	name string
+	size int64
+}
+
+// refsManifest is the refs dump last written to the storage namespace, its metaranges are kept so it can be restored
+type refsManifest struct {
+	CommitsMetaRangeID  string `json:"commits_meta_range_id"`
+	TagsMetaRangeID     string `json:"tags_meta_range_id"`
+	BranchesMetaRangeID string `json:"branches_meta_range_id"`
+}
+
+// CollectOrphanMetadata deletes the range and metarange files of a repository that are not used by any commit
+// reachable from a branch, a tag, a deleted ref within the trash retention, a branch history record or a dataset
+// release, e.g. files left by rewritten history or by failed commits. Only files older than the min age are collected.
+// Data objects are not collected, see PrepareExpiredCommits.
+func (c *Catalog) CollectOrphanMetadata(ctx context.Context, repositoryID string, params MetadataGCParams) (*MetadataGCResult, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, err
+	}
+	if params.MinAge < 0 {
+		return nil, fmt.Errorf("%s: %w", params.MinAge, ErrInvalidMetadataGCMinAge)
+	}
+	if params.MinAge == 0 {
+		params.MinAge = DefaultMetadataGCMinAge
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// list files before finding reachable metadata: files written after the listing are not collected, and
+	// metadata used by commits created while listing is found reachable
+	result := &MetadataGCResult{}
+	candidates, hasRefsManifest, err := c.listMetadataFiles(ctx, repository, time.Now().Add(-params.MinAge), result)
+	if err != nil {
+		return nil, err
+	}
+	reachableParams := graveler.FindReachableMetadataParams{IncludeDangling: params.KeepDangling}
+	reachableParams.CommitIDs, err = c.datasetReleaseCommits(ctx, repository.RepositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("dataset release commits: %w", err)
+	}
+	if hasRefsManifest {
+		reachableParams.MetaRangeIDs, err = c.readRefsManifestMetaRanges(ctx, repository)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reachable, err := c.Store.FindReachableMetadata(ctx, repository, reachableParams)
+	if err != nil {
+		return nil, err
+	}
+	result.ReachableMetaRanges = len(reachable.MetaRanges)
+	result.ReachableRanges = len(reachable.Ranges)
+
+	if params.Archive {
+		result.ArchivePath = c.metadataPath(metadataGCArchivePrefix) + "/"
+	}
+	for _, file := range candidates {
+		id := strings.TrimSuffix(file.name, keyFilterSuffix)
+		if _, ok := reachable.MetaRanges[graveler.MetaRangeID(id)]; ok {
+			continue
+		}
+		if _, ok := reachable.Ranges[graveler.RangeID(id)]; ok && id == file.name {
+			continue
+		}
+		if !params.DryRun {
+			if err := c.collectMetadataFile(ctx, repository, file.name, params.Archive); err != nil {
+				return nil, err
+			}
+		}
+		result.CollectedFiles++
+		result.CollectedBytes += file.size
+		if len(result.Files) < MaxMetadataGCListedFiles {
+			result.Files = append(result.Files, file.name)
+		}
+	}
+	c.log(ctx).WithFields(logging.Fields{
+		"repository":      repositoryID,
+		"dry_run":         params.DryRun,
+		"collected_files": result.CollectedFiles,
+		"collected_bytes": result.CollectedBytes,
+		"skipped":         result.Skipped,
+	}).Info("Collected orphan metadata")
+	return result, nil
+}
+
+func (c *Catalog) metadataPath(name string) string {
+	return c.blockStoragePrefix + "/" + name
+}
+
+// listMetadataFiles lists the metadata files directly under the metadata prefix of the repository that were last
+// modified before, counting younger files as skipped. It also reports whether a refs manifest exists.
+func (c *Catalog) listMetadataFiles(ctx context.Context, repository *graveler.RepositoryRecord, before time.Time, result *MetadataGCResult) ([]metadataFile, bool, error) {
+	storageURI := strings.TrimSuffix(repository.StorageNamespace.String(), "/") + "/" + c.metadataPath("")
+	walker, err := c.walkerFactory.GetWalker(ctx, store.WalkerOptions{StorageURI: storageURI})
+	if err != nil {
+		return nil, false, fmt.Errorf("creating object-store walker: %w", err)
+	}
+	var (
+		files           []metadataFile
+		hasRefsManifest bool
+	)
+	err = walker.Walk(ctx, block.WalkOptions{}, func(e block.ObjectStoreEntry) error {
+		if e.RelativeKey == refsManifestFilename {
+			hasRefsManifest = true
+			return nil
+		}
+		if !metadataFilenameRegexp.MatchString(e.RelativeKey) {
+			return nil
+		}
+		if !e.Mtime.Before(before) {
+			result.Skipped++
+			return nil
+		}
+		files = append(files, metadataFile{name: e.RelativeKey, size: e.Size})
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("walk metadata: %w", err)
+	}
+	return files, hasRefsManifest, nil
+}
+
+func (c *Catalog) readRefsManifestMetaRanges(ctx context.Context, repository *graveler.RepositoryRecord) ([]graveler.MetaRangeID, error) {
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       c.metadataPath(refsManifestFilename),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read refs manifest: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read refs manifest: %w", err)
+	}
+	var manifest refsManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse refs manifest: %w", err)
+	}
+	var metaRangeIDs []graveler.MetaRangeID
+	for _, id := range []string{manifest.CommitsMetaRangeID, manifest.TagsMetaRangeID, manifest.BranchesMetaRangeID} {
+		if id != "" {
+			metaRangeIDs = append(metaRangeIDs, graveler.MetaRangeID(id))
+		}
+	}
+	return metaRangeIDs, nil
+}
+
+// collectMetadataFile deletes a metadata file, copying it to the archive prefix first when archive is set
+func (c *Catalog) collectMetadataFile(ctx context.Context, repository *graveler.RepositoryRecord, name string, archive bool) error {
+	obj := block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       c.metadataPath(name),
+	}
+	if archive {
+		archiveObj := block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace.String(),
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       c.metadataPath(metadataGCArchivePrefix + "/" + name),
+		}
+		if err := c.BlockAdapter.Copy(ctx, obj, archiveObj); err != nil {
+			return fmt.Errorf("archive %s: %w", name, err)
+		}
+	}
+	if err := c.BlockAdapter.Remove(ctx, obj); err != nil {
+		return fmt.Errorf("remove %s: %w", name, err)
+	}
+	return nil
+}
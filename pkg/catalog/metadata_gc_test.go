@@ -0,0 +1,175 @@
+package catalog
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/ingest/store"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+type metadataWalker struct {
+	entries []block.ObjectStoreEntry
+}
+
+func (w *metadataWalker) Walk(_ context.Context, _ *url.URL, _ block.WalkOptions, walkFn func(e block.ObjectStoreEntry) error) error {
+	for _, e := range w.entries {
+		if err := walkFn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *metadataWalker) Marker() block.Mark { return block.Mark{} }
+
+func (w *metadataWalker) GetSkippedEntries() []block.ObjectStoreEntry { return nil }
+
+type metadataWalkerFactory struct {
+	walker *metadataWalker
+}
+
+func (f *metadataWalkerFactory) GetWalker(_ context.Context, opts store.WalkerOptions) (*store.WalkerWrapper, error) {
+	u, err := url.Parse(opts.StorageURI)
+	if err != nil {
+		return nil, err
+	}
+	return store.NewWrapper(f.walker, u), nil
+}
+
+func TestCatalog_CollectOrphanMetadata(t *testing.T) {
+	ctx := context.Background()
+	const ns = "mem://repo1"
+	var (
+		metaRangeID = strings.Repeat("a", 64)
+		rangeID     = strings.Repeat("b", 64)
+		orphanID    = strings.Repeat("c", 64)
+		youngID     = strings.Repeat("d", 64)
+	)
+	old := time.Now().Add(-2 * DefaultMetadataGCMinAge)
+	files := map[string]time.Time{
+		metaRangeID:                   old,
+		metaRangeID + keyFilterSuffix: old,
+		rangeID:                       old,
+		rangeID + keyFilterSuffix:     old,
+		orphanID:                      old,
+		orphanID + keyFilterSuffix:    old,
+		youngID:                       time.Now(),
+		"retention/gc/commits/run_id": old,
+		"dummy":                       old,
+	}
+
+	setup := func(t *testing.T) (*Catalog, *mem.Adapter) {
+		t.Helper()
+		adapter := mem.New(ctx)
+		walker := &metadataWalker{}
+		for name, mtime := range files {
+			err := adapter.Put(ctx, block.ObjectPointer{
+				StorageNamespace: ns,
+				IdentifierType:   block.IdentifierTypeRelative,
+				Identifier:       "_lakefs/" + name,
+			}, 4, strings.NewReader("data"), block.PutOpts{})
+			require.NoError(t, err)
+			walker.entries = append(walker.entries, block.ObjectStoreEntry{RelativeKey: name, Mtime: mtime, Size: 4})
+		}
+		c := &Catalog{
+			BlockAdapter: adapter,
+			Store: &FakeGraveler{
+				ReachableMetadata: &graveler.ReachableMetadata{
+					MetaRanges: map[graveler.MetaRangeID]struct{}{graveler.MetaRangeID(metaRangeID): {}},
+					Ranges:     map[graveler.RangeID]struct{}{graveler.RangeID(rangeID): {}},
+				},
+			},
+			KVStore:            kvtest.GetStore(ctx, t),
+			walkerFactory:      &metadataWalkerFactory{walker: walker},
+			blockStoragePrefix: "_lakefs",
+		}
+		return c, adapter
+	}
+	exists := func(t *testing.T, adapter *mem.Adapter, name string) bool {
+		t.Helper()
+		found, err := adapter.Exists(ctx, block.ObjectPointer{
+			StorageNamespace: ns,
+			IdentifierType:   block.IdentifierTypeRelative,
+			Identifier:       "_lakefs/" + name,
+		})
+		require.NoError(t, err)
+		return found
+	}
+	// key filters belong to metaranges, a key filter named after a reachable range is collected
+	expectedFiles := []string{orphanID, orphanID + keyFilterSuffix, rangeID + keyFilterSuffix}
+
+	t.Run("dry_run", func(t *testing.T) {
+		c, adapter := setup(t)
+		result, err := c.CollectOrphanMetadata(ctx, "repo1", MetadataGCParams{DryRun: true})
+		require.NoError(t, err)
+		require.ElementsMatch(t, expectedFiles, result.Files)
+		require.Equal(t, len(expectedFiles), result.CollectedFiles)
+		require.Equal(t, int64(4*len(expectedFiles)), result.CollectedBytes)
+		require.Equal(t, 1, result.Skipped)
+		for name := range files {
+			require.True(t, exists(t, adapter, name), name)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c, adapter := setup(t)
+		result, err := c.CollectOrphanMetadata(ctx, "repo1", MetadataGCParams{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, expectedFiles, result.Files)
+		for _, name := range expectedFiles {
+			require.False(t, exists(t, adapter, name), name)
+			require.False(t, exists(t, adapter, metadataGCArchivePrefix+"/"+name), name)
+		}
+		for _, name := range []string{metaRangeID, metaRangeID + keyFilterSuffix, rangeID, youngID, "dummy"} {
+			require.True(t, exists(t, adapter, name), name)
+		}
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		c, adapter := setup(t)
+		result, err := c.CollectOrphanMetadata(ctx, "repo1", MetadataGCParams{Archive: true})
+		require.NoError(t, err)
+		require.Equal(t, "_lakefs/"+metadataGCArchivePrefix+"/", result.ArchivePath)
+		for _, name := range expectedFiles {
+			require.False(t, exists(t, adapter, name), name)
+			require.True(t, exists(t, adapter, metadataGCArchivePrefix+"/"+name), name)
+		}
+	})
+
+	t.Run("min_age", func(t *testing.T) {
+		c, _ := setup(t)
+		result, err := c.CollectOrphanMetadata(ctx, "repo1", MetadataGCParams{DryRun: true, MinAge: 1})
+		require.NoError(t, err)
+		require.ElementsMatch(t, append(expectedFiles, youngID), result.Files)
+		require.Zero(t, result.Skipped)
+
+		_, err = c.CollectOrphanMetadata(ctx, "repo1", MetadataGCParams{MinAge: -time.Hour})
+		require.ErrorIs(t, err, ErrInvalidMetadataGCMinAge)
+	})
+
+	t.Run("dataset_releases", func(t *testing.T) {
+		c, _ := setup(t)
+		release := &DatasetRelease{
+			Dataset: "customers",
+			Version: "1.0.0",
+			Sources: []DatasetReleaseSource{
+				{Repository: "repo1", Ref: "main", CommitID: "c1"},
+				{Repository: "repo2", Ref: "main", CommitID: "c2"},
+			},
+		}
+		err := kv.SetMsg(ctx, c.KVStore, datasetsPartition, datasetReleasePath(release.Dataset, release.Version), protoFromDatasetRelease(release))
+		require.NoError(t, err)
+		_, err = c.CollectOrphanMetadata(ctx, "repo1", MetadataGCParams{DryRun: true})
+		require.NoError(t, err)
+		require.Equal(t, []graveler.CommitID{"c1"}, c.Store.(*FakeGraveler).ReachableMetadataParams.CommitIDs)
+	})
+}
@@ -23,6 +23,8 @@ type Repository struct {
 	DefaultBranch    string
 	CreationDate     time.Time
 	ReadOnly         bool
+	// ArchivedAt is when the repository was archived, zero for repositories that are not archived
+	ArchivedAt time.Time
 }
 
 type DBEntry struct {
@@ -36,6 +38,8 @@ type DBEntry struct {
 	Expired         bool
 	AddressType     AddressType
 	ContentType     string
+	// AliasTarget is set on an entry read through an alias to the path of the entry the alias resolved to
+	AliasTarget string
 }
 
 type CommitLog struct {
@@ -50,6 +54,13 @@ type CommitLog struct {
 	Version      CommitVersion
 }
 
+// DanglingCommit is a commit that cannot be reached from any branch or tag
+type DanglingCommit struct {
+	Commit *CommitLog
+	// Head is true when no other commit has this commit as its parent
+	Head bool
+}
+
 type Branch struct {
 	Name      string
 	Reference string
@@ -60,6 +71,19 @@ type Tag struct {
 	CommitID string
 }
 
+const (
+	DeletedRefTypeBranch = "branch"
+	DeletedRefTypeTag    = "tag"
+)
+
+// DeletedRef is a deleted branch or tag that can be restored
+type DeletedRef struct {
+	Type      string
+	ID        string
+	CommitID  string
+	DeletedAt time.Time
+}
+
 // AddressType is the type of an entry address
 type AddressType int32
 
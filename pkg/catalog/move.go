@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// RenamedFromMetadataKey entry metadata key holding the path an entry was last moved from
+const RenamedFromMetadataKey = apiutil.LakeFSMetadataPrefix + "renamed-from"
+
+type MoveEntryParams struct {
+	// CommitMessage when set commits the move on the branch.  The branch must not have other uncommitted
+	// changes, so the commit holds the move alone.
+	CommitMessage string
+	Committer     string
+}
+
+// MoveEntry moves the entry at srcPath to destPath on the branch.  Both the new entry and the deletion of the
+// old one are staged together, and the new entry keeps the physical address of the old one - no data is copied.
+// The source path is recorded in the entry metadata under RenamedFromMetadataKey, so history can be followed
+// across the rename.  Returns the commit log when a commit was requested.
+func (c *Catalog) MoveEntry(ctx context.Context, repositoryID, branch, srcPath, destPath string, params MoveEntryParams, opts ...graveler.SetOptionsFunc) (*DBEntry, *CommitLog, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "src_path", Value: Path(srcPath), Fn: ValidatePath},
+		{Name: "dest_path", Value: Path(destPath), Fn: ValidatePath},
+	}); err != nil {
+		return nil, nil, err
+	}
+	if srcPath == destPath {
+		return nil, nil, fmt.Errorf("%w: source and destination paths are the same", graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if params.CommitMessage != "" {
+		if err := c.checkNoUncommittedChanges(ctx, repository, branchID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// aliases are moved as they are, still pointing to the same target
+	srcEntry, err := c.GetEntry(ctx, repositoryID, branch, srcPath, GetEntryParams{NoFollowAlias: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := *srcEntry
+	entry.Path = destPath
+	entry.CreationDate = time.Now()
+	entry.Metadata = make(Metadata, len(srcEntry.Metadata)+1)
+	for k, v := range srcEntry.Metadata {
+		entry.Metadata[k] = v
+	}
+	entry.Metadata[RenamedFromMetadataKey] = srcPath
+	if err := c.normalizeDirectoryMarker(ctx, repository, &entry); err != nil {
+		return nil, nil, err
+	}
+	value, err := EntryToValue(newEntryFromCatalogEntry(entry))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Store.Move(ctx, repository, branchID, graveler.Key(srcPath), graveler.Key(destPath), *value, opts...); err != nil {
+		return nil, nil, err
+	}
+	if err := c.keepParentDirectories(ctx, repository, branchID, []string{srcPath}, opts...); err != nil {
+		return nil, nil, err
+	}
+	if params.CommitMessage == "" {
+		return &entry, nil, nil
+	}
+	commitLog, err := c.Commit(ctx, repositoryID, branch, params.CommitMessage, params.Committer, nil, nil, nil, false, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("commit move: %w", err)
+	}
+	return &entry, commitLog, nil
+}
+
+// checkNoUncommittedChanges returns graveler.ErrDirtyBranch if the branch has uncommitted changes
+func (c *Catalog) checkNoUncommittedChanges(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
+	it, err := c.Store.DiffUncommitted(ctx, repository, branchID)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	if it.Next() {
+		return fmt.Errorf("%s: %w", branchID, graveler.ErrDirtyBranch)
+	}
+	return it.Err()
+}
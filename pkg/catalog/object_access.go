@@ -0,0 +1,311 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	objectAccessPartition = "object_access"
+	objectAccessPrefix    = "access"
+
+	// objectAccessUpdateAttempts is the number of attempts to update an access record modified concurrently by
+	// another lakeFS server
+	objectAccessUpdateAttempts = 5
+	// objectAccessCloseFlushTimeout bounds persisting the reads counted when the catalog is closed
+	objectAccessCloseFlushTimeout = 10 * time.Second
+	// objectAccessDeleteBatchSize is the number of access records read before deleting them
+	objectAccessDeleteBatchSize = 1000
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(objectAccessPartition, objectAccessPrefix, (&ObjectAccessData{}).ProtoReflect().Type())
+}
+
+// PrincipalAccess is the read access of a user to an object
+type PrincipalAccess struct {
+	User         string
+	Count        int64
+	LastAccessed time.Time
+}
+
+// ObjectAccess is the read access to an object, by user. When reads are sampled, counts are estimates.
+type ObjectAccess struct {
+	Path         string
+	Count        int64
+	LastAccessed time.Time
+	Principals   []PrincipalAccess
+}
+
+type objectAccessKey struct {
+	repository string
+	path       string
+	user       string
+}
+
+type objectAccessCount struct {
+	count        int64
+	lastAccessed time.Time
+}
+
+// objectAccessTracker counts reads of objects until they are persisted. At most maxKeys reads of distinct objects
+// and users are counted between flushes, reads of others are dropped.
+type objectAccessTracker struct {
+	sampleRate float64
+	maxKeys    int
+	mu         sync.Mutex
+	counts     map[objectAccessKey]*objectAccessCount
+	dropped    int64
+}
+
+func newObjectAccessTracker(sampleRate float64, maxKeys int) *objectAccessTracker {
+	return &objectAccessTracker{
+		sampleRate: sampleRate,
+		maxKeys:    maxKeys,
+		counts:     make(map[objectAccessKey]*objectAccessCount),
+	}
+}
+
+func (t *objectAccessTracker) record(key objectAccessKey, accessed time.Time) {
+	weight := int64(1)
+	if t.sampleRate < 1 {
+		//nolint:gosec
+		if rand.Float64() >= t.sampleRate {
+			return
+		}
+		// each sampled read stands for the reads that were not sampled
+		weight = int64(math.Round(1 / t.sampleRate))
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.add(key, &objectAccessCount{count: weight, lastAccessed: accessed})
+}
+
+// add adds count to the reads counted of key, the caller must hold mu
+func (t *objectAccessTracker) add(key objectAccessKey, count *objectAccessCount) {
+	c, ok := t.counts[key]
+	if !ok {
+		if len(t.counts) >= t.maxKeys {
+			t.dropped += count.count
+			return
+		}
+		c = &objectAccessCount{}
+		t.counts[key] = c
+	}
+	c.count += count.count
+	if count.lastAccessed.After(c.lastAccessed) {
+		c.lastAccessed = count.lastAccessed
+	}
+}
+
+// merge adds back reads that could not be persisted
+func (t *objectAccessTracker) merge(counts map[objectAccessKey]*objectAccessCount) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, count := range counts {
+		t.add(key, count)
+	}
+}
+
+// reset returns the reads counted since the last reset, and the number of reads dropped since then
+func (t *objectAccessTracker) reset() (map[objectAccessKey]*objectAccessCount, int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts, dropped := t.counts, t.dropped
+	t.counts = make(map[objectAccessKey]*objectAccessCount)
+	t.dropped = 0
+	return counts, dropped
+}
+
+// deleteRepository drops the reads counted of objects in repository
+func (t *objectAccessTracker) deleteRepository(repositoryID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.counts {
+		if key.repository == repositoryID {
+			delete(t.counts, key)
+		}
+	}
+}
+
+// objectAccessPathKey returns the key prefix of the access records of path. Paths and users are escaped, so keys
+// of a path are not confused with keys of paths under it.
+func objectAccessPathKey(repositoryID, path string) string {
+	return kv.FormatPath(objectAccessPrefix, repositoryID, url.PathEscape(path))
+}
+
+func objectAccessKeyPath(key objectAccessKey) []byte {
+	return []byte(kv.FormatPath(objectAccessPathKey(key.repository, key.path), url.PathEscape(key.user)))
+}
+
+// RecordObjectAccess counts a read of path in repository by user, when object access tracking is enabled
+func (c *Catalog) RecordObjectAccess(repositoryID, path, user string) {
+	if c.objectAccess == nil {
+		return
+	}
+	c.objectAccess.record(objectAccessKey{repository: repositoryID, path: path, user: user}, time.Now())
+}
+
+// startObjectAccessFlush persists the object reads counted every interval, until ctx is done
+func (c *Catalog) startObjectAccessFlush(ctx context.Context, interval time.Duration) {
+	log := logging.FromContext(ctx).WithField("service_name", "object_access")
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.FlushObjectAccess(ctx); err != nil {
+					log.WithError(err).Error("Failed to persist object access records")
+				}
+			}
+		}
+	}()
+}
+
+// FlushObjectAccess adds the object reads counted since the last flush to their access records. Reads that could
+// not be added are counted again, to be added on the next flush.
+func (c *Catalog) FlushObjectAccess(ctx context.Context) error {
+	if c.objectAccess == nil {
+		return nil
+	}
+	counts, dropped := c.objectAccess.reset()
+	if dropped > 0 {
+		logging.FromContext(ctx).
+			WithFields(logging.Fields{"service_name": "object_access", "dropped": dropped}).
+			Warn("Object reads not tracked, too many objects read between flushes")
+	}
+	var errs error
+	failed := make(map[objectAccessKey]*objectAccessCount)
+	for key, count := range counts {
+		if err := c.addObjectAccess(ctx, key, count); err != nil {
+			errs = errors.Join(errs, err)
+			failed[key] = count
+		}
+	}
+	c.objectAccess.merge(failed)
+	return errs
+}
+
+// closeObjectAccess persists the object reads counted before the catalog is closed
+func (c *Catalog) closeObjectAccess() error {
+	ctx, cancel := context.WithTimeout(context.Background(), objectAccessCloseFlushTimeout)
+	defer cancel()
+	return c.FlushObjectAccess(ctx)
+}
+
+// deleteObjectAccess deletes the access records of a repository and the reads counted of its objects
+func (c *Catalog) deleteObjectAccess(ctx context.Context, repositoryID string) error {
+	if c.objectAccess != nil {
+		c.objectAccess.deleteRepository(repositoryID)
+	}
+	prefix := []byte(objectAccessPathKey(repositoryID, ""))
+	for {
+		it, err := kv.ScanPrefix(ctx, c.KVStore, []byte(objectAccessPartition), prefix, nil)
+		if err != nil {
+			return err
+		}
+		keys := make([][]byte, 0, objectAccessDeleteBatchSize)
+		for len(keys) < objectAccessDeleteBatchSize && it.Next() {
+			keys = append(keys, it.Entry().Key)
+		}
+		err = it.Err()
+		it.Close()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := c.KVStore.Delete(ctx, []byte(objectAccessPartition), key); err != nil {
+				return err
+			}
+		}
+		if len(keys) < objectAccessDeleteBatchSize {
+			return nil
+		}
+	}
+}
+
+func (c *Catalog) addObjectAccess(ctx context.Context, key objectAccessKey, count *objectAccessCount) error {
+	recordKey := objectAccessKeyPath(key)
+	for attempt := 0; ; attempt++ {
+		data := &ObjectAccessData{}
+		predicate, err := kv.GetMsg(ctx, c.KVStore, objectAccessPartition, recordKey, data)
+		if err != nil && !errors.Is(err, kv.ErrNotFound) {
+			return err
+		}
+		data.Path = key.path
+		data.User = key.user
+		data.Count += count.count
+		data.LastAccessed = max(data.LastAccessed, count.lastAccessed.UnixNano())
+		err = kv.SetMsgIf(ctx, c.KVStore, objectAccessPartition, recordKey, data, predicate)
+		if !errors.Is(err, kv.ErrPredicateFailed) || attempt+1 == objectAccessUpdateAttempts {
+			return err
+		}
+	}
+}
+
+// ListObjectAccess lists the read access to objects under prefix of a repository by path, starting after path
+// after. Objects that were never read since access tracking was enabled are not listed.
+func (c *Catalog) ListObjectAccess(ctx context.Context, repositoryID, prefix, after string, limit int) ([]*ObjectAccess, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+	}); err != nil {
+		return nil, false, err
+	}
+	if _, err := c.getRepository(ctx, repositoryID); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListObjectAccessLimitMax {
+		limit = ListObjectAccessLimitMax
+	}
+	var start []byte
+	if after != "" {
+		// all the keys of a path are "<path key>/<user>", the first key after them starts with the character
+		// following the delimiter
+		start = []byte(objectAccessPathKey(repositoryID, after) + string(kv.PathDelimiter[0]+1))
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&ObjectAccessData{}).ProtoReflect().Type(), objectAccessPartition,
+		[]byte(objectAccessPathKey(repositoryID, prefix)), kv.IteratorOptionsFrom(start))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var results []*ObjectAccess
+	for it.Next() {
+		data := it.Entry().Value.(*ObjectAccessData)
+		if len(results) == 0 || results[len(results)-1].Path != data.Path {
+			if len(results) == limit {
+				return results, true, nil
+			}
+			results = append(results, &ObjectAccess{Path: data.Path})
+		}
+		access := results[len(results)-1]
+		lastAccessed := time.Unix(0, data.LastAccessed).UTC()
+		access.Count += data.Count
+		if lastAccessed.After(access.LastAccessed) {
+			access.LastAccessed = lastAccessed
+		}
+		access.Principals = append(access.Principals, PrincipalAccess{
+			User:         data.User,
+			Count:        data.Count,
+			LastAccessed: lastAccessed,
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return results, false, nil
+}
@@ -0,0 +1,39 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObjectAccessTracker(t *testing.T) {
+	tracker := newObjectAccessTracker(1, 2)
+	now := time.Now()
+	keyA := objectAccessKey{repository: "repo1", path: "a", user: "u1"}
+	keyB := objectAccessKey{repository: "repo2", path: "b", user: "u1"}
+	keyC := objectAccessKey{repository: "repo1", path: "c", user: "u2"}
+	tracker.record(keyA, now)
+	tracker.record(keyA, now.Add(time.Second))
+	tracker.record(keyB, now)
+	// the tracker is full, reads of other objects are dropped
+	tracker.record(keyC, now)
+
+	counts, dropped := tracker.reset()
+	if len(counts) != 2 || dropped != 1 {
+		t.Fatalf("reset() = %d counts, %d dropped, expected 2 counts, 1 dropped", len(counts), dropped)
+	}
+	if c := counts[keyA]; c.count != 2 || !c.lastAccessed.Equal(now.Add(time.Second)) {
+		t.Errorf("count of %v = %+v", keyA, c)
+	}
+
+	// counts that failed to persist are added to the reads counted since
+	tracker.record(keyA, now.Add(2*time.Second))
+	tracker.merge(counts)
+	tracker.deleteRepository("repo2")
+	counts, dropped = tracker.reset()
+	if len(counts) != 1 || dropped != 0 {
+		t.Fatalf("reset() after merge = %d counts, %d dropped, expected 1 count", len(counts), dropped)
+	}
+	if c := counts[keyA]; c.count != 3 || !c.lastAccessed.Equal(now.Add(2*time.Second)) {
+		t.Errorf("merged count of %v = %+v", keyA, c)
+	}
+}
@@ -0,0 +1,367 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	operationsPrefix       = "operations"
+	operationCancelsPrefix = "operation_cancels"
+
+	// operationMaxLogs is the number of latest log entries kept on an operation record
+	operationMaxLogs = 100
+	// operationCancelPollInterval is the time between checks of a running operation for cancel requests made through
+	// other lakeFS instances
+	operationCancelPollInterval = 5 * time.Second
+
+	ListOperationsLimitMax = 1000
+)
+
+const (
+	OperationStatusRunning   = "running"
+	OperationStatusCompleted = "completed"
+	OperationStatusFailed    = "failed"
+	OperationStatusCanceled  = "canceled"
+)
+
+const (
+	OperationKindDumpRefs    = "dump_refs"
+	OperationKindRestoreRefs = "restore_refs"
+	OperationKindTagObjects  = "tag_objects"
+	OperationKindMerge       = "merge"
+	OperationKindImport      = "import"
+	OperationKindLocalGC     = "local_gc"
+)
+
+// Operation is a long-running operation on a repository, such as a merge or an import
+type Operation struct {
+	ID          string
+	Kind        string
+	Description string
+	Status      string
+	Progress    int64
+	// ProgressTotal is the progress expected on completion when known, or zero
+	ProgressTotal int64
+	Error         string
+	Logs          []OperationLog
+	CreatedBy     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type OperationLog struct {
+	Time    time.Time
+	Message string
+}
+
+// operationInfo describes the operation run by a background task
+type operationInfo struct {
+	Kind        string
+	Description string
+	CreatedBy   string
+}
+
+// runningOperation is an operation running on this lakeFS instance
+type runningOperation struct {
+	repository *graveler.RepositoryRecord
+	cancel     context.CancelFunc
+
+	mu   sync.Mutex
+	data *OperationData
+}
+
+func OperationPath(id string) string {
+	return kv.FormatPath(operationsPrefix, id)
+}
+
+func operationCancelPath(id string) string {
+	return kv.FormatPath(operationCancelsPrefix, id)
+}
+
+func operationFromProto(pb *OperationData) *Operation {
+	op := &Operation{
+		ID:            pb.Id,
+		Kind:          pb.Kind,
+		Description:   pb.Description,
+		Status:        pb.Status,
+		Progress:      pb.Progress,
+		ProgressTotal: pb.ProgressTotal,
+		Error:         pb.Error,
+		Logs:          make([]OperationLog, 0, len(pb.Logs)),
+		CreatedBy:     pb.CreatedBy,
+		CreatedAt:     pb.CreatedAt.AsTime(),
+		UpdatedAt:     pb.UpdatedAt.AsTime(),
+	}
+	for _, entry := range pb.Logs {
+		op.Logs = append(op.Logs, OperationLog{
+			Time:    entry.Time.AsTime(),
+			Message: entry.Message,
+		})
+	}
+	return op
+}
+
+// logf appends a message to the operation logs, keeping the latest operationMaxLogs messages
+func (o *runningOperation) logf(format string, args ...any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.data.Logs = append(o.data.Logs, &OperationLogEntry{
+		Time:    timestamppb.Now(),
+		Message: fmt.Sprintf(format, args...),
+	})
+	if len(o.data.Logs) > operationMaxLogs {
+		o.data.Logs = o.data.Logs[len(o.data.Logs)-operationMaxLogs:]
+	}
+}
+
+// operationLogf appends a message to the logs of the running operation id, if it runs on this lakeFS instance
+func (c *Catalog) operationLogf(id string, format string, args ...any) {
+	if v, ok := c.operations.Load(id); ok {
+		v.(*runningOperation).logf(format, args...)
+	}
+}
+
+func (o *runningOperation) update(fn func(data *OperationData)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fn(o.data)
+}
+
+func (o *runningOperation) save(ctx context.Context, store kv.Store) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.data.UpdatedAt = timestamppb.Now()
+	return kv.SetMsg(ctx, store, graveler.RepoPartition(o.repository), []byte(OperationPath(o.data.Id)), o.data)
+}
+
+// startOperation records a running operation for the background task id, which cancel stops
+func (c *Catalog) startOperation(ctx context.Context, repository *graveler.RepositoryRecord, id string, info operationInfo, cancel context.CancelFunc) (*runningOperation, error) {
+	now := timestamppb.Now()
+	op := &runningOperation{
+		repository: repository,
+		cancel:     cancel,
+		data: &OperationData{
+			Id:          id,
+			Kind:        info.Kind,
+			Description: info.Description,
+			Status:      OperationStatusRunning,
+			CreatedBy:   info.CreatedBy,
+			CreatedAt:   now,
+		},
+	}
+	if err := op.save(ctx, c.KVStore); err != nil {
+		return nil, err
+	}
+	c.operations.Store(id, op)
+	return op, nil
+}
+
+// finishOperation records the result of a running operation, and stops tracking it
+func (c *Catalog) finishOperation(ctx context.Context, op *runningOperation, err error) {
+	c.operations.Delete(op.data.Id)
+	op.update(func(data *OperationData) {
+		switch {
+		case err == nil:
+			data.Status = OperationStatusCompleted
+		case errors.Is(err, ErrOperationCanceled):
+			data.Status = OperationStatusCanceled
+			data.Error = err.Error()
+		default:
+			data.Status = OperationStatusFailed
+			data.Error = err.Error()
+		}
+	})
+	if err := op.save(ctx, c.KVStore); err != nil {
+		c.log(ctx).WithError(err).WithField("operation_id", op.data.Id).Error("Failed to record operation result")
+	}
+}
+
+// updateTaskProgress updates the status of a running task, and the progress of its operation out of total.  The
+// operation total is left unchanged when total is zero.
+func (c *Catalog) updateTaskProgress(ctx context.Context, repository *graveler.RepositoryRecord, task *Task, taskStatus protoreflect.ProtoMessage, total int64) error {
+	if err := UpdateTaskStatus(ctx, c.KVStore, repository, task.Id, taskStatus); err != nil {
+		return err
+	}
+	v, ok := c.operations.Load(task.Id)
+	if !ok {
+		return nil
+	}
+	op := v.(*runningOperation)
+	op.update(func(data *OperationData) {
+		data.Progress = task.Progress
+		if total > 0 {
+			data.ProgressTotal = total
+		}
+	})
+	return op.save(ctx, c.KVStore)
+}
+
+// watchOperationCancel cancels a running operation once a cancel request for it is recorded, until ctx is done
+func (c *Catalog) watchOperationCancel(ctx context.Context, op *runningOperation) {
+	ticker := time.NewTicker(operationCancelPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := c.KVStore.Get(ctx, []byte(graveler.RepoPartition(op.repository)), []byte(operationCancelPath(op.data.Id)))
+		if err == nil {
+			op.logf("cancel requested")
+			op.cancel()
+			return
+		}
+		if !errors.Is(err, kv.ErrNotFound) && ctx.Err() == nil {
+			c.log(ctx).WithError(err).WithField("operation_id", op.data.Id).Warn("Failed to check operation cancel request")
+		}
+	}
+}
+
+// GetOperation returns the operation id on the repository
+func (c *Catalog) GetOperation(ctx context.Context, repositoryID string, id string) (*Operation, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	data := &OperationData{}
+	_, err = kv.GetMsg(ctx, c.KVStore, graveler.RepoPartition(repository), []byte(OperationPath(id)), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, fmt.Errorf("operation %s: %w", id, graveler.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.operationWithStatus(ctx, repository, data), nil
+}
+
+// ListOperations lists the operations on the repository by ID, without their logs
+func (c *Catalog) ListOperations(ctx context.Context, repositoryID string, limit int, after string) ([]*Operation, bool, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListOperationsLimitMax {
+		limit = ListOperationsLimitMax
+	}
+	var afterKey []byte
+	if after != "" {
+		afterKey = []byte(OperationPath(after))
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&OperationData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		[]byte(operationsPrefix+kv.PathDelimiter), kv.IteratorOptionsAfter(afterKey))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var ops []*Operation
+	for it.Next() {
+		if len(ops) == limit {
+			return ops, true, nil
+		}
+		op := c.operationWithStatus(ctx, repository, it.Entry().Value.(*OperationData))
+		op.Logs = nil
+		ops = append(ops, op)
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return ops, false, nil
+}
+
+// operationWithStatus returns the operation recorded by data.  Imports keep their own status, so the status of running
+// import operations is read from it.
+func (c *Catalog) operationWithStatus(ctx context.Context, repository *graveler.RepositoryRecord, data *OperationData) *Operation {
+	op := operationFromProto(data)
+	if op.Kind != OperationKindImport || op.Status != OperationStatusRunning {
+		return op
+	}
+	status, err := c.getImportStatus(ctx, repository, op.ID)
+	if errors.Is(err, graveler.ErrNotFound) {
+		// import did not start yet
+		return op
+	}
+	if err != nil {
+		c.log(ctx).WithError(err).WithField("import_id", op.ID).Warn("Failed to get import operation status")
+		return op
+	}
+	op.Progress = status.Progress
+	op.UpdatedAt = status.UpdatedAt.AsTime()
+	switch {
+	case status.Completed:
+		op.Status = OperationStatusCompleted
+	case status.Error == ImportCanceled:
+		op.Status = OperationStatusCanceled
+	case status.Error != "":
+		op.Status = OperationStatusFailed
+		op.Error = status.Error
+	}
+	return op
+}
+
+// CancelOperation cancels the running operation id on the repository.  Operations running on other lakeFS instances
+// are canceled once they check for cancel requests.
+func (c *Catalog) CancelOperation(ctx context.Context, repositoryID string, id string) error {
+	op, err := c.GetOperation(ctx, repositoryID, id)
+	if err != nil {
+		return err
+	}
+	if op.Status != OperationStatusRunning {
+		return fmt.Errorf("operation %s is %s: %w", id, op.Status, ErrOperationNotRunning)
+	}
+	if op.Kind == OperationKindImport {
+		return c.CancelImport(ctx, repositoryID, id)
+	}
+
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	err = c.KVStore.Set(ctx, []byte(graveler.RepoPartition(repository)), []byte(operationCancelPath(id)), []byte(time.Now().UTC().Format(time.RFC3339)))
+	if err != nil {
+		return err
+	}
+	if v, ok := c.operations.Load(id); ok {
+		running := v.(*runningOperation)
+		running.logf("cancel requested")
+		running.cancel()
+	}
+	c.log(ctx).WithFields(logging.Fields{"repository": repositoryID, "operation_id": id, "kind": op.Kind}).Info("Operation cancel requested")
+	return nil
+}
+
+// deleteRepositoryExpiredOperations deletes operations of the repository not updated for TaskExpiryTime, and their
+// cancel requests
+func (c *Catalog) deleteRepositoryExpiredOperations(ctx context.Context, repository *graveler.RepositoryRecord) error {
+	repoPartition := graveler.RepoPartition(repository)
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStoreLimited, (&OperationData{}).ProtoReflect().Type(),
+		repoPartition, []byte(operationsPrefix+kv.PathDelimiter), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		ent := it.Entry()
+		data := ent.Value.(*OperationData)
+		if time.Since(data.UpdatedAt.AsTime()) < TaskExpiryTime {
+			continue
+		}
+		if err := c.KVStoreLimited.Delete(ctx, []byte(repoPartition), ent.Key); err != nil {
+			return err
+		}
+		if err := c.KVStoreLimited.Delete(ctx, []byte(repoPartition), []byte(operationCancelPath(data.Id))); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
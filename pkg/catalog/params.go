@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// ParamsPrefix is the path under which branch parameters are stored, one object per parameter, so parameters are
+	// committed, merged and reverted with the data they configure
+	ParamsPrefix = "_lakefs_params/"
+	// ParamContentType is the content type of parameter objects
+	ParamContentType = "text/plain; charset=utf-8"
+
+	ListParamsLimitMax = 100
+	// MaxParamValueSize is the size of the largest parameter value
+	MaxParamValueSize = 64 * 1024
+)
+
+var paramKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,256}$`)
+
+// Param is a named value stored on a branch
+type Param struct {
+	Key   string
+	Value string
+}
+
+func validateParamKey(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	if !paramKeyRegexp.MatchString(s) {
+		return ErrInvalidParamKey
+	}
+	return nil
+}
+
+func paramPath(key string) string {
+	return ParamsPrefix + key
+}
+
+// GetParam returns the value of a parameter at ref. Read parameters at a commit ID to read them atomically with the
+// data of that commit.
+func (c *Catalog) GetParam(ctx context.Context, repositoryID, ref, key string) (string, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "key", Value: key, Fn: validateParamKey},
+	}); err != nil {
+		return "", err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	entry, err := c.GetEntry(ctx, repositoryID, ref, paramPath(key), GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		return "", fmt.Errorf("%s: %w", key, ErrParamNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	return c.readParamValue(ctx, repository, entry)
+}
+
+// ListParams lists the parameters at ref, by key
+func (c *Catalog) ListParams(ctx context.Context, repositoryID, ref, after string, limit int) ([]*Param, bool, error) {
+	if limit < 0 || limit > ListParamsLimitMax {
+		limit = ListParamsLimitMax
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	entries, hasMore, err := c.ListEntries(ctx, repositoryID, ref, ParamsPrefix, paramPath(after), "/", limit)
+	if err != nil {
+		return nil, false, err
+	}
+	params := make([]*Param, 0, len(entries))
+	for _, entry := range entries {
+		if entry.CommonLevel {
+			continue
+		}
+		value, err := c.readParamValue(ctx, repository, entry)
+		if err != nil {
+			return nil, false, err
+		}
+		params = append(params, &Param{Key: strings.TrimPrefix(entry.Path, ParamsPrefix), Value: value})
+	}
+	return params, hasMore, nil
+}
+
+// SetParam stages a parameter value on a branch
+func (c *Catalog) SetParam(ctx context.Context, repositoryID, branch, key, value string, opts ...graveler.SetOptionsFunc) error {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+		{Name: "key", Value: key, Fn: validateParamKey},
+	}); err != nil {
+		return err
+	}
+	if len(value) > MaxParamValueSize {
+		return fmt.Errorf("%d bytes: %w", len(value), ErrParamValueTooLarge)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	address := c.PathProvider.NewPath()
+	obj := block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	}
+	if err := c.BlockAdapter.Put(ctx, obj, int64(len(value)), strings.NewReader(value), block.PutOpts{}); err != nil {
+		return err
+	}
+	checksum := md5.Sum([]byte(value)) //nolint:gosec
+	entry := NewDBEntryBuilder().
+		Path(paramPath(key)).
+		PhysicalAddress(address).
+		AddressType(AddressTypeRelative).
+		CreationDate(time.Now()).
+		Size(int64(len(value))).
+		Checksum(hex.EncodeToString(checksum[:])).
+		ContentType(ParamContentType).
+		Build()
+	return c.CreateEntry(ctx, repositoryID, branch, entry, opts...)
+}
+
+// DeleteParam stages the deletion of a parameter from a branch
+func (c *Catalog) DeleteParam(ctx context.Context, repositoryID, branch, key string, opts ...graveler.SetOptionsFunc) error {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "key", Value: key, Fn: validateParamKey},
+	}); err != nil {
+		return err
+	}
+	err := c.DeleteEntry(ctx, repositoryID, branch, paramPath(key), opts...)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return fmt.Errorf("%s: %w", key, ErrParamNotFound)
+	}
+	return err
+}
+
+func (c *Catalog) readParamValue(ctx context.Context, repository *graveler.RepositoryRecord, entry *DBEntry) (string, error) {
+	if entry.Size > MaxParamValueSize {
+		return "", fmt.Errorf("%s %d bytes: %w", entry.Path, entry.Size, ErrParamValueTooLarge)
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = reader.Close() }()
+	value, err := io.ReadAll(io.LimitReader(reader, MaxParamValueSize))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
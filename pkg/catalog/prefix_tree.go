@@ -0,0 +1,277 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// ranges are immutable, their stats are kept until evicted
+	rangeStatsCacheSize   = 10000
+	rangeStatsCacheExpiry = time.Hour
+	rangeStatsCacheJitter = rangeStatsCacheExpiry / 10
+)
+
+// PrefixTreeStats aggregates the objects under a path
+type PrefixTreeStats struct {
+	Count        int64
+	Size         int64
+	LastModified time.Time
+}
+
+// PrefixTreeChild is an immediate child of a listed prefix: an object, or a common prefix with the stats of all the
+// objects under it
+type PrefixTreeChild struct {
+	Path         string
+	CommonPrefix bool
+	// Entry is the object of a child that is not a common prefix
+	Entry *DBEntry
+	Stats PrefixTreeStats
+}
+
+type PrefixTree struct {
+	Children []PrefixTreeChild
+	HasMore  bool
+}
+
+func newRangeStatsCache() cache.Cache {
+	return cache.NewCache(rangeStatsCacheSize, rangeStatsCacheExpiry, cache.NewJitterFn(rangeStatsCacheJitter))
+}
+
+func (s *PrefixTreeStats) add(other PrefixTreeStats) {
+	s.Count += other.Count
+	s.Size += other.Size
+	if other.LastModified.After(s.LastModified) {
+		s.LastModified = other.LastModified
+	}
+}
+
+func entryPrefixTreeStats(entry *Entry) PrefixTreeStats {
+	return PrefixTreeStats{
+		Count:        1,
+		Size:         entry.Size,
+		LastModified: entry.LastModified.AsTime(),
+	}
+}
+
+// prefixTreeBuilder collects the children of a prefix from entries in key order
+type prefixTreeBuilder struct {
+	prefix    string
+	delimiter string
+	after     string
+	limit     int
+	tree      PrefixTree
+}
+
+// child returns the child of the listed prefix holding path, and whether it is a common prefix. path must be under
+// the listed prefix. Children are ordered as the paths they hold.
+func (b *prefixTreeBuilder) child(path string) (string, bool) {
+	if b.delimiter == "" {
+		return path, false
+	}
+	relPath := path[len(b.prefix):]
+	if idx := strings.Index(relPath, b.delimiter); idx >= 0 {
+		return b.prefix + relPath[:idx+len(b.delimiter)], true
+	}
+	return path, false
+}
+
+// add adds stats to child, returns false once the tree has all its children
+func (b *prefixTreeBuilder) add(child string, commonPrefix bool, entry *DBEntry, stats PrefixTreeStats) bool {
+	if child <= b.after {
+		return true
+	}
+	children := b.tree.Children
+	if len(children) > 0 && children[len(children)-1].Path == child {
+		children[len(children)-1].Stats.add(stats)
+		return true
+	}
+	if len(children) == b.limit {
+		b.tree.HasMore = true
+		return false
+	}
+	b.tree.Children = append(children, PrefixTreeChild{
+		Path:         child,
+		CommonPrefix: commonPrefix,
+		Entry:        entry,
+		Stats:        stats,
+	})
+	return true
+}
+
+// addEntries adds the entries of it under the listed prefix, returns false once the tree has all its children or
+// it passed the listed prefix
+func (b *prefixTreeBuilder) addEntries(it EntryIterator) (bool, error) {
+	start := b.prefix
+	if b.after > start {
+		start = b.after
+	}
+	it.SeekGE(Path(start))
+	for it.Next() {
+		v := it.Value()
+		p := v.Path.String()
+		if !strings.HasPrefix(p, b.prefix) {
+			return false, nil
+		}
+		child, commonPrefix := b.child(p)
+		var entry *DBEntry
+		if !commonPrefix {
+			e := newCatalogEntryFromEntry(false, p, v.Entry)
+			entry = &e
+		}
+		if !b.add(child, commonPrefix, entry, entryPrefixTreeStats(v.Entry)) {
+			return false, nil
+		}
+	}
+	return true, it.Err()
+}
+
+// ListPrefixTree lists the immediate children of prefix on reference, with the count, total size and last
+// modification time of the objects under each common prefix. A range of committed data whose keys are all under the
+// same common prefix is counted from its metadata and summarized once, without reading its entries for later calls,
+// so listing the top levels of a huge repository reads only the ranges that cross common prefixes. Uncommitted
+// changes under prefix are listed by reading all the entries under it.
+func (c *Catalog) ListPrefixTree(ctx context.Context, repositoryID, reference, prefix, after, delimiter string, limit int) (*PrefixTree, error) {
+	if limit < 0 || limit > ListEntriesLimitMax {
+		limit = ListEntriesLimitMax
+	}
+	ref := graveler.Ref(reference)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: ref, Fn: graveler.ValidateRef},
+		{Name: "prefix", Value: Path(prefix), Fn: ValidatePathOptional},
+		{Name: "delimiter", Value: Path(delimiter), Fn: ValidatePathOptional},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	b := &prefixTreeBuilder{
+		prefix:    prefix,
+		delimiter: delimiter,
+		after:     after,
+		limit:     limit,
+	}
+
+	resolved, err := c.Store.Dereference(ctx, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	uncommitted := false
+	if resolved.StagingToken != "" {
+		uncommitted, err = c.hasUncommittedUnder(ctx, repository, resolved.BranchID, prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if uncommitted {
+		iter, err := c.Store.List(ctx, repository, ref, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		it := NewValueToEntryIterator(iter)
+		defer it.Close()
+		if _, err := b.addEntries(it); err != nil {
+			return nil, err
+		}
+		return &b.tree, nil
+	}
+
+	ranges, err := c.Store.ListRanges(ctx, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, rng := range ranges {
+		minKey, maxKey := string(rng.MinKey), string(rng.MaxKey)
+		if maxKey < prefix {
+			continue
+		}
+		if !strings.HasPrefix(minKey, prefix) && minKey > prefix {
+			// ranges are sorted, the rest of the ranges are past prefix
+			break
+		}
+		if strings.HasPrefix(maxKey, prefix) {
+			maxChild, _ := b.child(maxKey)
+			if maxChild <= after {
+				continue
+			}
+			minChild, commonPrefix := "", false
+			if strings.HasPrefix(minKey, prefix) {
+				minChild, commonPrefix = b.child(minKey)
+			}
+			if commonPrefix && minChild == maxChild {
+				stats, err := c.getRangeStats(ctx, repository, rng)
+				if err != nil {
+					return nil, err
+				}
+				if !b.add(minChild, true, nil, stats) {
+					break
+				}
+				continue
+			}
+		}
+		more, err := c.addRangeEntries(ctx, repository, rng.ID, b)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+	}
+	return &b.tree, nil
+}
+
+func (c *Catalog) addRangeEntries(ctx context.Context, repository *graveler.RepositoryRecord, rangeID graveler.RangeID, b *prefixTreeBuilder) (bool, error) {
+	iter, err := c.Store.ListRange(ctx, repository, rangeID)
+	if err != nil {
+		return false, err
+	}
+	it := NewValueToEntryIterator(iter)
+	defer it.Close()
+	return b.addEntries(it)
+}
+
+// getRangeStats returns the stats of the objects of a range: the count from the range metadata, the size and last
+// modification time summarized from its entries on first use
+func (c *Catalog) getRangeStats(ctx context.Context, repository *graveler.RepositoryRecord, rng *graveler.RangeInfo) (PrefixTreeStats, error) {
+	v, err := c.rangeStatsCache.GetOrSet(rng.ID, func() (interface{}, error) {
+		iter, err := c.Store.ListRange(ctx, repository, rng.ID)
+		if err != nil {
+			return nil, err
+		}
+		it := NewValueToEntryIterator(iter)
+		defer it.Close()
+		var stats PrefixTreeStats
+		for it.Next() {
+			stats.add(entryPrefixTreeStats(it.Value().Entry))
+		}
+		return stats, it.Err()
+	})
+	if err != nil {
+		return PrefixTreeStats{}, err
+	}
+	stats := v.(PrefixTreeStats)
+	stats.Count = int64(rng.Count)
+	return stats, nil
+}
+
+// hasUncommittedUnder returns true if branch has uncommitted changes under prefix
+func (c *Catalog) hasUncommittedUnder(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, prefix string) (bool, error) {
+	it, err := c.Store.DiffUncommitted(ctx, repository, branchID)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	it.SeekGE(graveler.Key(prefix))
+	if !it.Next() {
+		return false, it.Err()
+	}
+	return strings.HasPrefix(it.Value().Key.String(), prefix), nil
+}
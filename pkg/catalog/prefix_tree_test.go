@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCatalog_ListPrefixTree(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	record := func(key string, size int64, age time.Duration) *graveler.ValueRecord {
+		return &graveler.ValueRecord{
+			Key:   graveler.Key(key),
+			Value: MustEntryToValue(&Entry{Address: key, LastModified: timestamppb.New(now.Add(-age)), Size: size}),
+		}
+	}
+	rangeValues := map[graveler.RangeID][]*graveler.ValueRecord{
+		// entirely under a/, summarized without listing its entries
+		"r1": {record("a/1", 10, time.Hour), record("a/2", 20, 0)},
+		// crosses a/, b/ and the object c
+		"r2": {record("a/3", 5, 2*time.Hour), record("b/1", 7, time.Minute), record("c", 3, time.Hour)},
+		"r3": {record("d/1", 1, time.Minute), record("d/sub/2", 2, time.Second)},
+	}
+	var ranges []*graveler.RangeInfo
+	for _, id := range []graveler.RangeID{"r1", "r2", "r3"} {
+		values := rangeValues[id]
+		ranges = append(ranges, &graveler.RangeInfo{
+			ID:     id,
+			MinKey: values[0].Key,
+			MaxKey: values[len(values)-1].Key,
+			Count:  len(values),
+		})
+	}
+	c := &Catalog{
+		Store:           &FakeGraveler{Ranges: ranges, RangeValues: rangeValues},
+		rangeStatsCache: newRangeStatsCache(),
+	}
+	prefixStats := func(path string, count, size int64, age time.Duration) PrefixTreeChild {
+		return PrefixTreeChild{
+			Path:         path,
+			CommonPrefix: true,
+			Stats:        PrefixTreeStats{Count: count, Size: size, LastModified: now.Add(-age)},
+		}
+	}
+	object := func(path string, size int64, age time.Duration) PrefixTreeChild {
+		entry := newCatalogEntryFromEntry(false, path, &Entry{Address: path, LastModified: timestamppb.New(now.Add(-age)), Size: size})
+		return PrefixTreeChild{
+			Path:  path,
+			Entry: &entry,
+			Stats: PrefixTreeStats{Count: 1, Size: size, LastModified: now.Add(-age)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		after    string
+		limit    int
+		expected *PrefixTree
+	}{
+		{
+			name:  "root",
+			limit: 100,
+			expected: &PrefixTree{Children: []PrefixTreeChild{
+				prefixStats("a/", 3, 35, 0),
+				prefixStats("b/", 1, 7, time.Minute),
+				object("c", 3, time.Hour),
+				prefixStats("d/", 2, 3, time.Second),
+			}},
+		},
+		{
+			name:  "limit",
+			limit: 2,
+			expected: &PrefixTree{
+				Children: []PrefixTreeChild{
+					prefixStats("a/", 3, 35, 0),
+					prefixStats("b/", 1, 7, time.Minute),
+				},
+				HasMore: true,
+			},
+		},
+		{
+			name:  "after",
+			after: "b/",
+			limit: 100,
+			expected: &PrefixTree{Children: []PrefixTreeChild{
+				object("c", 3, time.Hour),
+				prefixStats("d/", 2, 3, time.Second),
+			}},
+		},
+		{
+			name:   "prefix",
+			prefix: "a/",
+			limit:  100,
+			expected: &PrefixTree{Children: []PrefixTreeChild{
+				object("a/1", 10, time.Hour),
+				object("a/2", 20, 0),
+				object("a/3", 5, 2*time.Hour),
+			}},
+		},
+		{
+			name:   "nested prefix",
+			prefix: "d/",
+			limit:  100,
+			expected: &PrefixTree{Children: []PrefixTreeChild{
+				object("d/1", 1, time.Minute),
+				prefixStats("d/sub/", 1, 2, time.Second),
+			}},
+		},
+		{
+			name:     "missing prefix",
+			prefix:   "e/",
+			limit:    100,
+			expected: &PrefixTree{},
+		},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := c.ListPrefixTree(ctx, "repo", "main", tt.prefix, tt.after, DefaultPathDelimiter, tt.limit)
+			if err != nil {
+				t.Fatalf("ListPrefixTree failed: %s", err)
+			}
+			if diff := deep.Equal(tree, tt.expected); diff != nil {
+				t.Errorf("ListPrefixTree diff: %s", diff)
+			}
+		})
+	}
+}
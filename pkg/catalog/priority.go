@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"go.uber.org/ratelimit"
+)
+
+// PriorityClass of background jobs, limiting how many of them run at once and how fast they read ranges
+type PriorityClass string
+
+const (
+	PriorityHigh   PriorityClass = "high"
+	PriorityNormal PriorityClass = "normal"
+	PriorityLow    PriorityClass = "low"
+)
+
+// OperationKindStagingCompaction is the kind of the periodic compaction of branch staging areas
+const OperationKindStagingCompaction = "staging_compaction"
+
+// defaultJobPriorities are the priority classes of background jobs by kind.  Merges are usually waited on by users,
+// while dumps, restores, tagging and compaction can run slowly without getting in the way of requests.
+var defaultJobPriorities = map[string]PriorityClass{
+	OperationKindMerge:             PriorityHigh,
+	OperationKindImport:            PriorityNormal,
+	OperationKindTagObjects:        PriorityLow,
+	OperationKindDumpRefs:          PriorityLow,
+	OperationKindRestoreRefs:       PriorityLow,
+	OperationKindStagingCompaction: PriorityLow,
+	OperationKindLocalGC:           PriorityLow,
+}
+
+// priorityClass limits the jobs of a class
+type priorityClass struct {
+	// slots holds a token for each running job, nil for unlimited concurrency
+	slots chan struct{}
+	// readLimiter is shared by the jobs of the class, nil for unlimited range reads
+	readLimiter ratelimit.Limiter
+}
+
+// backgroundPriorities schedules background jobs by the priority class of their kind
+type backgroundPriorities struct {
+	classes map[PriorityClass]*priorityClass
+	jobs    map[string]PriorityClass
+}
+
+func newPriorityClass(cfg config.BackgroundPriority) *priorityClass {
+	class := &priorityClass{}
+	if cfg.MaxConcurrency > 0 {
+		class.slots = make(chan struct{}, cfg.MaxConcurrency)
+	}
+	if cfg.RangeReadRateLimit > 0 {
+		class.readLimiter = ratelimit.New(cfg.RangeReadRateLimit)
+	}
+	return class
+}
+
+func newBackgroundPriorities(cfg *config.Config) *backgroundPriorities {
+	background := cfg.Graveler.Background
+	p := &backgroundPriorities{
+		classes: map[PriorityClass]*priorityClass{
+			PriorityHigh:   newPriorityClass(background.Priorities.High),
+			PriorityNormal: newPriorityClass(background.Priorities.Normal),
+			PriorityLow:    newPriorityClass(background.Priorities.Low),
+		},
+		jobs: make(map[string]PriorityClass, len(defaultJobPriorities)),
+	}
+	for kind, class := range defaultJobPriorities {
+		p.jobs[kind] = class
+	}
+	for kind, class := range background.JobPriorities {
+		p.jobs[kind] = PriorityClass(class)
+	}
+	return p
+}
+
+// JobPriority returns the priority class of background jobs of kind
+func (p *backgroundPriorities) JobPriority(kind string) PriorityClass {
+	if class, ok := p.jobs[kind]; ok {
+		return class
+	}
+	return PriorityNormal
+}
+
+// acquire waits until a job of kind may run, and returns a context throttling its range reads and a release function
+// to call once the job ends.  Returns the context error if it is done while waiting.
+func (p *backgroundPriorities) acquire(ctx context.Context, kind string) (context.Context, func(), error) {
+	class, ok := p.classes[p.JobPriority(kind)]
+	if !ok {
+		return ctx, func() {}, nil
+	}
+	release := func() {}
+	if class.slots != nil {
+		select {
+		case class.slots <- struct{}{}:
+			release = func() { <-class.slots }
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	if class.readLimiter != nil {
+		ctx = graveler.WithReadLimiter(ctx, class.readLimiter)
+	}
+	return ctx, release, nil
+}
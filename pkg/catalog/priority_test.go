@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/config"
+)
+
+func TestBackgroundPriorities(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Graveler.Background.Priorities.Low.MaxConcurrency = 1
+	cfg.Graveler.Background.JobPriorities = map[string]string{OperationKindMerge: "low"}
+	p := newBackgroundPriorities(cfg)
+
+	if class := p.JobPriority(OperationKindMerge); class != PriorityLow {
+		t.Errorf("JobPriority(merge) = %s, expected overridden %s", class, PriorityLow)
+	}
+	if class := p.JobPriority(OperationKindImport); class != PriorityNormal {
+		t.Errorf("JobPriority(import) = %s, expected default %s", class, PriorityNormal)
+	}
+	if class := p.JobPriority("unknown"); class != PriorityNormal {
+		t.Errorf("JobPriority(unknown) = %s, expected %s", class, PriorityNormal)
+	}
+
+	ctx := context.Background()
+	_, release, err := p.acquire(ctx, OperationKindTagObjects)
+	if err != nil {
+		t.Fatalf("acquire first low priority job: %s", err)
+	}
+
+	// the class is full: another low priority job waits, while other classes run
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := p.acquire(waitCtx, OperationKindDumpRefs); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire second low priority job = %v, expected to wait until %s", err, context.DeadlineExceeded)
+	}
+	_, releaseImport, err := p.acquire(ctx, OperationKindImport)
+	if err != nil {
+		t.Fatalf("acquire normal priority job: %s", err)
+	}
+	releaseImport()
+
+	release()
+	_, release, err = p.acquire(ctx, OperationKindDumpRefs)
+	if err != nil {
+		t.Fatalf("acquire low priority job after release: %s", err)
+	}
+	release()
+}
@@ -0,0 +1,477 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	projectsPartition   = "projects"
+	organizationsPrefix = "organizations"
+	projectsPrefix      = "projects"
+
+	// ProjectRepositorySeparator separates the organization, project and name in the ID of a project repository.
+	// Organization and project IDs cannot contain it, so the ID of a project repository names a single project.
+	ProjectRepositorySeparator = "-"
+
+	ListOrganizationsLimitMax = 1000
+	ListProjectsLimitMax      = 1000
+)
+
+var projectIDRegexp = regexp.MustCompile(`^[a-z0-9]{2,20}$`)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(projectsPartition, organizationsPrefix, (&OrganizationData{}).ProtoReflect().Type())
+	kv.MustRegisterType(projectsPartition, projectsPrefix, (&ProjectData{}).ProtoReflect().Type())
+}
+
+// Organization groups the projects of a team or a tenant
+type Organization struct {
+	ID          string
+	Description string
+	// KMSKeyID encrypts the data of the repositories of organization projects without their own key
+	KMSKeyID  string
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// Project groups repositories of an organization. Repositories belong to a project by their ID,
+// <organization>-<project>-<name>, so their names do not collide with those of other projects, and policies on
+// arn:lakefs:fs:::repository/<organization>-<project>-* apply to all of the project repositories.
+type Project struct {
+	Organization string
+	ID           string
+	Description  string
+	// MaxRepositories is the largest number of repositories in the project, unlimited if zero
+	MaxRepositories int
+	// KMSKeyID encrypts the data of the project repositories, overriding the key of the organization
+	KMSKeyID  string
+	CreatedBy string
+	CreatedAt time.Time
+	// Repositories is the number of repositories in the project, including archived ones. Set when reading a
+	// single project.
+	Repositories int
+}
+
+func organizationFromProto(pb *OrganizationData) *Organization {
+	return &Organization{
+		ID:          pb.Id,
+		Description: pb.Description,
+		KMSKeyID:    pb.KmsKeyId,
+		CreatedBy:   pb.CreatedBy,
+		CreatedAt:   time.Unix(0, pb.CreatedAt).UTC(),
+	}
+}
+
+func protoFromOrganization(o *Organization) *OrganizationData {
+	return &OrganizationData{
+		Id:          o.ID,
+		Description: o.Description,
+		KmsKeyId:    o.KMSKeyID,
+		CreatedBy:   o.CreatedBy,
+		CreatedAt:   o.CreatedAt.UnixNano(),
+	}
+}
+
+func projectFromProto(pb *ProjectData) *Project {
+	return &Project{
+		Organization:    pb.Organization,
+		ID:              pb.Id,
+		Description:     pb.Description,
+		MaxRepositories: int(pb.MaxRepositories),
+		KMSKeyID:        pb.KmsKeyId,
+		CreatedBy:       pb.CreatedBy,
+		CreatedAt:       time.Unix(0, pb.CreatedAt).UTC(),
+	}
+}
+
+func protoFromProject(p *Project) *ProjectData {
+	return &ProjectData{
+		Organization:    p.Organization,
+		Id:              p.ID,
+		Description:     p.Description,
+		MaxRepositories: int32(p.MaxRepositories),
+		KmsKeyId:        p.KMSKeyID,
+		CreatedBy:       p.CreatedBy,
+		CreatedAt:       p.CreatedAt.UnixNano(),
+	}
+}
+
+func organizationPath(organization string) []byte {
+	return []byte(kv.FormatPath(organizationsPrefix, organization))
+}
+
+func projectPath(organization, project string) []byte {
+	return []byte(kv.FormatPath(projectsPrefix, organization, project))
+}
+
+func validateProjectID(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	if !projectIDRegexp.MatchString(s) {
+		return ErrInvalidProjectID
+	}
+	return nil
+}
+
+func validateMaxRepositories(v interface{}) error {
+	n, ok := v.(int)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	if n < 0 {
+		return ErrInvalidProjectQuota
+	}
+	return nil
+}
+
+// ProjectRepositoryID returns the ID of the repository name of project of organization
+func ProjectRepositoryID(organization, project, name string) string {
+	return organization + ProjectRepositorySeparator + project + ProjectRepositorySeparator + name
+}
+
+// projectRepositoryPrefix returns the prefix of the IDs of the repositories of project of organization
+func projectRepositoryPrefix(organization, project string) string {
+	return ProjectRepositoryID(organization, project, "")
+}
+
+// ParseProjectRepositoryID returns the organization, project and name a repository ID would have as a project
+// repository, and false if it cannot be the ID of a project repository. Whether the project exists is not checked.
+func ParseProjectRepositoryID(repositoryID string) (string, string, string, bool) {
+	const projectRepositoryParts = 3
+	parts := strings.SplitN(repositoryID, ProjectRepositorySeparator, projectRepositoryParts)
+	if len(parts) != projectRepositoryParts || parts[2] == "" ||
+		validateProjectID(parts[0]) != nil || validateProjectID(parts[1]) != nil {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// CreateOrganization creates an organization, failing with ErrOrganizationExists if it exists
+func (c *Catalog) CreateOrganization(ctx context.Context, organization Organization) (*Organization, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "organization", Value: organization.ID, Fn: validateProjectID},
+	}); err != nil {
+		return nil, err
+	}
+	if err := c.checkEncryptionDomainKey(organization.KMSKeyID); err != nil {
+		return nil, err
+	}
+	organization.CreatedAt = time.Now().UTC()
+	err := kv.SetMsgIf(ctx, c.KVStore, projectsPartition, organizationPath(organization.ID), protoFromOrganization(&organization), nil)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s: %w", organization.ID, ErrOrganizationExists)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// GetOrganization returns an organization
+func (c *Catalog) GetOrganization(ctx context.Context, organizationID string) (*Organization, error) {
+	organization, _, err := c.getOrganization(ctx, organizationID)
+	return organization, err
+}
+
+func (c *Catalog) getOrganization(ctx context.Context, organizationID string) (*Organization, kv.Predicate, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "organization", Value: organizationID, Fn: validateProjectID},
+	}); err != nil {
+		return nil, nil, err
+	}
+	data := &OrganizationData{}
+	pred, err := kv.GetMsg(ctx, c.KVStore, projectsPartition, organizationPath(organizationID), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, nil, fmt.Errorf("%s: %w", organizationID, ErrOrganizationNotFound)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return organizationFromProto(data), pred, nil
+}
+
+// SetOrganizationEncryptionKey sets the KMS key encrypting the data written to the repositories of the organization
+// projects that do not set their own key. An empty key uses the configured blockstore encryption. Data written
+// before the change keeps its key.
+func (c *Catalog) SetOrganizationEncryptionKey(ctx context.Context, organizationID, kmsKeyID string) (*Organization, error) {
+	if err := c.checkEncryptionDomainKey(kmsKeyID); err != nil {
+		return nil, err
+	}
+	organization, pred, err := c.getOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	organization.KMSKeyID = kmsKeyID
+	err = kv.SetMsgIf(ctx, c.KVStore, projectsPartition, organizationPath(organizationID), protoFromOrganization(organization), pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s changed: %w", organizationID, graveler.ErrConflictFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// ListOrganizations lists organizations by ID, starting after after
+func (c *Catalog) ListOrganizations(ctx context.Context, limit int, after string) ([]*Organization, bool, error) {
+	if limit < 0 || limit > ListOrganizationsLimitMax {
+		limit = ListOrganizationsLimitMax
+	}
+	var afterKey []byte
+	if after != "" {
+		afterKey = organizationPath(after)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&OrganizationData{}).ProtoReflect().Type(), projectsPartition,
+		[]byte(organizationsPrefix+kv.PathDelimiter), kv.IteratorOptionsAfter(afterKey))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var organizations []*Organization
+	for it.Next() {
+		if len(organizations) == limit {
+			return organizations, true, nil
+		}
+		organizations = append(organizations, organizationFromProto(it.Entry().Value.(*OrganizationData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return organizations, false, nil
+}
+
+// DeleteOrganization deletes an organization, failing with ErrOrganizationNotEmpty if it has projects
+func (c *Catalog) DeleteOrganization(ctx context.Context, organizationID string) error {
+	if _, err := c.GetOrganization(ctx, organizationID); err != nil {
+		return err
+	}
+	projects, _, err := c.ListProjects(ctx, organizationID, 1, "")
+	if err != nil {
+		return err
+	}
+	if len(projects) > 0 {
+		return fmt.Errorf("%s: %w", organizationID, ErrOrganizationNotEmpty)
+	}
+	return c.KVStore.Delete(ctx, []byte(projectsPartition), organizationPath(organizationID))
+}
+
+// CreateProject creates a project in an existing organization, failing with ErrProjectExists if it exists.
+// Repositories created before the project with IDs of its repositories become its repositories.
+func (c *Catalog) CreateProject(ctx context.Context, project Project) (*Project, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "organization", Value: project.Organization, Fn: validateProjectID},
+		{Name: "project", Value: project.ID, Fn: validateProjectID},
+		{Name: "max_repositories", Value: project.MaxRepositories, Fn: validateMaxRepositories},
+	}); err != nil {
+		return nil, err
+	}
+	if err := c.checkEncryptionDomainKey(project.KMSKeyID); err != nil {
+		return nil, err
+	}
+	if _, err := c.GetOrganization(ctx, project.Organization); err != nil {
+		return nil, err
+	}
+	project.CreatedAt = time.Now().UTC()
+	err := kv.SetMsgIf(ctx, c.KVStore, projectsPartition, projectPath(project.Organization, project.ID), protoFromProject(&project), nil)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s/%s: %w", project.Organization, project.ID, ErrProjectExists)
+	}
+	if err != nil {
+		return nil, err
+	}
+	project.Repositories, err = c.countProjectRepositories(ctx, project.Organization, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetProject returns a project with the number of its repositories
+func (c *Catalog) GetProject(ctx context.Context, organizationID, projectID string) (*Project, error) {
+	project, _, err := c.getProject(ctx, organizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	project.Repositories, err = c.countProjectRepositories(ctx, organizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (c *Catalog) getProject(ctx context.Context, organizationID, projectID string) (*Project, kv.Predicate, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "organization", Value: organizationID, Fn: validateProjectID},
+		{Name: "project", Value: projectID, Fn: validateProjectID},
+	}); err != nil {
+		return nil, nil, err
+	}
+	data := &ProjectData{}
+	pred, err := kv.GetMsg(ctx, c.KVStore, projectsPartition, projectPath(organizationID, projectID), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, nil, fmt.Errorf("%s/%s: %w", organizationID, projectID, ErrProjectNotFound)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return projectFromProto(data), pred, nil
+}
+
+// ListProjects lists the projects of an organization by ID, starting after after
+func (c *Catalog) ListProjects(ctx context.Context, organizationID string, limit int, after string) ([]*Project, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "organization", Value: organizationID, Fn: validateProjectID},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListProjectsLimitMax {
+		limit = ListProjectsLimitMax
+	}
+	var afterKey []byte
+	if after != "" {
+		afterKey = projectPath(organizationID, after)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&ProjectData{}).ProtoReflect().Type(), projectsPartition,
+		projectPath(organizationID, ""), kv.IteratorOptionsAfter(afterKey))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+	var projects []*Project
+	for it.Next() {
+		if len(projects) == limit {
+			return projects, true, nil
+		}
+		projects = append(projects, projectFromProto(it.Entry().Value.(*ProjectData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, false, err
+	}
+	return projects, false, nil
+}
+
+// SetProjectQuota sets the largest number of repositories in a project, unlimited if zero. Lowering it below the
+// number of project repositories only prevents creating more of them.
+func (c *Catalog) SetProjectQuota(ctx context.Context, organizationID, projectID string, maxRepositories int) (*Project, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "max_repositories", Value: maxRepositories, Fn: validateMaxRepositories},
+	}); err != nil {
+		return nil, err
+	}
+	project, pred, err := c.getProject(ctx, organizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	project.MaxRepositories = maxRepositories
+	err = kv.SetMsgIf(ctx, c.KVStore, projectsPartition, projectPath(organizationID, projectID), protoFromProject(project), pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s/%s changed: %w", organizationID, projectID, graveler.ErrConflictFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	project.Repositories, err = c.countProjectRepositories(ctx, organizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// SetProjectEncryptionKey sets the KMS key encrypting the data written to the project repositories. An empty key
+// uses the key of the organization. Data written before the change keeps its key.
+func (c *Catalog) SetProjectEncryptionKey(ctx context.Context, organizationID, projectID, kmsKeyID string) (*Project, error) {
+	if err := c.checkEncryptionDomainKey(kmsKeyID); err != nil {
+		return nil, err
+	}
+	project, pred, err := c.getProject(ctx, organizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	project.KMSKeyID = kmsKeyID
+	err = kv.SetMsgIf(ctx, c.KVStore, projectsPartition, projectPath(organizationID, projectID), protoFromProject(project), pred)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s/%s changed: %w", organizationID, projectID, graveler.ErrConflictFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	project.Repositories, err = c.countProjectRepositories(ctx, organizationID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// DeleteProject deletes a project, failing with ErrProjectNotEmpty if it has repositories
+func (c *Catalog) DeleteProject(ctx context.Context, organizationID, projectID string) error {
+	project, err := c.GetProject(ctx, organizationID, projectID)
+	if err != nil {
+		return err
+	}
+	if project.Repositories > 0 {
+		return fmt.Errorf("%s/%s has %d repositories: %w", organizationID, projectID, project.Repositories, ErrProjectNotEmpty)
+	}
+	return c.KVStore.Delete(ctx, []byte(projectsPartition), projectPath(organizationID, projectID))
+}
+
+// countProjectRepositories counts the repositories of a project, including archived ones
+func (c *Catalog) countProjectRepositories(ctx context.Context, organizationID, projectID string) (int, error) {
+	prefix := projectRepositoryPrefix(organizationID, projectID)
+	it, err := c.Store.ListRepositories(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	it.SeekGE(graveler.RepositoryID(prefix))
+	count := 0
+	for it.Next() {
+		if !strings.HasPrefix(it.Value().RepositoryID.String(), prefix) {
+			break
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// checkProjectQuota fails with ErrProjectQuotaExceeded if repositoryID is the ID of a repository of a project that
+// has its largest number of repositories. The quota is checked before the repository is created, so concurrent
+// creations may exceed it.
+func (c *Catalog) checkProjectQuota(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	organizationID, projectID, _, ok := ParseProjectRepositoryID(repositoryID.String())
+	if !ok {
+		return nil
+	}
+	project, _, err := c.getProject(ctx, organizationID, projectID)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if project.MaxRepositories == 0 {
+		return nil
+	}
+	count, err := c.countProjectRepositories(ctx, organizationID, projectID)
+	if err != nil {
+		return err
+	}
+	if count >= project.MaxRepositories {
+		return fmt.Errorf("%s/%s has %d repositories: %w", organizationID, projectID, count, ErrProjectQuotaExceeded)
+	}
+	return nil
+}
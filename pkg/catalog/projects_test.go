@@ -0,0 +1,164 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+)
+
+func TestParseProjectRepositoryID(t *testing.T) {
+	tests := []struct {
+		repositoryID string
+		organization string
+		project      string
+		name         string
+		ok           bool
+	}{
+		{repositoryID: "acme-ml-features", organization: "acme", project: "ml", name: "features", ok: true},
+		{repositoryID: "acme-ml-feature-store", organization: "acme", project: "ml", name: "feature-store", ok: true},
+		{repositoryID: "acme-ml-", ok: false},
+		{repositoryID: "acme-ml", ok: false},
+		{repositoryID: "a-ml-features", ok: false},
+		{repositoryID: "repository", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repositoryID, func(t *testing.T) {
+			organization, project, name, ok := catalog.ParseProjectRepositoryID(tt.repositoryID)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.organization, organization)
+			require.Equal(t, tt.project, project)
+			require.Equal(t, tt.name, name)
+		})
+	}
+}
+
+func TestCatalog_Projects(t *testing.T) {
+	ctx := context.Background()
+	c := &catalog.Catalog{
+		Store: &catalog.FakeGraveler{
+			RepositoryIteratorFactory: catalog.NewFakeRepositoryIteratorFactory([]*graveler.RepositoryRecord{
+				{RepositoryID: "acme-bi-reports", Repository: &graveler.Repository{}},
+				{RepositoryID: "acme-ml-features", Repository: &graveler.Repository{}},
+				{RepositoryID: "acme-ml-models", Repository: &graveler.Repository{State: graveler.RepositoryState_ARCHIVED}},
+				{RepositoryID: "acme-mlops-runs", Repository: &graveler.Repository{}},
+				{RepositoryID: "other", Repository: &graveler.Repository{}},
+			}),
+		},
+		KVStore: kvtest.GetStore(ctx, t),
+	}
+
+	_, err := c.CreateProject(ctx, catalog.Project{Organization: "acme", ID: "ml"})
+	require.ErrorIs(t, err, catalog.ErrOrganizationNotFound)
+	_, err = c.CreateOrganization(ctx, catalog.Organization{ID: "ac-me"})
+	require.ErrorIs(t, err, catalog.ErrInvalidProjectID)
+	_, err = c.CreateOrganization(ctx, catalog.Organization{ID: "acme", CreatedBy: "admin"})
+	require.NoError(t, err)
+	_, err = c.CreateOrganization(ctx, catalog.Organization{ID: "acme"})
+	require.ErrorIs(t, err, catalog.ErrOrganizationExists)
+
+	// existing repositories with the prefix of the project are its repositories
+	project, err := c.CreateProject(ctx, catalog.Project{Organization: "acme", ID: "ml", MaxRepositories: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, project.Repositories)
+	_, err = c.CreateProject(ctx, catalog.Project{Organization: "acme", ID: "bi", MaxRepositories: -1})
+	require.ErrorIs(t, err, catalog.ErrInvalidProjectQuota)
+	_, err = c.CreateProject(ctx, catalog.Project{Organization: "acme", ID: "bi"})
+	require.NoError(t, err)
+	projects, hasMore, err := c.ListProjects(ctx, "acme", 1, "")
+	require.NoError(t, err)
+	require.True(t, hasMore)
+	require.Len(t, projects, 1)
+	require.Equal(t, "bi", projects[0].ID)
+	projects, hasMore, err = c.ListProjects(ctx, "acme", 10, "bi")
+	require.NoError(t, err)
+	require.False(t, hasMore)
+	require.Len(t, projects, 1)
+	require.Equal(t, "ml", projects[0].ID)
+
+	// quota
+	_, err = c.CreateRepository(ctx, "acme-ml-labels", "mem://labels", "main", false)
+	require.ErrorIs(t, err, catalog.ErrProjectQuotaExceeded)
+	project, err = c.SetProjectQuota(ctx, "acme", "ml", 5)
+	require.NoError(t, err)
+	require.Equal(t, 5, project.MaxRepositories)
+	project, err = c.GetProject(ctx, "acme", "ml")
+	require.NoError(t, err)
+	require.Equal(t, 5, project.MaxRepositories)
+
+	// only empty projects and organizations are deleted
+	require.ErrorIs(t, c.DeleteProject(ctx, "acme", "ml"), catalog.ErrProjectNotEmpty)
+	require.ErrorIs(t, c.DeleteOrganization(ctx, "acme"), catalog.ErrOrganizationNotEmpty)
+	require.ErrorIs(t, c.DeleteProject(ctx, "acme", "ops"), catalog.ErrProjectNotFound)
+}
+
+// blockstoreTypeAdapter reports the blockstore type of another adapter
+type blockstoreTypeAdapter struct {
+	block.Adapter
+	blockstoreType string
+}
+
+func (a *blockstoreTypeAdapter) BlockstoreType() string {
+	return a.blockstoreType
+}
+
+func TestCatalog_EncryptionDomains(t *testing.T) {
+	ctx := context.Background()
+	adapter := &blockstoreTypeAdapter{Adapter: mem.New(ctx), blockstoreType: block.BlockstoreTypeMem}
+	c := &catalog.Catalog{
+		BlockAdapter: adapter,
+		Store: &catalog.FakeGraveler{
+			RepositoryIteratorFactory: catalog.NewFakeRepositoryIteratorFactory(nil),
+		},
+		KVStore: kvtest.GetStore(ctx, t),
+	}
+	_, err := c.CreateOrganization(ctx, catalog.Organization{ID: "acme", KMSKeyID: "acme-key"})
+	require.ErrorIs(t, err, catalog.ErrEncryptionDomainsNotSupported)
+
+	adapter.blockstoreType = block.BlockstoreTypeS3
+	_, err = c.CreateOrganization(ctx, catalog.Organization{ID: "acme", KMSKeyID: "acme-key"})
+	require.NoError(t, err)
+	_, err = c.CreateProject(ctx, catalog.Project{Organization: "acme", ID: "ml"})
+	require.NoError(t, err)
+	_, err = c.CreateProject(ctx, catalog.Project{Organization: "acme", ID: "bi", KMSKeyID: "acme-bi-key"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		repositoryID string
+		kmsKeyID     string
+	}{
+		{repositoryID: "acme-ml-features", kmsKeyID: "acme-key"},
+		{repositoryID: "acme-bi-reports", kmsKeyID: "acme-bi-key"},
+		{repositoryID: "acme-ops-runs"},
+		{repositoryID: "other"},
+	}
+	for _, tt := range tests {
+		kmsKeyID, err := c.RepositoryKMSKeyID(ctx, tt.repositoryID)
+		require.NoError(t, err)
+		require.Equal(t, tt.kmsKeyID, kmsKeyID, tt.repositoryID)
+	}
+	encryption, err := c.RepositoryEncryption(ctx, "acme-bi-reports")
+	require.NoError(t, err)
+	require.Equal(t, catalog.EncryptionContext{Algorithm: catalog.EncryptionAlgorithmAWSKMS, KMSKeyID: "acme-bi-key"}, encryption)
+
+	// the project key overrides the organization key, and clearing it falls back to the organization key
+	project, err := c.SetProjectEncryptionKey(ctx, "acme", "ml", "acme-ml-key")
+	require.NoError(t, err)
+	require.Equal(t, "acme-ml-key", project.KMSKeyID)
+	kmsKeyID, err := c.RepositoryKMSKeyID(ctx, "acme-ml-features")
+	require.NoError(t, err)
+	require.Equal(t, "acme-ml-key", kmsKeyID)
+	_, err = c.SetProjectEncryptionKey(ctx, "acme", "ml", "")
+	require.NoError(t, err)
+	organization, err := c.SetOrganizationEncryptionKey(ctx, "acme", "acme-new-key")
+	require.NoError(t, err)
+	require.Equal(t, "acme-new-key", organization.KMSKeyID)
+	kmsKeyID, err = c.RepositoryKMSKeyID(ctx, "acme-ml-features")
+	require.NoError(t, err)
+	require.Equal(t, "acme-new-key", kmsKeyID)
+}
@@ -0,0 +1,160 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	// QualityPrefix is the path under which data quality validation results are committed with the data they validate
+	QualityPrefix = "_lakefs_quality/"
+
+	ListQualityResultsLimitMax = 100
+	// maxQualityResultSize is the size of the largest validation result read, larger objects are skipped
+	maxQualityResultSize = 16 * 1024 * 1024
+)
+
+var ErrNotQualityResult = errors.New("not a validation result")
+
+// QualityResult summarizes a Great Expectations validation result (ExpectationSuiteValidationResult)
+type QualityResult struct {
+	Path                     string
+	Suite                    string
+	RunName                  string
+	RunTime                  string
+	Success                  bool
+	EvaluatedExpectations    int64
+	SuccessfulExpectations   int64
+	UnsuccessfulExpectations int64
+	SuccessPercent           float64
+}
+
+type greatExpectationsRunID struct {
+	RunName string `json:"run_name"`
+	RunTime string `json:"run_time"`
+}
+
+type greatExpectationsValidationResult struct {
+	Success    *bool `json:"success"`
+	Statistics *struct {
+		EvaluatedExpectations    int64    `json:"evaluated_expectations"`
+		SuccessfulExpectations   int64    `json:"successful_expectations"`
+		UnsuccessfulExpectations int64    `json:"unsuccessful_expectations"`
+		SuccessPercent           *float64 `json:"success_percent"`
+	} `json:"statistics"`
+	Meta struct {
+		ExpectationSuiteName string `json:"expectation_suite_name"`
+		// RunID is a run identifier object, or a string in older versions
+		RunID json.RawMessage `json:"run_id"`
+	} `json:"meta"`
+}
+
+// ParseQualityResult reads the Great Expectations validation result stored at path. Fails with ErrNotQualityResult
+// if the object is not a validation result, e.g. an expectation suite stored by the same store.
+func ParseQualityResult(path string, r io.Reader) (*QualityResult, error) {
+	var v greatExpectationsValidationResult
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s: %w", err, ErrNotQualityResult)
+	}
+	if v.Success == nil || v.Statistics == nil {
+		return nil, ErrNotQualityResult
+	}
+	result := &QualityResult{
+		Path:                     path,
+		Suite:                    v.Meta.ExpectationSuiteName,
+		Success:                  *v.Success,
+		EvaluatedExpectations:    v.Statistics.EvaluatedExpectations,
+		SuccessfulExpectations:   v.Statistics.SuccessfulExpectations,
+		UnsuccessfulExpectations: v.Statistics.UnsuccessfulExpectations,
+	}
+	if v.Statistics.SuccessPercent != nil {
+		result.SuccessPercent = *v.Statistics.SuccessPercent
+	}
+	var runID greatExpectationsRunID
+	if err := json.Unmarshal(v.Meta.RunID, &runID); err == nil {
+		result.RunName = runID.RunName
+		result.RunTime = runID.RunTime
+	} else {
+		_ = json.Unmarshal(v.Meta.RunID, &result.RunName)
+	}
+	return result, nil
+}
+
+// ListQualityResults lists the validation results committed under QualityPrefix at the commit ref points to, by path.
+// Objects under the prefix which are not validation results are skipped.
+func (c *Catalog) ListQualityResults(ctx context.Context, repositoryID, ref string, limit int, after string) ([]*QualityResult, bool, error) {
+	if limit < 0 || limit > ListQualityResultsLimitMax {
+		limit = ListQualityResultsLimitMax
+	}
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(ref), Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(ref))
+	if err != nil {
+		return nil, false, err
+	}
+	if after != "" && !strings.HasPrefix(after, QualityPrefix) {
+		after = QualityPrefix
+	}
+
+	var results []*QualityResult
+	for {
+		entries, hasMore, err := c.ListEntries(ctx, repositoryID, commitID.String(), QualityPrefix, after, "", limit)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, entry := range entries {
+			if len(results) == limit {
+				return results, true, nil
+			}
+			after = entry.Path
+			result, err := c.readQualityResult(ctx, repository, entry)
+			if errors.Is(err, ErrNotQualityResult) {
+				continue
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			results = append(results, result)
+		}
+		if !hasMore {
+			return results, false, nil
+		}
+	}
+}
+
+func (c *Catalog) readQualityResult(ctx context.Context, repository *graveler.RepositoryRecord, entry *DBEntry) (*QualityResult, error) {
+	if !strings.HasSuffix(entry.Path, ".json") || entry.Size > maxQualityResultSize {
+		return nil, ErrNotQualityResult
+	}
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	result, err := ParseQualityResult(entry.Path, reader)
+	if errors.Is(err, ErrNotQualityResult) {
+		logging.FromContext(ctx).WithError(err).WithField("path", entry.Path).Debug("Skipping quality object")
+	}
+	return result, err
+}
@@ -0,0 +1,56 @@
+package catalog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+)
+
+func TestParseQualityResult(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Content  string
+		Expected *catalog.QualityResult
+	}{
+		{
+			Name: "validation result",
+			Content: `{"success": false, "statistics": {"evaluated_expectations": 4, "successful_expectations": 3, "unsuccessful_expectations": 1, "success_percent": 75.0},
+				"meta": {"expectation_suite_name": "users", "run_id": {"run_name": "nightly", "run_time": "2024-03-01T00:00:00+00:00"}}, "results": []}`,
+			Expected: &catalog.QualityResult{
+				Path:                     "p",
+				Suite:                    "users",
+				RunName:                  "nightly",
+				RunTime:                  "2024-03-01T00:00:00+00:00",
+				Success:                  false,
+				EvaluatedExpectations:    4,
+				SuccessfulExpectations:   3,
+				UnsuccessfulExpectations: 1,
+				SuccessPercent:           75,
+			},
+		},
+		{
+			Name:    "string run id",
+			Content: `{"success": true, "statistics": {"evaluated_expectations": 0, "successful_expectations": 0, "unsuccessful_expectations": 0, "success_percent": null}, "meta": {"run_id": "run1"}}`,
+			Expected: &catalog.QualityResult{
+				Path:    "p",
+				RunName: "run1",
+				Success: true,
+			},
+		},
+		{Name: "expectation suite", Content: `{"expectation_suite_name": "users", "expectations": []}`},
+		{Name: "not json", Content: `success`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := catalog.ParseQualityResult("p", strings.NewReader(tc.Content))
+			if tc.Expected == nil {
+				require.ErrorIs(t, err, catalog.ErrNotQualityResult)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, result)
+		})
+	}
+}
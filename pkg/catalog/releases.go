@@ -0,0 +1,330 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const (
+	datasetsPartition      = "datasets"
+	datasetReleasesPrefix  = "releases"
+	datasetReferencePrefix = "dataset:"
+	// DatasetVersionLatest resolves to the latest release of a dataset that is not a pre-release
+	DatasetVersionLatest = "latest"
+
+	ListDatasetReleasesLimitMax = 1000
+	// MaxDatasetReleaseSources is the largest number of sources of a release
+	MaxDatasetReleaseSources = 100
+)
+
+var datasetNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,62}$`)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(datasetsPartition, datasetReleasesPrefix, (&DatasetReleaseData{}).ProtoReflect().Type())
+}
+
+// DatasetReleaseSource is data of a release: objects under Prefix at CommitID of Repository
+type DatasetReleaseSource struct {
+	Repository string
+	// Ref is the ref given when the release was created, CommitID the commit it pointed to
+	Ref      string
+	CommitID string
+	Prefix   string
+}
+
+// DatasetRelease groups data of one or more repositories under a semantic version of a dataset. Refs are resolved to
+// commits when the release is created and a release is never changed, so a version always resolves to the same data.
+type DatasetRelease struct {
+	Dataset     string
+	Version     string
+	Sources     []DatasetReleaseSource
+	Description string
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+func datasetReleaseFromProto(pb *DatasetReleaseData) *DatasetRelease {
+	release := &DatasetRelease{
+		Dataset:     pb.Dataset,
+		Version:     pb.Version,
+		Sources:     make([]DatasetReleaseSource, 0, len(pb.Sources)),
+		Description: pb.Description,
+		CreatedBy:   pb.CreatedBy,
+		CreatedAt:   time.Unix(0, pb.CreatedAt).UTC(),
+	}
+	for _, source := range pb.Sources {
+		release.Sources = append(release.Sources, DatasetReleaseSource{
+			Repository: source.Repository,
+			Ref:        source.Ref,
+			CommitID:   source.CommitId,
+			Prefix:     source.Prefix,
+		})
+	}
+	return release
+}
+
+func protoFromDatasetRelease(r *DatasetRelease) *DatasetReleaseData {
+	pb := &DatasetReleaseData{
+		Dataset:     r.Dataset,
+		Version:     r.Version,
+		Sources:     make([]*DatasetReleaseSourceData, 0, len(r.Sources)),
+		Description: r.Description,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt.UnixNano(),
+	}
+	for _, source := range r.Sources {
+		pb.Sources = append(pb.Sources, &DatasetReleaseSourceData{
+			Repository: source.Repository,
+			Ref:        source.Ref,
+			CommitId:   source.CommitID,
+			Prefix:     source.Prefix,
+		})
+	}
+	return pb
+}
+
+func datasetReleasePath(dataset, version string) []byte {
+	return []byte(kv.FormatPath(datasetReleasesPrefix, dataset, version))
+}
+
+func validateDatasetName(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		panic(graveler.ErrInvalidType)
+	}
+	if !datasetNameRegexp.MatchString(s) {
+		return ErrInvalidDatasetName
+	}
+	return nil
+}
+
+// parseDatasetVersion parses a semantic version, without a "v" prefix, e.g. 1.4.2 or 2.0.0-rc.1. Build metadata is not
+// allowed, as versions that differ only by it have the same precedence.
+func parseDatasetVersion(version string) (*semver.Version, error) {
+	v, err := semver.StrictNewVersion(version)
+	if err != nil || v.Metadata() != "" {
+		return nil, fmt.Errorf("%s: %w", version, ErrInvalidDatasetVersion)
+	}
+	return v, nil
+}
+
+// ParseDatasetReference splits a dataset reference, e.g. dataset:customers@1.4.2, into the dataset name and the
+// version. The dataset: prefix is optional, and the version is a version, a constraint such as ^1.4 or ~1.4.0, or
+// latest if not set.
+func ParseDatasetReference(reference string) (string, string, error) {
+	reference = strings.TrimPrefix(reference, datasetReferencePrefix)
+	dataset, version, found := strings.Cut(reference, "@")
+	if !found || version == "" {
+		version = DatasetVersionLatest
+	}
+	if err := validateDatasetName(dataset); err != nil {
+		return "", "", err
+	}
+	return dataset, version, nil
+}
+
+// CreateDatasetRelease creates a release of a dataset. The refs of the sources are resolved to commits, and the
+// release fails with ErrDatasetReleaseExists if the version was released before.
+func (c *Catalog) CreateDatasetRelease(ctx context.Context, release DatasetRelease) (*DatasetRelease, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "dataset", Value: release.Dataset, Fn: validateDatasetName},
+	}); err != nil {
+		return nil, err
+	}
+	version, err := parseDatasetVersion(release.Version)
+	if err != nil {
+		return nil, err
+	}
+	if len(release.Sources) == 0 || len(release.Sources) > MaxDatasetReleaseSources {
+		return nil, fmt.Errorf("%d sources: %w", len(release.Sources), ErrInvalidDatasetRelease)
+	}
+	sources := make([]DatasetReleaseSource, 0, len(release.Sources))
+	for _, source := range release.Sources {
+		if err := validator.Validate([]validator.ValidateArg{
+			{Name: "repository", Value: source.Repository, Fn: graveler.ValidateRepositoryID},
+			{Name: "ref", Value: graveler.Ref(source.Ref), Fn: graveler.ValidateRef},
+		}); err != nil {
+			return nil, err
+		}
+		repository, err := c.getRepository(ctx, source.Repository)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", source.Repository, err)
+		}
+		commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(source.Ref))
+		if err != nil {
+			return nil, fmt.Errorf("source %s@%s: %w", source.Repository, source.Ref, err)
+		}
+		source.CommitID = commitID.String()
+		sources = append(sources, source)
+	}
+
+	release.Version = version.String()
+	release.Sources = sources
+	release.CreatedAt = time.Now().UTC()
+	err = kv.SetMsgIf(ctx, c.KVStore, datasetsPartition, datasetReleasePath(release.Dataset, release.Version), protoFromDatasetRelease(&release), nil)
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		return nil, fmt.Errorf("%s@%s: %w", release.Dataset, release.Version, ErrDatasetReleaseExists)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// GetDatasetRelease returns a release by its exact version
+func (c *Catalog) GetDatasetRelease(ctx context.Context, dataset, version string) (*DatasetRelease, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "dataset", Value: dataset, Fn: validateDatasetName},
+	}); err != nil {
+		return nil, err
+	}
+	v, err := parseDatasetVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	data := &DatasetReleaseData{}
+	_, err = kv.GetMsg(ctx, c.KVStore, datasetsPartition, datasetReleasePath(dataset, v.String()), data)
+	if errors.Is(err, kv.ErrNotFound) {
+		return nil, fmt.Errorf("%s@%s: %w", dataset, version, ErrDatasetReleaseNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return datasetReleaseFromProto(data), nil
+}
+
+// datasetReleases returns the releases of a dataset, latest version first
+func (c *Catalog) datasetReleases(ctx context.Context, dataset string) ([]*DatasetRelease, []*semver.Version, error) {
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&DatasetReleaseData{}).ProtoReflect().Type(), datasetsPartition,
+		datasetReleasePath(dataset, ""), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	var releases []*DatasetRelease
+	var versions []*semver.Version
+	for it.Next() {
+		release := datasetReleaseFromProto(it.Entry().Value.(*DatasetReleaseData))
+		v, err := parseDatasetVersion(release.Version)
+		if err != nil {
+			return nil, nil, err
+		}
+		releases = append(releases, release)
+		versions = append(versions, v)
+	}
+	if err := it.Err(); err != nil {
+		return nil, nil, err
+	}
+	sort.Sort(&releasesByVersion{releases: releases, versions: versions})
+	return releases, versions, nil
+}
+
+// datasetReleaseCommits returns the commits of repositoryID used by the sources of all dataset releases
+func (c *Catalog) datasetReleaseCommits(ctx context.Context, repositoryID graveler.RepositoryID) ([]graveler.CommitID, error) {
+	it, err := kv.NewPrimaryIterator(ctx, c.KVStore, (&DatasetReleaseData{}).ProtoReflect().Type(), datasetsPartition,
+		[]byte(kv.FormatPath(datasetReleasesPrefix, "")), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var commitIDs []graveler.CommitID
+	for it.Next() {
+		for _, source := range it.Entry().Value.(*DatasetReleaseData).Sources {
+			if source.Repository == repositoryID.String() {
+				commitIDs = append(commitIDs, graveler.CommitID(source.CommitId))
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return commitIDs, nil
+}
+
+type releasesByVersion struct {
+	releases []*DatasetRelease
+	versions []*semver.Version
+}
+
+func (r *releasesByVersion) Len() int { return len(r.releases) }
+
+func (r *releasesByVersion) Less(i, j int) bool { return r.versions[i].GreaterThan(r.versions[j]) }
+
+func (r *releasesByVersion) Swap(i, j int) {
+	r.releases[i], r.releases[j] = r.releases[j], r.releases[i]
+	r.versions[i], r.versions[j] = r.versions[j], r.versions[i]
+}
+
+// ListDatasetReleases lists the releases of a dataset, latest version first. After is the version listing starts
+// after.
+func (c *Catalog) ListDatasetReleases(ctx context.Context, dataset, after string, limit int) ([]*DatasetRelease, bool, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "dataset", Value: dataset, Fn: validateDatasetName},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListDatasetReleasesLimitMax {
+		limit = ListDatasetReleasesLimitMax
+	}
+	var afterVersion *semver.Version
+	if after != "" {
+		v, err := parseDatasetVersion(after)
+		if err != nil {
+			return nil, false, err
+		}
+		afterVersion = v
+	}
+	releases, versions, err := c.datasetReleases(ctx, dataset)
+	if err != nil {
+		return nil, false, err
+	}
+	start := 0
+	if afterVersion != nil {
+		start = sort.Search(len(versions), func(i int) bool { return versions[i].LessThan(afterVersion) })
+	}
+	releases = releases[start:]
+	if len(releases) > limit {
+		return releases[:limit], true, nil
+	}
+	return releases, false, nil
+}
+
+// ResolveDatasetRelease returns the release a dataset reference resolves to: the release of its exact version, or
+// the latest release matching its version constraint. Pre-releases match only constraints that name a pre-release.
+func (c *Catalog) ResolveDatasetRelease(ctx context.Context, reference string) (*DatasetRelease, error) {
+	dataset, version, err := ParseDatasetReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := semver.StrictNewVersion(version); err == nil {
+		return c.GetDatasetRelease(ctx, dataset, version)
+	}
+	if version == DatasetVersionLatest {
+		version = "*"
+	}
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", version, ErrInvalidDatasetVersion)
+	}
+	releases, versions, err := c.datasetReleases(ctx, dataset)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range versions {
+		if constraint.Check(v) {
+			return releases[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", reference, ErrDatasetReleaseNotFound)
+}
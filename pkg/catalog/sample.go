@@ -0,0 +1,152 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/preview"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+// MaxSampleObjects is the maximal number of objects sampled together
+const MaxSampleObjects = 1000
+
+type SampleParams struct {
+	Prefix string
+	// Format of the objects to sample, other objects under the prefix are skipped. Detected from the first object of
+	// a known format when empty.
+	Format preview.Format
+	Rows   int
+	Seed   int64
+}
+
+// ObjectSample is a uniform sample of the rows of the objects under a prefix
+type ObjectSample struct {
+	*preview.Table
+	CommitID string
+	Objects  int
+	RowsRead int64
+}
+
+// SampleObjects draws a sample of the rows of the objects under a prefix, at the commit ref points to. Sampling the
+// same commit with the same seed draws the same rows.
+func (c *Catalog) SampleObjects(ctx context.Context, repositoryID, reference string, params SampleParams) (*ObjectSample, error) {
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "ref", Value: graveler.Ref(reference), Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	commitID, err := c.dereferenceCommitID(ctx, repository, graveler.Ref(reference))
+	if err != nil {
+		return nil, err
+	}
+
+	format := params.Format
+	var entries []*DBEntry
+	var after string
+	for {
+		page, hasMore, err := c.ListEntries(ctx, repositoryID, commitID.String(), params.Prefix, after, "", ListEntriesLimitMax)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range page {
+			after = entry.Path
+			entryFormat, err := preview.DetectFormat(entry.Path, entry.ContentType)
+			if err != nil || (format != "" && entryFormat != format) {
+				continue
+			}
+			format = entryFormat
+			if len(entries) == MaxSampleObjects {
+				return nil, fmt.Errorf("%s: %w", params.Prefix, ErrTooManySampleObjects)
+			}
+			entries = append(entries, entry)
+		}
+		if !hasMore {
+			break
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: %w", params.Prefix, ErrNoSampleObjects)
+	}
+
+	sampler := preview.NewSampler(format, params.Rows, params.Seed)
+	for _, entry := range entries {
+		if entry.Expired {
+			return nil, fmt.Errorf("%s: %w", entry.Path, ErrExpired)
+		}
+		if err := c.sampleObject(ctx, repository, sampler, entry, format); err != nil {
+			return nil, err
+		}
+	}
+	return &ObjectSample{
+		Table:    sampler.Table(),
+		CommitID: commitID.String(),
+		Objects:  len(entries),
+		RowsRead: sampler.Seen(),
+	}, nil
+}
+
+// sampleObject adds an object to sampler. Parquet objects are read by ranges, to read only the pages of the sampled
+// rows, other objects are read whole.
+func (c *Catalog) sampleObject(ctx context.Context, repository *graveler.RepositoryRecord, sampler *preview.Sampler, entry *DBEntry, format preview.Format) error {
+	pointer := block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	}
+	if format == preview.FormatParquet {
+		r := &blockReaderAt{ctx: ctx, adapter: c.BlockAdapter, pointer: pointer, size: entry.Size}
+		if err := sampler.AddParquet(r, entry.Size); err != nil {
+			return fmt.Errorf("%w %s: %s", ErrInvalidSampleObject, entry.Path, err)
+		}
+		return nil
+	}
+	reader, err := c.BlockAdapter.Get(ctx, pointer)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	if err := sampler.Add(reader); err != nil {
+		return fmt.Errorf("%w %s: %s", ErrInvalidSampleObject, entry.Path, err)
+	}
+	return nil
+}
+
+// blockReaderAt reads ranges of an object through the block adapter
+type blockReaderAt struct {
+	ctx     context.Context
+	adapter block.Adapter
+	pointer block.ObjectPointer
+	size    int64
+}
+
+func (b *blockReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= b.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p)) - 1
+	if end >= b.size {
+		end = b.size - 1
+	}
+	reader, err := b.adapter.GetRange(b.ctx, b.pointer, off, end)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = reader.Close() }()
+	n, err := io.ReadFull(reader, p[:end-off+1])
+	if err != nil {
+		return n, err
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
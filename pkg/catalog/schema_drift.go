@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/preview"
+	"github.com/treeverse/lakefs/pkg/validator"
+)
+
+const ListSchemaDriftLimitMax = 100
+
+// SchemaDrift is the column level change of a tabular object between two refs. Error is set instead of Changes
+// when the schema of either side could not be read, e.g. when the object is too large or is not well formed.
+type SchemaDrift struct {
+	Path    string
+	Type    DifferenceType
+	Format  preview.Format
+	Changes []preview.ColumnChange
+	Error   string
+}
+
+// ListSchemaDrift compares the schemas of the tabular objects under prefix that differ between the left and the right
+// refs, by path. An added object reports all its columns as added and a removed object all its columns as removed.
+// Changed objects with the same columns, and objects in formats that have no schema, are skipped.
+func (c *Catalog) ListSchemaDrift(ctx context.Context, repositoryID, leftReference, rightReference, prefix, after string, limit int) ([]*SchemaDrift, bool, error) {
+	if limit < 0 || limit > ListSchemaDriftLimitMax {
+		limit = ListSchemaDriftLimitMax
+	}
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "left", Value: graveler.Ref(leftReference), Fn: graveler.ValidateRef},
+		{Name: "right", Value: graveler.Ref(rightReference), Fn: graveler.ValidateRef},
+	}); err != nil {
+		return nil, false, err
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var results []*SchemaDrift
+	for {
+		diffs, hasMore, err := c.Diff(ctx, repositoryID, leftReference, rightReference, DiffParams{
+			Limit:  limit,
+			After:  after,
+			Prefix: prefix,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		for i := range diffs {
+			if len(results) == limit {
+				return results, true, nil
+			}
+			d := &diffs[i]
+			after = d.Path
+			format, err := preview.DetectFormat(d.Path, d.ContentType)
+			if err != nil {
+				continue
+			}
+			drift, err := c.readSchemaDrift(ctx, repository, leftReference, rightReference, d, format)
+			if err != nil {
+				return nil, false, err
+			}
+			if drift.Error == "" && len(drift.Changes) == 0 {
+				continue
+			}
+			results = append(results, drift)
+		}
+		if !hasMore {
+			return results, false, nil
+		}
+	}
+}
+
+// readSchemaDrift reads the schemas of the sides of a difference that have the object. Entries are read at each ref,
+// rather than taken from the difference, to follow aliases.
+func (c *Catalog) readSchemaDrift(ctx context.Context, repository *graveler.RepositoryRecord, leftReference, rightReference string, d *Difference, format preview.Format) (*SchemaDrift, error) {
+	drift := &SchemaDrift{Path: d.Path, Type: d.Type, Format: format}
+	var leftEntry, rightEntry *DBEntry
+	var err error
+	if d.Type == DifferenceTypeRemoved || d.Type == DifferenceTypeChanged {
+		leftEntry, err = c.GetEntry(ctx, repository.RepositoryID.String(), leftReference, d.Path, GetEntryParams{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if d.Type == DifferenceTypeAdded || d.Type == DifferenceTypeChanged {
+		rightEntry, err = c.GetEntry(ctx, repository.RepositoryID.String(), rightReference, d.Path, GetEntryParams{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var leftColumns, rightColumns []preview.Column
+	for _, side := range []struct {
+		entry   *DBEntry
+		columns *[]preview.Column
+	}{
+		{entry: leftEntry, columns: &leftColumns},
+		{entry: rightEntry, columns: &rightColumns},
+	} {
+		if side.entry == nil {
+			continue
+		}
+		if side.entry.Expired {
+			drift.Error = "object expired"
+			return drift, nil
+		}
+		columns, parseErr, err := c.readSchema(ctx, repository, side.entry, format)
+		if err != nil {
+			return nil, err
+		}
+		if parseErr != nil {
+			drift.Error = parseErr.Error()
+			return drift, nil
+		}
+		*side.columns = columns
+	}
+	drift.Changes = preview.DiffSchemas(leftColumns, rightColumns)
+	return drift, nil
+}
+
+// readSchema returns the columns of the object of entry. Failing to parse the object is returned as parseErr.
+func (c *Catalog) readSchema(ctx context.Context, repository *graveler.RepositoryRecord, entry *DBEntry, format preview.Format) (columns []preview.Column, parseErr error, err error) {
+	reader, err := c.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace.String(),
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	columns, parseErr = preview.Schema(reader, entry.Size, format)
+	return columns, parseErr, nil
+}
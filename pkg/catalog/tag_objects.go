@@ -0,0 +1,202 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/validator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// tagObjectsListBatchSize is the batch size used to list the branch while tagging objects
+	tagObjectsListBatchSize = 1000
+	// tagObjectsProgressInterval is the number of scanned entries between task progress updates
+	tagObjectsProgressInterval = 10000
+)
+
+type TagObjectsParams struct {
+	// Pattern glob matched against object paths. '*' does not match across '/', use '**' for that.
+	Pattern string
+	// Metadata user metadata set on each matching object
+	Metadata Metadata
+	// CommitMessage message of the commit holding the tagged objects, a default message is used when empty
+	CommitMessage string
+	Committer     string
+}
+
+// TagObjectsSubmit starts a background task that sets params.Metadata on all the objects on the branch whose
+// path matches params.Pattern, and commits them in a single commit.  The branch must not have uncommitted
+// changes, so the commit holds the tagging alone.  Task progress counts the objects tagged so far.
+// Returns the task ID to poll using TagObjectsStatus.
+func (c *Catalog) TagObjectsSubmit(ctx context.Context, repositoryID, branch string, params TagObjectsParams, opts ...graveler.SetOptionsFunc) (string, error) {
+	branchID := graveler.BranchID(branch)
+	if err := validator.Validate([]validator.ValidateArg{
+		{Name: "repository", Value: repositoryID, Fn: graveler.ValidateRepositoryID},
+		{Name: "branch", Value: branchID, Fn: graveler.ValidateBranchID},
+	}); err != nil {
+		return "", err
+	}
+	if len(params.Metadata) == 0 {
+		return "", fmt.Errorf("metadata: %w", graveler.ErrInvalidValue)
+	}
+	for k := range params.Metadata {
+		if strings.HasPrefix(k, apiutil.LakeFSMetadataPrefix) {
+			return "", fmt.Errorf("metadata key %s: %w", k, graveler.ErrInvalidValue)
+		}
+	}
+	matcher, err := glob.Compile(params.Pattern, '/')
+	if err != nil {
+		return "", fmt.Errorf("pattern %s: %w", params.Pattern, graveler.ErrInvalidValue)
+	}
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := c.Store.GetBranch(ctx, repository, branchID); err != nil {
+		return "", err
+	}
+	if err := c.checkNoUncommittedChanges(ctx, repository, branchID); err != nil {
+		return "", err
+	}
+	commitMessage := params.CommitMessage
+	if commitMessage == "" {
+		commitMessage = fmt.Sprintf("Tag objects matching %s", params.Pattern)
+	}
+
+	taskID := NewTaskID(TagObjectsTaskIDPrefix)
+	taskStatus := &TagObjectsStatus{}
+	var tagged int64
+	taskSteps := []taskStep{
+		{
+			Name: "tag objects",
+			Func: func(ctx context.Context) error {
+				var err error
+				tagged, err = c.tagObjects(ctx, repository, branchID, taskStatus, matcher, globLiteralPrefix(params.Pattern), params.Metadata, opts...)
+				return err
+			},
+		},
+		{
+			Name: "commit",
+			Func: func(ctx context.Context) error {
+				if tagged == 0 {
+					return nil
+				}
+				commitLog, err := c.Commit(ctx, repositoryID, branch, commitMessage, params.Committer, nil, nil, nil, false, opts...)
+				if err != nil {
+					return err
+				}
+				taskStatus.CommitId = commitLog.Reference
+				return nil
+			},
+		},
+	}
+	info := operationInfo{
+		Kind:        OperationKindTagObjects,
+		Description: fmt.Sprintf("tag objects matching %s on %s", params.Pattern, branch),
+		CreatedBy:   params.Committer,
+	}
+	if err := c.runBackgroundTaskSteps(repository, taskID, info, taskSteps, taskStatus); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+// tagObjects stages metadata on all the entries on the branch matching matcher, updating the task progress as it
+// goes.  Entries already holding the metadata and aliases are left as they are.  Returns the number of entries staged.
+func (c *Catalog) tagObjects(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, taskStatus *TagObjectsStatus, matcher glob.Glob, prefix string, metadata Metadata, opts ...graveler.SetOptionsFunc) (int64, error) {
+	it, err := c.Store.List(ctx, repository, graveler.Ref(branchID), tagObjectsListBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	it.SeekGE(graveler.Key(prefix))
+
+	var tagged, scanned int64
+	for it.Next() {
+		record := it.Value()
+		key := record.Key.String()
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+		scanned++
+		if scanned%tagObjectsProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return tagged, err
+			}
+			taskStatus.Task.Progress = tagged
+			taskStatus.Task.UpdatedAt = timestamppb.Now()
+			if err := c.updateTaskProgress(ctx, repository, taskStatus.Task, taskStatus, 0); err != nil {
+				return tagged, err
+			}
+		}
+		if !matcher.Match(key) {
+			continue
+		}
+		entry, err := ValueToEntry(record.Value)
+		if err != nil {
+			return tagged, err
+		}
+		if entry.Metadata[AliasTargetMetadataKey] != "" || hasMetadata(entry.Metadata, metadata) {
+			continue
+		}
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]string, len(metadata))
+		}
+		for k, v := range metadata {
+			entry.Metadata[k] = v
+		}
+		value, err := EntryToValue(entry)
+		if err != nil {
+			return tagged, err
+		}
+		if err := c.Store.Set(ctx, repository, branchID, record.Key, *value, opts...); err != nil {
+			return tagged, fmt.Errorf("tag %s: %w", key, err)
+		}
+		tagged++
+	}
+	if err := it.Err(); err != nil {
+		return tagged, err
+	}
+	taskStatus.Task.Progress = tagged
+	return tagged, nil
+}
+
+func (c *Catalog) TagObjectsStatus(ctx context.Context, repositoryID string, id string) (*TagObjectsStatus, error) {
+	repository, err := c.getRepository(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTaskID(TagObjectsTaskIDPrefix, id) {
+		return nil, graveler.ErrNotFound
+	}
+
+	var status TagObjectsStatus
+	err = GetTaskStatus(ctx, c.KVStore, repository, id, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// hasMetadata reports whether all of want is already set on metadata
+func hasMetadata(metadata map[string]string, want Metadata) bool {
+	for k, v := range want {
+		if got, ok := metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// globLiteralPrefix returns the part of pattern before its first special character, all matching paths start with it
+func globLiteralPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[{\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
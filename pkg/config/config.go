@@ -4,6 +4,8 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"sort"
 	"strings"
@@ -14,6 +16,7 @@ import (
 	"github.com/spf13/viper"
 	apiparams "github.com/treeverse/lakefs/pkg/api/params"
 	blockparams "github.com/treeverse/lakefs/pkg/block/params"
+	"github.com/treeverse/lakefs/pkg/faultinject"
 	"github.com/treeverse/lakefs/pkg/logging"
 )
 
@@ -23,13 +26,21 @@ var (
 	ErrMissingRequiredKeys   = fmt.Errorf("%w: missing required keys", ErrBadConfiguration)
 	ErrBadGCPCSEKValue       = fmt.Errorf("value of customer-supplied server side encryption is not a valid %d bytes AES key", gcpAESKeyLength)
 	ErrGCPEncryptKeyConflict = errors.New("setting both kms and customer supplied encryption will result failure when reading/writing object")
+	ErrBadListener           = fmt.Errorf("%w: listener", ErrBadConfiguration)
+	ErrBadUsageAttribution   = fmt.Errorf("%w: usage attribution", ErrBadConfiguration)
+	ErrBadObjectAccess       = fmt.Errorf("%w: object access", ErrBadConfiguration)
+	ErrBadOpenLineage        = fmt.Errorf("%w: openlineage", ErrBadConfiguration)
+	ErrBadBackgroundPriority = fmt.Errorf("%w: background priority", ErrBadConfiguration)
 )
 
 // UseLocalConfiguration set to true will add defaults that enable a lakeFS run
 // without any other configuration like DB or blockstore.
+// DevConfiguration adds a seeded admin user and a sample repository to these defaults, and disables calls to
+// external services.
 const (
 	UseLocalConfiguration   = "local-settings"
 	QuickstartConfiguration = "quickstart"
+	DevConfiguration        = "dev"
 )
 
 type OIDC struct {
@@ -71,16 +82,156 @@ type S3AuthInfo struct {
 	}
 }
 
+// TLS configuration of a listener
+type TLS struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// ClientCAFile when set, requires clients to present a certificate signed by one of the CAs in the file (mTLS)
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	// ACME when enabled, certificates are acquired and renewed automatically instead of read from CertFile and KeyFile
+	ACME ACME `mapstructure:"acme"`
+	// Certificates served by the server name clients request (SNI), e.g. a wildcard certificate per S3 gateway domain
+	// name. CertFile and KeyFile are served to clients requesting other names.
+	Certificates []TLSCertificate `mapstructure:"certificates"`
+}
+
+// TLSCertificate is a certificate served to clients requesting one of its domains
+type TLSCertificate struct {
+	// Domains server names the certificate is served for, "*.example.com" matches a single label under example.com.
+	// Defaults to the names of the certificate.
+	Domains  []string `mapstructure:"domains"`
+	CertFile string   `mapstructure:"cert_file"`
+	KeyFile  string   `mapstructure:"key_file"`
+}
+
+// ACME automatic certificate acquisition and renewal of a TLS listener
+type ACME struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Email contact address registered with the ACME account
+	Email string `mapstructure:"email"`
+	// Domains host names certificates are acquired for, in addition to the S3 gateway domain names
+	Domains []string `mapstructure:"domains"`
+	// GatewaySubdomains acquires certificates for virtual-host style bucket subdomains of the S3 gateway domain names
+	GatewaySubdomains bool `mapstructure:"gateway_subdomains"`
+	// CacheDir directory storing the account key and certificates
+	CacheDir string `mapstructure:"cache_dir"`
+	// DirectoryURL ACME directory endpoint, defaults to Let's Encrypt
+	DirectoryURL string `mapstructure:"directory_url"`
+}
+
+// AllowsHost reports whether a certificate may be acquired for host
+func (a *ACME) AllowsHost(host string, gatewayDomainNames []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, d := range a.Domains {
+		if host == strings.ToLower(d) {
+			return true
+		}
+	}
+	for _, d := range gatewayDomainNames {
+		d = strings.ToLower(d)
+		if host == d {
+			return true
+		}
+		// bucket subdomain: a single label under the gateway domain name
+		if a.GatewaySubdomains {
+			if bucket, ok := strings.CutSuffix(host, "."+d); ok && bucket != "" && !strings.Contains(bucket, ".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AnonymousReadRule makes a repository, or the objects under a prefix of it, readable without credentials
+type AnonymousReadRule struct {
+	Repository string `mapstructure:"repository"`
+	Prefix     string `mapstructure:"prefix"`
+}
+
+// BackgroundPriority limits the background jobs of a priority class
+type BackgroundPriority struct {
+	// MaxConcurrency is the number of jobs of the class running at once, 0 for unlimited
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+	// RangeReadRateLimit is the number of ranges read per second by all jobs of the class, 0 for unlimited
+	RangeReadRateLimit int `mapstructure:"range_read_rate_limit"`
+}
+
+// BlockstoreResilience configures protecting requests from a slow or failing blockstore bucket
+type BlockstoreResilience struct {
+	CircuitBreaker struct {
+		// FailureThreshold is the number of consecutive failed or slow calls to a bucket that open its circuit, 0
+		// disables the circuit breaker
+		FailureThreshold int `mapstructure:"failure_threshold"`
+		// SlowCallDuration is the duration from which calls count as failures, 0 counts only errors
+		SlowCallDuration time.Duration `mapstructure:"slow_call_duration"`
+		// OpenDuration is the time calls to a bucket with an open circuit fail fast, before a call probes it again
+		OpenDuration time.Duration `mapstructure:"open_duration"`
+	} `mapstructure:"circuit_breaker"`
+	HedgedReads struct {
+		Enabled bool `mapstructure:"enabled"`
+		// MinDelay is the minimal time a read runs before it is duplicated, the P99 read latency when it is longer
+		MinDelay time.Duration `mapstructure:"min_delay"`
+	} `mapstructure:"hedged_reads"`
+}
+
+func (r BlockstoreResilience) Params() blockparams.Resilience {
+	return blockparams.Resilience{
+		FailureThreshold:    r.CircuitBreaker.FailureThreshold,
+		SlowCallDuration:    r.CircuitBreaker.SlowCallDuration,
+		OpenDuration:        r.CircuitBreaker.OpenDuration,
+		HedgedReads:         r.HedgedReads.Enabled,
+		HedgedReadsMinDelay: r.HedgedReads.MinDelay,
+	}
+}
+
+// BlockstoreRetry configures retrying failed calls to a blockstore
+type BlockstoreRetry struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+}
+
+func (r BlockstoreRetry) Params() blockparams.Retry {
+	return blockparams.Retry(r)
+}
+
+// Listener address serving the API and the S3 gateway
+type Listener struct {
+	ListenAddress string `mapstructure:"listen_address"`
+	TLS           TLS    `mapstructure:"tls"`
+}
+
+// FaultInjection configures the faults injected into calls to a dependency. Probabilities are of a single call.
+type FaultInjection struct {
+	ErrorProbability          float64       `mapstructure:"error_probability"`
+	PartialFailureProbability float64       `mapstructure:"partial_failure_probability"`
+	LatencyProbability        float64       `mapstructure:"latency_probability"`
+	Latency                   time.Duration `mapstructure:"latency"`
+	// Operations limits injection to the named operations, such as "Put" or "SetIf"
+	Operations []string `mapstructure:"operations"`
+	Seed       int64    `mapstructure:"seed"`
+}
+
+func (f FaultInjection) Params() faultinject.Params {
+	return faultinject.Params{
+		ErrorProbability:          f.ErrorProbability,
+		PartialFailureProbability: f.PartialFailureProbability,
+		LatencyProbability:        f.LatencyProbability,
+		Latency:                   f.Latency,
+		Operations:                f.Operations,
+		Seed:                      f.Seed,
+	}
+}
+
 // Config - Output struct of configuration, used to validate.  If you read a key using a viper accessor
 // rather than accessing a field of this struct, that key will *not* be validated.  So don't
 // do that.
 type Config struct {
 	ListenAddress string `mapstructure:"listen_address"`
-	TLS           struct {
-		Enabled  bool   `mapstructure:"enabled"`
-		CertFile string `mapstructure:"cert_file"`
-		KeyFile  string `mapstructure:"key_file"`
-	} `mapstructure:"tls"`
+	TLS           TLS    `mapstructure:"tls"`
+	// Listeners additional addresses serving the API and the S3 gateway, each with its own TLS configuration
+	Listeners []Listener `mapstructure:"listeners"`
 
 	Actions struct {
 		// ActionsEnabled set to false will block any hook execution
@@ -92,6 +243,15 @@ type Config struct {
 			Enabled bool   `mapstructure:"enabled"`
 			Prefix  string `mapstructure:"prefix"`
 		} `mapstructure:"env"`
+		// Webhook AllowedEndpoints URL prefixes hooks may call, any endpoint is allowed when empty
+		Webhook struct {
+			AllowedEndpoints []string `mapstructure:"allowed_endpoints"`
+		} `mapstructure:"webhook"`
+		// Central repository branch whose actions apply to all the repositories, in addition to their own actions
+		Central struct {
+			Repository string `mapstructure:"repository"`
+			Branch     string `mapstructure:"branch"`
+		} `mapstructure:"central"`
 	} `mapstructure:"actions"`
 
 	Logging struct {
@@ -103,8 +263,30 @@ type Config struct {
 		AuditLogLevel string   `mapstructure:"audit_log_level"`
 		// TraceRequestHeaders work only on 'trace' level, default is false as it may log sensitive data to the log
 		TraceRequestHeaders bool `mapstructure:"trace_request_headers"`
+		// ModuleLevels override Level for the logs of lakeFS packages, e.g. gateway, graveler or auth
+		ModuleLevels map[string]string `mapstructure:"module_levels"`
+		// Sampling limits the debug and trace logs of frequently logged messages
+		Sampling struct {
+			Enabled    bool          `mapstructure:"enabled"`
+			Initial    int           `mapstructure:"initial"`
+			Thereafter int           `mapstructure:"thereafter"`
+			Interval   time.Duration `mapstructure:"interval"`
+		} `mapstructure:"sampling"`
 	}
 
+	// AccessLog logs the requests served by the API and gateways, separately from the application logs
+	AccessLog struct {
+		Enabled       bool     `mapstructure:"enabled"`
+		Format        string   `mapstructure:"format"`
+		Output        []string `mapstructure:"output"`
+		FileMaxSizeMB int      `mapstructure:"file_max_size_mb"`
+		FilesKeep     int      `mapstructure:"files_keep"`
+		S3            struct {
+			Region        string        `mapstructure:"region"`
+			FlushInterval time.Duration `mapstructure:"flush_interval"`
+		} `mapstructure:"s3"`
+	} `mapstructure:"access_log"`
+
 	Database struct {
 		// DropTables Development flag to delete tables after successful migration to KV
 		DropTables bool `mapstructure:"drop_tables"`
@@ -176,7 +358,9 @@ type Config struct {
 	}
 
 	Auth struct {
-		Cache struct {
+		// AnonymousRead lists the repositories, or prefixes of them, readable without credentials
+		AnonymousRead []AnonymousReadRule `mapstructure:"anonymous_read"`
+		Cache         struct {
 			Enabled bool          `mapstructure:"enabled"`
 			Size    int           `mapstructure:"size"`
 			TTL     time.Duration `mapstructure:"ttl"`
@@ -237,6 +421,10 @@ type Config struct {
 			ImportEnabled           bool     `mapstructure:"import_enabled"`
 			ImportHidden            bool     `mapstructure:"import_hidden"`
 			AllowedExternalPrefixes []string `mapstructure:"allowed_external_prefixes"`
+			Fsync                   bool     `mapstructure:"fsync"`
+			ShardDepth              int      `mapstructure:"shard_depth"`
+			MinFreeSpace            int64    `mapstructure:"min_free_space"`
+			ReflinkCopy             bool     `mapstructure:"reflink_copy"`
 		} `mapstructure:"local"`
 		S3 *struct {
 			S3AuthInfo                    `mapstructure:",squash"`
@@ -258,6 +446,9 @@ type Config struct {
 				SessionDuration     time.Duration `mapstructure:"session_duration"`
 				SessionExpiryWindow time.Duration `mapstructure:"session_expiry_window"`
 			} `mapstructure:"web_identity"`
+			Resilience       BlockstoreResilience `mapstructure:"resilience"`
+			Retry            BlockstoreRetry      `mapstructure:"retry"`
+			OperationTimeout time.Duration        `mapstructure:"operation_timeout"`
 		} `mapstructure:"s3"`
 		Azure *struct {
 			TryTimeout       time.Duration `mapstructure:"try_timeout"`
@@ -272,18 +463,27 @@ type Config struct {
 			ChinaCloudDeprecated bool   `mapstructure:"china_cloud"`
 			TestEndpointURL      string `mapstructure:"test_endpoint_url"`
 			// Domain by default points to Azure default domain blob.core.windows.net, can be set to other Azure domains (China/Gov)
-			Domain string `mapstructure:"domain"`
+			Domain           string               `mapstructure:"domain"`
+			Resilience       BlockstoreResilience `mapstructure:"resilience"`
+			Retry            BlockstoreRetry      `mapstructure:"retry"`
+			OperationTimeout time.Duration        `mapstructure:"operation_timeout"`
 		} `mapstructure:"azure"`
 		GS *struct {
-			S3Endpoint                           string        `mapstructure:"s3_endpoint"`
-			CredentialsFile                      string        `mapstructure:"credentials_file"`
-			CredentialsJSON                      string        `mapstructure:"credentials_json"`
-			PreSignedExpiry                      time.Duration `mapstructure:"pre_signed_expiry"`
-			DisablePreSigned                     bool          `mapstructure:"disable_pre_signed"`
-			DisablePreSignedUI                   bool          `mapstructure:"disable_pre_signed_ui"`
-			ServerSideEncryptionCustomerSupplied string        `mapstructure:"server_side_encryption_customer_supplied"`
-			ServerSideEncryptionKmsKeyID         string        `mapstructure:"server_side_encryption_kms_key_id"`
+			S3Endpoint                           string               `mapstructure:"s3_endpoint"`
+			CredentialsFile                      string               `mapstructure:"credentials_file"`
+			CredentialsJSON                      string               `mapstructure:"credentials_json"`
+			PreSignedExpiry                      time.Duration        `mapstructure:"pre_signed_expiry"`
+			DisablePreSigned                     bool                 `mapstructure:"disable_pre_signed"`
+			DisablePreSignedUI                   bool                 `mapstructure:"disable_pre_signed_ui"`
+			ServerSideEncryptionCustomerSupplied string               `mapstructure:"server_side_encryption_customer_supplied"`
+			ServerSideEncryptionKmsKeyID         string               `mapstructure:"server_side_encryption_kms_key_id"`
+			Resilience                           BlockstoreResilience `mapstructure:"resilience"`
+			Retry                                BlockstoreRetry      `mapstructure:"retry"`
+			OperationTimeout                     time.Duration        `mapstructure:"operation_timeout"`
 		} `mapstructure:"gs"`
+		Metrics struct {
+			RepositoryLabelsLimit int `mapstructure:"repository_labels_limit"`
+		} `mapstructure:"metrics"`
 	} `mapstructure:"blockstore"`
 	Committed struct {
 		LocalCache struct {
@@ -303,7 +503,16 @@ type Config struct {
 			Memory struct {
 				CacheSizeBytes int64 `mapstructure:"cache_size_bytes"`
 			} `mapstructure:"memory"`
+			FormatVersion         int `mapstructure:"format_version"`
+			BloomFilterBitsPerKey int `mapstructure:"bloom_filter_bits_per_key"`
 		} `mapstructure:"sstable"`
+		KeyFilter struct {
+			CacheSize int `mapstructure:"cache_size"`
+		} `mapstructure:"key_filter"`
+		RangePrefetch struct {
+			Ranges      int `mapstructure:"ranges"`
+			Concurrency int `mapstructure:"concurrency"`
+		} `mapstructure:"range_prefetch"`
 	} `mapstructure:"committed"`
 	UGC struct {
 		PrepareMaxFileSize int64         `mapstructure:"prepare_max_file_size"`
@@ -325,8 +534,50 @@ type Config struct {
 		} `mapstructure:"commit_cache"`
 		Background struct {
 			RateLimit int `mapstructure:"rate_limit"`
+			// Priorities configure the background jobs running concurrently and their range reads, by priority class
+			Priorities struct {
+				High   BackgroundPriority `mapstructure:"high"`
+				Normal BackgroundPriority `mapstructure:"normal"`
+				Low    BackgroundPriority `mapstructure:"low"`
+			} `mapstructure:"priorities"`
+			// JobPriorities override the priority class of background jobs by kind
+			JobPriorities map[string]string `mapstructure:"job_priorities"`
 		} `mapstructure:"background"`
-		MaxBatchDelay time.Duration `mapstructure:"max_batch_delay"`
+		MaxBatchDelay     time.Duration `mapstructure:"max_batch_delay"`
+		StagingCompaction struct {
+			Enabled          bool          `mapstructure:"enabled"`
+			Interval         time.Duration `mapstructure:"interval"`
+			MinStagedEntries int           `mapstructure:"min_staged_entries"`
+		} `mapstructure:"staging_compaction"`
+		Retention struct {
+			// AllowTimeOverrideTestOnly lets requests preparing garbage collection set the time retention rules are
+			// evaluated at
+			AllowTimeOverrideTestOnly bool `mapstructure:"allow_time_override_test_only"`
+		} `mapstructure:"retention"`
+		RepositoryArchive struct {
+			// GracePeriod is the time an archived repository is kept before it may be deleted
+			GracePeriod time.Duration `mapstructure:"grace_period"`
+		} `mapstructure:"repository_archive"`
+		BranchHistory struct {
+			// Retention is the time branch history records are kept to resolve branches at a time
+			Retention time.Duration `mapstructure:"retention"`
+			// Integrity chains branch history records using integrity hashes, notarized to the storage namespaces
+			// of the repositories
+			Integrity struct {
+				Enabled bool `mapstructure:"enabled"`
+				// NotarizationInterval is the interval of writing the latest integrity hashes of the branches of
+				// each repository to its storage namespace, zero does not notarize them
+				NotarizationInterval time.Duration `mapstructure:"notarization_interval"`
+			} `mapstructure:"integrity"`
+		} `mapstructure:"branch_history"`
+		RefTrash struct {
+			// Retention is the time deleted branches and tags can be restored
+			Retention time.Duration `mapstructure:"retention"`
+		} `mapstructure:"ref_trash"`
+		Merge struct {
+			// AsyncThreshold is the time a merge request allowing async runs before it returns a task ID to poll
+			AsyncThreshold time.Duration `mapstructure:"async_threshold"`
+		} `mapstructure:"merge"`
 	} `mapstructure:"graveler"`
 	Gateways struct {
 		S3 struct {
@@ -334,8 +585,41 @@ type Config struct {
 			Region            string  `mapstructure:"region"`
 			FallbackURL       string  `mapstructure:"fallback_url"`
 			VerifyUnsupported bool    `mapstructure:"verify_unsupported"`
+			// DenialsLogSize number of recent denied requests kept for the recent denials endpoint
+			DenialsLogSize int `mapstructure:"denials_log_size"`
 		} `mapstructure:"s3"`
+		NFS struct {
+			Enabled       bool    `mapstructure:"enabled"`
+			ListenAddress string  `mapstructure:"listen_address"`
+			Repositories  Strings `mapstructure:"repositories"`
+		} `mapstructure:"nfs"`
+		WebDAV struct {
+			Enabled  bool `mapstructure:"enabled"`
+			Writable bool `mapstructure:"writable"`
+		} `mapstructure:"webdav"`
+		Website struct {
+			Enabled       bool   `mapstructure:"enabled"`
+			IndexDocument string `mapstructure:"index_document"`
+			ErrorDocument string `mapstructure:"error_document"`
+		} `mapstructure:"website"`
 	}
+	GraphQL struct {
+		Enabled bool `mapstructure:"enabled"`
+	} `mapstructure:"graphql"`
+	Health struct {
+		// ProbeInterval time between probes of the dependencies reported by the readiness endpoint
+		ProbeInterval time.Duration `mapstructure:"probe_interval"`
+		// ProbeTimeout time a dependency probe may take before the dependency is reported failed
+		ProbeTimeout time.Duration `mapstructure:"probe_timeout"`
+	} `mapstructure:"health"`
+	GRPC struct {
+		Enabled       bool   `mapstructure:"enabled"`
+		ListenAddress string `mapstructure:"listen_address"`
+	} `mapstructure:"grpc"`
+	Iceberg struct {
+		Enabled    bool   `mapstructure:"enabled"`
+		S3Endpoint string `mapstructure:"s3_endpoint"`
+	} `mapstructure:"iceberg"`
 	Stats struct {
 		Enabled       bool          `mapstructure:"enabled"`
 		Address       string        `mapstructure:"address"`
@@ -346,18 +630,52 @@ type Config struct {
 	EmailSubscription struct {
 		Enabled bool `mapstructure:"enabled"`
 	} `mapstructure:"email_subscription"`
+	Notifications struct {
+		Enabled         bool          `mapstructure:"enabled"`
+		DeliveryTimeout time.Duration `mapstructure:"delivery_timeout"`
+		SMTP            struct {
+			Host     string       `mapstructure:"host"`
+			Port     int          `mapstructure:"port"`
+			Username string       `mapstructure:"username"`
+			Password SecureString `mapstructure:"password"`
+			From     string       `mapstructure:"from"`
+		} `mapstructure:"smtp"`
+		Slack struct {
+			// AllowedEndpoints URL prefixes Slack webhook subscriptions may post to
+			AllowedEndpoints []string `mapstructure:"allowed_endpoints"`
+		} `mapstructure:"slack"`
+	} `mapstructure:"notifications"`
+	// OpenLineage emits an OpenLineage run event for every commit and merge, e.g. to Marquez
+	OpenLineage struct {
+		Enabled bool `mapstructure:"enabled"`
+		// URL the events are posted to, e.g. http://marquez:5000/api/v1/lineage
+		URL    string       `mapstructure:"url"`
+		APIKey SecureString `mapstructure:"api_key"`
+		// Namespace of jobs that are not named by the metadata of their commit
+		Namespace string `mapstructure:"namespace"`
+		// DatasetPrefixDepth number of path components of the changed prefixes reported as datasets, 0 reports
+		// the branch as a single dataset
+		DatasetPrefixDepth int           `mapstructure:"dataset_prefix_depth"`
+		Timeout            time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"openlineage"`
 	Installation struct {
 		FixedID                 string       `mapstructure:"fixed_id"`
 		UserName                string       `mapstructure:"user_name"`
 		AccessKeyID             SecureString `mapstructure:"access_key_id"`
 		SecretAccessKey         SecureString `mapstructure:"secret_access_key"`
 		AllowInterRegionStorage bool         `mapstructure:"allow_inter_region_storage"`
+		// SampleRepository is the name of a repository with sample data created by the initial setup, none if empty
+		SampleRepository string `mapstructure:"sample_repository"`
 	} `mapstructure:"installation"`
 	Security struct {
 		CheckLatestVersion      bool          `mapstructure:"check_latest_version"`
 		CheckLatestVersionCache time.Duration `mapstructure:"check_latest_version_cache"`
 		AuditCheckInterval      time.Duration `mapstructure:"audit_check_interval"`
 		AuditCheckURL           string        `mapstructure:"audit_check_url"`
+		// EncryptionRestrictions lists KMS keys whose data may be placed only on branches all of whose readers may use them
+		EncryptionRestrictions []struct {
+			KMSKeyID string `mapstructure:"kms_key_id"`
+		} `mapstructure:"encryption_restrictions"`
 	} `mapstructure:"security"`
 	UI struct {
 		// Enabled - control serving of embedded UI
@@ -367,10 +685,38 @@ type Config struct {
 			Code string `mapstructure:"code"`
 		} `mapstructure:"snippets"`
 	} `mapstructure:"ui"`
+	// FaultInjection injects faults into calls to the blockstore and the database, for testing lakeFS under
+	// failures. Never enable it in production.
+	FaultInjection struct {
+		Blockstore FaultInjection `mapstructure:"blockstore"`
+		Database   FaultInjection `mapstructure:"database"`
+	} `mapstructure:"fault_injection"`
 	UsageReport struct {
 		Enabled       bool          `mapstructure:"enabled"`
 		FlushInterval time.Duration `mapstructure:"flush_interval"`
+		Attribution   struct {
+			Enabled               bool          `mapstructure:"enabled"`
+			Interval              time.Duration `mapstructure:"interval"`
+			Location              string        `mapstructure:"location"`
+			BranchPrefixDelimiter string        `mapstructure:"branch_prefix_delimiter"`
+		} `mapstructure:"attribution"`
 	} `mapstructure:"usage_report"`
+	// ObjectAccess tracks reads of objects by user, to find cold data and the consumers of data
+	ObjectAccess struct {
+		Enabled bool `mapstructure:"enabled"`
+		// SampleRate is the fraction of reads tracked, 1 tracks every read
+		SampleRate    float64       `mapstructure:"sample_rate"`
+		FlushInterval time.Duration `mapstructure:"flush_interval"`
+		// MaxPendingRecords is the number of distinct object and user read counts kept in memory between flushes
+		MaxPendingRecords int `mapstructure:"max_pending_records"`
+		// ColdData periodically reports the prefixes of each repository not read nor written for InactiveFor
+		ColdData struct {
+			Enabled     bool          `mapstructure:"enabled"`
+			Interval    time.Duration `mapstructure:"interval"`
+			InactiveFor time.Duration `mapstructure:"inactive_for"`
+			PrefixDepth int           `mapstructure:"prefix_depth"`
+		} `mapstructure:"cold_data"`
+	} `mapstructure:"object_access"`
 }
 
 func NewConfig(cfgType string) (*Config, error) {
@@ -398,13 +744,73 @@ func newConfig(cfgType string) (*Config, error) {
 		return nil, err
 	}
 
+	err = c.validateListeners()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateUsageAttribution()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateBackgroundPriorities()
+	if err != nil {
+		return nil, err
+	}
+	err = c.validateObjectAccess()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateOpenLineage()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateAnonymousRead()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateFaultInjection()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateStagingCompaction()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateSSTable()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Committed.KeyFilter.CacheSize <= 0 {
+		return nil, fmt.Errorf("%w: key filter cache size must be positive", ErrBadConfiguration)
+	}
+
+	if c.Committed.RangePrefetch.Ranges < 0 || c.Committed.RangePrefetch.Concurrency < 0 {
+		return nil, fmt.Errorf("%w: range prefetch ranges and concurrency must not be negative", ErrBadConfiguration)
+	}
+
 	// setup logging package
 	logging.SetOutputFormat(c.Logging.Format)
 	err = logging.SetOutputs(c.Logging.Output, c.Logging.FileMaxSizeMB, c.Logging.FilesKeep)
 	if err != nil {
 		return nil, err
 	}
-	logging.SetLevel(c.Logging.Level)
+	if err := logging.SetLevels(c.Logging.Level, c.Logging.ModuleLevels); err != nil {
+		return nil, fmt.Errorf("%w: logging: %w", ErrBadConfiguration, err)
+	}
+	logging.SetSampling(logging.SamplingConfig{
+		Enabled:    c.Logging.Sampling.Enabled,
+		Initial:    c.Logging.Sampling.Initial,
+		Thereafter: c.Logging.Sampling.Thereafter,
+		Interval:   c.Logging.Sampling.Interval,
+	})
 	return c, nil
 }
 
@@ -443,6 +849,166 @@ func (c *Config) validateDomainNames() error {
 	return nil
 }
 
+// HTTPListeners returns all the listeners serving the API and the S3 gateway, starting with the main listen address
+func (c *Config) HTTPListeners() []Listener {
+	listeners := make([]Listener, 0, len(c.Listeners)+1)
+	listeners = append(listeners, Listener{ListenAddress: c.ListenAddress, TLS: c.TLS})
+	return append(listeners, c.Listeners...)
+}
+
+func (c *Config) validateListeners() error {
+	seen := make(map[string]struct{})
+	for _, l := range c.HTTPListeners() {
+		if _, _, err := net.SplitHostPort(l.ListenAddress); err != nil {
+			return fmt.Errorf("%w: address '%s': %s", ErrBadListener, l.ListenAddress, err)
+		}
+		if _, ok := seen[l.ListenAddress]; ok {
+			return fmt.Errorf("%w: address '%s' used more than once", ErrBadListener, l.ListenAddress)
+		}
+		seen[l.ListenAddress] = struct{}{}
+		if l.TLS.Enabled && !l.TLS.ACME.Enabled && len(l.TLS.Certificates) == 0 && (l.TLS.CertFile == "" || l.TLS.KeyFile == "") {
+			return fmt.Errorf("%w: address '%s': tls requires cert_file and key_file", ErrBadListener, l.ListenAddress)
+		}
+		if (l.TLS.CertFile == "") != (l.TLS.KeyFile == "") {
+			return fmt.Errorf("%w: address '%s': tls requires both cert_file and key_file", ErrBadListener, l.ListenAddress)
+		}
+		for _, cert := range l.TLS.Certificates {
+			if cert.CertFile == "" || cert.KeyFile == "" {
+				return fmt.Errorf("%w: address '%s': certificates require cert_file and key_file", ErrBadListener, l.ListenAddress)
+			}
+		}
+		if len(l.TLS.Certificates) > 0 && (!l.TLS.Enabled || l.TLS.ACME.Enabled) {
+			return fmt.Errorf("%w: address '%s': certificates require tls without acme", ErrBadListener, l.ListenAddress)
+		}
+		if l.TLS.ACME.Enabled && !l.TLS.Enabled {
+			return fmt.Errorf("%w: address '%s': acme requires tls", ErrBadListener, l.ListenAddress)
+		}
+		if l.TLS.ACME.Enabled && len(l.TLS.ACME.Domains) == 0 && len(c.Gateways.S3.DomainNames) == 0 {
+			return fmt.Errorf("%w: address '%s': acme requires domains", ErrBadListener, l.ListenAddress)
+		}
+		if !l.TLS.Enabled && l.TLS.ClientCAFile != "" {
+			return fmt.Errorf("%w: address '%s': client_ca_file requires tls", ErrBadListener, l.ListenAddress)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateUsageAttribution() error {
+	attribution := c.UsageReport.Attribution
+	if !attribution.Enabled {
+		return nil
+	}
+	if attribution.Location == "" {
+		return fmt.Errorf("%w: location is required", ErrBadUsageAttribution)
+	}
+	if attribution.Interval < time.Hour {
+		return fmt.Errorf("%w: interval must be at least an hour", ErrBadUsageAttribution)
+	}
+	return nil
+}
+
+func (c *Config) validateBackgroundPriorities() error {
+	for kind, priority := range c.Graveler.Background.JobPriorities {
+		switch priority {
+		case "high", "normal", "low":
+		default:
+			return fmt.Errorf("%w: job '%s' priority '%s' must be high, normal or low", ErrBadBackgroundPriority, kind, priority)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateObjectAccess() error {
+	objectAccess := c.ObjectAccess
+	if !objectAccess.Enabled {
+		if objectAccess.ColdData.Enabled {
+			return fmt.Errorf("%w: cold data reports require object access tracking", ErrBadObjectAccess)
+		}
+		return nil
+	}
+	if objectAccess.SampleRate <= 0 || objectAccess.SampleRate > 1 {
+		return fmt.Errorf("%w: sample rate must be greater than 0 and at most 1", ErrBadObjectAccess)
+	}
+	if objectAccess.FlushInterval <= 0 {
+		return fmt.Errorf("%w: flush interval must be positive", ErrBadObjectAccess)
+	}
+	if objectAccess.MaxPendingRecords <= 0 {
+		return fmt.Errorf("%w: max pending records must be positive", ErrBadObjectAccess)
+	}
+	coldData := objectAccess.ColdData
+	if !coldData.Enabled {
+		return nil
+	}
+	if coldData.Interval <= 0 || coldData.InactiveFor <= 0 {
+		return fmt.Errorf("%w: cold data interval and inactive period must be positive", ErrBadObjectAccess)
+	}
+	if coldData.PrefixDepth < 0 {
+		return fmt.Errorf("%w: cold data prefix depth must not be negative", ErrBadObjectAccess)
+	}
+	return nil
+}
+
+func (c *Config) validateOpenLineage() error {
+	if !c.OpenLineage.Enabled {
+		return nil
+	}
+	u, err := url.Parse(c.OpenLineage.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an http or https URL", ErrBadOpenLineage)
+	}
+	if c.OpenLineage.DatasetPrefixDepth < 0 {
+		return fmt.Errorf("%w: dataset prefix depth must not be negative", ErrBadOpenLineage)
+	}
+	if c.OpenLineage.Timeout <= 0 {
+		return fmt.Errorf("%w: timeout must be positive", ErrBadOpenLineage)
+	}
+	return nil
+}
+
+func (c *Config) validateAnonymousRead() error {
+	for _, rule := range c.Auth.AnonymousRead {
+		if rule.Repository == "" {
+			return fmt.Errorf("%w: anonymous read rule requires repository", ErrBadConfiguration)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateFaultInjection() error {
+	if err := c.FaultInjection.Blockstore.Params().Validate(); err != nil {
+		return fmt.Errorf("%w: blockstore fault injection: %s", ErrBadConfiguration, err)
+	}
+	if err := c.FaultInjection.Database.Params().Validate(); err != nil {
+		return fmt.Errorf("%w: database fault injection: %s", ErrBadConfiguration, err)
+	}
+	return nil
+}
+
+func (c *Config) validateStagingCompaction() error {
+	compaction := c.Graveler.StagingCompaction
+	if !compaction.Enabled {
+		return nil
+	}
+	if compaction.Interval <= 0 {
+		return fmt.Errorf("%w: staging compaction interval must be positive", ErrBadConfiguration)
+	}
+	if compaction.MinStagedEntries <= 0 {
+		return fmt.Errorf("%w: staging compaction min staged entries must be positive", ErrBadConfiguration)
+	}
+	return nil
+}
+
+func (c *Config) validateSSTable() error {
+	sst := c.Committed.SSTable
+	if sst.FormatVersion != 1 && sst.FormatVersion != 2 {
+		return fmt.Errorf("%w: unknown sstable format version %d", ErrBadConfiguration, sst.FormatVersion)
+	}
+	if sst.BloomFilterBitsPerKey < 0 {
+		return fmt.Errorf("%w: sstable bloom filter bits per key must not be negative", ErrBadConfiguration)
+	}
+	return nil
+}
+
 func (c *Config) Validate() error {
 	missingKeys := ValidateMissingRequiredKeys(c, "mapstructure", "squash")
 	if len(missingKeys) > 0 {
@@ -490,9 +1056,36 @@ func (c *Config) BlockstoreS3Params() (blockparams.S3, error) {
 		ClientLogRetries:              c.Blockstore.S3.ClientLogRetries,
 		ClientLogRequest:              c.Blockstore.S3.ClientLogRequest,
 		WebIdentity:                   webIdentity,
+		Retry:                         c.Blockstore.S3.Retry.Params(),
+		OperationTimeout:              c.Blockstore.S3.OperationTimeout,
 	}, nil
 }
 
+// BlockstoreFaultInjectionParams returns the faults injected into calls to the blockstore
+func (c *Config) BlockstoreFaultInjectionParams() faultinject.Params {
+	return c.FaultInjection.Blockstore.Params()
+}
+
+// BlockstoreResilienceParams returns the protections of calls to the configured blockstore
+func (c *Config) BlockstoreMetricsParams() blockparams.Metrics {
+	return blockparams.Metrics{
+		RepositoryLabelsLimit: c.Blockstore.Metrics.RepositoryLabelsLimit,
+	}
+}
+
+func (c *Config) BlockstoreResilienceParams() blockparams.Resilience {
+	switch {
+	case c.Blockstore.S3 != nil && c.Blockstore.Type == "s3":
+		return c.Blockstore.S3.Resilience.Params()
+	case c.Blockstore.GS != nil && c.Blockstore.Type == "gs":
+		return c.Blockstore.GS.Resilience.Params()
+	case c.Blockstore.Azure != nil && c.Blockstore.Type == "azure":
+		return c.Blockstore.Azure.Resilience.Params()
+	default:
+		return blockparams.Resilience{}
+	}
+}
+
 func (c *Config) BlockstoreLocalParams() (blockparams.Local, error) {
 	localPath := c.Blockstore.Local.Path
 	path, err := homedir.Expand(localPath)
@@ -537,6 +1130,8 @@ func (c *Config) BlockstoreGSParams() (blockparams.GS, error) {
 		DisablePreSignedUI:                   c.Blockstore.GS.DisablePreSignedUI,
 		ServerSideEncryptionCustomerSupplied: customerSuppliedKey,
 		ServerSideEncryptionKmsKeyID:         c.Blockstore.GS.ServerSideEncryptionKmsKeyID,
+		Retry:                                c.Blockstore.GS.Retry.Params(),
+		OperationTimeout:                     c.Blockstore.GS.OperationTimeout,
 	}, nil
 }
 
@@ -557,9 +1152,28 @@ func (c *Config) BlockstoreAzureParams() (blockparams.Azure, error) {
 		Domain:             c.Blockstore.Azure.Domain,
 		DisablePreSigned:   c.Blockstore.Azure.DisablePreSigned,
 		DisablePreSignedUI: c.Blockstore.Azure.DisablePreSignedUI,
+		Retry:              c.Blockstore.Azure.Retry.Params(),
+		OperationTimeout:   c.Blockstore.Azure.OperationTimeout,
 	}, nil
 }
 
+const (
+	EncryptionAlgorithmGCPKMS = "gcp:kms"
+)
+
+// BlockstoreEncryption returns the server side encryption algorithm and KMS key id the block adapter applies
+// on data it writes, based on the blockstore configuration.
+func (c *Config) BlockstoreEncryption() (string, string) {
+	switch {
+	case c.Blockstore.S3 != nil && c.Blockstore.Type == "s3":
+		return c.Blockstore.S3.ServerSideEncryption, c.Blockstore.S3.ServerSideEncryptionKmsKeyID
+	case c.Blockstore.GS != nil && c.Blockstore.Type == "gs" && c.Blockstore.GS.ServerSideEncryptionKmsKeyID != "":
+		return EncryptionAlgorithmGCPKMS, c.Blockstore.GS.ServerSideEncryptionKmsKeyID
+	default:
+		return "", ""
+	}
+}
+
 const (
 	AuthRBACSimplified = "simplified"
 	AuthRBACExternal   = "external"
@@ -46,6 +46,21 @@ func TestConfig_Setup(t *testing.T) {
 	}
 }
 
+func TestConfig_Dev(t *testing.T) {
+	c, err := config.NewConfig(config.DevConfiguration)
+	testutil.Must(t, err)
+	if c.Database.Type != "local" || c.Blockstore.Type != "local" {
+		t.Errorf("dev config database %s and blockstore %s, expected local", c.Database.Type, c.Blockstore.Type)
+	}
+	if c.Installation.UserName != config.DefaultQuickstartUsername || c.Installation.SampleRepository != config.DefaultDevSampleRepository {
+		t.Errorf("dev config installation user %s and sample repository %s, expected %s and %s",
+			c.Installation.UserName, c.Installation.SampleRepository, config.DefaultQuickstartUsername, config.DefaultDevSampleRepository)
+	}
+	if c.Stats.Enabled || c.Security.CheckLatestVersion || c.Security.AuditCheckInterval != 0 {
+		t.Error("dev config calls external services")
+	}
+}
+
 func TestConfig_NewFromFile(t *testing.T) {
 	t.Run("valid config", func(t *testing.T) {
 		c, err := newConfigFromFile("testdata/valid_config.yaml")
@@ -107,6 +122,83 @@ func TestConfig_DomainNamePrefix(t *testing.T) {
 	}
 }
 
+func TestConfig_Listeners(t *testing.T) {
+	c, err := newConfigFromFile("testdata/listeners.yaml")
+	testutil.Must(t, err)
+	listeners := c.HTTPListeners()
+	expected := []config.Listener{
+		{ListenAddress: "0.0.0.0:8005"},
+		{ListenAddress: "[::1]:8006"},
+		{
+			ListenAddress: "10.0.0.1:8443",
+			TLS: config.TLS{
+				Enabled:      true,
+				CertFile:     "/etc/lakefs/internal.crt",
+				KeyFile:      "/etc/lakefs/internal.key",
+				ClientCAFile: "/etc/lakefs/clients-ca.crt",
+			},
+		},
+		{
+			ListenAddress: "0.0.0.0:443",
+			TLS: config.TLS{
+				Enabled: true,
+				Certificates: []config.TLSCertificate{
+					{
+						Domains:  []string{"data.internal", "*.data.internal"},
+						CertFile: "/etc/lakefs/data-internal.crt",
+						KeyFile:  "/etc/lakefs/data-internal.key",
+					},
+					{
+						CertFile: "/etc/lakefs/lake-example-com.crt",
+						KeyFile:  "/etc/lakefs/lake-example-com.key",
+					},
+				},
+			},
+		},
+	}
+	if diffs := deep.Equal(listeners, expected); diffs != nil {
+		t.Fatalf("unexpected listeners, diffs %s", diffs)
+	}
+
+	for _, filename := range []string{"testdata/bad_listener.yaml", "testdata/bad_client_ca.yaml", "testdata/bad_sni_certificate.yaml"} {
+		_, err = newConfigFromFile(filename)
+		if !errors.Is(err, config.ErrBadListener) {
+			t.Errorf("%s: got error %s not %s", filename, err, config.ErrBadListener)
+		}
+	}
+}
+
+func TestACME_AllowsHost(t *testing.T) {
+	gatewayDomainNames := []string{"s3.example.com"}
+	tests := []struct {
+		name              string
+		host              string
+		gatewaySubdomains bool
+		expected          bool
+	}{
+		{name: "domain", host: "lakefs.example.com", expected: true},
+		{name: "domain case", host: "LakeFS.Example.com", expected: true},
+		{name: "gateway domain", host: "s3.example.com", expected: true},
+		{name: "bucket subdomain disabled", host: "repo.s3.example.com", expected: false},
+		{name: "bucket subdomain", host: "repo.s3.example.com", gatewaySubdomains: true, expected: true},
+		{name: "nested subdomain", host: "a.repo.s3.example.com", gatewaySubdomains: true, expected: false},
+		{name: "other domain", host: "evil.example.com", gatewaySubdomains: true, expected: false},
+		{name: "suffix without dot", host: "mys3.example.com", gatewaySubdomains: true, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acme := config.ACME{
+				Enabled:           true,
+				Domains:           []string{"lakefs.example.com"},
+				GatewaySubdomains: tt.gatewaySubdomains,
+			}
+			if got := acme.AllowsHost(tt.host, gatewayDomainNames); got != tt.expected {
+				t.Errorf("AllowsHost(%s) = %t, expected %t", tt.host, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestConfig_BuildBlockAdapter(t *testing.T) {
 	ctx := context.Background()
 	t.Run("local block adapter", func(t *testing.T) {
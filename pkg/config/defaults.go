@@ -17,6 +17,9 @@ const (
 	DefaultAuthAPIHealthCheckTimeout = 20 * time.Second
 	DefaultAuthSecret                = "THIS_MUST_BE_CHANGED_IN_PRODUCTION"   // #nosec
 	DefaultSigningSecretKey          = "OVERRIDE_THIS_SIGNING_SECRET_DEFAULT" // #nosec
+	DefaultACMECacheDir              = "~/lakefs/data/acme"
+	DefaultDevDataDir                = "~/lakefs/dev"
+	DefaultDevSampleRepository       = "quickstart"
 )
 
 //nolint:mnd
@@ -38,6 +41,10 @@ func setDefaults(cfgType string) {
 
 	viper.SetDefault("blockstore.signing.secret_key", DefaultSigningSecretKey)
 	viper.SetDefault("listen_address", DefaultListenAddress)
+	viper.SetDefault("tls.acme.cache_dir", DefaultACMECacheDir)
+
+	viper.SetDefault("health.probe_interval", 10*time.Second)
+	viper.SetDefault("health.probe_timeout", 5*time.Second)
 
 	viper.SetDefault("logging.format", "text")
 	viper.SetDefault("logging.level", DefaultLoggingLevel)
@@ -47,10 +54,21 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("logging.audit_log_level", DefaultLoggingAuditLogLevel)
 
 	viper.SetDefault("logging.file_max_size_mb", (1<<10)*100) // 100MiB
+	viper.SetDefault("logging.sampling.enabled", false)
+	viper.SetDefault("logging.sampling.initial", 100)
+	viper.SetDefault("logging.sampling.thereafter", 100)
+	viper.SetDefault("logging.sampling.interval", time.Second)
+
+	viper.SetDefault("access_log.enabled", false)
+	viper.SetDefault("access_log.format", "json")
+	viper.SetDefault("access_log.output", "-")
+	viper.SetDefault("access_log.file_max_size_mb", 100)
+	viper.SetDefault("access_log.s3.flush_interval", time.Minute)
 
 	viper.SetDefault("actions.enabled", true)
 	viper.SetDefault("actions.env.enabled", true)
 	viper.SetDefault("actions.env.prefix", "LAKEFSACTION_")
+	viper.SetDefault("actions.central.branch", "main")
 
 	viper.SetDefault("auth.cache.enabled", true)
 	viper.SetDefault("auth.cache.size", 1024)
@@ -72,6 +90,7 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("auth.oidc.persist_friendly_name", false)
 	viper.SetDefault("auth.cookie_auth_verification.persist_friendly_name", false)
 
+	viper.SetDefault("blockstore.metrics.repository_labels_limit", 500)
 	viper.SetDefault("blockstore.local.path", "~/lakefs/data/block")
 	viper.SetDefault("blockstore.s3.region", "us-east-1")
 	viper.SetDefault("blockstore.s3.max_retries", 5)
@@ -79,6 +98,8 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("blockstore.s3.pre_signed_expiry", 15*time.Minute)
 	viper.SetDefault("blockstore.s3.web_identity.session_expiry_window", 5*time.Minute)
 	viper.SetDefault("blockstore.s3.disable_pre_signed_ui", true)
+	viper.SetDefault("blockstore.s3.resilience.circuit_breaker.open_duration", 30*time.Second)
+	viper.SetDefault("blockstore.s3.resilience.hedged_reads.min_delay", 100*time.Millisecond)
 
 	viper.SetDefault("committed.local_cache.size_bytes", 1*1024*1024*1024)
 	viper.SetDefault("committed.local_cache.dir", "~/lakefs/data/cache")
@@ -91,14 +112,35 @@ func setDefaults(cfgType string) {
 	viper.SetDefault("committed.permanent.max_range_size_bytes", 20*1024*1024)
 	viper.SetDefault("committed.permanent.range_raggedness_entries", 50_000)
 	viper.SetDefault("committed.sstable.memory.cache_size_bytes", 400_000_000)
+	viper.SetDefault("committed.sstable.format_version", 1)
+	viper.SetDefault("committed.sstable.bloom_filter_bits_per_key", 10)
+	viper.SetDefault("committed.key_filter.cache_size", 100)
+	viper.SetDefault("committed.range_prefetch.ranges", 4)
+	viper.SetDefault("committed.range_prefetch.concurrency", 32)
 
 	viper.SetDefault("gateways.s3.domain_name", "s3.local.lakefs.io")
 	viper.SetDefault("gateways.s3.region", "us-east-1")
 	viper.SetDefault("gateways.s3.verify_unsupported", true)
+	viper.SetDefault("gateways.s3.denials_log_size", 100)
+	viper.SetDefault("gateways.nfs.enabled", false)
+	viper.SetDefault("gateways.nfs.listen_address", "127.0.0.1:2049")
+	viper.SetDefault("gateways.webdav.enabled", false)
+	viper.SetDefault("gateways.webdav.writable", false)
+	viper.SetDefault("gateways.website.enabled", false)
+	viper.SetDefault("gateways.website.index_document", "index.html")
+
+	viper.SetDefault("graphql.enabled", false)
+
+	viper.SetDefault("grpc.enabled", false)
+	viper.SetDefault("grpc.listen_address", "0.0.0.0:8001")
+
+	viper.SetDefault("iceberg.enabled", false)
 
 	viper.SetDefault("blockstore.gs.s3_endpoint", "https://storage.googleapis.com")
 	viper.SetDefault("blockstore.gs.pre_signed_expiry", 15*time.Minute)
 	viper.SetDefault("blockstore.gs.disable_pre_signed_ui", true)
+	viper.SetDefault("blockstore.gs.resilience.circuit_breaker.open_duration", 30*time.Second)
+	viper.SetDefault("blockstore.gs.resilience.hedged_reads.min_delay", 100*time.Millisecond)
 
 	viper.SetDefault("stats.enabled", true)
 	viper.SetDefault("stats.address", "https://stats.lakefs.io")
@@ -107,9 +149,21 @@ func setDefaults(cfgType string) {
 
 	viper.SetDefault("email_subscription.enabled", true)
 
+	viper.SetDefault("notifications.enabled", false)
+	viper.SetDefault("notifications.delivery_timeout", 30*time.Second)
+	viper.SetDefault("notifications.smtp.port", 587)
+	viper.SetDefault("notifications.slack.allowed_endpoints", []string{"https://hooks.slack.com/"})
+
+	viper.SetDefault("openlineage.enabled", false)
+	viper.SetDefault("openlineage.namespace", "lakefs")
+	viper.SetDefault("openlineage.dataset_prefix_depth", 0)
+	viper.SetDefault("openlineage.timeout", 10*time.Second)
+
 	viper.SetDefault("blockstore.azure.try_timeout", 10*time.Minute)
 	viper.SetDefault("blockstore.azure.pre_signed_expiry", 15*time.Minute)
 	viper.SetDefault("blockstore.azure.disable_pre_signed_ui", true)
+	viper.SetDefault("blockstore.azure.resilience.circuit_breaker.open_duration", 30*time.Second)
+	viper.SetDefault("blockstore.azure.resilience.hedged_reads.min_delay", 100*time.Millisecond)
 
 	viper.SetDefault("security.audit_check_interval", 24*time.Hour)
 	viper.SetDefault("security.audit_check_url", "https://audit.lakefs.io/audit")
@@ -147,8 +201,53 @@ func setDefaults(cfgType string) {
 	// 3ms of delay with ~300 requests/second per resource sounds like a reasonable tradeoff.
 	viper.SetDefault("graveler.max_batch_delay", 3*time.Millisecond)
 
+	viper.SetDefault("graveler.staging_compaction.enabled", false)
+	viper.SetDefault("graveler.staging_compaction.interval", 10*time.Minute)
+	viper.SetDefault("graveler.staging_compaction.min_staged_entries", 100_000)
+	viper.SetDefault("graveler.repository_archive.grace_period", 7*24*time.Hour)
+	viper.SetDefault("graveler.branch_history.retention", 90*24*time.Hour)
+	viper.SetDefault("graveler.branch_history.integrity.notarization_interval", time.Hour)
+	viper.SetDefault("graveler.ref_trash.retention", 7*24*time.Hour)
+	viper.SetDefault("graveler.merge.async_threshold", 20*time.Second)
+	viper.SetDefault("graveler.background.priorities.low.max_concurrency", 2)
+
 	viper.SetDefault("ugc.prepare_interval", time.Minute)
 	viper.SetDefault("ugc.prepare_max_file_size", 20*1024*1024)
 
 	viper.SetDefault("usage_report.flush_interval", 5*time.Minute)
+	viper.SetDefault("usage_report.attribution.interval", 24*time.Hour)
+	viper.SetDefault("usage_report.attribution.branch_prefix_delimiter", "-")
+
+	viper.SetDefault("object_access.sample_rate", 1.0)
+	viper.SetDefault("object_access.flush_interval", time.Minute)
+	viper.SetDefault("object_access.max_pending_records", 100_000)
+	viper.SetDefault("object_access.cold_data.interval", 24*time.Hour)
+	viper.SetDefault("object_access.cold_data.inactive_for", 30*24*time.Hour)
+	viper.SetDefault("object_access.cold_data.prefix_depth", 1)
+
+	if cfgType == DevConfiguration {
+		setDevDefaults()
+	}
+}
+
+// setDevDefaults sets the defaults of the dev configuration, overriding the defaults set for every configuration:
+// an embedded KV store and blockstore kept under DefaultDevDataDir, a seeded admin user and a sample repository,
+// and no calls to external services
+func setDevDefaults() {
+	viper.SetDefault("installation.user_name", DefaultQuickstartUsername)
+	viper.SetDefault("installation.access_key_id", DefaultQuickstartKeyID)
+	viper.SetDefault("installation.secret_access_key", DefaultQuickstartSecretKey)
+	viper.SetDefault("installation.sample_repository", DefaultDevSampleRepository)
+	viper.SetDefault("auth.encrypt.secret_key", DefaultAuthSecret)
+
+	viper.SetDefault("database.type", "local")
+	viper.SetDefault("database.local.path", DefaultDevDataDir+"/metadata")
+	viper.SetDefault(BlockstoreTypeKey, "local")
+	viper.SetDefault("blockstore.local.path", DefaultDevDataDir+"/block")
+	viper.SetDefault("committed.local_cache.dir", DefaultDevDataDir+"/cache")
+
+	viper.SetDefault("stats.enabled", false)
+	viper.SetDefault("email_subscription.enabled", false)
+	viper.SetDefault("security.audit_check_interval", 0)
+	viper.SetDefault("security.check_latest_version", false)
 }
@@ -0,0 +1,162 @@
+// Package faultinject injects latency, errors and partial failures into calls to external dependencies, so tests
+// can verify retry and consistency behavior under failure. It should never be enabled in production.
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInjected      = errors.New("injected fault")
+	ErrInvalidParams = errors.New("invalid fault injection params")
+)
+
+// Fault is the fault injected into a call
+type Fault int
+
+const (
+	// FaultNone lets the call through
+	FaultNone Fault = iota
+	// FaultError fails the call without performing it
+	FaultError
+	// FaultPartial performs the call but fails it: writes are applied and then reported as failed, reads fail
+	// midway
+	FaultPartial
+)
+
+// Params configures the faults injected. Each probability is of a single call, between 0 and 1.
+type Params struct {
+	ErrorProbability          float64
+	PartialFailureProbability float64
+	LatencyProbability        float64
+	// Latency is added to calls picked by LatencyProbability, before any other fault
+	Latency time.Duration
+	// Operations limits injection to the named operations, all operations if empty
+	Operations []string
+	// Seed seeds the random faults, for reproducible runs. A zero seed picks a random seed.
+	Seed int64
+}
+
+// Enabled returns true if any fault is injected
+func (p Params) Enabled() bool {
+	return p.ErrorProbability > 0 || p.PartialFailureProbability > 0 || (p.LatencyProbability > 0 && p.Latency > 0)
+}
+
+func (p Params) Validate() error {
+	for name, probability := range map[string]float64{
+		"error probability":           p.ErrorProbability,
+		"partial failure probability": p.PartialFailureProbability,
+		"latency probability":         p.LatencyProbability,
+	} {
+		if probability < 0 || probability > 1 {
+			return fmt.Errorf("%w: %s %g is not between 0 and 1", ErrInvalidParams, name, probability)
+		}
+	}
+	if p.ErrorProbability+p.PartialFailureProbability > 1 {
+		return fmt.Errorf("%w: error and partial failure probabilities sum to more than 1", ErrInvalidParams)
+	}
+	if p.Latency < 0 {
+		return fmt.Errorf("%w: negative latency", ErrInvalidParams)
+	}
+	return nil
+}
+
+// Injector picks the faults injected into calls by Params
+type Injector struct {
+	params     Params
+	operations map[string]struct{}
+	mu         sync.Mutex
+	rand       *rand.Rand
+}
+
+func NewInjector(params Params) (*Injector, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	seed := params.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	i := &Injector{
+		params: params,
+		//nolint:gosec
+		rand: rand.New(rand.NewSource(seed)),
+	}
+	if len(params.Operations) > 0 {
+		i.operations = make(map[string]struct{}, len(params.Operations))
+		for _, op := range params.Operations {
+			i.operations[op] = struct{}{}
+		}
+	}
+	return i, nil
+}
+
+func (i *Injector) float64() float64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64()
+}
+
+// Inject delays a call to operation op if picked for latency, and returns the fault to inject into it. A call whose
+// context is canceled while delayed fails.
+func (i *Injector) Inject(ctx context.Context, op string) Fault {
+	if i.operations != nil {
+		if _, ok := i.operations[op]; !ok {
+			return FaultNone
+		}
+	}
+	if i.params.Latency > 0 && i.float64() < i.params.LatencyProbability {
+		timer := time.NewTimer(i.params.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return FaultError
+		case <-timer.C:
+		}
+	}
+	n := i.float64()
+	switch {
+	case n < i.params.ErrorProbability:
+		return FaultError
+	case n < i.params.ErrorProbability+i.params.PartialFailureProbability:
+		return FaultPartial
+	default:
+		return FaultNone
+	}
+}
+
+// Error returns the error of a fault injected into operation op
+func Error(op string) error {
+	return fmt.Errorf("%s: %w", op, ErrInjected)
+}
+
+// partialReadCloser fails after the first read from the underlying reader
+type partialReadCloser struct {
+	io.ReadCloser
+	op   string
+	read bool
+}
+
+// NewPartialReadCloser returns a reader of rc that fails after its first read, as a call failing midway
+func NewPartialReadCloser(rc io.ReadCloser, op string) io.ReadCloser {
+	return &partialReadCloser{ReadCloser: rc, op: op}
+}
+
+func (r *partialReadCloser) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, Error(r.op)
+	}
+	r.read = true
+	n, err := r.ReadCloser.Read(p)
+	if err == nil || errors.Is(err, io.EOF) {
+		// fail even if the whole object was read, so callers see the failure
+		err = Error(r.op)
+	}
+	return n, err
+}
@@ -0,0 +1,101 @@
+package faultinject_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/faultinject"
+)
+
+func TestParams_Validate(t *testing.T) {
+	tbl := []struct {
+		Name   string
+		Params faultinject.Params
+		Valid  bool
+	}{
+		{Name: "empty", Params: faultinject.Params{}, Valid: true},
+		{Name: "all", Params: faultinject.Params{ErrorProbability: 0.5, PartialFailureProbability: 0.5, LatencyProbability: 1, Latency: time.Second}, Valid: true},
+		{Name: "negative", Params: faultinject.Params{ErrorProbability: -0.1}},
+		{Name: "above_one", Params: faultinject.Params{LatencyProbability: 1.5}},
+		{Name: "sum_above_one", Params: faultinject.Params{ErrorProbability: 0.6, PartialFailureProbability: 0.6}},
+		{Name: "negative_latency", Params: faultinject.Params{Latency: -time.Second}},
+	}
+	for _, tt := range tbl {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := tt.Params.Validate()
+			if tt.Valid && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !tt.Valid && !errors.Is(err, faultinject.ErrInvalidParams) {
+				t.Fatalf("expected ErrInvalidParams, got %v", err)
+			}
+		})
+	}
+}
+
+func TestInjector_Inject(t *testing.T) {
+	ctx := context.Background()
+	tbl := []struct {
+		Name     string
+		Params   faultinject.Params
+		Op       string
+		Expected faultinject.Fault
+	}{
+		{Name: "none", Params: faultinject.Params{}, Op: "Get", Expected: faultinject.FaultNone},
+		{Name: "error", Params: faultinject.Params{ErrorProbability: 1}, Op: "Get", Expected: faultinject.FaultError},
+		{Name: "partial", Params: faultinject.Params{PartialFailureProbability: 1}, Op: "Get", Expected: faultinject.FaultPartial},
+		{Name: "operation", Params: faultinject.Params{ErrorProbability: 1, Operations: []string{"Put"}}, Op: "Put", Expected: faultinject.FaultError},
+		{Name: "other_operation", Params: faultinject.Params{ErrorProbability: 1, Operations: []string{"Put"}}, Op: "Get", Expected: faultinject.FaultNone},
+	}
+	for _, tt := range tbl {
+		t.Run(tt.Name, func(t *testing.T) {
+			injector, err := faultinject.NewInjector(tt.Params)
+			if err != nil {
+				t.Fatalf("new injector: %s", err)
+			}
+			for i := 0; i < 10; i++ {
+				if fault := injector.Inject(ctx, tt.Op); fault != tt.Expected {
+					t.Fatalf("expected fault %d, got %d", tt.Expected, fault)
+				}
+			}
+		})
+	}
+}
+
+func TestInjector_InjectLatency(t *testing.T) {
+	const latency = 50 * time.Millisecond
+	injector, err := faultinject.NewInjector(faultinject.Params{LatencyProbability: 1, Latency: latency})
+	if err != nil {
+		t.Fatalf("new injector: %s", err)
+	}
+	start := time.Now()
+	if fault := injector.Inject(context.Background(), "Get"); fault != faultinject.FaultNone {
+		t.Fatalf("expected no fault, got %d", fault)
+	}
+	if took := time.Since(start); took < latency {
+		t.Fatalf("expected latency of at least %s, took %s", latency, took)
+	}
+
+	// a call canceled while delayed fails
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if fault := injector.Inject(ctx, "Get"); fault != faultinject.FaultError {
+		t.Fatalf("expected error fault for canceled call, got %d", fault)
+	}
+}
+
+func TestPartialReadCloser(t *testing.T) {
+	rc := faultinject.NewPartialReadCloser(io.NopCloser(strings.NewReader("hello")), "Get")
+	buf := make([]byte, 2)
+	n, err := rc.Read(buf)
+	if n != 2 || !errors.Is(err, faultinject.ErrInjected) {
+		t.Fatalf("expected 2 bytes and an injected fault, got %d bytes and %v", n, err)
+	}
+	if n, err = rc.Read(buf); n != 0 || !errors.Is(err, faultinject.ErrInjected) {
+		t.Fatalf("expected no bytes and an injected fault, got %d bytes and %v", n, err)
+	}
+}
@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/gateway/operations"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	DenialReasonAuthentication = "authentication"
+	DenialReasonAuthorization  = "authorization"
+	DenialReasonAnonymous      = "anonymous"
+)
+
+// Denial describes a gateway request that was denied access
+type Denial struct {
+	Time        time.Time
+	RequestID   string
+	Reason      string
+	User        string
+	AccessKeyID string
+	Operation   string
+	Method      string
+	Host        string
+	Path        string
+	Repository  string
+	// MissingPermissions are the permissions the user lacks, granting any one of them may not be enough when the
+	// operation requires all of them.
+	MissingPermissions []permissions.Permission
+	Error              string
+}
+
+// DenialLog keeps the most recent denials of gateway requests
+type DenialLog struct {
+	mu      sync.Mutex
+	denials []Denial
+	next    int
+	full    bool
+}
+
+// NewDenialLog returns a log keeping the last size denials. A nil log, returned for size 0, keeps none.
+func NewDenialLog(size int) *DenialLog {
+	if size <= 0 {
+		return nil
+	}
+	return &DenialLog{denials: make([]Denial, size)}
+}
+
+func (l *DenialLog) Add(d Denial) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.denials[l.next] = d
+	l.next = (l.next + 1) % len(l.denials)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent returns up to limit denials, newest first. All kept denials are returned when limit is not positive.
+func (l *DenialLog) Recent(limit int) []Denial {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := l.next
+	if l.full {
+		count = len(l.denials)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	result := make([]Denial, 0, count)
+	for i := 1; i <= count; i++ {
+		result = append(result, l.denials[(l.next-i+len(l.denials))%len(l.denials)])
+	}
+	return result
+}
+
+// missingPermissions returns the permissions of perms that allowed denies. For an "or" node where every alternative
+// is denied, the missing permissions of all alternatives are returned.
+func missingPermissions(perms permissions.Node, allowed func(permissions.Node) bool) []permissions.Permission {
+	switch perms.Type {
+	case permissions.NodeTypeAnd, permissions.NodeTypeOr:
+		var missing []permissions.Permission
+		for _, node := range perms.Nodes {
+			nodeMissing := missingPermissions(node, allowed)
+			if perms.Type == permissions.NodeTypeOr && len(nodeMissing) == 0 {
+				return nil
+			}
+			missing = append(missing, nodeMissing...)
+		}
+		return missing
+	default:
+		if allowed(perms) {
+			return nil
+		}
+		return []permissions.Permission{perms.Permission}
+	}
+}
+
+// userMissingPermissions returns the permissions of perms that username lacks. When none is denied on its own, all
+// the permissions of perms are returned.
+func userMissingPermissions(ctx context.Context, authService auth.GatewayService, username string, perms permissions.Node) []permissions.Permission {
+	missing := missingPermissions(perms, func(node permissions.Node) bool {
+		resp, err := authService.Authorize(ctx, &auth.AuthorizationRequest{
+			Username:            username,
+			RequiredPermissions: node,
+		})
+		return err == nil && resp.Error == nil && resp.Allowed
+	})
+	if len(missing) == 0 {
+		missing = missingPermissions(perms, func(permissions.Node) bool { return false })
+	}
+	return missing
+}
+
+// recordDenial logs the denial of req with the permissions it is missing, and adds it to the log of recent denials.
+// It returns req with the request ID of the denial, for the error response to report the same ID.
+func recordDenial(log *DenialLog, req *http.Request, o *operations.Operation, d Denial) *http.Request {
+	req, d.RequestID = httputil.RequestID(req)
+	ctx := req.Context()
+	d.Time = time.Now()
+	d.Operation = string(o.OperationID)
+	d.Method = req.Method
+	d.Host = req.Host
+	d.Path = req.URL.Path
+	if repoID, ok := ctx.Value(ContextKeyRepositoryID).(string); ok {
+		d.Repository = repoID
+	}
+	fields := logging.Fields{
+		"denial_reason":           d.Reason,
+		"method":                  d.Method,
+		"request_path":            d.Path,
+		logging.RequestIDFieldKey: d.RequestID,
+	}
+	if d.AccessKeyID != "" {
+		fields["key"] = d.AccessKeyID
+	}
+	if d.User != "" {
+		fields[logging.UserFieldKey] = d.User
+	}
+	if len(d.MissingPermissions) > 0 {
+		actions := make([]string, 0, len(d.MissingPermissions))
+		resources := make([]string, 0, len(d.MissingPermissions))
+		for _, p := range d.MissingPermissions {
+			actions = append(actions, p.Action)
+			resources = append(resources, p.Resource)
+		}
+		fields["missing_action"] = actions
+		fields["resource"] = resources
+	}
+	l := o.Log(req).WithFields(fields)
+	if d.Error != "" {
+		l = l.WithField("error", d.Error)
+	}
+	l.Warn("S3 gateway request denied")
+	log.Add(d)
+	return req
+}
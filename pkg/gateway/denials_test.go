@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+func TestDenialLog(t *testing.T) {
+	var disabled *DenialLog
+	disabled.Add(Denial{RequestID: "1"})
+	require.Empty(t, disabled.Recent(10))
+	require.Nil(t, NewDenialLog(0))
+
+	l := NewDenialLog(3)
+	require.Empty(t, l.Recent(0))
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		l.Add(Denial{RequestID: id})
+	}
+	var ids []string
+	for _, d := range l.Recent(0) {
+		ids = append(ids, d.RequestID)
+	}
+	require.Equal(t, []string{"5", "4", "3"}, ids)
+	require.Len(t, l.Recent(2), 2)
+	require.Equal(t, "5", l.Recent(1)[0].RequestID)
+}
+
+func TestMissingPermissions(t *testing.T) {
+	read := permissions.Permission{Action: permissions.ReadObjectAction, Resource: permissions.ObjectArn("repo1", "data/a")}
+	write := permissions.Permission{Action: permissions.WriteObjectAction, Resource: permissions.ObjectArn("repo1", "data/b")}
+	list := permissions.Permission{Action: permissions.ListObjectsAction, Resource: permissions.RepoArn("repo1")}
+	allowed := func(node permissions.Node) bool {
+		return node.Permission == list
+	}
+
+	tests := []struct {
+		name  string
+		perms permissions.Node
+		want  []permissions.Permission
+	}{
+		{
+			name:  "single",
+			perms: permissions.Node{Permission: read},
+			want:  []permissions.Permission{read},
+		},
+		{
+			name:  "allowed",
+			perms: permissions.Node{Permission: list},
+			want:  nil,
+		},
+		{
+			name: "and",
+			perms: permissions.Node{Type: permissions.NodeTypeAnd, Nodes: []permissions.Node{
+				{Permission: list}, {Permission: read}, {Permission: write},
+			}},
+			want: []permissions.Permission{read, write},
+		},
+		{
+			name: "or_denied",
+			perms: permissions.Node{Type: permissions.NodeTypeOr, Nodes: []permissions.Node{
+				{Permission: read}, {Permission: write},
+			}},
+			want: []permissions.Permission{read, write},
+		},
+		{
+			name: "or_allowed",
+			perms: permissions.Node{Type: permissions.NodeTypeOr, Nodes: []permissions.Node{
+				{Permission: read}, {Permission: list},
+			}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, missingPermissions(tt.perms, allowed))
+		})
+	}
+}
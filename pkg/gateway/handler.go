@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/treeverse/lakefs/pkg/accesslog"
 	"github.com/treeverse/lakefs/pkg/auth"
 	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
@@ -57,12 +58,14 @@ type ServerContext struct {
 	multipartTracker  multipart.Tracker
 	blockStore        block.Adapter
 	authService       auth.GatewayService
+	anonymousRead     *auth.AnonymousReadPolicy
 	stats             stats.Collector
 	pathProvider      upload.PathProvider
 	verifyUnsupported bool
+	denials           *DenialLog
 }
 
-func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multipart.Tracker, blockStore block.Adapter, authService auth.GatewayService, bareDomains []string, stats stats.Collector, pathProvider upload.PathProvider, fallbackURL *url.URL, auditLogLevel string, traceRequestHeaders bool, verifyUnsupported bool) http.Handler {
+func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multipart.Tracker, blockStore block.Adapter, authService auth.GatewayService, anonymousRead *auth.AnonymousReadPolicy, bareDomains []string, stats stats.Collector, pathProvider upload.PathProvider, fallbackURL *url.URL, auditLogLevel string, traceRequestHeaders bool, verifyUnsupported bool, denials *DenialLog) http.Handler {
 	var fallbackHandler http.Handler
 	if fallbackURL != nil {
 		fallbackProxy := gohttputil.NewSingleHostReverseProxy(fallbackURL)
@@ -84,9 +87,11 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 		bareDomains:       bareDomains,
 		blockStore:        blockStore,
 		authService:       authService,
+		anonymousRead:     anonymousRead,
 		stats:             stats,
 		pathProvider:      pathProvider,
 		verifyUnsupported: verifyUnsupported,
+		denials:           denials,
 	}
 
 	// setup routes
@@ -118,10 +123,11 @@ func NewHandler(region string, catalog *catalog.Catalog, multipartTracker multip
 
 	h = EnrichWithOperation(sc,
 		DurationHandler(
-			AuthenticationHandler(authService, EnrichWithParts(bareDomains,
+			AuthenticationHandler(authService, anonymousRead, denials, EnrichWithParts(bareDomains,
 				EnrichWithRepositoryOrFallback(catalog, authService, fallbackHandler,
 					OperationLookupHandler(
 						h))))))
+	h = accesslog.Middleware("s3_gateway")(h)
 	logging.ContextUnavailable().WithFields(logging.Fields{
 		"s3_bare_domain": bareDomains,
 		"s3_region":      region,
@@ -160,7 +166,7 @@ func OperationHandler(sc *ServerContext, handler operations.AuthenticatedOperati
 			_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 			return
 		}
-		authOp := authorize(w, req, sc.authService, perms)
+		authOp := authorize(w, req, sc.authService, sc.anonymousRead, sc.denials, perms)
 		if authOp == nil {
 			return
 		}
@@ -179,10 +185,11 @@ func RepoOperationHandler(sc *ServerContext, handler operations.RepoOperationHan
 			_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 			return
 		}
-		authOp := authorize(w, req, sc.authService, perms)
+		authOp := authorize(w, req, sc.authService, sc.anonymousRead, sc.denials, perms)
 		if authOp == nil {
 			return
 		}
+		stats.RecordAttribution(repo.Name, "", authOp.Principal)
 		repoOperation := &operations.RepoOperation{
 			AuthorizedOperation: authOp,
 			Repository:          repo,
@@ -214,11 +221,13 @@ func PathOperationHandler(sc *ServerContext, handler operations.PathOperationHan
 			return
 		}
 
-		authOp := authorize(w, req, sc.authService, perms)
+		authOp := authorize(w, req, sc.authService, sc.anonymousRead, sc.denials, perms)
 		if authOp == nil {
 			return
 		}
 
+		stats.RecordAttribution(repo.Name, refID, authOp.Principal)
+
 		// run callback
 		operation := &operations.PathOperation{
 			RefOperation: &operations.RefOperation{
@@ -241,7 +250,7 @@ func PathOperationHandler(sc *ServerContext, handler operations.PathOperationHan
 	})
 }
 
-func authorize(w http.ResponseWriter, req *http.Request, authService auth.GatewayService, perms permissions.Node) *operations.AuthorizedOperation {
+func authorize(w http.ResponseWriter, req *http.Request, authService auth.GatewayService, anonymousRead *auth.AnonymousReadPolicy, denials *DenialLog, perms permissions.Node) *operations.AuthorizedOperation {
 	ctx := req.Context()
 	o := ctx.Value(ContextKeyOperation).(*operations.Operation)
 	user, err := auth.GetUser(ctx)
@@ -264,6 +273,27 @@ func authorize(w http.ResponseWriter, req *http.Request, authService auth.Gatewa
 		}
 	}
 
+	if auth.IsAnonymous(ctx) {
+		// requests without credentials are allowed by the anonymous read policy only
+		if !anonymousRead.Authorize(ctx, perms) {
+			missing := missingPermissions(perms, func(node permissions.Node) bool {
+				return anonymousRead.Authorize(ctx, node)
+			})
+			req = recordDenial(denials, req, o, Denial{
+				Reason:             DenialReasonAnonymous,
+				User:               username,
+				MissingPermissions: missing,
+			})
+			_ = o.EncodeError(w, req, nil, gatewayerrors.ErrAccessDenied.ToAPIErr())
+			return nil
+		}
+		return &operations.AuthorizedOperation{
+			Operation: o,
+			Principal: username,
+		}
+	}
+
+	perms = permissions.WithActionsConfig(perms)
 	authResp, err := authService.Authorize(req.Context(), &auth.AuthorizationRequest{
 		Username:            username,
 		RequiredPermissions: perms,
@@ -274,11 +304,16 @@ func authorize(w http.ResponseWriter, req *http.Request, authService auth.Gatewa
 		return nil
 	}
 	if authResp.Error != nil || !authResp.Allowed {
-		l := o.Log(req).WithError(authResp.Error)
-		if accessKeyID != "" {
-			l = l.WithField("key", accessKeyID)
+		d := Denial{
+			Reason:             DenialReasonAuthorization,
+			User:               username,
+			AccessKeyID:        accessKeyID,
+			MissingPermissions: userMissingPermissions(ctx, authService, username, perms),
+		}
+		if authResp.Error != nil {
+			d.Error = authResp.Error.Error()
 		}
-		l.Warn("no permission")
+		req = recordDenial(denials, req, o, d)
 		_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 		return nil
 	}
@@ -9,8 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/treeverse/lakefs/pkg/accesslog"
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
 	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/block"
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/gateway/operations"
@@ -23,7 +25,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/stats"
 )
 
-func AuthenticationHandler(authService auth.GatewayService, next http.Handler) http.Handler {
+func AuthenticationHandler(authService auth.GatewayService, anonymousRead *auth.AnonymousReadPolicy, denials *DenialLog, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		user, err := auth.GetUser(ctx)
@@ -33,6 +35,14 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 			next.ServeHTTP(w, req)
 			return
 		}
+		if anonymousRead.Enabled() && !sig.IsAWSSignedRequest(req) {
+			// unsigned requests are authorized by the anonymous read policy
+			ctx = logging.AddFields(ctx, logging.Fields{logging.UserFieldKey: auth.AnonymousUsername})
+			accesslog.SetPrincipal(ctx, auth.AnonymousUsername)
+			req = req.WithContext(auth.WithAnonymousUser(ctx))
+			next.ServeHTTP(w, req)
+			return
+		}
 		o := ctx.Value(ContextKeyOperation).(*operations.Operation)
 		authenticator := sig.ChainedAuthenticator(
 			sig.NewV4Authenticator(req),
@@ -41,7 +51,7 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 		)
 		authContext, err := authenticator.Parse()
 		if err != nil {
-			o.Log(req).WithError(err).Warn("failed to parse signature")
+			req = recordDenial(denials, req, o, Denial{Reason: DenialReasonAuthentication, Error: "failed to parse signature: " + err.Error()})
 			_ = o.EncodeError(w, req, err, getAPIErrOrDefault(err, gatewayerrors.ErrAccessDenied))
 			return
 		}
@@ -53,26 +63,37 @@ func AuthenticationHandler(authService auth.GatewayService, next http.Handler) h
 				logger.WithError(err).Warn("error getting access key")
 				_ = o.EncodeError(w, req, err, gatewayerrors.ErrInternalError.ToAPIErr())
 			} else {
-				logger.WithError(err).Warn("could not find access key")
+				req = recordDenial(denials, req, o, Denial{Reason: DenialReasonAuthentication, AccessKeyID: accessKeyID, Error: "could not find access key"})
 				_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 			}
 			return
 		}
 		err = authenticator.Verify(creds)
 		if err != nil {
-			logger.WithError(err).Warn("error verifying credentials for key")
+			req = recordDenial(denials, req, o, Denial{
+				Reason:      DenialReasonAuthentication,
+				User:        creds.Username,
+				AccessKeyID: accessKeyID,
+				Error:       "error verifying credentials for key: " + err.Error(),
+			})
 			_ = o.EncodeError(w, req, err, getAPIErrOrDefault(err, gatewayerrors.ErrAccessDenied))
 			return
 		}
 
 		user, err = authService.GetUser(ctx, creds.Username)
 		if err != nil {
-			logger.WithError(err).Warn("could not get user for credentials key")
+			req = recordDenial(denials, req, o, Denial{
+				Reason:      DenialReasonAuthentication,
+				User:        creds.Username,
+				AccessKeyID: accessKeyID,
+				Error:       "could not get user for credentials key: " + err.Error(),
+			})
 			_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
 			return
 		}
 		ctx = logging.AddFields(ctx, logging.Fields{logging.UserFieldKey: user.Username})
 		ctx = auth.WithUser(ctx, user)
+		accesslog.SetPrincipal(ctx, user.Username)
 		ctx = context.WithValue(ctx, ContextKeyAuthContext, authContext)
 		req = req.WithContext(ctx)
 		next.ServeHTTP(w, req)
@@ -87,17 +108,30 @@ func EnrichWithParts(bareDomains []string, next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, ContextKeyRef, parts.Ref)
 		ctx = context.WithValue(ctx, ContextKeyPath, parts.Path)
 		ctx = context.WithValue(ctx, ContextKeyMatchedHost, parts.MatchedHost)
+		accesslog.SetResource(ctx, parts.Repository, parts.Ref)
+		if parts.Repository != "" {
+			ctx = block.WithRepository(ctx, parts.Repository)
+		}
 		req = req.WithContext(ctx)
 		next.ServeHTTP(w, req)
 	})
 }
 
 func getBareDomain(hostname string, bareDomains []string) string {
+	var matched string
 	for _, bd := range bareDomains {
-		if hostname == stripPort(bd) || strings.HasSuffix(hostname, "."+stripPort(bd)) {
-			return bd
+		d := stripPort(bd)
+		if !strings.EqualFold(hostname, d) && !hasParentDomain(hostname, d) {
+			continue
+		}
+		// the most specific domain wins, e.g. for bucket.lake.example.com and domains example.com, lake.example.com
+		if len(d) > len(stripPort(matched)) {
+			matched = bd
 		}
 	}
+	if matched != "" {
+		return matched
+	}
 	// If no matching bare domain found, assume no gateways.s3.domain_name setting existing,
 	//  and we're using path-based routing, with whichever domain our Host header specifies.
 	return hostname
@@ -120,6 +154,7 @@ func EnrichWithOperation(sc *ServerContext, next http.Handler) http.Handler {
 			MultipartTracker:  sc.multipartTracker,
 			BlockStore:        sc.blockStore,
 			Auth:              sc.authService,
+			AnonymousRead:     sc.anonymousRead,
 			VerifyUnsupported: sc.verifyUnsupported,
 			Incr: func(action, userID, repository, ref string) {
 				logging.FromContext(ctx).
@@ -171,6 +206,10 @@ func EnrichWithRepositoryOrFallback(c *catalog.Catalog, authService auth.Gateway
 		}
 		repo, err := c.GetRepository(ctx, repoID)
 		if errors.Is(err, graveler.ErrNotFound) {
+			if auth.IsAnonymous(ctx) {
+				_ = o.EncodeError(w, req, err, gatewayerrors.ErrAccessDenied.ToAPIErr())
+				return
+			}
 			authResp, authErr := authService.Authorize(ctx, &auth.AuthorizationRequest{
 				Username: username,
 				RequiredPermissions: permissions.Node{
@@ -240,6 +279,11 @@ func memberFold(a string, bs []string) bool {
 	return false
 }
 
+// hasParentDomain reports whether host is a subdomain of domain
+func hasParentDomain(host, domain string) bool {
+	return len(host) > len(domain)+1 && host[len(host)-len(domain)-1] == '.' && strings.EqualFold(host[len(host)-len(domain):], domain)
+}
+
 type RequestParts struct {
 	Repository  string
 	Ref         string
@@ -266,15 +310,18 @@ func ParseRequestParts(host string, urlPath string, bareDomains []string) Reques
 		}
 		parts.MatchedHost = true
 	} else {
-		// virtual host style: extract repo from subdomain
-		host := httputil.HostOnly(host)
+		// virtual host style: extract repo from subdomain of the most specific domain
+		host := strings.ToLower(httputil.HostOnly(host))
+		var matched string
 		for _, ourHost := range ourHosts {
-			if strings.HasSuffix(host, ourHost) {
-				parts.Repository = strings.TrimSuffix(host, "."+ourHost)
-				parts.MatchedHost = true
-				break
+			if hasParentDomain(host, ourHost) && len(ourHost) > len(matched) {
+				matched = ourHost
 			}
 		}
+		if matched != "" {
+			parts.Repository = host[:len(host)-len(matched)-1]
+			parts.MatchedHost = true
+		}
 		if parts.MatchedHost {
 			p = strings.SplitN(urlPath, path.Separator, 2) //nolint: mnd
 		}
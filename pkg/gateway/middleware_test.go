@@ -225,3 +225,46 @@ func TestParseRequestParts(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequestParts_MultipleDomains(t *testing.T) {
+	bareDomains := []string{"data.internal", "lake.example.com", "s3.lake.example.com:8000"}
+	cases := []struct {
+		Name           string
+		Host           string
+		ExpectedResult gateway.RequestParts
+	}{
+		{
+			Name:           "first_domain",
+			Host:           "repo1.data.internal",
+			ExpectedResult: gateway.RequestParts{Repository: "repo1", Ref: "main", Path: "a/b", MatchedHost: true},
+		},
+		{
+			Name:           "second_domain",
+			Host:           "repo1.lake.example.com:8000",
+			ExpectedResult: gateway.RequestParts{Repository: "repo1", Ref: "main", Path: "a/b", MatchedHost: true},
+		},
+		{
+			Name:           "most_specific_domain",
+			Host:           "repo1.s3.lake.example.com",
+			ExpectedResult: gateway.RequestParts{Repository: "repo1", Ref: "main", Path: "a/b", MatchedHost: true},
+		},
+		{
+			Name:           "case_insensitive",
+			Host:           "Repo1.Data.Internal",
+			ExpectedResult: gateway.RequestParts{Repository: "repo1", Ref: "main", Path: "a/b", MatchedHost: true},
+		},
+		{
+			Name:           "not_a_subdomain",
+			Host:           "repo1.xdata.internal",
+			ExpectedResult: gateway.RequestParts{Repository: "main", Ref: "a", Path: "b", MatchedHost: false},
+		},
+	}
+	for _, cas := range cases {
+		t.Run(cas.Name, func(t *testing.T) {
+			got := gateway.ParseRequestParts(cas.Host, "/main/a/b", bareDomains)
+			if !reflect.DeepEqual(cas.ExpectedResult, got) {
+				t.Errorf("expected parts = %+v for host '%s', got %+v", cas.ExpectedResult, cas.Host, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package nfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewaypath "github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const (
+	listEntriesAmount = 1000
+
+	dirMode  = fs.ModeDir | 0o555
+	fileMode = 0o444
+)
+
+// refsFS is a read-only billy.Filesystem exposing the refs of the exported repositories, laid out as
+// /<repository>/<ref>/<object path>. Only branches are listed under a repository, any other ref can be
+// accessed by name.
+type refsFS struct {
+	ctx          context.Context
+	catalog      *catalog.Catalog
+	repositories map[string]struct{}
+}
+
+var _ billy.Filesystem = (*refsFS)(nil)
+
+func newRefsFS(ctx context.Context, c *catalog.Catalog, repositories []string) *refsFS {
+	exported := make(map[string]struct{}, len(repositories))
+	for _, repo := range repositories {
+		exported[repo] = struct{}{}
+	}
+	return &refsFS{ctx: ctx, catalog: c, repositories: exported}
+}
+
+// fileInfo implements os.FileInfo for repositories, refs, directories and objects
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+func dirInfo(name string, modTime time.Time) *fileInfo {
+	return &fileInfo{name: name, mode: dirMode, modTime: modTime}
+}
+
+func entryInfo(entry *catalog.DBEntry) *fileInfo {
+	if entry.CommonLevel {
+		return dirInfo(path.Base(entry.Path), time.Time{})
+	}
+	return &fileInfo{name: path.Base(entry.Path), size: entry.Size, mode: fileMode, modTime: entry.CreationDate}
+}
+
+// notExist wraps os.ErrNotExist, which the NFS handler reports as NFS3ERR_NOENT
+func notExist(p gatewaypath.FilesystemPath) error {
+	return &os.PathError{Op: "stat", Path: p.String(), Err: os.ErrNotExist}
+}
+
+func (f *refsFS) getRepository(p gatewaypath.FilesystemPath) (*catalog.Repository, error) {
+	if _, ok := f.repositories[p.Repository]; !ok {
+		return nil, notExist(p)
+	}
+	repository, err := f.catalog.GetRepository(f.ctx, p.Repository)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, notExist(p)
+	}
+	return repository, err
+}
+
+// getEntry returns the entry of an object path, or a common level entry if the path is a directory
+func (f *refsFS) getEntry(p gatewaypath.FilesystemPath) (*catalog.DBEntry, error) {
+	entry, err := f.catalog.GetEntry(f.ctx, p.Repository, p.Ref, p.Path, catalog.GetEntryParams{})
+	if err == nil {
+		return entry, nil
+	}
+	if !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+	entries, _, err := f.catalog.ListEntries(f.ctx, p.Repository, p.Ref, p.DirPrefix(), "", "", 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, notExist(p)
+	}
+	return &catalog.DBEntry{CommonLevel: true, Path: p.DirPrefix()}, nil
+}
+
+func (f *refsFS) Stat(filename string) (os.FileInfo, error) {
+	p := gatewaypath.ParseFilesystemPath(filename)
+	if p.IsRoot() {
+		return dirInfo(gatewaypath.Separator, time.Time{}), nil
+	}
+	repository, err := f.getRepository(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsRepository() {
+		return dirInfo(repository.Name, repository.CreationDate), nil
+	}
+	if p.IsRef() {
+		commit, err := f.catalog.GetCommit(f.ctx, p.Repository, p.Ref)
+		if errors.Is(err, graveler.ErrNotFound) || errors.Is(err, graveler.ErrInvalid) {
+			return nil, notExist(p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return dirInfo(p.Ref, commit.CreationDate), nil
+	}
+	entry, err := f.getEntry(p)
+	if err != nil {
+		return nil, err
+	}
+	return entryInfo(entry), nil
+}
+
+func (f *refsFS) Lstat(filename string) (os.FileInfo, error) {
+	return f.Stat(filename)
+}
+
+func (f *refsFS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	p := gatewaypath.ParseFilesystemPath(dirname)
+	if p.IsRoot() {
+		var infos []os.FileInfo
+		for name := range f.repositories {
+			repository, err := f.catalog.GetRepository(f.ctx, name)
+			if errors.Is(err, graveler.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, dirInfo(repository.Name, repository.CreationDate))
+		}
+		return infos, nil
+	}
+	if _, err := f.getRepository(p); err != nil {
+		return nil, err
+	}
+	if p.IsRepository() {
+		return f.readBranches(p)
+	}
+	var (
+		infos []os.FileInfo
+		after string
+	)
+	for {
+		entries, hasMore, err := f.catalog.ListEntries(f.ctx, p.Repository, p.Ref, p.DirPrefix(), after, gatewaypath.Separator, listEntriesAmount)
+		if errors.Is(err, graveler.ErrNotFound) {
+			return nil, notExist(p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			infos = append(infos, entryInfo(entry))
+		}
+		if !hasMore || len(entries) == 0 {
+			return infos, nil
+		}
+		after = entries[len(entries)-1].Path
+	}
+}
+
+func (f *refsFS) readBranches(p gatewaypath.FilesystemPath) ([]os.FileInfo, error) {
+	var (
+		infos []os.FileInfo
+		after string
+	)
+	for {
+		branches, hasMore, err := f.catalog.ListBranches(f.ctx, p.Repository, "", listEntriesAmount, after)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			infos = append(infos, dirInfo(branch.Name, time.Time{}))
+		}
+		if !hasMore || len(branches) == 0 {
+			return infos, nil
+		}
+		after = branches[len(branches)-1].Name
+	}
+}
+
+func (f *refsFS) Open(filename string) (billy.File, error) {
+	p := gatewaypath.ParseFilesystemPath(filename)
+	if p.Path == "" {
+		return nil, fmt.Errorf("open %s: %w", p, os.ErrInvalid)
+	}
+	repository, err := f.getRepository(p)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := f.getEntry(p)
+	if err != nil {
+		return nil, err
+	}
+	if entry.CommonLevel {
+		return nil, fmt.Errorf("open %s: is a directory: %w", p, os.ErrInvalid)
+	}
+	return &objectFile{
+		ctx:     f.ctx,
+		adapter: f.catalog.BlockAdapter,
+		name:    filename,
+		size:    entry.Size,
+		pointer: block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		},
+	}, nil
+}
+
+func (f *refsFS) OpenFile(filename string, flag int, _ os.FileMode) (billy.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		return nil, billy.ErrReadOnly
+	}
+	return f.Open(filename)
+}
+
+func (f *refsFS) Create(string) (billy.File, error) {
+	return nil, billy.ErrReadOnly
+}
+
+func (f *refsFS) Rename(string, string) error {
+	return billy.ErrReadOnly
+}
+
+func (f *refsFS) Remove(string) error {
+	return billy.ErrReadOnly
+}
+
+func (f *refsFS) TempFile(string, string) (billy.File, error) {
+	return nil, billy.ErrReadOnly
+}
+
+func (f *refsFS) MkdirAll(string, os.FileMode) error {
+	return billy.ErrReadOnly
+}
+
+func (f *refsFS) Symlink(string, string) error {
+	return billy.ErrReadOnly
+}
+
+func (f *refsFS) Readlink(link string) (string, error) {
+	return "", fmt.Errorf("readlink %s: %w", link, billy.ErrNotSupported)
+}
+
+func (f *refsFS) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+func (f *refsFS) Chroot(string) (billy.Filesystem, error) {
+	return nil, billy.ErrNotSupported
+}
+
+func (f *refsFS) Root() string {
+	return gatewaypath.Separator
+}
+
+func (f *refsFS) Capabilities() billy.Capability {
+	return billy.ReadCapability | billy.SeekCapability
+}
+
+// objectFile reads an object from the blockstore, by range
+type objectFile struct {
+	ctx     context.Context
+	adapter block.Adapter
+	name    string
+	size    int64
+	pointer block.ObjectPointer
+	offset  int64
+}
+
+var _ billy.File = (*objectFile)(nil)
+
+func (o *objectFile) Name() string {
+	return o.name
+}
+
+func (o *objectFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= o.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p)) - 1
+	if end >= o.size {
+		end = o.size - 1
+	}
+	reader, err := o.adapter.GetRange(o.ctx, o.pointer, off, end)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = reader.Close() }()
+	n, err := io.ReadFull(reader, p[:end-off+1])
+	if err != nil {
+		return n, err
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (o *objectFile) Read(p []byte) (int, error) {
+	n, err := o.ReadAt(p, o.offset)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *objectFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, os.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *objectFile) Write([]byte) (int, error) {
+	return 0, billy.ErrReadOnly
+}
+
+func (o *objectFile) Truncate(int64) error {
+	return billy.ErrReadOnly
+}
+
+func (o *objectFile) Close() error {
+	return nil
+}
+
+func (o *objectFile) Lock() error {
+	return nil
+}
+
+func (o *objectFile) Unlock() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package nfs
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/logging"
+	gonfs "github.com/willscott/go-nfs"
+	nfshelper "github.com/willscott/go-nfs/helpers"
+)
+
+// handleCacheSize is the number of file handles kept by the server, NFS clients address files by handle
+const handleCacheSize = 65536
+
+// Server exports the refs of the configured repositories, read-only, over NFSv3.
+// NFSv3 has no authentication: access is granted to any client that can reach the listen address.
+type Server struct {
+	listener net.Listener
+	handler  gonfs.Handler
+}
+
+func NewServer(ctx context.Context, c *catalog.Catalog, listenAddress string, repositories []string) (*Server, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	handler := nfshelper.NewCachingHandler(nfshelper.NewNullAuthHandler(newRefsFS(ctx, c, repositories)), handleCacheSize)
+	return &Server{listener: listener, handler: handler}, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serve serves NFS requests until the server is closed
+func (s *Server) Serve() error {
+	err := gonfs.Serve(s.listener, s.handler)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// ListenAndServe serves in the background, until ctx is done
+func (s *Server) ListenAndServe(ctx context.Context) {
+	log := logging.FromContext(ctx).WithField("listen_address", s.Addr().String())
+	go func() {
+		<-ctx.Done()
+		_ = s.Close()
+	}()
+	go func() {
+		log.Info("Starting NFS gateway")
+		if err := s.Serve(); err != nil {
+			log.WithError(err).Error("NFS gateway stopped")
+		}
+	}()
+}
+
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
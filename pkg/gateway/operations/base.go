@@ -2,6 +2,7 @@ package operations
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -52,6 +53,7 @@ type Operation struct {
 	MultipartTracker  multipart.Tracker
 	BlockStore        block.Adapter
 	Auth              auth.GatewayService
+	AnonymousRead     *auth.AnonymousReadPolicy
 	Incr              ActionIncr
 	MatchedHost       bool
 	PathProvider      upload.PathProvider
@@ -150,7 +152,7 @@ func (o *Operation) SetHeaders(w http.ResponseWriter, headers http.Header) {
 
 func (o *Operation) EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gwerrors.APIError) *http.Request {
 	err := fallbackError
-	if errors.Is(originalError, kv.ErrSlowDown) {
+	if errors.Is(originalError, kv.ErrSlowDown) || errors.Is(originalError, block.ErrCircuitOpen) {
 		err = gwerrors.ErrSlowDown.ToAPIErr()
 	}
 	req, rid := httputil.RequestID(req)
@@ -180,6 +182,31 @@ type AuthorizedOperation struct {
 	Principal string
 }
 
+// AuthorizeAliasTarget checks that the principal may read the object an alias entry was resolved to. Reading an
+// alias serves the object found at its target, so permission to read the alias path alone is not enough.
+func (o *AuthorizedOperation) AuthorizeAliasTarget(ctx context.Context, repository string, entry *catalog.DBEntry) (bool, error) {
+	if entry.AliasTarget == "" {
+		return true, nil
+	}
+	perms := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, entry.AliasTarget),
+		},
+	}
+	if auth.IsAnonymous(ctx) {
+		return o.AnonymousRead.Authorize(ctx, perms), nil
+	}
+	resp, err := o.Auth.Authorize(ctx, &auth.AuthorizationRequest{
+		Username:            o.Principal,
+		RequiredPermissions: permissions.WithActionsConfig(perms),
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Error == nil && resp.Allowed, nil
+}
+
 type RepoOperation struct {
 	*AuthorizedOperation
 	Repository  *catalog.Repository
@@ -188,7 +215,7 @@ type RepoOperation struct {
 
 func (o *RepoOperation) EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gwerrors.APIError) *http.Request {
 	err := fallbackError
-	if errors.Is(originalError, kv.ErrSlowDown) {
+	if errors.Is(originalError, kv.ErrSlowDown) || errors.Is(originalError, block.ErrCircuitOpen) {
 		err = gwerrors.ErrSlowDown.ToAPIErr()
 	}
 	req, rid := httputil.RequestID(req)
@@ -218,9 +245,26 @@ type PathOperation struct {
 	Path string
 }
 
+// authorizeAliasTarget checks that the principal may read the object entry of repository was resolved to when it is
+// an alias, encoding an access denied error if not
+func authorizeAliasTarget(w http.ResponseWriter, req *http.Request, o *PathOperation, repository string, entry *catalog.DBEntry) bool {
+	allowed, err := o.AuthorizeAliasTarget(req.Context(), repository, entry)
+	if err != nil {
+		o.Log(req).WithError(err).Error("failed to authorize alias target")
+		_ = o.EncodeError(w, req, err, gwerrors.Codes.ToAPIErr(gwerrors.ErrInternalError))
+		return false
+	}
+	if !allowed {
+		o.Log(req).WithField("alias_target", entry.AliasTarget).Warn("no permission to read alias target")
+		_ = o.EncodeError(w, req, nil, gwerrors.Codes.ToAPIErr(gwerrors.ErrAccessDenied))
+		return false
+	}
+	return true
+}
+
 func (o *PathOperation) EncodeError(w http.ResponseWriter, req *http.Request, originalError error, fallbackError gwerrors.APIError) *http.Request {
 	err := fallbackError
-	if errors.Is(originalError, kv.ErrSlowDown) {
+	if errors.Is(originalError, kv.ErrSlowDown) || errors.Is(originalError, block.ErrCircuitOpen) {
 		err = gwerrors.ErrSlowDown.ToAPIErr()
 	}
 	req, rid := httputil.RequestID(req)
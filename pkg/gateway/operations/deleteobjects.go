@@ -71,12 +71,12 @@ func (controller *DeleteObjects) Handle(w http.ResponseWriter, req *http.Request
 		// authorize this object deletion
 		authResp, err := o.Auth.Authorize(req.Context(), &auth.AuthorizationRequest{
 			Username: o.Principal,
-			RequiredPermissions: permissions.Node{
+			RequiredPermissions: permissions.WithActionsConfig(permissions.Node{
 				Permission: permissions.Permission{
 					Action:   permissions.DeleteObjectAction,
 					Resource: permissions.ObjectArn(o.Repository.Name, resolvedPath.Path),
 				},
-			},
+			}),
 		})
 		if err != nil || !authResp.Allowed {
 			errs = append(errs, serde.DeleteError{
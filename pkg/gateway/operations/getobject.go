@@ -18,6 +18,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
 )
 
 const (
@@ -87,6 +88,10 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
 		return
 	}
+	if !authorizeAliasTarget(w, req, o, o.Repository.Name, entry) {
+		return
+	}
+	o.Catalog.RecordObjectAccess(o.Repository.Name, o.Path, o.Principal)
 
 	// TODO: the rest of https://docs.aws.amazon.com/en_pv/AmazonS3/latest/API/API_GetObject.html
 	// range query
@@ -158,6 +163,9 @@ func (controller *GetObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "X-Frame-Options", "SAMEORIGIN")
 	o.SetHeader(w, "Content-Security-Policy", "default-src 'none'")
 	amzMetaWriteHeaders(w, entry.Metadata)
+	if strings.EqualFold(req.Header.Get(ChecksumModeHeader), checksumModeEnabled) {
+		setChecksumHeaders(w, o, upload.ChecksumsFromMetadata(entry.Metadata))
+	}
 	w.WriteHeader(statusCode)
 
 	defer func() {
@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
 )
 
 type HeadObject struct{}
@@ -37,6 +39,9 @@ func (controller *HeadObject) Handle(w http.ResponseWriter, req *http.Request, o
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
 		return
 	}
+	if !authorizeAliasTarget(w, req, o, o.Repository.Name, entry) {
+		return
+	}
 	if entry.Expired {
 		o.Log(req).WithError(err).Info("querying expired object")
 		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
@@ -65,6 +70,9 @@ func (controller *HeadObject) Handle(w http.ResponseWriter, req *http.Request, o
 	o.SetHeader(w, "Content-Type", entry.ContentType)
 
 	amzMetaWriteHeaders(w, entry.Metadata)
+	if strings.EqualFold(req.Header.Get(ChecksumModeHeader), checksumModeEnabled) {
+		setChecksumHeaders(w, o, upload.ChecksumsFromMetadata(entry.Metadata))
+	}
 	if rangeSpec != "" && rngErr == nil {
 		o.SetHeader(w, "Content-Length", fmt.Sprintf("%d", rng.Size()))
 		o.SetHeader(w, "Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
@@ -53,5 +53,6 @@ func (controller *ListBuckets) Handle(w http.ResponseWriter, req *http.Request,
 	// write response
 	o.EncodeResponse(w, req, serde.ListAllMyBucketsResult{
 		Buckets: serde.Buckets{Bucket: buckets},
+		Owner:   *principalOwner(o.Principal),
 	}, http.StatusOK)
 }
@@ -1,10 +1,13 @@
 package operations
 
 import (
+	"encoding/base64"
 	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/treeverse/lakefs/pkg/catalog"
 	gatewayerrors "github.com/treeverse/lakefs/pkg/gateway/errors"
@@ -14,6 +17,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
 )
 
 const (
@@ -21,8 +25,65 @@ const (
 
 	// defaultBucketLocation used to identify if we need to specify the location constraint
 	defaultBucketLocation = "us-east-1"
+
+	// encodingTypeURL is the only encoding-type of listings, names in the response are URL encoded
+	encodingTypeURL = "url"
 )
 
+// s3NameEncoder encodes names as S3 does for encoding-type=url: query escaping that keeps '/' and '*' and escapes '~'
+var s3NameEncoder = strings.NewReplacer("%2F", "/", "%2A", "*", "~", "%7E")
+
+func encodeName(name string, urlEncode bool) string {
+	if !urlEncode {
+		return name
+	}
+	return s3NameEncoder.Replace(url.QueryEscape(name))
+}
+
+// encodeContinuationToken returns an opaque continuation token that resumes a listing after key
+func encodeContinuationToken(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// decodeContinuationToken returns the key a listing resumes after. Tokens that do not decode are the plain keys
+// returned by earlier versions.
+func decodeContinuationToken(token string) string {
+	key, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !utf8.Valid(key) {
+		return token
+	}
+	return string(key)
+}
+
+// listAfter returns the path in ref a listing starts after, given a key of the bucket such as start-after. Keys
+// before all the keys of ref start the listing at its beginning, and inRef is false for keys after all of them.
+func listAfter(ref, key string) (after string, inRef bool) {
+	refPrefix := ref + path.Separator
+	if strings.HasPrefix(key, refPrefix) {
+		return key[len(refPrefix):], true
+	}
+	return "", key < refPrefix
+}
+
+// principalOwner returns the owner reported for buckets and objects. lakeFS does not record owners, so they are
+// reported as owned by the requesting user.
+func principalOwner(principal string) *serde.Owner {
+	return &serde.Owner{ID: principal, DisplayName: principal}
+}
+
+// checksumAlgorithms returns the algorithms of the additional checksums stored on an object
+func checksumAlgorithms(metadata catalog.Metadata) []string {
+	checksums := upload.ChecksumsFromMetadata(metadata)
+	var algorithms []string
+	if checksums.CRC32C != "" {
+		algorithms = append(algorithms, "CRC32C")
+	}
+	if checksums.SHA256 != "" {
+		algorithms = append(algorithms, "SHA256")
+	}
+	return algorithms
+}
+
 type ListObjects struct{}
 
 func (controller *ListObjects) RequiredPermissions(req *http.Request, repoID string) (permissions.Node, error) {
@@ -61,33 +122,37 @@ func (controller *ListObjects) getMaxKeys(req *http.Request, _ *RepoOperation) i
 	return maxKeys
 }
 
-func (controller *ListObjects) serializeEntries(ref string, entries []*catalog.DBEntry) ([]serde.CommonPrefixes, []serde.Contents, string) {
+// serializeEntries serializes a listing of ref. Owner is set on each of the files when not nil, and the names are
+// URL encoded when urlEncode is set.
+func (controller *ListObjects) serializeEntries(ref string, entries []*catalog.DBEntry, owner *serde.Owner, urlEncode bool) ([]serde.CommonPrefixes, []serde.Contents, string) {
 	dirs := make([]serde.CommonPrefixes, 0)
 	files := make([]serde.Contents, 0)
 	var lastKey string
 	for _, entry := range entries {
 		lastKey = entry.Path
 		if entry.CommonLevel {
-			dirs = append(dirs, serde.CommonPrefixes{Prefix: path.WithRef(entry.Path, ref)})
+			dirs = append(dirs, serde.CommonPrefixes{Prefix: encodeName(path.WithRef(entry.Path, ref), urlEncode)})
 		} else {
 			files = append(files, serde.Contents{
-				Key:          path.WithRef(entry.Path, ref),
-				LastModified: serde.Timestamp(entry.CreationDate),
-				ETag:         httputil.ETag(entry.Checksum),
-				Size:         entry.Size,
-				StorageClass: "STANDARD",
+				Key:               encodeName(path.WithRef(entry.Path, ref), urlEncode),
+				LastModified:      serde.Timestamp(entry.CreationDate),
+				ETag:              httputil.ETag(entry.Checksum),
+				ChecksumAlgorithm: checksumAlgorithms(entry.Metadata),
+				Size:              entry.Size,
+				Owner:             owner,
+				StorageClass:      "STANDARD",
 			})
 		}
 	}
 	return dirs, files, lastKey
 }
 
-func (controller *ListObjects) serializeBranches(branches []*catalog.Branch) ([]serde.CommonPrefixes, string) {
+func (controller *ListObjects) serializeBranches(branches []*catalog.Branch, urlEncode bool) ([]serde.CommonPrefixes, string) {
 	dirs := make([]serde.CommonPrefixes, 0)
 	var lastKey string
 	for _, branch := range branches {
 		lastKey = branch.Name
-		dirs = append(dirs, serde.CommonPrefixes{Prefix: path.WithRef("", branch.Name)})
+		dirs = append(dirs, serde.CommonPrefixes{Prefix: encodeName(path.WithRef("", branch.Name), urlEncode)})
 	}
 	return dirs, lastKey
 }
@@ -100,15 +165,14 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 	delimiter := params.Get("delimiter")
 	startAfter := params.Get("start-after")
 	continuationToken := params.Get("continuation-token")
-
-	// resolve "from"
-	var fromStr string
-	if len(startAfter) > 0 {
-		fromStr = startAfter
+	urlEncode := params.Get("encoding-type") == encodingTypeURL
+	if params.Has("continuation-token") && continuationToken == "" {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidContinuationToken))
+		return
 	}
-	if len(continuationToken) > 0 {
-		// take this instead
-		fromStr = continuationToken
+	var owner *serde.Owner
+	if params.Get("fetch-owner") == "true" {
+		owner = principalOwner(o.Principal)
 	}
 
 	maxKeys := controller.getMaxKeys(req, o)
@@ -127,96 +191,101 @@ func (controller *ListObjects) ListV2(w http.ResponseWriter, req *http.Request,
 		return
 	}
 
-	var from path.ResolvedPath
+	resp := serde.ListObjectsV2Output{
+		Name:              o.Repository.Name,
+		Prefix:            encodeName(params.Get("prefix"), urlEncode),
+		Delimiter:         encodeName(delimiter, urlEncode),
+		MaxKeys:           maxKeys,
+		StartAfter:        encodeName(startAfter, urlEncode),
+		ContinuationToken: continuationToken,
+	}
+	if urlEncode {
+		resp.EncodingType = encodingTypeURL
+	}
+
 	if !prefix.WithPath {
 		// list branches then.
 		branchPrefix := prefix.Ref // TODO: same prefix logic also in V1!!!!!
+		after := startAfter
+		if continuationToken != "" {
+			after = decodeContinuationToken(continuationToken)
+		}
 		o.Log(req).WithField("prefix", branchPrefix).Debug("listing branches with prefix")
-		branches, hasMore, err := o.Catalog.ListBranches(req.Context(), o.Repository.Name, branchPrefix, maxKeys, fromStr)
+		branches, hasMore, err := o.Catalog.ListBranches(req.Context(), o.Repository.Name, branchPrefix, maxKeys, after)
 		if err != nil {
 			o.Log(req).WithError(err).Error("could not list branches")
 			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
 			return
 		}
 		// return branch response
-		dirs, lastKey := controller.serializeBranches(branches)
-		resp := serde.ListObjectsV2Output{
-			Name:           o.Repository.Name,
-			Prefix:         params.Get("prefix"),
-			Delimiter:      delimiter,
-			KeyCount:       len(dirs),
-			MaxKeys:        maxKeys,
-			CommonPrefixes: dirs,
-			Contents:       make([]serde.Contents, 0),
-		}
-
-		if len(continuationToken) > 0 && strings.EqualFold(continuationToken, fromStr) {
-			resp.ContinuationToken = continuationToken
-		}
-
+		dirs, lastKey := controller.serializeBranches(branches, urlEncode)
+		resp.KeyCount = len(dirs)
+		resp.CommonPrefixes = dirs
+		resp.Contents = make([]serde.Contents, 0)
 		if hasMore {
 			resp.IsTruncated = true
-			resp.NextContinuationToken = lastKey
+			resp.NextContinuationToken = encodeContinuationToken(lastKey)
 		}
 
 		o.EncodeResponse(w, req, resp, http.StatusOK)
 		return
-	} else {
-		// list objects then.
-		ref = prefix.Ref
-		if len(fromStr) > 0 {
-			from, err = path.ResolvePath(fromStr)
-			if err != nil || !strings.EqualFold(from.Ref, prefix.Ref) {
-				o.Log(req).WithError(err).WithFields(logging.Fields{
-					"branch": prefix.Ref,
-					"path":   prefix.Path,
-					"from":   fromStr,
-				}).Error("invalid marker - doesnt start with branch name")
-				_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
-				return
-			}
-		}
+	}
 
-		results, hasMore, err = o.Catalog.ListEntries(
-			req.Context(),
-			o.Repository.Name,
-			prefix.Ref,
-			prefix.Path,
-			from.Path,
-			delimiter,
-			maxKeys,
-		)
-		log := o.Log(req).WithError(err).WithFields(logging.Fields{
-			"ref":  prefix.Ref,
-			"path": prefix.Path,
-		})
-		if errors.Is(err, graveler.ErrBranchNotFound) {
-			log.Debug("could not list objects in path")
-		} else if err != nil {
-			log.Error("could not list objects in path")
-			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+	// list objects then.
+	ref = prefix.Ref
+	var after string
+	if continuationToken != "" {
+		// continuation tokens are returned by earlier listings of the same ref
+		var found bool
+		after, found = strings.CutPrefix(decodeContinuationToken(continuationToken), ref+path.Separator)
+		if !found {
+			o.Log(req).WithFields(logging.Fields{
+				"ref":                ref,
+				"continuation_token": continuationToken,
+			}).Debug("continuation token is not of the listed ref")
+			_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidContinuationToken))
+			return
+		}
+	} else if startAfter != "" {
+		var inRef bool
+		after, inRef = listAfter(ref, startAfter)
+		if !inRef {
+			// start-after is past all the keys of the ref
+			resp.CommonPrefixes = make([]serde.CommonPrefixes, 0)
+			resp.Contents = make([]serde.Contents, 0)
+			o.EncodeResponse(w, req, resp, http.StatusOK)
 			return
 		}
 	}
 
-	dirs, files, lastKey := controller.serializeEntries(ref, results)
-	resp := serde.ListObjectsV2Output{
-		Name:           o.Repository.Name,
-		Prefix:         params.Get("prefix"),
-		Delimiter:      delimiter,
-		KeyCount:       len(results),
-		MaxKeys:        maxKeys,
-		CommonPrefixes: dirs,
-		Contents:       files,
-	}
-
-	if len(continuationToken) > 0 && strings.EqualFold(continuationToken, fromStr) {
-		resp.ContinuationToken = continuationToken
+	results, hasMore, err = o.Catalog.ListEntries(
+		req.Context(),
+		o.Repository.Name,
+		prefix.Ref,
+		prefix.Path,
+		after,
+		delimiter,
+		maxKeys,
+	)
+	log := o.Log(req).WithError(err).WithFields(logging.Fields{
+		"ref":  prefix.Ref,
+		"path": prefix.Path,
+	})
+	if errors.Is(err, graveler.ErrBranchNotFound) {
+		log.Debug("could not list objects in path")
+	} else if err != nil {
+		log.Error("could not list objects in path")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+		return
 	}
 
+	dirs, files, lastKey := controller.serializeEntries(ref, results, owner, urlEncode)
+	resp.KeyCount = len(results)
+	resp.CommonPrefixes = dirs
+	resp.Contents = files
 	if hasMore {
 		resp.IsTruncated = true
-		resp.NextContinuationToken = path.WithRef(lastKey, ref)
+		resp.NextContinuationToken = encodeContinuationToken(path.WithRef(lastKey, ref))
 	}
 
 	o.EncodeResponse(w, req, resp, http.StatusOK)
@@ -229,6 +298,8 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 	// handle ListObjects (v1)
 	params := req.URL.Query()
 	delimiter := params.Get("delimiter")
+	marker := params.Get("marker")
+	urlEncode := params.Get("encoding-type") == encodingTypeURL
 	descend := true
 	if len(delimiter) >= 1 {
 		descend = false
@@ -251,9 +322,20 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 		return
 	}
 
+	resp := serde.ListBucketResult{
+		Name:      o.Repository.Name,
+		Prefix:    encodeName(params.Get("prefix"), urlEncode),
+		Delimiter: encodeName(delimiter, urlEncode),
+		Marker:    encodeName(marker, urlEncode),
+		MaxKeys:   maxKeys,
+	}
+	if urlEncode {
+		resp.EncodingType = encodingTypeURL
+	}
+
 	if !prefix.WithPath {
 		// list branches then.
-		branches, hasMore, err := o.Catalog.ListBranches(req.Context(), o.Repository.Name, prefix.Ref, maxKeys, params.Get("marker"))
+		branches, hasMore, err := o.Catalog.ListBranches(req.Context(), o.Repository.Name, prefix.Ref, maxKeys, marker)
 		if err != nil {
 			// TODO incorrect error type
 			o.Log(req).WithError(err).Error("could not list branches")
@@ -261,90 +343,67 @@ func (controller *ListObjects) ListV1(w http.ResponseWriter, req *http.Request,
 			return
 		}
 		// return branch response
-		dirs, lastKey := controller.serializeBranches(branches)
-		resp := serde.ListBucketResult{
-			Name:           o.Repository.Name,
-			Prefix:         params.Get("prefix"),
-			Delimiter:      delimiter,
-			Marker:         params.Get("marker"),
-			KeyCount:       len(results),
-			MaxKeys:        maxKeys,
-			CommonPrefixes: dirs,
-			Contents:       make([]serde.Contents, 0),
-		}
+		dirs, lastKey := controller.serializeBranches(branches, urlEncode)
+		resp.KeyCount = len(dirs)
+		resp.CommonPrefixes = dirs
+		resp.Contents = make([]serde.Contents, 0)
 
 		if hasMore {
 			resp.IsTruncated = true
 			if !descend {
 				// NextMarker is only set if a delimiter exists
-				resp.NextMarker = lastKey
+				resp.NextMarker = encodeName(lastKey, urlEncode)
 			}
 		}
 
 		o.EncodeResponse(w, req, resp, http.StatusOK)
 		return
-	} else {
-		prefix, err := path.ResolvePath(params.Get("prefix"))
-		if err != nil {
-			o.Log(req).WithError(err).Error("could not list branches")
-			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
-			return
-		}
-		ref = prefix.Ref
-		// see if we have a continuation token in the request to pick up from
-		var marker path.ResolvedPath
-		// strip the branch from the marker
-		if len(params.Get("marker")) > 0 {
-			marker, err = path.ResolvePath(params.Get("marker"))
-			if err != nil || !strings.EqualFold(marker.Ref, prefix.Ref) {
-				o.Log(req).WithError(err).WithFields(logging.Fields{
-					"branch": prefix.Ref,
-					"path":   prefix.Path,
-					"marker": marker,
-				}).Error("invalid marker - doesnt start with branch name")
-				_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
-				return
-			}
-		}
-		results, hasMore, err = o.Catalog.ListEntries(
-			req.Context(),
-			o.Repository.Name,
-			prefix.Ref,
-			prefix.Path,
-			marker.Path,
-			delimiter,
-			maxKeys,
-		)
-		if errors.Is(err, graveler.ErrNotFound) {
-			results = make([]*catalog.DBEntry, 0) // no results found
-		} else if err != nil {
-			o.Log(req).WithError(err).WithFields(logging.Fields{
-				"branch": prefix.Ref,
-				"path":   prefix.Path,
-			}).Error("could not list objects in path")
-			_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+	}
+
+	ref = prefix.Ref
+	// the marker is a key of the bucket, pick up the listing of ref from it
+	var after string
+	if marker != "" {
+		var inRef bool
+		after, inRef = listAfter(ref, marker)
+		if !inRef {
+			resp.CommonPrefixes = make([]serde.CommonPrefixes, 0)
+			resp.Contents = make([]serde.Contents, 0)
+			o.EncodeResponse(w, req, resp, http.StatusOK)
 			return
 		}
 	}
-
-	// build a response
-	dirs, files, lastKey := controller.serializeEntries(ref, results)
-	resp := serde.ListBucketResult{
-		Name:           o.Repository.Name,
-		Prefix:         params.Get("prefix"),
-		Delimiter:      delimiter,
-		Marker:         params.Get("marker"),
-		KeyCount:       len(results),
-		MaxKeys:        maxKeys,
-		CommonPrefixes: dirs,
-		Contents:       files,
+	results, hasMore, err = o.Catalog.ListEntries(
+		req.Context(),
+		o.Repository.Name,
+		prefix.Ref,
+		prefix.Path,
+		after,
+		delimiter,
+		maxKeys,
+	)
+	if errors.Is(err, graveler.ErrNotFound) {
+		results = make([]*catalog.DBEntry, 0) // no results found
+	} else if err != nil {
+		o.Log(req).WithError(err).WithFields(logging.Fields{
+			"branch": prefix.Ref,
+			"path":   prefix.Path,
+		}).Error("could not list objects in path")
+		_ = o.EncodeError(w, req, err, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+		return
 	}
 
+	// build a response, ListObjects (v1) always reports the owner of objects
+	dirs, files, lastKey := controller.serializeEntries(ref, results, principalOwner(o.Principal), urlEncode)
+	resp.KeyCount = len(results)
+	resp.CommonPrefixes = dirs
+	resp.Contents = files
+
 	if hasMore {
 		resp.IsTruncated = true
 		if !descend {
 			// NextMarker is only set if a delimiter exists
-			resp.NextMarker = path.WithRef(lastKey, ref)
+			resp.NextMarker = encodeName(path.WithRef(lastKey, ref), urlEncode)
 		}
 	}
 
@@ -378,6 +437,11 @@ func (controller *ListObjects) Handle(w http.ResponseWriter, req *http.Request,
 	}
 	o.Incr("list_objects", o.Principal, o.Repository.Name, "")
 
+	if encodingType := query.Get("encoding-type"); encodingType != "" && encodingType != encodingTypeURL {
+		_ = o.EncodeError(w, req, nil, gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidEncodingMethod))
+		return
+	}
+
 	// parse request parameters
 	// GET /example?list-type=2&prefix=main%2F&delimiter=%2F&encoding-type=url HTTP/1.1
 
@@ -0,0 +1,67 @@
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+func TestEncodeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "main/data/file.csv", want: "main/data/file.csv"},
+		{name: "main/with space+plus", want: "main/with+space%2Bplus"},
+		{name: "main/a*b~c", want: "main/a*b%7Ec"},
+		{name: "main/ünï\x01", want: "main/%C3%BCn%C3%AF%01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, encodeName(tt.name, true))
+			require.Equal(t, tt.name, encodeName(tt.name, false))
+		})
+	}
+}
+
+func TestContinuationToken(t *testing.T) {
+	for _, key := range []string{"main/a", "main/dir-with-dashes/", "main/ünï code\n", "feature"} {
+		token := encodeContinuationToken(key)
+		require.NotContains(t, token, "/")
+		require.Equal(t, key, decodeContinuationToken(token))
+	}
+	// plain keys returned as tokens by earlier versions
+	require.Equal(t, "main/a/b", decodeContinuationToken("main/a/b"))
+}
+
+func TestListAfter(t *testing.T) {
+	tests := []struct {
+		key       string
+		wantAfter string
+		wantInRef bool
+	}{
+		{key: "main/data-2024-01", wantAfter: "data-2024-01", wantInRef: true},
+		{key: "main/", wantAfter: "", wantInRef: true},
+		{key: "dev/x", wantAfter: "", wantInRef: true},
+		{key: "main", wantAfter: "", wantInRef: true},
+		{key: "maim/zzz", wantAfter: "", wantInRef: true},
+		{key: "main0", wantAfter: "", wantInRef: false},
+		{key: "zzz", wantAfter: "", wantInRef: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			after, inRef := listAfter("main", tt.key)
+			require.Equal(t, tt.wantAfter, after)
+			require.Equal(t, tt.wantInRef, inRef)
+		})
+	}
+}
+
+func TestChecksumAlgorithms(t *testing.T) {
+	metadata := catalog.Metadata{}
+	require.Empty(t, checksumAlgorithms(metadata))
+	upload.Checksums{SHA256: "sha", CRC32C: "crc"}.SetMetadata(metadata)
+	require.Equal(t, []string{"CRC32C", "SHA256"}, checksumAlgorithms(metadata))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/treeverse/lakefs/pkg/catalog"
 	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/upload"
 )
 
 const amzMetaHeaderPrefix = "X-Amz-Meta-"
@@ -32,8 +33,26 @@ func amzMetaWriteHeaders(w http.ResponseWriter, metadata catalog.Metadata) {
 	}
 }
 
+// setChecksumHeaders set the additional checksums headers on http response
+func setChecksumHeaders(w http.ResponseWriter, o *PathOperation, checksums upload.Checksums) {
+	if checksums.SHA256 != "" {
+		o.SetHeader(w, ChecksumSHA256Header, checksums.SHA256)
+	}
+	if checksums.CRC32C != "" {
+		o.SetHeader(w, ChecksumCRC32CHeader, checksums.CRC32C)
+	}
+}
+
 func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddress string, size int64, relative bool, metadata map[string]string, contentType string) error {
 	// write metadata
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	encryption, err := o.Catalog.RepositoryEncryption(req.Context(), o.Repository.Name)
+	if err != nil {
+		return err
+	}
+	encryption.SetMetadata(metadata)
 	writeTime := time.Now()
 	entry := catalog.NewDBEntryBuilder().
 		Path(o.Path).
@@ -46,7 +65,7 @@ func (o *PathOperation) finishUpload(req *http.Request, checksum, physicalAddres
 		ContentType(contentType).
 		Build()
 
-	err := o.Catalog.CreateEntry(req.Context(), o.Repository.Name, o.Reference, entry)
+	err = o.Catalog.CreateEntry(req.Context(), o.Repository.Name, o.Reference, entry)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not update metadata")
 		return err
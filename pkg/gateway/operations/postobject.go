@@ -18,6 +18,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/httputil"
 	"github.com/treeverse/lakefs/pkg/logging"
 	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
 )
 
 const (
@@ -110,21 +111,47 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		return
 	}
 	normalizeMultipartUploadCompletion(&multipartList)
-	resp, err := o.BlockStore.CompleteMultiPartUpload(req.Context(),
-		block.ObjectPointer{
-			StorageNamespace: o.Repository.StorageNamespace,
-			IdentifierType:   block.IdentifierTypeRelative,
-			Identifier:       objName,
-		},
-		uploadID,
-		&multipartList)
+	// checksums sent on complete are of the whole object data
+	checksums := upload.Checksums{
+		SHA256: req.Header.Get(ChecksumSHA256Header),
+		CRC32C: req.Header.Get(ChecksumCRC32CHeader),
+	}
+	if err := checksums.Validate(); err != nil {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidDigest))
+		return
+	}
+	obj := block.ObjectPointer{
+		StorageNamespace: o.Repository.StorageNamespace,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       objName,
+	}
+	resp, err := o.BlockStore.CompleteMultiPartUpload(req.Context(), obj, uploadID, &multipartList)
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not complete multipart upload")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
 	}
+	err = upload.VerifyObject(req.Context(), o.BlockStore, obj, checksums)
+	if errors.Is(err, upload.ErrChecksumMismatch) {
+		o.Log(req).WithError(err).Warn("uploaded data does not match checksum")
+		if err := o.MultipartTracker.Delete(req.Context(), uploadID); err != nil {
+			o.Log(req).WithError(err).Warn("could not delete multipart record")
+		}
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrBadDigest))
+		return
+	}
+	if err != nil {
+		o.Log(req).WithError(err).Error("could not verify multipart upload checksums")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
+		return
+	}
+	metadata := make(map[string]string, len(multiPart.Metadata))
+	for k, v := range multiPart.Metadata {
+		metadata[k] = v
+	}
+	checksums.SetMetadata(metadata)
 	checksum := strings.Split(resp.ETag, "-")[0]
-	err = o.finishUpload(req, checksum, objName, resp.ContentLength, true, multiPart.Metadata, multiPart.ContentType)
+	err = o.finishUpload(req, checksum, objName, resp.ContentLength, true, metadata, multiPart.ContentType)
 	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrWriteToProtectedBranch))
 		return
@@ -150,6 +177,7 @@ func (controller *PostObject) HandleCompleteMultipartUpload(w http.ResponseWrite
 		location = fmt.Sprintf("%s://%s/%s/%s/%s", scheme, req.Host, o.Repository.Name, o.Reference, o.Path)
 	}
 	o.SetHeaders(w, resp.ServerSideHeader)
+	setChecksumHeaders(w, o, checksums)
 	o.EncodeResponse(w, req, &serde.CompleteMultipartUploadResult{
 		Location: location,
 		Bucket:   o.Repository.Name,
@@ -24,6 +24,12 @@ const (
 	CopySourceRangeHeader = "x-amz-copy-source-range"
 	QueryParamUploadID    = "uploadId"
 	QueryParamPartNumber  = "partNumber"
+
+	ChecksumSHA256Header = "x-amz-checksum-sha256"
+	ChecksumCRC32CHeader = "x-amz-checksum-crc32c"
+	ChecksumModeHeader   = "x-amz-checksum-mode"
+
+	checksumModeEnabled = "ENABLED"
 )
 
 type PutObject struct{}
@@ -73,6 +79,9 @@ func extractEntryFromCopyReq(w http.ResponseWriter, req *http.Request, o *PathOp
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidCopySource))
 		return nil
 	}
+	if !authorizeAliasTarget(w, req, o, copySource.Repo, ent) {
+		return nil
+	}
 	return ent
 }
 
@@ -104,7 +113,20 @@ func handleCopy(w http.ResponseWriter, req *http.Request, o *PathOperation, copy
 	}
 
 	ctx := req.Context()
+	// copying an alias copies the object it points to, which the principal must be allowed to read
+	srcEntry := extractEntryFromCopyReq(w, req, o, srcPath)
+	if srcEntry == nil {
+		return
+	}
+	if srcEntry.AliasTarget != "" {
+		srcPath.Path = srcEntry.AliasTarget
+	}
 	entry, err := o.Catalog.CopyEntry(ctx, srcPath.Repo, srcPath.Reference, srcPath.Path, repository, branch, o.Path)
+	if errors.Is(err, catalog.ErrEncryptionKeyNotAllowed) {
+		o.Log(req).WithError(err).Warn("copy: encryption key not allowed on destination")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrAccessDenied))
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("could create a copy")
 		apiErr := gatewayErrors.Codes.ToAPIErrWithInternalError(gatewayErrors.ErrInvalidCopyDest, err)
@@ -160,6 +182,11 @@ func handleUploadPart(w http.ResponseWriter, req *http.Request, o *PathOperation
 		if ent == nil {
 			return // operation already failed
 		}
+		if err := o.Catalog.CheckEntryEncryption(req.Context(), o.Repository.Name, o.Reference, o.Path, ent); err != nil {
+			o.Log(req).WithField("copy_source", copySource).WithError(err).Warn("copy part: encryption key not allowed on destination")
+			_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrAccessDenied))
+			return
+		}
 		srcRepo := o.Repository
 		if resolvedCopySource.Repo != o.Repository.Name {
 			srcRepo, err = o.Catalog.GetRepository(req.Context(), resolvedCopySource.Repo)
@@ -295,7 +322,20 @@ func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 	storageClass := StorageClassFromHeader(req.Header)
 	opts := block.PutOpts{StorageClass: storageClass}
 	address := o.PathProvider.NewPath()
-	blob, err := upload.WriteBlob(req.Context(), o.BlockStore, o.Repository.StorageNamespace, address, req.Body, req.ContentLength, opts)
+	checksums := upload.Checksums{
+		SHA256: req.Header.Get(ChecksumSHA256Header),
+		CRC32C: req.Header.Get(ChecksumCRC32CHeader),
+	}
+	blob, err := upload.WriteBlobWithChecksums(req.Context(), o.BlockStore, o.Repository.StorageNamespace, address, req.Body, req.ContentLength, opts, checksums)
+	if errors.Is(err, upload.ErrInvalidChecksum) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidDigest))
+		return
+	}
+	if errors.Is(err, upload.ErrChecksumMismatch) {
+		o.Log(req).WithError(err).Warn("uploaded data does not match checksum")
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrBadDigest))
+		return
+	}
 	if err != nil {
 		o.Log(req).WithError(err).Error("could not write request body to block adapter")
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
@@ -304,6 +344,7 @@ func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 
 	// write metadata
 	metadata := amzMetaAsMetadata(req)
+	blob.Checksums.SetMetadata(metadata)
 	contentType := req.Header.Get("Content-Type")
 	err = o.finishUpload(req, blob.Checksum, blob.PhysicalAddress, blob.Size, true, metadata, contentType)
 	if errors.Is(err, graveler.ErrWriteToProtectedBranch) {
@@ -314,10 +355,15 @@ func handlePut(w http.ResponseWriter, req *http.Request, o *PathOperation) {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrReadOnlyRepository))
 		return
 	}
+	if errors.Is(err, catalog.ErrInvalidDirectoryMarker) {
+		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInvalidRequestBody))
+		return
+	}
 	if err != nil {
 		_ = o.EncodeError(w, req, err, gatewayErrors.Codes.ToAPIErr(gatewayErrors.ErrInternalError))
 		return
 	}
 	o.SetHeader(w, "ETag", httputil.ETag(blob.Checksum))
+	setChecksumHeaders(w, o, blob.Checksums)
 	w.WriteHeader(http.StatusOK)
 }
@@ -5,11 +5,16 @@ import (
 	"context"
 	"crypto/md5" //nolint:gosec
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"testing"
 
 	"github.com/treeverse/lakefs/pkg/api/apiutil"
 	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"github.com/treeverse/lakefs/pkg/upload"
 )
@@ -76,6 +81,58 @@ func TestWriteBlob(t *testing.T) {
 			if blob.Checksum != expectedMD5 {
 				t.Fatalf("expected blob checksum to be equal to data checksum, got: blob:%s , data:%s", blob.Checksum, expectedMD5)
 			}
+			if blob.Checksums != (upload.Checksums{}) {
+				t.Fatalf("expected no additional checksums without client checksums, got: %+v", blob.Checksums)
+			}
+		})
+	}
+}
+
+func TestWriteBlobWithChecksums(t *testing.T) {
+	data := []byte("checksummed data")
+	sha256Sum := sha256.Sum256(data)
+	validSHA256 := base64.StdEncoding.EncodeToString(sha256Sum[:])
+	otherSum := sha256.Sum256([]byte("other data"))
+	otherSHA256 := base64.StdEncoding.EncodeToString(otherSum[:])
+	crc32cHash := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	_, _ = crc32cHash.Write(data)
+	validCRC32C := base64.StdEncoding.EncodeToString(crc32cHash.Sum(nil))
+
+	tt := []struct {
+		name        string
+		checksums   upload.Checksums
+		expectedErr error
+	}{
+		{"none", upload.Checksums{}, nil},
+		{"valid sha256", upload.Checksums{SHA256: validSHA256}, nil},
+		{"valid sha256 and crc32c", upload.Checksums{SHA256: validSHA256, CRC32C: validCRC32C}, nil},
+		{"mismatch sha256", upload.Checksums{SHA256: otherSHA256}, upload.ErrChecksumMismatch},
+		{"invalid sha256", upload.Checksums{SHA256: "not-base64!"}, upload.ErrInvalidChecksum},
+		{"invalid crc32c", upload.Checksums{CRC32C: validSHA256}, upload.ErrInvalidChecksum},
+	}
+	const namespace = "mem://" + bucketName
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			adapter := mem.New(ctx)
+			address := upload.DefaultPathProvider.NewPath()
+			blob, err := upload.WriteBlobWithChecksums(ctx, adapter, namespace, address, bytes.NewReader(data), int64(len(data)), block.PutOpts{}, tc.checksums)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("WriteBlobWithChecksums() err=%v, expected=%v", err, tc.expectedErr)
+			}
+			if tc.expectedErr == nil && blob.Checksums != tc.checksums {
+				t.Fatalf("blob checksums=%+v, expected=%+v", blob.Checksums, tc.checksums)
+			}
+			if errors.Is(tc.expectedErr, upload.ErrChecksumMismatch) {
+				_, err := adapter.Get(ctx, block.ObjectPointer{
+					StorageNamespace: namespace,
+					IdentifierType:   block.IdentifierTypeRelative,
+					Identifier:       address,
+				})
+				if err == nil {
+					t.Fatal("expected object with mismatching checksum to be removed")
+				}
+			}
 		})
 	}
 }
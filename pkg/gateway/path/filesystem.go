@@ -0,0 +1,61 @@
+package path
+
+import (
+	stdpath "path"
+	"strings"
+)
+
+// filesystemPathParts is the number of parts of a filesystem path: repository, ref and object path
+const filesystemPathParts = 3
+
+// FilesystemPath is a path of the filesystem view of lakeFS served by the file gateways, laid out as
+// /<repository>/<ref>/<object path>
+type FilesystemPath struct {
+	Repository string
+	Ref        string
+	// Path is the object path, or the directory prefix without the trailing separator
+	Path string
+}
+
+// ParseFilesystemPath splits a filesystem path into its repository, ref and object path parts
+func ParseFilesystemPath(p string) FilesystemPath {
+	p = strings.TrimPrefix(stdpath.Clean(Separator+p), Separator)
+	if p == "" {
+		return FilesystemPath{}
+	}
+	parts := strings.SplitN(p, Separator, filesystemPathParts)
+	var ep FilesystemPath
+	ep.Repository = parts[0]
+	if len(parts) > 1 {
+		ep.Ref = parts[1]
+	}
+	if len(parts) == filesystemPathParts {
+		ep.Path = parts[2]
+	}
+	return ep
+}
+
+func (p FilesystemPath) IsRoot() bool {
+	return p.Repository == ""
+}
+
+func (p FilesystemPath) IsRepository() bool {
+	return p.Repository != "" && p.Ref == ""
+}
+
+func (p FilesystemPath) IsRef() bool {
+	return p.Ref != "" && p.Path == ""
+}
+
+// DirPrefix returns the prefix of the objects under the path, when the path is a directory
+func (p FilesystemPath) DirPrefix() string {
+	if p.Path == "" {
+		return ""
+	}
+	return p.Path + Separator
+}
+
+func (p FilesystemPath) String() string {
+	parts := []string{p.Repository, p.Ref, p.Path}
+	return Separator + strings.TrimRight(strings.Join(parts, Separator), Separator)
+}
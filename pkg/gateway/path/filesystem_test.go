@@ -0,0 +1,52 @@
+package path_test
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/gateway/path"
+)
+
+func TestParseFilesystemPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected path.FilesystemPath
+		root     bool
+		repo     bool
+		ref      bool
+	}{
+		{path: "/", expected: path.FilesystemPath{}, root: true},
+		{path: "", expected: path.FilesystemPath{}, root: true},
+		{path: "/repo", expected: path.FilesystemPath{Repository: "repo"}, repo: true},
+		{path: "repo/", expected: path.FilesystemPath{Repository: "repo"}, repo: true},
+		{path: "/repo/main", expected: path.FilesystemPath{Repository: "repo", Ref: "main"}, ref: true},
+		{path: "/repo/main/data/file.csv", expected: path.FilesystemPath{Repository: "repo", Ref: "main", Path: "data/file.csv"}},
+		{path: "/repo/main/data/../other//file.csv", expected: path.FilesystemPath{Repository: "repo", Ref: "main", Path: "other/file.csv"}},
+		{path: "/../repo/main~1/a", expected: path.FilesystemPath{Repository: "repo", Ref: "main~1", Path: "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := path.ParseFilesystemPath(tt.path)
+			if got != tt.expected {
+				t.Fatalf("ParseFilesystemPath(%s) = %+v, expected %+v", tt.path, got, tt.expected)
+			}
+			if got.IsRoot() != tt.root || got.IsRepository() != tt.repo || got.IsRef() != tt.ref {
+				t.Fatalf("ParseFilesystemPath(%s) root=%t repository=%t ref=%t, expected %t %t %t",
+					tt.path, got.IsRoot(), got.IsRepository(), got.IsRef(), tt.root, tt.repo, tt.ref)
+			}
+		})
+	}
+}
+
+func TestFilesystemPathString(t *testing.T) {
+	tests := map[string]string{
+		"/":                    "/",
+		"/repo":                "/repo",
+		"/repo/main":           "/repo/main",
+		"/repo/main/data/a.db": "/repo/main/data/a.db",
+	}
+	for p, expected := range tests {
+		if got := path.ParseFilesystemPath(p).String(); got != expected {
+			t.Errorf("ParseFilesystemPath(%s).String() = %s, expected %s", p, got, expected)
+		}
+	}
+}
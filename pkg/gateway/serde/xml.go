@@ -38,11 +38,13 @@ type CreateBucketConfiguration struct {
 }
 
 type Contents struct {
-	Key          string `xml:"Key"`
-	LastModified string `xml:"LastModified"`
-	ETag         string `xml:"ETag"`
-	Size         int64  `xml:"Size"`
-	StorageClass string `xml:"StorageClass"`
+	Key               string   `xml:"Key"`
+	LastModified      string   `xml:"LastModified"`
+	ETag              string   `xml:"ETag"`
+	ChecksumAlgorithm []string `xml:"ChecksumAlgorithm,omitempty"`
+	Size              int64    `xml:"Size"`
+	Owner             *Owner   `xml:"Owner,omitempty"`
+	StorageClass      string   `xml:"StorageClass"`
 }
 
 type CommonPrefixes struct {
@@ -60,6 +62,8 @@ type ListObjectsV2Output struct {
 	CommonPrefixes        []CommonPrefixes `xml:"CommonPrefixes"`
 	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
 	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	EncodingType          string           `xml:"EncodingType,omitempty"`
 	Contents              []Contents       `xml:"Contents"`
 }
 
@@ -86,6 +90,7 @@ type ListBucketResult struct {
 	CommonPrefixes []CommonPrefixes `xml:"CommonPrefixes"`
 	Marker         string           `xml:"Marker"`
 	NextMarker     string           `xml:"NextMarker,omitempty"`
+	EncodingType   string           `xml:"EncodingType,omitempty"`
 	Contents       []Contents       `xml:"Contents"`
 }
 
@@ -0,0 +1,237 @@
+package webdav
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewaypath "github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/upload"
+	xwebdav "golang.org/x/net/webdav"
+)
+
+// dirFile is an open directory, its entries are listed on the first call to Readdir
+type dirFile struct {
+	ctx     context.Context
+	fs      *refsFS
+	path    gatewaypath.FilesystemPath
+	info    os.FileInfo
+	entries []os.FileInfo
+	listed  bool
+}
+
+var _ xwebdav.File = (*dirFile)(nil)
+
+func (d *dirFile) Readdir(count int) ([]fs.FileInfo, error) {
+	if !d.listed {
+		entries, err := d.fs.readDir(d.ctx, d.path)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.listed = true
+	}
+	if count <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n := min(count, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: d.path.String(), Err: os.ErrInvalid}
+}
+
+func (d *dirFile) Seek(int64, int) (int64, error) {
+	return 0, &os.PathError{Op: "seek", Path: d.path.String(), Err: os.ErrInvalid}
+}
+
+func (d *dirFile) Write([]byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: d.path.String(), Err: os.ErrInvalid}
+}
+
+func (d *dirFile) Close() error {
+	return nil
+}
+
+// objectFile reads an object from the blockstore. Reads are streamed from the current offset to the end of
+// the object, seeking closes the stream and the next read starts a new one.
+type objectFile struct {
+	ctx     context.Context
+	adapter block.Adapter
+	pointer block.ObjectPointer
+	info    os.FileInfo
+	offset  int64
+	reader  io.ReadCloser
+}
+
+var _ xwebdav.File = (*objectFile)(nil)
+
+func newObjectFile(ctx context.Context, adapter block.Adapter, repository *catalog.Repository, entry *catalog.DBEntry, info os.FileInfo) *objectFile {
+	return &objectFile{
+		ctx:     ctx,
+		adapter: adapter,
+		pointer: block.ObjectPointer{
+			StorageNamespace: repository.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		},
+		info: info,
+	}
+}
+
+func (o *objectFile) Read(p []byte) (int, error) {
+	size := o.info.Size()
+	if o.offset >= size {
+		return 0, io.EOF
+	}
+	if o.reader == nil {
+		reader, err := o.adapter.GetRange(o.ctx, o.pointer, o.offset, size-1)
+		if err != nil {
+			return 0, err
+		}
+		o.reader = reader
+	}
+	n, err := o.reader.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *objectFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.info.Size()
+	default:
+		return 0, os.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+	if offset != o.offset {
+		if err := o.closeReader(); err != nil {
+			return 0, err
+		}
+		o.offset = offset
+	}
+	return offset, nil
+}
+
+func (o *objectFile) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: o.info.Name(), Err: os.ErrInvalid}
+}
+
+func (o *objectFile) Stat() (fs.FileInfo, error) {
+	return o.info, nil
+}
+
+func (o *objectFile) Write([]byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: o.info.Name(), Err: os.ErrPermission}
+}
+
+func (o *objectFile) Close() error {
+	return o.closeReader()
+}
+
+func (o *objectFile) closeReader() error {
+	if o.reader == nil {
+		return nil
+	}
+	err := o.reader.Close()
+	o.reader = nil
+	return err
+}
+
+// uploadFile buffers written content in a temporary file, which is uploaded and staged on the branch when closed
+type uploadFile struct {
+	*os.File
+	ctx        context.Context
+	fs         *refsFS
+	repository *catalog.Repository
+	path       gatewaypath.FilesystemPath
+}
+
+var _ xwebdav.File = (*uploadFile)(nil)
+
+func (u *uploadFile) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: u.path.String(), Err: os.ErrInvalid}
+}
+
+func (u *uploadFile) Stat() (fs.FileInfo, error) {
+	info, err := u.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{
+		name:    path.Base(u.path.Path),
+		size:    info.Size(),
+		mode:    writableFileMode,
+		modTime: info.ModTime(),
+	}, nil
+}
+
+func (u *uploadFile) Close() error {
+	defer func() {
+		_ = u.File.Close()
+		_ = os.Remove(u.File.Name())
+	}()
+	size, err := u.File.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := u.File.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	c := u.fs.catalog
+	address := u.fs.pathProvider.NewPath()
+	ctx := block.WithRepository(u.ctx, u.repository.Name)
+	blob, err := upload.WriteBlob(ctx, c.BlockAdapter, u.repository.StorageNamespace, address, u.File, size, block.PutOpts{})
+	if err != nil {
+		return err
+	}
+	entryBuilder := catalog.NewDBEntryBuilder().
+		Path(u.path.Path).
+		PhysicalAddress(blob.PhysicalAddress).
+		CreationDate(time.Now()).
+		Size(blob.Size).
+		Checksum(blob.Checksum).
+		ContentType(mime.TypeByExtension(path.Ext(u.path.Path)))
+	if blob.RelativePath {
+		entryBuilder.AddressType(catalog.AddressTypeRelative)
+	} else {
+		entryBuilder.AddressType(catalog.AddressTypeFull)
+	}
+	encryption, err := c.RepositoryEncryption(ctx, u.repository.Name)
+	if err != nil {
+		return err
+	}
+	meta := catalog.Metadata{}
+	encryption.SetMetadata(meta)
+	entryBuilder.Metadata(meta)
+	err = c.CreateEntry(u.ctx, u.repository.Name, u.path.Ref, entryBuilder.Build())
+	if errors.Is(err, graveler.ErrNotFound) {
+		return &os.PathError{Op: "close", Path: u.path.String(), Err: os.ErrNotExist}
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package webdav
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	xwebdav "golang.org/x/net/webdav"
+)
+
+func TestDirFileReaddir(t *testing.T) {
+	entries := []os.FileInfo{
+		&fileInfo{name: "a", mode: fileMode},
+		&fileInfo{name: "b", mode: fileMode},
+		&fileInfo{name: "c", mode: dirMode},
+	}
+	d := &dirFile{entries: entries, listed: true}
+	var names []string
+	for {
+		infos, err := d.Readdir(2)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Readdir(2) unexpected error: %s", err)
+		}
+		for _, info := range infos {
+			names = append(names, info.Name())
+		}
+	}
+	if len(names) != len(entries) {
+		t.Fatalf("Readdir(2) returned %v, expected %d entries", names, len(entries))
+	}
+
+	d = &dirFile{entries: entries, listed: true}
+	infos, err := d.Readdir(0)
+	if err != nil || len(infos) != len(entries) {
+		t.Fatalf("Readdir(0) = %d entries, %v, expected %d entries", len(infos), err, len(entries))
+	}
+}
+
+func TestFileInfoProperties(t *testing.T) {
+	ctx := context.Background()
+	info := &fileInfo{name: "data.csv", mode: fileMode, contentType: "text/csv", checksum: "abc"}
+	if contentType, err := info.ContentType(ctx); err != nil || contentType != "text/csv" {
+		t.Errorf("ContentType() = %s, %v, expected text/csv", contentType, err)
+	}
+	if etag, err := info.ETag(ctx); err != nil || etag != `"abc"` {
+		t.Errorf(`ETag() = %s, %v, expected "abc"`, etag, err)
+	}
+
+	dir := &fileInfo{name: "dir", mode: dirMode}
+	if _, err := dir.ContentType(ctx); !errors.Is(err, xwebdav.ErrNotImplemented) {
+		t.Errorf("directory ContentType() error = %v, expected %v", err, xwebdav.ErrNotImplemented)
+	}
+	if _, err := dir.ETag(ctx); !errors.Is(err, xwebdav.ErrNotImplemented) {
+		t.Errorf("directory ETag() error = %v, expected %v", err, xwebdav.ErrNotImplemented)
+	}
+}
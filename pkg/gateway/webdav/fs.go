@@ -0,0 +1,485 @@
+package webdav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	gatewaypath "github.com/treeverse/lakefs/pkg/gateway/path"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
+	xwebdav "golang.org/x/net/webdav"
+)
+
+const (
+	listEntriesAmount = 1000
+
+	dirMode          = fs.ModeDir | 0o555
+	writableDirMode  = fs.ModeDir | 0o755
+	fileMode         = 0o444
+	writableFileMode = 0o644
+
+	writeFlags = os.O_WRONLY | os.O_RDWR | os.O_CREATE | os.O_TRUNC | os.O_APPEND
+)
+
+// refsFS implements webdav.FileSystem over the repositories the user can access, laid out as
+// /<repository>/<ref>/<object path>. Only branches are listed under a repository, any other ref can be
+// accessed by name. Directories are the common prefixes of object paths, they exist as long as they hold objects.
+type refsFS struct {
+	catalog      *catalog.Catalog
+	authService  auth.Service
+	pathProvider upload.PathProvider
+	writable     bool
+}
+
+var _ xwebdav.FileSystem = (*refsFS)(nil)
+
+func newRefsFS(c *catalog.Catalog, authService auth.Service, pathProvider upload.PathProvider, writable bool) *refsFS {
+	return &refsFS{
+		catalog:      c,
+		authService:  authService,
+		pathProvider: pathProvider,
+		writable:     writable,
+	}
+}
+
+// fileInfo implements os.FileInfo for repositories, refs, directories and objects
+type fileInfo struct {
+	name        string
+	size        int64
+	mode        os.FileMode
+	modTime     time.Time
+	contentType string
+	checksum    string
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+// ContentType implements webdav.ContentTyper, reporting the object content type instead of sniffing its content
+func (fi *fileInfo) ContentType(context.Context) (string, error) {
+	if fi.contentType == "" {
+		return "", xwebdav.ErrNotImplemented
+	}
+	return fi.contentType, nil
+}
+
+// ETag implements webdav.ETager, using the object checksum
+func (fi *fileInfo) ETag(context.Context) (string, error) {
+	if fi.checksum == "" {
+		return "", xwebdav.ErrNotImplemented
+	}
+	return `"` + fi.checksum + `"`, nil
+}
+
+func (f *refsFS) dirInfo(name string, modTime time.Time, writable bool) *fileInfo {
+	mode := dirMode
+	if writable {
+		mode = writableDirMode
+	}
+	return &fileInfo{name: name, mode: mode, modTime: modTime}
+}
+
+func (f *refsFS) entryInfo(entry *catalog.DBEntry, writable bool) *fileInfo {
+	if entry.CommonLevel {
+		return f.dirInfo(path.Base(entry.Path), time.Time{}, writable)
+	}
+	mode := os.FileMode(fileMode)
+	if writable {
+		mode = writableFileMode
+	}
+	return &fileInfo{
+		name:        path.Base(entry.Path),
+		size:        entry.Size,
+		mode:        mode,
+		modTime:     entry.CreationDate,
+		contentType: entry.ContentType,
+		checksum:    entry.Checksum,
+	}
+}
+
+// notExist wraps os.ErrNotExist, which the WebDAV handler reports as 404 Not Found
+func notExist(op string, p gatewaypath.FilesystemPath) error {
+	return &os.PathError{Op: op, Path: p.String(), Err: os.ErrNotExist}
+}
+
+// permissionDenied wraps os.ErrPermission, which the WebDAV handler reports as 403 Forbidden
+func permissionDenied(op string, p gatewaypath.FilesystemPath) error {
+	return &os.PathError{Op: op, Path: p.String(), Err: os.ErrPermission}
+}
+
+// authorize checks that the user of the request holds the required permission
+func (f *refsFS) authorize(ctx context.Context, op string, p gatewaypath.FilesystemPath, action, resource string) error {
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return permissionDenied(op, p)
+	}
+	resp, err := f.authService.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: user.Username,
+		RequiredPermissions: permissions.WithActionsConfig(permissions.Node{
+			Permission: permissions.Permission{Action: action, Resource: resource},
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil || !resp.Allowed {
+		return permissionDenied(op, p)
+	}
+	return nil
+}
+
+func (f *refsFS) getRepository(ctx context.Context, op string, p gatewaypath.FilesystemPath) (*catalog.Repository, error) {
+	repository, err := f.catalog.GetRepository(ctx, p.Repository)
+	if errors.Is(err, graveler.ErrNotFound) || errors.Is(err, graveler.ErrInvalidValue) {
+		return nil, notExist(op, p)
+	}
+	return repository, err
+}
+
+// isWritable checks if changes can be made under p: the gateway is writable and the ref is a branch
+func (f *refsFS) isWritable(ctx context.Context, repository *catalog.Repository, p gatewaypath.FilesystemPath) (bool, error) {
+	if !f.writable || repository.ReadOnly {
+		return false, nil
+	}
+	exists, err := f.catalog.BranchExists(ctx, repository.Name, p.Ref)
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		return false, err
+	}
+	return exists, nil
+}
+
+// writableBranch returns the repository of p, after checking the user can perform action on the branch
+func (f *refsFS) writableBranch(ctx context.Context, op string, p gatewaypath.FilesystemPath, action string) (*catalog.Repository, error) {
+	if p.Path == "" {
+		return nil, permissionDenied(op, p)
+	}
+	repository, err := f.getRepository(ctx, op, p)
+	if err != nil {
+		return nil, err
+	}
+	writable, err := f.isWritable(ctx, repository, p)
+	if err != nil {
+		return nil, err
+	}
+	if !writable {
+		return nil, permissionDenied(op, p)
+	}
+	if err := f.authorize(ctx, op, p, action, permissions.ObjectArn(p.Repository, p.Path)); err != nil {
+		return nil, err
+	}
+	return repository, nil
+}
+
+// getEntry returns the entry of an object path, or a common level entry if the path is a directory. An alias is
+// returned only if the user may read the object it resolved to.
+func (f *refsFS) getEntry(ctx context.Context, op string, p gatewaypath.FilesystemPath) (*catalog.DBEntry, error) {
+	entry, err := f.catalog.GetEntry(ctx, p.Repository, p.Ref, p.Path, catalog.GetEntryParams{})
+	if err == nil {
+		if entry.AliasTarget != "" {
+			if err := f.authorize(ctx, op, p, permissions.ReadObjectAction, permissions.ObjectArn(p.Repository, entry.AliasTarget)); err != nil {
+				return nil, err
+			}
+		}
+		return entry, nil
+	}
+	if !errors.Is(err, graveler.ErrNotFound) {
+		return nil, err
+	}
+	entries, _, err := f.catalog.ListEntries(ctx, p.Repository, p.Ref, p.DirPrefix(), "", "", 1)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, notExist(op, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, notExist(op, p)
+	}
+	return &catalog.DBEntry{CommonLevel: true, Path: p.DirPrefix()}, nil
+}
+
+func (f *refsFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	const op = "stat"
+	p := gatewaypath.ParseFilesystemPath(name)
+	if p.IsRoot() {
+		return f.dirInfo(gatewaypath.Separator, time.Time{}, false), nil
+	}
+	if err := f.authorize(ctx, op, p, permissions.ReadRepositoryAction, permissions.RepoArn(p.Repository)); err != nil {
+		return nil, err
+	}
+	repository, err := f.getRepository(ctx, op, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsRepository() {
+		return f.dirInfo(repository.Name, repository.CreationDate, false), nil
+	}
+	writable, err := f.isWritable(ctx, repository, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsRef() {
+		commit, err := f.catalog.GetCommit(ctx, p.Repository, p.Ref)
+		if errors.Is(err, graveler.ErrNotFound) || errors.Is(err, graveler.ErrInvalid) {
+			return nil, notExist(op, p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return f.dirInfo(p.Ref, commit.CreationDate, writable), nil
+	}
+	if err := f.authorize(ctx, op, p, permissions.ReadObjectAction, permissions.ObjectArn(p.Repository, p.Path)); err != nil {
+		return nil, err
+	}
+	entry, err := f.getEntry(ctx, op, p)
+	if err != nil {
+		return nil, err
+	}
+	return f.entryInfo(entry, writable), nil
+}
+
+func (f *refsFS) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (xwebdav.File, error) {
+	p := gatewaypath.ParseFilesystemPath(name)
+	if flag&writeFlags != 0 {
+		return f.create(ctx, p)
+	}
+	info, err := f.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &dirFile{ctx: ctx, fs: f, path: p, info: info}, nil
+	}
+	repository, err := f.getRepository(ctx, "open", p)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := f.getEntry(ctx, "open", p)
+	if err != nil {
+		return nil, err
+	}
+	return newObjectFile(ctx, f.catalog.BlockAdapter, repository, entry, info), nil
+}
+
+// create returns a file that uploads its content to the branch when closed
+func (f *refsFS) create(ctx context.Context, p gatewaypath.FilesystemPath) (xwebdav.File, error) {
+	const op = "open"
+	repository, err := f.writableBranch(ctx, op, p, permissions.WriteObjectAction)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "lakefs-webdav-")
+	if err != nil {
+		return nil, err
+	}
+	return &uploadFile{
+		File:       tmp,
+		ctx:        ctx,
+		fs:         f,
+		repository: repository,
+		path:       p,
+	}, nil
+}
+
+// readDir lists the entries of a directory: the repositories the user can read at the root, the branches of
+// a repository, or the objects and directories under a ref
+func (f *refsFS) readDir(ctx context.Context, p gatewaypath.FilesystemPath) ([]os.FileInfo, error) {
+	const op = "readdir"
+	if p.IsRoot() {
+		return f.readRepositories(ctx, p)
+	}
+	if p.IsRepository() {
+		if err := f.authorize(ctx, op, p, permissions.ListBranchesAction, permissions.RepoArn(p.Repository)); err != nil {
+			return nil, err
+		}
+		return f.readBranches(ctx, p)
+	}
+	if err := f.authorize(ctx, op, p, permissions.ListObjectsAction, permissions.RepoArn(p.Repository)); err != nil {
+		return nil, err
+	}
+	repository, err := f.getRepository(ctx, op, p)
+	if err != nil {
+		return nil, err
+	}
+	writable, err := f.isWritable(ctx, repository, p)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		infos []os.FileInfo
+		after string
+	)
+	for {
+		entries, hasMore, err := f.catalog.ListEntries(ctx, p.Repository, p.Ref, p.DirPrefix(), after, gatewaypath.Separator, listEntriesAmount)
+		if errors.Is(err, graveler.ErrNotFound) {
+			return nil, notExist(op, p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			infos = append(infos, f.entryInfo(entry, writable))
+		}
+		if !hasMore || len(entries) == 0 {
+			return infos, nil
+		}
+		after = entries[len(entries)-1].Path
+	}
+}
+
+func (f *refsFS) readRepositories(ctx context.Context, p gatewaypath.FilesystemPath) ([]os.FileInfo, error) {
+	if err := f.authorize(ctx, "readdir", p, permissions.ListRepositoriesAction, permissions.All); err != nil {
+		return nil, err
+	}
+	var (
+		infos []os.FileInfo
+		after string
+	)
+	for {
+		repositories, hasMore, err := f.catalog.ListRepositories(ctx, listEntriesAmount, "", after)
+		if err != nil {
+			return nil, err
+		}
+		for _, repository := range repositories {
+			infos = append(infos, f.dirInfo(repository.Name, repository.CreationDate, false))
+		}
+		if !hasMore || len(repositories) == 0 {
+			return infos, nil
+		}
+		after = repositories[len(repositories)-1].Name
+	}
+}
+
+func (f *refsFS) readBranches(ctx context.Context, p gatewaypath.FilesystemPath) ([]os.FileInfo, error) {
+	var (
+		infos []os.FileInfo
+		after string
+	)
+	for {
+		branches, hasMore, err := f.catalog.ListBranches(ctx, p.Repository, "", listEntriesAmount, after)
+		if errors.Is(err, graveler.ErrNotFound) {
+			return nil, notExist("readdir", p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			infos = append(infos, f.dirInfo(branch.Name, time.Time{}, f.writable))
+		}
+		if !hasMore || len(branches) == 0 {
+			return infos, nil
+		}
+		after = branches[len(branches)-1].Name
+	}
+}
+
+// Mkdir accepts creating directories on writable branches, without staging anything: directories exist
+// implicitly, once objects are written under them
+func (f *refsFS) Mkdir(ctx context.Context, name string, _ os.FileMode) error {
+	p := gatewaypath.ParseFilesystemPath(name)
+	_, err := f.writableBranch(ctx, "mkdir", p, permissions.WriteObjectAction)
+	return err
+}
+
+// RemoveAll deletes an object, or all the objects under a directory, from the branch
+func (f *refsFS) RemoveAll(ctx context.Context, name string) error {
+	const op = "remove"
+	p := gatewaypath.ParseFilesystemPath(name)
+	repository, err := f.writableBranch(ctx, op, p, permissions.DeleteObjectAction)
+	if err != nil {
+		return err
+	}
+	entry, err := f.getEntry(ctx, op, p)
+	if err != nil {
+		return err
+	}
+	if !entry.CommonLevel {
+		return f.catalog.DeleteEntry(ctx, repository.Name, p.Ref, p.Path)
+	}
+	paths, err := f.listPaths(ctx, p)
+	if err != nil {
+		return err
+	}
+	for len(paths) > 0 {
+		n := min(len(paths), listEntriesAmount)
+		if err := f.catalog.DeleteEntries(ctx, repository.Name, p.Ref, paths[:n]); err != nil {
+			return err
+		}
+		paths = paths[n:]
+	}
+	return nil
+}
+
+// Rename moves an object, or all the objects under a directory, within a branch
+func (f *refsFS) Rename(ctx context.Context, oldName, newName string) error {
+	const op = "rename"
+	src := gatewaypath.ParseFilesystemPath(oldName)
+	dst := gatewaypath.ParseFilesystemPath(newName)
+	if src.Repository != dst.Repository || src.Ref != dst.Ref {
+		return fmt.Errorf("rename %s to %s: %w", src, dst, xwebdav.ErrNotImplemented)
+	}
+	repository, err := f.writableBranch(ctx, op, src, permissions.DeleteObjectAction)
+	if err != nil {
+		return err
+	}
+	if _, err := f.writableBranch(ctx, op, dst, permissions.WriteObjectAction); err != nil {
+		return err
+	}
+	entry, err := f.getEntry(ctx, op, src)
+	if err != nil {
+		return err
+	}
+	if !entry.CommonLevel {
+		return f.moveEntry(ctx, repository, src.Ref, src.Path, dst.Path)
+	}
+	paths, err := f.listPaths(ctx, src)
+	if err != nil {
+		return err
+	}
+	for _, srcPath := range paths {
+		dstPath := dst.DirPrefix() + srcPath[len(src.DirPrefix()):]
+		if err := f.moveEntry(ctx, repository, src.Ref, srcPath, dstPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveEntry moves the entry without copying its data, aliases are moved as they are
+func (f *refsFS) moveEntry(ctx context.Context, repository *catalog.Repository, branch, srcPath, dstPath string) error {
+	_, _, err := f.catalog.MoveEntry(ctx, repository.Name, branch, srcPath, dstPath, catalog.MoveEntryParams{})
+	return err
+}
+
+// listPaths returns the paths of all the objects under the directory p
+func (f *refsFS) listPaths(ctx context.Context, p gatewaypath.FilesystemPath) ([]string, error) {
+	var (
+		paths []string
+		after string
+	)
+	for {
+		entries, hasMore, err := f.catalog.ListEntries(ctx, p.Repository, p.Ref, p.DirPrefix(), after, "", listEntriesAmount)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		if !hasMore || len(entries) == 0 {
+			return paths, nil
+		}
+		after = entries[len(entries)-1].Path
+	}
+}
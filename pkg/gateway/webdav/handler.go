@@ -0,0 +1,57 @@
+package webdav
+
+import (
+	"net/http"
+
+	"github.com/treeverse/lakefs/pkg/accesslog"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/upload"
+	xwebdav "golang.org/x/net/webdav"
+)
+
+const (
+	// BasePath is the URI clients use as the WebDAV endpoint
+	BasePath = "/webdav"
+
+	LoggerServiceName = "webdav_gateway"
+
+	authenticateRealm = `Basic realm="lakeFS"`
+)
+
+// NewHandler serves the refs of the repositories over WebDAV, laid out as /<repository>/<ref>/<object path>.
+// Requests are authenticated by the lakeFS authentication middleware and authorized per operation.
+// When writable, objects can be uploaded, moved and deleted on branches: changes are staged on the branch.
+func NewHandler(c *catalog.Catalog, authService auth.Service, pathProvider upload.PathProvider, writable bool, auditLogLevel string, traceRequestHeaders bool) http.Handler {
+	handler := &xwebdav.Handler{
+		Prefix:     BasePath,
+		FileSystem: newRefsFS(c, authService, pathProvider, writable),
+		LockSystem: xwebdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				logging.FromContext(r.Context()).WithError(err).WithField("method", r.Method).Debug("webdav request failed")
+			}
+		},
+	}
+	loggingMiddleware := httputil.LoggingMiddleware(
+		httputil.RequestIDHeaderName,
+		logging.Fields{logging.ServiceNameFieldKey: LoggerServiceName},
+		auditLogLevel,
+		traceRequestHeaders)
+	return accesslog.Middleware(LoggerServiceName)(loggingMiddleware(requireUser(handler)))
+}
+
+// requireUser rejects requests that were not authenticated by the lakeFS authentication middleware, asking
+// WebDAV clients to send their lakeFS access key as basic authentication credentials
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := auth.GetUser(r.Context()); err != nil {
+			w.Header().Set("WWW-Authenticate", authenticateRealm)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,233 @@
+package website
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/accesslog"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	// BasePath is the URI under which refs are served as websites
+	BasePath = "/website"
+
+	LoggerServiceName = "website_gateway"
+
+	authenticateRealm  = `Basic realm="lakeFS"`
+	defaultContentType = "application/octet-stream"
+
+	// contentSecurityPolicy serves pages in a unique origin, so their scripts can't act as the user on lakeFS
+	contentSecurityPolicy = "sandbox allow-scripts allow-forms allow-popups allow-downloads"
+)
+
+var (
+	errUnauthenticated = errors.New("unauthenticated")
+	errAccessDenied    = errors.New("access denied")
+)
+
+// Config of the website served for each ref
+type Config struct {
+	// IndexDocument is served for paths ending with "/"
+	IndexDocument string
+	// ErrorDocument is served, when set, for paths not found on the ref
+	ErrorDocument string
+}
+
+type handler struct {
+	catalog       *catalog.Catalog
+	authService   auth.Authorizer
+	anonymousRead *auth.AnonymousReadPolicy
+	cfg           Config
+}
+
+// NewHandler serves the objects of refs as static websites, laid out as /<repository>/<ref>/<object path>.
+// Requests are authenticated by the lakeFS authentication middleware, or authorized by the anonymous read policy
+// when made without credentials.
+func NewHandler(c *catalog.Catalog, authService auth.Authorizer, anonymousRead *auth.AnonymousReadPolicy, cfg Config, auditLogLevel string, traceRequestHeaders bool) http.Handler {
+	loggingMiddleware := httputil.LoggingMiddleware(
+		httputil.RequestIDHeaderName,
+		logging.Fields{logging.ServiceNameFieldKey: LoggerServiceName},
+		auditLogLevel,
+		traceRequestHeaders)
+	return accesslog.Middleware(LoggerServiceName)(loggingMiddleware(&handler{
+		catalog:       c,
+		authService:   authService,
+		anonymousRead: anonymousRead,
+		cfg:           cfg,
+	}))
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, BasePath+"/"), "/", 3) //nolint:mnd
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	repository, ref := parts[0], parts[1]
+	if len(parts) == 2 {
+		// serve the ref root as a directory, so relative links resolve under it
+		http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+		return
+	}
+	objectPath := parts[2]
+
+	name := objectPath
+	if name == "" || strings.HasSuffix(name, "/") {
+		name += h.cfg.IndexDocument
+	}
+	entry, err := h.getEntry(r, repository, ref, name)
+	if errors.Is(err, graveler.ErrNotFound) && name == objectPath {
+		// a directory without a trailing slash
+		if _, indexErr := h.getEntry(r, repository, ref, objectPath+"/"+h.cfg.IndexDocument); indexErr == nil {
+			http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+	}
+	status := http.StatusOK
+	if errors.Is(err, graveler.ErrNotFound) && h.cfg.ErrorDocument != "" {
+		status = http.StatusNotFound
+		entry, err = h.getEntry(r, repository, ref, h.cfg.ErrorDocument)
+	}
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeEntry(w, r, repository, entry, status)
+}
+
+// getEntry reads the entry of name on ref, if the request may read it. An alias is read only if the request may
+// also read the object it resolved to.
+func (h *handler) getEntry(r *http.Request, repository, ref, name string) (*catalog.DBEntry, error) {
+	ctx := r.Context()
+	if err := h.authorizeRead(ctx, repository, name); err != nil {
+		return nil, err
+	}
+	entry, err := h.catalog.GetEntry(ctx, repository, ref, name, catalog.GetEntryParams{})
+	if err != nil {
+		return nil, err
+	}
+	if entry.AliasTarget != "" {
+		if err := h.authorizeRead(ctx, repository, entry.AliasTarget); err != nil {
+			return nil, err
+		}
+	}
+	if entry.Expired {
+		return nil, graveler.ErrNotFound
+	}
+	return entry, nil
+}
+
+// authorizeRead checks that the request may read the object at name
+func (h *handler) authorizeRead(ctx context.Context, repository, name string) error {
+	perms := permissions.Node{
+		Permission: permissions.Permission{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(repository, name),
+		},
+	}
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		if !h.anonymousRead.Authorize(ctx, perms) {
+			return errUnauthenticated
+		}
+		return nil
+	}
+	resp, err := h.authService.Authorize(ctx, &auth.AuthorizationRequest{
+		Username:            user.Username,
+		RequiredPermissions: perms,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil || !resp.Allowed {
+		return errAccessDenied
+	}
+	return nil
+}
+
+func (h *handler) writeEntry(w http.ResponseWriter, r *http.Request, repository string, entry *catalog.DBEntry, status int) {
+	ctx := r.Context()
+	eTag := httputil.ETag(entry.Checksum)
+	if status == http.StatusOK && r.Header.Get("If-None-Match") == eTag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	repo, err := h.catalog.GetRepository(ctx, repository)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	var reader io.ReadCloser
+	if r.Method != http.MethodHead {
+		reader, err = h.catalog.BlockAdapter.Get(ctx, block.ObjectPointer{
+			StorageNamespace: repo.StorageNamespace,
+			IdentifierType:   entry.AddressType.ToIdentifierType(),
+			Identifier:       entry.PhysicalAddress,
+		})
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		defer func() { _ = reader.Close() }()
+	}
+
+	w.Header().Set("Content-Type", contentType(entry))
+	w.Header().Set("Content-Length", strconv.FormatInt(entry.Size, 10))
+	w.Header().Set("ETag", eTag)
+	w.Header().Set("Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
+	w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if reader == nil {
+		return
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("path", entry.Path).Debug("website copy content")
+	}
+}
+
+func (h *handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errUnauthenticated):
+		w.Header().Set("WWW-Authenticate", authenticateRealm)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	case errors.Is(err, errAccessDenied):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	case errors.Is(err, graveler.ErrNotFound):
+		http.NotFound(w, r)
+	case errors.Is(err, graveler.ErrInvalidValue):
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	default:
+		logging.FromContext(r.Context()).WithError(err).Error("website request failed")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// contentType returns the content type the object was uploaded with, or the type of its extension
+func contentType(entry *catalog.DBEntry) string {
+	if entry.ContentType != "" && entry.ContentType != defaultContentType {
+		return entry.ContentType
+	}
+	if t := mime.TypeByExtension(path.Ext(entry.Path)); t != "" {
+		return t
+	}
+	return defaultContentType
+}
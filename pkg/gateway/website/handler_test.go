@@ -0,0 +1,28 @@
+package website
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+)
+
+func TestContentType(t *testing.T) {
+	cases := []struct {
+		Path        string
+		ContentType string
+		Expected    string
+	}{
+		{Path: "index.html", Expected: "text/html; charset=utf-8"},
+		{Path: "style.css", ContentType: defaultContentType, Expected: "text/css; charset=utf-8"},
+		{Path: "data.json", ContentType: "application/x-ndjson", Expected: "application/x-ndjson"},
+		{Path: "README", Expected: defaultContentType},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Path, func(t *testing.T) {
+			got := contentType(&catalog.DBEntry{Path: tc.Path, ContentType: tc.ContentType})
+			if got != tc.Expected {
+				t.Errorf("contentType() = %s, expected %s", got, tc.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package graphqlapi
+
+import "errors"
+
+var (
+	ErrNotAuthorized = errors.New("not authorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrBadRequest    = errors.New("bad request")
+)
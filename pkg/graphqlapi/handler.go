@@ -0,0 +1,118 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/treeverse/lakefs/pkg/accesslog"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	// BasePath is the URI clients send GraphQL queries to
+	BasePath = "/graphql"
+
+	LoggerServiceName = "graphql"
+
+	contentTypeJSON = "application/json"
+
+	// maxRequestSize bounds the size of a query request body
+	maxRequestSize = 1 << 20
+)
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewHandler serves read-only GraphQL queries over repositories, refs, commits, objects and action runs.
+// Requests are authenticated by the lakeFS authentication middleware, and every resolved field is authorized
+// using the same permissions as the matching API operation.
+func NewHandler(c *catalog.Catalog, authService auth.Service, actionsService actions.Service, auditLogLevel string, traceRequestHeaders bool) (http.Handler, error) {
+	schema, err := newSchema(&resolver{
+		catalog:     c,
+		authService: authService,
+		actions:     actionsService,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := auth.GetUser(r.Context()); err != nil {
+			writeError(w, r, http.StatusUnauthorized, ErrNotAuthorized)
+			return
+		}
+		req, err := parseRequest(r)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        r.Context(),
+		})
+		writeJSON(w, r, http.StatusOK, result)
+	})
+	loggingMiddleware := httputil.LoggingMiddleware(
+		httputil.RequestIDHeaderName,
+		logging.Fields{logging.ServiceNameFieldKey: LoggerServiceName},
+		auditLogLevel,
+		traceRequestHeaders)
+	return accesslog.Middleware(LoggerServiceName)(loggingMiddleware(h)), nil
+}
+
+// parseRequest reads a query from a POST JSON body, or from the query parameters of a GET request
+func parseRequest(r *http.Request) (*request, error) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if variables := q.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return nil, fmt.Errorf("%w: variables: %s", ErrBadRequest, err)
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestSize)).Decode(&req); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrBadRequest, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: method %s", ErrBadRequest, r.Method)
+	}
+	if req.Query == "" {
+		return nil, fmt.Errorf("%w: missing query", ErrBadRequest)
+	}
+	return &req, nil
+}
+
+type errorResponse struct {
+	Errors []errorMessage `json:"errors"`
+}
+
+type errorMessage struct {
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	writeJSON(w, r, status, errorResponse{Errors: []errorMessage{{Message: err.Error()}}})
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Debug("failed to write graphql response")
+	}
+}
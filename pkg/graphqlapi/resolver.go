@@ -0,0 +1,426 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/treeverse/lakefs/pkg/actions"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	pathTypeObject       = "object"
+	pathTypeCommonPrefix = "common_prefix"
+
+	runStatusCompleted = "completed"
+	runStatusFailed    = "failed"
+)
+
+// resolver resolves query fields from the catalog and the actions service. Views returned by the resolver
+// hold the repository name, used to resolve their nested fields.
+type resolver struct {
+	catalog     *catalog.Catalog
+	authService auth.Service
+	actions     actions.Service
+}
+
+type metadataEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type repositoryView struct {
+	Name             string    `json:"name"`
+	StorageNamespace string    `json:"storageNamespace"`
+	DefaultBranch    string    `json:"defaultBranch"`
+	CreationDate     time.Time `json:"creationDate"`
+	ReadOnly         bool      `json:"readOnly"`
+}
+
+type branchView struct {
+	repo     string
+	Name     string `json:"name"`
+	CommitID string `json:"commitId"`
+}
+
+type tagView struct {
+	repo     string
+	ID       string `json:"id"`
+	CommitID string `json:"commitId"`
+}
+
+type commitView struct {
+	repo         string
+	ID           string          `json:"id"`
+	Parents      []string        `json:"parents"`
+	Committer    string          `json:"committer"`
+	Message      string          `json:"message"`
+	CreationDate time.Time       `json:"creationDate"`
+	MetaRangeID  string          `json:"metaRangeId"`
+	Generation   int64           `json:"generation"`
+	Metadata     []metadataEntry `json:"metadata"`
+}
+
+type entryView struct {
+	Path            string          `json:"path"`
+	PathType        string          `json:"pathType"`
+	PhysicalAddress string          `json:"physicalAddress"`
+	Checksum        string          `json:"checksum"`
+	SizeBytes       int64           `json:"sizeBytes"`
+	Mtime           time.Time       `json:"mtime"`
+	ContentType     string          `json:"contentType"`
+	Metadata        []metadataEntry `json:"metadata"`
+}
+
+type runView struct {
+	repo      string
+	RunID     string    `json:"runId"`
+	Branch    string    `json:"branch"`
+	CommitID  string    `json:"commitId"`
+	SourceRef string    `json:"sourceRef"`
+	EventType string    `json:"eventType"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Status    string    `json:"status"`
+}
+
+type hookRunView struct {
+	HookRunID  string    `json:"hookRunId"`
+	HookID     string    `json:"hookId"`
+	ActionName string    `json:"actionName"`
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+	Status     string    `json:"status"`
+}
+
+func metadataEntries(m map[string]string) []metadataEntry {
+	entries := make([]metadataEntry, 0, len(m))
+	for k, v := range m {
+		entries = append(entries, metadataEntry{Key: k, Value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+func newRepositoryView(repo *catalog.Repository) *repositoryView {
+	return &repositoryView{
+		Name:             repo.Name,
+		StorageNamespace: repo.StorageNamespace,
+		DefaultBranch:    repo.DefaultBranch,
+		CreationDate:     repo.CreationDate,
+		ReadOnly:         repo.ReadOnly,
+	}
+}
+
+func newCommitView(repo string, commit *catalog.CommitLog) *commitView {
+	return &commitView{
+		repo:         repo,
+		ID:           commit.Reference,
+		Parents:      commit.Parents,
+		Committer:    commit.Committer,
+		Message:      commit.Message,
+		CreationDate: commit.CreationDate,
+		MetaRangeID:  commit.MetaRangeID,
+		Generation:   int64(commit.Generation),
+		Metadata:     metadataEntries(commit.Metadata),
+	}
+}
+
+func newEntryView(entry *catalog.DBEntry) *entryView {
+	if entry.CommonLevel {
+		return &entryView{Path: entry.Path, PathType: pathTypeCommonPrefix}
+	}
+	return &entryView{
+		Path:            entry.Path,
+		PathType:        pathTypeObject,
+		PhysicalAddress: entry.PhysicalAddress,
+		Checksum:        entry.Checksum,
+		SizeBytes:       entry.Size,
+		Mtime:           entry.CreationDate,
+		ContentType:     catalog.ContentTypeOrDefault(entry.ContentType),
+		Metadata:        metadataEntries(entry.Metadata),
+	}
+}
+
+func runStatus(passed bool) string {
+	if passed {
+		return runStatusCompleted
+	}
+	return runStatusFailed
+}
+
+func stringArgValue(p graphql.ResolveParams, name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+// pageSize returns the "first" argument, bounded by maxPageSize
+func pageSize(p graphql.ResolveParams) int {
+	first, ok := p.Args["first"].(int)
+	if !ok || first <= 0 {
+		return defaultPageSize
+	}
+	return min(first, maxPageSize)
+}
+
+// authorize checks that the authenticated user holds the permission to perform action on resource
+func (r *resolver) authorize(ctx context.Context, action, resource string) error {
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return ErrNotAuthorized
+	}
+	resp, err := r.authService.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: user.Username,
+		RequiredPermissions: permissions.Node{
+			Permission: permissions.Permission{Action: action, Resource: resource},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil || !resp.Allowed {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// notFoundAsNil resolves fields of missing resources to null, other errors are reported
+func notFoundAsNil(err error) (interface{}, error) {
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, nil
+	}
+	return nil, err
+}
+
+func (r *resolver) repositories(p graphql.ResolveParams) (interface{}, error) {
+	if err := r.authorize(p.Context, permissions.ListRepositoriesAction, permissions.All); err != nil {
+		return nil, err
+	}
+	repos, _, err := r.catalog.ListRepositories(p.Context, pageSize(p), stringArgValue(p, "prefix"), stringArgValue(p, "after"))
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*repositoryView, 0, len(repos))
+	for _, repo := range repos {
+		views = append(views, newRepositoryView(repo))
+	}
+	return views, nil
+}
+
+func (r *resolver) repository(p graphql.ResolveParams) (interface{}, error) {
+	name := stringArgValue(p, "name")
+	if err := r.authorize(p.Context, permissions.ReadRepositoryAction, permissions.RepoArn(name)); err != nil {
+		return nil, err
+	}
+	repo, err := r.catalog.GetRepository(p.Context, name)
+	if err != nil {
+		return notFoundAsNil(err)
+	}
+	return newRepositoryView(repo), nil
+}
+
+func (r *resolver) branches(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	if err := r.authorize(p.Context, permissions.ListBranchesAction, permissions.RepoArn(repo.Name)); err != nil {
+		return nil, err
+	}
+	branches, _, err := r.catalog.ListBranches(p.Context, repo.Name, stringArgValue(p, "prefix"), pageSize(p), stringArgValue(p, "after"))
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*branchView, 0, len(branches))
+	for _, branch := range branches {
+		views = append(views, &branchView{repo: repo.Name, Name: branch.Name, CommitID: branch.Reference})
+	}
+	return views, nil
+}
+
+func (r *resolver) branch(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	name := stringArgValue(p, "name")
+	if err := r.authorize(p.Context, permissions.ReadBranchAction, permissions.BranchArn(repo.Name, name)); err != nil {
+		return nil, err
+	}
+	commitID, err := r.catalog.GetBranchReference(p.Context, repo.Name, name)
+	if err != nil {
+		return notFoundAsNil(err)
+	}
+	return &branchView{repo: repo.Name, Name: name, CommitID: commitID}, nil
+}
+
+func (r *resolver) tags(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	if err := r.authorize(p.Context, permissions.ListTagsAction, permissions.RepoArn(repo.Name)); err != nil {
+		return nil, err
+	}
+	tags, _, err := r.catalog.ListTags(p.Context, repo.Name, stringArgValue(p, "prefix"), pageSize(p), stringArgValue(p, "after"))
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*tagView, 0, len(tags))
+	for _, tag := range tags {
+		views = append(views, &tagView{repo: repo.Name, ID: tag.ID, CommitID: tag.CommitID})
+	}
+	return views, nil
+}
+
+func (r *resolver) getCommit(ctx context.Context, repo, ref string) (interface{}, error) {
+	if err := r.authorize(ctx, permissions.ReadCommitAction, permissions.RepoArn(repo)); err != nil {
+		return nil, err
+	}
+	commit, err := r.catalog.GetCommit(ctx, repo, ref)
+	if err != nil {
+		return notFoundAsNil(err)
+	}
+	return newCommitView(repo, commit), nil
+}
+
+func (r *resolver) commit(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	return r.getCommit(p.Context, repo.Name, stringArgValue(p, "ref"))
+}
+
+func (r *resolver) branchHead(p graphql.ResolveParams) (interface{}, error) {
+	branch := p.Source.(*branchView)
+	return r.getCommit(p.Context, branch.repo, branch.CommitID)
+}
+
+func (r *resolver) tagCommit(p graphql.ResolveParams) (interface{}, error) {
+	tag := p.Source.(*tagView)
+	return r.getCommit(p.Context, tag.repo, tag.CommitID)
+}
+
+func (r *resolver) log(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	ref := stringArgValue(p, "ref")
+	if err := r.authorize(p.Context, permissions.ReadBranchAction, permissions.BranchArn(repo.Name, ref)); err != nil {
+		return nil, err
+	}
+	commits, _, err := r.catalog.ListCommits(p.Context, repo.Name, ref, catalog.LogParams{
+		FromReference: stringArgValue(p, "after"),
+		Amount:        pageSize(p),
+	})
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*commitView, 0, len(commits))
+	for _, commit := range commits {
+		views = append(views, newCommitView(repo.Name, commit))
+	}
+	return views, nil
+}
+
+func (r *resolver) objects(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	if err := r.authorize(p.Context, permissions.ListObjectsAction, permissions.RepoArn(repo.Name)); err != nil {
+		return nil, err
+	}
+	entries, _, err := r.catalog.ListEntries(p.Context, repo.Name, stringArgValue(p, "ref"), stringArgValue(p, "prefix"), stringArgValue(p, "after"), stringArgValue(p, "delimiter"), pageSize(p))
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*entryView, 0, len(entries))
+	for _, entry := range entries {
+		views = append(views, newEntryView(entry))
+	}
+	return views, nil
+}
+
+func (r *resolver) object(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	path := stringArgValue(p, "path")
+	if err := r.authorize(p.Context, permissions.ReadObjectAction, permissions.ObjectArn(repo.Name, path)); err != nil {
+		return nil, err
+	}
+	entry, err := r.catalog.GetEntry(p.Context, repo.Name, stringArgValue(p, "ref"), path, catalog.GetEntryParams{})
+	if err != nil {
+		return notFoundAsNil(err)
+	}
+	// an alias resolves to the object it points to, which must be readable as well
+	if entry.AliasTarget != "" {
+		if err := r.authorize(p.Context, permissions.ReadObjectAction, permissions.ObjectArn(repo.Name, entry.AliasTarget)); err != nil {
+			return nil, err
+		}
+	}
+	return newEntryView(entry), nil
+}
+
+// listRuns returns up to limit action runs of the repository, optionally filtered by branch or commit
+func (r *resolver) listRuns(ctx context.Context, repo, branch, commitID, after string, limit int) ([]*runView, error) {
+	if err := r.authorize(ctx, permissions.ReadActionsAction, permissions.RepoArn(repo)); err != nil {
+		return nil, err
+	}
+	it, err := r.actions.ListRunResults(ctx, repo, branch, commitID, after)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var views []*runView
+	for len(views) < limit && it.Next() {
+		run := it.Value()
+		views = append(views, &runView{
+			repo:      repo,
+			RunID:     run.RunID,
+			Branch:    run.BranchID,
+			CommitID:  run.CommitID,
+			SourceRef: run.SourceRef,
+			EventType: run.EventType,
+			StartTime: run.StartTime,
+			EndTime:   run.EndTime,
+			Status:    runStatus(run.Passed),
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (r *resolver) runs(p graphql.ResolveParams) (interface{}, error) {
+	repo := p.Source.(*repositoryView)
+	return r.listRuns(p.Context, repo.Name, stringArgValue(p, "branch"), stringArgValue(p, "commitId"), stringArgValue(p, "after"), pageSize(p))
+}
+
+func (r *resolver) branchRuns(p graphql.ResolveParams) (interface{}, error) {
+	branch := p.Source.(*branchView)
+	return r.listRuns(p.Context, branch.repo, branch.Name, "", stringArgValue(p, "after"), pageSize(p))
+}
+
+func (r *resolver) commitRuns(p graphql.ResolveParams) (interface{}, error) {
+	commit := p.Source.(*commitView)
+	return r.listRuns(p.Context, commit.repo, "", commit.ID, stringArgValue(p, "after"), pageSize(p))
+}
+
+func (r *resolver) hookRuns(p graphql.ResolveParams) (interface{}, error) {
+	run := p.Source.(*runView)
+	if err := r.authorize(p.Context, permissions.ReadActionsAction, permissions.RepoArn(run.repo)); err != nil {
+		return nil, err
+	}
+	it, err := r.actions.ListRunTaskResults(p.Context, run.repo, run.RunID, "")
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var views []*hookRunView
+	for it.Next() {
+		task := it.Value()
+		views = append(views, &hookRunView{
+			HookRunID:  task.HookRunID,
+			HookID:     task.HookID,
+			ActionName: task.ActionName,
+			StartTime:  task.StartTime,
+			EndTime:    task.EndTime,
+			Status:     runStatus(task.Passed),
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
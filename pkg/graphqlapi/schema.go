@@ -0,0 +1,239 @@
+package graphqlapi
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	// defaultPageSize is the number of list items returned unless requested otherwise
+	defaultPageSize = 100
+	// maxPageSize bounds the number of list items a query can request on each list field
+	maxPageSize = 1000
+)
+
+// longType is an output scalar for 64-bit integers (object sizes, commit generations), which do not fit the
+// 32-bit GraphQL Int
+var longType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "64-bit integer",
+	Serialize: func(value interface{}) interface{} {
+		return value
+	},
+})
+
+// pageArgs returns the pagination arguments of a list field, along with the field specific arguments
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	paged := graphql.FieldConfigArgument{
+		"after": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "return items after this one",
+		},
+		"first": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: defaultPageSize,
+			Description:  "maximal number of items to return",
+		},
+	}
+	for name, arg := range args {
+		paged[name] = arg
+	}
+	return paged
+}
+
+func stringArg(description string) *graphql.ArgumentConfig {
+	return &graphql.ArgumentConfig{Type: graphql.String, Description: description}
+}
+
+func requiredStringArg(description string) *graphql.ArgumentConfig {
+	return &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String), Description: description}
+}
+
+func newSchema(r *resolver) (graphql.Schema, error) {
+	nonNullString := graphql.NewNonNull(graphql.String)
+
+	metadataType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MetadataEntry",
+		Fields: graphql.Fields{
+			"key":   &graphql.Field{Type: nonNullString},
+			"value": &graphql.Field{Type: nonNullString},
+		},
+	})
+
+	hookRunType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HookRun",
+		Fields: graphql.Fields{
+			"hookRunId":  &graphql.Field{Type: nonNullString},
+			"hookId":     &graphql.Field{Type: graphql.String},
+			"actionName": &graphql.Field{Type: graphql.String},
+			"startTime":  &graphql.Field{Type: graphql.DateTime},
+			"endTime":    &graphql.Field{Type: graphql.DateTime},
+			"status":     &graphql.Field{Type: graphql.String, Description: "completed or failed"},
+		},
+	})
+
+	actionRunType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ActionRun",
+		Fields: graphql.Fields{
+			"runId":     &graphql.Field{Type: nonNullString},
+			"branch":    &graphql.Field{Type: graphql.String},
+			"commitId":  &graphql.Field{Type: graphql.String},
+			"sourceRef": &graphql.Field{Type: graphql.String},
+			"eventType": &graphql.Field{Type: graphql.String},
+			"startTime": &graphql.Field{Type: graphql.DateTime},
+			"endTime":   &graphql.Field{Type: graphql.DateTime},
+			"status":    &graphql.Field{Type: graphql.String, Description: "completed or failed"},
+			"hooks": &graphql.Field{
+				Type:        graphql.NewList(hookRunType),
+				Description: "hooks executed by the run",
+				Resolve:     r.hookRuns,
+			},
+		},
+	})
+
+	entryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Entry",
+		Fields: graphql.Fields{
+			"path":            &graphql.Field{Type: nonNullString},
+			"pathType":        &graphql.Field{Type: nonNullString, Description: "object or common_prefix"},
+			"physicalAddress": &graphql.Field{Type: graphql.String},
+			"checksum":        &graphql.Field{Type: graphql.String},
+			"sizeBytes":       &graphql.Field{Type: longType},
+			"mtime":           &graphql.Field{Type: graphql.DateTime},
+			"contentType":     &graphql.Field{Type: graphql.String},
+			"metadata":        &graphql.Field{Type: graphql.NewList(metadataType)},
+		},
+	})
+
+	commitType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Commit",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: nonNullString},
+			"parents":      &graphql.Field{Type: graphql.NewList(graphql.String)},
+			"committer":    &graphql.Field{Type: graphql.String},
+			"message":      &graphql.Field{Type: graphql.String},
+			"creationDate": &graphql.Field{Type: graphql.DateTime},
+			"metaRangeId":  &graphql.Field{Type: graphql.String},
+			"generation":   &graphql.Field{Type: longType},
+			"metadata":     &graphql.Field{Type: graphql.NewList(metadataType)},
+			"runs": &graphql.Field{
+				Type:        graphql.NewList(actionRunType),
+				Description: "action runs of the commit",
+				Args:        pageArgs(nil),
+				Resolve:     r.commitRuns,
+			},
+		},
+	})
+
+	branchType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Branch",
+		Fields: graphql.Fields{
+			"name":     &graphql.Field{Type: nonNullString},
+			"commitId": &graphql.Field{Type: nonNullString},
+			"head": &graphql.Field{
+				Type:        commitType,
+				Description: "the commit the branch points to",
+				Resolve:     r.branchHead,
+			},
+			"runs": &graphql.Field{
+				Type:        graphql.NewList(actionRunType),
+				Description: "action runs of the branch, most recent first",
+				Args:        pageArgs(nil),
+				Resolve:     r.branchRuns,
+			},
+		},
+	})
+
+	tagType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tag",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: nonNullString},
+			"commitId": &graphql.Field{Type: nonNullString},
+			"commit": &graphql.Field{
+				Type:        commitType,
+				Description: "the tagged commit",
+				Resolve:     r.tagCommit,
+			},
+		},
+	})
+
+	repositoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Repository",
+		Fields: graphql.Fields{
+			"name":             &graphql.Field{Type: nonNullString},
+			"storageNamespace": &graphql.Field{Type: graphql.String},
+			"defaultBranch":    &graphql.Field{Type: graphql.String},
+			"creationDate":     &graphql.Field{Type: graphql.DateTime},
+			"readOnly":         &graphql.Field{Type: graphql.Boolean},
+			"branches": &graphql.Field{
+				Type:    graphql.NewList(branchType),
+				Args:    pageArgs(graphql.FieldConfigArgument{"prefix": stringArg("return branches starting with this prefix")}),
+				Resolve: r.branches,
+			},
+			"branch": &graphql.Field{
+				Type:    branchType,
+				Args:    graphql.FieldConfigArgument{"name": requiredStringArg("branch name")},
+				Resolve: r.branch,
+			},
+			"tags": &graphql.Field{
+				Type:    graphql.NewList(tagType),
+				Args:    pageArgs(graphql.FieldConfigArgument{"prefix": stringArg("return tags starting with this prefix")}),
+				Resolve: r.tags,
+			},
+			"commit": &graphql.Field{
+				Type:    commitType,
+				Args:    graphql.FieldConfigArgument{"ref": requiredStringArg("branch, tag or commit ID")},
+				Resolve: r.commit,
+			},
+			"log": &graphql.Field{
+				Type:        graphql.NewList(commitType),
+				Description: "commits reachable from the ref",
+				Args:        pageArgs(graphql.FieldConfigArgument{"ref": requiredStringArg("branch, tag or commit ID")}),
+				Resolve:     r.log,
+			},
+			"objects": &graphql.Field{
+				Type: graphql.NewList(entryType),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"ref":       requiredStringArg("branch, tag or commit ID"),
+					"prefix":    stringArg("return objects starting with this prefix"),
+					"delimiter": stringArg("group objects sharing a prefix up to the delimiter into common prefixes"),
+				}),
+				Resolve: r.objects,
+			},
+			"object": &graphql.Field{
+				Type: entryType,
+				Args: graphql.FieldConfigArgument{
+					"ref":  requiredStringArg("branch, tag or commit ID"),
+					"path": requiredStringArg("object path"),
+				},
+				Resolve: r.object,
+			},
+			"runs": &graphql.Field{
+				Type:        graphql.NewList(actionRunType),
+				Description: "action runs of the repository, most recent first",
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"branch":   stringArg("return runs of this branch"),
+					"commitId": stringArg("return runs of this commit"),
+				}),
+				Resolve: r.runs,
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"repositories": &graphql.Field{
+				Type:    graphql.NewList(repositoryType),
+				Args:    pageArgs(graphql.FieldConfigArgument{"prefix": stringArg("return repositories starting with this prefix")}),
+				Resolve: r.repositories,
+			},
+			"repository": &graphql.Field{
+				Type:    repositoryType,
+				Args:    graphql.FieldConfigArgument{"name": requiredStringArg("repository name")},
+				Resolve: r.repository,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
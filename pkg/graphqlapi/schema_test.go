@@ -0,0 +1,48 @@
+package graphqlapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestSchema(t *testing.T) {
+	schema, err := newSchema(&resolver{})
+	if err != nil {
+		t.Fatalf("newSchema() failed: %s", err)
+	}
+
+	t.Run("introspection", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ __type(name: "Repository") { fields { name } } }`,
+			Context:       context.Background(),
+		})
+		if result.HasErrors() {
+			t.Fatalf("introspection query failed: %v", result.Errors)
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ repositories { name branches { name head { id } } } }`,
+			Context:       context.Background(),
+		})
+		if len(result.Errors) != 1 || result.Errors[0].Message != ErrNotAuthorized.Error() {
+			t.Fatalf("query errors = %v, expected %s", result.Errors, ErrNotAuthorized)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ repository { name } }`,
+			Context:       context.Background(),
+		})
+		if !result.HasErrors() {
+			t.Fatal("expected missing required argument error")
+		}
+	})
+}
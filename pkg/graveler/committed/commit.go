@@ -5,10 +5,20 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/logging"
 )
 
+var commitRangesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "graveler_commit_ranges_total",
+		Help: "Number of base ranges copied as-is (reused) or rewritten by commits.",
+	},
+	[]string{"type"},
+)
+
 type CommitOptions struct {
 	// Set to allow commits that change nothing (otherwise ErrNoChanges)
 	AllowEmpty bool
@@ -24,6 +34,89 @@ type committer struct {
 	opts                  *CommitOptions
 	summary               graveler.DiffSummary
 	haveChanges, haveBase bool
+
+	// enteredRange is the base range currently scanned record by record.  Until a change
+	// applies to it, its records are kept in pending and the range is reused as-is when
+	// leaving it.
+	enteredRange *Range
+	pending      []graveler.ValueRecord
+	rangeChanged bool
+	// reusedRanges and rewrittenRanges count base ranges copied as-is and rewritten
+	reusedRanges, rewrittenRanges int
+}
+
+// copyRange writes an entire base range to writer
+func (a *committer) copyRange(rng *Range) error {
+	if err := a.writer.WriteRange(*rng); err != nil {
+		return fmt.Errorf("copy base range %s: %w", rng.ID, err)
+	}
+	a.reusedRanges++
+	return nil
+}
+
+func (a *committer) enterRange(rng *Range) {
+	entered := *rng
+	a.enteredRange = &entered
+	a.pending = a.pending[:0]
+	a.rangeChanged = false
+}
+
+// leaveRange is called once done scanning the entered range.  If no change applied to it,
+// it is copied as-is instead of its records.
+func (a *committer) leaveRange() error {
+	if a.enteredRange == nil {
+		return nil
+	}
+	rng := a.enteredRange
+	a.enteredRange = nil
+	if a.rangeChanged {
+		a.rewrittenRanges++
+		return nil
+	}
+	if a.logger.IsTracing() {
+		a.logger.WithFields(logging.Fields{
+			"from": string(rng.MinKey),
+			"to":   string(rng.MaxKey),
+			"ID":   rng.ID,
+		}).Trace("copy unchanged entered range")
+	}
+	a.pending = a.pending[:0]
+	return a.copyRange(rng)
+}
+
+// markRangeChanged is called before applying a change: the records kept for the entered
+// range are written and it will be rewritten.
+func (a *committer) markRangeChanged() error {
+	if a.enteredRange == nil || a.rangeChanged {
+		return nil
+	}
+	a.rangeChanged = true
+	for _, record := range a.pending {
+		if err := a.writer.WriteRecord(record); err != nil {
+			return fmt.Errorf("write record: %w", err)
+		}
+	}
+	a.pending = a.pending[:0]
+	return nil
+}
+
+// writeBaseRecord writes a record from base, keeping it while the entered range is unchanged
+func (a *committer) writeBaseRecord(record *graveler.ValueRecord) error {
+	if a.enteredRange != nil && !a.rangeChanged {
+		// base iterator may reuse its buffers, keep a copy
+		a.pending = append(a.pending, graveler.ValueRecord{
+			Key: record.Key.Copy(),
+			Value: &graveler.Value{
+				Identity: bytes.Clone(record.Identity),
+				Data:     bytes.Clone(record.Data),
+			},
+		})
+		return nil
+	}
+	if err := a.writer.WriteRecord(*record); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return nil
 }
 
 // applyAllBase writes all remaining changes from Base Iterator to writer
@@ -35,7 +128,17 @@ func (a *committer) applyAllBase(iter Iterator) error {
 		default:
 		}
 		iterValue, iterRange := iter.Value()
-		if iterValue == nil {
+		if iterValue == nil || (a.enteredRange != nil && !a.rangeChanged) {
+			// done with the entered range, an unchanged one is copied entirely
+			if err := a.leaveRange(); err != nil {
+				return err
+			}
+			if iterValue != nil {
+				if !iter.NextRange() {
+					break
+				}
+				continue
+			}
 			if a.logger.IsTracing() {
 				a.logger.WithFields(logging.Fields{
 					"from": string(iterRange.MinKey),
@@ -43,8 +146,8 @@ func (a *committer) applyAllBase(iter Iterator) error {
 					"ID":   iterRange.ID,
 				}).Trace("copy entire range at end")
 			}
-			if err := a.writer.WriteRange(*iterRange); err != nil {
-				return fmt.Errorf("copy iter range %s: %w", iterRange.ID, err)
+			if err := a.copyRange(iterRange); err != nil {
+				return err
 			}
 			if !iter.NextRange() {
 				break
@@ -125,19 +228,23 @@ func (a *committer) applyBaseRange(baseRange *Range, changeValue *graveler.Value
 			}).Trace("copy entire base range")
 		}
 
-		if err := a.writer.WriteRange(*baseRange); err != nil {
-			return fmt.Errorf("copy base range %s: %w", baseRange.ID, err)
+		if err := a.copyRange(baseRange); err != nil {
+			return err
 		}
 		a.haveBase = a.base.NextRange()
 	} else {
 		// Base is at start of range which we need to scan, enter it.
+		a.enterRange(baseRange)
 		a.haveBase = a.base.Next()
 	}
 	return nil
 }
 
 func (a *committer) applyNextKey(baseValue *graveler.ValueRecord, changeValue *graveler.ValueRecord) error {
-	var writeRecord *graveler.ValueRecord
+	var (
+		writeRecord *graveler.ValueRecord
+		changed     bool
+	)
 
 	compare := bytes.Compare(baseValue.Key, changeValue.Key)
 	switch {
@@ -149,6 +256,7 @@ func (a *committer) applyNextKey(baseValue *graveler.ValueRecord, changeValue *g
 		if compare == 0 {
 			// key is equal - report as deleted
 			a.incrementDiffSummary(graveler.DiffTypeRemoved)
+			changed = true
 		}
 	case compare == 0:
 		// base key is equal, no tombstone - handle change
@@ -158,11 +266,19 @@ func (a *committer) applyNextKey(baseValue *graveler.ValueRecord, changeValue *g
 		} else {
 			a.incrementDiffSummary(graveler.DiffTypeChanged)
 			writeRecord = changeValue
+			changed = true
 		}
 	default:
 		// base key is bigger, no tombstone - handle new key
 		a.incrementDiffSummary(graveler.DiffTypeAdded)
 		writeRecord = changeValue
+		changed = true
+	}
+
+	if changed {
+		if err := a.markRangeChanged(); err != nil {
+			return err
+		}
 	}
 
 	// Write record if needed
@@ -173,8 +289,17 @@ func (a *committer) applyNextKey(baseValue *graveler.ValueRecord, changeValue *g
 				"identity": string(writeRecord.Identity),
 			}).Trace("write record")
 		}
-		if err := a.writer.WriteRecord(*writeRecord); err != nil {
-			return fmt.Errorf("write record: %w", err)
+		var err error
+		if changed {
+			err = a.writer.WriteRecord(*writeRecord)
+			if err != nil {
+				err = fmt.Errorf("write record: %w", err)
+			}
+		} else {
+			err = a.writeBaseRecord(writeRecord)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
@@ -202,6 +327,10 @@ func (a *committer) commit() error {
 		changeValue := a.changes.Value()
 		var err error
 		if baseValue == nil {
+			// reached the next range, done with the entered one
+			if err := a.leaveRange(); err != nil {
+				return err
+			}
 			err = a.applyBaseRange(baseRange, changeValue)
 		} else {
 			err = a.applyNextKey(baseValue, changeValue)
@@ -221,6 +350,9 @@ func (a *committer) commit() error {
 			return err
 		}
 	}
+	if err := a.leaveRange(); err != nil {
+		return err
+	}
 
 	if a.haveChanges {
 		numAdded, err := a.applyAllChanges(a.changes)
@@ -248,5 +380,12 @@ func Commit(ctx context.Context, writer MetaRangeWriter, base Iterator, changes
 		opts:    opts,
 		summary: graveler.DiffSummary{Count: make(map[graveler.DiffType]int)},
 	}
-	return c.summary, c.commit()
+	err := c.commit()
+	commitRangesCounter.WithLabelValues("reused").Add(float64(c.reusedRanges))
+	commitRangesCounter.WithLabelValues("rewritten").Add(float64(c.rewrittenRanges))
+	c.logger.WithFields(logging.Fields{
+		"reused_ranges":    c.reusedRanges,
+		"rewritten_ranges": c.rewrittenRanges,
+	}).Debug("Commit ranges")
+	return c.summary, err
 }
@@ -166,12 +166,11 @@ func TestCommitOverrideNoChange(t *testing.T) {
 		{Key: graveler.Key("e"), Value: &graveler.Value{Identity: []byte("base:e"), Data: []byte("new")}},
 	})
 
+	// ranges with no effective change are copied as-is
 	writer := mock.NewMockMetaRangeWriter(ctrl)
-	writer.EXPECT().WriteRecord(gomock.Eq(*makeV("a", "base:a")))
-	writer.EXPECT().WriteRecord(gomock.Eq(graveler.ValueRecord{Key: graveler.Key("b"), Value: &graveler.Value{Identity: []byte("base:b"), Data: []byte("base")}}))
-	writer.EXPECT().WriteRecord(gomock.Eq(*makeV("c", "base:c")))
+	writer.EXPECT().WriteRange(gomock.Eq(committed.Range{ID: "base:one", MinKey: committed.Key("a"), MaxKey: committed.Key("cz"), Count: 3}))
 	writer.EXPECT().WriteRange(gomock.Eq(committed.Range{ID: "two", MinKey: committed.Key("d"), MaxKey: committed.Key("dz"), Count: 1}))
-	writer.EXPECT().WriteRecord(gomock.Eq(graveler.ValueRecord{Key: graveler.Key("e"), Value: &graveler.Value{Identity: []byte("base:e"), Data: []byte("base")}}))
+	writer.EXPECT().WriteRange(gomock.Eq(committed.Range{ID: "three", MinKey: committed.Key("e"), MaxKey: committed.Key("ez"), Count: 1}))
 
 	summary, err := committed.Commit(context.Background(), writer, base, changes, &committed.CommitOptions{})
 	assert.Error(t, err, graveler.ErrNoChanges)
@@ -300,8 +299,7 @@ func TestCommitTombstonesBeforeRange(t *testing.T) {
 	})
 
 	writer := mock.NewMockMetaRangeWriter(ctrl)
-	writer.EXPECT().WriteRecord(gomock.Eq(*makeV("b", "base:b")))
-	writer.EXPECT().WriteRecord(gomock.Eq(*makeV("c", "base:c")))
+	writer.EXPECT().WriteRange(gomock.Eq(committed.Range{ID: "base:range", MinKey: committed.Key("b"), MaxKey: committed.Key("c"), Count: 2}))
 	writer.EXPECT().WriteRecord(gomock.Eq(*makeV("d", "changes:d")))
 
 	summary, err := committed.Commit(context.Background(), writer, base, changes, &committed.CommitOptions{})
@@ -313,6 +311,43 @@ func TestCommitTombstonesBeforeRange(t *testing.T) {
 	}, summary)
 }
 
+func TestCommitReusesRangeWithoutChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	range1 := &committed.Range{ID: "one", MinKey: committed.Key("a"), MaxKey: committed.Key("d"), Count: 3}
+	range2 := &committed.Range{ID: "two", MinKey: committed.Key("e"), MaxKey: committed.Key("h"), Count: 3}
+	base := testutil.NewFakeIterator().
+		AddRange(range1).
+		AddValueRecords(makeV("a", "base:a"), makeV("c", "base:c"), makeV("d", "base:d")).
+		AddRange(range2).
+		AddValueRecords(makeV("e", "base:e"), makeV("f", "base:f"), makeV("h", "base:h"))
+
+	changes := testutil.NewValueIteratorFake([]graveler.ValueRecord{
+		*makeTombstoneV("b"),
+		*makeV("c", "base:c"),
+		*makeV("e", "base:e"),
+		*makeV("g", "changes:g"),
+	})
+
+	// range one only has no-op changes and is reused, range two is rewritten from its start
+	writer := mock.NewMockMetaRangeWriter(ctrl)
+	gomock.InOrder(
+		writer.EXPECT().WriteRange(gomock.Eq(*range1)),
+		writer.EXPECT().WriteRecord(gomock.Eq(*makeV("e", "base:e"))),
+		writer.EXPECT().WriteRecord(gomock.Eq(*makeV("f", "base:f"))),
+		writer.EXPECT().WriteRecord(gomock.Eq(*makeV("g", "changes:g"))),
+		writer.EXPECT().WriteRecord(gomock.Eq(*makeV("h", "base:h"))),
+	)
+
+	summary, err := committed.Commit(context.Background(), writer, base, changes, &committed.CommitOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, graveler.DiffSummary{
+		Count: map[graveler.DiffType]int{
+			graveler.DiffTypeAdded: 1,
+		},
+	}, summary)
+}
+
 func TestCommitCopiesLeftoverBase(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -371,7 +371,7 @@ func Test_import(t *testing.T) {
 				writer.EXPECT().Abort().AnyTimes()
 				metaRangeId := graveler.MetaRangeID("import")
 				writer.EXPECT().Close(gomock.Any()).Return(&metaRangeId, nil).AnyTimes()
-				committedManager := committed.NewCommittedManager(metaRangeManager, rangeManager, params)
+				committedManager := committed.NewCommittedManager(metaRangeManager, rangeManager, nil, params)
 				_, err := committedManager.Import(ctx, "ns", destMetaRangeID, sourceMetaRangeID, tst.prefixes)
 				if !errors.Is(err, expectedResult.expectedErr) {
 					t.Fatalf("Import error = '%v', expected '%v'", err, expectedResult.expectedErr)
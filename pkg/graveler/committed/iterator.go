@@ -8,6 +8,13 @@ import (
 	"github.com/treeverse/lakefs/pkg/graveler"
 )
 
+// RangePrefetcher is implemented by RangeManagers that can fetch Ranges before they are read.
+type RangePrefetcher interface {
+	// Prefetch starts fetching the Range with id in the background.  It does not wait for
+	// the fetch, which may be skipped if too many fetches are in progress.
+	Prefetch(ctx context.Context, ns Namespace, id ID)
+}
+
 type iterator struct {
 	ctx         context.Context
 	started     bool
@@ -18,6 +25,13 @@ type iterator struct {
 	err         error
 	namespace   Namespace
 	beforeRange bool
+
+	prefetcher RangePrefetcher
+	readAhead  int
+	// ahead holds ranges read from rangesIt after rng, their files are being prefetched
+	ahead []*Range
+	// aheadErr is the error reading ahead, returned once reaching it
+	aheadErr error
 }
 
 func NewIterator(ctx context.Context, manager RangeManager, namespace Namespace, rangesIt ValueIterator) Iterator {
@@ -29,6 +43,19 @@ func NewIterator(ctx context.Context, manager RangeManager, namespace Namespace,
 	}
 }
 
+// NewIteratorWithReadAhead returns an Iterator that prefetches the files of the next
+// readAhead Ranges while iterating.
+func NewIteratorWithReadAhead(ctx context.Context, manager RangeManager, namespace Namespace, rangesIt ValueIterator, prefetcher RangePrefetcher, readAhead int) Iterator {
+	return &iterator{
+		ctx:        ctx,
+		manager:    manager,
+		namespace:  namespace,
+		rangesIt:   rangesIt,
+		prefetcher: prefetcher,
+		readAhead:  readAhead,
+	}
+}
+
 // loadIt loads rvi.it to start iterating over a new range.  It returns false and sets rvi.err
 // if it fails to open the new range.
 func (rvi *iterator) loadIt() bool {
@@ -41,36 +68,78 @@ func (rvi *iterator) loadIt() bool {
 	return true
 }
 
-func (rvi *iterator) NextRange() bool {
-	if rvi.it != nil {
-		rvi.it.Close()
-	}
-	rvi.it = nil
-	rvi.rng = nil
-
+// readRange reads the next Range from rangesIt.  It returns nil at the end of rangesIt.
+func (rvi *iterator) readRange() (*Range, error) {
 	var rngRecord *Record
 	for rngRecord == nil { // Skip this and any consecutive finished ranges.
 		if !rvi.rangesIt.Next() {
-			return false
+			return nil, nil
 		}
 		rngRecord = rvi.rangesIt.Value()
 	}
 
 	gv, err := UnmarshalValue(rngRecord.Value)
 	if err != nil {
-		rvi.err = fmt.Errorf("unmarshal value for %s: %w", string(rngRecord.Key), err)
-		return false
+		return nil, fmt.Errorf("unmarshal value for %s: %w", string(rngRecord.Key), err)
 	}
 
 	rng, err := UnmarshalRange(gv.Data)
 	if err != nil {
-		rvi.err = fmt.Errorf("unmarshal %s: %w", string(rngRecord.Key), err)
-		return false
+		return nil, fmt.Errorf("unmarshal %s: %w", string(rngRecord.Key), err)
 	}
 
 	rng.ID = ID(gv.Identity)
-	rvi.rng = &rng
+	return &rng, nil
+}
 
+// fillReadAhead reads Ranges after rng until readAhead Ranges are prefetched
+func (rvi *iterator) fillReadAhead() {
+	for rvi.prefetcher != nil && rvi.aheadErr == nil && len(rvi.ahead) < rvi.readAhead {
+		rng, err := rvi.readRange()
+		if err != nil {
+			rvi.aheadErr = err
+			return
+		}
+		if rng == nil {
+			return
+		}
+		rvi.prefetcher.Prefetch(rvi.ctx, rvi.namespace, rng.ID)
+		rvi.ahead = append(rvi.ahead, rng)
+	}
+}
+
+// resetReadAhead drops Ranges read ahead, after moving rangesIt
+func (rvi *iterator) resetReadAhead() {
+	rvi.ahead = nil
+	rvi.aheadErr = nil
+}
+
+func (rvi *iterator) NextRange() bool {
+	if rvi.it != nil {
+		rvi.it.Close()
+	}
+	rvi.it = nil
+	rvi.rng = nil
+
+	if len(rvi.ahead) > 0 {
+		rvi.rng = rvi.ahead[0]
+		rvi.ahead = rvi.ahead[1:]
+	} else {
+		if rvi.aheadErr != nil {
+			rvi.err = rvi.aheadErr
+			return false
+		}
+		rng, err := rvi.readRange()
+		if err != nil {
+			rvi.err = err
+			return false
+		}
+		if rng == nil {
+			return false
+		}
+		rvi.rng = rng
+	}
+	rvi.fillReadAhead()
 	return true
 }
 
@@ -136,6 +205,7 @@ func (rvi *iterator) Close() {
 
 func (rvi *iterator) loadRange(key graveler.Key) bool {
 	rvi.rangesIt.SeekGE(Key(key))
+	rvi.resetReadAhead()
 	if err := rvi.rangesIt.Err(); err != nil {
 		rvi.err = err
 		return false
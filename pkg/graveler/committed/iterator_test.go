@@ -253,3 +253,78 @@ func TestIterator(t *testing.T) {
 		})
 	}
 }
+
+type fakePrefetcher struct {
+	ids []committed.ID
+}
+
+func (p *fakePrefetcher) Prefetch(_ context.Context, _ committed.Namespace, id committed.ID) {
+	p.ids = append(p.ids, id)
+}
+
+func TestIteratorReadAhead(t *testing.T) {
+	namespace := committed.Namespace("ns")
+	ranges := []rangeKeys{
+		{Name: "a2", Keys: makeKeys("a1", "a2")},
+		{Name: "b2", Keys: makeKeys("b1", "b2")},
+		{Name: "c2", Keys: makeKeys("c1", "c2")},
+		{Name: "d2", Keys: makeKeys("d1", "d2")},
+	}
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	manager := mock.NewMockRangeManager(ctrl)
+	for _, r := range ranges {
+		keys := r.Keys
+		manager.EXPECT().
+			NewRangeIterator(gomock.Any(), gomock.Eq(namespace), r.Name).
+			DoAndReturn(func(context.Context, committed.Namespace, committed.ID) (committed.ValueIterator, error) {
+				return makeRangeIterator(keys), nil
+			}).
+			AnyTimes()
+	}
+
+	t.Run("iterate", func(t *testing.T) {
+		prefetcher := &fakePrefetcher{}
+		rangesIt := testutil.NewCommittedValueIteratorFake(makeRangeRecords(ranges))
+		it := committed.NewIteratorWithReadAhead(ctx, manager, namespace, rangesIt, prefetcher, 2)
+		defer it.Close()
+
+		require.True(t, it.Next())
+		// the next 2 ranges are prefetched when starting the first range
+		require.Equal(t, []committed.ID{"b2", "c2"}, prefetcher.ids)
+		it.NextRange()
+		require.Equal(t, []committed.ID{"b2", "c2", "d2"}, prefetcher.ids)
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		rangesIt := testutil.NewCommittedValueIteratorFake(makeRangeRecords(ranges))
+		it := committed.NewIteratorWithReadAhead(ctx, manager, namespace, rangesIt, &fakePrefetcher{}, 2)
+		defer it.Close()
+		assert.Equal(t, ranges, keysByRanges(t, it))
+		assert.False(t, it.NextRange())
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		prefetcher := &fakePrefetcher{}
+		rangesIt := testutil.NewCommittedValueIteratorFake(makeRangeRecords(ranges))
+		it := committed.NewIteratorWithReadAhead(ctx, manager, namespace, rangesIt, prefetcher, 1)
+		defer it.Close()
+
+		require.True(t, it.Next())
+		// seeking outside the current range reads ahead from the new position
+		it.SeekGE(graveler.Key("c1"))
+		require.True(t, it.Next())
+		v, rng := it.Value()
+		require.Nil(t, v)
+		require.Equal(t, committed.ID("c2"), rng.ID)
+		require.True(t, it.Next())
+		v, _ = it.Value()
+		require.Equal(t, graveler.Key("c1"), v.Key)
+		require.Equal(t, []committed.ID{"b2", "d2"}, prefetcher.ids)
+		require.True(t, it.NextRange())
+		_, rng = it.Value()
+		require.Equal(t, committed.ID("d2"), rng.ID)
+		require.False(t, it.NextRange())
+	})
+}
@@ -0,0 +1,71 @@
+package committed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/cache"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+const keyFilterCacheExpiry = time.Hour
+
+// KeyFilter answers whether a key may exist in a MetaRange.  It may return false positives
+// but never false negatives.
+type KeyFilter interface {
+	MayContain(key graveler.Key) bool
+}
+
+// KeyFilterManager persists key filters of MetaRanges alongside their Ranges.
+type KeyFilterManager interface {
+	// GetKeyFilter returns the key filter stored for the MetaRange id, or ErrNotFound if
+	// none was written.
+	GetKeyFilter(ctx context.Context, ns Namespace, id ID) (KeyFilter, error)
+
+	// WriteKeyFilter builds a key filter of all keys of it and stores it for the MetaRange id.
+	WriteKeyFilter(ctx context.Context, ns Namespace, id ID, it graveler.ValueIterator) (KeyFilter, error)
+}
+
+type keyFilterCacheKey struct {
+	ns Namespace
+	id ID
+}
+
+// keyFilters caches key filters of MetaRanges, building missing filters on first use.
+// MetaRanges are immutable so a filter never needs to be rebuilt.
+type keyFilters struct {
+	manager KeyFilterManager
+	cache   cache.Cache
+}
+
+func newKeyFilters(manager KeyFilterManager, cacheSize int) *keyFilters {
+	return &keyFilters{
+		manager: manager,
+		cache:   cache.NewCache(cacheSize, keyFilterCacheExpiry, cache.NewJitterFn(keyFilterCacheExpiry/10)),
+	}
+}
+
+func (f *keyFilters) get(ctx context.Context, ns Namespace, id ID, list func() (graveler.ValueIterator, error)) (KeyFilter, error) {
+	v, err := f.cache.GetOrSet(keyFilterCacheKey{ns: ns, id: id}, func() (interface{}, error) {
+		filter, err := f.manager.GetKeyFilter(ctx, ns, id)
+		if !errors.Is(err, ErrNotFound) {
+			return filter, err
+		}
+		it, err := list()
+		if err != nil {
+			return nil, err
+		}
+		defer it.Close()
+		filter, err = f.manager.WriteKeyFilter(ctx, ns, id, it)
+		if err != nil {
+			return nil, fmt.Errorf("write key filter of metarange %s: %w", id, err)
+		}
+		return filter, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(KeyFilter), nil
+}
@@ -14,15 +14,22 @@ import (
 type committedManager struct {
 	metaRangeManager MetaRangeManager
 	RangeManager     RangeManager
+	keyFilters       *keyFilters
 	params           *Params
 }
 
-func NewCommittedManager(m MetaRangeManager, r RangeManager, p Params) graveler.CommittedManager {
-	return &committedManager{
+// NewCommittedManager returns a CommittedManager.  k may be nil, in which case MayContain
+// always reports that a key may exist.
+func NewCommittedManager(m MetaRangeManager, r RangeManager, k KeyFilterManager, p Params) graveler.CommittedManager {
+	c := &committedManager{
 		metaRangeManager: m,
 		RangeManager:     r,
 		params:           &p,
 	}
+	if k != nil {
+		c.keyFilters = newKeyFilters(k, p.KeyFilterCacheSize)
+	}
+	return c
 }
 
 func (c *committedManager) Exists(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) (bool, error) {
@@ -53,6 +60,19 @@ func (c *committedManager) Get(ctx context.Context, ns graveler.StorageNamespace
 	return rec.Value, nil
 }
 
+func (c *committedManager) MayContain(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID, key graveler.Key) (bool, error) {
+	if c.keyFilters == nil {
+		return true, nil
+	}
+	filter, err := c.keyFilters.get(ctx, Namespace(ns), ID(rangeID), func() (graveler.ValueIterator, error) {
+		return c.List(ctx, ns, rangeID)
+	})
+	if err != nil {
+		return false, err
+	}
+	return filter.MayContain(key), nil
+}
+
 func (c *committedManager) List(ctx context.Context, ns graveler.StorageNamespace, rangeID graveler.MetaRangeID) (graveler.ValueIterator, error) {
 	it, err := c.metaRangeManager.NewMetaRangeIterator(ctx, ns, rangeID)
 	if err != nil {
@@ -208,6 +228,10 @@ func (c *committedManager) Merge(ctx context.Context, ns graveler.StorageNamespa
 		return "", graveler.ErrNoChanges
 	}
 
+	if options.PathScope != "" {
+		return c.mergeScoped(ctx, ns, destination, source, base, strategy, options)
+	}
+
 	if destination == base {
 		// changes introduced only on source
 		return source, nil
@@ -220,6 +244,19 @@ func (c *committedManager) Merge(ctx context.Context, ns graveler.StorageNamespa
 		sourceID:      source,
 		baseID:        base,
 	}
+	if options.Progress != nil {
+		// progress counts the source and destination ranges merged, count them upfront to report the total
+		var total int64
+		for _, id := range []graveler.MetaRangeID{source, destination} {
+			count, err := c.countRanges(ctx, ns, id)
+			if err != nil {
+				return "", fmt.Errorf("count ranges of %s: %w", id, err)
+			}
+			total += count
+		}
+		mctx.progress = &rangeProgress{report: options.Progress, total: total}
+		mctx.progress.report(0, total)
+	}
 	return c.merge(ctx, mctx)
 }
 
@@ -232,6 +269,7 @@ type mergeContext struct {
 	destinationID graveler.MetaRangeID
 	sourceID      graveler.MetaRangeID
 	baseID        graveler.MetaRangeID
+	progress      *rangeProgress
 }
 
 func (c *committedManager) merge(ctx context.Context, mctx mergeContext) (graveler.MetaRangeID, error) {
@@ -271,6 +309,10 @@ func (c *committedManager) merge(ctx context.Context, mctx mergeContext) (gravel
 		}
 	}()
 
+	if mctx.progress != nil {
+		srcIt = newProgressIterator(srcIt, mctx.progress)
+		destIt = newProgressIterator(destIt, mctx.progress)
+	}
 	err = Merge(ctx, mwWriter, baseIt, srcIt, destIt, mctx.strategy)
 	if err != nil {
 		if !errors.Is(err, graveler.ErrUserVisible) {
@@ -278,6 +320,9 @@ func (c *committedManager) merge(ctx context.Context, mctx mergeContext) (gravel
 		}
 		return "", err
 	}
+	if mctx.progress != nil {
+		mctx.progress.done()
+	}
 	newID, err := mwWriter.Close(ctx)
 	if newID == nil {
 		return "", fmt.Errorf("close writer ns=%s id=%s: %w", mctx.ns, mctx.destinationID, err)
@@ -348,3 +393,52 @@ func (c *committedManager) GetRangeIDByKey(ctx context.Context, ns graveler.Stor
 	}
 	return graveler.RangeID(r.ID), nil
 }
+
+func (c *committedManager) ListRangeIDs(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) ([]graveler.RangeID, error) {
+	it, err := c.metaRangeManager.NewMetaRangeIterator(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var rangeIDs []graveler.RangeID
+	// only range headers are read, moving from one header to the next
+	for ok := it.Next(); ok; ok = it.NextRange() {
+		_, rng := it.Value()
+		rangeIDs = append(rangeIDs, graveler.RangeID(rng.ID))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return rangeIDs, nil
+}
+
+func (c *committedManager) ListRanges(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	it, err := c.metaRangeManager.NewMetaRangeIterator(ctx, ns, id)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var ranges []*graveler.RangeInfo
+	for ok := it.Next(); ok; ok = it.NextRange() {
+		_, rng := it.Value()
+		ranges = append(ranges, &graveler.RangeInfo{
+			ID:                      graveler.RangeID(rng.ID),
+			MinKey:                  graveler.Key(rng.MinKey.Copy()),
+			MaxKey:                  graveler.Key(rng.MaxKey.Copy()),
+			Count:                   int(rng.Count),
+			EstimatedRangeSizeBytes: rng.EstimatedSize,
+		})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+func (c *committedManager) ListRange(ctx context.Context, ns graveler.StorageNamespace, id graveler.RangeID) (graveler.ValueIterator, error) {
+	it, err := c.RangeManager.NewRangeIterator(ctx, Namespace(ns), ID(id))
+	if err != nil {
+		return nil, err
+	}
+	return NewUnmarshalIterator(it), nil
+}
@@ -58,7 +58,7 @@ func TestManager_WriteRange(t *testing.T) {
 			rangeWriter.EXPECT().Abort().Return(nil)
 			rangeManager.EXPECT().GetWriter(context.Background(), committed.Namespace(ns), nil).Return(rangeWriter, nil)
 
-			sut := committed.NewCommittedManager(metarangeManager, rangeManager, params)
+			sut := committed.NewCommittedManager(metarangeManager, rangeManager, nil, params)
 
 			times := 0
 			expectedTimes := _min(len(tt.records), maxRecords)
@@ -137,7 +137,7 @@ func TestManager_WriteMetaRange(t *testing.T) {
 				}).Times(len(tt.records))
 			metarangeWriter.EXPECT().Close(gomock.Any()).Return(&expectedMetarangeID, nil)
 			metarangeWriter.EXPECT().Abort().Return(nil)
-			sut := committed.NewCommittedManager(metarangeManager, rangeManager, params)
+			sut := committed.NewCommittedManager(metarangeManager, rangeManager, nil, params)
 
 			actualMetarangeID, err := sut.WriteMetaRange(context.Background(), ns, tt.records)
 			require.NoError(t, err)
@@ -0,0 +1,111 @@
+package committed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// scopedChangesIterator lists the changes to apply to the merge destination in order to merge the source changes
+// under a prefix, resolving conflicts by the merge strategy. Removed values are listed as tombstones.
+type scopedChangesIterator struct {
+	compareIt graveler.DiffIterator
+	prefix    graveler.Key
+	strategy  graveler.MergeStrategy
+	// sourceValue returns the value of key on the source, nil if it was removed there
+	sourceValue func(key graveler.Key) (*graveler.Value, error)
+	seeked      bool
+	value       *graveler.ValueRecord
+	err         error
+}
+
+func (s *scopedChangesIterator) Next() bool {
+	if !s.seeked {
+		s.compareIt.SeekGE(s.prefix)
+		s.seeked = true
+	}
+	s.value = nil
+	for s.compareIt.Next() {
+		d := s.compareIt.Value()
+		if !bytes.HasPrefix(d.Key, s.prefix) {
+			return false
+		}
+		record := &graveler.ValueRecord{Key: d.Key.Copy()}
+		switch d.Type {
+		case graveler.DiffTypeAdded, graveler.DiffTypeChanged:
+			record.Value = d.Value
+		case graveler.DiffTypeRemoved:
+			// a tombstone removes the key from the destination
+		case graveler.DiffTypeConflict:
+			switch s.strategy {
+			case graveler.MergeStrategyDest:
+				continue
+			case graveler.MergeStrategySrc:
+				record.Value, s.err = s.sourceValue(d.Key)
+				if s.err != nil {
+					return false
+				}
+			default:
+				s.err = graveler.ErrConflictFound
+				return false
+			}
+		default:
+			s.err = fmt.Errorf("%w: %d", graveler.ErrInvalidValue, d.Type)
+			return false
+		}
+		s.value = record
+		return true
+	}
+	s.err = s.compareIt.Err()
+	return false
+}
+
+func (s *scopedChangesIterator) SeekGE(id graveler.Key) {
+	s.compareIt.SeekGE(id)
+	s.seeked = true
+	s.value = nil
+	s.err = nil
+}
+
+func (s *scopedChangesIterator) Value() *graveler.ValueRecord {
+	return s.value
+}
+
+func (s *scopedChangesIterator) Err() error {
+	return s.err
+}
+
+func (s *scopedChangesIterator) Close() {
+	s.compareIt.Close()
+}
+
+// mergeScoped merges the changes of source under the path scope of options into destination, relative to base. It
+// is a filtered apply: the changes to merge source into destination are compared, and only those under the scope
+// are applied to destination.
+func (c *committedManager) mergeScoped(ctx context.Context, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID, strategy graveler.MergeStrategy, options *graveler.SetOptions) (graveler.MetaRangeID, error) {
+	compareIt, err := c.Compare(ctx, ns, destination, source, base)
+	if err != nil {
+		return "", err
+	}
+	changes := &scopedChangesIterator{
+		compareIt: compareIt,
+		prefix:    graveler.Key(options.PathScope),
+		strategy:  strategy,
+		sourceValue: func(key graveler.Key) (*graveler.Value, error) {
+			value, err := c.Get(ctx, ns, source, key)
+			if errors.Is(err, graveler.ErrNotFound) {
+				return nil, nil
+			}
+			return value, err
+		},
+	}
+	defer changes.Close()
+	metaRangeID, _, err := c.Commit(ctx, ns, destination, changes, options.AllowEmpty || options.Force)
+	if err != nil {
+		return "", err
+	}
+	return metaRangeID, nil
+}
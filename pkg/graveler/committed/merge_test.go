@@ -1728,7 +1728,7 @@ func runMergeTests(tests testCases, t *testing.T) {
 					writer.EXPECT().Abort().AnyTimes()
 					metaRangeId := graveler.MetaRangeID("merge")
 					writer.EXPECT().Close(gomock.Any()).Return(&metaRangeId, nil).AnyTimes()
-					committedManager := committed.NewCommittedManager(metaRangeManager, rangeManager, params)
+					committedManager := committed.NewCommittedManager(metaRangeManager, rangeManager, nil, params)
 					_, err := committedManager.Merge(ctx, "ns", destMetaRangeID, sourceMetaRangeID, baseMetaRangeID, mergeStrategy)
 					if !errors.Is(err, expectedResult.expectedErr) {
 						t.Fatalf("Merge error='%v', expected='%v'", err, expectedResult.expectedErr)
@@ -1805,3 +1805,119 @@ func TestMergeCancelContext(t *testing.T) {
 		assert.True(t, errors.Is(err, context.Canceled), "context canceled error")
 	})
 }
+
+func TestMergeProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	baseRange := newTestMetaRange([]testRange{
+		{rng: committed.Range{ID: "base:a-b", MinKey: committed.Key("a"), MaxKey: committed.Key("b"), Count: 2}, records: []testValueRecord{
+			{"a", "base:a"}, {"b", "base:b"},
+		}},
+	})
+	sourceRange := newTestMetaRange([]testRange{
+		{rng: committed.Range{ID: "base:a-b", MinKey: committed.Key("a"), MaxKey: committed.Key("b"), Count: 2}, records: []testValueRecord{
+			{"a", "base:a"}, {"b", "base:b"},
+		}},
+		{rng: committed.Range{ID: "source:d-e", MinKey: committed.Key("d"), MaxKey: committed.Key("e"), Count: 2}, records: []testValueRecord{
+			{"d", "source:d"}, {"e", "source:e"},
+		}},
+	})
+	destRange := newTestMetaRange([]testRange{
+		{rng: committed.Range{ID: "dest:a-c", MinKey: committed.Key("a"), MaxKey: committed.Key("c"), Count: 3}, records: []testValueRecord{
+			{"a", "base:a"}, {"b", "base:b"}, {"c", "dest:c"},
+		}},
+	})
+
+	writer := mock.NewMockMetaRangeWriter(ctrl)
+	writer.EXPECT().WriteRecord(gomock.Any()).AnyTimes()
+	writer.EXPECT().WriteRange(gomock.Any()).AnyTimes()
+	writer.EXPECT().Abort().AnyTimes()
+	metaRangeID := graveler.MetaRangeID("merge")
+	writer.EXPECT().Close(gomock.Any()).Return(&metaRangeID, nil)
+	metaRangeManager := mock.NewMockMetaRangeManager(ctrl)
+	metaRangeManager.EXPECT().NewWriter(gomock.Any(), gomock.Any(), gomock.Any()).Return(writer)
+	// ranges are counted before merging, so each metarange may be iterated twice
+	for _, tr := range []*testMetaRange{baseRange, sourceRange, destRange} {
+		tr := tr
+		metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), tr.GetMetaRangeID()).AnyTimes().
+			DoAndReturn(func(context.Context, graveler.StorageNamespace, graveler.MetaRangeID) (committed.Iterator, error) {
+				return createIter(tr), nil
+			})
+	}
+	committedManager := committed.NewCommittedManager(metaRangeManager, mock.NewMockRangeManager(ctrl), nil, params)
+
+	type report struct{ processed, total int64 }
+	var reports []report
+	_, err := committedManager.Merge(context.Background(), "ns", destRange.GetMetaRangeID(), sourceRange.GetMetaRangeID(), baseRange.GetMetaRangeID(), graveler.MergeStrategyNone,
+		graveler.WithProgress(func(processed, total int64) {
+			reports = append(reports, report{processed: processed, total: total})
+		}))
+	if err != nil {
+		t.Fatalf("Merge failed: %s", err)
+	}
+	if len(reports) < 2 {
+		t.Fatalf("Got %d progress reports, expected at least 2", len(reports))
+	}
+	const expectedTotal = 3
+	if reports[0] != (report{processed: 0, total: expectedTotal}) {
+		t.Errorf("First progress report %+v, expected 0 of %d", reports[0], expectedTotal)
+	}
+	if last := reports[len(reports)-1]; last != (report{processed: expectedTotal, total: expectedTotal}) {
+		t.Errorf("Last progress report %+v, expected %d of %d", last, expectedTotal, expectedTotal)
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].processed < reports[i-1].processed || reports[i].total != expectedTotal {
+			t.Errorf("Progress report %d %+v after %+v", i, reports[i], reports[i-1])
+		}
+	}
+}
+
+func TestMergePathScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	baseRange := newTestMetaRange([]testRange{
+		{rng: committed.Range{ID: "base:a/1-b/2", MinKey: committed.Key("a/1"), MaxKey: committed.Key("b/2"), Count: 4}, records: []testValueRecord{
+			{"a/1", "base:a/1"}, {"a/2", "base:a/2"}, {"b/1", "base:b/1"}, {"b/2", "base:b/2"},
+		}},
+	})
+	sourceRange := newTestMetaRange([]testRange{
+		{rng: committed.Range{ID: "source:a/1-b/2", MinKey: committed.Key("a/1"), MaxKey: committed.Key("b/2"), Count: 4}, records: []testValueRecord{
+			{"a/1", "source:a/1"}, {"a/3", "source:a/3"}, {"b/1", "source:b/1"}, {"b/2", "base:b/2"},
+		}},
+	})
+	destRange := newTestMetaRange([]testRange{
+		{rng: committed.Range{ID: "dest:a/1-c", MinKey: committed.Key("a/1"), MaxKey: committed.Key("c"), Count: 5}, records: []testValueRecord{
+			{"a/1", "base:a/1"}, {"a/2", "base:a/2"}, {"b/1", "dest:b/1"}, {"b/2", "base:b/2"}, {"c", "dest:c"},
+		}},
+	})
+
+	var written []string
+	writer := mock.NewMockMetaRangeWriter(ctrl)
+	writer.EXPECT().WriteRecord(gomock.Any()).AnyTimes().DoAndReturn(func(record graveler.ValueRecord) error {
+		written = append(written, fmt.Sprintf("%s=%s", record.Key, record.Identity))
+		return nil
+	})
+	writer.EXPECT().Abort().AnyTimes()
+	metaRangeID := graveler.MetaRangeID("merge")
+	writer.EXPECT().Close(gomock.Any()).Return(&metaRangeID, nil)
+	metaRangeManager := mock.NewMockMetaRangeManager(ctrl)
+	metaRangeManager.EXPECT().NewWriter(gomock.Any(), gomock.Any(), gomock.Any()).Return(writer)
+	for _, tr := range []*testMetaRange{baseRange, sourceRange, destRange} {
+		tr := tr
+		metaRangeManager.EXPECT().NewMetaRangeIterator(gomock.Any(), gomock.Any(), tr.GetMetaRangeID()).AnyTimes().
+			DoAndReturn(func(context.Context, graveler.StorageNamespace, graveler.MetaRangeID) (committed.Iterator, error) {
+				return createIter(tr), nil
+			})
+	}
+	committedManager := committed.NewCommittedManager(metaRangeManager, mock.NewMockRangeManager(ctrl), nil, params)
+
+	// a/2 removed, a/3 added and a/1 changed on the source are merged. b/1 conflicts outside the scope, and
+	// remains as on the destination.
+	_, err := committedManager.Merge(context.Background(), "ns", destRange.GetMetaRangeID(), sourceRange.GetMetaRangeID(), baseRange.GetMetaRangeID(), graveler.MergeStrategyNone,
+		graveler.WithPathScope("a/"))
+	if err != nil {
+		t.Fatalf("Merge failed: %s", err)
+	}
+	expected := []string{"a/1=source:a/1", "a/3=source:a/3", "b/1=dest:b/1", "b/2=base:b/2", "c=dest:c"}
+	assert.Equal(t, expected, written)
+}
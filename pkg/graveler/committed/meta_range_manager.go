@@ -22,6 +22,10 @@ type Params struct {
 	RangeSizeEntriesRaggedness float64
 	// MaxUploaders is the maximal number of uploaders to use in a single metarange writer.
 	MaxUploaders int
+	// KeyFilterCacheSize is the number of MetaRange key filters to keep in memory.
+	KeyFilterCacheSize int
+	// ReadAheadRanges is the number of Ranges to prefetch ahead of MetaRange iterators.
+	ReadAheadRanges int
 }
 
 type metaRangeManager struct {
@@ -108,6 +112,9 @@ func (m *metaRangeManager) NewMetaRangeIterator(ctx context.Context, ns graveler
 	if err != nil {
 		return nil, fmt.Errorf("manage metarange %s: %w", id, err)
 	}
+	if prefetcher, ok := m.rangeManager.(RangePrefetcher); ok && m.params.ReadAheadRanges > 0 {
+		return NewIteratorWithReadAhead(ctx, m.rangeManager, Namespace(ns), rangesIt, prefetcher, m.params.ReadAheadRanges), nil
+	}
 	return NewIterator(ctx, m.rangeManager, Namespace(ns), rangesIt), nil
 }
 
@@ -0,0 +1,68 @@
+package committed
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+// rangeProgress counts the Ranges reached by merge iterators out of a known total, and reports them
+type rangeProgress struct {
+	report    graveler.ProgressFunc
+	processed int64
+	total     int64
+}
+
+func (p *rangeProgress) add() {
+	p.processed++
+	// Ranges of the source and destination may be reached together with their last values, keep within total
+	p.report(min(p.processed, p.total), p.total)
+}
+
+func (p *rangeProgress) done() {
+	p.report(p.total, p.total)
+}
+
+// progressIterator wraps an Iterator and counts each Range it reaches, whether it enters or skips it
+type progressIterator struct {
+	Iterator
+	progress *rangeProgress
+	current  ID
+}
+
+func newProgressIterator(it Iterator, progress *rangeProgress) *progressIterator {
+	return &progressIterator{Iterator: it, progress: progress}
+}
+
+func (pi *progressIterator) track(ok bool) bool {
+	if !ok {
+		return false
+	}
+	if _, rng := pi.Iterator.Value(); rng != nil && rng.ID != pi.current {
+		pi.current = rng.ID
+		pi.progress.add()
+	}
+	return true
+}
+
+func (pi *progressIterator) Next() bool {
+	return pi.track(pi.Iterator.Next())
+}
+
+func (pi *progressIterator) NextRange() bool {
+	return pi.track(pi.Iterator.NextRange())
+}
+
+// countRanges returns the number of Ranges in the MetaRange id
+func (c *committedManager) countRanges(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) (int64, error) {
+	it, err := c.metaRangeManager.NewMetaRangeIterator(ctx, ns, id)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	var count int64
+	for it.NextRange() {
+		count++
+	}
+	return count, it.Err()
+}
@@ -36,6 +36,9 @@ var (
 	ErrCreateBranchNoCommit         = fmt.Errorf("can't create a branch without commit")
 	ErrRepositoryNotFound           = fmt.Errorf("repository %w", ErrNotFound)
 	ErrRepositoryInDeletion         = errors.New("repository in deletion")
+	ErrRepositoryArchived           = fmt.Errorf("repository archived: %w", ErrConflictFound)
+	ErrRepositoryNotArchived        = fmt.Errorf("repository not archived: %w", ErrConflictFound)
+	ErrArchiveGracePeriod           = fmt.Errorf("archived repository grace period not over: %w", ErrConflictFound)
 	ErrBranchNotFound               = fmt.Errorf("branch %w", ErrNotFound)
 	ErrTagNotFound                  = fmt.Errorf("tag %w", ErrNotFound)
 	ErrNoChanges                    = wrapError(ErrUserVisible, "no changes")
@@ -60,6 +63,8 @@ var (
 	ErrSkipValueUpdate              = errors.New("skip value update")
 	ErrImport                       = wrapError(ErrUserVisible, "import error")
 	ErrReadOnlyRepository           = wrapError(ErrUserVisible, "read-only repository")
+	ErrSplitMergeCommit             = wrapError(ErrUserVisible, "can only split a commit with a single parent")
+	ErrCommitNotOnBranch            = fmt.Errorf("commit not on the branch history: %w", ErrNotFound)
 )
 
 // wrappedError is an error for wrapping another error while ignoring its message.
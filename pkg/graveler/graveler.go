@@ -29,6 +29,15 @@ var kvRetriesCounter = promauto.NewCounterVec(
 	[]string{"operation"},
 )
 
+var createBranchDurationSecs = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "graveler_create_branch_duration_seconds",
+		Help:    "Duration of branch creation, including pre- and post-create hooks.",
+		Buckets: []float64{0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 30, 60},
+	},
+	[]string{"status"},
+)
+
 //go:generate go run github.com/golang/mock/mockgen@v1.6.0 -source=graveler.go -destination=mock/graveler.go -package=mock
 
 const (
@@ -64,11 +73,17 @@ const (
 	RefModTypeCaret  RefModType = '^'
 	RefModTypeAt     RefModType = '@'
 	RefModTypeDollar RefModType = '$'
+	// RefModTypeAtTime is parsed from '@{<time>}', the commit at that time on the first-parent history
+	RefModTypeAtTime RefModType = 'T'
+	// RefModTypeCommit is parsed from '^{commit}', the commit the reference points to
+	RefModTypeCommit RefModType = 'C'
 )
 
 type RefModifier struct {
 	Type  RefModType
 	Value int
+	// Time is set on RefModTypeAtTime modifiers
+	Time time.Time
 }
 
 // RawRef is a parsed Ref that includes 'BaseRef' that holds the branch/tag/hash and a list of
@@ -129,6 +144,10 @@ const (
 	MergeStrategySrcWinsStr  = "source-wins"
 
 	MergeStrategyMetadataKey = ".lakefs.merge.strategy"
+	// MergeScopeMetadataKey and MergeSourceMetadataKey record the path scope and the source of a partial merge, whose
+	// commit does not have the source as a parent
+	MergeScopeMetadataKey  = ".lakefs.merge.scope"
+	MergeSourceMetadataKey = ".lakefs.merge.source"
 )
 
 // mergeStrategyString String representation for MergeStrategy consts. Pay attention to the order!
@@ -193,8 +212,15 @@ type SetOptions struct {
 	Force bool
 	// AllowEmpty set to true will allow committing an empty commit.
 	AllowEmpty bool
+	// Progress if set is called by long-running operations (merge) as they process ranges.
+	Progress ProgressFunc
+	// PathScope if set limits a merge to the changes of the source under this prefix.
+	PathScope Prefix
 }
 
+// ProgressFunc reports that processed out of total units of work (ranges) are done
+type ProgressFunc func(processed, total int64)
+
 type SetOptionsFunc func(opts *SetOptions)
 
 func NewSetOptions(opts []SetOptionsFunc) *SetOptions {
@@ -223,6 +249,18 @@ func WithAllowEmpty(v bool) SetOptionsFunc {
 	}
 }
 
+func WithProgress(fn ProgressFunc) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.Progress = fn
+	}
+}
+
+func WithPathScope(prefix Prefix) SetOptionsFunc {
+	return func(opts *SetOptions) {
+		opts.PathScope = prefix
+	}
+}
+
 // function/methods receiving the following basic types could assume they passed validation
 
 // StorageNamespace is the URI to the storage location
@@ -291,6 +329,8 @@ type Repository struct {
 	// ReadOnly indicates if the repository is a read-only repository. All write operations will be blocked for a
 	// read-only repository.
 	ReadOnly bool
+	// ArchivedAt is when the repository was archived, zero unless its state is RepositoryState_ARCHIVED
+	ArchivedAt time.Time
 }
 
 type RepositoryMetadata map[string]string
@@ -416,7 +456,8 @@ type Branch struct {
 	StagingToken StagingToken
 	// SealedTokens - Staging tokens are appended to the front, this allows building the diff iterator easily
 	SealedTokens []StagingToken
-	// CompactedBaseMetaRangeID - the MetaRangeID of the last compaction's
+	// CompactedBaseMetaRangeID - the MetaRangeID of the last compaction's result: the branch commit with the staged
+	// changes compacted so far applied. SealedTokens hold only changes staged after it.
 	CompactedBaseMetaRangeID MetaRangeID
 }
 
@@ -438,6 +479,61 @@ type TagRecord struct {
 	CommitID CommitID
 }
 
+// UnreachableCommitFunc is called for each commit that cannot be reached from any branch or tag. head is true when
+// the commit is not the parent of any other commit: pointing a branch at every head makes all unreachable commits
+// reachable again.
+type UnreachableCommitFunc func(commit *CommitRecord, head bool) error
+
+// ReachableMetadata is the metadata of a repository that is in use: the metaranges of its commits and branch
+// compactions, and the ranges of these metaranges
+type ReachableMetadata struct {
+	MetaRanges map[MetaRangeID]struct{}
+	Ranges     map[RangeID]struct{}
+}
+
+type FindReachableMetadataParams struct {
+	// IncludeDangling includes the metadata of commits that cannot be reached from any branch or tag
+	IncludeDangling bool
+	// MetaRangeIDs are metaranges used outside of commits and branches, e.g. by a refs dump
+	MetaRangeIDs []MetaRangeID
+	// CommitIDs are commits used outside of refs, e.g. by dataset releases. Their metadata and the metadata of
+	// their ancestors is reachable.
+	CommitIDs []CommitID
+}
+
+// DeletedRef is a branch or tag kept after it was deleted, so that it can be restored
+type DeletedRef struct {
+	// Type is ReferenceTypeBranch or ReferenceTypeTag
+	Type      ReferenceType
+	ID        string
+	CommitID  CommitID
+	DeletedAt time.Time
+}
+
+// BranchHistoryRecord is a record of the commit a branch was set to at a time, and its integrity hash
+type BranchHistoryRecord struct {
+	BranchID BranchID
+	CommitID CommitID
+	Time     time.Time
+	// Hash chains the hash of the record before it, "" if the record was written without integrity hashes
+	Hash string
+}
+
+// BranchHistoryVerification is the result of verifying the integrity hash chain of the history of a branch
+type BranchHistoryVerification struct {
+	BranchID BranchID
+	// Records is the number of records verified
+	Records int
+	// Latest is the latest record, nil if the branch has no history
+	Latest *BranchHistoryRecord
+	// Notarized reports that the notarized record was found with its notarized hash
+	Notarized bool
+	// NotarizationExpired reports that the notarized record is older than the branch history retention
+	NotarizationExpired bool
+	// Error describes the first record breaking the chain or not matching the notarized record, "" if none does
+	Error string
+}
+
 // Diff represents a change in value based on key
 type Diff struct {
 	Type         DiffType
@@ -500,6 +596,13 @@ type KeyValueStore interface {
 	// GetRangeIDByKey returns rangeID from the commitID that contains the key
 	GetRangeIDByKey(ctx context.Context, repository *RepositoryRecord, commitID CommitID, key Key) (RangeID, error)
 
+	// FindReachableMetadata returns the metadata used by the commits reachable from any branch or tag and by the
+	// metaranges of params. It reads all the commits of the repository and all their metaranges.
+	FindReachableMetadata(ctx context.Context, repository *RepositoryRecord, params FindReachableMetadataParams) (*ReachableMetadata, error)
+
+	// FindKeyInRefs returns the refs out of refs in which key exists
+	FindKeyInRefs(ctx context.Context, repository *RepositoryRecord, key Key, refs []Ref) ([]Ref, error)
+
 	// Set stores value on repository / branch by key. nil value is a valid value for tombstone
 	Set(ctx context.Context, repository *RepositoryRecord, branchID BranchID, key Key, value Value, opts ...SetOptionsFunc) error
 
@@ -509,6 +612,9 @@ type KeyValueStore interface {
 	// DeleteBatch delete values from repository / branch by batch of keys
 	DeleteBatch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, keys []Key, opts ...SetOptionsFunc) error
 
+	// Move stores value on repository / branch by destKey and deletes srcKey in a single branch write
+	Move(ctx context.Context, repository *RepositoryRecord, branchID BranchID, srcKey, destKey Key, value Value, opts ...SetOptionsFunc) error
+
 	// List lists values on repository / ref
 	List(ctx context.Context, repository *RepositoryRecord, ref Ref, batchSize int) (ValueIterator, error)
 }
@@ -529,6 +635,12 @@ type VersionController interface {
 	// DeleteRepository deletes the repository
 	DeleteRepository(ctx context.Context, repositoryID RepositoryID, opts ...SetOptionsFunc) error
 
+	// ArchiveRepository makes the repository read-only and hidden from listings, keeping all of its data
+	ArchiveRepository(ctx context.Context, repositoryID RepositoryID) error
+
+	// RestoreRepository restores an archived repository to its state before it was archived
+	RestoreRepository(ctx context.Context, repositoryID RepositoryID) error
+
 	// GetRepositoryMetadata returns repository user metadata
 	GetRepositoryMetadata(ctx context.Context, repositoryID RepositoryID) (RepositoryMetadata, error)
 
@@ -559,12 +671,33 @@ type VersionController interface {
 	// Log returns an iterator starting at commit ID up to repository root
 	Log(ctx context.Context, repository *RepositoryRecord, commitID CommitID, firstParent bool, since *time.Time) (CommitIterator, error)
 
+	// FindUnreachableCommits calls fn, in commit ID order, for each commit that cannot be reached from any branch
+	// or tag. It reads all the commits of the repository.
+	FindUnreachableCommits(ctx context.Context, repository *RepositoryRecord, fn UnreachableCommitFunc) error
+
 	// ListBranches lists branches on repositories
 	ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error)
 
 	// DeleteBranch deletes branch from repository
 	DeleteBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) error
 
+	// ListDeletedRefs lists the deleted branches and tags that can still be restored, most recently deleted first
+	ListDeletedRefs(ctx context.Context, repository *RepositoryRecord) ([]*DeletedRef, error)
+
+	// GetBranchHistoryHead returns the latest branch history record of the branch
+	GetBranchHistoryHead(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*BranchHistoryRecord, error)
+
+	// VerifyBranchHistory verifies the integrity hash chain of the history of the branch, and that it includes the
+	// notarized record if one is passed
+	VerifyBranchHistory(ctx context.Context, repository *RepositoryRecord, branchID BranchID, notarized *BranchHistoryRecord) (*BranchHistoryVerification, error)
+
+	// RestoreBranch recreates a deleted branch pointing to the commit it pointed to when deleted, without its
+	// uncommitted changes
+	RestoreBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) (*Branch, error)
+
+	// RestoreTag recreates a deleted tag pointing to the commit it pointed to when deleted
+	RestoreTag(ctx context.Context, repository *RepositoryRecord, tagID TagID, opts ...SetOptionsFunc) (CommitID, error)
+
 	// Commit the staged data and returns a commit ID that references that change
 	//   ErrNothingToCommit in case there is no data in stage
 	Commit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, commitParams CommitParams, opts ...SetOptionsFunc) (CommitID, error)
@@ -610,7 +743,14 @@ type VersionController interface {
 	// CherryPick creates a patch to the commit given as 'ref', and applies it as a new commit on the given branch.
 	CherryPick(ctx context.Context, repository *RepositoryRecord, id BranchID, reference Ref, number *int, committer string, commitOverrides *CommitOverrides, opts ...SetOptionsFunc) (CommitID, error)
 
+	// SplitCommit replaces the commit 'reference' on the history of branch 'branchID' with a commit of its changes
+	// under each of 'prefixes', followed by a commit of its remaining changes, and recreates the commits after it on
+	// the branch on top of them. Returns the IDs of the commits replacing it.
+	SplitCommit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, reference Ref, prefixes []Prefix, opts ...SetOptionsFunc) ([]CommitID, error)
+
 	// Merge merges 'source' into 'destination' and returns the commit id for the created merge commit.
+	// WithPathScope merges only the changes of 'source' under a prefix, creating a commit whose only parent is
+	// 'destination'.
 	Merge(ctx context.Context, repository *RepositoryRecord, destination BranchID, source Ref, commitParams CommitParams, strategy string, opts ...SetOptionsFunc) (CommitID, error)
 
 	// Import creates a merge-commit in the destination branch using the source MetaRangeID, overriding any destination
@@ -628,9 +768,21 @@ type VersionController interface {
 	// This is similar to a three-dot (from...to) diff in git.
 	Compare(ctx context.Context, repository *RepositoryRecord, left, right Ref) (DiffIterator, error)
 
+	// DiffPrefixes returns the changes from the values under leftPrefix of 'left' to the values under rightPrefix of
+	// 'right', with keys relative to the prefixes. Like Diff, it includes uncommitted changes only of a 'right'
+	// branch with the staging modifier.
+	DiffPrefixes(ctx context.Context, repository *RepositoryRecord, left Ref, leftPrefix Key, right Ref, rightPrefix Key) (DiffIterator, error)
+
 	// FindMergeBase returns the 'from' commit, the 'to' commit and the merge base commit of 'from' and 'to' commits.
 	FindMergeBase(ctx context.Context, repository *RepositoryRecord, from Ref, to Ref) (*CommitRecord, *CommitRecord, *Commit, error)
 
+	// ListRanges returns the ranges of the committed data of ref, without reading the ranges. For a branch these are
+	// the ranges of its commit, or of its compacted staging area, without its uncommitted changes.
+	ListRanges(ctx context.Context, repository *RepositoryRecord, ref Ref) ([]*RangeInfo, error)
+
+	// ListRange returns an iterator over the values of the range rangeID
+	ListRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (ValueIterator, error)
+
 	// SetHooksHandler set handler for all graveler hooks
 	SetHooksHandler(handler HooksHandler)
 
@@ -803,6 +955,12 @@ type RefManager interface {
 	// DeleteRepository deletes the repository
 	DeleteRepository(ctx context.Context, repositoryID RepositoryID, opts ...SetOptionsFunc) error
 
+	// ArchiveRepository sets the state of an active repository to archived
+	ArchiveRepository(ctx context.Context, repositoryID RepositoryID) error
+
+	// RestoreRepository sets the state of an archived repository back to active
+	RestoreRepository(ctx context.Context, repositoryID RepositoryID) error
+
 	// GetRepositoryMetadata gets repository user metadata
 	GetRepositoryMetadata(ctx context.Context, repositoryID RepositoryID) (RepositoryMetadata, error)
 
@@ -849,6 +1007,23 @@ type RefManager interface {
 	// ListTags lists tags
 	ListTags(ctx context.Context, repository *RepositoryRecord) (TagIterator, error)
 
+	// ListDeletedRefs lists the deleted branches and tags kept for the trash retention, most recently deleted first
+	ListDeletedRefs(ctx context.Context, repository *RepositoryRecord) ([]*DeletedRef, error)
+
+	// GetBranchHistoryHead returns the latest branch history record of the branch. Returns ErrNotFound when the
+	// branch has no history.
+	GetBranchHistoryHead(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*BranchHistoryRecord, error)
+
+	// VerifyBranchHistory verifies that each branch history record chains the integrity hash of the record before
+	// it, and that the history includes the notarized record with its hash unless it is nil or expired
+	VerifyBranchHistory(ctx context.Context, repository *RepositoryRecord, branchID BranchID, notarized *BranchHistoryRecord) (*BranchHistoryVerification, error)
+
+	// RestoreBranch creates the most recently deleted branch branchID again, with a new staging token
+	RestoreBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*Branch, error)
+
+	// RestoreTag creates the most recently deleted tag tagID again
+	RestoreTag(ctx context.Context, repository *RepositoryRecord, tagID TagID) (CommitID, error)
+
 	// GetCommit returns the Commit metadata object for the given CommitID.
 	GetCommit(ctx context.Context, repository *RepositoryRecord, commitID CommitID) (*Commit, error)
 
@@ -876,6 +1051,10 @@ type RefManager interface {
 	// ListCommits returns an iterator over all known commits, ordered by their commit ID
 	ListCommits(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error)
 
+	// FindUnreachableCommits calls fn, in commit ID order, for each commit that cannot be reached from any branch
+	// or tag
+	FindUnreachableCommits(ctx context.Context, repository *RepositoryRecord, fn UnreachableCommitFunc) error
+
 	// GCCommitIterator TODO (niro): Remove when DB implementation is deleted
 	// GCCommitIterator temporary WA to support both DB and KV GC CommitIterator
 	GCCommitIterator(ctx context.Context, repository *RepositoryRecord) (CommitIterator, error)
@@ -917,6 +1096,7 @@ type CommittedManager interface {
 	// Merge applies changes from 'source' to 'destination', relative to a merge base 'base' and
 	// returns the ID of the new metarange. This is similar to a git merge operation.
 	// The resulting tree is expected to be immediately addressable.
+	// WithPathScope applies only the changes of 'source' under a prefix to 'destination'.
 	Merge(ctx context.Context, ns StorageNamespace, destination, source, base MetaRangeID, strategy MergeStrategy, opts ...SetOptionsFunc) (MetaRangeID, error)
 
 	// Import sync changes from 'source' to 'destination'. All the given prefixes are completely overridden on the resulting metarange. Returns the ID of the new
@@ -935,6 +1115,18 @@ type CommittedManager interface {
 
 	// GetRangeIDByKey returns the RangeID that contains the given key.
 	GetRangeIDByKey(ctx context.Context, ns StorageNamespace, id MetaRangeID, key Key) (RangeID, error)
+
+	// ListRangeIDs returns the IDs of the ranges of a MetaRange, without reading the ranges.
+	ListRangeIDs(ctx context.Context, ns StorageNamespace, id MetaRangeID) ([]RangeID, error)
+
+	// ListRanges returns the ranges of a MetaRange, without reading the ranges.
+	ListRanges(ctx context.Context, ns StorageNamespace, id MetaRangeID) ([]*RangeInfo, error)
+
+	// ListRange returns an iterator over the values of a Range.
+	ListRange(ctx context.Context, ns StorageNamespace, id RangeID) (ValueIterator, error)
+
+	// MayContain returns false if key surely doesn't exist in the MetaRange, true if it may exist.
+	MayContain(ctx context.Context, ns StorageNamespace, id MetaRangeID, key Key) (bool, error)
 }
 
 // StagingManager manages entries in a staging area, denoted by a staging token
@@ -1106,6 +1298,14 @@ func (g *Graveler) DeleteRepository(ctx context.Context, repositoryID Repository
 	return g.RefManager.DeleteRepository(ctx, repositoryID, opts...)
 }
 
+func (g *Graveler) ArchiveRepository(ctx context.Context, repositoryID RepositoryID) error {
+	return g.RefManager.ArchiveRepository(ctx, repositoryID)
+}
+
+func (g *Graveler) RestoreRepository(ctx context.Context, repositoryID RepositoryID) error {
+	return g.RefManager.RestoreRepository(ctx, repositoryID)
+}
+
 func (g *Graveler) GetRepositoryMetadata(ctx context.Context, repositoryID RepositoryID) (RepositoryMetadata, error) {
 	return g.RefManager.GetRepositoryMetadata(ctx, repositoryID)
 }
@@ -1151,7 +1351,21 @@ func GenerateStagingToken(repositoryID RepositoryID, branchID BranchID) StagingT
 	return StagingToken(fmt.Sprintf("%s-%s:%s", repositoryID, branchID, uid))
 }
 
+// CreateBranch creates branchID pointing at the commit of ref.  The new branch starts with a fresh, empty
+// staging token: staged data of ref is never read or copied, so the cost of creating a branch does not
+// depend on the size of the repository.
 func (g *Graveler) CreateBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, opts ...SetOptionsFunc) (*Branch, error) {
+	start := time.Now()
+	branch, err := g.createBranch(ctx, repository, branchID, ref, opts...)
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	createBranchDurationSecs.WithLabelValues(status).Observe(time.Since(start).Seconds())
+	return branch, err
+}
+
+func (g *Graveler) createBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, opts ...SetOptionsFunc) (*Branch, error) {
 	options := NewSetOptions(opts)
 	if repository.ReadOnly && !options.Force {
 		return nil, ErrReadOnlyRepository
@@ -1252,6 +1466,7 @@ func (g *Graveler) UpdateBranch(ctx context.Context, repository *RepositoryRecor
 
 		tokensToDrop = currBranch.SealedTokens
 		currBranch.SealedTokens = []StagingToken{}
+		currBranch.CompactedBaseMetaRangeID = ""
 		currBranch.CommitID = reference.CommitID
 		newBranch = currBranch
 		return currBranch, nil
@@ -1432,6 +1647,36 @@ func (g *Graveler) ListTags(ctx context.Context, repository *RepositoryRecord) (
 	return g.RefManager.ListTags(ctx, repository)
 }
 
+func (g *Graveler) ListDeletedRefs(ctx context.Context, repository *RepositoryRecord) ([]*DeletedRef, error) {
+	return g.RefManager.ListDeletedRefs(ctx, repository)
+}
+
+func (g *Graveler) GetBranchHistoryHead(ctx context.Context, repository *RepositoryRecord, branchID BranchID) (*BranchHistoryRecord, error) {
+	return g.RefManager.GetBranchHistoryHead(ctx, repository, branchID)
+}
+
+func (g *Graveler) VerifyBranchHistory(ctx context.Context, repository *RepositoryRecord, branchID BranchID, notarized *BranchHistoryRecord) (*BranchHistoryVerification, error) {
+	return g.RefManager.VerifyBranchHistory(ctx, repository, branchID, notarized)
+}
+
+// RestoreBranch restores a deleted branch. Branch creation hooks are not run: the branch existed before.
+func (g *Graveler) RestoreBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID, opts ...SetOptionsFunc) (*Branch, error) {
+	options := NewSetOptions(opts)
+	if repository.ReadOnly && !options.Force {
+		return nil, ErrReadOnlyRepository
+	}
+	return g.RefManager.RestoreBranch(ctx, repository, branchID)
+}
+
+// RestoreTag restores a deleted tag. Tag creation hooks are not run: the tag existed before.
+func (g *Graveler) RestoreTag(ctx context.Context, repository *RepositoryRecord, tagID TagID, opts ...SetOptionsFunc) (CommitID, error) {
+	options := NewSetOptions(opts)
+	if repository.ReadOnly && !options.Force {
+		return "", ErrReadOnlyRepository
+	}
+	return g.RefManager.RestoreTag(ctx, repository, tagID)
+}
+
 func (g *Graveler) Dereference(ctx context.Context, repository *RepositoryRecord, ref Ref) (*ResolvedRef, error) {
 	rawRef, err := g.ParseRef(ref)
 	if err != nil {
@@ -1452,6 +1697,10 @@ func (g *Graveler) Log(ctx context.Context, repository *RepositoryRecord, commit
 	return g.RefManager.Log(ctx, repository, commitID, firstParent, since)
 }
 
+func (g *Graveler) FindUnreachableCommits(ctx context.Context, repository *RepositoryRecord, fn UnreachableCommitFunc) error {
+	return g.RefManager.FindUnreachableCommits(ctx, repository, fn)
+}
+
 func (g *Graveler) ListBranches(ctx context.Context, repository *RepositoryRecord) (BranchIterator, error) {
 	return g.RefManager.ListBranches(ctx, repository)
 }
@@ -1664,6 +1913,67 @@ func (g *Graveler) Get(ctx context.Context, repository *RepositoryRecord, ref Re
 	return g.CommittedManager.Get(ctx, repository.StorageNamespace, commit.MetaRangeID, key)
 }
 
+func (g *Graveler) FindKeyInRefs(ctx context.Context, repository *RepositoryRecord, key Key, refs []Ref) ([]Ref, error) {
+	found := make([]Ref, 0)
+	// refs often share their metarange, check each one once
+	committedExists := make(map[MetaRangeID]bool)
+	for _, ref := range refs {
+		reference, err := g.Dereference(ctx, repository, ref)
+		if err != nil {
+			return nil, err
+		}
+		if reference.StagingToken != "" {
+			value, err := g.getFromStagingArea(ctx, reference.Branch, key)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+			if err == nil {
+				// a nil value is a tombstone - the entry was deleted on the branch
+				if value != nil {
+					found = append(found, ref)
+				}
+				continue
+			}
+		}
+		metaRangeID := reference.CompactedBaseMetaRangeID
+		if metaRangeID == "" {
+			commit, err := g.RefManager.GetCommit(ctx, repository, reference.CommitID)
+			if err != nil {
+				return nil, err
+			}
+			metaRangeID = commit.MetaRangeID
+		}
+		exists, ok := committedExists[metaRangeID]
+		if !ok {
+			exists, err = g.committedKeyExists(ctx, repository.StorageNamespace, metaRangeID, key)
+			if err != nil {
+				return nil, err
+			}
+			committedExists[metaRangeID] = exists
+		}
+		if exists {
+			found = append(found, ref)
+		}
+	}
+	return found, nil
+}
+
+// committedKeyExists checks the key filter of the metarange before looking up key
+func (g *Graveler) committedKeyExists(ctx context.Context, ns StorageNamespace, metaRangeID MetaRangeID, key Key) (bool, error) {
+	mayContain, err := g.CommittedManager.MayContain(ctx, ns, metaRangeID, key)
+	if err != nil {
+		return false, err
+	}
+	if !mayContain {
+		return false, nil
+	}
+	_, err = g.CommittedManager.Get(ctx, ns, metaRangeID, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (g *Graveler) GetByCommitID(ctx context.Context, repository *RepositoryRecord, commitID CommitID, key Key) (*Value, error) {
 	// If key is not found in staging area (or reference is not a branch), return the key from committed
 	commit, err := g.RefManager.GetCommit(ctx, repository, commitID)
@@ -1681,6 +1991,77 @@ func (g *Graveler) GetRangeIDByKey(ctx context.Context, repository *RepositoryRe
 	return g.CommittedManager.GetRangeIDByKey(ctx, repository.StorageNamespace, commit.MetaRangeID, key)
 }
 
+func (g *Graveler) FindReachableMetadata(ctx context.Context, repository *RepositoryRecord, params FindReachableMetadataParams) (*ReachableMetadata, error) {
+	unreachable := make(map[CommitID]CommitParents)
+	if !params.IncludeDangling {
+		err := g.RefManager.FindUnreachableCommits(ctx, repository, func(commit *CommitRecord, _ bool) error {
+			unreachable[commit.CommitID] = commit.Parents
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("find unreachable commits: %w", err)
+		}
+		// commits used outside of refs and their ancestors are reachable
+		pending := append([]CommitID{}, params.CommitIDs...)
+		for len(pending) > 0 {
+			commitID := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			parents, ok := unreachable[commitID]
+			if !ok {
+				continue
+			}
+			delete(unreachable, commitID)
+			pending = append(pending, parents...)
+		}
+	}
+
+	metaRanges := make(map[MetaRangeID]struct{})
+	for _, id := range params.MetaRangeIDs {
+		metaRanges[id] = struct{}{}
+	}
+	commitsIt, err := g.RefManager.ListCommits(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("list commits: %w", err)
+	}
+	defer commitsIt.Close()
+	for commitsIt.Next() {
+		commit := commitsIt.Value()
+		if _, ok := unreachable[commit.CommitID]; ok || commit.MetaRangeID == "" {
+			continue
+		}
+		metaRanges[commit.MetaRangeID] = struct{}{}
+	}
+	if err := commitsIt.Err(); err != nil {
+		return nil, fmt.Errorf("list commits: %w", err)
+	}
+
+	branchesIt, err := g.RefManager.ListBranches(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	defer branchesIt.Close()
+	for branchesIt.Next() {
+		if id := branchesIt.Value().CompactedBaseMetaRangeID; id != "" {
+			metaRanges[id] = struct{}{}
+		}
+	}
+	if err := branchesIt.Err(); err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+
+	ranges := make(map[RangeID]struct{})
+	for metaRangeID := range metaRanges {
+		rangeIDs, err := g.CommittedManager.ListRangeIDs(ctx, repository.StorageNamespace, metaRangeID)
+		if err != nil {
+			return nil, fmt.Errorf("list ranges of metarange %s: %w", metaRangeID, err)
+		}
+		for _, rangeID := range rangeIDs {
+			ranges[rangeID] = struct{}{}
+		}
+	}
+	return &ReachableMetadata{MetaRanges: metaRanges, Ranges: ranges}, nil
+}
+
 func (g *Graveler) Set(ctx context.Context, repository *RepositoryRecord, branchID BranchID, key Key, value Value, opts ...SetOptionsFunc) error {
 	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_STAGING_WRITE)
 	if err != nil {
@@ -1826,6 +2207,59 @@ func (g *Graveler) DeleteBatch(ctx context.Context, repository *RepositoryRecord
 	return err
 }
 
+// Move stores value by destKey and deletes srcKey on the branch.  Both changes are written to the same
+// staging token, retrying on both if the token changes, so a commit never holds only one of them.  The
+// value is staged before srcKey is deleted: a failure in between leaves both keys, never neither.
+func (g *Graveler) Move(ctx context.Context, repository *RepositoryRecord, branchID BranchID, srcKey, destKey Key, value Value, opts ...SetOptionsFunc) error {
+	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_STAGING_WRITE)
+	if err != nil {
+		return err
+	}
+	if isProtected {
+		return ErrWriteToProtectedBranch
+	}
+
+	options := NewSetOptions(opts)
+	if repository.ReadOnly && !options.Force {
+		return ErrReadOnlyRepository
+	}
+	if bytes.Equal(srcKey, destKey) {
+		return fmt.Errorf("move key onto itself: %w", ErrInvalidValue)
+	}
+
+	log := g.log(ctx).WithFields(logging.Fields{"src_key": srcKey, "dest_key": destKey, "operation": "move"})
+	err = g.safeBranchWrite(ctx, log, repository, branchID, safeBranchWriteOptions{MaxTries: options.MaxTries}, func(branch *Branch) error {
+		if !options.IfAbsent {
+			err = g.StagingManager.Set(ctx, branch.StagingToken, destKey, &value, false)
+		} else {
+			err = g.moveIfAbsent(ctx, repository, branchID, branch, destKey, value)
+		}
+		if err != nil {
+			return err
+		}
+		return g.deleteUnsafe(ctx, repository, srcKey, BranchRecord{branchID, branch})
+	}, "move")
+	return err
+}
+
+// moveIfAbsent stages value by destKey only if destKey does not exist on the branch
+func (g *Graveler) moveIfAbsent(ctx context.Context, repository *RepositoryRecord, branchID BranchID, branch *Branch, destKey Key, value Value) error {
+	_, err := g.Get(ctx, repository, Ref(branchID), destKey)
+	if err == nil {
+		return ErrPreconditionFailed
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return g.StagingManager.Update(ctx, branch.StagingToken, destKey, func(currentValue *Value) (*Value, error) {
+		if currentValue == nil || currentValue.Identity == nil {
+			return &value, nil
+		}
+		// staged concurrently since the check above
+		return nil, ErrPreconditionFailed
+	})
+}
+
 func (g *Graveler) deleteUnsafe(ctx context.Context, repository *RepositoryRecord, key Key, branchRecord BranchRecord) error {
 	// First attempt to update on staging token
 	err := g.deleteAndNotify(ctx, repository.RepositoryID, branchRecord, key, true)
@@ -1907,20 +2341,30 @@ func (g *Graveler) sealedTokensIterator(ctx context.Context, b *Branch, batchSiz
 	return changes, nil
 }
 
+// committedMetaRangeID returns the MetaRange of the committed data of reference: the compacted staging area of a
+// branch if it has one, otherwise the MetaRange of its commit
+func (g *Graveler) committedMetaRangeID(ctx context.Context, repository *RepositoryRecord, reference *ResolvedRef) (MetaRangeID, error) {
+	if reference.CompactedBaseMetaRangeID != "" {
+		return reference.CompactedBaseMetaRangeID, nil
+	}
+	if reference.CommitID == "" {
+		return "", nil
+	}
+	commit, err := g.RefManager.GetCommit(ctx, repository, reference.CommitID)
+	if err != nil {
+		return "", err
+	}
+	return commit.MetaRangeID, nil
+}
+
 func (g *Graveler) List(ctx context.Context, repository *RepositoryRecord, ref Ref, batchSize int) (ValueIterator, error) {
 	reference, err := g.Dereference(ctx, repository, ref)
 	if err != nil {
 		return nil, err
 	}
-	var metaRangeID MetaRangeID
-	if reference.CompactedBaseMetaRangeID != "" {
-		metaRangeID = reference.CompactedBaseMetaRangeID
-	} else if reference.CommitID != "" {
-		commit, err := g.RefManager.GetCommit(ctx, repository, reference.CommitID)
-		if err != nil {
-			return nil, err
-		}
-		metaRangeID = commit.MetaRangeID
+	metaRangeID, err := g.committedMetaRangeID(ctx, repository, reference)
+	if err != nil {
+		return nil, err
 	}
 
 	listing, err := g.CommittedManager.List(ctx, repository.StorageNamespace, metaRangeID)
@@ -1939,6 +2383,25 @@ func (g *Graveler) List(ctx context.Context, repository *RepositoryRecord, ref R
 	return listing, nil
 }
 
+func (g *Graveler) ListRanges(ctx context.Context, repository *RepositoryRecord, ref Ref) ([]*RangeInfo, error) {
+	reference, err := g.Dereference(ctx, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	metaRangeID, err := g.committedMetaRangeID(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	if metaRangeID == "" {
+		return nil, nil
+	}
+	return g.CommittedManager.ListRanges(ctx, repository.StorageNamespace, metaRangeID)
+}
+
+func (g *Graveler) ListRange(ctx context.Context, repository *RepositoryRecord, rangeID RangeID) (ValueIterator, error) {
+	return g.CommittedManager.ListRange(ctx, repository.StorageNamespace, rangeID)
+}
+
 func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, params CommitParams, opts ...SetOptionsFunc) (CommitID, error) {
 	var preRunID string
 	var commit Commit
@@ -2038,11 +2501,26 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 				return nil, err
 			}
 			defer changes.Close()
+			// apply the changes staged after the last compaction on top of its result
+			baseMetaRangeID := branchMetaRangeID
+			if branch.CompactedBaseMetaRangeID != "" {
+				baseMetaRangeID = branch.CompactedBaseMetaRangeID
+			}
 			// returns err if the commit is empty (no changes)
-			commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, storageNamespace, branchMetaRangeID, changes, params.AllowEmpty)
+			commit.MetaRangeID, _, err = g.CommittedManager.Commit(ctx, storageNamespace, baseMetaRangeID, changes, params.AllowEmpty || branch.CompactedBaseMetaRangeID != "")
 			if err != nil {
 				return nil, fmt.Errorf("commit: %w", err)
 			}
+			if branch.CompactedBaseMetaRangeID != "" && !params.AllowEmpty {
+				// the staged changes may be empty while the compacted changes are not
+				empty, err := g.isMetaRangeDiffEmpty(ctx, storageNamespace, branchMetaRangeID, commit.MetaRangeID)
+				if err != nil {
+					return nil, fmt.Errorf("commit: %w", err)
+				}
+				if empty {
+					return nil, fmt.Errorf("commit: %w", ErrNoChanges)
+				}
+			}
 		}
 		sealedToDrop = branch.SealedTokens
 
@@ -2054,6 +2532,7 @@ func (g *Graveler) Commit(ctx context.Context, repository *RepositoryRecord, bra
 
 		branch.CommitID = newCommitID
 		branch.SealedTokens = make([]StagingToken, 0)
+		branch.CompactedBaseMetaRangeID = ""
 		return branch, nil
 	}, "commit")
 	if err != nil {
@@ -2232,6 +2711,17 @@ func (g *Graveler) checkEmpty(ctx context.Context, repository *RepositoryRecord,
 }
 
 func (g *Graveler) isSealedEmpty(ctx context.Context, repository *RepositoryRecord, branch *Branch) (bool, error) {
+	if branch.CompactedBaseMetaRangeID != "" {
+		// compacted changes are sealed changes too
+		commit, err := g.RefManager.GetCommit(ctx, repository, branch.CommitID)
+		if err != nil {
+			return false, err
+		}
+		empty, err := g.isMetaRangeDiffEmpty(ctx, repository.StorageNamespace, commit.MetaRangeID, branch.CompactedBaseMetaRangeID)
+		if err != nil || !empty {
+			return false, err
+		}
+	}
 	if len(branch.SealedTokens) == 0 {
 		return true, nil
 	}
@@ -2243,6 +2733,22 @@ func (g *Graveler) isSealedEmpty(ctx context.Context, repository *RepositoryReco
 	return g.checkEmpty(ctx, repository, branch, itrs)
 }
 
+// isMetaRangeDiffEmpty returns true if there is no difference between the metaranges
+func (g *Graveler) isMetaRangeDiffEmpty(ctx context.Context, storageNamespace StorageNamespace, left, right MetaRangeID) (bool, error) {
+	if left == right {
+		return true, nil
+	}
+	diffIt, err := g.CommittedManager.Diff(ctx, storageNamespace, left, right)
+	if err != nil {
+		return false, err
+	}
+	defer diffIt.Close()
+	if diffIt.Next() {
+		return false, nil
+	}
+	return true, diffIt.Err()
+}
+
 // dropTokens deletes all staging area entries of a given branch from store
 func (g *Graveler) dropTokens(ctx context.Context, tokens ...StagingToken) {
 	for _, token := range tokens {
@@ -2309,6 +2815,8 @@ func (g *Graveler) ResetHard(ctx context.Context, repository *RepositoryRecord,
 			return nil, fmt.Errorf("hard-reset %s to %s: %w", branchID, ref, err)
 		}
 		branch.CommitID = commitRecord.CommitID
+		// the compacted base is relative to the previous commit
+		branch.CompactedBaseMetaRangeID = ""
 		return branch, nil
 	}, "reset_hard")
 	return err
@@ -2337,6 +2845,7 @@ func (g *Graveler) Reset(ctx context.Context, repository *RepositoryRecord, bran
 		// Zero tokens and try to set branch
 		branch.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
 		branch.SealedTokens = make([]StagingToken, 0)
+		branch.CompactedBaseMetaRangeID = ""
 		return branch, nil
 	})
 	if err != nil { // Branch update failed, don't drop staging tokens
@@ -2579,6 +3088,7 @@ func (g *Graveler) Revert(ctx context.Context, repository *RepositoryRecord, bra
 
 		tokensToDrop = branch.SealedTokens
 		branch.SealedTokens = []StagingToken{}
+		branch.CompactedBaseMetaRangeID = ""
 		branch.CommitID = commitID
 		return branch, nil
 	})
@@ -2678,6 +3188,7 @@ func (g *Graveler) CherryPick(ctx context.Context, repository *RepositoryRecord,
 
 		tokensToDrop = branch.SealedTokens
 		branch.SealedTokens = []StagingToken{}
+		branch.CompactedBaseMetaRangeID = ""
 		branch.CommitID = commitID
 		return branch, nil
 	})
@@ -2778,11 +3289,20 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 		commit.Committer = commitParams.Committer
 		commit.Message = commitParams.Message
 		commit.MetaRangeID = metaRangeID
-		commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
-		if toCommit.Generation > fromCommit.Generation {
+		if options.PathScope != "" {
+			// the changes of the source outside the scope are not merged: the source is not a parent, so that a
+			// later merge from it still finds them
+			commit.Parents = []CommitID{toCommit.CommitID}
 			commit.Generation = toCommit.Generation + 1
+			metadata[MergeScopeMetadataKey] = string(options.PathScope)
+			metadata[MergeSourceMetadataKey] = string(fromCommit.CommitID)
 		} else {
-			commit.Generation = fromCommit.Generation + 1
+			commit.Parents = []CommitID{toCommit.CommitID, fromCommit.CommitID}
+			if toCommit.Generation > fromCommit.Generation {
+				commit.Generation = toCommit.Generation + 1
+			} else {
+				commit.Generation = fromCommit.Generation + 1
+			}
 		}
 		metadata[MergeStrategyMetadataKey] = mergeStrategyString[mergeStrategy]
 		commit.Metadata = metadata
@@ -2801,6 +3321,7 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 				SourceRef:        fromCommit.CommitID.Ref(),
 				Commit:           commit,
 				CommitID:         commitID,
+				MergeSource:      source,
 			})
 			if err != nil {
 				return nil, &HookAbortError{
@@ -2812,6 +3333,7 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 		}
 		tokensToDrop = branch.SealedTokens
 		branch.SealedTokens = []StagingToken{}
+		branch.CompactedBaseMetaRangeID = ""
 		branch.CommitID = commitID
 		return branch, nil
 	}, "merge")
@@ -2829,10 +3351,11 @@ func (g *Graveler) Merge(ctx context.Context, repository *RepositoryRecord, dest
 			StorageNamespace: storageNamespace,
 			BranchID:         destination,
 
-			SourceRef: commitID.Ref(),
-			Commit:    commit,
-			CommitID:  commitID,
-			PreRunID:  preRunID,
+			SourceRef:   commitID.Ref(),
+			Commit:      commit,
+			CommitID:    commitID,
+			PreRunID:    preRunID,
+			MergeSource: source,
 		})
 		if err != nil {
 			g.log(ctx).
@@ -2959,6 +3482,7 @@ func (g *Graveler) Import(ctx context.Context, repository *RepositoryRecord, des
 
 		tokensToDrop = branch.SealedTokens
 		branch.SealedTokens = []StagingToken{}
+		branch.CompactedBaseMetaRangeID = ""
 		branch.CommitID = commitID
 		return branch, nil
 	}, "import")
@@ -3105,6 +3629,36 @@ func (g *Graveler) Diff(ctx context.Context, repository *RepositoryRecord, left,
 	return NewCombinedDiffIterator(compactedDiffIterator, leftValueIterator, stagingIterator), nil
 }
 
+func (g *Graveler) DiffPrefixes(ctx context.Context, repository *RepositoryRecord, left Ref, leftPrefix Key, right Ref, rightPrefix Key) (DiffIterator, error) {
+	leftCommit, err := g.dereferenceCommit(ctx, repository, left)
+	if err != nil {
+		return nil, err
+	}
+	rightRawRef, err := g.Dereference(ctx, repository, right)
+	if err != nil {
+		return nil, err
+	}
+	var rightValueIterator ValueIterator
+	if rightRawRef.ResolvedBranchModifier == ResolvedBranchModifierStaging {
+		rightValueIterator, err = g.List(ctx, repository, right, 0)
+	} else {
+		var rightCommit *Commit
+		rightCommit, err = g.RefManager.GetCommit(ctx, repository, rightRawRef.CommitID)
+		if err == nil {
+			rightValueIterator, err = g.CommittedManager.List(ctx, repository.StorageNamespace, rightCommit.MetaRangeID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	leftValueIterator, err := g.CommittedManager.List(ctx, repository.StorageNamespace, leftCommit.MetaRangeID)
+	if err != nil {
+		rightValueIterator.Close()
+		return nil, err
+	}
+	return NewPrefixDiffIterator(leftValueIterator, leftPrefix, rightValueIterator, rightPrefix), nil
+}
+
 func (g *Graveler) FindMergeBase(ctx context.Context, repository *RepositoryRecord, from Ref, to Ref) (*CommitRecord, *CommitRecord, *Commit, error) {
 	fromCommit, err := g.dereferenceCommit(ctx, repository, from)
 	if err != nil {
@@ -1350,10 +1350,14 @@ func TestGravelerDiffUncommitted_Advanced(t *testing.T) {
 
 func TestGraveler_CreateBranch(t *testing.T) {
 	gravel := newGraveler(t, nil, nil, &testutil.RefsFake{Err: graveler.ErrBranchNotFound, CommitID: "8888888798e3aeface8e62d1c7072a965314b4"}, nil, nil)
-	_, err := gravel.CreateBranch(context.Background(), repository, "", "")
+	// staging manager is nil - creating a branch must not touch staged data
+	branch, err := gravel.CreateBranch(context.Background(), repository, "", "")
 	if err != nil {
 		t.Fatal("unexpected error on create branch", err)
 	}
+	if branch.CommitID != "8888888798e3aeface8e62d1c7072a965314b4" || len(branch.SealedTokens) != 0 {
+		t.Fatalf("unexpected new branch %+v", branch)
+	}
 	// test create branch when branch exists
 	gravel = newGraveler(t, nil, nil, &testutil.RefsFake{Branch: &graveler.Branch{}}, nil, nil)
 	_, err = gravel.CreateBranch(context.Background(), repository, "", "")
@@ -3077,3 +3081,75 @@ func TestGraveler_Revert(t *testing.T) {
 		})
 	}
 }
+
+func TestGraveler_FindReachableMetadata(t *testing.T) {
+	commits := []*graveler.CommitRecord{
+		{CommitID: "c1", Commit: &graveler.Commit{MetaRangeID: "mr1"}},
+		{CommitID: "c2", Commit: &graveler.Commit{MetaRangeID: "mr2"}},
+		{CommitID: "c3", Commit: &graveler.Commit{MetaRangeID: "mr3"}},
+		{CommitID: "c4", Commit: &graveler.Commit{MetaRangeID: "mr5", Parents: graveler.CommitParents{"c3"}}},
+		{CommitID: "c5", Commit: &graveler.Commit{MetaRangeID: "mr6"}},
+	}
+	rangeIDs := map[graveler.MetaRangeID][]graveler.RangeID{
+		"mr1": {"r1", "r2"},
+		"mr2": {"r2", "r3"},
+		"mr3": {"r4"},
+		"mr4": {"r1", "r5"},
+		"mr5": {"r6"},
+		"mr6": {"r7"},
+	}
+	tests := []struct {
+		name               string
+		includeDangling    bool
+		commitIDs          []graveler.CommitID
+		expectedMetaRanges []graveler.MetaRangeID
+		expectedRanges     []graveler.RangeID
+	}{
+		{
+			name:               "reachable",
+			expectedMetaRanges: []graveler.MetaRangeID{"mr1", "mr2", "mr4"},
+			expectedRanges:     []graveler.RangeID{"r1", "r2", "r3", "r5"},
+		},
+		{
+			name:               "include_dangling",
+			includeDangling:    true,
+			expectedMetaRanges: []graveler.MetaRangeID{"mr1", "mr2", "mr3", "mr4", "mr5", "mr6"},
+			expectedRanges:     []graveler.RangeID{"r1", "r2", "r3", "r4", "r5", "r6", "r7"},
+		},
+		{
+			name:               "commit_ids",
+			commitIDs:          []graveler.CommitID{"c4"},
+			expectedMetaRanges: []graveler.MetaRangeID{"mr1", "mr2", "mr3", "mr4", "mr5"},
+			expectedRanges:     []graveler.RangeID{"r1", "r2", "r3", "r4", "r5", "r6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := &testutil.RefsFake{
+				ListCommitsRes: testutil.NewFakeCommitIterator(commits),
+				ListBranchesRes: testutil.NewFakeBranchIterator([]*graveler.BranchRecord{
+					{BranchID: "main", Branch: &graveler.Branch{CommitID: "c2", CompactedBaseMetaRangeID: "mr4"}},
+				}),
+				UnreachableCommits: commits[2:],
+			}
+			committed := &testutil.CommittedFake{RangeIDs: rangeIDs}
+			g := newGraveler(t, committed, nil, refs, nil, testutil.NewProtectedBranchesManagerFake())
+
+			reachable, err := g.FindReachableMetadata(context.Background(), repository, graveler.FindReachableMetadataParams{
+				IncludeDangling: tt.includeDangling,
+				CommitIDs:       tt.commitIDs,
+			})
+			require.NoError(t, err)
+			metaRanges := make(map[graveler.MetaRangeID]struct{})
+			for _, id := range tt.expectedMetaRanges {
+				metaRanges[id] = struct{}{}
+			}
+			ranges := make(map[graveler.RangeID]struct{})
+			for _, id := range tt.expectedRanges {
+				ranges[id] = struct{}{}
+			}
+			require.Equal(t, metaRanges, reachable.MetaRanges)
+			require.Equal(t, ranges, reachable.Ranges)
+		})
+	}
+}
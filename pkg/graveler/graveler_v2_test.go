@@ -3,6 +3,7 @@ package graveler_test
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -317,6 +318,96 @@ func TestGravelerGet(t *testing.T) {
 	})
 }
 
+func TestGravelerFindKeyInRefs(t *testing.T) {
+	ctx := context.Background()
+	setupBranch := func(test *testutil.GravelerTest) {
+		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch1ID)).Times(1).Return(rawRefBranch, nil)
+		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefBranch).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeBranch, BranchRecord: graveler.BranchRecord{BranchID: branch1ID, Branch: &branch1}}, nil)
+	}
+	setupCommit := func(test *testutil.GravelerTest, commitID graveler.CommitID, rawRef graveler.RawRef, commit *graveler.Commit) {
+		test.RefManager.EXPECT().ParseRef(graveler.Ref(commitID)).Times(1).Return(rawRef, nil)
+		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRef).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commitID}}}, nil)
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commitID).Times(1).Return(commit, nil)
+	}
+
+	t.Run("committed", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		setupBranch(test)
+		test.StagingManager.EXPECT().Get(ctx, stagingToken1, key1).Times(1).Return(nil, graveler.ErrNotFound)
+		test.StagingManager.EXPECT().Get(ctx, stagingToken2, key1).Times(1).Return(nil, graveler.ErrNotFound)
+		test.StagingManager.EXPECT().Get(ctx, stagingToken3, key1).Times(1).Return(nil, graveler.ErrNotFound)
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
+		setupCommit(test, commit1ID, rawRefCommit1, &commit1)
+		setupCommit(test, commit2ID, rawRefCommit2, &commit2)
+
+		// the metarange of branch1 and commit1 is checked once, the filter of commit2 excludes the key
+		test.CommittedManager.EXPECT().MayContain(ctx, repository.StorageNamespace, mr1ID, graveler.Key(key1)).Times(1).Return(true, nil)
+		test.CommittedManager.EXPECT().Get(ctx, repository.StorageNamespace, mr1ID, graveler.Key(key1)).Times(1).Return(value1, nil)
+		test.CommittedManager.EXPECT().MayContain(ctx, repository.StorageNamespace, mr2ID, graveler.Key(key1)).Times(1).Return(false, nil)
+
+		refs, err := test.Sut.FindKeyInRefs(ctx, repository, key1, []graveler.Ref{graveler.Ref(branch1ID), graveler.Ref(commit1ID), graveler.Ref(commit2ID)})
+		require.NoError(t, err)
+		require.Equal(t, []graveler.Ref{graveler.Ref(branch1ID), graveler.Ref(commit1ID)}, refs)
+	})
+
+	t.Run("filter false positive", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		setupCommit(test, commit1ID, rawRefCommit1, &commit1)
+		test.CommittedManager.EXPECT().MayContain(ctx, repository.StorageNamespace, mr1ID, graveler.Key(key1)).Times(1).Return(true, nil)
+		test.CommittedManager.EXPECT().Get(ctx, repository.StorageNamespace, mr1ID, graveler.Key(key1)).Times(1).Return(nil, graveler.ErrNotFound)
+
+		refs, err := test.Sut.FindKeyInRefs(ctx, repository, key1, []graveler.Ref{graveler.Ref(commit1ID)})
+		require.NoError(t, err)
+		require.Empty(t, refs)
+	})
+
+	t.Run("deleted on branch", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		setupBranch(test)
+		test.StagingManager.EXPECT().Get(ctx, stagingToken1, key1).Times(1).Return(nil, nil)
+
+		refs, err := test.Sut.FindKeyInRefs(ctx, repository, key1, []graveler.Ref{graveler.Ref(branch1ID)})
+		require.NoError(t, err)
+		require.Empty(t, refs)
+	})
+}
+
+func TestGravelerMove(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("move staged", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_STAGING_WRITE).Return(false, nil)
+		test.RefManager.EXPECT().GetBranch(ctx, repository, branch1ID).Times(2).Return(&branch1, nil)
+		gomock.InOrder(
+			test.StagingManager.EXPECT().Set(ctx, stagingToken1, graveler.Key(key2), value1, false).Times(1).Return(nil),
+			test.StagingManager.EXPECT().Set(ctx, stagingToken1, graveler.Key(key1), nil, true).Times(1).Return(nil),
+		)
+
+		require.NoError(t, test.Sut.Move(ctx, repository, branch1ID, key1, key2, *value1))
+	})
+
+	t.Run("destination exists", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_STAGING_WRITE).Return(false, nil)
+		test.RefManager.EXPECT().GetBranch(ctx, repository, branch1ID).Times(1).Return(&branch1, nil)
+		test.RefManager.EXPECT().ParseRef(graveler.Ref(branch1ID)).Times(1).Return(rawRefBranch, nil)
+		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRefBranch).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeBranch, BranchRecord: graveler.BranchRecord{BranchID: branch1ID, Branch: &branch1}}, nil)
+		test.StagingManager.EXPECT().Get(ctx, stagingToken1, graveler.Key(key2)).Times(1).Return(value2, nil)
+
+		err := test.Sut.Move(ctx, repository, branch1ID, key1, key2, *value1, graveler.WithIfAbsent(true))
+		require.ErrorIs(t, err, graveler.ErrPreconditionFailed)
+	})
+
+	t.Run("onto itself", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_STAGING_WRITE).Return(false, nil)
+
+		err := test.Sut.Move(ctx, repository, branch1ID, key1, key1, *value1)
+		require.ErrorIs(t, err, graveler.ErrInvalidValue)
+	})
+}
+
 func TestGravelerMerge(t *testing.T) {
 	ctx := context.Background()
 
@@ -1045,3 +1136,119 @@ func TestGravelerImport(t *testing.T) {
 		importTest(t, graveler.Metadata{"key": "value"})
 	})
 }
+
+func TestGravelerSplitCommit(t *testing.T) {
+	ctx := context.Background()
+	baseCommit := graveler.Commit{MetaRangeID: mr4ID, Generation: 1}
+	splitCommit := graveler.Commit{
+		Message:     "import",
+		MetaRangeID: mr2ID,
+		Parents:     []graveler.CommitID{commit4ID},
+		Metadata:    graveler.Metadata{"key": "value"},
+		Generation:  2,
+	}
+	headCommit := graveler.Commit{
+		Message:     "after import",
+		MetaRangeID: mr3ID,
+		Parents:     []graveler.CommitID{commit2ID},
+		Generation:  3,
+	}
+	dereferenceSplitCommit := func(test *testutil.GravelerTest, commit *graveler.Commit) {
+		rawRef := graveler.RawRef{BaseRef: string(commit2ID)}
+		test.RefManager.EXPECT().ParseRef(graveler.Ref(commit2ID)).Times(1).Return(rawRef, nil)
+		test.RefManager.EXPECT().ResolveRawRef(ctx, repository, rawRef).Times(1).Return(&graveler.ResolvedRef{Type: graveler.ReferenceTypeCommit, BranchRecord: graveler.BranchRecord{Branch: &graveler.Branch{CommitID: commit2ID}}}, nil)
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commit2ID).Times(1).Return(commit, nil)
+	}
+
+	t.Run("split successful", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
+		dereferenceSplitCommit(test, &splitCommit)
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commit3ID).Times(1).Return(&headCommit, nil)
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commit4ID).Times(1).Return(&baseCommit, nil)
+		test.CommittedManager.EXPECT().Diff(ctx, repository.StorageNamespace, mr4ID, mr2ID).Times(3).
+			DoAndReturn(func(context.Context, graveler.StorageNamespace, graveler.MetaRangeID, graveler.MetaRangeID) (graveler.DiffIterator, error) {
+				return testutil.NewDiffIter([]graveler.Diff{
+					{Type: graveler.DiffTypeAdded, Key: graveler.Key("a/1"), Value: value1},
+					{Type: graveler.DiffTypeChanged, Key: graveler.Key("b/1"), Value: value2},
+					{Type: graveler.DiffTypeRemoved, Key: graveler.Key("c"), Value: value1},
+				}), nil
+			})
+		var commitChanges [][]string
+		test.CommittedManager.EXPECT().Commit(ctx, repository.StorageNamespace, gomock.Any(), gomock.Any(), false).Times(3).
+			DoAndReturn(func(_ context.Context, _ graveler.StorageNamespace, base graveler.MetaRangeID, changes graveler.ValueIterator, _ bool, _ ...graveler.SetOptionsFunc) (graveler.MetaRangeID, graveler.DiffSummary, error) {
+				changed := []string{string(base)}
+				for changes.Next() {
+					v := changes.Value()
+					changed = append(changed, v.Key.String()+":"+strconv.FormatBool(v.Value == nil))
+				}
+				require.NoError(t, changes.Err())
+				commitChanges = append(commitChanges, changed)
+				return graveler.MetaRangeID("split" + strconv.Itoa(len(commitChanges))), graveler.DiffSummary{}, nil
+			})
+		var addedCommits []graveler.Commit
+		test.RefManager.EXPECT().AddCommit(ctx, repository, gomock.Any()).Times(4).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, commit graveler.Commit) (graveler.CommitID, error) {
+				addedCommits = append(addedCommits, commit)
+				return graveler.CommitID("new" + strconv.Itoa(len(addedCommits))), nil
+			})
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+				updatedBranch, err := f(&graveler.Branch{CommitID: commit3ID, StagingToken: stagingToken1})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, graveler.CommitID("new4"), updatedBranch.CommitID)
+				require.Equal(t, stagingToken1, updatedBranch.StagingToken)
+				return nil
+			}).Times(1)
+
+		splitIDs, err := test.Sut.SplitCommit(ctx, repository, branch1ID, graveler.Ref(commit2ID), []graveler.Prefix{"a/", "b/"})
+		require.NoError(t, err)
+		require.Equal(t, []graveler.CommitID{"new1", "new2", "new3"}, splitIDs)
+		require.Equal(t, [][]string{{"mr4", "a/1:false"}, {"split1", "b/1:false"}, {"split2", "c:true"}}, commitChanges)
+
+		require.Len(t, addedCommits, 4)
+		require.Equal(t, "import (a/)", addedCommits[0].Message)
+		require.Equal(t, graveler.Metadata{"key": "value", graveler.SplitSourceMetadataKey: "commit2", graveler.SplitPrefixMetadataKey: "a/"}, addedCommits[0].Metadata)
+		require.Equal(t, graveler.CommitParents{commit4ID}, addedCommits[0].Parents)
+		require.Equal(t, graveler.CommitGeneration(2), addedCommits[0].Generation)
+		require.Equal(t, "import", addedCommits[2].Message)
+		require.Equal(t, graveler.Metadata{"key": "value", graveler.SplitSourceMetadataKey: "commit2"}, addedCommits[2].Metadata)
+		require.Equal(t, graveler.CommitParents{"new2"}, addedCommits[2].Parents)
+		require.Equal(t, graveler.MetaRangeID("split3"), addedCommits[2].MetaRangeID)
+		// the commit after the split commit keeps its contents
+		require.Equal(t, "after import", addedCommits[3].Message)
+		require.Equal(t, mr3ID, addedCommits[3].MetaRangeID)
+		require.Equal(t, graveler.CommitParents{"new3"}, addedCommits[3].Parents)
+		require.Equal(t, graveler.CommitGeneration(5), addedCommits[3].Generation)
+		require.Equal(t, graveler.Metadata{"key": "value"}, splitCommit.Metadata)
+	})
+
+	t.Run("split merge commit", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
+		mergeCommit := splitCommit
+		mergeCommit.Parents = []graveler.CommitID{commit4ID, commit1ID}
+		dereferenceSplitCommit(test, &mergeCommit)
+
+		_, err := test.Sut.SplitCommit(ctx, repository, branch1ID, graveler.Ref(commit2ID), []graveler.Prefix{"a/"})
+		require.ErrorIs(t, err, graveler.ErrSplitMergeCommit)
+	})
+
+	t.Run("split commit not on branch", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		test.ProtectedBranchesManager.EXPECT().IsBlocked(ctx, repository, branch1ID, graveler.BranchProtectionBlockedAction_COMMIT).Return(false, nil)
+		dereferenceSplitCommit(test, &splitCommit)
+		// the branch head is older than the split commit
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commit4ID).Times(1).Return(&graveler.Commit{MetaRangeID: mr4ID, Parents: []graveler.CommitID{commit1ID}, Generation: 1}, nil)
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+				_, err := f(&graveler.Branch{CommitID: commit4ID, StagingToken: stagingToken1})
+				return err
+			}).Times(1)
+
+		_, err := test.Sut.SplitCommit(ctx, repository, branch1ID, graveler.Ref(commit2ID), []graveler.Prefix{"a/"})
+		require.ErrorIs(t, err, graveler.ErrCommitNotOnBranch)
+	})
+}
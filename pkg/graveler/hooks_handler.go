@@ -47,6 +47,8 @@ type HookRecord struct {
 	PreRunID string
 	// Exists only in tag actions.
 	TagID TagID
+	// Exists only in merge actions. The reference merged into the branch
+	MergeSource Ref
 }
 
 type HooksHandler interface {
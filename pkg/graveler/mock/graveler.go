@@ -74,6 +74,21 @@ func (mr *MockKeyValueStoreMockRecorder) DeleteBatch(ctx, repository, branchID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockKeyValueStore)(nil).DeleteBatch), varargs...)
 }
 
+// FindKeyInRefs mocks base method.
+func (m *MockKeyValueStore) FindKeyInRefs(ctx context.Context, repository *graveler.RepositoryRecord, key graveler.Key, refs []graveler.Ref) ([]graveler.Ref, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindKeyInRefs", ctx, repository, key, refs)
+	ret0, _ := ret[0].([]graveler.Ref)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindKeyInRefs indicates an expected call of FindKeyInRefs.
+func (mr *MockKeyValueStoreMockRecorder) FindKeyInRefs(ctx, repository, key, refs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindKeyInRefs", reflect.TypeOf((*MockKeyValueStore)(nil).FindKeyInRefs), ctx, repository, key, refs)
+}
+
 // Get mocks base method.
 func (m *MockKeyValueStore) Get(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref, key graveler.Key, opts ...graveler.GetOptionsFunc) (*graveler.Value, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +154,25 @@ func (mr *MockKeyValueStoreMockRecorder) List(ctx, repository, ref, batchSize in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockKeyValueStore)(nil).List), ctx, repository, ref, batchSize)
 }
 
+// Move mocks base method.
+func (m *MockKeyValueStore) Move(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, srcKey, destKey graveler.Key, value graveler.Value, opts ...graveler.SetOptionsFunc) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID, srcKey, destKey, value}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Move", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockKeyValueStoreMockRecorder) Move(ctx, repository, branchID, srcKey, destKey, value interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID, srcKey, destKey, value}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockKeyValueStore)(nil).Move), varargs...)
+}
+
 // Set mocks base method.
 func (m *MockKeyValueStore) Set(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, key graveler.Key, value graveler.Value, opts ...graveler.SetOptionsFunc) error {
 	m.ctrl.T.Helper()
@@ -201,6 +235,20 @@ func (mr *MockVersionControllerMockRecorder) AddCommit(ctx, repository, commit i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommit", reflect.TypeOf((*MockVersionController)(nil).AddCommit), varargs...)
 }
 
+// ArchiveRepository mocks base method.
+func (m *MockVersionController) ArchiveRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveRepository", ctx, repositoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveRepository indicates an expected call of ArchiveRepository.
+func (mr *MockVersionControllerMockRecorder) ArchiveRepository(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveRepository", reflect.TypeOf((*MockVersionController)(nil).ArchiveRepository), ctx, repositoryID)
+}
+
 // CherryPick mocks base method.
 func (m *MockVersionController) CherryPick(ctx context.Context, repository *graveler.RepositoryRecord, id graveler.BranchID, reference graveler.Ref, number *int, committer string, commitOverrides *graveler.CommitOverrides, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -445,6 +493,21 @@ func (mr *MockVersionControllerMockRecorder) Diff(ctx, repository, left, right i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockVersionController)(nil).Diff), ctx, repository, left, right)
 }
 
+// DiffPrefixes mocks base method.
+func (m *MockVersionController) DiffPrefixes(ctx context.Context, repository *graveler.RepositoryRecord, left graveler.Ref, leftPrefix graveler.Key, right graveler.Ref, rightPrefix graveler.Key) (graveler.DiffIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffPrefixes", ctx, repository, left, leftPrefix, right, rightPrefix)
+	ret0, _ := ret[0].(graveler.DiffIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffPrefixes indicates an expected call of DiffPrefixes.
+func (mr *MockVersionControllerMockRecorder) DiffPrefixes(ctx, repository, left, leftPrefix, right, rightPrefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffPrefixes", reflect.TypeOf((*MockVersionController)(nil).DiffPrefixes), ctx, repository, left, leftPrefix, right, rightPrefix)
+}
+
 // DiffUncommitted mocks base method.
 func (m *MockVersionController) DiffUncommitted(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (graveler.DiffIterator, error) {
 	m.ctrl.T.Helper()
@@ -477,6 +540,35 @@ func (mr *MockVersionControllerMockRecorder) FindMergeBase(ctx, repository, from
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMergeBase", reflect.TypeOf((*MockVersionController)(nil).FindMergeBase), ctx, repository, from, to)
 }
 
+// FindReachableMetadata mocks base method.
+func (m *MockVersionController) FindReachableMetadata(ctx context.Context, repository *graveler.RepositoryRecord, params graveler.FindReachableMetadataParams) (*graveler.ReachableMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReachableMetadata", ctx, repository, params)
+	ret0, _ := ret[0].(*graveler.ReachableMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReachableMetadata indicates an expected call of FindReachableMetadata.
+func (mr *MockVersionControllerMockRecorder) FindReachableMetadata(ctx, repository, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReachableMetadata", reflect.TypeOf((*MockVersionController)(nil).FindReachableMetadata), ctx, repository, params)
+}
+
+// FindUnreachableCommits mocks base method.
+func (m *MockVersionController) FindUnreachableCommits(ctx context.Context, repository *graveler.RepositoryRecord, fn graveler.UnreachableCommitFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnreachableCommits", ctx, repository, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindUnreachableCommits indicates an expected call of FindUnreachableCommits.
+func (mr *MockVersionControllerMockRecorder) FindUnreachableCommits(ctx, repository, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnreachableCommits", reflect.TypeOf((*MockVersionController)(nil).FindUnreachableCommits), ctx, repository, fn)
+}
+
 // GCGetUncommittedLocation mocks base method.
 func (m *MockVersionController) GCGetUncommittedLocation(repository *graveler.RepositoryRecord, runID string) (string, error) {
 	m.ctrl.T.Helper()
@@ -521,6 +613,21 @@ func (mr *MockVersionControllerMockRecorder) GetBranch(ctx, repository, branchID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranch", reflect.TypeOf((*MockVersionController)(nil).GetBranch), ctx, repository, branchID)
 }
 
+// GetBranchHistoryHead mocks base method.
+func (m *MockVersionController) GetBranchHistoryHead(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchHistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchHistoryHead", ctx, repository, branchID)
+	ret0, _ := ret[0].(*graveler.BranchHistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchHistoryHead indicates an expected call of GetBranchHistoryHead.
+func (mr *MockVersionControllerMockRecorder) GetBranchHistoryHead(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchHistoryHead", reflect.TypeOf((*MockVersionController)(nil).GetBranchHistoryHead), ctx, repository, branchID)
+}
+
 // GetBranchProtectionRules mocks base method.
 func (m *MockVersionController) GetBranchProtectionRules(ctx context.Context, repository *graveler.RepositoryRecord) (*graveler.BranchProtectionRules, *string, error) {
 	m.ctrl.T.Helper()
@@ -662,6 +769,51 @@ func (mr *MockVersionControllerMockRecorder) ListBranches(ctx, repository interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBranches", reflect.TypeOf((*MockVersionController)(nil).ListBranches), ctx, repository)
 }
 
+// ListDeletedRefs mocks base method.
+func (m *MockVersionController) ListDeletedRefs(ctx context.Context, repository *graveler.RepositoryRecord) ([]*graveler.DeletedRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedRefs", ctx, repository)
+	ret0, _ := ret[0].([]*graveler.DeletedRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedRefs indicates an expected call of ListDeletedRefs.
+func (mr *MockVersionControllerMockRecorder) ListDeletedRefs(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedRefs", reflect.TypeOf((*MockVersionController)(nil).ListDeletedRefs), ctx, repository)
+}
+
+// ListRange mocks base method.
+func (m *MockVersionController) ListRange(ctx context.Context, repository *graveler.RepositoryRecord, rangeID graveler.RangeID) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, repository, rangeID)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockVersionControllerMockRecorder) ListRange(ctx, repository, rangeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockVersionController)(nil).ListRange), ctx, repository, rangeID)
+}
+
+// ListRanges mocks base method.
+func (m *MockVersionController) ListRanges(ctx context.Context, repository *graveler.RepositoryRecord, ref graveler.Ref) ([]*graveler.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRanges", ctx, repository, ref)
+	ret0, _ := ret[0].([]*graveler.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRanges indicates an expected call of ListRanges.
+func (mr *MockVersionControllerMockRecorder) ListRanges(ctx, repository, ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRanges", reflect.TypeOf((*MockVersionController)(nil).ListRanges), ctx, repository, ref)
+}
+
 // ListRepositories mocks base method.
 func (m *MockVersionController) ListRepositories(ctx context.Context) (graveler.RepositoryIterator, error) {
 	m.ctrl.T.Helper()
@@ -833,6 +985,60 @@ func (mr *MockVersionControllerMockRecorder) ResolveRawRef(ctx, repository, rawR
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRawRef", reflect.TypeOf((*MockVersionController)(nil).ResolveRawRef), ctx, repository, rawRef)
 }
 
+// RestoreBranch mocks base method.
+func (m *MockVersionController) RestoreBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, opts ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreBranch", varargs...)
+	ret0, _ := ret[0].(*graveler.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBranch indicates an expected call of RestoreBranch.
+func (mr *MockVersionControllerMockRecorder) RestoreBranch(ctx, repository, branchID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBranch", reflect.TypeOf((*MockVersionController)(nil).RestoreBranch), varargs...)
+}
+
+// RestoreRepository mocks base method.
+func (m *MockVersionController) RestoreRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRepository", ctx, repositoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRepository indicates an expected call of RestoreRepository.
+func (mr *MockVersionControllerMockRecorder) RestoreRepository(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRepository", reflect.TypeOf((*MockVersionController)(nil).RestoreRepository), ctx, repositoryID)
+}
+
+// RestoreTag mocks base method.
+func (m *MockVersionController) RestoreTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, tagID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreTag", varargs...)
+	ret0, _ := ret[0].(graveler.CommitID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTag indicates an expected call of RestoreTag.
+func (mr *MockVersionControllerMockRecorder) RestoreTag(ctx, repository, tagID interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, tagID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTag", reflect.TypeOf((*MockVersionController)(nil).RestoreTag), varargs...)
+}
+
 // Revert mocks base method.
 func (m *MockVersionController) Revert(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, parentNumber int, commitParams graveler.CommitParams, commitOverrides *graveler.CommitOverrides, opts ...graveler.SetOptionsFunc) (graveler.CommitID, error) {
 	m.ctrl.T.Helper()
@@ -922,6 +1128,26 @@ func (mr *MockVersionControllerMockRecorder) SetRepositoryMetadata(ctx, reposito
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryMetadata", reflect.TypeOf((*MockVersionController)(nil).SetRepositoryMetadata), ctx, repository, updateFunc)
 }
 
+// SplitCommit mocks base method.
+func (m *MockVersionController) SplitCommit(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, reference graveler.Ref, prefixes []graveler.Prefix, opts ...graveler.SetOptionsFunc) ([]graveler.CommitID, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, repository, branchID, reference, prefixes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SplitCommit", varargs...)
+	ret0, _ := ret[0].([]graveler.CommitID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitCommit indicates an expected call of SplitCommit.
+func (mr *MockVersionControllerMockRecorder) SplitCommit(ctx, repository, branchID, reference, prefixes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, repository, branchID, reference, prefixes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitCommit", reflect.TypeOf((*MockVersionController)(nil).SplitCommit), varargs...)
+}
+
 // UpdateBranch mocks base method.
 func (m *MockVersionController) UpdateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, ref graveler.Ref, opts ...graveler.SetOptionsFunc) (*graveler.Branch, error) {
 	m.ctrl.T.Helper()
@@ -942,6 +1168,21 @@ func (mr *MockVersionControllerMockRecorder) UpdateBranch(ctx, repository, branc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranch", reflect.TypeOf((*MockVersionController)(nil).UpdateBranch), varargs...)
 }
 
+// VerifyBranchHistory mocks base method.
+func (m *MockVersionController) VerifyBranchHistory(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, notarized *graveler.BranchHistoryRecord) (*graveler.BranchHistoryVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyBranchHistory", ctx, repository, branchID, notarized)
+	ret0, _ := ret[0].(*graveler.BranchHistoryVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyBranchHistory indicates an expected call of VerifyBranchHistory.
+func (mr *MockVersionControllerMockRecorder) VerifyBranchHistory(ctx, repository, branchID, notarized interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBranchHistory", reflect.TypeOf((*MockVersionController)(nil).VerifyBranchHistory), ctx, repository, branchID, notarized)
+}
+
 // WriteMetaRangeByIterator mocks base method.
 func (m *MockVersionController) WriteMetaRangeByIterator(ctx context.Context, repository *graveler.RepositoryRecord, it graveler.ValueIterator, opts ...graveler.SetOptionsFunc) (*graveler.MetaRangeID, error) {
 	m.ctrl.T.Helper()
@@ -1878,6 +2119,20 @@ func (mr *MockRefManagerMockRecorder) AddCommit(ctx, repository, commit interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommit", reflect.TypeOf((*MockRefManager)(nil).AddCommit), ctx, repository, commit)
 }
 
+// ArchiveRepository mocks base method.
+func (m *MockRefManager) ArchiveRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveRepository", ctx, repositoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveRepository indicates an expected call of ArchiveRepository.
+func (mr *MockRefManagerMockRecorder) ArchiveRepository(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveRepository", reflect.TypeOf((*MockRefManager)(nil).ArchiveRepository), ctx, repositoryID)
+}
+
 // BranchUpdate mocks base method.
 func (m *MockRefManager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, f graveler.BranchUpdateFunc) error {
 	m.ctrl.T.Helper()
@@ -2045,6 +2300,20 @@ func (mr *MockRefManagerMockRecorder) FindMergeBase(ctx, repository interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMergeBase", reflect.TypeOf((*MockRefManager)(nil).FindMergeBase), varargs...)
 }
 
+// FindUnreachableCommits mocks base method.
+func (m *MockRefManager) FindUnreachableCommits(ctx context.Context, repository *graveler.RepositoryRecord, fn graveler.UnreachableCommitFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnreachableCommits", ctx, repository, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindUnreachableCommits indicates an expected call of FindUnreachableCommits.
+func (mr *MockRefManagerMockRecorder) FindUnreachableCommits(ctx, repository, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnreachableCommits", reflect.TypeOf((*MockRefManager)(nil).FindUnreachableCommits), ctx, repository, fn)
+}
+
 // GCBranchIterator mocks base method.
 func (m *MockRefManager) GCBranchIterator(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
 	m.ctrl.T.Helper()
@@ -2090,6 +2359,21 @@ func (mr *MockRefManagerMockRecorder) GetBranch(ctx, repository, branchID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranch", reflect.TypeOf((*MockRefManager)(nil).GetBranch), ctx, repository, branchID)
 }
 
+// GetBranchHistoryHead mocks base method.
+func (m *MockRefManager) GetBranchHistoryHead(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchHistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchHistoryHead", ctx, repository, branchID)
+	ret0, _ := ret[0].(*graveler.BranchHistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchHistoryHead indicates an expected call of GetBranchHistoryHead.
+func (mr *MockRefManagerMockRecorder) GetBranchHistoryHead(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchHistoryHead", reflect.TypeOf((*MockRefManager)(nil).GetBranchHistoryHead), ctx, repository, branchID)
+}
+
 // GetCommit mocks base method.
 func (m *MockRefManager) GetCommit(ctx context.Context, repository *graveler.RepositoryRecord, commitID graveler.CommitID) (*graveler.Commit, error) {
 	m.ctrl.T.Helper()
@@ -2195,6 +2479,21 @@ func (mr *MockRefManagerMockRecorder) ListCommits(ctx, repository interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockRefManager)(nil).ListCommits), ctx, repository)
 }
 
+// ListDeletedRefs mocks base method.
+func (m *MockRefManager) ListDeletedRefs(ctx context.Context, repository *graveler.RepositoryRecord) ([]*graveler.DeletedRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedRefs", ctx, repository)
+	ret0, _ := ret[0].([]*graveler.DeletedRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedRefs indicates an expected call of ListDeletedRefs.
+func (mr *MockRefManagerMockRecorder) ListDeletedRefs(ctx, repository interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedRefs", reflect.TypeOf((*MockRefManager)(nil).ListDeletedRefs), ctx, repository)
+}
+
 // ListRepositories mocks base method.
 func (m *MockRefManager) ListRepositories(ctx context.Context) (graveler.RepositoryIterator, error) {
 	m.ctrl.T.Helper()
@@ -2284,6 +2583,50 @@ func (mr *MockRefManagerMockRecorder) ResolveRawRef(ctx, repository, rawRef inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRawRef", reflect.TypeOf((*MockRefManager)(nil).ResolveRawRef), ctx, repository, rawRef)
 }
 
+// RestoreBranch mocks base method.
+func (m *MockRefManager) RestoreBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.Branch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBranch", ctx, repository, branchID)
+	ret0, _ := ret[0].(*graveler.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBranch indicates an expected call of RestoreBranch.
+func (mr *MockRefManagerMockRecorder) RestoreBranch(ctx, repository, branchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBranch", reflect.TypeOf((*MockRefManager)(nil).RestoreBranch), ctx, repository, branchID)
+}
+
+// RestoreRepository mocks base method.
+func (m *MockRefManager) RestoreRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRepository", ctx, repositoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRepository indicates an expected call of RestoreRepository.
+func (mr *MockRefManagerMockRecorder) RestoreRepository(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRepository", reflect.TypeOf((*MockRefManager)(nil).RestoreRepository), ctx, repositoryID)
+}
+
+// RestoreTag mocks base method.
+func (m *MockRefManager) RestoreTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (graveler.CommitID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTag", ctx, repository, tagID)
+	ret0, _ := ret[0].(graveler.CommitID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTag indicates an expected call of RestoreTag.
+func (mr *MockRefManagerMockRecorder) RestoreTag(ctx, repository, tagID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTag", reflect.TypeOf((*MockRefManager)(nil).RestoreTag), ctx, repository, tagID)
+}
+
 // SetBranch mocks base method.
 func (m *MockRefManager) SetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
 	m.ctrl.T.Helper()
@@ -2312,6 +2655,21 @@ func (mr *MockRefManagerMockRecorder) SetRepositoryMetadata(ctx, repository, upd
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepositoryMetadata", reflect.TypeOf((*MockRefManager)(nil).SetRepositoryMetadata), ctx, repository, updateFunc)
 }
 
+// VerifyBranchHistory mocks base method.
+func (m *MockRefManager) VerifyBranchHistory(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, notarized *graveler.BranchHistoryRecord) (*graveler.BranchHistoryVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyBranchHistory", ctx, repository, branchID, notarized)
+	ret0, _ := ret[0].(*graveler.BranchHistoryVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyBranchHistory indicates an expected call of VerifyBranchHistory.
+func (mr *MockRefManagerMockRecorder) VerifyBranchHistory(ctx, repository, branchID, notarized interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBranchHistory", reflect.TypeOf((*MockRefManager)(nil).VerifyBranchHistory), ctx, repository, branchID, notarized)
+}
+
 // MockCommittedManager is a mock of CommittedManager interface.
 type MockCommittedManager struct {
 	ctrl     *gomock.Controller
@@ -2496,6 +2854,66 @@ func (mr *MockCommittedManagerMockRecorder) List(ctx, ns, rangeID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCommittedManager)(nil).List), ctx, ns, rangeID)
 }
 
+// ListRange mocks base method.
+func (m *MockCommittedManager) ListRange(ctx context.Context, ns graveler.StorageNamespace, id graveler.RangeID) (graveler.ValueIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, ns, id)
+	ret0, _ := ret[0].(graveler.ValueIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockCommittedManagerMockRecorder) ListRange(ctx, ns, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockCommittedManager)(nil).ListRange), ctx, ns, id)
+}
+
+// ListRangeIDs mocks base method.
+func (m *MockCommittedManager) ListRangeIDs(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) ([]graveler.RangeID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRangeIDs", ctx, ns, id)
+	ret0, _ := ret[0].([]graveler.RangeID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRangeIDs indicates an expected call of ListRangeIDs.
+func (mr *MockCommittedManagerMockRecorder) ListRangeIDs(ctx, ns, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRangeIDs", reflect.TypeOf((*MockCommittedManager)(nil).ListRangeIDs), ctx, ns, id)
+}
+
+// ListRanges mocks base method.
+func (m *MockCommittedManager) ListRanges(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRanges", ctx, ns, id)
+	ret0, _ := ret[0].([]*graveler.RangeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRanges indicates an expected call of ListRanges.
+func (mr *MockCommittedManagerMockRecorder) ListRanges(ctx, ns, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRanges", reflect.TypeOf((*MockCommittedManager)(nil).ListRanges), ctx, ns, id)
+}
+
+// MayContain mocks base method.
+func (m *MockCommittedManager) MayContain(ctx context.Context, ns graveler.StorageNamespace, id graveler.MetaRangeID, key graveler.Key) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MayContain", ctx, ns, id, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MayContain indicates an expected call of MayContain.
+func (mr *MockCommittedManagerMockRecorder) MayContain(ctx, ns, id, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MayContain", reflect.TypeOf((*MockCommittedManager)(nil).MayContain), ctx, ns, id, key)
+}
+
 // Merge mocks base method.
 func (m *MockCommittedManager) Merge(ctx context.Context, ns graveler.StorageNamespace, destination, source, base graveler.MetaRangeID, strategy graveler.MergeStrategy, opts ...graveler.SetOptionsFunc) (graveler.MetaRangeID, error) {
 	m.ctrl.T.Helper()
@@ -2,6 +2,10 @@ package graveler
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/treeverse/lakefs/pkg/kv"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -18,12 +22,16 @@ const (
 	addressesPrefix        = "link-addresses"
 	importsPrefix          = "imports"
 	repoMetadataPrefix     = "repo-metadata"
+	branchHistoryPrefix    = "branch-history"
+	deletedRefsPrefix      = "deleted-refs"
 )
 
 //nolint:gochecknoinits
 func init() {
 	kv.MustRegisterType("graveler", "repos", (&RepositoryData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "branches", (&BranchData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", "branch-history", (&BranchData{}).ProtoReflect().Type())
+	kv.MustRegisterType("*", "deleted-refs", (&BranchData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "commits", (&CommitData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "tags", (&TagData{}).ProtoReflect().Type())
 	kv.MustRegisterType("*", "*", (&StagedEntryData{}).ProtoReflect().Type())
@@ -61,6 +69,76 @@ func BranchPath(branchID BranchID) string {
 	return kv.FormatPath(branchesPrefix, branchID.String())
 }
 
+// BranchHistoriesPrefix - the prefix of the branch history records of all branches
+func BranchHistoriesPrefix() string {
+	return kv.FormatPath(branchHistoryPrefix, "")
+}
+
+// BranchHistoryPrefix - the prefix of the branch history records, ordered from the most recent one
+func BranchHistoryPrefix(branchID BranchID) string {
+	return kv.FormatPath(branchHistoryPrefix, branchID.String(), "")
+}
+
+// BranchHistoryPath - the path of the record of the commit the branch was set to at time t. The time is inverted so
+// that scanning from a time returns the latest record at or before it.
+func BranchHistoryPath(branchID BranchID, t time.Time) string {
+	return BranchHistoryPrefix(branchID) + fmt.Sprintf("%019d", math.MaxInt64-t.UnixNano())
+}
+
+// BranchHistoryTime - the time of the branch history record at path
+func BranchHistoryTime(branchID BranchID, path string) (time.Time, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(path, BranchHistoryPrefix(branchID)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("branch history record %s: %w", path, err)
+	}
+	return time.Unix(0, math.MaxInt64-n), nil
+}
+
+// DeletedRefsPrefix - the prefix of the records of deleted branches and tags, ordered from the most recently deleted
+func DeletedRefsPrefix() string {
+	return kv.FormatPath(deletedRefsPrefix, "")
+}
+
+// DeletedRefsFrom - the path from which the records of refs deleted at or before time t are found. The time is
+// inverted so that records are ordered from the most recently deleted.
+func DeletedRefsFrom(t time.Time) string {
+	return DeletedRefsPrefix() + fmt.Sprintf("%019d", math.MaxInt64-t.UnixNano())
+}
+
+// DeletedRefPath - the path of the record of a branch or tag deleted at time t
+func DeletedRefPath(t time.Time, refType ReferenceType, id string) string {
+	return kv.FormatPath(DeletedRefsFrom(t), deletedRefTypeName(refType), id)
+}
+
+// DeletedRefFromPath - the time, reference type and ID of the deleted ref record at path
+func DeletedRefFromPath(path string) (time.Time, ReferenceType, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, DeletedRefsPrefix()), kv.PathDelimiter, 3)
+	if len(parts) != 3 {
+		return time.Time{}, 0, "", fmt.Errorf("deleted ref record %s: %w", path, ErrInvalidValue)
+	}
+	n, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, "", fmt.Errorf("deleted ref record %s: %w", path, err)
+	}
+	var refType ReferenceType
+	switch parts[1] {
+	case deletedRefTypeName(ReferenceTypeBranch):
+		refType = ReferenceTypeBranch
+	case deletedRefTypeName(ReferenceTypeTag):
+		refType = ReferenceTypeTag
+	default:
+		return time.Time{}, 0, "", fmt.Errorf("deleted ref record %s: %w", path, ErrInvalidValue)
+	}
+	return time.Unix(0, math.MaxInt64-n), refType, parts[2], nil
+}
+
+func deletedRefTypeName(refType ReferenceType) string {
+	if refType == ReferenceTypeTag {
+		return tagsPrefix
+	}
+	return branchesPrefix
+}
+
 func CommitPath(commitID CommitID) string {
 	return kv.FormatPath(commitsPrefix, commitID.String())
 }
@@ -120,7 +198,7 @@ func ProtoFromCommit(commitID CommitID, c *Commit) *CommitData {
 }
 
 func RepoFromProto(pb *RepositoryData) *RepositoryRecord {
-	return &RepositoryRecord{
+	repo := &RepositoryRecord{
 		RepositoryID: RepositoryID(pb.Id),
 		Repository: &Repository{
 			StorageNamespace: StorageNamespace(pb.StorageNamespace),
@@ -131,10 +209,14 @@ func RepoFromProto(pb *RepositoryData) *RepositoryRecord {
 			ReadOnly:         pb.ReadOnly,
 		},
 	}
+	if pb.ArchivedAt != 0 {
+		repo.ArchivedAt = time.Unix(0, pb.ArchivedAt).UTC()
+	}
+	return repo
 }
 
 func ProtoFromRepo(repo *RepositoryRecord) *RepositoryData {
-	return &RepositoryData{
+	pb := &RepositoryData{
 		Id:               repo.RepositoryID.String(),
 		StorageNamespace: repo.Repository.StorageNamespace.String(),
 		DefaultBranchId:  repo.Repository.DefaultBranchID.String(),
@@ -143,6 +225,10 @@ func ProtoFromRepo(repo *RepositoryRecord) *RepositoryData {
 		InstanceUid:      repo.InstanceUID,
 		ReadOnly:         repo.Repository.ReadOnly,
 	}
+	if !repo.ArchivedAt.IsZero() {
+		pb.ArchivedAt = repo.ArchivedAt.UnixNano()
+	}
+	return pb
 }
 
 func StagedEntryFromProto(pb *StagedEntryData) *Value {
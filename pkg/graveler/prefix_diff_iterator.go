@@ -0,0 +1,161 @@
+package graveler
+
+import (
+	"bytes"
+)
+
+// prefixValueIterator lists the values of an iterator under a prefix, with keys relative to the prefix
+type prefixValueIterator struct {
+	it     ValueIterator
+	prefix Key
+	seeked bool
+	value  *ValueRecord
+}
+
+func newPrefixValueIterator(it ValueIterator, prefix Key) *prefixValueIterator {
+	return &prefixValueIterator{it: it, prefix: prefix}
+}
+
+func (p *prefixValueIterator) Next() bool {
+	if !p.seeked {
+		p.it.SeekGE(p.prefix)
+		p.seeked = true
+	}
+	p.value = nil
+	if !p.it.Next() {
+		return false
+	}
+	v := p.it.Value()
+	if !bytes.HasPrefix(v.Key, p.prefix) {
+		return false
+	}
+	p.value = &ValueRecord{
+		Key:   v.Key[len(p.prefix):].Copy(),
+		Value: v.Value,
+	}
+	return true
+}
+
+func (p *prefixValueIterator) SeekGE(id Key) {
+	key := make(Key, 0, len(p.prefix)+len(id))
+	key = append(append(key, p.prefix...), id...)
+	p.it.SeekGE(key)
+	p.seeked = true
+	p.value = nil
+}
+
+func (p *prefixValueIterator) Value() *ValueRecord {
+	return p.value
+}
+
+func (p *prefixValueIterator) Err() error {
+	return p.it.Err()
+}
+
+func (p *prefixValueIterator) Close() {
+	p.it.Close()
+}
+
+type prefixDiffIterator struct {
+	left         *prefixValueIterator
+	right        *prefixValueIterator
+	leftValue    *ValueRecord
+	rightValue   *ValueRecord
+	advanceLeft  bool
+	advanceRight bool
+	value        *Diff
+	err          error
+}
+
+// NewPrefixDiffIterator lists the changes from the values of left under leftPrefix to the values of right under
+// rightPrefix. Keys are relative to the prefixes, so each value under leftPrefix is compared with the value under
+// rightPrefix with the same relative key.
+func NewPrefixDiffIterator(left ValueIterator, leftPrefix Key, right ValueIterator, rightPrefix Key) DiffIterator {
+	return &prefixDiffIterator{
+		left:         newPrefixValueIterator(left, leftPrefix),
+		right:        newPrefixValueIterator(right, rightPrefix),
+		advanceLeft:  true,
+		advanceRight: true,
+	}
+}
+
+func (d *prefixDiffIterator) next(it *prefixValueIterator) *ValueRecord {
+	if it.Next() {
+		return it.Value()
+	}
+	if err := it.Err(); err != nil {
+		d.err = err
+	}
+	return nil
+}
+
+func (d *prefixDiffIterator) Next() bool {
+	for {
+		if d.advanceLeft {
+			d.leftValue = d.next(d.left)
+			d.advanceLeft = false
+		}
+		if d.advanceRight {
+			d.rightValue = d.next(d.right)
+			d.advanceRight = false
+		}
+		d.value = nil
+		if d.err != nil || (d.leftValue == nil && d.rightValue == nil) {
+			return false
+		}
+		switch {
+		case d.rightValue == nil || (d.leftValue != nil && bytes.Compare(d.leftValue.Key, d.rightValue.Key) < 0):
+			d.value = &Diff{
+				Type:         DiffTypeRemoved,
+				Key:          d.leftValue.Key,
+				Value:        d.leftValue.Value,
+				LeftIdentity: d.leftValue.Identity,
+			}
+			d.advanceLeft = true
+			return true
+		case d.leftValue == nil || bytes.Compare(d.leftValue.Key, d.rightValue.Key) > 0:
+			d.value = &Diff{
+				Type:  DiffTypeAdded,
+				Key:   d.rightValue.Key,
+				Value: d.rightValue.Value,
+			}
+			d.advanceRight = true
+			return true
+		default:
+			d.advanceLeft = true
+			d.advanceRight = true
+			if bytes.Equal(d.leftValue.Identity, d.rightValue.Identity) {
+				continue
+			}
+			d.value = &Diff{
+				Type:         DiffTypeChanged,
+				Key:          d.rightValue.Key,
+				Value:        d.rightValue.Value,
+				LeftIdentity: d.leftValue.Identity,
+			}
+			return true
+		}
+	}
+}
+
+func (d *prefixDiffIterator) SeekGE(id Key) {
+	d.left.SeekGE(id)
+	d.right.SeekGE(id)
+	d.advanceLeft = true
+	d.advanceRight = true
+	d.value = nil
+	d.err = nil
+}
+
+func (d *prefixDiffIterator) Value() *Diff {
+	return d.value
+}
+
+func (d *prefixDiffIterator) Err() error {
+	return d.err
+}
+
+func (d *prefixDiffIterator) Close() {
+	d.left.Close()
+	d.right.Close()
+}
@@ -0,0 +1,112 @@
+package graveler_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/catalog/testutils"
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+func TestNewPrefixDiffIterator(t *testing.T) {
+	records := func(kv ...string) []*graveler.ValueRecord {
+		var res []*graveler.ValueRecord
+		for i := 0; i < len(kv); i += 2 {
+			res = append(res, &graveler.ValueRecord{
+				Key:   graveler.Key(kv[i]),
+				Value: &graveler.Value{Identity: []byte(kv[i+1])},
+			})
+		}
+		return res
+	}
+	// both prefixes are listed from the same values, as in a diff of two prefixes of the same ref
+	values := records(
+		"before", "x",
+		"curated/v1/a", "a1",
+		"curated/v1/b", "b1",
+		"curated/v1/c", "c1",
+		"curated/v2/a", "a1",
+		"curated/v2/b", "b2",
+		"curated/v2/d", "d2",
+		"other", "y",
+	)
+	type change struct {
+		Key  string
+		Type graveler.DiffType
+	}
+	tests := []struct {
+		name        string
+		leftPrefix  string
+		rightPrefix string
+		seek        string
+		expected    []change
+	}{
+		{
+			name:        "remapped",
+			leftPrefix:  "curated/v1/",
+			rightPrefix: "curated/v2/",
+			expected: []change{
+				{Key: "b", Type: graveler.DiffTypeChanged},
+				{Key: "c", Type: graveler.DiffTypeRemoved},
+				{Key: "d", Type: graveler.DiffTypeAdded},
+			},
+		},
+		{
+			name:        "reversed",
+			leftPrefix:  "curated/v2/",
+			rightPrefix: "curated/v1/",
+			expected: []change{
+				{Key: "b", Type: graveler.DiffTypeChanged},
+				{Key: "c", Type: graveler.DiffTypeAdded},
+				{Key: "d", Type: graveler.DiffTypeRemoved},
+			},
+		},
+		{
+			name:        "seek",
+			leftPrefix:  "curated/v1/",
+			rightPrefix: "curated/v2/",
+			seek:        "c",
+			expected: []change{
+				{Key: "c", Type: graveler.DiffTypeRemoved},
+				{Key: "d", Type: graveler.DiffTypeAdded},
+			},
+		},
+		{
+			name:        "same prefix",
+			leftPrefix:  "curated/v1/",
+			rightPrefix: "curated/v1/",
+		},
+		{
+			name:        "missing prefix",
+			leftPrefix:  "curated/v1/",
+			rightPrefix: "curated/v3/",
+			expected: []change{
+				{Key: "a", Type: graveler.DiffTypeRemoved},
+				{Key: "b", Type: graveler.DiffTypeRemoved},
+				{Key: "c", Type: graveler.DiffTypeRemoved},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := graveler.NewPrefixDiffIterator(
+				testutils.NewFakeValueIterator(values), graveler.Key(tt.leftPrefix),
+				testutils.NewFakeValueIterator(values), graveler.Key(tt.rightPrefix))
+			defer it.Close()
+			if tt.seek != "" {
+				it.SeekGE(graveler.Key(tt.seek))
+			}
+			var changes []change
+			for it.Next() {
+				v := it.Value()
+				changes = append(changes, change{Key: v.Key.String(), Type: v.Type})
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("diff failed: %s", err)
+			}
+			if diff := deep.Equal(changes, tt.expected); diff != nil {
+				t.Errorf("unexpected changes: %s", diff)
+			}
+		})
+	}
+}
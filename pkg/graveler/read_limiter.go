@@ -0,0 +1,22 @@
+package graveler
+
+import (
+	"context"
+
+	"go.uber.org/ratelimit"
+)
+
+type readLimiterContextKey struct{}
+
+// WithReadLimiter returns a context whose range reads wait on limiter, throttling background work so it doesn't
+// starve range reads serving requests
+func WithReadLimiter(ctx context.Context, limiter ratelimit.Limiter) context.Context {
+	return context.WithValue(ctx, readLimiterContextKey{}, limiter)
+}
+
+// WaitRangeRead waits for the read limiter of ctx before reading a range, if ctx has one
+func WaitRangeRead(ctx context.Context) {
+	if limiter, ok := ctx.Value(readLimiterContextKey{}).(ratelimit.Limiter); ok {
+		_ = limiter.Take()
+	}
+}
@@ -0,0 +1,142 @@
+package ref
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+// branchHistoryHash returns the integrity hash of the branch history record of the commit the branch was set to at
+// time t, chaining previousHash, the integrity hash of the record before it ("" for the first record)
+func branchHistoryHash(previousHash string, branchID graveler.BranchID, commitID graveler.CommitID, t time.Time) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n%d", previousHash, branchID, commitID, t.UnixNano())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func branchHistoryRecordFromProto(branchID graveler.BranchID, key []byte, data *graveler.BranchData) (*graveler.BranchHistoryRecord, error) {
+	t, err := graveler.BranchHistoryTime(branchID, string(key))
+	if err != nil {
+		return nil, err
+	}
+	return &graveler.BranchHistoryRecord{
+		BranchID: branchID,
+		CommitID: graveler.CommitID(data.CommitId),
+		Time:     t,
+		Hash:     data.IntegrityHash,
+	}, nil
+}
+
+func formatBranchHistoryTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// branchHistoryLinkError describes how record breaks the hash chain from the record before it, whose integrity hash
+// is previousHash, or returns "" if it does not. Records written without integrity hashes may only precede the
+// chain, e.g. records written before branch history integrity was enabled.
+func branchHistoryLinkError(record *graveler.BranchHistoryRecord, previousHash string) string {
+	switch {
+	case record.Hash == "" && previousHash != "":
+		return fmt.Sprintf("record at %s has no integrity hash", formatBranchHistoryTime(record.Time))
+	case record.Hash == "":
+		return ""
+	case record.Hash != branchHistoryHash(previousHash, record.BranchID, record.CommitID, record.Time):
+		return fmt.Sprintf("record at %s does not chain the record before it", formatBranchHistoryTime(record.Time))
+	default:
+		return ""
+	}
+}
+
+// GetBranchHistoryHead returns the latest branch history record of the branch. Returns graveler.ErrNotFound when the
+// branch has no history.
+func (m *Manager) GetBranchHistoryHead(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.BranchHistoryRecord, error) {
+	data, key, err := m.getBranchHistoryFrom(ctx, graveler.RepoPartition(repository), branchID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return branchHistoryRecordFromProto(branchID, key, data)
+}
+
+// VerifyBranchHistory verifies that each branch history record of the branch chains the integrity hash of the
+// record before it, that the latest record is the commit of the branch, and that the history includes the notarized
+// record with its hash. The oldest record is not verified against the records deleted by the branch history
+// retention, and a notarized record older than the retention is reported as expired.
+func (m *Manager) VerifyBranchHistory(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, notarized *graveler.BranchHistoryRecord) (*graveler.BranchHistoryVerification, error) {
+	it, err := kv.NewPrimaryIterator(ctx, m.kvStore, (&graveler.BranchData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		[]byte(graveler.BranchHistoryPrefix(branchID)), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	result := &graveler.BranchHistoryVerification{BranchID: branchID}
+	fail := func(format string, args ...interface{}) {
+		if result.Error == "" {
+			result.Error = fmt.Sprintf(format, args...)
+		}
+	}
+	// records are read from the latest one, each one verifying the link of the record after it
+	var after *graveler.BranchHistoryRecord
+	for it.Next() {
+		entry := it.Entry()
+		data, ok := entry.Value.(*graveler.BranchData)
+		if !ok {
+			return nil, fmt.Errorf("branch history record: %w", graveler.ErrReadingFromStore)
+		}
+		record, err := branchHistoryRecordFromProto(branchID, entry.Key, data)
+		if err != nil {
+			return nil, err
+		}
+		if result.Latest == nil {
+			result.Latest = record
+		}
+		if after != nil {
+			if linkErr := branchHistoryLinkError(after, record.Hash); linkErr != "" {
+				fail("%s", linkErr)
+			}
+		}
+		if notarized != nil && record.Time.Equal(notarized.Time) {
+			if record.Hash == notarized.Hash {
+				result.Notarized = true
+			} else {
+				fail("record at %s does not match its notarized hash", formatBranchHistoryTime(record.Time))
+			}
+		}
+		if record.Hash != "" {
+			result.Records++
+		}
+		after = record
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	// the oldest record may chain a record deleted by the retention, it is verified only if it starts the chain
+	if after != nil && after.Hash != "" && (m.branchHistoryRetention <= 0 || after.Time.After(time.Now().Add(-m.branchHistoryRetention))) {
+		if linkErr := branchHistoryLinkError(after, ""); linkErr != "" {
+			fail("%s", linkErr)
+		}
+	}
+
+	if result.Latest != nil && result.Latest.Hash != "" {
+		branch, err := m.GetBranch(ctx, repository, branchID)
+		if err != nil {
+			return nil, err
+		}
+		if branch.CommitID != result.Latest.CommitID {
+			fail("branch commit %s is not the commit of its latest record", branch.CommitID)
+		}
+	}
+	if notarized != nil && !result.Notarized && result.Error == "" {
+		if m.branchHistoryRetention > 0 && notarized.Time.Before(time.Now().Add(-m.branchHistoryRetention)) {
+			result.NotarizationExpired = true
+		} else {
+			fail("notarized record at %s is missing", formatBranchHistoryTime(notarized.Time))
+		}
+	}
+	return result, nil
+}
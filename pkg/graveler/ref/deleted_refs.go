@@ -0,0 +1,153 @@
+package ref
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// trashRef keeps a record of a deleted branch or tag for the trash retention, and deletes the records that
+// expired. Failing to keep the record does not fail the delete, it is logged.
+func (m *Manager) trashRef(ctx context.Context, repository *graveler.RepositoryRecord, refType graveler.ReferenceType, id string, commitID graveler.CommitID) {
+	if m.refTrashRetention <= 0 {
+		return
+	}
+	repoPartition := graveler.RepoPartition(repository)
+	now := time.Now()
+	err := kv.SetMsg(ctx, m.kvStore, repoPartition, []byte(graveler.DeletedRefPath(now, refType, id)), &graveler.BranchData{
+		Id:       id,
+		CommitId: commitID.String(),
+	})
+	if err == nil {
+		err = m.deletePrefix(ctx, repoPartition, []byte(graveler.DeletedRefsPrefix()), []byte(graveler.DeletedRefsFrom(now.Add(-m.refTrashRetention))))
+	}
+	if err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithFields(logging.Fields{"ref": id, "commit_id": commitID}).
+			Warn("Failed to keep deleted ref")
+	}
+}
+
+// listDeletedRefs calls fn with each deleted ref record that did not expire and its key, most recently deleted
+// first, until fn returns false
+func (m *Manager) listDeletedRefs(ctx context.Context, repository *graveler.RepositoryRecord, fn func(ref *graveler.DeletedRef, key []byte) bool) error {
+	if m.refTrashRetention <= 0 {
+		return nil
+	}
+	it, err := kv.NewPrimaryIterator(ctx, m.kvStore, (&graveler.BranchData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		[]byte(graveler.DeletedRefsPrefix()), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	expiry := time.Now().Add(-m.refTrashRetention)
+	for it.Next() {
+		entry := it.Entry()
+		data, ok := entry.Value.(*graveler.BranchData)
+		if !ok {
+			return fmt.Errorf("deleted ref record: %w", graveler.ErrReadingFromStore)
+		}
+		deletedAt, refType, id, err := graveler.DeletedRefFromPath(string(entry.Key))
+		if err != nil {
+			return err
+		}
+		if deletedAt.Before(expiry) {
+			break
+		}
+		ref := &graveler.DeletedRef{
+			Type:      refType,
+			ID:        id,
+			CommitID:  graveler.CommitID(data.CommitId),
+			DeletedAt: deletedAt,
+		}
+		if !fn(ref, entry.Key) {
+			return nil
+		}
+	}
+	return it.Err()
+}
+
+func (m *Manager) ListDeletedRefs(ctx context.Context, repository *graveler.RepositoryRecord) ([]*graveler.DeletedRef, error) {
+	var refs []*graveler.DeletedRef
+	err := m.listDeletedRefs(ctx, repository, func(ref *graveler.DeletedRef, _ []byte) bool {
+		refs = append(refs, ref)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// restoreRef finds the most recent record of the deleted ref, calls restore with it and then deletes all the
+// records of the ref. Returns graveler.ErrNotFound when the ref was not deleted within the trash retention.
+func (m *Manager) restoreRef(ctx context.Context, repository *graveler.RepositoryRecord, refType graveler.ReferenceType, id string, restore func(ref *graveler.DeletedRef) error) error {
+	var (
+		deleted *graveler.DeletedRef
+		keys    [][]byte
+	)
+	err := m.listDeletedRefs(ctx, repository, func(ref *graveler.DeletedRef, key []byte) bool {
+		if ref.Type == refType && ref.ID == id {
+			if deleted == nil {
+				deleted = ref
+			}
+			keys = append(keys, key)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == nil {
+		return graveler.ErrNotFound
+	}
+	if err := restore(deleted); err != nil {
+		return err
+	}
+	repoPartition := []byte(graveler.RepoPartition(repository))
+	for _, key := range keys {
+		if err := m.kvStore.Delete(ctx, repoPartition, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) RestoreBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) (*graveler.Branch, error) {
+	var branch *graveler.Branch
+	err := m.restoreRef(ctx, repository, graveler.ReferenceTypeBranch, branchID.String(), func(ref *graveler.DeletedRef) error {
+		branch = &graveler.Branch{
+			CommitID:     ref.CommitID,
+			StagingToken: graveler.GenerateStagingToken(repository.RepositoryID, branchID),
+		}
+		return m.createBranch(ctx, repository, branchID, *branch)
+	})
+	if errors.Is(err, graveler.ErrNotFound) {
+		err = fmt.Errorf("deleted %w", graveler.ErrBranchNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+func (m *Manager) RestoreTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (graveler.CommitID, error) {
+	var commitID graveler.CommitID
+	err := m.restoreRef(ctx, repository, graveler.ReferenceTypeTag, tagID.String(), func(ref *graveler.DeletedRef) error {
+		commitID = ref.CommitID
+		return m.CreateTag(ctx, repository, tagID, commitID)
+	})
+	if errors.Is(err, graveler.ErrNotFound) {
+		err = fmt.Errorf("deleted %w", graveler.ErrTagNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	return commitID, nil
+}
@@ -21,6 +21,8 @@ const (
 	commitIDStringLength = 64
 	// ImportExpiryTime Expiry time to remove imports from ref-store
 	ImportExpiryTime = 24 * time.Hour
+	// deleteBatchSize number of records read before deleting them
+	deleteBatchSize = 1000
 )
 
 type CacheConfig struct {
@@ -37,6 +39,14 @@ type Manager struct {
 	repoCache       cache.Cache
 	commitCache     cache.Cache
 	maxBatchDelay   time.Duration
+	// archiveGracePeriod is the time an archived repository is kept before it may be deleted
+	archiveGracePeriod time.Duration
+	// branchHistoryRetention is the time branch history records are kept, zero keeps them forever
+	branchHistoryRetention time.Duration
+	// branchHistoryIntegrity chains each branch history record to the record before it using integrity hashes
+	branchHistoryIntegrity bool
+	// refTrashRetention is the time deleted branches and tags can be restored, zero does not keep them
+	refTrashRetention time.Duration
 }
 
 func branchFromProto(pb *graveler.BranchData) *graveler.Branch {
@@ -45,9 +55,10 @@ func branchFromProto(pb *graveler.BranchData) *graveler.Branch {
 		sealedTokens = append(sealedTokens, graveler.StagingToken(st))
 	}
 	branch := &graveler.Branch{
-		CommitID:     graveler.CommitID(pb.CommitId),
-		StagingToken: graveler.StagingToken(pb.StagingToken),
-		SealedTokens: sealedTokens,
+		CommitID:                 graveler.CommitID(pb.CommitId),
+		StagingToken:             graveler.StagingToken(pb.StagingToken),
+		SealedTokens:             sealedTokens,
+		CompactedBaseMetaRangeID: graveler.MetaRangeID(pb.CompactedBaseMetaRangeId),
 	}
 	return branch
 }
@@ -58,10 +69,11 @@ func protoFromBranch(branchID graveler.BranchID, b *graveler.Branch) *graveler.B
 		sealedTokens = append(sealedTokens, st.String())
 	}
 	branch := &graveler.BranchData{
-		Id:           branchID.String(),
-		CommitId:     b.CommitID.String(),
-		StagingToken: b.StagingToken.String(),
-		SealedTokens: sealedTokens,
+		Id:                       branchID.String(),
+		CommitId:                 b.CommitID.String(),
+		StagingToken:             b.StagingToken.String(),
+		SealedTokens:             sealedTokens,
+		CompactedBaseMetaRangeId: b.CompactedBaseMetaRangeID.String(),
 	}
 	return branch
 }
@@ -74,17 +86,29 @@ type ManagerConfig struct {
 	RepositoryCacheConfig CacheConfig
 	CommitCacheConfig     CacheConfig
 	MaxBatchDelay         time.Duration
+	// ArchiveGracePeriod is the time an archived repository is kept before it may be deleted
+	ArchiveGracePeriod time.Duration
+	// BranchHistoryRetention is the time branch history records are kept, zero keeps them forever
+	BranchHistoryRetention time.Duration
+	// BranchHistoryIntegrity chains each branch history record to the record before it using integrity hashes
+	BranchHistoryIntegrity bool
+	// RefTrashRetention is the time deleted branches and tags can be restored, zero does not keep them
+	RefTrashRetention time.Duration
 }
 
 func NewRefManager(cfg ManagerConfig) *Manager {
 	return &Manager{
-		kvStore:         cfg.KVStore,
-		kvStoreLimited:  cfg.KVStoreLimited,
-		addressProvider: cfg.AddressProvider,
-		batchExecutor:   cfg.Executor,
-		repoCache:       newCache(cfg.RepositoryCacheConfig),
-		commitCache:     newCache(cfg.CommitCacheConfig),
-		maxBatchDelay:   cfg.MaxBatchDelay,
+		kvStore:                cfg.KVStore,
+		kvStoreLimited:         cfg.KVStoreLimited,
+		addressProvider:        cfg.AddressProvider,
+		batchExecutor:          cfg.Executor,
+		repoCache:              newCache(cfg.RepositoryCacheConfig),
+		commitCache:            newCache(cfg.CommitCacheConfig),
+		maxBatchDelay:          cfg.MaxBatchDelay,
+		archiveGracePeriod:     cfg.ArchiveGracePeriod,
+		branchHistoryRetention: cfg.BranchHistoryRetention,
+		branchHistoryIntegrity: cfg.BranchHistoryIntegrity,
+		refTrashRetention:      cfg.RefTrashRetention,
 	}
 }
 
@@ -121,6 +145,11 @@ func (m *Manager) GetRepository(ctx context.Context, repositoryID graveler.Repos
 		switch repo.State {
 		case graveler.RepositoryState_ACTIVE:
 			return repo, nil
+		case graveler.RepositoryState_ARCHIVED:
+			// archived repositories are readable, writes are blocked as in read-only repositories. The stored
+			// read-only flag is kept for restoring the repository.
+			repo.ReadOnly = true
+			return repo, nil
 		case graveler.RepositoryState_IN_DELETION:
 			return nil, graveler.ErrRepositoryInDeletion
 		default:
@@ -171,7 +200,7 @@ func (m *Manager) CreateRepository(ctx context.Context, repositoryID graveler.Re
 		StagingToken: graveler.GenerateStagingToken(repositoryID, repository.DefaultBranchID),
 		SealedTokens: nil,
 	}
-	err = m.createBranch(ctx, graveler.RepoPartition(repo), repository.DefaultBranchID, branch)
+	err = m.createBranch(ctx, repo, repository.DefaultBranchID, branch)
 	if err != nil {
 		return nil, err
 	}
@@ -284,6 +313,9 @@ func (m *Manager) DeleteRepository(ctx context.Context, repositoryID graveler.Re
 	if repo.ReadOnly && !options.Force {
 		return graveler.ErrReadOnlyRepository
 	}
+	if repo.State == graveler.RepositoryState_ARCHIVED && time.Since(repo.ArchivedAt) < m.archiveGracePeriod {
+		return fmt.Errorf("repository %s archived at %s: %w", repositoryID, repo.ArchivedAt, graveler.ErrArchiveGracePeriod)
+	}
 
 	// Set repository state to deleted and then perform background delete.
 	if repo.State != graveler.RepositoryState_IN_DELETION {
@@ -297,6 +329,36 @@ func (m *Manager) DeleteRepository(ctx context.Context, repositoryID graveler.Re
 	return m.deleteRepository(ctx, repo)
 }
 
+func (m *Manager) ArchiveRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	repo, err := m.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	switch repo.State {
+	case graveler.RepositoryState_ACTIVE:
+	case graveler.RepositoryState_ARCHIVED:
+		return graveler.ErrRepositoryArchived
+	case graveler.RepositoryState_IN_DELETION:
+		return graveler.ErrRepositoryInDeletion
+	default:
+		return fmt.Errorf("invalid repository state (%d): %w", repo.State, graveler.ErrInvalid)
+	}
+	repo.ArchivedAt = time.Now().UTC()
+	return m.updateRepoState(ctx, repo, graveler.RepositoryState_ARCHIVED)
+}
+
+func (m *Manager) RestoreRepository(ctx context.Context, repositoryID graveler.RepositoryID) error {
+	repo, err := m.getRepository(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if repo.State != graveler.RepositoryState_ARCHIVED {
+		return graveler.ErrRepositoryNotArchived
+	}
+	repo.ArchivedAt = time.Time{}
+	return m.updateRepoState(ctx, repo, graveler.RepositoryState_ACTIVE)
+}
+
 func (m *Manager) getRepositoryMetadata(ctx context.Context, repo *graveler.RepositoryRecord) (graveler.RepositoryMetadata, kv.Predicate, error) {
 	data := graveler.RepoMetadata{}
 	pred, err := kv.GetMsg(ctx, m.kvStore, graveler.RepoPartition(repo), []byte(graveler.RepoMetadataPath()), &data)
@@ -378,20 +440,192 @@ func (m *Manager) GetBranch(ctx context.Context, repository *graveler.Repository
 	return branch, err
 }
 
-func (m *Manager) createBranch(ctx context.Context, repositoryPartition string, branchID graveler.BranchID, branch graveler.Branch) error {
-	err := kv.SetMsgIf(ctx, m.kvStore, repositoryPartition, []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch), nil)
+func (m *Manager) createBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
+	repoPartition := graveler.RepoPartition(repository)
+	err := kv.SetMsgIf(ctx, m.kvStore, repoPartition, []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch), nil)
 	if errors.Is(err, kv.ErrPredicateFailed) {
 		err = graveler.ErrBranchExists
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	// history left by a deleted branch with the same name does not apply to the new branch
+	err = m.deleteBranchHistory(ctx, repoPartition, branchID, nil)
+	if err == nil {
+		err = m.recordBranchHistory(ctx, repository, branchID, "", branch.CommitID)
+	}
+	if err != nil {
+		logBranchHistoryError(ctx, err, branchID, branch.CommitID)
+	}
+	return nil
+}
+
+// logBranchHistoryError logs failing to update the branch history after the branch was updated. Resolving the branch
+// at a time falls back to the commit log before the first record.
+func logBranchHistoryError(ctx context.Context, err error, branchID graveler.BranchID, commitID graveler.CommitID) {
+	logging.FromContext(ctx).
+		WithError(err).
+		WithFields(logging.Fields{"branch": branchID, "commit_id": commitID}).
+		Warn("Failed to record branch history")
+}
+
+// recordBranchHistory adds a branch history record of the commit the branch was set to, after it was updated from
+// previous (empty for a new branch). Record times only move forward, even if the clocks of lakeFS servers are
+// skewed. A change to previous missing from the history, because recording it failed, is recorded first.
+// Records older than the branch history retention are deleted, except the one the branch was set to at that time.
+// With branch history integrity, each record chains the integrity hash of the record before it.
+func (m *Manager) recordBranchHistory(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, previous, commitID graveler.CommitID) error {
+	repoPartition := graveler.RepoPartition(repository)
+	latest, latestKey, err := m.getBranchHistoryFrom(ctx, repoPartition, branchID, nil)
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		return err
+	}
+	var (
+		last         time.Time
+		previousHash string
+	)
+	if latest != nil {
+		if last, err = graveler.BranchHistoryTime(branchID, string(latestKey)); err != nil {
+			return err
+		}
+		previousHash = latest.IntegrityHash
+	}
+	if previous != "" && (latest == nil || graveler.CommitID(latest.CommitId) != previous) {
+		// the branch was most likely set to previous when it was committed
+		repairTime := time.Now()
+		if commit, err := m.GetCommit(ctx, repository, previous); err == nil {
+			repairTime = commit.CreationDate
+		}
+		last = nextBranchHistoryTime(repairTime, last)
+		if previousHash, err = m.setBranchHistory(ctx, repoPartition, branchID, previous, last, previousHash); err != nil {
+			return err
+		}
+	}
+	now := nextBranchHistoryTime(time.Now(), last)
+	if _, err := m.setBranchHistory(ctx, repoPartition, branchID, commitID, now, previousHash); err != nil {
+		return err
+	}
+	if m.branchHistoryRetention <= 0 {
+		return nil
+	}
+	_, keep, err := m.getBranchHistoryFrom(ctx, repoPartition, branchID, []byte(graveler.BranchHistoryPath(branchID, now.Add(-m.branchHistoryRetention))))
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return m.deleteBranchHistory(ctx, repoPartition, branchID, append(keep, 0))
+}
+
+// nextBranchHistoryTime returns t, or the time right after last if t is not after it
+func nextBranchHistoryTime(t, last time.Time) time.Time {
+	if t.After(last) {
+		return t
+	}
+	return last.Add(time.Nanosecond)
+}
+
+// setBranchHistory writes a branch history record, chaining previousHash with branch history integrity, and
+// returns its integrity hash ("" without branch history integrity)
+func (m *Manager) setBranchHistory(ctx context.Context, repositoryPartition string, branchID graveler.BranchID, commitID graveler.CommitID, t time.Time, previousHash string) (string, error) {
+	var hash string
+	if m.branchHistoryIntegrity {
+		hash = branchHistoryHash(previousHash, branchID, commitID, t)
+	}
+	err := kv.SetMsg(ctx, m.kvStore, repositoryPartition, []byte(graveler.BranchHistoryPath(branchID, t)), &graveler.BranchData{
+		Id:            branchID.String(),
+		CommitId:      commitID.String(),
+		IntegrityHash: hash,
+	})
+	return hash, err
+}
+
+// getBranchHistoryFrom returns the first branch history record from start (the latest one when start is empty) and
+// its key. Returns graveler.ErrNotFound when no such record exists.
+func (m *Manager) getBranchHistoryFrom(ctx context.Context, repositoryPartition string, branchID graveler.BranchID, start []byte) (*graveler.BranchData, []byte, error) {
+	it, err := kv.NewPrimaryIterator(ctx, m.kvStore, (&graveler.BranchData{}).ProtoReflect().Type(), repositoryPartition,
+		[]byte(graveler.BranchHistoryPrefix(branchID)), kv.IteratorOptionsFrom(start))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	if !it.Next() {
+		if err := it.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, graveler.ErrNotFound
+	}
+	entry := it.Entry()
+	data, ok := entry.Value.(*graveler.BranchData)
+	if !ok {
+		return nil, nil, fmt.Errorf("branch history record: %w", graveler.ErrReadingFromStore)
+	}
+	return data, entry.Key, nil
+}
+
+// GetBranchCommitAt returns the commit the branch was set to at time t, based on the branch history records.
+// Returns graveler.ErrNotFound when no record exists at or before t.
+func (m *Manager) GetBranchCommitAt(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, t time.Time) (graveler.CommitID, error) {
+	data, _, err := m.getBranchHistoryFrom(ctx, graveler.RepoPartition(repository), branchID, []byte(graveler.BranchHistoryPath(branchID, t)))
+	if err != nil {
+		return "", err
+	}
+	return graveler.CommitID(data.CommitId), nil
+}
+
+// deleteBranchHistory deletes the branch history records from start, all of them when start is empty
+func (m *Manager) deleteBranchHistory(ctx context.Context, repositoryPartition string, branchID graveler.BranchID, start []byte) error {
+	return m.deletePrefix(ctx, repositoryPartition, []byte(graveler.BranchHistoryPrefix(branchID)), start)
+}
+
+// deletePrefix deletes the records under prefix from start, all of them when start is empty. Keys are read in
+// batches and deleted once each batch was read.
+func (m *Manager) deletePrefix(ctx context.Context, repositoryPartition string, prefix, start []byte) error {
+	for {
+		it, err := kv.ScanPrefix(ctx, m.kvStore, []byte(repositoryPartition), prefix, start)
+		if err != nil {
+			return err
+		}
+		keys := make([][]byte, 0, deleteBatchSize)
+		for len(keys) < deleteBatchSize && it.Next() {
+			keys = append(keys, it.Entry().Key)
+		}
+		err = it.Err()
+		it.Close()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := m.kvStore.Delete(ctx, []byte(repositoryPartition), key); err != nil {
+				return err
+			}
+		}
+		if len(keys) < deleteBatchSize {
+			return nil
+		}
+	}
 }
 
 func (m *Manager) CreateBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
-	return m.createBranch(ctx, graveler.RepoPartition(repository), branchID, branch)
+	return m.createBranch(ctx, repository, branchID, branch)
 }
 
 func (m *Manager) SetBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, branch graveler.Branch) error {
-	return kv.SetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch))
+	var previous graveler.CommitID
+	if b, err := m.GetBranch(ctx, repository, branchID); err == nil {
+		previous = b.CommitID
+	}
+	err := kv.SetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, &branch))
+	if err != nil {
+		return err
+	}
+	if previous != branch.CommitID {
+		if err := m.recordBranchHistory(ctx, repository, branchID, previous, branch.CommitID); err != nil {
+			logBranchHistoryError(ctx, err, branchID, branch.CommitID)
+		}
+	}
+	return nil
 }
 
 func (m *Manager) BranchUpdate(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, f graveler.BranchUpdateFunc) error {
@@ -404,15 +638,37 @@ func (m *Manager) BranchUpdate(ctx context.Context, repository *graveler.Reposit
 	if err != nil || newBranch == nil {
 		return err
 	}
-	return kv.SetMsgIf(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, newBranch), pred)
+	err = kv.SetMsgIf(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(graveler.BranchPath(branchID)), protoFromBranch(branchID, newBranch), pred)
+	if err != nil {
+		return err
+	}
+	if newBranch.CommitID != b.CommitID {
+		if err := m.recordBranchHistory(ctx, repository, branchID, b.CommitID, newBranch.CommitID); err != nil {
+			logBranchHistoryError(ctx, err, branchID, newBranch.CommitID)
+		}
+	}
+	return nil
 }
 
 func (m *Manager) DeleteBranch(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID) error {
-	_, err := m.GetBranch(ctx, repository, branchID)
+	branch, err := m.GetBranch(ctx, repository, branchID)
 	if err != nil {
 		return err
 	}
-	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(graveler.BranchPath(branchID)))
+	repoPartition := graveler.RepoPartition(repository)
+	err = m.kvStore.Delete(ctx, []byte(repoPartition), []byte(graveler.BranchPath(branchID)))
+	if err != nil {
+		return err
+	}
+	m.trashRef(ctx, repository, graveler.ReferenceTypeBranch, branchID.String(), branch.CommitID)
+	// the branch is deleted, history left behind is deleted when a branch with the same name is created
+	if err := m.deleteBranchHistory(ctx, repoPartition, branchID, nil); err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithField("branch", branchID).
+			Warn("Failed to delete branch history")
+	}
+	return nil
 }
 
 func (m *Manager) ListBranches(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.BranchIterator, error) {
@@ -462,8 +718,20 @@ func (m *Manager) CreateTag(ctx context.Context, repository *graveler.Repository
 
 func (m *Manager) DeleteTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) error {
 	tagKey := graveler.TagPath(tagID)
+	t := graveler.TagData{}
+	_, err := kv.GetMsg(ctx, m.kvStore, graveler.RepoPartition(repository), []byte(tagKey), &t)
+	if err != nil && !errors.Is(err, kv.ErrNotFound) {
+		return err
+	}
 	// TODO (issue 3640) align with delete tag DB - return ErrNotFound when tag does not exist
-	return m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(tagKey))
+	err = m.kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), []byte(tagKey))
+	if err != nil {
+		return err
+	}
+	if t.CommitId != "" {
+		m.trashRef(ctx, repository, graveler.ReferenceTypeTag, tagID.String(), graveler.CommitID(t.CommitId))
+	}
+	return nil
 }
 
 func (m *Manager) ListTags(ctx context.Context, repository *graveler.RepositoryRecord) (graveler.TagIterator, error) {
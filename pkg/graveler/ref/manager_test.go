@@ -21,6 +21,7 @@ import (
 	"github.com/treeverse/lakefs/pkg/graveler/ref"
 	"github.com/treeverse/lakefs/pkg/ident"
 	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
 	"github.com/treeverse/lakefs/pkg/kv/mock"
 	"github.com/treeverse/lakefs/pkg/testutil"
 	"google.golang.org/protobuf/proto"
@@ -302,6 +303,49 @@ func TestManager_DeleteRepository(t *testing.T) {
 	})
 }
 
+func TestManager_ArchiveRepository(t *testing.T) {
+	r, _ := testRefManager(t)
+	ctx := context.Background()
+	repoID := graveler.RepositoryID("archived-repo")
+	_, err := r.CreateRepository(ctx, repoID, graveler.Repository{
+		StorageNamespace: "s3://foo",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	getRecord := func(t *testing.T) *graveler.RepositoryRecord {
+		t.Helper()
+		it, err := r.ListRepositories(ctx)
+		testutil.Must(t, err)
+		defer it.Close()
+		it.SeekGE(repoID)
+		require.True(t, it.Next(), "repository %s not listed", repoID)
+		require.NoError(t, it.Err())
+		return it.Value()
+	}
+
+	testutil.Must(t, r.ArchiveRepository(ctx, repoID))
+	repo := getRecord(t)
+	require.Equal(t, graveler.RepositoryState_ARCHIVED, repo.State)
+	require.False(t, repo.ArchivedAt.IsZero())
+	require.False(t, repo.ReadOnly, "archiving should keep the stored read-only flag")
+
+	err = r.ArchiveRepository(ctx, repoID)
+	require.ErrorIs(t, err, graveler.ErrRepositoryArchived)
+
+	testutil.Must(t, r.RestoreRepository(ctx, repoID))
+	repo = getRecord(t)
+	require.Equal(t, graveler.RepositoryState_ACTIVE, repo.State)
+	require.True(t, repo.ArchivedAt.IsZero())
+
+	err = r.RestoreRepository(ctx, repoID)
+	require.ErrorIs(t, err, graveler.ErrRepositoryNotArchived)
+
+	err = r.ArchiveRepository(ctx, "no-such-repo")
+	require.ErrorIs(t, err, graveler.ErrRepositoryNotFound)
+}
+
 func TestManager_GetBranch(t *testing.T) {
 	r, _ := testRefManager(t)
 	repository, err := r.CreateRepository(context.Background(), "repo1", graveler.Repository{
@@ -503,6 +547,213 @@ func TestManager_DeleteBranch(t *testing.T) {
 	}
 }
 
+func TestManager_BranchHistory(t *testing.T) {
+	r, kvStore := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c0, err := r.AddCommit(ctx, repository, graveler.Commit{
+		Message:      "c0",
+		CreationDate: ts,
+		Parents:      graveler.CommitParents{},
+	})
+	testutil.Must(t, err)
+	c1, err := r.AddCommit(ctx, repository, graveler.Commit{
+		Message:      "c1",
+		CreationDate: ts.Add(24 * time.Hour),
+		Parents:      graveler.CommitParents{c0},
+	})
+	testutil.Must(t, err)
+
+	resolveAt := func(branchID graveler.BranchID, at string) graveler.CommitID {
+		t.Helper()
+		rawRef, err := r.ParseRef(graveler.Ref(branchID.String() + "@{" + at + "}"))
+		testutil.Must(t, err)
+		resolved, err := r.ResolveRawRef(ctx, repository, rawRef)
+		testutil.Must(t, err)
+		return resolved.CommitID
+	}
+
+	// move the branch back to c0 - its history resolves to c1 until then, while its commit log only holds c0
+	testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{CommitID: c1}))
+	time.Sleep(time.Millisecond)
+	atC1 := time.Now().UTC().Format(time.RFC3339Nano)
+	time.Sleep(time.Millisecond)
+	testutil.Must(t, r.BranchUpdate(ctx, repository, "branch1", func(b *graveler.Branch) (*graveler.Branch, error) {
+		b.CommitID = c0
+		return b, nil
+	}))
+
+	if got := resolveAt("branch1", atC1); got != c1 {
+		t.Errorf("branch1 at %s resolved to %s, expected %s", atC1, got, c1)
+	}
+	if got := resolveAt("branch1", time.Now().UTC().Format(time.RFC3339Nano)); got != c0 {
+		t.Errorf("branch1 now resolved to %s, expected %s", got, c0)
+	}
+	// before the branch history, fall back to the commit log
+	if got := resolveAt("branch1", "2020-01-01T12:00:00Z"); got != c0 {
+		t.Errorf("branch1 before its history resolved to %s, expected %s", got, c0)
+	}
+
+	// a branch re-created with the same name does not inherit the history
+	testutil.Must(t, r.DeleteBranch(ctx, repository, "branch1"))
+	testutil.Must(t, r.CreateBranch(ctx, repository, "branch1", graveler.Branch{CommitID: c0}))
+	if got := resolveAt("branch1", atC1); got != c0 {
+		t.Errorf("re-created branch1 at %s resolved to %s, expected %s", atC1, got, c0)
+	}
+
+	// a change missing from the history is recorded on the next change, at the time of its commit
+	testutil.Must(t, r.SetBranch(ctx, repository, "branch2", graveler.Branch{CommitID: c1}))
+	for _, key := range branchHistoryKeys(t, kvStore, repository, "branch2") {
+		testutil.Must(t, kvStore.Delete(ctx, []byte(graveler.RepoPartition(repository)), key))
+	}
+	testutil.Must(t, r.SetBranch(ctx, repository, "branch2", graveler.Branch{CommitID: c0}))
+	if got := resolveAt("branch2", "2020-01-03T00:00:00Z"); got != c1 {
+		t.Errorf("branch2 after its repaired change resolved to %s, expected %s", got, c1)
+	}
+}
+
+func TestManager_BranchHistoryRetention(t *testing.T) {
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	r := ref.NewRefManager(ref.ManagerConfig{
+		Executor:               batch.NopExecutor(),
+		KVStore:                kvStore,
+		AddressProvider:        ident.NewHexAddressProvider(),
+		RepositoryCacheConfig:  testRepoCacheConfig,
+		CommitCacheConfig:      testCommitCacheConfig,
+		BranchHistoryRetention: time.Nanosecond,
+	})
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	var commitIDs []graveler.CommitID
+	for i := 0; i < 3; i++ {
+		commitID, err := r.AddCommit(ctx, repository, graveler.Commit{
+			Message:      fmt.Sprintf("c%d", i),
+			CreationDate: time.Now(),
+			Parents:      graveler.CommitParents{},
+		})
+		testutil.Must(t, err)
+		commitIDs = append(commitIDs, commitID)
+		testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{CommitID: commitID}))
+		time.Sleep(time.Millisecond)
+	}
+
+	// the latest record and the one the branch was set to at the retention time are kept
+	keys := branchHistoryKeys(t, kvStore, repository, "branch1")
+	if len(keys) != 2 {
+		t.Fatalf("branch1 has %d history records, expected 2", len(keys))
+	}
+	commitID, err := r.GetBranchCommitAt(ctx, repository, "branch1", time.Now())
+	testutil.Must(t, err)
+	if commitID != commitIDs[2] {
+		t.Errorf("branch1 now at %s, expected %s", commitID, commitIDs[2])
+	}
+}
+
+func TestManager_BranchHistoryIntegrity(t *testing.T) {
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	r := ref.NewRefManager(ref.ManagerConfig{
+		Executor:               batch.NopExecutor(),
+		KVStore:                kvStore,
+		AddressProvider:        ident.NewHexAddressProvider(),
+		RepositoryCacheConfig:  testRepoCacheConfig,
+		CommitCacheConfig:      testCommitCacheConfig,
+		BranchHistoryIntegrity: true,
+	})
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	var commitIDs []graveler.CommitID
+	for i := 0; i < 3; i++ {
+		commitID, err := r.AddCommit(ctx, repository, graveler.Commit{
+			Message:      fmt.Sprintf("c%d", i),
+			CreationDate: time.Now(),
+			Parents:      graveler.CommitParents{},
+		})
+		testutil.Must(t, err)
+		commitIDs = append(commitIDs, commitID)
+	}
+	testutil.Must(t, r.CreateBranch(ctx, repository, "branch1", graveler.Branch{CommitID: commitIDs[0]}))
+	testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{CommitID: commitIDs[1]}))
+	notarized, err := r.GetBranchHistoryHead(ctx, repository, "branch1")
+	testutil.Must(t, err)
+	testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{CommitID: commitIDs[2]}))
+
+	verification, err := r.VerifyBranchHistory(ctx, repository, "branch1", notarized)
+	testutil.Must(t, err)
+	if verification.Error != "" || !verification.Notarized || verification.Records != 3 {
+		t.Fatalf("branch1 verification %+v, expected 3 notarized records without error", verification)
+	}
+	if verification.Latest == nil || verification.Latest.CommitID != commitIDs[2] {
+		t.Errorf("branch1 latest record %+v, expected commit %s", verification.Latest, commitIDs[2])
+	}
+
+	// a notarized record that is not in the history fails verification
+	missing := *notarized
+	missing.Time = notarized.Time.Add(time.Nanosecond)
+	verification, err = r.VerifyBranchHistory(ctx, repository, "branch1", &missing)
+	testutil.Must(t, err)
+	if verification.Error == "" || verification.Notarized {
+		t.Errorf("branch1 verification with missing notarized record %+v, expected error", verification)
+	}
+
+	// rewriting a record breaks the chain
+	partition := graveler.RepoPartition(repository)
+	key := []byte(graveler.BranchHistoryPath("branch1", notarized.Time))
+	testutil.Must(t, kv.SetMsg(ctx, kvStore, partition, key, &graveler.BranchData{
+		Id:            "branch1",
+		CommitId:      commitIDs[0].String(),
+		IntegrityHash: notarized.Hash,
+	}))
+	verification, err = r.VerifyBranchHistory(ctx, repository, "branch1", nil)
+	testutil.Must(t, err)
+	if verification.Error == "" {
+		t.Errorf("branch1 verification with rewritten record %+v, expected error", verification)
+	}
+
+	// setting a branch without recording its history fails verification
+	testutil.Must(t, r.CreateBranch(ctx, repository, "branch2", graveler.Branch{CommitID: commitIDs[0]}))
+	testutil.Must(t, kv.SetMsg(ctx, kvStore, partition, []byte(graveler.BranchPath("branch2")), &graveler.BranchData{
+		Id:       "branch2",
+		CommitId: commitIDs[1].String(),
+	}))
+	verification, err = r.VerifyBranchHistory(ctx, repository, "branch2", nil)
+	testutil.Must(t, err)
+	if verification.Error == "" {
+		t.Errorf("branch2 verification after setting its commit directly %+v, expected error", verification)
+	}
+}
+
+func branchHistoryKeys(t *testing.T, kvStore kv.Store, repository *graveler.RepositoryRecord, branchID graveler.BranchID) [][]byte {
+	t.Helper()
+	it, err := kv.ScanPrefix(context.Background(), kvStore, []byte(graveler.RepoPartition(repository)), []byte(graveler.BranchHistoryPrefix(branchID)), nil)
+	testutil.Must(t, err)
+	defer it.Close()
+	var keys [][]byte
+	for it.Next() {
+		keys = append(keys, it.Entry().Key)
+	}
+	testutil.Must(t, it.Err())
+	return keys
+}
+
 func TestManager_ListBranches(t *testing.T) {
 	r, _ := testRefManager(t)
 	repository, err := r.CreateRepository(context.Background(), "repo1", graveler.Repository{
@@ -635,6 +886,100 @@ func TestManager_DeleteTag(t *testing.T) {
 	}
 }
 
+func TestManager_RestoreRefs(t *testing.T) {
+	newManager := func(kvStore kv.Store, retention time.Duration) graveler.RefManager {
+		return ref.NewRefManager(ref.ManagerConfig{
+			Executor:              batch.NopExecutor(),
+			KVStore:               kvStore,
+			AddressProvider:       ident.NewHexAddressProvider(),
+			RepositoryCacheConfig: testRepoCacheConfig,
+			CommitCacheConfig:     testCommitCacheConfig,
+			RefTrashRetention:     retention,
+		})
+	}
+	ctx := context.Background()
+	kvStore := kvtest.GetStore(ctx, t)
+	r := newManager(kvStore, time.Hour)
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+
+	testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{CommitID: "c1"}))
+	testutil.Must(t, r.DeleteBranch(ctx, repository, "branch1"))
+	testutil.Must(t, r.CreateTag(ctx, repository, "v1", "c2"))
+	testutil.Must(t, r.DeleteTag(ctx, repository, "v1"))
+
+	deleted, err := r.ListDeletedRefs(ctx, repository)
+	testutil.Must(t, err)
+	if len(deleted) != 2 {
+		t.Fatalf("ListDeletedRefs() returned %d refs, expected 2", len(deleted))
+	}
+	if deleted[0].Type != graveler.ReferenceTypeTag || deleted[0].ID != "v1" || deleted[0].CommitID != "c2" {
+		t.Errorf("most recently deleted ref = %+v, expected tag v1", deleted[0])
+	}
+	if deleted[1].Type != graveler.ReferenceTypeBranch || deleted[1].ID != "branch1" || deleted[1].CommitID != "c1" {
+		t.Errorf("deleted ref = %+v, expected branch branch1", deleted[1])
+	}
+
+	t.Run("restore branch", func(t *testing.T) {
+		branch, err := r.RestoreBranch(ctx, repository, "branch1")
+		testutil.Must(t, err)
+		if branch.CommitID != "c1" || branch.StagingToken == "" {
+			t.Errorf("RestoreBranch() = %+v, expected branch at c1 with a staging token", branch)
+		}
+		got, err := r.GetBranch(ctx, repository, "branch1")
+		testutil.Must(t, err)
+		if got.CommitID != "c1" {
+			t.Errorf("restored branch1 at %s, expected c1", got.CommitID)
+		}
+		_, err = r.RestoreBranch(ctx, repository, "branch1")
+		if !errors.Is(err, graveler.ErrBranchNotFound) {
+			t.Errorf("RestoreBranch() after restore err=%v, expected %s", err, graveler.ErrBranchNotFound)
+		}
+	})
+
+	t.Run("restore existing branch", func(t *testing.T) {
+		testutil.Must(t, r.DeleteBranch(ctx, repository, "branch1"))
+		testutil.Must(t, r.CreateBranch(ctx, repository, "branch1", graveler.Branch{CommitID: "c3"}))
+		_, err := r.RestoreBranch(ctx, repository, "branch1")
+		if !errors.Is(err, graveler.ErrBranchExists) {
+			t.Errorf("RestoreBranch() of existing branch err=%v, expected %s", err, graveler.ErrBranchExists)
+		}
+	})
+
+	t.Run("restore tag", func(t *testing.T) {
+		commitID, err := r.RestoreTag(ctx, repository, "v1")
+		testutil.Must(t, err)
+		if commitID != "c2" {
+			t.Errorf("RestoreTag() = %s, expected c2", commitID)
+		}
+		got, err := r.GetTag(ctx, repository, "v1")
+		testutil.Must(t, err)
+		if *got != "c2" {
+			t.Errorf("restored tag v1 at %s, expected c2", *got)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expiring := newManager(kvStore, time.Nanosecond)
+		testutil.Must(t, expiring.SetBranch(ctx, repository, "branch2", graveler.Branch{CommitID: "c1"}))
+		testutil.Must(t, expiring.DeleteBranch(ctx, repository, "branch2"))
+		time.Sleep(time.Millisecond)
+		deleted, err := expiring.ListDeletedRefs(ctx, repository)
+		testutil.Must(t, err)
+		if len(deleted) != 0 {
+			t.Errorf("ListDeletedRefs() after retention returned %d refs, expected none", len(deleted))
+		}
+		_, err = expiring.RestoreBranch(ctx, repository, "branch2")
+		if !errors.Is(err, graveler.ErrBranchNotFound) {
+			t.Errorf("RestoreBranch() after retention err=%v, expected %s", err, graveler.ErrBranchNotFound)
+		}
+	})
+}
+
 func TestManager_ListTags(t *testing.T) {
 	r, _ := testRefManager(t)
 	ctx := context.Background()
@@ -1226,3 +1571,46 @@ func TestManager_SetRepositoryMetadata(t *testing.T) {
 		})
 	}
 }
+
+// BenchmarkManager_CreateBranch measures branch creation on repositories with growing histories.  Creating
+// a branch only writes the branch record, so the time per operation should not depend on the number of
+// commits or branches in the repository.
+func BenchmarkManager_CreateBranch(b *testing.B) {
+	for _, size := range []int{10, 1000, 10000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			r, _ := testRefManager(b)
+			ctx := context.Background()
+			repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+				StorageNamespace: "s3://",
+				CreationDate:     time.Now(),
+				DefaultBranchID:  "main",
+			})
+			testutil.Must(b, err)
+
+			var (
+				commitID graveler.CommitID
+				parents  graveler.CommitParents
+			)
+			for i := 0; i < size; i++ {
+				commitID, err = r.AddCommit(ctx, repository, graveler.Commit{
+					Committer:    "user1",
+					Message:      "message" + strconv.Itoa(i),
+					MetaRangeID:  "deadbeef123",
+					CreationDate: time.Now(),
+					Parents:      parents,
+				})
+				testutil.Must(b, err)
+				testutil.Must(b, r.CreateBranch(ctx, repository, graveler.BranchID("existing-"+strconv.Itoa(i)), graveler.Branch{CommitID: commitID}))
+				parents = graveler.CommitParents{commitID}
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				branchID := graveler.BranchID("bench-" + strconv.Itoa(i))
+				if err := r.CreateBranch(ctx, repository, branchID, graveler.Branch{CommitID: commitID, StagingToken: graveler.GenerateStagingToken(repository.RepositoryID, branchID)}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
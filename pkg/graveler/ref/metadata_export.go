@@ -0,0 +1,325 @@
+package ref
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	MetadataExportRepositoryFilename = "repository.parquet"
+	MetadataExportCommitsFilename    = "commits.parquet"
+	MetadataExportBranchesFilename   = "branches.parquet"
+	MetadataExportTagsFilename       = "tags.parquet"
+
+	metadataExportParallelNum = 4
+	metadataImportBatchSize   = 1000
+)
+
+type RepositoryParquetRow struct {
+	RepositoryID     string            `parquet:"name=repository_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StorageNamespace string            `parquet:"name=storage_namespace, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DefaultBranchID  string            `parquet:"name=default_branch_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreationDate     int64             `parquet:"name=creation_date, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	InstanceUID      string            `parquet:"name=instance_uid, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ReadOnly         bool              `parquet:"name=read_only, type=BOOLEAN"`
+	Metadata         map[string]string `parquet:"name=metadata, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+}
+
+type CommitParquetRow struct {
+	CommitID     string            `parquet:"name=commit_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Version      int32             `parquet:"name=version, type=INT32"`
+	Committer    string            `parquet:"name=committer, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Message      string            `parquet:"name=message, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MetaRangeID  string            `parquet:"name=metarange_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreationDate int64             `parquet:"name=creation_date, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	Parents      []string          `parquet:"name=parents, type=MAP, convertedtype=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Metadata     map[string]string `parquet:"name=metadata, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Generation   int64             `parquet:"name=generation, type=INT64"`
+}
+
+type BranchParquetRow struct {
+	BranchID                 string   `parquet:"name=branch_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CommitID                 string   `parquet:"name=commit_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StagingToken             string   `parquet:"name=staging_token, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SealedTokens             []string `parquet:"name=sealed_tokens, type=MAP, convertedtype=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	CompactedBaseMetaRangeID string   `parquet:"name=compacted_base_metarange_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+type TagParquetRow struct {
+	TagID    string `parquet:"name=tag_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CommitID string `parquet:"name=commit_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ExportMetadata writes the repository record with its commits, branches and tags as parquet files.
+// create is called once for each of the MetadataExport*Filename files, and the returned writer is closed after
+// the file is written.
+func ExportMetadata(ctx context.Context, manager graveler.RefManager, repository *graveler.RepositoryRecord, create func(name string) (io.WriteCloser, error)) error {
+	repositoryMetadata, err := manager.GetRepositoryMetadata(ctx, repository.RepositoryID)
+	if err != nil {
+		return fmt.Errorf("get repository metadata: %w", err)
+	}
+	err = writeParquetFile(create, MetadataExportRepositoryFilename, new(RepositoryParquetRow), func(pw *writer.ParquetWriter) error {
+		return pw.Write(RepositoryParquetRow{
+			RepositoryID:     repository.RepositoryID.String(),
+			StorageNamespace: repository.StorageNamespace.String(),
+			DefaultBranchID:  repository.DefaultBranchID.String(),
+			CreationDate:     repository.CreationDate.UnixMicro(),
+			InstanceUID:      repository.InstanceUID,
+			ReadOnly:         repository.ReadOnly,
+			Metadata:         repositoryMetadata,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeParquetFile(create, MetadataExportCommitsFilename, new(CommitParquetRow), func(pw *writer.ParquetWriter) error {
+		it, err := manager.ListCommits(ctx, repository)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+		for it.Next() {
+			commit := it.Value()
+			err := pw.Write(CommitParquetRow{
+				CommitID:     commit.CommitID.String(),
+				Version:      int32(commit.Version),
+				Committer:    commit.Committer,
+				Message:      commit.Message,
+				MetaRangeID:  commit.MetaRangeID.String(),
+				CreationDate: commit.CreationDate.UnixMicro(),
+				Parents:      commit.Parents.AsStringSlice(),
+				Metadata:     commit.Metadata,
+				Generation:   int64(commit.Generation),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeParquetFile(create, MetadataExportBranchesFilename, new(BranchParquetRow), func(pw *writer.ParquetWriter) error {
+		it, err := manager.ListBranches(ctx, repository)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+		for it.Next() {
+			branch := it.Value()
+			sealedTokens := make([]string, len(branch.SealedTokens))
+			for i, token := range branch.SealedTokens {
+				sealedTokens[i] = token.String()
+			}
+			err := pw.Write(BranchParquetRow{
+				BranchID:                 branch.BranchID.String(),
+				CommitID:                 branch.CommitID.String(),
+				StagingToken:             branch.StagingToken.String(),
+				SealedTokens:             sealedTokens,
+				CompactedBaseMetaRangeID: branch.CompactedBaseMetaRangeID.String(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeParquetFile(create, MetadataExportTagsFilename, new(TagParquetRow), func(pw *writer.ParquetWriter) error {
+		it, err := manager.ListTags(ctx, repository)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+		for it.Next() {
+			tag := it.Value()
+			if err := pw.Write(TagParquetRow{TagID: tag.TagID.String(), CommitID: tag.CommitID.String()}); err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	})
+}
+
+// ImportMetadata creates repositoryID with the commits, branches and tags previously written by ExportMetadata.
+// open is called once for each of the MetadataExport*Filename files.
+// The repository must not exist; its storage namespace and settings are taken from the export. If the import fails
+// after the repository was created, the repository is deleted.
+func ImportMetadata(ctx context.Context, manager graveler.RefManager, repositoryID graveler.RepositoryID, open func(name string) (source.ParquetFile, error)) (*graveler.RepositoryRecord, error) {
+	var repositoryRows []RepositoryParquetRow
+	err := readParquetFile(open, MetadataExportRepositoryFilename, new(RepositoryParquetRow), func(pr *reader.ParquetReader, n int) error {
+		rows := make([]RepositoryParquetRow, n)
+		if err := pr.Read(&rows); err != nil {
+			return err
+		}
+		repositoryRows = append(repositoryRows, rows...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(repositoryRows) != 1 {
+		return nil, fmt.Errorf("%s: expected a single repository, found %d: %w", MetadataExportRepositoryFilename, len(repositoryRows), graveler.ErrInvalid)
+	}
+	repositoryRow := repositoryRows[0]
+	repository, err := manager.CreateBareRepository(ctx, repositoryID, graveler.Repository{
+		StorageNamespace: graveler.StorageNamespace(repositoryRow.StorageNamespace),
+		CreationDate:     time.UnixMicro(repositoryRow.CreationDate).UTC(),
+		DefaultBranchID:  graveler.BranchID(repositoryRow.DefaultBranchID),
+		State:            graveler.RepositoryState_ACTIVE,
+		InstanceUID:      repositoryRow.InstanceUID,
+		ReadOnly:         repositoryRow.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create repository: %w", err)
+	}
+	if err := importRepositoryMetadata(ctx, manager, repository, repositoryRow, open); err != nil {
+		if deleteErr := manager.DeleteRepository(ctx, repositoryID, graveler.WithForce(true)); deleteErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("delete partially imported repository: %w", deleteErr))
+		}
+		return nil, err
+	}
+	return repository, nil
+}
+
+// importRepositoryMetadata imports the repository metadata, commits, branches and tags into the created repository
+func importRepositoryMetadata(ctx context.Context, manager graveler.RefManager, repository *graveler.RepositoryRecord, repositoryRow RepositoryParquetRow, open func(name string) (source.ParquetFile, error)) error {
+	if len(repositoryRow.Metadata) > 0 {
+		err := manager.SetRepositoryMetadata(ctx, repository, func(graveler.RepositoryMetadata) (graveler.RepositoryMetadata, error) {
+			return repositoryRow.Metadata, nil
+		})
+		if err != nil {
+			return fmt.Errorf("set repository metadata: %w", err)
+		}
+	}
+
+	err := readParquetFile(open, MetadataExportCommitsFilename, new(CommitParquetRow), func(pr *reader.ParquetReader, n int) error {
+		rows := make([]CommitParquetRow, n)
+		if err := pr.Read(&rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			parents := make(graveler.CommitParents, len(row.Parents))
+			for i, parent := range row.Parents {
+				parents[i] = graveler.CommitID(parent)
+			}
+			err := manager.CreateCommitRecord(ctx, repository, graveler.CommitID(row.CommitID), graveler.Commit{
+				Version:      graveler.CommitVersion(row.Version),
+				Committer:    row.Committer,
+				Message:      row.Message,
+				MetaRangeID:  graveler.MetaRangeID(row.MetaRangeID),
+				CreationDate: time.UnixMicro(row.CreationDate).UTC(),
+				Parents:      parents,
+				Metadata:     row.Metadata,
+				Generation:   graveler.CommitGeneration(row.Generation),
+			})
+			if err != nil {
+				return fmt.Errorf("commit %s: %w", row.CommitID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = readParquetFile(open, MetadataExportBranchesFilename, new(BranchParquetRow), func(pr *reader.ParquetReader, n int) error {
+		rows := make([]BranchParquetRow, n)
+		if err := pr.Read(&rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			sealedTokens := make([]graveler.StagingToken, len(row.SealedTokens))
+			for i, token := range row.SealedTokens {
+				sealedTokens[i] = graveler.StagingToken(token)
+			}
+			err := manager.CreateBranch(ctx, repository, graveler.BranchID(row.BranchID), graveler.Branch{
+				CommitID:                 graveler.CommitID(row.CommitID),
+				StagingToken:             graveler.StagingToken(row.StagingToken),
+				SealedTokens:             sealedTokens,
+				CompactedBaseMetaRangeID: graveler.MetaRangeID(row.CompactedBaseMetaRangeID),
+			})
+			if err != nil {
+				return fmt.Errorf("branch %s: %w", row.BranchID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = readParquetFile(open, MetadataExportTagsFilename, new(TagParquetRow), func(pr *reader.ParquetReader, n int) error {
+		rows := make([]TagParquetRow, n)
+		if err := pr.Read(&rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := manager.CreateTag(ctx, repository, graveler.TagID(row.TagID), graveler.CommitID(row.CommitID)); err != nil {
+				return fmt.Errorf("tag %s: %w", row.TagID, err)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func writeParquetFile(create func(name string) (io.WriteCloser, error), name string, schema interface{}, write func(pw *writer.ParquetWriter) error) error {
+	w, err := create(name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	pw, err := writer.NewParquetWriterFromWriter(w, schema, metadataExportParallelNum)
+	if err != nil {
+		_ = w.Close()
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	pw.CompressionType = parquet.CompressionCodec_GZIP
+	if err := write(pw); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := pw.WriteStop(); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// readParquetFile opens name and calls read with batches of up to metadataImportBatchSize rows until all rows are read
+func readParquetFile(open func(name string) (source.ParquetFile, error), name string, schema interface{}, read func(pr *reader.ParquetReader, n int) error) error {
+	f, err := open(name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer func() { _ = f.Close() }()
+	pr, err := reader.NewParquetReader(f, schema, metadataExportParallelNum)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer pr.ReadStop()
+	for remaining := int(pr.GetNumRows()); remaining > 0; remaining -= metadataImportBatchSize {
+		if err := read(pr, min(remaining, metadataImportBatchSize)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
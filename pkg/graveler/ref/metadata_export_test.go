@@ -0,0 +1,123 @@
+package ref_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/testutil"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+type nopCloseBuffer struct {
+	bytes.Buffer
+}
+
+func (*nopCloseBuffer) Close() error { return nil }
+
+func TestExportImportMetadata(t *testing.T) {
+	ctx := context.Background()
+	origin, _ := testRefManager(t)
+	repository, err := origin.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://bucket/repo1",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+		InstanceUID:      "instance1",
+	})
+	testutil.Must(t, err)
+	testutil.Must(t, origin.SetRepositoryMetadata(ctx, repository, func(graveler.RepositoryMetadata) (graveler.RepositoryMetadata, error) {
+		return graveler.RepositoryMetadata{"key": "value"}, nil
+	}))
+	mainBranch, err := origin.GetBranch(ctx, repository, "main")
+	testutil.Must(t, err)
+	c1, err := origin.AddCommit(ctx, repository, graveler.Commit{
+		Committer:    "tester",
+		Message:      "c1",
+		MetaRangeID:  "range1",
+		CreationDate: time.Now(),
+		Parents:      graveler.CommitParents{mainBranch.CommitID},
+		Metadata:     graveler.Metadata{"foo": "bar"},
+		Generation:   2,
+	})
+	testutil.Must(t, err)
+	testutil.Must(t, origin.CreateBranch(ctx, repository, "feature", graveler.Branch{
+		CommitID:     c1,
+		StagingToken: "token1",
+		SealedTokens: []graveler.StagingToken{"sealed1", "sealed2"},
+	}))
+	testutil.Must(t, origin.CreateTag(ctx, repository, "v1", c1))
+
+	files := make(map[string]*nopCloseBuffer)
+	err = ref.ExportMetadata(ctx, origin, repository, func(name string) (io.WriteCloser, error) {
+		files[name] = &nopCloseBuffer{}
+		return files[name], nil
+	})
+	testutil.MustDo(t, "export metadata", err)
+
+	target, _ := testRefManager(t)
+	t.Run("failed import", func(t *testing.T) {
+		errOpen := errors.New("open failed")
+		_, err := ref.ImportMetadata(ctx, target, "repo2", func(name string) (source.ParquetFile, error) {
+			if name == ref.MetadataExportTagsFilename {
+				return nil, errOpen
+			}
+			return buffer.NewBufferFileFromBytes(files[name].Bytes()), nil
+		})
+		if !errors.Is(err, errOpen) {
+			t.Fatalf("ImportMetadata err=%v, expected %s", err, errOpen)
+		}
+		if _, err := target.GetRepository(ctx, "repo2"); !errors.Is(err, graveler.ErrRepositoryNotFound) {
+			t.Fatalf("GetRepository after failed import err=%v, expected %s", err, graveler.ErrRepositoryNotFound)
+		}
+	})
+
+	imported, err := ref.ImportMetadata(ctx, target, "repo2", func(name string) (source.ParquetFile, error) {
+		return buffer.NewBufferFileFromBytes(files[name].Bytes()), nil
+	})
+	testutil.MustDo(t, "import metadata", err)
+
+	if imported.StorageNamespace != repository.StorageNamespace || imported.DefaultBranchID != repository.DefaultBranchID {
+		t.Errorf("imported repository %+v, expected %+v", imported.Repository, repository.Repository)
+	}
+	metadata, err := target.GetRepositoryMetadata(ctx, "repo2")
+	testutil.Must(t, err)
+	if diff := deep.Equal(metadata, graveler.RepositoryMetadata{"key": "value"}); diff != nil {
+		t.Errorf("repository metadata diff: %s", diff)
+	}
+
+	for _, commitID := range []graveler.CommitID{mainBranch.CommitID, c1} {
+		expected, err := origin.GetCommit(ctx, repository, commitID)
+		testutil.Must(t, err)
+		got, err := target.GetCommit(ctx, imported, commitID)
+		testutil.MustDo(t, "get imported commit "+commitID.String(), err)
+		if diff := deep.Equal(got.Parents, expected.Parents); diff != nil {
+			t.Errorf("commit %s parents diff: %s", commitID, diff)
+		}
+		if got.Generation != expected.Generation || got.MetaRangeID != expected.MetaRangeID || got.CreationDate.Unix() != expected.CreationDate.Unix() {
+			t.Errorf("commit %s imported as %+v, expected %+v", commitID, got, expected)
+		}
+	}
+
+	for _, branchID := range []graveler.BranchID{"main", "feature"} {
+		expected, err := origin.GetBranch(ctx, repository, branchID)
+		testutil.Must(t, err)
+		got, err := target.GetBranch(ctx, imported, branchID)
+		testutil.MustDo(t, "get imported branch "+branchID.String(), err)
+		if diff := deep.Equal(got, expected); diff != nil {
+			t.Errorf("branch %s diff: %s", branchID, diff)
+		}
+	}
+
+	tagCommitID, err := target.GetTag(ctx, imported, "v1")
+	testutil.MustDo(t, "get imported tag", err)
+	if *tagCommitID != c1 {
+		t.Errorf("tag v1 points to %s, expected %s", *tagCommitID, c1)
+	}
+}
@@ -4,13 +4,63 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
 )
 
 var modifiersRegexp = regexp.MustCompile("(^|[~^@$])[^^~@$]*")
 
+// refTimeLayouts are the time formats accepted by '@{<time>}', times without a zone are in UTC. Refs cannot contain
+// whitespace, so layouts separating the date and the time with a space are not accepted.
+var refTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseRefTime parses the time of an '@{<time>}' modifier: a timestamp in one of refTimeLayouts or Unix epoch seconds
+func parseRefTime(s string) (time.Time, error) {
+	for _, layout := range refTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if seconds, err := strconv.ParseUint(s, 10, 63); err == nil {
+		return time.Unix(int64(seconds), 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("could not parse time %s: %w", s, graveler.ErrInvalidRef)
+}
+
+// parseBracedModifier parses the '@{<time>}' and '^{commit}' modifiers
+func parseBracedModifier(buf string) (graveler.RefModifier, error) {
+	arg := strings.TrimSuffix(buf[2:], "}")
+	switch buf[0] {
+	case '@':
+		t, err := parseRefTime(arg)
+		if err != nil {
+			return graveler.RefModifier{}, err
+		}
+		return graveler.RefModifier{Type: graveler.RefModTypeAtTime, Time: t}, nil
+	case '^':
+		// '^{}' peels a tag like '^{commit}', as tags can only point to commits
+		if arg != "commit" && arg != "" {
+			return graveler.RefModifier{}, fmt.Errorf("unknown object type %s: %w", arg, graveler.ErrInvalidRef)
+		}
+		return graveler.RefModifier{Type: graveler.RefModTypeCommit}, nil
+	default:
+		return graveler.RefModifier{}, graveler.ErrInvalidRef
+	}
+}
+
 func parseRefModifier(buf string) (graveler.RefModifier, error) {
+	if len(buf) > 1 && buf[1] == '{' {
+		if !strings.HasSuffix(buf, "}") {
+			return graveler.RefModifier{}, fmt.Errorf("unterminated modifier %s: %w", buf, graveler.ErrInvalidRef)
+		}
+		return parseBracedModifier(buf)
+	}
 	amount := 1
 	var err error
 	var typ graveler.RefModType
@@ -3,6 +3,7 @@ package ref_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/graveler/ref"
@@ -132,6 +133,76 @@ func TestParseRef(t *testing.T) {
 			Input:       "main^a",
 			ExpectedErr: graveler.ErrInvalidRef,
 		},
+		{
+			Name:  "branch_at_date",
+			Input: "main@{2023-01-01}",
+			Expected: graveler.RawRef{
+				BaseRef: "main",
+				Modifiers: []graveler.RefModifier{
+					{
+						Type: graveler.RefModTypeAtTime,
+						Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			Name:  "branch_at_timestamp_with_tilde",
+			Input: "main@{2023-01-01T10:30:00+02:00}~2",
+			Expected: graveler.RawRef{
+				BaseRef: "main",
+				Modifiers: []graveler.RefModifier{
+					{
+						Type: graveler.RefModTypeAtTime,
+						Time: time.Date(2023, 1, 1, 8, 30, 0, 0, time.UTC),
+					},
+					{
+						Type:  graveler.RefModTypeTilde,
+						Value: 2,
+					},
+				},
+			},
+		},
+		{
+			Name:  "branch_at_epoch",
+			Input: "main@{1672531200}",
+			Expected: graveler.RawRef{
+				BaseRef: "main",
+				Modifiers: []graveler.RefModifier{
+					{
+						Type: graveler.RefModTypeAtTime,
+						Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			Name:        "branch_at_invalid_time",
+			Input:       "main@{last monday}",
+			ExpectedErr: graveler.ErrInvalidRef,
+		},
+		{
+			Name:        "branch_at_unterminated",
+			Input:       "main@{2023-01-01",
+			ExpectedErr: graveler.ErrInvalidRef,
+		},
+		{
+			Name:  "tag_peel_commit",
+			Input: "v1^{commit}",
+			Expected: graveler.RawRef{
+				BaseRef: "v1",
+				Modifiers: []graveler.RefModifier{
+					{
+						Type: graveler.RefModTypeCommit,
+					},
+				},
+			},
+		},
+		{
+			Name:        "tag_peel_unknown_type",
+			Input:       "v1^{tree}",
+			ExpectedErr: graveler.ErrInvalidRef,
+		},
 	}
 
 	for _, cas := range table {
@@ -164,6 +235,10 @@ func TestParseRef(t *testing.T) {
 					t.Fatalf("unexpected modifier at index %d: expected value %d got %d",
 						i, cas.Expected.Modifiers[i].Value, m.Value)
 				}
+				if !m.Time.Equal(cas.Expected.Modifiers[i].Time) {
+					t.Fatalf("unexpected modifier at index %d: expected time %s got %s",
+						i, cas.Expected.Modifiers[i].Time, m.Time)
+				}
 			}
 		})
 	}
@@ -0,0 +1,133 @@
+package ref
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+// FindUnreachableCommits scans all the commits of a repository and calls fn, in commit ID order, for each commit
+// that is not reachable from any branch or tag, or from roots. Only the commit parents are kept in memory, the
+// commits are read again while reporting.
+func FindUnreachableCommits(ctx context.Context, manager graveler.RefManager, repository *graveler.RepositoryRecord, roots []graveler.CommitID, fn graveler.UnreachableCommitFunc) error {
+	// load commits graph
+	parents := make(map[graveler.CommitID]graveler.CommitParents)
+	hasChildren := make(map[graveler.CommitID]struct{})
+	err := iterateCommits(ctx, manager, repository, func(commit *graveler.CommitRecord) error {
+		parents[commit.CommitID] = commit.Parents
+		for _, parent := range commit.Parents {
+			hasChildren[parent] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// collect roots and refs as starting points
+	pending := append([]graveler.CommitID{}, roots...)
+	branchesIt, err := manager.ListBranches(ctx, repository)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	defer branchesIt.Close()
+	for branchesIt.Next() {
+		pending = append(pending, branchesIt.Value().CommitID)
+	}
+	if err := branchesIt.Err(); err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	tagsIt, err := manager.ListTags(ctx, repository)
+	if err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+	defer tagsIt.Close()
+	for tagsIt.Next() {
+		pending = append(pending, tagsIt.Value().CommitID)
+	}
+	if err := tagsIt.Err(); err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+
+	// mark everything reachable from refs, dropping reachable commits from the graph
+	for len(pending) > 0 {
+		commitID := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		commitParents, ok := parents[commitID]
+		if !ok {
+			continue
+		}
+		delete(parents, commitID)
+		pending = append(pending, commitParents...)
+	}
+
+	// report the commits left in the graph
+	return iterateCommits(ctx, manager, repository, func(commit *graveler.CommitRecord) error {
+		if _, ok := parents[commit.CommitID]; !ok {
+			return nil
+		}
+		_, hasChild := hasChildren[commit.CommitID]
+		return fn(commit, !hasChild)
+	})
+}
+
+// FindUnreachableCommits also treats the commits of deleted refs within the trash retention and of branch history
+// records as reachable, as they can still be restored or resolved.
+func (m *Manager) FindUnreachableCommits(ctx context.Context, repository *graveler.RepositoryRecord, fn graveler.UnreachableCommitFunc) error {
+	roots, err := m.retainedCommits(ctx, repository)
+	if err != nil {
+		return err
+	}
+	return FindUnreachableCommits(ctx, m, repository, roots, fn)
+}
+
+// retainedCommits returns the commits kept by the ref manager outside of branches and tags: the commits of deleted
+// refs within the trash retention and of branch history records
+func (m *Manager) retainedCommits(ctx context.Context, repository *graveler.RepositoryRecord) ([]graveler.CommitID, error) {
+	var commitIDs []graveler.CommitID
+	err := m.listDeletedRefs(ctx, repository, func(ref *graveler.DeletedRef, _ []byte) bool {
+		commitIDs = append(commitIDs, ref.CommitID)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list deleted refs: %w", err)
+	}
+	it, err := kv.NewPrimaryIterator(ctx, m.kvStore, (&graveler.BranchData{}).ProtoReflect().Type(), graveler.RepoPartition(repository),
+		[]byte(graveler.BranchHistoriesPrefix()), kv.IteratorOptionsFrom(nil))
+	if err != nil {
+		return nil, fmt.Errorf("list branch history: %w", err)
+	}
+	defer it.Close()
+	for it.Next() {
+		data, ok := it.Entry().Value.(*graveler.BranchData)
+		if !ok {
+			return nil, fmt.Errorf("branch history record: %w", graveler.ErrReadingFromStore)
+		}
+		if data.CommitId != "" {
+			commitIDs = append(commitIDs, graveler.CommitID(data.CommitId))
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("list branch history: %w", err)
+	}
+	return commitIDs, nil
+}
+
+func iterateCommits(ctx context.Context, manager graveler.RefManager, repository *graveler.RepositoryRecord, fn func(commit *graveler.CommitRecord) error) error {
+	it, err := manager.ListCommits(ctx, repository)
+	if err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+	defer it.Close()
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package ref_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/batch"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/ref"
+	"github.com/treeverse/lakefs/pkg/ident"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/testutil"
+)
+
+func TestFindUnreachableCommits(t *testing.T) {
+	r, _ := testRefManager(t)
+	ctx := context.Background()
+	repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+		StorageNamespace: "s3://",
+		CreationDate:     time.Now(),
+		DefaultBranchID:  "main",
+	})
+	testutil.Must(t, err)
+	mainBranch, err := r.GetBranch(ctx, repository, "main")
+	testutil.Must(t, err)
+
+	addCommit := func(message string, parents ...graveler.CommitID) graveler.CommitID {
+		cid, err := r.AddCommit(ctx, repository, graveler.Commit{Message: message, Parents: parents})
+		testutil.MustDo(t, "Add commit "+message, err)
+		return cid
+	}
+	lost1 := addCommit("lost1", mainBranch.CommitID)
+	lost2 := addCommit("lost2", lost1)
+	lost3 := addCommit("lost3", mainBranch.CommitID)
+	tagged := addCommit("tagged", mainBranch.CommitID)
+	testutil.Must(t, r.CreateTag(ctx, repository, "v1", tagged))
+	/*
+	 main---lost1---lost2
+	   |\
+	   | lost3
+	    \
+	     tagged (v1)
+	*/
+
+	var unreachable, heads []graveler.CommitID
+	err = ref.FindUnreachableCommits(ctx, r, repository, nil, func(commit *graveler.CommitRecord, head bool) error {
+		unreachable = append(unreachable, commit.CommitID)
+		if head {
+			heads = append(heads, commit.CommitID)
+		}
+		return nil
+	})
+	testutil.MustDo(t, "find unreachable commits", err)
+
+	sorted := func(ids ...graveler.CommitID) []graveler.CommitID {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+	if diff := deep.Equal(unreachable, sorted(lost1, lost2, lost3)); diff != nil {
+		t.Errorf("unreachable commits diff: %s", diff)
+	}
+	if diff := deep.Equal(heads, sorted(lost2, lost3)); diff != nil {
+		t.Errorf("unreachable heads diff: %s", diff)
+	}
+}
+
+func TestManager_FindUnreachableCommits(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (graveler.RefManager, *graveler.RepositoryRecord, graveler.CommitID) {
+		t.Helper()
+		r := ref.NewRefManager(ref.ManagerConfig{
+			Executor:              batch.NopExecutor(),
+			KVStore:               kvtest.GetStore(ctx, t),
+			AddressProvider:       ident.NewHexAddressProvider(),
+			RepositoryCacheConfig: testRepoCacheConfig,
+			CommitCacheConfig:     testCommitCacheConfig,
+			RefTrashRetention:     time.Hour,
+		})
+		repository, err := r.CreateRepository(ctx, "repo1", graveler.Repository{
+			StorageNamespace: "s3://",
+			CreationDate:     time.Now(),
+			DefaultBranchID:  "main",
+		})
+		testutil.Must(t, err)
+		mainBranch, err := r.GetBranch(ctx, repository, "main")
+		testutil.Must(t, err)
+		return r, repository, mainBranch.CommitID
+	}
+	addCommit := func(t *testing.T, r graveler.RefManager, repository *graveler.RepositoryRecord, message string, parents ...graveler.CommitID) graveler.CommitID {
+		t.Helper()
+		cid, err := r.AddCommit(ctx, repository, graveler.Commit{Message: message, Parents: parents})
+		testutil.MustDo(t, "Add commit "+message, err)
+		return cid
+	}
+	requireUnreachable := func(t *testing.T, r graveler.RefManager, repository *graveler.RepositoryRecord, expected ...graveler.CommitID) {
+		t.Helper()
+		var unreachable []graveler.CommitID
+		err := r.FindUnreachableCommits(ctx, repository, func(commit *graveler.CommitRecord, _ bool) error {
+			unreachable = append(unreachable, commit.CommitID)
+			return nil
+		})
+		testutil.MustDo(t, "find unreachable commits", err)
+		if diff := deep.Equal(unreachable, expected); diff != nil {
+			t.Errorf("unreachable commits diff: %s", diff)
+		}
+	}
+
+	t.Run("deleted_refs", func(t *testing.T) {
+		r, repository, base := setup(t)
+		lost := addCommit(t, r, repository, "lost", base)
+		trashedParent := addCommit(t, r, repository, "trashed parent", base)
+		trashed := addCommit(t, r, repository, "trashed", trashedParent)
+		trashedTag := addCommit(t, r, repository, "trashed tag", base)
+		testutil.Must(t, r.CreateBranch(ctx, repository, "branch1", graveler.Branch{CommitID: trashed}))
+		testutil.Must(t, r.DeleteBranch(ctx, repository, "branch1"))
+		testutil.Must(t, r.CreateTag(ctx, repository, "v1", trashedTag))
+		testutil.Must(t, r.DeleteTag(ctx, repository, "v1"))
+		requireUnreachable(t, r, repository, lost)
+	})
+
+	t.Run("branch_history", func(t *testing.T) {
+		r, repository, base := setup(t)
+		lost := addCommit(t, r, repository, "lost", base)
+		historyParent := addCommit(t, r, repository, "history parent", base)
+		history := addCommit(t, r, repository, "history", historyParent)
+		current := addCommit(t, r, repository, "current", base)
+		// branch1 was set to history before it was reset to current
+		testutil.Must(t, r.CreateBranch(ctx, repository, "branch1", graveler.Branch{CommitID: history}))
+		testutil.Must(t, r.SetBranch(ctx, repository, "branch1", graveler.Branch{CommitID: current}))
+		requireUnreachable(t, r, repository, lost)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"time"
 
 	"github.com/treeverse/lakefs/pkg/graveler"
 	"github.com/treeverse/lakefs/pkg/ident"
@@ -14,6 +15,7 @@ type Store interface {
 	GetTag(ctx context.Context, repository *graveler.RepositoryRecord, tagID graveler.TagID) (*graveler.CommitID, error)
 	GetCommitByPrefix(ctx context.Context, repository *graveler.RepositoryRecord, prefix graveler.CommitID) (*graveler.Commit, error)
 	GetCommit(ctx context.Context, repository *graveler.RepositoryRecord, prefix graveler.CommitID) (*graveler.Commit, error)
+	GetBranchCommitAt(ctx context.Context, repository *graveler.RepositoryRecord, branchID graveler.BranchID, t time.Time) (graveler.CommitID, error)
 }
 
 type revResolverFunc func(context.Context, Store, ident.AddressProvider, *graveler.RepositoryRecord, string) (*graveler.ResolvedRef, error)
@@ -55,7 +57,7 @@ func ResolveRawRef(ctx context.Context, store Store, addressProvider ident.Addre
 		return rr, nil
 	}
 	baseCommit := rr.CommitID
-	for _, mod := range rawRef.Modifiers {
+	for i, mod := range rawRef.Modifiers {
 		// lastly, apply modifier
 		switch mod.Type {
 		case graveler.RefModTypeAt:
@@ -82,9 +84,10 @@ func ResolveRawRef(ctx context.Context, store Store, addressProvider ident.Addre
 				BranchRecord: graveler.BranchRecord{
 					BranchID: rr.BranchID,
 					Branch: &graveler.Branch{
-						CommitID:     rr.CommitID,
-						StagingToken: rr.StagingToken,
-						SealedTokens: rr.SealedTokens,
+						CommitID:                 rr.CommitID,
+						StagingToken:             rr.StagingToken,
+						SealedTokens:             rr.SealedTokens,
+						CompactedBaseMetaRangeID: rr.CompactedBaseMetaRangeID,
 					},
 				},
 			}, nil
@@ -116,6 +119,36 @@ func ResolveRawRef(ctx context.Context, store Store, addressProvider ident.Addre
 			}
 			baseCommit = c.Parents[mod.Value-1]
 
+		case graveler.RefModTypeAtTime:
+			// a branch resolves to the commit it was set to at the requested time, using its history records
+			if i == 0 && rr.Type == graveler.ReferenceTypeBranch {
+				commitID, err := store.GetBranchCommitAt(ctx, repository, rr.BranchID, mod.Time)
+				if err == nil {
+					baseCommit = commitID
+					continue
+				}
+				if !errors.Is(err, graveler.ErrNotFound) {
+					return nil, err
+				}
+			}
+			// otherwise, follow the first-parent history back to the latest commit created at or before the requested time
+			for {
+				commit, err := store.GetCommit(ctx, repository, baseCommit)
+				if err != nil {
+					return nil, err
+				}
+				if !commit.CreationDate.After(mod.Time) {
+					break
+				}
+				if len(commit.Parents) == 0 {
+					return nil, graveler.ErrNotFound
+				}
+				baseCommit = commit.Parents[0]
+			}
+
+		case graveler.RefModTypeCommit:
+			// the reference already resolves to a commit, the modifier only drops the branch staging area
+
 		default:
 			return nil, graveler.ErrInvalidRef
 		}
@@ -190,9 +223,10 @@ func revResolveBranch(ctx context.Context, store Store, _ ident.AddressProvider,
 		BranchRecord: graveler.BranchRecord{
 			BranchID: branchID,
 			Branch: &graveler.Branch{
-				CommitID:     branch.CommitID,
-				StagingToken: branch.StagingToken,
-				SealedTokens: branch.SealedTokens,
+				CommitID:                 branch.CommitID,
+				StagingToken:             branch.StagingToken,
+				SealedTokens:             branch.SealedTokens,
+				CompactedBaseMetaRangeID: branch.CompactedBaseMetaRangeID,
 			},
 		},
 	}, nil
@@ -241,6 +241,56 @@ func TestResolveRawRef(t *testing.T) {
 			Ref:         graveler.Ref(commitCommitID + "~200"),
 			ExpectedErr: graveler.ErrNotFound,
 		},
+		{
+			Name:             "branch_at_time",
+			Ref:              graveler.Ref("branch1@{2020-12-01T15:10:00Z}"),
+			ExpectedCommitID: commitLog[9],
+		},
+		{
+			Name:             "branch_at_time_between_commits",
+			Ref:              graveler.Ref("branch1@{2020-12-01 15:12:30}"),
+			ExpectedCommitID: commitLog[7],
+		},
+		{
+			Name:             "branch_at_time_after_head",
+			Ref:              graveler.Ref("branch1@{2021-01-01}"),
+			ExpectedCommitID: branch1CommitID,
+		},
+		{
+			Name:             "branch_at_time_with_modifier",
+			Ref:              graveler.Ref("branch1@{2020-12-01T15:10:00Z}~2"),
+			ExpectedCommitID: commitLog[11],
+		},
+		{
+			Name:        "branch_at_time_before_history",
+			Ref:         graveler.Ref("branch1@{2020-01-01}"),
+			ExpectedErr: graveler.ErrNotFound,
+		},
+		{
+			Name:        "branch_at_invalid_time",
+			Ref:         graveler.Ref("branch1@{yesterday}"),
+			ExpectedErr: graveler.ErrInvalidRef,
+		},
+		{
+			Name:             "tag_peel_commit",
+			Ref:              graveler.Ref("v1.0^{commit}"),
+			ExpectedCommitID: tagCommitID,
+		},
+		{
+			Name:             "tag_peel_commit_with_modifier",
+			Ref:              graveler.Ref("v1.0^{commit}~1"),
+			ExpectedCommitID: commitLog[10],
+		},
+		{
+			Name:             "branch_peel_commit",
+			Ref:              graveler.Ref("branch1^{commit}"),
+			ExpectedCommitID: branch1CommitID,
+		},
+		{
+			Name:        "tag_peel_unknown_type",
+			Ref:         graveler.Ref("v1.0^{tree}"),
+			ExpectedErr: graveler.ErrInvalidRef,
+		},
 	}
 
 	for _, cas := range table {
@@ -25,10 +25,11 @@ func NewCommitNode(creationDate time.Time, mainParent graveler.CommitID, metaRan
 
 var ErrCommitNotFound = errors.New("commit not found")
 
-// GetGarbageCollectionCommits returns the sets of active commits, according to the repository's garbage collection rules.
+// GetGarbageCollectionCommits returns the sets of active commits, according to the repository's garbage collection rules
+// evaluated at the given time.
 // See https://github.com/treeverse/lakeFS/issues/1932 for more details.
 // Upon completion, the given startingPointIterator is closed.
-func GetGarbageCollectionCommits(ctx context.Context, startingPointIterator *GCStartingPointIterator, commitGetter *RepositoryCommitGetter, rules *graveler.GarbageCollectionRules) (map[graveler.CommitID]graveler.MetaRangeID, error) {
+func GetGarbageCollectionCommits(ctx context.Context, startingPointIterator *GCStartingPointIterator, commitGetter *RepositoryCommitGetter, rules *graveler.GarbageCollectionRules, now time.Time) (map[graveler.CommitID]graveler.MetaRangeID, error) {
 	// From each starting point in the given startingPointIterator, it iterates through its main ancestry.
 	// All commits reached are added to the active set, until and including the first commit performed before the start of the retention period.
 	processed := make(map[graveler.CommitID]time.Time)
@@ -53,7 +54,6 @@ func GetGarbageCollectionCommits(ctx context.Context, startingPointIterator *GCS
 		commitsMap[commitRecord.CommitID] = NewCommitNode(commitRecord.Commit.CreationDate, mainParent, commitRecord.MetaRangeID)
 	}
 
-	now := time.Now()
 	defer startingPointIterator.Close()
 	for startingPointIterator.Next() {
 		startingPoint := startingPointIterator.Value()
@@ -225,7 +225,7 @@ func TestActiveCommits(t *testing.T) {
 	}
 	for name, tst := range tests {
 		t.Run(name, func(t *testing.T) {
-			now := time.Now()
+			now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
 			ctrl := gomock.NewController(t)
 			refManagerMock := mock.NewMockRefManager(ctrl)
 			ctx := context.Background()
@@ -267,7 +267,7 @@ func TestActiveCommits(t *testing.T) {
 				testutil.NewFakeBranchIterator(branches)), &RepositoryCommitGetter{
 				refManager: refManagerMock,
 				repository: repositoryRecord,
-			}, garbageCollectionRules)
+			}, garbageCollectionRules, now)
 			if err != nil {
 				t.Fatalf("failed to find expired commits: %v", err)
 			}
@@ -0,0 +1,25 @@
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+// Clock returns the current time retention rules are evaluated at
+type Clock func() time.Time
+
+type clockContextKey struct{}
+
+// ContextWithTime returns a context evaluating retention rules at tm instead of the current time of the
+// GarbageCollectionManager clock. Used by tests to check retention deterministically.
+func ContextWithTime(ctx context.Context, tm time.Time) context.Context {
+	return context.WithValue(ctx, clockContextKey{}, tm)
+}
+
+// now returns the time set on ctx by ContextWithTime, or the current time of clock
+func now(ctx context.Context, clock Clock) time.Time {
+	if tm, ok := ctx.Value(clockContextKey{}).(time.Time); ok {
+		return tm
+	}
+	return clock()
+}
@@ -0,0 +1,20 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNow(t *testing.T) {
+	clockTime := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return clockTime }
+	ctx := context.Background()
+	if got := now(ctx, clock); !got.Equal(clockTime) {
+		t.Errorf("now() = %s, expected clock time %s", got, clockTime)
+	}
+	overrideTime := clockTime.AddDate(0, 0, 30)
+	if got := now(ContextWithTime(ctx, overrideTime), clock); !got.Equal(overrideTime) {
+		t.Errorf("now() = %s, expected context time %s", got, overrideTime)
+	}
+}
@@ -32,6 +32,16 @@ type GarbageCollectionManager struct {
 	blockAdapter                block.Adapter
 	refManager                  graveler.RefManager
 	committedBlockStoragePrefix string
+	clock                       Clock
+}
+
+type GarbageCollectionManagerOption func(m *GarbageCollectionManager)
+
+// WithClock sets the clock retention rules are evaluated with, instead of the system clock
+func WithClock(clock Clock) GarbageCollectionManagerOption {
+	return func(m *GarbageCollectionManager) {
+		m.clock = clock
+	}
 }
 
 func (m *GarbageCollectionManager) GetCommitsCSVLocation(runID string, sn graveler.StorageNamespace) (string, error) {
@@ -96,12 +106,17 @@ func (r *RepositoryCommitGetter) ListCommits(ctx context.Context) (graveler.Comm
 	return r.refManager.ListCommits(ctx, r.repository)
 }
 
-func NewGarbageCollectionManager(blockAdapter block.Adapter, refManager graveler.RefManager, committedBlockStoragePrefix string) *GarbageCollectionManager {
-	return &GarbageCollectionManager{
+func NewGarbageCollectionManager(blockAdapter block.Adapter, refManager graveler.RefManager, committedBlockStoragePrefix string, opts ...GarbageCollectionManagerOption) *GarbageCollectionManager {
+	m := &GarbageCollectionManager{
 		blockAdapter:                blockAdapter,
 		refManager:                  refManager,
 		committedBlockStoragePrefix: committedBlockStoragePrefix,
+		clock:                       time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 func (m *GarbageCollectionManager) GetRules(ctx context.Context, storageNamespace graveler.StorageNamespace) (*graveler.GarbageCollectionRules, error) {
@@ -166,7 +181,7 @@ func (m *GarbageCollectionManager) SaveGarbageCollectionCommits(ctx context.Cont
 	defer commitIterator.Close()
 	startingPointIterator := NewGCStartingPointIterator(commitIterator, branchIterator)
 	defer startingPointIterator.Close()
-	gcCommits, err := GetGarbageCollectionCommits(ctx, startingPointIterator, commitGetter, rules)
+	gcCommits, err := GetGarbageCollectionCommits(ctx, startingPointIterator, commitGetter, rules, now(ctx, m.clock))
 	if err != nil {
 		return "", fmt.Errorf("find expired commits: %w", err)
 	}
@@ -0,0 +1,208 @@
+package graveler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	// SplitSourceMetadataKey and SplitPrefixMetadataKey record the commit that was split into a commit, and the
+	// prefix of its changes the commit holds. The commit of the remaining changes has no prefix.
+	SplitSourceMetadataKey = ".lakefs.split.source"
+	SplitPrefixMetadataKey = ".lakefs.split.prefix"
+)
+
+// splitPartition returns the index of the first prefix holding key, or len(prefixes) if none of them does
+func splitPartition(prefixes []Prefix, key Key) int {
+	for i, prefix := range prefixes {
+		if bytes.HasPrefix(key, []byte(prefix)) {
+			return i
+		}
+	}
+	return len(prefixes)
+}
+
+// splitChangesIterator lists the changes of a diff in one partition of a split, removed values as tombstones
+type splitChangesIterator struct {
+	diffIt    DiffIterator
+	prefixes  []Prefix
+	partition int
+	value     *ValueRecord
+	err       error
+}
+
+func (s *splitChangesIterator) Next() bool {
+	s.value = nil
+	for s.diffIt.Next() {
+		d := s.diffIt.Value()
+		if splitPartition(s.prefixes, d.Key) != s.partition {
+			continue
+		}
+		record := &ValueRecord{Key: d.Key.Copy()}
+		switch d.Type {
+		case DiffTypeAdded, DiffTypeChanged:
+			record.Value = d.Value
+		case DiffTypeRemoved:
+			// a tombstone removes the key
+		default:
+			s.err = fmt.Errorf("%w: %d", ErrInvalidValue, d.Type)
+			return false
+		}
+		s.value = record
+		return true
+	}
+	s.err = s.diffIt.Err()
+	return false
+}
+
+func (s *splitChangesIterator) SeekGE(id Key) {
+	s.diffIt.SeekGE(id)
+	s.value = nil
+	s.err = nil
+}
+
+func (s *splitChangesIterator) Value() *ValueRecord {
+	return s.value
+}
+
+func (s *splitChangesIterator) Err() error {
+	return s.err
+}
+
+func (s *splitChangesIterator) Close() {
+	s.diffIt.Close()
+}
+
+// SplitCommit replaces the commit 'ref' on the first-parent history of branch 'branchID' with a commit of its changes
+// under each of 'prefixes', in order, followed by a commit of its remaining changes. A change is part of the first
+// prefix holding it, and prefixes without changes are skipped. The commits after it on the branch keep their
+// contents and are recreated on top of the replacing commits, so the branch head moves to a commit with the same
+// contents and uncommitted changes remain staged.
+func (g *Graveler) SplitCommit(ctx context.Context, repository *RepositoryRecord, branchID BranchID, ref Ref, prefixes []Prefix, opts ...SetOptionsFunc) ([]CommitID, error) {
+	options := NewSetOptions(opts)
+	if repository.ReadOnly && !options.Force {
+		return nil, ErrReadOnlyRepository
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("prefixes: %w", ErrRequiredValue)
+	}
+	isProtected, err := g.protectedBranchesManager.IsBlocked(ctx, repository, branchID, BranchProtectionBlockedAction_COMMIT)
+	if err != nil {
+		return nil, err
+	}
+	if isProtected {
+		return nil, ErrCommitToProtectedBranch
+	}
+
+	commitRecord, err := g.dereferenceCommit(ctx, repository, ref)
+	if err != nil {
+		return nil, fmt.Errorf("get commit from ref %s: %w", ref, err)
+	}
+	if len(commitRecord.Parents) != 1 {
+		return nil, ErrSplitMergeCommit
+	}
+
+	var splitIDs []CommitID
+	err = g.retryBranchUpdate(ctx, repository, branchID, func(branch *Branch) (*Branch, error) {
+		// the commits after the split commit, from the branch head
+		descendants, err := g.firstParentDescendants(ctx, repository, branch.CommitID, commitRecord)
+		if err != nil {
+			return nil, err
+		}
+		splitIDs, err = g.addSplitCommits(ctx, repository, commitRecord, prefixes)
+		if err != nil {
+			return nil, err
+		}
+		headID := splitIDs[len(splitIDs)-1]
+		generationDelta := CommitGeneration(len(splitIDs) - 1)
+		for i := len(descendants) - 1; i >= 0; i-- {
+			commit := *descendants[i]
+			commit.Parents = append(CommitParents{headID}, commit.Parents[1:]...)
+			commit.Generation += generationDelta
+			headID, err = g.RefManager.AddCommit(ctx, repository, commit)
+			if err != nil {
+				return nil, fmt.Errorf("add commit: %w", err)
+			}
+		}
+		branch.CommitID = headID
+		return branch, nil
+	}, "split_commit")
+	if err != nil {
+		return nil, err
+	}
+	return splitIDs, nil
+}
+
+// firstParentDescendants returns the commits following commitRecord on the first-parent history of headID, starting
+// with headID
+func (g *Graveler) firstParentDescendants(ctx context.Context, repository *RepositoryRecord, headID CommitID, commitRecord *CommitRecord) ([]*Commit, error) {
+	var descendants []*Commit
+	for commitID := headID; commitID != commitRecord.CommitID; {
+		commit, err := g.RefManager.GetCommit(ctx, repository, commitID)
+		if err != nil {
+			return nil, fmt.Errorf("get commit %s: %w", commitID, err)
+		}
+		// generations decrease along the history, older commits cannot lead to the split commit
+		if len(commit.Parents) == 0 || (commitRecord.Generation > 0 && commit.Generation <= commitRecord.Generation) {
+			return nil, fmt.Errorf("%s: %w", commitRecord.CommitID, ErrCommitNotOnBranch)
+		}
+		descendants = append(descendants, commit)
+		commitID = commit.Parents[0]
+	}
+	return descendants, nil
+}
+
+// addSplitCommits adds the commits replacing commitRecord, each applying the changes of one partition of its
+// changes, and returns their IDs
+func (g *Graveler) addSplitCommits(ctx context.Context, repository *RepositoryRecord, commitRecord *CommitRecord, prefixes []Prefix) ([]CommitID, error) {
+	parentID := commitRecord.Parents[0]
+	parent, err := g.RefManager.GetCommit(ctx, repository, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("get commit %s: %w", parentID, err)
+	}
+	metaRangeID := parent.MetaRangeID
+	generation := parent.Generation
+	var splitIDs []CommitID
+	for partition := 0; partition <= len(prefixes); partition++ {
+		diffIt, err := g.CommittedManager.Diff(ctx, repository.StorageNamespace, parent.MetaRangeID, commitRecord.MetaRangeID)
+		if err != nil {
+			return nil, fmt.Errorf("diff: %w", err)
+		}
+		changes := &splitChangesIterator{diffIt: diffIt, prefixes: prefixes, partition: partition}
+		partitionMetaRangeID, _, err := g.CommittedManager.Commit(ctx, repository.StorageNamespace, metaRangeID, changes, false)
+		changes.Close()
+		if errors.Is(err, ErrNoChanges) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("commit: %w", err)
+		}
+		metaRangeID = partitionMetaRangeID
+
+		commit := *commitRecord.Commit
+		commit.MetaRangeID = metaRangeID
+		commit.Parents = CommitParents{parentID}
+		generation++
+		commit.Generation = generation
+		commit.Metadata = make(Metadata, len(commitRecord.Metadata)+2)
+		for k, v := range commitRecord.Metadata {
+			commit.Metadata[k] = v
+		}
+		commit.Metadata[SplitSourceMetadataKey] = commitRecord.CommitID.String()
+		if partition < len(prefixes) {
+			commit.Message = fmt.Sprintf("%s (%s)", commitRecord.Message, prefixes[partition])
+			commit.Metadata[SplitPrefixMetadataKey] = string(prefixes[partition])
+		}
+		parentID, err = g.RefManager.AddCommit(ctx, repository, commit)
+		if err != nil {
+			return nil, fmt.Errorf("add commit: %w", err)
+		}
+		splitIDs = append(splitIDs, parentID)
+	}
+	if len(splitIDs) < 2 {
+		return nil, fmt.Errorf("%s has changes in a single partition: %w", commitRecord.CommitID, ErrNoChanges)
+	}
+	return splitIDs, nil
+}
@@ -0,0 +1,71 @@
+package sstable
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/cockroachdb/pebble/sstable"
+)
+
+// FormatVersion is the format of the sstable files written for ranges and metaranges.
+// Readers handle files of all versions, so the version may be changed at any time.
+type FormatVersion int
+
+const (
+	// FormatV1 is the original format: snappy block compression, no filters.
+	FormatV1 FormatVersion = 1
+	// FormatV2 uses zstd block compression, and optionally bloom filters to speed up point
+	// lookups.  Files in this format cannot be read by lakeFS versions that predate it, nor by
+	// Spark clients up to v0.14.1.
+	FormatV2 FormatVersion = 2
+)
+
+var ErrInvalidWriterConfig = errors.New("invalid sstable writer config")
+
+// bloomFilterPolicy is the filter policy used by readers.  The bits per key of the filter is
+// stored in each file, so a single policy reads filters written with any bits per key.
+var bloomFilterPolicy = bloom.FilterPolicy(10)
+
+// WriterConfig configures the format of written sstables.  The zero value writes FormatV1.
+type WriterConfig struct {
+	Format FormatVersion
+	// BloomFilterBitsPerKey adds a bloom filter with this many bits per key to FormatV2
+	// files.  0 writes no filter.
+	BloomFilterBitsPerKey int
+}
+
+func (c WriterConfig) Validate() error {
+	switch c.Format {
+	case 0, FormatV1, FormatV2:
+	default:
+		return fmt.Errorf("%w: unknown format version %d", ErrInvalidWriterConfig, c.Format)
+	}
+	if c.BloomFilterBitsPerKey < 0 {
+		return fmt.Errorf("%w: negative bloom filter bits per key %d", ErrInvalidWriterConfig, c.BloomFilterBitsPerKey)
+	}
+	return nil
+}
+
+func (c WriterConfig) writerOptions(collectors ...func() sstable.TablePropertyCollector) sstable.WriterOptions {
+	opts := sstable.WriterOptions{
+		Compression:             sstable.SnappyCompression,
+		TablePropertyCollectors: collectors,
+	}
+	if c.Format == FormatV2 {
+		opts.Compression = sstable.ZstdCompression
+		opts.TableFormat = sstable.TableFormatPebblev2
+		if c.BloomFilterBitsPerKey > 0 {
+			opts.FilterPolicy = bloom.FilterPolicy(c.BloomFilterBitsPerKey)
+			opts.FilterType = sstable.TableFilter
+		}
+	}
+	return opts
+}
+
+// readerFilters returns the filters used by readers when present in a file
+func readerFilters() map[string]sstable.FilterPolicy {
+	return map[string]sstable.FilterPolicy{
+		bloomFilterPolicy.Name(): bloomFilterPolicy,
+	}
+}
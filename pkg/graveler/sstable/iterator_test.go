@@ -122,11 +122,15 @@ type fakeReader struct {
 
 // createSStableReader creates the table from keys, vals passed to it
 func createSStableReader(t *testing.T, keys []string, vals []string) fakeReader {
+	return createSStableReaderWithOptions(t, keys, vals, pebblesst.WriterOptions{
+		Compression: pebblesst.SnappyCompression,
+	}, pebblesst.ReaderOptions{})
+}
+
+func createSStableReaderWithOptions(t *testing.T, keys []string, vals []string, writerOpts pebblesst.WriterOptions, readerOpts pebblesst.ReaderOptions) fakeReader {
 	f, err := os.CreateTemp(os.TempDir(), "test file")
 	require.NoError(t, err)
-	w := pebblesst.NewWriter(f, pebblesst.WriterOptions{
-		Compression: pebblesst.SnappyCompression,
-	})
+	w := pebblesst.NewWriter(f, writerOpts)
 	for i, key := range keys {
 		require.NoError(t, w.Set([]byte(key), []byte(vals[i])))
 	}
@@ -140,7 +144,8 @@ func createSStableReader(t *testing.T, keys []string, vals []string) fakeReader
 	readF, err := os.Open(f.Name())
 	require.NoError(t, err)
 	wf := &wrapReadableFile{readF, 0}
-	ssReader, err := pebblesst.NewReader(wf, pebblesst.ReaderOptions{Cache: cache})
+	readerOpts.Cache = cache
+	ssReader, err := pebblesst.NewReader(wf, readerOpts)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		if wf.NumClosed == 0 {
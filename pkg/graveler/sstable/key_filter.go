@@ -0,0 +1,73 @@
+package sstable
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cockroachdb/pebble/sstable"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/committed"
+)
+
+// keyFilterSuffix is appended to the MetaRange ID to name the file holding its key filter
+const keyFilterSuffix = ".keys.bloom"
+
+var _ committed.KeyFilterManager = &RangeManager{}
+
+type bloomKeyFilter struct {
+	data []byte
+}
+
+func (f *bloomKeyFilter) MayContain(key graveler.Key) bool {
+	return bloomFilterPolicy.MayContain(sstable.TableFilter, f.data, key)
+}
+
+func keyFilterFilename(id committed.ID) string {
+	return string(id) + keyFilterSuffix
+}
+
+func (m *RangeManager) GetKeyFilter(ctx context.Context, ns committed.Namespace, id committed.ID) (committed.KeyFilter, error) {
+	filename := keyFilterFilename(id)
+	exists, err := m.fs.Exists(ctx, string(ns), filename)
+	if err != nil {
+		return nil, fmt.Errorf("check key filter %s %s: %w", ns, id, err)
+	}
+	if !exists {
+		return nil, committed.ErrNotFound
+	}
+	file, err := m.fs.Open(ctx, string(ns), filename)
+	if err != nil {
+		return nil, fmt.Errorf("open key filter %s %s: %w", ns, id, err)
+	}
+	defer m.execAndLog(ctx, file.Close, "close key filter")
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read key filter %s %s: %w", ns, id, err)
+	}
+	return &bloomKeyFilter{data: data}, nil
+}
+
+func (m *RangeManager) WriteKeyFilter(ctx context.Context, ns committed.Namespace, id committed.ID, it graveler.ValueIterator) (committed.KeyFilter, error) {
+	w := bloomFilterPolicy.NewWriter(sstable.TableFilter)
+	for it.Next() {
+		w.AddKey(it.Value().Key)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	data := w.Finish(nil)
+
+	fh, err := m.fs.Create(ctx, string(ns))
+	if err != nil {
+		return nil, fmt.Errorf("create key filter file: %w", err)
+	}
+	if _, err := fh.Write(data); err != nil {
+		m.execAndLog(ctx, func() error { return fh.Abort(ctx) }, "abort key filter")
+		return nil, fmt.Errorf("write key filter: %w", err)
+	}
+	if err := fh.Store(ctx, keyFilterFilename(id)); err != nil {
+		return nil, fmt.Errorf("store key filter %s: %w", id, err)
+	}
+	return &bloomKeyFilter{data: data}, nil
+}
@@ -0,0 +1,77 @@
+package sstable_test
+
+import (
+	"context"
+	"crypto"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/committed"
+	"github.com/treeverse/lakefs/pkg/graveler/sstable"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
+	"github.com/treeverse/lakefs/pkg/pyramid/mock"
+)
+
+func TestKeyFilter(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockFS := mock.NewMockFS(ctrl)
+	ns := committed.Namespace("some-namespace")
+	id := committed.ID("some-metarange")
+	const filename = "some-metarange.keys.bloom"
+
+	keys := randomStrings(1000)
+	records := make([]graveler.ValueRecord, len(keys))
+	for i, key := range keys {
+		records[i] = graveler.ValueRecord{Key: graveler.Key(key), Value: &graveler.Value{Identity: []byte(key)}}
+	}
+
+	// write the filter through a mock file, keeping its content
+	var stored []byte
+	mockFile := mock.NewMockStoredFile(ctrl)
+	mockFS.EXPECT().Create(gomock.Any(), string(ns)).Return(mockFile, nil)
+	mockFile.EXPECT().Write(gomock.Any()).DoAndReturn(func(b []byte) (int, error) {
+		stored = append(stored, b...)
+		return len(b), nil
+	}).MinTimes(1)
+	mockFile.EXPECT().Store(gomock.Any(), filename).Return(nil)
+
+	sut := sstable.NewPebbleSSTableRangeManagerWithNewReader(nil, &NoCache{}, mockFS, crypto.SHA256)
+	written, err := sut.WriteKeyFilter(ctx, ns, id, testutil.NewValueIteratorFake(records))
+	require.NoError(t, err)
+	for _, key := range keys {
+		require.True(t, written.MayContain(graveler.Key(key)))
+	}
+
+	t.Run("read", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), filename)
+		require.NoError(t, os.WriteFile(path, stored, 0o600))
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		mockFS.EXPECT().Exists(gomock.Any(), string(ns), filename).Return(true, nil)
+		mockFS.EXPECT().Open(gomock.Any(), string(ns), filename).Return(f, nil)
+
+		filter, err := sut.GetKeyFilter(ctx, ns, id)
+		require.NoError(t, err)
+		for _, key := range keys {
+			require.True(t, filter.MayContain(graveler.Key(key)))
+		}
+		falsePositives := 0
+		for _, key := range randomStrings(1000) {
+			if filter.MayContain(graveler.Key("missing/" + key)) {
+				falsePositives++
+			}
+		}
+		require.Less(t, falsePositives, 50)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		mockFS.EXPECT().Exists(gomock.Any(), string(ns), "other.keys.bloom").Return(false, nil)
+		_, err := sut.GetKeyFilter(ctx, ns, "other")
+		require.ErrorIs(t, err, committed.ErrNotFound)
+	})
+}
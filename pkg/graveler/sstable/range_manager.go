@@ -21,21 +21,31 @@ type Unrefer interface {
 }
 
 type RangeManager struct {
-	newReader NewSSTableReaderFn
-	fs        pyramid.FS
-	hash      crypto.Hash
-	cache     Unrefer
+	newReader    NewSSTableReaderFn
+	fs           pyramid.FS
+	hash         crypto.Hash
+	cache        Unrefer
+	writerConfig WriterConfig
+	// prefetchSem limits the number of concurrent prefetches, nil disables prefetching
+	prefetchSem chan struct{}
 }
 
-func NewPebbleSSTableRangeManager(cache *pebble.Cache, fs pyramid.FS, hash crypto.Hash) *RangeManager {
+// NewPebbleSSTableRangeManager returns a RangeManager.  It prefetches up to maxPrefetches
+// ranges concurrently.
+func NewPebbleSSTableRangeManager(cache *pebble.Cache, fs pyramid.FS, hash crypto.Hash, writerConfig WriterConfig, maxPrefetches int) *RangeManager {
 	if cache != nil { // nil cache allowed (size=0), see sstable.ReaderOptions
 		cache.Ref()
 	}
-	opts := sstable.ReaderOptions{Cache: cache}
+	opts := sstable.ReaderOptions{Cache: cache, Filters: readerFilters()}
 	newReader := func(ctx context.Context, ns committed.Namespace, id committed.ID) (*sstable.Reader, error) {
 		return newReader(ctx, fs, ns, id, opts)
 	}
-	return NewPebbleSSTableRangeManagerWithNewReader(newReader, opts.Cache, fs, hash)
+	m := NewPebbleSSTableRangeManagerWithNewReader(newReader, opts.Cache, fs, hash)
+	m.writerConfig = writerConfig
+	if maxPrefetches > 0 {
+		m.prefetchSem = make(chan struct{}, maxPrefetches)
+	}
+	return m
 }
 
 func newReader(ctx context.Context, fs pyramid.FS, ns committed.Namespace, id committed.ID, opts sstable.ReaderOptions) (*sstable.Reader, error) {
@@ -63,7 +73,8 @@ var (
 	// ErrKeyNotFound is the error returned when a path is not found
 	ErrKeyNotFound = fmt.Errorf("key: %w", committed.ErrNotFound)
 
-	_ committed.RangeManager = &RangeManager{}
+	_ committed.RangeManager    = &RangeManager{}
+	_ committed.RangePrefetcher = &RangeManager{}
 )
 
 func (m *RangeManager) Exists(ctx context.Context, ns committed.Namespace, id committed.ID) (bool, error) {
@@ -71,6 +82,7 @@ func (m *RangeManager) Exists(ctx context.Context, ns committed.Namespace, id co
 }
 
 func (m *RangeManager) GetValueGE(ctx context.Context, ns committed.Namespace, id committed.ID, lookup committed.Key) (*committed.Record, error) {
+	graveler.WaitRangeRead(ctx)
 	reader, err := m.newReader(ctx, ns, id)
 	if err != nil {
 		return nil, err
@@ -106,6 +118,7 @@ func (m *RangeManager) GetValueGE(ctx context.Context, ns committed.Namespace, i
 // GetValue returns the Record matching the key in the SSTable referenced by the id.
 // If key is not found, (nil, ErrKeyNotFound) is returned.
 func (m *RangeManager) GetValue(ctx context.Context, ns committed.Namespace, id committed.ID, lookup committed.Key) (*committed.Record, error) {
+	graveler.WaitRangeRead(ctx)
 	reader, err := m.newReader(ctx, ns, id)
 	if err != nil {
 		return nil, err
@@ -118,8 +131,9 @@ func (m *RangeManager) GetValue(ctx context.Context, ns committed.Namespace, id
 	}
 	defer m.execAndLog(ctx, it.Close, "close iterator")
 
-	// actual reading
-	key, value := it.SeekGE(lookup, sstable.SeekGEFlags(0))
+	// actual reading: a prefix seek skips the data block when the bloom filter of the
+	// file excludes lookup, it behaves as SeekGE on files without filters.
+	key, value := it.SeekPrefixGE(lookup, lookup, sstable.SeekGEFlags(0))
 	if key == nil {
 		if it.Error() != nil {
 			return nil, fmt.Errorf("read key from sstable id %s: %w", id, it.Error())
@@ -147,6 +161,7 @@ func (m *RangeManager) GetValue(ctx context.Context, ns committed.Namespace, id
 
 // NewRangeIterator takes a given SSTable and returns an EntryIterator seeked to >= "from" path
 func (m *RangeManager) NewRangeIterator(ctx context.Context, ns committed.Namespace, tid committed.ID) (committed.ValueIterator, error) {
+	graveler.WaitRangeRead(ctx)
 	reader, err := m.newReader(ctx, ns, tid)
 	if err != nil {
 		return nil, err
@@ -163,9 +178,28 @@ func (m *RangeManager) NewRangeIterator(ctx context.Context, ns committed.Namesp
 	return NewIterator(iter, reader.Close), nil
 }
 
+// Prefetch fetches the SSTable of the range tid to the local cache in the background
+func (m *RangeManager) Prefetch(ctx context.Context, ns committed.Namespace, tid committed.ID) {
+	select {
+	case m.prefetchSem <- struct{}{}:
+	default:
+		// too many prefetches in progress (or prefetching disabled), the range is fetched when read
+		return
+	}
+	go func() {
+		defer func() { <-m.prefetchSem }()
+		file, err := m.fs.Open(ctx, string(ns), string(tid))
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("range_id", tid).Debug("Failed to prefetch range")
+			return
+		}
+		m.execAndLog(ctx, file.Close, "close prefetched range")
+	}()
+}
+
 // GetWriter returns a new SSTable writer instance
 func (m *RangeManager) GetWriter(ctx context.Context, ns committed.Namespace, metadata graveler.Metadata) (committed.RangeWriter, error) {
-	return NewDiskWriter(ctx, m.fs, ns, m.hash.New(), metadata)
+	return NewDiskWriter(ctx, m.fs, ns, m.hash.New(), metadata, m.writerConfig)
 }
 
 func (m *RangeManager) GetURI(ctx context.Context, ns committed.Namespace, id committed.ID) (string, error) {
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/cockroachdb/pebble/bloom"
 	pebblesst "github.com/cockroachdb/pebble/sstable"
 
 	"github.com/golang/mock/gomock"
@@ -94,6 +95,54 @@ func TestGetEntryNotFound(t *testing.T) {
 	require.Equal(t, 1, reader.GetNumClosed())
 }
 
+func TestGetEntryFormatV2(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	mockFS := fsMock.NewMockFS(ctrl)
+
+	keys := randomStrings(100)
+	sort.Strings(keys)
+	vals := randomStrings(len(keys))
+
+	filter := bloom.FilterPolicy(10)
+	writerOpts := pebblesst.WriterOptions{
+		Compression:  pebblesst.ZstdCompression,
+		TableFormat:  pebblesst.TableFormatPebblev2,
+		FilterPolicy: filter,
+		FilterType:   pebblesst.TableFilter,
+	}
+	readerOpts := pebblesst.ReaderOptions{
+		Filters: map[string]pebblesst.FilterPolicy{filter.Name(): filter},
+	}
+
+	t.Run("found", func(t *testing.T) {
+		for _, i := range []int{0, len(keys) / 3, len(keys) - 1} {
+			// GetValue closes the reader
+			reader := createSStableReaderWithOptions(t, keys, vals, writerOpts, readerOpts)
+			sut := sstable.NewPebbleSSTableRangeManagerWithNewReader(makeNewReader(reader), &NoCache{}, mockFS, crypto.SHA256)
+			val, err := sut.GetValue(ctx, "some-ns", "some-id", committed.Key(keys[i]))
+			require.NoError(t, err)
+			require.Equal(t, []byte(vals[i]), val.Value)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		reader := createSStableReaderWithOptions(t, keys, vals, writerOpts, readerOpts)
+		sut := sstable.NewPebbleSSTableRangeManagerWithNewReader(makeNewReader(reader), &NoCache{}, mockFS, crypto.SHA256)
+		val, err := sut.GetValue(ctx, "some-ns", "some-id", committed.Key("does-not-exist"))
+		require.ErrorIs(t, err, sstable.ErrKeyNotFound)
+		require.Nil(t, val)
+	})
+}
+
+func TestWriterConfigValidate(t *testing.T) {
+	require.NoError(t, sstable.WriterConfig{}.Validate())
+	require.NoError(t, sstable.WriterConfig{Format: sstable.FormatV2, BloomFilterBitsPerKey: 10}.Validate())
+	require.ErrorIs(t, sstable.WriterConfig{Format: 3}.Validate(), sstable.ErrInvalidWriterConfig)
+	require.ErrorIs(t, sstable.WriterConfig{Format: sstable.FormatV2, BloomFilterBitsPerKey: -1}.Validate(), sstable.ErrInvalidWriterConfig)
+}
+
 func TestGetWriterSuccess(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -35,7 +35,7 @@ type DiskWriter struct {
 	closed bool
 }
 
-func NewDiskWriter(ctx context.Context, tierFS pyramid.FS, ns committed.Namespace, hash hash.Hash, metadata graveler.Metadata) (*DiskWriter, error) {
+func NewDiskWriter(ctx context.Context, tierFS pyramid.FS, ns committed.Namespace, hash hash.Hash, metadata graveler.Metadata, config WriterConfig) (*DiskWriter, error) {
 	fh, err := tierFS.Create(ctx, string(ns))
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
@@ -46,10 +46,7 @@ func NewDiskWriter(ctx context.Context, tierFS pyramid.FS, ns committed.Namespac
 		props[k] = v
 	}
 
-	writer := sstable.NewWriter(fh, sstable.WriterOptions{
-		Compression:             sstable.SnappyCompression,
-		TablePropertyCollectors: []func() sstable.TablePropertyCollector{NewStaticCollector(props)},
-	})
+	writer := sstable.NewWriter(fh, config.writerOptions(NewStaticCollector(props)))
 
 	return &DiskWriter{
 		ctx:    ctx,
@@ -0,0 +1,206 @@
+package graveler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// ErrCompactionConflict is returned when the branch changed while compacting it, the compaction result is discarded
+var ErrCompactionConflict = fmt.Errorf("%w: branch changed during compaction", ErrConflictFound)
+
+// CompactBranch seals the staging token of a branch and merges the sealed tokens into a metarange set as the branch
+// compacted base. Reading the branch lists fewer staged entries, and committing it only applies the changes staged
+// after the compaction.
+func (g *Graveler) CompactBranch(ctx context.Context, repository *RepositoryRecord, branchID BranchID) error {
+	// seal the staging token: writes from now on are staged on top of the compaction result
+	var branch *Branch
+	err := g.RefManager.BranchUpdate(ctx, repository, branchID, func(b *Branch) (*Branch, error) {
+		b.SealedTokens = append([]StagingToken{b.StagingToken}, b.SealedTokens...)
+		b.StagingToken = GenerateStagingToken(repository.RepositoryID, branchID)
+		branch = b
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	baseMetaRangeID := branch.CompactedBaseMetaRangeID
+	if baseMetaRangeID == "" {
+		commit, err := g.RefManager.GetCommit(ctx, repository, branch.CommitID)
+		if err != nil {
+			return fmt.Errorf("get commit: %w", err)
+		}
+		baseMetaRangeID = commit.MetaRangeID
+	}
+	changes, err := g.sealedTokensIterator(ctx, branch, 0)
+	if err != nil {
+		return err
+	}
+	defer changes.Close()
+	metaRangeID, _, err := g.CommittedManager.Commit(ctx, repository.StorageNamespace, baseMetaRangeID, changes, true)
+	if err != nil {
+		return fmt.Errorf("compact: %w", err)
+	}
+
+	// replace the compacted tokens with the result, keeping tokens sealed since by concurrent operations
+	compactedTokens := branch.SealedTokens
+	err = g.RefManager.BranchUpdate(ctx, repository, branchID, func(b *Branch) (*Branch, error) {
+		keep := len(b.SealedTokens) - len(compactedTokens)
+		if b.CommitID != branch.CommitID || b.CompactedBaseMetaRangeID != branch.CompactedBaseMetaRangeID ||
+			keep < 0 || !stagingTokensEqual(b.SealedTokens[keep:], compactedTokens) {
+			return nil, ErrCompactionConflict
+		}
+		b.SealedTokens = append([]StagingToken{}, b.SealedTokens[:keep]...)
+		b.CompactedBaseMetaRangeID = metaRangeID
+		return b, nil
+	})
+	if errors.Is(err, kv.ErrPredicateFailed) {
+		err = ErrCompactionConflict
+	}
+	if err != nil {
+		return err
+	}
+	g.dropTokens(ctx, compactedTokens...)
+	return nil
+}
+
+func stagingTokensEqual(a, b []StagingToken) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasStagedEntries returns true if the staging area of branch, excluding compacted changes, holds at least n entries
+func (g *Graveler) hasStagedEntries(ctx context.Context, branch *Branch, n int) (bool, error) {
+	it, err := g.listStagingAreaWithoutCompaction(ctx, branch, n)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	count := 0
+	for count < n && it.Next() {
+		count++
+	}
+	return count >= n, it.Err()
+}
+
+// StagingCompactorConfig configures the background compaction of branch staging areas
+type StagingCompactorConfig struct {
+	// Interval between scans of all branches
+	Interval time.Duration
+	// MinStagedEntries is the number of entries staged on a branch from which it is compacted
+	MinStagedEntries int
+	// Acquire, when set, waits before each scan until compaction may run.  It returns the context to compact with
+	// and a function to call once the scan is done.
+	Acquire func(ctx context.Context) (context.Context, func(), error)
+}
+
+// StagingCompactor periodically compacts the staging area of branches with many staged entries, so that listing
+// them and committing them stays fast
+type StagingCompactor struct {
+	graveler *Graveler
+	config   StagingCompactorConfig
+}
+
+func NewStagingCompactor(g *Graveler, config StagingCompactorConfig) *StagingCompactor {
+	return &StagingCompactor{
+		graveler: g,
+		config:   config,
+	}
+}
+
+// Run compacts branches every interval until ctx is done
+func (c *StagingCompactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.compactAllWhenAcquired(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logging.FromContext(ctx).WithError(err).Error("Staging compaction failed")
+			}
+		}
+	}
+}
+
+func (c *StagingCompactor) compactAllWhenAcquired(ctx context.Context) error {
+	if c.config.Acquire == nil {
+		return c.CompactAll(ctx)
+	}
+	ctx, release, err := c.config.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.CompactAll(ctx)
+}
+
+// CompactAll compacts the branches of all active repositories with at least MinStagedEntries staged entries.
+// Failing to compact a branch is logged and doesn't stop compacting the others.
+func (c *StagingCompactor) CompactAll(ctx context.Context) error {
+	repositories, err := c.graveler.RefManager.ListRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	defer repositories.Close()
+	for repositories.Next() {
+		repository := repositories.Value()
+		if repository.State != RepositoryState_ACTIVE || repository.ReadOnly {
+			continue
+		}
+		if err := c.compactRepository(ctx, repository); err != nil {
+			return err
+		}
+	}
+	return repositories.Err()
+}
+
+func (c *StagingCompactor) compactRepository(ctx context.Context, repository *RepositoryRecord) error {
+	branches, err := c.graveler.RefManager.ListBranches(ctx, repository)
+	if err != nil {
+		return err
+	}
+	defer branches.Close()
+	for branches.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		branch := branches.Value()
+		log := logging.FromContext(ctx).WithFields(logging.Fields{
+			"repository": repository.RepositoryID,
+			"branch":     branch.BranchID,
+		})
+		compact, err := c.graveler.hasStagedEntries(ctx, branch.Branch, c.config.MinStagedEntries)
+		if err != nil {
+			log.WithError(err).Warn("Failed to count staged entries")
+			continue
+		}
+		if !compact {
+			continue
+		}
+		start := time.Now()
+		err = c.graveler.CompactBranch(ctx, repository, branch.BranchID)
+		switch {
+		case errors.Is(err, ErrCompactionConflict):
+			log.Debug("Branch changed during compaction, will retry on next scan")
+		case err != nil:
+			log.WithError(err).Warn("Failed to compact branch")
+		default:
+			log.WithField("took", time.Since(start)).Info("Compacted branch staging area")
+		}
+	}
+	return branches.Err()
+}
@@ -0,0 +1,69 @@
+package graveler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog/testutils"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/graveler/testutil"
+)
+
+func TestGravelerCompactBranch(t *testing.T) {
+	ctx := context.Background()
+
+	// sealBranch expects the compaction to seal the staging token of a branch with one sealed token
+	sealBranch := func(test *testutil.GravelerTest) {
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+				branchTest := &graveler.Branch{CommitID: commit1ID, StagingToken: stagingToken1, SealedTokens: []graveler.StagingToken{stagingToken2}}
+				updatedBranch, err := f(branchTest)
+				require.NoError(t, err)
+				require.Equal(t, []graveler.StagingToken{stagingToken1, stagingToken2}, updatedBranch.SealedTokens)
+				require.NotEqual(t, stagingToken1, updatedBranch.StagingToken)
+				return nil
+			}).Times(1)
+		test.RefManager.EXPECT().GetCommit(ctx, repository, commit1ID).Times(1).Return(&commit1, nil)
+		test.StagingManager.EXPECT().List(ctx, stagingToken1, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{{Key: key1, Value: value1}}))
+		test.StagingManager.EXPECT().List(ctx, stagingToken2, gomock.Any()).Times(1).Return(testutils.NewFakeValueIterator([]*graveler.ValueRecord{{Key: key2, Value: nil}}))
+		test.CommittedManager.EXPECT().Commit(ctx, repository.StorageNamespace, mr1ID, gomock.Any(), true, []graveler.SetOptionsFunc{}).Times(1).Return(mr2ID, graveler.DiffSummary{}, nil)
+	}
+
+	t.Run("compact successful", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		sealBranch(test)
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+				// a token sealed by a concurrent operation is kept
+				branchTest := &graveler.Branch{CommitID: commit1ID, StagingToken: stagingToken4, SealedTokens: []graveler.StagingToken{stagingToken3, stagingToken1, stagingToken2}}
+				updatedBranch, err := f(branchTest)
+				require.NoError(t, err)
+				require.Equal(t, []graveler.StagingToken{stagingToken3}, updatedBranch.SealedTokens)
+				require.Equal(t, stagingToken4, updatedBranch.StagingToken)
+				require.Equal(t, mr2ID, updatedBranch.CompactedBaseMetaRangeID)
+				return nil
+			}).Times(1)
+		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken1).Times(1)
+		test.StagingManager.EXPECT().DropAsync(ctx, stagingToken2).Times(1)
+
+		err := test.Sut.CompactBranch(ctx, repository, branch1ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("branch committed during compaction", func(t *testing.T) {
+		test := testutil.InitGravelerTest(t)
+		sealBranch(test)
+		test.RefManager.EXPECT().BranchUpdate(ctx, repository, branch1ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, f graveler.BranchUpdateFunc) error {
+				branchTest := &graveler.Branch{CommitID: commit2ID, StagingToken: stagingToken4, SealedTokens: []graveler.StagingToken{}}
+				updatedBranch, err := f(branchTest)
+				require.Nil(t, updatedBranch)
+				return err
+			}).Times(1)
+
+		err := test.Sut.CompactBranch(ctx, repository, branch1ID)
+		require.ErrorIs(t, err, graveler.ErrCompactionConflict)
+	})
+}
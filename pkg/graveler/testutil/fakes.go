@@ -28,12 +28,20 @@ type CommittedFake struct {
 	RangeInfo     graveler.RangeInfo
 	DiffSummary   graveler.DiffSummary
 	AppliedData   AppliedData
+	RangeIDs      map[graveler.MetaRangeID][]graveler.RangeID
 }
 
 func (c *CommittedFake) GetRangeIDByKey(_ context.Context, _ graveler.StorageNamespace, _ graveler.MetaRangeID, _ graveler.Key) (graveler.RangeID, error) {
 	panic("implement me")
 }
 
+func (c *CommittedFake) MayContain(_ context.Context, _ graveler.StorageNamespace, _ graveler.MetaRangeID, _ graveler.Key) (bool, error) {
+	if c.Err != nil {
+		return false, c.Err
+	}
+	return true, nil
+}
+
 type MetaRangeFake struct {
 	id graveler.MetaRangeID
 }
@@ -126,6 +134,25 @@ func (c *CommittedFake) GetRange(_ context.Context, _ graveler.StorageNamespace,
 	return graveler.RangeAddress(fmt.Sprintf("fake://prefix/%s(range)", rangeID)), nil
 }
 
+func (c *CommittedFake) ListRangeIDs(_ context.Context, _ graveler.StorageNamespace, id graveler.MetaRangeID) ([]graveler.RangeID, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	rangeIDs, ok := c.RangeIDs[id]
+	if !ok {
+		return nil, graveler.ErrNotFound
+	}
+	return rangeIDs, nil
+}
+
+func (c *CommittedFake) ListRanges(context.Context, graveler.StorageNamespace, graveler.MetaRangeID) ([]*graveler.RangeInfo, error) {
+	panic("implement me")
+}
+
+func (c *CommittedFake) ListRange(context.Context, graveler.StorageNamespace, graveler.RangeID) (graveler.ValueIterator, error) {
+	panic("implement me")
+}
+
 // Backwards compatibility for test pre-KV
 const defaultKey = "key"
 
@@ -253,6 +280,7 @@ type RefsFake struct {
 	StagingToken        graveler.StagingToken
 	SealedTokens        []graveler.StagingToken
 	BaseMetaRangeID     graveler.MetaRangeID
+	UnreachableCommits  []*graveler.CommitRecord
 }
 
 func (m *RefsFake) CreateBranch(_ context.Context, _ *graveler.RepositoryRecord, _ graveler.BranchID, branch graveler.Branch) error {
@@ -278,6 +306,15 @@ func (m *RefsFake) ListCommits(_ context.Context, _ *graveler.RepositoryRecord)
 	return m.ListCommitsRes, nil
 }
 
+func (m *RefsFake) FindUnreachableCommits(_ context.Context, _ *graveler.RepositoryRecord, fn graveler.UnreachableCommitFunc) error {
+	for _, commit := range m.UnreachableCommits {
+		if err := fn(commit, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *RefsFake) GCCommitIterator(_ context.Context, _ *graveler.RepositoryRecord) (graveler.CommitIterator, error) {
 	return nil, nil
 }
@@ -390,6 +427,26 @@ func (m *RefsFake) ListTags(context.Context, *graveler.RepositoryRecord) (gravel
 	return m.ListTagsRes, nil
 }
 
+func (m *RefsFake) ListDeletedRefs(context.Context, *graveler.RepositoryRecord) ([]*graveler.DeletedRef, error) {
+	panic("implement me")
+}
+
+func (m *RefsFake) GetBranchHistoryHead(context.Context, *graveler.RepositoryRecord, graveler.BranchID) (*graveler.BranchHistoryRecord, error) {
+	panic("implement me")
+}
+
+func (m *RefsFake) VerifyBranchHistory(context.Context, *graveler.RepositoryRecord, graveler.BranchID, *graveler.BranchHistoryRecord) (*graveler.BranchHistoryVerification, error) {
+	panic("implement me")
+}
+
+func (m *RefsFake) RestoreBranch(context.Context, *graveler.RepositoryRecord, graveler.BranchID) (*graveler.Branch, error) {
+	panic("implement me")
+}
+
+func (m *RefsFake) RestoreTag(context.Context, *graveler.RepositoryRecord, graveler.TagID) (graveler.CommitID, error) {
+	panic("implement me")
+}
+
 func (m *RefsFake) GetCommit(_ context.Context, _ *graveler.RepositoryRecord, id graveler.CommitID) (*graveler.Commit, error) {
 	if val, ok := m.Commits[id]; ok {
 		return val, nil
@@ -875,6 +932,16 @@ func (p ProtectedBranchesManagerFake) IsBlocked(_ context.Context, _ *graveler.R
 	return false, nil
 }
 
+func (m *RefsFake) ArchiveRepository(_ context.Context, _ graveler.RepositoryID) error {
+	// TODO implement me
+	panic("implement me")
+}
+
+func (m *RefsFake) RestoreRepository(_ context.Context, _ graveler.RepositoryID) error {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (m *RefsFake) GetRepositoryMetadata(_ context.Context, _ graveler.RepositoryID) (graveler.RepositoryMetadata, error) {
 	// TODO implement me
 	panic("implement me")
@@ -0,0 +1,121 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/api"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/permissions"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authorizationMetadataKey = "authorization"
+
+// interceptor authenticates calls using the "authorization" metadata, holding lakeFS access key credentials
+// ("Basic" scheme) or a lakeFS login token ("Bearer" scheme), as HTTP API requests do
+type interceptor struct {
+	authenticator auth.Authenticator
+	authService   auth.Service
+	logger        logging.Logger
+}
+
+func (a *interceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationMetadataKey)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+	scheme, credentials, _ := strings.Cut(values[0], " ")
+	var (
+		user *model.User
+		err  error
+	)
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		user, err = api.UserByToken(ctx, a.logger, a.authService, credentials)
+	case strings.EqualFold(scheme, "Basic"):
+		accessKey, secretKey, ok := parseBasicAuth(credentials)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid basic authorization")
+		}
+		user, err = api.UserByAuth(ctx, a.logger, a.authenticator, a.authService, accessKey, secretKey)
+	default:
+		return nil, status.Errorf(codes.Unauthenticated, "unsupported authorization scheme '%s'", scheme)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return auth.WithUser(ctx, user), nil
+}
+
+func parseBasicAuth(credentials string) (string, string, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
+
+func (a *interceptor) unary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *interceptor) stream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticatedStream passes the context holding the authenticated user to stream handlers
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize checks that the authenticated user holds the permission to perform action on resource
+func (s *service) authorize(ctx context.Context, action, resource string) error {
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	resp, err := s.authService.Authorize(ctx, &auth.AuthorizationRequest{
+		Username: user.Username,
+		RequiredPermissions: permissions.Node{
+			Permission: permissions.Permission{Action: action, Resource: resource},
+		},
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+	if resp.Error != nil || !resp.Allowed {
+		return status.Errorf(codes.PermissionDenied, "%s: %s", action, resource)
+	}
+	return nil
+}
+
+// authorizeAliasTarget checks that the user may read the object an alias entry was resolved to. Reading an alias
+// serves the object found at its target, so permission to read the alias path alone is not enough.
+func (s *service) authorizeAliasTarget(ctx context.Context, repository string, entry *catalog.DBEntry) error {
+	if entry.AliasTarget == "" {
+		return nil
+	}
+	return s.authorize(ctx, permissions.ReadObjectAction, permissions.ObjectArn(repository, entry.AliasTarget))
+}
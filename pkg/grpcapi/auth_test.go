@@ -0,0 +1,35 @@
+package grpcapi
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestParseBasicAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials string
+		accessKey   string
+		secretKey   string
+		ok          bool
+	}{
+		{name: "valid", credentials: base64.StdEncoding.EncodeToString([]byte("AKIAEXAMPLE:secret")), accessKey: "AKIAEXAMPLE", secretKey: "secret", ok: true},
+		{name: "secret with colon", credentials: base64.StdEncoding.EncodeToString([]byte("AKIAEXAMPLE:se:cret")), accessKey: "AKIAEXAMPLE", secretKey: "se:cret", ok: true},
+		{name: "missing separator", credentials: base64.StdEncoding.EncodeToString([]byte("AKIAEXAMPLE")), ok: false},
+		{name: "not base64", credentials: "not base64!", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessKey, secretKey, ok := parseBasicAuth(tt.credentials)
+			if ok != tt.ok {
+				t.Fatalf("parseBasicAuth() ok = %t, expected %t", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if accessKey != tt.accessKey || secretKey != tt.secretKey {
+				t.Errorf("parseBasicAuth() = %s, %s, expected %s, %s", accessKey, secretKey, tt.accessKey, tt.secretKey)
+			}
+		})
+	}
+}
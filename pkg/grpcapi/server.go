@@ -0,0 +1,110 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/upload"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the LakeFS gRPC service, alongside the HTTP API
+type Server struct {
+	listener net.Listener
+	server   *grpc.Server
+}
+
+// NewServer listens on listenAddress, opts are applied to the gRPC server (e.g. TLS credentials)
+func NewServer(c *catalog.Catalog, authenticator auth.Authenticator, authService auth.Service, pathProvider upload.PathProvider, listenAddress string, logger logging.Logger, opts ...grpc.ServerOption) (*Server, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	a := &interceptor{
+		authenticator: authenticator,
+		authService:   authService,
+		logger:        logger,
+	}
+	opts = append(opts,
+		grpc.UnaryInterceptor(a.unary),
+		grpc.StreamInterceptor(a.stream),
+	)
+	server := grpc.NewServer(opts...)
+	RegisterLakeFSServer(server, &service{
+		catalog:      c,
+		authService:  authService,
+		pathProvider: pathProvider,
+	})
+	return &Server{listener: listener, server: server}, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serve serves gRPC requests until the server is closed
+func (s *Server) Serve() error {
+	err := s.server.Serve(s.listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
+
+// ListenAndServe serves in the background, until the server is shut down
+func (s *Server) ListenAndServe(ctx context.Context) {
+	log := logging.FromContext(ctx).WithField("listen_address", s.Addr().String())
+	go func() {
+		log.Info("Starting gRPC server")
+		if err := s.Serve(); err != nil {
+			log.WithError(err).Error("gRPC server stopped")
+		}
+	}()
+}
+
+// Shutdown stops the server, waiting for in-flight calls to complete. Calls still running when ctx is done are
+// canceled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+// toStatus converts errors returned by the catalog to gRPC status errors
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, graveler.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, graveler.ErrInvalid),
+		errors.Is(err, graveler.ErrInvalidRef),
+		errors.Is(err, graveler.ErrDereferenceCommitWithStaging):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, graveler.ErrReadOnlyRepository):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
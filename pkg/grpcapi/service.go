@@ -0,0 +1,327 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultPageSize is the number of entries sent in each streamed response, unless requested otherwise
+	defaultPageSize = 1000
+	// maxPageSize bounds the page size a client can request
+	maxPageSize = 10000
+	// downloadChunkSize is the size of the object content sent in each streamed response
+	downloadChunkSize = 1024 * 1024
+
+	pathTypeObject       = "object"
+	pathTypeCommonPrefix = "common_prefix"
+)
+
+type service struct {
+	UnimplementedLakeFSServer
+	catalog      *catalog.Catalog
+	authService  auth.Service
+	pathProvider upload.PathProvider
+}
+
+func pageSize(requested int32) int {
+	if requested <= 0 {
+		return defaultPageSize
+	}
+	return min(int(requested), maxPageSize)
+}
+
+func objectStats(entry *catalog.DBEntry) *ObjectStats {
+	return &ObjectStats{
+		Path:            entry.Path,
+		PhysicalAddress: entry.PhysicalAddress,
+		Checksum:        entry.Checksum,
+		SizeBytes:       entry.Size,
+		Mtime:           entry.CreationDate.Unix(),
+		ContentType:     catalog.ContentTypeOrDefault(entry.ContentType),
+	}
+}
+
+func (s *service) StatObject(ctx context.Context, req *StatObjectRequest) (*ObjectStats, error) {
+	if err := s.authorize(ctx, permissions.ReadObjectAction, permissions.ObjectArn(req.GetRepository(), req.GetPath())); err != nil {
+		return nil, err
+	}
+	entry, err := s.catalog.GetEntry(ctx, req.GetRepository(), req.GetRef(), req.GetPath(), catalog.GetEntryParams{})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.authorizeAliasTarget(ctx, req.GetRepository(), entry); err != nil {
+		return nil, err
+	}
+	return objectStats(entry), nil
+}
+
+func (s *service) ListObjects(req *ListObjectsRequest, stream LakeFS_ListObjectsServer) error {
+	ctx := stream.Context()
+	if err := s.authorize(ctx, permissions.ListObjectsAction, permissions.RepoArn(req.GetRepository())); err != nil {
+		return err
+	}
+	limit := pageSize(req.GetPageSize())
+	after := req.GetAfter()
+	for {
+		entries, hasMore, err := s.catalog.ListEntries(ctx, req.GetRepository(), req.GetRef(), req.GetPrefix(), after, req.GetDelimiter(), limit)
+		if err != nil {
+			return toStatus(err)
+		}
+		resp := &ListObjectsResponse{}
+		for _, entry := range entries {
+			if entry.CommonLevel {
+				resp.CommonPrefixes = append(resp.CommonPrefixes, entry.Path)
+			} else {
+				resp.Objects = append(resp.Objects, objectStats(entry))
+			}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		if !hasMore || len(entries) == 0 {
+			return nil
+		}
+		after = entries[len(entries)-1].Path
+	}
+}
+
+func diffType(t catalog.DifferenceType) string {
+	switch t {
+	case catalog.DifferenceTypeAdded:
+		return "added"
+	case catalog.DifferenceTypeRemoved:
+		return "removed"
+	case catalog.DifferenceTypeChanged:
+		return "changed"
+	case catalog.DifferenceTypeConflict:
+		return "conflict"
+	case catalog.DifferenceTypePrefixChanged:
+		return "prefix_changed"
+	default:
+		return ""
+	}
+}
+
+func (s *service) Diff(req *DiffRequest, stream LakeFS_DiffServer) error {
+	ctx := stream.Context()
+	if err := s.authorize(ctx, permissions.ListObjectsAction, permissions.RepoArn(req.GetRepository())); err != nil {
+		return err
+	}
+	params := catalog.DiffParams{
+		Limit:     pageSize(req.GetPageSize()),
+		After:     req.GetAfter(),
+		Prefix:    req.GetPrefix(),
+		Delimiter: req.GetDelimiter(),
+	}
+	for {
+		diffs, hasMore, err := s.catalog.Diff(ctx, req.GetRepository(), req.GetLeftRef(), req.GetRightRef(), params)
+		if err != nil {
+			return toStatus(err)
+		}
+		resp := &DiffResponse{Diffs: make([]*Diff, 0, len(diffs))}
+		for _, d := range diffs {
+			pathType := pathTypeObject
+			if d.CommonLevel {
+				pathType = pathTypeCommonPrefix
+			}
+			resp.Diffs = append(resp.Diffs, &Diff{
+				Type:      diffType(d.Type),
+				Path:      d.Path,
+				PathType:  pathType,
+				SizeBytes: d.Size,
+			})
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		if !hasMore || len(diffs) == 0 {
+			return nil
+		}
+		params.After = diffs[len(diffs)-1].Path
+	}
+}
+
+func (s *service) Log(req *LogRequest, stream LakeFS_LogServer) error {
+	ctx := stream.Context()
+	if err := s.authorize(ctx, permissions.ReadBranchAction, permissions.BranchArn(req.GetRepository(), req.GetRef())); err != nil {
+		return err
+	}
+	remaining := int(req.GetLimit())
+	after := req.GetAfter()
+	for {
+		amount := pageSize(req.GetPageSize())
+		if req.GetLimit() > 0 {
+			amount = min(amount, remaining)
+		}
+		commits, hasMore, err := s.catalog.ListCommits(ctx, req.GetRepository(), req.GetRef(), catalog.LogParams{
+			FromReference: after,
+			Amount:        amount,
+		})
+		if err != nil {
+			return toStatus(err)
+		}
+		resp := &LogResponse{Commits: make([]*Commit, 0, len(commits))}
+		for _, commit := range commits {
+			resp.Commits = append(resp.Commits, &Commit{
+				Id:           commit.Reference,
+				Parents:      commit.Parents,
+				Committer:    commit.Committer,
+				Message:      commit.Message,
+				CreationDate: commit.CreationDate.Unix(),
+				MetaRangeId:  commit.MetaRangeID,
+				Generation:   int64(commit.Generation),
+			})
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		remaining -= len(commits)
+		if !hasMore || len(commits) == 0 || (req.GetLimit() > 0 && remaining <= 0) {
+			return nil
+		}
+		after = commits[len(commits)-1].Reference
+	}
+}
+
+func (s *service) DownloadObject(req *DownloadObjectRequest, stream LakeFS_DownloadObjectServer) error {
+	ctx := stream.Context()
+	if err := s.authorize(ctx, permissions.ReadObjectAction, permissions.ObjectArn(req.GetRepository(), req.GetPath())); err != nil {
+		return err
+	}
+	repository, err := s.catalog.GetRepository(ctx, req.GetRepository())
+	if err != nil {
+		return toStatus(err)
+	}
+	entry, err := s.catalog.GetEntry(ctx, req.GetRepository(), req.GetRef(), req.GetPath(), catalog.GetEntryParams{})
+	if err != nil {
+		return toStatus(err)
+	}
+	if err := s.authorizeAliasTarget(ctx, req.GetRepository(), entry); err != nil {
+		return err
+	}
+	if entry.Expired {
+		return status.Errorf(codes.NotFound, "object %s expired", req.GetPath())
+	}
+	reader, err := s.catalog.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	// messages may be used by the transport after Send returns, each chunk is read into a new buffer
+	resp := &DownloadObjectResponse{Stats: objectStats(entry)}
+	for {
+		buf := make([]byte, downloadChunkSize)
+		n, err := io.ReadFull(reader, buf)
+		if n > 0 || resp.Stats != nil {
+			resp.Data = buf[:n]
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			resp = &DownloadObjectResponse{}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return toStatus(err)
+		}
+	}
+}
+
+// UploadObject streams the content to the blockstore as it is received, and stages the object on the branch once
+// the client closes the stream
+func (s *service) UploadObject(stream LakeFS_UploadObjectServer) error {
+	ctx := stream.Context()
+	req, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "missing upload request")
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, permissions.WriteObjectAction, permissions.ObjectArn(req.GetRepository(), req.GetPath())); err != nil {
+		return err
+	}
+	repository, err := s.catalog.GetRepository(ctx, req.GetRepository())
+	if err != nil {
+		return toStatus(err)
+	}
+	if repository.ReadOnly {
+		return status.Errorf(codes.FailedPrecondition, "repository %s is read-only", repository.Name)
+	}
+
+	content := &uploadReader{stream: stream, data: req.GetData()}
+	ctx = block.WithRepository(ctx, repository.Name)
+	blob, err := upload.WriteBlob(ctx, s.catalog.BlockAdapter, repository.StorageNamespace, s.pathProvider.NewPath(), content, -1, block.PutOpts{})
+	if err != nil {
+		return toStatus(err)
+	}
+	entryBuilder := catalog.NewDBEntryBuilder().
+		Path(req.GetPath()).
+		PhysicalAddress(blob.PhysicalAddress).
+		CreationDate(time.Now()).
+		Size(blob.Size).
+		Checksum(blob.Checksum).
+		ContentType(req.GetContentType())
+	if blob.RelativePath {
+		entryBuilder.AddressType(catalog.AddressTypeRelative)
+	} else {
+		entryBuilder.AddressType(catalog.AddressTypeFull)
+	}
+	encryption, err := s.catalog.RepositoryEncryption(ctx, repository.Name)
+	if err != nil {
+		return toStatus(err)
+	}
+	meta := catalog.Metadata{}
+	encryption.SetMetadata(meta)
+	entryBuilder.Metadata(meta)
+	entry := entryBuilder.Build()
+	if err := s.catalog.CreateEntry(ctx, repository.Name, req.GetBranch(), entry); err != nil {
+		return toStatus(err)
+	}
+	return stream.SendAndClose(&UploadObjectResponse{Stats: objectStats(&entry)})
+}
+
+// uploadReader reads the object content from the requests streamed by the client, starting with the data of the
+// first request
+type uploadReader struct {
+	stream LakeFS_UploadObjectServer
+	data   []byte
+	done   bool
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		req, err := r.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			r.done = true
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		r.data = req.GetData()
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
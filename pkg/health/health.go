@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	// BasePath is the URI prefix of the liveness and readiness endpoints
+	BasePath = "/_health"
+	// LivePath serves the liveness probe: the server is up and able to handle requests
+	LivePath = BasePath + "/live"
+	// ReadyPath serves the readiness probe: all the dependencies required to serve requests are healthy
+	ReadyPath = BasePath + "/ready"
+
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusPending = "pending"
+)
+
+// CheckFunc probes a dependency, returning an error when the dependency is not healthy
+type CheckFunc func(ctx context.Context) error
+
+// DependencyStatus is the result of the last probe of a dependency
+type DependencyStatus struct {
+	Status    string    `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the readiness of the server and the status of each of its dependencies
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// Checker probes dependencies in the background on an interval and keeps the last result of each one, so
+// readiness requests never wait on a slow dependency.
+type Checker struct {
+	interval time.Duration
+	timeout  time.Duration
+	checks   map[string]CheckFunc
+	logger   logging.Logger
+
+	mu      sync.RWMutex
+	results map[string]DependencyStatus
+}
+
+// NewChecker returns a checker probing each of the checks every interval, failing probes taking longer than timeout
+func NewChecker(checks map[string]CheckFunc, interval, timeout time.Duration, logger logging.Logger) *Checker {
+	results := make(map[string]DependencyStatus, len(checks))
+	for name := range checks {
+		results[name] = DependencyStatus{Status: StatusPending}
+	}
+	return &Checker{
+		interval: interval,
+		timeout:  timeout,
+		checks:   checks,
+		logger:   logger,
+		results:  results,
+	}
+}
+
+// Start probes all dependencies once and then every interval, until ctx is done
+func (c *Checker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			c.CheckAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CheckAll probes all dependencies concurrently and records the results
+func (c *Checker) CheckAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			c.setResult(name, c.probe(ctx, check))
+		}(name, check)
+	}
+	wg.Wait()
+}
+
+func (c *Checker) probe(ctx context.Context, check CheckFunc) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	err := check(ctx)
+	result := DependencyStatus{
+		Status:    StatusOK,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (c *Checker) setResult(name string, result DependencyStatus) {
+	c.mu.Lock()
+	prev := c.results[name]
+	c.results[name] = result
+	c.mu.Unlock()
+	if prev.Status != result.Status && c.logger != nil {
+		log := c.logger.WithFields(logging.Fields{"dependency": name, "status": result.Status})
+		if result.Status == StatusFailed {
+			log.WithField("error", result.Error).Warn("Health check failed")
+		} else {
+			log.Info("Health check status changed")
+		}
+	}
+}
+
+// Report returns the last status of every dependency. The server is ready only once all dependencies were probed
+// successfully.
+func (c *Checker) Report() Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	report := Report{
+		Status:       StatusOK,
+		Dependencies: make(map[string]DependencyStatus, len(c.results)),
+	}
+	for name, result := range c.results {
+		report.Dependencies[name] = result
+		if result.Status != StatusOK {
+			report.Status = StatusFailed
+		}
+	}
+	return report
+}
+
+// Handler serves the liveness endpoint, and the readiness endpoint with the status of each dependency.
+// Readiness returns 503 while any dependency is not healthy.
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LivePath:
+			writeJSON(w, http.StatusOK, Report{Status: StatusOK})
+		case ReadyPath:
+			report := c.Report()
+			status := http.StatusOK
+			if report.Status != StatusOK {
+				status = http.StatusServiceUnavailable
+			}
+			writeJSON(w, status, report)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
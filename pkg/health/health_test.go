@@ -0,0 +1,72 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/health"
+)
+
+var errBroken = errors.New("broken")
+
+func getReport(t *testing.T, h http.Handler, path string) (int, health.Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+	var blockstoreErr error
+	checker := health.NewChecker(map[string]health.CheckFunc{
+		"kv":         func(context.Context) error { return nil },
+		"blockstore": func(context.Context) error { return blockstoreErr },
+	}, time.Minute, time.Second, nil)
+	h := checker.Handler()
+
+	// not ready before the first probe
+	code, report := getReport(t, h, health.ReadyPath)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, health.StatusPending, report.Dependencies["kv"].Status)
+
+	// liveness does not depend on the dependencies
+	code, report = getReport(t, h, health.LivePath)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusOK, report.Status)
+
+	checker.CheckAll(ctx)
+	code, report = getReport(t, h, health.ReadyPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusOK, report.Status)
+	require.Equal(t, health.StatusOK, report.Dependencies["blockstore"].Status)
+
+	blockstoreErr = errBroken
+	checker.CheckAll(ctx)
+	code, report = getReport(t, h, health.ReadyPath)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, health.StatusOK, report.Dependencies["kv"].Status)
+	require.Equal(t, health.StatusFailed, report.Dependencies["blockstore"].Status)
+	require.Equal(t, errBroken.Error(), report.Dependencies["blockstore"].Error)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	checker := health.NewChecker(map[string]health.CheckFunc{
+		"kv": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, time.Minute, 10*time.Millisecond, nil)
+	checker.CheckAll(context.Background())
+	report := checker.Report()
+	require.Equal(t, health.StatusFailed, report.Status)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies["kv"].Error)
+}
@@ -0,0 +1,97 @@
+package httputil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNoCertificate = errors.New("no certificate for server name")
+
+// SNICertificate is a certificate served to clients that request one of its domains
+type SNICertificate struct {
+	// Domains server names the certificate is served for, "*.example.com" matches a single label under example.com.
+	// The names of the certificate are used when empty.
+	Domains     []string
+	Certificate tls.Certificate
+}
+
+// CertificateSelector selects the certificate served on a TLS handshake by the server name the client requests (SNI):
+// the certificate of the exact name, then the certificate of a wildcard matching it, then the default certificate.
+type CertificateSelector struct {
+	exact    map[string]*tls.Certificate
+	wildcard map[string]*tls.Certificate
+	fallback *tls.Certificate
+}
+
+// NewCertificateSelector returns a selector of certificates, serving fallback to clients requesting other names or
+// no name. Without fallback, these handshakes fail.
+func NewCertificateSelector(certificates []SNICertificate, fallback *tls.Certificate) (*CertificateSelector, error) {
+	s := &CertificateSelector{
+		exact:    make(map[string]*tls.Certificate),
+		wildcard: make(map[string]*tls.Certificate),
+		fallback: fallback,
+	}
+	for i := range certificates {
+		cert := &certificates[i].Certificate
+		domains := certificates[i].Domains
+		if len(domains) == 0 {
+			names, err := certificateNames(cert)
+			if err != nil {
+				return nil, err
+			}
+			domains = names
+		}
+		for _, domain := range domains {
+			domain = normalizeServerName(domain)
+			if parent, ok := strings.CutPrefix(domain, "*."); ok {
+				s.wildcard[parent] = cert
+			} else {
+				s.exact[domain] = cert
+			}
+		}
+	}
+	return s, nil
+}
+
+// certificateNames returns the DNS names of the leaf of cert, or its common name when it has none
+func certificateNames(cert *tls.Certificate) ([]string, error) {
+	if len(cert.Certificate) == 0 {
+		return nil, fmt.Errorf("%w: empty certificate", ErrNoCertificate)
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate: %w", err)
+		}
+	}
+	if len(leaf.DNSNames) > 0 {
+		return leaf.DNSNames, nil
+	}
+	return []string{leaf.Subject.CommonName}, nil
+}
+
+func normalizeServerName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// GetCertificate selects the certificate of hello, for use as tls.Config GetCertificate
+func (s *CertificateSelector) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := normalizeServerName(hello.ServerName)
+	if cert, ok := s.exact[name]; ok {
+		return cert, nil
+	}
+	if _, parent, found := strings.Cut(name, "."); found {
+		if cert, ok := s.wildcard[parent]; ok {
+			return cert, nil
+		}
+	}
+	if s.fallback != nil {
+		return s.fallback, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoCertificate, hello.ServerName)
+}
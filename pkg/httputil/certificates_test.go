@@ -0,0 +1,84 @@
+package httputil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, commonName string, dnsNames ...string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestCertificateSelector(t *testing.T) {
+	fallback := newTestCertificate(t, "lakefs.example.com", "lakefs.example.com")
+	certificates := []SNICertificate{
+		{Domains: []string{"data.internal", "*.data.internal"}, Certificate: newTestCertificate(t, "data.internal")},
+		// domains from the names of the certificate
+		{Certificate: newTestCertificate(t, "lake", "lake.example.com", "*.lake.example.com")},
+	}
+	selector, err := NewCertificateSelector(certificates, &fallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		serverName string
+		expected   string
+	}{
+		{serverName: "data.internal", expected: "data.internal"},
+		{serverName: "repo1.data.internal", expected: "data.internal"},
+		{serverName: "REPO1.Data.Internal.", expected: "data.internal"},
+		{serverName: "lake.example.com", expected: "lake"},
+		{serverName: "repo1.lake.example.com", expected: "lake"},
+		// wildcards match a single label
+		{serverName: "a.repo1.lake.example.com", expected: "lakefs.example.com"},
+		{serverName: "other.example.com", expected: "lakefs.example.com"},
+		{serverName: "", expected: "lakefs.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serverName, func(t *testing.T) {
+			cert, err := selector.GetCertificate(&tls.ClientHelloInfo{ServerName: tt.serverName})
+			if err != nil {
+				t.Fatal(err)
+			}
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if leaf.Subject.CommonName != tt.expected {
+				t.Errorf("server name %s got certificate %s, expected %s", tt.serverName, leaf.Subject.CommonName, tt.expected)
+			}
+		})
+	}
+
+	noFallback, err := NewCertificateSelector(certificates, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := noFallback.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"}); !errors.Is(err, ErrNoCertificate) {
+		t.Errorf("got error %v, expected %s", err, ErrNoCertificate)
+	}
+}
@@ -2,4 +2,6 @@ package httputil
 
 const (
 	RequestIDHeaderName = "X-Request-ID"
+	// TraceParentHeaderName is the W3C trace context header identifying the trace of a request
+	TraceParentHeaderName = "traceparent"
 )
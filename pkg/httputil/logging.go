@@ -40,20 +40,55 @@ func (w *ResponseRecordingWriter) WriteHeader(statusCode int) {
 	w.Writer.WriteHeader(statusCode)
 }
 
+// RequestID returns r with the ID of the request on its context, and the ID. The ID is the X-Request-ID header
+// sent by the client, or the trace ID of its traceparent header, so that clients can correlate their requests with
+// lakeFS logs, or a new ID.
 func RequestID(r *http.Request) (*http.Request, string) {
 	ctx := r.Context()
 	resp := ctx.Value(RequestIDContextKey)
 	var reqID string
 	if resp == nil {
 		// assign a request ID for this request
-		reqID = uuid.New().String()
-		r = r.WithContext(context.WithValue(ctx, RequestIDContextKey, reqID))
+		traceContext, hasTraceContext := ParseTraceParent(r.Header.Get(TraceParentHeaderName))
+		reqID = r.Header.Get(RequestIDHeaderName)
+		switch {
+		case validRequestID(reqID):
+		case hasTraceContext:
+			reqID = traceContext.TraceID
+		default:
+			reqID = uuid.New().String()
+		}
+		ctx = context.WithValue(ctx, RequestIDContextKey, reqID)
+		if hasTraceContext {
+			ctx = context.WithValue(ctx, traceContextKey, traceContext)
+		}
+		r = r.WithContext(ctx)
 	} else {
 		reqID = resp.(string)
 	}
 	return r, reqID
 }
 
+// RequestIDFromContext returns the ID of the request handled with ctx, or "" if none
+func RequestIDFromContext(ctx context.Context) string {
+	reqID, _ := ctx.Value(RequestIDContextKey).(string)
+	return reqID
+}
+
+// requestFields returns the logging fields identifying the request reqID of r
+func requestFields(r *http.Request, reqID string) logging.Fields {
+	fields := logging.Fields{
+		logging.PathFieldKey:      r.RequestURI,
+		logging.MethodFieldKey:    r.Method,
+		logging.HostFieldKey:      r.Host,
+		logging.RequestIDFieldKey: reqID,
+	}
+	if traceContext, ok := TraceContextFromContext(r.Context()); ok {
+		fields[logging.TraceIDFieldKey] = traceContext.TraceID
+	}
+	return fields
+}
+
 func SourceIP(r *http.Request) string {
 	sourceIP, sourcePort, err := net.SplitHostPort(r.RemoteAddr)
 
@@ -73,12 +108,7 @@ func DefaultLoggingMiddleware(requestIDHeaderName string, fields logging.Fields,
 			sourceIP := SourceIP(r)
 
 			// add default fields to context
-			requestFields := logging.Fields{
-				logging.PathFieldKey:      r.RequestURI,
-				logging.MethodFieldKey:    r.Method,
-				logging.HostFieldKey:      r.Host,
-				logging.RequestIDFieldKey: reqID,
-			}
+			requestFields := requestFields(r, reqID)
 			for k, v := range fields {
 				requestFields[k] = v
 			}
@@ -11,6 +11,8 @@ func RequestScheme(r *http.Request) string {
 	switch {
 	case r.URL.Scheme == schemeHTTPS:
 		return schemeHTTPS
+	case r.TLS != nil:
+		return schemeHTTPS
 	case r.Header.Get("X-Forwarded-Proto") == schemeHTTPS:
 		return schemeHTTPS
 	case r.Header.Get("X-Forwarded-Ssl") == "on":
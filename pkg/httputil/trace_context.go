@@ -0,0 +1,96 @@
+package httputil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	traceContextKey contextKey = "trace_context"
+
+	traceParentVersion = "00"
+	traceIDLength      = 32
+	parentIDLength     = 16
+	traceFlagsLength   = 2
+
+	// maxRequestIDLength bounds the length of request IDs sent by clients
+	maxRequestIDLength = 128
+)
+
+// TraceContext is the W3C trace context of a request, from its traceparent header
+type TraceContext struct {
+	TraceID  string
+	ParentID string
+	Flags    string
+}
+
+func isLowerHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTraceParent parses a W3C traceparent header value, returning false if it is not valid
+func ParseTraceParent(value string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	const traceParentParts = 4
+	if len(parts) < traceParentParts ||
+		!isLowerHex(parts[0], len(traceParentVersion)) || parts[0] == "ff" ||
+		// version 00 has exactly 4 parts, later versions may add more
+		(parts[0] == traceParentVersion && len(parts) != traceParentParts) ||
+		!isLowerHex(parts[1], traceIDLength) || parts[1] == strings.Repeat("0", traceIDLength) ||
+		!isLowerHex(parts[2], parentIDLength) || parts[2] == strings.Repeat("0", parentIDLength) ||
+		!isLowerHex(parts[3], traceFlagsLength) {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceID: parts[1], ParentID: parts[2], Flags: parts[3]}, true
+}
+
+// ChildTraceParent returns the traceparent header value of a request sent while handling a request of the trace,
+// with a new parent ID
+func (t TraceContext) ChildTraceParent() string {
+	parentID := make([]byte, parentIDLength/2) //nolint:mnd
+	_, _ = rand.Read(parentID)
+	return traceParentVersion + "-" + t.TraceID + "-" + hex.EncodeToString(parentID) + "-" + t.Flags
+}
+
+// TraceContextFromContext returns the trace context of the request handled with ctx, and false if the request has
+// none
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	traceContext, ok := ctx.Value(traceContextKey).(TraceContext)
+	return traceContext, ok
+}
+
+// SetTraceHeaders sets the request ID and trace context of the request handled with ctx on the headers of a request
+// sent while handling it, e.g. to a webhook, so that the receiver can correlate the requests
+func SetTraceHeaders(ctx context.Context, header http.Header) {
+	if reqID := RequestIDFromContext(ctx); reqID != "" {
+		header.Set(RequestIDHeaderName, reqID)
+	}
+	if traceContext, ok := TraceContextFromContext(ctx); ok {
+		header.Set(TraceParentHeaderName, traceContext.ChildTraceParent())
+	}
+}
+
+// validRequestID reports whether a request ID sent by a client may identify its request: printable, without
+// spaces, and not too long to log
+func validRequestID(reqID string) bool {
+	if reqID == "" || len(reqID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range reqID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/httputil"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	cases := []struct {
+		Value string
+		Valid bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		t.Run(c.Value, func(t *testing.T) {
+			traceContext, ok := httputil.ParseTraceParent(c.Value)
+			if ok != c.Valid {
+				t.Fatalf("ParseTraceParent valid %t, expected %t", ok, c.Valid)
+			}
+			if ok && traceContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("trace ID %s", traceContext.TraceID)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	cases := []struct {
+		Name        string
+		RequestID   string
+		TraceParent string
+		Expected    string
+	}{
+		{Name: "request_id", RequestID: "client-id-1", TraceParent: traceParent, Expected: "client-id-1"},
+		{Name: "trace_parent", TraceParent: traceParent, Expected: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{Name: "invalid_request_id", RequestID: "with space", TraceParent: traceParent, Expected: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{Name: "too_long_request_id", RequestID: strings.Repeat("a", 200)},
+		{Name: "none"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.RequestID != "" {
+				r.Header.Set(httputil.RequestIDHeaderName, c.RequestID)
+			}
+			if c.TraceParent != "" {
+				r.Header.Set(httputil.TraceParentHeaderName, c.TraceParent)
+			}
+			r, reqID := httputil.RequestID(r)
+			if c.Expected != "" && reqID != c.Expected {
+				t.Errorf("request ID %s, expected %s", reqID, c.Expected)
+			}
+			if reqID == "" || (c.Expected == "" && reqID == c.RequestID) {
+				t.Errorf("request ID %q, expected a new ID", reqID)
+			}
+			if _, again := httputil.RequestID(r); again != reqID {
+				t.Errorf("request ID changed from %s to %s", reqID, again)
+			}
+
+			header := http.Header{}
+			httputil.SetTraceHeaders(r.Context(), header)
+			if header.Get(httputil.RequestIDHeaderName) != reqID {
+				t.Errorf("propagated request ID %s, expected %s", header.Get(httputil.RequestIDHeaderName), reqID)
+			}
+			propagated, ok := httputil.ParseTraceParent(header.Get(httputil.TraceParentHeaderName))
+			if ok != (c.TraceParent != "") {
+				t.Fatalf("propagated traceparent %q", header.Get(httputil.TraceParentHeaderName))
+			}
+			if ok && (propagated.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || propagated.ParentID == "00f067aa0ba902b7") {
+				t.Errorf("propagated trace context %+v", propagated)
+			}
+		})
+	}
+}
@@ -115,12 +115,7 @@ func TracingMiddleware(requestIDHeaderName string, fields logging.Fields, traceR
 			r, reqID := RequestID(r)
 
 			// add default fields to context
-			requestFields := logging.Fields{
-				logging.PathFieldKey:      r.RequestURI,
-				logging.MethodFieldKey:    r.Method,
-				logging.HostFieldKey:      r.Host,
-				logging.RequestIDFieldKey: reqID,
-			}
+			requestFields := requestFields(r, reqID)
 			for k, v := range fields {
 				requestFields[k] = v
 			}
@@ -0,0 +1,63 @@
+package iceberg
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+)
+
+var (
+	ErrBadRequest           = errors.New("bad request")
+	ErrNotAuthorized        = errors.New("not authorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrNoSuchNamespace      = errors.New("namespace does not exist")
+	ErrNoSuchTable          = errors.New("table does not exist")
+	ErrAlreadyExists        = errors.New("already exists")
+	ErrNamespaceNotEmpty    = errors.New("namespace is not empty")
+	ErrCommitFailed         = errors.New("commit failed")
+	ErrUnsupportedOperation = errors.New("unsupported operation")
+	ErrReadOnlyRef          = errors.New("ref is not a branch")
+)
+
+// ErrorModel is the error returned by the Iceberg REST catalog
+type ErrorModel struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    int    `json:"code"`
+}
+
+type errorResponse struct {
+	Error ErrorModel `json:"error"`
+}
+
+// errorModelFromError maps errors to the Iceberg exception type and status code expected by clients
+func errorModelFromError(err error) ErrorModel {
+	var (
+		code    int
+		errType string
+	)
+	switch {
+	case errors.Is(err, ErrBadRequest), errors.Is(err, graveler.ErrInvalid), errors.Is(err, ErrReadOnlyRef):
+		code, errType = http.StatusBadRequest, "BadRequestException"
+	case errors.Is(err, ErrNotAuthorized):
+		code, errType = http.StatusUnauthorized, "NotAuthorizedException"
+	case errors.Is(err, ErrForbidden):
+		code, errType = http.StatusForbidden, "ForbiddenException"
+	case errors.Is(err, ErrNoSuchTable):
+		code, errType = http.StatusNotFound, "NoSuchTableException"
+	case errors.Is(err, ErrNoSuchNamespace), errors.Is(err, graveler.ErrNotFound):
+		code, errType = http.StatusNotFound, "NoSuchNamespaceException"
+	case errors.Is(err, ErrAlreadyExists):
+		code, errType = http.StatusConflict, "AlreadyExistsException"
+	case errors.Is(err, ErrNamespaceNotEmpty):
+		code, errType = http.StatusConflict, "NamespaceNotEmptyException"
+	case errors.Is(err, ErrCommitFailed):
+		code, errType = http.StatusConflict, "CommitFailedException"
+	case errors.Is(err, ErrUnsupportedOperation):
+		code, errType = http.StatusNotAcceptable, "UnsupportedOperationException"
+	default:
+		code, errType = http.StatusInternalServerError, "ServiceFailureException"
+	}
+	return ErrorModel{Message: err.Error(), Type: errType, Code: code}
+}
@@ -0,0 +1,265 @@
+package iceberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/treeverse/lakefs/pkg/accesslog"
+	"github.com/treeverse/lakefs/pkg/api"
+	"github.com/treeverse/lakefs/pkg/auth"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/httputil"
+	"github.com/treeverse/lakefs/pkg/logging"
+	"github.com/treeverse/lakefs/pkg/permissions"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+const (
+	// BasePath is the URI clients use as the Iceberg REST catalog endpoint
+	BasePath = "/iceberg/api"
+
+	LoggerServiceName = "iceberg_rest_catalog"
+
+	// namespaceSeparator separates multipart namespace levels in request paths and parameters
+	namespaceSeparator = "\x1f"
+
+	contentTypeJSON = "application/json"
+
+	oauthTokenType       = "bearer"
+	oauthIssuedTokenType = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// Config holds the catalog settings passed to Iceberg clients and used to issue tokens
+type Config struct {
+	// S3Endpoint is the lakeFS S3 gateway endpoint clients use to read and write table files
+	S3Endpoint string
+	// LoginDuration is the validity of tokens issued by the OAuth2 token endpoint
+	LoginDuration time.Duration
+}
+
+// Handler serves the Iceberg REST catalog API.
+// The catalog prefix is the repository, the first level of every namespace is a ref of the repository and the
+// following levels are the directories of the table location. Tables use the Hadoop catalog layout: metadata files
+// are stored under the table "metadata/" directory, and "metadata/version-hint.text" holds the current version.
+type Handler struct {
+	catalog       *catalog.Catalog
+	authenticator auth.Authenticator
+	authService   auth.Service
+	pathProvider  upload.PathProvider
+	cfg           Config
+}
+
+func NewHandler(c *catalog.Catalog, authenticator auth.Authenticator, authService auth.Service, pathProvider upload.PathProvider, cfg Config, auditLogLevel string, traceRequestHeaders bool) http.Handler {
+	h := &Handler{
+		catalog:       c,
+		authenticator: authenticator,
+		authService:   authService,
+		pathProvider:  pathProvider,
+		cfg:           cfg,
+	}
+	r := chi.NewRouter()
+	r.Use(accesslog.Middleware(LoggerServiceName))
+	r.Use(httputil.LoggingMiddleware(
+		httputil.RequestIDHeaderName,
+		logging.Fields{logging.ServiceNameFieldKey: LoggerServiceName},
+		auditLogLevel,
+		traceRequestHeaders))
+	r.Route(BasePath+"/v1", func(r chi.Router) {
+		r.Post("/oauth/tokens", h.oauthTokens)
+		r.Group(func(r chi.Router) {
+			r.Use(requireUser)
+			r.Get("/config", h.getConfig)
+			r.Route("/{prefix}", func(r chi.Router) {
+				r.Get("/namespaces", h.listNamespaces)
+				r.Post("/namespaces", h.createNamespace)
+				r.Get("/namespaces/{namespace}", h.loadNamespace)
+				r.Head("/namespaces/{namespace}", h.namespaceExists)
+				r.Delete("/namespaces/{namespace}", h.dropNamespace)
+				r.Post("/namespaces/{namespace}/properties", h.updateNamespaceProperties)
+				r.Get("/namespaces/{namespace}/tables", h.listTables)
+				r.Post("/namespaces/{namespace}/tables", h.createTable)
+				r.Get("/namespaces/{namespace}/tables/{table}", h.loadTable)
+				r.Head("/namespaces/{namespace}/tables/{table}", h.tableExists)
+				r.Post("/namespaces/{namespace}/tables/{table}", h.commitTable)
+				r.Delete("/namespaces/{namespace}/tables/{table}", h.dropTable)
+				r.Post("/tables/rename", h.renameTable)
+			})
+		})
+	})
+	return r
+}
+
+// requireUser rejects requests that were not authenticated by the lakeFS authentication middleware
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := auth.GetUser(r.Context()); err != nil {
+			writeError(w, r, ErrNotAuthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type oauthTokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	IssuedTokenType string `json:"issued_token_type"`
+}
+
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// oauthTokens exchanges lakeFS access key credentials (OAuth2 client credentials) for a lakeFS token
+func (h *Handler) oauthTokens(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, r, http.StatusBadRequest, oauthErrorResponse{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+	if grantType := r.PostForm.Get("grant_type"); grantType != "client_credentials" {
+		writeJSON(w, r, http.StatusBadRequest, oauthErrorResponse{Error: "unsupported_grant_type", ErrorDescription: grantType})
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	ctx := r.Context()
+	username, err := h.authenticator.AuthenticateUser(ctx, clientID, clientSecret)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("client_id", clientID).Debug("iceberg oauth authenticate")
+		writeJSON(w, r, http.StatusUnauthorized, oauthErrorResponse{Error: "invalid_client"})
+		return
+	}
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(h.cfg.LoginDuration)
+	token, err := api.GenerateJWTLogin(h.authService.SecretStore().SharedSecret(), username, issuedAt, expiresAt)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, oauthTokenResponse{
+		AccessToken:     token,
+		TokenType:       oauthTokenType,
+		ExpiresIn:       int64(h.cfg.LoginDuration.Seconds()),
+		IssuedTokenType: oauthIssuedTokenType,
+	})
+}
+
+type catalogConfig struct {
+	Defaults  map[string]string `json:"defaults"`
+	Overrides map[string]string `json:"overrides"`
+}
+
+// getConfig returns the client configuration. The warehouse selects the repository, either by name or as a
+// lakefs:// URI, and is used as the catalog prefix.
+func (h *Handler) getConfig(w http.ResponseWriter, r *http.Request) {
+	warehouse := strings.TrimSuffix(strings.TrimPrefix(r.URL.Query().Get("warehouse"), "lakefs://"), "/")
+	if warehouse == "" {
+		writeError(w, r, fmt.Errorf("%w: warehouse must be set to the repository name", ErrBadRequest))
+		return
+	}
+	repository, err := h.getRepository(r, warehouse, permissions.ReadRepositoryAction)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defaults := map[string]string{}
+	if h.cfg.S3Endpoint != "" {
+		defaults["s3.endpoint"] = h.cfg.S3Endpoint
+		defaults["s3.path-style-access"] = "true"
+	}
+	writeJSON(w, r, http.StatusOK, catalogConfig{
+		Defaults:  defaults,
+		Overrides: map[string]string{"prefix": repository.Name},
+	})
+}
+
+// renameTable is not supported: table files are addressed by their location, which includes the table path
+func (h *Handler) renameTable(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, fmt.Errorf("%w: rename table", ErrUnsupportedOperation))
+}
+
+// getRepository returns the repository after checking the user is allowed to perform action on it
+func (h *Handler) getRepository(r *http.Request, name string, action string) (*catalog.Repository, error) {
+	if err := h.authorize(r, permissions.Node{
+		Permission: permissions.Permission{Action: action, Resource: permissions.RepoArn(name)},
+	}); err != nil {
+		return nil, err
+	}
+	repository, err := h.catalog.GetRepository(r.Context(), name)
+	if err != nil {
+		return nil, fmt.Errorf("repository %s: %w", name, err)
+	}
+	return repository, nil
+}
+
+// authorize checks that the authenticated user holds the required permissions
+func (h *Handler) authorize(r *http.Request, perms permissions.Node) error {
+	ctx := r.Context()
+	user, err := auth.GetUser(ctx)
+	if err != nil {
+		return ErrNotAuthorized
+	}
+	resp, err := h.authService.Authorize(ctx, &auth.AuthorizationRequest{
+		Username:            user.Username,
+		RequiredPermissions: perms,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil || !resp.Allowed {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// parseNamespace splits a namespace path parameter into its levels
+func parseNamespace(s string) ([]string, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty namespace", ErrBadRequest)
+	}
+	levels := strings.Split(s, namespaceSeparator)
+	for _, level := range levels {
+		if level == "" || strings.Contains(level, "/") {
+			return nil, fmt.Errorf("%w: invalid namespace level '%s'", ErrBadRequest, level)
+		}
+	}
+	return levels, nil
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	if v == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Debug("failed to write iceberg response")
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	model := errorModelFromError(err)
+	if model.Code == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).WithError(err).Error("iceberg rest catalog request failed")
+	}
+	if r.Method == http.MethodHead {
+		w.WriteHeader(model.Code)
+		return
+	}
+	writeJSON(w, r, model.Code, errorResponse{Error: model})
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s", ErrBadRequest, err)
+	}
+	return nil
+}
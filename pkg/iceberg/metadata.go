@@ -0,0 +1,600 @@
+package iceberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// formatVersionDefault format version of tables created through the catalog
+	formatVersionDefault = 2
+	formatVersionMax     = 2
+
+	// partitionFieldIDStart partition field IDs are assigned starting after this value
+	partitionFieldIDStart = 999
+
+	mainBranchRef = "main"
+
+	refTypeBranch = "branch"
+	refTypeTag    = "tag"
+
+	propertyMetadataPreviousVersionsMax = "write.metadata.previous-versions-max"
+	metadataPreviousVersionsMaxDefault  = 100
+
+	noSnapshotID = -1
+	lastAddedID  = -1
+)
+
+// TableMetadata is the content of an Iceberg table metadata file.
+// Only the fields the catalog updates are modeled, schema and sort order fields are kept as is.
+type TableMetadata struct {
+	FormatVersion       int                    `json:"format-version"`
+	TableUUID           string                 `json:"table-uuid"`
+	Location            string                 `json:"location"`
+	LastSequenceNumber  int64                  `json:"last-sequence-number"`
+	LastUpdatedMS       int64                  `json:"last-updated-ms"`
+	LastColumnID        int                    `json:"last-column-id"`
+	Schema              json.RawMessage        `json:"schema,omitempty"`
+	Schemas             []Schema               `json:"schemas"`
+	CurrentSchemaID     int                    `json:"current-schema-id"`
+	PartitionSpec       json.RawMessage        `json:"partition-spec,omitempty"`
+	PartitionSpecs      []PartitionSpec        `json:"partition-specs"`
+	DefaultSpecID       int                    `json:"default-spec-id"`
+	LastPartitionID     int                    `json:"last-partition-id"`
+	Properties          map[string]string      `json:"properties,omitempty"`
+	CurrentSnapshotID   *int64                 `json:"current-snapshot-id,omitempty"`
+	Snapshots           []Snapshot             `json:"snapshots,omitempty"`
+	SnapshotLog         []SnapshotLogEntry     `json:"snapshot-log,omitempty"`
+	MetadataLog         []MetadataLogEntry     `json:"metadata-log,omitempty"`
+	SortOrders          []SortOrder            `json:"sort-orders"`
+	DefaultSortOrderID  int                    `json:"default-sort-order-id"`
+	Refs                map[string]SnapshotRef `json:"refs,omitempty"`
+	Statistics          []json.RawMessage      `json:"statistics,omitempty"`
+	PartitionStatistics []json.RawMessage      `json:"partition-statistics,omitempty"`
+}
+
+type Schema struct {
+	Type               string            `json:"type"`
+	SchemaID           int               `json:"schema-id"`
+	IdentifierFieldIDs []int             `json:"identifier-field-ids,omitempty"`
+	Fields             []json.RawMessage `json:"fields"`
+}
+
+type PartitionSpec struct {
+	SpecID int              `json:"spec-id"`
+	Fields []PartitionField `json:"fields"`
+}
+
+type PartitionField struct {
+	SourceID  int    `json:"source-id"`
+	FieldID   *int   `json:"field-id,omitempty"`
+	Name      string `json:"name"`
+	Transform string `json:"transform"`
+}
+
+type SortOrder struct {
+	OrderID int               `json:"order-id"`
+	Fields  []json.RawMessage `json:"fields"`
+}
+
+type Snapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	SequenceNumber   int64             `json:"sequence-number,omitempty"`
+	TimestampMS      int64             `json:"timestamp-ms"`
+	ManifestList     string            `json:"manifest-list,omitempty"`
+	Manifests        []string          `json:"manifests,omitempty"`
+	Summary          map[string]string `json:"summary,omitempty"`
+	SchemaID         *int              `json:"schema-id,omitempty"`
+}
+
+type SnapshotRef struct {
+	SnapshotID         int64  `json:"snapshot-id"`
+	Type               string `json:"type"`
+	MinSnapshotsToKeep *int   `json:"min-snapshots-to-keep,omitempty"`
+	MaxSnapshotAgeMS   *int64 `json:"max-snapshot-age-ms,omitempty"`
+	MaxRefAgeMS        *int64 `json:"max-ref-age-ms,omitempty"`
+}
+
+type SnapshotLogEntry struct {
+	TimestampMS int64 `json:"timestamp-ms"`
+	SnapshotID  int64 `json:"snapshot-id"`
+}
+
+type MetadataLogEntry struct {
+	TimestampMS  int64  `json:"timestamp-ms"`
+	MetadataFile string `json:"metadata-file"`
+}
+
+// TableRequirement is a condition that must hold on the current table metadata for a commit to apply
+type TableRequirement struct {
+	Type                    string `json:"type"`
+	Ref                     string `json:"ref,omitempty"`
+	UUID                    string `json:"uuid,omitempty"`
+	SnapshotID              *int64 `json:"snapshot-id,omitempty"`
+	LastAssignedFieldID     *int   `json:"last-assigned-field-id,omitempty"`
+	CurrentSchemaID         *int   `json:"current-schema-id,omitempty"`
+	LastAssignedPartitionID *int   `json:"last-assigned-partition-id,omitempty"`
+	DefaultSpecID           *int   `json:"default-spec-id,omitempty"`
+	DefaultSortOrderID      *int   `json:"default-sort-order-id,omitempty"`
+}
+
+// TableUpdate is a single change applied to table metadata by a commit
+type TableUpdate struct {
+	Action             string            `json:"action"`
+	UUID               string            `json:"uuid,omitempty"`
+	FormatVersion      int               `json:"format-version,omitempty"`
+	Schema             *Schema           `json:"schema,omitempty"`
+	LastColumnID       *int              `json:"last-column-id,omitempty"`
+	SchemaID           *int              `json:"schema-id,omitempty"`
+	Spec               *PartitionSpec    `json:"spec,omitempty"`
+	SpecID             *int              `json:"spec-id,omitempty"`
+	SortOrder          *SortOrder        `json:"sort-order,omitempty"`
+	SortOrderID        *int              `json:"sort-order-id,omitempty"`
+	Snapshot           *Snapshot         `json:"snapshot,omitempty"`
+	RefName            string            `json:"ref-name,omitempty"`
+	Type               string            `json:"type,omitempty"`
+	SnapshotID         *int64            `json:"snapshot-id,omitempty"`
+	MinSnapshotsToKeep *int              `json:"min-snapshots-to-keep,omitempty"`
+	MaxSnapshotAgeMS   *int64            `json:"max-snapshot-age-ms,omitempty"`
+	MaxRefAgeMS        *int64            `json:"max-ref-age-ms,omitempty"`
+	SnapshotIDs        []int64           `json:"snapshot-ids,omitempty"`
+	Location           string            `json:"location,omitempty"`
+	Updates            map[string]string `json:"updates,omitempty"`
+	Removals           []string          `json:"removals,omitempty"`
+}
+
+// NewTableMetadata builds the metadata of a new table
+func NewTableMetadata(location string, schema Schema, spec *PartitionSpec, sortOrder *SortOrder, properties map[string]string, now time.Time) (*TableMetadata, error) {
+	schema.SchemaID = 0
+	lastColumnID, err := schema.maxFieldID()
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		spec = &PartitionSpec{}
+	}
+	if sortOrder == nil {
+		sortOrder = &SortOrder{}
+	}
+	if properties == nil {
+		properties = map[string]string{}
+	}
+	m := &TableMetadata{
+		FormatVersion:   formatVersionDefault,
+		TableUUID:       uuid.NewString(),
+		Location:        location,
+		LastUpdatedMS:   now.UnixMilli(),
+		LastColumnID:    lastColumnID,
+		Schemas:         []Schema{schema},
+		LastPartitionID: partitionFieldIDStart,
+		PartitionSpecs:  []PartitionSpec{},
+		SortOrders:      []SortOrder{},
+		Properties:      properties,
+	}
+	if formatVersion, ok := properties["format-version"]; ok {
+		v, err := strconv.Atoi(formatVersion)
+		if err != nil || v < 1 || v > formatVersionMax {
+			return nil, fmt.Errorf("%w: unsupported format-version %s", ErrBadRequest, formatVersion)
+		}
+		m.FormatVersion = v
+		delete(properties, "format-version")
+	}
+	m.DefaultSpecID = m.addSpec(*spec)
+	m.DefaultSortOrderID = m.addSortOrder(*sortOrder)
+	return m, nil
+}
+
+// Validate checks that the requirement holds on metadata. metadata is nil when the table does not exist.
+func (r TableRequirement) Validate(m *TableMetadata) error {
+	if r.Type == "assert-create" {
+		if m != nil {
+			return fmt.Errorf("%w: table already exists", ErrCommitFailed)
+		}
+		return nil
+	}
+	if m == nil {
+		return fmt.Errorf("%w: requirement %s on a table that does not exist", ErrCommitFailed, r.Type)
+	}
+	switch r.Type {
+	case "assert-table-uuid":
+		if r.UUID != m.TableUUID {
+			return fmt.Errorf("%w: table UUID %s does not match %s", ErrCommitFailed, m.TableUUID, r.UUID)
+		}
+	case "assert-ref-snapshot-id":
+		ref, ok := m.snapshotRef(r.Ref)
+		switch {
+		case r.SnapshotID == nil && ok:
+			return fmt.Errorf("%w: ref %s was created concurrently", ErrCommitFailed, r.Ref)
+		case r.SnapshotID != nil && !ok:
+			return fmt.Errorf("%w: ref %s is missing, expected snapshot %d", ErrCommitFailed, r.Ref, *r.SnapshotID)
+		case r.SnapshotID != nil && ref.SnapshotID != *r.SnapshotID:
+			return fmt.Errorf("%w: ref %s snapshot %d does not match %d", ErrCommitFailed, r.Ref, ref.SnapshotID, *r.SnapshotID)
+		}
+	case "assert-last-assigned-field-id":
+		if r.LastAssignedFieldID == nil || *r.LastAssignedFieldID != m.LastColumnID {
+			return fmt.Errorf("%w: last assigned field id changed to %d", ErrCommitFailed, m.LastColumnID)
+		}
+	case "assert-current-schema-id":
+		if r.CurrentSchemaID == nil || *r.CurrentSchemaID != m.CurrentSchemaID {
+			return fmt.Errorf("%w: current schema changed to %d", ErrCommitFailed, m.CurrentSchemaID)
+		}
+	case "assert-last-assigned-partition-id":
+		if r.LastAssignedPartitionID == nil || *r.LastAssignedPartitionID != m.LastPartitionID {
+			return fmt.Errorf("%w: last assigned partition id changed to %d", ErrCommitFailed, m.LastPartitionID)
+		}
+	case "assert-default-spec-id":
+		if r.DefaultSpecID == nil || *r.DefaultSpecID != m.DefaultSpecID {
+			return fmt.Errorf("%w: default partition spec changed to %d", ErrCommitFailed, m.DefaultSpecID)
+		}
+	case "assert-default-sort-order-id":
+		if r.DefaultSortOrderID == nil || *r.DefaultSortOrderID != m.DefaultSortOrderID {
+			return fmt.Errorf("%w: default sort order changed to %d", ErrCommitFailed, m.DefaultSortOrderID)
+		}
+	default:
+		return fmt.Errorf("%w: unknown requirement %s", ErrBadRequest, r.Type)
+	}
+	return nil
+}
+
+// Apply applies the updates, in order, to the table metadata
+func (m *TableMetadata) Apply(updates []TableUpdate, now time.Time) error {
+	var (
+		lastAddedSchemaID    *int
+		lastAddedSpecID      *int
+		lastAddedSortOrderID *int
+	)
+	for _, u := range updates {
+		switch u.Action {
+		case "assign-uuid":
+			m.TableUUID = u.UUID
+		case "upgrade-format-version":
+			if u.FormatVersion < m.FormatVersion || u.FormatVersion > formatVersionMax {
+				return fmt.Errorf("%w: cannot change format version from %d to %d", ErrBadRequest, m.FormatVersion, u.FormatVersion)
+			}
+			m.FormatVersion = u.FormatVersion
+		case "add-schema":
+			if u.Schema == nil {
+				return fmt.Errorf("%w: add-schema without schema", ErrBadRequest)
+			}
+			id, err := m.addSchema(*u.Schema, u.LastColumnID)
+			if err != nil {
+				return err
+			}
+			lastAddedSchemaID = &id
+		case "set-current-schema":
+			id, err := resolveLastAdded(u.SchemaID, lastAddedSchemaID, "schema")
+			if err != nil {
+				return err
+			}
+			if m.findSchema(id) < 0 {
+				return fmt.Errorf("%w: unknown schema %d", ErrBadRequest, id)
+			}
+			m.CurrentSchemaID = id
+		case "add-spec":
+			if u.Spec == nil {
+				return fmt.Errorf("%w: add-spec without spec", ErrBadRequest)
+			}
+			id := m.addSpec(*u.Spec)
+			lastAddedSpecID = &id
+		case "set-default-spec":
+			id, err := resolveLastAdded(u.SpecID, lastAddedSpecID, "partition spec")
+			if err != nil {
+				return err
+			}
+			if m.findSpec(id) < 0 {
+				return fmt.Errorf("%w: unknown partition spec %d", ErrBadRequest, id)
+			}
+			m.DefaultSpecID = id
+		case "add-sort-order":
+			if u.SortOrder == nil {
+				return fmt.Errorf("%w: add-sort-order without sort order", ErrBadRequest)
+			}
+			id := m.addSortOrder(*u.SortOrder)
+			lastAddedSortOrderID = &id
+		case "set-default-sort-order":
+			id, err := resolveLastAdded(u.SortOrderID, lastAddedSortOrderID, "sort order")
+			if err != nil {
+				return err
+			}
+			if m.findSortOrder(id) < 0 {
+				return fmt.Errorf("%w: unknown sort order %d", ErrBadRequest, id)
+			}
+			m.DefaultSortOrderID = id
+		case "add-snapshot":
+			if err := m.addSnapshot(u.Snapshot); err != nil {
+				return err
+			}
+		case "set-snapshot-ref":
+			if err := m.setSnapshotRef(u, now); err != nil {
+				return err
+			}
+		case "remove-snapshots":
+			m.removeSnapshots(u.SnapshotIDs)
+		case "remove-snapshot-ref":
+			delete(m.Refs, u.RefName)
+			if u.RefName == mainBranchRef {
+				m.CurrentSnapshotID = nil
+			}
+		case "set-location":
+			m.Location = u.Location
+		case "set-properties":
+			if m.Properties == nil {
+				m.Properties = make(map[string]string, len(u.Updates))
+			}
+			for k, v := range u.Updates {
+				m.Properties[k] = v
+			}
+		case "remove-properties":
+			for _, k := range u.Removals {
+				delete(m.Properties, k)
+			}
+		default:
+			return fmt.Errorf("%w: unknown update action %s", ErrBadRequest, u.Action)
+		}
+	}
+	return nil
+}
+
+// AddMetadataLog records the previous metadata file in the metadata log, keeping up to the number of entries
+// configured by the table properties
+func (m *TableMetadata) AddMetadataLog(previous MetadataLogEntry) {
+	m.MetadataLog = append(m.MetadataLog, previous)
+	maxEntries := metadataPreviousVersionsMaxDefault
+	if v, err := strconv.Atoi(m.Properties[propertyMetadataPreviousVersionsMax]); err == nil && v > 0 {
+		maxEntries = v
+	}
+	if len(m.MetadataLog) > maxEntries {
+		m.MetadataLog = m.MetadataLog[len(m.MetadataLog)-maxEntries:]
+	}
+}
+
+func resolveLastAdded(id, lastAdded *int, name string) (int, error) {
+	if id == nil {
+		return 0, fmt.Errorf("%w: missing %s id", ErrBadRequest, name)
+	}
+	if *id != lastAddedID {
+		return *id, nil
+	}
+	if lastAdded == nil {
+		return 0, fmt.Errorf("%w: no %s was added in this commit", ErrBadRequest, name)
+	}
+	return *lastAdded, nil
+}
+
+func (m *TableMetadata) snapshotRef(name string) (SnapshotRef, bool) {
+	if ref, ok := m.Refs[name]; ok {
+		return ref, true
+	}
+	// tables written without refs keep the main branch as the current snapshot
+	if name == mainBranchRef && m.CurrentSnapshotID != nil && *m.CurrentSnapshotID != noSnapshotID {
+		return SnapshotRef{SnapshotID: *m.CurrentSnapshotID, Type: refTypeBranch}, true
+	}
+	return SnapshotRef{}, false
+}
+
+func (m *TableMetadata) findSchema(id int) int {
+	for i, s := range m.Schemas {
+		if s.SchemaID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *TableMetadata) addSchema(schema Schema, lastColumnID *int) (int, error) {
+	maxID, err := schema.maxFieldID()
+	if err != nil {
+		return 0, err
+	}
+	if lastColumnID != nil && *lastColumnID > maxID {
+		maxID = *lastColumnID
+	}
+	schema.SchemaID = 0
+	for _, s := range m.Schemas {
+		if s.SchemaID >= schema.SchemaID {
+			schema.SchemaID = s.SchemaID + 1
+		}
+	}
+	m.Schemas = append(m.Schemas, schema)
+	if maxID > m.LastColumnID {
+		m.LastColumnID = maxID
+	}
+	return schema.SchemaID, nil
+}
+
+func (m *TableMetadata) findSpec(id int) int {
+	for i, s := range m.PartitionSpecs {
+		if s.SpecID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *TableMetadata) addSpec(spec PartitionSpec) int {
+	spec.SpecID = 0
+	for _, s := range m.PartitionSpecs {
+		if s.SpecID >= spec.SpecID {
+			spec.SpecID = s.SpecID + 1
+		}
+	}
+	fields := make([]PartitionField, len(spec.Fields))
+	for i, f := range spec.Fields {
+		if f.FieldID == nil {
+			id := m.LastPartitionID + 1
+			f.FieldID = &id
+		}
+		if *f.FieldID > m.LastPartitionID {
+			m.LastPartitionID = *f.FieldID
+		}
+		fields[i] = f
+	}
+	spec.Fields = fields
+	m.PartitionSpecs = append(m.PartitionSpecs, spec)
+	return spec.SpecID
+}
+
+func (m *TableMetadata) findSortOrder(id int) int {
+	for i, s := range m.SortOrders {
+		if s.OrderID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *TableMetadata) addSortOrder(order SortOrder) int {
+	// the unsorted order always uses ID 0
+	if len(order.Fields) == 0 {
+		order.OrderID = 0
+		if m.findSortOrder(0) < 0 {
+			m.SortOrders = append(m.SortOrders, order)
+		}
+		return 0
+	}
+	order.OrderID = 1
+	for _, s := range m.SortOrders {
+		if s.OrderID >= order.OrderID {
+			order.OrderID = s.OrderID + 1
+		}
+	}
+	m.SortOrders = append(m.SortOrders, order)
+	return order.OrderID
+}
+
+func (m *TableMetadata) findSnapshot(id int64) int {
+	for i, s := range m.Snapshots {
+		if s.SnapshotID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *TableMetadata) addSnapshot(snapshot *Snapshot) error {
+	if snapshot == nil {
+		return fmt.Errorf("%w: add-snapshot without snapshot", ErrBadRequest)
+	}
+	if m.findSnapshot(snapshot.SnapshotID) >= 0 {
+		return fmt.Errorf("%w: snapshot %d already exists", ErrCommitFailed, snapshot.SnapshotID)
+	}
+	if m.FormatVersion > 1 && snapshot.SequenceNumber <= m.LastSequenceNumber && snapshot.ParentSnapshotID != nil {
+		return fmt.Errorf("%w: snapshot sequence number %d is not greater than last sequence number %d", ErrCommitFailed, snapshot.SequenceNumber, m.LastSequenceNumber)
+	}
+	m.Snapshots = append(m.Snapshots, *snapshot)
+	if snapshot.SequenceNumber > m.LastSequenceNumber {
+		m.LastSequenceNumber = snapshot.SequenceNumber
+	}
+	if snapshot.TimestampMS > m.LastUpdatedMS {
+		m.LastUpdatedMS = snapshot.TimestampMS
+	}
+	return nil
+}
+
+func (m *TableMetadata) setSnapshotRef(u TableUpdate, now time.Time) error {
+	if u.RefName == "" || u.SnapshotID == nil {
+		return fmt.Errorf("%w: set-snapshot-ref requires ref name and snapshot id", ErrBadRequest)
+	}
+	i := m.findSnapshot(*u.SnapshotID)
+	if i < 0 {
+		return fmt.Errorf("%w: unknown snapshot %d", ErrBadRequest, *u.SnapshotID)
+	}
+	refType := u.Type
+	if refType == "" {
+		refType = refTypeBranch
+	}
+	if refType != refTypeBranch && refType != refTypeTag {
+		return fmt.Errorf("%w: unknown ref type %s", ErrBadRequest, refType)
+	}
+	if m.Refs == nil {
+		m.Refs = make(map[string]SnapshotRef)
+	}
+	m.Refs[u.RefName] = SnapshotRef{
+		SnapshotID:         *u.SnapshotID,
+		Type:               refType,
+		MinSnapshotsToKeep: u.MinSnapshotsToKeep,
+		MaxSnapshotAgeMS:   u.MaxSnapshotAgeMS,
+		MaxRefAgeMS:        u.MaxRefAgeMS,
+	}
+	if u.RefName == mainBranchRef {
+		snapshotID := *u.SnapshotID
+		m.CurrentSnapshotID = &snapshotID
+		timestamp := m.Snapshots[i].TimestampMS
+		if timestamp == 0 {
+			timestamp = now.UnixMilli()
+		}
+		m.SnapshotLog = append(m.SnapshotLog, SnapshotLogEntry{TimestampMS: timestamp, SnapshotID: snapshotID})
+	}
+	return nil
+}
+
+func (m *TableMetadata) removeSnapshots(ids []int64) {
+	removed := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		removed[id] = struct{}{}
+	}
+	snapshots := m.Snapshots[:0]
+	for _, s := range m.Snapshots {
+		if _, ok := removed[s.SnapshotID]; !ok {
+			snapshots = append(snapshots, s)
+		}
+	}
+	m.Snapshots = snapshots
+	snapshotLog := m.SnapshotLog[:0]
+	for _, e := range m.SnapshotLog {
+		if _, ok := removed[e.SnapshotID]; !ok {
+			snapshotLog = append(snapshotLog, e)
+		}
+	}
+	m.SnapshotLog = snapshotLog
+	for name, ref := range m.Refs {
+		if _, ok := removed[ref.SnapshotID]; ok {
+			delete(m.Refs, name)
+		}
+	}
+	if m.CurrentSnapshotID != nil {
+		if _, ok := removed[*m.CurrentSnapshotID]; ok {
+			m.CurrentSnapshotID = nil
+		}
+	}
+}
+
+// maxFieldID returns the highest field ID used by the schema, including nested fields
+func (s Schema) maxFieldID() (int, error) {
+	maxID := 0
+	for _, raw := range s.Fields {
+		var field interface{}
+		if err := json.Unmarshal(raw, &field); err != nil {
+			return 0, fmt.Errorf("%w: schema field: %s", ErrBadRequest, err)
+		}
+		walkFieldIDs(field, func(id int) {
+			if id > maxID {
+				maxID = id
+			}
+		})
+	}
+	return maxID, nil
+}
+
+func walkFieldIDs(v interface{}, fn func(id int)) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, key := range []string{"id", "element-id", "key-id", "value-id"} {
+			if id, ok := t[key].(float64); ok {
+				fn(int(id))
+			}
+		}
+		for _, key := range []string{"type", "fields", "element", "key", "value"} {
+			walkFieldIDs(t[key], fn)
+		}
+	case []interface{}:
+		for _, e := range t {
+			walkFieldIDs(e, fn)
+		}
+	}
+}
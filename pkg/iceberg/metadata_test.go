@@ -0,0 +1,161 @@
+package iceberg_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/iceberg"
+)
+
+func testSchema(t *testing.T) iceberg.Schema {
+	t.Helper()
+	var schema iceberg.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "struct",
+		"schema-id": 7,
+		"fields": [
+			{"id": 1, "name": "id", "required": true, "type": "long"},
+			{"id": 2, "name": "tags", "required": false, "type": {"type": "list", "element-id": 5, "element": "string", "element-required": false}},
+			{"id": 3, "name": "location", "required": false, "type": {"type": "struct", "fields": [{"id": 4, "name": "lat", "required": false, "type": "double"}]}}
+		]
+	}`), &schema)
+	require.NoError(t, err)
+	return schema
+}
+
+func TestNewTableMetadata(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fieldName := "id_bucket"
+	spec := &iceberg.PartitionSpec{Fields: []iceberg.PartitionField{{SourceID: 1, Name: fieldName, Transform: "bucket[16]"}}}
+	m, err := iceberg.NewTableMetadata("s3://repo/main/db/t", testSchema(t), spec, nil, map[string]string{"format-version": "1", "owner": "me"}, now)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, m.FormatVersion)
+	require.NotEmpty(t, m.TableUUID)
+	require.Equal(t, now.UnixMilli(), m.LastUpdatedMS)
+	require.Equal(t, 5, m.LastColumnID, "last column id should include nested field ids")
+	require.Len(t, m.Schemas, 1)
+	require.Equal(t, 0, m.Schemas[0].SchemaID)
+	require.Equal(t, 0, m.CurrentSchemaID)
+	require.Len(t, m.PartitionSpecs, 1)
+	require.Equal(t, 0, m.DefaultSpecID)
+	require.Equal(t, 1000, *m.PartitionSpecs[0].Fields[0].FieldID)
+	require.Equal(t, 1000, m.LastPartitionID)
+	require.Len(t, m.SortOrders, 1)
+	require.Equal(t, 0, m.DefaultSortOrderID)
+	require.Equal(t, map[string]string{"owner": "me"}, m.Properties)
+
+	_, err = iceberg.NewTableMetadata("s3://repo/main/db/t", testSchema(t), nil, nil, map[string]string{"format-version": "3"}, now)
+	require.ErrorIs(t, err, iceberg.ErrBadRequest)
+}
+
+func TestTableRequirementValidate(t *testing.T) {
+	m, err := iceberg.NewTableMetadata("s3://repo/main/db/t", testSchema(t), nil, nil, nil, time.Now())
+	require.NoError(t, err)
+	snapshotID := int64(42)
+	require.NoError(t, m.Apply([]iceberg.TableUpdate{
+		{Action: "add-snapshot", Snapshot: &iceberg.Snapshot{SnapshotID: snapshotID, SequenceNumber: 1, TimestampMS: 1}},
+		{Action: "set-snapshot-ref", RefName: "main", SnapshotID: &snapshotID},
+	}, time.Now()))
+
+	otherSnapshotID := int64(43)
+	fieldID := 5
+	otherFieldID := 4
+	tests := []struct {
+		name        string
+		metadata    *iceberg.TableMetadata
+		requirement iceberg.TableRequirement
+		expectedErr error
+	}{
+		{name: "create_missing", requirement: iceberg.TableRequirement{Type: "assert-create"}},
+		{name: "create_exists", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-create"}, expectedErr: iceberg.ErrCommitFailed},
+		{name: "uuid_missing_table", requirement: iceberg.TableRequirement{Type: "assert-table-uuid", UUID: m.TableUUID}, expectedErr: iceberg.ErrCommitFailed},
+		{name: "uuid", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-table-uuid", UUID: m.TableUUID}},
+		{name: "uuid_mismatch", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-table-uuid", UUID: "other"}, expectedErr: iceberg.ErrCommitFailed},
+		{name: "ref_snapshot", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-ref-snapshot-id", Ref: "main", SnapshotID: &snapshotID}},
+		{name: "ref_snapshot_mismatch", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-ref-snapshot-id", Ref: "main", SnapshotID: &otherSnapshotID}, expectedErr: iceberg.ErrCommitFailed},
+		{name: "ref_created_concurrently", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-ref-snapshot-id", Ref: "main"}, expectedErr: iceberg.ErrCommitFailed},
+		{name: "ref_missing", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-ref-snapshot-id", Ref: "audit"}},
+		{name: "last_field_id", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-last-assigned-field-id", LastAssignedFieldID: &fieldID}},
+		{name: "last_field_id_mismatch", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-last-assigned-field-id", LastAssignedFieldID: &otherFieldID}, expectedErr: iceberg.ErrCommitFailed},
+		{name: "unknown", metadata: m, requirement: iceberg.TableRequirement{Type: "assert-nothing"}, expectedErr: iceberg.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.requirement.Validate(tt.metadata)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Validate() error = %v, expected %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestTableMetadataApply(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	m, err := iceberg.NewTableMetadata("s3://repo/main/db/t", testSchema(t), nil, nil, nil, now)
+	require.NoError(t, err)
+
+	t.Run("schema_and_spec", func(t *testing.T) {
+		schema := testSchema(t)
+		schema.Fields = append(schema.Fields, json.RawMessage(`{"id": 6, "name": "ts", "required": false, "type": "timestamptz"}`))
+		lastAdded := -1
+		err := m.Apply([]iceberg.TableUpdate{
+			{Action: "add-schema", Schema: &schema},
+			{Action: "set-current-schema", SchemaID: &lastAdded},
+			{Action: "add-spec", Spec: &iceberg.PartitionSpec{Fields: []iceberg.PartitionField{{SourceID: 6, Name: "ts_day", Transform: "day"}}}},
+			{Action: "set-default-spec", SpecID: &lastAdded},
+			{Action: "set-properties", Updates: map[string]string{"a": "1", "b": "2"}},
+			{Action: "remove-properties", Removals: []string{"a"}},
+		}, now)
+		require.NoError(t, err)
+		require.Len(t, m.Schemas, 2)
+		require.Equal(t, 1, m.CurrentSchemaID)
+		require.Equal(t, 6, m.LastColumnID)
+		require.Equal(t, 1, m.DefaultSpecID)
+		require.Equal(t, 1000, m.LastPartitionID)
+		require.Equal(t, map[string]string{"b": "2"}, m.Properties)
+	})
+
+	t.Run("snapshots", func(t *testing.T) {
+		first, second := int64(1), int64(2)
+		err := m.Apply([]iceberg.TableUpdate{
+			{Action: "add-snapshot", Snapshot: &iceberg.Snapshot{SnapshotID: first, SequenceNumber: 1, TimestampMS: now.UnixMilli() + 1}},
+			{Action: "set-snapshot-ref", RefName: "main", Type: "branch", SnapshotID: &first},
+			{Action: "add-snapshot", Snapshot: &iceberg.Snapshot{SnapshotID: second, ParentSnapshotID: &first, SequenceNumber: 2, TimestampMS: now.UnixMilli() + 2}},
+			{Action: "set-snapshot-ref", RefName: "main", Type: "branch", SnapshotID: &second},
+			{Action: "set-snapshot-ref", RefName: "v1", Type: "tag", SnapshotID: &first},
+		}, now)
+		require.NoError(t, err)
+		require.Equal(t, second, *m.CurrentSnapshotID)
+		require.Equal(t, int64(2), m.LastSequenceNumber)
+		require.Len(t, m.SnapshotLog, 2)
+		require.Len(t, m.Refs, 2)
+
+		err = m.Apply([]iceberg.TableUpdate{{Action: "add-snapshot", Snapshot: &iceberg.Snapshot{SnapshotID: second}}}, now)
+		require.ErrorIs(t, err, iceberg.ErrCommitFailed)
+
+		err = m.Apply([]iceberg.TableUpdate{{Action: "remove-snapshots", SnapshotIDs: []int64{first}}}, now)
+		require.NoError(t, err)
+		require.Len(t, m.Snapshots, 1)
+		require.Len(t, m.SnapshotLog, 1)
+		require.NotContains(t, m.Refs, "v1")
+		require.Equal(t, second, *m.CurrentSnapshotID)
+	})
+
+	t.Run("unknown_action", func(t *testing.T) {
+		err := m.Apply([]iceberg.TableUpdate{{Action: "do-something"}}, now)
+		require.ErrorIs(t, err, iceberg.ErrBadRequest)
+	})
+}
+
+func TestTableMetadataAddMetadataLog(t *testing.T) {
+	m, err := iceberg.NewTableMetadata("s3://repo/main/db/t", testSchema(t), nil, nil, map[string]string{"write.metadata.previous-versions-max": "2"}, time.Now())
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		m.AddMetadataLog(iceberg.MetadataLogEntry{TimestampMS: int64(i), MetadataFile: "v" + string(rune('0'+i))})
+	}
+	require.Equal(t, []iceberg.MetadataLogEntry{{TimestampMS: 2, MetadataFile: "v2"}, {TimestampMS: 3, MetadataFile: "v3"}}, m.MetadataLog)
+}
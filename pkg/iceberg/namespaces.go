@@ -0,0 +1,398 @@
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	// namespacePropertiesFilename holds the properties of a namespace, relative to the namespace directory
+	namespacePropertiesFilename = ".iceberg-namespace.json"
+
+	pathDelimiter = "/"
+)
+
+type namespaceResponse struct {
+	Namespace  []string          `json:"namespace"`
+	Properties map[string]string `json:"properties"`
+}
+
+type listNamespacesResponse struct {
+	Namespaces [][]string `json:"namespaces"`
+}
+
+type createNamespaceRequest struct {
+	Namespace  []string          `json:"namespace"`
+	Properties map[string]string `json:"properties"`
+}
+
+type updateNamespacePropertiesRequest struct {
+	Removals []string          `json:"removals"`
+	Updates  map[string]string `json:"updates"`
+}
+
+type updateNamespacePropertiesResponse struct {
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// namespacePath returns the directory of the namespace on its ref: levels following the ref, with a trailing
+// delimiter. The namespace of the ref itself is the root of the ref.
+func namespacePath(levels []string) string {
+	if len(levels) <= 1 {
+		return ""
+	}
+	return strings.Join(levels[1:], pathDelimiter) + pathDelimiter
+}
+
+// requestRepository returns the repository selected by the catalog prefix of the request
+func (h *Handler) requestRepository(r *http.Request) (*catalog.Repository, error) {
+	return h.getRepository(r, chi.URLParam(r, "prefix"), permissions.ReadRepositoryAction)
+}
+
+// requestNamespace returns the repository and the levels of the namespace in the request path
+func (h *Handler) requestNamespace(r *http.Request) (*catalog.Repository, []string, error) {
+	repository, err := h.requestRepository(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	param, err := url.PathUnescape(chi.URLParam(r, "namespace"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrBadRequest, err)
+	}
+	levels, err := parseNamespace(param)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repository, levels, nil
+}
+
+// authorizeObject checks the user is allowed to perform action on path in repository
+func (h *Handler) authorizeObject(r *http.Request, action string, repository *catalog.Repository, path string) error {
+	return h.authorize(r, permissions.Node{
+		Permission: permissions.Permission{Action: action, Resource: permissions.ObjectArn(repository.Name, path)},
+	})
+}
+
+// checkNamespaceExists returns ErrNoSuchNamespace unless the namespace exists. The namespace of a ref exists when
+// the ref does, nested namespaces exist as long as their directory holds any object.
+func (h *Handler) checkNamespaceExists(ctx context.Context, repository *catalog.Repository, levels []string) error {
+	if len(levels) == 1 {
+		_, err := h.catalog.GetCommit(ctx, repository.Name, levels[0])
+		if errors.Is(err, graveler.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrNoSuchNamespace, strings.Join(levels, "."))
+		}
+		return err
+	}
+	empty, err := h.isEmpty(ctx, repository, levels[0], namespacePath(levels))
+	if errors.Is(err, graveler.ErrNotFound) || empty {
+		return fmt.Errorf("%w: %s", ErrNoSuchNamespace, strings.Join(levels, "."))
+	}
+	return err
+}
+
+// readNamespaceProperties returns the stored properties of the namespace, empty if none were set
+func (h *Handler) readNamespaceProperties(ctx context.Context, repository *catalog.Repository, levels []string) (map[string]string, error) {
+	properties := map[string]string{}
+	if len(levels) == 1 {
+		return properties, nil
+	}
+	data, err := h.readObject(ctx, repository, levels[0], namespacePath(levels)+namespacePropertiesFilename)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return properties, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, fmt.Errorf("namespace %s properties: %w", strings.Join(levels, "."), err)
+	}
+	return properties, nil
+}
+
+func (h *Handler) writeNamespaceProperties(ctx context.Context, repository *catalog.Repository, levels []string, properties map[string]string, ifAbsent bool) error {
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+	return h.writeObject(ctx, repository, levels[0], namespacePath(levels)+namespacePropertiesFilename, contentTypeJSON, data, ifAbsent)
+}
+
+// listNamespaces lists the namespaces under the parent namespace. Without a parent, the namespaces are the
+// repository branches.
+func (h *Handler) listNamespaces(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, err := h.requestRepository(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	parent := r.URL.Query().Get("parent")
+	response := listNamespacesResponse{Namespaces: [][]string{}}
+	if parent == "" {
+		if err := h.authorize(r, permissions.Node{
+			Permission: permissions.Permission{Action: permissions.ListBranchesAction, Resource: permissions.RepoArn(repository.Name)},
+		}); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		after := ""
+		for {
+			branches, hasMore, err := h.catalog.ListBranches(ctx, repository.Name, "", listEntriesAmount, after)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			for _, branch := range branches {
+				response.Namespaces = append(response.Namespaces, []string{branch.Name})
+			}
+			if !hasMore || len(branches) == 0 {
+				break
+			}
+			after = branches[len(branches)-1].Name
+		}
+		writeJSON(w, r, http.StatusOK, response)
+		return
+	}
+
+	levels, err := parseNamespace(parent)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.authorize(r, permissions.Node{
+		Permission: permissions.Permission{Action: permissions.ListObjectsAction, Resource: permissions.RepoArn(repository.Name)},
+	}); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(ctx, repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	entries, err := h.listEntries(ctx, repository, levels[0], namespacePath(levels), pathDelimiter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.CommonLevel {
+			continue
+		}
+		// directories holding a table are tables, not namespaces
+		isTable, err := h.objectExists(ctx, repository, levels[0], entry.Path+versionHintPath)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if isTable {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(entry.Path, namespacePath(levels)), pathDelimiter)
+		namespace := append(append([]string{}, levels...), name)
+		response.Namespaces = append(response.Namespaces, namespace)
+	}
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// createNamespace creates a nested namespace. Namespaces of refs are created by creating a branch.
+func (h *Handler) createNamespace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, err := h.requestRepository(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var body createNamespaceRequest
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	levels, err := parseNamespace(strings.Join(body.Namespace, namespaceSeparator))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(levels) == 1 {
+		writeError(w, r, fmt.Errorf("%w: top level namespaces are the repository branches, create a branch instead", ErrBadRequest))
+		return
+	}
+	propertiesPath := namespacePath(levels) + namespacePropertiesFilename
+	if err := h.authorizeObject(r, permissions.WriteObjectAction, repository, propertiesPath); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.writableBranch(ctx, repository, levels[0]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	err = h.checkNamespaceExists(ctx, repository, levels)
+	if err == nil {
+		writeError(w, r, fmt.Errorf("%w: namespace %s", ErrAlreadyExists, strings.Join(levels, ".")))
+		return
+	}
+	if !errors.Is(err, ErrNoSuchNamespace) {
+		writeError(w, r, err)
+		return
+	}
+	properties := body.Properties
+	if properties == nil {
+		properties = map[string]string{}
+	}
+	err = h.writeNamespaceProperties(ctx, repository, levels, properties, true)
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		err = fmt.Errorf("%w: namespace %s", ErrAlreadyExists, strings.Join(levels, "."))
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, namespaceResponse{Namespace: levels, Properties: properties})
+}
+
+func (h *Handler) loadNamespace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.authorizeObject(r, permissions.ReadObjectAction, repository, namespacePath(levels)+namespacePropertiesFilename); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(ctx, repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	properties, err := h.readNamespaceProperties(ctx, repository, levels)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, namespaceResponse{Namespace: levels, Properties: properties})
+}
+
+func (h *Handler) namespaceExists(w http.ResponseWriter, r *http.Request) {
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(r.Context(), repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dropNamespace deletes an empty nested namespace
+func (h *Handler) dropNamespace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(levels) == 1 {
+		writeError(w, r, fmt.Errorf("%w: top level namespaces are the repository branches, delete the branch instead", ErrBadRequest))
+		return
+	}
+	propertiesPath := namespacePath(levels) + namespacePropertiesFilename
+	if err := h.authorizeObject(r, permissions.DeleteObjectAction, repository, propertiesPath); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.writableBranch(ctx, repository, levels[0]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(ctx, repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	empty, err := h.isEmpty(ctx, repository, levels[0], namespacePath(levels), propertiesPath)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !empty {
+		writeError(w, r, fmt.Errorf("%w: %s", ErrNamespaceNotEmpty, strings.Join(levels, ".")))
+		return
+	}
+	if err := h.deletePrefix(ctx, repository, levels[0], namespacePath(levels)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) updateNamespaceProperties(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var body updateNamespacePropertiesRequest
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(levels) == 1 {
+		writeError(w, r, fmt.Errorf("%w: properties of top level namespaces", ErrUnsupportedOperation))
+		return
+	}
+	for _, key := range body.Removals {
+		if _, ok := body.Updates[key]; ok {
+			writeError(w, r, fmt.Errorf("%w: property %s is both updated and removed", ErrBadRequest, key))
+			return
+		}
+	}
+	if err := h.authorizeObject(r, permissions.WriteObjectAction, repository, namespacePath(levels)+namespacePropertiesFilename); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.writableBranch(ctx, repository, levels[0]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(ctx, repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	properties, err := h.readNamespaceProperties(ctx, repository, levels)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := updateNamespacePropertiesResponse{Updated: []string{}, Removed: []string{}}
+	for _, key := range body.Removals {
+		if _, ok := properties[key]; !ok {
+			response.Missing = append(response.Missing, key)
+			continue
+		}
+		delete(properties, key)
+		response.Removed = append(response.Removed, key)
+	}
+	for key, value := range body.Updates {
+		properties[key] = value
+		response.Updated = append(response.Updated, key)
+	}
+	if err := h.writeNamespaceProperties(ctx, repository, levels, properties, false); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,155 @@
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+const listEntriesAmount = 1000
+
+// readObject returns the content of path on ref, returns graveler.ErrNotFound if the object does not exist
+func (h *Handler) readObject(ctx context.Context, repository *catalog.Repository, ref, path string) ([]byte, error) {
+	entry, err := h.catalog.GetEntry(ctx, repository.Name, ref, path, catalog.GetEntryParams{})
+	if err != nil {
+		return nil, err
+	}
+	// table metadata is read on behalf of callers authorized for the table path only; an alias could expose any
+	// other path in the repository
+	if entry.AliasTarget != "" {
+		return nil, fmt.Errorf("%s: %w", path, catalog.ErrInvalidAlias)
+	}
+	reader, err := h.catalog.BlockAdapter.Get(ctx, block.ObjectPointer{
+		StorageNamespace: repository.StorageNamespace,
+		IdentifierType:   entry.AddressType.ToIdentifierType(),
+		Identifier:       entry.PhysicalAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(reader)
+}
+
+// objectExists checks if path exists on ref
+func (h *Handler) objectExists(ctx context.Context, repository *catalog.Repository, ref, path string) (bool, error) {
+	_, err := h.catalog.GetEntry(ctx, repository.Name, ref, path, catalog.GetEntryParams{})
+	if errors.Is(err, graveler.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeObject uploads data to path on branch. With ifAbsent, returns graveler.ErrPreconditionFailed if path exists.
+func (h *Handler) writeObject(ctx context.Context, repository *catalog.Repository, branch, path, contentType string, data []byte, ifAbsent bool) error {
+	address := h.pathProvider.NewPath()
+	ctx = block.WithRepository(ctx, repository.Name)
+	blob, err := upload.WriteBlob(ctx, h.catalog.BlockAdapter, repository.StorageNamespace, address, bytes.NewReader(data), int64(len(data)), block.PutOpts{})
+	if err != nil {
+		return err
+	}
+	entryBuilder := catalog.NewDBEntryBuilder().
+		Path(path).
+		PhysicalAddress(blob.PhysicalAddress).
+		CreationDate(time.Now()).
+		Size(blob.Size).
+		Checksum(blob.Checksum).
+		ContentType(contentType)
+	if blob.RelativePath {
+		entryBuilder.AddressType(catalog.AddressTypeRelative)
+	} else {
+		entryBuilder.AddressType(catalog.AddressTypeFull)
+	}
+	encryption, err := h.catalog.RepositoryEncryption(ctx, repository.Name)
+	if err != nil {
+		return err
+	}
+	meta := catalog.Metadata{}
+	encryption.SetMetadata(meta)
+	entryBuilder.Metadata(meta)
+	return h.catalog.CreateEntry(ctx, repository.Name, branch, entryBuilder.Build(), graveler.WithIfAbsent(ifAbsent))
+}
+
+// listEntries lists all entries under prefix on ref. With a delimiter, common prefixes are returned as entries
+// with CommonLevel set.
+func (h *Handler) listEntries(ctx context.Context, repository *catalog.Repository, ref, prefix, delimiter string) ([]*catalog.DBEntry, error) {
+	var (
+		entries []*catalog.DBEntry
+		after   string
+	)
+	for {
+		page, hasMore, err := h.catalog.ListEntries(ctx, repository.Name, ref, prefix, after, delimiter, listEntriesAmount)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if !hasMore || len(page) == 0 {
+			return entries, nil
+		}
+		after = page[len(page)-1].Path
+	}
+}
+
+// isEmpty checks if there are no entries under prefix on ref, other than the given ignored paths
+func (h *Handler) isEmpty(ctx context.Context, repository *catalog.Repository, ref, prefix string, ignore ...string) (bool, error) {
+	page, _, err := h.catalog.ListEntries(ctx, repository.Name, ref, prefix, "", "", len(ignore)+1)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range page {
+		ignored := false
+		for _, p := range ignore {
+			if entry.Path == p {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// deletePrefix deletes all the entries under prefix on branch
+func (h *Handler) deletePrefix(ctx context.Context, repository *catalog.Repository, branch, prefix string) error {
+	entries, err := h.listEntries(ctx, repository, branch, prefix, "")
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	for len(paths) > 0 {
+		n := min(len(paths), listEntriesAmount)
+		if err := h.catalog.DeleteEntries(ctx, repository.Name, branch, paths[:n]); err != nil {
+			return err
+		}
+		paths = paths[n:]
+	}
+	return nil
+}
+
+// writableBranch verifies ref is a branch, only branches accept changes
+func (h *Handler) writableBranch(ctx context.Context, repository *catalog.Repository, ref string) error {
+	exists, err := h.catalog.BranchExists(ctx, repository.Name, ref)
+	if err != nil && !errors.Is(err, graveler.ErrNotFound) {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrReadOnlyRef, ref)
+	}
+	return nil
+}
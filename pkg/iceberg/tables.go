@@ -0,0 +1,436 @@
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+const (
+	metadataDir = "metadata/"
+
+	// versionHintPath holds the current metadata version of a table, relative to the table directory
+	versionHintPath = metadataDir + "version-hint.text"
+
+	contentTypeText = "text/plain"
+)
+
+type tableIdentifier struct {
+	Namespace []string `json:"namespace"`
+	Name      string   `json:"name"`
+}
+
+type listTablesResponse struct {
+	Identifiers []tableIdentifier `json:"identifiers"`
+}
+
+type createTableRequest struct {
+	Name          string            `json:"name"`
+	Location      string            `json:"location,omitempty"`
+	Schema        Schema            `json:"schema"`
+	PartitionSpec *PartitionSpec    `json:"partition-spec,omitempty"`
+	WriteOrder    *SortOrder        `json:"write-order,omitempty"`
+	StageCreate   bool              `json:"stage-create,omitempty"`
+	Properties    map[string]string `json:"properties,omitempty"`
+}
+
+type commitTableRequest struct {
+	Identifier   *tableIdentifier   `json:"identifier,omitempty"`
+	Requirements []TableRequirement `json:"requirements"`
+	Updates      []TableUpdate      `json:"updates"`
+}
+
+type loadTableResponse struct {
+	MetadataLocation string            `json:"metadata-location"`
+	Metadata         *TableMetadata    `json:"metadata"`
+	Config           map[string]string `json:"config,omitempty"`
+}
+
+type commitTableResponse struct {
+	MetadataLocation string         `json:"metadata-location"`
+	Metadata         *TableMetadata `json:"metadata"`
+}
+
+// table is a table resolved from a request path
+type table struct {
+	repository *catalog.Repository
+	namespace  []string
+	name       string
+}
+
+func (t *table) ref() string {
+	return t.namespace[0]
+}
+
+// path returns the table directory on its ref, with a trailing delimiter
+func (t *table) path() string {
+	return namespacePath(t.namespace) + t.name + pathDelimiter
+}
+
+// location returns the table location, as addressed through the lakeFS S3 gateway
+func (t *table) location() string {
+	return "s3://" + t.repository.Name + pathDelimiter + t.ref() + pathDelimiter + strings.TrimSuffix(t.path(), pathDelimiter)
+}
+
+func (t *table) metadataPath(version int) string {
+	return t.path() + metadataDir + "v" + strconv.Itoa(version) + ".metadata.json"
+}
+
+func (t *table) metadataLocation(version int) string {
+	return "s3://" + t.repository.Name + pathDelimiter + t.ref() + pathDelimiter + t.metadataPath(version)
+}
+
+func (t *table) String() string {
+	return strings.Join(append(append([]string{}, t.namespace...), t.name), ".")
+}
+
+// validLocation checks location is on the branch of the table, the only location lakeFS serves table files from
+func (t *table) validLocation(location string) error {
+	branchLocation := "s3://" + t.repository.Name + pathDelimiter + t.ref() + pathDelimiter
+	if !strings.HasPrefix(location, branchLocation) || len(location) == len(branchLocation) {
+		return fmt.Errorf("%w: table location %s must be under %s", ErrBadRequest, location, branchLocation)
+	}
+	return nil
+}
+
+// requestTable returns the table in the request path
+func (h *Handler) requestTable(r *http.Request) (*table, error) {
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		return nil, err
+	}
+	name, err := url.PathUnescape(chi.URLParam(r, "table"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadRequest, err)
+	}
+	if err := validateTableName(name); err != nil {
+		return nil, err
+	}
+	return &table{repository: repository, namespace: levels, name: name}, nil
+}
+
+func validateTableName(name string) error {
+	if name == "" || strings.Contains(name, pathDelimiter) || name == "." || name == ".." {
+		return fmt.Errorf("%w: invalid table name '%s'", ErrBadRequest, name)
+	}
+	return nil
+}
+
+// currentVersion returns the current metadata version of the table. The version hint is updated after every
+// commit, versions written after it by commits that failed to update it are found by probing the following
+// versions.
+func (h *Handler) currentVersion(ctx context.Context, t *table) (int, error) {
+	data, err := h.readObject(ctx, t.repository, t.ref(), t.path()+versionHintPath)
+	if errors.Is(err, graveler.ErrNotFound) {
+		return 0, fmt.Errorf("%w: %s", ErrNoSuchTable, t)
+	}
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("table %s version hint: %w", t, err)
+	}
+	for {
+		exists, err := h.objectExists(ctx, t.repository, t.ref(), t.metadataPath(version+1))
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			return version, nil
+		}
+		version++
+	}
+}
+
+// loadMetadata returns the current metadata of the table and its version
+func (h *Handler) loadMetadata(ctx context.Context, t *table) (*TableMetadata, int, error) {
+	version, err := h.currentVersion(ctx, t)
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := h.readObject(ctx, t.repository, t.ref(), t.metadataPath(version))
+	if errors.Is(err, graveler.ErrNotFound) {
+		return nil, 0, fmt.Errorf("%w: %s metadata version %d", ErrNoSuchTable, t, version)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	var metadata TableMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, 0, fmt.Errorf("table %s metadata version %d: %w", t, version, err)
+	}
+	return &metadata, version, nil
+}
+
+// writeMetadata writes a new metadata version of the table and points the version hint at it. Fails with
+// ErrCommitFailed if the version was written by a concurrent commit.
+func (h *Handler) writeMetadata(ctx context.Context, t *table, metadata *TableMetadata, version int) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	err = h.writeObject(ctx, t.repository, t.ref(), t.metadataPath(version), contentTypeJSON, data, true)
+	if errors.Is(err, graveler.ErrPreconditionFailed) {
+		return fmt.Errorf("%w: table %s metadata version %d was written concurrently", ErrCommitFailed, t, version)
+	}
+	if err != nil {
+		return err
+	}
+	return h.writeObject(ctx, t.repository, t.ref(), t.path()+versionHintPath, contentTypeText, []byte(strconv.Itoa(version)), false)
+}
+
+// tableConfig returns the configuration clients use to access the table files
+func (h *Handler) tableConfig() map[string]string {
+	config := map[string]string{}
+	if h.cfg.S3Endpoint != "" {
+		config["s3.endpoint"] = h.cfg.S3Endpoint
+		config["s3.path-style-access"] = "true"
+	}
+	return config
+}
+
+func (h *Handler) listTables(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.authorize(r, permissions.Node{
+		Permission: permissions.Permission{Action: permissions.ListObjectsAction, Resource: permissions.RepoArn(repository.Name)},
+	}); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(ctx, repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	entries, err := h.listEntries(ctx, repository, levels[0], namespacePath(levels), pathDelimiter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := listTablesResponse{Identifiers: []tableIdentifier{}}
+	for _, entry := range entries {
+		if !entry.CommonLevel {
+			continue
+		}
+		isTable, err := h.objectExists(ctx, repository, levels[0], entry.Path+versionHintPath)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if !isTable {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(entry.Path, namespacePath(levels)), pathDelimiter)
+		response.Identifiers = append(response.Identifiers, tableIdentifier{Namespace: levels, Name: name})
+	}
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+func (h *Handler) createTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	repository, levels, err := h.requestNamespace(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var body createTableRequest
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if body.StageCreate {
+		writeError(w, r, fmt.Errorf("%w: staged table creation", ErrUnsupportedOperation))
+		return
+	}
+	if err := validateTableName(body.Name); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	t := &table{repository: repository, namespace: levels, name: body.Name}
+	if err := h.authorizeObject(r, permissions.WriteObjectAction, repository, t.path()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.writableBranch(ctx, repository, t.ref()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkNamespaceExists(ctx, repository, levels); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	exists, err := h.objectExists(ctx, repository, t.ref(), t.path()+versionHintPath)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if exists {
+		writeError(w, r, fmt.Errorf("%w: table %s", ErrAlreadyExists, t))
+		return
+	}
+	location := t.location()
+	if body.Location != "" {
+		location = strings.TrimSuffix(body.Location, pathDelimiter)
+		if err := t.validLocation(location); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	const firstVersion = 1
+	metadata, err := NewTableMetadata(location, body.Schema, body.PartitionSpec, body.WriteOrder, body.Properties, time.Now())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	err = h.writeMetadata(ctx, t, metadata, firstVersion)
+	if errors.Is(err, ErrCommitFailed) {
+		err = fmt.Errorf("%w: table %s", ErrAlreadyExists, t)
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, loadTableResponse{
+		MetadataLocation: t.metadataLocation(firstVersion),
+		Metadata:         metadata,
+		Config:           h.tableConfig(),
+	})
+}
+
+func (h *Handler) loadTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	t, err := h.requestTable(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.authorizeObject(r, permissions.ReadObjectAction, t.repository, t.path()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	metadata, version, err := h.loadMetadata(ctx, t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, loadTableResponse{
+		MetadataLocation: t.metadataLocation(version),
+		Metadata:         metadata,
+		Config:           h.tableConfig(),
+	})
+}
+
+func (h *Handler) tableExists(w http.ResponseWriter, r *http.Request) {
+	t, err := h.requestTable(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, err := h.currentVersion(r.Context(), t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commitTable applies the updates of the request to the current table metadata, once all requirements hold,
+// and writes the result as the next metadata version
+func (h *Handler) commitTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	t, err := h.requestTable(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	var body commitTableRequest
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.authorizeObject(r, permissions.WriteObjectAction, t.repository, t.path()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.writableBranch(ctx, t.repository, t.ref()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	metadata, version, err := h.loadMetadata(ctx, t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	for _, requirement := range body.Requirements {
+		if err := requirement.Validate(metadata); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	previous := MetadataLogEntry{TimestampMS: metadata.LastUpdatedMS, MetadataFile: t.metadataLocation(version)}
+	if err := metadata.Apply(body.Updates, time.Now()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := t.validLocation(metadata.Location); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	metadata.AddMetadataLog(previous)
+	if err := h.writeMetadata(ctx, t, metadata, version+1); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, commitTableResponse{
+		MetadataLocation: t.metadataLocation(version + 1),
+		Metadata:         metadata,
+	})
+}
+
+// dropTable removes the table metadata, leaving its data files in place unless a purge is requested
+func (h *Handler) dropTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	t, err := h.requestTable(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	purge, _ := strconv.ParseBool(r.URL.Query().Get("purgeRequested"))
+	if err := h.authorizeObject(r, permissions.DeleteObjectAction, t.repository, t.path()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.writableBranch(ctx, t.repository, t.ref()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, err := h.currentVersion(ctx, t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	prefix := t.path() + metadataDir
+	if purge {
+		prefix = t.path()
+	}
+	if err := h.deletePrefix(ctx, t.repository, t.ref(), prefix); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -375,6 +375,50 @@ func (s *Store) Delete(ctx context.Context, partitionKey, key []byte) error {
 	return nil
 }
 
+func (s *Store) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	if len(partitionKey) == 0 {
+		return kv.ErrMissingPartitionKey
+	}
+	if len(key) == 0 {
+		return kv.ErrMissingKey
+	}
+	pred, ok := valuePredicate.([]byte)
+	if !ok {
+		return kv.ErrPredicateFailed
+	}
+	predicateCondition := expression.Name(ItemValue).Equal(expression.Value(pred))
+	conditionExpression, err := expression.NewBuilder().WithCondition(predicateCondition).Build()
+	if err != nil {
+		return fmt.Errorf("build condition expression: %w", err)
+	}
+
+	resp, err := s.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(s.params.TableName),
+		Key:                       s.bytesKeyToDynamoKey(partitionKey, key),
+		ConditionExpression:       conditionExpression.Condition(),
+		ExpressionAttributeNames:  conditionExpression.Names(),
+		ExpressionAttributeValues: conditionExpression.Values(),
+		ReturnConsumedCapacity:    types.ReturnConsumedCapacityTotal,
+	})
+	const operation = "DeleteItem"
+	if err != nil {
+		var errConditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &errConditionalCheckFailed) {
+			return kv.ErrPredicateFailed
+		}
+		if s.isSlowDownErr(err) {
+			s.logger.WithField("partition_key", partitionKey).WithContext(ctx).Error("delete item: %w", kv.ErrSlowDown)
+			dynamoSlowdown.WithLabelValues(operation).Inc()
+			err = errors.Join(err, kv.ErrSlowDown)
+		}
+		return fmt.Errorf("delete item: %w", err)
+	}
+	if resp.ConsumedCapacity != nil {
+		dynamoConsumedCapacity.WithLabelValues(operation).Add(*resp.ConsumedCapacity.CapacityUnits)
+	}
+	return nil
+}
+
 func (s *Store) Scan(ctx context.Context, partitionKey []byte, options kv.ScanOptions) (kv.EntriesIterator, error) {
 	if len(partitionKey) == 0 {
 		return nil, kv.ErrMissingPartitionKey
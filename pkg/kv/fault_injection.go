@@ -0,0 +1,106 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/faultinject"
+)
+
+// StoreFaultInjectionWrapper injects faults into the calls of any Store, for testing. Partial failures of writes
+// apply the write and then fail it, partial failures of scans fail the scan after its first entry.
+type StoreFaultInjectionWrapper struct {
+	Store
+	Injector *faultinject.Injector
+}
+
+func (s *StoreFaultInjectionWrapper) Get(ctx context.Context, partitionKey, key []byte) (*ValueWithPredicate, error) {
+	const operation = "Get"
+	if s.Injector.Inject(ctx, operation) != faultinject.FaultNone {
+		return nil, faultinject.Error(operation)
+	}
+	return s.Store.Get(ctx, partitionKey, key)
+}
+
+// write applies a write of operation unless it should fail
+func (s *StoreFaultInjectionWrapper) write(ctx context.Context, operation string, fn func() error) error {
+	fault := s.Injector.Inject(ctx, operation)
+	if fault == faultinject.FaultError {
+		return faultinject.Error(operation)
+	}
+	err := fn()
+	if err == nil && fault == faultinject.FaultPartial {
+		return faultinject.Error(operation)
+	}
+	return err
+}
+
+func (s *StoreFaultInjectionWrapper) Set(ctx context.Context, partitionKey, key, value []byte) error {
+	return s.write(ctx, "Set", func() error {
+		return s.Store.Set(ctx, partitionKey, key, value)
+	})
+}
+
+func (s *StoreFaultInjectionWrapper) SetIf(ctx context.Context, partitionKey, key, value []byte, valuePredicate Predicate) error {
+	return s.write(ctx, "SetIf", func() error {
+		return s.Store.SetIf(ctx, partitionKey, key, value, valuePredicate)
+	})
+}
+
+func (s *StoreFaultInjectionWrapper) Delete(ctx context.Context, partitionKey, key []byte) error {
+	return s.write(ctx, "Delete", func() error {
+		return s.Store.Delete(ctx, partitionKey, key)
+	})
+}
+
+func (s *StoreFaultInjectionWrapper) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate Predicate) error {
+	return s.write(ctx, "DeleteIf", func() error {
+		return s.Store.DeleteIf(ctx, partitionKey, key, valuePredicate)
+	})
+}
+
+func (s *StoreFaultInjectionWrapper) Scan(ctx context.Context, partitionKey []byte, options ScanOptions) (EntriesIterator, error) {
+	const operation = "Scan"
+	fault := s.Injector.Inject(ctx, operation)
+	if fault == faultinject.FaultError {
+		return nil, faultinject.Error(operation)
+	}
+	it, err := s.Store.Scan(ctx, partitionKey, options)
+	if err != nil || fault != faultinject.FaultPartial {
+		return it, err
+	}
+	return &partialEntriesIterator{EntriesIterator: it, operation: operation}, nil
+}
+
+// partialEntriesIterator fails after its first entry
+type partialEntriesIterator struct {
+	EntriesIterator
+	operation string
+	read      bool
+	err       error
+}
+
+func (it *partialEntriesIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.read {
+		it.err = faultinject.Error(it.operation)
+		return false
+	}
+	it.read = true
+	return it.EntriesIterator.Next()
+}
+
+func (it *partialEntriesIterator) Entry() *Entry {
+	if it.err != nil {
+		return nil
+	}
+	return it.EntriesIterator.Entry()
+}
+
+func (it *partialEntriesIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.EntriesIterator.Err()
+}
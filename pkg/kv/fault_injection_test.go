@@ -0,0 +1,85 @@
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/treeverse/lakefs/pkg/faultinject"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/mock"
+)
+
+func newFaultInjectionStore(t *testing.T, store kv.Store, params faultinject.Params) kv.Store {
+	t.Helper()
+	injector, err := faultinject.NewInjector(params)
+	if err != nil {
+		t.Fatalf("new injector: %s", err)
+	}
+	return &kv.StoreFaultInjectionWrapper{Store: store, Injector: injector}
+}
+
+func TestStoreFaultInjectionWrapper(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		// failed calls never reach the store
+		store := newFaultInjectionStore(t, mock.NewMockStore(ctrl), faultinject.Params{ErrorProbability: 1})
+		if _, err := store.Get(ctx, nil, nil); !errors.Is(err, faultinject.ErrInjected) {
+			t.Errorf("Get: expected injected fault, got %v", err)
+		}
+		if err := store.Set(ctx, nil, nil, nil); !errors.Is(err, faultinject.ErrInjected) {
+			t.Errorf("Set: expected injected fault, got %v", err)
+		}
+		if _, err := store.Scan(ctx, nil, kv.ScanOptions{}); !errors.Is(err, faultinject.ErrInjected) {
+			t.Errorf("Scan: expected injected fault, got %v", err)
+		}
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := mock.NewMockStore(ctrl)
+		rec := mockStore.EXPECT()
+		rec.Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		rec.SetIf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		rec.Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		store := newFaultInjectionStore(t, mockStore, faultinject.Params{PartialFailureProbability: 1})
+
+		// writes are applied, and then fail
+		if err := store.Set(ctx, nil, nil, nil); !errors.Is(err, faultinject.ErrInjected) {
+			t.Errorf("Set: expected injected fault, got %v", err)
+		}
+		if err := store.SetIf(ctx, nil, nil, nil, nil); !errors.Is(err, faultinject.ErrInjected) {
+			t.Errorf("SetIf: expected injected fault, got %v", err)
+		}
+		if err := store.Delete(ctx, nil, nil); !errors.Is(err, faultinject.ErrInjected) {
+			t.Errorf("Delete: expected injected fault, got %v", err)
+		}
+	})
+
+	t.Run("partial_scan", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStore := mock.NewMockStore(ctrl)
+		it := mock.NewMockEntriesIterator(ctrl)
+		it.EXPECT().Next().Times(1).Return(true)
+		it.EXPECT().Entry().Times(1).Return(&kv.Entry{Key: []byte("a")})
+		mockStore.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(it, nil)
+		store := newFaultInjectionStore(t, mockStore, faultinject.Params{PartialFailureProbability: 1})
+
+		scan, err := store.Scan(ctx, nil, kv.ScanOptions{})
+		if err != nil {
+			t.Fatalf("Scan: %s", err)
+		}
+		if !scan.Next() || string(scan.Entry().Key) != "a" {
+			t.Fatal("expected the first entry")
+		}
+		if scan.Next() {
+			t.Fatal("expected the scan to fail after the first entry")
+		}
+		if err := scan.Err(); !errors.Is(err, faultinject.ErrInjected) {
+			t.Fatalf("expected injected fault, got %v", err)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/faultinject"
 )
 
 type Config struct {
@@ -15,6 +16,8 @@ type Config struct {
 	DynamoDB *DynamoDB
 	Local    *Local
 	CosmosDB *CosmosDB
+	// FaultInjection injects faults into the calls of the store, for testing
+	FaultInjection *faultinject.Params
 }
 
 type Local struct {
@@ -132,5 +135,9 @@ func NewConfig(cfg *config.Config) (Config, error) {
 		}
 	}
 
+	if faultInjection := cfg.FaultInjection.Database.Params(); faultInjection.Enabled() {
+		p.FaultInjection = &faultInjection
+	}
+
 	return p, nil
 }
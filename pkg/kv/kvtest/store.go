@@ -56,6 +56,7 @@ func DriverTest(t *testing.T, ms MakeStore) {
 	t.Run("Store_SetGet", func(t *testing.T) { testStoreSetGet(t, ms) })
 	t.Run("Store_SetIf", func(t *testing.T) { testStoreSetIf(t, ms) })
 	t.Run("Store_Delete", func(t *testing.T) { testStoreDelete(t, ms) })
+	t.Run("Store_DeleteIf", func(t *testing.T) { testStoreDeleteIf(t, ms) })
 	t.Run("Store_Scan", func(t *testing.T) { testStoreScan(t, ms) })
 	t.Run("Store_MissingArgument", func(t *testing.T) { testStoreMissingArgument(t, ms) })
 	t.Run("Store_ContextCancelled", func(t *testing.T) { testStoreContextCancelled(t, ms) })
@@ -230,6 +231,75 @@ func testStoreDelete(t *testing.T, ms MakeStore) {
 	})
 }
 
+func testStoreDeleteIf(t *testing.T, ms MakeStore) {
+	ctx := context.Background()
+	store := ms(t, ctx)
+	defer store.Close()
+
+	t.Run("delete_matching", func(t *testing.T) {
+		key := uniqueKey("delete-if-matching")
+		err := store.Set(ctx, []byte(testPartitionKey), key, []byte("v1"))
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s: %s", key, err)
+		}
+		res, err := store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get while testing DeleteIf - key=%s: %s", key, err)
+		}
+		err = store.DeleteIf(ctx, []byte(testPartitionKey), key, res.Predicate)
+		if err != nil {
+			t.Fatalf("DeleteIf with current predicate - key=%s: %s", key, err)
+		}
+		_, err = store.Get(ctx, []byte(testPartitionKey), key)
+		require.ErrorIs(t, err, kv.ErrNotFound)
+	})
+
+	t.Run("fail_changed_value", func(t *testing.T) {
+		key := uniqueKey("delete-if-changed")
+		err := store.Set(ctx, []byte(testPartitionKey), key, []byte("v1"))
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s: %s", key, err)
+		}
+		res, err := store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get while testing DeleteIf - key=%s: %s", key, err)
+		}
+		err = store.Set(ctx, []byte(testPartitionKey), key, []byte("v2"))
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s: %s", key, err)
+		}
+		err = store.DeleteIf(ctx, []byte(testPartitionKey), key, res.Predicate)
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			t.Fatalf("DeleteIf err=%v - key=%s, expected err=%s", err, key, kv.ErrPredicateFailed)
+		}
+		res, err = store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get after failed DeleteIf - key=%s: %s", key, err)
+		}
+		require.Equal(t, []byte("v2"), res.Value)
+	})
+
+	t.Run("fail_missing", func(t *testing.T) {
+		key := uniqueKey("delete-if-missing")
+		err := store.Set(ctx, []byte(testPartitionKey), key, []byte("v1"))
+		if err != nil {
+			t.Fatalf("Set while testing DeleteIf - key=%s: %s", key, err)
+		}
+		res, err := store.Get(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Get while testing DeleteIf - key=%s: %s", key, err)
+		}
+		err = store.Delete(ctx, []byte(testPartitionKey), key)
+		if err != nil {
+			t.Fatalf("Delete while testing DeleteIf - key=%s: %s", key, err)
+		}
+		err = store.DeleteIf(ctx, []byte(testPartitionKey), key, res.Predicate)
+		if !errors.Is(err, kv.ErrPredicateFailed) {
+			t.Fatalf("DeleteIf err=%v - key=%s, expected err=%s", err, key, kv.ErrPredicateFailed)
+		}
+	})
+}
+
 func testStoreSetIf(t *testing.T, ms MakeStore) {
 	ctx := context.Background()
 	store := ms(t, ctx)
@@ -158,6 +158,26 @@ func (s *Store) Delete(_ context.Context, partitionKey, key []byte) error {
 	return nil
 }
 
+func (s *Store) DeleteIf(_ context.Context, partitionKey, key []byte, valuePredicate kv.Predicate) error {
+	if len(partitionKey) == 0 {
+		return kv.ErrMissingPartitionKey
+	}
+	if len(key) == 0 {
+		return kv.ErrMissingKey
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sKey := encodeKey(key)
+	curr, currOK := s.m[string(partitionKey)][sKey]
+	pred, predOK := valuePredicate.([]byte)
+	if !currOK || !predOK || !bytes.Equal(pred, curr.Value) {
+		return fmt.Errorf("%w: partition=%s, key=%v, encoding=%s", kv.ErrPredicateFailed, partitionKey, key, sKey)
+	}
+	delete(s.m[string(partitionKey)], sKey)
+	return nil
+}
+
 func (s *Store) Scan(_ context.Context, partitionKey []byte, options kv.ScanOptions) (kv.EntriesIterator, error) {
 	if len(partitionKey) == 0 {
 		return nil, kv.ErrMissingPartitionKey
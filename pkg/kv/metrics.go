@@ -77,6 +77,18 @@ func (s *StoreMetricsWrapper) Delete(ctx context.Context, partitionKey, key []by
 	return err
 }
 
+func (s *StoreMetricsWrapper) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate Predicate) error {
+	const operation = "DeleteIf"
+	timer := prometheus.NewTimer(requestDuration.WithLabelValues(s.StoreType, operation))
+	ctx = httputil.SetClientTrace(ctx, s.StoreType)
+	defer timer.ObserveDuration()
+	err := s.Store.DeleteIf(ctx, partitionKey, key, valuePredicate)
+	if err != nil {
+		requestFailures.WithLabelValues(s.StoreType, operation).Inc()
+	}
+	return err
+}
+
 func (s *StoreMetricsWrapper) Scan(ctx context.Context, partitionKey []byte, options ScanOptions) (EntriesIterator, error) {
 	const operation = "Scan"
 	timer := prometheus.NewTimer(requestDuration.WithLabelValues(s.StoreType, operation))
@@ -63,6 +63,8 @@ func ValidateSchemaVersion(ctx context.Context, store Store) (int, error) {
 		return kvVersion, fmt.Errorf("migration to ACL required. Please run 'lakefs migrate up': %w", ErrMigrationRequired)
 	case kvVersion < ACLImportMigrateVersion:
 		return kvVersion, fmt.Errorf("ACL migration required. Please run 'lakefs migrate up': %w", ErrMigrationRequired)
+	case kvVersion < ActionsConfigMigrateVersion:
+		return kvVersion, fmt.Errorf("actions configuration permissions migration required. Please run 'lakefs migrate up': %w", ErrMigrationRequired)
 	}
 
 	logging.FromContext(ctx).WithField("version", kvVersion).Info("KV valid")
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/pkg/auth/model"
+	"github.com/treeverse/lakefs/pkg/auth/wildcard"
+	"github.com/treeverse/lakefs/pkg/config"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/permissions"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MigrateActionsConfigPermissions grants the actions configuration permissions to existing policies, which were
+// allowed to access the actions configuration as any other object before it required permissions of its own.
+// Statements allowing reading objects are allowed to read the actions configuration, and statements allowing writing
+// objects through a wildcard, such as "fs:*", are allowed to modify it. Statements naming fs:WriteObject explicitly
+// are left unchanged, so that writers do not get to alter the hooks validating their writes.
+func MigrateActionsConfigPermissions(ctx context.Context, kvStore kv.Store, cfg *config.Config) error {
+	// skip migrate for users with External authorizations
+	if !cfg.IsAuthUISimplified() {
+		fmt.Println("skipping actions configuration permissions migration - external Authorization")
+		return updateKVSchemaVersion(ctx, kvStore, kv.ActionsConfigMigrateVersion)
+	}
+
+	it, err := kv.NewPrimaryIterator(ctx, kvStore, (&model.PolicyData{}).ProtoReflect().Type(), model.PartitionKey, model.PolicyPath(""), kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		update := false
+		entry := it.Entry()
+		policy := entry.Value.(*model.PolicyData)
+		for _, statement := range policy.Statements {
+			if statement.Effect != model.StatementEffectAllow {
+				continue
+			}
+			if matchesAnyAction(statement.Action, permissions.ReadObjectAction, false) &&
+				!matchesAnyAction(statement.Action, permissions.ReadActionsConfigAction, false) {
+				statement.Action = append(statement.Action, permissions.ReadActionsConfigAction)
+				update = true
+			}
+			if matchesAnyAction(statement.Action, permissions.WriteObjectAction, true) &&
+				!matchesAnyAction(statement.Action, permissions.WriteActionsConfigAction, false) {
+				statement.Action = append(statement.Action, permissions.WriteActionsConfigAction)
+				update = true
+			}
+		}
+
+		if update {
+			policy.CreatedAt = timestamppb.Now()
+			if err = kv.SetMsg(ctx, kvStore, model.PartitionKey, entry.Key, policy); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	return updateKVSchemaVersion(ctx, kvStore, kv.ActionsConfigMigrateVersion)
+}
+
+// matchesAnyAction returns true if one of patterns matches action, considering only wildcard patterns if wildcardOnly
+func matchesAnyAction(patterns []string, action string, wildcardOnly bool) bool {
+	for _, pattern := range patterns {
+		if wildcardOnly && !strings.ContainsAny(pattern, "*?") {
+			continue
+		}
+		if wildcard.Match(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
@@ -377,3 +377,99 @@ func verifyMigration(t *testing.T, ctx context.Context, authService *auth.AuthSe
 		}
 	}
 }
+
+func TestMigrateActionsConfigPermissions(t *testing.T) {
+	ctx := context.Background()
+	readStatement := model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{"fs:List*", "fs:Read*"},
+		Resource: permissions.All,
+	}
+	writeStatement := model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{"fs:Read*", permissions.WriteObjectAction, permissions.DeleteObjectAction},
+		Resource: permissions.All,
+	}
+	fullStatement := model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{"fs:*"},
+		Resource: permissions.All,
+	}
+	denyStatement := model.Statement{
+		Effect:   model.StatementEffectDeny,
+		Action:   []string{"fs:*"},
+		Resource: permissions.RepoArn("secrets"),
+	}
+	ciStatement := model.Statement{
+		Effect:   model.StatementEffectAllow,
+		Action:   []string{"fs:*", "ci:*"},
+		Resource: permissions.All,
+	}
+
+	tests := []struct {
+		name       string
+		statement  model.Statement
+		expected   []string
+		uiAuthType string
+	}{
+		{
+			name:       "read",
+			statement:  readStatement,
+			expected:   []string{"fs:List*", "fs:Read*", permissions.ReadActionsConfigAction},
+			uiAuthType: config.AuthRBACSimplified,
+		},
+		{
+			name:       "write",
+			statement:  writeStatement,
+			expected:   []string{"fs:Read*", permissions.WriteObjectAction, permissions.DeleteObjectAction, permissions.ReadActionsConfigAction},
+			uiAuthType: config.AuthRBACSimplified,
+		},
+		{
+			name:       "full",
+			statement:  fullStatement,
+			expected:   []string{"fs:*", permissions.ReadActionsConfigAction, permissions.WriteActionsConfigAction},
+			uiAuthType: config.AuthRBACSimplified,
+		},
+		{
+			name:       "deny",
+			statement:  denyStatement,
+			expected:   []string{"fs:*"},
+			uiAuthType: config.AuthRBACSimplified,
+		},
+		{
+			name:       "already_allowed",
+			statement:  ciStatement,
+			expected:   []string{"fs:*", "ci:*"},
+			uiAuthType: config.AuthRBACSimplified,
+		},
+		{
+			name:       "external_auth_type",
+			statement:  fullStatement,
+			expected:   []string{"fs:*"},
+			uiAuthType: config.AuthRBACExternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService, store := authtestutil.SetupService(t, ctx, []byte("some secret"))
+			statement := tt.statement
+			statement.Action = slices.Clone(tt.statement.Action)
+			policy := &model.Policy{
+				CreatedAt:   time.Now().Add(-time.Hour).UTC(),
+				DisplayName: "policy",
+				Statement:   []model.Statement{statement},
+			}
+			testutil.MustDo(t, "create Policy", authService.WritePolicy(ctx, policy, false))
+
+			cfg := config.Config{}
+			cfg.Auth.UIConfig.RBAC = tt.uiAuthType
+			testutil.MustDo(t, "migrate", migrations.MigrateActionsConfigPermissions(ctx, store, &cfg))
+
+			migrated, err := authService.GetPolicy(ctx, policy.DisplayName)
+			testutil.MustDo(t, "get policy", err)
+			require.Len(t, migrated.Statement, 1)
+			require.Equal(t, tt.expected, migrated.Statement[0].Action)
+		})
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/treeverse/lakefs/pkg/faultinject"
 	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	"github.com/treeverse/lakefs/pkg/logging"
 )
 
 // KV Schema versions
@@ -19,6 +21,7 @@ const (
 	ACLMigrateVersion
 	ACLNoReposMigrateVersion
 	ACLImportMigrateVersion
+	ActionsConfigMigrateVersion
 	NextSchemaVersion
 )
 
@@ -64,6 +67,7 @@ type Driver interface {
 //
 //	Store's Get used to pull the key's value with the associated predicate.
 //	Store's SetIf used to set the key's value based on the predicate.
+//	Store's DeleteIf used to delete the key based on the predicate.
 type Predicate interface{}
 
 // ValueWithPredicate value with predicate - Value holds the data and Predicate a value used for conditional set.
@@ -100,6 +104,10 @@ type Store interface {
 	// Delete will delete the key, no error in if key doesn't exist
 	Delete(ctx context.Context, partitionKey, key []byte) error
 
+	// DeleteIf deletes the key only if valuePredicate, the predicate returned by Get, matches the currently stored
+	//  value. Returns an ErrPredicateFailed error if it doesn't match or the key doesn't exist.
+	DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate Predicate) error
+
 	// Scan returns entries of partitionKey, by key order.
 	// 'options' holds optional parameters to control the batch size and the key to start the scan with.
 	Scan(ctx context.Context, partitionKey []byte, options ScanOptions) (EntriesIterator, error)
@@ -187,6 +195,16 @@ func Open(ctx context.Context, params kvparams.Config) (Store, error) {
 	if err != nil {
 		return nil, err
 	}
+	if params.FaultInjection != nil && params.FaultInjection.Enabled() {
+		injector, err := faultinject.NewInjector(*params.FaultInjection)
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+		logging.FromContext(ctx).WithField("type", params.Type).Warn("KV store fault injection enabled, not for production use")
+		// metrics wrap injected faults, so they count like any other failure
+		store = &StoreFaultInjectionWrapper{Store: store, Injector: injector}
+	}
 	return storeMetrics(store, params.Type), nil
 }
 
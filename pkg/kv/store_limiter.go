@@ -38,6 +38,11 @@ func (s *StoreLimiter) Delete(ctx context.Context, partitionKey, key []byte) err
 	return s.Store.Delete(ctx, partitionKey, key)
 }
 
+func (s *StoreLimiter) DeleteIf(ctx context.Context, partitionKey, key []byte, valuePredicate Predicate) error {
+	_ = s.Limiter.Take()
+	return s.Store.DeleteIf(ctx, partitionKey, key, valuePredicate)
+}
+
 func (s *StoreLimiter) Scan(ctx context.Context, partitionKey []byte, options ScanOptions) (EntriesIterator, error) {
 	_ = s.Limiter.Take()
 	return s.Store.Scan(ctx, partitionKey, options)
@@ -39,6 +39,10 @@ func (m *MockStore) Delete(_ context.Context, _, _ []byte) error {
 	return errNotImplemented
 }
 
+func (m *MockStore) DeleteIf(_ context.Context, _, _ []byte, _ kv.Predicate) error {
+	return errNotImplemented
+}
+
 func (m *MockStore) Scan(_ context.Context, _ []byte, _ kv.ScanOptions) (kv.EntriesIterator, error) {
 	return nil, errNotImplemented
 }
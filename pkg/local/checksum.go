@@ -0,0 +1,91 @@
+package local
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+const checksumBufferSize = 64 * 1024
+
+// lfWriter writes to w the data written to it, with CRLF line endings converted to LF
+type lfWriter struct {
+	w io.Writer
+	// pendingCR is set when the last byte written is a CR, which is dropped if the next byte is LF
+	pendingCR bool
+}
+
+func (l *lfWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if l.pendingCR {
+		l.pendingCR = false
+		if p[0] != '\n' {
+			if _, err := l.w.Write([]byte{'\r'}); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if p[len(p)-1] == '\r' {
+		l.pendingCR = true
+		p = p[:len(p)-1]
+	}
+	if _, err := l.w.Write(bytes.ReplaceAll(p, []byte("\r\n"), []byte("\n"))); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Close writes a CR left at the end of the data
+func (l *lfWriter) Close() error {
+	if !l.pendingCR {
+		return nil
+	}
+	l.pendingCR = false
+	_, err := l.w.Write([]byte{'\r'})
+	return err
+}
+
+// ContentChecksums returns the MD5 checksums of r, as is and with CRLF line endings converted to LF
+func ContentChecksums(r io.Reader) (string, string, error) {
+	var plain, normalized hash.Hash = md5.New(), md5.New() //nolint:gosec
+	lf := &lfWriter{w: normalized}
+	if _, err := io.CopyBuffer(io.MultiWriter(plain, lf), r, make([]byte, checksumBufferSize)); err != nil {
+		return "", "", err
+	}
+	if err := lf.Close(); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(plain.Sum(nil)), hex.EncodeToString(normalized.Sum(nil)), nil
+}
+
+// sameContent returns true if the content of the file at p matches the remote checksum, as is or with CRLF line
+// endings converted to LF. Checksums of objects uploaded in parts are not the MD5 of their content and never match.
+func sameContent(p, checksum string) (bool, error) {
+	checksum = strings.Trim(checksum, `"`)
+	if checksum == "" {
+		return false, nil
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	plain, normalized, err := ContentChecksums(f)
+	if err != nil {
+		return false, err
+	}
+	return plain == checksum || normalized == checksum, nil
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/pkg/api/apigen"
-	"github.com/treeverse/lakefs/pkg/gateway/path"
 	"github.com/treeverse/lakefs/pkg/uri"
 )
 
@@ -187,13 +186,20 @@ func Undo(c Changes) Changes {
 	return reversed
 }
 
+// walkDir is a directory encountered by WalkS3, kept until all paths that precede it are processed
+type walkDir struct {
+	path string
+	info os.FileInfo
+}
+
 // WalkS3 - walk like an Egyptian... ¯\_(ツ)¯\_
 // This walker function simulates the way object listing is performed by S3. Contrary to how a standard FS walk function behaves, S3
 // does not take into consideration the directory hierarchy. Instead, object paths include the entire path relative to the root and as a result
 // the directory or "path separator" is also taken into account when providing the listing in a lexicographical order.
+// Paths are ordered by their "/" separated form, so the order matches the object listing also where the OS separator is "\".
 func WalkS3(root string, callbackFunc func(p string, info fs.FileInfo, err error) error) error {
 	var stringHeap StringHeap
-	var dirsInfo = make(map[string]os.FileInfo)
+	var dirsInfo = make(map[string]walkDir)
 
 	fpWalkErr := filepath.Walk(root, func(p string, info fs.FileInfo, walkErr error) error {
 		if walkErr != nil {
@@ -205,29 +211,20 @@ func WalkS3(root string, callbackFunc func(p string, info fs.FileInfo, err error
 
 		if info.IsDir() {
 			// Save encountered directories in a min heap and compare them with the first appearance of a file in that level
-			dir := p + path.Separator
-			dirsInfo[dir] = info        // save dir info for processing it later
-			heap.Push(&stringHeap, dir) // add path separator to dir name and sort it later
+			dir := p + string(filepath.Separator)
+			key := filepath.ToSlash(dir)
+			dirsInfo[key] = walkDir{path: dir, info: info} // save dir info for processing it later
+			heap.Push(&stringHeap, key)                    // add path separator to dir name and sort it later
 			return filepath.SkipDir
 		}
 
 		for stringHeap.Len() > 0 {
-			dir := stringHeap.Peek().(string)
-			if p < dir { // file should be processed before dir
+			key := stringHeap.Peek().(string)
+			if filepath.ToSlash(p) < key { // file should be processed before dir
 				break
 			}
 			heap.Pop(&stringHeap) // remove from queue
-
-			fileInfo := dirsInfo[dir]
-			if fileInfo == nil {
-				return fmt.Errorf("fileInfo not found in dirsInfo [%s]: %w", dir, ErrNotFound)
-			}
-
-			if err := callbackFunc(dir, fileInfo, nil); err != nil {
-				return err
-			}
-
-			if err := WalkS3(dir, callbackFunc); err != nil {
+			if err := walkS3Dir(dirsInfo, key, callbackFunc); err != nil {
 				return err
 			}
 		}
@@ -245,28 +242,32 @@ func WalkS3(root string, callbackFunc func(p string, info fs.FileInfo, err error
 
 	// Finally, finished walking over FS, handle remaining dirs
 	for stringHeap.Len() > 0 {
-		dir := heap.Pop(&stringHeap).(string)
-
-		fileInfo := dirsInfo[dir]
-		if fileInfo == nil {
-			return fmt.Errorf("fileInfo not found in dirsInfo [%s]: %w", dir, ErrNotFound)
-		}
-
-		if err := callbackFunc(dir, fileInfo, nil); err != nil {
-			return err
-		}
-
-		if err := WalkS3(dir, callbackFunc); err != nil {
+		key := heap.Pop(&stringHeap).(string)
+		if err := walkS3Dir(dirsInfo, key, callbackFunc); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// walkS3Dir calls callbackFunc on the directory saved under key and walks it
+func walkS3Dir(dirsInfo map[string]walkDir, key string, callbackFunc func(p string, info fs.FileInfo, err error) error) error {
+	dir, ok := dirsInfo[key]
+	if !ok {
+		return fmt.Errorf("fileInfo not found in dirsInfo [%s]: %w", key, ErrNotFound)
+	}
+	if err := callbackFunc(dir.path, dir.info, nil); err != nil {
+		return err
+	}
+	return WalkS3(dir.path, callbackFunc)
+}
+
 // DiffLocalWithHead Checks changes between a local directory and the head it is pointing to. The diff check assumes the remote
 // is an immutable set so any changes found resulted from changes in the local directory
-// left is an object channel which contains results from a remote source. rightPath is the local directory to diff with
-func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string, includeDirs, includePOSIXPermissions bool) (Changes, error) {
+// left is an object channel which contains results from a remote source. rightPath is the local directory to diff with.
+// With ignoreLineEndings, a file that differs in size or mtime is unchanged when its content matches the remote checksum
+// once its CRLF line endings are converted to LF, e.g. a file checked out with CRLF line endings on Windows.
+func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string, includeDirs, includePOSIXPermissions, ignoreLineEndings bool) (Changes, error) {
 	// left should be the base commit
 	changes := make([]*Change, 0)
 
@@ -311,6 +312,13 @@ func DiffLocalWithHead(left <-chan apigen.ObjectStats, rightPath string, include
 				sizeChanged := !info.IsDir() && localBytes != swag.Int64Value(currentRemoteFile.SizeBytes)
 				mtimeChanged := localMtime != remoteMtime
 				permissionsChanged := includePOSIXPermissions && isPermissionsChanged(info, currentRemoteFile)
+				if (sizeChanged || mtimeChanged) && !permissionsChanged && ignoreLineEndings && !info.IsDir() {
+					same, err := sameContent(p, currentRemoteFile.Checksum)
+					if err != nil {
+						return err
+					}
+					sizeChanged, mtimeChanged = !same, !same
+				}
 				if sizeChanged || mtimeChanged || permissionsChanged {
 					// we made a change!
 					changes = append(changes, &Change{ChangeSourceLocal, localPath, ChangeTypeModified})
@@ -3,8 +3,10 @@ package local
 import "errors"
 
 var (
-	ErrConflict        = errors.New("conflict")
-	ErrDownloadingFile = errors.New("error downloading file")
-	ErrRemoteFailure   = errors.New("remote failure")
-	ErrNotFound        = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrDownloadingFile  = errors.New("error downloading file")
+	ErrRemoteFailure    = errors.New("remote failure")
+	ErrNotFound         = errors.New("not found")
+	ErrInvalidLocalPath = errors.New("path cannot be written to the local filesystem")
+	ErrCaseConflict     = errors.New("paths differ only by case on a case-insensitive filesystem")
 )
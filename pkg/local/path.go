@@ -0,0 +1,87 @@
+package local
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+// windowsInvalidChars are characters Windows does not allow in file names, besides control characters
+const windowsInvalidChars = `<>:"|?*\`
+
+// windowsReservedNames are device names Windows does not allow as file names, with or without an extension
+var windowsReservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// ValidateWindowsPath checks that the "/" separated relative path p can be written to a Windows filesystem. A "\" in
+// an object path is rejected rather than written as a directory separator, which would sync the object back under a
+// different path.
+func ValidateWindowsPath(p string) error {
+	for _, name := range strings.Split(strings.TrimSuffix(p, uri.PathSeparator), uri.PathSeparator) {
+		if i := strings.IndexFunc(name, func(r rune) bool {
+			return r < ' ' || strings.ContainsRune(windowsInvalidChars, r)
+		}); i >= 0 {
+			return fmt.Errorf("%s: character %q: %w", p, name[i], ErrInvalidLocalPath)
+		}
+		if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+			if name != "." && name != ".." {
+				return fmt.Errorf("%s: name ends with a dot or space: %w", p, ErrInvalidLocalPath)
+			}
+		}
+		base, _, _ := strings.Cut(name, ".")
+		if _, ok := windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))]; ok {
+			return fmt.Errorf("%s: reserved name %s: %w", p, name, ErrInvalidLocalPath)
+		}
+	}
+	return nil
+}
+
+// LocalPath returns the path on the local filesystem of the "/" separated path p, relative to rootPath
+func LocalPath(rootPath, p string) (string, error) {
+	if runtime.GOOS == "windows" {
+		if err := ValidateWindowsPath(p); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(rootPath, filepath.FromSlash(p)), nil
+}
+
+// caseFolder detects paths that differ only by case, which a case-insensitive filesystem stores as the same file
+type caseFolder struct {
+	mu    sync.Mutex
+	paths map[string]string
+}
+
+func newCaseFolder() *caseFolder {
+	return &caseFolder{paths: make(map[string]string)}
+}
+
+// add records the "/" separated path p and its parent directories, failing when one of them differs only by case
+// from a path added before
+func (f *caseFolder) add(p string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 0; i < len(p); i++ {
+		if p[i] != '/' && i != len(p)-1 {
+			continue
+		}
+		prefix := p[:i+1]
+		folded := strings.ToLower(prefix)
+		prev, ok := f.paths[folded]
+		if !ok {
+			f.paths[folded] = prefix
+			continue
+		}
+		if prev != prefix {
+			return fmt.Errorf("%s and %s: %w", prev, prefix, ErrCaseConflict)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package local_test
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/local"
+)
+
+func TestValidateWindowsPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+	}{
+		{path: "data/2024/part-0.parquet", valid: true},
+		{path: "dir/", valid: true},
+		{path: "console/log.txt", valid: true},
+		{path: "data\\file.csv"},
+		{path: "data/a:b"},
+		{path: "data/what?"},
+		{path: "data/tab\there"},
+		{path: "data/trailing."},
+		{path: "data/trailing "},
+		{path: "CON"},
+		{path: "logs/nul.txt"},
+		{path: "Com1/file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := local.ValidateWindowsPath(tt.path)
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, local.ErrInvalidLocalPath)
+			}
+		})
+	}
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s)) //nolint:gosec
+	return hex.EncodeToString(sum[:])
+}
+
+func TestContentChecksums(t *testing.T) {
+	content := strings.Repeat("line one\r\nline two\r\n", 10000) + "bare\rcr\r"
+	plain, normalized, err := local.ContentChecksums(&shortReader{r: strings.NewReader(content)})
+	require.NoError(t, err)
+	require.Equal(t, md5Hex(content), plain)
+	require.Equal(t, md5Hex(strings.ReplaceAll(content, "\r\n", "\n")), normalized)
+}
+
+// shortReader reads a byte less than asked, to split CRLF pairs between reads
+type shortReader struct {
+	r *strings.Reader
+}
+
+func (s *shortReader) Read(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:len(p)-1]
+	}
+	return s.r.Read(p)
+}
+
+func TestDiffLocalWithHead_IgnoreLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	lfContent := "a,b\n1,2\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "crlf.csv"), []byte(strings.ReplaceAll(lfContent, "\n", "\r\n")), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "changed.csv"), []byte("a,b\r\n3,4\r\n"), 0o600))
+	remote := []apigen.ObjectStats{
+		{Path: "changed.csv", SizeBytes: swag.Int64(int64(len(lfContent))), Checksum: md5Hex(lfContent)},
+		{Path: "crlf.csv", SizeBytes: swag.Int64(int64(len(lfContent))), Checksum: md5Hex(lfContent)},
+	}
+
+	for _, ignore := range []bool{false, true} {
+		lc := make(chan apigen.ObjectStats, len(remote))
+		makeChan(lc, remote)
+		changes, err := local.DiffLocalWithHead(lc, dir, false, false, ignore)
+		require.NoError(t, err)
+		var paths []string
+		for _, c := range changes {
+			require.Equal(t, local.ChangeTypeModified, c.Type)
+			paths = append(paths, c.Path)
+		}
+		if ignore {
+			require.Equal(t, []string{"changed.csv"}, paths)
+		} else {
+			require.Equal(t, []string{"changed.csv", "crlf.csv"}, paths)
+		}
+	}
+}
@@ -35,6 +35,8 @@ type SyncFlags struct {
 	Parallelism      int
 	Presign          bool
 	PresignMultipart bool
+	// CaseInsensitive fails downloads of paths that differ only by case, which would overwrite each other
+	CaseInsensitive bool
 }
 
 func getMtimeFromStats(stats apigen.ObjectStats) (int64, error) {
@@ -64,6 +66,7 @@ type SyncManager struct {
 	tasks       Tasks
 	// includePerm - Experimental: preserve Unix file permissions
 	includePerm bool
+	downloaded  *caseFolder
 }
 
 func NewSyncManager(ctx context.Context, client *apigen.ClientWithResponses, httpClient *http.Client, flags SyncFlags, includePerm bool) *SyncManager {
@@ -74,6 +77,7 @@ func NewSyncManager(ctx context.Context, client *apigen.ClientWithResponses, htt
 		progressBar: NewProgressPool(),
 		flags:       flags,
 		includePerm: includePerm,
+		downloaded:  newCaseFolder(),
 	}
 }
 
@@ -213,7 +217,19 @@ func (s *SyncManager) download(ctx context.Context, rootPath string, remote *uri
 	if err := fileutil.VerifyRelPath(strings.TrimPrefix(path, uri.PathSeparator), rootPath); err != nil {
 		return err
 	}
-	destination := filepath.ToSlash(fmt.Sprintf("%s%s%s", rootPath, uri.PathSeparator, path))
+	destination, err := LocalPath(rootPath, path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(path, uri.PathSeparator) {
+		// keep directory markers as directories, to create them with their parent directory
+		destination += string(filepath.Separator)
+	}
+	if s.flags.CaseInsensitive {
+		if err := s.downloaded.add(path); err != nil {
+			return err
+		}
+	}
 	destinationDirectory := filepath.Dir(destination)
 
 	if err := os.MkdirAll(destinationDirectory, os.FileMode(DefaultDirectoryPermissions)); err != nil {
@@ -273,7 +289,10 @@ func (s *SyncManager) download(ctx context.Context, rootPath string, remote *uri
 }
 
 func (s *SyncManager) upload(ctx context.Context, rootPath string, remote *uri.URI, path string) error {
-	source := filepath.Join(rootPath, path)
+	source, err := LocalPath(rootPath, path)
+	if err != nil {
+		return err
+	}
 	if err := fileutil.VerifySafeFilename(source); err != nil {
 		return err
 	}
@@ -350,7 +369,10 @@ func (s *SyncManager) deleteLocal(rootPath string, change *Change) (err error) {
 			}
 		}()
 	}()
-	source := filepath.Join(rootPath, change.Path)
+	source, err := LocalPath(rootPath, change.Path)
+	if err != nil {
+		return err
+	}
 	err = fileutil.RemoveFile(source)
 	if err != nil {
 		return err
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidLevel = errors.New("invalid log level")
+
+// levelSetting is a log level, or no logs at all
+type levelSetting struct {
+	level logrus.Level
+	none  bool
+}
+
+func (l levelSetting) String() string {
+	if l.none {
+		return "none"
+	}
+	return l.level.String()
+}
+
+func (l levelSetting) enabled(level logrus.Level) bool {
+	return !l.none && level <= l.level
+}
+
+func parseLevel(level string) (levelSetting, error) {
+	switch strings.ToLower(level) {
+	case "null", "none":
+		return levelSetting{none: true}, nil
+	}
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return levelSetting{}, fmt.Errorf("%w: %s", ErrInvalidLevel, level)
+	}
+	return levelSetting{level: l}, nil
+}
+
+var (
+	levelsMu     sync.RWMutex
+	baseLevel    = levelSetting{level: logrus.InfoLevel}
+	moduleLevels = map[string]levelSetting{}
+)
+
+// applyLevels sets the level of the logger to the most verbose of the levels, for entries of modules with more
+// verbose levels to reach the formatter. Must be called with levelsMu held.
+func applyLevels() {
+	logrusLevel := logrus.PanicLevel
+	for _, l := range append([]levelSetting{baseLevel}, moduleLevelsList()...) {
+		if !l.none && l.level > logrusLevel {
+			logrusLevel = l.level
+		}
+	}
+	defaultLogger.SetLevel(logrusLevel)
+}
+
+func moduleLevelsList() []levelSetting {
+	levels := make([]levelSetting, 0, len(moduleLevels))
+	for _, l := range moduleLevels {
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+// SetLevels sets the log level, and the levels of modules overriding it. Modules are the packages of lakeFS, e.g.
+// gateway, graveler or auth, and include their subpackages.
+func SetLevels(level string, modules map[string]string) error {
+	base, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels := make(map[string]levelSetting, len(modules))
+	for module, moduleLevel := range modules {
+		l, err := parseLevel(moduleLevel)
+		if err != nil {
+			return fmt.Errorf("module %s: %w", module, err)
+		}
+		levels[strings.ToLower(module)] = l
+	}
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	baseLevel = base
+	moduleLevels = levels
+	applyLevels()
+	return nil
+}
+
+// ModuleLevels returns the levels of modules overriding the log level
+func ModuleLevels() map[string]string {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	levels := make(map[string]string, len(moduleLevels))
+	for module, l := range moduleLevels {
+		levels[module] = l.String()
+	}
+	return levels
+}
+
+// callerModule returns the lakeFS package a log entry was logged from, "" if logged from elsewhere
+func callerModule(caller *runtime.Frame) string {
+	if caller == nil {
+		return ""
+	}
+	module, ok := strings.CutPrefix(caller.Function, ModuleName+"/pkg/")
+	if !ok {
+		return ""
+	}
+	if i := strings.IndexAny(module, "/."); i >= 0 {
+		module = module[:i]
+	}
+	return module
+}
+
+// entryEnabled reports whether to log e, by the level of its module and by sampling
+func entryEnabled(e *logrus.Entry) bool {
+	levelsMu.RLock()
+	l, ok := moduleLevels[callerModule(e.Caller)]
+	if !ok {
+		l = baseLevel
+	}
+	levelsMu.RUnlock()
+	return l.enabled(e.Level) && logSampler.sample(e)
+}
@@ -49,6 +49,8 @@ const (
 	PartNumberFieldKey = "part_number"
 	// RequestIDFieldKey request ID (string) based on the request ID found on context
 	RequestIDFieldKey = "request_id"
+	// TraceIDFieldKey W3C trace ID (string) of the traceparent header of the request
+	TraceIDFieldKey = "trace_id"
 	// HostFieldKey request's host (string)
 	HostFieldKey = "host"
 	// MethodFieldKey request's method (string)
@@ -67,8 +69,11 @@ var (
 	openLoggers       []io.Closer
 )
 
+// Level returns the log level of logs of modules without a module level
 func Level() string {
-	return defaultLogger.GetLevel().String()
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	return baseLevel.String()
 }
 
 type Fields map[string]interface{}
@@ -86,24 +91,16 @@ func logCallerTrimmer(frame *runtime.Frame) (function string, file string) {
 	return
 }
 
+// SetLevel sets the log level of logs of modules without a module level, ignoring unknown levels
 func SetLevel(level string) {
-	switch strings.ToLower(level) {
-	case "trace":
-		defaultLogger.SetLevel(logrus.TraceLevel)
-	case "debug":
-		defaultLogger.SetLevel(logrus.DebugLevel)
-	case "info":
-		defaultLogger.SetLevel(logrus.InfoLevel)
-	case "warn", "warning":
-		defaultLogger.SetLevel(logrus.WarnLevel)
-	case "error":
-		defaultLogger.SetLevel(logrus.ErrorLevel)
-	case "panic":
-		defaultLogger.SetLevel(logrus.PanicLevel)
-	case "null", "none":
-		defaultLogger.SetLevel(logrus.PanicLevel)
-		defaultLogger.SetOutput(io.Discard)
+	l, err := ParseLevel(level)
+	if err != nil {
+		return
 	}
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	baseLevel = l
+	applyLevels()
 }
 
 func CloseWriters() error {
@@ -374,8 +371,12 @@ type logrusCallerFormatter struct {
 	f logrus.Formatter
 }
 
+// Format formats entries enabled by the level of the module logging them and by log sampling, and drops others
 func (lf logrusCallerFormatter) Format(e *logrus.Entry) ([]byte, error) {
 	e.Caller = getCaller()
+	if !entryEnabled(e) {
+		return nil, nil
+	}
 	return lf.f.Format(e)
 }
 
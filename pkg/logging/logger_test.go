@@ -2,12 +2,16 @@ package logging
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
 func TestSetOutputs(t *testing.T) {
@@ -158,3 +162,58 @@ func TestDurationFormatting(t *testing.T) {
 		})
 	}
 }
+
+func TestModuleLevels(t *testing.T) {
+	defer func() { _ = SetLevels("info", nil) }()
+	if err := SetLevels("info", map[string]string{"Graveler": "debug", "auth": "none"}); err != nil {
+		t.Fatalf("SetLevels: %s", err)
+	}
+	if err := SetLevels("info", map[string]string{"gateway": "verbose"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Fatalf("SetLevels with invalid level: %v, expected %s", err, ErrInvalidLevel)
+	}
+	cases := []struct {
+		Function string
+		Level    logrus.Level
+		Enabled  bool
+	}{
+		{"github.com/treeverse/lakefs/pkg/graveler/ref.(*Manager).GetBranch", logrus.DebugLevel, true},
+		{"github.com/treeverse/lakefs/pkg/graveler.(*Graveler).Commit", logrus.TraceLevel, false},
+		{"github.com/treeverse/lakefs/pkg/auth.(*AuthService).GetUser", logrus.ErrorLevel, false},
+		{"github.com/treeverse/lakefs/pkg/catalog.(*Catalog).GetEntry", logrus.DebugLevel, false},
+		{"github.com/treeverse/lakefs/pkg/catalog.(*Catalog).GetEntry", logrus.InfoLevel, true},
+		{"main.main", logrus.InfoLevel, true},
+	}
+	for _, c := range cases {
+		e := &logrus.Entry{Level: c.Level, Time: time.Now(), Caller: &runtime.Frame{Function: c.Function}}
+		if enabled := entryEnabled(e); enabled != c.Enabled {
+			t.Errorf("%s at %s enabled %t, expected %t", c.Function, c.Level, enabled, c.Enabled)
+		}
+	}
+	if levels := ModuleLevels(); levels["graveler"] != "debug" || levels["auth"] != "none" || len(levels) != 2 {
+		t.Errorf("module levels %v", levels)
+	}
+	if defaultLogger.GetLevel() != logrus.DebugLevel {
+		t.Errorf("logger level %s, expected the most verbose module level", defaultLogger.GetLevel())
+	}
+}
+
+func TestSampling(t *testing.T) {
+	defer SetSampling(SamplingConfig{})
+	SetSampling(SamplingConfig{Enabled: true, Initial: 2, Thereafter: 3, Interval: time.Hour})
+	now := time.Now()
+	var logged []int
+	for i := 1; i <= 8; i++ {
+		if logSampler.sample(&logrus.Entry{Level: logrus.DebugLevel, Time: now, Message: "frequent"}) {
+			logged = append(logged, i)
+		}
+	}
+	if fmt.Sprint(logged) != "[1 2 5 8]" {
+		t.Errorf("sampled entries %v, expected [1 2 5 8]", logged)
+	}
+	if !logSampler.sample(&logrus.Entry{Level: logrus.InfoLevel, Time: now, Message: "frequent"}) {
+		t.Error("info entry sampled out")
+	}
+	if !logSampler.sample(&logrus.Entry{Level: logrus.DebugLevel, Time: now.Add(time.Hour), Message: "frequent"}) {
+		t.Error("first entry of a new interval sampled out")
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const DefaultSamplingInterval = time.Second
+
+// SamplingConfig configures sampling of debug and trace logs. Of the entries with the same message logged during
+// each interval, the first Initial entries are logged, and after them every Thereafter-th entry.
+type SamplingConfig struct {
+	Enabled    bool
+	Initial    int
+	Thereafter int
+	Interval   time.Duration
+}
+
+type sampler struct {
+	mu          sync.Mutex
+	cfg         SamplingConfig
+	windowStart time.Time
+	counts      map[string]int
+}
+
+var logSampler = &sampler{counts: map[string]int{}}
+
+// SetSampling configures sampling of debug and trace logs
+func SetSampling(cfg SamplingConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultSamplingInterval
+	}
+	if cfg.Initial < 0 {
+		cfg.Initial = 0
+	}
+	logSampler.mu.Lock()
+	defer logSampler.mu.Unlock()
+	logSampler.cfg = cfg
+	logSampler.windowStart = time.Time{}
+	clear(logSampler.counts)
+}
+
+// Sampling returns the sampling configuration of debug and trace logs
+func Sampling() SamplingConfig {
+	logSampler.mu.Lock()
+	defer logSampler.mu.Unlock()
+	return logSampler.cfg
+}
+
+// sample reports whether to log e
+func (s *sampler) sample(e *logrus.Entry) bool {
+	if e.Level < logrus.DebugLevel {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.cfg.Enabled {
+		return true
+	}
+	if e.Time.Sub(s.windowStart) >= s.cfg.Interval {
+		s.windowStart = e.Time
+		clear(s.counts)
+	}
+	s.counts[e.Message]++
+	n := s.counts[e.Message]
+	if n <= s.cfg.Initial {
+		return true
+	}
+	return s.cfg.Thereafter > 0 && (n-s.cfg.Initial)%s.cfg.Thereafter == 0
+}
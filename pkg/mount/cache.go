@@ -0,0 +1,110 @@
+package mount
+
+import (
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+type cachedStat struct {
+	stats   *apigen.ObjectStats // nil caches a missing object
+	expires time.Time
+}
+
+type cachedDir struct {
+	entries []apigen.ObjectStats
+	expires time.Time
+}
+
+// metadataCache keeps object stats and directory listings for ttl, saving a round trip to lakeFS on every
+// lookup and getattr the kernel sends
+type metadataCache struct {
+	ttl   time.Duration
+	now   func() time.Time
+	mu    sync.Mutex
+	stats map[string]cachedStat
+	dirs  map[string]cachedDir
+}
+
+func newMetadataCache(ttl time.Duration) *metadataCache {
+	return &metadataCache{
+		ttl:   ttl,
+		now:   time.Now,
+		stats: make(map[string]cachedStat),
+		dirs:  make(map[string]cachedDir),
+	}
+}
+
+// getStat returns the cached stats of path. found reports whether path is cached, stats is nil when path
+// was cached as missing.
+func (c *metadataCache) getStat(path string) (stats *apigen.ObjectStats, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stats[path]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(s.expires) {
+		delete(c.stats, path)
+		return nil, false
+	}
+	return s.stats, true
+}
+
+func (c *metadataCache) putStat(path string, stats *apigen.ObjectStats) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats[path] = cachedStat{stats: stats, expires: c.now().Add(c.ttl)}
+}
+
+func (c *metadataCache) getDir(dir string) ([]apigen.ObjectStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.dirs[dir]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(d.expires) {
+		delete(c.dirs, dir)
+		return nil, false
+	}
+	return d.entries, true
+}
+
+// putDir caches the listing of dir, along with the stats of the objects it holds
+func (c *metadataCache) putDir(dir string, entries []apigen.ObjectStats) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	c.dirs[dir] = cachedDir{entries: entries, expires: expires}
+	for i := range entries {
+		if entries[i].PathType == pathTypeObject {
+			c.stats[entries[i].Path] = cachedStat{stats: &entries[i], expires: expires}
+		}
+	}
+}
+
+// invalidate drops path and the listing of its directory, after path was changed through the mount
+func (c *metadataCache) invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.stats, path)
+	delete(c.dirs, parentDir(path))
+}
+
+// parentDir returns the directory prefix holding path, with a trailing delimiter. The root is the empty string.
+func parentDir(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[:i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package mount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+func TestMetadataCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newMetadataCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.putDir("data/", []apigen.ObjectStats{
+		{Path: "data/a.csv", PathType: pathTypeObject},
+		{Path: "data/sub/", PathType: "common_prefix"},
+	})
+	cache.putStat("data/missing.csv", nil)
+
+	if entries, found := cache.getDir("data/"); !found || len(entries) != 2 {
+		t.Fatalf("getDir() = %v, %t, expected 2 cached entries", entries, found)
+	}
+	if stats, found := cache.getStat("data/a.csv"); !found || stats == nil || stats.Path != "data/a.csv" {
+		t.Fatalf("getStat() = %v, %t, expected stats cached by listing", stats, found)
+	}
+	if _, found := cache.getStat("data/sub/"); found {
+		t.Fatal("common prefix should not be cached as an object")
+	}
+	if stats, found := cache.getStat("data/missing.csv"); !found || stats != nil {
+		t.Fatalf("getStat() = %v, %t, expected cached missing object", stats, found)
+	}
+
+	cache.invalidate("data/a.csv")
+	if _, found := cache.getStat("data/a.csv"); found {
+		t.Fatal("invalidated object is still cached")
+	}
+	if _, found := cache.getDir("data/"); found {
+		t.Fatal("directory of invalidated object is still cached")
+	}
+
+	cache.putStat("data/b.csv", &apigen.ObjectStats{Path: "data/b.csv"})
+	now = now.Add(2 * time.Minute)
+	if _, found := cache.getStat("data/b.csv"); found {
+		t.Fatal("expired object is still cached")
+	}
+}
+
+func TestMetadataCacheDisabled(t *testing.T) {
+	cache := newMetadataCache(0)
+	cache.putStat("a", &apigen.ObjectStats{Path: "a"})
+	cache.putDir("", []apigen.ObjectStats{{Path: "a", PathType: pathTypeObject}})
+	if _, found := cache.getStat("a"); found {
+		t.Fatal("cache without ttl should not cache stats")
+	}
+	if _, found := cache.getDir(""); found {
+		t.Fatal("cache without ttl should not cache listings")
+	}
+}
+
+func TestParentDir(t *testing.T) {
+	tests := map[string]string{
+		"a":        "",
+		"a/b":      "a/",
+		"a/b/c.sv": "a/b/",
+		"a/b/":     "a/b/",
+	}
+	for path, expected := range tests {
+		if got := parentDir(path); got != expected {
+			t.Errorf("parentDir(%s) = %s, expected %s", path, got, expected)
+		}
+	}
+}
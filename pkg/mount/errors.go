@@ -0,0 +1,9 @@
+package mount
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrNotSupported = errors.New("mount is not supported on this platform")
+	ErrReadOnly     = errors.New("read-only mount")
+)
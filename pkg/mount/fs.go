@@ -0,0 +1,471 @@
+//go:build linux || darwin
+
+package mount
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	dirMode       = 0o755
+	fileMode      = 0o644
+	readOnlyMode  = 0o444
+	blockSize     = 512
+	fsName        = "lakefs"
+	mountTempName = "lakefs-mount-"
+)
+
+// filesystem holds the state shared by all the nodes of a mount
+type filesystem struct {
+	store *objectStore
+	opts  Options
+
+	// dirs created through the mount, which exist only until an object is written under them
+	mu        sync.Mutex
+	localDirs map[string]struct{}
+}
+
+// node is a file or a directory of the mount. path is the object path of files, and the prefix, with a trailing
+// delimiter, of directories.
+type node struct {
+	fs.Inode
+	fsys *filesystem
+	path string
+	dir  bool
+}
+
+var (
+	_ = (fs.NodeLookuper)((*node)(nil))
+	_ = (fs.NodeReaddirer)((*node)(nil))
+	_ = (fs.NodeGetattrer)((*node)(nil))
+	_ = (fs.NodeSetattrer)((*node)(nil))
+	_ = (fs.NodeOpener)((*node)(nil))
+	_ = (fs.NodeCreater)((*node)(nil))
+	_ = (fs.NodeUnlinker)((*node)(nil))
+	_ = (fs.NodeMkdirer)((*node)(nil))
+	_ = (fs.NodeRmdirer)((*node)(nil))
+)
+
+// Mount exposes the ref of opts at mountpoint until the returned server is unmounted
+func Mount(mountpoint string, client apigen.ClientWithResponsesInterface, opts Options) (Server, error) {
+	fsys := &filesystem{
+		store:     newObjectStore(client, opts),
+		opts:      opts,
+		localDirs: make(map[string]struct{}),
+	}
+	root := &node{fsys: fsys, path: opts.Prefix, dir: true}
+	timeout := opts.CacheTTL
+	mountOptions := fuse.MountOptions{
+		FsName:     "lakefs://" + opts.Repository + "/" + opts.Ref + "/" + opts.Prefix,
+		Name:       fsName,
+		AllowOther: opts.AllowOther,
+		Debug:      opts.Debug,
+	}
+	if !opts.Writable {
+		mountOptions.Options = append(mountOptions.Options, "ro")
+	}
+	server, err := fs.Mount(mountpoint, root, &fs.Options{
+		MountOptions:    mountOptions,
+		EntryTimeout:    &timeout,
+		AttrTimeout:     &timeout,
+		NegativeTimeout: &timeout,
+		UID:             uint32(os.Getuid()),
+		GID:             uint32(os.Getgid()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+func (f *filesystem) hasLocalDir(dir string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.localDirs[dir]
+	return ok
+}
+
+func (f *filesystem) setLocalDir(dir string, exists bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if exists {
+		f.localDirs[dir] = struct{}{}
+	} else {
+		delete(f.localDirs, dir)
+	}
+}
+
+func (f *filesystem) fileMode() uint32 {
+	if f.opts.Writable {
+		return fileMode
+	}
+	return readOnlyMode
+}
+
+func (f *filesystem) fillFileAttr(out *fuse.Attr, stats *apigen.ObjectStats) {
+	out.Mode = fuse.S_IFREG | f.fileMode()
+	if stats.SizeBytes != nil {
+		out.Size = uint64(*stats.SizeBytes)
+	}
+	out.Blocks = (out.Size + blockSize - 1) / blockSize
+	mtime := uint64(stats.Mtime)
+	out.Mtime, out.Ctime, out.Atime = mtime, mtime, mtime
+}
+
+func fillDirAttr(out *fuse.Attr) {
+	out.Mode = fuse.S_IFDIR | dirMode
+}
+
+func (n *node) newChild(ctx context.Context, name string, dir bool) *fs.Inode {
+	child := &node{fsys: n.fsys, path: n.path + name, dir: dir}
+	mode := uint32(fuse.S_IFREG)
+	if dir {
+		child.path += "/"
+		mode = fuse.S_IFDIR
+	}
+	return n.NewInode(ctx, child, fs.StableAttr{Mode: mode})
+}
+
+func (n *node) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if !n.dir {
+		return nil, syscall.ENOTDIR
+	}
+	stats, err := n.fsys.store.stat(ctx, n.path+name)
+	if err == nil {
+		n.fsys.fillFileAttr(&out.Attr, stats)
+		return n.newChild(ctx, name, false), fs.OK
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, toErrno(ctx, err)
+	}
+	dir := n.path + name + "/"
+	isDir := n.fsys.hasLocalDir(dir)
+	if !isDir {
+		isDir, err = n.fsys.store.isDir(ctx, dir)
+		if err != nil {
+			return nil, toErrno(ctx, err)
+		}
+	}
+	if !isDir {
+		return nil, syscall.ENOENT
+	}
+	fillDirAttr(&out.Attr)
+	return n.newChild(ctx, name, true), fs.OK
+}
+
+func (n *node) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
+	if !n.dir {
+		return nil, syscall.ENOTDIR
+	}
+	objects, err := n.fsys.store.list(ctx, n.path)
+	if err != nil {
+		return nil, toErrno(ctx, err)
+	}
+	seen := make(map[string]struct{}, len(objects))
+	entries := make([]fuse.DirEntry, 0, len(objects))
+	for _, object := range objects {
+		name := strings.TrimPrefix(object.Path, n.path)
+		mode := uint32(fuse.S_IFREG)
+		if object.PathType != pathTypeObject {
+			mode = fuse.S_IFDIR
+		} else if strings.HasSuffix(name, "/") {
+			// directory marker objects are listed as the directory itself
+			continue
+		}
+		name = strings.TrimSuffix(name, "/")
+		if _, ok := seen[name]; name == "" || ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		entries = append(entries, fuse.DirEntry{Name: name, Mode: mode})
+	}
+	n.fsys.mu.Lock()
+	for dir := range n.fsys.localDirs {
+		if parentDir(strings.TrimSuffix(dir, "/")) != n.path {
+			continue
+		}
+		name := baseName(dir)
+		if _, ok := seen[name]; !ok {
+			entries = append(entries, fuse.DirEntry{Name: name, Mode: fuse.S_IFDIR})
+		}
+	}
+	n.fsys.mu.Unlock()
+	return fs.NewListDirStream(entries), fs.OK
+}
+
+func (n *node) Getattr(ctx context.Context, fh fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	if n.dir {
+		fillDirAttr(&out.Attr)
+		return fs.OK
+	}
+	if h, ok := fh.(*writeHandle); ok {
+		return h.getattr(out)
+	}
+	stats, err := n.fsys.store.stat(ctx, n.path)
+	if err != nil {
+		return toErrno(ctx, err)
+	}
+	n.fsys.fillFileAttr(&out.Attr, stats)
+	return fs.OK
+}
+
+// Setattr supports truncating files, which editors and shell redirects do when they overwrite a file
+func (n *node) Setattr(ctx context.Context, fh fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	size, hasSize := in.GetSize()
+	if !hasSize {
+		return n.Getattr(ctx, fh, out)
+	}
+	if !n.fsys.opts.Writable {
+		return syscall.EROFS
+	}
+	if n.dir {
+		return syscall.EISDIR
+	}
+	if h, ok := fh.(*writeHandle); ok {
+		if errno := h.truncate(int64(size)); errno != fs.OK {
+			return errno
+		}
+		return h.getattr(out)
+	}
+	if size != 0 {
+		return syscall.ENOTSUP
+	}
+	stats, err := n.fsys.store.upload(ctx, n.path, strings.NewReader(""))
+	if err != nil {
+		return toErrno(ctx, err)
+	}
+	n.fsys.fillFileAttr(&out.Attr, stats)
+	return fs.OK
+}
+
+func (n *node) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if n.dir {
+		return nil, 0, syscall.EISDIR
+	}
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) == 0 {
+		stats, err := n.fsys.store.stat(ctx, n.path)
+		if err != nil {
+			return nil, 0, toErrno(ctx, err)
+		}
+		var size int64
+		if stats.SizeBytes != nil {
+			size = *stats.SizeBytes
+		}
+		fetch := func(ctx context.Context, offset, size int64) ([]byte, error) {
+			return n.fsys.store.readRange(ctx, n.path, offset, size)
+		}
+		return &readHandle{reader: newReadAheadReader(fetch, size, n.fsys.opts.ReadAhead)}, 0, fs.OK
+	}
+	if !n.fsys.opts.Writable {
+		return nil, 0, syscall.EROFS
+	}
+	h, err := newWriteHandle(n)
+	if err != nil {
+		return nil, 0, toErrno(ctx, err)
+	}
+	if flags&syscall.O_TRUNC != 0 {
+		h.dirty = true
+	} else if err := n.fsys.store.download(ctx, n.path, h.file); err != nil && !errors.Is(err, ErrNotFound) {
+		h.close()
+		return nil, 0, toErrno(ctx, err)
+	}
+	return h, fuse.FOPEN_DIRECT_IO, fs.OK
+}
+
+func (n *node) Create(ctx context.Context, name string, flags uint32, mode uint32, out *fuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if !n.fsys.opts.Writable {
+		return nil, nil, 0, syscall.EROFS
+	}
+	inode := n.newChild(ctx, name, false)
+	child := inode.Operations().(*node)
+	h, err := newWriteHandle(child)
+	if err != nil {
+		return nil, nil, 0, toErrno(ctx, err)
+	}
+	// the object is staged once the new file is flushed, even when nothing was written
+	h.dirty = true
+	out.Attr.Mode = fuse.S_IFREG | n.fsys.fileMode()
+	return inode, h, fuse.FOPEN_DIRECT_IO, fs.OK
+}
+
+func (n *node) Unlink(ctx context.Context, name string) syscall.Errno {
+	if !n.fsys.opts.Writable {
+		return syscall.EROFS
+	}
+	if err := n.fsys.store.delete(ctx, n.path+name); err != nil {
+		return toErrno(ctx, err)
+	}
+	return fs.OK
+}
+
+// Mkdir creates a directory that exists in the mount only, lakeFS has no directories. It becomes a prefix
+// once a file is written under it.
+func (n *node) Mkdir(ctx context.Context, name string, mode uint32, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if !n.fsys.opts.Writable {
+		return nil, syscall.EROFS
+	}
+	n.fsys.setLocalDir(n.path+name+"/", true)
+	fillDirAttr(&out.Attr)
+	return n.newChild(ctx, name, true), fs.OK
+}
+
+func (n *node) Rmdir(ctx context.Context, name string) syscall.Errno {
+	if !n.fsys.opts.Writable {
+		return syscall.EROFS
+	}
+	dir := n.path + name + "/"
+	notEmpty, err := n.fsys.store.isDir(ctx, dir)
+	if err != nil {
+		return toErrno(ctx, err)
+	}
+	if notEmpty {
+		return syscall.ENOTEMPTY
+	}
+	n.fsys.setLocalDir(dir, false)
+	return fs.OK
+}
+
+// readHandle reads a file through its read-ahead window
+type readHandle struct {
+	reader *readAheadReader
+}
+
+var _ = (fs.FileReader)((*readHandle)(nil))
+
+func (h *readHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	n, err := h.reader.readAt(ctx, dest, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, toErrno(ctx, err)
+	}
+	return fuse.ReadResultData(dest[:n]), fs.OK
+}
+
+// writeHandle buffers the content of a file opened for writing into a temporary file, and stages it on the
+// branch when the file is flushed
+type writeHandle struct {
+	node  *node
+	mu    sync.Mutex
+	file  *os.File
+	dirty bool
+}
+
+var (
+	_ = (fs.FileReader)((*writeHandle)(nil))
+	_ = (fs.FileWriter)((*writeHandle)(nil))
+	_ = (fs.FileFlusher)((*writeHandle)(nil))
+	_ = (fs.FileReleaser)((*writeHandle)(nil))
+)
+
+func newWriteHandle(n *node) (*writeHandle, error) {
+	f, err := os.CreateTemp("", mountTempName)
+	if err != nil {
+		return nil, err
+	}
+	return &writeHandle{node: n, file: f}, nil
+}
+
+func (h *writeHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n, err := h.file.ReadAt(dest, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, toErrno(ctx, err)
+	}
+	return fuse.ReadResultData(dest[:n]), fs.OK
+}
+
+func (h *writeHandle) Write(ctx context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n, err := h.file.WriteAt(data, off)
+	h.dirty = true
+	if err != nil {
+		return uint32(n), toErrno(ctx, err)
+	}
+	return uint32(n), fs.OK
+}
+
+// Flush stages the file content, it is called on every close of the file descriptor
+func (h *writeHandle) Flush(ctx context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.dirty {
+		return fs.OK
+	}
+	if _, err := h.file.Seek(0, io.SeekStart); err != nil {
+		return toErrno(ctx, err)
+	}
+	if _, err := h.node.fsys.store.upload(ctx, h.node.path, h.file); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("path", h.node.path).Error("Failed to stage file")
+		return toErrno(ctx, err)
+	}
+	h.dirty = false
+	return fs.OK
+}
+
+func (h *writeHandle) Release(_ context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.close()
+	return fs.OK
+}
+
+func (h *writeHandle) close() {
+	_ = h.file.Close()
+	_ = os.Remove(h.file.Name())
+}
+
+func (h *writeHandle) truncate(size int64) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.file.Truncate(size); err != nil {
+		return fs.ToErrno(err)
+	}
+	h.dirty = true
+	return fs.OK
+}
+
+func (h *writeHandle) getattr(out *fuse.AttrOut) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	info, err := h.file.Stat()
+	if err != nil {
+		return fs.ToErrno(err)
+	}
+	out.Mode = fuse.S_IFREG | h.node.fsys.fileMode()
+	out.Size = uint64(info.Size())
+	out.Blocks = (out.Size + blockSize - 1) / blockSize
+	mtime := uint64(time.Now().Unix())
+	out.Mtime, out.Ctime, out.Atime = mtime, mtime, mtime
+	return fs.OK
+}
+
+// toErrno maps errors accessing lakeFS to the errno returned to the kernel
+func toErrno(ctx context.Context, err error) syscall.Errno {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return syscall.ENOENT
+	case errors.Is(err, ErrReadOnly):
+		return syscall.EROFS
+	case errors.Is(err, context.Canceled):
+		return syscall.EINTR
+	}
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return errno
+	}
+	logging.FromContext(ctx).WithError(err).Debug("mount operation failed")
+	return syscall.EIO
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package mount
+
+import "github.com/treeverse/lakefs/pkg/api/apigen"
+
+// Mount is supported on Linux and macOS only
+func Mount(_ string, _ apigen.ClientWithResponsesInterface, _ Options) (Server, error) {
+	return nil, ErrNotSupported
+}
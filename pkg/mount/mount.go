@@ -0,0 +1,44 @@
+package mount
+
+import (
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+const (
+	DefaultCacheTTL  = 5 * time.Second
+	DefaultReadAhead = 8 * 1024 * 1024
+)
+
+// Options configures a mount of a lakeFS ref
+type Options struct {
+	Repository string
+	Ref        string
+	// Prefix is the path under the ref exposed as the root of the mount, empty or ending with a delimiter
+	Prefix string
+	// Writable stages files written through the mount on the branch. Changes are not committed.
+	Writable bool
+	// CacheTTL is how long object metadata and directory listings are cached, by the mount and by the kernel
+	CacheTTL time.Duration
+	// ReadAhead is the size of the range fetched on each read from lakeFS
+	ReadAhead  int64
+	AllowOther bool
+	Debug      bool
+}
+
+// Server is a mounted filesystem
+type Server interface {
+	// Wait blocks until the filesystem is unmounted
+	Wait()
+	Unmount() error
+}
+
+func newObjectStore(client apigen.ClientWithResponsesInterface, opts Options) *objectStore {
+	return &objectStore{
+		client:     client,
+		repository: opts.Repository,
+		ref:        opts.Ref,
+		cache:      newMetadataCache(opts.CacheTTL),
+	}
+}
@@ -0,0 +1,59 @@
+package mount
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// rangeFetcher reads up to size bytes of the object starting at offset
+type rangeFetcher func(ctx context.Context, offset, size int64) ([]byte, error)
+
+// readAheadReader serves reads of an object from a window fetched ahead of the reads. Sequential readers,
+// which the kernel splits into small requests, fetch the object in a few large range requests.
+type readAheadReader struct {
+	fetch     rangeFetcher
+	size      int64
+	readAhead int64
+
+	mu     sync.Mutex
+	offset int64
+	buf    []byte
+}
+
+func newReadAheadReader(fetch rangeFetcher, size, readAhead int64) *readAheadReader {
+	return &readAheadReader{fetch: fetch, size: size, readAhead: readAhead}
+}
+
+// readAt reads len(p) bytes at off, like io.ReaderAt. Reads outside the current window fetch a new window
+// starting at off.
+func (r *readAheadReader) readAt(ctx context.Context, p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	want := int64(len(p))
+	if off+want > r.size {
+		want = r.size - off
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if off < r.offset || off+want > r.offset+int64(len(r.buf)) {
+		fetchSize := want
+		if fetchSize < r.readAhead {
+			fetchSize = r.readAhead
+		}
+		if off+fetchSize > r.size {
+			fetchSize = r.size - off
+		}
+		data, err := r.fetch(ctx, off, fetchSize)
+		if err != nil {
+			return 0, err
+		}
+		r.offset, r.buf = off, data
+	}
+	n := copy(p, r.buf[off-r.offset:])
+	if int64(n) < int64(len(p)) {
+		return n, io.EOF
+	}
+	return n, nil
+}
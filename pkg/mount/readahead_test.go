@@ -0,0 +1,67 @@
+package mount
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestReadAheadReader(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	var fetches []int64
+	fetch := func(_ context.Context, offset, size int64) ([]byte, error) {
+		fetches = append(fetches, offset)
+		return content[offset : offset+size], nil
+	}
+	const readAhead = 16
+	r := newReadAheadReader(fetch, int64(len(content)), readAhead)
+
+	// sequential reads are served from the fetched window
+	var out bytes.Buffer
+	buf := make([]byte, 4)
+	var off int64
+	for {
+		n, err := r.readAt(ctx, buf, off)
+		out.Write(buf[:n])
+		off += int64(n)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("readAt(%d) unexpected error: %s", off, err)
+		}
+	}
+	if !bytes.Equal(out.Bytes(), content) {
+		t.Fatalf("read %q, expected %q", out.Bytes(), content)
+	}
+	if len(fetches) != 3 {
+		t.Fatalf("fetched %d times at %v, expected 3 fetches", len(fetches), fetches)
+	}
+
+	// reading back before the window fetches a new window
+	n, err := r.readAt(ctx, buf, 2)
+	if err != nil || n != len(buf) || string(buf) != "2345" {
+		t.Fatalf("readAt(2) = %d, %v, %q", n, err, buf)
+	}
+	if fetches[len(fetches)-1] != 2 {
+		t.Fatalf("last fetch at %d, expected 2", fetches[len(fetches)-1])
+	}
+
+	// reading past the end
+	if n, err := r.readAt(ctx, buf, int64(len(content))); n != 0 || !errors.Is(err, io.EOF) {
+		t.Fatalf("readAt(end) = %d, %v, expected EOF", n, err)
+	}
+}
+
+func TestReadAheadReaderFetchError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	r := newReadAheadReader(func(context.Context, int64, int64) ([]byte, error) {
+		return nil, errFetch
+	}, 10, 4)
+	if _, err := r.readAt(context.Background(), make([]byte, 2), 0); !errors.Is(err, errFetch) {
+		t.Fatalf("readAt() error = %v, expected %v", err, errFetch)
+	}
+}
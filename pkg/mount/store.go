@@ -0,0 +1,181 @@
+package mount
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+const (
+	pathTypeObject = "object"
+
+	listAmount = 1000
+)
+
+// objectStore accesses the objects of the mounted ref through the lakeFS API, caching their metadata
+type objectStore struct {
+	client     apigen.ClientWithResponsesInterface
+	repository string
+	ref        string
+	cache      *metadataCache
+}
+
+// stat returns the stats of the object at path, ErrNotFound if it does not exist
+func (s *objectStore) stat(ctx context.Context, path string) (*apigen.ObjectStats, error) {
+	if stats, found := s.cache.getStat(path); found {
+		if stats == nil {
+			return nil, ErrNotFound
+		}
+		return stats, nil
+	}
+	resp, err := s.client.StatObjectWithResponse(ctx, s.repository, s.ref, &apigen.StatObjectParams{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		s.cache.putStat(path, nil)
+		return nil, ErrNotFound
+	}
+	if resp.JSON200 == nil {
+		return nil, helpers.ResponseAsError(resp)
+	}
+	s.cache.putStat(path, resp.JSON200)
+	return resp.JSON200, nil
+}
+
+// list returns the objects and common prefixes directly under the dir prefix
+func (s *objectStore) list(ctx context.Context, dir string) ([]apigen.ObjectStats, error) {
+	if entries, found := s.cache.getDir(dir); found {
+		return entries, nil
+	}
+	var (
+		entries   []apigen.ObjectStats
+		after     string
+		prefix    = apigen.PaginationPrefix(dir)
+		delimiter = apigen.PaginationDelimiter("/")
+		amount    = apigen.PaginationAmount(listAmount)
+	)
+	for {
+		resp, err := s.client.ListObjectsWithResponse(ctx, s.repository, s.ref, &apigen.ListObjectsParams{
+			Prefix:    &prefix,
+			Delimiter: &delimiter,
+			Amount:    &amount,
+			After:     (*apigen.PaginationAfter)(swag.String(after)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, helpers.ResponseAsError(resp)
+		}
+		entries = append(entries, resp.JSON200.Results...)
+		if !resp.JSON200.Pagination.HasMore {
+			break
+		}
+		after = resp.JSON200.Pagination.NextOffset
+	}
+	s.cache.putDir(dir, entries)
+	return entries, nil
+}
+
+// isDir checks if there are objects under the dir prefix
+func (s *objectStore) isDir(ctx context.Context, dir string) (bool, error) {
+	if entries, found := s.cache.getDir(dir); found {
+		return len(entries) > 0, nil
+	}
+	prefix := apigen.PaginationPrefix(dir)
+	amount := apigen.PaginationAmount(1)
+	resp, err := s.client.ListObjectsWithResponse(ctx, s.repository, s.ref, &apigen.ListObjectsParams{
+		Prefix: &prefix,
+		Amount: &amount,
+	})
+	if err != nil {
+		return false, err
+	}
+	if resp.JSON200 == nil {
+		return false, helpers.ResponseAsError(resp)
+	}
+	return len(resp.JSON200.Results) > 0, nil
+}
+
+// readRange reads size bytes of the object at path starting at offset
+func (s *objectStore) readRange(ctx context.Context, path string, offset, size int64) ([]byte, error) {
+	resp, err := s.client.GetObject(ctx, s.repository, s.ref, &apigen.GetObjectParams{
+		Path:  path,
+		Range: swag.String(fmt.Sprintf("bytes=%d-%d", offset, offset+size-1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return io.ReadAll(resp.Body)
+	case http.StatusOK:
+		// the server ignored the range, skip to the requested offset
+		if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+			return nil, err
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, size))
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, helpers.HTTPResponseAsError(resp)
+	}
+}
+
+// download writes the content of the object at path to w
+func (s *objectStore) download(ctx context.Context, path string, w io.Writer) error {
+	resp, err := s.client.GetObject(ctx, s.repository, s.ref, &apigen.GetObjectParams{Path: path})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return helpers.HTTPResponseAsError(resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// upload stages content as the object at path on the mounted branch
+func (s *objectStore) upload(ctx context.Context, path string, content io.ReadSeeker) (*apigen.ObjectStats, error) {
+	stats, err := helpers.ClientUpload(ctx, s.client, s.repository, s.ref, path, nil, "", content)
+	s.cache.invalidate(path)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.putStat(path, stats)
+	return stats, nil
+}
+
+// delete removes the object at path from the mounted branch
+func (s *objectStore) delete(ctx context.Context, path string) error {
+	resp, err := s.client.DeleteObjectWithResponse(ctx, s.repository, s.ref, &apigen.DeleteObjectParams{Path: path})
+	s.cache.invalidate(path)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return helpers.ResponseAsError(resp)
+	}
+	return nil
+}
+
+// baseName returns the last element of an object path or a common prefix
+func baseName(path string) string {
+	path = strings.TrimSuffix(path, "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
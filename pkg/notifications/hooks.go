@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+// DefaultBranchFunc returns the default branch of a repository
+type DefaultBranchFunc func(ctx context.Context, repositoryID string) (string, error)
+
+// HooksHandler wraps a graveler.HooksHandler, notifying subscribers of the branch, tag and merge events it handles
+type HooksHandler struct {
+	graveler.HooksHandler
+	service       *Service
+	defaultBranch DefaultBranchFunc
+}
+
+func NewHooksHandler(next graveler.HooksHandler, service *Service, defaultBranch DefaultBranchFunc) *HooksHandler {
+	return &HooksHandler{
+		HooksHandler:  next,
+		service:       service,
+		defaultBranch: defaultBranch,
+	}
+}
+
+func (h *HooksHandler) PostCreateBranchHook(ctx context.Context, record graveler.HookRecord) {
+	h.HooksHandler.PostCreateBranchHook(ctx, record)
+	h.service.Notify(Notification{
+		Event:      EventBranchCreated,
+		Repository: record.RepositoryID.String(),
+		Branch:     record.BranchID.String(),
+		CommitID:   record.CommitID.String(),
+		Message:    fmt.Sprintf("Branch %s was created", record.BranchID),
+	})
+}
+
+func (h *HooksHandler) PostCreateTagHook(ctx context.Context, record graveler.HookRecord) {
+	h.HooksHandler.PostCreateTagHook(ctx, record)
+	h.service.Notify(Notification{
+		Event:      EventTagCreated,
+		Repository: record.RepositoryID.String(),
+		Tag:        record.TagID.String(),
+		CommitID:   record.CommitID.String(),
+		Message:    fmt.Sprintf("Tag %s was created", record.TagID),
+	})
+}
+
+func (h *HooksHandler) PostMergeHook(ctx context.Context, record graveler.HookRecord) error {
+	err := h.HooksHandler.PostMergeHook(ctx, record)
+	defaultBranch, branchErr := h.defaultBranch(ctx, record.RepositoryID.String())
+	if branchErr != nil {
+		logging.FromContext(ctx).WithError(branchErr).WithField("repository", record.RepositoryID).
+			Warn("Failed to get default branch for merge notification")
+		return err
+	}
+	if record.BranchID.String() == defaultBranch {
+		h.service.Notify(Notification{
+			Event:      EventMergeToDefaultBranch,
+			Repository: record.RepositoryID.String(),
+			Branch:     record.BranchID.String(),
+			CommitID:   record.CommitID.String(),
+			Message:    fmt.Sprintf("New version of %s: %s", record.BranchID, record.Commit.Message),
+		})
+	}
+	return err
+}
+
+// NotifyHookFailed notifies subscribers that running the hooks of record failed with err
+func (h *HooksHandler) NotifyHookFailed(_ context.Context, record graveler.HookRecord, err error) {
+	h.service.Notify(Notification{
+		Event:      EventHookFailed,
+		Repository: record.RepositoryID.String(),
+		Branch:     record.BranchID.String(),
+		Tag:        record.TagID.String(),
+		CommitID:   record.CommitID.String(),
+		Message:    fmt.Sprintf("Hooks of %s event (run %s) failed: %s", record.EventType, record.RunID, err),
+	})
+}
@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrDeliveryFailed = errors.New("delivery failed")
+
+// Notification of an event that occurred on a repository
+type Notification struct {
+	Event      EventType
+	Repository string
+	Branch     string
+	Tag        string
+	CommitID   string
+	// Message describes the event for the subscriber
+	Message string
+	Time    time.Time
+}
+
+func (n Notification) Subject() string {
+	return fmt.Sprintf("[lakeFS] %s: %s", n.Repository, strings.ReplaceAll(string(n.Event), "_", " "))
+}
+
+func (n Notification) Text() string {
+	var b strings.Builder
+	b.WriteString(n.Message)
+	b.WriteString("\n\n")
+	_, _ = fmt.Fprintf(&b, "Repository: %s\n", n.Repository)
+	if n.Branch != "" {
+		_, _ = fmt.Fprintf(&b, "Branch: %s\n", n.Branch)
+	}
+	if n.Tag != "" {
+		_, _ = fmt.Fprintf(&b, "Tag: %s\n", n.Tag)
+	}
+	if n.CommitID != "" {
+		_, _ = fmt.Fprintf(&b, "Commit: %s\n", n.CommitID)
+	}
+	_, _ = fmt.Fprintf(&b, "Time: %s\n", n.Time.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// Sender delivers notifications over a channel to a subscription target
+type Sender interface {
+	Send(ctx context.Context, target string, n Notification) error
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// EmailSender delivers notifications as plain text email through an SMTP server
+type EmailSender struct {
+	cfg SMTPConfig
+}
+
+func NewEmailSender(cfg SMTPConfig) *EmailSender {
+	return &EmailSender{cfg: cfg}
+}
+
+func (s *EmailSender) Send(_ context.Context, target string, n Notification) error {
+	var msg bytes.Buffer
+	_, _ = fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	_, _ = fmt.Fprintf(&msg, "To: %s\r\n", target)
+	_, _ = fmt.Fprintf(&msg, "Subject: %s\r\n", n.Subject())
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(n.Text(), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	return smtp.SendMail(addr, auth, s.cfg.From, []string{target}, msg.Bytes())
+}
+
+// SlackSender delivers notifications to Slack incoming webhooks
+type SlackSender struct {
+	client *http.Client
+}
+
+func NewSlackSender(client *http.Client) *SlackSender {
+	return &SlackSender{client: client}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (s *SlackSender) Send(ctx context.Context, target string, n Notification) error {
+	body, err := json.Marshal(slackMessage{Text: "*" + n.Subject() + "*\n" + n.Text()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: webhook status %d", ErrDeliveryFailed, resp.StatusCode)
+	}
+	return nil
+}
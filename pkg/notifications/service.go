@@ -0,0 +1,177 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	nanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+type Config struct {
+	// DeliveryTimeout bounds the time spent delivering a notification to a single subscription
+	DeliveryTimeout time.Duration
+	// SMTP server used to deliver email notifications, the email channel is not available without a host
+	SMTP SMTPConfig
+	// SlackAllowedEndpoints URL prefixes Slack webhook subscriptions may post to
+	SlackAllowedEndpoints []string
+}
+
+// Service manages user subscriptions to repository events and delivers notifications of these events to them.
+// Delivery is best effort: failures are logged and not retried.
+type Service struct {
+	store   kv.Store
+	cfg     Config
+	senders map[Channel]Sender
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewService(ctx context.Context, store kv.Store, cfg Config) *Service {
+	senders := map[Channel]Sender{
+		ChannelSlack: NewSlackSender(&http.Client{Timeout: cfg.DeliveryTimeout}),
+	}
+	if cfg.SMTP.Host != "" {
+		senders[ChannelEmail] = NewEmailSender(cfg.SMTP)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		store:   store,
+		cfg:     cfg,
+		senders: senders,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// SetSender replaces the sender used to deliver notifications over channel
+func (s *Service) SetSender(channel Channel, sender Sender) {
+	s.senders[channel] = sender
+}
+
+// Stop waits for pending deliveries to complete
+func (s *Service) Stop() {
+	s.wg.Wait()
+	s.cancel()
+}
+
+// Subscribe subscribes username to events of repository, delivered to target over channel
+func (s *Service) Subscribe(ctx context.Context, username, repository string, events []EventType, channel Channel, target string) (*Subscription, error) {
+	if err := validateEvents(events); err != nil {
+		return nil, err
+	}
+	switch channel {
+	case ChannelEmail:
+		if err := validateEmailTarget(target); err != nil {
+			return nil, err
+		}
+	case ChannelSlack:
+		if err := validateWebhookTarget(target, s.cfg.SlackAllowedEndpoints); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidChannel, channel)
+	}
+	if _, ok := s.senders[channel]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrChannelNotConfigured, channel)
+	}
+
+	sub := &Subscription{
+		ID:         nanoid.Must(subscriptionIDLength),
+		Username:   username,
+		Repository: repository,
+		Events:     events,
+		Channel:    channel,
+		Target:     target,
+		CreatedAt:  time.Now().UTC(),
+	}
+	err := kv.SetMsg(ctx, s.store, notificationsPartition, subscriptionPath(username, sub.ID), protoFromSubscription(sub))
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns the subscriptions of username
+func (s *Service) ListSubscriptions(ctx context.Context, username string) ([]*Subscription, error) {
+	return s.listSubscriptions(ctx, subscriptionsUserPrefix(username))
+}
+
+func (s *Service) listSubscriptions(ctx context.Context, prefix []byte) ([]*Subscription, error) {
+	it, err := kv.NewPrimaryIterator(ctx, s.store, (&SubscriptionData{}).ProtoReflect().Type(), notificationsPartition,
+		prefix, kv.IteratorOptionsFrom([]byte("")))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var subs []*Subscription
+	for it.Next() {
+		subs = append(subs, subscriptionFromProto(it.Entry().Value.(*SubscriptionData)))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// Unsubscribe deletes subscription id of username
+func (s *Service) Unsubscribe(ctx context.Context, username, id string) error {
+	key := subscriptionPath(username, id)
+	_, err := kv.GetMsg(ctx, s.store, notificationsPartition, key, &SubscriptionData{})
+	if errors.Is(err, kv.ErrNotFound) {
+		return fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, []byte(notificationsPartition), key)
+}
+
+// Notify delivers n in the background to all the subscriptions matching its repository and event
+func (s *Service) Notify(n Notification) {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.deliver(s.ctx, n)
+	}()
+}
+
+func (s *Service) deliver(ctx context.Context, n Notification) {
+	log := logging.FromContext(ctx).WithFields(logging.Fields{
+		"event":      n.Event,
+		"repository": n.Repository,
+	})
+	subs, err := s.listSubscriptions(ctx, []byte(subscriptionsPrefix+kv.PathDelimiter))
+	if err != nil {
+		log.WithError(err).Error("Failed to list notification subscriptions")
+		return
+	}
+	for _, sub := range subs {
+		if !sub.Matches(n.Repository, n.Event) {
+			continue
+		}
+		sender, ok := s.senders[sub.Channel]
+		if !ok {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, s.cfg.DeliveryTimeout)
+		err := sender.Send(sendCtx, sub.Target, n)
+		cancel()
+		if err != nil {
+			log.WithError(err).WithFields(logging.Fields{
+				"subscription_id": sub.ID,
+				"user":            sub.Username,
+				"channel":         sub.Channel,
+			}).Warn("Failed to deliver notification")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package notifications_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/kv/kvtest"
+	"github.com/treeverse/lakefs/pkg/notifications"
+)
+
+type sentNotification struct {
+	target       string
+	notification notifications.Notification
+}
+
+type memSender struct {
+	mu   sync.Mutex
+	sent []sentNotification
+}
+
+func (m *memSender) Send(_ context.Context, target string, n notifications.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentNotification{target: target, notification: n})
+	return nil
+}
+
+func newTestService(t *testing.T) (*notifications.Service, *memSender) {
+	t.Helper()
+	ctx := context.Background()
+	svc := notifications.NewService(ctx, kvtest.GetStore(ctx, t), notifications.Config{
+		DeliveryTimeout:       time.Second,
+		SMTP:                  notifications.SMTPConfig{Host: "smtp.example.com", Port: 587, From: "lakefs@example.com"},
+		SlackAllowedEndpoints: []string{"https://hooks.slack.com/"},
+	})
+	sender := &memSender{}
+	svc.SetSender(notifications.ChannelEmail, sender)
+	svc.SetSender(notifications.ChannelSlack, sender)
+	return svc, sender
+}
+
+func TestService_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t)
+	defer svc.Stop()
+
+	tests := []struct {
+		name    string
+		events  []notifications.EventType
+		channel notifications.Channel
+		target  string
+		err     error
+	}{
+		{name: "email", events: []notifications.EventType{notifications.EventTagCreated}, channel: notifications.ChannelEmail, target: "user@example.com"},
+		{name: "slack", events: []notifications.EventType{notifications.EventHookFailed}, channel: notifications.ChannelSlack, target: "https://hooks.slack.com/services/T/B/X"},
+		{name: "no events", channel: notifications.ChannelEmail, target: "user@example.com", err: notifications.ErrInvalidEvent},
+		{name: "unknown event", events: []notifications.EventType{"pushed"}, channel: notifications.ChannelEmail, target: "user@example.com", err: notifications.ErrInvalidEvent},
+		{name: "unknown channel", events: []notifications.EventType{notifications.EventTagCreated}, channel: "sms", target: "+1555", err: notifications.ErrInvalidChannel},
+		{name: "bad email", events: []notifications.EventType{notifications.EventTagCreated}, channel: notifications.ChannelEmail, target: "user", err: notifications.ErrInvalidTarget},
+		{name: "webhook not allowed", events: []notifications.EventType{notifications.EventTagCreated}, channel: notifications.ChannelSlack, target: "https://example.com/hook", err: notifications.ErrInvalidTarget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := svc.Subscribe(ctx, "user", "repo", tt.events, tt.channel, tt.target)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, sub.ID)
+		})
+	}
+
+	subs, err := svc.ListSubscriptions(ctx, "user")
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	otherSubs, err := svc.ListSubscriptions(ctx, "other")
+	require.NoError(t, err)
+	require.Empty(t, otherSubs)
+
+	// subscriptions are deleted only by their user
+	require.ErrorIs(t, svc.Unsubscribe(ctx, "other", subs[0].ID), notifications.ErrNotFound)
+	require.NoError(t, svc.Unsubscribe(ctx, "user", subs[0].ID))
+	require.ErrorIs(t, svc.Unsubscribe(ctx, "user", subs[0].ID), notifications.ErrNotFound)
+	subs, err = svc.ListSubscriptions(ctx, "user")
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+}
+
+func TestHooksHandler_Notify(t *testing.T) {
+	ctx := context.Background()
+	svc, sender := newTestService(t)
+
+	_, err := svc.Subscribe(ctx, "user1", "repo", []notifications.EventType{notifications.EventMergeToDefaultBranch}, notifications.ChannelEmail, "user1@example.com")
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user2", "repo", []notifications.EventType{notifications.EventBranchCreated}, notifications.ChannelSlack, "https://hooks.slack.com/services/T/B/X")
+	require.NoError(t, err)
+	_, err = svc.Subscribe(ctx, "user3", "other", []notifications.EventType{notifications.EventMergeToDefaultBranch}, notifications.ChannelEmail, "user3@example.com")
+	require.NoError(t, err)
+
+	h := notifications.NewHooksHandler(&graveler.HooksNoOp{}, svc, func(context.Context, string) (string, error) {
+		return "main", nil
+	})
+	// merges into other branches are not notified
+	require.NoError(t, h.PostMergeHook(ctx, graveler.HookRecord{RepositoryID: "repo", BranchID: "dev", CommitID: "c1"}))
+	require.NoError(t, h.PostMergeHook(ctx, graveler.HookRecord{RepositoryID: "repo", BranchID: "main", CommitID: "c2"}))
+	h.PostCreateBranchHook(ctx, graveler.HookRecord{RepositoryID: "repo", BranchID: "feature", CommitID: "c2"})
+	svc.Stop()
+
+	require.Len(t, sender.sent, 2)
+	targets := map[string]notifications.Notification{}
+	for _, s := range sender.sent {
+		targets[s.target] = s.notification
+	}
+	merge := targets["user1@example.com"]
+	require.Equal(t, notifications.EventMergeToDefaultBranch, merge.Event)
+	require.Equal(t, "c2", merge.CommitID)
+	branch := targets["https://hooks.slack.com/services/T/B/X"]
+	require.Equal(t, notifications.EventBranchCreated, branch.Event)
+	require.Equal(t, "feature", branch.Branch)
+}
@@ -0,0 +1,155 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+)
+
+const (
+	notificationsPartition = "notifications"
+	subscriptionsPrefix    = "subscriptions"
+
+	subscriptionIDLength = 16
+)
+
+//nolint:gochecknoinits
+func init() {
+	kv.MustRegisterType(notificationsPartition, subscriptionsPrefix, (&SubscriptionData{}).ProtoReflect().Type())
+}
+
+var (
+	ErrNotFound             = errors.New("subscription not found")
+	ErrInvalidEvent         = errors.New("invalid event")
+	ErrInvalidChannel       = errors.New("invalid channel")
+	ErrInvalidTarget        = errors.New("invalid target")
+	ErrChannelNotConfigured = errors.New("channel not configured")
+)
+
+type EventType string
+
+const (
+	EventBranchCreated        EventType = "branch_created"
+	EventTagCreated           EventType = "tag_created"
+	EventMergeToDefaultBranch EventType = "merge_to_default_branch"
+	EventGCCompleted          EventType = "gc_completed"
+	EventHookFailed           EventType = "hook_failed"
+)
+
+var eventTypes = map[EventType]struct{}{
+	EventBranchCreated:        {},
+	EventTagCreated:           {},
+	EventMergeToDefaultBranch: {},
+	EventGCCompleted:          {},
+	EventHookFailed:           {},
+}
+
+type Channel string
+
+const (
+	ChannelEmail Channel = "email"
+	ChannelSlack Channel = "slack"
+)
+
+// Subscription of a user to events of a repository, delivered to target over channel. The target is an email
+// address for the email channel and an incoming webhook URL for the Slack channel.
+type Subscription struct {
+	ID         string
+	Username   string
+	Repository string
+	Events     []EventType
+	Channel    Channel
+	Target     string
+	CreatedAt  time.Time
+}
+
+func subscriptionFromProto(pb *SubscriptionData) *Subscription {
+	events := make([]EventType, 0, len(pb.Events))
+	for _, e := range pb.Events {
+		events = append(events, EventType(e))
+	}
+	return &Subscription{
+		ID:         pb.Id,
+		Username:   pb.Username,
+		Repository: pb.Repository,
+		Events:     events,
+		Channel:    Channel(pb.Channel),
+		Target:     pb.Target,
+		CreatedAt:  time.Unix(pb.CreatedAt, 0).UTC(),
+	}
+}
+
+func protoFromSubscription(s *Subscription) *SubscriptionData {
+	events := make([]string, 0, len(s.Events))
+	for _, e := range s.Events {
+		events = append(events, string(e))
+	}
+	return &SubscriptionData{
+		Id:         s.ID,
+		Username:   s.Username,
+		Repository: s.Repository,
+		Events:     events,
+		Channel:    string(s.Channel),
+		Target:     s.Target,
+		CreatedAt:  s.CreatedAt.Unix(),
+	}
+}
+
+// Matches reports whether the subscription should be notified of event on repository
+func (s *Subscription) Matches(repository string, event EventType) bool {
+	if s.Repository != repository {
+		return false
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func subscriptionPath(username, id string) []byte {
+	return []byte(kv.FormatPath(subscriptionsPrefix, username, id))
+}
+
+func subscriptionsUserPrefix(username string) []byte {
+	return []byte(kv.FormatPath(subscriptionsPrefix, username) + kv.PathDelimiter)
+}
+
+func validateEvents(events []EventType) error {
+	if len(events) == 0 {
+		return fmt.Errorf("%w: no events", ErrInvalidEvent)
+	}
+	for _, e := range events {
+		if _, ok := eventTypes[e]; !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidEvent, e)
+		}
+	}
+	return nil
+}
+
+func validateEmailTarget(target string) error {
+	addr, err := mail.ParseAddress(target)
+	if err != nil || addr.Address != target {
+		return fmt.Errorf("%w: email address %s", ErrInvalidTarget, target)
+	}
+	return nil
+}
+
+func validateWebhookTarget(target string, allowedEndpoints []string) error {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: webhook URL %s", ErrInvalidTarget, target)
+	}
+	for _, endpoint := range allowedEndpoints {
+		if strings.HasPrefix(target, endpoint) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: webhook URL %s is not an allowed endpoint", ErrInvalidTarget, target)
+}
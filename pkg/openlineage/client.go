@@ -0,0 +1,57 @@
+package openlineage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrEmitFailed = errors.New("emit failed")
+
+// Emitter sends OpenLineage events
+type Emitter interface {
+	Emit(ctx context.Context, event *RunEvent) error
+}
+
+// Client posts OpenLineage events to an HTTP endpoint, e.g. the lineage endpoint of Marquez
+type Client struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewClient(url, apiKey string, timeout time.Duration) *Client {
+	return &Client{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) Emit(ctx context.Context, event *RunEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: status %d", ErrEmitFailed, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package openlineage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/treeverse/lakefs/pkg/version"
+)
+
+const (
+	RunEventSchemaURL            = "https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/RunEvent"
+	ParentRunFacetSchemaURL      = "https://openlineage.io/spec/facets/1-0-0/ParentRunFacet.json#/$defs/ParentRunFacet"
+	JobTypeFacetSchemaURL        = "https://openlineage.io/spec/facets/2-0-2/JobTypeJobFacet.json#/$defs/JobTypeJobFacet"
+	DatasetVersionFacetSchemaURL = "https://openlineage.io/spec/facets/1-0-0/DatasetVersionDatasetFacet.json#/$defs/DatasetVersionDatasetFacet"
+	LakeFSRunFacetSchemaURL      = "https://docs.lakefs.io/integrations/openlineage.html#events"
+
+	EventTypeComplete = "COMPLETE"
+
+	JobTypeCommit = "COMMIT"
+	JobTypeMerge  = "MERGE"
+
+	// Metadata keys of a commit that name the job that created it and the run of that job
+	MetadataKeyJobNamespace = "openlineage.job.namespace"
+	MetadataKeyJobName      = "openlineage.job.name"
+	MetadataKeyRunID        = "openlineage.run.id"
+)
+
+// Producer identifies this version of lakeFS as the producer of events and facets
+var Producer = "https://github.com/treeverse/lakeFS/tree/" + version.Version
+
+// RunEvent is an OpenLineage run event, see https://openlineage.io/docs/spec/object-model
+type RunEvent struct {
+	EventType string    `json:"eventType"`
+	EventTime time.Time `json:"eventTime"`
+	Producer  string    `json:"producer"`
+	SchemaURL string    `json:"schemaURL"`
+	Run       Run       `json:"run"`
+	Job       Job       `json:"job"`
+	Inputs    []Dataset `json:"inputs"`
+	Outputs   []Dataset `json:"outputs"`
+}
+
+type Run struct {
+	RunID  string                 `json:"runId"`
+	Facets map[string]interface{} `json:"facets,omitempty"`
+}
+
+type Job struct {
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Facets    map[string]interface{} `json:"facets,omitempty"`
+}
+
+type Dataset struct {
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Facets    map[string]interface{} `json:"facets,omitempty"`
+}
+
+// BaseFacet holds the fields common to all facets
+type BaseFacet struct {
+	Producer  string `json:"_producer"`
+	SchemaURL string `json:"_schemaURL"`
+}
+
+func newBaseFacet(schemaURL string) BaseFacet {
+	return BaseFacet{Producer: Producer, SchemaURL: schemaURL}
+}
+
+type ParentRunFacet struct {
+	BaseFacet
+	Run struct {
+		RunID string `json:"runId"`
+	} `json:"run"`
+	Job struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"job"`
+}
+
+type JobTypeFacet struct {
+	BaseFacet
+	ProcessingType string `json:"processingType"`
+	Integration    string `json:"integration"`
+	JobType        string `json:"jobType"`
+}
+
+type DatasetVersionFacet struct {
+	BaseFacet
+	DatasetVersion string `json:"datasetVersion"`
+}
+
+// LakeFSRunFacet describes the commit created by a run
+type LakeFSRunFacet struct {
+	BaseFacet
+	Repository  string            `json:"repository"`
+	Branch      string            `json:"branch"`
+	CommitID    string            `json:"commitId"`
+	MergeSource string            `json:"mergeSource,omitempty"`
+	HookRunID   string            `json:"hookRunId"`
+	Committer   string            `json:"committer"`
+	Message     string            `json:"message"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// RunID returns the run ID of the commit with commitID in repository. OpenLineage run IDs are UUIDs, the ID of a commit
+// is derived from its lakeFS URI so the same commit is always reported as the same run.
+func RunID(repository, commitID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("lakefs://"+repository+"/commits/"+commitID)).String()
+}
+
+// DatasetNamespace returns the namespace of the datasets of repository
+func DatasetNamespace(repository string) string {
+	return "lakefs://" + repository
+}
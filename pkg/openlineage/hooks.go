@@ -0,0 +1,251 @@
+package openlineage
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	// MaxDatasets is the largest number of datasets reported as the inputs or the outputs of a run
+	MaxDatasets = 100
+
+	diffPageSize = 1000
+)
+
+// Differ lists the differences between two refs of a repository
+type Differ interface {
+	Diff(ctx context.Context, repositoryID string, leftReference string, rightReference string, params catalog.DiffParams) (catalog.Differences, bool, error)
+}
+
+type Config struct {
+	// Namespace of jobs that are not named by the metadata of their commit
+	Namespace string
+	// DatasetPrefixDepth number of path components of the changed prefixes reported as datasets, 0 reports the
+	// branch as a single dataset
+	DatasetPrefixDepth int
+}
+
+// HooksHandler wraps a graveler.HooksHandler, emitting an OpenLineage run event for every commit and merge it
+// handles. Events are emitted in the background and failures are logged, a commit never fails on lineage.
+type HooksHandler struct {
+	graveler.HooksHandler
+	emitter Emitter
+	differ  Differ
+	cfg     Config
+	ctx     context.Context
+	wg      sync.WaitGroup
+}
+
+func NewHooksHandler(ctx context.Context, next graveler.HooksHandler, emitter Emitter, differ Differ, cfg Config) *HooksHandler {
+	return &HooksHandler{
+		HooksHandler: next,
+		emitter:      emitter,
+		differ:       differ,
+		cfg:          cfg,
+		ctx:          ctx,
+	}
+}
+
+// Stop waits for pending events to be emitted
+func (h *HooksHandler) Stop() {
+	h.wg.Wait()
+}
+
+func (h *HooksHandler) PostCommitHook(ctx context.Context, record graveler.HookRecord) error {
+	err := h.HooksHandler.PostCommitHook(ctx, record)
+	h.emit(record, JobTypeCommit)
+	return err
+}
+
+func (h *HooksHandler) PostMergeHook(ctx context.Context, record graveler.HookRecord) error {
+	err := h.HooksHandler.PostMergeHook(ctx, record)
+	h.emit(record, JobTypeMerge)
+	return err
+}
+
+func (h *HooksHandler) emit(record graveler.HookRecord, jobType string) {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		log := logging.FromContext(h.ctx).WithFields(logging.Fields{
+			"repository": record.RepositoryID,
+			"branch":     record.BranchID,
+			"commit_id":  record.CommitID,
+		})
+		event, err := h.NewEvent(h.ctx, record, jobType)
+		if err != nil {
+			log.WithError(err).Warn("Failed to create OpenLineage event")
+			return
+		}
+		if err := h.emitter.Emit(h.ctx, event); err != nil {
+			log.WithError(err).Warn("Failed to emit OpenLineage event")
+		}
+	}()
+}
+
+// NewEvent returns the run event of the commit or merge of record. The job is named by the metadata of the commit,
+// or after the branch. The datasets are the changed prefixes of the branch, and for a merge, the same prefixes of the
+// merge source.
+func (h *HooksHandler) NewEvent(ctx context.Context, record graveler.HookRecord, jobType string) (*RunEvent, error) {
+	repository := record.RepositoryID.String()
+	branch := record.BranchID.String()
+	commitID := record.CommitID.String()
+	metadata := record.Commit.Metadata
+
+	job := Job{
+		Namespace: h.cfg.Namespace,
+		Name:      repository + "." + branch + "." + strings.ToLower(jobType),
+		Facets: map[string]interface{}{
+			"jobType": JobTypeFacet{
+				BaseFacet:      newBaseFacet(JobTypeFacetSchemaURL),
+				ProcessingType: "BATCH",
+				Integration:    "LAKEFS",
+				JobType:        jobType,
+			},
+		},
+	}
+	if metadata[MetadataKeyJobNamespace] != "" {
+		job.Namespace = metadata[MetadataKeyJobNamespace]
+	}
+	if metadata[MetadataKeyJobName] != "" {
+		job.Name = metadata[MetadataKeyJobName]
+	}
+
+	run := Run{
+		RunID: RunID(repository, commitID),
+		Facets: map[string]interface{}{
+			"lakefs": LakeFSRunFacet{
+				BaseFacet:   newBaseFacet(LakeFSRunFacetSchemaURL),
+				Repository:  repository,
+				Branch:      branch,
+				CommitID:    commitID,
+				MergeSource: record.MergeSource.String(),
+				HookRunID:   record.RunID,
+				Committer:   record.Commit.Committer,
+				Message:     record.Commit.Message,
+				Metadata:    metadata,
+			},
+		},
+	}
+	// a run ID given by metadata is the run of the job that created the commit
+	if parentRunID := metadata[MetadataKeyRunID]; parentRunID != "" {
+		parent := ParentRunFacet{BaseFacet: newBaseFacet(ParentRunFacetSchemaURL)}
+		parent.Run.RunID = parentRunID
+		parent.Job.Namespace = job.Namespace
+		parent.Job.Name = job.Name
+		run.Facets["parent"] = parent
+	}
+
+	prefixes := []string{""}
+	if len(record.Commit.Parents) > 0 && h.cfg.DatasetPrefixDepth > 0 {
+		var err error
+		prefixes, err = h.changedPrefixes(ctx, repository, record.Commit.Parents[0].String(), commitID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	event := &RunEvent{
+		EventType: EventTypeComplete,
+		EventTime: record.Commit.CreationDate.UTC(),
+		Producer:  Producer,
+		SchemaURL: RunEventSchemaURL,
+		Run:       run,
+		Job:       job,
+		Inputs:    []Dataset{},
+		Outputs:   make([]Dataset, 0, len(prefixes)),
+	}
+	for _, prefix := range prefixes {
+		event.Outputs = append(event.Outputs, newDataset(repository, branch, prefix, commitID))
+	}
+	if jobType == JobTypeMerge && len(record.Commit.Parents) > 1 {
+		sourceCommitID := record.Commit.Parents[1].String()
+		source := record.MergeSource.String()
+		if source == "" {
+			source = sourceCommitID
+		}
+		for _, prefix := range prefixes {
+			event.Inputs = append(event.Inputs, newDataset(repository, source, prefix, sourceCommitID))
+		}
+	}
+	return event, nil
+}
+
+// newDataset returns the dataset of prefix in ref, named <ref>/<prefix>, at version commitID
+func newDataset(repository, ref, prefix, commitID string) Dataset {
+	name := ref
+	if prefix != "" {
+		name += "/" + strings.TrimSuffix(prefix, "/")
+	}
+	return Dataset{
+		Namespace: DatasetNamespace(repository),
+		Name:      name,
+		Facets: map[string]interface{}{
+			"version": DatasetVersionFacet{
+				BaseFacet:      newBaseFacet(DatasetVersionFacetSchemaURL),
+				DatasetVersion: commitID,
+			},
+		},
+	}
+}
+
+// changedPrefixes returns the prefixes, up to the configured depth, that changed between left and right. Objects
+// changed above that depth are reported as their parent prefix, "" for the root. At most MaxDatasets prefixes are
+// returned.
+func (h *HooksHandler) changedPrefixes(ctx context.Context, repository, left, right string) ([]string, error) {
+	var prefixes []string
+	seen := make(map[string]struct{})
+	add := func(prefix string) {
+		if _, ok := seen[prefix]; !ok {
+			seen[prefix] = struct{}{}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	var walk func(prefix string, depth int) error
+	walk = func(prefix string, depth int) error {
+		after := ""
+		for len(prefixes) < MaxDatasets {
+			diffs, hasMore, err := h.differ.Diff(ctx, repository, left, right, catalog.DiffParams{
+				Limit:     diffPageSize,
+				After:     after,
+				Prefix:    prefix,
+				Delimiter: "/",
+			})
+			if err != nil {
+				return err
+			}
+			for _, d := range diffs {
+				if len(prefixes) >= MaxDatasets {
+					return nil
+				}
+				switch {
+				case !d.CommonLevel:
+					add(prefix)
+				case depth+1 < h.cfg.DatasetPrefixDepth:
+					if err := walk(d.Path, depth+1); err != nil {
+						return err
+					}
+				default:
+					add(d.Path)
+				}
+			}
+			if !hasMore || len(diffs) == 0 {
+				return nil
+			}
+			after = diffs[len(diffs)-1].Path
+		}
+		return nil
+	}
+	if err := walk("", 0); err != nil {
+		return nil, err
+	}
+	if len(prefixes) == 0 {
+		// nothing changed, e.g. an empty commit, the run still writes a version of the branch
+		prefixes = []string{""}
+	}
+	return prefixes, nil
+}
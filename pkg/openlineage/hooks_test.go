@@ -0,0 +1,150 @@
+package openlineage_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/catalog"
+	"github.com/treeverse/lakefs/pkg/graveler"
+	"github.com/treeverse/lakefs/pkg/openlineage"
+)
+
+// fakeDiffer reports the changes of paths, listed with a delimiter
+type fakeDiffer struct {
+	paths []string
+}
+
+func (d *fakeDiffer) Diff(_ context.Context, _, _, _ string, params catalog.DiffParams) (catalog.Differences, bool, error) {
+	var diffs catalog.Differences
+	seen := make(map[string]struct{})
+	for _, p := range d.paths {
+		if !strings.HasPrefix(p, params.Prefix) || p <= params.After {
+			continue
+		}
+		rest := strings.TrimPrefix(p, params.Prefix)
+		if i := strings.Index(rest, params.Delimiter); params.Delimiter != "" && i >= 0 {
+			common := params.Prefix + rest[:i+1]
+			if _, ok := seen[common]; ok || common <= params.After {
+				continue
+			}
+			seen[common] = struct{}{}
+			diffs = append(diffs, catalog.Difference{DBEntry: catalog.DBEntry{Path: common, CommonLevel: true}})
+			continue
+		}
+		diffs = append(diffs, catalog.Difference{DBEntry: catalog.DBEntry{Path: p}})
+	}
+	return diffs, false, nil
+}
+
+func mergeRecord() graveler.HookRecord {
+	return graveler.HookRecord{
+		RunID:        "hook-run",
+		EventType:    graveler.EventTypePostMerge,
+		RepositoryID: "repo1",
+		BranchID:     "main",
+		MergeSource:  "etl",
+		CommitID:     "c3",
+		Commit: graveler.Commit{
+			Committer:    "etl-user",
+			Message:      "Merge etl into main",
+			CreationDate: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			Parents:      graveler.CommitParents{"c1", "c2"},
+			Metadata: graveler.Metadata{
+				openlineage.MetadataKeyJobNamespace: "airflow",
+				openlineage.MetadataKeyJobName:      "daily_etl",
+				openlineage.MetadataKeyRunID:        "0b8a1c4e-5a4f-4d6e-9f52-7e0b1a3c9d21",
+			},
+		},
+	}
+}
+
+func TestHooksHandler_NewEvent(t *testing.T) {
+	ctx := context.Background()
+	differ := &fakeDiffer{paths: []string{"README.md", "tables/orders/part-0.parquet", "tables/users/part-0.parquet", "tables/users/part-1.parquet"}}
+
+	t.Run("branch", func(t *testing.T) {
+		h := openlineage.NewHooksHandler(ctx, &graveler.HooksNoOp{}, nil, differ, openlineage.Config{Namespace: "lakefs"})
+		record := mergeRecord()
+		record.EventType = graveler.EventTypePostCommit
+		record.Commit.Metadata = nil
+		record.Commit.Parents = graveler.CommitParents{"c1"}
+		event, err := h.NewEvent(ctx, record, openlineage.JobTypeCommit)
+		require.NoError(t, err)
+		require.Equal(t, openlineage.EventTypeComplete, event.EventType)
+		require.Equal(t, "lakefs", event.Job.Namespace)
+		require.Equal(t, "repo1.main.commit", event.Job.Name)
+		require.Equal(t, openlineage.RunID("repo1", "c3"), event.Run.RunID)
+		require.NotContains(t, event.Run.Facets, "parent")
+		require.Empty(t, event.Inputs)
+		require.Len(t, event.Outputs, 1)
+		require.Equal(t, "lakefs://repo1", event.Outputs[0].Namespace)
+		require.Equal(t, "main", event.Outputs[0].Name)
+	})
+
+	t.Run("merge_prefixes", func(t *testing.T) {
+		h := openlineage.NewHooksHandler(ctx, &graveler.HooksNoOp{}, nil, differ, openlineage.Config{Namespace: "lakefs", DatasetPrefixDepth: 2})
+		event, err := h.NewEvent(ctx, mergeRecord(), openlineage.JobTypeMerge)
+		require.NoError(t, err)
+		require.Equal(t, "airflow", event.Job.Namespace)
+		require.Equal(t, "daily_etl", event.Job.Name)
+		require.Contains(t, event.Run.Facets, "parent")
+
+		var outputs, inputs []string
+		for _, d := range event.Outputs {
+			outputs = append(outputs, d.Name)
+			require.Equal(t, "c3", d.Facets["version"].(openlineage.DatasetVersionFacet).DatasetVersion)
+		}
+		for _, d := range event.Inputs {
+			inputs = append(inputs, d.Name)
+			require.Equal(t, "c2", d.Facets["version"].(openlineage.DatasetVersionFacet).DatasetVersion)
+		}
+		require.Equal(t, []string{"main", "main/tables/orders", "main/tables/users"}, outputs)
+		require.Equal(t, []string{"etl", "etl/tables/orders", "etl/tables/users"}, inputs)
+	})
+}
+
+func TestHooksHandler_Emit(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []openlineage.RunEvent
+		auth     string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event openlineage.RunEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event)
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := openlineage.NewClient(server.URL, "secret", time.Second)
+	h := openlineage.NewHooksHandler(ctx, &graveler.HooksNoOp{}, client, &fakeDiffer{}, openlineage.Config{Namespace: "lakefs"})
+	require.NoError(t, h.PostMergeHook(ctx, mergeRecord()))
+	record := mergeRecord()
+	record.EventType = graveler.EventTypePostCommit
+	require.NoError(t, h.PostCommitHook(ctx, record))
+	h.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2)
+	require.Equal(t, "Bearer secret", auth)
+	for _, event := range received {
+		require.Equal(t, openlineage.RunEventSchemaURL, event.SchemaURL)
+		require.Equal(t, "daily_etl", event.Job.Name)
+	}
+}
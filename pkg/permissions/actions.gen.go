@@ -9,6 +9,7 @@ var Actions = []string{
 	"fs:AttachStorageNamespace",
 	"fs:ImportFromStorage",
 	"fs:ImportCancel",
+	"fs:CancelOperation",
 	"fs:DeleteRepository",
 	"fs:ListRepositories",
 	"fs:ReadObject",
@@ -18,6 +19,7 @@ var Actions = []string{
 	"fs:CreateCommit",
 	"fs:CreateMetaRange",
 	"fs:ReadCommit",
+	"fs:CreateLineage",
 	"fs:ListCommits",
 	"fs:CreateBranch",
 	"fs:DeleteBranch",
@@ -29,6 +31,23 @@ var Actions = []string{
 	"fs:ReadTag",
 	"fs:ListTags",
 	"fs:ReadConfig",
+	"fs:ReloadConfig",
+	"fs:UpdateLogging",
+	"fs:ReadGatewayDenials",
+	"fs:ReadUsageReport",
+	"fs:UseEncryptionKey",
+	"fs:CreateDatasetRelease",
+	"fs:ReadDatasetRelease",
+	"fs:CreateOrganization",
+	"fs:ReadOrganization",
+	"fs:ListOrganizations",
+	"fs:UpdateOrganization",
+	"fs:DeleteOrganization",
+	"fs:CreateProject",
+	"fs:ReadProject",
+	"fs:ListProjects",
+	"fs:UpdateProject",
+	"fs:DeleteProject",
 	"auth:ReadUser",
 	"auth:CreateUser",
 	"auth:DeleteUser",
@@ -54,10 +73,14 @@ var Actions = []string{
 	"auth:DeleteUserExternalPrincipal",
 	"auth:ReadExternalPrincipal",
 	"ci:ReadAction",
+	"ci:ReadActionsConfig",
+	"ci:WriteActionsConfig",
 	"retention:PrepareGarbageCollectionCommits",
 	"retention:GetGarbageCollectionRules",
 	"retention:SetGarbageCollectionRules",
 	"retention:PrepareGarbageCollectionUncommitted",
+	"retention:CollectOrphanMetadata",
+	"retention:RunLocalGarbageCollection",
 	"branches:GetBranchProtectionRules",
 	"branches:SetBranchProtectionRules",
 }
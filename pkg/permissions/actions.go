@@ -20,6 +20,7 @@ const (
 	AttachStorageNamespaceAction              = "fs:AttachStorageNamespace"
 	ImportFromStorageAction                   = "fs:ImportFromStorage"
 	ImportCancelAction                        = "fs:ImportCancel"
+	CancelOperationAction                     = "fs:CancelOperation"
 	DeleteRepositoryAction                    = "fs:DeleteRepository"
 	ListRepositoriesAction                    = "fs:ListRepositories"
 	ReadObjectAction                          = "fs:ReadObject"
@@ -29,6 +30,7 @@ const (
 	CreateCommitAction                        = "fs:CreateCommit"
 	CreateMetaRangeAction                     = "fs:CreateMetaRange"
 	ReadCommitAction                          = "fs:ReadCommit"
+	CreateLineageAction                       = "fs:CreateLineage"
 	ListCommitsAction                         = "fs:ListCommits"
 	CreateBranchAction                        = "fs:CreateBranch"
 	DeleteBranchAction                        = "fs:DeleteBranch"
@@ -40,6 +42,23 @@ const (
 	ReadTagAction                             = "fs:ReadTag"
 	ListTagsAction                            = "fs:ListTags"
 	ReadConfigAction                          = "fs:ReadConfig"
+	ReloadConfigAction                        = "fs:ReloadConfig"
+	UpdateLoggingAction                       = "fs:UpdateLogging"
+	ReadGatewayDenialsAction                  = "fs:ReadGatewayDenials"
+	ReadUsageReportAction                     = "fs:ReadUsageReport"
+	UseEncryptionKeyAction                    = "fs:UseEncryptionKey"
+	CreateDatasetReleaseAction                = "fs:CreateDatasetRelease"
+	ReadDatasetReleaseAction                  = "fs:ReadDatasetRelease"
+	CreateOrganizationAction                  = "fs:CreateOrganization"
+	ReadOrganizationAction                    = "fs:ReadOrganization"
+	ListOrganizationsAction                   = "fs:ListOrganizations"
+	UpdateOrganizationAction                  = "fs:UpdateOrganization"
+	DeleteOrganizationAction                  = "fs:DeleteOrganization"
+	CreateProjectAction                       = "fs:CreateProject"
+	ReadProjectAction                         = "fs:ReadProject"
+	ListProjectsAction                        = "fs:ListProjects"
+	UpdateProjectAction                       = "fs:UpdateProject"
+	DeleteProjectAction                       = "fs:DeleteProject"
 	ReadUserAction                            = "auth:ReadUser"
 	CreateUserAction                          = "auth:CreateUser"
 	DeleteUserAction                          = "auth:DeleteUser"
@@ -65,10 +84,14 @@ const (
 	DeleteUserExternalPrincipalAction         = "auth:DeleteUserExternalPrincipal"
 	ReadExternalPrincipalAction               = "auth:ReadExternalPrincipal"
 	ReadActionsAction                         = "ci:ReadAction"
+	ReadActionsConfigAction                   = "ci:ReadActionsConfig"
+	WriteActionsConfigAction                  = "ci:WriteActionsConfig"
 	PrepareGarbageCollectionCommitsAction     = "retention:PrepareGarbageCollectionCommits"
 	GetGarbageCollectionRulesAction           = "retention:GetGarbageCollectionRules"
 	SetGarbageCollectionRulesAction           = "retention:SetGarbageCollectionRules"
 	PrepareGarbageCollectionUncommittedAction = "retention:PrepareGarbageCollectionUncommitted"
+	CollectOrphanMetadataAction               = "retention:CollectOrphanMetadata"
+	RunLocalGarbageCollectionAction           = "retention:RunLocalGarbageCollection"
 	GetBranchProtectionRulesAction            = "branches:GetBranchProtectionRules"
 	SetBranchProtectionRulesAction            = "branches:SetBranchProtectionRules"
 )
@@ -1,10 +1,15 @@
 package permissions
 
+import "strings"
+
 const (
 	fsArnPrefix   = "arn:lakefs:fs:::"
 	authArnPrefix = "arn:lakefs:auth:::"
 
 	All = "*"
+
+	// ActionsConfigPrefix is the path of the actions configuration of a repository
+	ActionsConfigPrefix = "_lakefs_actions/"
 )
 
 type Permission struct {
@@ -38,6 +43,57 @@ func ObjectArn(repoID, key string) string {
 	return fsArnPrefix + "repository/" + repoID + "/object/" + key
 }
 
+// IsActionsConfigArn returns true if arn is of an object of the actions configuration of a repository
+func IsActionsConfigArn(arn string) bool {
+	resource, ok := strings.CutPrefix(arn, fsArnPrefix+"repository/")
+	if !ok {
+		return false
+	}
+	repoID, key, ok := strings.Cut(resource, "/object/")
+	return ok && !strings.Contains(repoID, "/") && strings.HasPrefix(key, ActionsConfigPrefix)
+}
+
+// ActionsConfigPermission returns the permission required in addition to permission when it accesses the actions
+// configuration of a repository: reading it requires ReadActionsConfigAction and modifying it requires
+// WriteActionsConfigAction, so that writing objects does not allow altering the hooks validating the writes.
+func ActionsConfigPermission(permission Permission) (Permission, bool) {
+	var action string
+	switch permission.Action {
+	case ReadObjectAction:
+		action = ReadActionsConfigAction
+	case WriteObjectAction, DeleteObjectAction:
+		action = WriteActionsConfigAction
+	default:
+		return Permission{}, false
+	}
+	if !IsActionsConfigArn(permission.Resource) {
+		return Permission{}, false
+	}
+	return Permission{Action: action, Resource: permission.Resource}, true
+}
+
+// WithActionsConfig returns node where every permission accessing the actions configuration of a repository also
+// requires its ActionsConfigPermission. Requests carry the added permissions explicitly, so that any authorizer
+// enforces them and not only the policies evaluated by lakeFS.
+func WithActionsConfig(node Node) Node {
+	if node.Type == NodeTypeNode {
+		configPermission, ok := ActionsConfigPermission(node.Permission)
+		if !ok {
+			return node
+		}
+		return Node{
+			Type:  NodeTypeAnd,
+			Nodes: []Node{node, {Permission: configPermission}},
+		}
+	}
+	nodes := make([]Node, len(node.Nodes))
+	for i, n := range node.Nodes {
+		nodes[i] = WithActionsConfig(n)
+	}
+	node.Nodes = nodes
+	return node
+}
+
 func BranchArn(repoID, branchID string) string {
 	return fsArnPrefix + "repository/" + repoID + "/branch/" + branchID
 }
@@ -46,6 +102,22 @@ func TagArn(repoID, tagID string) string {
 	return fsArnPrefix + "repository/" + repoID + "/tag/" + tagID
 }
 
+func EncryptionKeyArn(keyID string) string {
+	return fsArnPrefix + "encryption-key/" + keyID
+}
+
+func DatasetArn(dataset string) string {
+	return fsArnPrefix + "dataset/" + dataset
+}
+
+func OrganizationArn(organizationID string) string {
+	return fsArnPrefix + "organization/" + organizationID
+}
+
+func ProjectArn(organizationID, projectID string) string {
+	return fsArnPrefix + "organization/" + organizationID + "/project/" + projectID
+}
+
 func UserArn(userID string) string {
 	return authArnPrefix + "user/" + userID
 }
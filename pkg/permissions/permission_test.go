@@ -0,0 +1,79 @@
+package permissions_test
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/permissions"
+)
+
+func TestActionsConfigPermission(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Permission permissions.Permission
+		Expected   string
+	}{
+		{
+			Name:       "read",
+			Permission: permissions.Permission{Action: permissions.ReadObjectAction, Resource: permissions.ObjectArn("repo1", "_lakefs_actions/hooks.yaml")},
+			Expected:   permissions.ReadActionsConfigAction,
+		},
+		{
+			Name:       "write",
+			Permission: permissions.Permission{Action: permissions.WriteObjectAction, Resource: permissions.ObjectArn("repo1", "_lakefs_actions/hooks.yaml")},
+			Expected:   permissions.WriteActionsConfigAction,
+		},
+		{
+			Name:       "delete",
+			Permission: permissions.Permission{Action: permissions.DeleteObjectAction, Resource: permissions.ObjectArn("repo1", "_lakefs_actions/hooks.yaml")},
+			Expected:   permissions.WriteActionsConfigAction,
+		},
+		{
+			Name:       "other_object",
+			Permission: permissions.Permission{Action: permissions.WriteObjectAction, Resource: permissions.ObjectArn("repo1", "data/_lakefs_actions/hooks.yaml")},
+		},
+		{
+			Name:       "other_action",
+			Permission: permissions.Permission{Action: permissions.ListObjectsAction, Resource: permissions.ObjectArn("repo1", "_lakefs_actions/hooks.yaml")},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			permission, ok := permissions.ActionsConfigPermission(tt.Permission)
+			if ok != (tt.Expected != "") {
+				t.Fatalf("ActionsConfigPermission(%+v) ok=%t, expected %t", tt.Permission, ok, tt.Expected != "")
+			}
+			if !ok {
+				return
+			}
+			if permission.Action != tt.Expected || permission.Resource != tt.Permission.Resource {
+				t.Errorf("ActionsConfigPermission(%+v)=%+v, expected action %s on the same resource", tt.Permission, permission, tt.Expected)
+			}
+		})
+	}
+}
+
+func TestWithActionsConfig(t *testing.T) {
+	config := permissions.Permission{Action: permissions.WriteObjectAction, Resource: permissions.ObjectArn("repo1", "_lakefs_actions/hooks.yaml")}
+	other := permissions.Permission{Action: permissions.WriteObjectAction, Resource: permissions.ObjectArn("repo1", "data/file")}
+	node := permissions.WithActionsConfig(permissions.Node{
+		Type: permissions.NodeTypeOr,
+		Nodes: []permissions.Node{
+			{Permission: other},
+			{Permission: config},
+		},
+	})
+	if node.Type != permissions.NodeTypeOr || len(node.Nodes) != 2 {
+		t.Fatalf("WithActionsConfig changed the node to %+v", node)
+	}
+	if node.Nodes[0].Type != permissions.NodeTypeNode || node.Nodes[0].Permission != other {
+		t.Errorf("WithActionsConfig changed a permission outside the actions configuration to %+v", node.Nodes[0])
+	}
+	configNode := node.Nodes[1]
+	if configNode.Type != permissions.NodeTypeAnd || len(configNode.Nodes) != 2 {
+		t.Fatalf("WithActionsConfig(%+v)=%+v, expected both permissions to be required", config, configNode)
+	}
+	expected := permissions.Permission{Action: permissions.WriteActionsConfigAction, Resource: config.Resource}
+	if configNode.Nodes[0].Permission != config || configNode.Nodes[1].Permission != expected {
+		t.Errorf("WithActionsConfig(%+v)=%+v, expected %+v and %+v", config, configNode, config, expected)
+	}
+}
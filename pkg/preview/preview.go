@@ -0,0 +1,461 @@
+// Package preview parses the first rows of tabular objects (CSV, JSON lines and Parquet) into a common table
+// structure with an inferred schema.
+package preview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatTSV     Format = "tsv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+
+	// MaxRows is the maximal number of rows returned by a preview
+	MaxRows = 1000
+	// DefaultRows is the number of rows returned when no number is requested
+	DefaultRows = 100
+	// MaxReadBytes is the maximal number of bytes read from CSV and JSON lines objects
+	MaxReadBytes = 8 * 1024 * 1024
+	// MaxParquetSize is the maximal size of a Parquet object that can be previewed: the whole object is read to
+	// reach its footer
+	MaxParquetSize = 64 * 1024 * 1024
+)
+
+// Column types inferred from the previewed values
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeNull    = "null"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported preview format")
+	ErrObjectTooLarge    = errors.New("object too large to preview")
+)
+
+type Column struct {
+	Name string
+	Type string
+}
+
+// Table is the preview of an object. Each row holds a value for each column.
+type Table struct {
+	Format  Format
+	Columns []Column
+	Rows    [][]interface{}
+	// Truncated is true when the object has rows after the previewed ones, or more data than was read
+	Truncated bool
+}
+
+// DetectFormat returns the format of an object by its path extension, or by its content type
+func DetectFormat(objectPath, contentType string) (Format, error) {
+	switch strings.ToLower(path.Ext(objectPath)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".tsv":
+		return FormatTSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	case ".parquet":
+		return FormatParquet, nil
+	}
+	switch contentType {
+	case "text/csv":
+		return FormatCSV, nil
+	case "text/tab-separated-values":
+		return FormatTSV, nil
+	case "application/x-ndjson", "application/jsonl":
+		return FormatJSONL, nil
+	case "application/vnd.apache.parquet", "application/x-parquet":
+		return FormatParquet, nil
+	}
+	return "", fmt.Errorf("%s: %w", objectPath, ErrUnsupportedFormat)
+}
+
+// Preview parses at most rows rows of an object of size bytes in format
+func Preview(r io.Reader, size int64, format Format, rows int) (*Table, error) {
+	if rows <= 0 {
+		rows = DefaultRows
+	}
+	if rows > MaxRows {
+		rows = MaxRows
+	}
+	switch format {
+	case FormatCSV:
+		return previewCSV(r, size, rows, FormatCSV, ',')
+	case FormatTSV:
+		return previewCSV(r, size, rows, FormatTSV, '\t')
+	case FormatJSONL:
+		return previewJSONL(r, size, rows)
+	case FormatParquet:
+		return previewParquet(r, size, rows)
+	default:
+		return nil, fmt.Errorf("%s: %w", format, ErrUnsupportedFormat)
+	}
+}
+
+// previewCSV reads the header and the first rows. A row cut at the end of the read bytes is dropped.
+func previewCSV(r io.Reader, size int64, rows int, format Format, comma rune) (*Table, error) {
+	limited := &io.LimitedReader{R: r, N: MaxReadBytes}
+	cr := csv.NewReader(limited)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	t := &Table{Format: format}
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	// read one more row to know whether rows are left
+	var records [][]string
+	for len(records) <= rows {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if limited.N <= 0 {
+				t.Truncated = true
+				break
+			}
+			return nil, fmt.Errorf("read row %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) > rows {
+		t.Truncated = true
+		records = records[:rows]
+	}
+	if limited.N <= 0 && size > MaxReadBytes {
+		// the last row read may have been cut
+		t.Truncated = true
+	}
+	types := make([]string, len(header))
+	for i, name := range header {
+		var values []string
+		for _, record := range records {
+			if i < len(record) {
+				values = append(values, record[i])
+			}
+		}
+		types[i] = inferStringType(values)
+		t.Columns = append(t.Columns, Column{Name: name, Type: types[i]})
+	}
+	for _, record := range records {
+		row := make([]interface{}, len(header))
+		for i := range header {
+			if i < len(record) {
+				row[i] = parseString(record[i], types[i])
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
+
+// inferStringType returns the narrowest type all non-empty values parse as
+func inferStringType(values []string) string {
+	typ := ""
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		switch {
+		case typ != TypeNumber && typ != TypeBoolean && typ != TypeString && isInteger(v):
+			typ = TypeInteger
+		case (typ == "" || typ == TypeInteger || typ == TypeNumber) && isNumber(v):
+			typ = TypeNumber
+		case (typ == "" || typ == TypeBoolean) && isBoolean(v):
+			typ = TypeBoolean
+		default:
+			return TypeString
+		}
+	}
+	if typ == "" {
+		return TypeString
+	}
+	return typ
+}
+
+func isInteger(v string) bool {
+	_, err := strconv.ParseInt(v, 10, 64)
+	return err == nil
+}
+
+func isNumber(v string) bool {
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+func isBoolean(v string) bool {
+	_, err := strconv.ParseBool(v)
+	return err == nil && !isNumber(v)
+}
+
+func parseString(v, typ string) interface{} {
+	if v == "" && typ != TypeString {
+		return nil
+	}
+	switch typ {
+	case TypeInteger:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case TypeNumber:
+		n, _ := strconv.ParseFloat(v, 64)
+		return n
+	case TypeBoolean:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return v
+	}
+}
+
+// previewJSONL reads the first lines, each a JSON object. Columns are the object keys, ordered by their first
+// appearance.
+func previewJSONL(r io.Reader, size int64, rows int) (*Table, error) {
+	scanner := bufio.NewScanner(io.LimitReader(r, MaxReadBytes))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxReadBytes)
+	t := &Table{Format: FormatJSONL}
+	columnIndex := make(map[string]int)
+	var objects []map[string]json.RawMessage
+	var read int64
+	// read one more line to know whether rows are left
+	for len(objects) <= rows && scanner.Scan() {
+		line := scanner.Bytes()
+		read += int64(len(line)) + 1
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(line, &obj); err != nil {
+			if read >= MaxReadBytes {
+				// the last line was cut
+				t.Truncated = true
+				break
+			}
+			return nil, fmt.Errorf("line %d: %w", len(objects)+1, err)
+		}
+		if len(objects) == rows {
+			t.Truncated = true
+			break
+		}
+		// keys order is not kept by unmarshal, read it from the line
+		for _, key := range objectKeys(line) {
+			if _, ok := columnIndex[key]; !ok {
+				columnIndex[key] = len(t.Columns)
+				t.Columns = append(t.Columns, Column{Name: key})
+			}
+		}
+		objects = append(objects, obj)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read json lines: %w", err)
+	}
+	if read >= MaxReadBytes && size > MaxReadBytes {
+		t.Truncated = true
+	}
+	for _, obj := range objects {
+		row := make([]interface{}, len(t.Columns))
+		for key, raw := range obj {
+			var v interface{}
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, err
+			}
+			i := columnIndex[key]
+			row[i] = v
+			t.Columns[i].Type = mergeTypes(t.Columns[i].Type, jsonType(v))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	for i := range t.Columns {
+		if t.Columns[i].Type == "" {
+			t.Columns[i].Type = TypeNull
+		}
+	}
+	return t, nil
+}
+
+// objectKeys returns the top level keys of a JSON object, in order
+func objectKeys(line []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return keys
+		}
+		keys = append(keys, key)
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return keys
+		}
+	}
+	return keys
+}
+
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case float64:
+		if val == float64(int64(val)) {
+			return TypeInteger
+		}
+		return TypeNumber
+	case string:
+		return TypeString
+	case []interface{}:
+		return TypeArray
+	default:
+		return TypeObject
+	}
+}
+
+// mergeTypes returns a type that describes values of both types
+func mergeTypes(a, b string) string {
+	switch {
+	case a == "" || a == TypeNull || a == b:
+		return b
+	case b == TypeNull:
+		return a
+	case (a == TypeInteger && b == TypeNumber) || (a == TypeNumber && b == TypeInteger):
+		return TypeNumber
+	default:
+		return TypeString
+	}
+}
+
+// previewParquet reads the whole object, the schema and row groups are found from its footer
+func previewParquet(r io.Reader, size int64, rows int) (*Table, error) {
+	if size > MaxParquetSize {
+		return nil, fmt.Errorf("%d bytes: %w", size, ErrObjectTooLarge)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, MaxParquetSize))
+	if err != nil {
+		return nil, err
+	}
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(data), nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("read parquet footer: %w", err)
+	}
+	defer pr.ReadStop()
+
+	t := &Table{Format: FormatParquet}
+	schema := pr.Footer.GetSchema()
+	if len(schema) == 0 {
+		return t, nil
+	}
+	// top level columns are the children of the root element, skipping the children of nested groups
+	for i := 1; i < len(schema); {
+		elem := schema[i]
+		t.Columns = append(t.Columns, Column{Name: elem.GetName(), Type: parquetType(elem)})
+		i += 1 + countDescendants(schema[i:])
+	}
+	numRows := pr.GetNumRows()
+	n := rows
+	if int64(n) > numRows {
+		n = int(numRows)
+	}
+	t.Truncated = numRows > int64(n)
+	if n == 0 {
+		return t, nil
+	}
+	values, err := pr.ReadByNumber(n)
+	if err != nil {
+		return nil, fmt.Errorf("read parquet rows: %w", err)
+	}
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		for v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		row := make([]interface{}, len(t.Columns))
+		for i := 0; i < len(row) && v.Kind() == reflect.Struct && i < v.NumField(); i++ {
+			row[i] = plainValue(v.Field(i))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
+
+// countDescendants returns the number of schema elements nested under the first element
+func countDescendants(schema []*parquet.SchemaElement) int {
+	count := 0
+	pending := int(schema[0].GetNumChildren())
+	for i := 1; pending > 0 && i < len(schema); i++ {
+		count++
+		pending += int(schema[i].GetNumChildren()) - 1
+	}
+	return count
+}
+
+// parquetType returns the column type of a schema element
+func parquetType(elem *parquet.SchemaElement) string {
+	if elem.ConvertedType != nil {
+		switch *elem.ConvertedType {
+		case parquet.ConvertedType_LIST:
+			return TypeArray
+		case parquet.ConvertedType_MAP, parquet.ConvertedType_MAP_KEY_VALUE:
+			return TypeObject
+		case parquet.ConvertedType_UTF8, parquet.ConvertedType_ENUM, parquet.ConvertedType_JSON:
+			return TypeString
+		}
+	}
+	if elem.GetNumChildren() > 0 || elem.Type == nil {
+		return TypeObject
+	}
+	switch *elem.Type {
+	case parquet.Type_BOOLEAN:
+		return TypeBoolean
+	case parquet.Type_INT32, parquet.Type_INT64:
+		return TypeInteger
+	case parquet.Type_FLOAT, parquet.Type_DOUBLE:
+		return TypeNumber
+	default:
+		return TypeString
+	}
+}
+
+func plainValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
@@ -0,0 +1,169 @@
+package preview_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/preview"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path        string
+		contentType string
+		expected    preview.Format
+		expectedErr error
+	}{
+		{path: "data/a.csv", expected: preview.FormatCSV},
+		{path: "data/a.TSV", expected: preview.FormatTSV},
+		{path: "data/a.jsonl", expected: preview.FormatJSONL},
+		{path: "data/a.parquet", expected: preview.FormatParquet},
+		{path: "data/a", contentType: "text/csv", expected: preview.FormatCSV},
+		{path: "data/a.bin", contentType: "application/octet-stream", expectedErr: preview.ErrUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			format, err := preview.DetectFormat(tt.path, tt.contentType)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("DetectFormat() err=%v, expected %v", err, tt.expectedErr)
+			}
+			if format != tt.expected {
+				t.Errorf("DetectFormat() = %s, expected %s", format, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreview_CSV(t *testing.T) {
+	data := "id,name,score,active\n1,a,1.5,true\n2,b,2,false\n3,,,\n"
+	tests := []struct {
+		name      string
+		rows      int
+		expected  [][]interface{}
+		truncated bool
+	}{
+		{
+			name: "all",
+			rows: 10,
+			expected: [][]interface{}{
+				{int64(1), "a", 1.5, true},
+				{int64(2), "b", 2.0, false},
+				{int64(3), "", nil, nil},
+			},
+		},
+		{
+			name: "exact",
+			rows: 3,
+			expected: [][]interface{}{
+				{int64(1), "a", 1.5, true},
+				{int64(2), "b", 2.0, false},
+				{int64(3), "", nil, nil},
+			},
+		},
+		{
+			name:      "first",
+			rows:      1,
+			expected:  [][]interface{}{{int64(1), "a", 1.5, true}},
+			truncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := preview.Preview(strings.NewReader(data), int64(len(data)), preview.FormatCSV, tt.rows)
+			if err != nil {
+				t.Fatalf("Preview() err=%s", err)
+			}
+			expectedColumns := []preview.Column{
+				{Name: "id", Type: preview.TypeInteger},
+				{Name: "name", Type: preview.TypeString},
+				{Name: "score", Type: preview.TypeNumber},
+				{Name: "active", Type: preview.TypeBoolean},
+			}
+			if diff := deep.Equal(table.Columns, expectedColumns); diff != nil {
+				t.Error("Preview() columns diff", diff)
+			}
+			if diff := deep.Equal(table.Rows, tt.expected); diff != nil {
+				t.Error("Preview() rows diff", diff)
+			}
+			if table.Truncated != tt.truncated {
+				t.Errorf("Preview() truncated=%t, expected %t", table.Truncated, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestPreview_JSONL(t *testing.T) {
+	data := `{"id": 1, "tags": ["a"], "score": 1}
+{"id": 2, "score": 2.5, "extra": {"k": "v"}}
+
+{"id": 3, "late": true}
+`
+	table, err := preview.Preview(strings.NewReader(data), int64(len(data)), preview.FormatJSONL, 2)
+	if err != nil {
+		t.Fatalf("Preview() err=%s", err)
+	}
+	expectedColumns := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "tags", Type: preview.TypeArray},
+		{Name: "score", Type: preview.TypeNumber},
+		{Name: "extra", Type: preview.TypeObject},
+	}
+	if diff := deep.Equal(table.Columns, expectedColumns); diff != nil {
+		t.Error("Preview() columns diff", diff)
+	}
+	expectedRows := [][]interface{}{
+		{1.0, []interface{}{"a"}, 1.0, nil},
+		{2.0, nil, 2.5, map[string]interface{}{"k": "v"}},
+	}
+	if diff := deep.Equal(table.Rows, expectedRows); diff != nil {
+		t.Error("Preview() rows diff", diff)
+	}
+	if !table.Truncated {
+		t.Error("Preview() of 2 out of 3 lines is not truncated")
+	}
+}
+
+type parquetRow struct {
+	ID    int64   `parquet:"name=id, type=INT64"`
+	Name  string  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Score float64 `parquet:"name=score, type=DOUBLE"`
+}
+
+func TestPreview_Parquet(t *testing.T) {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(parquetRow), 1)
+	if err != nil {
+		t.Fatalf("NewParquetWriterFromWriter: %s", err)
+	}
+	for _, row := range []parquetRow{{ID: 1, Name: "a", Score: 0.5}, {ID: 2, Name: "b", Score: 1.5}} {
+		if err := pw.Write(row); err != nil {
+			t.Fatalf("Write: %s", err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("WriteStop: %s", err)
+	}
+
+	table, err := preview.Preview(bytes.NewReader(buf.Bytes()), int64(buf.Len()), preview.FormatParquet, 1)
+	if err != nil {
+		t.Fatalf("Preview() err=%s", err)
+	}
+	expectedColumns := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "name", Type: preview.TypeString},
+		{Name: "score", Type: preview.TypeNumber},
+	}
+	if diff := deep.Equal(table.Columns, expectedColumns); diff != nil {
+		t.Error("Preview() columns diff", diff)
+	}
+	if diff := deep.Equal(table.Rows, [][]interface{}{{int64(1), "a", 0.5}}); diff != nil {
+		t.Error("Preview() rows diff", diff)
+	}
+	if !table.Truncated {
+		t.Error("Preview() of 1 out of 2 rows is not truncated")
+	}
+}
@@ -0,0 +1,335 @@
+package preview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// MaxSampleRows is the maximal number of rows drawn by a Sampler
+const MaxSampleRows = 10000
+
+var ErrReadOnly = errors.New("read only")
+
+// Sampler draws a uniform sample of rows from the objects added to it. Adding the same objects in the same order to
+// samplers of the same seed draws the same sample.
+//
+// Rows are drawn by reservoir sampling (Algorithm L), which computes the number of rows to skip until the next
+// sampled one: rows of CSV and JSON lines objects are scanned but only sampled rows are parsed, Parquet pages and row
+// groups with no sampled rows are not decoded.
+type Sampler struct {
+	format      Format
+	size        int
+	rng         *rand.Rand
+	columns     []Column
+	columnIndex map[string]int
+	// sample rows hold a value for each column known when the row was sampled, CSV values are kept as strings until
+	// the column types are inferred
+	sample [][]interface{}
+	seen   int64
+	next   int64
+	w      float64
+}
+
+func NewSampler(format Format, rows int, seed int64) *Sampler {
+	if rows <= 0 {
+		rows = DefaultRows
+	}
+	if rows > MaxSampleRows {
+		rows = MaxSampleRows
+	}
+	return &Sampler{
+		format:      format,
+		size:        rows,
+		rng:         rand.New(rand.NewSource(seed)), //nolint:gosec
+		columnIndex: make(map[string]int),
+	}
+}
+
+// Seen returns the number of rows of the objects added
+func (s *Sampler) Seen() int64 {
+	return s.seen
+}
+
+// random returns a random number in (0, 1]
+func (s *Sampler) random() float64 {
+	return 1 - s.rng.Float64()
+}
+
+// take returns the sample slot of the next row, or -1 when the row is not sampled
+func (s *Sampler) take() int {
+	i := s.seen
+	s.seen++
+	if i < int64(s.size) {
+		s.sample = append(s.sample, nil)
+		if i == int64(s.size)-1 {
+			s.w = math.Exp(math.Log(s.random()) / float64(s.size))
+			s.advance(i)
+		}
+		return int(i)
+	}
+	if i != s.next {
+		return -1
+	}
+	slot := s.rng.Intn(s.size)
+	s.w *= math.Exp(math.Log(s.random()) / float64(s.size))
+	s.advance(i)
+	return slot
+}
+
+// advance draws the index of the next sampled row after row i
+func (s *Sampler) advance(i int64) {
+	skip := math.Floor(math.Log(s.random()) / math.Log(1-s.w))
+	if math.IsNaN(skip) || skip > math.MaxInt64/2 {
+		skip = math.MaxInt64 / 2
+	}
+	s.next = i + int64(skip) + 1
+}
+
+// skip returns the number of rows that are not sampled before the next sampled row
+func (s *Sampler) skip() int64 {
+	if len(s.sample) < s.size {
+		return 0
+	}
+	return s.next - s.seen
+}
+
+func (s *Sampler) column(name, typ string) int {
+	i, ok := s.columnIndex[name]
+	if !ok {
+		i = len(s.columns)
+		s.columnIndex[name] = i
+		s.columns = append(s.columns, Column{Name: name})
+	}
+	if typ != "" {
+		s.columns[i].Type = mergeTypes(s.columns[i].Type, typ)
+	}
+	return i
+}
+
+// Add samples the rows of a CSV, TSV or JSON lines object
+func (s *Sampler) Add(r io.Reader) error {
+	switch s.format {
+	case FormatCSV:
+		return s.addCSV(r, ',')
+	case FormatTSV:
+		return s.addCSV(r, '\t')
+	case FormatJSONL:
+		return s.addJSONL(r)
+	default:
+		return fmt.Errorf("%s: %w", s.format, ErrUnsupportedFormat)
+	}
+}
+
+func (s *Sampler) addCSV(r io.Reader, comma rune) error {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	indexes := make([]int, len(header))
+	for i, name := range header {
+		indexes[i] = s.column(name, "")
+	}
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read row %d: %w", line, err)
+		}
+		slot := s.take()
+		if slot < 0 {
+			continue
+		}
+		row := make([]interface{}, len(s.columns))
+		for i, v := range record {
+			if i < len(indexes) {
+				row[indexes[i]] = v
+			}
+		}
+		s.sample[slot] = row
+	}
+}
+
+func (s *Sampler) addJSONL(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxReadBytes)
+	for line := 1; scanner.Scan(); line++ {
+		b := scanner.Bytes()
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+		slot := s.take()
+		if slot < 0 {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		// keys order is not kept by unmarshal, read it from the line
+		for _, key := range objectKeys(b) {
+			s.column(key, "")
+		}
+		row := make([]interface{}, len(s.columns))
+		for key, v := range obj {
+			row[s.column(key, "")] = v
+		}
+		s.sample[slot] = row
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read json lines: %w", err)
+	}
+	return nil
+}
+
+// AddParquet samples the rows of a Parquet object of size bytes. Only the footer and the pages of sampled rows are
+// read from r.
+func (s *Sampler) AddParquet(r io.ReaderAt, size int64) error {
+	if s.format != FormatParquet {
+		return fmt.Errorf("%s: %w", FormatParquet, ErrUnsupportedFormat)
+	}
+	pr, err := reader.NewParquetReader(newReaderAtFile(r, size), nil, 1)
+	if err != nil {
+		return fmt.Errorf("read parquet footer: %w", err)
+	}
+	defer pr.ReadStop()
+
+	schema := pr.Footer.GetSchema()
+	var indexes []int
+	for i := 1; i < len(schema); {
+		elem := schema[i]
+		indexes = append(indexes, s.column(elem.GetName(), parquetType(elem)))
+		i += 1 + countDescendants(schema[i:])
+	}
+	numRows := pr.GetNumRows()
+	for read := int64(0); read < numRows; {
+		if skip := s.skip(); skip > 0 {
+			if skip >= numRows-read {
+				s.seen += numRows - read
+				return nil
+			}
+			if err := pr.SkipRows(skip); err != nil {
+				return fmt.Errorf("skip parquet rows: %w", err)
+			}
+			s.seen += skip
+			read += skip
+		}
+		// read the rows that fill the sample together, then one sampled row at a time
+		n := int64(s.size - len(s.sample))
+		if n < 1 {
+			n = 1
+		}
+		if n > numRows-read {
+			n = numRows - read
+		}
+		values, err := pr.ReadByNumber(int(n))
+		if err != nil {
+			return fmt.Errorf("read parquet rows: %w", err)
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		for _, value := range values {
+			read++
+			v := reflect.ValueOf(value)
+			for v.Kind() == reflect.Ptr {
+				v = v.Elem()
+			}
+			row := make([]interface{}, len(s.columns))
+			for i := 0; i < len(indexes) && v.Kind() == reflect.Struct && i < v.NumField(); i++ {
+				row[indexes[i]] = plainValue(v.Field(i))
+			}
+			s.sample[s.take()] = row
+		}
+	}
+	return nil
+}
+
+// Table returns the sampled rows. Truncated is true when rows were not sampled.
+func (s *Sampler) Table() *Table {
+	t := &Table{
+		Format:    s.format,
+		Columns:   make([]Column, len(s.columns)),
+		Rows:      make([][]interface{}, len(s.sample)),
+		Truncated: s.seen > int64(len(s.sample)),
+	}
+	copy(t.Columns, s.columns)
+	for i, row := range s.sample {
+		t.Rows[i] = make([]interface{}, len(t.Columns))
+		copy(t.Rows[i], row)
+	}
+	for i := range t.Columns {
+		switch s.format {
+		case FormatCSV, FormatTSV:
+			var values []string
+			for _, row := range t.Rows {
+				if v, ok := row[i].(string); ok {
+					values = append(values, v)
+				}
+			}
+			typ := inferStringType(values)
+			for _, row := range t.Rows {
+				if v, ok := row[i].(string); ok {
+					row[i] = parseString(v, typ)
+				}
+			}
+			t.Columns[i].Type = typ
+		case FormatJSONL:
+			typ := ""
+			for _, row := range t.Rows {
+				typ = mergeTypes(typ, jsonType(row[i]))
+			}
+			if typ == "" {
+				typ = TypeNull
+			}
+			t.Columns[i].Type = typ
+		}
+	}
+	return t
+}
+
+// readerAtFile is a read only source.ParquetFile of r
+type readerAtFile struct {
+	*io.SectionReader
+	r    io.ReaderAt
+	size int64
+}
+
+func newReaderAtFile(r io.ReaderAt, size int64) *readerAtFile {
+	return &readerAtFile{SectionReader: io.NewSectionReader(r, 0, size), r: r, size: size}
+}
+
+func (f *readerAtFile) Open(string) (source.ParquetFile, error) {
+	return newReaderAtFile(f.r, f.size), nil
+}
+
+func (f *readerAtFile) Create(string) (source.ParquetFile, error) {
+	return nil, ErrReadOnly
+}
+
+func (f *readerAtFile) Write([]byte) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (f *readerAtFile) Close() error {
+	return nil
+}
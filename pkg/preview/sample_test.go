@@ -0,0 +1,165 @@
+package preview_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/preview"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// csvObjects returns CSV objects of ids, count rows each
+func csvObjects(objects, count int) []string {
+	var result []string
+	for o := 0; o < objects; o++ {
+		var sb strings.Builder
+		sb.WriteString("id,name\n")
+		for i := 0; i < count; i++ {
+			id := o*count + i
+			fmt.Fprintf(&sb, "%d,name%d\n", id, id)
+		}
+		result = append(result, sb.String())
+	}
+	return result
+}
+
+func sampleIDs(t *testing.T, table *preview.Table) []int64 {
+	t.Helper()
+	ids := make([]int64, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		id, ok := row[0].(int64)
+		if !ok {
+			t.Fatalf("sampled id %v is not an integer", row[0])
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func sampleCSV(t *testing.T, objects []string, rows int, seed int64) *preview.Sampler {
+	t.Helper()
+	s := preview.NewSampler(preview.FormatCSV, rows, seed)
+	for _, object := range objects {
+		if err := s.Add(strings.NewReader(object)); err != nil {
+			t.Fatalf("Add() err=%s", err)
+		}
+	}
+	return s
+}
+
+func TestSampler_CSV(t *testing.T) {
+	objects := csvObjects(4, 250)
+	s := sampleCSV(t, objects, 20, 1)
+	if s.Seen() != 1000 {
+		t.Errorf("Seen()=%d, expected 1000", s.Seen())
+	}
+	table := s.Table()
+	expectedColumns := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "name", Type: preview.TypeString},
+	}
+	if diff := deep.Equal(table.Columns, expectedColumns); diff != nil {
+		t.Error("Table() columns diff", diff)
+	}
+	if !table.Truncated {
+		t.Error("Table() of 20 out of 1000 rows is not truncated")
+	}
+	ids := sampleIDs(t, table)
+	if len(ids) != 20 {
+		t.Fatalf("sampled %d rows, expected 20", len(ids))
+	}
+	seen := make(map[int64]bool)
+	var late bool
+	for i, id := range ids {
+		if seen[id] {
+			t.Errorf("row %d sampled twice", id)
+		}
+		seen[id] = true
+		if id >= 20 {
+			late = true
+		}
+		if table.Rows[i][1] != fmt.Sprintf("name%d", id) {
+			t.Errorf("sampled row %v does not match its id", table.Rows[i])
+		}
+	}
+	if !late {
+		t.Error("sampled only the first rows")
+	}
+
+	// the same seed draws the same sample
+	if diff := deep.Equal(sampleIDs(t, sampleCSV(t, objects, 20, 1).Table()), ids); diff != nil {
+		t.Error("sample with the same seed diff", diff)
+	}
+	if diff := deep.Equal(sampleIDs(t, sampleCSV(t, objects, 20, 2).Table()), ids); diff == nil {
+		t.Error("sample with another seed is the same")
+	}
+}
+
+func TestSampler_SmallObject(t *testing.T) {
+	data := `{"id": 1, "name": "a"}
+
+{"id": 2, "score": 1.5}
+`
+	s := preview.NewSampler(preview.FormatJSONL, 10, 0)
+	if err := s.Add(strings.NewReader(data)); err != nil {
+		t.Fatalf("Add() err=%s", err)
+	}
+	table := s.Table()
+	expectedColumns := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "name", Type: preview.TypeString},
+		{Name: "score", Type: preview.TypeNumber},
+	}
+	if diff := deep.Equal(table.Columns, expectedColumns); diff != nil {
+		t.Error("Table() columns diff", diff)
+	}
+	expectedRows := [][]interface{}{
+		{1.0, "a", nil},
+		{2.0, nil, 1.5},
+	}
+	if diff := deep.Equal(table.Rows, expectedRows); diff != nil {
+		t.Error("Table() rows diff", diff)
+	}
+	if table.Truncated {
+		t.Error("Table() of all rows is truncated")
+	}
+}
+
+type parquetIDRow struct {
+	ID int64 `parquet:"name=id, type=INT64"`
+}
+
+func TestSampler_Parquet(t *testing.T) {
+	const count = 1000
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(parquetIDRow), 1)
+	if err != nil {
+		t.Fatalf("NewParquetWriterFromWriter: %s", err)
+	}
+	// small row groups, so most are skipped
+	pw.RowGroupSize = 1024
+	for i := 0; i < count; i++ {
+		if err := pw.Write(parquetIDRow{ID: int64(i)}); err != nil {
+			t.Fatalf("Write: %s", err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("WriteStop: %s", err)
+	}
+
+	s := preview.NewSampler(preview.FormatParquet, 20, 1)
+	if err := s.AddParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		t.Fatalf("AddParquet() err=%s", err)
+	}
+	if s.Seen() != count {
+		t.Errorf("Seen()=%d, expected %d", s.Seen(), count)
+	}
+	// rows are drawn the same way for all formats
+	expected := sampleIDs(t, sampleCSV(t, csvObjects(1, count), 20, 1).Table())
+	if diff := deep.Equal(sampleIDs(t, s.Table()), expected); diff != nil {
+		t.Error("AddParquet() sample diff", diff)
+	}
+}
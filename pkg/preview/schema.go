@@ -0,0 +1,58 @@
+package preview
+
+import "io"
+
+type ColumnChangeType string
+
+const (
+	ColumnAdded   ColumnChangeType = "added"
+	ColumnRemoved ColumnChangeType = "removed"
+	ColumnRetyped ColumnChangeType = "retyped"
+)
+
+// ColumnChange is a difference between the columns of two schemas. LeftType is empty for added columns and
+// RightType is empty for removed columns.
+type ColumnChange struct {
+	Name      string
+	Type      ColumnChangeType
+	LeftType  string
+	RightType string
+}
+
+// Schema returns the columns of an object of size bytes in format. The types of CSV and JSON lines columns are
+// inferred from the first DefaultRows rows, Parquet types are read from the object schema.
+func Schema(r io.Reader, size int64, format Format) ([]Column, error) {
+	table, err := Preview(r, size, format, DefaultRows)
+	if err != nil {
+		return nil, err
+	}
+	return table.Columns, nil
+}
+
+// DiffSchemas returns the changes from the left columns to the right columns: added and retyped columns in the
+// right order, followed by removed columns in the left order. A column whose type is null on either side, e.g. a
+// JSON lines key that is null in all the sampled rows, is not reported as retyped.
+func DiffSchemas(left, right []Column) []ColumnChange {
+	leftTypes := make(map[string]string, len(left))
+	for _, c := range left {
+		leftTypes[c.Name] = c.Type
+	}
+	rightNames := make(map[string]struct{}, len(right))
+	var changes []ColumnChange
+	for _, c := range right {
+		rightNames[c.Name] = struct{}{}
+		leftType, ok := leftTypes[c.Name]
+		switch {
+		case !ok:
+			changes = append(changes, ColumnChange{Name: c.Name, Type: ColumnAdded, RightType: c.Type})
+		case leftType != c.Type && leftType != TypeNull && c.Type != TypeNull:
+			changes = append(changes, ColumnChange{Name: c.Name, Type: ColumnRetyped, LeftType: leftType, RightType: c.Type})
+		}
+	}
+	for _, c := range left {
+		if _, ok := rightNames[c.Name]; !ok {
+			changes = append(changes, ColumnChange{Name: c.Name, Type: ColumnRemoved, LeftType: c.Type})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,50 @@
+package preview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/preview"
+)
+
+func TestSchema_CSV(t *testing.T) {
+	data := "id,name\n1,a\n2,b\n"
+	columns, err := preview.Schema(strings.NewReader(data), int64(len(data)), preview.FormatCSV)
+	if err != nil {
+		t.Fatalf("Schema() err=%s", err)
+	}
+	expected := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "name", Type: preview.TypeString},
+	}
+	if diff := deep.Equal(columns, expected); diff != nil {
+		t.Error("Schema() diff", diff)
+	}
+}
+
+func TestDiffSchemas(t *testing.T) {
+	left := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "name", Type: preview.TypeString},
+		{Name: "score", Type: preview.TypeInteger},
+		{Name: "note", Type: preview.TypeNull},
+	}
+	right := []preview.Column{
+		{Name: "id", Type: preview.TypeInteger},
+		{Name: "score", Type: preview.TypeNumber},
+		{Name: "note", Type: preview.TypeString},
+		{Name: "active", Type: preview.TypeBoolean},
+	}
+	expected := []preview.ColumnChange{
+		{Name: "score", Type: preview.ColumnRetyped, LeftType: preview.TypeInteger, RightType: preview.TypeNumber},
+		{Name: "active", Type: preview.ColumnAdded, RightType: preview.TypeBoolean},
+		{Name: "name", Type: preview.ColumnRemoved, LeftType: preview.TypeString},
+	}
+	if diff := deep.Equal(preview.DiffSchemas(left, right), expected); diff != nil {
+		t.Error("DiffSchemas() diff", diff)
+	}
+	if changes := preview.DiffSchemas(left, left); len(changes) != 0 {
+		t.Errorf("DiffSchemas() of the same columns = %v, expected no changes", changes)
+	}
+}
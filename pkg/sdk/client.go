@@ -0,0 +1,124 @@
+// Package sdk is the supported Go client for lakeFS. It wraps the generated OpenAPI client with high-level
+// helpers for common workflows: uploading and downloading objects, walking listings, committing and merging,
+// and waiting for action runs.
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/securityprovider"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/version"
+)
+
+const (
+	DefaultMaxAttempts     = 4
+	DefaultMinWaitInterval = 200 * time.Millisecond
+	DefaultMaxWaitInterval = 30 * time.Second
+
+	// defaultMaxIdleConnsPerHost allows concurrent uploads and downloads to reuse connections
+	defaultMaxIdleConnsPerHost = 100
+)
+
+// RetryConfig controls retries of requests that failed on a transport error or a retryable status code
+type RetryConfig struct {
+	// MaxAttempts is the number of retries after the first attempt, 0 disables retries
+	MaxAttempts     int
+	MinWaitInterval time.Duration
+	MaxWaitInterval time.Duration
+}
+
+// Config holds the connection settings of a Client
+type Config struct {
+	// EndpointURL is the lakeFS server address, with or without the API base path
+	EndpointURL     string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PreSign transfers object content directly with the underlying storage using pre-signed URLs
+	PreSign bool
+	// Retries is used when set, otherwise requests are retried using the default retry settings
+	Retries *RetryConfig
+	// HTTPClient overrides the client used to send requests, Retries is ignored when set
+	HTTPClient *http.Client
+	// UserAgent is sent on each request, defaults to lakefs-sdk-go/<version>
+	UserAgent string
+}
+
+// Client is a lakeFS client providing high-level helpers. API exposes the generated client for any operation
+// not covered by a helper.
+type Client struct {
+	API        *apigen.ClientWithResponses
+	HTTPClient *http.Client
+	PreSign    bool
+}
+
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts:     DefaultMaxAttempts,
+		MinWaitInterval: DefaultMinWaitInterval,
+		MaxWaitInterval: DefaultMaxWaitInterval,
+	}
+}
+
+// NewClient returns a client connected to the lakeFS server described by cfg
+func NewClient(cfg Config) (*Client, error) {
+	endpoint, err := apiutil.NormalizeLakeFSEndpoint(cfg.EndpointURL)
+	if err != nil {
+		return nil, err
+	}
+	basicAuthProvider, err := securityprovider.NewSecurityProviderBasicAuth(cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		retries := cfg.Retries
+		if retries == nil {
+			retries = DefaultRetryConfig()
+		}
+		httpClient = newHTTPClient(*retries)
+	}
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = "lakefs-sdk-go/" + version.Version
+	}
+	client, err := apigen.NewClientWithResponses(
+		endpoint,
+		apigen.WithHTTPClient(httpClient),
+		apigen.WithRequestEditorFn(basicAuthProvider.Intercept),
+		apigen.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			req.Header.Set("User-Agent", userAgent)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("initialize API client: %w", err)
+	}
+	return &Client{
+		API:        client,
+		HTTPClient: httpClient,
+		PreSign:    cfg.PreSign,
+	}, nil
+}
+
+func newHTTPClient(retries RetryConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	if retries.MaxAttempts <= 0 {
+		return &http.Client{Transport: transport}
+	}
+	retryClient := retryablehttp.NewClient()
+	retryClient.HTTPClient.Transport = transport
+	retryClient.Logger = nil
+	retryClient.RetryMax = retries.MaxAttempts
+	retryClient.RetryWaitMin = retries.MinWaitInterval
+	retryClient.RetryWaitMax = retries.MaxWaitInterval
+	// return the last response instead of an error once retries are exhausted, so it can be reported as an API error
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	return retryClient.StandardClient()
+}
@@ -0,0 +1,9 @@
+package sdk
+
+import "errors"
+
+var (
+	ErrBadResponse   = errors.New("bad response from server")
+	ErrMergeConflict = errors.New("merge conflict")
+	ErrRunFailed     = errors.New("action run failed")
+)
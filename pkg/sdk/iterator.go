@@ -0,0 +1,167 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+// pageFetcher returns the page of results following after, along with its pagination
+type pageFetcher[T any] func(ctx context.Context, after string) ([]T, *apigen.Pagination, error)
+
+// Iterator lazily fetches the pages of a listing, requesting the next page only once the current one was
+// consumed. Iteration stops on the first error or once the context is canceled.
+//
+//	it := client.ListBranches(ctx, "example-repo", nil)
+//	for it.Next() {
+//		fmt.Println(it.Value().Id)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx     context.Context
+	fetch   pageFetcher[T]
+	page    []T
+	idx     int
+	after   string
+	hasMore bool
+	value   *T
+	err     error
+}
+
+func newIterator[T any](ctx context.Context, after string, fetch pageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:     ctx,
+		fetch:   fetch,
+		after:   after,
+		hasMore: true,
+	}
+}
+
+// Next advances the iterator to the next value, fetching the next page when needed. It returns false once
+// iteration is done or failed.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.setErr(err)
+		return false
+	}
+	for it.idx >= len(it.page) {
+		if !it.hasMore {
+			it.value = nil
+			return false
+		}
+		page, pagination, err := it.fetch(it.ctx, it.after)
+		if err != nil {
+			it.setErr(err)
+			return false
+		}
+		it.page = page
+		it.idx = 0
+		it.hasMore = pagination.HasMore && len(page) > 0
+		it.after = pagination.NextOffset
+	}
+	it.value = &it.page[it.idx]
+	it.idx++
+	return true
+}
+
+// Value returns the current value, valid until the next call to Next
+func (it *Iterator[T]) Value() *T {
+	return it.value
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+func (it *Iterator[T]) setErr(err error) {
+	it.err = err
+	it.value = nil
+	it.page = nil
+}
+
+// checkPage returns the error of a failed listing response
+func checkPage(resp interface{}, err error, hasJSON200 bool) error {
+	if err != nil {
+		return err
+	}
+	if err := helpers.ResponseAsError(resp); err != nil {
+		return err
+	}
+	if !hasJSON200 {
+		return ErrBadResponse
+	}
+	return nil
+}
+
+// ListObjects iterates over the objects on ref matching params. The After parameter sets where iteration
+// starts, it is replaced by the continuation token of each fetched page.
+func (c *Client) ListObjects(ctx context.Context, repository, ref string, params *apigen.ListObjectsParams) *Iterator[apigen.ObjectStats] {
+	p := apigen.ListObjectsParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, string(apiutil.Value(p.After)), func(ctx context.Context, after string) ([]apigen.ObjectStats, *apigen.Pagination, error) {
+		p.After = apiutil.Ptr(apigen.PaginationAfter(after))
+		resp, err := c.API.ListObjectsWithResponse(ctx, repository, ref, &p)
+		if err := checkPage(resp, err, resp != nil && resp.JSON200 != nil); err != nil {
+			return nil, nil, err
+		}
+		return resp.JSON200.Results, &resp.JSON200.Pagination, nil
+	})
+}
+
+// ListBranches iterates over the branches of the repository matching params
+func (c *Client) ListBranches(ctx context.Context, repository string, params *apigen.ListBranchesParams) *Iterator[apigen.Ref] {
+	p := apigen.ListBranchesParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, string(apiutil.Value(p.After)), func(ctx context.Context, after string) ([]apigen.Ref, *apigen.Pagination, error) {
+		p.After = apiutil.Ptr(apigen.PaginationAfter(after))
+		resp, err := c.API.ListBranchesWithResponse(ctx, repository, &p)
+		if err := checkPage(resp, err, resp != nil && resp.JSON200 != nil); err != nil {
+			return nil, nil, err
+		}
+		return resp.JSON200.Results, &resp.JSON200.Pagination, nil
+	})
+}
+
+// Log iterates over the commits reachable from ref matching params, most recent first
+func (c *Client) Log(ctx context.Context, repository, ref string, params *apigen.LogCommitsParams) *Iterator[apigen.Commit] {
+	p := apigen.LogCommitsParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, string(apiutil.Value(p.After)), func(ctx context.Context, after string) ([]apigen.Commit, *apigen.Pagination, error) {
+		p.After = apiutil.Ptr(apigen.PaginationAfter(after))
+		resp, err := c.API.LogCommitsWithResponse(ctx, repository, ref, &p)
+		if err := checkPage(resp, err, resp != nil && resp.JSON200 != nil); err != nil {
+			return nil, nil, err
+		}
+		return resp.JSON200.Results, &resp.JSON200.Pagination, nil
+	})
+}
+
+// Diff iterates over the differences between leftRef and rightRef matching params
+func (c *Client) Diff(ctx context.Context, repository, leftRef, rightRef string, params *apigen.DiffRefsParams) *Iterator[apigen.Diff] {
+	p := apigen.DiffRefsParams{}
+	if params != nil {
+		p = *params
+	}
+	return newIterator(ctx, string(apiutil.Value(p.After)), func(ctx context.Context, after string) ([]apigen.Diff, *apigen.Pagination, error) {
+		p.After = apiutil.Ptr(apigen.PaginationAfter(after))
+		resp, err := c.API.DiffRefsWithResponse(ctx, repository, leftRef, rightRef, &p)
+		if err := checkPage(resp, err, resp != nil && resp.JSON200 != nil); err != nil {
+			return nil, nil, err
+		}
+		return resp.JSON200.Results, &resp.JSON200.Pagination, nil
+	})
+}
@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+)
+
+var errFetch = errors.New("fetch failed")
+
+// fakePages serves the integers [0, total) in pages of size, failing on fetch number failOn when positive
+type fakePages struct {
+	total   int
+	size    int
+	failOn  int
+	fetches int
+}
+
+func (f *fakePages) fetch(_ context.Context, after string) ([]int, *apigen.Pagination, error) {
+	f.fetches++
+	if f.fetches == f.failOn {
+		return nil, nil, errFetch
+	}
+	start := 0
+	if after != "" {
+		n, err := strconv.Atoi(after)
+		if err != nil {
+			return nil, nil, err
+		}
+		start = n + 1
+	}
+	end := min(start+f.size, f.total)
+	page := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		page = append(page, i)
+	}
+	pagination := &apigen.Pagination{HasMore: end < f.total}
+	if len(page) > 0 {
+		pagination.NextOffset = strconv.Itoa(page[len(page)-1])
+	}
+	return page, pagination, nil
+}
+
+func TestIterator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all", func(t *testing.T) {
+		pages := &fakePages{total: 25, size: 10}
+		it := newIterator(ctx, "", pages.fetch)
+		expected := 0
+		for it.Next() {
+			if *it.Value() != expected {
+				t.Fatalf("Value() = %d, expected %d", *it.Value(), expected)
+			}
+			expected++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %s", err)
+		}
+		if expected != pages.total {
+			t.Errorf("iterated %d values, expected %d", expected, pages.total)
+		}
+		if pages.fetches != 3 {
+			t.Errorf("fetched %d pages, expected 3", pages.fetches)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		pages := &fakePages{total: 25, size: 10}
+		it := newIterator(ctx, "", pages.fetch)
+		for i := 0; i < 10; i++ {
+			if !it.Next() {
+				t.Fatalf("Next() done after %d values: %v", i, it.Err())
+			}
+		}
+		if pages.fetches != 1 {
+			t.Errorf("fetched %d pages after consuming the first, expected 1", pages.fetches)
+		}
+	})
+
+	t.Run("after", func(t *testing.T) {
+		pages := &fakePages{total: 25, size: 10}
+		it := newIterator(ctx, "19", pages.fetch)
+		if !it.Next() || *it.Value() != 20 {
+			t.Fatalf("first value after 19: %v, %v", it.Value(), it.Err())
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		pages := &fakePages{total: 0, size: 10}
+		it := newIterator(ctx, "", pages.fetch)
+		if it.Next() {
+			t.Fatalf("Next() on empty listing returned %d", *it.Value())
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %s", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		pages := &fakePages{total: 25, size: 10, failOn: 2}
+		it := newIterator(ctx, "", pages.fetch)
+		count := 0
+		for it.Next() {
+			count++
+		}
+		if !errors.Is(it.Err(), errFetch) {
+			t.Fatalf("Err() = %v, expected %s", it.Err(), errFetch)
+		}
+		if count != 10 {
+			t.Errorf("iterated %d values before failing, expected 10", count)
+		}
+		if it.Next() {
+			t.Error("Next() after failure returned true")
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		pages := &fakePages{total: 25, size: 10}
+		it := newIterator(ctx, "", pages.fetch)
+		for i := 0; i < 10; i++ {
+			it.Next()
+		}
+		cancel()
+		if it.Next() {
+			t.Fatal("Next() after cancel returned true")
+		}
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Fatalf("Err() = %v, expected %s", it.Err(), context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+// CommitAndMergeOptions holds optional parameters of CommitAndMerge
+type CommitAndMergeOptions struct {
+	// Metadata is set on both the commit and the merge commit
+	Metadata map[string]string
+	// AllowEmpty commits and merges even when the branch has no changes
+	AllowEmpty bool
+	// Strategy resolves merge conflicts: "dest-wins" or "source-wins". Conflicts fail the merge when empty.
+	Strategy string
+}
+
+// CommitAndMergeResult holds the references created by CommitAndMerge
+type CommitAndMergeResult struct {
+	Commit         *apigen.Commit
+	MergeReference string
+}
+
+// CommitAndMerge commits the staged changes of branch and merges it into destination. A merge conflict is
+// returned as ErrMergeConflict, leaving the commit on branch.
+func (c *Client) CommitAndMerge(ctx context.Context, repository, branch, destination, message string, opts *CommitAndMergeOptions) (*CommitAndMergeResult, error) {
+	if opts == nil {
+		opts = &CommitAndMergeOptions{}
+	}
+	commitResp, err := c.API.CommitWithResponse(ctx, repository, branch, &apigen.CommitParams{}, apigen.CommitJSONRequestBody{
+		Message:    message,
+		Metadata:   &apigen.CommitCreation_Metadata{AdditionalProperties: opts.Metadata},
+		AllowEmpty: &opts.AllowEmpty,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", branch, err)
+	}
+	if err := helpers.ResponseAsError(commitResp); err != nil {
+		return nil, fmt.Errorf("commit %s: %w", branch, err)
+	}
+	if commitResp.JSON201 == nil {
+		return nil, fmt.Errorf("commit %s: %w", branch, ErrBadResponse)
+	}
+
+	body := apigen.MergeIntoBranchJSONRequestBody{
+		Message:    &message,
+		Metadata:   &apigen.Merge_Metadata{AdditionalProperties: opts.Metadata},
+		AllowEmpty: &opts.AllowEmpty,
+	}
+	if opts.Strategy != "" {
+		body.Strategy = &opts.Strategy
+	}
+	mergeResp, err := c.API.MergeIntoBranchWithResponse(ctx, repository, branch, destination, body)
+	if err != nil {
+		return nil, fmt.Errorf("merge %s into %s: %w", branch, destination, err)
+	}
+	if mergeResp.JSON409 != nil {
+		return nil, fmt.Errorf("merge %s into %s: %w", branch, destination, ErrMergeConflict)
+	}
+	if err := helpers.ResponseAsError(mergeResp); err != nil {
+		return nil, fmt.Errorf("merge %s into %s: %w", branch, destination, err)
+	}
+	if mergeResp.JSON200 == nil {
+		return nil, fmt.Errorf("merge %s into %s: %w", branch, destination, ErrBadResponse)
+	}
+	return &CommitAndMergeResult{
+		Commit:         commitResp.JSON201,
+		MergeReference: mergeResp.JSON200.Reference,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/go-openapi/swag"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+	"github.com/treeverse/lakefs/pkg/uri"
+)
+
+// DefaultWalkPageSize is the number of objects fetched by each listing request while walking
+const DefaultWalkPageSize = 1000
+
+// ErrStopWalk may be returned by a WalkFunc to stop walking without failing
+var ErrStopWalk = errors.New("stop walk")
+
+// UploadOptions holds optional attributes of an uploaded object
+type UploadOptions struct {
+	ContentType string
+	Metadata    map[string]string
+	// DisableMultipart uploads pre-signed content in a single request, even when the server supports multipart
+	DisableMultipart bool
+}
+
+// WalkFunc is called on each object visited by WalkObjects
+type WalkFunc func(object apigen.ObjectStats) error
+
+// Upload stages the content as an object on the branch. With PreSign the content is uploaded directly to the
+// underlying storage, in parts when the server supports pre-signed multipart uploads.
+func (c *Client) Upload(ctx context.Context, repository, branch, path string, content io.ReadSeeker, opts *UploadOptions) (*apigen.ObjectStats, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	if !c.PreSign {
+		return helpers.ClientUpload(ctx, c.API, repository, branch, path, opts.Metadata, opts.ContentType, content)
+	}
+	multipartSupport := false
+	if !opts.DisableMultipart {
+		var err error
+		multipartSupport, err = c.presignMultipartSupport(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return helpers.ClientUploadPreSign(ctx, c.API, c.HTTPClient, repository, branch, path, opts.Metadata, opts.ContentType, content, multipartSupport)
+}
+
+func (c *Client) presignMultipartSupport(ctx context.Context) (bool, error) {
+	resp, err := c.API.GetConfigWithResponse(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := helpers.ResponseAsError(resp); err != nil {
+		return false, err
+	}
+	if resp.JSON200 == nil || resp.JSON200.StorageConfig == nil {
+		return false, nil
+	}
+	return swag.BoolValue(resp.JSON200.StorageConfig.PreSignMultipartUpload), nil
+}
+
+// Download writes the object at path on ref to the local file dst, creating its directory if needed. With
+// PreSign large objects are downloaded in concurrent ranges directly from the underlying storage.
+func (c *Client) Download(ctx context.Context, repository, ref, path, dst string) error {
+	downloader := helpers.NewDownloader(c.API, c.PreSign)
+	downloader.HTTPClient = c.HTTPClient
+	src := uri.URI{
+		Repository: repository,
+		Ref:        ref,
+		Path:       &path,
+	}
+	return downloader.Download(ctx, src, dst)
+}
+
+// WalkObjects calls fn on each object under prefix on ref, in lexicographical order. Listing stops on the first
+// error returned by fn, which is returned unless it is ErrStopWalk.
+func (c *Client) WalkObjects(ctx context.Context, repository, ref, prefix string, fn WalkFunc) error {
+	it := c.ListObjects(ctx, repository, ref, &apigen.ListObjectsParams{
+		Prefix: apiutil.Ptr(apigen.PaginationPrefix(prefix)),
+		Amount: apiutil.Ptr(apigen.PaginationAmount(DefaultWalkPageSize)),
+	})
+	for it.Next() {
+		if err := fn(*it.Value()); err != nil {
+			if errors.Is(err, ErrStopWalk) {
+				return nil
+			}
+			return err
+		}
+	}
+	return it.Err()
+}
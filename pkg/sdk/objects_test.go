@@ -0,0 +1,101 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/sdk"
+)
+
+// newListServer serves object listings of paths, pageSize objects at a time
+func newListServer(t *testing.T, paths []string, pageSize int) *httptest.Server {
+	t.Helper()
+	sort.Strings(paths)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repositories/repo/refs/main/objects/ls" {
+			http.NotFound(w, r)
+			return
+		}
+		after := r.URL.Query().Get("after")
+		i := sort.SearchStrings(paths, after)
+		if i < len(paths) && paths[i] == after {
+			i++
+		}
+		end := min(i+pageSize, len(paths))
+		list := apigen.ObjectStatsList{Results: []apigen.ObjectStats{}}
+		for _, p := range paths[i:end] {
+			list.Results = append(list.Results, apigen.ObjectStats{Path: p, PathType: "object"})
+		}
+		list.Pagination.HasMore = end < len(paths)
+		if list.Pagination.HasMore {
+			list.Pagination.NextOffset = paths[end-1]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_WalkObjects(t *testing.T) {
+	paths := make([]string, 0, 25)
+	for i := 0; i < 25; i++ {
+		paths = append(paths, fmt.Sprintf("data/%02d", i))
+	}
+	server := newListServer(t, paths, 10)
+	client, err := sdk.NewClient(sdk.Config{
+		EndpointURL: server.URL,
+		Retries:     &sdk.RetryConfig{},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	ctx := context.Background()
+
+	t.Run("all", func(t *testing.T) {
+		var walked []string
+		err := client.WalkObjects(ctx, "repo", "main", "", func(object apigen.ObjectStats) error {
+			walked = append(walked, object.Path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WalkObjects: %s", err)
+		}
+		if diff := deep.Equal(walked, paths); diff != nil {
+			t.Errorf("walked objects diff: %s", diff)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		var walked []string
+		err := client.WalkObjects(ctx, "repo", "main", "", func(object apigen.ObjectStats) error {
+			if len(walked) == 12 {
+				return sdk.ErrStopWalk
+			}
+			walked = append(walked, object.Path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WalkObjects: %s", err)
+		}
+		if diff := deep.Equal(walked, paths[:12]); diff != nil {
+			t.Errorf("walked objects diff: %s", diff)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := client.WalkObjects(ctx, "repo", "no-such-ref", "", func(apigen.ObjectStats) error {
+			return nil
+		})
+		if err == nil {
+			t.Fatal("WalkObjects on a missing ref succeeded, expected an error")
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/treeverse/lakefs/pkg/api/apigen"
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+	"github.com/treeverse/lakefs/pkg/api/helpers"
+)
+
+const (
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+
+	DefaultWaitForRunTimeout = 5 * time.Minute
+)
+
+var errRunNotFound = errors.New("run not found")
+
+// WaitForRun waits until the actions run triggered by eventType (e.g. "post-commit") on commitID is recorded,
+// and returns it. Post-event hooks run asynchronously, so their runs are recorded only after the triggering
+// operation returns. A failed run is returned along with ErrRunFailed.
+// A zero timeout waits up to DefaultWaitForRunTimeout.
+func (c *Client) WaitForRun(ctx context.Context, repository, commitID, eventType string, timeout time.Duration) (*apigen.ActionRun, error) {
+	if timeout <= 0 {
+		timeout = DefaultWaitForRunTimeout
+	}
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = timeout
+
+	var run *apigen.ActionRun
+	findRun := func() error {
+		resp, err := c.API.ListRepositoryRunsWithResponse(ctx, repository, &apigen.ListRepositoryRunsParams{
+			Commit: apiutil.Ptr(commitID),
+		})
+		if err != nil {
+			return err
+		}
+		if err := helpers.ResponseAsError(resp); err != nil {
+			return backoff.Permanent(err)
+		}
+		if resp.JSON200 == nil {
+			return backoff.Permanent(ErrBadResponse)
+		}
+		for i := range resp.JSON200.Results {
+			if resp.JSON200.Results[i].EventType == eventType {
+				run = &resp.JSON200.Results[i]
+				return nil
+			}
+		}
+		return errRunNotFound
+	}
+	if err := backoff.Retry(findRun, backoff.WithContext(bo, ctx)); err != nil {
+		if errors.Is(err, errRunNotFound) {
+			return nil, fmt.Errorf("%s run of commit %s: %w", eventType, commitID, err)
+		}
+		return nil, err
+	}
+	if run.Status == RunStatusFailed {
+		return run, fmt.Errorf("run %s: %w", run.RunId, ErrRunFailed)
+	}
+	return run, nil
+}
@@ -0,0 +1,162 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	kvAttributionPartition  = "attribution"
+	attributionHourlyPrefix = "hourly"
+	attributionHourFormat   = "2006010215"
+)
+
+var ErrInvalidAttributionKeyFormat = errors.New("invalid attribution key format")
+
+// AttributionKey identifies the requests of a user on a ref of a repository. Ref is empty for requests on the
+// repository itself.
+type AttributionKey struct {
+	Repository string
+	Ref        string
+	User       string
+}
+
+// AttributionRecord is the number of requests counted for a key during an hour
+type AttributionRecord struct {
+	AttributionKey
+	Hour  time.Time
+	Count int64
+}
+
+// attributionCounts counts requests by key until they are persisted
+var attributionCounts = struct {
+	sync.Mutex
+	counts map[AttributionKey]int64
+}{counts: make(map[AttributionKey]int64)}
+
+// RecordAttribution counts a request of user on ref of repository, for usage attribution reports
+func RecordAttribution(repository, ref, user string) {
+	key := AttributionKey{Repository: repository, Ref: ref, User: user}
+	attributionCounts.Lock()
+	attributionCounts.counts[key]++
+	attributionCounts.Unlock()
+}
+
+// resetAttributionCounts returns the requests counted since the last reset
+func resetAttributionCounts() map[AttributionKey]int64 {
+	attributionCounts.Lock()
+	defer attributionCounts.Unlock()
+	counts := attributionCounts.counts
+	attributionCounts.counts = make(map[AttributionKey]int64)
+	return counts
+}
+
+// AttributionRecorder persists request counts by repository, ref and user to hourly records
+type AttributionRecorder struct {
+	storage kv.Store
+}
+
+func NewAttributionRecorder(storage kv.Store) *AttributionRecorder {
+	return &AttributionRecorder{storage: storage}
+}
+
+// Start persists the request counts every interval, until ctx is done
+func (a *AttributionRecorder) Start(ctx context.Context, interval time.Duration, logger logging.Logger) {
+	if interval == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := a.Flush(ctx); err != nil {
+					logger.WithError(err).Error("failed to persist attribution counts")
+				}
+			}
+		}
+	}()
+}
+
+// Flush adds the request counts since the last flush to the record of the current hour
+func (a *AttributionRecorder) Flush(ctx context.Context) error {
+	hour := time.Now().UTC().Truncate(time.Hour)
+	var errs error
+	for key, count := range resetAttributionCounts() {
+		if err := addCount(ctx, a.storage, []byte(kvAttributionPartition), formatAttributionKey(hour, key), count); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// Records returns the hourly records of requests from the hour of start, until end
+func (a *AttributionRecorder) Records(ctx context.Context, start, end time.Time) ([]*AttributionRecord, error) {
+	it, err := kv.ScanPrefix(ctx, a.storage, []byte(kvAttributionPartition), []byte(attributionHourlyPrefix+kv.PathDelimiter), formatAttributionHourPrefix(start))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var records []*AttributionRecord
+	for it.Next() {
+		ent := it.Entry()
+		record, err := parseAttributionKey(ent.Key)
+		if err != nil {
+			return nil, err
+		}
+		if !record.Hour.Before(end) {
+			break
+		}
+		record.Count, err = strconv.ParseInt(string(ent.Value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse attribution record '%s': %w", ent.Key, err)
+		}
+		records = append(records, record)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func formatAttributionHourPrefix(hour time.Time) []byte {
+	return []byte(kv.FormatPath(attributionHourlyPrefix, hour.UTC().Format(attributionHourFormat)))
+}
+
+// formatAttributionKey formats the key of the record of key during hour. Key parts are escaped, as refs may hold '/'.
+func formatAttributionKey(hour time.Time, key AttributionKey) []byte {
+	return []byte(kv.FormatPath(attributionHourlyPrefix, hour.UTC().Format(attributionHourFormat),
+		url.PathEscape(key.Repository), url.PathEscape(key.Ref), url.PathEscape(key.User)))
+}
+
+func parseAttributionKey(key []byte) (*AttributionRecord, error) {
+	const keyParts = 5
+	parts := strings.Split(string(key), kv.PathDelimiter)
+	if len(parts) != keyParts || parts[0] != attributionHourlyPrefix {
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidAttributionKeyFormat, key)
+	}
+	hour, err := time.Parse(attributionHourFormat, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid attribution key '%s': %w", key, err)
+	}
+	record := &AttributionRecord{Hour: hour}
+	for i, field := range []*string{&record.Repository, &record.Ref, &record.User} {
+		*field, err = url.PathUnescape(parts[i+2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribution key '%s': %w", key, err)
+		}
+	}
+	return record, nil
+}
@@ -0,0 +1,68 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/kv"
+	"github.com/treeverse/lakefs/pkg/kv/kvparams"
+	_ "github.com/treeverse/lakefs/pkg/kv/mem"
+	"github.com/treeverse/lakefs/pkg/stats"
+)
+
+func TestAttributionRecorder(t *testing.T) {
+	ctx := context.Background()
+	storage, err := kv.Open(ctx, kvparams.Config{Type: "mem"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := stats.NewAttributionRecorder(storage)
+
+	const rounds = 2
+	for i := 0; i < rounds; i++ {
+		stats.RecordAttribution("repo1", "main", "alice")
+		stats.RecordAttribution("repo1", "feature/x", "alice")
+		stats.RecordAttribution("repo1", "main", "bob")
+		if err := recorder.Flush(ctx); err != nil {
+			t.Fatal("Flush() expected no error", err)
+		}
+	}
+	stats.RecordAttribution("repo2", "", "alice")
+	if err := recorder.Flush(ctx); err != nil {
+		t.Fatal("Flush() expected no error", err)
+	}
+
+	now := time.Now()
+	records, err := recorder.Records(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal("Records() expected no error", err)
+	}
+	counts := make(map[stats.AttributionKey]int64)
+	for _, rec := range records {
+		counts[rec.AttributionKey] += rec.Count
+	}
+	expected := map[stats.AttributionKey]int64{
+		{Repository: "repo1", Ref: "main", User: "alice"}:      rounds,
+		{Repository: "repo1", Ref: "feature/x", User: "alice"}: rounds,
+		{Repository: "repo1", Ref: "main", User: "bob"}:        rounds,
+		{Repository: "repo2", Ref: "", User: "alice"}:          1,
+	}
+	if len(counts) != len(expected) {
+		t.Fatalf("Records() got %v, expected %v", counts, expected)
+	}
+	for key, count := range expected {
+		if counts[key] != count {
+			t.Errorf("Records() %+v count %d, expected %d", key, counts[key], count)
+		}
+	}
+
+	// records outside the window are not returned
+	records, err = recorder.Records(ctx, now.Add(time.Hour), now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal("Records() expected no error", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("Records() after window got %d records, expected none", len(records))
+	}
+}
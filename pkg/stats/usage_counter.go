@@ -220,14 +220,18 @@ func (u *UsageReporter) persistUsageCounters(ctx context.Context, tm time.Time)
 func (u *UsageReporter) updateRecord(ctx context.Context, rec *UsageRecord) error {
 	// format the key we use to store the usage record
 	key := formatUsageKey(u.installationID, rec.Year, rec.Month)
+	return addCount(ctx, u.storage, []byte(kvUsagePartition), key, rec.Count)
+}
 
+// addCount adds delta to the count stored under key, creating it if not found
+func addCount(ctx context.Context, storage kv.Store, partition, key []byte, delta int64) error {
 	// use a backoff with jitter to retry on predicate failures
 	const updateRecordRetryDuration = 200 * time.Millisecond
 	bo := NewConstantWithJitterBackOff(updateRecordRetryDuration)
 	return backoff.Retry(func() error {
 		// get current value if found
 		var predicate kv.Predicate = nil
-		valueWithPredicate, err := u.storage.Get(ctx, []byte(kvUsagePartition), key)
+		valueWithPredicate, err := storage.Get(ctx, partition, key)
 		if err != nil {
 			// ignore not found error, we'll create a new record
 			if !errors.Is(err, kv.ErrNotFound) {
@@ -238,7 +242,7 @@ func (u *UsageReporter) updateRecord(ctx context.Context, rec *UsageRecord) erro
 		}
 
 		// updated value (calls + previous calls)
-		totalCount := rec.Count
+		totalCount := delta
 		if valueWithPredicate != nil {
 			curr, err := strconv.ParseInt(string(valueWithPredicate.Value), 10, 64)
 			if err != nil {
@@ -249,7 +253,7 @@ func (u *UsageReporter) updateRecord(ctx context.Context, rec *UsageRecord) erro
 
 		// save the updated value
 		totalCountStr := strconv.FormatInt(totalCount, 10)
-		err = u.storage.SetIf(ctx, []byte(kvUsagePartition), key, []byte(totalCountStr), predicate)
+		err = storage.SetIf(ctx, partition, key, []byte(totalCountStr), predicate)
 		if err != nil {
 			if errors.Is(err, kv.ErrPredicateFailed) {
 				return err // retry if predicate failed
@@ -9,8 +9,10 @@ import (
 )
 
 type Result struct {
-	Error error
-	Took  time.Duration
+	// Operation names the operation measured, for workers running more than one kind of operation
+	Operation string
+	Error     error
+	Took      time.Duration
 }
 
 type WorkFn func(input chan string, output chan Result)
@@ -111,7 +113,8 @@ func (g *Generator) Setup(fn GenerateFn) {
 
 // Run will start the worker goroutines and print out their
 // progress every second. Upon completion (or on a SIGTERM), will also print a latency histogram
-func (g *Generator) Run(fn WorkFn) {
+// and the latency percentiles of each operation, and return them as a report.
+func (g *Generator) Run(fn WorkFn) *Report {
 	go g.collector.Collect()
 	g.pool.Start(fn)
 
@@ -134,4 +137,9 @@ func (g *Generator) Run(fn WorkFn) {
 	}
 	fmt.Printf("%s\n\n", g.collector.Stats())
 	fmt.Printf("Histogram (ms):\n%s\n", g.collector.Histogram())
+	report := g.collector.Report()
+	report.Name = g.name
+	report.Parallelism = g.pool.parallelism
+	fmt.Printf("%s\n", report)
+	return report
 }
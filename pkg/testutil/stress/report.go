@@ -0,0 +1,110 @@
+package stress
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOperation is the operation of results that don't set one
+const DefaultOperation = "default"
+
+// ReportPercentiles are the latency percentiles reported for each operation
+var ReportPercentiles = []float64{50, 90, 95, 99}
+
+// OperationReport summarizes the results of one operation of a run. Latencies are of successful results.
+type OperationReport struct {
+	Operation        string             `json:"operation"`
+	Completed        int64              `json:"completed"`
+	Errors           int64              `json:"errors"`
+	ThroughputPerSec float64            `json:"throughput_per_second"`
+	LatencyMeanMs    float64            `json:"latency_mean_ms"`
+	LatencyMaxMs     float64            `json:"latency_max_ms"`
+	LatencyMs        map[string]float64 `json:"latency_percentiles_ms"`
+}
+
+// Report is the machine-readable result of a run
+type Report struct {
+	Name        string            `json:"name"`
+	StartTime   time.Time         `json:"start_time"`
+	Duration    time.Duration     `json:"duration_ns"`
+	Parallelism int               `json:"parallelism"`
+	Completed   int64             `json:"completed"`
+	Errors      int64             `json:"errors"`
+	Operations  []OperationReport `json:"operations"`
+}
+
+func (r *Report) String() string {
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("%s: %d completed, %d errors in %s\n", r.Name, r.Completed, r.Errors, r.Duration.Round(time.Millisecond)))
+	for _, op := range r.Operations {
+		builder.WriteString(fmt.Sprintf("%s\tcompleted: %d, errors: %d, %.2f/second, mean: %.2fms",
+			op.Operation, op.Completed, op.Errors, op.ThroughputPerSec, op.LatencyMeanMs))
+		for _, p := range ReportPercentiles {
+			key := percentileKey(p)
+			builder.WriteString(fmt.Sprintf(", %s: %.2fms", key, op.LatencyMs[key]))
+		}
+		builder.WriteString(fmt.Sprintf(", max: %.2fms\n", op.LatencyMaxMs))
+	}
+	return builder.String()
+}
+
+func percentileKey(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// Percentile returns the p-th percentile of sorted durations, using the nearest-rank method
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted)))) //nolint:mnd
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type operationResults struct {
+	completed int64
+	errors    int64
+	latencies []time.Duration
+}
+
+func (o *operationResults) report(name string, elapsed time.Duration) OperationReport {
+	latencies := make([]time.Duration, len(o.latencies))
+	copy(latencies, o.latencies)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	r := OperationReport{
+		Operation: name,
+		Completed: o.completed,
+		Errors:    o.errors,
+		LatencyMs: make(map[string]float64, len(ReportPercentiles)),
+	}
+	if elapsed > 0 {
+		r.ThroughputPerSec = float64(o.completed) / elapsed.Seconds()
+	}
+	if len(latencies) > 0 {
+		var total time.Duration
+		for _, l := range latencies {
+			total += l
+		}
+		r.LatencyMeanMs = durationMs(total / time.Duration(len(latencies)))
+		r.LatencyMaxMs = durationMs(latencies[len(latencies)-1])
+	}
+	for _, p := range ReportPercentiles {
+		r.LatencyMs[percentileKey(p)] = durationMs(Percentile(latencies, p))
+	}
+	return r
+}
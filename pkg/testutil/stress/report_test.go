@@ -0,0 +1,71 @@
+package stress_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/pkg/testutil/stress"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 0, 100)
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tbl := []struct {
+		Name       string
+		Durations  []time.Duration
+		Percentile float64
+		Expected   time.Duration
+	}{
+		{Name: "empty", Durations: nil, Percentile: 50, Expected: 0},
+		{Name: "single", Durations: []time.Duration{time.Second}, Percentile: 99, Expected: time.Second},
+		{Name: "p50", Durations: sorted, Percentile: 50, Expected: 50 * time.Millisecond},
+		{Name: "p99", Durations: sorted, Percentile: 99, Expected: 99 * time.Millisecond},
+		{Name: "p100", Durations: sorted, Percentile: 100, Expected: 100 * time.Millisecond},
+		{Name: "p0", Durations: sorted, Percentile: 0, Expected: time.Millisecond},
+	}
+	for _, example := range tbl {
+		t.Run(example.Name, func(t *testing.T) {
+			if got := stress.Percentile(example.Durations, example.Percentile); got != example.Expected {
+				t.Fatalf("expected %s, got %s", example.Expected, got)
+			}
+		})
+	}
+}
+
+func TestResultCollector_Report(t *testing.T) {
+	results := make(chan stress.Result)
+	collector := stress.NewResultCollector(results)
+	go collector.Collect()
+
+	for i := 1; i <= 4; i++ {
+		results <- stress.Result{Operation: "read", Took: time.Duration(i) * time.Millisecond}
+	}
+	results <- stress.Result{Operation: "write", Error: errors.New("failed"), Took: time.Second}
+	results <- stress.Result{Took: 10 * time.Millisecond}
+
+	report := collector.Report()
+	if report.Completed != 6 || report.Errors != 1 {
+		t.Fatalf("expected 6 completed and 1 error, got %d completed and %d errors", report.Completed, report.Errors)
+	}
+	if len(report.Operations) != 3 {
+		t.Fatalf("expected 3 operations, got %+v", report.Operations)
+	}
+	// operations are sorted by name
+	def, read, write := report.Operations[0], report.Operations[1], report.Operations[2]
+	if def.Operation != stress.DefaultOperation || def.Completed != 1 || def.LatencyMaxMs != 10 {
+		t.Errorf("unexpected default operation report %+v", def)
+	}
+	if read.Operation != "read" || read.Completed != 4 || read.Errors != 0 {
+		t.Errorf("unexpected read report %+v", read)
+	}
+	if read.LatencyMs["p50"] != 2 || read.LatencyMs["p99"] != 4 || read.LatencyMaxMs != 4 || read.LatencyMeanMs != 2.5 {
+		t.Errorf("unexpected read latencies %+v", read)
+	}
+	// failed results count as errors, but are not part of the latencies
+	if write.Operation != "write" || write.Completed != 1 || write.Errors != 1 || write.LatencyMaxMs != 0 {
+		t.Errorf("unexpected write report %+v", write)
+	}
+}
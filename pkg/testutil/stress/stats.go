@@ -2,6 +2,7 @@ package stress
 
 import (
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -10,6 +11,7 @@ type collectorRequest int
 const (
 	collectorRequestStats collectorRequest = iota
 	collectorRequestHistogram
+	collectorRequestReport
 )
 
 type Stats struct {
@@ -33,10 +35,13 @@ type ResultCollector struct {
 	requests   chan collectorRequest
 	stats      chan *Stats
 	histograms chan *Histogram
+	reports    chan *Report
 
 	// collected stats
+	startTime        time.Time
 	lastFlush        time.Time
 	histogram        *Histogram
+	operations       map[string]*operationResults
 	totalCompleted   int64
 	totalErrors      int64
 	currentCompleted int64
@@ -61,21 +66,57 @@ func (rc *ResultCollector) Histogram() *Histogram {
 	return <-rc.histograms
 }
 
+// Report summarizes the results collected so far by operation
+func (rc *ResultCollector) Report() *Report {
+	rc.requests <- collectorRequestReport
+	return <-rc.reports
+}
+
+func (rc *ResultCollector) report() *Report {
+	elapsed := time.Since(rc.startTime)
+	r := &Report{
+		StartTime:  rc.startTime,
+		Duration:   elapsed,
+		Completed:  rc.totalCompleted,
+		Errors:     rc.totalErrors,
+		Operations: make([]OperationReport, 0, len(rc.operations)),
+	}
+	for name, op := range rc.operations {
+		r.Operations = append(r.Operations, op.report(name, elapsed))
+	}
+	sort.Slice(r.Operations, func(i, j int) bool { return r.Operations[i].Operation < r.Operations[j].Operation })
+	return r
+}
+
 func (rc *ResultCollector) Collect() {
 	for {
 		select {
 		case result := <-rc.Results:
 			rc.totalCompleted++
 			rc.currentCompleted++
+			opName := result.Operation
+			if opName == "" {
+				opName = DefaultOperation
+			}
+			op, ok := rc.operations[opName]
+			if !ok {
+				op = &operationResults{}
+				rc.operations[opName] = op
+			}
+			op.completed++
 			if result.Error != nil {
 				rc.totalErrors++
+				op.errors++
 			} else {
 				rc.histogram.Add(result.Took.Milliseconds())
+				op.latencies = append(op.latencies, result.Took)
 			}
 		case request := <-rc.requests:
 			switch request {
 			case collectorRequestHistogram:
 				rc.histograms <- rc.histogram.Clone()
+			case collectorRequestReport:
+				rc.reports <- rc.report()
 			case collectorRequestStats:
 				rc.stats <- rc.flushCurrent()
 				rc.currentCompleted = 0
@@ -93,7 +134,10 @@ func NewResultCollector(workerResults chan Result) *ResultCollector {
 		requests:   make(chan collectorRequest),
 		stats:      make(chan *Stats),
 		histograms: make(chan *Histogram),
+		reports:    make(chan *Report),
+		startTime:  time.Now(),
 		lastFlush:  time.Now(),
 		histogram:  NewHistogram(DefaultHistogramBuckets),
+		operations: make(map[string]*operationResults),
 	}
 }
@@ -0,0 +1,92 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/logging"
+)
+
+const (
+	// minCopyPartSize is the minimum size of a multipart upload part that is not the last part
+	minCopyPartSize = 5 * 1024 * 1024
+	// maxCopyPartSize is the maximum size of a part copied in a multipart upload
+	maxCopyPartSize = 5 * 1024 * 1024 * 1024
+)
+
+// AppendBlob writes a new blob at address holding the data of source followed by body.  When source is large
+// enough to be a multipart upload part, its data is copied by the blockstore and only body is uploaded.
+// Otherwise, or if the blockstore cannot copy parts, source is read and written again along with body.
+func AppendBlob(ctx context.Context, adapter block.Adapter, bucketName string, source block.ObjectPointer, sourceSize int64, address string, body io.Reader, contentLength int64, opts block.PutOpts) (*Blob, error) {
+	if sourceSize >= minCopyPartSize && contentLength >= 0 {
+		blob, err := appendBlobMultipart(ctx, adapter, bucketName, source, sourceSize, address, body, contentLength)
+		if !errors.Is(err, block.ErrOperationNotSupported) {
+			return blob, err
+		}
+	}
+	reader, err := adapter.Get(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	size := int64(-1)
+	if contentLength >= 0 {
+		size = sourceSize + contentLength
+	}
+	return WriteBlob(ctx, adapter, bucketName, address, io.MultiReader(reader, body), size, opts)
+}
+
+func appendBlobMultipart(ctx context.Context, adapter block.Adapter, bucketName string, source block.ObjectPointer, sourceSize int64, address string, body io.Reader, contentLength int64) (*Blob, error) {
+	dest := block.ObjectPointer{
+		StorageNamespace: bucketName,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	}
+	mpu, err := adapter.CreateMultiPartUpload(ctx, dest, nil, block.CreateMultiPartUploadOpts{})
+	if err != nil {
+		return nil, err
+	}
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := adapter.AbortMultiPartUpload(ctx, dest, mpu.UploadID); err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("upload_id", mpu.UploadID).Warn("Failed to abort append upload")
+		}
+	}()
+
+	// split the source into even parts, as all parts except the last must be at least minCopyPartSize
+	numCopyParts := (sourceSize + maxCopyPartSize - 1) / maxCopyPartSize
+	partSize := (sourceSize + numCopyParts - 1) / numCopyParts
+	var parts []block.MultipartPart
+	for start := int64(0); start < sourceSize; start += partSize {
+		end := min(start+partSize, sourceSize) - 1
+		partNumber := len(parts) + 1
+		resp, err := adapter.UploadCopyPartRange(ctx, source, dest, mpu.UploadID, partNumber, start, end)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, block.MultipartPart{ETag: resp.ETag, PartNumber: partNumber})
+	}
+	partNumber := len(parts) + 1
+	resp, err := adapter.UploadPart(ctx, dest, contentLength, body, mpu.UploadID, partNumber)
+	if err != nil {
+		return nil, err
+	}
+	parts = append(parts, block.MultipartPart{ETag: resp.ETag, PartNumber: partNumber})
+
+	completion, err := adapter.CompleteMultiPartUpload(ctx, dest, mpu.UploadID, &block.MultipartUploadCompletion{Part: parts})
+	if err != nil {
+		return nil, err
+	}
+	completed = true
+	return &Blob{
+		PhysicalAddress: address,
+		RelativePath:    true,
+		Checksum:        completion.ETag,
+		Size:            sourceSize + contentLength,
+	}, nil
+}
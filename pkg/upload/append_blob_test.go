@@ -0,0 +1,54 @@
+package upload_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/treeverse/lakefs/pkg/block"
+	"github.com/treeverse/lakefs/pkg/block/mem"
+	"github.com/treeverse/lakefs/pkg/upload"
+)
+
+func TestAppendBlob(t *testing.T) {
+	const namespace = "mem://append"
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		sourceSize int
+	}{
+		{name: "small source", sourceSize: 1024},
+		{name: "copied source", sourceSize: 6 * 1024 * 1024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := mem.New(ctx)
+			sourceData := bytes.Repeat([]byte("s"), tt.sourceSize)
+			appendData := []byte("appended line\n")
+			source := block.ObjectPointer{
+				StorageNamespace: namespace,
+				IdentifierType:   block.IdentifierTypeRelative,
+				Identifier:       "source",
+			}
+			require.NoError(t, adapter.Put(ctx, source, int64(len(sourceData)), bytes.NewReader(sourceData), block.PutOpts{}))
+
+			blob, err := upload.AppendBlob(ctx, adapter, namespace, source, int64(len(sourceData)), "dest", bytes.NewReader(appendData), int64(len(appendData)), block.PutOpts{})
+			require.NoError(t, err)
+			require.Equal(t, int64(len(sourceData)+len(appendData)), blob.Size)
+			require.Equal(t, upload.Checksums{}, blob.Checksums, "checksums are stored only when supplied by the client")
+
+			reader, err := adapter.Get(ctx, block.ObjectPointer{
+				StorageNamespace: namespace,
+				IdentifierType:   block.IdentifierTypeRelative,
+				Identifier:       blob.PhysicalAddress,
+			})
+			require.NoError(t, err)
+			defer func() { _ = reader.Close() }()
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.Equal(t, append(sourceData, appendData...), data)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package upload
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/treeverse/lakefs/pkg/api/apiutil"
+)
+
+const (
+	// ChecksumSHA256MetadataKey entry metadata key used to store the base64 encoded SHA256 of the object data
+	ChecksumSHA256MetadataKey = apiutil.LakeFSMetadataPrefix + "checksum-sha256"
+	// ChecksumCRC32CMetadataKey entry metadata key used to store the base64 encoded CRC32C of the object data
+	ChecksumCRC32CMetadataKey = apiutil.LakeFSMetadataPrefix + "checksum-crc32c"
+
+	sha256Size = 32
+)
+
+var (
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrInvalidChecksum  = errors.New("invalid checksum")
+)
+
+// Checksums holds base64 encoded checksums of object data, using the same encoding as S3's x-amz-checksum-* headers.
+// Empty value means the checksum is not set.
+type Checksums struct {
+	SHA256 string
+	CRC32C string
+}
+
+// Validate checks that the set checksums are well-formed
+func (c Checksums) Validate() error {
+	if c.SHA256 != "" {
+		if b, err := base64.StdEncoding.DecodeString(c.SHA256); err != nil || len(b) != sha256Size {
+			return fmt.Errorf("sha256 %w", ErrInvalidChecksum)
+		}
+	}
+	if c.CRC32C != "" {
+		if b, err := base64.StdEncoding.DecodeString(c.CRC32C); err != nil || len(b) != crc32.Size {
+			return fmt.Errorf("crc32c %w", ErrInvalidChecksum)
+		}
+	}
+	return nil
+}
+
+// Verify compares the set checksums with the actual checksums calculated on the data
+func (c Checksums) Verify(actual Checksums) error {
+	if c.SHA256 != "" && c.SHA256 != actual.SHA256 {
+		return fmt.Errorf("sha256 %w: expected %s, got %s", ErrChecksumMismatch, c.SHA256, actual.SHA256)
+	}
+	if c.CRC32C != "" && c.CRC32C != actual.CRC32C {
+		return fmt.Errorf("crc32c %w: expected %s, got %s", ErrChecksumMismatch, c.CRC32C, actual.CRC32C)
+	}
+	return nil
+}
+
+// SetMetadata stores the set checksums into entry metadata
+func (c Checksums) SetMetadata(metadata map[string]string) {
+	if c.SHA256 != "" {
+		metadata[ChecksumSHA256MetadataKey] = c.SHA256
+	}
+	if c.CRC32C != "" {
+		metadata[ChecksumCRC32CMetadataKey] = c.CRC32C
+	}
+}
+
+// ChecksumsFromMetadata extracts checksums stored on entry metadata
+func ChecksumsFromMetadata(metadata map[string]string) Checksums {
+	return Checksums{
+		SHA256: metadata[ChecksumSHA256MetadataKey],
+		CRC32C: metadata[ChecksumCRC32CMetadataKey],
+	}
+}
@@ -2,7 +2,9 @@ package upload
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"io"
 
 	"github.com/treeverse/lakefs/pkg/block"
@@ -13,11 +15,13 @@ type Blob struct {
 	RelativePath    bool
 	Checksum        string
 	Size            int64
+	// Checksums holds the additional checksums supplied by the client and verified on the stored data
+	Checksums Checksums
 }
 
 func WriteBlob(ctx context.Context, adapter block.Adapter, bucketName, address string, body io.Reader, contentLength int64, opts block.PutOpts) (*Blob, error) {
 	// handle the upload itself
-	hashReader := block.NewHashingReader(body, block.HashFunctionMD5, block.HashFunctionSHA256)
+	hashReader := block.NewHashingReader(body, block.HashFunctionMD5)
 	err := adapter.Put(ctx, block.ObjectPointer{
 		StorageNamespace: bucketName,
 		IdentifierType:   block.IdentifierTypeRelative,
@@ -34,3 +38,62 @@ func WriteBlob(ctx context.Context, adapter block.Adapter, bucketName, address s
 		Size:            hashReader.CopiedSize,
 	}, nil
 }
+
+// WriteBlobWithChecksums writes the blob like WriteBlob and verifies the stored data against the expected checksums
+// supplied by the client. On mismatch the written object is removed and ErrChecksumMismatch is returned.
+func WriteBlobWithChecksums(ctx context.Context, adapter block.Adapter, bucketName, address string, body io.Reader, contentLength int64, opts block.PutOpts, expected Checksums) (*Blob, error) {
+	if err := expected.Validate(); err != nil {
+		return nil, err
+	}
+	blob, err := WriteBlob(ctx, adapter, bucketName, address, body, contentLength, opts)
+	if err != nil {
+		return nil, err
+	}
+	obj := block.ObjectPointer{
+		StorageNamespace: bucketName,
+		IdentifierType:   block.IdentifierTypeRelative,
+		Identifier:       address,
+	}
+	if err := VerifyObject(ctx, adapter, obj, expected); err != nil {
+		return nil, err
+	}
+	blob.Checksums = expected
+	return blob, nil
+}
+
+// VerifyObject reads the object back from the adapter and verifies its data against the expected checksums.
+// Nothing is read if no checksum is expected. On mismatch the object is removed and ErrChecksumMismatch is returned.
+func VerifyObject(ctx context.Context, adapter block.Adapter, obj block.ObjectPointer, expected Checksums) error {
+	var hashTypes []block.HashFunction
+	if expected.SHA256 != "" {
+		hashTypes = append(hashTypes, block.HashFunctionSHA256)
+	}
+	if expected.CRC32C != "" {
+		hashTypes = append(hashTypes, block.HashFunctionCRC32C)
+	}
+	if len(hashTypes) == 0 {
+		return nil
+	}
+	reader, err := adapter.Get(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("read stored data: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+	hashReader := block.NewHashingReader(reader, hashTypes...)
+	if _, err := io.Copy(io.Discard, hashReader); err != nil {
+		return fmt.Errorf("read stored data: %w", err)
+	}
+	var actual Checksums
+	if hashReader.Sha256 != nil {
+		actual.SHA256 = base64.StdEncoding.EncodeToString(hashReader.Sha256.Sum(nil))
+	}
+	if hashReader.Crc32c != nil {
+		actual.CRC32C = base64.StdEncoding.EncodeToString(hashReader.Crc32c.Sum(nil))
+	}
+	if err := expected.Verify(actual); err != nil {
+		// best effort - the object is not referenced by any entry
+		_ = adapter.Remove(ctx, obj)
+		return err
+	}
+	return nil
+}